@@ -0,0 +1,167 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/3idey/codescan/codescan"
+	"github.com/go-openapi/spec"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// baseline command flags
+	baselineWorkDir    string
+	baselineBuildTags  string
+	baselineScanModels bool
+	baselineConfigFile string
+	updateBaseline     bool
+)
+
+var baselineCmd = &cobra.Command{
+	Use:   "baseline",
+	Short: "Record and enforce a compatibility baseline of the spec",
+	Long: `Records the public surface of the spec of the specified Go packages in a baseline
+file: the signatures of the operations and the shapes of the definitions. The
+descriptions and examples are not recorded, so that they change freely.
+
+check fails on the breaking changes from the baseline, e.g. a removed property or
+a new required parameter, and allows the additive ones, e.g. a new operation or
+an optional property. The suppressions of the baseline file accept breaking
+changes until they expire:
+
+  "suppressions": [
+    {"location": "definition User.nickname", "reason": "unused", "expires": "2026-12-31"}
+  ]
+
+Examples:
+  codescan baseline write baseline.json ./...
+  codescan baseline check baseline.json ./...`,
+}
+
+var baselineWriteCmd = &cobra.Command{
+	Use:   "write <baseline> [packages...]",
+	Short: "Record the baseline of the spec, keeping the suppressions of the baseline file",
+	Args:  cobra.MinimumNArgs(2),
+	RunE:  runBaselineWrite,
+}
+
+var baselineCheckCmd = &cobra.Command{
+	Use:   "check <baseline> [packages...]",
+	Short: "Fail on the breaking changes of the spec from the baseline",
+	Args:  cobra.MinimumNArgs(2),
+	RunE:  runBaselineCheck,
+}
+
+func init() {
+	baselineCmd.PersistentFlags().StringVarP(&baselineWorkDir, "work-dir", "w", "", "working directory for package resolution")
+	baselineCmd.PersistentFlags().StringVar(&baselineBuildTags, "tags", "", "build tags to use when scanning")
+	baselineCmd.PersistentFlags().BoolVar(&baselineScanModels, "scan-models", false, "include models that are not referenced by operations")
+	baselineCmd.PersistentFlags().StringVar(&baselineConfigFile, "config", "", "YAML config file")
+	baselineCheckCmd.Flags().BoolVar(&updateBaseline, "update-baseline", false, "accept the breaking changes, recording the baseline again")
+
+	baselineCmd.AddCommand(baselineWriteCmd)
+	baselineCmd.AddCommand(baselineCheckCmd)
+}
+
+func runBaselineWrite(_ *cobra.Command, args []string) error {
+	previous, err := loadBaseline(args[0])
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	swspec, err := scanBaseline(args[1:])
+	if err != nil {
+		return err
+	}
+	return writeBaseline(args[0], swspec, previous)
+}
+
+func runBaselineCheck(cmd *cobra.Command, args []string) error {
+	baseline, err := loadBaseline(args[0])
+	if err != nil {
+		return err
+	}
+	swspec, err := scanBaseline(args[1:])
+	if err != nil {
+		return err
+	}
+
+	changes := codescan.CheckBaseline(baseline, swspec, time.Now())
+	for _, change := range changes {
+		fmt.Fprintln(os.Stdout, change.Error())
+	}
+	if updateBaseline {
+		if len(changes) > 0 {
+			fmt.Fprintf(os.Stderr, "Accepting the breaking changes from %s\n", args[0])
+		}
+		return writeBaseline(args[0], swspec, baseline)
+	}
+
+	// the breaking changes are not a misuse of the command
+	cmd.SilenceUsage = true
+	switch len(changes) {
+	case 0:
+		return nil
+	case 1:
+		return fmt.Errorf("1 breaking change from %s", args[0])
+	default:
+		return fmt.Errorf("%d breaking changes from %s", len(changes), args[0])
+	}
+}
+
+func scanBaseline(packages []string) (*spec.Swagger, error) {
+	opts := &codescan.Options{
+		Packages:   packages,
+		WorkDir:    baselineWorkDir,
+		BuildTags:  baselineBuildTags,
+		ScanModels: baselineScanModels,
+	}
+	if baselineConfigFile != "" {
+		cfg, err := loadConfig(baselineConfigFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.apply(opts, baselineCmd.PersistentFlags())
+	}
+
+	swspec, err := codescan.Run(opts)
+	if err != nil {
+		return nil, fmt.Errorf("scan failed: %w", err)
+	}
+	return swspec, nil
+}
+
+func loadBaseline(path string) (*codescan.Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+	baseline, err := codescan.ParseBaseline(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return baseline, nil
+}
+
+// writeBaseline records the baseline of a spec, with the suppressions of the previous baseline, if any.
+func writeBaseline(path string, swspec *spec.Swagger, previous *codescan.Baseline) error {
+	baseline := codescan.NewBaseline(swspec)
+	if previous != nil {
+		baseline.Suppressions = previous.Suppressions
+	}
+	output, err := json.MarshalIndent(baseline, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, append(output, '\n')); err != nil {
+		return fmt.Errorf("failed to write baseline: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Baseline written to %s (%d operations, %d definitions)\n", path, len(baseline.Operations), len(baseline.Definitions))
+	return nil
+}
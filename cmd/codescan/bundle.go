@@ -0,0 +1,154 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/3idey/codescan/codescan"
+	"github.com/go-openapi/spec"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var (
+	// bundle command flags
+	bundleOutputFile   string
+	bundleOutputFormat string
+	bundleCompact      bool
+
+	// remote ref flags, shared by bundle and validate
+	refsCacheDir  string
+	allowedHosts  []string
+	offline       bool
+	fetchRetries  int
+	retryBackoff  time.Duration
+	fetchInterval time.Duration
+	refsLockFile  string
+)
+
+var bundleCmd = &cobra.Command{
+	Use:   "bundle [spec]",
+	Short: "Join a spec and the documents of its external refs into a single document",
+	Long: `Resolves the external $refs of a spec, to files relative to it and to http or
+https URLs, and replaces them with local refs: the definitions, parameters and
+responses they point to are added to the spec, and the path items replace the
+refs of its paths. A spec written with generate --split-output is joined back.
+
+The remote documents are fetched with retries of the transient failures, see
+--retries, at most a request per --fetch-interval to a host, and only from the
+--allow-host hosts when given. They are kept in --refs-cache-dir by the hash of
+their content, which --refs-lock records: a remote document which changed since
+is read from the cache, with a warning, until its hash is removed from the lock.
+With --offline, the documents missing from the cache are listed, without
+fetching anything.
+
+Examples:
+  codescan bundle -o bundled.json swagger.json
+  codescan bundle --refs-cache-dir .codescan/refs --refs-lock refs.lock.json -o bundled.json swagger.json
+  codescan bundle --offline --refs-cache-dir .codescan/refs --refs-lock refs.lock.json swagger.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBundle,
+}
+
+func init() {
+	bundleCmd.Flags().StringVarP(&bundleOutputFile, "output", "o", "", "output file (default: stdout)")
+	bundleCmd.Flags().StringVar(&bundleOutputFormat, "format", "json", "output format: json or yaml")
+	bundleCmd.Flags().BoolVar(&bundleCompact, "compact", false, "produce compact JSON output")
+	addRemoteRefFlags(bundleCmd.Flags())
+}
+
+// addRemoteRefFlags adds the flags of the resolution of the external refs.
+func addRemoteRefFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&refsCacheDir, "refs-cache-dir", "", "directory keeping the remote documents of the external refs by the hash of their content")
+	flags.StringSliceVar(&allowedHosts, "allow-host", nil, "host the remote documents may be fetched from, e.g. *.acme.com (repeatable; default: any)")
+	flags.BoolVar(&offline, "offline", false, "read the remote documents from --refs-cache-dir only, failing with those missing")
+	flags.IntVar(&fetchRetries, "retries", 3, "retries of a fetch failing with a network error, 429 or 5xx")
+	flags.DurationVar(&retryBackoff, "retry-backoff", 500*time.Millisecond, "delay before the first retry of a fetch, doubled after each one")
+	flags.DurationVar(&fetchInterval, "fetch-interval", 0, "minimum delay between two requests to a host")
+	flags.StringVar(&refsLockFile, "refs-lock", "", "JSON file recording the hashes of the remote documents, updated when they are fetched")
+}
+
+func runBundle(cmd *cobra.Command, args []string) error {
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to read spec: %w", err)
+	}
+	doc, err := codescan.ParseInputSpec(data, false)
+	if err != nil {
+		return fmt.Errorf("invalid spec %s: %w", args[0], err)
+	}
+
+	// the failures of the resolution are not a misuse of the command
+	cmd.SilenceUsage = true
+	resolved, err := resolveExternalRefs(cmd.Context(), doc, args[0])
+	if err != nil {
+		return err
+	}
+	if err := codescan.BundleSpec(doc, resolved); err != nil {
+		return fmt.Errorf("bundle failed: %w", err)
+	}
+
+	var outputFiles []string
+	if bundleOutputFile != "" {
+		outputFiles = []string{resolvePath(bundleOutputFile)}
+	}
+	return writeSpec(doc, outputFiles, bundleOutputFormat, bundleCompact)
+}
+
+// resolveExternalRefs resolves the external refs of the spec at base with the remote ref flags, and records
+// the hashes of the remote documents in --refs-lock.
+func resolveExternalRefs(ctx context.Context, doc *spec.Swagger, base string) (*codescan.ResolvedRefs, error) {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	lockFile := resolvePath(refsLockFile)
+	var lock map[string]string
+	if refsLockFile != "" {
+		data, err := os.ReadFile(lockFile)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+		case err != nil:
+			return nil, fmt.Errorf("failed to read the refs lock: %w", err)
+		default:
+			if err := json.Unmarshal(data, &lock); err != nil {
+				return nil, fmt.Errorf("invalid refs lock %s: %w", lockFile, err)
+			}
+		}
+	}
+
+	resolved, err := codescan.ResolveRemoteRefs(ctx, doc, codescan.RemoteRefOptions{
+		Base:         base,
+		CacheDir:     resolvePath(refsCacheDir),
+		AllowedHosts: allowedHosts,
+		Offline:      offline,
+		Retries:      fetchRetries,
+		Backoff:      retryBackoff,
+		Interval:     fetchInterval,
+		Lock:         lock,
+		Logger:       printWarning,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if refsLockFile != "" && !maps.Equal(lock, resolved.Lock) {
+		data, err := json.MarshalIndent(resolved.Lock, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		if err := writeFileAtomic(lockFile, append(data, '\n')); err != nil {
+			return nil, fmt.Errorf("failed to write the refs lock: %w", err)
+		}
+	}
+	return resolved, nil
+}
@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// extractStrings makes runGenerate write the translatable strings of the spec instead of the spec.
+var extractStrings bool
+
+var extractStringsCmd = &cobra.Command{
+	Use:   "extract-strings [packages...]",
+	Short: "Extract the translatable strings of the spec for a description catalog",
+	Long: `Scans the specified Go packages like generate, and writes the titles, summaries
+and descriptions of the spec by JSON pointer, as a YAML catalog for translators.
+
+The flags are the ones of generate: the pointers only match the spec generated
+with the same flags. The translated catalog is given to generate with
+--description-catalog.
+
+Examples:
+  codescan extract-strings -o en.yaml ./...
+  codescan generate --description-catalog ja.yaml -o swagger.ja.json ./...`,
+	Args: packagesArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		extractStrings = true
+		return runGenerateCommand(cmd, args)
+	},
+}
+
+func loadDescriptionCatalog(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	catalog := make(map[string]string)
+	if err := yaml.Unmarshal(data, &catalog); err != nil {
+		return nil, fmt.Errorf("invalid description catalog %s: %w", path, err)
+	}
+	return catalog, nil
+}
+
+func writeStrings(strs map[string]string, outputFiles []string) error {
+	data, err := yaml.Marshal(strs)
+	if err != nil {
+		return err
+	}
+
+	if len(outputFiles) == 0 {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	for _, file := range outputFiles {
+		if err := writeFileAtomic(file, data); err != nil {
+			return fmt.Errorf("failed to write strings: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "Strings written to %s (%d entries)\n", file, len(strs))
+	}
+	return nil
+}
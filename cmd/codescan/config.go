@@ -0,0 +1,147 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"reflect"
+	"slices"
+
+	"github.com/3idey/codescan/codescan"
+	"github.com/go-openapi/spec"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultConfigFile is the config file of generate when --config isn't given, if it exists in the current
+// directory.
+const defaultConfigFile = ".codescan.yaml"
+
+// generateConfig holds the settings of the generate command which are read from the --config file: the
+// options of codescan.LoadOptionsFromFile, and the settings of the command.
+type generateConfig struct {
+	// Services are the specs generated with --all-services.
+	Services []serviceConfig `yaml:"services"`
+	// Generate sets the flags of generate which don't set a key of the options, e.g. output or format,
+	// unless given on the command line.
+	Generate map[string]any `yaml:"generate"`
+	// Options are the keys of the options, see codescan.OptionsFileKey.
+	Options map[string]any `yaml:",inline"`
+
+	options *codescan.Options
+}
+
+// configPath returns the config file of generate: the --config file, or .codescan.yaml when it exists.
+func configPath() string {
+	if configFile != "" {
+		return resolvePath(configFile)
+	}
+	if _, err := os.Stat(defaultConfigFile); err == nil {
+		return defaultConfigFile
+	}
+	return ""
+}
+
+// loadConfig reads a config file. Unknown keys are rejected, with codescan.ErrUnknownOption for top-level ones.
+func loadConfig(path string) (*generateConfig, error) {
+	options, err := codescan.LoadOptionsFromFile(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := generateConfig{options: options}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	return &cfg, nil
+}
+
+// setFlags sets the flags of the generate section, unless given on the command line.
+func (c *generateConfig) setFlags(flags *pflag.FlagSet) error {
+	for _, name := range slices.Sorted(maps.Keys(c.Generate)) {
+		if flags.Lookup(name) == nil || slices.Contains(serviceFlags, name) {
+			return fmt.Errorf("generate: unsupported flag %q", name)
+		}
+		if key, ok := codescan.OptionsFileKey(flagOption(name)); ok {
+			return fmt.Errorf("generate: flag %q sets the key %s of the options", name, key)
+		}
+		if flags.Changed(name) {
+			continue
+		}
+		if err := setFlag(flags, name, c.Generate[name]); err != nil {
+			return fmt.Errorf("generate: %w", err)
+		}
+	}
+	return nil
+}
+
+// apply sets the options configured by the file, unless set by the flags given on the command line: those
+// of generate setting the same option, or the flags of the other commands with the same name, e.g. --tags.
+// The packages given as arguments replace those of the file, and the --type-mapping flags override the
+// type_mappings of the same types.
+func (c *generateConfig) apply(opts *codescan.Options, flags *pflag.FlagSet) {
+	from, to := reflect.ValueOf(c.options).Elem(), reflect.ValueOf(opts).Elem()
+	for _, key := range slices.Sorted(maps.Keys(c.Options)) {
+		field := optionField(key)
+		switch {
+		case field == "Packages" && len(opts.Packages) > 0:
+		case field == "TypeMappings":
+			for name, schema := range c.options.TypeMappings {
+				if _, set := opts.TypeMappings[name]; !set {
+					if opts.TypeMappings == nil {
+						opts.TypeMappings = make(map[string]spec.Schema)
+					}
+					opts.TypeMappings[name] = schema
+				}
+			}
+		case !optionChanged(flags, field):
+			to.FieldByName(field).Set(from.FieldByName(field))
+		}
+	}
+}
+
+// optionField returns the field of codescan.Options set by a key of the config file.
+func optionField(key string) string {
+	for _, field := range reflect.VisibleFields(reflect.TypeFor[codescan.Options]()) {
+		if fieldKey, ok := codescan.OptionsFileKey(field.Name); ok && fieldKey == key {
+			return field.Name
+		}
+	}
+	return ""
+}
+
+// optionChanged tells whether a flag setting a field of codescan.Options was given on the command line.
+func optionChanged(flags *pflag.FlagSet, option string) bool {
+	for _, entry := range generateFlags {
+		if entry.option == option && flags.Changed(entry.name) {
+			return true
+		}
+	}
+	return false
+}
+
+// addTypeMapping sets the schema of a Go type, written as a type with an optional format, e.g. string:uuid.
+func addTypeMapping(opts *codescan.Options, name, value string) error {
+	schema, err := codescan.ParseTypeMapping(value)
+	if err != nil {
+		return err
+	}
+	if opts.TypeMappings == nil {
+		opts.TypeMappings = make(map[string]spec.Schema)
+	}
+	opts.TypeMappings[name] = schema
+	return nil
+}
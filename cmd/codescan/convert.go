@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/3idey/codescan/codescan"
+	"github.com/go-openapi/spec"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// convert command flags
+	convertOutputFile   string
+	convertOutputFormat string
+	convertCompact      bool
+	normalizeRefs       string
+	convertFailOnWarn   bool
+)
+
+var convertCmd = &cobra.Command{
+	Use:   "convert [spec]",
+	Short: "Convert an OpenAPI 3.x spec to swagger 2.0",
+	Long: `Converts an OpenAPI 3.x document (JSON or YAML) to an OpenAPI 2.0 (Swagger)
+specification, e.g. to use it as an input spec for the generate command.
+
+Constructs without a swagger 2.0 equivalent are approximated or preserved as
+vendor extensions, with an unsupported-swagger2 warning, which --fail-on-warning
+turns into a failure.
+
+Examples:
+  # Convert an OpenAPI 3.0 document
+  codescan convert -o swagger.json openapi.yaml
+
+  # Turn refs qualified by generate --relative-refs back into local refs
+  codescan convert --normalize-refs swagger.json -o local.json swagger.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConvert,
+}
+
+func init() {
+	convertCmd.Flags().StringVarP(&convertOutputFile, "output", "o", "", "output file (default: stdout)")
+	convertCmd.Flags().StringVar(&convertOutputFormat, "format", "json", "output format: json or yaml")
+	convertCmd.Flags().BoolVar(&convertCompact, "compact", false, "produce compact JSON output")
+	convertCmd.Flags().StringVar(&normalizeRefs, "normalize-refs", "", "turn refs qualified with this document name back into local refs; the input may then be a swagger 2.0 spec")
+	convertCmd.Flags().BoolVar(&convertFailOnWarn, "fail-on-warning", false, "fail when the conversion approximates or drops constructs swagger 2.0 can't express")
+}
+
+func runConvert(cmd *cobra.Command, args []string) error {
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to read spec: %w", err)
+	}
+
+	var swspec *spec.Swagger
+	if normalizeRefs != "" {
+		swspec, err = codescan.ParseInputSpec(data, true)
+	} else {
+		swspec, err = codescan.DowngradeOpenAPI3For(data, &codescan.Options{Logger: printWarning, FailOnWarning: convertFailOnWarn})
+	}
+	if err != nil {
+		return fmt.Errorf("conversion failed: %w", err)
+	}
+
+	if normalizeRefs != "" {
+		if err := codescan.NormalizeRefs(swspec, normalizeRefs); err != nil {
+			return err
+		}
+	}
+
+	var outputFiles []string
+	if convertOutputFile != "" {
+		outputFiles = []string{convertOutputFile}
+	}
+
+	return writeSpec(swspec, outputFiles, convertOutputFormat, convertCompact)
+}
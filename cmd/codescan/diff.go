@@ -0,0 +1,188 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/3idey/codescan/codescan"
+	"github.com/3idey/codescan/codescan/specdiff"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// diff command flags
+	diffWorkDir    string
+	diffBuildTags  string
+	diffScanModels bool
+	diffConfigFile string
+	diffAgainst    string
+	diffFormat     string
+	diffFailOn     string
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff --against <spec> [packages...]",
+	Short: "Compare the generated spec with an existing one",
+	Long: `Scans the specified Go packages like generate, and compares the spec with the
+swagger 2.0 spec of --against, e.g. the one committed with the code, instead of
+writing it. The changes are grouped by operation and definition, and classified
+as breaking or not: a removed path or a new required request field is breaking,
+a new optional response field isn't.
+
+The command fails on the breaking changes with --fail-on breaking, the default,
+and on any change with --fail-on any. --format json prints the changes for
+tools, with their kind, JSON pointer, direction and values before and after.
+
+Examples:
+  codescan diff --against api.yaml ./...
+  codescan diff --against api.yaml --fail-on any --format json ./...`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runDiff,
+}
+
+func init() {
+	diffCmd.Flags().StringVarP(&diffWorkDir, "work-dir", "w", "", "working directory for package resolution")
+	diffCmd.Flags().StringVar(&diffBuildTags, "tags", "", "build tags to use when scanning")
+	diffCmd.Flags().BoolVar(&diffScanModels, "scan-models", false, "include models that are not referenced by operations")
+	diffCmd.Flags().StringVar(&diffConfigFile, "config", "", "YAML config file (e.g. with force_include_dirs)")
+	diffCmd.Flags().StringVar(&diffAgainst, "against", "", "swagger 2.0 spec, in JSON or YAML, the generated spec is compared with")
+	diffCmd.Flags().StringVar(&diffFormat, "format", "text", "output format: text or json")
+	diffCmd.Flags().StringVar(&diffFailOn, "fail-on", "breaking", "fail on the breaking changes, or on any change: breaking or any")
+	_ = diffCmd.MarkFlagRequired("against")
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	if diffFormat != "text" && diffFormat != "json" {
+		return fmt.Errorf("invalid --format %q, expected text or json", diffFormat)
+	}
+	if diffFailOn != "breaking" && diffFailOn != "any" {
+		return fmt.Errorf("invalid --fail-on %q, expected breaking or any", diffFailOn)
+	}
+	data, err := os.ReadFile(diffAgainst)
+	if err != nil {
+		return err
+	}
+	before, err := codescan.ParseInputSpec(data, false)
+	if err != nil {
+		return fmt.Errorf("invalid spec %s: %w", diffAgainst, err)
+	}
+
+	opts := &codescan.Options{
+		Packages:   args,
+		WorkDir:    diffWorkDir,
+		BuildTags:  diffBuildTags,
+		ScanModels: diffScanModels,
+	}
+	if diffConfigFile != "" {
+		cfg, cfgErr := loadConfig(diffConfigFile)
+		if cfgErr != nil {
+			return cfgErr
+		}
+		cfg.apply(opts, cmd.Flags())
+	}
+	after, err := codescan.Run(opts)
+	if err != nil {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("scan failed: %w", err)
+	}
+
+	changes := specdiff.Diff(before, after, nil)
+	breaking := len(specdiff.Breaking(changes))
+	if diffFormat == "json" {
+		err = reportChangesJSON(os.Stdout, changes, breaking)
+	} else {
+		reportChanges(os.Stdout, changes)
+	}
+	if err != nil {
+		return err
+	}
+
+	// the changes are not a misuse of the command
+	cmd.SilenceUsage = true
+	switch {
+	case breaking > 0:
+		return fmt.Errorf("%s from %s, %d breaking", countChanges(len(changes)), diffAgainst, breaking)
+	case len(changes) > 0 && diffFailOn == "any":
+		return fmt.Errorf("%s from %s", countChanges(len(changes)), diffAgainst)
+	case len(changes) > 0:
+		fmt.Fprintf(os.Stderr, "%s from %s, none breaking\n", countChanges(len(changes)), diffAgainst)
+	}
+	return nil
+}
+
+// reportChanges prints the changes grouped by the element they belong to, e.g. an operation or a
+// definition, in the order of the diff. The breaking changes are marked as such.
+func reportChanges(w io.Writer, changes []specdiff.Change) {
+	var (
+		groups  []string
+		grouped = make(map[string][]specdiff.Change)
+	)
+	for _, change := range changes {
+		group := changeGroup(change.Path)
+		if _, found := grouped[group]; !found {
+			groups = append(groups, group)
+		}
+		grouped[group] = append(grouped[group], change)
+	}
+	for i, group := range groups {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintln(w, group)
+		for _, change := range grouped[group] {
+			marker := "  "
+			if change.Breaking {
+				marker = "! "
+			}
+			fmt.Fprintf(w, "  %s%s [%s]\n", marker, change.Description, change.Kind)
+		}
+	}
+}
+
+// changeGroup is the element a change belongs to: an operation, e.g. GET /pets/{id}, a path, a
+// definition, or the spec itself.
+func changeGroup(path specdiff.JSONPointer) string {
+	tokens := path.Tokens()
+	switch {
+	case len(tokens) >= 3 && tokens[0] == "paths" && slices.Contains(httpMethods, tokens[2]):
+		return strings.ToUpper(tokens[2]) + " " + tokens[1]
+	case len(tokens) >= 2 && tokens[0] == "paths":
+		return "path " + tokens[1]
+	case len(tokens) >= 2 && tokens[0] == "definitions":
+		return "definition " + tokens[1]
+	default:
+		return "spec"
+	}
+}
+
+// reportChangesJSON prints the changes as a JSON object, with the number of breaking changes.
+func reportChangesJSON(w io.Writer, changes []specdiff.Change, breaking int) error {
+	if changes == nil {
+		changes = []specdiff.Change{}
+	}
+	output, err := json.MarshalIndent(struct {
+		Breaking int               `json:"breaking"`
+		Changes  []specdiff.Change `json:"changes"`
+	}{breaking, changes}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode the changes: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(output))
+	return err
+}
+
+var httpMethods = []string{"get", "put", "post", "delete", "options", "head", "patch"}
+
+func countChanges(count int) string {
+	if count == 1 {
+		return "1 change"
+	}
+	return fmt.Sprintf("%d changes", count)
+}
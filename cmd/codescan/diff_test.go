@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	cli := buildCLI(t)
+
+	// the spec of the module, which the cases change into the spec the scan is compared with
+	dir := t.TempDir()
+	writeFiles(t, dir, pathsModule)
+	out, code := runCLI(t, cli, dir, "generate", "-o", "swagger.json", ".")
+	require.Zero(t, code, "can't generate the spec: %s", out)
+	data, err := os.ReadFile(filepath.Join(dir, "swagger.json"))
+	require.NoError(t, err)
+
+	unchanged := func(map[string]any) {}
+	pathAdded := func(doc map[string]any) { delete(doc["paths"].(map[string]any), "/pets") }
+	pathRemoved := func(doc map[string]any) {
+		doc["paths"].(map[string]any)["/owners"] = map[string]any{
+			"get": map[string]any{"operationId": "listOwners", "responses": map[string]any{"default": map[string]any{"description": "x"}}},
+		}
+	}
+
+	for _, tc := range []struct {
+		name     string
+		change   func(doc map[string]any)
+		args     []string
+		exitCode int
+		expected []string
+	}{
+		{
+			name:   "should accept an unchanged spec",
+			change: unchanged,
+		},
+		{
+			name:     "should accept the changes which are not breaking",
+			change:   pathAdded,
+			expected: []string{"path /pets\n    the path /pets was added [path-added]", "1 change from against.json, none breaking"},
+		},
+		{
+			name:     "should fail on any change with --fail-on any",
+			change:   pathAdded,
+			args:     []string{"--fail-on", "any"},
+			exitCode: 1,
+			expected: []string{"the path /pets was added [path-added]", "1 change from against.json"},
+		},
+		{
+			name:     "should fail on a breaking change",
+			change:   pathRemoved,
+			exitCode: 1,
+			expected: []string{"path /owners\n  ! the path /owners was removed [path-removed]", "1 change from against.json, 1 breaking"},
+		},
+		{
+			name:     "should print the changes as JSON with --format json",
+			change:   pathRemoved,
+			args:     []string{"--format", "json"},
+			exitCode: 1,
+			expected: []string{`"breaking": 1,`, `"kind": "path-removed",`, `"path": "/paths/~1owners",`},
+		},
+		{
+			name:     "should print no change as JSON with --format json",
+			change:   unchanged,
+			args:     []string{"--format", "json"},
+			expected: []string{`"breaking": 0,`, `"changes": []`},
+		},
+		{
+			name:     "should refuse an invalid --fail-on",
+			change:   unchanged,
+			args:     []string{"--fail-on", "minor"},
+			exitCode: 1,
+			expected: []string{`invalid --fail-on "minor", expected breaking or any`},
+		},
+		{
+			name:     "should refuse an invalid --format",
+			change:   unchanged,
+			args:     []string{"--format", "yaml"},
+			exitCode: 1,
+			expected: []string{`invalid --format "yaml", expected text or json`},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var doc map[string]any
+			require.NoError(t, json.Unmarshal(data, &doc))
+			tc.change(doc)
+			against, err := json.Marshal(doc)
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "against.json"), against, 0o600))
+
+			out, code := runCLI(t, cli, dir, append([]string{"diff", "--against", "against.json", "."}, tc.args...)...)
+			assert.Equal(t, tc.exitCode, code, out)
+			for _, expected := range tc.expected {
+				assert.Contains(t, out, expected)
+			}
+			if len(tc.expected) == 0 {
+				assert.Empty(t, out)
+			}
+		})
+	}
+}
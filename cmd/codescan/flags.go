@@ -0,0 +1,282 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/3idey/codescan/codescan"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// Sections of the help of the generate command, in their order.
+const (
+	groupOutput        = "Output"
+	groupScanning      = "Scanning"
+	groupFiltering     = "Filtering"
+	groupSchema        = "Schema"
+	groupCompatibility = "Compatibility"
+)
+
+var flagGroups = []string{groupOutput, groupScanning, groupFiltering, groupSchema, groupCompatibility}
+
+// listOptions is the generate --list-options flag.
+var listOptions bool
+
+// generateFlag is a flag of the generate command, with the section of the help listing it and the field of
+// codescan.Options it sets, if any.
+type generateFlag struct {
+	name    string
+	group   string
+	option  string
+	negated bool // the flag sets the option to false, e.g. --no-default-skips
+}
+
+// generateFlags are the flags of the generate command, in the order of the help. Every flag registered must
+// be listed, see checkFlagTable, which --list-options, the help and optionFlag rely on.
+var generateFlags = []generateFlag{
+	{name: "output", group: groupOutput},
+	{name: "split-output", group: groupOutput},
+	{name: "format", group: groupOutput},
+	{name: "spec-version", group: groupOutput},
+	{name: "check", group: groupOutput},
+	{name: "watch", group: groupOutput},
+	{name: "watch-interval", group: groupOutput},
+	{name: "output-set", group: groupOutput, option: "OutputSets"},
+	{name: "compact", group: groupOutput},
+	{name: "no-examples", group: groupOutput, option: "NoExamples"},
+	{name: "emit-generation-info", group: groupOutput, option: "EmitGenerationInfo"},
+	{name: "reproducible", group: groupOutput, option: "Reproducible"},
+	{name: "code-samples", group: groupOutput, option: "CodeSamples"},
+	{name: "description-catalog", group: groupOutput, option: "DescriptionCatalog"},
+	{name: "mark-untranslated", group: groupOutput, option: "MarkUntranslated"},
+	{name: "definition-index", group: groupOutput, option: "DefinitionIndex"},
+	{name: "definition-index-location", group: groupOutput},
+	{name: "source-map", group: groupOutput, option: "SourceMap"},
+	{name: "report-single-use", group: groupOutput},
+	{name: "report-identical", group: groupOutput},
+	{name: "report-unused", group: groupOutput, option: "UnusedDefinitions"},
+	{name: "stats", group: groupOutput, option: "Stats"},
+	{name: "verbose", group: groupOutput},
+	{name: "quiet", group: groupOutput, option: "Logger"},
+	{name: "progress", group: groupOutput, option: "OnProgress"},
+	{name: "list-options", group: groupOutput},
+
+	{name: "config", group: groupScanning},
+	{name: "all-services", group: groupScanning},
+	{name: "service", group: groupScanning},
+	{name: "work-dir", group: groupScanning, option: "WorkDir"},
+	{name: "tags", group: groupScanning, option: "BuildTags"},
+	{name: "extra-tags", group: groupScanning, option: "ExtraBuildTags"},
+	{name: "env", group: groupScanning, option: "Env"},
+	{name: "packages-driver", group: groupScanning, option: "PackagesDriver"},
+	{name: "scan-models", group: groupScanning, option: "ScanModels"},
+	{name: "exclude-deps", group: groupScanning, option: "ExcludeDeps"},
+	{name: "compat", group: groupCompatibility, option: "Compat"},
+	{name: "explain-package", group: groupScanning, option: "ExplainPackages"},
+	{name: "no-default-skips", group: groupScanning, option: "DefaultSkips", negated: true},
+	{name: "also-scan", group: groupScanning, option: "AlsoScan"},
+	{name: "include-test-scope", group: groupScanning, option: "IncludeTestScope"},
+	{name: "input", group: groupScanning, option: "InputSpec"},
+	{name: "merge-strategy", group: groupScanning, option: "MergeStrategy"},
+	{name: "dry-run-merge", group: groupScanning},
+	{name: "downgrade-input", group: groupScanning},
+	{name: "meta-file", group: groupScanning, option: "Meta"},
+	{name: "precheck", group: groupScanning},
+	{name: "allow-empty", group: groupScanning, option: "AllowEmpty"},
+	{name: "keep-going", group: groupScanning, option: "KeepGoing"},
+	{name: "fail-fast", group: groupScanning, option: "FailFast"},
+	{name: "fail-on-warning", group: groupScanning, option: "FailOnWarning"},
+	{name: "cache-dir", group: groupScanning, option: "CacheDir"},
+	{name: "no-cache", group: groupScanning},
+	{name: "router-discovery", group: groupScanning, option: "RouterDiscovery"},
+	{name: "include-undocumented", group: groupScanning, option: "IncludeUndocumented"},
+	{name: "infer-param-types", group: groupScanning, option: "InferParamTypes"},
+	{name: "trailing-slash", group: groupScanning, option: "TrailingSlash"},
+	{name: "panic", group: groupScanning, option: "NoRecover"},
+	{name: "concurrency", group: groupScanning, option: "Concurrency"},
+
+	{name: "include", group: groupFiltering, option: "Include"},
+	{name: "exclude", group: groupFiltering, option: "Exclude"},
+	{name: "include-tags", group: groupFiltering, option: "IncludeTags"},
+	{name: "exclude-tags", group: groupFiltering, option: "ExcludeTags"},
+	{name: "require-all-include-tags", group: groupFiltering, option: "RequireAllIncludeTags"},
+	{name: "audience", group: groupFiltering, option: "Audience"},
+	{name: "require-audience", group: groupFiltering, option: "RequireAudience"},
+
+	{name: "ref-aliases", group: groupSchema, option: "RefAliases"},
+	{name: "transparent-aliases", group: groupSchema, option: "TransparentAliases"},
+	{name: "declaration-order", group: groupSchema, option: "DeclarationOrder"},
+	{name: "required-from-pointers", group: groupSchema, option: "RequiredFromPointers"},
+	{name: "required-from-pointers-pkg", group: groupSchema, option: "RequiredFromPointersPackages"},
+	{name: "omitempty-optional", group: groupSchema, option: "OmitEmptyAsOptional"},
+	{name: "validator-tags", group: groupSchema, option: "ValidatorTags"},
+	{name: "default-tag", group: groupSchema, option: "DefaultTag"},
+	{name: "auto-deprecate", group: groupSchema, option: "AutoDeprecate"},
+	{name: "deprecation-notice", group: groupSchema, option: "DeprecationNotice"},
+	{name: "type-mapping", group: groupSchema, option: "TypeMappings"},
+	{name: "max-schema-depth", group: groupSchema, option: "MaxSchemaDepth"},
+	{name: "discover-enums", group: groupSchema, option: "DiscoverEnums"},
+	{name: "max-enum-values", group: groupSchema, option: "MaxEnumValues"},
+	{name: "custom-formats", group: groupSchema, option: "CustomFormats"},
+	{name: "set-types", group: groupSchema, option: "SetTypes"},
+	{name: "default-idempotency", group: groupSchema, option: "DefaultIdempotency"},
+	{name: "security-default", group: groupSchema, option: "SecurityDefaults"},
+	{name: "require-security", group: groupSchema, option: "RequireSecurity"},
+	{name: "naming", group: groupSchema, option: "DefinitionNaming"},
+	{name: "preserve-comment-format", group: groupSchema, option: "PreserveCommentFormat"},
+	{name: "sort-parameters", group: groupSchema, option: "SortParameters"},
+	{name: "hoist-parameters", group: groupSchema, option: "HoistParameters"},
+	{name: "inline-single-use", group: groupSchema, option: "InlineSingleUse"},
+	{name: "merge-identical", group: groupSchema, option: "MergeIdentical"},
+	{name: "prune-unused", group: groupSchema, option: "PruneUnused"},
+	{name: "prune-input", group: groupSchema, option: "PruneInput"},
+
+	{name: "x-nullable-pointers", group: groupCompatibility, option: "SetXNullableForPointers"},
+	{name: "desc-with-ref", group: groupCompatibility, option: "DescWithRef"},
+	{name: "wrap-refs-for-description", group: groupCompatibility, option: "WrapRefsForDescription"},
+	{name: "enum-extension-style", group: groupCompatibility, option: "EnumExtensionStyle"},
+	{name: "binding-extensions", group: groupCompatibility, option: "BindingExtensions"},
+	{name: "rename-collisions", group: groupCompatibility, option: "RenameCollisions"},
+	{name: "relative-refs", group: groupCompatibility, option: "RelativeRefs"},
+	{name: "use-definition-index", group: groupCompatibility, option: "UseDefinitionIndex"},
+	{name: "strict-json-names", group: groupCompatibility, option: "StrictJSONNames"},
+	{name: "strict-formats", group: groupCompatibility, option: "StrictFormats"},
+	{name: "strict-parameters", group: groupCompatibility, option: "StrictParameters"},
+	{name: "strict-tags", group: groupCompatibility, option: "StrictTags"},
+	{name: "forbid-empty-schemas", group: groupCompatibility, option: "ForbidEmptySchemas"},
+	{name: "fail-on-secrets", group: groupCompatibility, option: "FailOnSecrets"},
+	{name: "validate-examples", group: groupCompatibility, option: "ValidateExamples"},
+}
+
+// checkFlagTable panics when the flags registered and generateFlags differ, so that a flag can't be added
+// without its section and option.
+func checkFlagTable(flags *pflag.FlagSet, table []generateFlag) {
+	listed := make(map[string]bool, len(table))
+	for _, entry := range table {
+		if flags.Lookup(entry.name) == nil {
+			panic(fmt.Sprintf("flag --%s is listed, but not registered", entry.name))
+		}
+		if !slices.Contains(flagGroups, entry.group) {
+			panic(fmt.Sprintf("flag --%s is listed in the unknown section %q", entry.name, entry.group))
+		}
+		listed[entry.name] = true
+	}
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if !listed[flag.Name] {
+			panic(fmt.Sprintf("flag --%s is registered, but not listed in generateFlags", flag.Name))
+		}
+	})
+}
+
+// groupedUsage prints the usage of a command sharing the generate flags, with the flags in the sections of
+// generateFlags. The others, e.g. --help, are listed last.
+func groupedUsage(cmd *cobra.Command) error {
+	w := cmd.OutOrStderr()
+	fmt.Fprintf(w, "Usage:\n  %s\n", cmd.UseLine())
+
+	flags := cmd.LocalFlags()
+	grouped := make(map[string]bool, len(generateFlags))
+	for _, group := range flagGroups {
+		section := pflag.NewFlagSet(group, pflag.ContinueOnError)
+		section.SortFlags = false
+		for _, entry := range generateFlags {
+			if flag := flags.Lookup(entry.name); flag != nil && entry.group == group {
+				section.AddFlag(flag)
+				grouped[entry.name] = true
+			}
+		}
+		if section.HasAvailableFlags() {
+			fmt.Fprintf(w, "\n%s Flags:\n%s", group, section.FlagUsages())
+		}
+	}
+
+	others := pflag.NewFlagSet("others", pflag.ContinueOnError)
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if !grouped[flag.Name] {
+			others.AddFlag(flag)
+		}
+	})
+	if others.HasAvailableFlags() {
+		fmt.Fprintf(w, "\nFlags:\n%s", others.FlagUsages())
+	}
+	return nil
+}
+
+// optionDescription describes a flag of the generate command for --list-options.
+type optionDescription struct {
+	Flag      string `json:"flag" yaml:"flag"`
+	Shorthand string `json:"shorthand,omitempty" yaml:"shorthand,omitempty"`
+	Section   string `json:"section" yaml:"section"`
+	Type      string `json:"type" yaml:"type"`
+	Default   string `json:"default" yaml:"default"`
+	Usage     string `json:"usage" yaml:"usage"`
+	Option    string `json:"option,omitempty" yaml:"option,omitempty"` // field of codescan.Options
+	Negated   bool   `json:"negated,omitempty" yaml:"negated,omitempty"`
+	Hidden    bool   `json:"hidden,omitempty" yaml:"hidden,omitempty"`
+}
+
+// writeOptionList writes the flags of the generate command, in the order of generateFlags, as JSON or YAML.
+func writeOptionList(w io.Writer, flags *pflag.FlagSet, outputFormat string) error {
+	descriptions := make([]optionDescription, 0, len(generateFlags))
+	for _, entry := range generateFlags {
+		flag := flags.Lookup(entry.name)
+		descriptions = append(descriptions, optionDescription{
+			Flag:      "--" + flag.Name,
+			Shorthand: flag.Shorthand,
+			Section:   entry.group,
+			Type:      flag.Value.Type(),
+			Default:   flag.DefValue,
+			Usage:     flag.Usage,
+			Option:    entry.option,
+			Negated:   entry.negated,
+			Hidden:    flag.Hidden,
+		})
+	}
+
+	switch strings.ToLower(outputFormat) {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(descriptions)
+	case "yaml", "yml":
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(descriptions); err != nil {
+			return err
+		}
+		return enc.Close()
+	default:
+		return fmt.Errorf("unsupported output format: %s", outputFormat)
+	}
+}
+
+// optionFlag returns the flag, or the config key, setting a field of codescan.Options.
+func optionFlag(option string) string {
+	for _, entry := range generateFlags {
+		if entry.option == option {
+			return "--" + entry.name
+		}
+	}
+	if key, ok := codescan.OptionsFileKey(option); ok {
+		return key + " (config)"
+	}
+	return option
+}
+
+// flagOption returns the field of codescan.Options a flag of the generate command sets, if any.
+func flagOption(name string) string {
+	for _, entry := range generateFlags {
+		if entry.name == name {
+			return entry.option
+		}
+	}
+	return ""
+}
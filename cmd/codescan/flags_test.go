@@ -0,0 +1,139 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/3idey/codescan/codescan"
+)
+
+func TestListOptions(t *testing.T) {
+	cli := buildCLI(t)
+	dir := t.TempDir()
+
+	for _, tc := range []struct {
+		format   string
+		decode   func([]byte, any) error
+		exitCode int
+		expected string
+	}{
+		{format: "json", decode: json.Unmarshal},
+		{format: "yaml", decode: yaml.Unmarshal},
+		{format: "xml", exitCode: 1, expected: "unsupported output format: xml"},
+	} {
+		t.Run("should list the options as "+tc.format, func(t *testing.T) {
+			out, code := runCLI(t, cli, dir, "generate", "--list-options", "--format", tc.format)
+			require.Equal(t, tc.exitCode, code, out)
+			if tc.decode == nil {
+				assert.Contains(t, out, tc.expected)
+				return
+			}
+
+			var listed []optionDescription
+			require.NoError(t, tc.decode([]byte(out), &listed))
+			require.Len(t, listed, len(generateFlags))
+			for i, option := range listed {
+				assert.Equal(t, "--"+generateFlags[i].name, option.Flag, "the options are listed in the order of the help")
+			}
+		})
+	}
+
+	t.Run("should list the options in sync with codescan.Options", func(t *testing.T) {
+		out, code := runCLI(t, cli, dir, "generate", "--list-options", "--format", "json")
+		require.Zero(t, code, out)
+		var listed []optionDescription
+		require.NoError(t, json.Unmarshal([]byte(out), &listed))
+
+		optionsType := reflect.TypeFor[codescan.Options]()
+		for _, option := range listed {
+			if option.Option == "" {
+				continue
+			}
+			field, found := optionsType.FieldByName(option.Option)
+			if !assert.True(t, found, "%s sets the unknown field %s of codescan.Options", option.Flag, option.Option) {
+				continue
+			}
+			switch option.Type {
+			case "bool":
+				// or an option the flag enables, e.g. --stats a pointer to the statistics, --quiet a Logger
+				assert.Contains(t, []reflect.Kind{reflect.Bool, reflect.Func, reflect.Pointer}, field.Type.Kind(), "%s sets %s", option.Flag, option.Option)
+			case "int":
+				assert.Equal(t, reflect.Int, field.Type.Kind(), "%s sets %s", option.Flag, option.Option)
+			}
+		}
+	})
+
+	t.Run("should list the flags of the help", func(t *testing.T) {
+		out, code := runCLI(t, cli, dir, "generate", "--help")
+		require.Zero(t, code, out)
+		for _, entry := range generateFlags {
+			if generateCmd.Flags().Lookup(entry.name).Hidden {
+				continue
+			}
+			assert.True(t, strings.Contains(out, "--"+entry.name+" "), "--%s is missing from the help", entry.name)
+		}
+		for _, group := range flagGroups {
+			assert.True(t, strings.Contains(out, "\n"+group+" Flags:\n"), "the %s section is missing from the help", group)
+		}
+	})
+}
+
+func TestCheckFlagTable(t *testing.T) {
+	flags := generateCmd.Flags()
+	require.NotPanics(t, func() { checkFlagTable(flags, generateFlags) })
+
+	for _, tc := range []struct {
+		name     string
+		table    func() []generateFlag
+		expected string
+	}{
+		{
+			name: "should refuse a flag registered, but not listed",
+			table: func() []generateFlag {
+				return slices.DeleteFunc(slices.Clone(generateFlags), func(entry generateFlag) bool { return entry.name == "scan-models" })
+			},
+			expected: "flag --scan-models is registered, but not listed in generateFlags",
+		},
+		{
+			name: "should refuse a flag listed, but not registered",
+			table: func() []generateFlag {
+				return append(slices.Clone(generateFlags), generateFlag{name: "scan-everything", group: groupScanning})
+			},
+			expected: "flag --scan-everything is listed, but not registered",
+		},
+		{
+			name: "should refuse a flag of an unknown section",
+			table: func() []generateFlag {
+				table := slices.Clone(generateFlags)
+				table[0].group = "Misc"
+				return table
+			},
+			expected: `flag --output is listed in the unknown section "Misc"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.PanicsWithValue(t, tc.expected, func() { checkFlagTable(flags, tc.table()) })
+		})
+	}
+}
+
+func TestOptionFlag(t *testing.T) {
+	for option, expected := range map[string]string{
+		"ScanModels":   "--scan-models",
+		"DefaultSkips": "--no-default-skips",
+	} {
+		assert.Equal(t, expected, optionFlag(option))
+	}
+	// the options without a flag are set by the config file
+	assert.True(t, strings.HasSuffix(optionFlag("ForceIncludeDirs"), " (config)"), optionFlag("ForceIncludeDirs"))
+}
@@ -0,0 +1,114 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+
+	"github.com/3idey/codescan/codescan"
+	"github.com/go-openapi/spec"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var (
+	// reportGraph makes runGenerate write the dependency graph of the spec instead of the spec.
+	reportGraph bool
+
+	// graph command flags
+	graphOutputFile    string
+	graphFormat        string
+	graphWithPositions bool
+	graphOptions       codescan.GraphOptions
+)
+
+var graphCmd = &cobra.Command{
+	Use:   "graph [packages...]",
+	Short: "Write the dependency graph of the operations and the definitions",
+	Long: `Scans the specified Go packages like generate, and writes the dependency graph
+of the spec: the operations, linked to the parameters, responses and definitions
+they use, themselves linked to the definitions they refer to. The subtypes of a
+polymorphic definition are linked to their base.
+
+--root keeps the operations of a path, or one of them with its method, and what
+they reach. --around keeps the nodes at most --depth links away from a
+definition, e.g. what uses it and what it uses. --with-positions adds the Go
+position of each node to its label.
+
+The graph is written in the DOT language of Graphviz, or as a Mermaid flowchart
+with --format mermaid.
+
+Examples:
+  codescan graph ./... -o graph.dot && dot -Tsvg graph.dot > graph.svg
+  codescan graph --root 'POST /v1/users' --format mermaid ./...
+  codescan graph --around User --depth 2 --with-positions ./...`,
+	Args: packagesArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if graphFormat != "dot" && graphFormat != "mermaid" {
+			return fmt.Errorf("unsupported graph format %q: expected dot or mermaid", graphFormat)
+		}
+		reportGraph = true
+		return runGenerateCommand(cmd, args)
+	},
+}
+
+func init() {
+	// the scan flags of generate, apart from those of its output
+	generateCmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if flag.Name != "output" && flag.Name != "format" {
+			graphCmd.Flags().AddFlag(flag)
+		}
+	})
+	flags := graphCmd.Flags()
+	flags.StringVarP(&graphOutputFile, "output", "o", "", "output file (default: stdout)")
+	flags.StringVar(&graphFormat, "format", "dot", "graph format: dot or mermaid")
+	flags.StringArrayVar(&graphOptions.Roots, "root", nil, "keep the operations of this path, or of this method and path, e.g. 'POST /v1/users', with what they reach; repeatable")
+	flags.StringVar(&graphOptions.Around, "around", "", "keep the nodes linked to this definition, at most --depth links away")
+	flags.IntVar(&graphOptions.Depth, "depth", 0, "number of links to --around of the nodes kept, 0 for any number")
+	flags.BoolVar(&graphWithPositions, "with-positions", false, "add the file:line of the Go source of each node to its label")
+	graphCmd.SetUsageFunc(groupedUsage)
+}
+
+// writeGraph writes the dependency graph of the spec, with the positions of the scan relative to the working
+// directory.
+func writeGraph(swspec *spec.Swagger, sourceMap map[string]token.Position) error {
+	if graphWithPositions {
+		base, err := filepath.Abs(workDir)
+		if err != nil {
+			return err
+		}
+		graphOptions.Positions = make(map[string]token.Position, len(sourceMap))
+		for pointer, pos := range sourceMap {
+			pos.Filename = codescan.RelativePath(base, pos.Filename)
+			graphOptions.Positions[pointer] = pos
+		}
+	}
+	graph, err := codescan.BuildGraph(swspec, graphOptions)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if graphFormat == "mermaid" {
+		err = graph.WriteMermaid(&buf)
+	} else {
+		err = graph.WriteDOT(&buf)
+	}
+	if err != nil {
+		return err
+	}
+	if graphOutputFile == "" {
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	if err := writeFileAtomic(resolvePath(graphOutputFile), buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write the graph: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Graph written to %s (%d nodes, %d links)\n", graphOutputFile, len(graph.Nodes), len(graph.Edges))
+	return nil
+}
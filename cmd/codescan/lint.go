@@ -0,0 +1,176 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/3idey/codescan/codescan"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// lint command flags
+	lintWorkDir    string
+	lintBuildTags  string
+	lintScanModels bool
+	lintConfigFile string
+	lintNoSuppress bool
+	lintCompat     []string
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint [packages...]",
+	Short: "Report the problems of the swagger annotations",
+	Long: `Scans the specified Go packages like generate, and reports the problems found
+in the annotations instead of writing the spec, e.g. a POST operation declared
+idempotent without an Idempotency-Key header, a status code written by the
+handler of an operation which its responses don't document, or an example
+holding a secret like an AWS access key.
+
+The rules of the --config file add checks of the spec, e.g. requiring a 204
+response for the DELETE operations, and set the severity of the built-in
+problems:
+
+  rules:
+    - name: delete-no-content
+      match: operation
+      where: method == 'DELETE'
+      require: responses.204 exists
+      severity: warning
+    - name: skipped-field
+      severity: "off"
+
+A problem is suppressed within a declaration or a field by a comment naming its
+rule, with a reason and an optional expiry date, after which it is reported
+again:
+
+  // codescan:ignore skipped-field until=2026-01-01 the channel is set by the server
+
+The suppressions are listed after the problems, for audits; --no-suppressions
+ignores them.
+
+The command fails when a problem with the error severity is found.
+
+Examples:
+  codescan lint ./...`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runLint,
+}
+
+func init() {
+	lintCmd.Flags().StringVarP(&lintWorkDir, "work-dir", "w", "", "working directory for package resolution")
+	lintCmd.Flags().StringVar(&lintBuildTags, "tags", "", "build tags to use when scanning")
+	lintCmd.Flags().BoolVar(&lintScanModels, "scan-models", false, "include models that are not referenced by operations")
+	lintCmd.Flags().StringVar(&lintConfigFile, "config", "", "YAML config file with lint rules")
+	lintCmd.Flags().BoolVar(&lintNoSuppress, "no-suppressions", false, "ignore the codescan:ignore comments, reporting all the problems")
+	lintCmd.Flags().StringSliceVar(&lintCompat, "compat", nil, "recognize the annotations of other dialects: legacy-go-swagger, e.g. swagger:params, with a warning each")
+}
+
+func runLint(cmd *cobra.Command, args []string) error {
+	var (
+		diagnostics  []codescan.Diagnostic
+		suppressions []codescan.Suppression
+	)
+	opts := &codescan.Options{
+		Packages:         args,
+		WorkDir:          lintWorkDir,
+		BuildTags:        lintBuildTags,
+		ScanModels:       lintScanModels,
+		Diagnostics:      &diagnostics,
+		Suppressions:     &suppressions,
+		NoSuppressions:   lintNoSuppress,
+		CheckStatusCodes: true,
+		CheckSecrets:     true,
+		Compat:           lintCompat,
+		// the annotations are checked even when there are none
+		AllowEmpty: true,
+	}
+	if lintConfigFile != "" {
+		cfg, err := loadConfig(lintConfigFile)
+		if err != nil {
+			return err
+		}
+		cfg.apply(opts, cmd.Flags())
+	}
+
+	// the diagnostics are reported below, rather than as warnings
+	log.SetOutput(io.Discard)
+	_, err := codescan.Run(opts)
+	log.SetOutput(os.Stderr)
+	if err != nil {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("scan failed: %w", err)
+	}
+
+	// the problems are not a misuse of the command
+	cmd.SilenceUsage = true
+	err = reportDiagnostics(os.Stdout, lintWorkDir, diagnostics)
+	if reportErr := reportSuppressions(os.Stdout, lintWorkDir, suppressions); reportErr != nil {
+		return reportErr
+	}
+	return err
+}
+
+// reportSuppressions lists the suppressions, with the number of problems they suppress, and those expired.
+func reportSuppressions(w io.Writer, workDir string, suppressions []codescan.Suppression) error {
+	if len(suppressions) == 0 {
+		return nil
+	}
+	base, err := filepath.Abs(workDir)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "\nsuppressions:\n")
+	for _, suppression := range suppressions {
+		status := fmt.Sprintf("%d suppressed", suppression.Suppressed)
+		switch {
+		case suppression.Expired:
+			status = "expired on " + suppression.Until
+		case suppression.Until != "":
+			status += ", until " + suppression.Until
+		}
+		pos := suppression.Pos
+		fmt.Fprintf(w, "%s:%d:%d: %s: %s (%s)\n", codescan.RelativePath(base, pos.Filename), pos.Line, pos.Column,
+			suppression.Code, suppression.Reason, status)
+	}
+	return nil
+}
+
+// reportDiagnostics prints the diagnostics, with file names relative to the working directory, and fails
+// when there are some with the error severity. Warnings are marked as such.
+func reportDiagnostics(w io.Writer, workDir string, diagnostics []codescan.Diagnostic) error {
+	base, err := filepath.Abs(workDir)
+	if err != nil {
+		return err
+	}
+	errorCount := 0
+	for _, diagnostic := range diagnostics {
+		message := diagnostic.Message
+		if diagnostic.Severity == codescan.SeverityWarning {
+			message = "warning: " + message
+		} else {
+			errorCount++
+		}
+		pos := diagnostic.Pos
+		if !pos.IsValid() {
+			fmt.Fprintf(w, "%s [%s]\n", message, diagnostic.Code)
+			continue
+		}
+		fmt.Fprintf(w, "%s:%d:%d: %s [%s]\n", codescan.RelativePath(base, pos.Filename), pos.Line, pos.Column, message, diagnostic.Code)
+	}
+
+	switch errorCount {
+	case 0:
+		return nil
+	case 1:
+		return fmt.Errorf("1 problem found")
+	default:
+		return fmt.Errorf("%d problems found", errorCount)
+	}
+}
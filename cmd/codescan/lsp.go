@@ -0,0 +1,591 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/3idey/codescan/codescan"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// lsp command flags
+	lspWorkDir    string
+	lspBuildTags  string
+	lspConfigFile string
+)
+
+var lspCmd = &cobra.Command{
+	Use:   "lsp [packages...]",
+	Short: "Serve the problems and the documentation of the annotations to editors",
+	Long: `Speaks the language server protocol on stdin and stdout, for the editors. For the
+Go files open in the editor, it publishes:
+
+  - as you type, the problems precheck finds in the package of the file, reading
+    the unsaved buffers instead of the files
+  - when a file is opened or saved, the problems lint finds in the specified
+    packages, with the lint rules of the --config file
+
+The hover of a swagger: annotation, or of a key of a comment, e.g. Maximum,
+documents it, and the keys and the annotations are completed in the comments
+holding swagger: annotations.
+
+The packages default to those of the --config file, or to ./... in the working
+directory.
+
+Examples:
+  codescan lsp ./...`,
+	RunE: runLSP,
+}
+
+func init() {
+	lspCmd.Flags().StringVarP(&lspWorkDir, "work-dir", "w", "", "working directory for package resolution")
+	lspCmd.Flags().StringVar(&lspBuildTags, "tags", "", "build tags to use when scanning")
+	lspCmd.Flags().StringVar(&lspConfigFile, "config", "", "YAML config file with lint rules")
+}
+
+func runLSP(cmd *cobra.Command, args []string) error {
+	opts := &codescan.Options{
+		Packages:  args,
+		WorkDir:   lspWorkDir,
+		BuildTags: lspBuildTags,
+		// the checks of lint
+		CheckStatusCodes: true,
+		CheckSecrets:     true,
+		AllowEmpty:       true,
+	}
+	if lspConfigFile != "" {
+		cfg, err := loadConfig(lspConfigFile)
+		if err != nil {
+			return err
+		}
+		cfg.apply(opts, cmd.Flags())
+	}
+	if len(opts.Packages) == 0 {
+		opts.Packages = []string{"./..."}
+	}
+
+	// stdout is the connection with the editor: the problems of the scans are published instead
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+	cmd.SilenceUsage = true
+	return newLSPServer(opts, os.Stdout).serve(os.Stdin)
+}
+
+// The codes of the errors of the responses, see the language server protocol.
+const (
+	lspInvalidParams  = -32602
+	lspMethodNotFound = -32601
+)
+
+// The kinds of the values of the protocol.
+const (
+	lspSeverityError     = 1  // severity of a diagnostic
+	lspSeverityWarning   = 2  // severity of a diagnostic
+	lspMessageError      = 1  // type of a logged message
+	lspCompletionKeyword = 14 // kind of a completion item
+)
+
+// lspMessage is a request, a notification or a response of the language server protocol, over JSON-RPC.
+type lspMessage struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Method  string           `json:"method,omitempty"`
+	Params  json.RawMessage  `json:"params,omitempty"`
+}
+
+// lspResponse answers a request with its result, which may be null.
+type lspResponse struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id"`
+	Result  any              `json:"result"`
+}
+
+// lspErrorResponse answers a request with an error, without result.
+type lspErrorResponse struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id"`
+	Error   lspError         `json:"error"`
+}
+
+type lspError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type lspPosition struct {
+	Line      int `json:"line"`
+	Character int `json:"character"` // in UTF-16 code units
+}
+
+type lspRange struct {
+	Start lspPosition `json:"start"`
+	End   lspPosition `json:"end"`
+}
+
+type lspDiagnostic struct {
+	Range    lspRange `json:"range"`
+	Severity int      `json:"severity"`
+	Code     string   `json:"code"`
+	Source   string   `json:"source"`
+	Message  string   `json:"message"`
+}
+
+type lspCompletionItem struct {
+	Label         string `json:"label"`
+	Kind          int    `json:"kind"`
+	Detail        string `json:"detail,omitempty"`
+	Documentation string `json:"documentation,omitempty"`
+	InsertText    string `json:"insertText,omitempty"`
+}
+
+// lspDocumentParams are the params of the notifications and requests about a document.
+type lspDocumentParams struct {
+	TextDocument struct {
+		URI  string `json:"uri"`
+		Text string `json:"text"`
+	} `json:"textDocument"`
+	ContentChanges []struct {
+		Text string `json:"text"`
+	} `json:"contentChanges"`
+	Position lspPosition `json:"position"`
+}
+
+// lspInput is a message read from the editor, or the error ending the connection.
+type lspInput struct {
+	message *lspMessage
+	err     error
+}
+
+// lspServer publishes the problems of the open documents, and answers the hovers and the completions.
+type lspServer struct {
+	opts *codescan.Options // options of the lint, with the packages
+	out  io.Writer
+
+	documents map[string]string                // contents of the open documents, by path
+	prechecks map[string][]codescan.Diagnostic // problems found by precheck, by document
+	lint      []codescan.Diagnostic            // problems found by the last lint
+	changed   map[string]bool                  // documents changed since their last precheck
+	stale     bool                             // a document was opened or saved since the last lint
+	shutdown  bool
+}
+
+func newLSPServer(opts *codescan.Options, out io.Writer) *lspServer {
+	return &lspServer{
+		opts:      opts,
+		out:       out,
+		documents: make(map[string]string),
+		prechecks: make(map[string][]codescan.Diagnostic),
+		changed:   make(map[string]bool),
+	}
+}
+
+// serve answers the messages of the editor until it exits. The problems are refreshed once no message is
+// waiting, so that a burst of changes while typing runs a single precheck.
+func (s *lspServer) serve(in io.Reader) error {
+	inputs := make(chan lspInput, 64)
+	go func() {
+		reader := bufio.NewReader(in)
+		for {
+			message, err := readLSPMessage(reader)
+			inputs <- lspInput{message: message, err: err}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	for input := range inputs {
+		if input.err != nil {
+			if errors.Is(input.err, io.EOF) && s.shutdown {
+				return nil
+			}
+			return fmt.Errorf("lsp: %w", input.err)
+		}
+		if input.message.Method == "exit" {
+			if !s.shutdown {
+				return errors.New("lsp: exit before shutdown")
+			}
+			return nil
+		}
+		if err := s.handle(input.message); err != nil {
+			return err
+		}
+		if len(inputs) == 0 {
+			if err := s.refresh(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// handle answers a message, leaving the problems of the documents it changes to refresh.
+func (s *lspServer) handle(message *lspMessage) error {
+	var params lspDocumentParams
+	if len(message.Params) > 0 {
+		if err := json.Unmarshal(message.Params, &params); err != nil {
+			return s.respondError(message, lspInvalidParams, err.Error())
+		}
+	}
+	path := uriPath(params.TextDocument.URI)
+
+	switch message.Method {
+	case "initialize":
+		return s.respond(message, map[string]any{
+			"capabilities": map[string]any{
+				// the documents are sent whole at each change
+				"textDocumentSync":   map[string]any{"openClose": true, "change": 1, "save": true},
+				"hoverProvider":      true,
+				"completionProvider": map[string]any{"triggerCharacters": []string{":"}},
+			},
+			"serverInfo": map[string]string{"name": "codescan", "version": version},
+		})
+	case "shutdown":
+		s.shutdown = true
+		return s.respond(message, nil)
+	case "textDocument/didOpen":
+		s.documents[path] = params.TextDocument.Text
+		s.changed[path], s.stale = true, true
+	case "textDocument/didChange":
+		if len(params.ContentChanges) > 0 {
+			s.documents[path] = params.ContentChanges[len(params.ContentChanges)-1].Text
+			s.changed[path] = true
+		}
+	case "textDocument/didSave":
+		s.changed[path], s.stale = true, true
+	case "textDocument/didClose":
+		delete(s.documents, path)
+		delete(s.prechecks, path)
+		delete(s.changed, path)
+		return s.publish(path, nil)
+	case "textDocument/hover":
+		return s.respond(message, s.hover(path, params.Position))
+	case "textDocument/completion":
+		return s.respond(message, s.complete(path, params.Position))
+	default:
+		if message.ID != nil {
+			return s.respondError(message, lspMethodNotFound, "unsupported method "+message.Method)
+		}
+	}
+	return nil
+}
+
+// refresh runs the lint when a document was opened or saved, and precheck for the changed documents, then
+// publishes their problems.
+func (s *lspServer) refresh() error {
+	published := s.changed
+	if s.stale {
+		s.stale = false
+		s.lint = s.runLint()
+		published = make(map[string]bool, len(s.documents))
+		for path := range s.documents {
+			published[path] = true
+		}
+	}
+	if len(s.changed) > 0 {
+		s.runPrecheck()
+		s.changed = make(map[string]bool)
+	}
+
+	for _, path := range slices.Sorted(maps.Keys(published)) {
+		var diagnostics []codescan.Diagnostic
+		seen := make(map[string]bool)
+		for _, diagnostic := range slices.Concat(s.prechecks[path], s.lint) {
+			key := fmt.Sprintf("%d:%s:%s", diagnostic.Pos.Line, diagnostic.Code, diagnostic.Message)
+			if filepath.Clean(diagnostic.Pos.Filename) != path || seen[key] {
+				continue
+			}
+			seen[key] = true
+			diagnostics = append(diagnostics, diagnostic)
+		}
+		if err := s.publish(path, diagnostics); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runLint scans the packages like lint, with the open documents. A failed scan is logged to the editor:
+// its errors are the problems precheck finds, in most cases.
+func (s *lspServer) runLint() []codescan.Diagnostic {
+	var diagnostics []codescan.Diagnostic
+	opts := *s.opts
+	opts.Diagnostics = &diagnostics
+	opts.Overlay = s.overlay()
+	if _, err := codescan.Run(&opts); err != nil {
+		s.logMessage(fmt.Sprintf("scan failed: %v", err))
+		return nil
+	}
+	return diagnostics
+}
+
+// runPrecheck checks the packages of the changed documents, reading the open documents.
+func (s *lspServer) runPrecheck() {
+	var dirs []string
+	for path := range s.changed {
+		if _, open := s.documents[path]; open && strings.HasSuffix(path, ".go") {
+			dirs = append(dirs, filepath.Dir(path))
+		}
+		delete(s.prechecks, path)
+	}
+	if len(dirs) == 0 {
+		return
+	}
+	slices.Sort(dirs)
+
+	opts := *s.opts
+	opts.Packages = slices.Compact(dirs)
+	opts.Overlay = s.overlay()
+	diagnostics, err := codescan.Precheck(&opts)
+	if err != nil {
+		s.logMessage(fmt.Sprintf("precheck failed: %v", err))
+		return
+	}
+	for _, diagnostic := range diagnostics {
+		path := filepath.Clean(diagnostic.Pos.Filename)
+		if s.changed[path] {
+			s.prechecks[path] = append(s.prechecks[path], diagnostic)
+		}
+	}
+}
+
+// overlay holds the contents of the open documents, which may not be saved.
+func (s *lspServer) overlay() map[string][]byte {
+	overlay := make(map[string][]byte, len(s.documents))
+	for path, text := range s.documents {
+		overlay[path] = []byte(text)
+	}
+	return overlay
+}
+
+// hover documents the directive at a position, or returns nil.
+func (s *lspServer) hover(path string, position lspPosition) any {
+	line, offset, found := s.documentLine(path, position)
+	if !found {
+		return nil
+	}
+	directive, found := codescan.DirectiveAt(line, offset)
+	if !found {
+		return nil
+	}
+	value := fmt.Sprintf("**%s**\n\n%s", directive.Name, directive.Doc)
+	if directive.Syntax != "" {
+		value = fmt.Sprintf("```\n%s\n```\n\n%s", directive.Syntax, value)
+	}
+	return map[string]any{"contents": map[string]string{"kind": "markdown", "value": value}}
+}
+
+var (
+	// rxAnnotationPrefix matches an annotation being typed, e.g. "// swagger:ro".
+	rxAnnotationPrefix = regexp.MustCompile(`swagger:([\p{L}\p{N}\p{Pd}\p{Pc}]*)$`)
+	// rxKeyPrefix matches a key being typed at the start of a line of a comment, e.g. "//	Max".
+	rxKeyPrefix = regexp.MustCompile(`^[\p{Zs}\t]*(?://|\*)[\p{Zs}\t]*[A-Za-z]*$`)
+)
+
+// complete lists the annotations after swagger:, and the keys at the start of the lines of the comments
+// holding an annotation.
+func (s *lspServer) complete(path string, position lspPosition) []lspCompletionItem {
+	line, offset, found := s.documentLine(path, position)
+	if !found {
+		return nil
+	}
+	typed := line[:offset]
+	annotation := rxAnnotationPrefix.MatchString(typed)
+	if !annotation && !(rxKeyPrefix.MatchString(typed) && s.inAnnotatedComment(path, position.Line)) {
+		return nil
+	}
+
+	items := []lspCompletionItem{}
+	for _, directive := range codescan.Directives() {
+		if directive.Annotation != annotation {
+			continue
+		}
+		item := lspCompletionItem{Label: directive.Name, Kind: lspCompletionKeyword, Detail: directive.Syntax, Documentation: directive.Doc}
+		if annotation {
+			item.Label = strings.TrimPrefix(directive.Name, "swagger:")
+		} else {
+			item.InsertText = directive.Name + ": "
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// inAnnotatedComment tells whether a line is in a block of line comments holding a swagger: annotation.
+func (s *lspServer) inAnnotatedComment(path string, line int) bool {
+	lines := strings.Split(s.documents[path], "\n")
+	isComment := func(i int) bool {
+		return strings.HasPrefix(strings.TrimSpace(lines[i]), "//")
+	}
+	first, last := line, line
+	for first > 0 && isComment(first-1) {
+		first--
+	}
+	for last+1 < len(lines) && isComment(last+1) {
+		last++
+	}
+	return slices.ContainsFunc(lines[first:last+1], func(text string) bool {
+		return strings.Contains(text, "swagger:")
+	})
+}
+
+// documentLine returns the line of an open document at a position, with the offset of the position in bytes.
+func (s *lspServer) documentLine(path string, position lspPosition) (string, int, bool) {
+	text, open := s.documents[path]
+	if !open {
+		return "", 0, false
+	}
+	lines := strings.Split(text, "\n")
+	if position.Line < 0 || position.Line >= len(lines) {
+		return "", 0, false
+	}
+	line := strings.TrimSuffix(lines[position.Line], "\r")
+	return line, byteOffset(line, position.Character), true
+}
+
+// publish replaces the problems of a document shown by the editor.
+func (s *lspServer) publish(path string, diagnostics []codescan.Diagnostic) error {
+	lines := strings.Split(s.documents[path], "\n")
+	published := make([]lspDiagnostic, 0, len(diagnostics))
+	for _, diagnostic := range diagnostics {
+		severity := lspSeverityError
+		if diagnostic.Severity == codescan.SeverityWarning {
+			severity = lspSeverityWarning
+		}
+		// the problems are shown from their column to the end of their line
+		var start, end lspPosition
+		if line := diagnostic.Pos.Line - 1; line >= 0 {
+			start.Line, end.Line = line, line
+			if line < len(lines) {
+				text := strings.TrimSuffix(lines[line], "\r")
+				start.Character = utf16Length(text[:min(max(diagnostic.Pos.Column-1, 0), len(text))])
+				end.Character = utf16Length(text)
+			}
+		}
+		published = append(published, lspDiagnostic{
+			Range:    lspRange{Start: start, End: end},
+			Severity: severity,
+			Code:     diagnostic.Code,
+			Source:   "codescan",
+			Message:  diagnostic.Message,
+		})
+	}
+	return s.notify("textDocument/publishDiagnostics", map[string]any{"uri": pathURI(path), "diagnostics": published})
+}
+
+// logMessage logs a message to the editor, e.g. a failed scan.
+func (s *lspServer) logMessage(message string) {
+	_ = s.notify("window/logMessage", map[string]any{"type": lspMessageError, "message": message})
+}
+
+func (s *lspServer) respond(request *lspMessage, result any) error {
+	if request.ID == nil {
+		return nil
+	}
+	return writeLSPMessage(s.out, lspResponse{JSONRPC: "2.0", ID: request.ID, Result: result})
+}
+
+func (s *lspServer) respondError(request *lspMessage, code int, message string) error {
+	if request.ID == nil {
+		return nil
+	}
+	return writeLSPMessage(s.out, lspErrorResponse{JSONRPC: "2.0", ID: request.ID, Error: lspError{Code: code, Message: message}})
+}
+
+func (s *lspServer) notify(method string, params any) error {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return writeLSPMessage(s.out, lspMessage{JSONRPC: "2.0", Method: method, Params: data})
+}
+
+// readLSPMessage reads a message, framed by its Content-Length header.
+func readLSPMessage(reader *bufio.Reader) (*lspMessage, error) {
+	header, err := textproto.NewReader(reader).ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Content-Length header: %w", err)
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(reader, data); err != nil {
+		return nil, err
+	}
+	var message lspMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		return nil, fmt.Errorf("invalid message: %w", err)
+	}
+	return &message, nil
+}
+
+func writeLSPMessage(w io.Writer, message any) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "Content-Length: %d\r\n\r\n%s", len(data), data)
+	return err
+}
+
+// uriPath returns the path of a file URI, e.g. file:///src/api.go.
+func uriPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+	path := u.Path
+	if filepath.Separator == '\\' {
+		// file:///C:/src/api.go
+		path = strings.TrimPrefix(path, "/")
+	}
+	return filepath.Clean(filepath.FromSlash(path))
+}
+
+func pathURI(path string) string {
+	slashed := filepath.ToSlash(path)
+	if !strings.HasPrefix(slashed, "/") {
+		slashed = "/" + slashed
+	}
+	return (&url.URL{Scheme: "file", Path: slashed}).String()
+}
+
+// byteOffset converts an offset in UTF-16 code units, as the positions of the protocol, to bytes.
+func byteOffset(line string, character int) int {
+	units := 0
+	for i, r := range line {
+		if units >= character {
+			return i
+		}
+		units += utf16.RuneLen(r)
+	}
+	return len(line)
+}
+
+func utf16Length(text string) int {
+	units := 0
+	for _, r := range text {
+		units += utf16.RuneLen(r)
+	}
+	return units
+}
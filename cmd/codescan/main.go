@@ -7,14 +7,22 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"go/token"
+	"io"
+	"log"
 	"os"
+	"os/signal"
+	"path/filepath"
 	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
 
 	"github.com/3idey/codescan/codescan"
 	"github.com/go-openapi/spec"
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
 var (
@@ -43,38 +51,128 @@ comments to build a complete API specification.`,
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Print version information",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		fmt.Printf("codescan %s\n", version)
 		fmt.Printf("  commit: %s\n", commit)
 		fmt.Printf("  built:  %s\n", date)
+		if versionVerbose {
+			return writeBuildInfo(os.Stdout, generateCmd.Flags())
+		}
+		return nil
 	},
 }
 
 var (
 	// generate command flags
-	outputFile              string
+	outputFiles             []string
 	outputFormat            string
 	workDir                 string
 	buildTags               string
+	extraBuildTags          string
 	scanModels              bool
 	excludeDeps             bool
+	includeTestScope        bool
 	includes                []string
 	excludes                []string
 	includeTags             []string
 	excludeTags             []string
 	inputSpec               string
+	downgradeInput          bool
+	metaFile                string
 	setXNullableForPointers bool
 	refAliases              bool
 	transparentAliases      bool
 	descWithRef             bool
+	declarationOrder        bool
+	requiredFromPointers    bool
+	requiredFromPtrPkgs     []string
+	inlineSingleUse         bool
+	reportSingleUse         bool
+	mergeIdentical          bool
+	forbidEmptySchemas      bool
+	reportIdentical         bool
 	compact                 bool
+	configFile              string
+	printStats              bool
+	showProgress            bool
+	checkOutputs            bool
+	relativeRefs            string
+	definitionIndex         string
+	definitionIndexLocation string
+	useDefinitionIndex      string
+	sourceMapFile           string
+	noDefaultSkips          bool
+	alsoScan                []string
+	verbose                 bool
+	codeSamples             bool
+	defaultIdempotency      bool
+	descriptionCatalog      string
+	markUntranslated        bool
+	strictJSONNames         bool
+	maxSchemaDepth          int
+	enumExtensionStyle      string
+	customFormats           []string
+	strictFormats           bool
+	audience                []string
+	requireAudience         bool
+	runPrecheckFirst        bool
+	strictParameters        bool
+	requireAllIncludeTags   bool
+	keepGoing               bool
+	noRecover               bool
+	failOnSecrets           bool
+	validateExamples        bool
+	noExamples              bool
+	emitGenerationInfo      bool
+	reproducible            bool
+	setTypes                []string
+	specVersion             string
+	allowEmpty              bool
+	discoverEnums           bool
+	maxEnumValues           int
+	bindingExtensions       bool
+	renameCollisions        bool
+	sortParameters          bool
+	hoistParameters         int
+	omitEmptyOptional       bool
+	cacheDir                string
+	noCache                 bool
+	strictTags              bool
+	validatorTags           []string
+	typeMappings            []string
+	routerDiscovery         string
+	includeUndocumented     bool
+	securityDefaults        []string
+	quiet                   bool
+	outputSets              []string
+	wrapRefs                bool
+	failFast                bool
+	failOnWarning           bool
+	pruneUnused             bool
+	pruneInput              bool
+	reportUnused            bool
+	requireSecurity         bool
+	definitionNaming        string
+	preserveCommentFormat   bool
+	concurrency             int
+	defaultTag              string
+	autoDeprecate           bool
+	deprecationNotice       string
+	explainPackages         []string
+	mergeStrategy           string
+	dryRunMerge             bool
+	compat                  []string
+	inferParamTypes         bool
+	loaderEnv               []string
+	packagesDriver          string
+	trailingSlash           string
 )
 
 var generateCmd = &cobra.Command{
 	Use:   "generate [packages...]",
 	Short: "Generate a swagger spec from annotated Go code",
 	Long: `Scans the specified Go packages for swagger annotations and generates
-an OpenAPI 2.0 specification.
+an OpenAPI 2.0 specification, or an OpenAPI 3.0 one with --spec-version 3.0.
 
 Examples:
   # Generate spec for current package
@@ -83,51 +181,284 @@ Examples:
   # Generate spec with custom output
   codescan generate -o api.yaml --format yaml ./cmd/server
 
+  # Generate JSON and YAML from a single scan
+  codescan generate -o dist/swagger.json -o dist/swagger.yaml ./...
+
+  # Regenerate the spec whenever the sources change
+  codescan generate --watch -o dist/swagger.json ./...
+
+  # Generate an OpenAPI 3.0 document from the same annotations
+  codescan generate --spec-version 3.0 -o dist/openapi.json ./...
+
   # Generate spec with build tags
-  codescan generate --tags=integration ./...`,
-	Args: cobra.MinimumNArgs(1),
-	RunE: runGenerate,
+  codescan generate --tags=integration ./...
+
+  # Generate the spec configured by the .codescan.yaml of the current directory
+  codescan generate
+
+  # Generate the specs of all the services of the config file
+  codescan generate --config codescan.yaml --all-services
+
+  # Scan another module, writing the spec in its docs directory
+  codescan generate -w ../api -o workdir:docs/swagger.json ./...
+
+` + pathsHelp,
+	Args: packagesArgs,
+	RunE: runGenerateCommand,
 }
 
 func init() {
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(generateCmd)
+	rootCmd.AddCommand(convertCmd)
+	rootCmd.AddCommand(bundleCmd)
+	rootCmd.AddCommand(pruneCmd)
+	rootCmd.AddCommand(lintCmd)
+	rootCmd.AddCommand(validateCmd)
+	rootCmd.AddCommand(precheckCmd)
+	rootCmd.AddCommand(baselineCmd)
+	rootCmd.AddCommand(diffCmd)
+	rootCmd.AddCommand(extractStringsCmd)
+	rootCmd.AddCommand(statsCmd)
+	rootCmd.AddCommand(lspCmd)
+	rootCmd.AddCommand(genfixtureCmd)
+	rootCmd.AddCommand(migrateAnnotationsCmd)
+	rootCmd.AddCommand(graphCmd)
+
+	generateCmd.Flags().StringVar(&configFile, "config", "", "YAML config file setting the options and flags, see Config file in the README (default: .codescan.yaml, if it exists)")
+	generateCmd.Flags().BoolVar(&allServices, "all-services", false, "generate the specs of all the services of the config file")
+	generateCmd.Flags().StringSliceVar(&serviceNames, "service", nil, "generate the specs of these services of the config file")
 
 	// Output flags
-	generateCmd.Flags().StringVarP(&outputFile, "output", "o", "", "output file (default: stdout)")
-	generateCmd.Flags().StringVar(&outputFormat, "format", "json", "output format: json or yaml")
+	generateCmd.Flags().StringArrayVarP(&outputFiles, "output", "o", nil, "output file, repeatable, with the format inferred from its extension (default: stdout)")
+	generateCmd.Flags().StringVar(&outputFormat, "format", "json", "output format: json or yaml, for stdout and files without a known extension")
+	generateCmd.Flags().StringArrayVar(&outputSets, "output-set", nil, "also write the spec of the operations of some tags, repeatable, e.g. public=public.yaml:tags=public; see Output sets in the README")
+	generateCmd.Flags().BoolVar(&checkOutputs, "check", false, "verify that the output files are up to date instead of writing them")
+	generateCmd.Flags().StringVar(&specVersion, "spec-version", "2.0", "version of the output document: 2.0 (swagger) or 3.0 (openapi)")
 
 	// Scan options
-	generateCmd.Flags().StringVarP(&workDir, "work-dir", "w", "", "working directory for package resolution")
+	generateCmd.Flags().StringVarP(&workDir, "work-dir", "w", "", "working directory for package resolution, and for the file paths prefixed with workdir:")
 	generateCmd.Flags().StringVar(&buildTags, "tags", "", "build tags to use when scanning")
+	generateCmd.Flags().StringVar(&extraBuildTags, "extra-tags", "", "build tags of documentation-only files, only used to find annotations")
+	generateCmd.Flags().StringArrayVar(&loaderEnv, "env", nil, "environment variable of the go command or driver loading the packages, repeatable, e.g. GOFLAGS=-mod=vendor")
+	generateCmd.Flags().StringVar(&packagesDriver, "packages-driver", "", "GOPACKAGESDRIVER loading the packages, e.g. a Bazel gopackagesdriver, or off for the go command")
 	generateCmd.Flags().BoolVar(&scanModels, "scan-models", false, "include models that are not referenced by operations")
 	generateCmd.Flags().BoolVar(&excludeDeps, "exclude-deps", false, "exclude dependencies from scanning")
+	generateCmd.Flags().StringSliceVar(&compat, "compat", nil, "recognize the annotations of other dialects: legacy-go-swagger, e.g. swagger:params, with a warning each")
+	generateCmd.Flags().StringSliceVar(&explainPackages, "explain-package", nil, "import paths of packages to tell on stderr whether they were loaded, classified or excluded, and by which rule")
+	generateCmd.Flags().BoolVar(&noDefaultSkips, "no-default-skips", false, "scan the packages in vendor, third_party and testdata directories")
+	generateCmd.Flags().StringArrayVar(&alsoScan, "also-scan", nil, "directory glob scanned despite the default skips, e.g. vendor/github.com/acme/...")
+	generateCmd.Flags().BoolVar(&runPrecheckFirst, "precheck", false, "check the grammar of the annotations before the scan, failing fast on malformed ones, see precheck")
+	generateCmd.Flags().BoolVar(&includeTestScope, "include-test-scope", false, "include declarations annotated with scope:test, scanning _test.go files")
+	generateCmd.Flags().BoolVar(&keepGoing, "keep-going", false, "write the spec without the declarations whose building panicked, rather than failing")
+	generateCmd.Flags().BoolVar(&failFast, "fail-fast", false, "stop the scan at the first mistake of the annotations, rather than reporting them all")
+	generateCmd.Flags().BoolVar(&failOnWarning, "fail-on-warning", false, "fail when the scan reports warnings, e.g. the malformed annotations it ignores, rather than only printing them")
+	generateCmd.Flags().StringVar(&cacheDir, "cache-dir", "", "cache the scans in this directory, returning the cached spec while the Go files and the options don't change, and the models of the unchanged packages otherwise")
+	generateCmd.Flags().BoolVar(&noCache, "no-cache", false, "scan without the --cache-dir, e.g. set by a service of the config file")
+	generateCmd.Flags().StringVar(&routerDiscovery, "router-discovery", "", "infer the routes registered with a router: chi, gin or echo, besides the swagger:route annotations")
+	generateCmd.Flags().BoolVar(&includeUndocumented, "include-undocumented", false, "keep the routes of --router-discovery whose handler has no doc comment, as minimal operations marked x-undocumented")
+	generateCmd.Flags().BoolVar(&inferParamTypes, "infer-param-types", false, "type the path parameters added for the route constraints, e.g. {id:[0-9]+}, as integers when they only match integers")
+	generateCmd.Flags().StringVar(&trailingSlash, "trailing-slash", "", "policy of the slash ending the paths, of the scan and of the input spec: keep, or strip, e.g. /users/ as /users (default: keep)")
+	generateCmd.Flags().BoolVar(&noRecover, "panic", false, "let the panics of the builders crash with their stack trace, for debugging codescan")
+	_ = generateCmd.Flags().MarkHidden("panic")
 
 	// Include/Exclude filters
 	generateCmd.Flags().StringSliceVar(&includes, "include", nil, "patterns to include")
 	generateCmd.Flags().StringSliceVar(&excludes, "exclude", nil, "patterns to exclude")
 	generateCmd.Flags().StringSliceVar(&includeTags, "include-tags", nil, "tags to include")
-	generateCmd.Flags().StringSliceVar(&excludeTags, "exclude-tags", nil, "tags to exclude")
+	generateCmd.Flags().StringSliceVar(&excludeTags, "exclude-tags", nil, "tags to exclude, whatever the other tags of an operation")
+	generateCmd.Flags().BoolVar(&requireAllIncludeTags, "require-all-include-tags", false, "keep the operations with all the --include-tags, rather than one of them")
+	generateCmd.Flags().StringSliceVar(&audience, "audience", nil, "keep the operations of these x-audience values, e.g. public, and what only they use")
+	generateCmd.Flags().BoolVar(&requireAudience, "require-audience", false, "fail when an operation has no x-audience, rather than making it public")
 
 	// Input spec
 	generateCmd.Flags().StringVarP(&inputSpec, "input", "i", "", "input swagger spec to merge with")
+	generateCmd.Flags().StringVar(&mergeStrategy, "merge-strategy", "", "merge the scan with the --input spec key by key, the conflicts resolved by scan-wins, input-wins or error, rather than building over it")
+	generateCmd.Flags().BoolVar(&dryRunMerge, "dry-run-merge", false, "list on stdout whether each entry of the merged spec comes from the --input spec, the scan or both, instead of writing the spec")
+	generateCmd.Flags().BoolVar(&downgradeInput, "downgrade-input", false, "convert an OpenAPI 3.x input spec to swagger 2.0 before merging")
+	generateCmd.Flags().StringVar(&metaFile, "meta-file", "", "YAML file with meta information overriding swagger:meta")
 
 	// Schema options
 	generateCmd.Flags().BoolVar(&setXNullableForPointers, "x-nullable-pointers", false, "set x-nullable for pointer types")
 	generateCmd.Flags().BoolVar(&refAliases, "ref-aliases", false, "use $ref for type aliases")
 	generateCmd.Flags().BoolVar(&transparentAliases, "transparent-aliases", false, "make type aliases completely transparent")
 	generateCmd.Flags().BoolVar(&descWithRef, "desc-with-ref", false, "allow descriptions together with $ref")
+	generateCmd.Flags().BoolVar(&wrapRefs, "wrap-refs-for-description", false, "keep the descriptions of the $refs by wrapping them in an allOf, rather than next to them like --desc-with-ref")
+	generateCmd.Flags().BoolVar(&declarationOrder, "declaration-order", false, "emit x-order on properties and keep struct field declaration order")
+	generateCmd.Flags().BoolVar(&requiredFromPointers, "required-from-pointers", false, "mark non-pointer fields without omitempty as required")
+	generateCmd.Flags().StringSliceVar(&requiredFromPtrPkgs, "required-from-pointers-pkg", nil, "package globs the required-from-pointers convention applies to")
+	generateCmd.Flags().BoolVar(&strictJSONNames, "strict-json-names", false, "fail when tagged struct fields have the same json name at the same embedding depth")
+	generateCmd.Flags().IntVar(&maxSchemaDepth, "max-schema-depth", 50, "fail when a schema nests more levels of properties and items than this, 0 for no limit")
+	generateCmd.Flags().StringVar(&enumExtensionStyle, "enum-extension-style", "", "emit the names of enum values and discriminator mappings with the extensions of: go-swagger, nswag or both")
+	generateCmd.Flags().BoolVar(&discoverEnums, "discover-enums", false, "document the exported constants of the named basic types as their enums, without swagger:enum")
+	generateCmd.Flags().IntVar(&maxEnumValues, "max-enum-values", codescan.DefaultMaxEnumValues, "omit the enums of more values than this, naming their Go type with x-enum-reference, 0 for no limit")
+	generateCmd.Flags().BoolVar(&bindingExtensions, "binding-extensions", false, "emit x-go-field, x-go-type and x-go-decoder on the parameters of swagger:parameters structs")
+	generateCmd.Flags().BoolVar(&renameCollisions, "rename-collisions", false, "rename the definitions whose names break the generators, e.g. Error to ErrorModel")
+	generateCmd.Flags().BoolVar(&sortParameters, "sort-parameters", false, "order the parameters of the operations by location, then name")
+	generateCmd.Flags().IntVar(&hoistParameters, "hoist-parameters", 0, "replace the inline parameters identical in this number of operations or more with refs to shared parameters, 0 for none")
+	generateCmd.Flags().BoolVar(&omitEmptyOptional, "omitempty-optional", false, "never require the fields tagged omitempty, even annotated required: true")
+	generateCmd.Flags().StringSliceVar(&validatorTags, "validator-tags", nil, "keys of the struct tags of validator rules setting the constraints of the fields, e.g. validate,binding")
+	generateCmd.Flags().StringArrayVar(&typeMappings, "type-mapping", nil, "schema of a Go type, repeatable, e.g. github.com/acme/money.Amount=string:decimal, besides the built-in mappings")
+	generateCmd.Flags().StringSliceVar(&customFormats, "custom-formats", nil, "formats of swagger:strfmt known to the consumers of the spec, besides the strfmt registry")
+	generateCmd.Flags().StringSliceVar(&setTypes, "set-types", nil, "Go types marshaled as JSON arrays of unique elements, e.g. github.com/acme/sets.StringSet, besides the common set libraries")
+	generateCmd.Flags().BoolVar(&strictFormats, "strict-formats", false, "fail when a swagger:strfmt type doesn't marshal as a string, or names an unknown format")
+	generateCmd.Flags().BoolVar(&failOnSecrets, "fail-on-secrets", false, "fail when an example, default or description matches a secret pattern, e.g. an AWS access key")
+	generateCmd.Flags().BoolVar(&validateExamples, "validate-examples", false, "fail when the value of a named example of a body parameter or a response doesn't match its schema")
+	generateCmd.Flags().BoolVar(&strictTags, "strict-tags", false, "fail when an operation uses a tag declared by no swagger:tag, input spec or meta file, suggesting the closest one")
+	generateCmd.Flags().BoolVar(&strictParameters, "strict-parameters", false, "fail when the structs embedded in a swagger:parameters struct declare the same parameter, or a simple parameter has a composite type without an encoding")
+
+	// Analysis
+	generateCmd.Flags().BoolVar(&forbidEmptySchemas, "forbid-empty-schemas", false, "fail when properties are documented with an empty schema")
+	generateCmd.Flags().BoolVar(&inlineSingleUse, "inline-single-use", false, "inline definitions referenced from exactly one place")
+	generateCmd.Flags().BoolVar(&reportSingleUse, "report-single-use", false, "list definitions referenced from exactly one place instead of writing the spec")
+	generateCmd.Flags().BoolVar(&mergeIdentical, "merge-identical", false, "merge structurally identical definitions into one canonical definition")
+	generateCmd.Flags().BoolVar(&reportIdentical, "report-identical", false, "list groups of structurally identical definitions instead of writing the spec")
+	generateCmd.Flags().BoolVar(&pruneUnused, "prune-unused", false, "remove the definitions the operations don't reach through their refs, but those of the input spec")
+	generateCmd.Flags().BoolVar(&pruneInput, "prune-input", false, "also remove the unreachable definitions of the input spec with --prune-unused or --report-unused")
+	generateCmd.Flags().BoolVar(&reportUnused, "report-unused", false, "list the definitions --prune-unused removes, with their Go type, instead of writing the spec")
+	generateCmd.Flags().StringVar(&definitionNaming, "naming", "", "name the definitions of the types without a swagger:model name: short, full or camel-pkg, failing on the types with the same name")
+	generateCmd.Flags().BoolVar(&requireSecurity, "require-security", false, "fail on the operations without security requirements, nor top-level ones, unless declared 'Security: none'")
+	generateCmd.Flags().BoolVar(&preserveCommentFormat, "preserve-comment-format", false, "keep the indentation of the lines of the descriptions, for their markdown lists and code blocks")
+	generateCmd.Flags().IntVar(&concurrency, "concurrency", 0, "number of goroutines classifying the files and building the schemas, 0 for GOMAXPROCS and 1 to scan sequentially")
+	generateCmd.Flags().StringVar(&defaultTag, "default-tag", "default", "key of the struct tags setting the defaults of the properties and parameters, empty to ignore them")
+	generateCmd.Flags().BoolVar(&autoDeprecate, "auto-deprecate", false, "deprecate the types, fields and route handlers whose doc comments have a \"Deprecated: ...\" paragraph, the Go convention")
+	generateCmd.Flags().StringVar(&deprecationNotice, "deprecation-notice", "", "prefix of the descriptions of the deprecated fields, parameters and models, e.g. Deprecated:")
 
 	// Output formatting
 	generateCmd.Flags().BoolVar(&compact, "compact", false, "produce compact JSON output")
+	generateCmd.Flags().StringVar(&definitionIndex, "definition-index", "", "write the Go type of each definition to this index file, for the scans of other modules")
+	generateCmd.Flags().StringVar(&definitionIndexLocation, "definition-index-location", "", "URL the spec is published at, recorded in the definition index")
+	generateCmd.Flags().StringVar(&useDefinitionIndex, "use-definition-index", "", "refer to the definitions of the index file with external refs instead of emitting them")
+	generateCmd.Flags().StringVar(&sourceMapFile, "source-map", "", "write the Go position of the elements of the spec, by JSON pointer, to this file")
+	generateCmd.Flags().BoolVar(&codeSamples, "code-samples", false, "attach x-codeSamples with curl and Go snippets to the operations, see code_sample_templates in --config")
+	generateCmd.Flags().BoolVar(&allowEmpty, "allow-empty", false, "accept a scan without operations and models, which otherwise fails with its likely causes")
+	generateCmd.Flags().BoolVar(&noExamples, "no-examples", false, "remove the examples of the spec, e.g. for a spec published to third parties; defaults and enums are kept")
+	generateCmd.Flags().BoolVar(&emitGenerationInfo, "emit-generation-info", false, "record the options of the scan under x-codescan-options, secrets redacted, for the replay command")
+	generateCmd.Flags().BoolVar(&reproducible, "reproducible", false, "leave x-codescan-options out of the spec, that of the --input spec too, whatever --emit-generation-info")
+	generateCmd.Flags().BoolVar(&defaultIdempotency, "default-idempotency", false, "document the idempotency of the operations from their method, unless declared with Idempotent")
+	generateCmd.Flags().StringArrayVar(&securityDefaults, "security-default", nil, "security requirement of the operations which don't declare Security, repeatable for alternatives, e.g. 'oauth2: read:users, write:users'")
+	generateCmd.Flags().StringVar(&descriptionCatalog, "description-catalog", "", "replace the titles, summaries and descriptions with their translation from this catalog, see extract-strings")
+	generateCmd.Flags().BoolVar(&markUntranslated, "mark-untranslated", false, "add x-untranslated to the elements missing from the description catalog")
+	generateCmd.Flags().StringVar(&relativeRefs, "relative-refs", "", "qualify local refs with this document name, e.g. swagger.json#/definitions/Pet")
+	generateCmd.Flags().BoolVar(&printStats, "stats", false, "print scan statistics as JSON on stderr")
+	generateCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "report details of the scan on stderr, e.g. the skipped directories, and the codes of the warnings")
+	generateCmd.Flags().BoolVarP(&quiet, "quiet", "q", false, "don't print the warnings of the scan on stderr")
+	generateCmd.Flags().BoolVar(&showProgress, "progress", false, "report the progress of the scan on stderr")
+
+	// extract-strings scans like generate
+	extractStringsCmd.Flags().AddFlagSet(generateCmd.Flags())
+	statsCmd.Flags().AddFlagSet(generateCmd.Flags())
+	statsCmd.Flags().BoolVar(&statsByTag, "by-tag", false, "write the measures of the operations by tag as a markdown table, rather than the statistics as JSON")
+
+	// Watch mode, generate only
+	generateCmd.Flags().BoolVar(&watch, "watch", false, "regenerate the output files whenever the sources of the scanned packages change, until interrupted")
+	generateCmd.Flags().DurationVar(&watchInterval, "watch-interval", 500*time.Millisecond, "how often the sources are polled by --watch, which waits for them to stay the same that long")
+	generateCmd.Flags().StringVar(&splitOutput, "split-output", "", "write the spec to this directory as a root document, with a document per definition and per path, removing the stale ones")
+	generateCmd.Flags().BoolVar(&listOptions, "list-options", false, "print the flags, with their section, type, default and the codescan.Options field they set, as --format, instead of scanning")
+
+	checkFlagTable(generateCmd.Flags(), generateFlags)
+	generateCmd.SetUsageFunc(groupedUsage)
+	extractStringsCmd.SetUsageFunc(groupedUsage)
+	statsCmd.SetUsageFunc(groupedUsage)
 }
 
 func runGenerate(cmd *cobra.Command, args []string) error {
+	if quiet && verbose {
+		return errors.New("--quiet drops the warnings, and can't be combined with --verbose")
+	}
+	if splitOutput != "" {
+		if err := checkSplitOutput(); err != nil {
+			return err
+		}
+	}
+	if len(outputSets) > 0 && (watch || splitOutput != "") {
+		return errors.New("--output-set can't be combined with --watch or --split-output")
+	}
+	if dryRunMerge && mergeStrategy == "" {
+		return errors.New("--dry-run-merge lists the merge of --merge-strategy, which isn't set")
+	}
+	if watch {
+		return runWatch(cmd, args)
+	}
+
+	swspec, opts, err := scanForGenerate(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	if extractStrings {
+		return writeStrings(codescan.ExtractStrings(swspec), resolvePaths(outputFiles))
+	}
+
+	if dryRunMerge {
+		writeMergeEntries(os.Stdout, opts.Stats.MergeEntries)
+		return nil
+	}
+
+	if reportStats {
+		if statsByTag {
+			return writeTagStatsTable(os.Stdout, opts.Stats.Tags)
+		}
+		return writeStats(os.Stdout, opts.Stats)
+	}
+
+	if reportSingleUse {
+		return writeSingleUseReport(os.Stdout, codescan.SingleUseDefinitions(swspec))
+	}
+
+	if reportIdentical {
+		return writeIdenticalReport(os.Stdout, codescan.IdenticalDefinitionGroups(swspec), opts.DefinitionPositions)
+	}
+
+	if reportUnused {
+		return writeUnusedReport(os.Stdout, *opts.UnusedDefinitions)
+	}
+
+	if reportGraph {
+		return writeGraph(swspec, opts.SourceMap)
+	}
+
+	doc, err := outputDocument(swspec, opts)
+	if err != nil {
+		return err
+	}
+
+	if splitOutput != "" {
+		return outputSplitSpec(swspec)
+	}
+
+	if len(opts.OutputSets) > 0 {
+		if err := writeOutputSets(opts); err != nil {
+			return err
+		}
+		// with output sets, the spec of the scan is only written to the -o files
+		if len(outputFiles) == 0 {
+			return nil
+		}
+	}
+
+	if checkOutputs {
+		return checkSpec(doc, resolvePaths(outputFiles), outputFormat, compact)
+	}
+
+	return writeSpec(doc, resolvePaths(outputFiles), outputFormat, compact)
+}
+
+// scanForGenerate scans the packages with the options of the generate flags and of the config file, and
+// writes the by-products of the scan, e.g. the --source-map.
+func scanForGenerate(cmd *cobra.Command, args []string) (*spec.Swagger, *codescan.Options, error) {
+	if specVersion != "2.0" && specVersion != "3.0" {
+		return nil, nil, fmt.Errorf("unsupported spec version %q, expected 2.0 or 3.0", specVersion)
+	}
+
 	opts := &codescan.Options{
 		Packages:                args,
 		ScanModels:              scanModels,
 		WorkDir:                 workDir,
 		BuildTags:               buildTags,
+		ExtraBuildTags:          extraBuildTags,
+		Env:                     loaderEnv,
+		PackagesDriver:          packagesDriver,
 		ExcludeDeps:             excludeDeps,
 		Include:                 includes,
 		Exclude:                 excludes,
@@ -137,51 +468,415 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 		RefAliases:              refAliases,
 		TransparentAliases:      transparentAliases,
 		DescWithRef:             descWithRef,
+
+		RequiredFromPointers:         requiredFromPointers,
+		RequiredFromPointersPackages: requiredFromPtrPkgs,
+		InlineSingleUse:              inlineSingleUse,
+		IncludeTestScope:             includeTestScope,
+		DeclarationOrder:             declarationOrder,
+		MergeIdentical:               mergeIdentical,
+		ForbidEmptySchemas:           forbidEmptySchemas,
+		RelativeRefs:                 relativeRefs,
+		DefaultSkips:                 !noDefaultSkips,
+		AlsoScan:                     alsoScan,
+		CodeSamples:                  codeSamples,
+		DefaultIdempotency:           defaultIdempotency,
+		MarkUntranslated:             markUntranslated,
+		StrictJSONNames:              strictJSONNames,
+		MaxSchemaDepth:               maxSchemaDepth,
+		EnumExtensionStyle:           enumExtensionStyle,
+		CustomFormats:                customFormats,
+		StrictFormats:                strictFormats,
+		Audience:                     audience,
+		RequireAudience:              requireAudience,
+		StrictParameters:             strictParameters,
+		RequireAllIncludeTags:        requireAllIncludeTags,
+		KeepGoing:                    keepGoing,
+		NoRecover:                    noRecover,
+		FailOnSecrets:                failOnSecrets,
+		ValidateExamples:             validateExamples,
+		NoExamples:                   noExamples,
+		EmitGenerationInfo:           emitGenerationInfo,
+		Reproducible:                 reproducible,
+		SetTypes:                     setTypes,
+		AllowEmpty:                   allowEmpty,
+		DiscoverEnums:                discoverEnums,
+		MaxEnumValues:                maxEnumValues,
+		BindingExtensions:            bindingExtensions,
+		RenameCollisions:             renameCollisions,
+		SortParameters:               sortParameters,
+		HoistParameters:              hoistParameters,
+		OmitEmptyAsOptional:          omitEmptyOptional,
+		StrictTags:                   strictTags,
+		ValidatorTags:                validatorTags,
+		RouterDiscovery:              routerDiscovery,
+		IncludeUndocumented:          includeUndocumented,
+		WrapRefsForDescription:       wrapRefs,
+		FailFast:                     failFast,
+		FailOnWarning:                failOnWarning,
+		PruneUnused:                  pruneUnused,
+		PruneInput:                   pruneInput,
+		RequireSecurity:              requireSecurity,
+		DefinitionNaming:             definitionNaming,
+		PreserveCommentFormat:        preserveCommentFormat,
+		Concurrency:                  concurrency,
+		DefaultTag:                   defaultTag,
+		AutoDeprecate:                autoDeprecate,
+		DeprecationNotice:            deprecationNotice,
+		ExplainPackages:              explainPackages,
+		MergeStrategy:                mergeStrategy,
+		Compat:                       compat,
+		InferParamTypes:              inferParamTypes,
+		TrailingSlash:                trailingSlash,
+	}
+	if cacheDir != "" && !noCache {
+		opts.CacheDir = resolvePath(cacheDir)
+	}
+	for _, value := range securityDefaults {
+		requirement, err := codescan.ParseSecurityRequirement(value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid --security-default: %w", err)
+		}
+		opts.SecurityDefaults = append(opts.SecurityDefaults, requirement)
+	}
+	for _, mapping := range typeMappings {
+		name, value, _ := strings.Cut(mapping, "=")
+		if err := addTypeMapping(opts, name, value); err != nil {
+			return nil, nil, fmt.Errorf("invalid --type-mapping %q: %w", mapping, err)
+		}
+	}
+	if reportIdentical {
+		opts.DefinitionPositions = make(map[string]token.Position)
+	}
+	if reportUnused {
+		opts.UnusedDefinitions = new([]codescan.UnusedDefinition)
+	}
+	// the OpenAPI 3.0 upgrade reports the constructs it drops at their positions
+	if sourceMapFile != "" || (reportGraph && graphWithPositions) || specVersion == "3.0" {
+		opts.SourceMap = make(map[string]token.Position)
+	}
+
+	if path := configPath(); path != "" {
+		cfg, err := loadConfig(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		cfg.apply(opts, cmd.Flags())
+	}
+	if len(opts.Packages) == 0 {
+		return nil, nil, errors.New("no packages to scan: give them as arguments, or with the packages of the config file")
+	}
+	if noCache {
+		opts.CacheDir = ""
+	}
+	if extractStrings {
+		// the strings extracted are those of the sources
+		opts.DescriptionCatalog = nil
+	}
+
+	if definitionIndex != "" {
+		opts.DefinitionIndex = &codescan.DefinitionIndex{Location: definitionIndexLocation}
+	}
+	if descriptionCatalog != "" && !extractStrings {
+		catalog, err := loadDescriptionCatalog(resolvePath(descriptionCatalog))
+		if err != nil {
+			return nil, nil, err
+		}
+		opts.DescriptionCatalog = catalog
+	}
+	if useDefinitionIndex != "" {
+		index, err := loadDefinitionIndex(resolvePath(useDefinitionIndex))
+		if err != nil {
+			return nil, nil, err
+		}
+		opts.UseDefinitionIndex = index
+	}
+
+	if err := validateOptions(opts); err != nil {
+		return nil, nil, err
+	}
+
+	if runPrecheckFirst {
+		diagnostics, err := precheck(opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := reportDiagnostics(os.Stderr, workDir, diagnostics); err != nil {
+			cmd.SilenceUsage = true
+			return nil, nil, fmt.Errorf("precheck: %w", err)
+		}
+	}
+
+	if showProgress {
+		opts.OnProgress = func(phase codescan.Phase, done, total int) {
+			writeProgress(os.Stderr, phase, done, total)
+		}
+	}
+
+	var stats codescan.Stats
+	if printStats || verbose || reportStats || len(opts.ExplainPackages) > 0 || dryRunMerge {
+		opts.Stats = &stats
 	}
 
 	// Load input spec if provided
 	if inputSpec != "" {
-		spec, err := loadInputSpec(inputSpec)
+		spec, err := loadInputSpec(resolvePath(inputSpec))
 		if err != nil {
-			return fmt.Errorf("failed to load input spec: %w", err)
+			return nil, nil, fmt.Errorf("failed to load input spec: %w", err)
 		}
 		opts.InputSpec = spec
 	}
 
-	// Run the scanner
-	swspec, err := codescan.Run(opts)
+	if err := addOutputSets(opts); err != nil {
+		return nil, nil, err
+	}
+
+	if metaFile != "" {
+		data, err := os.ReadFile(resolvePath(metaFile))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read meta file: %w", err)
+		}
+		meta, err := codescan.ParseMeta(data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid meta file %s:\n%w", metaFile, err)
+		}
+		opts.Meta = meta
+	}
+
+	// Run the scanner, which Ctrl-C stops
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if quiet {
+		// the other messages of the scan, e.g. the summary of the empty schemas
+		log.SetOutput(io.Discard)
+		defer log.SetOutput(os.Stderr)
+	} else {
+		opts.Logger = printWarning
+	}
+	result, err := codescan.RunWithContext(ctx, opts)
 	if err != nil {
-		return fmt.Errorf("scan failed: %w", err)
+		// a failed scan is not a misuse of the command: the usage would bury the errors reported
+		cmd.SilenceUsage = true
+		if errors.Is(err, codescan.ErrEmptyScan) {
+			writePackageDecisions(os.Stderr, stats.PackageDecisions)
+			if printStats {
+				_ = writeStats(os.Stderr, &stats)
+			}
+			return nil, nil, fmt.Errorf("%w\npass %s to accept an empty spec", err, optionFlag("AllowEmpty"))
+		}
+		if errors.Is(err, codescan.ErrMergeConflict) && dryRunMerge {
+			writeMergeEntries(os.Stdout, stats.MergeEntries)
+		}
+		return nil, nil, fmt.Errorf("scan failed: %w", err)
+	}
+	// nor are the failures past the scan, e.g. an outdated or unwritable output file
+	cmd.SilenceUsage = true
+	swspec := result.Spec
+	writePackageDecisions(os.Stderr, stats.PackageDecisions)
+
+	if verbose {
+		switch {
+		case stats.Cached:
+			fmt.Fprintf(os.Stderr, "spec read from the scan cache in %s\n", opts.CacheDir)
+		case len(stats.ChangedPackages) > 0:
+			fmt.Fprintf(os.Stderr, "scan cache invalidated by the changes of %s\n", strings.Join(stats.ChangedPackages, ", "))
+		}
+		if !stats.Cached && len(stats.ReusedModels) > 0 {
+			fmt.Fprintf(os.Stderr, "%d of %d models read from the scan cache\n", len(stats.ReusedModels), stats.Definitions)
+		}
+		for _, dir := range stats.SkippedDirs {
+			fmt.Fprintf(os.Stderr, "skipped %s/ (default skip, see --also-scan)\n", dir)
+		}
+		for _, decision := range stats.TagDecisions {
+			verdict := "excluded"
+			if decision.Kept {
+				verdict = "included"
+			}
+			fmt.Fprintf(os.Stderr, "%s %s %s %s (tags %s): %s\n", verdict, decision.Method, decision.Path, decision.ID, strings.Join(decision.Tags, ", "), decision.Reason)
+		}
+		for _, param := range stats.HoistedParameters {
+			fmt.Fprintf(os.Stderr, "hoisted the parameter %s, shared by %s\n", param.Name, strings.Join(param.Operations, ", "))
+		}
+	}
+
+	if printStats {
+		if err := writeStats(os.Stderr, &stats); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if definitionIndex != "" {
+		if err := writeDefinitionIndex(resolvePath(definitionIndex), opts.DefinitionIndex); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if sourceMapFile != "" {
+		if err := writeSourceMap(resolvePath(sourceMapFile), opts.SourceMap, workDir); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return swspec, opts, nil
+}
+
+// outputDocument returns the document written for a spec: the spec itself, or its OpenAPI 3.0 upgrade,
+// reporting the constructs dropped like the scan.
+func outputDocument(swspec *spec.Swagger, opts *codescan.Options) (any, error) {
+	if specVersion == "3.0" {
+		return codescan.UpgradeSwaggerFor(swspec, opts)
+	}
+	return swspec, nil
+}
+
+func writeProgress(w io.Writer, phase codescan.Phase, done, total int) {
+	if total > 0 {
+		fmt.Fprintf(w, "%s: %d/%d\n", phase, done, total)
+		return
+	}
+	fmt.Fprintf(w, "%s: %d\n", phase, done)
+}
+
+// printWarning prints a diagnostic of the scan on stderr, with its code for --verbose, e.g. to configure it
+// with a rule of the config file.
+func printWarning(diagnostic codescan.Diagnostic) {
+	if verbose {
+		log.Printf("WARNING: %v [%s]", diagnostic, diagnostic.Code)
+		return
 	}
+	log.Printf("WARNING: %v", diagnostic)
+}
 
-	// Marshal the output
-	var output []byte
-	switch strings.ToLower(outputFormat) {
-	case "yaml", "yml":
-		output, err = yaml.Marshal(swspec)
-	case "json":
-		if compact {
-			output, err = json.Marshal(swspec)
-		} else {
-			output, err = json.MarshalIndent(swspec, "", "  ")
+// writePackageDecisions writes the decisions of the scan on the packages of --explain-package.
+func writePackageDecisions(w io.Writer, decisions []codescan.PackageDecision) {
+	for _, decision := range decisions {
+		verdict := "excluded"
+		switch {
+		case !decision.Loaded:
+			verdict = "not loaded"
+		case decision.Classified:
+			verdict = "classified"
 		}
-	default:
-		return fmt.Errorf("unsupported output format: %s", outputFormat)
+		fmt.Fprintf(w, "%s %s: %s\n", verdict, decision.Package, decision.Reason)
 	}
+}
+
+// writeMergeEntries writes where the entries of the spec merged by --merge-strategy come from, for
+// --dry-run-merge.
+func writeMergeEntries(w io.Writer, entries []codescan.MergeEntry) {
+	for _, entry := range entries {
+		origin := entry.Origin
+		if entry.Kept != "" {
+			origin += ", kept " + entry.Kept
+		}
+		if entry.Source != "" {
+			fmt.Fprintf(w, "%s %s: %s (%s)\n", entry.Section, entry.Key, origin, entry.Source)
+			continue
+		}
+		fmt.Fprintf(w, "%s %s: %s\n", entry.Section, entry.Key, origin)
+	}
+}
+
+func writeStats(w io.Writer, stats *codescan.Stats) error {
+	output, err := json.MarshalIndent(stats, "", "  ")
 	if err != nil {
-		return fmt.Errorf("failed to marshal spec: %w", err)
+		return err
 	}
+	_, err = fmt.Fprintln(w, string(output))
+	return err
+}
 
-	// Write output
-	if outputFile != "" {
-		if err := os.WriteFile(outputFile, output, 0644); err != nil {
-			return fmt.Errorf("failed to write output file: %w", err)
+func writeSingleUseReport(w io.Writer, uses []codescan.DefinitionUse) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "DEFINITION\tREFERRER\tINLINABLE")
+	for _, use := range uses {
+		inlinable := "yes"
+		if !use.Inlinable {
+			inlinable = "no (" + use.Reason + ")"
 		}
-		fmt.Fprintf(os.Stderr, "Spec written to %s\n", outputFile)
-	} else {
-		fmt.Println(string(output))
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", use.Name, use.Referrer, inlinable)
+	}
+	return tw.Flush()
+}
+
+func writeIdenticalReport(w io.Writer, groups []codescan.IdenticalDefinitions, positions map[string]token.Position) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "CANONICAL\tDEFINITION\tPOSITION")
+	for _, group := range groups {
+		for _, name := range group.Names {
+			position := "-"
+			if pos, ok := positions[name]; ok {
+				position = pos.String()
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", group.Canonical, name, position)
+		}
+	}
+	return tw.Flush()
+}
+
+func writeUnusedReport(w io.Writer, unused []codescan.UnusedDefinition) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "DEFINITION\tGO TYPE\tPOSITION")
+	for _, definition := range unused {
+		goType, position := definition.GoType, "-"
+		if definition.Input {
+			goType = "(input spec)"
+		} else if definition.Pos.IsValid() {
+			position = definition.Pos.String()
+		}
+		if goType == "" {
+			goType = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", definition.Name, goType, position)
+	}
+	return tw.Flush()
+}
+
+func loadDefinitionIndex(path string) (*codescan.DefinitionIndex, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read definition index: %w", err)
+	}
+	index := new(codescan.DefinitionIndex)
+	if err := json.Unmarshal(data, index); err != nil {
+		return nil, fmt.Errorf("invalid definition index %s: %w", path, err)
 	}
+	return index, nil
+}
 
+func writeDefinitionIndex(path string, index *codescan.DefinitionIndex) error {
+	output, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, append(output, '\n')); err != nil {
+		return fmt.Errorf("failed to write definition index: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Definition index written to %s (%d definitions)\n", path, len(index.Definitions))
+	return nil
+}
+
+// writeSourceMap writes the source map as a JSON object of "file:line:col" positions, sorted by JSON pointer.
+// Files are relative to the working directory when they are below it, with forward slashes.
+func writeSourceMap(path string, sourceMap map[string]token.Position, dir string) error {
+	base, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	positions := make(map[string]string, len(sourceMap))
+	for pointer, pos := range sourceMap {
+		pos.Filename = codescan.RelativePath(base, pos.Filename)
+		positions[pointer] = fmt.Sprintf("%s:%d:%d", pos.Filename, pos.Line, pos.Column)
+	}
+
+	output, err := json.MarshalIndent(positions, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, append(output, '\n')); err != nil {
+		return fmt.Errorf("failed to write source map: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Source map written to %s (%d entries)\n", path, len(positions))
 	return nil
 }
 
@@ -191,13 +886,5 @@ func loadInputSpec(path string) (*spec.Swagger, error) {
 		return nil, err
 	}
 
-	var swspec spec.Swagger
-	// Try JSON first
-	if err := json.Unmarshal(data, &swspec); err != nil {
-		// Fall back to YAML
-		if err := yaml.Unmarshal(data, &swspec); err != nil {
-			return nil, fmt.Errorf("failed to parse as JSON or YAML: %w", err)
-		}
-	}
-	return &swspec, nil
+	return codescan.ParseInputSpec(data, downgradeInput)
 }
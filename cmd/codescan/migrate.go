@@ -0,0 +1,142 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/3idey/codescan/codescan"
+	"github.com/spf13/cobra"
+	"golang.org/x/tools/go/packages"
+)
+
+var (
+	// migrate-annotations command flags
+	migrateWorkDir   string
+	migrateBuildTags string
+	migrateDryRun    bool
+)
+
+var migrateAnnotationsCmd = &cobra.Command{
+	Use:   "migrate-annotations [packages...]",
+	Short: "Rewrite the legacy spellings of the annotations to the canonical ones",
+	Long: `Rewrites in place the annotations of the Go files of the specified packages,
+their tests included, which generate only recognizes with --compat
+legacy-go-swagger, e.g. swagger:params to swagger:parameters, +swagger:model
+and @swagger:route to swagger:model and swagger:route. Only the comments are
+edited, so that the formatted files stay formatted. Each rewritten annotation is
+listed on stderr.
+
+--dry-run prints the changes as a unified diff instead of writing them.
+
+Examples:
+  codescan migrate-annotations ./...
+  codescan migrate-annotations --dry-run ./... | less`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runMigrateAnnotations,
+}
+
+func init() {
+	migrateAnnotationsCmd.Flags().StringVarP(&migrateWorkDir, "work-dir", "w", "", "working directory for package resolution")
+	migrateAnnotationsCmd.Flags().StringVar(&migrateBuildTags, "tags", "", "build tags to use when loading the packages")
+	migrateAnnotationsCmd.Flags().BoolVar(&migrateDryRun, "dry-run", false, "print the changes as a unified diff, rather than writing them")
+}
+
+func runMigrateAnnotations(cmd *cobra.Command, args []string) error {
+	cfg := &packages.Config{Mode: packages.NeedName | packages.NeedFiles, Dir: migrateWorkDir, Tests: true}
+	if migrateBuildTags != "" {
+		cfg.BuildFlags = []string{"-tags", migrateBuildTags}
+	}
+	pkgs, err := packages.Load(cfg, args...)
+	if err != nil {
+		return fmt.Errorf("failed to load the packages: %w", err)
+	}
+	var files []string
+	for _, pkg := range pkgs {
+		files = append(files, pkg.GoFiles...)
+		files = append(files, pkg.IgnoredFiles...)
+	}
+	slices.Sort(files)
+	files = slices.Compact(files)
+
+	base, err := filepath.Abs(migrateWorkDir)
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+	var migrated int
+	for _, file := range files {
+		if filepath.Ext(file) != ".go" {
+			continue
+		}
+		changed, err := migrateFile(os.Stdout, base, file)
+		if err != nil {
+			return err
+		}
+		migrated += changed
+	}
+	fmt.Fprintf(os.Stderr, "migrated %d annotations\n", migrated)
+	return nil
+}
+
+// migrateFile rewrites the legacy annotations of a file, or prints the diff of their rewriting with --dry-run,
+// and returns their number.
+func migrateFile(w io.Writer, base, file string) (int, error) {
+	src, err := os.ReadFile(file)
+	if err != nil {
+		return 0, err
+	}
+	migrated, legacy, err := codescan.MigrateAnnotations(file, src)
+	if err != nil {
+		return 0, err
+	}
+	if len(legacy) == 0 {
+		return 0, nil
+	}
+	for _, annotation := range legacy {
+		fmt.Fprintf(os.Stderr, "%s:%d:%d: %s -> %s\n", codescan.RelativePath(base, file), annotation.Pos.Line, annotation.Pos.Column, annotation.Legacy, annotation.Canonical)
+	}
+	if migrateDryRun {
+		return len(legacy), writeLineDiff(w, codescan.RelativePath(base, file), string(src), string(migrated))
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		return 0, err
+	}
+	return len(legacy), os.WriteFile(file, migrated, info.Mode().Perm())
+}
+
+// writeLineDiff writes a unified diff, without context, of texts with the same lines but the changed ones.
+func writeLineDiff(w io.Writer, name, before, after string) error {
+	old, changed := strings.Split(before, "\n"), strings.Split(after, "\n")
+	if _, err := fmt.Fprintf(w, "--- a/%s\n+++ b/%s\n", name, name); err != nil {
+		return err
+	}
+	for start := 0; start < len(old); start++ {
+		if old[start] == changed[start] {
+			continue
+		}
+		end := start
+		for end < len(old) && old[end] != changed[end] {
+			end++
+		}
+		hunk := fmt.Sprintf("@@ -%[1]d,%[2]d +%[1]d,%[2]d @@\n", start+1, end-start)
+		for _, line := range old[start:end] {
+			hunk += "-" + line + "\n"
+		}
+		for _, line := range changed[start:end] {
+			hunk += "+" + line + "\n"
+		}
+		if _, err := io.WriteString(w, hunk); err != nil {
+			return err
+		}
+		start = end
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/3idey/codescan/codescan"
+)
+
+// validateOptions validates the options, reporting each problem with the flags involved.
+func validateOptions(opts *codescan.Options) error {
+	err := opts.Validate()
+	if err == nil {
+		return nil
+	}
+
+	errs := []error{err}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	}
+
+	problems := make([]string, 0, len(errs))
+	for _, err := range errs {
+		var conflict *codescan.OptionsConflictError
+		var invalid *codescan.InvalidOptionError
+		switch {
+		case errors.As(err, &conflict):
+			flags := make([]string, 0, len(conflict.Options))
+			for _, option := range conflict.Options {
+				flags = append(flags, optionFlag(option))
+			}
+			problems = append(problems, fmt.Sprintf("%s can't be used together: %s", strings.Join(flags, " and "), conflict.Reason))
+		case errors.As(err, &invalid):
+			problems = append(problems, fmt.Sprintf("invalid %s: %v", optionFlag(invalid.Option), invalid.Err))
+		default:
+			problems = append(problems, err.Error())
+		}
+	}
+
+	return fmt.Errorf("invalid options:\n  %s", strings.Join(problems, "\n  "))
+}
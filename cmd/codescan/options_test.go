@@ -0,0 +1,58 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateOptions(t *testing.T) {
+	cli := buildCLI(t)
+	dir := t.TempDir()
+	writeFiles(t, dir, pathsModule)
+
+	for _, tc := range []struct {
+		name     string
+		args     []string
+		exitCode int
+		expected []string
+	}{
+		{
+			name: "should accept coherent flags",
+			args: []string{"--scan-models", "--ref-aliases"},
+		},
+		{
+			name:     "should name the flags of a conflict",
+			args:     []string{"--scan-models", "--prune-unused"},
+			exitCode: 1,
+			expected: []string{"--scan-models and --prune-unused can't be used together: the models no operation uses, which ScanModels adds, are removed by PruneUnused"},
+		},
+		{
+			name:     "should report all the conflicts together",
+			args:     []string{"--scan-models", "--prune-unused", "--ref-aliases", "--transparent-aliases"},
+			exitCode: 1,
+			expected: []string{
+				"invalid options:",
+				"--transparent-aliases and --ref-aliases can't be used together",
+				"--scan-models and --prune-unused can't be used together",
+			},
+		},
+		{
+			name:     "should name the flag of an invalid value",
+			args:     []string{"--max-schema-depth", "-1"},
+			exitCode: 1,
+			expected: []string{"invalid --max-schema-depth"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			out, code := runCLI(t, cli, dir, append([]string{"generate", "-o", "swagger.json"}, append(tc.args, ".")...)...)
+			assert.Equal(t, tc.exitCode, code, out)
+			for _, expected := range tc.expected {
+				assert.Contains(t, out, expected)
+			}
+		})
+	}
+}
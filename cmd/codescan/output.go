@@ -0,0 +1,248 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/3idey/codescan/codescan"
+)
+
+// outputFormatFor infers the format of an output file from its extension, falling back to the --format flag.
+func outputFormatFor(file, defaultFormat string) string {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".json":
+		return "json"
+	case ".yaml", ".yml":
+		return "yaml"
+	default:
+		return defaultFormat
+	}
+}
+
+// encoderFor returns the encoder of the requested format, writing to w.
+func encoderFor(w io.Writer, outputFormat string, compact bool) (codescan.Encoder, error) {
+	switch strings.ToLower(outputFormat) {
+	case "yaml", "yml":
+		return codescan.NewYAMLEncoder(w), nil
+	case "json":
+		return codescan.NewJSONEncoder(w, compact), nil
+	default:
+		return nil, fmt.Errorf("unsupported output format: %s", outputFormat)
+	}
+}
+
+// encodeSpec streams a spec in the requested format to w.
+func encodeSpec(w io.Writer, doc any, outputFormat string, compact bool) error {
+	enc, err := encoderFor(w, outputFormat, compact)
+	if err != nil {
+		return err
+	}
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("failed to marshal spec: %w", err)
+	}
+	return nil
+}
+
+// writeSpec streams a spec to every output file, or to stdout when there is none.
+//
+// The format of each file is inferred from its extension, see outputFormatFor. The files are written to
+// temporary files first, and only renamed in place once all of them are written.
+func writeSpec(doc any, outputFiles []string, outputFormat string, compact bool) error {
+	if len(outputFiles) == 0 {
+		stdout := bufio.NewWriter(os.Stdout)
+		if err := encodeSpec(stdout, doc, outputFormat, compact); err != nil {
+			return err
+		}
+		stdout.WriteByte('\n')
+		return stdout.Flush()
+	}
+
+	written, err := writeSpecFiles(doc, outputFiles, outputFormat, compact)
+	if err != nil {
+		return err
+	}
+	for _, file := range written {
+		fmt.Fprintf(os.Stderr, "Spec written to %s (%d bytes)\n", file.name, file.size)
+	}
+	return nil
+}
+
+// writeSpecFiles writes a spec to output files, through temporary files renamed in place once all of them
+// are written, and returns the files written.
+func writeSpecFiles(doc any, outputFiles []string, outputFormat string, compact bool) ([]*pendingFile, error) {
+	var pending []*pendingFile
+	defer func() {
+		for _, file := range pending {
+			file.discard()
+		}
+	}()
+	for _, file := range outputFiles {
+		tmp, err := createPendingFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write output file: %w", err)
+		}
+		pending = append(pending, tmp)
+		if err := encodeSpec(tmp, doc, outputFormatFor(file, outputFormat), compact); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+	}
+	for _, file := range pending {
+		if err := file.commit(); err != nil {
+			return nil, fmt.Errorf("failed to write output file: %w", err)
+		}
+	}
+
+	return pending, nil
+}
+
+// checkSpec verifies that every output file is up to date with the spec, without writing anything. The spec
+// is compared with the files as it is encoded.
+func checkSpec(doc any, outputFiles []string, outputFormat string, compact bool) error {
+	if len(outputFiles) == 0 {
+		return errors.New("--check requires at least one output file")
+	}
+
+	var stale []string
+	for _, file := range outputFiles {
+		upToDate, err := specMatchesFile(doc, file, outputFormatFor(file, outputFormat), compact)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			fmt.Fprintf(os.Stderr, "%s is missing\n", file)
+			stale = append(stale, file)
+		case err != nil:
+			return fmt.Errorf("failed to read output file: %w", err)
+		case !upToDate:
+			fmt.Fprintf(os.Stderr, "%s is out of date\n", file)
+			stale = append(stale, file)
+		default:
+			fmt.Fprintf(os.Stderr, "%s is up to date\n", file)
+		}
+	}
+	if len(stale) > 0 {
+		return fmt.Errorf("%d of %d output files are out of date: %s", len(stale), len(outputFiles), strings.Join(stale, ", "))
+	}
+
+	return nil
+}
+
+func specMatchesFile(doc any, file, outputFormat string, compact bool) (bool, error) {
+	current, err := os.Open(file)
+	if err != nil {
+		return false, err
+	}
+	defer current.Close()
+
+	cmp := &compareWriter{current: bufio.NewReader(current)}
+	if err := encodeSpec(cmp, doc, outputFormat, compact); err != nil {
+		return false, err
+	}
+	if cmp.err != nil {
+		return false, cmp.err
+	}
+	if cmp.differs {
+		return false, nil
+	}
+	// the file must not be longer than the spec
+	_, err = cmp.current.ReadByte()
+	if errors.Is(err, io.EOF) {
+		return true, nil
+	}
+	return false, err
+}
+
+// compareWriter compares what is written to it with the content of a reader.
+type compareWriter struct {
+	current *bufio.Reader
+	buf     []byte
+	differs bool
+	err     error // reading the file
+}
+
+func (w *compareWriter) Write(p []byte) (int, error) {
+	if w.differs || w.err != nil {
+		return len(p), nil
+	}
+	if cap(w.buf) < len(p) {
+		w.buf = make([]byte, len(p))
+	}
+	buf := w.buf[:len(p)]
+	n, err := io.ReadFull(w.current, buf)
+	switch {
+	case errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF):
+		w.differs = true
+	case err != nil:
+		w.err = err
+	case !bytes.Equal(buf[:n], p):
+		w.differs = true
+	}
+	return len(p), nil
+}
+
+// pendingFile is an output file written to a temporary file, until it is committed.
+type pendingFile struct {
+	*os.File
+	name string
+	size int64
+}
+
+func createPendingFile(file string) (*pendingFile, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(file), "."+filepath.Base(file)+".*")
+	if err != nil {
+		return nil, err
+	}
+	return &pendingFile{File: tmp, name: file}, nil
+}
+
+func (f *pendingFile) Write(p []byte) (int, error) {
+	n, err := f.File.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// commit renames the temporary file in place.
+func (f *pendingFile) commit() error {
+	if err := f.Chmod(0o644); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), f.name)
+}
+
+// discard removes the temporary file, unless it was committed.
+func (f *pendingFile) discard() {
+	f.Close()
+	os.Remove(f.Name()) // no-op once renamed
+}
+
+// writeFileAtomic writes a file through a temporary file renamed in place, so readers never see a partial spec.
+func writeFileAtomic(file string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(file), "."+filepath.Base(file)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
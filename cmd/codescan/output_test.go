@@ -0,0 +1,94 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runCLI runs the codescan command in dir, and returns its combined output and exit code.
+func runCLI(t *testing.T, cli, dir string, args ...string) (string, int) {
+	t.Helper()
+	cmd := exec.Command(cli, args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return string(out), exitErr.ExitCode()
+	}
+	require.NoError(t, err, "can't run codescan %v", args)
+	return string(out), 0
+}
+
+func TestCheckOutputs(t *testing.T) {
+	cli := buildCLI(t)
+
+	for _, tc := range []struct {
+		name     string
+		change   func(t *testing.T, dir string) // of the outputs written, before the check
+		exitCode int
+		expected []string
+	}{
+		{
+			name:     "should accept the outputs up to date",
+			change:   func(*testing.T, string) {},
+			expected: []string{"swagger.json is up to date", "swagger.yaml is up to date"},
+		},
+		{
+			name: "should report an output out of date",
+			change: func(t *testing.T, dir string) {
+				file := filepath.Join(dir, "swagger.yaml")
+				data, err := os.ReadFile(file)
+				require.NoError(t, err)
+				require.NoError(t, os.WriteFile(file, append(data, "x-edited: true\n"...), 0o600))
+			},
+			exitCode: 1,
+			expected: []string{
+				"swagger.json is up to date", "swagger.yaml is out of date",
+				"1 of 2 output files are out of date: swagger.yaml",
+			},
+		},
+		{
+			name: "should report all the outputs missing or out of date together",
+			change: func(t *testing.T, dir string) {
+				require.NoError(t, os.Remove(filepath.Join(dir, "swagger.json")))
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "swagger.yaml"), []byte("swagger: \"2.0\"\n"), 0o600))
+			},
+			exitCode: 1,
+			expected: []string{
+				"swagger.json is missing", "swagger.yaml is out of date",
+				"2 of 2 output files are out of date: swagger.json, swagger.yaml",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, pathsModule)
+			out, code := runCLI(t, cli, dir, "generate", "-o", "swagger.json", "-o", "swagger.yaml", ".")
+			require.Zero(t, code, "can't write the outputs: %s", out)
+			tc.change(t, dir)
+
+			out, code = runCLI(t, cli, dir, "generate", "-o", "swagger.json", "-o", "swagger.yaml", "--check", ".")
+			assert.Equal(t, tc.exitCode, code, out)
+			for _, expected := range tc.expected {
+				assert.Contains(t, out, expected)
+			}
+		})
+	}
+
+	t.Run("should require an output file", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFiles(t, dir, pathsModule)
+		out, code := runCLI(t, cli, dir, "generate", "--check", ".")
+		assert.Equal(t, 1, code, out)
+		assert.Contains(t, out, "--check requires at least one output file")
+	})
+}
@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/3idey/codescan/codescan"
+	"github.com/go-openapi/spec"
+)
+
+// outputSetFiles are the files of the --output-set flags, by name of set.
+var outputSetFiles map[string]string
+
+// parseOutputSet parses an --output-set flag: name=file, then the options of the set separated by colons, e.g.
+// public=public.yaml:tags=public,pets:exclude-tags=beta:input=public-base.yaml:scan-models. The parts which are
+// not options belong to the file, e.g. the drive of a Windows path, or its workdir: prefix.
+func parseOutputSet(value string) (codescan.OutputSet, string, error) {
+	name, rest, found := strings.Cut(value, "=")
+	if !found || name == "" {
+		return codescan.OutputSet{}, "", errors.New("expects name=file")
+	}
+
+	set := codescan.OutputSet{Name: name}
+	var file []string
+	parts := strings.Split(rest, ":")
+	for i := 0; i < len(parts); i++ {
+		part := parts[i]
+		key, optionValue, _ := strings.Cut(part, "=")
+		if key == "input" && optionValue+":" == workDirPrefix && i+1 < len(parts) {
+			i++
+			optionValue += ":" + parts[i]
+		}
+		switch key {
+		case "tags":
+			set.IncludeTags = splitTags(optionValue)
+		case "exclude-tags":
+			set.ExcludeTags = splitTags(optionValue)
+		case "input":
+			input, err := loadInputSpec(resolvePath(optionValue))
+			if err != nil {
+				return codescan.OutputSet{}, "", fmt.Errorf("failed to load the input spec: %w", err)
+			}
+			set.InputSpec = input
+		case "scan-models":
+			set.ScanModels = true
+		default:
+			file = append(file, part)
+		}
+	}
+	if len(file) == 0 || file[0] == "" {
+		return codescan.OutputSet{}, "", errors.New("expects the output file after name=")
+	}
+	return set, strings.Join(file, ":"), nil
+}
+
+func splitTags(value string) []string {
+	var tags []string
+	for tag := range strings.SplitSeq(value, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// addOutputSets adds the sets of the --output-set flags to the options.
+func addOutputSets(opts *codescan.Options) error {
+	outputSetFiles = make(map[string]string, len(outputSets))
+	for _, value := range outputSets {
+		set, file, err := parseOutputSet(value)
+		if err != nil {
+			return fmt.Errorf("invalid --output-set %q: %w", value, err)
+		}
+		opts.OutputSets = append(opts.OutputSets, set)
+		outputSetFiles[set.Name] = resolvePath(file)
+	}
+	if len(opts.OutputSets) > 0 {
+		opts.OutputSetSpecs = make(map[string]*spec.Swagger, len(opts.OutputSets))
+	}
+	return nil
+}
+
+// writeOutputSets writes the spec of each output set to its file, or checks it with --check.
+func writeOutputSets(opts *codescan.Options) error {
+	var errs []error
+	for _, set := range opts.OutputSets {
+		doc, err := outputDocument(opts.OutputSetSpecs[set.Name], opts)
+		if err != nil {
+			return err
+		}
+		file := []string{outputSetFiles[set.Name]}
+		if checkOutputs {
+			errs = append(errs, checkSpec(doc, file, outputFormat, compact))
+			continue
+		}
+		if err := writeSpec(doc, file, outputFormat, compact); err != nil {
+			return fmt.Errorf("output set %s: %w", set.Name, err)
+		}
+	}
+	return errors.Join(errs...)
+}
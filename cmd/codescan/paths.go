@@ -0,0 +1,39 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// workDirPrefix resolves the path of a file flag against --work-dir rather than the current directory,
+// e.g. -o workdir:docs/swagger.json.
+const workDirPrefix = "workdir:"
+
+// pathsHelp documents the resolution of the paths in the help of the commands.
+const pathsHelp = `Package patterns are resolved against --work-dir. The paths of files, e.g. --output,
+--input, --meta-file or --config, are resolved against the current directory, unless they start
+with "workdir:", as in -o workdir:docs/swagger.json.`
+
+// resolvePath resolves the path of a file flag: relative to the current directory, or to --work-dir
+// with the workdir: prefix.
+func resolvePath(path string) string {
+	relative, ok := strings.CutPrefix(path, workDirPrefix)
+	if !ok {
+		return path
+	}
+	if filepath.IsAbs(relative) {
+		return relative
+	}
+	return filepath.Join(workDir, relative)
+}
+
+func resolvePaths(paths []string) []string {
+	resolved := make([]string, 0, len(paths))
+	for _, path := range paths {
+		resolved = append(resolved, resolvePath(path))
+	}
+	return resolved
+}
@@ -0,0 +1,146 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pathsModule is the module scanned from another directory: an operation, and a legacy annotation to migrate.
+var pathsModule = map[string]string{
+	"go.mod": "module example.com/api\n\ngo 1.24\n",
+	"api.go": `// Package api is the module of the file paths tests.
+package api
+
+// swagger:route GET /pets pets listPets
+//
+// Lists the pets.
+//
+// Responses:
+//   default: genericError
+func ListPets() {}
+
+// A genericError is the error of an operation.
+//
+// swagger:response genericError
+type genericError struct{}
+`,
+	"legacy/legacy.go": `// Package legacy has the legacy spelling of an annotation.
+package legacy
+
+// swagger:params listPets
+type listPetsParams struct {
+	// in: query
+	Limit int ` + "`json:\"limit\"`" + `
+}
+`,
+	"docs/base.yaml": "swagger: \"2.0\"\ninfo:\n  title: from the work dir\n  version: \"1.0\"\npaths: {}\n",
+}
+
+// buildCLI builds the codescan command.
+func buildCLI(t *testing.T) string {
+	t.Helper()
+	cli := filepath.Join(t.TempDir(), "codescan")
+	out, err := exec.Command("go", "build", "-o", cli, ".").CombinedOutput()
+	require.NoError(t, err, "can't build the command: %s", out)
+	return cli
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+}
+
+func readTitle(t *testing.T, file string) string {
+	t.Helper()
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	var doc struct {
+		Info struct {
+			Title string `json:"title"`
+		} `json:"info"`
+	}
+	require.NoError(t, json.Unmarshal(data, &doc))
+	return doc.Info.Title
+}
+
+func TestFilePaths(t *testing.T) {
+	cli := buildCLI(t)
+
+	// the command runs in work, next to the module in api, which -w ../api scans
+	root := t.TempDir()
+	module, cwd := filepath.Join(root, "api"), filepath.Join(root, "work")
+	writeFiles(t, module, pathsModule)
+	writeFiles(t, cwd, map[string]string{
+		"base.yaml": "swagger: \"2.0\"\ninfo:\n  title: from the current directory\n  version: \"1.0\"\npaths: {}\n",
+	})
+
+	run := func(t *testing.T, args ...string) string {
+		t.Helper()
+		cmd := exec.Command(cli, args...)
+		cmd.Dir = cwd
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, "codescan %v failed: %s", args, out)
+		return string(out)
+	}
+
+	t.Run("should write a relative output in the current directory", func(t *testing.T) {
+		run(t, "generate", "-w", "../api", "-o", "swagger.json", ".")
+		assert.FileExists(t, filepath.Join(cwd, "swagger.json"))
+		assert.NoFileExists(t, filepath.Join(module, "swagger.json"))
+	})
+
+	t.Run("should write a workdir: output in the work dir", func(t *testing.T) {
+		run(t, "generate", "-w", "../api", "-o", "workdir:docs/swagger.json", ".")
+		assert.FileExists(t, filepath.Join(module, "docs", "swagger.json"))
+		assert.NoDirExists(t, filepath.Join(cwd, "docs"))
+
+		out := run(t, "generate", "-w", "../api", "-o", "workdir:docs/swagger.json", "--check", ".")
+		assert.Contains(t, out, filepath.Join("..", "api", "docs", "swagger.json")+" is up to date")
+	})
+
+	t.Run("should read a relative input from the current directory", func(t *testing.T) {
+		run(t, "generate", "-w", "../api", "-i", "base.yaml", "-o", "input.json", ".")
+		assert.Equal(t, "from the current directory", readTitle(t, filepath.Join(cwd, "input.json")))
+	})
+
+	t.Run("should read a workdir: input from the work dir", func(t *testing.T) {
+		run(t, "generate", "-w", "../api", "-i", "workdir:docs/base.yaml", "-o", "input.json", ".")
+		assert.Equal(t, "from the work dir", readTitle(t, filepath.Join(cwd, "input.json")))
+	})
+
+	t.Run("should resolve an absolute workdir: path as is", func(t *testing.T) {
+		output := filepath.Join(root, "absolute.json")
+		run(t, "generate", "-w", "../api", "-o", workDirPrefix+output, ".")
+		assert.FileExists(t, output)
+	})
+
+	t.Run("should write the paths of the patch relative to the work dir", func(t *testing.T) {
+		legacy := filepath.Join(module, "legacy", "legacy.go")
+		before, err := os.ReadFile(legacy)
+		require.NoError(t, err)
+
+		cmd := exec.Command(cli, "migrate-annotations", "-w", "../api", "--dry-run", "./legacy")
+		cmd.Dir = cwd
+		patch, err := cmd.Output()
+		require.NoError(t, err)
+		assert.Contains(t, string(patch), "--- a/legacy/legacy.go\n+++ b/legacy/legacy.go\n")
+		assert.Contains(t, string(patch), "+// swagger:parameters listPets\n")
+
+		after, err := os.ReadFile(legacy)
+		require.NoError(t, err)
+		assert.Equal(t, string(before), string(after), "--dry-run doesn't write the files")
+	})
+}
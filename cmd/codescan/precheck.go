@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/3idey/codescan/codescan"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// precheck command flags
+	precheckWorkDir   string
+	precheckBuildTags string
+	precheckExtraTags string
+	precheckCompat    []string
+)
+
+var precheckCmd = &cobra.Command{
+	Use:   "precheck [packages...]",
+	Short: "Check the grammar of the swagger annotations, without type checking",
+	Long: `Parses the files of the specified Go packages, without loading their dependencies
+nor type checking them, and reports the annotations generate would reject or
+ignore, e.g. an unknown swagger: annotation or a route without operation ID.
+
+It takes a fraction of the time of generate, for pre-commit hooks. The annotations
+which need the types, e.g. the properties of models, are only checked by generate.
+
+The command fails when a problem is found.
+
+Examples:
+  codescan precheck ./...`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runPrecheck,
+}
+
+func init() {
+	precheckCmd.Flags().StringVarP(&precheckWorkDir, "work-dir", "w", "", "working directory for package resolution")
+	precheckCmd.Flags().StringVar(&precheckBuildTags, "tags", "", "build tags to use when scanning")
+	precheckCmd.Flags().StringVar(&precheckExtraTags, "extra-tags", "", "build tags of documentation-only files, only used to find annotations")
+	precheckCmd.Flags().StringSliceVar(&precheckCompat, "compat", nil, "recognize the annotations of other dialects: legacy-go-swagger, e.g. swagger:params, with a warning each")
+}
+
+func runPrecheck(cmd *cobra.Command, args []string) error {
+	opts := &codescan.Options{
+		Packages:       args,
+		WorkDir:        precheckWorkDir,
+		BuildTags:      precheckBuildTags,
+		ExtraBuildTags: precheckExtraTags,
+		Compat:         precheckCompat,
+	}
+	diagnostics, err := precheck(opts)
+	if err != nil {
+		return err
+	}
+
+	// the problems are not a misuse of the command
+	cmd.SilenceUsage = true
+	return reportDiagnostics(os.Stdout, precheckWorkDir, diagnostics)
+}
+
+func precheck(opts *codescan.Options) ([]codescan.Diagnostic, error) {
+	// the diagnostics are reported by the caller, rather than as warnings
+	log.SetOutput(io.Discard)
+	diagnostics, err := codescan.Precheck(opts)
+	log.SetOutput(os.Stderr)
+	if err != nil {
+		return nil, fmt.Errorf("precheck failed: %w", err)
+	}
+	return diagnostics, nil
+}
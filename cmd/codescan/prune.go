@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/3idey/codescan/codescan"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// prune command flags
+	pruneOutputFile     string
+	pruneOutputFormat   string
+	pruneCompact        bool
+	pruneDowngradeInput bool
+	pruneOptions        codescan.PruneOptions
+)
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune [spec]",
+	Short: "Remove operations from a spec, with what only they use",
+	Long: `Removes from an existing swagger 2.0 spec (JSON or YAML) the operations which
+don't match the filters, then the definitions, parameters, responses, security
+definitions and tags the kept operations don't reach through their refs. What
+is removed is listed on stderr.
+
+An operation is removed by any of the --drop filters, and otherwise needs to
+match each kind of --keep filter given. The paths match patterns where *
+matches a segment and ** any number of them. The extensions are written
+x-name, or x-name=value to require one of their values.
+
+The operations are filtered by the same rules as the --include-tags and
+--audience of generate.
+
+Examples:
+  codescan prune --keep-tags public --keep-paths '/v1/**' -o public.json swagger.json
+  codescan prune --drop-extensions x-internal --keep-unreferenced swagger.yaml`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPrune,
+}
+
+func init() {
+	flags := pruneCmd.Flags()
+	flags.StringVarP(&pruneOutputFile, "output", "o", "", "output file (default: stdout)")
+	flags.StringVar(&pruneOutputFormat, "format", "json", "output format: json or yaml")
+	flags.BoolVar(&pruneCompact, "compact", false, "produce compact JSON output")
+	flags.BoolVar(&pruneDowngradeInput, "downgrade-input", false, "convert an OpenAPI 3.x spec to swagger 2.0 before pruning it")
+	flags.StringSliceVar(&pruneOptions.IncludeTags, "keep-tags", nil, "keep the operations with one of these tags")
+	flags.StringSliceVar(&pruneOptions.ExcludeTags, "drop-tags", nil, "remove the operations with one of these tags, whatever their other tags")
+	flags.BoolVar(&pruneOptions.RequireAllIncludeTags, "require-all-keep-tags", false, "keep the operations with all the --keep-tags, rather than one of them")
+	flags.StringSliceVar(&pruneOptions.IncludePaths, "keep-paths", nil, "keep the operations of the paths matching one of these patterns, e.g. /v1/**")
+	flags.StringSliceVar(&pruneOptions.ExcludePaths, "drop-paths", nil, "remove the operations of the paths matching one of these patterns")
+	flags.StringSliceVar(&pruneOptions.IncludeExtensions, "keep-extensions", nil, "keep the operations with one of these extensions, e.g. x-audience=public")
+	flags.StringSliceVar(&pruneOptions.ExcludeExtensions, "drop-extensions", nil, "remove the operations with one of these extensions, e.g. x-internal")
+	flags.BoolVar(&pruneOptions.KeepUnreferenced, "keep-unreferenced", false, "keep what no operation uses, only removing what the removed operations used")
+}
+
+func runPrune(cmd *cobra.Command, args []string) error {
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to read spec: %w", err)
+	}
+	swspec, err := codescan.ParseInputSpec(data, pruneDowngradeInput)
+	if err != nil {
+		return err
+	}
+
+	report, err := codescan.Prune(swspec, pruneOptions)
+	if err != nil {
+		return err
+	}
+	writePruneReport(os.Stderr, report)
+
+	var outputFiles []string
+	if pruneOutputFile != "" {
+		outputFiles = []string{pruneOutputFile}
+	}
+	return writeSpec(swspec, outputFiles, pruneOutputFormat, pruneCompact)
+}
+
+// writePruneReport lists what prune removed, by kind.
+func writePruneReport(w io.Writer, report *codescan.PruneReport) {
+	removed := []struct {
+		kind  string
+		names []string
+	}{
+		{"operations", report.Operations},
+		{"definitions", report.Definitions},
+		{"parameters", report.Parameters},
+		{"responses", report.Responses},
+		{"security definitions", report.SecurityDefinitions},
+		{"tags", report.Tags},
+	}
+	nothing := true
+	for _, kind := range removed {
+		if len(kind.names) == 0 {
+			continue
+		}
+		nothing = false
+		fmt.Fprintf(w, "Removed %s (%d): %s\n", kind.kind, len(kind.names), strings.Join(kind.names, ", "))
+	}
+	if nothing {
+		fmt.Fprintln(w, "Nothing removed")
+	}
+}
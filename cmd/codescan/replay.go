@@ -0,0 +1,155 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+
+	"github.com/3idey/codescan/codescan"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// replay command flags
+	replayOutputFiles []string
+	replayCheck       bool
+	replayWorkDir     string
+	replayInputSpec   string
+	replayMetaFile    string
+)
+
+var replayCmd = &cobra.Command{
+	Use:   "replay <spec> [packages...]",
+	Short: "Generate a spec again with the options it records",
+	Long: `Reads the options recorded under x-codescan-options by generate --emit-generation-info,
+and scans the packages again with them, to reproduce the spec: in the same version, 2.0 or 3.0,
+format and indentation.
+
+The packages given replace the recorded ones. The options read from files, e.g. the --input
+spec, and the absolute paths aren't recorded: they are given with their flags, or replayed unset
+with a warning. The redacted environment variables, e.g. GITHUB_TOKEN, take their values from
+the environment.
+
+Examples:
+  # Check that the spec is reproduced by the current tree
+  codescan replay --check docs/swagger.json
+
+  # Regenerate the spec with the recorded options, to another file
+  codescan replay -o /tmp/swagger.json docs/swagger.json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runReplay,
+}
+
+func init() {
+	rootCmd.AddCommand(replayCmd)
+
+	replayCmd.Flags().StringArrayVarP(&replayOutputFiles, "output", "o", nil, "output file, repeatable, with the format inferred from its extension (default: stdout, or the spec with --check)")
+	replayCmd.Flags().BoolVar(&replayCheck, "check", false, "verify that the output files, or the spec, are reproduced instead of writing them")
+	replayCmd.Flags().StringVarP(&replayWorkDir, "work-dir", "w", "", "working directory for package resolution, replacing the recorded one")
+	replayCmd.Flags().StringVarP(&replayInputSpec, "input", "i", "", "input swagger spec to merge with, which isn't recorded")
+	replayCmd.Flags().StringVar(&replayMetaFile, "meta-file", "", "YAML file with meta information overriding swagger:meta, which isn't recorded")
+}
+
+func runReplay(cmd *cobra.Command, args []string) error {
+	file := args[0]
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to read spec: %w", err)
+	}
+	info, err := codescan.ReadGenerationInfo(data)
+	if err != nil {
+		return fmt.Errorf("invalid spec %s: %w", file, err)
+	}
+	if info == nil {
+		return fmt.Errorf("%s has no %s: generate it with --emit-generation-info", file, codescan.GenerationInfoExtension)
+	}
+	opts, err := info.ToOptions()
+	if err != nil {
+		return err
+	}
+
+	if len(args) > 1 {
+		opts.Packages = args[1:]
+	}
+	if replayWorkDir != "" {
+		opts.WorkDir = replayWorkDir
+	}
+	omitted := info.Omitted
+	if replayInputSpec != "" {
+		spec, err := loadInputSpec(replayInputSpec)
+		if err != nil {
+			return fmt.Errorf("failed to load input spec: %w", err)
+		}
+		opts.InputSpec = spec
+		omitted = slices.DeleteFunc(omitted, func(key string) bool { return key == "input_spec" })
+	}
+	if replayMetaFile != "" {
+		data, err := os.ReadFile(replayMetaFile)
+		if err != nil {
+			return fmt.Errorf("failed to read meta file: %w", err)
+		}
+		meta, err := codescan.ParseMeta(data)
+		if err != nil {
+			return fmt.Errorf("invalid meta file %s:\n%w", replayMetaFile, err)
+		}
+		opts.Meta = meta
+		omitted = slices.DeleteFunc(omitted, func(key string) bool { return key == "meta" })
+	}
+	if len(omitted) > 0 {
+		fmt.Fprintf(os.Stderr, "WARNING: the options %s aren't recorded, and are replayed unset\n", strings.Join(omitted, ", "))
+	}
+	if current, err := codescan.NewGenerationInfo(&codescan.Options{}); err == nil && current.Codescan != info.Codescan {
+		fmt.Fprintf(os.Stderr, "WARNING: %s was generated by codescan %s, and is replayed by %s: the spec may differ\n", file, info.Codescan, current.Codescan)
+	}
+
+	if err := validateOptions(opts); err != nil {
+		return err
+	}
+	opts.Logger = printWarning
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	result, err := codescan.RunWithContext(ctx, opts)
+	if err != nil {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("scan failed: %w", err)
+	}
+
+	var doc any = result.Spec
+	if isOpenAPI3Document(data) {
+		if doc, err = codescan.UpgradeSwaggerFor(result.Spec, opts); err != nil {
+			return err
+		}
+	}
+
+	format := outputFormatFor(file, "json")
+	compact := format == "json" && bytes.Count(bytes.TrimSpace(data), []byte("\n")) == 0
+	if replayCheck {
+		files := replayOutputFiles
+		if len(files) == 0 {
+			files = []string{file}
+		}
+		cmd.SilenceUsage = true
+		return checkSpec(doc, files, format, compact)
+	}
+
+	return writeSpec(doc, replayOutputFiles, format, compact)
+}
+
+// isOpenAPI3Document tells whether a spec, in JSON or YAML, is an OpenAPI 3.0 document.
+func isOpenAPI3Document(data []byte) bool {
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return false
+	}
+	_, found := doc["openapi"]
+	return found
+}
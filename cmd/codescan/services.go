@@ -0,0 +1,220 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var (
+	allServices  bool
+	serviceNames []string
+)
+
+// serviceConfig is a spec generated by --all-services, configured with the flags of generate.
+type serviceConfig struct {
+	Name     string         `yaml:"name"`
+	Packages []string       `yaml:"packages"`
+	Flags    map[string]any `yaml:"flags"`
+}
+
+// serviceFlags are the flags a service can't set.
+var serviceFlags = []string{"config", "all-services", "service", "list-options"}
+
+// packagesArgs requires packages as arguments, unless they are configured by the services or the config file.
+func packagesArgs(cmd *cobra.Command, args []string) error {
+	if listOptions || len(args) == 0 && configPath() != "" {
+		return nil
+	}
+	if allServices || len(serviceNames) > 0 {
+		if len(args) > 0 {
+			return errors.New("the packages are configured by the services of --config, not given as arguments")
+		}
+		return nil
+	}
+	return cobra.MinimumNArgs(1)(cmd, args)
+}
+
+// runGenerateCommand runs the services of the config file with --all-services or --service, and a single
+// generation otherwise.
+func runGenerateCommand(cmd *cobra.Command, args []string) error {
+	if listOptions {
+		return writeOptionList(cmd.OutOrStdout(), cmd.Flags(), outputFormat)
+	}
+	if path := configPath(); path != "" && cmd.Name() == "generate" {
+		cfg, err := loadConfig(path)
+		if err != nil {
+			return err
+		}
+		if err := cfg.setFlags(cmd.Flags()); err != nil {
+			return fmt.Errorf("config file %s: %w", path, err)
+		}
+	}
+	if allServices || len(serviceNames) > 0 {
+		if watch {
+			return errors.New("--watch regenerates a single spec, and can't be combined with --all-services or --service")
+		}
+		return runServices(cmd)
+	}
+	return runGenerate(cmd, args)
+}
+
+// runServices generates the specs of the services in one process. The flags given on the command line apply
+// to all the services, unless a service sets them. Failures are reported together, once all the services ran.
+func runServices(cmd *cobra.Command) error {
+	path := configPath()
+	if path == "" {
+		return errors.New("--all-services and --service require a --config file with services")
+	}
+	cfg, err := loadConfig(path)
+	if err != nil {
+		return err
+	}
+	services, err := selectServices(cfg.Services, serviceNames)
+	if err != nil {
+		return err
+	}
+	for _, svc := range services {
+		if err := checkService(cmd.Flags(), svc); err != nil {
+			return fmt.Errorf("config file %s: %w", path, err)
+		}
+	}
+
+	defaults := saveFlags(cmd.Flags())
+	var failures []error
+	for _, svc := range services {
+		err := runService(cmd, svc)
+		restoreFlags(cmd.Flags(), defaults)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "service %s failed\n", svc.Name)
+			failures = append(failures, fmt.Errorf("service %s: %w", svc.Name, err))
+		}
+	}
+
+	if len(failures) > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d of %d services failed:\n%w", len(failures), len(services), errors.Join(failures...))
+	}
+	return nil
+}
+
+func selectServices(services []serviceConfig, names []string) ([]serviceConfig, error) {
+	if len(services) == 0 {
+		return nil, fmt.Errorf("config file %s has no services", configPath())
+	}
+	if len(names) == 0 {
+		return services, nil
+	}
+
+	known := make([]string, 0, len(services))
+	selected := make([]serviceConfig, 0, len(names))
+	for _, svc := range services {
+		known = append(known, svc.Name)
+		if slices.Contains(names, svc.Name) {
+			selected = append(selected, svc)
+		}
+	}
+	for _, name := range names {
+		if !slices.Contains(known, name) {
+			return nil, fmt.Errorf("unknown service %q, expected one of %s", name, strings.Join(known, ", "))
+		}
+	}
+	return selected, nil
+}
+
+// checkService rejects the services which can't run, before running any.
+func checkService(flags *pflag.FlagSet, svc serviceConfig) error {
+	if svc.Name == "" {
+		return errors.New("a service has no name")
+	}
+	if len(svc.Packages) == 0 {
+		return fmt.Errorf("service %s has no packages", svc.Name)
+	}
+	for _, name := range slices.Sorted(maps.Keys(svc.Flags)) {
+		if flags.Lookup(name) == nil || slices.Contains(serviceFlags, name) {
+			return fmt.Errorf("service %s: unsupported flag %q", svc.Name, name)
+		}
+	}
+	return nil
+}
+
+func runService(cmd *cobra.Command, svc serviceConfig) error {
+	flags := cmd.Flags()
+	for _, name := range slices.Sorted(maps.Keys(svc.Flags)) {
+		if err := setFlag(flags, name, svc.Flags[name]); err != nil {
+			return err
+		}
+	}
+	return runGenerate(cmd, svc.Packages)
+}
+
+// setFlag sets a flag from a value of the config file: a scalar, or a list for the flags taking several values.
+func setFlag(flags *pflag.FlagSet, name string, value any) error {
+	flag := flags.Lookup(name)
+	list, isList := value.([]any)
+	sliceValue, isSlice := flag.Value.(pflag.SliceValue)
+	switch {
+	case isList && isSlice:
+		values := make([]string, 0, len(list))
+		for _, item := range list {
+			values = append(values, fmt.Sprint(item))
+		}
+		if err := sliceValue.Replace(values); err != nil {
+			return fmt.Errorf("invalid value for flag %s: %w", name, err)
+		}
+		flag.Changed = true
+		return nil
+	case isList:
+		return fmt.Errorf("flag %s takes a single value", name)
+	case isSlice:
+		if err := sliceValue.Replace([]string{fmt.Sprint(value)}); err != nil {
+			return fmt.Errorf("invalid value for flag %s: %w", name, err)
+		}
+		flag.Changed = true
+		return nil
+	default:
+		if err := flags.Set(name, fmt.Sprint(value)); err != nil {
+			return fmt.Errorf("invalid value for flag %s: %w", name, err)
+		}
+		return nil
+	}
+}
+
+type flagState struct {
+	values  []string
+	changed bool
+}
+
+// saveFlags records the values of the flags, so that each service starts from the command line.
+func saveFlags(flags *pflag.FlagSet) map[string]flagState {
+	states := make(map[string]flagState)
+	flags.VisitAll(func(flag *pflag.Flag) {
+		state := flagState{values: []string{flag.Value.String()}, changed: flag.Changed}
+		if sliceValue, isSlice := flag.Value.(pflag.SliceValue); isSlice {
+			state.values = slices.Clone(sliceValue.GetSlice())
+		}
+		states[flag.Name] = state
+	})
+	return states
+}
+
+func restoreFlags(flags *pflag.FlagSet, states map[string]flagState) {
+	flags.VisitAll(func(flag *pflag.Flag) {
+		state := states[flag.Name]
+		if sliceValue, isSlice := flag.Value.(pflag.SliceValue); isSlice {
+			_ = sliceValue.Replace(slices.Clone(state.values))
+		} else {
+			_ = flag.Value.Set(state.values[0])
+		}
+		flag.Changed = state.changed
+	})
+}
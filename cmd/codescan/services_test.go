@@ -0,0 +1,99 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// servicesConfig configures a service generating a spec, and two failing ones.
+const servicesConfig = `services:
+  - name: pets
+    packages: [.]
+    flags:
+      output: pets.json
+  - name: orders
+    packages: [.]
+    flags:
+      output: orders.json
+      input: missing.json
+  - name: users
+    packages: [.]
+    flags:
+      output: users.json
+      spec-version: "4.0"
+`
+
+func TestServices(t *testing.T) {
+	cli := buildCLI(t)
+
+	for _, tc := range []struct {
+		name       string
+		args       []string
+		exitCode   int
+		expected   []string
+		unexpected []string
+		written    []string
+		unwritten  []string
+	}{
+		{
+			name:     "should report the failures of the services together",
+			args:     []string{"--all-services"},
+			exitCode: 1,
+			expected: []string{
+				"service orders failed", "service users failed", "2 of 3 services failed",
+				"service orders: ", "missing.json",
+				`service users: unsupported spec version "4.0"`,
+			},
+			written:   []string{"pets.json"},
+			unwritten: []string{"orders.json", "users.json"},
+		},
+		{
+			name:       "should run a single service",
+			args:       []string{"--service", "pets"},
+			unexpected: []string{"failed"},
+			written:    []string{"pets.json"},
+			unwritten:  []string{"orders.json", "users.json"},
+		},
+		{
+			name:      "should fail a single service",
+			args:      []string{"--service", "users"},
+			exitCode:  1,
+			expected:  []string{"service users failed", "1 of 1 services failed"},
+			unwritten: []string{"pets.json", "users.json"},
+		},
+		{
+			name:      "should refuse an unknown service before running any",
+			args:      []string{"--service", "pets", "--service", "payments"},
+			exitCode:  1,
+			expected:  []string{`unknown service "payments", expected one of pets, orders, users`},
+			unwritten: []string{"pets.json"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, pathsModule)
+			writeFiles(t, dir, map[string]string{"codescan.yaml": servicesConfig})
+
+			out, code := runCLI(t, cli, dir, append([]string{"generate", "--config", "codescan.yaml"}, tc.args...)...)
+			require.Equal(t, tc.exitCode, code, out)
+			for _, expected := range tc.expected {
+				assert.Contains(t, out, expected)
+			}
+			for _, unexpected := range tc.unexpected {
+				assert.NotContains(t, out, unexpected)
+			}
+			for _, file := range tc.written {
+				assert.FileExists(t, filepath.Join(dir, file))
+			}
+			for _, file := range tc.unwritten {
+				assert.NoFileExists(t, filepath.Join(dir, file))
+			}
+		})
+	}
+}
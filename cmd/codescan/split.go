@@ -0,0 +1,177 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/3idey/codescan/codescan"
+	"github.com/go-openapi/spec"
+)
+
+// splitOutput is the generate --split-output flag.
+var splitOutput string
+
+// checkSplitOutput rejects the flags --split-output can't be combined with.
+func checkSplitOutput() error {
+	switch {
+	case len(outputFiles) > 0:
+		return errors.New("--split-output writes the spec as the files of its directory, and can't be combined with --output")
+	case specVersion != "2.0":
+		return errors.New("--split-output writes swagger 2.0 specs, and can't be combined with --spec-version 3.0")
+	case relativeRefs != "":
+		return errors.New("--split-output relocates the refs of the documents itself, and can't be combined with --relative-refs")
+	}
+	return nil
+}
+
+// splitDocuments splits a spec, with a root document named after --format, e.g. swagger.yaml.
+func splitDocuments(swspec *spec.Swagger) (map[string]any, error) {
+	var root string
+	switch strings.ToLower(outputFormat) {
+	case "json":
+		root = "swagger.json"
+	case "yaml", "yml":
+		root = "swagger.yaml"
+	default:
+		return nil, fmt.Errorf("unsupported output format: %s", outputFormat)
+	}
+	return codescan.SplitSpec(swspec, root)
+}
+
+// outputSplitSpec writes the documents of a split spec to --split-output, or checks them with --check.
+func outputSplitSpec(swspec *spec.Swagger) error {
+	documents, err := splitDocuments(swspec)
+	if err != nil {
+		return err
+	}
+	dir := resolvePath(splitOutput)
+	if checkOutputs {
+		return checkSplitSpec(dir, documents)
+	}
+
+	changed, err := writeSplitSpec(dir, documents)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Spec written to %s (%d files, %d changed or removed)\n", dir, len(documents), len(changed))
+	return nil
+}
+
+// writeSplitSpec writes the documents of a split spec to a directory, through temporary files renamed in place
+// once all of them are written, and removes the stale documents of the previous runs. Only the documents which
+// changed are written. It returns the files written and removed.
+func writeSplitSpec(dir string, documents map[string]any) ([]string, error) {
+	var pending []*pendingFile
+	defer func() {
+		for _, file := range pending {
+			file.discard()
+		}
+	}()
+
+	var changed []string
+	for _, name := range slices.Sorted(maps.Keys(documents)) {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		upToDate, err := specMatchesFile(documents[name], file, outputFormatFor(file, outputFormat), compact)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read output file: %w", err)
+		}
+		if upToDate {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			return nil, fmt.Errorf("failed to write output file: %w", err)
+		}
+		tmp, err := createPendingFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write output file: %w", err)
+		}
+		pending = append(pending, tmp)
+		if err := encodeSpec(tmp, documents[name], outputFormatFor(file, outputFormat), compact); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		changed = append(changed, file)
+	}
+	for _, file := range pending {
+		if err := file.commit(); err != nil {
+			return nil, fmt.Errorf("failed to write output file: %w", err)
+		}
+	}
+
+	stale, err := staleDocuments(dir, documents)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range stale {
+		if err := os.Remove(file); err != nil {
+			return nil, fmt.Errorf("failed to remove stale output file: %w", err)
+		}
+		changed = append(changed, file)
+	}
+	return changed, nil
+}
+
+// checkSplitSpec verifies that the documents of a split spec are up to date in a directory, without stale
+// documents, without writing anything.
+func checkSplitSpec(dir string, documents map[string]any) error {
+	var outdated []string
+	for _, name := range slices.Sorted(maps.Keys(documents)) {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		upToDate, err := specMatchesFile(documents[name], file, outputFormatFor(file, outputFormat), compact)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			fmt.Fprintf(os.Stderr, "%s is missing\n", file)
+			outdated = append(outdated, file)
+		case err != nil:
+			return fmt.Errorf("failed to read output file: %w", err)
+		case !upToDate:
+			fmt.Fprintf(os.Stderr, "%s is out of date\n", file)
+			outdated = append(outdated, file)
+		}
+	}
+	stale, err := staleDocuments(dir, documents)
+	if err != nil {
+		return err
+	}
+	for _, file := range stale {
+		fmt.Fprintf(os.Stderr, "%s is stale\n", file)
+	}
+
+	if len(outdated)+len(stale) > 0 {
+		return fmt.Errorf("the split spec in %s is out of date: %d of %d files differ, and %d are stale", dir, len(outdated), len(documents), len(stale))
+	}
+	fmt.Fprintf(os.Stderr, "%s is up to date\n", dir)
+	return nil
+}
+
+// staleDocuments lists the documents of the definitions and paths directories which aren't part of the split
+// spec, e.g. of a definition removed since the previous run. Only the JSON and YAML files are considered.
+func staleDocuments(dir string, documents map[string]any) ([]string, error) {
+	var stale []string
+	for _, sub := range []string{codescan.SplitDefinitionsDir, codescan.SplitPathsDir} {
+		entries, err := os.ReadDir(filepath.Join(dir, sub))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || outputFormatFor(entry.Name(), "") == "" {
+				continue
+			}
+			if _, current := documents[sub+"/"+entry.Name()]; !current {
+				stale = append(stale, filepath.Join(dir, sub, entry.Name()))
+			}
+		}
+	}
+	return stale, nil
+}
@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/3idey/codescan/codescan"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// reportStats makes runGenerate write the statistics of the scan instead of the spec.
+	reportStats bool
+	statsByTag  bool
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats [packages...]",
+	Short: "Report the statistics of the scan, and the size of the API by tag",
+	Long: `Scans the specified Go packages like generate, and writes the statistics of the
+scan as JSON on stdout, as --stats prints them on stderr. They measure the
+operations of the spec by tag: the operations, their average number of
+parameters, the definitions they reach, the percentage of them deprecated and
+the number of them undocumented, see --include-undocumented.
+The operations without tags are grouped under "(none)".
+
+--by-tag writes these measures as a markdown table instead.
+
+Examples:
+  codescan stats ./...
+  codescan stats --by-tag ./... >> API.md`,
+	Args: packagesArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		reportStats = true
+		return runGenerateCommand(cmd, args)
+	},
+}
+
+// writeTagStatsTable writes the measures of the operations by tag as a markdown table.
+func writeTagStatsTable(w io.Writer, tags []codescan.TagStats) error {
+	if _, err := fmt.Fprintln(w, "| Tag | Operations | Parameters per operation | Definitions | Deprecated | Undocumented |"); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(w, "|-----|-----------:|-------------------------:|------------:|-----------:|-------------:|"); err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		if _, err := fmt.Fprintf(w, "| %s | %d | %.2f | %d | %.1f%% | %d |\n",
+			tag.Tag, tag.Operations, tag.AverageParameters, tag.Definitions, tag.DeprecatedPercent, tag.Undocumented); err != nil {
+			return err
+		}
+	}
+	return nil
+}
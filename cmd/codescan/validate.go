@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"os"
+
+	"github.com/3idey/codescan/codescan"
+	"github.com/go-openapi/spec"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// validate command flags
+	validateWorkDir    string
+	validateBuildTags  string
+	validateScanModels bool
+	validateConfigFile string
+	validateInput      string
+	validateStrict     bool
+	validateRefs       bool
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate [packages...]",
+	Short: "Check the generated spec against the rules of swagger 2.0",
+	Long: `Scans the specified Go packages like generate, and checks the spec against the
+rules of swagger 2.0 instead of writing it, e.g. a $ref to an undefined
+definition, two operations with the same operationId, or a path parameter
+which the operation doesn't declare, then with go-openapi/validate, against the
+JSON schema of swagger 2.0.
+
+The problems are reported at the Go file and line of the annotation building
+the element at fault, followed by its JSON pointer in the spec. An existing
+spec is checked with --input, without scanning.
+
+Unused definitions and operations without an operationId are warnings, which
+fail the command with --strict. The uses of deprecated definitions are warnings
+too, even with --strict.
+
+The external $refs, to files and to http or https URLs, are resolved with
+--resolve-refs, or --offline, and those pointing to nothing are reported too.
+The remote documents are fetched like with the bundle command, see its flags.
+
+Examples:
+  codescan validate ./...
+  codescan validate --strict ./api/...
+  codescan validate --input swagger.json
+  codescan validate --resolve-refs --allow-host specs.acme.com --input swagger.json`,
+	RunE: runValidate,
+}
+
+func init() {
+	validateCmd.Flags().StringVarP(&validateWorkDir, "work-dir", "w", "", "working directory for package resolution")
+	validateCmd.Flags().StringVar(&validateBuildTags, "tags", "", "build tags to use when scanning")
+	validateCmd.Flags().BoolVar(&validateScanModels, "scan-models", false, "include models that are not referenced by operations")
+	validateCmd.Flags().StringVar(&validateConfigFile, "config", "", "YAML config file (e.g. with force_include_dirs)")
+	validateCmd.Flags().StringVar(&validateInput, "input", "", "validate this spec file instead of scanning packages")
+	validateCmd.Flags().BoolVar(&validateStrict, "strict", false, "fail on warnings, e.g. unused definitions")
+	validateCmd.Flags().BoolVar(&validateRefs, "resolve-refs", false, "resolve the external refs, reporting those pointing to nothing")
+	addRemoteRefFlags(validateCmd.Flags())
+}
+
+func runValidate(cmd *cobra.Command, args []string) error {
+	var (
+		doc       *spec.Swagger
+		sourceMap map[string]token.Position
+		err       error
+	)
+	switch {
+	case validateInput != "" && len(args) > 0:
+		return errors.New("validate takes either packages or --input")
+	case validateInput != "":
+		var data []byte
+		data, err = os.ReadFile(validateInput)
+		if err != nil {
+			return err
+		}
+		doc, err = codescan.ParseInputSpec(data, false)
+		if err != nil {
+			return fmt.Errorf("invalid input spec %s: %w", validateInput, err)
+		}
+	case len(args) == 0:
+		return errors.New("validate requires packages to scan or --input")
+	default:
+		sourceMap = make(map[string]token.Position)
+		opts := &codescan.Options{
+			Packages:   args,
+			WorkDir:    validateWorkDir,
+			BuildTags:  validateBuildTags,
+			ScanModels: validateScanModels,
+			SourceMap:  sourceMap,
+		}
+		if validateConfigFile != "" {
+			cfg, cfgErr := loadConfig(validateConfigFile)
+			if cfgErr != nil {
+				return cfgErr
+			}
+			cfg.apply(opts, cmd.Flags())
+		}
+		doc, err = codescan.Run(opts)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("scan failed: %w", err)
+		}
+	}
+
+	// the problems are not a misuse of the command
+	cmd.SilenceUsage = true
+	problems := codescan.ValidateSpec(doc, sourceMap)
+	if validateRefs || offline {
+		resolved, err := resolveExternalRefs(cmd.Context(), doc, validateInput)
+		if err != nil {
+			return err
+		}
+		problems = append(problems, codescan.ValidateExternalRefs(doc, resolved)...)
+	}
+	if validateStrict {
+		for i := range problems {
+			if problems[i].Code != codescan.DiagnosticDeprecatedDefinition {
+				problems[i].Severity = codescan.SeverityError
+			}
+		}
+	}
+
+	return reportDiagnostics(os.Stdout, validateWorkDir, problems)
+}
@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/3idey/codescan/codescan"
+	"github.com/spf13/pflag"
+)
+
+// versionVerbose is the version --verbose flag.
+var versionVerbose bool
+
+func init() {
+	versionCmd.Flags().BoolVarP(&versionVerbose, "verbose", "v", false, "also print the build settings, the features and the defaults of the generate flags")
+}
+
+// buildSettings are the settings of the build info printed by version --verbose, besides the vcs ones.
+var buildSettings = []string{"-tags", "-trimpath", "CGO_ENABLED", "GOEXPERIMENT"}
+
+// writeBuildInfo writes the build settings of codescan, the values of the options naming its features, and
+// the defaults of the generate flags, for the bug reports.
+func writeBuildInfo(w io.Writer, flags *pflag.FlagSet) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "build:")
+	fmt.Fprintf(tw, "  go:\t%s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	if info, ok := debug.ReadBuildInfo(); ok {
+		fmt.Fprintf(tw, "  module:\t%s %s\n", info.Main.Path, info.Main.Version)
+		for _, setting := range info.Settings {
+			if strings.HasPrefix(setting.Key, "vcs.") || slices.Contains(buildSettings, setting.Key) {
+				fmt.Fprintf(tw, "  %s:\t%s\n", setting.Key, setting.Value)
+			}
+		}
+	}
+
+	fmt.Fprintln(tw, "features:")
+	for _, feature := range []struct {
+		name   string
+		values []string
+	}{
+		{"spec versions", []string{"2.0", "3.0"}},
+		{"compat", []string{codescan.CompatLegacyGoSwagger}},
+		{"naming", []string{codescan.NamingShort, codescan.NamingFull, codescan.NamingCamelPkg}},
+		{"enum extension styles", []string{codescan.EnumStyleGoSwagger, codescan.EnumStyleNSwag, codescan.EnumStyleBoth}},
+		{"merge strategies", []string{codescan.MergeScanWins, codescan.MergeInputWins, codescan.MergeError}},
+		{"router discovery", []string{codescan.RouterChi, codescan.RouterGin, codescan.RouterEcho}},
+		{"trailing slash", []string{codescan.TrailingSlashKeep, codescan.TrailingSlashStrip}},
+		{"secret patterns", secretPatternNames()},
+	} {
+		fmt.Fprintf(tw, "  %s:\t%s\n", feature.name, strings.Join(feature.values, ", "))
+	}
+
+	fmt.Fprintln(tw, "defaults:")
+	flags.VisitAll(func(flag *pflag.Flag) {
+		switch flag.DefValue {
+		case "", "false", "0", "[]":
+			return
+		}
+		if !flag.Hidden {
+			fmt.Fprintf(tw, "  --%s:\t%s\n", flag.Name, flag.DefValue)
+		}
+	})
+
+	return tw.Flush()
+}
+
+func secretPatternNames() []string {
+	var names []string
+	for _, pattern := range codescan.DefaultSecretPatterns() {
+		names = append(names, pattern.Name)
+	}
+	return names
+}
@@ -0,0 +1,234 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/tools/go/packages"
+)
+
+var (
+	// generate --watch flags
+	watch         bool
+	watchInterval time.Duration
+)
+
+// fileState is what tells a watched file changed.
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// runWatch regenerates the spec whenever the sources of the scanned packages change, until interrupted.
+// The sources are polled: a change is picked up once the files stay the same for a whole interval, so
+// that a burst of saves regenerates the spec once. Failed scans are reported, and the watch goes on.
+func runWatch(cmd *cobra.Command, args []string) error {
+	switch {
+	case len(outputFiles) == 0 && splitOutput == "":
+		return errors.New("--watch requires an output file, or --split-output")
+	case checkOutputs || reportSingleUse || reportIdentical || reportUnused:
+		return errors.New("--watch writes the spec, and can't be combined with --check or the reports")
+	case watchInterval <= 0:
+		return errors.New("--watch-interval must be positive")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// the failing scans are not a misuse of the command
+	cmd.SilenceUsage = true
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	fmt.Fprintf(os.Stderr, "watching %s, press Ctrl-C to stop\n", strings.Join(args, " "))
+	var dirs []watchedDir
+	for {
+		// the packages are resolved again at each regeneration, to watch the packages added since
+		dirs = watchedDirs(args, dirs)
+		before := snapshotFiles(dirs)
+		regenerate(cmd, args)
+
+		if !waitForChange(ctx, ticker, dirs, before) {
+			fmt.Fprintln(os.Stderr, "stopped watching")
+			return nil
+		}
+	}
+}
+
+// regenerate scans the packages and writes the output files which changed, reporting the outcome on one line.
+func regenerate(cmd *cobra.Command, args []string) {
+	start := time.Now()
+	elapsed := func() time.Duration { return time.Since(start).Round(time.Millisecond) }
+
+	changed, err := regenerateSpec(cmd, args)
+	switch {
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "%s scan failed after %v: %v\n", start.Format(time.TimeOnly), elapsed(), err)
+	case len(changed) == 0:
+		fmt.Fprintf(os.Stderr, "%s spec regenerated in %v, unchanged\n", start.Format(time.TimeOnly), elapsed())
+	default:
+		fmt.Fprintf(os.Stderr, "%s spec regenerated in %v, changed %s\n", start.Format(time.TimeOnly), elapsed(), strings.Join(changed, ", "))
+	}
+}
+
+// regenerateSpec scans the packages, and writes the output files which differ from the spec.
+func regenerateSpec(cmd *cobra.Command, args []string) ([]string, error) {
+	swspec, opts, err := scanForGenerate(cmd, args)
+	if err != nil {
+		return nil, err
+	}
+	if splitOutput != "" {
+		documents, err := splitDocuments(swspec)
+		if err != nil {
+			return nil, err
+		}
+		return writeSplitSpec(resolvePath(splitOutput), documents)
+	}
+	doc, err := outputDocument(swspec, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	for _, file := range resolvePaths(outputFiles) {
+		upToDate, err := specMatchesFile(doc, file, outputFormatFor(file, outputFormat), compact)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read output file: %w", err)
+		}
+		if !upToDate {
+			changed = append(changed, file)
+		}
+	}
+	if len(changed) == 0 {
+		return nil, nil
+	}
+	if _, err := writeSpecFiles(doc, changed, outputFormat, compact); err != nil {
+		return nil, err
+	}
+	return changed, nil
+}
+
+// waitForChange polls the watched files until they change and then stay the same for an interval. It
+// returns false once interrupted.
+func waitForChange(ctx context.Context, ticker *time.Ticker, dirs []watchedDir, before map[string]fileState) bool {
+	previous := before
+	changed := false
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+		current := snapshotFiles(dirs)
+		same := maps.EqualFunc(previous, current, func(a, b fileState) bool { return a == b })
+		if changed && same {
+			return true
+		}
+		changed = changed || !same
+		previous = current
+	}
+}
+
+// watchedDir is a directory whose Go files are watched, with its subdirectories for the patterns ending
+// with /..., e.g. to pick up the packages added below it.
+type watchedDir struct {
+	path      string
+	recursive bool
+}
+
+// watchedDirs returns the directories of the packages matching the patterns, and of the recursive patterns
+// relative to the working directory. The previous directories are kept when the packages fail to resolve.
+func watchedDirs(patterns []string, previous []watchedDir) []watchedDir {
+	var dirs []watchedDir
+	for _, pattern := range patterns {
+		if root, recursive := strings.CutSuffix(pattern, "/..."); recursive && (root == "." || strings.HasPrefix(root, "./") || strings.HasPrefix(root, "../")) {
+			dirs = append(dirs, watchedDir{path: filepath.Join(workDir, root), recursive: true})
+		}
+	}
+
+	cfg := &packages.Config{Mode: packages.NeedName | packages.NeedFiles, Dir: workDir}
+	if len(loaderEnv) > 0 || packagesDriver != "" {
+		cfg.Env = append(os.Environ(), loaderEnv...)
+		if packagesDriver != "" {
+			cfg.Env = append(cfg.Env, "GOPACKAGESDRIVER="+packagesDriver)
+		}
+	}
+	if buildTags != "" {
+		cfg.BuildFlags = []string{"-tags", buildTags}
+	}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "can't resolve the packages to watch: %v\n", err)
+		return previous
+	}
+	for _, pkg := range pkgs {
+		files := slices.Concat(pkg.GoFiles, pkg.IgnoredFiles)
+		if len(files) > 0 {
+			dirs = append(dirs, watchedDir{path: filepath.Dir(files[0])})
+		}
+	}
+
+	// the config and input files change the spec as well
+	for _, file := range []string{configPath(), inputSpec, metaFile, descriptionCatalog} {
+		if file != "" {
+			dirs = append(dirs, watchedDir{path: resolvePath(file)})
+		}
+	}
+	return dirs
+}
+
+// snapshotFiles returns the state of the Go files of the watched directories, and of the watched files.
+func snapshotFiles(dirs []watchedDir) map[string]fileState {
+	snapshot := make(map[string]fileState)
+	record := func(path string, info fs.FileInfo) {
+		snapshot[path] = fileState{modTime: info.ModTime(), size: info.Size()}
+	}
+
+	for _, dir := range dirs {
+		info, err := os.Stat(dir.path)
+		if err != nil {
+			continue
+		}
+		if !info.IsDir() {
+			record(dir.path, info)
+			continue
+		}
+		_ = filepath.WalkDir(dir.path, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				// a directory removed while walking is picked up by the next snapshot
+				return nil
+			}
+			if entry.IsDir() {
+				if path == dir.path {
+					return nil
+				}
+				name := entry.Name()
+				if !dir.recursive || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata" || name == "vendor" {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if strings.HasSuffix(entry.Name(), ".go") {
+				if info, err := entry.Info(); err == nil {
+					record(path, info)
+				}
+			}
+			return nil
+		})
+	}
+	return snapshot
+}
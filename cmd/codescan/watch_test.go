@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// watchedAPI is the api.go of pathsModule, with the summary of its operation.
+func watchedAPI(summary string) string {
+	return strings.Replace(pathsModule["api.go"], "Lists the pets.", summary, 1)
+}
+
+// brokenParams are parameters whose field type fails the scan.
+const brokenParams = `
+// swagger:parameters listPets
+type listPetsParams struct {
+	// in: query
+	Limit chan int ` + "`json:\"limit\"`" + `
+}
+`
+
+func TestWatch(t *testing.T) {
+	cli := buildCLI(t)
+
+	t.Run("should refuse the flags it can't be combined with", func(t *testing.T) {
+		for _, tc := range []struct {
+			args     []string
+			expected string
+		}{
+			{[]string{"--watch", "."}, "--watch requires an output file, or --split-output"},
+			{[]string{"--watch", "-o", "swagger.json", "--check", "."}, "can't be combined with --check or the reports"},
+			{[]string{"--watch", "-o", "swagger.json", "--watch-interval", "0s", "."}, "--watch-interval must be positive"},
+		} {
+			dir := t.TempDir()
+			writeFiles(t, dir, pathsModule)
+			out, code := runCLI(t, cli, dir, append([]string{"generate"}, tc.args...)...)
+			assert.Equal(t, 1, code, out)
+			assert.Contains(t, out, tc.expected)
+		}
+	})
+
+	t.Run("should regenerate the spec once per burst of saves, until interrupted", func(t *testing.T) {
+		const interval = 300 * time.Millisecond
+		dir := t.TempDir()
+		writeFiles(t, dir, pathsModule)
+
+		cmd := exec.Command(cli, "generate", "--watch", "--watch-interval", interval.String(), "-o", "swagger.json", ".")
+		cmd.Dir = dir
+		stderr, err := cmd.StderrPipe()
+		require.NoError(t, err)
+		require.NoError(t, cmd.Start())
+		t.Cleanup(func() { _ = cmd.Process.Kill() })
+
+		lines := make(chan string)
+		go func() {
+			defer close(lines)
+			scanner := bufio.NewScanner(stderr)
+			for scanner.Scan() {
+				lines <- scanner.Text()
+			}
+		}()
+		// next returns the next line of the summaries, failing after the timeout
+		next := func(t *testing.T, timeout time.Duration) string {
+			t.Helper()
+			select {
+			case line, ok := <-lines:
+				require.True(t, ok, "the watch stopped")
+				return line
+			case <-time.After(timeout):
+				require.FailNow(t, "no summary printed", "after %v", timeout)
+				return ""
+			}
+		}
+
+		assert.Contains(t, next(t, time.Minute), "watching .")
+		assert.Contains(t, next(t, time.Minute), "spec regenerated")
+
+		for _, step := range []struct {
+			name     string
+			saves    []string // the contents of api.go saved in a burst
+			expected string
+		}{
+			{
+				name:     "a burst of saves",
+				saves:    []string{watchedAPI("Lists the cats."), watchedAPI("Lists the dogs."), watchedAPI("Lists the animals.")},
+				expected: "changed swagger.json",
+			},
+			{
+				name:     "a save leaving the spec unchanged",
+				saves:    []string{watchedAPI("Lists the animals.") + "\n"},
+				expected: "unchanged",
+			},
+			{
+				name:     "a save breaking the scan",
+				saves:    []string{watchedAPI("Lists the animals.") + brokenParams},
+				expected: "scan failed",
+			},
+			{
+				name:     "a save fixing the scan",
+				saves:    []string{watchedAPI("Lists the pets.")},
+				expected: "changed swagger.json",
+			},
+		} {
+			for _, content := range step.saves {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "api.go"), []byte(content), 0o600))
+				time.Sleep(interval / 6)
+			}
+			assert.Contains(t, next(t, time.Minute), step.expected, step.name)
+			select {
+			case line := <-lines:
+				assert.Fail(t, "the burst was regenerated twice", "%s: %s", step.name, line)
+			case <-time.After(4 * interval):
+			}
+		}
+		spec, err := os.ReadFile(filepath.Join(dir, "swagger.json"))
+		require.NoError(t, err)
+		assert.Contains(t, string(spec), "Lists the pets.")
+
+		require.NoError(t, cmd.Process.Signal(os.Interrupt))
+		assert.Equal(t, "stopped watching", next(t, time.Minute))
+		require.NoError(t, cmd.Wait(), "an interrupt stops the watch cleanly")
+	})
+}
@@ -4,13 +4,20 @@
 package codescan
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"go/ast"
 	"go/token"
 	"go/types"
 	"log"
+	"maps"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-openapi/spec"
 	"github.com/go-openapi/swag"
@@ -18,7 +25,7 @@ import (
 	"golang.org/x/tools/go/packages"
 )
 
-const pkgLoadMode = packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps | packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo
+const pkgLoadMode = packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps | packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo | packages.NeedModule
 
 func safeConvert(str string) bool {
 	b, err := swag.ConvertBool(str)
@@ -40,9 +47,15 @@ const (
 	modelNode
 	parametersNode
 	responseNode
+	pathNode
+	tagNode
 )
 
 // Options for the scanner.
+//
+// The zero value of every field keeps the behavior of the version which introduced it, forever: new fields
+// only ever opt into new behavior, so that a zero Options, as returned by NewOptions, always scans the same way.
+// Run does not call Validate, so that options accepted by earlier versions keep working.
 type Options struct {
 	Packages                []string
 	InputSpec               *spec.Swagger
@@ -58,15 +71,373 @@ type Options struct {
 	RefAliases              bool // aliases result in $ref, otherwise aliases are expanded
 	TransparentAliases      bool // aliases are completely transparent, never creating definitions
 	DescWithRef             bool // allow overloaded descriptions together with $ref, otherwise jsonschema draft4 $ref predates everything
+	// ExtraBuildTags are build tags only applied to find annotations, e.g. in documentation-only files
+	// guarded by "//go:build codescan_docs". Files only compiled with these tags are classified on top of
+	// the packages loaded with BuildTags, without affecting which runtime code is type-checked.
+	ExtraBuildTags string
+	// RequiredFromPointers marks non-pointer struct fields without omitempty as required in
+	// model and body schemas, leaving pointer fields optional. An explicit "required:" directive
+	// on a field always wins, and readOnly fields are never made required automatically.
+	RequiredFromPointers bool
+	// RequiredFromPointersPackages restricts RequiredFromPointers to structs declared in packages
+	// matching one of these globs (e.g. "github.com/acme/api/models/..."). Empty means all packages.
+	RequiredFromPointersPackages []string
+	// InlineSingleUse inlines the definitions referenced from exactly one place, except those
+	// taking part in a reference cycle or a discriminated hierarchy.
+	InlineSingleUse bool
+	// Meta holds meta information (e.g. loaded with ParseMeta) merged with precedence over swagger:meta.
+	Meta *spec.Swagger
+	// IncludeTestScope includes the declarations annotated with the "scope:test" modifier
+	// (e.g. "swagger:response listUsersResponse scope:test"), and loads _test.go files to find them.
+	IncludeTestScope bool
+	// DeclarationOrder emits an x-order extension on the properties of struct schemas, following the
+	// declaration order of the fields. MarshalJSON and MarshalYAML emit properties in this order.
+	DeclarationOrder bool
+	// ForceIncludeDirs lists directories (e.g. handlers generated from design files) whose packages are
+	// always loaded and classified, even when not matched by Packages, filtered out by Include and Exclude,
+	// or only reached as dependencies with ExcludeDeps.
+	ForceIncludeDirs []ForceIncludeDir
+	// RateLimits documents rate limits, given as "<limit>/<period>" (e.g. "100/min"), with x-rate-limit extensions.
+	// The "default" entry applies to the spec root. Other keys are "METHOD /path" globs (e.g. "POST /orders",
+	// "* /admin/...") matched against operations, which also get a sentence appended to their description.
+	// Exact keys win over globs, then the longest glob wins. Explicit x-rate-limit extensions are kept.
+	RateLimits map[string]string
+	// MergeIdentical merges the structurally identical definitions (see IdenticalDefinitionGroups)
+	// into their canonical definition, rewriting the $refs to the merged ones.
+	MergeIdentical bool
+	// DefinitionPositions, when not nil, is filled with the position of the Go declaration of each definition.
+	DefinitionPositions map[string]token.Position
+	// OnProgress, when not nil, is called with the progress of the scan. It is called from a dedicated
+	// goroutine and never blocks the scan: updates not consumed yet are replaced by newer ones.
+	// Run waits for the last update to be delivered before returning, so that OnProgress is never
+	// called once Run returns: a slow callback delays the return by the calls still pending, two at most.
+	OnProgress func(phase Phase, done, total int)
+	// ForbidEmptySchemas fails the scan when properties are documented with an empty schema (e.g. from an
+	// unsupported type, or an interface), instead of warning about them.
+	ForbidEmptySchemas bool
+	// Stats, when not nil, is filled with statistics about the scan.
+	Stats *Stats
+	// RelativeRefs, when set, is the name of the spec document qualifying its local $refs,
+	// e.g. "swagger.json#/definitions/Pet" instead of "#/definitions/Pet". See QualifyRefs.
+	RelativeRefs string
+	// DefinitionIndex, when not nil, is filled with the Go type of each definition, e.g. to be published
+	// with the spec and used by the scans of other modules.
+	DefinitionIndex *DefinitionIndex
+	// UseDefinitionIndex refers to the definitions of a published spec for the Go types found in the index,
+	// with external $refs, instead of emitting local definitions.
+	UseDefinitionIndex *DefinitionIndex
+	// SourceMap, when not nil, is filled with the Go position of the operations, parameters, responses,
+	// headers, definitions and properties of the spec, by JSON pointer, e.g. "/paths/~1users/post/parameters/0".
+	SourceMap map[string]token.Position
+	// DefaultSkips skips the packages found in vendor, third_party and testdata directories below WorkDir,
+	// e.g. vendored code with stray annotations. Packages matched by ForceIncludeDirs are still scanned.
+	DefaultSkips bool
+	// AlsoScan lists directory globs, relative to WorkDir, which are scanned despite DefaultSkips
+	// (e.g. "vendor/github.com/acme/...").
+	AlsoScan []string
+	// CodeSamples attaches x-codeSamples extensions to the operations, with curl and Go (net/http) snippets
+	// built from their parameters, body example and security requirements.
+	CodeSamples bool
+	// CodeSampleTemplates adds snippets in other languages to CodeSamples, as text/template sources by language,
+	// executed with a *CodeSampleRequest. The "curl" and "go" entries replace the built-in snippets.
+	CodeSampleTemplates map[string]string
+	// DefaultIdempotency documents the idempotency of the operations which don't declare it with an
+	// x-idempotent extension: true for GET, HEAD, OPTIONS, PUT and DELETE, false for POST and PATCH.
+	DefaultIdempotency bool
+	// Diagnostics, when not nil, is filled with the problems found in the Go sources, e.g. for a lint report.
+	Diagnostics *[]Diagnostic
+	// DescriptionCatalog, when not nil, replaces the titles, summaries and descriptions of the spec with their
+	// translation, by JSON pointer (e.g. "/paths/~1pets/get/summary"). See ExtractStrings.
+	DescriptionCatalog map[string]string
+	// MarkUntranslated adds an x-untranslated extension to the elements whose strings are missing from
+	// DescriptionCatalog, and are kept in the source language.
+	MarkUntranslated bool
+	// StrictJSONNames fails the scan when tagged fields of a struct have the same json name at the same
+	// embedding depth, which encoding/json drops as ambiguous. Otherwise they are reported with a diagnostic.
+	StrictJSONNames bool
+	// MaxSchemaDepth, when positive, fails the scan when the schema of a type nests more than this number of
+	// properties, items and additional properties, e.g. with deeply nested anonymous structs. Zero is unlimited.
+	MaxSchemaDepth int
+	// EnumExtensionStyle emits the names of the enum values, and the discriminator mappings, with the extensions
+	// of EnumStyleGoSwagger (x-enum-varnames), EnumStyleNSwag (x-enumNames, x-discriminator-mapping) or
+	// EnumStyleBoth. Empty emits none.
+	EnumExtensionStyle string
+	// CustomFormats are the formats of swagger:strfmt annotations known to the consumers of the spec, besides the
+	// formats of the swagger specification and of the strfmt registry.
+	CustomFormats []string
+	// StrictFormats fails the scan when a swagger:strfmt annotation is on a type which doesn't marshal as a string,
+	// or names an unknown format. Otherwise they are reported with a diagnostic.
+	StrictFormats bool
+	// CheckStatusCodes reports, with diagnostics, the constant status codes written by the handler of a route
+	// or operation which its responses don't document, and the documented ones it never writes. The handler
+	// is the function documented by the annotation, or holding it.
+	CheckStatusCodes bool
+	// Audience keeps the operations of these audiences, declared with an x-audience extension (e.g. internal,
+	// partner or public), and drops the others, with the definitions, parameters, responses, security definitions
+	// and tags only they use. Operations without an audience are public. Empty keeps all the operations.
+	Audience []string
+	// RequireAudience fails the scan when an operation has no audience, rather than making it public.
+	RequireAudience bool
+	// StrictParameters fails the scan when the structs embedded in a swagger:parameters struct declare the same
+	// parameter, in the same location, or when a simple parameter has a composite type without an encoding, e.g.
+	// a struct in the query. Otherwise it is reported with a diagnostic, and the last embedded struct wins.
+	StrictParameters bool
+	// RequireAllIncludeTags keeps the routes and operations with all the IncludeTags, rather than one of them.
+	// An excluded tag drops a route or an operation either way.
+	RequireAllIncludeTags bool
+	// DefinitionNameTemplate names the definitions of the types without a name in their swagger:model annotation,
+	// with a text/template executed with a DefinitionNameData, e.g. "{{.PackageAlias}}.{{.TypeName}}". Empty
+	// names the definitions after their type. The scan fails when it gives the same name to two types.
+	DefinitionNameTemplate string
+	// PackageAliases are the PackageAlias of DefinitionNameTemplate and of the NamingCamelPkg DefinitionNaming,
+	// by package path (e.g. "users.v1" for "github.com/acme/api/users/v1"). The other packages are aliased by
+	// their name.
+	PackageAliases map[string]string
+	// Rules are lint rules checking the built spec, and configuring the severity of the built-in diagnostics.
+	// The problems are reported with Diagnostics. See Rule.
+	Rules []Rule
+	// KeepGoing builds the spec without the declarations whose building panicked, rather than failing once
+	// they are all reported with the DiagnosticBuilderPanic diagnostics.
+	KeepGoing bool
+	// NoRecover lets the panics of the builders crash the process, with their stack trace, e.g. to debug codescan.
+	NoRecover bool
+	// CheckSecrets reports the examples, defaults and descriptions of the spec matching the secret patterns,
+	// e.g. an AWS access key, with DiagnosticSecret diagnostics.
+	CheckSecrets bool
+	// FailOnSecrets fails the scan when CheckSecrets finds secrets, which it implies.
+	FailOnSecrets bool
+	// SecretPatterns extend DefaultSecretPatterns: a pattern named after a default one replaces it, or
+	// disables it without a Pattern.
+	SecretPatterns []SecretPattern
+	// NoExamples removes the examples of the spec once it is built, e.g. from a spec published to third
+	// parties, see StripExamples. Defaults and enums are kept.
+	NoExamples bool
+	// SetTypes are the Go types marshaled as JSON arrays of unique elements, e.g.
+	// "github.com/acme/sets.StringSet", besides the sets of github.com/deckarep/golang-set and
+	// github.com/hashicorp/go-set: they are documented as arrays with uniqueItems.
+	SetTypes []string
+	// AllowEmpty accepts a scan without operations and definitions, which otherwise fails with ErrEmptyScan,
+	// e.g. for packages which aren't annotated yet.
+	AllowEmpty bool
+	// DiscoverEnums documents the exported constants of the named basic types, e.g. a type Status string and
+	// its const block, as their enums without a swagger:enum annotation. A type opts out with
+	// swagger:enum:ignore, or an enum: directive which sets the values.
+	DiscoverEnums bool
+	// BindingExtensions emits x-go-field, x-go-type and x-go-decoder on the parameters of the swagger:parameters
+	// structs: the Go field of a parameter, its type and its decoder (json, form, path, query or header), e.g.
+	// for the generators of binding code.
+	BindingExtensions bool
+	// GenericNameTemplate names the definitions of the instantiations of the generic struct types, e.g. Page[User],
+	// with a text/template executed with a GenericNameData. Empty is DefaultGenericNameTemplate, e.g. PageOfUser.
+	GenericNameTemplate string
+	// DefinitionRenames renames definitions, by the name the scan gives them, e.g. {"Error": "APIError"}, rewriting
+	// the $refs to them. The original name is kept in x-go-original-name.
+	DefinitionRenames map[string]string
+	// RenameCollisions renames the definitions whose names break the generators of clients and servers, instead of
+	// reporting them with DiagnosticReservedName: reserved names are suffixed, e.g. ErrorModel, and invalid
+	// characters removed, e.g. PageUser for Page/User.
+	RenameCollisions bool
+	// NoSuppressions ignores the codescan:ignore comments suppressing diagnostics, see Suppression.
+	NoSuppressions bool
+	// Suppressions, when not nil, is filled with the codescan:ignore comments found, with the number of
+	// diagnostics each one suppresses, e.g. to audit them.
+	Suppressions *[]Suppression
+	// SortParameters orders the parameters of the paths and operations by location, then name, e.g. the
+	// header parameters before the query ones, rather than in the order of the Go fields declaring them.
+	SortParameters bool
+	// OmitEmptyAsOptional never requires the properties of the fields tagged omitempty, which may be missing
+	// from the JSON, even when they are annotated required: true.
+	OmitEmptyAsOptional bool
+	// ContentNegotiators are the functions taking the media types an API supports, e.g. the constructors of
+	// content-negotiation middlewares: the constant strings passed to them set the consumes or produces of
+	// the spec, or of the operations of the package calling them, unless annotated.
+	ContentNegotiators []ContentNegotiator
+	// CacheDir, when set, is the directory of the cache of the scans: a scan whose options, codescan build and Go
+	// files, of the packages and of their dependencies, are those of a cached scan returns its spec, with its
+	// Stats, Diagnostics and other outputs, without loading and type-checking the packages. See Stats.Cached.
+	// The other scans read the schemas of the models of the packages unchanged, with their dependencies,
+	// rather than building them again. See Stats.ReusedModels.
+	CacheDir string
+	// StrictTags fails when a tag of a scanned operation is declared by no swagger:tag, input spec or meta
+	// file, e.g. user for users, with the closest declared tag.
+	StrictTags bool
+	// ValidatorTags are the keys of the struct tags of go-playground/validator rules, e.g. validate or binding,
+	// which set the constraints of the properties and parameters of their fields: required, min, max, len,
+	// oneof, the formats like email or uuid, and the rules of the items after dive. The annotations of a field
+	// win over its tags, and the unknown rules are ignored.
+	ValidatorTags []string
+	// TypeMappings are the schemas of Go types by qualified name, e.g. "github.com/acme/money.Amount", documenting
+	// their values directly, as pointers, as elements or as map values, instead of their own fields. They
+	// extend and override the built-in mappings of common types, e.g. time.Duration to an int64 integer, or
+	// github.com/google/uuid.UUID to a uuid string.
+	TypeMappings map[string]spec.Schema
+	// RouterDiscovery infers the routes registered with a router, chi, gin or echo, from the calls of its
+	// methods, e.g. r.Get("/pets", listPets), through the groups, sub-routers and mounts, whose prefixes
+	// compose. The doc comment of a handler is the body of its route, and its name the ID of the operation.
+	// The routes declared with swagger:route or swagger:operation win over the inferred ones.
+	RouterDiscovery string
+	// IncludeUndocumented keeps the routes of RouterDiscovery whose handler has no doc comment, as minimal
+	// operations: the ID of the handler, the tag of its router group, or of its package, and a default
+	// response, marked x-undocumented. They are left out otherwise. Both are reported with an
+	// undocumented-operation diagnostic, and counted by Stats.UndocumentedOperations.
+	IncludeUndocumented bool
+	// SecurityDefaults are the security requirements of the scanned operations which don't declare any, e.g.
+	// {"oauth2": {"read:users"}}, alternatives like the lines of the Security: section of a route. The
+	// operations declaring "Security: none" keep an empty list, e.g. the public ones.
+	SecurityDefaults []map[string][]string
+	// Logger, when not nil, receives the diagnostics of the scan as they are found, e.g. the skipped fields,
+	// the unresolved $refs and the duplicate operationIds, with their positions, instead of Run logging them.
+	// The diagnostics disabled by a Rule are not sent.
+	Logger func(Diagnostic)
+	// OutputSets derive several specs from the same scan, e.g. a public and an admin API distinguished by the
+	// tags of their operations, see OutputSet. The packages are loaded and type-checked once.
+	OutputSets []OutputSet
+	// OutputSetSpecs, when not nil, is filled with the spec of each of the OutputSets, by name.
+	OutputSetSpecs map[string]*spec.Swagger
+	// ErrorHelpers are the functions writing error responses, e.g. httperr.NotFound(w, msg): the operations
+	// whose handlers call them get their responses, marked x-inferred, unless they document their status.
+	ErrorHelpers []ErrorHelper
+	// WrapRefsForDescription keeps the descriptions of the $refs without DescWithRef, e.g. of a property or of
+	// an allOf member, by wrapping the $ref in an allOf: {"description": ..., "allOf": [{"$ref": ...}]}.
+	WrapRefsForDescription bool
+	// FailFast stops the scan at the first mistake of the annotations. By default, the scan goes on with the
+	// other files and declarations, and fails with all the mistakes found, each at its position.
+	FailFast bool
+	// FailOnWarning fails the scan when it reports diagnostics, e.g. the malformed annotations it ignores and
+	// the $refs to undefined responses, rather than only logging them.
+	FailOnWarning bool
+	// PruneUnused removes the definitions the operations don't reach through their $refs, e.g. the leftovers of
+	// an input spec or of a field removed from a response, see UnreachableDefinitions. The definitions of the
+	// InputSpec are kept, with those they refer to, unless PruneInput is set.
+	PruneUnused bool
+	// PruneInput also removes the unreachable definitions of the InputSpec with PruneUnused.
+	PruneInput bool
+	// UnusedDefinitions, when not nil, is filled with the definitions PruneUnused removes, or would remove
+	// without it, with their Go type.
+	UnusedDefinitions *[]UnusedDefinition
+	// DefinitionNaming is the strategy naming the definitions of the types without a name in their
+	// swagger:model annotation: NamingShort, NamingFull or NamingCamelPkg. Empty names them like NamingShort,
+	// but a type whose definition has the name of another one overrides it, with a definition-name-collision
+	// diagnostic: with a strategy, a template or a DefinitionNamer, the scan fails.
+	DefinitionNaming string
+	// DefinitionNamer, when not nil, names the definitions of the types without a name in their swagger:model
+	// annotation from their package path and type name, after their type when it returns an empty name. The
+	// scans with a DefinitionNamer aren't cached, see CacheDir.
+	DefinitionNamer func(pkgPath, typeName string) string
+	// RequireSecurity fails the scan on the operations without effective security requirements, i.e. declaring
+	// none when the spec has no top-level ones, with unsecured-operation diagnostics. The public operations,
+	// declaring "Security: none", are deliberate and pass.
+	RequireSecurity bool
+	// Overlay maps the absolute paths of Go files to the contents which the scan reads instead of those on
+	// disk, e.g. the unsaved buffers of an editor, see packages.Config. Precheck reads them too.
+	Overlay map[string][]byte
+	// PreserveCommentFormat keeps the indentation of the lines of the titles and the descriptions, removing
+	// only the comment markers and the space after them, so that their markdown lists and code blocks
+	// survive. By default, the lines are stripped of their indentation, of their dashes and of their stars.
+	PreserveCommentFormat bool
+	// Concurrency is the number of goroutines classifying the annotations of the files and building the
+	// schemas of the models, GOMAXPROCS when zero, and 1 to scan sequentially. The spec, the diagnostics and
+	// the calls of Logger are the same whatever the concurrency.
+	Concurrency int
+	// DefaultTag, when set, is the key of the struct tags setting the defaults of the properties and parameters
+	// of their fields, e.g. default for `default:"25"`, parsed as values of the types of the fields: a value
+	// which isn't one fails the scan. The Default: annotations win over the tags.
+	DefaultTag string
+	// AutoDeprecate deprecates the types, the fields and the handlers of the routes whose doc comments follow
+	// the convention of Go, a paragraph starting with "Deprecated:", like a swagger:deprecated annotation: the
+	// definitions and the properties get an x-deprecated extension, and the operations are deprecated.
+	AutoDeprecate bool
+	// DeprecationNotice, when set, prefixes the descriptions of the deprecated properties, parameters and
+	// definitions, e.g. "Deprecated:".
+	DeprecationNotice string
+	// ExplainPackages are import paths whose decisions the scan records in Stats.PackageDecisions: whether
+	// each package was loaded, classified or excluded, and by which rule.
+	ExplainPackages []string
+	// MergeStrategy merges the scan with InputSpec key by key, rather than building over it: the operations by
+	// path and method, the definitions and the security definitions defined by both are conflicts, which
+	// MergeScanWins, MergeInputWins or MergeError resolve, the latter failing with ErrMergeConflict. The info,
+	// the host and the base path of the input spec are filled by swagger:meta, the schemes, consumes and
+	// produces joined, and the tags filled by swagger:tag. Stats.MergeEntries tell where each entry comes from.
+	// Empty builds over the input spec.
+	MergeStrategy string
+	// Compat recognizes the annotations of other dialects, e.g. with CompatLegacyGoSwagger the swagger:params,
+	// +swagger:model and @swagger:route of older go-swagger code, each one reported as a legacy-annotation
+	// diagnostic. MigrateAnnotations rewrites them to the canonical spelling.
+	Compat []string
+	// InferParamTypes types the path parameters added for the regular expressions of the route templates, e.g.
+	// {id:[0-9]+}, after them: integer for the ones only matching integers, rather than strings with a pattern.
+	InferParamTypes bool
+	// Env are variables added to the environment of the go command, or of the driver, loading the packages, as
+	// KEY=value pairs, e.g. GOFLAGS=-mod=vendor or the variables of a Bazel gopackagesdriver.
+	Env []string
+	// PackagesDriver is the GOPACKAGESDRIVER loading the packages, e.g. the gopackagesdriver of rules_go,
+	// rather than the go command: it is handed the load mode of the scan, PackagesLoadMode, and must answer
+	// with the names, Go files, compiled Go files, imports and modules of the packages and of their
+	// dependencies, which codescan parses and type checks from source, without export data. "off" forces the
+	// go command. Empty uses the GOPACKAGESDRIVER of Env, or of the environment of the process.
+	PackagesDriver string
+	// Loader, when not nil, loads the packages instead of DefaultLoader, e.g. with the loader of a build
+	// system, from a packages.Config holding the working directory, the build flags, the overlay, the
+	// environment and the load mode of the scan. The packages fail the scan when they lack the information of
+	// PackagesLoadMode. ForceIncludeDirs, ExtraBuildTags and CacheDir load with it too.
+	Loader Loader
+	// TrailingSlash is the policy of the slash ending the paths of the routes, of the operations, of the
+	// swagger:path annotations and of the InputSpec, which the scanned paths are compared with:
+	// TrailingSlashKeep keeps them, e.g. /users/ and /users are two paths, and TrailingSlashStrip removes
+	// them, but for the root path. Empty keeps them.
+	TrailingSlash string
+	// DefaultResponse is a response of every operation, e.g. the error envelope of a recovery middleware,
+	// added, marked x-injected-default, to the operations which document neither its status nor a default
+	// response, unless they have the "No Default Response: true" directive. The zero value adds none.
+	DefaultResponse DefaultResponse
+	// ValidateExamples fails the scan when the value of a named example of a body parameter or a response,
+	// see the Examples: block, doesn't match its schema, with an invalid-example diagnostic naming the example.
+	ValidateExamples bool
+	// MaxEnumValues is the number of values above which the enum of a type, of a swagger:enum or discovered,
+	// is omitted from the schemas, which name the Go type with x-enum-reference instead, with a large-enum
+	// diagnostic. A type keeps its enum with the "Enum: full" directive, and omits it with "Enum: omit".
+	// Zero, or a negative number, keeps the enums whatever their size; the codescan command defaults to
+	// DefaultMaxEnumValues.
+	MaxEnumValues int
+	// HoistParameters, when 2 or more, replaces the inline parameters identical in at least this number of
+	// operations, but for the body parameters, with $refs to shared parameters, named after their name and
+	// location, e.g. limitQuery, unless a shared parameter of the spec is identical. Stats.HoistedParameters
+	// list them. Zero hoists none.
+	HoistParameters int
+	// EmitGenerationInfo records the options of the scan under the x-codescan-options extension of the root
+	// of the spec, see GenerationInfo, for the replay command to generate the spec again.
+	EmitGenerationInfo bool
+	// Reproducible leaves the generation info out of the spec, that of the input spec too, so that the spec
+	// only depends on the code whatever the version of codescan. It takes precedence over EmitGenerationInfo.
+	Reproducible bool
+}
+
+// ForceIncludeDir is a directory scanned regardless of the package rules, see Options.ForceIncludeDirs.
+type ForceIncludeDir struct {
+	Dir         string   // directory, relative to WorkDir, scanned recursively
+	IncludeTags []string // tags of the routes and operations to include, in place of Options.IncludeTags
+	ExcludeTags []string // tags of the routes and operations to exclude, in place of Options.ExcludeTags
 }
 
 type scanCtx struct {
-	pkgs []*packages.Package
-	app  *typeIndex
+	pkgs     []*packages.Package
+	app      *typeIndex
+	progress *progressReporter
+	rules    []compiledRule
+	secrets  []secretPattern
+	context  context.Context // stops the scan once done, see RunWithContext
 
 	opts *Options
 }
 
+// canceled is the error of the context of the scan once it is done, see RunWithContext.
+func (s *scanCtx) canceled() error {
+	if s.context == nil {
+		return nil
+	}
+	return s.context.Err()
+}
+
 func sliceToSet(names []string) map[string]bool {
 	result := make(map[string]bool)
 	for _, v := range names {
@@ -75,31 +446,209 @@ func sliceToSet(names []string) map[string]bool {
 	return result
 }
 
-// Run the scanner to produce a spec with the options provided.
+// Result is the outcome of a scan run with RunWithContext.
+type Result struct {
+	Spec *spec.Swagger
+	// Diagnostics are the problems found in the Go sources, with their positions, but those disabled by a Rule.
+	Diagnostics []Diagnostic
+	Stats       Stats
+	// OutputSets are the specs of Options.OutputSets, by name.
+	OutputSets map[string]*spec.Swagger
+}
+
+// Run the scanner to produce a spec with the options provided. The diagnostics of the scan are logged as
+// warnings, unless Options.Logger receives them.
 func Run(opts *Options) (*spec.Swagger, error) {
-	sc, err := newScanCtx(opts)
+	logged := *opts
+	if logged.Logger == nil {
+		logged.Logger = logDiagnostic
+	}
+	result, err := RunWithContext(context.Background(), &logged)
+	if err != nil {
+		return nil, err
+	}
+	return result.Spec, nil
+}
+
+// RunWithContext runs the scanner like Run, returning the diagnostics with the spec. Unlike Run, it doesn't log
+// them: Options.Logger receives them as they are found. The scan stops once the context is done, while loading
+// the packages or building the spec, and fails with the error of the context.
+func RunWithContext(ctx context.Context, opts *Options) (*Result, error) {
+	result := new(Result)
+	recording := *opts
+	recording.Stats = &result.Stats
+	recording.Diagnostics = &result.Diagnostics
+	if recording.Logger == nil {
+		recording.Logger = func(Diagnostic) {}
+	}
+	if len(opts.OutputSets) > 0 {
+		result.OutputSets = make(map[string]*spec.Swagger, len(opts.OutputSets))
+		recording.OutputSetSpecs = result.OutputSets
+	}
+
+	var err error
+	// the names of a DefinitionNamer can't be keyed
+	if opts.CacheDir != "" && opts.DefinitionNamer == nil {
+		result.Spec, err = runCached(ctx, &recording)
+	} else {
+		result.Spec, err = run(ctx, &recording, nil, nil)
+	}
+	if opts.Stats != nil {
+		*opts.Stats = result.Stats
+	}
+	if opts.Diagnostics != nil {
+		*opts.Diagnostics = result.Diagnostics
+	}
+	if err != nil {
+		return nil, err
+	}
+	if opts.OutputSetSpecs != nil {
+		maps.Copy(opts.OutputSetSpecs, result.OutputSets)
+	}
+	return result, nil
+}
+
+// run scans the packages of the options, or the preloaded ones, reusing the schemas of the models cached, if any.
+func run(ctx context.Context, opts *Options, preloaded []*packages.Package, models *modelCache) (*spec.Swagger, error) {
+	sc, err := newScanCtxFor(ctx, opts, preloaded)
+	if err != nil {
+		return nil, err
+	}
+	sc.app.models = models
+	defer sc.progress.close()
+
+	// the input spec is the base of the spec built, and is copied first for the output sets
+	setInputs, err := outputSetInputs(opts)
 	if err != nil {
 		return nil, err
 	}
 	sb := newSpecBuilder(opts.InputSpec, sc, opts.ScanModels)
-	return sb.Build()
+	swspec, err := sb.Build()
+	if err != nil {
+		if errors.Is(err, ErrMergeConflict) && opts.Stats != nil {
+			*opts.Stats = sc.app.stats
+		}
+		return nil, err
+	}
+	if err := sc.app.checkPanics(opts.KeepGoing); err != nil {
+		return nil, err
+	}
+	if err := sc.app.checkEmptyScan(swspec, opts); err != nil {
+		if opts.Stats != nil {
+			*opts.Stats = sc.app.stats
+		}
+		return nil, err
+	}
+	sc.app.stats.Tags = StatsByTag(swspec)
+	if err := sc.app.checkEmptySchemas(opts.ForbidEmptySchemas); err != nil {
+		return nil, err
+	}
+	var positions map[string]token.Position
+	if sc.app.sourceMap {
+		positions = extractSourceMap(swspec)
+	}
+	if err := sc.app.checkRules(sc.rules, swspec, positions); err != nil {
+		return nil, err
+	}
+	if err := sc.app.checkSecrets(sc.secrets, swspec, positions, opts.FailOnSecrets); err != nil {
+		return nil, err
+	}
+	if err := sc.app.checkWarnings(opts.FailOnWarning); err != nil {
+		return nil, err
+	}
+	setSpecs, err := buildOutputSets(sc, opts.OutputSets, setInputs)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Stats != nil {
+		*opts.Stats = sc.app.stats
+	}
+	if opts.Diagnostics != nil {
+		*opts.Diagnostics = sc.app.reportedDiagnostics()
+	}
+	if opts.Suppressions != nil {
+		*opts.Suppressions = sc.app.reportedSuppressions()
+	}
+	if opts.DefinitionPositions != nil {
+		maps.Copy(opts.DefinitionPositions, sc.app.definitionPositions)
+	}
+	if opts.DefinitionIndex != nil {
+		sc.app.fillDefinitionIndex(opts.DefinitionIndex, swspec)
+	}
+	if opts.SourceMap != nil {
+		maps.Copy(opts.SourceMap, positions)
+	}
+	if opts.OutputSetSpecs != nil {
+		maps.Copy(opts.OutputSetSpecs, setSpecs)
+	}
+
+	return swspec, nil
 }
 
 func newScanCtx(opts *Options) (*scanCtx, error) {
-	cfg := &packages.Config{
-		Dir:   opts.WorkDir,
-		Mode:  pkgLoadMode,
-		Tests: false,
+	return newScanCtxFor(context.Background(), opts, nil)
+}
+
+// newScanCtxFor loads the packages of the options, unless they are preloaded, and indexes them.
+func newScanCtxFor(ctx context.Context, opts *Options, preloaded []*packages.Package) (*scanCtx, error) {
+	if err := checkDefinitionIndex(opts.UseDefinitionIndex); err != nil {
+		return nil, err
 	}
-	if opts.BuildTags != "" {
-		cfg.BuildFlags = []string{"-tags", opts.BuildTags}
+	if err := checkMergeStrategy(opts.MergeStrategy); err != nil {
+		return nil, err
+	}
+	if err := checkCompat(opts.Compat); err != nil {
+		return nil, err
+	}
+	if err := checkTrailingSlash(opts.TrailingSlash); err != nil {
+		return nil, err
+	}
+	if err := checkDefaultResponse(opts.DefaultResponse); err != nil {
+		return nil, fmt.Errorf("invalid default response: %w", err)
+	}
+	if err := checkHoistParameters(opts.HoistParameters); err != nil {
+		return nil, err
+	}
+	rules, ruleSeverities, err := compileRules(opts.Rules)
+	if err != nil {
+		return nil, err
+	}
+	namer, err := newDefinitionNamer(opts)
+	if err != nil {
+		return nil, fmt.Errorf("invalid definition naming: %w", err)
+	}
+	genericNamer, err := newGenericNamer(opts.GenericNameTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid generic name template: %w", err)
+	}
+	var secrets []secretPattern
+	if opts.CheckSecrets || opts.FailOnSecrets {
+		if secrets, err = compileSecretPatterns(opts.SecretPatterns); err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		pkgs, docPkgs []*packages.Package
+		forced        []forceIncludeDir
+	)
+	switch {
+	case preloaded == nil:
+		if pkgs, docPkgs, forced, err = loadPackages(ctx, opts); err != nil {
+			return nil, err
+		}
+	case opts.IncludeTestScope:
+		pkgs = preferTestVariants(preloaded)
+	default:
+		pkgs = preloaded
 	}
 
-	pkgs, err := packages.Load(cfg, opts.Packages...)
+	workDir, err := filepath.Abs(opts.WorkDir)
 	if err != nil {
 		return nil, err
 	}
 
+	progress := newProgressReporter(opts.OnProgress)
 	app, err := newTypeIndex(pkgs,
 		withExcludeDeps(opts.ExcludeDeps),
 		withIncludeTags(sliceToSet(opts.IncludeTags)),
@@ -109,18 +658,231 @@ func newScanCtx(opts *Options) (*scanCtx, error) {
 		withXNullableForPointers(opts.SetXNullableForPointers),
 		withRefAliases(opts.RefAliases),
 		withTransparentAliases(opts.TransparentAliases),
+		withRequiredFromPointers(opts.RequiredFromPointers, opts.RequiredFromPointersPackages),
+		withTestScope(opts.IncludeTestScope),
+		withDeclarationOrder(opts.DeclarationOrder),
+		withForceIncludeDirs(forced),
+		withDocumentationPackages(docPkgs),
+		withProgress(progress, countPackages(pkgs, opts.ExcludeDeps)),
+		withDefinitionIndex(opts.UseDefinitionIndex),
+		withSourceMap(opts.SourceMap != nil || len(rules) > 0 || len(secrets) > 0 || opts.ValidateExamples),
+		withDefaultSkips(opts.DefaultSkips, workDir, opts.AlsoScan),
+		withStrictJSONNames(opts.StrictJSONNames),
+		withMaxSchemaDepth(opts.MaxSchemaDepth),
+		withFormats(opts.CustomFormats, opts.StrictFormats),
+		withStatusCodeCheck(opts.CheckStatusCodes),
+		withStrictParameters(opts.StrictParameters),
+		withSeverities(ruleSeverities),
+		withRequireAllIncludeTags(opts.RequireAllIncludeTags),
+		withDefinitionNamer(namer),
+		withSetTypes(opts.SetTypes),
+		withTypeMappings(opts.TypeMappings),
+		withGenericNamer(genericNamer),
+		withSuppressions(opts.NoSuppressions, time.Now()),
+		withContentNegotiators(opts.ContentNegotiators),
+		withRouterDiscovery(opts.RouterDiscovery, opts.IncludeUndocumented),
+		withLogger(opts.Logger),
+		withFailFast(opts.FailFast),
+		withTrailingSlash(opts.TrailingSlash),
+		withConcurrency(opts.Concurrency),
+		withExplainPackages(opts.ExplainPackages),
+		withLegacyAnnotations(slices.Contains(opts.Compat, CompatLegacyGoSwagger)),
 	)
 	if err != nil {
+		progress.close()
 		return nil, err
 	}
 
 	return &scanCtx{
-		pkgs: pkgs,
-		app:  app,
-		opts: opts,
+		pkgs:     pkgs,
+		app:      app,
+		progress: progress,
+		rules:    rules,
+		secrets:  secrets,
+		context:  ctx,
+		opts:     opts,
 	}, nil
 }
 
+// loadPackages loads the packages of the options, with those of the force include directories, and the
+// documentation packages of Options.ExtraBuildTags.
+func loadPackages(ctx context.Context, opts *Options) (pkgs, docPkgs []*packages.Package, forced []forceIncludeDir, err error) {
+	cfg, patterns, forced, err := loadConfig(opts, pkgLoadMode)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	cfg.Context = ctx
+
+	pkgs, err = loadWith(opts, cfg, patterns...)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		// the go command is killed, failing with its own error
+		return nil, nil, nil, ctxErr
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if cfg.Tests {
+		pkgs = preferTestVariants(pkgs)
+	}
+
+	if opts.ExtraBuildTags != "" {
+		docCfg := *cfg
+		docCfg.BuildFlags = []string{"-tags", joinBuildTags(opts.BuildTags, opts.ExtraBuildTags)}
+		docPkgs, err = loadWith(opts, &docCfg, patterns...)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, nil, nil, ctxErr
+		}
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if docCfg.Tests {
+			docPkgs = preferTestVariants(docPkgs)
+		}
+	}
+	return pkgs, docPkgs, forced, nil
+}
+
+// loadConfig returns the configuration loading the packages of the options with a mode, and the patterns
+// loaded, with those of the force include directories.
+func loadConfig(opts *Options, mode packages.LoadMode) (*packages.Config, []string, []forceIncludeDir, error) {
+	if err := checkEnv(opts.Env); err != nil {
+		return nil, nil, nil, err
+	}
+	cfg := &packages.Config{
+		Dir:     opts.WorkDir,
+		Mode:    mode,
+		Tests:   opts.IncludeTestScope,
+		Overlay: opts.Overlay,
+		Env:     loadEnv(opts),
+	}
+	if opts.BuildTags != "" {
+		cfg.BuildFlags = []string{"-tags", opts.BuildTags}
+	}
+
+	forced, err := resolveForceIncludeDirs(opts.WorkDir, opts.ForceIncludeDirs)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	patterns := opts.Packages
+	if len(forced) > 0 {
+		if len(patterns) == 0 {
+			patterns = []string{"."}
+		}
+		patterns = slices.Clone(patterns)
+		for _, dir := range opts.ForceIncludeDirs {
+			patterns = append(patterns, forceIncludePattern(dir.Dir))
+		}
+	}
+	return cfg, patterns, forced, nil
+}
+
+// countPackages returns the number of distinct packages classified from the loaded ones.
+func countPackages(pkgs []*packages.Package, excludeDeps bool) int {
+	seen := make(map[string]bool)
+	for _, pkg := range pkgs {
+		seen[pkg.PkgPath] = true
+	}
+	roots := maps.Clone(seen)
+	packages.Visit(pkgs, func(pkg *packages.Package) bool {
+		if !roots[pkg.PkgPath] && !followsImport(excludeDeps, pkg) {
+			return false
+		}
+		seen[pkg.PkgPath] = true
+		return true
+	}, nil)
+	return len(seen)
+}
+
+// joinBuildTags joins comma-separated lists of build tags.
+func joinBuildTags(lists ...string) string {
+	var tags []string
+	for _, list := range lists {
+		for tag := range strings.SplitSeq(list, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return strings.Join(tags, ",")
+}
+
+// preferTestVariants orders loaded packages so that the test variant of a package, which includes
+// its _test.go files, is indexed instead of the package itself. Generated test main packages are dropped.
+func preferTestVariants(pkgs []*packages.Package) []*packages.Package {
+	result := make([]*packages.Package, 0, len(pkgs))
+	for _, pkg := range pkgs {
+		if strings.HasSuffix(pkg.PkgPath, ".test") {
+			continue
+		}
+		if strings.Contains(pkg.ID, " [") {
+			result = append([]*packages.Package{pkg}, result...)
+			continue
+		}
+		result = append(result, pkg)
+	}
+
+	return result
+}
+
+// forceIncludeDir is a ForceIncludeDir resolved to an absolute path.
+type forceIncludeDir struct {
+	dir         string
+	includeTags map[string]bool
+	excludeTags map[string]bool
+}
+
+func resolveForceIncludeDirs(workDir string, dirs []ForceIncludeDir) ([]forceIncludeDir, error) {
+	if len(dirs) == 0 {
+		return nil, nil
+	}
+	base, err := filepath.Abs(workDir)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]forceIncludeDir, 0, len(dirs))
+	for _, dir := range dirs {
+		if dir.Dir == "" {
+			return nil, errors.New("force include directory must not be empty")
+		}
+		abs := dir.Dir
+		if !filepath.IsAbs(abs) {
+			abs = filepath.Join(base, abs)
+		}
+		result = append(result, forceIncludeDir{
+			dir:         filepath.Clean(abs),
+			includeTags: sliceToSet(dir.IncludeTags),
+			excludeTags: sliceToSet(dir.ExcludeTags),
+		})
+	}
+
+	return result, nil
+}
+
+// forceIncludePattern returns the package pattern loading all the packages below dir.
+func forceIncludePattern(dir string) string {
+	pattern := filepath.ToSlash(filepath.Clean(dir))
+	if !filepath.IsAbs(dir) && !strings.HasPrefix(pattern, ".") {
+		pattern = "./" + pattern
+	}
+
+	return strings.TrimSuffix(pattern, "/") + "/..."
+}
+
+// contains tells if the package is located in the directory, or below it.
+func (f forceIncludeDir) contains(pkg *packages.Package) bool {
+	dir, ok := packageDir(pkg)
+	if !ok {
+		return false
+	}
+	rel, err := filepath.Rel(f.dir, dir)
+	if err != nil {
+		return false
+	}
+
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
 type entityDecl struct {
 	Comments               *ast.CommentGroup
 	Type                   *types.Named
@@ -132,6 +894,8 @@ type entityDecl struct {
 	hasModelAnnotation     bool
 	hasResponseAnnotation  bool
 	hasParameterAnnotation bool
+	namer                  *definitionNamer // names the definition without a name in the annotation, see Options.DefinitionNameTemplate
+	instanceName           string           // names the definition of an instantiation of a generic type, see Options.GenericNameTemplate
 }
 
 // Obj returns the type name for the declaration defining the named type or alias t.
@@ -158,27 +922,37 @@ func (d *entityDecl) ObjType() types.Type {
 }
 
 func (d *entityDecl) Names() (name, goName string) {
+	if d.instanceName != "" {
+		return d.instanceName, d.instanceName
+	}
 	goName = d.Ident.Name
-	name = goName
-	if d.Comments == nil {
+	if name = d.annotatedName(); name != "" {
 		return name, goName
 	}
+	if d.namer != nil {
+		return d.namer.name(d), goName
+	}
+	return goName, goName
+}
 
-DECLS:
+// annotatedName returns the name given by the swagger:model annotation, if any.
+func (d *entityDecl) annotatedName() string {
+	if d.Comments == nil {
+		return ""
+	}
 	for _, cmt := range d.Comments.List {
-		for ln := range strings.SplitSeq(cmt.Text, "\n") {
+		for ln := range commentLines(cmt.Text) {
 			matches := rxModelOverride.FindStringSubmatch(ln)
-			if len(matches) > 0 {
-				d.hasModelAnnotation = true
-			}
 			if len(matches) > 1 && len(matches[1]) > 0 {
-				name = matches[1]
-				break DECLS
+				return matches[1]
 			}
 		}
 	}
+	return ""
+}
 
-	return name, goName
+func (d *entityDecl) namedByAnnotation() bool {
+	return d.annotatedName() != ""
 }
 
 func (d *entityDecl) ResponseNames() (name, goName string) {
@@ -190,11 +964,8 @@ func (d *entityDecl) ResponseNames() (name, goName string) {
 
 DECLS:
 	for _, cmt := range d.Comments.List {
-		for ln := range strings.SplitSeq(cmt.Text, "\n") {
+		for ln := range commentLines(cmt.Text) {
 			matches := rxResponseOverride.FindStringSubmatch(ln)
-			if len(matches) > 0 {
-				d.hasResponseAnnotation = true
-			}
 			if len(matches) > 1 && len(matches[1]) > 0 {
 				name = matches[1]
 				break DECLS
@@ -210,7 +981,7 @@ func (d *entityDecl) OperationIDs() (result []string) {
 	}
 
 	for _, cmt := range d.Comments.List {
-		for ln := range strings.SplitSeq(cmt.Text, "\n") {
+		for ln := range commentLines(cmt.Text) {
 			matches := rxParametersOverride.FindStringSubmatch(ln)
 			if len(matches) > 0 {
 				d.hasParameterAnnotation = true
@@ -228,6 +999,22 @@ func (d *entityDecl) OperationIDs() (result []string) {
 	return result
 }
 
+// Scope returns the scope modifier of the declaration annotation, e.g. "test" for "swagger:model Foo scope:test".
+func (d *entityDecl) Scope() string {
+	if d.Comments == nil {
+		return ""
+	}
+	for _, cmt := range d.Comments.List {
+		for ln := range commentLines(cmt.Text) {
+			matches := rxDeclScope.FindStringSubmatch(ln)
+			if len(matches) > 1 {
+				return matches[1]
+			}
+		}
+	}
+	return ""
+}
+
 func (d *entityDecl) HasModelAnnotation() bool {
 	if d.hasModelAnnotation {
 		return true
@@ -236,7 +1023,7 @@ func (d *entityDecl) HasModelAnnotation() bool {
 		return false
 	}
 	for _, cmt := range d.Comments.List {
-		for ln := range strings.SplitSeq(cmt.Text, "\n") {
+		for ln := range commentLines(cmt.Text) {
 			matches := rxModelOverride.FindStringSubmatch(ln)
 			if len(matches) > 0 {
 				d.hasModelAnnotation = true
@@ -255,7 +1042,7 @@ func (d *entityDecl) HasResponseAnnotation() bool {
 		return false
 	}
 	for _, cmt := range d.Comments.List {
-		for ln := range strings.SplitSeq(cmt.Text, "\n") {
+		for ln := range commentLines(cmt.Text) {
 			matches := rxResponseOverride.FindStringSubmatch(ln)
 			if len(matches) > 0 {
 				d.hasResponseAnnotation = true
@@ -274,7 +1061,7 @@ func (d *entityDecl) HasParameterAnnotation() bool {
 		return false
 	}
 	for _, cmt := range d.Comments.List {
-		for ln := range strings.SplitSeq(cmt.Text, "\n") {
+		for ln := range commentLines(cmt.Text) {
 			matches := rxParametersOverride.FindStringSubmatch(ln)
 			if len(matches) > 0 {
 				d.hasParameterAnnotation = true
@@ -286,7 +1073,19 @@ func (d *entityDecl) HasParameterAnnotation() bool {
 }
 
 func (s *scanCtx) FindDecl(pkgPath, name string) (*entityDecl, bool) {
-	if pkg, ok := s.app.AllPackages[pkgPath]; ok {
+	decl, found := findDeclInPackage(s.app.AllPackages[pkgPath], name)
+	if !found {
+		// declarations in documentation-only files
+		decl, found = findDeclInPackage(s.app.docPackages[pkgPath], name)
+	}
+	if found {
+		decl.namer = s.app.definitionNamer
+	}
+	return decl, found
+}
+
+func findDeclInPackage(pkg *packages.Package, name string) (*entityDecl, bool) {
+	if pkg != nil {
 		for _, file := range pkg.Syntax {
 			for _, d := range file.Decls {
 				gd, ok := d.(*ast.GenDecl)
@@ -352,6 +1151,21 @@ func (s *scanCtx) FindModel(pkgPath, name string) (*entityDecl, bool) {
 	return nil, false
 }
 
+// FindModelByName returns the declaration of the model annotated with the given definition name.
+func (s *scanCtx) FindModelByName(name string) (*entityDecl, bool) {
+	if s.app.forked {
+		s.app.orderDependent = true
+	}
+	for _, models := range []map[*ast.Ident]*entityDecl{s.app.Models, s.app.ExtraModels} {
+		for _, cand := range sortedDecls(models) {
+			if nm, _ := cand.Names(); nm == name {
+				return cand, true
+			}
+		}
+	}
+	return nil, false
+}
+
 func (s *scanCtx) PkgForPath(pkgPath string) (*packages.Package, bool) {
 	v, ok := s.app.AllPackages[pkgPath]
 	return v, ok
@@ -414,7 +1228,7 @@ func (s *scanCtx) FindComments(pkg *packages.Package, name string) (*ast.Comment
 	return nil, false
 }
 
-func (s *scanCtx) FindEnumValues(pkg *packages.Package, enumName string) (list []any, descList []string, _ bool) {
+func (s *scanCtx) FindEnumValues(pkg *packages.Package, enumName string) (list []any, descList, nameList []string, _ bool) {
 	for _, f := range pkg.Syntax {
 		for _, d := range f.Decls {
 			gd, ok := d.(*ast.GenDecl)
@@ -434,11 +1248,12 @@ func (s *scanCtx) FindEnumValues(pkg *packages.Package, enumName string) (list [
 
 				list = append(list, literalValue)
 				descList = append(descList, description)
+				nameList = append(nameList, spec.(*ast.ValueSpec).Names[0].Name)
 			}
 		}
 	}
 
-	return list, descList, true
+	return list, descList, nameList, true
 }
 
 func (s *scanCtx) findEnumValue(spec ast.Spec, enumName string) (literalValue any, description string) {
@@ -489,7 +1304,7 @@ func (s *scanCtx) findEnumValue(spec ast.Spec, enumName string) (literalValue an
 
 		for i, doc := range vs.Doc.List {
 			if doc.Text != "" {
-				text := strings.TrimPrefix(doc.Text, "//")
+				text := strings.TrimSuffix(strings.TrimPrefix(doc.Text, "//"), "\r")
 				desc.WriteString(text)
 				if i < docListLen-1 {
 					desc.WriteString(" ")
@@ -511,6 +1326,18 @@ func withExcludeDeps(excluded bool) typeIndexOption {
 	}
 }
 
+func withExplainPackages(paths []string) typeIndexOption {
+	return func(a *typeIndex) {
+		a.explainPkgs = paths
+	}
+}
+
+func withLegacyAnnotations(enabled bool) typeIndexOption {
+	return func(a *typeIndex) {
+		a.legacyAnnotations = enabled
+	}
+}
+
 func withIncludeTags(included map[string]bool) typeIndexOption {
 	return func(a *typeIndex) {
 		a.includeTags = included
@@ -553,11 +1380,152 @@ func withTransparentAliases(enabled bool) typeIndexOption {
 	}
 }
 
+func withRequiredFromPointers(enabled bool, pkgGlobs []string) typeIndexOption {
+	return func(a *typeIndex) {
+		a.requiredFromPointers = enabled
+		a.requiredFromPointersPkgs = pkgGlobs
+	}
+}
+
+func withTestScope(included bool) typeIndexOption {
+	return func(a *typeIndex) {
+		a.includeTestScope = included
+	}
+}
+
+func withDeclarationOrder(enabled bool) typeIndexOption {
+	return func(a *typeIndex) {
+		a.declarationOrder = enabled
+	}
+}
+
+func withProgress(progress *progressReporter, total int) typeIndexOption {
+	return func(a *typeIndex) {
+		a.progress = progress
+		a.packagesTotal = total
+	}
+}
+
+func withDocumentationPackages(pkgs []*packages.Package) typeIndexOption {
+	return func(a *typeIndex) {
+		a.docRoots = pkgs
+	}
+}
+
+func withDefaultSkips(enabled bool, workDir string, alsoScan []string) typeIndexOption {
+	return func(a *typeIndex) {
+		a.defaultSkips = enabled
+		a.workDir = workDir
+		a.alsoScan = alsoScan
+	}
+}
+
+func withStrictJSONNames(enabled bool) typeIndexOption {
+	return func(a *typeIndex) {
+		a.strictJSONNames = enabled
+	}
+}
+
+func withMaxSchemaDepth(depth int) typeIndexOption {
+	return func(a *typeIndex) {
+		a.maxSchemaDepth = depth
+	}
+}
+
+func withFormats(custom []string, strict bool) typeIndexOption {
+	return func(a *typeIndex) {
+		a.customFormats = custom
+		a.strictFormats = strict
+	}
+}
+
+func withStatusCodeCheck(enabled bool) typeIndexOption {
+	return func(a *typeIndex) {
+		a.checkStatuses = enabled
+	}
+}
+
+func withStrictParameters(enabled bool) typeIndexOption {
+	return func(a *typeIndex) {
+		a.strictParameters = enabled
+	}
+}
+
+func withLogger(logger func(Diagnostic)) typeIndexOption {
+	return func(a *typeIndex) {
+		a.logger = logger
+	}
+}
+
+func withTrailingSlash(policy string) typeIndexOption {
+	return func(a *typeIndex) {
+		a.trailingSlash = policy
+	}
+}
+
+func withFailFast(enabled bool) typeIndexOption {
+	return func(a *typeIndex) {
+		a.failFast = enabled
+	}
+}
+
+func withSeverities(severities map[string]string) typeIndexOption {
+	return func(a *typeIndex) {
+		a.severities = severities
+	}
+}
+
+func withRequireAllIncludeTags(enabled bool) typeIndexOption {
+	return func(a *typeIndex) {
+		a.requireAllIncludeTags = enabled
+	}
+}
+
+func withDefinitionNamer(namer *definitionNamer) typeIndexOption {
+	return func(a *typeIndex) {
+		a.definitionNamer = namer
+	}
+}
+
+func withSourceMap(enabled bool) typeIndexOption {
+	return func(a *typeIndex) {
+		a.sourceMap = enabled
+	}
+}
+
+func withDefinitionIndex(index *DefinitionIndex) typeIndexOption {
+	return func(a *typeIndex) {
+		if index == nil {
+			return
+		}
+		a.definitionIndex = index
+		a.indexedPackages = make(map[string]bool)
+		for key := range index.Definitions {
+			if dot := strings.LastIndex(key, "."); dot > 0 {
+				a.indexedPackages[key[:dot]] = true
+			}
+		}
+	}
+}
+
+func withForceIncludeDirs(dirs []forceIncludeDir) typeIndexOption {
+	return func(a *typeIndex) {
+		a.forceIncludeDirs = dirs
+	}
+}
+
 func newTypeIndex(pkgs []*packages.Package, opts ...typeIndexOption) (*typeIndex, error) {
 	ac := &typeIndex{
 		AllPackages: make(map[string]*packages.Package),
 		Models:      make(map[*ast.Ident]*entityDecl),
 		ExtraModels: make(map[*ast.Ident]*entityDecl),
+		forcedPkgs:  make(map[string]*forceIncludeDir),
+		docPackages: make(map[string]*packages.Package),
+
+		definitionPositions: make(map[string]token.Position),
+		definitionTypes:     make(map[string]string),
+		discriminatedBases:  make(map[string]bool),
+		subtypes:            make(map[string][]subtypeRef),
 	}
 	for _, apply := range opts {
 		apply(ac)
@@ -576,6 +1544,8 @@ type typeIndex struct {
 	Meta                    []metaSection
 	Routes                  []parsedPathContent
 	Operations              []parsedPathContent
+	Paths                   []parsedPathDoc
+	Tags                    []parsedTagDoc
 	Parameters              []*entityDecl
 	Responses               []*entityDecl
 	excludeDeps             bool
@@ -586,9 +1556,104 @@ type typeIndex struct {
 	setXNullableForPointers bool
 	refAliases              bool
 	transparentAliases      bool
+
+	requiredFromPointers     bool
+	requiredFromPointersPkgs []string
+	includeTestScope         bool
+	outOfScopeResponses      []string
+	declarationOrder         bool
+	forceIncludeDirs         []forceIncludeDir
+	forcedPkgs               map[string]*forceIncludeDir
+	stats                    Stats
+	definitionPositions      map[string]token.Position
+	docRoots                 []*packages.Package
+	docPackages              map[string]*packages.Package
+	progress                 *progressReporter
+	packagesTotal            int
+	packagesDone             int
+	diagnostics              []Diagnostic
+	definitionTypes          map[string]string
+	definitionIndex          *DefinitionIndex
+	indexedPackages          map[string]bool
+	sourceMap                bool
+	defaultSkips             bool
+	workDir                  string
+	alsoScan                 []string
+	strictJSONNames          bool
+	maxSchemaDepth           int
+	customFormats            []string
+	strictFormats            bool
+	checkStatuses            bool
+	strictParameters         bool
+	severities               map[string]string
+	requireAllIncludeTags    bool
+	definitionNamer          *definitionNamer
+	setTypes                 map[string]bool
+	typeMappings             map[string]spec.Schema
+	genericNamer             *genericNamer
+	instances                map[string]*entityDecl // the declarations of the instantiations of generic types, by type
+	suppressions             []*Suppression
+	suppressionsDisabled     bool
+	today                    string // the date the suppressions expire against, formatted as 2006-01-02
+	contentNegotiators       []ContentNegotiator
+	negotiated               map[string]*negotiatedMediaTypes // the media types of the content negotiators, by package path
+	routerFlavor             *routerFlavor
+	includeUndocumented      bool                    // see Options.IncludeUndocumented
+	discriminatedBases       map[string]bool         // whether a struct has a swagger:discriminator field, by type
+	subtypes                 map[string][]subtypeRef // the structs embedding a discriminated base, by base type
+	logger                   func(Diagnostic)        // receives the diagnostics, see Options.Logger
+	failFast                 bool
+	trailingSlash            string  // see Options.TrailingSlash
+	annotationErrors         []error // the mistakes of the annotations of the files, see Options.FailFast
+
+	concurrency    int                          // the goroutines classifying the files and building the schemas, see Options.Concurrency
+	classified     map[*ast.File]classifiedFile // the files classified concurrently, see classifyFiles
+	forked         bool                         // records the diagnostics in the journal, see fork
+	journal        []journaledDiagnostic
+	orderDependent bool        // a fork read the models an earlier declaration may add, see FindModelByName
+	crossPackage   bool        // a fork read packages its declaration doesn't depend on, see polymorphicBase and resolveTypeRef
+	shared         *sync.Mutex // guards the caches the forks fill, see lock
+	models         *modelCache // the schemas of the models cached in Options.CacheDir, if any
+
+	explainPkgs       []string // the packages whose decisions are recorded, see Options.ExplainPackages
+	legacyAnnotations bool     // rewrites the legacy annotations before classifying them, see Options.Compat
+}
+
+// forceIncludeDirFor returns the force include directory holding the package, or nil.
+func (a *typeIndex) forceIncludeDirFor(pkg *packages.Package) *forceIncludeDir {
+	for i := range a.forceIncludeDirs {
+		if a.forceIncludeDirs[i].contains(pkg) {
+			return &a.forceIncludeDirs[i]
+		}
+	}
+	return nil
+}
+
+func (a *typeIndex) countAnnotation(pkg *packages.Package) {
+	a.stats.Annotations++
+	if a.forcedPkgs[pkg.PkgPath] != nil {
+		a.stats.ForcedAnnotations++
+	}
+}
+
+// requiredFromPointersFor tells if the required-from-pointers convention applies to structs declared in pkgPath.
+func (a *typeIndex) requiredFromPointersFor(pkgPath string) bool {
+	if !a.requiredFromPointers {
+		return false
+	}
+	if len(a.requiredFromPointersPkgs) == 0 {
+		return true
+	}
+	for _, glob := range a.requiredFromPointersPkgs {
+		if matchPackageGlob(glob, pkgPath) {
+			return true
+		}
+	}
+	return false
 }
 
 func (a *typeIndex) build(pkgs []*packages.Package) error {
+	a.classifyFiles(pkgs)
 	for _, pkg := range pkgs {
 		if _, known := a.AllPackages[pkg.PkgPath]; known {
 			continue
@@ -602,23 +1667,142 @@ func (a *typeIndex) build(pkgs []*packages.Package) error {
 		}
 	}
 
+	visited := make(map[string]bool)
+	for _, pkg := range a.docRoots {
+		if err := a.processDocumentationPackage(pkg, visited); err != nil {
+			return err
+		}
+	}
+
+	a.discoverRoutes()
+	a.collectSubtypes()
+	if err := a.checkModelAnnotations(); err != nil {
+		return err
+	}
+	a.explainPackages(pkgs)
+	return nil
+}
+
+// processDocumentationPackage classifies the files of a package loaded with ExtraBuildTags
+// which are not part of the package loaded with BuildTags, then walks its imports.
+func (a *typeIndex) processDocumentationPackage(pkg *packages.Package, visited map[string]bool) error {
+	if visited[pkg.PkgPath] {
+		return nil
+	}
+	visited[pkg.PkgPath] = true
+
+	if runtime, known := a.AllPackages[pkg.PkgPath]; known {
+		if _, ok := a.acceptsPackage(runtime); ok {
+			compiled := make(map[string]bool, len(runtime.Syntax))
+			for _, file := range runtime.Syntax {
+				compiled[runtime.Fset.Position(file.Pos()).Filename] = true
+			}
+			var files []*ast.File
+			for _, file := range pkg.Syntax {
+				if !compiled[pkg.Fset.Position(file.Pos()).Filename] {
+					files = append(files, file)
+				}
+			}
+			if len(files) > 0 {
+				debugLogf("package %s has %d documentation-only files", pkg.Name, len(files))
+				a.docPackages[pkg.PkgPath] = pkg
+				if err := a.processFiles(pkg, files); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for _, imp := range pkg.Imports {
+		if !followsImport(a.excludeDeps, imp) {
+			continue
+		}
+		if err := a.processDocumentationPackage(imp, visited); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// acceptsPackage tells if a package is classified, and returns its force include directory, if any.
+func (a *typeIndex) acceptsPackage(pkg *packages.Package) (*forceIncludeDir, bool) {
+	forced := a.forceIncludeDirFor(pkg)
+	if forced != nil {
+		return forced, true
+	}
+	if root, skipped := a.skippedRoot(pkg); skipped {
+		debugLogf("package %s is skipped in %s", pkg.Name, root)
+		a.recordSkippedRoot(root)
+		return nil, false
+	}
+	return nil, shouldAcceptPkg(pkg.PkgPath, a.includePkgs, a.excludePkgs)
+}
+
 func (a *typeIndex) processPackage(pkg *packages.Package) error {
-	if !shouldAcceptPkg(pkg.PkgPath, a.includePkgs, a.excludePkgs) {
+	a.packagesDone++
+	a.progress.report(PhasePackages, a.packagesDone, a.packagesTotal)
+
+	forced, accepted := a.acceptsPackage(pkg)
+	if !accepted {
 		debugLogf("package %s is ignored due to rules", pkg.Name)
 		return nil
 	}
+	if forced != nil {
+		debugLogf("package %s is force included from %s", pkg.Name, forced.dir)
+		a.forcedPkgs[pkg.PkgPath] = forced
+	}
+	a.stats.Packages++
+
+	return a.processFiles(pkg, pkg.Syntax)
+}
+
+// filterTags applies the tag rules to a route or an operation, recording the decision in the stats when
+// there are rules.
+func (a *typeIndex) filterTags(pp parsedPathContent, includeTags, excludeTags map[string]bool) bool {
+	accepted, reason := shouldAcceptTag(pp.Tags, includeTags, excludeTags, a.requireAllIncludeTags)
+	if len(includeTags) == 0 && len(excludeTags) == 0 {
+		return accepted
+	}
+	if !accepted {
+		debugLogf("operation %s %s is ignored due to tag rules: %s", pp.Method, pp.Path, reason)
+	}
+	a.stats.TagDecisions = append(a.stats.TagDecisions, TagDecision{
+		Method: pp.Method,
+		Path:   pp.Path,
+		ID:     pp.ID,
+		Tags:   pp.Tags,
+		Kept:   accepted,
+		Reason: reason,
+	})
+	return accepted
+}
+
+func (a *typeIndex) processFiles(pkg *packages.Package, files []*ast.File) error {
+	includeTags, excludeTags := a.includeTags, a.excludeTags
+	if forced := a.forcedPkgs[pkg.PkgPath]; forced != nil {
+		includeTags, excludeTags = forced.includeTags, forced.excludeTags
+	}
 
-	for _, file := range pkg.Syntax {
-		n, err := a.detectNodes(file)
+	for _, file := range files {
+		a.stats.Files++
+		a.collectSuppressions(pkg.Fset, file)
+		a.collectNegotiatedMediaTypes(pkg, file)
+		n, err := a.classify(pkg.Fset, file)
 		if err != nil {
-			return err
+			if a.failFast {
+				return joinedErrors(err)[0]
+			}
+			// the rest of the file is still scanned, for its other mistakes
+			a.annotationErrors = append(a.annotationErrors, joinedErrors(err)...)
+		}
+		if isTestFile(pkg, file) {
+			// only test-scoped declarations are picked from test files
+			n &= modelNode | parametersNode | responseNode
 		}
 
 		if n&metaNode != 0 {
-			a.Meta = append(a.Meta, metaSection{Comments: file.Doc})
+			a.Meta = append(a.Meta, metaSection{Comments: file.Doc, fset: pkg.Fset})
+			a.countAnnotation(pkg)
 		}
 
 		if n&operationNode != 0 {
@@ -627,11 +1811,14 @@ func (a *typeIndex) processPackage(pkg *packages.Package) error {
 				if pp.Method == "" {
 					continue // not a valid operation
 				}
-				if !shouldAcceptTag(pp.Tags, a.includeTags, a.excludeTags) {
-					debugLogf("operation %s %s is ignored due to tag rules", pp.Method, pp.Path)
+				pp.Path = a.normalizePath(pp.Path)
+				pp.Pos = pkg.Fset.Position(pp.annotation)
+				if !a.filterTags(pp, includeTags, excludeTags) {
 					continue
 				}
+				pp.handler, pp.file, pp.pkg = handlerFor(file, pp.annotation), file, pkg
 				a.Operations = append(a.Operations, pp)
+				a.countAnnotation(pkg)
 			}
 		}
 
@@ -641,11 +1828,26 @@ func (a *typeIndex) processPackage(pkg *packages.Package) error {
 				if pp.Method == "" {
 					continue // not a valid operation
 				}
-				if !shouldAcceptTag(pp.Tags, a.includeTags, a.excludeTags) {
-					debugLogf("operation %s %s is ignored due to tag rules", pp.Method, pp.Path)
+				pp.Path = a.normalizePath(pp.Path)
+				pp.Pos = pkg.Fset.Position(pp.annotation)
+				if !a.filterTags(pp, includeTags, excludeTags) {
 					continue
 				}
+				pp.handler, pp.file, pp.pkg = handlerFor(file, pp.annotation), file, pkg
 				a.Routes = append(a.Routes, pp)
+				a.countAnnotation(pkg)
+			}
+		}
+
+		if n&pathNode != 0 {
+			if err := a.collectPathDocs(pkg, file); err != nil {
+				return err
+			}
+		}
+
+		if n&tagNode != 0 {
+			if err := a.collectTagDocs(pkg, file); err != nil {
+				return err
 			}
 		}
 
@@ -660,27 +1862,31 @@ func (a *typeIndex) processPackage(pkg *packages.Package) error {
 				for _, stmt := range fd.Body.List {
 					if dstm, ok := stmt.(*ast.DeclStmt); ok {
 						if gd, isGD := dstm.Decl.(*ast.GenDecl); isGD {
-							a.processDecl(pkg, file, n, gd)
+							if err := a.processDecl(pkg, file, n, gd); err != nil {
+								return err
+							}
 						}
 					}
 				}
 			case *ast.GenDecl:
-				a.processDecl(pkg, file, n, fd)
+				if err := a.processDecl(pkg, file, n, fd); err != nil {
+					return err
+				}
 			}
 		}
 	}
 	return nil
 }
 
-func (a *typeIndex) processDecl(pkg *packages.Package, file *ast.File, n node, gd *ast.GenDecl) {
+func (a *typeIndex) processDecl(pkg *packages.Package, file *ast.File, n node, gd *ast.GenDecl) error {
 	for _, sp := range gd.Specs {
 		switch ts := sp.(type) {
 		case *ast.ValueSpec:
 			debugLogf("saw value spec: %v", ts.Names)
-			return
+			return nil
 		case *ast.ImportSpec:
 			debugLogf("saw import spec: %v", ts.Name)
-			return
+			return nil
 		case *ast.TypeSpec:
 			def, ok := pkg.TypesInfo.Defs[ts.Name]
 			if !ok {
@@ -708,15 +1914,34 @@ func (a *typeIndex) processDecl(pkg *packages.Package, file *ast.File, n node, g
 				Spec:     ts,
 				File:     file,
 				Pkg:      pkg,
+				namer:    a.definitionNamer,
+			}
+			include, err := a.acceptsScope(decl)
+			if err != nil {
+				return err
+			}
+			if !include {
+				debugLogf("type %q skipped because it is out of the scope of the scan", decl.Obj().Name())
+				if decl.HasResponseAnnotation() {
+					name, _ := decl.ResponseNames()
+					a.outOfScopeResponses = append(a.outOfScopeResponses, name)
+				}
+				continue
+			}
+			if err := a.checkFormats(decl); err != nil {
+				return err
 			}
 			key := ts.Name
 			switch {
 			case n&modelNode != 0 && decl.HasModelAnnotation():
 				a.Models[key] = decl
+				a.countAnnotation(pkg)
 			case n&parametersNode != 0 && decl.HasParameterAnnotation():
 				a.Parameters = append(a.Parameters, decl)
+				a.countAnnotation(pkg)
 			case n&responseNode != 0 && decl.HasResponseAnnotation():
 				a.Responses = append(a.Responses, decl)
+				a.countAnnotation(pkg)
 			default:
 				debugLogf(
 					"type %q skipped because it is not tagged as a model, a parameter or a response. %s",
@@ -726,14 +1951,35 @@ func (a *typeIndex) processDecl(pkg *packages.Package, file *ast.File, n node, g
 			}
 		}
 	}
+	return nil
 }
 
-func (a *typeIndex) walkImports(pkg *packages.Package) error {
-	if a.excludeDeps {
-		return nil
+// acceptsScope tells if an annotated declaration is in the scope of the scan.
+//
+// Declarations scoped with "scope:test" are only included with IncludeTestScope. Annotated declarations
+// found in _test.go files must be test-scoped: other declarations in test files are ignored.
+func (a *typeIndex) acceptsScope(decl *entityDecl) (bool, error) {
+	scope := decl.Scope()
+	switch scope {
+	case "":
+		return !isTestFile(decl.Pkg, decl.File), nil
+	case "test":
+		return a.includeTestScope, nil
+	default:
+		return false, fmt.Errorf(
+			"%v: unsupported annotation scope %q on %s, expected scope:test",
+			decl.Pkg.Fset.Position(decl.Ident.Pos()), scope, decl.Ident.Name,
+		)
 	}
+}
+
+func isTestFile(pkg *packages.Package, file *ast.File) bool {
+	return strings.HasSuffix(pkg.Fset.Position(file.Pos()).Filename, "_test.go")
+}
+
+func (a *typeIndex) walkImports(pkg *packages.Package) error {
 	for _, v := range pkg.Imports {
-		if _, known := a.AllPackages[v.PkgPath]; known {
+		if _, known := a.AllPackages[v.PkgPath]; known || !followsImport(a.excludeDeps, v) {
 			continue
 		}
 
@@ -748,20 +1994,21 @@ func (a *typeIndex) walkImports(pkg *packages.Package) error {
 	return nil
 }
 
-func (a *typeIndex) detectNodes(file *ast.File) (node, error) {
+// detectNodes classifies the annotations of a file. It returns the mistakes of the annotations which Run rejects,
+// each positioned at its comment, and diagnoses those Run ignores, e.g. the annotations with a misspelled prefix.
+func (a *typeIndex) detectNodes(fset *token.FileSet, file *ast.File) (node, error) {
 	var n node
+	var errs []error
 	for _, comments := range file.Comments {
 		var seenStruct string
 		for _, cline := range comments.List {
 			if cline == nil {
 				continue
 			}
-		}
-
-		for _, cline := range comments.List {
-			if cline == nil {
-				continue
+			if a.legacyAnnotations {
+				a.canonicalizeComment(fset, cline)
 			}
+			a.diagnoseMisspelledPrefixes(fset, cline)
 
 			matches := rxSwaggerAnnotation.FindStringSubmatch(cline.Text)
 			if len(matches) < 2 {
@@ -773,12 +2020,16 @@ func (a *typeIndex) detectNodes(file *ast.File) (node, error) {
 				n |= routeNode
 			case "operation":
 				n |= operationNode
+			case "path":
+				n |= pathNode
+			case "tag":
+				n |= tagNode
 			case "model":
 				n |= modelNode
 				if seenStruct == "" || seenStruct == matches[1] {
 					seenStruct = matches[1]
 				} else {
-					return 0, fmt.Errorf("classifier: already annotated as %s, can't also be %q - %s", seenStruct, matches[1], cline.Text)
+					errs = append(errs, malformedAnnotation(fset.Position(cline.Slash), fmt.Sprintf("classifier: already annotated as %s, can't also be %q - %s", seenStruct, matches[1], cline.Text)))
 				}
 			case "meta":
 				n |= metaNode
@@ -787,25 +2038,37 @@ func (a *typeIndex) detectNodes(file *ast.File) (node, error) {
 				if seenStruct == "" || seenStruct == matches[1] {
 					seenStruct = matches[1]
 				} else {
-					return 0, fmt.Errorf("classifier: already annotated as %s, can't also be %q - %s", seenStruct, matches[1], cline.Text)
+					errs = append(errs, malformedAnnotation(fset.Position(cline.Slash), fmt.Sprintf("classifier: already annotated as %s, can't also be %q - %s", seenStruct, matches[1], cline.Text)))
 				}
 			case "response":
 				n |= responseNode
 				if seenStruct == "" || seenStruct == matches[1] {
 					seenStruct = matches[1]
 				} else {
-					return 0, fmt.Errorf("classifier: already annotated as %s, can't also be %q - %s", seenStruct, matches[1], cline.Text)
+					errs = append(errs, malformedAnnotation(fset.Position(cline.Slash), fmt.Sprintf("classifier: already annotated as %s, can't also be %q - %s", seenStruct, matches[1], cline.Text)))
 				}
-			case "strfmt", "name", "discriminated", "file", "enum", "default", "alias", "type":
+			case "strfmt", "name", "discriminated", "discriminator", "file", "enum", "default", "alias", "type", "example":
 				// TODO: perhaps collect these and pass along to avoid lookups later on
-			case "allOf":
-			case "ignore":
+			case "allOf", "allOfRef":
+			case "ignore", "deprecated":
 			default:
-				return 0, fmt.Errorf("classifier: unknown swagger annotation %q", matches[1])
+				message := fmt.Sprintf("classifier: unknown swagger annotation %q", matches[1])
+				if canonical, legacy := legacyVerbs[matches[1]]; legacy {
+					message += fmt.Sprintf(", the legacy spelling of swagger:%s, see Options.Compat", canonical)
+				} else if suggestion := closestAnnotation(matches[1]); suggestion != "" {
+					message += fmt.Sprintf(", did you mean swagger:%s?", suggestion)
+				}
+				errs = append(errs, malformedAnnotation(fset.Position(cline.Slash), message))
 			}
 		}
+		if pathErrs := checkAnnotatedPaths(fset, comments); len(pathErrs) > 0 {
+			errs = append(errs, pathErrs...)
+		} else {
+			a.diagnosePathAnnotations(fset, comments)
+		}
 	}
-	return n, nil
+	errs = append(errs, malformedValidations(fset, file)...)
+	return n, errors.Join(errs...)
 }
 
 func debugLogf(format string, args ...any) {
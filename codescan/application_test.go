@@ -4,10 +4,14 @@
 package codescan
 
 import (
+	"bytes"
+	"context"
 	"flag"
+	"go/token"
 	"io"
 	"log"
 	"os"
+	"path/filepath"
 	"sort"
 	"testing"
 
@@ -26,11 +30,13 @@ var (
 var (
 	enableSpecOutput bool
 	enableDebug      bool
+	updateGolden     bool
 )
 
 func init() {
 	flag.BoolVar(&enableSpecOutput, "enable-spec-output", false, "enable spec gen test to write output to a file")
 	flag.BoolVar(&enableDebug, "enable-debug", false, "enable debug output in tests")
+	flag.BoolVar(&updateGolden, "update-golden", false, "update the golden files of the tests with their output")
 }
 
 func TestMain(m *testing.M) {
@@ -525,3 +531,295 @@ type namedParams []namedParam
 func (g namedParams) Len() int           { return len(g) }
 func (g namedParams) Swap(i, j int)      { g[i], g[j] = g[j], g[i] }
 func (g namedParams) Less(i, j int) bool { return g[i].Name < g[j].Name }
+
+func TestAppScanner_TestScope(t *testing.T) {
+	const pkg = "github.com/3idey/codescan/fixtures/goparsing/testscope"
+
+	runtime, err := Run(&Options{Packages: []string{pkg}, ScanModels: true})
+	require.NoError(t, err)
+
+	contract, err := Run(&Options{Packages: []string{pkg}, ScanModels: true, IncludeTestScope: true})
+	require.NoError(t, err)
+
+	assert.Contains(t, runtime.Responses, "listUsersResponse")
+	assert.NotContains(t, runtime.Responses, "errorResponse")
+	assert.NotContains(t, runtime.Responses, "pagedUsersResponse")
+	assert.Nil(t, runtime.Paths.Paths["/users"].Get.Responses.Default, "out of scope responses should not dangle")
+
+	assert.Contains(t, contract.Responses, "errorResponse")
+	assert.Contains(t, contract.Responses, "pagedUsersResponse")
+	assert.NotContains(t, contract.Responses, "notScopedResponse")
+	assert.NotContains(t, contract.Paths.Paths, "/ignored")
+
+	get := contract.Paths.Paths["/users"].Get
+	require.NotNil(t, get)
+	require.Len(t, get.Parameters, 1)
+	assert.Equal(t, "limit", get.Parameters[0].Name)
+
+	// both specs only differ by the test-scoped items
+	delete(contract.Responses, "errorResponse")
+	delete(contract.Responses, "pagedUsersResponse")
+	contract.Paths.Paths["/users"].Get.Parameters = nil
+	contract.Paths.Paths["/users"].Get.Responses.Default = nil
+	assert.Equal(t, runtime, contract)
+}
+
+func TestAppScanner_ScopeModifier(t *testing.T) {
+	decl := &entityDecl{Comments: ascg("swagger:model Foo scope:staging")}
+	assert.Equal(t, "staging", decl.Scope())
+	decl = &entityDecl{Comments: ascg("swagger:parameters listUsers createUser scope:test")}
+	assert.Equal(t, "test", decl.Scope())
+	assert.Equal(t, []string{"listUsers", "createUser"}, decl.OperationIDs())
+	decl = &entityDecl{Comments: ascg("swagger:model scope:test")}
+	assert.True(t, decl.HasModelAnnotation())
+}
+
+func TestAppScanner_ForceIncludeDirs(t *testing.T) {
+	const (
+		api = "github.com/3idey/codescan/fixtures/goparsing/forceinclude/api"
+		gen = "github.com/3idey/codescan/fixtures/goparsing/forceinclude/gen/..."
+	)
+
+	t.Run("should ignore excluded generated packages", func(t *testing.T) {
+		doc, err := Run(&Options{Packages: []string{api, gen}, Exclude: []string{gen}, ExcludeDeps: true})
+		require.NoError(t, err)
+
+		assert.Contains(t, doc.Paths.Paths, "/status")
+		assert.NotContains(t, doc.Paths.Paths, "/users")
+	})
+
+	t.Run("should classify force included directories", func(t *testing.T) {
+		var stats Stats
+		doc, err := Run(&Options{
+			Packages:    []string{api},
+			Exclude:     []string{gen},
+			ExcludeDeps: true,
+			ExcludeTags: []string{"users"},
+			ForceIncludeDirs: []ForceIncludeDir{
+				{Dir: "../fixtures/goparsing/forceinclude/gen", ExcludeTags: []string{"internal"}},
+			},
+			Stats: &stats,
+		})
+		require.NoError(t, err)
+
+		assert.Contains(t, doc.Paths.Paths, "/status")
+		require.Contains(t, doc.Paths.Paths, "/users")
+		assert.NotContains(t, doc.Paths.Paths, "/users/reindex")
+		assert.Contains(t, doc.Responses, "listUsersResponse")
+		assert.Contains(t, doc.Definitions, "User")
+
+		assert.Equal(t, 2, stats.Packages)
+		assert.Equal(t, 5, stats.Annotations)
+		assert.Equal(t, 3, stats.ForcedAnnotations)
+	})
+
+	t.Run("should refuse an empty directory", func(t *testing.T) {
+		_, err := Run(&Options{Packages: []string{api}, ForceIncludeDirs: []ForceIncludeDir{{}}})
+		require.Error(t, err)
+	})
+}
+
+func TestForceIncludePattern(t *testing.T) {
+	assert.Equal(t, "./gen/http/...", forceIncludePattern("gen/http"))
+	assert.Equal(t, "./gen/http/...", forceIncludePattern("./gen/http/"))
+	assert.Equal(t, "../gen/...", forceIncludePattern("../gen"))
+	assert.Equal(t, "./...", forceIncludePattern("."))
+}
+
+func TestAppScanner_ExtraBuildTags(t *testing.T) {
+	const pkg = "github.com/3idey/codescan/fixtures/goparsing/doconly"
+
+	t.Run("should ignore documentation-only files by default", func(t *testing.T) {
+		doc, err := Run(&Options{Packages: []string{pkg}})
+		require.NoError(t, err)
+		assert.Empty(t, doc.Responses)
+	})
+
+	t.Run("should classify documentation-only files with ExtraBuildTags", func(t *testing.T) {
+		var stats Stats
+		doc, err := Run(&Options{Packages: []string{pkg}, ExtraBuildTags: "codescan_docs", Stats: &stats})
+		require.NoError(t, err)
+
+		require.Contains(t, doc.Responses, "userResponse")
+		assert.Contains(t, doc.Responses, "notFoundResponse")
+		body := doc.Responses["userResponse"].Schema
+		require.NotNil(t, body)
+		assert.ElementsMatch(t, []string{"id", "name", "documents"}, sortedKeys(body.Properties))
+		assert.Equal(t, "#/definitions/Document", body.Properties["documents"].Items.Schema.Ref.String())
+		assert.Contains(t, doc.Definitions, "Document")
+
+		assert.Equal(t, 1, stats.Packages)
+		assert.Equal(t, 2, stats.Files)
+	})
+}
+
+func TestAppScanner_DefaultSkips(t *testing.T) {
+	workDir := filepath.Join("..", "fixtures", "goparsing", "skipdirs")
+
+	t.Run("should scan third-party directories without default skips", func(t *testing.T) {
+		doc, err := Run(&Options{WorkDir: workDir, Packages: []string{"./..."}, ScanModels: true})
+		require.NoError(t, err)
+		assert.Contains(t, doc.Definitions, "Account")
+		assert.Contains(t, doc.Definitions, "Stray")
+	})
+
+	t.Run("should skip third-party directories with default skips", func(t *testing.T) {
+		var stats Stats
+		doc, err := Run(&Options{WorkDir: workDir, Packages: []string{"./..."}, ScanModels: true, DefaultSkips: true, Stats: &stats})
+		require.NoError(t, err)
+		assert.Contains(t, doc.Definitions, "Account")
+		assert.NotContains(t, doc.Definitions, "Stray")
+		assert.Equal(t, []string{"third_party"}, stats.SkippedDirs)
+	})
+
+	t.Run("should scan skipped directories matched by AlsoScan", func(t *testing.T) {
+		var stats Stats
+		doc, err := Run(&Options{
+			WorkDir: workDir, Packages: []string{"./..."}, ScanModels: true,
+			DefaultSkips: true, AlsoScan: []string{"third_party/..."}, Stats: &stats,
+		})
+		require.NoError(t, err)
+		assert.Contains(t, doc.Definitions, "Stray")
+		assert.Empty(t, stats.SkippedDirs)
+	})
+}
+
+func TestAppScanner_TagRules(t *testing.T) {
+	const pkg = "github.com/3idey/codescan/fixtures/goparsing/tagfilter"
+
+	paths := func(t *testing.T, opts *Options) []string {
+		t.Helper()
+		opts.Packages = []string{pkg}
+		doc, err := Run(opts)
+		require.NoError(t, err)
+		return sortedKeys(doc.Paths.Paths)
+	}
+
+	t.Run("should let excluded tags win over included ones", func(t *testing.T) {
+		var stats Stats
+		assert.Equal(t, []string{"/users", "/users/admins"}, paths(t, &Options{
+			IncludeTags: []string{"users"},
+			ExcludeTags: []string{"internal"},
+			Stats:       &stats,
+		}))
+
+		assert.Equal(t, []TagDecision{
+			{Method: "GET", Path: "/users", ID: "listUsers", Tags: []string{"users"}, Kept: true, Reason: "tag users is included"},
+			{Method: "POST", Path: "/users/reindex", ID: "reindexUsers", Tags: []string{"users", "internal"}, Reason: "tag internal is excluded"},
+			{Method: "GET", Path: "/users/admins", ID: "listAdmins", Tags: []string{"users", "admin"}, Kept: true, Reason: "tag users is included"},
+			{Method: "GET", Path: "/audit", ID: "getAudit", Tags: []string{"admin"}, Reason: "no tag is included"},
+		}, stats.TagDecisions)
+	})
+
+	t.Run("should keep the routes with one of the included tags", func(t *testing.T) {
+		assert.Equal(t, []string{"/audit", "/users", "/users/admins", "/users/reindex"}, paths(t, &Options{
+			IncludeTags: []string{"users", "admin"},
+		}))
+	})
+
+	t.Run("should keep the routes with all the included tags with RequireAllIncludeTags", func(t *testing.T) {
+		assert.Equal(t, []string{"/users/admins"}, paths(t, &Options{
+			IncludeTags:           []string{"users", "admin"},
+			RequireAllIncludeTags: true,
+		}))
+	})
+
+	t.Run("should not record decisions without tag rules", func(t *testing.T) {
+		var stats Stats
+		assert.Len(t, paths(t, &Options{Stats: &stats}), 4)
+		assert.Empty(t, stats.TagDecisions)
+	})
+}
+
+func TestRunWithContext(t *testing.T) {
+	opts := func() *Options {
+		return &Options{
+			Packages:   []string{"github.com/3idey/codescan/fixtures/goparsing/warnings"},
+			ScanModels: true,
+		}
+	}
+	codes := func(diagnostics []Diagnostic) map[string]token.Position {
+		result := make(map[string]token.Position)
+		for _, diagnostic := range diagnostics {
+			result[diagnostic.Code] = diagnostic.Pos
+		}
+		return result
+	}
+
+	t.Run("should return the diagnostics with the spec", func(t *testing.T) {
+		var logged []Diagnostic
+		withLogger := opts()
+		withLogger.Logger = func(diagnostic Diagnostic) { logged = append(logged, diagnostic) }
+		result, err := RunWithContext(context.Background(), withLogger)
+		require.NoError(t, err)
+
+		require.NotNil(t, result.Spec)
+		assert.Contains(t, result.Spec.Paths.Paths, "/pets")
+		assert.Equal(t, 1, result.Stats.SkippedFields)
+		assert.Equal(t, result.Diagnostics, logged, "the logger receives the diagnostics")
+
+		found := codes(result.Diagnostics)
+		for code, line := range map[string]int{
+			DiagnosticSkippedField:         26,
+			DiagnosticUnsupportedType:      32,
+			DiagnosticDuplicateOperationID: 12,
+			DiagnosticUnresolvedRef:        4,
+		} {
+			require.Contains(t, found, code)
+			assert.Equal(t, line, found[code].Line, code)
+			assert.Equal(t, "api.go", filepath.Base(found[code].Filename), code)
+		}
+	})
+
+	t.Run("should describe the unresolved refs and the duplicate operationIds", func(t *testing.T) {
+		var diagnostics []Diagnostic
+		withDiagnostics := opts()
+		withDiagnostics.Diagnostics = &diagnostics
+		_, err := RunWithContext(context.Background(), withDiagnostics)
+		require.NoError(t, err)
+
+		var messages []string
+		for _, diagnostic := range diagnostics {
+			if diagnostic.Code == DiagnosticUnresolvedRef || diagnostic.Code == DiagnosticDuplicateOperationID {
+				messages = append(messages, diagnostic.Message)
+			}
+		}
+		require.Len(t, messages, 2, "the diagnostics of the options are filled")
+		assert.Contains(t, messages[0], "the operationId listPets of GET /animals is already used by GET /pets")
+		assert.Equal(t, "operation listPets refers to #/responses/notFoundResponse, which the spec doesn't define", messages[1])
+	})
+
+	t.Run("should log the diagnostics with Run", func(t *testing.T) {
+		var logs bytes.Buffer
+		log.SetOutput(&logs)
+		defer log.SetOutput(io.Discard)
+
+		_, err := Run(opts())
+		require.NoError(t, err)
+		assert.Contains(t, logs.String(), "WARNING: ")
+		assert.Contains(t, logs.String(), "field Updates is skipped")
+
+		logs.Reset()
+		_, err = RunWithContext(context.Background(), opts())
+		require.NoError(t, err)
+		assert.NotContains(t, logs.String(), "field Updates is skipped", "RunWithContext doesn't log the diagnostics")
+	})
+
+	t.Run("should stop once the context is canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := RunWithContext(ctx, opts())
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("should stop building the spec once the context is canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		sc, err := newScanCtxFor(ctx, opts(), nil)
+		require.NoError(t, err)
+		defer sc.progress.close()
+
+		cancel()
+		_, err = newSpecBuilder(nil, sc, true).Build()
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
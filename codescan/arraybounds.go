@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+)
+
+// setArrayLength bounds the items of an array to its length, e.g. [4]T has 4 items, neither fewer nor more.
+func setArrayLength(tgt swaggerTypable, length int64) {
+	minItems, maxItems := length, length
+	switch typable := tgt.(type) {
+	case paramTypable:
+		if typable.param.In == "body" {
+			typable.Schema().MinItems, typable.Schema().MaxItems = &minItems, &maxItems
+			return
+		}
+		typable.param.MinItems, typable.param.MaxItems = &minItems, &maxItems
+	case itemsTypable:
+		typable.items.MinItems, typable.items.MaxItems = &minItems, &maxItems
+	case responseTypable:
+		if typable.in == "body" {
+			typable.Schema().MinItems, typable.Schema().MaxItems = &minItems, &maxItems
+			return
+		}
+		typable.header.MinItems, typable.header.MaxItems = &minItems, &maxItems
+	default:
+		if schema := tgt.Schema(); schema != nil {
+			schema.MinItems, schema.MaxItems = &minItems, &maxItems
+		}
+	}
+}
+
+// itemsCount parses the value of a minItems or maxItems validation: a count, e.g. 10, or a constant of the
+// package of the declaration between braces, e.g. {MaxBatchSize}, or of a package it imports, e.g.
+// {limits.MaxBatchSize}, so that the spec follows the limit the code enforces.
+func itemsCount(value string, pkg *types.Package) (int64, error) {
+	name, isConstant := strings.CutPrefix(value, "{")
+	if !isConstant {
+		return strconv.ParseInt(value, 10, 64)
+	}
+	name = strings.TrimSuffix(name, "}")
+	if pkg == nil {
+		return 0, fmt.Errorf("the constant %s can't be resolved outside of a package", name)
+	}
+
+	scope := pkg.Scope()
+	ident := name
+	if pkgName, constName, qualified := strings.Cut(name, "."); qualified {
+		scope = nil
+		for _, imported := range pkg.Imports() {
+			if imported.Name() == pkgName {
+				scope = imported.Scope()
+				break
+			}
+		}
+		if scope == nil {
+			return 0, fmt.Errorf("the constant %s refers to a package %s doesn't import", name, pkg.Path())
+		}
+		ident = constName
+	}
+
+	obj, isConst := scope.Lookup(ident).(*types.Const)
+	if !isConst {
+		return 0, fmt.Errorf("%s is not a constant of %s", name, pkg.Path())
+	}
+	count, exact := constant.Int64Val(constant.ToInt(obj.Val()))
+	if !exact || count < 0 {
+		return 0, fmt.Errorf("the constant %s is not a count of items: %s", name, obj.Val())
+	}
+	return count, nil
+}
+
+// notAnArrayError is the error of an items validation of a type which isn't an array, e.g. unique on a
+// string, which the sectioned parsers with an ignore function report rather than fail with.
+type notAnArrayError struct {
+	keyword string
+	tpe     string
+}
+
+func (e *notAnArrayError) Error() string {
+	return fmt.Sprintf("%s only applies to arrays, not to a %s", e.keyword, e.tpe)
+}
+
+// notAnArray tells the type which the items validations of a builder don't apply to, e.g. a string: they
+// only apply to arrays. The targets without a type, e.g. a $ref or a body parameter, are not checked.
+func notAnArray(builder validationBuilder) (string, bool) {
+	var tpe string
+	switch validations := builder.(type) {
+	case schemaValidations:
+		if len(validations.current.Type) != 1 {
+			return "", false
+		}
+		tpe = validations.current.Type[0]
+	case paramValidations:
+		tpe = validations.current.Type
+	case itemsValidations:
+		tpe = validations.current.Type
+	case headerValidations:
+		tpe = validations.current.Type
+	}
+	return tpe, tpe != "" && tpe != "array"
+}
+
+// ignoreItemsValidations reports the items validations of a field which isn't an array, e.g. unique on an
+// email, with a DiagnosticMalformedAnnotation diagnostic: the scan leaves them out of the spec.
+func (a *typeIndex) ignoreItemsValidations(fset *token.FileSet, field *ast.Field) func(error) {
+	return func(err error) {
+		a.diagnoseMessage(malformedAnnotation(fset.Position(field.Pos()), fmt.Sprintf("%v, ignored on the field %s", err, fieldName(field))))
+	}
+}
@@ -0,0 +1,127 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"go/types"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/packages"
+)
+
+func TestArrayBounds(t *testing.T) {
+	const pkg = "github.com/3idey/codescan/fixtures/goparsing/arraybounds"
+
+	t.Run("should bound the items of the arrays", func(t *testing.T) {
+		doc, err := Run(&Options{Packages: []string{pkg}, ScanModels: true})
+		require.NoError(t, err)
+
+		batch := doc.Definitions["Batch"]
+		orders := batch.Properties["orders"]
+		require.NotNil(t, orders.MinItems)
+		require.NotNil(t, orders.MaxItems)
+		assert.Equal(t, int64(1), *orders.MinItems)
+		assert.Equal(t, int64(100), *orders.MaxItems, "the value of the constant MaxBatchSize")
+		assert.True(t, orders.UniqueItems)
+
+		tags := batch.Properties["tags"]
+		require.NotNil(t, tags.MaxItems)
+		assert.Equal(t, int64(8), *tags.MaxItems, "the value of the constant of an imported package")
+		require.NotNil(t, tags.Items.Schema.MaxItems)
+		assert.Equal(t, int64(2), *tags.Items.Schema.MaxItems)
+
+		location := batch.Properties["location"]
+		require.NotNil(t, location.MinItems)
+		require.NotNil(t, location.MaxItems)
+		assert.Equal(t, int64(2), *location.MinItems, "the length of the array")
+		assert.Equal(t, int64(2), *location.MaxItems)
+
+		checksum := doc.Definitions["Checksum"]
+		require.NotNil(t, checksum.MaxItems)
+		assert.Equal(t, int64(20), *checksum.MaxItems)
+
+		params := doc.Paths.Paths["/batches"].Post.Parameters
+		require.Len(t, params, 2)
+		require.NotNil(t, params[0].MaxItems)
+		assert.Equal(t, int64(100), *params[0].MaxItems)
+		assert.True(t, params[0].UniqueItems)
+		require.NotNil(t, params[1].MinItems)
+		assert.Equal(t, int64(2), *params[1].MinItems)
+	})
+
+	t.Run("should ignore the items validations of other types", func(t *testing.T) {
+		var diagnostics []Diagnostic
+		doc, err := Run(&Options{Packages: []string{pkg + "/invalid"}, ScanModels: true, Diagnostics: &diagnostics, Logger: func(Diagnostic) {}})
+		require.NoError(t, err)
+
+		label := doc.Definitions["Label"]
+		assert.Nil(t, label.Properties["name"].MaxItems)
+		assert.False(t, label.Properties["email"].UniqueItems)
+		assert.Equal(t, "email", label.Properties["email"].Format)
+
+		var messages []string
+		for _, diagnostic := range diagnostics {
+			assert.Equal(t, DiagnosticMalformedAnnotation, diagnostic.Code)
+			assert.Equal(t, "models.go", filepath.Base(diagnostic.Pos.Filename))
+			messages = append(messages, diagnostic.Message)
+		}
+		assert.ElementsMatch(t, []string{
+			"maxItems only applies to arrays, not to a string, ignored on the field Name",
+			"unique only applies to arrays, not to a string, ignored on the field Email",
+		}, messages)
+	})
+
+	t.Run("should scan the classification models, whose email is unique", func(t *testing.T) {
+		var diagnostics []Diagnostic
+		doc, err := Run(&Options{
+			Packages:    []string{"github.com/3idey/codescan/fixtures/goparsing/classification/models"},
+			ScanModels:  true,
+			Diagnostics: &diagnostics,
+			Logger:      func(Diagnostic) {},
+		})
+		require.NoError(t, err)
+
+		email := doc.Definitions["User"].Properties["login"]
+		assert.Equal(t, "email", email.Format)
+		assert.False(t, email.UniqueItems)
+
+		var ignored []string
+		for _, diagnostic := range diagnostics {
+			if diagnostic.Code == DiagnosticMalformedAnnotation {
+				ignored = append(ignored, diagnostic.Message)
+			}
+		}
+		assert.Contains(t, ignored, "unique only applies to arrays, not to a string, ignored on the field Email")
+	})
+}
+
+func TestItemsCount(t *testing.T) {
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.NeedTypes | packages.NeedImports | packages.NeedDeps},
+		"github.com/3idey/codescan/fixtures/goparsing/arraybounds")
+	require.NoError(t, err)
+	require.Len(t, pkgs, 1)
+	pkg := pkgs[0].Types
+
+	for value, expected := range map[string]int64{"10": 10, "{MaxBatchSize}": 100, "{limits.MaxTags}": 8} {
+		count, err := itemsCount(value, pkg)
+		require.NoError(t, err)
+		assert.Equal(t, expected, count, value)
+	}
+
+	for value, message := range map[string]string{
+		"{MaxLabels}":       "MaxLabels is not a constant of " + pkg.Path(),
+		"{Checksum}":        "Checksum is not a constant",
+		"{strings.MaxTags}": "the constant strings.MaxTags refers to a package",
+	} {
+		_, err := itemsCount(value, pkg)
+		require.Error(t, err, value)
+		assert.Contains(t, err.Error(), message)
+	}
+
+	_, err = itemsCount("{MaxBatchSize}", types.NewPackage("acme", "acme"))
+	require.Error(t, err)
+}
@@ -0,0 +1,249 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+const (
+	xAudience = "x-audience"
+
+	// AudiencePublic is the audience of the operations which don't declare one, unless Options.RequireAudience is set.
+	AudiencePublic = "public"
+)
+
+// setAudienceOp parses the "Audience: internal" line of a route, a list of audiences separated by commas.
+type setAudienceOp struct {
+	tgt *spec.Operation
+}
+
+func (su *setAudienceOp) Matches(line string) bool {
+	return rxAudience.MatchString(line)
+}
+
+func (su *setAudienceOp) Parse(lines []string) error {
+	if len(lines) == 0 || (len(lines) == 1 && len(lines[0]) == 0) {
+		return nil
+	}
+	matches := rxAudience.FindStringSubmatch(lines[0])
+	if len(matches) < 2 {
+		return nil
+	}
+	var audiences []string
+	for audience := range strings.SplitSeq(matches[1], ",") {
+		if audience = strings.TrimSpace(audience); audience != "" {
+			audiences = append(audiences, audience)
+		}
+	}
+	switch len(audiences) {
+	case 0:
+	case 1:
+		su.tgt.AddExtension(xAudience, audiences[0])
+	default:
+		su.tgt.AddExtension(xAudience, audiences)
+	}
+	return nil
+}
+
+// operationAudiences returns the audiences of an operation, declared by an x-audience extension holding
+// one audience or a list of them.
+func operationAudiences(op *spec.Operation) ([]string, bool) {
+	switch value := op.Extensions[xAudience].(type) {
+	case string:
+		return []string{value}, true
+	case []string:
+		return value, true
+	case []any:
+		audiences := make([]string, 0, len(value))
+		for _, item := range value {
+			if audience, ok := item.(string); ok {
+				audiences = append(audiences, audience)
+			}
+		}
+		return audiences, true
+	default:
+		return nil, false
+	}
+}
+
+// FilterAudience removes from a spec the operations of other audiences than the given ones, then the
+// definitions, parameters, responses, security definitions and tags only used by the removed operations.
+// Operations without an x-audience extension are public, unless requireAudience is set, which fails instead.
+func FilterAudience(doc *spec.Swagger, audiences []string, requireAudience bool) error {
+	if doc == nil || doc.Paths == nil || len(audiences) == 0 {
+		return nil
+	}
+
+	_, err := pruneOperations(doc, func(pth, method string, op *spec.Operation) (bool, error) {
+		declared, ok := operationAudiences(op)
+		if !ok {
+			if requireAudience {
+				return false, fmt.Errorf("operation %s (%s %s) has no %s", op.ID, strings.ToUpper(method), pth, xAudience)
+			}
+			declared = []string{AudiencePublic}
+		}
+		return slices.ContainsFunc(declared, func(audience string) bool { return slices.Contains(audiences, audience) }), nil
+	}, false)
+	return err
+}
+
+// operationUses collects the references, security definitions and tags used by operations.
+type operationUses struct {
+	refs     []string
+	security []string
+	tags     []string
+}
+
+func (u *operationUses) add(op *spec.Operation) {
+	u.refs = append(u.refs, paramsRefs(op.Parameters)...)
+	if op.Responses != nil {
+		if op.Responses.Default != nil {
+			u.refs = append(u.refs, responseRefs(op.Responses.Default)...)
+		}
+		for _, code := range sortedKeys(op.Responses.StatusCodeResponses) {
+			resp := op.Responses.StatusCodeResponses[code]
+			u.refs = append(u.refs, responseRefs(&resp)...)
+		}
+	}
+	for _, requirement := range op.Security {
+		for name := range requirement {
+			u.security = append(u.security, name)
+		}
+	}
+	u.tags = append(u.tags, op.Tags...)
+}
+
+func paramsRefs(params []spec.Parameter) []string {
+	var refs []string
+	for _, param := range params {
+		if ref := param.Ref.String(); ref != "" {
+			refs = append(refs, ref)
+		}
+		if param.Schema != nil {
+			refs = append(refs, schemaRefs(param.Schema)...)
+		}
+	}
+	return refs
+}
+
+func responseRefs(resp *spec.Response) []string {
+	var refs []string
+	if ref := resp.Ref.String(); ref != "" {
+		refs = append(refs, ref)
+	}
+	if resp.Schema != nil {
+		refs = append(refs, schemaRefs(resp.Schema)...)
+	}
+	schemas := contentSchemas(resp)
+	for _, mediaType := range sortedKeys(schemas) {
+		refs = append(refs, schemaRefs(schemas[mediaType])...)
+	}
+	return refs
+}
+
+// schemaRefs returns the refs of a schema and of its subschemas, in the order of walkSchema.
+func schemaRefs(sch *spec.Schema) []string {
+	var refs []string
+	appendSchemaRefs(&refs, sch)
+	return refs
+}
+
+// appendSchemaRefs appends the refs of a schema to those of schemaRefs, reading the schema like walkSchema
+// does, without copying its properties nor building their locations.
+func appendSchemaRefs(refs *[]string, sch *spec.Schema) {
+	if ref := sch.Ref.String(); ref != "" {
+		*refs = append(*refs, ref)
+	}
+	for _, name := range sortedKeys(sch.Properties) {
+		prop := sch.Properties[name]
+		appendSchemaRefs(refs, &prop)
+	}
+	for _, name := range sortedKeys(sch.PatternProperties) {
+		prop := sch.PatternProperties[name]
+		appendSchemaRefs(refs, &prop)
+	}
+	if sch.Items != nil {
+		if sch.Items.Schema != nil {
+			appendSchemaRefs(refs, sch.Items.Schema)
+		}
+		for i := range sch.Items.Schemas {
+			appendSchemaRefs(refs, &sch.Items.Schemas[i])
+		}
+	}
+	if sch.AdditionalProperties != nil && sch.AdditionalProperties.Schema != nil {
+		appendSchemaRefs(refs, sch.AdditionalProperties.Schema)
+	}
+	if sch.AdditionalItems != nil && sch.AdditionalItems.Schema != nil {
+		appendSchemaRefs(refs, sch.AdditionalItems.Schema)
+	}
+	for _, schemas := range [][]spec.Schema{sch.AllOf, sch.AnyOf, sch.OneOf} {
+		for i := range schemas {
+			appendSchemaRefs(refs, &schemas[i])
+		}
+	}
+	if sch.Not != nil {
+		appendSchemaRefs(refs, sch.Not)
+	}
+}
+
+// refsClosure follows the local refs of a spec from the given ones, through definitions, parameters
+// and responses.
+func refsClosure(doc *spec.Swagger, refs []string) map[string]bool {
+	return newRefGraph(doc).closure(refs)
+}
+
+// refGraph follows the local refs of a spec, reading the refs of each definition, parameter and response once
+// for the closures of several sets of refs, e.g. those of the tags of StatsByTag.
+type refGraph struct {
+	doc  *spec.Swagger
+	refs map[string][]string // the refs of the definitions, parameters and responses, by their own ref
+}
+
+func newRefGraph(doc *spec.Swagger) *refGraph {
+	return &refGraph{doc: doc, refs: make(map[string][]string)}
+}
+
+// closure follows the local refs of the spec from the given ones, see refsClosure.
+func (g *refGraph) closure(refs []string) map[string]bool {
+	closure := make(map[string]bool)
+	pending := slices.Clone(refs)
+	for len(pending) > 0 {
+		ref := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if closure[ref] {
+			continue
+		}
+		closure[ref] = true
+		pending = append(pending, g.targetRefs(ref)...)
+	}
+	return closure
+}
+
+// targetRefs returns the refs of the definition, parameter or response a ref points to.
+func (g *refGraph) targetRefs(ref string) []string {
+	if refs, cached := g.refs[ref]; cached {
+		return refs
+	}
+	var refs []string
+	if name, ok := strings.CutPrefix(ref, definitionsPrefix); ok {
+		if definition, known := g.doc.Definitions[unescapePointer(name)]; known {
+			refs = schemaRefs(&definition)
+		}
+	} else if name, ok := strings.CutPrefix(ref, parametersPrefix); ok {
+		if param, known := g.doc.Parameters[unescapePointer(name)]; known {
+			refs = paramsRefs([]spec.Parameter{param})
+		}
+	} else if name, ok := strings.CutPrefix(ref, responsesPrefix); ok {
+		if resp, known := g.doc.Responses[unescapePointer(name)]; known {
+			refs = responseRefs(&resp)
+		}
+	}
+	g.refs[ref] = refs
+	return refs
+}
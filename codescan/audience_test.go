@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const audienceFixture = "github.com/3idey/codescan/fixtures/goparsing/audience"
+
+func audienceScan(t *testing.T, opts *Options) *spec.Swagger {
+	t.Helper()
+	opts.Packages = []string{audienceFixture}
+	opts.ScanModels = true
+	opts.InputSpec = &spec.Swagger{SwaggerProps: spec.SwaggerProps{Tags: []spec.Tag{
+		spec.NewTag("pets", "the pets", nil), spec.NewTag("admin", "the administration", nil),
+	}}}
+	doc, err := Run(opts)
+	require.NoError(t, err)
+	return doc
+}
+
+func TestAudience(t *testing.T) {
+	t.Run("should parse the audience of routes", func(t *testing.T) {
+		doc := audienceScan(t, &Options{})
+		assert.Equal(t, "public", doc.Paths.Paths["/pets"].Get.Extensions[xAudience])
+		assert.Equal(t, []string{"internal", "partner"}, doc.Paths.Paths["/pets/{id}/shares"].Post.Extensions[xAudience])
+		assert.Len(t, doc.Paths.Paths, 4)
+	})
+
+	t.Run("should keep the public operations", func(t *testing.T) {
+		doc := audienceScan(t, &Options{Audience: []string{AudiencePublic}})
+		assert.Equal(t, []string{"/pets", "/pets/{id}"}, sortedKeys(doc.Paths.Paths))
+		assert.Equal(t, []string{"Owner", "Pet", "Unused"}, sortedKeys(doc.Definitions))
+		assert.Equal(t, []string{"petsResponse"}, sortedKeys(doc.Responses))
+		assert.Equal(t, []string{"api_key"}, sortedKeys(doc.SecurityDefinitions))
+		require.Len(t, doc.Tags, 1)
+		assert.Equal(t, "pets", doc.Tags[0].Name)
+	})
+
+	t.Run("should keep the operations of several audiences", func(t *testing.T) {
+		doc := audienceScan(t, &Options{Audience: []string{"partner"}})
+		assert.Equal(t, []string{"/pets/{id}/shares"}, sortedKeys(doc.Paths.Paths))
+		assert.Equal(t, []string{"Unused"}, sortedKeys(doc.Definitions))
+		assert.Empty(t, doc.Responses)
+		assert.Empty(t, doc.SecurityDefinitions)
+
+		doc = audienceScan(t, &Options{Audience: []string{"internal"}})
+		assert.Equal(t, []string{"/audit", "/pets/{id}/shares"}, sortedKeys(doc.Paths.Paths))
+		assert.Equal(t, []string{"AuditEntry", "Owner", "Pet", "Unused"}, sortedKeys(doc.Definitions))
+		assert.Equal(t, []string{"admin_key"}, sortedKeys(doc.SecurityDefinitions))
+	})
+
+	t.Run("should require an audience", func(t *testing.T) {
+		_, err := Run(&Options{Packages: []string{audienceFixture}, Audience: []string{AudiencePublic}, RequireAudience: true})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "operation getPet (GET /pets/{id}) has no x-audience")
+	})
+}
@@ -0,0 +1,496 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/3idey/codescan/codescan/specdiff"
+	"github.com/go-openapi/spec"
+)
+
+// baselineVersion is the version of the format of the baselines written by NewBaseline.
+const baselineVersion = 1
+
+// baselineDate is the format of the expiry dates of the suppressions of a baseline.
+const baselineDate = "2006-01-02"
+
+// Baseline is the public surface of a spec, recorded by NewBaseline: the signatures of the operations and
+// the shapes of the definitions. CheckBaseline compares a later spec with it, so that the descriptions,
+// the examples and the Go internals of the API may change freely.
+type Baseline struct {
+	Version int `json:"version"`
+	// Operations are keyed by method and path, e.g. "GET /users/{id}".
+	Operations  map[string]BaselineOperation `json:"operations"`
+	Definitions map[string]*BaselineSchema   `json:"definitions"`
+	// Suppressions accept breaking changes until they expire. NewBaseline records none: they are written
+	// by hand, and kept when the baseline is recorded again.
+	Suppressions []BaselineSuppression `json:"suppressions,omitempty"`
+}
+
+// BaselineOperation is the signature of an operation in a baseline.
+type BaselineOperation struct {
+	ID       string   `json:"id,omitempty"`
+	Consumes []string `json:"consumes,omitempty"`
+	Produces []string `json:"produces,omitempty"`
+	// Parameters are keyed by location and name, e.g. "query.limit".
+	Parameters map[string]BaselineParameter `json:"parameters,omitempty"`
+	// Responses are keyed by status code, or "default".
+	Responses map[string]BaselineResponse `json:"responses,omitempty"`
+}
+
+// BaselineParameter is the signature of a parameter in a baseline. Body parameters have a schema.
+type BaselineParameter struct {
+	Type     string          `json:"type,omitempty"`
+	Format   string          `json:"format,omitempty"`
+	Required bool            `json:"required,omitempty"`
+	Enum     []any           `json:"enum,omitempty"`
+	Items    *BaselineSchema `json:"items,omitempty"`
+	Schema   *BaselineSchema `json:"schema,omitempty"`
+}
+
+// BaselineResponse is the signature of a response in a baseline.
+type BaselineResponse struct {
+	Schema *BaselineSchema `json:"schema,omitempty"`
+}
+
+// BaselineSchema is the shape of a schema in a baseline: the members of an allOf are flattened into its
+// properties, except the definitions it composes.
+type BaselineSchema struct {
+	Ref                  string                     `json:"ref,omitempty"`
+	Type                 string                     `json:"type,omitempty"`
+	Format               string                     `json:"format,omitempty"`
+	Enum                 []any                      `json:"enum,omitempty"`
+	AllOf                []string                   `json:"allOf,omitempty"`
+	Properties           map[string]*BaselineSchema `json:"properties,omitempty"`
+	Required             []string                   `json:"required,omitempty"`
+	Items                *BaselineSchema            `json:"items,omitempty"`
+	AdditionalProperties *BaselineSchema            `json:"additionalProperties,omitempty"`
+}
+
+// BaselineSuppression accepts the breaking changes at a location until the end of the day it expires.
+type BaselineSuppression struct {
+	// Location is the location of a breaking change, or a prefix of locations ending with *, e.g.
+	// "definition User.*".
+	Location string `json:"location"`
+	Reason   string `json:"reason,omitempty"`
+	// Expires is a date formatted as 2006-01-02. A suppression without one never expires.
+	Expires string `json:"expires,omitempty"`
+}
+
+// BaselineBreak is a breaking change of a spec from its baseline, reported by CheckBaseline.
+type BaselineBreak struct {
+	// Location is e.g. "GET /users/{id}", "GET /users/{id} param query.limit",
+	// "GET /users/{id} response 200.name" or "definition User.tags[]".
+	Location string
+	Message  string
+	Kind     specdiff.Kind
+}
+
+func (b BaselineBreak) Error() string {
+	return b.Location + ": " + b.Message
+}
+
+// ParseBaseline decodes a baseline written by NewBaseline, validating its suppressions.
+func ParseBaseline(data []byte) (*Baseline, error) {
+	baseline := new(Baseline)
+	if err := json.Unmarshal(data, baseline); err != nil {
+		return nil, fmt.Errorf("invalid baseline: %w", err)
+	}
+	if baseline.Version > baselineVersion {
+		return nil, fmt.Errorf("unsupported baseline version %d, this version of codescan reads version %d", baseline.Version, baselineVersion)
+	}
+
+	var errs []error
+	for _, suppression := range baseline.Suppressions {
+		if suppression.Location == "" {
+			errs = append(errs, fmt.Errorf("a suppression of the baseline has no location"))
+			continue
+		}
+		if suppression.Expires == "" {
+			continue
+		}
+		if _, err := time.Parse(baselineDate, suppression.Expires); err != nil {
+			errs = append(errs, fmt.Errorf("invalid expiry date %q of the suppression of %s, expected YYYY-MM-DD", suppression.Expires, suppression.Location))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return baseline, nil
+}
+
+// NewBaseline records the public surface of a spec.
+func NewBaseline(doc *spec.Swagger) *Baseline {
+	baseline := &Baseline{
+		Version:     baselineVersion,
+		Operations:  make(map[string]BaselineOperation),
+		Definitions: make(map[string]*BaselineSchema, len(doc.Definitions)),
+	}
+	for name, schema := range doc.Definitions {
+		baseline.Definitions[name] = baselineSchema(&schema)
+	}
+
+	if doc.Paths == nil {
+		return baseline
+	}
+	for pth, pathItem := range doc.Paths.Paths {
+		for method, op := range pathItemOperations(&pathItem) {
+			operation := BaselineOperation{
+				ID:         op.ID,
+				Consumes:   sortedStrings(orDefault(op.Consumes, doc.Consumes)),
+				Produces:   sortedStrings(orDefault(op.Produces, doc.Produces)),
+				Parameters: make(map[string]BaselineParameter),
+			}
+			// the parameters of the operation override those of its path
+			for _, params := range [][]spec.Parameter{pathItem.Parameters, op.Parameters} {
+				for _, param := range params {
+					param = resolveBaselineParameter(doc, param)
+					operation.Parameters[param.In+"."+param.Name] = baselineParameter(&param)
+				}
+			}
+			if op.Responses != nil {
+				operation.Responses = make(map[string]BaselineResponse)
+				if op.Responses.Default != nil {
+					operation.Responses["default"] = baselineResponse(doc, *op.Responses.Default)
+				}
+				for code, resp := range op.Responses.StatusCodeResponses {
+					operation.Responses[strconv.Itoa(code)] = baselineResponse(doc, resp)
+				}
+			}
+			baseline.Operations[strings.ToUpper(method)+" "+pth] = operation
+		}
+	}
+	return baseline
+}
+
+func orDefault(values, defaults []string) []string {
+	if len(values) > 0 {
+		return values
+	}
+	return defaults
+}
+
+func sortedStrings(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	return slices.Compact(sorted)
+}
+
+func resolveBaselineParameter(doc *spec.Swagger, param spec.Parameter) spec.Parameter {
+	if name, ok := strings.CutPrefix(param.Ref.String(), parametersPrefix); ok {
+		if shared, found := doc.Parameters[name]; found {
+			return shared
+		}
+	}
+	return param
+}
+
+func baselineParameter(param *spec.Parameter) BaselineParameter {
+	return BaselineParameter{
+		Type:     param.Type,
+		Format:   param.Format,
+		Required: param.Required,
+		Enum:     param.Enum,
+		Items:    baselineItems(param.Items),
+		Schema:   baselineSchema(param.Schema),
+	}
+}
+
+func baselineItems(items *spec.Items) *BaselineSchema {
+	if items == nil {
+		return nil
+	}
+	return &BaselineSchema{
+		Type:   items.Type,
+		Format: items.Format,
+		Enum:   items.Enum,
+		Items:  baselineItems(items.Items),
+	}
+}
+
+func baselineResponse(doc *spec.Swagger, resp spec.Response) BaselineResponse {
+	if name, ok := strings.CutPrefix(resp.Ref.String(), responsesPrefix); ok {
+		if shared, found := doc.Responses[name]; found {
+			resp = shared
+		}
+	}
+	return BaselineResponse{Schema: baselineSchema(resp.Schema)}
+}
+
+func baselineSchema(schema *spec.Schema) *BaselineSchema {
+	if schema == nil {
+		return nil
+	}
+	shape := new(BaselineSchema)
+	if ref := schema.Ref.String(); ref != "" {
+		shape.Ref = ref
+		return shape
+	}
+	shape.Type = strings.Join(schema.Type, ",")
+	shape.Format = schema.Format
+	shape.Enum = schema.Enum
+	shape.Required = sortedStrings(schema.Required)
+	for name, property := range schema.Properties {
+		if shape.Properties == nil {
+			shape.Properties = make(map[string]*BaselineSchema, len(schema.Properties))
+		}
+		shape.Properties[name] = baselineSchema(&property)
+	}
+	for _, member := range schema.AllOf {
+		flattened := baselineSchema(&member)
+		if flattened.Ref != "" {
+			shape.AllOf = append(shape.AllOf, flattened.Ref)
+			continue
+		}
+		shape.AllOf = append(shape.AllOf, flattened.AllOf...)
+		for name, property := range flattened.Properties {
+			if shape.Properties == nil {
+				shape.Properties = make(map[string]*BaselineSchema)
+			}
+			shape.Properties[name] = property
+		}
+		shape.Required = sortedStrings(append(shape.Required, flattened.Required...))
+		if shape.Type == "" {
+			shape.Type = flattened.Type
+		}
+	}
+	if schema.Items != nil {
+		shape.Items = baselineSchema(schema.Items.Schema)
+	}
+	if schema.AdditionalProperties != nil {
+		shape.AdditionalProperties = baselineSchema(schema.AdditionalProperties.Schema)
+	}
+	return shape
+}
+
+// CheckBaseline reports the breaking changes of a spec from its baseline, on the day of now, classified by the
+// specdiff.DefaultPolicy. The additions are not breaking: new operations, definitions, optional parameters
+// and properties, responses, media types and enum values. The changes accepted by a suppression of the
+// baseline which hasn't expired are skipped.
+//
+// The inline schemas of the parameters are those of requests, and the inline schemas of the responses those of
+// responses, e.g. a property of a response which becomes required doesn't break. The baseline doesn't record
+// which operations use the definitions: since a definition may be used both by requests and responses, a
+// property which is required, or not anymore, breaks the definition.
+func CheckBaseline(baseline *Baseline, doc *spec.Swagger, now time.Time) []BaselineBreak {
+	current := NewBaseline(doc)
+	c := &baselineChecker{policy: specdiff.DefaultPolicy()}
+
+	for _, key := range sortedKeys(baseline.Operations) {
+		previous := baseline.Operations[key]
+		operation, found := current.Operations[key]
+		if !found {
+			c.report(key, specdiff.OperationRemoved, "the operation was removed")
+			continue
+		}
+		c.checkOperation(key, previous, operation)
+	}
+	for _, name := range sortedKeys(baseline.Definitions) {
+		location := "definition " + name
+		schema, found := current.Definitions[name]
+		if !found {
+			c.report(location, specdiff.DefinitionRemoved, "the definition was removed")
+			continue
+		}
+		c.direction = specdiff.Both
+		c.checkSchema(location, baseline.Definitions[name], schema)
+	}
+
+	today := now.Format(baselineDate)
+	breaks := c.breaks[:0]
+	for _, change := range c.breaks {
+		suppression, suppressed := findSuppression(baseline.Suppressions, change.Location)
+		switch {
+		case !suppressed:
+		case suppression.Expires == "" || suppression.Expires >= today:
+			continue
+		default:
+			change.Message += fmt.Sprintf(" (the suppression expired on %s)", suppression.Expires)
+		}
+		breaks = append(breaks, change)
+	}
+	return breaks
+}
+
+func findSuppression(suppressions []BaselineSuppression, location string) (BaselineSuppression, bool) {
+	for _, suppression := range suppressions {
+		prefix, isPrefix := strings.CutSuffix(suppression.Location, "*")
+		if suppression.Location == location || (isPrefix && strings.HasPrefix(location, prefix)) {
+			return suppression, true
+		}
+	}
+	return BaselineSuppression{}, false
+}
+
+type baselineChecker struct {
+	policy specdiff.Policy
+	// direction of the checked elements, e.g. specdiff.Request for the parameters
+	direction specdiff.Direction
+	breaks    []BaselineBreak
+}
+
+// report records a change when it breaks the clients, by the policy.
+func (c *baselineChecker) report(location string, kind specdiff.Kind, format string, args ...any) {
+	if !c.policy.Breaking(specdiff.Change{Kind: kind, Direction: c.direction}) {
+		return
+	}
+	c.breaks = append(c.breaks, BaselineBreak{Location: location, Message: fmt.Sprintf(format, args...), Kind: kind})
+}
+
+func (c *baselineChecker) checkOperation(location string, previous, current BaselineOperation) {
+	c.direction = 0
+	if previous.ID != current.ID {
+		c.report(location, specdiff.OperationIDChanged, "the operation ID is %q instead of %q", current.ID, previous.ID)
+	}
+	for _, mediaType := range previous.Consumes {
+		if !slices.Contains(current.Consumes, mediaType) {
+			c.report(location, specdiff.ConsumesRemoved, "the operation doesn't consume %s anymore", mediaType)
+		}
+	}
+	for _, mediaType := range previous.Produces {
+		if !slices.Contains(current.Produces, mediaType) {
+			c.report(location, specdiff.ProducesRemoved, "the operation doesn't produce %s anymore", mediaType)
+		}
+	}
+
+	c.direction = specdiff.Request
+	for _, key := range sortedKeys(previous.Parameters) {
+		paramLocation := location + " param " + key
+		param, found := current.Parameters[key]
+		if !found {
+			c.report(paramLocation, specdiff.ParameterRemoved, "the parameter was removed")
+			continue
+		}
+		c.checkParameter(paramLocation, previous.Parameters[key], param)
+	}
+	for _, key := range sortedKeys(current.Parameters) {
+		if _, known := previous.Parameters[key]; !known && current.Parameters[key].Required {
+			c.report(location+" param "+key, specdiff.RequiredParameterAdded, "the new parameter is required")
+		}
+	}
+
+	c.direction = specdiff.Response
+	for _, code := range sortedKeys(previous.Responses) {
+		responseLocation := location + " response " + code
+		resp, found := current.Responses[code]
+		if !found {
+			c.report(responseLocation, specdiff.ResponseRemoved, "the response was removed")
+			continue
+		}
+		c.checkSchema(responseLocation, previous.Responses[code].Schema, resp.Schema)
+	}
+}
+
+func (c *baselineChecker) checkParameter(location string, previous, current BaselineParameter) {
+	if !previous.Required && current.Required {
+		c.report(location, specdiff.ParameterRequired, "the parameter is required")
+	}
+	c.checkType(location, previous.Type, current.Type, previous.Format, current.Format)
+	c.checkEnum(location, previous.Enum, current.Enum)
+	c.checkSchema(location+"[]", previous.Items, current.Items)
+	c.checkSchema(location, previous.Schema, current.Schema)
+}
+
+func (c *baselineChecker) checkSchema(location string, previous, current *BaselineSchema) {
+	switch {
+	case previous == nil:
+		return
+	case current == nil:
+		c.report(location, specdiff.SchemaRemoved, "the schema was removed")
+		return
+	case previous.Ref != current.Ref:
+		c.report(location, specdiff.RefChanged, "the schema refers to %s instead of %s", orNone(current.Ref), orNone(previous.Ref))
+		return
+	}
+
+	c.checkType(location, previous.Type, current.Type, previous.Format, current.Format)
+	c.checkEnum(location, previous.Enum, current.Enum)
+	for _, ref := range previous.AllOf {
+		if !slices.Contains(current.AllOf, ref) {
+			c.report(location, specdiff.AllOfRemoved, "the schema doesn't compose %s anymore", ref)
+		}
+	}
+
+	for _, name := range sortedKeys(previous.Properties) {
+		propertyLocation := location + "." + name
+		property, found := current.Properties[name]
+		if !found {
+			c.report(propertyLocation, specdiff.PropertyRemoved, "the property was removed")
+			continue
+		}
+		c.checkSchema(propertyLocation, previous.Properties[name], property)
+	}
+	for _, name := range current.Required {
+		if !slices.Contains(previous.Required, name) {
+			kind := specdiff.PropertyRequired
+			if _, existed := previous.Properties[name]; !existed {
+				kind = specdiff.RequiredPropertyAdded
+			}
+			c.report(location+"."+name, kind, "the property is required")
+		}
+	}
+	for _, name := range previous.Required {
+		if _, found := current.Properties[name]; found && !slices.Contains(current.Required, name) {
+			c.report(location+"."+name, specdiff.PropertyOptional, "the property isn't required anymore")
+		}
+	}
+
+	c.checkSchema(location+"[]", previous.Items, current.Items)
+	c.checkSchema(location+"{}", previous.AdditionalProperties, current.AdditionalProperties)
+}
+
+func (c *baselineChecker) checkType(location, previousType, currentType, previousFormat, currentFormat string) {
+	if previousType != currentType {
+		c.report(location, specdiff.TypeChanged, "the type is %s instead of %s", orNone(currentType), orNone(previousType))
+		return
+	}
+	if previousFormat != currentFormat {
+		c.report(location, specdiff.FormatChanged, "the format is %s instead of %s", orNone(currentFormat), orNone(previousFormat))
+	}
+}
+
+func (c *baselineChecker) checkEnum(location string, previous, current []any) {
+	if len(current) == 0 {
+		return
+	}
+	if len(previous) == 0 {
+		c.report(location, specdiff.EnumAdded, "the values are restricted to an enum")
+		return
+	}
+	values := make([]string, 0, len(current))
+	for _, value := range current {
+		values = append(values, enumKey(value))
+	}
+	for _, value := range previous {
+		if key := enumKey(value); !slices.Contains(values, key) {
+			c.report(location, specdiff.EnumValueRemoved, "the enum value %s was removed", key)
+		}
+	}
+}
+
+// enumKey compares the values of enums recorded from a spec with those decoded from a baseline.
+func enumKey(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "none"
+	}
+	return value
+}
@@ -0,0 +1,195 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/3idey/codescan/codescan/specdiff"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const baselineSpec = `{
+  "swagger": "2.0",
+  "produces": ["application/json"],
+  "paths": {
+    "/users/{id}": {
+      "parameters": [{"name": "id", "in": "path", "type": "integer", "format": "int64", "required": true}],
+      "get": {
+        "operationId": "getUser",
+        "parameters": [{"$ref": "#/parameters/verbose"}],
+        "responses": {
+          "200": {"description": "the user", "schema": {"$ref": "#/definitions/User"}},
+          "404": {"$ref": "#/responses/notFound"}
+        }
+      }
+    }
+  },
+  "parameters": {
+    "verbose": {"name": "verbose", "in": "query", "type": "boolean"}
+  },
+  "responses": {
+    "notFound": {"description": "not found", "schema": {"type": "string"}}
+  },
+  "definitions": {
+    "User": {
+      "description": "A user.",
+      "type": "object",
+      "required": ["name"],
+      "properties": {
+        "name": {"type": "string", "example": "Ada"},
+        "role": {"type": "string", "enum": ["admin", "guest"]},
+        "tags": {"type": "array", "items": {"type": "string"}}
+      }
+    }
+  }
+}`
+
+func TestBaseline(t *testing.T) {
+	load := func(t *testing.T, edit func(doc *spec.Swagger)) *spec.Swagger {
+		t.Helper()
+		doc := new(spec.Swagger)
+		require.NoError(t, json.Unmarshal([]byte(baselineSpec), doc))
+		if edit != nil {
+			edit(doc)
+		}
+		return doc
+	}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should record the signatures of the operations", func(t *testing.T) {
+		baseline := NewBaseline(load(t, nil))
+
+		operation := baseline.Operations["GET /users/{id}"]
+		assert.Equal(t, "getUser", operation.ID)
+		assert.Equal(t, []string{"application/json"}, operation.Produces)
+		assert.Equal(t, BaselineParameter{Type: "integer", Format: "int64", Required: true}, operation.Parameters["path.id"])
+		assert.Equal(t, BaselineParameter{Type: "boolean"}, operation.Parameters["query.verbose"], "the shared parameter")
+		assert.Equal(t, "#/definitions/User", operation.Responses["200"].Schema.Ref)
+		assert.Equal(t, "string", operation.Responses["404"].Schema.Type, "the shared response")
+
+		user := baseline.Definitions["User"]
+		assert.Equal(t, []string{"name"}, user.Required)
+		assert.Equal(t, "string", user.Properties["tags"].Items.Type)
+	})
+
+	t.Run("should pass the round trip of a baseline", func(t *testing.T) {
+		data, err := json.Marshal(NewBaseline(load(t, nil)))
+		require.NoError(t, err)
+		baseline, err := ParseBaseline(data)
+		require.NoError(t, err)
+		assert.Empty(t, CheckBaseline(baseline, load(t, nil), now))
+	})
+
+	t.Run("should allow additive and internal changes", func(t *testing.T) {
+		baseline := NewBaseline(load(t, nil))
+		doc := load(t, func(doc *spec.Swagger) {
+			user := doc.Definitions["User"]
+			user.Description = "A user of the store."
+			user.Properties["email"] = *spec.StringProperty()
+			role := user.Properties["role"]
+			role.Enum = append(role.Enum, "owner")
+			user.Properties["role"] = role
+			doc.Definitions["User"] = user
+			doc.Definitions["Order"] = *new(spec.Schema).Typed("object", "")
+
+			op := doc.Paths.Paths["/users/{id}"].Get
+			op.AddParam(spec.QueryParam("fields").Typed("string", ""))
+			op.Produces = []string{"application/json", "application/xml"}
+			op.Responses.StatusCodeResponses[500] = *spec.NewResponse().WithDescription("failure")
+		})
+		assert.Empty(t, CheckBaseline(baseline, doc, now))
+	})
+
+	t.Run("should report the breaking changes", func(t *testing.T) {
+		baseline := NewBaseline(load(t, nil))
+		doc := load(t, func(doc *spec.Swagger) {
+			user := doc.Definitions["User"]
+			delete(user.Properties, "tags")
+			user.Required = append(user.Required, "role")
+			role := user.Properties["role"]
+			role.Enum = []any{"admin"}
+			user.Properties["role"] = role
+			doc.Definitions["User"] = user
+
+			doc.Parameters["verbose"] = *spec.QueryParam("verbose").Typed("string", "")
+			op := doc.Paths.Paths["/users/{id}"].Get
+			op.ID = "fetchUser"
+			op.AddParam(spec.QueryParam("tenant").Typed("string", "").AsRequired())
+			delete(op.Responses.StatusCodeResponses, 404)
+		})
+
+		var messages []string
+		for _, change := range CheckBaseline(baseline, doc, now) {
+			messages = append(messages, change.Error())
+		}
+		assert.Equal(t, []string{
+			`GET /users/{id}: the operation ID is "fetchUser" instead of "getUser"`,
+			`GET /users/{id} param query.verbose: the type is string instead of boolean`,
+			`GET /users/{id} param query.tenant: the new parameter is required`,
+			`GET /users/{id} response 404: the response was removed`,
+			`definition User.role: the enum value "guest" was removed`,
+			`definition User.tags: the property was removed`,
+			`definition User.role: the property is required`,
+		}, messages)
+	})
+
+	t.Run("should classify the changes of the inline schemas by direction", func(t *testing.T) {
+		inline := func(doc *spec.Swagger) {
+			op := doc.Paths.Paths["/users/{id}"].Get
+			notFound := op.Responses.StatusCodeResponses[404]
+			notFound.Schema = new(spec.Schema).Typed("object", "").SetProperty("reason", *spec.StringProperty())
+			op.Responses.StatusCodeResponses[404] = notFound
+		}
+		baseline := NewBaseline(load(t, inline))
+		doc := load(t, func(doc *spec.Swagger) {
+			inline(doc)
+			op := doc.Paths.Paths["/users/{id}"].Get
+			op.Responses.StatusCodeResponses[404].Schema.AddRequired("reason")
+			user := doc.Definitions["User"]
+			user.Required = nil
+			doc.Definitions["User"] = user
+		})
+
+		changes := CheckBaseline(baseline, doc, now)
+		require.Len(t, changes, 1, "the required property of a response doesn't break")
+		assert.Equal(t, "definition User.name", changes[0].Location)
+		assert.Equal(t, specdiff.PropertyOptional, changes[0].Kind)
+	})
+
+	t.Run("should skip the suppressed changes until they expire", func(t *testing.T) {
+		removed := load(t, func(doc *spec.Swagger) {
+			user := doc.Definitions["User"]
+			delete(user.Properties, "tags")
+			doc.Definitions["User"] = user
+			delete(doc.Paths.Paths["/users/{id}"].Get.Responses.StatusCodeResponses, 404)
+		})
+
+		baseline := NewBaseline(load(t, nil))
+		baseline.Suppressions = []BaselineSuppression{
+			{Location: "definition User.*", Reason: "tags moved to Profile", Expires: "2026-03-01"},
+			{Location: "GET /users/{id} response 404", Expires: "2026-02-28"},
+		}
+		changes := CheckBaseline(baseline, removed, now)
+		require.Len(t, changes, 1)
+		assert.Equal(t, "the response was removed (the suppression expired on 2026-02-28)", changes[0].Message)
+
+		assert.Len(t, CheckBaseline(baseline, removed, now.AddDate(0, 0, 1)), 2)
+	})
+
+	t.Run("should fail on invalid suppressions", func(t *testing.T) {
+		_, err := ParseBaseline([]byte(`{"version": 1, "suppressions": [{"location": "definition User", "expires": "next year"}, {"reason": "why"}]}`))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `invalid expiry date "next year" of the suppression of definition User, expected YYYY-MM-DD`)
+		assert.Contains(t, err.Error(), "a suppression of the baseline has no location")
+
+		_, err = ParseBaseline([]byte(`{"version": 2}`))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported baseline version 2")
+	})
+}
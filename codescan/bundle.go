@@ -0,0 +1,253 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// BundleSpec replaces the external refs of a spec with local ones, making it a single document: the
+// definitions, parameters and responses of the documents resolved by ResolveRemoteRefs become those of the
+// spec, and the path items they hold replace the refs of its paths.
+//
+// The elements are named after their name in their document, e.g. Money for
+// https://specs.acme.com/common.json#/definitions/Money, or after their document when it holds a single
+// element, e.g. Pet for definitions/Pet.json, with a numbered suffix when the spec has it already. The
+// definitions of the spec which only refer to another document, e.g. those of SplitSpec, keep their name.
+func BundleSpec(doc *spec.Swagger, resolved *ResolvedRefs) error {
+	b := &bundler{
+		resolved: resolved,
+		root:     rootLocation(resolved.Base),
+		doc:      doc,
+		locals:   make(map[string]string),
+		added:    make(map[string]map[string]any),
+	}
+
+	var moved []string
+	for _, name := range sortedKeys(doc.Definitions) {
+		definition := doc.Definitions[name]
+		if key, external := b.externalKey(b.root, definition.Ref.String()); external && isRefOnly(definition) {
+			b.locals[key] = definitionsPrefix + escapePointer(name)
+			moved = append(moved, name)
+		}
+	}
+	for _, name := range moved {
+		definition := doc.Definitions[name]
+		key, _ := b.externalKey(b.root, definition.Ref.String())
+		b.addElement(key, sectionDefinitions, name)
+	}
+
+	if doc.Paths != nil {
+		for _, pth := range sortedKeys(doc.Paths.Paths) {
+			item := doc.Paths.Paths[pth]
+			key, external := b.externalKey(b.root, item.Ref.String())
+			if !external {
+				continue
+			}
+			document, _, _ := strings.Cut(key, "#")
+			item = spec.PathItem{}
+			if !b.decode(key, &item) {
+				continue
+			}
+			part := &spec.Swagger{SwaggerProps: spec.SwaggerProps{Paths: &spec.Paths{Paths: map[string]spec.PathItem{pth: item}}}}
+			b.rewrite(part, document)
+			doc.Paths.Paths[pth] = part.Paths.Paths[pth]
+		}
+	}
+
+	b.rewrite(doc, b.root)
+	if b.err != nil {
+		return b.err
+	}
+
+	for _, name := range sortedKeys(b.added[sectionDefinitions]) {
+		if doc.Definitions == nil {
+			doc.Definitions = make(spec.Definitions)
+		}
+		doc.Definitions[name] = b.added[sectionDefinitions][name].(spec.Schema)
+	}
+	for _, name := range sortedKeys(b.added[sectionParameters]) {
+		if doc.Parameters == nil {
+			doc.Parameters = make(map[string]spec.Parameter)
+		}
+		doc.Parameters[name] = b.added[sectionParameters][name].(spec.Parameter)
+	}
+	for _, name := range sortedKeys(b.added[sectionResponses]) {
+		if doc.Responses == nil {
+			doc.Responses = make(map[string]spec.Response)
+		}
+		doc.Responses[name] = b.added[sectionResponses][name].(spec.Response)
+	}
+	return nil
+}
+
+// Sections of a spec holding the elements refs point to.
+const (
+	sectionDefinitions = "definitions"
+	sectionParameters  = "parameters"
+	sectionResponses   = "responses"
+)
+
+type bundler struct {
+	resolved *ResolvedRefs
+	root     string
+	doc      *spec.Swagger
+	locals   map[string]string         // local refs of the external ones, by document#pointer
+	added    map[string]map[string]any // elements added to the spec, by section and name
+	err      error
+}
+
+// externalKey returns the document#pointer of a ref found in the document at base, unless it points to the spec.
+func (b *bundler) externalKey(base, ref string) (string, bool) {
+	document := refLocation(base, ref)
+	if ref == "" || document == "" || document == b.root {
+		return "", false
+	}
+	_, pointer, _ := strings.Cut(ref, "#")
+	return document + "#" + pointer, true
+}
+
+// rewrite replaces the refs of a part of the spec found in the document at base with local refs.
+func (b *bundler) rewrite(part *spec.Swagger, base string) {
+	err := rewriteRefs(part, func(ref string) string {
+		if b.err != nil {
+			return ref
+		}
+		key, external := b.externalKey(base, ref)
+		if !external {
+			if _, pointer, _ := strings.Cut(ref, "#"); base != b.root {
+				// a ref of another document to the spec itself
+				return "#" + pointer
+			}
+			return ref
+		}
+		if local, added := b.locals[key]; added {
+			return local
+		}
+		return b.addElement(key, "", "")
+	})
+	if err != nil && b.err == nil {
+		b.err = err
+	}
+}
+
+// addElement adds the element at key to the spec, in its section under its name unless given, and returns
+// the local ref to it.
+func (b *bundler) addElement(key, section, name string) string {
+	document, pointer, _ := strings.Cut(key, "#")
+	if section == "" {
+		section, name = elementName(document, pointer)
+		name = b.uniqueName(section, name)
+	}
+	local := "#/" + section + "/" + escapePointer(name)
+	b.locals[key] = local
+	if b.added[section] == nil {
+		b.added[section] = make(map[string]any)
+	}
+	// reserves the name while the refs of the element are rewritten
+	b.added[section][name] = nil
+
+	part := &spec.Swagger{}
+	switch section {
+	case sectionParameters:
+		var param spec.Parameter
+		if !b.decode(key, &param) {
+			return local
+		}
+		part.Paths = &spec.Paths{Paths: map[string]spec.PathItem{"/": {PathItemProps: spec.PathItemProps{Parameters: []spec.Parameter{param}}}}}
+		b.rewrite(part, document)
+		b.added[section][name] = part.Paths.Paths["/"].Parameters[0]
+	case sectionResponses:
+		var resp spec.Response
+		if !b.decode(key, &resp) {
+			return local
+		}
+		part.Responses = map[string]spec.Response{name: resp}
+		b.rewrite(part, document)
+		b.added[section][name] = part.Responses[name]
+	default:
+		var schema spec.Schema
+		if !b.decode(key, &schema) {
+			return local
+		}
+		part.Definitions = spec.Definitions{name: schema}
+		b.rewrite(part, document)
+		b.added[section][name] = part.Definitions[name]
+	}
+	return local
+}
+
+// decode decodes the element at key, failing the bundle when it points to nothing.
+func (b *bundler) decode(key string, element any) bool {
+	document, pointer, _ := strings.Cut(key, "#")
+	content, found := documentElement(b.resolved.Documents[document], pointer)
+	if _, resolved := b.resolved.Documents[document]; !resolved || !found {
+		b.err = fmt.Errorf("%w: %s points to nothing", ErrUnresolvedRefs, key)
+		return false
+	}
+	jazon, err := json.Marshal(content)
+	if err == nil {
+		err = json.Unmarshal(jazon, element)
+	}
+	if err != nil {
+		b.err = fmt.Errorf("invalid element %s: %w", key, err)
+		return false
+	}
+	return true
+}
+
+// uniqueName returns the name, or the name with the first numbered suffix, which the spec doesn't have in a section.
+func (b *bundler) uniqueName(section, name string) string {
+	taken := func(candidate string) bool {
+		if _, added := b.added[section][candidate]; added {
+			return true
+		}
+		switch section {
+		case sectionParameters:
+			_, exists := b.doc.Parameters[candidate]
+			return exists
+		case sectionResponses:
+			_, exists := b.doc.Responses[candidate]
+			return exists
+		default:
+			_, exists := b.doc.Definitions[candidate]
+			return exists
+		}
+	}
+	candidate := name
+	for i := 2; taken(candidate); i++ {
+		candidate = name + strconv.Itoa(i)
+	}
+	return candidate
+}
+
+// elementName returns the section and the name of the element of a document a pointer points to.
+func elementName(document, pointer string) (string, string) {
+	tokens := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	if len(tokens) == 2 && (tokens[0] == sectionParameters || tokens[0] == sectionResponses || tokens[0] == sectionDefinitions) {
+		return tokens[0], unescapePointer(tokens[1])
+	}
+	if pointer != "" && pointer != "/" {
+		return sectionDefinitions, unescapePointer(tokens[len(tokens)-1])
+	}
+	name := path.Base(strings.ReplaceAll(document, "\\", "/"))
+	if i := strings.IndexAny(name, "?#"); i >= 0 {
+		name = name[:i]
+	}
+	return sectionDefinitions, strings.TrimSuffix(name, path.Ext(name))
+}
+
+// isRefOnly tells whether a schema is only a $ref.
+func isRefOnly(schema spec.Schema) bool {
+	ref := schema.Ref
+	schema.Ref = spec.Ref{}
+	jazon, err := json.Marshal(schema)
+	return err == nil && string(jazon) == "{}" && ref.String() != ""
+}
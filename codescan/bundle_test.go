@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundleSpec(t *testing.T) {
+	t.Run("should join the documents of a split spec", func(t *testing.T) {
+		doc, err := Run(&Options{Packages: []string{"github.com/3idey/codescan/fixtures/goparsing/petstore/..."}})
+		require.NoError(t, err)
+		documents, err := SplitSpec(doc, "swagger.json")
+		require.NoError(t, err)
+
+		files := make(map[string]string, len(documents))
+		for name, document := range documents {
+			jazon, err := MarshalJSON(document, false)
+			require.NoError(t, err)
+			files[name] = string(jazon)
+		}
+		dir := writeRemoteDocuments(t, files)
+		rootPath := filepath.Join(dir, "swagger.json")
+		data, err := os.ReadFile(rootPath)
+		require.NoError(t, err)
+		split, err := ParseInputSpec(data, false)
+		require.NoError(t, err)
+
+		resolved, err := ResolveRemoteRefs(context.Background(), split, RemoteRefOptions{Base: rootPath})
+		require.NoError(t, err)
+		require.NoError(t, BundleSpec(split, resolved))
+
+		expected, err := MarshalJSON(doc, false)
+		require.NoError(t, err)
+		bundled, err := MarshalJSON(split, false)
+		require.NoError(t, err)
+		assert.JSONEq(t, string(expected), string(bundled))
+	})
+
+	t.Run("should add the elements of the remote documents", func(t *testing.T) {
+		server := httptest.NewServer(http.FileServer(http.Dir(writeRemoteDocuments(t, map[string]string{
+			"common.json": `{
+  "definitions": {"Money": {"type": "object", "properties": {"currency": {"$ref": "#/definitions/Currency"}}}, "Currency": {"type": "string"}},
+  "parameters": {"page": {"name": "page", "in": "query", "type": "integer"}},
+  "responses": {"notFound": {"description": "not found", "schema": {"$ref": "errors.json"}}}
+}`,
+			"errors.json": `{"type": "object", "properties": {"message": {"type": "string"}}}`,
+		}))))
+		defer server.Close()
+
+		doc, err := ParseInputSpec([]byte(`{
+  "swagger": "2.0",
+  "info": {"title": "Orders", "version": "1.0"},
+  "paths": {"/orders": {"get": {
+    "parameters": [{"$ref": "`+server.URL+`/common.json#/parameters/page"}],
+    "responses": {
+      "200": {"description": "the orders", "schema": {"type": "array", "items": {"$ref": "#/definitions/Order"}}},
+      "404": {"$ref": "`+server.URL+`/common.json#/responses/notFound"}
+    }
+  }}},
+  "definitions": {
+    "Order": {"type": "object", "properties": {"total": {"$ref": "`+server.URL+`/common.json#/definitions/Money"}}},
+    "Currency": {"type": "integer"}
+  }
+}`), false)
+		require.NoError(t, err)
+
+		resolved, err := ResolveRemoteRefs(context.Background(), doc, RemoteRefOptions{})
+		require.NoError(t, err)
+		require.NoError(t, BundleSpec(doc, resolved))
+
+		total := doc.Definitions["Order"].Properties["total"]
+		assert.Equal(t, "#/definitions/Money", total.Ref.String())
+		currency := doc.Definitions["Money"].Properties["currency"]
+		assert.Equal(t, "#/definitions/Currency2", currency.Ref.String(), "the names are unique")
+		assert.Equal(t, "string", doc.Definitions["Currency2"].Type[0])
+		assert.Equal(t, "integer", doc.Definitions["Currency"].Type[0])
+		assert.Contains(t, doc.Definitions, "errors", "named after their document")
+
+		get := doc.Paths.Paths["/orders"].Get
+		assert.Equal(t, "#/parameters/page", get.Parameters[0].Ref.String())
+		assert.Equal(t, "page", doc.Parameters["page"].Name)
+		notFoundRef := get.Responses.StatusCodeResponses[404]
+		assert.Equal(t, "#/responses/notFound", notFoundRef.Ref.String())
+		notFound := doc.Responses["notFound"]
+		assert.Equal(t, "#/definitions/errors", notFound.Schema.Ref.String())
+
+		jazon, err := MarshalJSON(doc, false)
+		require.NoError(t, err)
+		assert.NotContains(t, string(jazon), server.URL)
+		for _, problem := range ValidateSpec(doc, nil) {
+			assert.NotContains(t, problem.Message, "$ref")
+		}
+	})
+
+	t.Run("should fail with the refs pointing to nothing", func(t *testing.T) {
+		dir := writeRemoteDocuments(t, map[string]string{"money.json": moneyDocument})
+		doc := remoteRefSpec(t, "money.json#/definitions/Amount")
+		resolved, err := ResolveRemoteRefs(context.Background(), doc, RemoteRefOptions{Base: filepath.Join(dir, "swagger.json")})
+		require.NoError(t, err)
+
+		err = BundleSpec(doc, resolved)
+		require.ErrorIs(t, err, ErrUnresolvedRefs)
+		assert.Contains(t, err.Error(), "money.json#/definitions/Amount points to nothing")
+	})
+}
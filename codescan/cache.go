@@ -0,0 +1,456 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/token"
+	"io"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"slices"
+	"sync"
+
+	"github.com/go-openapi/spec"
+	"golang.org/x/tools/go/packages"
+)
+
+// cacheFormat is the version of the format of the entries of Options.CacheDir, changed with it.
+const cacheFormat = 4
+
+// modulePath is the module of codescan, whose version is part of the cache keys.
+const modulePath = "github.com/3idey/codescan"
+
+// fingerprintLoadMode loads the files of the packages and of their dependencies, without type-checking them.
+const fingerprintLoadMode = packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps | packages.NeedModule
+
+// cacheEntry is a scan cached in Options.CacheDir: its spec and outputs, with the fingerprints of the
+// packages it loaded and of the files it read, e.g. JSON Schema files.
+type cacheEntry struct {
+	Packages            map[string]string          `json:"packages"`        // fingerprint by package ID
+	Files               map[string]string          `json:"files,omitempty"` // hash by path
+	Spec                json.RawMessage            `json:"spec"`
+	Stats               Stats                      `json:"stats"`
+	Diagnostics         []Diagnostic               `json:"diagnostics,omitempty"`
+	Suppressions        []Suppression              `json:"suppressions,omitempty"`
+	DefinitionPositions map[string]token.Position  `json:"definitionPositions,omitempty"`
+	DefinitionIndex     map[string]string          `json:"definitionIndex,omitempty"`
+	SourceMap           map[string]token.Position  `json:"sourceMap,omitempty"`
+	UnusedDefinitions   []UnusedDefinition         `json:"unusedDefinitions,omitempty"`
+	OutputSets          map[string]json.RawMessage `json:"outputSets,omitempty"`
+}
+
+// cacheKeyOptions are the options keying the cache entries. The outputs of the scan, and the callbacks, are
+// shadowed: only whether the source map and the definition index are requested changes the scan.
+type cacheKeyOptions struct {
+	*Options
+
+	DefinitionPositions bool
+	OnProgress          bool
+	Logger              bool
+	Stats               bool
+	Diagnostics         bool
+	Suppressions        bool
+	DefinitionIndex     bool
+	SourceMap           bool
+	CacheDir            bool
+	OutputSetSpecs      bool
+	UnusedDefinitions   bool
+	DefinitionNamer     bool
+	Loader              bool
+}
+
+// runCached runs a scan, returning the cached one when neither the options, the codescan build nor the Go
+// files of the packages changed since, and caching it otherwise. The scans missing the cache reuse the models
+// cached of the packages unchanged, see modelCache. The failed scans are not cached.
+func runCached(ctx context.Context, opts *Options) (*spec.Swagger, error) {
+	key, err := cacheKey(opts)
+	if err != nil {
+		return nil, err
+	}
+	file := filepath.Join(opts.CacheDir, key+".json")
+	fingerprints, err := fingerprintPackages(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	cached, err := readCacheEntry(file)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Printf("WARNING: ignoring the scan cache %s: %v", file, err)
+	}
+	if cached != nil && maps.Equal(cached.Packages, fingerprints) && cached.filesUnchanged() {
+		return cached.replay(opts)
+	}
+
+	recording := *opts
+	recording.Stats = new(Stats)
+	recording.Diagnostics = new([]Diagnostic)
+	recording.Suppressions = new([]Suppression)
+	recording.DefinitionPositions = make(map[string]token.Position)
+	if opts.DefinitionIndex != nil {
+		recording.DefinitionIndex = new(DefinitionIndex)
+	}
+	if opts.SourceMap != nil {
+		recording.SourceMap = make(map[string]token.Position)
+	}
+	if opts.UnusedDefinitions != nil {
+		recording.UnusedDefinitions = new([]UnusedDefinition)
+	}
+	if len(opts.OutputSets) > 0 {
+		recording.OutputSetSpecs = make(map[string]*spec.Swagger, len(opts.OutputSets))
+	}
+
+	models := newModelCache(filepath.Join(opts.CacheDir, "models"), key)
+	swspec, scanErr := run(ctx, &recording, nil, models)
+	entry := &cacheEntry{
+		Packages:            fingerprints,
+		Stats:               *recording.Stats,
+		Diagnostics:         *recording.Diagnostics,
+		Suppressions:        *recording.Suppressions,
+		DefinitionPositions: recording.DefinitionPositions,
+		SourceMap:           recording.SourceMap,
+	}
+	if recording.DefinitionIndex != nil {
+		entry.DefinitionIndex = recording.DefinitionIndex.Definitions
+	}
+	if recording.UnusedDefinitions != nil {
+		entry.UnusedDefinitions = *recording.UnusedDefinitions
+	}
+	if cached != nil {
+		entry.Stats.ChangedPackages = changedPackages(cached.Packages, fingerprints)
+	}
+	entry.fill(opts)
+	if scanErr != nil {
+		return nil, scanErr
+	}
+	if entry.Files, err = hashFiles(slices.Concat(recording.Stats.SchemaFiles, recording.Stats.ExampleFiles)); err != nil {
+		return nil, err
+	}
+
+	if entry.Spec, err = json.Marshal(swspec); err != nil {
+		return nil, err
+	}
+	for name, setSpec := range recording.OutputSetSpecs {
+		if entry.OutputSets == nil {
+			entry.OutputSets = make(map[string]json.RawMessage, len(recording.OutputSetSpecs))
+		}
+		if entry.OutputSets[name], err = json.Marshal(setSpec); err != nil {
+			return nil, err
+		}
+	}
+	if opts.OutputSetSpecs != nil {
+		maps.Copy(opts.OutputSetSpecs, recording.OutputSetSpecs)
+	}
+	if err := writeCacheFile(file, entry); err != nil {
+		log.Printf("WARNING: can't write the scan cache %s: %v", file, err)
+	}
+	if err := models.save(); err != nil {
+		log.Printf("WARNING: can't write the models of the scan cache: %v", err)
+	}
+	return swspec, nil
+}
+
+// replay returns the cached spec, filling the outputs of the options and logging the diagnostics again, or
+// sending them to Options.Logger.
+func (e *cacheEntry) replay(opts *Options) (*spec.Swagger, error) {
+	swspec := new(spec.Swagger)
+	if err := json.Unmarshal(e.Spec, swspec); err != nil {
+		return nil, fmt.Errorf("invalid cached spec: %w", err)
+	}
+	if opts.OutputSetSpecs != nil {
+		for name, data := range e.OutputSets {
+			setSpec := new(spec.Swagger)
+			if err := json.Unmarshal(data, setSpec); err != nil {
+				return nil, fmt.Errorf("invalid cached spec of the output set %s: %w", name, err)
+			}
+			opts.OutputSetSpecs[name] = setSpec
+		}
+	}
+	for _, diagnostic := range e.Diagnostics {
+		if opts.Logger != nil {
+			opts.Logger(diagnostic)
+		} else {
+			logDiagnostic(diagnostic)
+		}
+	}
+	e.Stats.Cached = true
+	e.fill(opts)
+	return swspec, nil
+}
+
+// fill fills the outputs requested by the options.
+func (e *cacheEntry) fill(opts *Options) {
+	if opts.Stats != nil {
+		*opts.Stats = e.Stats
+	}
+	if opts.Diagnostics != nil {
+		*opts.Diagnostics = e.Diagnostics
+	}
+	if opts.Suppressions != nil {
+		*opts.Suppressions = e.Suppressions
+	}
+	if opts.DefinitionPositions != nil {
+		maps.Copy(opts.DefinitionPositions, e.DefinitionPositions)
+	}
+	if opts.DefinitionIndex != nil && len(e.DefinitionIndex) > 0 {
+		if opts.DefinitionIndex.Definitions == nil {
+			opts.DefinitionIndex.Definitions = make(map[string]string)
+		}
+		maps.Copy(opts.DefinitionIndex.Definitions, e.DefinitionIndex)
+	}
+	if opts.SourceMap != nil {
+		maps.Copy(opts.SourceMap, e.SourceMap)
+	}
+	if opts.UnusedDefinitions != nil {
+		*opts.UnusedDefinitions = e.UnusedDefinitions
+	}
+}
+
+// cacheKey returns the key of the scans of the options: a hash of the options, of the codescan build and of the
+// format of the entries. The file of the key holds the last scan with these options.
+func cacheKey(opts *Options) (string, error) {
+	keyed := *opts
+	workDir, err := filepath.Abs(opts.WorkDir)
+	if err != nil {
+		return "", err
+	}
+	keyed.WorkDir = workDir
+	keyed.Concurrency = 0 // the scans are the same whatever the concurrency
+
+	key, err := json.Marshal(struct {
+		Format  int             `json:"format"`
+		Build   string          `json:"build"`
+		Options cacheKeyOptions `json:"options"`
+	}{
+		Format: cacheFormat,
+		Build:  codescanBuild(),
+		Options: cacheKeyOptions{
+			Options:         &keyed,
+			DefinitionIndex: opts.DefinitionIndex != nil,
+			SourceMap:       opts.SourceMap != nil,
+
+			UnusedDefinitions: opts.UnusedDefinitions != nil,
+			Loader:            opts.Loader != nil,
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("can't key the scan cache: %w", err)
+	}
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:16]), nil
+}
+
+// moduleVersion is the version of the module of codescan in the build information of the executable, empty
+// for the development builds, which have none.
+var moduleVersion = sync.OnceValue(func() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	version := info.Main.Version
+	if info.Main.Path != modulePath {
+		version = ""
+		for _, dep := range info.Deps {
+			if dep.Path == modulePath {
+				version = dep.Version
+			}
+		}
+	}
+	if version == "(devel)" {
+		return ""
+	}
+	return version
+})
+
+// codescanBuild identifies the build of codescan: the version of its module, or the hash of the executable
+// for the development builds, which have no version.
+var codescanBuild = sync.OnceValue(func() string {
+	if version := moduleVersion(); version != "" {
+		return version
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return "unknown"
+	}
+	hash, err := hashFile(exe)
+	if err != nil {
+		return "unknown"
+	}
+	return hash
+})
+
+// fingerprintPackages returns the fingerprints of the packages the scan loads, with their dependencies, by ID.
+// The packages of the module cache are identified by the version of their module, those outside modules, e.g.
+// of the standard library, by the size and the modification time of their files, and the others by the
+// contents of their files.
+func fingerprintPackages(opts *Options) (map[string]string, error) {
+	cfg, patterns, _, err := loadConfig(opts, fingerprintLoadMode)
+	if err != nil {
+		return nil, err
+	}
+	roots, err := loadWith(opts, cfg, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	if opts.ExtraBuildTags != "" {
+		docCfg := *cfg
+		docCfg.BuildFlags = []string{"-tags", joinBuildTags(opts.BuildTags, opts.ExtraBuildTags)}
+		docRoots, err := loadWith(opts, &docCfg, patterns...)
+		if err != nil {
+			return nil, err
+		}
+		roots = append(roots, docRoots...)
+	}
+
+	fingerprints := make(map[string]string)
+	var errs []error
+	packages.Visit(roots, nil, func(pkg *packages.Package) {
+		if _, done := fingerprints[pkg.ID]; done {
+			return
+		}
+		fingerprint, err := fingerprintPackage(pkg)
+		if err != nil {
+			errs = append(errs, err)
+			return
+		}
+		fingerprints[pkg.ID] = fingerprint
+	})
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return fingerprints, nil
+}
+
+func fingerprintPackage(pkg *packages.Package) (string, error) {
+	h := sha256.New()
+	for _, pkgErr := range pkg.Errors {
+		fmt.Fprintln(h, "error", pkgErr.Error())
+	}
+
+	module := pkg.Module
+	if module != nil && module.Replace != nil {
+		module = module.Replace
+	}
+	if module != nil && module.Version != "" && !pkg.Module.Main {
+		fmt.Fprintln(h, "module", module.Path, module.Version)
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+
+	files := slices.Concat(pkg.GoFiles, pkg.OtherFiles, pkg.IgnoredFiles)
+	slices.Sort(files)
+	for _, file := range slices.Compact(files) {
+		if pkg.Module == nil {
+			info, err := os.Stat(file)
+			if err != nil {
+				return "", err
+			}
+			fmt.Fprintln(h, "file", file, info.Size(), info.ModTime().UnixNano())
+			continue
+		}
+		hash, err := hashFile(file)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintln(h, "file", file, hash)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// filesUnchanged tells if the files the cached scan read, besides those of the packages, are unchanged.
+func (e *cacheEntry) filesUnchanged() bool {
+	hashes, err := hashFiles(slices.Collect(maps.Keys(e.Files)))
+	return err == nil && maps.Equal(hashes, e.Files)
+}
+
+func hashFiles(paths []string) (map[string]string, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	hashes := make(map[string]string, len(paths))
+	for _, path := range paths {
+		hash, err := hashFile(path)
+		if err != nil {
+			return nil, err
+		}
+		hashes[path] = hash
+	}
+	return hashes, nil
+}
+
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// changedPackages returns the packages added, removed or changed between two sets of fingerprints, sorted.
+func changedPackages(before, after map[string]string) []string {
+	var changed []string
+	for id, fingerprint := range after {
+		if before[id] != fingerprint {
+			changed = append(changed, id)
+		}
+	}
+	for id := range before {
+		if _, found := after[id]; !found {
+			changed = append(changed, id)
+		}
+	}
+	slices.Sort(changed)
+	return changed
+}
+
+func readCacheEntry(file string) (*cacheEntry, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	entry := new(cacheEntry)
+	if err := json.Unmarshal(data, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// writeCacheFile writes an entry through a temporary file renamed in place, so that concurrent scans never
+// read a partial entry.
+func writeCacheFile(file string, entry any) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(file), ".scan-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
@@ -0,0 +1,520 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"encoding/json"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const cachedModels = `package models
+
+// User of the API.
+//
+// swagger:model
+type User struct {
+	Name string ` + "`json:\"name\"`" + `
+
+	// Schema File: profile.schema.json
+	Profile map[string]any ` + "`json:\"profile\"`" + `
+}
+
+// Order of a user.
+//
+// swagger:model
+type Order struct {
+	ID string ` + "`json:\"id\"`" + `
+}
+`
+
+const cachedAPI = `package api
+
+import "example.com/cached/models"
+
+// The users.
+//
+// swagger:response usersResponse
+type UsersResponse struct {
+	// in: body
+	Body []models.User
+}
+
+// swagger:route GET /users users listUsers
+//
+// Lists the users.
+//
+// Responses:
+//   200: usersResponse
+`
+
+// writeCachedModule writes a module with a models and an api package, returning its directory.
+func writeCachedModule(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":                     "module example.com/cached\n\ngo 1.22\n",
+		"models/models.go":           cachedModels,
+		"api/api.go":                 cachedAPI,
+		"models/profile.schema.json": `{"type": "object", "properties": {"bio": {"type": "string"}}}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+	return dir
+}
+
+func TestScanCache(t *testing.T) {
+	dir := writeCachedModule(t)
+	cacheDir := t.TempDir()
+
+	scan := func(t *testing.T, cached bool, opts Options) (*spec.Swagger, []byte, Stats) {
+		t.Helper()
+		var stats Stats
+		opts.Packages = []string{"./..."}
+		opts.WorkDir = dir
+		opts.ScanModels = true
+		opts.Stats = &stats
+		if cached {
+			opts.CacheDir = cacheDir
+		}
+		doc, err := Run(&opts)
+		require.NoError(t, err)
+		jazon, err := json.Marshal(doc)
+		require.NoError(t, err)
+		return doc, jazon, stats
+	}
+
+	_, cold, stats := scan(t, true, Options{})
+	assert.False(t, stats.Cached)
+	assert.Equal(t, 2, stats.Definitions)
+
+	t.Run("should return the cached scan of unchanged packages", func(t *testing.T) {
+		positions := make(map[string]token.Position)
+		_, warm, stats := scan(t, true, Options{DefinitionPositions: positions})
+		assert.True(t, stats.Cached)
+		assert.Equal(t, 2, stats.Definitions, "the statistics of the cached scan")
+		assert.Equal(t, cold, warm)
+		assert.Equal(t, "models.go", filepath.Base(positions["User"].Filename))
+
+		_, uncached, _ := scan(t, false, Options{})
+		assert.Equal(t, uncached, warm)
+	})
+
+	t.Run("should scan again with other options", func(t *testing.T) {
+		_, _, stats := scan(t, true, Options{BuildTags: "integration"})
+		assert.False(t, stats.Cached)
+		_, _, stats = scan(t, true, Options{BuildTags: "integration"})
+		assert.True(t, stats.Cached)
+	})
+
+	t.Run("should scan again once a file changed", func(t *testing.T) {
+		before, _, _ := scan(t, true, Options{})
+
+		changed := strings.Replace(cachedModels, "ID string", "Total int64 `json:\"total\"`\n\tID string", 1)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "models", "models.go"), []byte(changed), 0o600))
+
+		after, rescanned, stats := scan(t, true, Options{})
+		assert.False(t, stats.Cached)
+		assert.Equal(t, []string{"example.com/cached/models"}, stats.ChangedPackages)
+		assert.Equal(t, before.Definitions["User"], after.Definitions["User"])
+		assert.NotEqual(t, before.Definitions["Order"], after.Definitions["Order"])
+		assert.Contains(t, after.Definitions["Order"].Properties, "total")
+		assert.Equal(t, before.Paths, after.Paths)
+
+		_, uncached, _ := scan(t, false, Options{})
+		assert.Equal(t, uncached, rescanned)
+
+		_, warm, stats := scan(t, true, Options{})
+		assert.True(t, stats.Cached)
+		assert.Equal(t, rescanned, warm)
+	})
+
+	t.Run("should scan again once a schema file changed", func(t *testing.T) {
+		profile := filepath.Join(dir, "models", "profile.schema.json")
+		require.NoError(t, os.WriteFile(profile, []byte(`{"type": "object", "properties": {"age": {"type": "integer"}}}`), 0o600))
+
+		after, _, stats := scan(t, true, Options{})
+		assert.False(t, stats.Cached)
+		assert.Empty(t, stats.ChangedPackages)
+		assert.Equal(t, []string{"Order"}, stats.ReusedModels, "the model reading the schema file is built again")
+		assert.Equal(t, []string{profile}, stats.SchemaFiles)
+		assert.Contains(t, after.Definitions["User"].Properties["profile"].Properties, "age")
+
+		_, _, stats = scan(t, true, Options{})
+		assert.True(t, stats.Cached)
+	})
+}
+
+// cachedPackages are the packages of a module whose models are declared apart, for TestScanCacheModels.
+var cachedPackages = map[string]string{
+	"go.mod": "module example.com/shop\n\ngo 1.22\n",
+	"api/api.go": `package api
+
+import (
+	"example.com/shop/orders"
+	"example.com/shop/users"
+)
+
+// The users.
+//
+// swagger:response usersResponse
+type UsersResponse struct {
+	// in: body
+	Body []users.User
+}
+
+// The orders.
+//
+// swagger:response ordersResponse
+type OrdersResponse struct {
+	// in: body
+	Body []orders.Order
+}
+
+// swagger:route GET /users users listUsers
+//
+// Responses:
+//   200: usersResponse
+
+// swagger:route GET /orders orders listOrders
+//
+// Responses:
+//   200: ordersResponse
+`,
+	"users/users.go": `package users
+
+// User of the shop.
+type User struct {
+	// the name of the user
+	Name string ` + "`json:\"name\"`" + `
+	// the role of the user
+	//
+	// enum: ["admin","member"]
+	Role string ` + "`json:\"role\"`" + `
+}
+`,
+	"orders/orders.go": ordersSource,
+}
+
+const ordersSource = `package orders
+
+// Order of a user.
+type Order struct {
+	ID string ` + "`json:\"id\"`" + `
+	Lines []Line ` + "`json:\"lines\"`" + `
+}
+
+// Line of an order.
+type Line struct {
+	// the count of the product
+	//
+	// minimum: 1
+	Count int ` + "`json:\"count\"`" + `
+}
+`
+
+func TestScanCacheModels(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range cachedPackages {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+	cacheDir := t.TempDir()
+	scan := func(t *testing.T, cached bool) (*spec.Swagger, []byte, Stats) {
+		t.Helper()
+		var stats Stats
+		opts := Options{Packages: []string{"./api"}, WorkDir: dir, Stats: &stats, SourceMap: make(map[string]token.Position)}
+		if cached {
+			opts.CacheDir = cacheDir
+		}
+		doc, err := Run(&opts)
+		require.NoError(t, err)
+		jazon, err := json.MarshalIndent(doc, "", "  ")
+		require.NoError(t, err)
+		return doc, jazon, stats
+	}
+
+	before, _, stats := scan(t, true)
+	require.False(t, stats.Cached)
+	assert.Empty(t, stats.ReusedModels)
+
+	changed := strings.Replace(ordersSource, "minimum: 1", "minimum: 1\n\t// maximum: 99", 1)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "orders", "orders.go"), []byte(changed), 0o600))
+
+	after, rescanned, stats := scan(t, true)
+	assert.False(t, stats.Cached)
+	assert.Equal(t, []string{"example.com/shop/orders"}, stats.ChangedPackages)
+	assert.Equal(t, []string{"User"}, stats.ReusedModels, "the models of the unchanged packages are reused")
+
+	require.Len(t, after.Definitions, len(before.Definitions))
+	var changedDefinitions []string
+	for name, definition := range after.Definitions {
+		if !assert.Contains(t, before.Definitions, name) {
+			continue
+		}
+		if !reflect.DeepEqual(before.Definitions[name], definition) {
+			changedDefinitions = append(changedDefinitions, name)
+		}
+	}
+	assert.Equal(t, []string{"Line"}, changedDefinitions, "only the definitions of the changed declarations change")
+	assert.Equal(t, before.Paths, after.Paths)
+
+	_, cold, _ := scan(t, false)
+	assert.Equal(t, string(cold), string(rescanned), "the scan reusing models is the scan of a cold cache")
+}
+
+// cachedDependencies are the packages of a module whose models refer to the types of deeper and deeper
+// packages, the deepest one holding the Unit type the mutations of TestScanCacheDependencies change.
+var cachedDependencies = map[string]string{
+	"go.mod": "module example.com/deps\n\ngo 1.22\n",
+	"api/api.go": `package api
+
+import "example.com/deps/models"
+
+// The orders.
+//
+// swagger:response ordersResponse
+type OrdersResponse struct {
+	// in: body
+	Body []models.Order
+}
+
+// swagger:route GET /orders orders listOrders
+//
+// Lists the orders.
+//
+// Responses:
+//   200: ordersResponse
+`,
+	"models/models.go": `package models
+
+import "example.com/deps/lines"
+
+// Order of a user.
+type Order struct {
+	Lines []lines.Line ` + "`json:\"lines\"`" + `
+}
+`,
+	"lines/lines.go": `package lines
+
+import "example.com/deps/units"
+
+// Line of an order.
+//
+// swagger:allOf
+type Line struct {
+	Quantity units.Quantity ` + "`json:\"quantity\"`" + `
+}
+`,
+	"units/units.go": unitsSource,
+}
+
+const unitsSource = `package units
+
+// Quantity of a product.
+type Quantity struct {
+	// the amount, in units
+	Amount int64 ` + "`json:\"amount\"`" + `
+}
+`
+
+func TestScanCacheDependencies(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range cachedDependencies {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+	cacheDir := t.TempDir()
+	scan := func(t *testing.T, cached bool) ([]byte, Stats) {
+		t.Helper()
+		var stats Stats
+		opts := Options{Packages: []string{"./api"}, WorkDir: dir, Stats: &stats}
+		if cached {
+			opts.CacheDir = cacheDir
+		}
+		doc, err := Run(&opts)
+		require.NoError(t, err)
+		jazon, err := json.MarshalIndent(doc, "", "  ")
+		require.NoError(t, err)
+		return jazon, stats
+	}
+	_, stats := scan(t, true)
+	require.False(t, stats.Cached)
+
+	// each mutation of the deepest package changes the spec through the packages referring to it
+	mutations := []struct {
+		name, old, new string
+	}{
+		{"a new field", "Amount int64", "Unit string `json:\"unit\"`\n\tAmount int64"},
+		{"a new description", "the amount, in units", "the amount, in the unit"},
+		{"a new type", "Amount int64", "Amount float64"},
+		{"a new json name", "`json:\"unit\"`", "`json:\"unitName\"`"},
+		{"a removed field", "Unit string `json:\"unitName\"`\n\t", ""},
+		{"a validation", "// the amount, in the unit", "// the amount, in the unit\n\t// minimum: 1"},
+	}
+	source := unitsSource
+	var previous []byte
+	for _, mutation := range mutations {
+		mutated := strings.Replace(source, mutation.old, mutation.new, 1)
+		require.NotEqual(t, source, mutated, mutation.name)
+		source = mutated
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "units", "units.go"), []byte(source), 0o600))
+
+		rescanned, stats := scan(t, true)
+		assert.False(t, stats.Cached, mutation.name)
+		assert.Equal(t, []string{"example.com/deps/units"}, stats.ChangedPackages, mutation.name)
+		assert.Empty(t, stats.ReusedModels, "the models depend on the units of %s", mutation.name)
+		assert.NotEqual(t, previous, rescanned, mutation.name)
+
+		cold, _ := scan(t, false)
+		assert.Equal(t, string(cold), string(rescanned), "the cached scan of %s is the scan of a cold cache", mutation.name)
+
+		warm, stats := scan(t, true)
+		assert.True(t, stats.Cached, mutation.name)
+		assert.Equal(t, string(cold), string(warm), mutation.name)
+		previous = rescanned
+	}
+}
+
+// cachedReferences are the packages of a module whose models refer to the types of packages they don't import:
+// the pet of a union of types, and the implementations of an interface, which embed a discriminated base.
+var cachedReferences = map[string]string{
+	"go.mod": "module example.com/refs\n\ngo 1.22\n",
+	"shop/shop.go": `package shop
+
+// Entry of the shop.
+//
+// swagger:model
+type Entry struct {
+	// Types: string, pets.Dog
+	Pet any ` + "`json:\"pet\"`" + `
+}
+
+// Notification is implemented by the events.
+type Notification interface {
+	Notify()
+}
+
+// Feed of the notifications.
+//
+// swagger:model
+type Feed struct {
+	Items []Notification ` + "`json:\"items\"`" + `
+}
+`,
+	"pets/pets.go": petsSource,
+	"events/events.go": `package events
+
+// Event is the base of the events.
+//
+// swagger:model
+type Event struct {
+	// swagger:discriminator
+	Type string ` + "`json:\"type\"`" + `
+}
+`,
+	"users/users.go": usersSource,
+}
+
+const petsSource = `package pets
+
+// Dog is a pet.
+//
+// swagger:model Dog
+type Dog struct {
+	Name string ` + "`json:\"name\"`" + `
+}
+`
+
+const usersSource = `package users
+
+import "example.com/refs/events"
+
+// UserCreated is the event of a new user.
+//
+// swagger:discriminator user.created
+type UserCreated struct {
+	events.Event
+
+	Name string ` + "`json:\"name\"`" + `
+}
+
+func (UserCreated) Notify() {}
+`
+
+// TestScanCacheReferences changes the packages which the models of another package refer to without importing
+// them: the models can't be read from the cache, since the fingerprint of their package doesn't cover them.
+func TestScanCacheReferences(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range cachedReferences {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+	cacheDir := t.TempDir()
+	scan := func(t *testing.T, cached bool) ([]byte, Stats) {
+		t.Helper()
+		var stats Stats
+		opts := Options{Packages: []string{"./..."}, WorkDir: dir, ScanModels: true, Stats: &stats}
+		if cached {
+			opts.CacheDir = cacheDir
+		}
+		doc, err := Run(&opts)
+		require.NoError(t, err)
+		jazon, err := json.MarshalIndent(doc, "", "  ")
+		require.NoError(t, err)
+		return jazon, stats
+	}
+
+	for _, mutation := range []struct {
+		name, file, source, old, new string
+		model, ref                   string
+	}{
+		{
+			name: "the renamed model of a union", file: "pets/pets.go", source: petsSource,
+			old: "swagger:model Dog", new: "swagger:model Hound",
+			model: "Entry", ref: "#/definitions/Hound",
+		},
+		{
+			name: "the removed implementation of an interface", file: "users/users.go", source: usersSource,
+			old: "func (UserCreated) Notify() {}", new: "",
+			model: "Feed",
+		},
+	} {
+		t.Run("should rebuild the models referring to "+mutation.name, func(t *testing.T) {
+			_, stats := scan(t, true)
+			require.False(t, stats.Cached)
+
+			mutated := strings.Replace(mutation.source, mutation.old, mutation.new, 1)
+			require.NotEqual(t, mutation.source, mutated)
+			require.NoError(t, os.WriteFile(filepath.Join(dir, mutation.file), []byte(mutated), 0o600))
+			t.Cleanup(func() {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, mutation.file), []byte(mutation.source), 0o600))
+			})
+
+			rescanned, stats := scan(t, true)
+			assert.False(t, stats.Cached)
+			assert.NotContains(t, stats.ReusedModels, mutation.model, "the package of %s doesn't import the changed package", mutation.model)
+
+			cold, _ := scan(t, false)
+			assert.Equal(t, string(cold), string(rescanned), "the cached scan is the scan of a cold cache")
+			if mutation.ref != "" {
+				assert.Contains(t, string(rescanned), mutation.ref, "the union refers to the renamed model")
+			}
+		})
+	}
+}
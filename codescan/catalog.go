@@ -0,0 +1,135 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+const xUntranslated = "x-untranslated"
+
+// stringVisitor is called for every translatable string of a spec, with the JSON pointer locating it
+// (e.g. "/paths/~1pets/get/summary") and the element holding it.
+type stringVisitor func(text *string, pointer string, holder *spec.VendorExtensible)
+
+// ExtractStrings returns the translatable strings of a spec by JSON pointer: titles, summaries and
+// descriptions. The result is the source of a catalog for Options.DescriptionCatalog.
+func ExtractStrings(doc *spec.Swagger) map[string]string {
+	strs := make(map[string]string)
+	walkSpecStrings(doc, func(text *string, pointer string, _ *spec.VendorExtensible) {
+		strs[pointer] = *text
+	})
+	return strs
+}
+
+// translateStrings replaces the strings of a spec with their translation from a catalog, see
+// Options.DescriptionCatalog. The elements holding untranslated strings get an x-untranslated extension,
+// when markUntranslated is set.
+func translateStrings(doc *spec.Swagger, catalog map[string]string, markUntranslated bool) {
+	used := make(map[string]bool, len(catalog))
+	walkSpecStrings(doc, func(text *string, pointer string, holder *spec.VendorExtensible) {
+		translation, ok := catalog[pointer]
+		if !ok || translation == "" {
+			if markUntranslated {
+				holder.AddExtension(xUntranslated, true)
+			}
+			return
+		}
+		used[pointer] = true
+		*text = translation
+	})
+
+	for _, pointer := range sortedKeys(catalog) {
+		if !used[pointer] {
+			log.Printf("WARNING: description catalog entry %s matches no string of the spec", pointer)
+		}
+	}
+}
+
+// walkSpecStrings visits the non-empty translatable strings of a spec, in a deterministic order.
+func walkSpecStrings(doc *spec.Swagger, visit stringVisitor) {
+	if doc == nil {
+		return
+	}
+	text := func(value *string, pointer string, holder *spec.VendorExtensible) {
+		if *value != "" {
+			visit(value, pointer, holder)
+		}
+	}
+
+	if doc.Info != nil {
+		text(&doc.Info.Title, "/info/title", &doc.Info.VendorExtensible)
+		text(&doc.Info.Description, "/info/description", &doc.Info.VendorExtensible)
+	}
+	for i := range doc.Tags {
+		text(&doc.Tags[i].Description, "/tags/"+strconv.Itoa(i)+"/description", &doc.Tags[i].VendorExtensible)
+	}
+	for _, name := range sortedKeys(doc.SecurityDefinitions) {
+		if scheme := doc.SecurityDefinitions[name]; scheme != nil {
+			text(&scheme.Description, "/securityDefinitions/"+escapePointer(name)+"/description", &scheme.VendorExtensible)
+		}
+	}
+
+	walkSpecSchemas(doc, func(schema *spec.Schema, location string) {
+		pointer := strings.TrimPrefix(location, "#")
+		text(&schema.Title, pointer+"/title", &schema.VendorExtensible)
+		text(&schema.Description, pointer+"/description", &schema.VendorExtensible)
+	})
+
+	params := func(params []spec.Parameter, pointer string) {
+		for i := range params {
+			text(&params[i].Description, pointer+"/"+strconv.Itoa(i)+"/description", &params[i].VendorExtensible)
+		}
+	}
+	response := func(resp *spec.Response, pointer string) {
+		text(&resp.Description, pointer+"/description", &resp.VendorExtensible)
+		for _, name := range sortedKeys(resp.Headers) {
+			header := resp.Headers[name]
+			text(&header.Description, pointer+"/headers/"+escapePointer(name)+"/description", &header.VendorExtensible)
+			resp.Headers[name] = header
+		}
+	}
+
+	for _, name := range sortedKeys(doc.Parameters) {
+		param := doc.Parameters[name]
+		text(&param.Description, "/parameters/"+escapePointer(name)+"/description", &param.VendorExtensible)
+		doc.Parameters[name] = param
+	}
+	for _, name := range sortedKeys(doc.Responses) {
+		resp := doc.Responses[name]
+		response(&resp, "/responses/"+escapePointer(name))
+		doc.Responses[name] = resp
+	}
+
+	if doc.Paths == nil {
+		return
+	}
+	for _, pth := range sortedKeys(doc.Paths.Paths) {
+		pathItem := doc.Paths.Paths[pth]
+		pointer := "/paths/" + escapePointer(pth)
+		params(pathItem.Parameters, pointer+"/parameters")
+		for method, op := range pathItemOperations(&pathItem) {
+			opPointer := pointer + "/" + method
+			text(&op.Summary, opPointer+"/summary", &op.VendorExtensible)
+			text(&op.Description, opPointer+"/description", &op.VendorExtensible)
+			params(op.Parameters, opPointer+"/parameters")
+			if op.Responses == nil {
+				continue
+			}
+			if op.Responses.Default != nil {
+				response(op.Responses.Default, opPointer+"/responses/default")
+			}
+			for _, code := range sortedKeys(op.Responses.StatusCodeResponses) {
+				resp := op.Responses.StatusCodeResponses[code]
+				response(&resp, opPointer+"/responses/"+strconv.Itoa(code))
+				op.Responses.StatusCodeResponses[code] = resp
+			}
+		}
+		doc.Paths.Paths[pth] = pathItem
+	}
+}
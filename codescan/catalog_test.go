@@ -0,0 +1,58 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescriptionCatalog(t *testing.T) {
+	const packages = "github.com/3idey/codescan/fixtures/goparsing/petstore/..."
+
+	doc, err := Run(&Options{Packages: []string{packages}})
+	require.NoError(t, err)
+	strs := ExtractStrings(doc)
+	assert.Equal(t, "Petstore API.", strs["/info/title"])
+	assert.Equal(t, "The name of the pet.", strs["/definitions/pet/properties/name/description"])
+	assert.Contains(t, strs, "/paths/~1pets/get/summary")
+	assert.Contains(t, strs, "/responses/genericError/description")
+	for pointer, text := range strs {
+		assert.NotEmpty(t, text, pointer)
+	}
+
+	t.Run("should translate the strings of the catalog", func(t *testing.T) {
+		translated, err := Run(&Options{
+			Packages: []string{packages},
+			DescriptionCatalog: map[string]string{
+				"/info/title": "ペットストア API",
+				"/definitions/pet/properties/name/description": "ペットの名前",
+				"/paths/~1pets/get/summary":                    "ペットの一覧",
+			},
+			MarkUntranslated: true,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "ペットストア API", translated.Info.Title)
+		pet := translated.Definitions["pet"]
+		name := pet.Properties["name"]
+		assert.Equal(t, "ペットの名前", name.Description)
+		assert.NotContains(t, name.Extensions, xUntranslated)
+		assert.Contains(t, pet.Extensions, xUntranslated, "the description of pet is not translated")
+
+		pets := translated.Paths.Paths["/pets"]
+		assert.Equal(t, "ペットの一覧", pets.Get.Summary)
+		assert.Contains(t, pets.Get.Extensions, xUntranslated, "the description of the operation is not translated")
+	})
+
+	t.Run("should keep the source language without marking it", func(t *testing.T) {
+		translated, err := Run(&Options{Packages: []string{packages}, DescriptionCatalog: map[string]string{}})
+		require.NoError(t, err)
+		assert.Equal(t, strs, ExtractStrings(translated))
+		pet := translated.Definitions["pet"]
+		assert.NotContains(t, pet.Extensions, xUntranslated)
+	})
+}
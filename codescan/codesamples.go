@@ -0,0 +1,503 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/go-openapi/spec"
+)
+
+const (
+	xCodeSamples = "x-codeSamples"
+
+	// maxSampleDepth bounds the nesting of the synthesized body examples.
+	maxSampleDepth = 8
+)
+
+// CodeSampleRequest describes the request of an operation, as used by the code sample templates.
+type CodeSampleRequest struct {
+	OperationID string
+	Method      string            // e.g. "POST"
+	URL         string            // absolute URL, with the path and query parameters filled in
+	Headers     []CodeSampleField // header parameters, content type and credentials
+	Form        []CodeSampleField // formData parameters
+	Multipart   bool              // Form is sent as multipart/form-data, rather than urlencoded
+	Body        string            // indented JSON example of the body, empty without a body parameter
+	BasicAuth   bool              // the operation authenticates with HTTP basic auth
+}
+
+// CodeSampleField is a named value of a request, e.g. a header.
+type CodeSampleField struct {
+	Name  string
+	Value string
+	File  bool // the value is sent as the content of a file (formData parameter of type file)
+}
+
+// codeSampleLanguage is a language of x-codeSamples entries, rendered with a template.
+type codeSampleLanguage struct {
+	key      string // name in Options.CodeSampleTemplates
+	lang     string // language of the entry, used for syntax highlighting
+	label    string
+	template *template.Template
+}
+
+var codeSampleFuncs = template.FuncMap{
+	// shellQuote quotes a value for a POSIX shell
+	"shellQuote": func(value string) string {
+		return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+	},
+	// quote quotes a value as a Go string literal
+	"quote": strconv.Quote,
+	// backquote quotes a multi-line value as a Go raw string literal, when possible
+	"backquote": func(value string) string {
+		if strings.Contains(value, "`") {
+			return strconv.Quote(value)
+		}
+		return "`" + value + "`"
+	},
+}
+
+const curlSampleTemplate = `curl -X {{ .Method }} {{ shellQuote .URL }}
+{{- if .BasicAuth }} \
+  -u '<username>:<password>'
+{{- end }}
+{{- range .Headers }} \
+  -H {{ shellQuote (printf "%s: %s" .Name .Value) }}
+{{- end }}
+{{- $multipart := .Multipart }}
+{{- range .Form }} \
+  {{ if $multipart }}-F {{ shellQuote (printf "%s=%s%s" .Name (or (and .File "@") "") .Value) }}{{ else }}--data-urlencode {{ shellQuote (printf "%s=%s" .Name .Value) }}{{ end }}
+{{- end }}
+{{- if .Body }} \
+  -d {{ shellQuote .Body }}
+{{- end }}
+`
+
+const goSampleTemplate = `package main
+
+import (
+{{- if .Body }}
+	"bytes"
+{{- end }}
+	"fmt"
+	"io"
+{{- if .Multipart }}
+	"mime/multipart"
+{{- end }}
+	"net/http"
+{{- if and .Form (not .Multipart) }}
+	"net/url"
+	"strings"
+{{- end }}
+)
+
+func main() {
+{{- if .Body }}
+	body := bytes.NewBufferString({{ backquote .Body }})
+{{- else if .Multipart }}
+	body := new(bytes.Buffer)
+	form := multipart.NewWriter(body)
+{{- range .Form }}
+	{{- if .File }}
+	if part, err := form.CreateFormFile({{ quote .Name }}, {{ quote .Value }}); err == nil {
+		part.Write([]byte("..."))
+	}
+	{{- else }}
+	form.WriteField({{ quote .Name }}, {{ quote .Value }})
+	{{- end }}
+{{- end }}
+	form.Close()
+{{- else if .Form }}
+	form := url.Values{}
+{{- range .Form }}
+	form.Set({{ quote .Name }}, {{ quote .Value }})
+{{- end }}
+	body := strings.NewReader(form.Encode())
+{{- end }}
+	req, err := http.NewRequest({{ quote .Method }}, {{ quote .URL }}, {{ if or .Body .Form }}body{{ else }}nil{{ end }})
+	if err != nil {
+		panic(err)
+	}
+{{- if .Multipart }}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+{{- end }}
+{{- range .Headers }}
+	req.Header.Set({{ quote .Name }}, {{ quote .Value }})
+{{- end }}
+{{- if .BasicAuth }}
+	req.SetBasicAuth("<username>", "<password>")
+{{- end }}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		panic(err)
+	}
+	defer resp.Body.Close()
+
+	payload, _ := io.ReadAll(resp.Body)
+	fmt.Println(resp.Status, string(payload))
+}
+`
+
+// codeSampleLanguages parses the templates of the code samples: the built-in curl and go languages,
+// unless replaced, followed by the custom languages sorted by name.
+func codeSampleLanguages(templates map[string]string) ([]codeSampleLanguage, error) {
+	builtins := []struct{ key, lang, label, source string }{
+		{"curl", "Shell", "curl", curlSampleTemplate},
+		{"go", "Go", "Go", goSampleTemplate},
+	}
+
+	languages := make([]codeSampleLanguage, 0, len(builtins)+len(templates))
+	for _, builtin := range builtins {
+		source := builtin.source
+		if custom, ok := templates[builtin.key]; ok {
+			source = custom
+		}
+		tpl, err := template.New(builtin.key).Funcs(codeSampleFuncs).Parse(source)
+		if err != nil {
+			return nil, fmt.Errorf("code sample template %s: %w", builtin.key, err)
+		}
+		languages = append(languages, codeSampleLanguage{key: builtin.key, lang: builtin.lang, label: builtin.label, template: tpl})
+	}
+
+	for _, key := range sortedKeys(templates) {
+		if key == "curl" || key == "go" {
+			continue
+		}
+		tpl, err := template.New(key).Funcs(codeSampleFuncs).Parse(templates[key])
+		if err != nil {
+			return nil, fmt.Errorf("code sample template %s: %w", key, err)
+		}
+		languages = append(languages, codeSampleLanguage{key: key, lang: key, label: key, template: tpl})
+	}
+
+	return languages, nil
+}
+
+// applyCodeSamples attaches x-codeSamples entries to the operations, see Options.CodeSamples.
+//
+// The generated entries replace the entries of the same language, e.g. from a previous generation
+// given as input spec. Entries of other languages are kept.
+func (s *specBuilder) applyCodeSamples() error {
+	if !s.ctx.opts.CodeSamples || s.input.Paths == nil {
+		return nil
+	}
+
+	languages, err := codeSampleLanguages(s.ctx.opts.CodeSampleTemplates)
+	if err != nil {
+		return err
+	}
+
+	for _, pth := range sortedKeys(s.input.Paths.Paths) {
+		pathItem := s.input.Paths.Paths[pth]
+		for method, op := range pathItemOperations(&pathItem) {
+			req := newCodeSampleRequest(s.input, pth, method, &pathItem, op)
+
+			var samples []any
+			generated := make(map[string]bool, len(languages))
+			for _, language := range languages {
+				var source bytes.Buffer
+				if err := language.template.Execute(&source, req); err != nil {
+					return fmt.Errorf("code sample %s for %s %s: %w", language.key, strings.ToUpper(method), pth, err)
+				}
+				generated[language.lang] = true
+				samples = append(samples, map[string]any{
+					"lang":   language.lang,
+					"label":  language.label,
+					"source": source.String(),
+				})
+			}
+
+			// the portals expect the camel case key, which AddExtension would lower
+			for _, key := range []string{xCodeSamples, strings.ToLower(xCodeSamples)} {
+				existing, _ := op.Extensions[key].([]any)
+				for _, sample := range existing {
+					if entry, isEntry := sample.(map[string]any); isEntry && generated[fmt.Sprint(entry["lang"])] {
+						continue
+					}
+					samples = append(samples, sample)
+				}
+				delete(op.Extensions, key)
+			}
+			if op.Extensions == nil {
+				op.Extensions = make(spec.Extensions)
+			}
+			op.Extensions[xCodeSamples] = samples
+		}
+		s.input.Paths.Paths[pth] = pathItem
+	}
+
+	return nil
+}
+
+// newCodeSampleRequest describes the request of an operation, with example values for its parameters.
+func newCodeSampleRequest(doc *spec.Swagger, pth, method string, pathItem *spec.PathItem, op *spec.Operation) *CodeSampleRequest {
+	req := &CodeSampleRequest{
+		OperationID: op.ID,
+		Method:      strings.ToUpper(method),
+	}
+
+	query := url.Values{}
+	var hasFile bool
+	for _, param := range operationParameters(doc, pathItem, op) {
+		switch param.In {
+		case "path":
+			pth = strings.ReplaceAll(pth, "{"+param.Name+"}", url.PathEscape(paramSample(&param)))
+		case "query":
+			if param.Required {
+				query.Set(param.Name, paramSample(&param))
+			}
+		case "header":
+			if param.Required {
+				req.Headers = append(req.Headers, CodeSampleField{Name: param.Name, Value: paramSample(&param)})
+			}
+		case "formData":
+			file := param.Type == "file"
+			hasFile = hasFile || file
+			value := paramSample(&param)
+			if file {
+				value = param.Name
+			}
+			req.Form = append(req.Form, CodeSampleField{Name: param.Name, Value: value, File: file})
+		case "body":
+			if param.Schema != nil {
+				jazon, err := json.MarshalIndent(schemaSample(doc, param.Schema, 0), "", "  ")
+				if err == nil {
+					req.Body = string(jazon)
+				}
+			}
+		}
+	}
+
+	consumes := op.Consumes
+	if len(consumes) == 0 {
+		consumes = doc.Consumes
+	}
+	req.Multipart = hasFile || slices.Contains(consumes, "multipart/form-data")
+	switch {
+	case req.Body != "":
+		req.Headers = append(req.Headers, CodeSampleField{Name: "Content-Type", Value: firstMediaType(consumes, "application/json")})
+	case len(req.Form) > 0 && !req.Multipart:
+		req.Headers = append(req.Headers, CodeSampleField{Name: "Content-Type", Value: "application/x-www-form-urlencoded"})
+	}
+
+	security := op.Security
+	if security == nil {
+		security = doc.Security
+	}
+	if len(security) > 0 {
+		for _, name := range sortedKeys(security[0]) {
+			scheme, ok := doc.SecurityDefinitions[name]
+			if !ok || scheme == nil {
+				continue
+			}
+			switch scheme.Type {
+			case "basic":
+				req.BasicAuth = true
+			case "apiKey":
+				if scheme.In == "query" {
+					query.Set(scheme.Name, "<api-key>")
+				} else {
+					req.Headers = append(req.Headers, CodeSampleField{Name: scheme.Name, Value: "<api-key>"})
+				}
+			case "oauth2":
+				req.Headers = append(req.Headers, CodeSampleField{Name: "Authorization", Value: "Bearer <token>"})
+			}
+		}
+	}
+
+	schemes := op.Schemes
+	if len(schemes) == 0 {
+		schemes = doc.Schemes
+	}
+	scheme := "https"
+	if len(schemes) > 0 {
+		scheme = schemes[0]
+	}
+	host := doc.Host
+	if host == "" {
+		host = "localhost"
+	}
+	req.URL = scheme + "://" + host + strings.TrimSuffix(doc.BasePath, "/") + pth
+	if len(query) > 0 {
+		req.URL += "?" + query.Encode()
+	}
+
+	return req
+}
+
+// operationParameters returns the parameters of an operation, completed with the ones of its path.
+func operationParameters(doc *spec.Swagger, pathItem *spec.PathItem, op *spec.Operation) []spec.Parameter {
+	resolve := func(param spec.Parameter) spec.Parameter {
+		if name, ok := strings.CutPrefix(refFragment(param.Ref), "/parameters/"); ok {
+			if shared, found := doc.Parameters[name]; found {
+				return shared
+			}
+		}
+		return param
+	}
+
+	params := make([]spec.Parameter, 0, len(pathItem.Parameters)+len(op.Parameters))
+	for _, param := range op.Parameters {
+		params = append(params, resolve(param))
+	}
+	for _, param := range pathItem.Parameters {
+		param = resolve(param)
+		if !slices.ContainsFunc(params, func(p spec.Parameter) bool { return p.Name == param.Name && p.In == param.In }) {
+			params = append(params, param)
+		}
+	}
+
+	return params
+}
+
+// refFragment returns the JSON pointer of a ref, e.g. "/definitions/Pet" for "swagger.json#/definitions/Pet".
+func refFragment(ref spec.Ref) string {
+	_, fragment, _ := strings.Cut(ref.String(), "#")
+	return fragment
+}
+
+func firstMediaType(mediaTypes []string, fallback string) string {
+	if len(mediaTypes) == 0 {
+		return fallback
+	}
+	return mediaTypes[0]
+}
+
+// paramSample returns an example value for a non-body parameter.
+func paramSample(param *spec.Parameter) string {
+	value := simpleSample(&param.SimpleSchema, param.Example, param.Enum)
+	if values, ok := value.([]any); ok {
+		parts := make([]string, 0, len(values))
+		for _, v := range values {
+			parts = append(parts, fmt.Sprint(v))
+		}
+		return strings.Join(parts, ",")
+	}
+	return fmt.Sprint(value)
+}
+
+func simpleSample(simple *spec.SimpleSchema, example any, enum []any) any {
+	switch {
+	case example != nil:
+		return example
+	case simple.Default != nil:
+		return simple.Default
+	case len(enum) > 0:
+		return enum[0]
+	case simple.Type == "array" && simple.Items != nil:
+		return []any{simpleSample(&simple.Items.SimpleSchema, simple.Items.Example, simple.Items.Enum)}
+	default:
+		return typeSample(simple.Type, simple.Format)
+	}
+}
+
+// schemaSample synthesizes an example value from a schema, resolving the refs to definitions.
+// Read-only properties are left out, as the sample is a request body.
+func schemaSample(doc *spec.Swagger, schema *spec.Schema, depth int) any {
+	if depth > maxSampleDepth {
+		return nil
+	}
+	if schema.Ref.String() != "" {
+		name, ok := strings.CutPrefix(refFragment(schema.Ref), "/definitions/")
+		if !ok {
+			return map[string]any{}
+		}
+		definition, found := doc.Definitions[name]
+		if !found {
+			return map[string]any{}
+		}
+		return schemaSample(doc, &definition, depth+1)
+	}
+
+	switch {
+	case schema.Example != nil:
+		return schema.Example
+	case schema.Default != nil:
+		return schema.Default
+	case len(schema.Enum) > 0:
+		return schema.Enum[0]
+	}
+
+	if len(schema.AllOf) > 0 {
+		merged := make(map[string]any)
+		for i := range schema.AllOf {
+			if member, ok := schemaSample(doc, &schema.AllOf[i], depth+1).(map[string]any); ok {
+				for key, value := range member {
+					merged[key] = value
+				}
+			}
+		}
+		for key, value := range propertiesSample(doc, schema, depth) {
+			merged[key] = value
+		}
+		return merged
+	}
+
+	var typ string
+	if len(schema.Type) > 0 {
+		typ = schema.Type[0]
+	}
+	switch {
+	case typ == "array":
+		if schema.Items == nil || schema.Items.Schema == nil {
+			return []any{}
+		}
+		return []any{schemaSample(doc, schema.Items.Schema, depth+1)}
+	case typ == "object" || typ == "" && (len(schema.Properties) > 0 || schema.AdditionalProperties != nil):
+		sample := propertiesSample(doc, schema, depth)
+		if len(sample) == 0 && schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil {
+			sample["key"] = schemaSample(doc, schema.AdditionalProperties.Schema, depth+1)
+		}
+		return sample
+	case typ == "":
+		return map[string]any{}
+	default:
+		return typeSample(typ, schema.Format)
+	}
+}
+
+func propertiesSample(doc *spec.Swagger, schema *spec.Schema, depth int) map[string]any {
+	sample := make(map[string]any, len(schema.Properties))
+	for name, property := range schema.Properties {
+		if property.ReadOnly {
+			continue
+		}
+		sample[name] = schemaSample(doc, &property, depth+1)
+	}
+	return sample
+}
+
+// typeSample returns an example value for a primitive type.
+func typeSample(typ, format string) any {
+	switch typ {
+	case "integer", "number":
+		return 0
+	case "boolean":
+		return true
+	case "file":
+		return "file"
+	}
+	switch format {
+	case "date-time":
+		return "2006-01-02T15:04:05Z"
+	case "date":
+		return "2006-01-02"
+	case "uuid":
+		return "3fa85f64-5717-4562-b3fc-2c963f66afa6"
+	case "email":
+		return "user@example.com"
+	case "uri":
+		return "https://example.com"
+	}
+	return "string"
+}
@@ -0,0 +1,146 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func codeSamples(t *testing.T, op *spec.Operation) map[string]string {
+	t.Helper()
+	entries, ok := op.Extensions[xCodeSamples].([]any)
+	require.True(t, ok, "missing %s", xCodeSamples)
+
+	sources := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		sample := entry.(map[string]any)
+		sources[sample["lang"].(string)] = sample["source"].(string)
+	}
+	return sources
+}
+
+func TestCodeSamples(t *testing.T) {
+	doc, err := Run(&Options{
+		Packages:    []string{"github.com/3idey/codescan/fixtures/goparsing/petstore/..."},
+		CodeSamples: true,
+		CodeSampleTemplates: map[string]string{
+			"python": `requests.request({{ printf "%q" .Method }}, {{ printf "%q" .URL }})`,
+		},
+	})
+	require.NoError(t, err)
+
+	for pth, pathItem := range doc.Paths.Paths {
+		for method, op := range pathItemOperations(&pathItem) {
+			samples := codeSamples(t, op)
+			assert.Len(t, samples, 3, "%s %s", method, pth)
+			_, err := parser.ParseFile(token.NewFileSet(), "main.go", samples["Go"], 0)
+			assert.NoError(t, err, "%s %s: the Go sample must compile", method, pth)
+		}
+	}
+
+	orders := doc.Paths.Paths["/orders"]
+	samples := codeSamples(t, orders.Post)
+	assert.Contains(t, samples["Shell"], "curl -X POST 'http://localhost/v2/orders'")
+	assert.Contains(t, samples["Shell"], "-H 'Content-Type: application/json'")
+	assert.Contains(t, samples["Shell"], `"orderedAt": "2006-01-02T15:04:05Z"`)
+	assert.Contains(t, samples["Go"], `http.NewRequest("POST", "http://localhost/v2/orders", body)`)
+	assert.Equal(t, `requests.request("POST", "http://localhost/v2/orders")`, samples["python"])
+
+	order := doc.Paths.Paths["/orders/{id}"]
+	assert.Contains(t, codeSamples(t, order.Get)["Shell"], "'http://localhost/v2/orders/0'")
+}
+
+func TestCodeSampleRequest(t *testing.T) {
+	doc := &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+		Host:     "api.acme.com",
+		BasePath: "/v1",
+		Schemes:  []string{"https"},
+		SecurityDefinitions: spec.SecurityDefinitions{
+			"key":   spec.APIKeyAuth("X-API-Key", "header"),
+			"basic": spec.BasicAuth(),
+		},
+		Security: []map[string][]string{{"key": {}}},
+	}}
+
+	team := spec.PathParam("team").Typed("string", "")
+	upload := new(spec.Operation)
+	upload.Consumes = []string{"multipart/form-data"}
+	upload.Security = []map[string][]string{{"basic": {}}}
+	upload.Parameters = []spec.Parameter{
+		*spec.FileParam("avatar"),
+		*spec.FormDataParam("name").Typed("string", "").WithDefault("Rex"),
+		*spec.QueryParam("dryRun").Typed("boolean", "").AsRequired(),
+		*spec.QueryParam("tags").CollectionOf(spec.NewItems().Typed("string", ""), "csv"),
+	}
+	pathItem := &spec.PathItem{PathItemProps: spec.PathItemProps{Post: upload, Parameters: []spec.Parameter{*team}}}
+
+	req := newCodeSampleRequest(doc, "/teams/{team}/avatar", "post", pathItem, upload)
+	assert.Equal(t, "https://api.acme.com/v1/teams/string/avatar?dryRun=true", req.URL)
+	assert.True(t, req.Multipart)
+	assert.True(t, req.BasicAuth)
+	assert.Empty(t, req.Headers)
+	assert.Equal(t, []CodeSampleField{
+		{Name: "avatar", Value: "avatar", File: true},
+		{Name: "name", Value: "Rex"},
+	}, req.Form)
+
+	languages, err := codeSampleLanguages(nil)
+	require.NoError(t, err)
+	var curl, goSample string
+	for _, language := range languages {
+		var source bytes.Buffer
+		require.NoError(t, language.template.Execute(&source, req))
+		switch language.key {
+		case "curl":
+			curl = source.String()
+		case "go":
+			goSample = source.String()
+		}
+	}
+	assert.Contains(t, curl, "-u '<username>:<password>'")
+	assert.Contains(t, curl, "-F 'avatar=@avatar'")
+	assert.Contains(t, curl, "-F 'name=Rex'")
+	_, err = parser.ParseFile(token.NewFileSet(), "main.go", goSample, 0)
+	require.NoError(t, err)
+	assert.Contains(t, goSample, `form.CreateFormFile("avatar", "avatar")`)
+
+	t.Run("api keys come from the global security", func(t *testing.T) {
+		get := new(spec.Operation)
+		req := newCodeSampleRequest(doc, "/teams", "get", &spec.PathItem{}, get)
+		assert.Equal(t, []CodeSampleField{{Name: "X-API-Key", Value: "<api-key>"}}, req.Headers)
+	})
+}
+
+func TestCodeSamplesKeepOtherLanguages(t *testing.T) {
+	input := &spec.Swagger{}
+	input.Paths = &spec.Paths{Paths: map[string]spec.PathItem{}}
+	op := new(spec.Operation)
+	op.Extensions = spec.Extensions{xCodeSamples: []any{
+		map[string]any{"lang": "Shell", "source": "curl stale"},
+		map[string]any{"lang": "Ruby", "source": "Net::HTTP.get(uri)"},
+	}}
+	input.Paths.Paths["/ping"] = spec.PathItem{PathItemProps: spec.PathItemProps{Get: op}}
+
+	sb := &specBuilder{input: input, ctx: &scanCtx{opts: &Options{CodeSamples: true}}}
+	require.NoError(t, sb.applyCodeSamples())
+
+	samples := codeSamples(t, input.Paths.Paths["/ping"].Get)
+	assert.Equal(t, "curl -X GET 'https://localhost/ping'\n", samples["Shell"])
+	assert.Equal(t, "Net::HTTP.get(uri)", samples["Ruby"])
+	assert.Contains(t, samples, "Go")
+}
+
+func TestCodeSampleTemplatesValidation(t *testing.T) {
+	err := (&Options{CodeSamples: true, CodeSampleTemplates: map[string]string{"python": "{{ .Method"}}).Validate()
+	var invalid *InvalidOptionError
+	require.ErrorAs(t, err, &invalid)
+	assert.Equal(t, "CodeSampleTemplates", invalid.Option)
+}
@@ -0,0 +1,157 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Compatibility modes of the annotations, see Options.Compat.
+const (
+	// CompatLegacyGoSwagger recognizes the spellings of the annotations of older go-swagger code, e.g.
+	// swagger:params, +swagger:model and @swagger:route, reported as legacy-annotation diagnostics.
+	CompatLegacyGoSwagger = "legacy-go-swagger"
+)
+
+var compatModes = []string{CompatLegacyGoSwagger}
+
+// legacyVerbs are the canonical verbs of the legacy spellings of annotations.
+var legacyVerbs = map[string]string{
+	"params": "parameters",
+}
+
+// rxLegacyAnnotation matches the annotations with their legacy prefix, i.e. the + of the build-tag-like
+// markers and the @ of the javadoc-like ones, e.g. +swagger:model or @swagger:route.
+var rxLegacyAnnotation = regexp.MustCompile(`(^|[^\p{L}\p{N}\p{Pd}\p{Pc}+@])([+@]?)swagger:([\p{L}\p{N}\p{Pd}\p{Pc}]+)`)
+
+func checkCompat(modes []string) error {
+	for _, mode := range modes {
+		if !slices.Contains(compatModes, mode) {
+			return fmt.Errorf("unknown compatibility mode %q, expected %s", mode, strings.Join(compatModes, " or "))
+		}
+	}
+	return nil
+}
+
+// LegacyAnnotation is an annotation spelled the legacy way, see Options.Compat and MigrateAnnotations.
+type LegacyAnnotation struct {
+	Pos       token.Position
+	Legacy    string // e.g. +swagger:model or swagger:params
+	Canonical string // e.g. swagger:model or swagger:parameters
+}
+
+// canonicalAnnotations rewrites the legacy annotations of the text of a comment to their canonical
+// spelling, and returns them with their positions, from that of the start of the text.
+func canonicalAnnotations(text string, start token.Position) (string, []LegacyAnnotation) {
+	var found []LegacyAnnotation
+	var rewritten strings.Builder
+	last := 0
+	for _, match := range rxLegacyAnnotation.FindAllStringSubmatchIndex(text, -1) {
+		prefix, verb := text[match[4]:match[5]], text[match[6]:match[7]]
+		canonical, legacyVerb := legacyVerbs[verb]
+		if !legacyVerb {
+			canonical = verb
+		}
+		if prefix == "" && !legacyVerb {
+			continue
+		}
+		found = append(found, LegacyAnnotation{
+			Pos:       offsetPosition(text, start, match[4]),
+			Legacy:    text[match[4]:match[7]],
+			Canonical: "swagger:" + canonical,
+		})
+		rewritten.WriteString(text[last:match[4]])
+		rewritten.WriteString("swagger:" + canonical)
+		last = match[7]
+	}
+	if found == nil {
+		return text, nil
+	}
+	rewritten.WriteString(text[last:])
+	return rewritten.String(), found
+}
+
+// offsetPosition returns the position of a byte offset of a text starting at a position.
+func offsetPosition(text string, start token.Position, offset int) token.Position {
+	pos := start
+	pos.Offset += offset
+	if newline := strings.LastIndexByte(text[:offset], '\n'); newline >= 0 {
+		pos.Line += strings.Count(text[:offset], "\n")
+		pos.Column = offset - newline
+	} else {
+		pos.Column += offset
+	}
+	return pos
+}
+
+// canonicalizeComment rewrites the legacy annotations of a comment before it is classified and parsed, with
+// a legacy-annotation diagnostic for each of them.
+func (a *typeIndex) canonicalizeComment(fset *token.FileSet, cmt *ast.Comment) {
+	text, found := canonicalAnnotations(cmt.Text, fset.Position(cmt.Slash))
+	for _, legacy := range found {
+		a.diagnose(Diagnostic{
+			Pos:     legacy.Pos,
+			Code:    DiagnosticLegacyAnnotation,
+			Message: fmt.Sprintf("%s is the legacy spelling of %s, see codescan migrate-annotations", legacy.Legacy, legacy.Canonical),
+		})
+	}
+	cmt.Text = text
+}
+
+// MigrateAnnotations rewrites the legacy annotations of the comments of a Go source file to their canonical
+// spelling, e.g. swagger:params to swagger:parameters, recognized by Options.Compat. Only the comments are
+// edited, so that a formatted file stays formatted. It returns the source unchanged without legacy annotations.
+func MigrateAnnotations(filename string, src []byte) ([]byte, []LegacyAnnotation, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var migrated bytes.Buffer
+	var found []LegacyAnnotation
+	last := 0
+	for _, group := range file.Comments {
+		for _, cmt := range group.List {
+			start := fset.Position(cmt.Slash)
+			end := commentEnd(src, start.Offset)
+			text, legacy := canonicalAnnotations(string(src[start.Offset:end]), start)
+			if legacy == nil {
+				continue
+			}
+			found = append(found, legacy...)
+			migrated.Write(src[last:start.Offset])
+			migrated.WriteString(text)
+			last = end
+		}
+	}
+	if found == nil {
+		return src, nil, nil
+	}
+	migrated.Write(src[last:])
+	return migrated.Bytes(), found, nil
+}
+
+// commentEnd returns the offset of the end of the comment at an offset of a source, whose text may differ
+// from that of ast.Comment, which drops the carriage returns.
+func commentEnd(src []byte, offset int) int {
+	if bytes.HasPrefix(src[offset:], []byte("/*")) {
+		if end := bytes.Index(src[offset+2:], []byte("*/")); end >= 0 {
+			return offset + 2 + end + 2
+		}
+		return len(src)
+	}
+	end := bytes.IndexByte(src[offset:], '\n')
+	if end < 0 {
+		return len(src)
+	}
+	return offset + len(bytes.TrimSuffix(src[offset:offset+end], []byte("\r")))
+}
@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompatLegacyGoSwagger(t *testing.T) {
+	const pkg = "github.com/3idey/codescan/fixtures/goparsing/legacy"
+
+	t.Run("should recognize the legacy annotations with a warning each", func(t *testing.T) {
+		var diagnostics []Diagnostic
+		doc, err := Run(&Options{Packages: []string{pkg}, Compat: []string{CompatLegacyGoSwagger}, Diagnostics: &diagnostics})
+		require.NoError(t, err)
+
+		op := doc.Paths.Paths["/pets"].Get
+		require.NotNil(t, op)
+		assert.Equal(t, "listPets", op.ID)
+		require.Len(t, op.Parameters, 1)
+		assert.Equal(t, "limit", op.Parameters[0].Name)
+		assert.Contains(t, doc.Definitions, "Pet")
+
+		var legacy []string
+		for _, diagnostic := range diagnostics {
+			if diagnostic.Code == DiagnosticLegacyAnnotation {
+				legacy = append(legacy, fmt.Sprintf("%s:%d:%d: %s", filepath.Base(diagnostic.Pos.Filename), diagnostic.Pos.Line, diagnostic.Pos.Column, diagnostic.Message))
+			}
+		}
+		assert.Equal(t, []string{
+			"api.go:10:4: @swagger:route is the legacy spelling of swagger:route, see codescan migrate-annotations",
+			"api.go:21:4: swagger:params is the legacy spelling of swagger:parameters, see codescan migrate-annotations",
+			"api.go:39:4: +swagger:model is the legacy spelling of swagger:model, see codescan migrate-annotations",
+		}, legacy)
+	})
+
+	t.Run("should point to the compatibility mode without it", func(t *testing.T) {
+		_, err := Run(&Options{Packages: []string{pkg}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unknown swagger annotation "params", the legacy spelling of swagger:parameters, see Options.Compat`)
+	})
+
+	t.Run("should precheck the legacy annotations as warnings", func(t *testing.T) {
+		diagnostics, err := Precheck(&Options{Packages: []string{pkg}, Compat: []string{CompatLegacyGoSwagger}})
+		require.NoError(t, err)
+		require.Len(t, diagnostics, 3)
+		for _, diagnostic := range diagnostics {
+			assert.Equal(t, DiagnosticLegacyAnnotation, diagnostic.Code)
+			assert.Equal(t, SeverityWarning, diagnostic.Severity)
+		}
+	})
+
+	t.Run("should reject an unknown mode", func(t *testing.T) {
+		_, err := Run(&Options{Packages: []string{pkg}, Compat: []string{"swag"}})
+		require.EqualError(t, err, `unknown compatibility mode "swag", expected legacy-go-swagger`)
+	})
+}
+
+func TestMigrateAnnotations(t *testing.T) {
+	src, err := os.ReadFile(filepath.Join("..", "fixtures", "goparsing", "legacy", "api.go"))
+	require.NoError(t, err)
+
+	t.Run("should rewrite the comments to the canonical spelling", func(t *testing.T) {
+		migrated, legacy, err := MigrateAnnotations("api.go", src)
+		require.NoError(t, err)
+		require.Len(t, legacy, 3)
+		assert.Equal(t, "swagger:parameters", legacy[1].Canonical)
+		assert.Equal(t, 21, legacy[1].Pos.Line)
+
+		assert.Contains(t, string(migrated), "// swagger:route GET /pets pets listPets\n")
+		assert.Contains(t, string(migrated), "// swagger:parameters listPets\n")
+		assert.Contains(t, string(migrated), "// swagger:model\ntype Pet struct")
+		assert.Len(t, migrated, len(src)+4-2, "only the annotations change")
+
+		again, legacy, err := MigrateAnnotations("api.go", migrated)
+		require.NoError(t, err)
+		assert.Empty(t, legacy)
+		assert.Equal(t, migrated, again)
+	})
+
+	t.Run("should rewrite the block comments and keep the carriage returns", func(t *testing.T) {
+		src := []byte("package p\r\n\r\n/*\r\nPet is a pet.\r\n\r\n+swagger:model pet\r\n*/\r\ntype Pet struct{} // @swagger:ignore\r\n")
+		migrated, legacy, err := MigrateAnnotations("p.go", src)
+		require.NoError(t, err)
+		assert.Equal(t, "package p\r\n\r\n/*\r\nPet is a pet.\r\n\r\nswagger:model pet\r\n*/\r\ntype Pet struct{} // swagger:ignore\r\n", string(migrated))
+		require.Len(t, legacy, 2)
+		assert.Equal(t, 6, legacy[0].Pos.Line)
+		assert.Equal(t, 1, legacy[0].Pos.Column)
+	})
+
+	t.Run("should leave the mentions alone", func(t *testing.T) {
+		src := []byte("package p\n\n// See x-swagger:params and a+swagger:model.\nvar v int\n")
+		migrated, legacy, err := MigrateAnnotations("p.go", src)
+		require.NoError(t, err)
+		assert.Empty(t, legacy)
+		assert.Equal(t, src, migrated)
+	})
+}
@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"fmt"
+	"go/token"
+
+	"github.com/go-openapi/spec"
+)
+
+// allOfRef is a composition member declared by name, e.g. "swagger:allOfRef Pet class:Dog".
+type allOfRef struct {
+	name  string // name of the definition, either a model or a definition of the input spec
+	class string // optional x-class of the composed type
+	pos   token.Position
+}
+
+// allOfRefs returns the composition members declared by name in the doc comments of a struct.
+func allOfRefs(decl *entityDecl) []allOfRef {
+	if decl.Comments == nil {
+		return nil
+	}
+
+	var refs []allOfRef
+	for _, cmt := range decl.Comments.List {
+		for ln := range commentLines(cmt.Text) {
+			matches := rxAllOfRef.FindStringSubmatch(ln)
+			if len(matches) < 3 {
+				continue
+			}
+			refs = append(refs, allOfRef{
+				name:  matches[1],
+				class: matches[2],
+				pos:   decl.Pkg.Fset.Position(cmt.Pos()),
+			})
+		}
+	}
+
+	return refs
+}
+
+// buildAllOfRefs builds the composition members declared by name.
+//
+// A member naming a model is a $ref to that model, which gets discovered. Otherwise, the member must be a
+// definition supplied by the input spec: this is checked once the schema is built, see checkExternalRefs.
+func (s *schemaBuilder) buildAllOfRefs(decl *entityDecl, schema *spec.Schema) ([]spec.Schema, error) {
+	refs := allOfRefs(decl)
+	members := make([]spec.Schema, 0, len(refs))
+	for _, ref := range refs {
+		var member spec.Schema
+		if model, found := s.ctx.FindModelByName(ref.name); found {
+			if err := s.makeRef(model, schemaTypable{&member, 0}); err != nil {
+				return nil, err
+			}
+		} else {
+			member.Ref = spec.MustCreateRef(definitionsPrefix + ref.name)
+			s.externalRefs = append(s.externalRefs, ref)
+		}
+		if ref.class != "" {
+			schema.AddExtension("x-class", ref.class)
+		}
+		members = append(members, member)
+	}
+
+	return members, nil
+}
+
+// checkExternalRefs verifies that the composition members which are not models are found in the definitions.
+func (s *schemaBuilder) checkExternalRefs(definitions map[string]spec.Schema) error {
+	for _, ref := range s.externalRefs {
+		if _, found := definitions[ref.name]; !found {
+			return fmt.Errorf("%v: model %s is composed with unknown definition %q", ref.pos, s.Name, ref.name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,181 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/types"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// serializationExtension documents how the value of a simple parameter of a composite type is encoded,
+// e.g. json for a map.
+const serializationExtension = "x-serialization"
+
+// compositeType tells if a parameter of a type has no encoding as a simple parameter, i.e. as a string or an
+// array of strings: a map, which it returns, or a struct, or an array of them. The type mappings, sets,
+// text marshalers, times and the types annotated with swagger:strfmt or swagger:type are simple.
+func (p *parameterBuilder) compositeType(tpe types.Type) (*types.Map, bool) {
+	if _, isMapped := p.ctx.app.typeMapping(tpe); isMapped {
+		return nil, false
+	}
+	if _, isSet := p.ctx.app.setElement(tpe); isSet {
+		return nil, false
+	}
+	if isTextMarshaler(tpe) || isTextMarshaler(types.NewPointer(tpe)) {
+		return nil, false
+	}
+
+	switch ctpe := tpe.(type) {
+	case *types.Pointer:
+		return p.compositeType(ctpe.Elem())
+	case *types.Alias:
+		return p.compositeType(types.Unalias(ctpe))
+	case *types.Slice:
+		_, composite := p.compositeType(ctpe.Elem())
+		return nil, composite
+	case *types.Array:
+		_, composite := p.compositeType(ctpe.Elem())
+		return nil, composite
+	case *types.Map:
+		return ctpe, true
+	case *types.Struct:
+		return nil, true
+	case *types.Named:
+		o := ctpe.Obj()
+		if o.Pkg() == nil || isStdTime(o) || isAny(o) || isStdError(o) {
+			return nil, false
+		}
+		if decl, found := p.ctx.DeclForType(o.Type()); found {
+			if _, isStrfmt := strfmtName(decl.Comments); isStrfmt {
+				return nil, false
+			}
+			if _, isTyped := typeName(decl.Comments); isTyped {
+				return nil, false
+			}
+		}
+		return p.compositeType(ctpe.Underlying())
+	default:
+		return nil, false
+	}
+}
+
+// buildComposite documents a simple parameter of a composite type, see compositeType. A map is a string with
+// an x-serialization: json extension, or, in a query or a form, has a parameter per key when its field lists
+// them with Keys:, typed by the values of the map, which buildFromStruct names name[key]. The maps whose
+// values are composite, or whose keys can't be sent in their location, are strings too, with a
+// composite-parameter diagnostic. The other composite types keep the schema they always had, which no
+// simple parameter can send, with the diagnostic. It returns whether the parameter has a parameter per key.
+func (p *parameterBuilder) buildComposite(decl *entityDecl, fld *types.Var, mapType *types.Map, keys []string, ps *spec.Parameter, seen map[string]spec.Parameter) (bool, error) {
+	if mapType == nil {
+		problem := fmt.Sprintf("the %s parameter %s of %s has the composite type %s, which has no encoding as a %s parameter: send it in: body, or give its type a swagger:strfmt or a swagger:type",
+			ps.In, fld.Name(), decl.Obj().Name(), types.TypeString(fld.Type(), types.RelativeTo(decl.Pkg.Types)), ps.In)
+		if err := p.diagnoseComposite(decl, fld, problem); err != nil {
+			return false, err
+		}
+		return false, p.buildFromField(fld, fld.Type(), paramTypable{ps}, seen)
+	}
+
+	var problem string
+	switch {
+	case len(keys) > 0 && ps.In != "query" && ps.In != "formData":
+		problem = fmt.Sprintf("the keys of the %s parameter %s of %s can't be separate %s parameters: documented as a JSON string",
+			ps.In, fld.Name(), decl.Obj().Name(), ps.In)
+	case len(keys) > 0:
+		if _, composite := p.compositeType(mapType.Elem()); !composite {
+			return true, p.buildFromField(fld, mapType.Elem(), paramTypable{ps}, seen)
+		}
+		problem = fmt.Sprintf("the values of the %s parameter %s of %s, of type %s, have no encoding as separate %s parameters: documented as a JSON string",
+			ps.In, fld.Name(), decl.Obj().Name(), types.TypeString(mapType.Elem(), types.RelativeTo(decl.Pkg.Types)), ps.In)
+	}
+
+	ps.Typed("string", "")
+	addExtension(&ps.VendorExtensible, serializationExtension, "json")
+	if problem == "" {
+		return false, nil
+	}
+	return false, p.diagnoseComposite(decl, fld, problem)
+}
+
+// diagnoseComposite reports a composite-parameter diagnostic, returned with StrictParameters.
+func (p *parameterBuilder) diagnoseComposite(decl *entityDecl, fld *types.Var, problem string) error {
+	diagnostic := Diagnostic{Pos: decl.Pkg.Fset.Position(fld.Pos()), Code: DiagnosticCompositeParameter, Message: problem}
+	if p.ctx.app.strictParameters {
+		return diagnostic
+	}
+	p.ctx.app.diagnose(diagnostic)
+	return nil
+}
+
+// encodingDescription describes the JSON encoding of a map parameter, appended to its description.
+func encodingDescription(mapType *types.Map) string {
+	return "Encoded as a JSON object mapping the keys to " + describeValues(mapType.Elem()) + "."
+}
+
+// describeValues names the values of a type in plain words, e.g. arrays of strings for []string.
+func describeValues(tpe types.Type) string {
+	switch vtpe := types.Unalias(tpe).(type) {
+	case *types.Pointer:
+		return describeValues(vtpe.Elem())
+	case *types.Slice:
+		return "arrays of " + describeValues(vtpe.Elem())
+	case *types.Array:
+		return "arrays of " + describeValues(vtpe.Elem())
+	case *types.Map:
+		return "objects of " + describeValues(vtpe.Elem())
+	case *types.Struct:
+		return "objects"
+	case *types.Named:
+		o := vtpe.Obj()
+		switch {
+		case isStdTime(o):
+			return "date-time strings"
+		case isAny(o):
+			return "values"
+		}
+		if _, isStruct := vtpe.Underlying().(*types.Struct); isStruct {
+			return o.Name() + " objects"
+		}
+		return describeValues(vtpe.Underlying())
+	case *types.Basic:
+		switch info := vtpe.Info(); {
+		case info&types.IsBoolean != 0:
+			return "booleans"
+		case info&types.IsInteger != 0:
+			return "integers"
+		case info&types.IsFloat != 0:
+			return "numbers"
+		case info&types.IsString != 0:
+			return "strings"
+		}
+	}
+	return "values"
+}
+
+// splitKeys splits the keys listed by a Keys: line, separated by commas.
+func splitKeys(list string) []string {
+	var keys []string
+	for key := range strings.SplitSeq(list, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// cloneParameter returns a deep copy of a parameter.
+func cloneParameter(param spec.Parameter) (spec.Parameter, error) {
+	jazon, err := json.Marshal(param)
+	if err != nil {
+		return spec.Parameter{}, err
+	}
+	var clone spec.Parameter
+	if err := json.Unmarshal(jazon, &clone); err != nil {
+		return spec.Parameter{}, err
+	}
+	return clone, nil
+}
@@ -0,0 +1,99 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompositeParameters(t *testing.T) {
+	const pkg = "github.com/3idey/codescan/fixtures/goparsing/compositeparams"
+	var diagnostics []Diagnostic
+	doc, err := Run(&Options{Packages: []string{pkg}, Diagnostics: &diagnostics})
+	require.NoError(t, err)
+	op := doc.Paths.Paths["/pets"].Get
+	require.NotNil(t, op)
+	params := make(map[string]spec.Parameter, len(op.Parameters))
+	for _, param := range op.Parameters {
+		params[param.Name] = param
+	}
+
+	t.Run("should encode the maps as JSON strings", func(t *testing.T) {
+		filters := params["filters"]
+		assert.Equal(t, "query", filters.In)
+		assert.Equal(t, "string", filters.Type)
+		assert.Nil(t, filters.Schema)
+		assert.Equal(t, "json", filters.Extensions[serializationExtension])
+		assert.Equal(t, "the filters\n\nEncoded as a JSON object mapping the keys to arrays of strings.", filters.Description)
+
+		scores := params["scores"]
+		assert.Equal(t, "string", scores.Type, "a pointer to a map is a map")
+		assert.Equal(t, "json", scores.Extensions[serializationExtension])
+		assert.Contains(t, scores.Description, "mapping the keys to numbers")
+	})
+
+	t.Run("should explode the maps with keys into a parameter per key", func(t *testing.T) {
+		assert.NotContains(t, params, "labels")
+		for _, name := range []string{"labels[team]", "labels[env]"} {
+			require.Contains(t, params, name)
+			keyed := params[name]
+			assert.Equal(t, "query", keyed.In)
+			assert.Equal(t, "string", keyed.Type)
+			assert.Equal(t, int64(10), *keyed.MaxLength, "the validations apply to the value of each key")
+			assert.Equal(t, "the labels of the pets", keyed.Description)
+			assert.NotContains(t, keyed.Extensions, serializationExtension)
+		}
+		assert.Equal(t, "labels[team]", op.Parameters[1].Name)
+		assert.Equal(t, "labels[env]", op.Parameters[2].Name, "the keys are in the order of the Keys line")
+		team, env := params["labels[team]"], params["labels[env]"]
+		assert.NotSame(t, team.MaxLength, env.MaxLength)
+	})
+
+	t.Run("should leave the strfmt types to their format", func(t *testing.T) {
+		born := params["born"]
+		assert.Equal(t, "string", born.Type)
+		assert.Equal(t, "date", born.Format)
+		assert.NotContains(t, born.Extensions, serializationExtension)
+	})
+
+	t.Run("should report the other composite types with a diagnostic", func(t *testing.T) {
+		ages := params["ages"]
+		assert.Equal(t, "#/definitions/Range", ages.Ref.String(), "the schema is kept")
+		assert.NotContains(t, ages.Extensions, serializationExtension)
+		assert.Equal(t, "array", params["ranges"].Type)
+
+		var messages []string
+		for _, diagnostic := range diagnostics {
+			if diagnostic.Code == DiagnosticCompositeParameter {
+				messages = append(messages, diagnostic.Message)
+			}
+		}
+		assert.Equal(t, []string{
+			"the query parameter Ages of listPetsParams has the composite type Range, which has no encoding as a query parameter: send it in: body, or give its type a swagger:strfmt or a swagger:type",
+			"the query parameter Ranges of listPetsParams has the composite type []Range, which has no encoding as a query parameter: send it in: body, or give its type a swagger:strfmt or a swagger:type",
+			"the keys of the header parameter Flags of listPetsParams can't be separate header parameters: documented as a JSON string",
+			"the values of the query parameter Nested of listPetsParams, of type map[string]int, have no encoding as separate query parameters: documented as a JSON string",
+		}, messages)
+	})
+
+	t.Run("should encode the maps with keys the location can't explode as JSON strings", func(t *testing.T) {
+		flags := params["X-Flags"]
+		assert.Equal(t, "header", flags.In)
+		assert.Equal(t, "json", flags.Extensions[serializationExtension])
+		nested := params["nested"]
+		assert.Equal(t, "json", nested.Extensions[serializationExtension])
+		assert.Contains(t, nested.Description, "mapping the keys to objects of integers")
+	})
+
+	t.Run("should fail on the composite parameters with StrictParameters", func(t *testing.T) {
+		_, err := Run(&Options{Packages: []string{pkg}, StrictParameters: true})
+		var problem Diagnostic
+		require.ErrorAs(t, err, &problem)
+		assert.Equal(t, DiagnosticCompositeParameter, problem.Code)
+	})
+}
@@ -0,0 +1,285 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"go/ast"
+	"go/token"
+	"maps"
+	"runtime"
+	"slices"
+	"sync"
+
+	"github.com/go-openapi/spec"
+	"golang.org/x/tools/go/packages"
+)
+
+func withConcurrency(workers int) typeIndexOption {
+	return func(a *typeIndex) {
+		if workers == 0 {
+			workers = runtime.GOMAXPROCS(0)
+		}
+		a.concurrency = workers
+		if workers > 1 {
+			a.shared = new(sync.Mutex)
+		}
+	}
+}
+
+// parallel calls do with each index below n, on at most workers goroutines, and returns once all are done.
+func parallel(workers, n int, do func(int)) {
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range min(workers, n) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				do(i)
+			}
+		}()
+	}
+	for i := range n {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
+
+// journaledDiagnostic is a diagnostic of a fork, recorded by the scan when the fork joins it.
+type journaledDiagnostic struct {
+	Diagnostic
+
+	byMessage bool // recorded once per position, kind and message, see diagnoseMessage
+}
+
+// fork returns a copy of the index for a goroutine of a concurrent scan. The fork journals its diagnostics,
+// fills its own stats and extra models, and locks the caches it shares with the others: join merges them in
+// the order of a sequential scan.
+func (a *typeIndex) fork() *typeIndex {
+	forked := *a
+	forked.forked = true
+	forked.journal = nil
+	forked.diagnostics = nil
+	forked.orderDependent = false
+	forked.crossPackage = false
+	forked.stats = Stats{}
+	forked.ExtraModels = make(map[*ast.Ident]*entityDecl)
+	return &forked
+}
+
+// join records the diagnostics of a fork, then the extra models it found and the files it read.
+func (a *typeIndex) join(fork *typeIndex) {
+	a.replay(fork.journal)
+	maps.Copy(a.ExtraModels, fork.ExtraModels)
+	for _, file := range fork.stats.ExampleFiles {
+		if !slices.Contains(a.stats.ExampleFiles, file) {
+			a.stats.ExampleFiles = append(a.stats.ExampleFiles, file)
+		}
+	}
+	slices.Sort(a.stats.ExampleFiles)
+	for _, file := range fork.stats.SchemaFiles {
+		if !slices.Contains(a.stats.SchemaFiles, file) {
+			a.stats.SchemaFiles = append(a.stats.SchemaFiles, file)
+		}
+	}
+	slices.Sort(a.stats.SchemaFiles)
+}
+
+// replay records the diagnostics journaled by a fork, in order.
+func (a *typeIndex) replay(journal []journaledDiagnostic) {
+	for _, entry := range journal {
+		if entry.byMessage {
+			a.diagnoseMessage(entry.Diagnostic)
+			continue
+		}
+		a.diagnose(entry.Diagnostic)
+	}
+}
+
+// lock locks the caches shared by the forks of a concurrent scan, i.e. the discriminated bases and the
+// instances of the generic types, and returns the unlock.
+func (a *typeIndex) lock() func() {
+	if a.shared == nil {
+		return func() {}
+	}
+	a.shared.Lock()
+	return a.shared.Unlock
+}
+
+// fork returns a copy of the context for a goroutine of a concurrent scan, with a fork of its index.
+func (s *scanCtx) fork() *scanCtx {
+	forked := *s
+	forked.app = s.app.fork()
+	return &forked
+}
+
+// cacheAnnotations caches the annotations of a declaration, so that the goroutines of a concurrent scan
+// sharing it only read them.
+func (d *entityDecl) cacheAnnotations() {
+	d.HasModelAnnotation()
+	d.HasResponseAnnotation()
+	d.HasParameterAnnotation()
+}
+
+// classifiedFile is the classification of a file by a goroutine, see classifyFiles.
+type classifiedFile struct {
+	n       node
+	err     error
+	journal []journaledDiagnostic
+}
+
+// classifyFiles classifies the annotations of the files of the packages build walks, concurrently, before
+// the walk: processFiles then replays the diagnostics of each file in order, see classify.
+func (a *typeIndex) classifyFiles(pkgs []*packages.Package) {
+	if a.concurrency < 2 {
+		return
+	}
+
+	type pkgFile struct {
+		pkg  *packages.Package
+		file *ast.File
+	}
+	var files []pkgFile
+	seen := make(map[string]bool)
+	var walk func(*packages.Package)
+	walk = func(pkg *packages.Package) {
+		if seen[pkg.PkgPath] {
+			return
+		}
+		seen[pkg.PkgPath] = true
+		if a.classifiesPackage(pkg) {
+			for _, file := range pkg.Syntax {
+				files = append(files, pkgFile{pkg: pkg, file: file})
+			}
+		}
+		for _, imp := range pkg.Imports {
+			if followsImport(a.excludeDeps, imp) {
+				walk(imp)
+			}
+		}
+	}
+	for _, pkg := range pkgs {
+		walk(pkg)
+	}
+
+	classified := make([]classifiedFile, len(files))
+	parallel(a.concurrency, len(files), func(i int) {
+		fork := a.fork()
+		n, err := fork.detectNodes(files[i].pkg.Fset, files[i].file)
+		classified[i] = classifiedFile{n: n, err: err, journal: fork.journal}
+	})
+	a.classified = make(map[*ast.File]classifiedFile, len(files))
+	for i, file := range files {
+		a.classified[file.file] = classified[i]
+	}
+}
+
+// classifiesPackage tells if acceptsPackage accepts a package, without recording anything.
+func (a *typeIndex) classifiesPackage(pkg *packages.Package) bool {
+	if a.forceIncludeDirFor(pkg) != nil {
+		return true
+	}
+	if _, skipped := a.skippedRoot(pkg); skipped {
+		return false
+	}
+	return shouldAcceptPkg(pkg.PkgPath, a.includePkgs, a.excludePkgs)
+}
+
+// classify classifies the annotations of a file, see detectNodes, from its concurrent classification when
+// there is one, whose diagnostics are recorded now.
+func (a *typeIndex) classify(fset *token.FileSet, file *ast.File) (node, error) {
+	classified, known := a.classified[file]
+	if !known {
+		return a.detectNodes(fset, file)
+	}
+	delete(a.classified, file)
+	a.replay(classified.journal)
+	return classified.n, classified.err
+}
+
+// prebuiltSchema is the schema of a declaration built by a goroutine, or read from the models cached, see
+// buildSchemas.
+type prebuiltSchema struct {
+	name    string // the definition name of the declaration
+	seeded  bool   // the definitions held the name when the schema was built, e.g. from the input spec
+	builder *schemaBuilder
+	schema  spec.Schema
+	fork    *typeIndex   // nil when the schema is built in order
+	model   *cachedModel // the schema to cache once recorded, nil when it can't be, see modelCache.capture
+	reused  bool         // the schema is read from the models cached
+}
+
+// buildSchemas builds the schemas of declarations, in order. With Options.Concurrency, the schemas are built
+// concurrently, each by a fork of the scan, then recorded in order, so that the definitions and the
+// diagnostics are those of a sequential build: a cycle of types is made of $refs, whichever is built first.
+// The schemas depending on the order are built again in order: those building over a definition, derived
+// from a model or composed by name, and those which fail or panic, for the scan to report them.
+func (s *specBuilder) buildSchemas(decls []*entityDecl) error {
+	prebuilt := s.prebuildSchemas(decls)
+	for i, decl := range decls {
+		var schema *prebuiltSchema
+		if prebuilt != nil && prebuilt[i].fork != nil {
+			if _, exists := s.definitions[prebuilt[i].name]; !exists {
+				schema = &prebuilt[i]
+			}
+		}
+		if err := s.buildDiscoveredSchema(decl, schema); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// prebuildSchemas builds the schemas of declarations concurrently, returning nil without concurrency. With
+// the models cached, the schemas are read from the cache, or built by forks for the cache even without
+// concurrency.
+func (s *specBuilder) prebuildSchemas(decls []*entityDecl) []prebuiltSchema {
+	models := s.ctx.app.models
+	if (s.ctx.app.concurrency < 2 || len(decls) < 2) && models == nil {
+		return nil
+	}
+
+	for _, decl := range s.ctx.app.Models {
+		decl.cacheAnnotations()
+	}
+	prebuilt := make([]prebuiltSchema, len(decls))
+	for i, decl := range decls {
+		decl.cacheAnnotations()
+		sb := &schemaBuilder{ctx: s.ctx, decl: decl}
+		sb.inferNames()
+		prebuilt[i].name = sb.Name
+		_, prebuilt[i].seeded = s.definitions[sb.Name]
+		if !prebuilt[i].seeded {
+			models.reuse(s.ctx, sb, &prebuilt[i])
+		}
+	}
+
+	discovered := slices.Clone(s.discovered)
+	parallel(s.ctx.app.concurrency, len(decls), func(i int) {
+		if prebuilt[i].seeded || prebuilt[i].reused || s.ctx.canceled() != nil {
+			return
+		}
+		if derivation, err := decls[i].Derivation(); err != nil || derivation != nil {
+			return
+		}
+		ctx := s.ctx.fork()
+		if _, indexed := ctx.app.indexedRef(decls[i]); indexed {
+			return
+		}
+		ctx.app.journal = nil // diagnosed again in order, see buildDiscoveredSchema
+		defer func() {
+			// the schema is built again in order, which recovers the panic, see recoverBuild
+			_ = recover()
+		}()
+		sb := &schemaBuilder{ctx: ctx, decl: decls[i], discovered: discovered}
+		schema, err := sb.buildSchema(s.definitions)
+		if err != nil || ctx.app.orderDependent {
+			return
+		}
+		prebuilt[i].builder, prebuilt[i].schema, prebuilt[i].fork = sb, schema, ctx.app
+		prebuilt[i].model = models.capture(ctx, sb, schema)
+	})
+	return prebuilt
+}
@@ -0,0 +1,236 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/3idey/codescan/codescan/genfixture"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/packages"
+)
+
+// writeSyntheticModule writes a module of generated packages, each with models referring to those of the
+// previous package, a cycle of models, instantiations of a generic type of the first package, a misspelled
+// annotation and a route, returning its directory.
+func writeSyntheticModule(tb testing.TB, pkgs, models int) string {
+	tb.Helper()
+	dir := tb.TempDir()
+	files := map[string]string{"go.mod": "module example.com/synthetic\n\ngo 1.22\n"}
+	for p := range pkgs {
+		name := fmt.Sprintf("p%03d", p)
+		var src strings.Builder
+		fmt.Fprintf(&src, "// Package %s is generated.\npackage %s\n\n", name, name)
+		if p > 0 {
+			fmt.Fprintf(&src, "import (\n\t\"example.com/synthetic/p000\"\n\t\"example.com/synthetic/p%03d\"\n)\n\n", p-1)
+		} else {
+			src.WriteString("// Page is a page of items.\ntype Page[T any] struct {\n\tItems []T `json:\"items\"`\n\tTotal int `json:\"total\"`\n}\n\n")
+		}
+		for m := range models {
+			fmt.Fprintf(&src, "// M%d is a model of %s.\n//\n// It spans\n// several lines.\n//\n// swagger:model %s_M%d\ntype M%d struct {\n", m, name, name, m, m)
+			src.WriteString("\t// The identifier.\n\t//\n\t// required: true\n\t// minimum: 1\n\tID int64 `json:\"id\"`\n")
+			src.WriteString("\t// The name.\n\t//\n\t// max length: 64\n\t// pattern: ^[a-z]+$\n\tName string `json:\"name,omitempty\"`\n")
+			fmt.Fprintf(&src, "\t// The next model, closing a cycle.\n\tNext *M%d `json:\"next,omitempty\"`\n", (m+1)%models)
+			if p > 0 {
+				fmt.Fprintf(&src, "\t// The models of the previous package.\n\tPrevious []p%03d.M%d `json:\"previous\"`\n", p-1, m)
+				fmt.Fprintf(&src, "\t// A page of models.\n\tPage p000.Page[M%d] `json:\"page\"`\n", m)
+			}
+			src.WriteString("\tLabels map[string]string `json:\"labels\"`\n}\n\n")
+		}
+		fmt.Fprintf(&src, "// Unlisted is a model with a misspelled annotation.\n//\n// swager:model\ntype Unlisted struct{}\n\n")
+		fmt.Fprintf(&src, "// swagger:route GET /%s/{id} %s get%s\n//\n// Gets a model.\n//\n// Responses:\n//   200: body:%s_M0\nfunc Get() {}\n", name, name, name, name)
+		files[filepath.Join(name, name+".go")] = src.String()
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(tb, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(tb, os.WriteFile(path, []byte(content), 0o600))
+	}
+	return dir
+}
+
+// writeGeneratedFixture writes the module generated for the options, see genfixture.
+func writeGeneratedFixture(tb testing.TB, opts genfixture.Options) string {
+	tb.Helper()
+	dir := tb.TempDir()
+	require.NoError(tb, genfixture.Write(dir, opts))
+	return dir
+}
+
+// TestConcurrentScan compares the concurrent scans with the sequential ones, run with -race for the data
+// races of the concurrent builds.
+func TestConcurrentScan(t *testing.T) {
+	type scan struct {
+		spec        string
+		err         string
+		diagnostics []Diagnostic
+		logged      []string
+		stats       Stats
+	}
+	// the packages are loaded once for the scans compared, since loading them, not scanning them, takes most
+	// of the time of the scans run with -race: the fixtures of this module share a load, and with it the
+	// type-checking of their dependencies, e.g. net/http
+	load := func(t *testing.T, dir string, patterns ...string) []*packages.Package {
+		t.Helper()
+		pkgs, err := packages.Load(&packages.Config{Mode: PackagesLoadMode, Dir: dir}, patterns...)
+		require.NoError(t, err)
+		return pkgs
+	}
+	run := func(t *testing.T, pkgs []*packages.Package, opts Options, concurrency int) scan {
+		t.Helper()
+		var result scan
+		opts.Concurrency = concurrency
+		opts.Diagnostics = &result.diagnostics
+		opts.Stats = &result.stats
+		opts.Logger = func(diagnostic Diagnostic) { result.logged = append(result.logged, diagnostic.Error()) }
+		doc, err := RunOnPackages(pkgs, &opts)
+		if err != nil {
+			result.err = err.Error()
+			return result
+		}
+		jazon, err := MarshalJSON(doc, false)
+		require.NoError(t, err)
+		result.spec = string(jazon)
+		return result
+	}
+
+	const fixtures = "github.com/3idey/codescan/fixtures/goparsing/"
+	dir := writeSyntheticModule(t, 12, 8)
+	synthetic := load(t, dir, "./...")
+	models := load(t, writeGeneratedFixture(t, genfixture.Options{Models: 60, Depth: 2, Enums: 3}), "./...")
+	api := load(t, writeGeneratedFixture(t, genfixture.Options{Operations: 20, Models: 150, Depth: 3, Enums: 5}), "./...")
+	module := load(t, "", fixtures+"classification/...", fixtures+"petstore/...", fixtures+"generics", fixtures+"discriminator",
+		fixtures+"derive", fixtures+"compose", fixtures+"examples", fixtures+"mistakes")
+	// roots are the packages of the shared load under a directory of the fixtures
+	roots := func(fixture string) []*packages.Package {
+		var matched []*packages.Package
+		for _, pkg := range module {
+			if pkg.PkgPath == fixtures+fixture || strings.HasPrefix(pkg.PkgPath, fixtures+fixture+"/") {
+				matched = append(matched, pkg)
+			}
+		}
+		require.NotEmpty(t, matched, fixture)
+		return matched
+	}
+	for _, tc := range []struct {
+		name  string
+		pkgs  []*packages.Package
+		opts  Options
+		fails bool // the fixture has mistakes the scan fails with
+	}{
+		{name: "synthetic", pkgs: synthetic, opts: Options{ScanModels: true}},
+		{name: "synthetic with full names", pkgs: synthetic, opts: Options{ScanModels: true, DefinitionNaming: NamingFull}},
+		{name: "the models of a generated fixture", pkgs: models, opts: Options{ScanModels: true}},
+		{name: "the operations of a generated fixture", pkgs: api, opts: Options{ScanModels: true}},
+		{name: "classification", pkgs: roots("classification"), opts: Options{ScanModels: true}},
+		{name: "petstore", pkgs: roots("petstore"), opts: Options{ScanModels: true}},
+		{name: "generics", pkgs: roots("generics"), opts: Options{ScanModels: true}},
+		{name: "discriminator", pkgs: roots("discriminator"), opts: Options{ScanModels: true}, fails: true},
+		{name: "derive", pkgs: roots("derive"), opts: Options{ScanModels: true}, fails: true},
+		{name: "compose", pkgs: roots("compose"), opts: Options{ScanModels: true}, fails: true},
+		{name: "examples", pkgs: roots("examples"), opts: Options{ScanModels: true}},
+		{name: "mistakes", pkgs: roots("mistakes"), opts: Options{ScanModels: true}, fails: true},
+	} {
+		t.Run("should scan "+tc.name+" like a sequential scan", func(t *testing.T) {
+			pkgs := tc.pkgs
+			sequential := run(t, pkgs, tc.opts, 1)
+			if tc.fails {
+				assert.NotEmpty(t, sequential.err)
+			} else {
+				assert.Empty(t, sequential.err)
+			}
+			for range 3 {
+				concurrent := run(t, pkgs, tc.opts, 8)
+				assert.Equal(t, sequential.err, concurrent.err)
+				require.Equal(t, sequential.spec, concurrent.spec)
+				assert.Equal(t, sequential.diagnostics, concurrent.diagnostics)
+				assert.Equal(t, sequential.logged, concurrent.logged, "the Logger is called in the same order")
+				assert.Equal(t, sequential.stats, concurrent.stats)
+			}
+		})
+	}
+
+	t.Run("should resolve the cycles and the references across packages", func(t *testing.T) {
+		doc, err := RunOnPackages(synthetic, &Options{ScanModels: true, Concurrency: 8})
+		require.NoError(t, err)
+		require.Contains(t, doc.Definitions, "p011_M7")
+		model := doc.Definitions["p011_M7"]
+		next, previous, page := model.Properties["next"], model.Properties["previous"], model.Properties["page"]
+		assert.Equal(t, "#/definitions/p011_M0", next.Ref.String())
+		assert.Equal(t, "#/definitions/p010_M7", previous.Items.Schema.Ref.String())
+		assert.Equal(t, "#/definitions/PageOfP011_M7", page.Ref.String())
+		assert.Contains(t, doc.Definitions, "p000_M0")
+	})
+
+	t.Run("should scan the operations and the models of the generated fixtures", func(t *testing.T) {
+		var stats Stats
+		doc, err := RunOnPackages(api, &Options{ScanModels: true, Stats: &stats})
+		require.NoError(t, err)
+		assert.Equal(t, 20, stats.Operations)
+		assert.Len(t, doc.Definitions, 150)
+		model := doc.Definitions["Model120"]
+		assert.Equal(t, "#/definitions/Model20", model.Properties["previous"].Items.Schema.Ref.String())
+		assert.Len(t, model.Properties["status"].Enum, 5)
+		details := model.Properties["details"]
+		level2 := details.Properties["level2"]
+		assert.Contains(t, level2.Properties["level3"].Properties, "count", "nested to the depth")
+	})
+
+	t.Run("should not accept a negative concurrency", func(t *testing.T) {
+		err := (&Options{Concurrency: -1}).Validate()
+		var invalid *InvalidOptionError
+		require.ErrorAs(t, err, &invalid)
+		assert.Equal(t, "Concurrency", invalid.Option)
+	})
+}
+
+// BenchmarkScan scans a large synthetic module, sequentially and concurrently, reporting the allocations of
+// the scans, e.g. with go test -run '^$' -bench Scan/sequential ./codescan.
+func BenchmarkScan(b *testing.B) {
+	dir := writeSyntheticModule(b, 40, 20)
+	for _, bench := range []struct {
+		name        string
+		concurrency int
+	}{
+		{name: "sequential", concurrency: 1},
+		{name: "parallel", concurrency: 0},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := Run(&Options{WorkDir: dir, Packages: []string{"./..."}, ScanModels: true, Concurrency: bench.concurrency}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkScanGenerated scans generated fixtures of several sizes, e.g. with
+// go test -run '^$' -bench ScanGenerated/large ./codescan.
+func BenchmarkScanGenerated(b *testing.B) {
+	for _, bench := range []struct {
+		name string
+		opts genfixture.Options
+	}{
+		{name: "small", opts: genfixture.Options{Operations: 50, Models: 200, Depth: 1, Enums: 3}},
+		{name: "medium", opts: genfixture.Options{Operations: 200, Models: 1000, Depth: 2, Enums: 5}},
+		{name: "large", opts: genfixture.Options{Operations: 500, Models: 2000, Depth: 3, Enums: 8}},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			dir := writeGeneratedFixture(b, bench.opts)
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := Run(&Options{WorkDir: dir, Packages: []string{"./..."}, ScanModels: true}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// xContentSchemas is the extension of a swagger 2.0 response with the schemas of the media types whose body
+// differs from the schema of the response, by media type. OpenAPI 3.0 documents have a content per media
+// type instead.
+const xContentSchemas = "x-content-schemas"
+
+// mediaTypeBody is the body of a response for one of the media types produced by the operation, declared
+// with a body tag of this media type, e.g. body[text/csv]:string.
+type mediaTypeBody struct {
+	mediaType string
+	target    string
+	arrays    int
+}
+
+// mediaTypeBodyTag returns the media type of a body tag like body[text/csv], normalized like Produces.
+func mediaTypeBodyTag(tag string) (string, bool) {
+	mediaType, ok := strings.CutPrefix(tag, BodyTag+"[")
+	if !ok {
+		return "", false
+	}
+	mediaType, ok = strings.CutSuffix(mediaType, "]")
+	if !ok || mediaType == "" {
+		return "", false
+	}
+	return normalizeMediaTypes([]string{mediaType}, "a response body")[0], true
+}
+
+// setContents sets the bodies of the media types of a response. Without a body tag, the body of a JSON
+// media type, else the first one, is the schema of the response; the others are recorded in x-content-schemas.
+func (ss *setOpResponses) setContents(resp *spec.Response, contents []mediaTypeBody) error {
+	if resp.Ref.String() != "" {
+		return errors.New("the bodies of the media types can't complete a swagger:response, use a body tag")
+	}
+
+	standard := -1
+	if resp.Schema == nil {
+		standard = 0
+		for i, content := range contents {
+			if isJSONMediaType(content.mediaType) {
+				standard = i
+				break
+			}
+		}
+	}
+
+	schemas := make(map[string]*spec.Schema, len(contents))
+	for i, content := range contents {
+		schema, err := ss.bodySchema(content.target, content.arrays)
+		if err != nil {
+			return err
+		}
+		if i == standard {
+			resp.Schema = schema
+			if resp.Description == "" {
+				resp.Description = content.target
+			}
+			continue
+		}
+		schemas[content.mediaType] = schema
+	}
+	if len(schemas) > 0 {
+		resp.AddExtension(xContentSchemas, schemas)
+	}
+	return nil
+}
+
+// bodySchema returns the schema of the body of a media type: a definition, a Go type, which is then built,
+// or a builtin type, e.g. string.
+func (ss *setOpResponses) bodySchema(target string, arrays int) (*spec.Schema, error) {
+	schema := new(spec.Schema)
+	elem := schema
+	for range arrays {
+		elem.Typed("array", "")
+		elem.Items = &spec.SchemaOrArray{Schema: new(spec.Schema)}
+		elem = elem.Items.Schema
+	}
+
+	if _, known := ss.definitions[target]; !known {
+		if ss.resolve != nil {
+			ref, resolved, err := ss.resolve(target)
+			if err != nil {
+				return nil, err
+			}
+			if resolved {
+				elem.Ref = ref
+				return schema, nil
+			}
+		}
+		if err := swaggerSchemaForType(target, schemaTypable{schema: elem}); err == nil {
+			return schema, nil
+		}
+	}
+
+	ref, err := spec.NewRef(definitionsPrefix + target)
+	if err != nil {
+		return nil, err
+	}
+	elem.Ref = ref
+	return schema, nil
+}
+
+// contentSchemas returns the schemas of the x-content-schemas extension of a response. Generic JSON values,
+// e.g. of an input spec, are decoded into the extension, so that the schemas are modified in place.
+func contentSchemas(resp *spec.Response) map[string]*spec.Schema {
+	value, ok := resp.Extensions[xContentSchemas]
+	if !ok {
+		return nil
+	}
+	if schemas, isDecoded := value.(map[string]*spec.Schema); isDecoded {
+		return schemas
+	}
+
+	jazon, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	var schemas map[string]*spec.Schema
+	if err := json.Unmarshal(jazon, &schemas); err != nil {
+		return nil
+	}
+	resp.Extensions[xContentSchemas] = schemas
+	return schemas
+}
+
+func isJSONMediaType(mediaType string) bool {
+	mediaType, _, _ = strings.Cut(mediaType, ";")
+	mediaType = strings.TrimSpace(mediaType)
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentSchemas(t *testing.T) {
+	const pkg = "github.com/3idey/codescan/fixtures/goparsing/contents"
+
+	t.Run("should record the bodies of the other media types in x-content-schemas", func(t *testing.T) {
+		doc, err := Run(&Options{Packages: []string{pkg}})
+		require.NoError(t, err)
+
+		response := doc.Paths.Paths["/reports"].Get.Responses.StatusCodeResponses[200]
+		assert.Equal(t, "the reports", response.Description)
+		assert.Equal(t, "#/definitions/Report", response.Schema.Ref.String())
+		schemas := contentSchemas(&response)
+		require.Len(t, schemas, 2)
+		assert.True(t, schemas["text/csv"].Type.Contains("string"))
+		assert.Equal(t, "#/definitions/ReportXML", schemas["application/xml"].Ref.String(), "the xml shorthand is expanded")
+		assert.Contains(t, doc.Definitions, "ReportXML", "the types of the media types are built")
+	})
+
+	t.Run("should use the first body without a JSON media type", func(t *testing.T) {
+		doc, err := Run(&Options{Packages: []string{pkg}})
+		require.NoError(t, err)
+
+		response := doc.Paths.Paths["/reports/latest"].Get.Responses.StatusCodeResponses[200]
+		assert.True(t, response.Schema.Type.Contains("array"))
+		assert.True(t, response.Schema.Items.Schema.Type.Contains("string"))
+		assert.Equal(t, []string{"text/plain"}, sortedKeys(contentSchemas(&response)))
+	})
+
+	t.Run("should round trip through openapi 3.0", func(t *testing.T) {
+		doc, err := Run(&Options{Packages: []string{pkg}})
+		require.NoError(t, err)
+		upgraded, err := UpgradeSwagger(doc)
+		require.NoError(t, err)
+
+		reports := asObject(asObject(asObject(upgraded["paths"])["/reports"])["get"])
+		content := asObject(asObject(asObject(reports["responses"])["200"])["content"])
+		assert.Equal(t, map[string]any{"type": "string"}, asObject(content["text/csv"])["schema"])
+		assert.Equal(t, map[string]any{"$ref": "#/components/schemas/ReportXML"}, asObject(content["application/xml"])["schema"])
+		assert.Equal(t, map[string]any{"$ref": "#/components/schemas/Report"}, asObject(content["application/json"])["schema"])
+
+		data, err := json.Marshal(upgraded)
+		require.NoError(t, err)
+		downgraded, err := DowngradeOpenAPI3(data)
+		require.NoError(t, err)
+		response := downgraded.Paths.Paths["/reports"].Get.Responses.StatusCodeResponses[200]
+		assert.Equal(t, "#/definitions/Report", response.Schema.Ref.String())
+		schemas := contentSchemas(&response)
+		require.Len(t, schemas, 2)
+		assert.Equal(t, "#/definitions/ReportXML", schemas["application/xml"].Ref.String())
+	})
+
+	t.Run("should fail on the bodies of media types with a swagger:response", func(t *testing.T) {
+		_, err := Run(&Options{Packages: []string{pkg + "/invalid"}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "response 200: the bodies of the media types can't complete a swagger:response, use a body tag")
+	})
+}
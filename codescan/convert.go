@@ -0,0 +1,801 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/go-openapi/loads/fmts"
+	"github.com/go-openapi/spec"
+)
+
+// ErrOpenAPI3Input is returned when an OpenAPI 3.x document is used where a Swagger 2.0 one is expected.
+var ErrOpenAPI3Input = errors.New("input spec is an OpenAPI 3.x document")
+
+// ParseInputSpec parses a Swagger 2.0 document, in JSON or YAML, to be used as Options.InputSpec.
+//
+// OpenAPI 3.x documents are refused with ErrOpenAPI3Input, unless downgrade is set:
+// they are then converted to Swagger 2.0 with DowngradeOpenAPI3.
+func ParseInputSpec(data []byte, downgrade bool) (*spec.Swagger, error) {
+	doc, err := parseSpecDocument(data)
+	if err != nil {
+		return nil, err
+	}
+
+	version, isOpenAPI3 := openAPIVersion(doc)
+	switch {
+	case isOpenAPI3 && downgrade:
+		return downgradeOpenAPI3(doc, &Options{})
+	case isOpenAPI3:
+		return nil, fmt.Errorf(
+			"%w (openapi: %s): convert it to swagger 2.0 first with `codescan convert`, or pass --downgrade-input",
+			ErrOpenAPI3Input, version,
+		)
+	}
+
+	if swaggerVersion, ok := doc["swagger"]; ok && fmt.Sprint(swaggerVersion) != "2.0" {
+		return nil, fmt.Errorf("unsupported input spec version (swagger: %v): only swagger 2.0 documents may be merged", swaggerVersion)
+	}
+	if _, ok := doc["swagger"]; !ok {
+		log.Printf("WARNING: input spec has no swagger: \"2.0\" field, assuming a swagger 2.0 document")
+	}
+
+	return specFromDocument(doc)
+}
+
+// DowngradeOpenAPI3 converts an OpenAPI 3.x document, in JSON or YAML, to Swagger 2.0 like
+// DowngradeOpenAPI3For, logging the constructs approximated or dropped as warnings.
+func DowngradeOpenAPI3(data []byte) (*spec.Swagger, error) {
+	return DowngradeOpenAPI3For(data, &Options{})
+}
+
+// DowngradeOpenAPI3For converts an OpenAPI 3.x document, in JSON or YAML, to Swagger 2.0.
+//
+// Constructs without a Swagger 2.0 equivalent are either approximated or preserved as vendor
+// extensions (e.g. oneOf becomes x-oneOf), each reported by a DiagnosticUnsupportedSwagger2 sent to
+// Options.Logger, or logged as a warning without one, and added to Options.Diagnostics, in the order of
+// the keys of the document. The Rules set its severity, and FailOnWarning fails the conversion.
+func DowngradeOpenAPI3For(data []byte, opts *Options) (*spec.Swagger, error) {
+	doc, err := parseSpecDocument(data)
+	if err != nil {
+		return nil, err
+	}
+	if _, isOpenAPI3 := openAPIVersion(doc); !isOpenAPI3 {
+		return nil, errors.New("not an OpenAPI 3.x document: the openapi field is missing")
+	}
+
+	return downgradeOpenAPI3(doc, opts)
+}
+
+func parseSpecDocument(data []byte) (map[string]any, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, fmt.Errorf("failed to parse spec as JSON or YAML: %w", err)
+	}
+	var yamlValue any
+	if len(node.Content) > 0 {
+		if root := node.Content[0]; root.Kind == yaml.MappingNode {
+			orderScopes(metaLookup(root, "securityDefinitions"))
+		}
+		if err := node.Decode(&yamlValue); err != nil {
+			return nil, fmt.Errorf("failed to parse spec as JSON or YAML: %w", err)
+		}
+	}
+	jsonValue, err := fmts.YAMLToJSON(yamlValue)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal(jsonValue, &doc); err != nil {
+		return nil, fmt.Errorf("spec document must be an object: %w", err)
+	}
+
+	return doc, nil
+}
+
+func openAPIVersion(doc map[string]any) (string, bool) {
+	version, ok := doc["openapi"]
+	if !ok {
+		return "", false
+	}
+	v := fmt.Sprint(version)
+
+	return v, strings.HasPrefix(v, "3.")
+}
+
+func specFromDocument(doc map[string]any) (*spec.Swagger, error) {
+	jazon, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	swspec := new(spec.Swagger)
+	if err := json.Unmarshal(jazon, swspec); err != nil {
+		return nil, fmt.Errorf("invalid swagger 2.0 document: %w", err)
+	}
+
+	return swspec, nil
+}
+
+// downgrader converts an OpenAPI 3.x document, represented as generic JSON values, to Swagger 2.0.
+type downgrader struct {
+	components map[string]any
+	report     func(Diagnostic)
+}
+
+func downgradeOpenAPI3(doc map[string]any, opts *Options) (*spec.Swagger, error) {
+	_, configured, err := compileRules(opts.Rules)
+	if err != nil {
+		return nil, err
+	}
+	reporter := &typeIndex{severities: configured, logger: opts.Logger}
+	d := &downgrader{components: asObject(doc["components"]), report: reporter.diagnoseMessage}
+
+	out := map[string]any{"swagger": "2.0"}
+	for _, key := range sortedKeys(doc) {
+		value := doc[key]
+		switch {
+		case key == "info":
+			out["info"] = d.info(asObject(value))
+		case key == "tags", key == "externalDocs", key == "security", rxAllowedExtensions.MatchString(key):
+			out[key] = value
+		case key == "servers":
+			d.servers(asList(value), out)
+		case key == "paths":
+			out["paths"] = d.paths(asObject(value))
+		case key == "openapi", key == "components":
+		default:
+			d.warnf("dropped unsupported top-level field %q", key)
+		}
+	}
+
+	d.componentsTo(out)
+	rewriteComponentRefs(out)
+
+	if opts.Diagnostics != nil {
+		*opts.Diagnostics = append(*opts.Diagnostics, reporter.reportedDiagnostics()...)
+	}
+	if err := reporter.checkWarnings(opts.FailOnWarning); err != nil {
+		return nil, err
+	}
+
+	return specFromDocument(out)
+}
+
+// warnf reports a construct approximated or dropped: an OpenAPI 3.x document has no positions.
+func (d *downgrader) warnf(format string, args ...any) {
+	d.report(Diagnostic{Code: DiagnosticUnsupportedSwagger2, Message: "openapi downgrade: " + fmt.Sprintf(format, args...)})
+}
+
+func (d *downgrader) info(info map[string]any) map[string]any {
+	result := make(map[string]any, len(info))
+	for key, value := range info {
+		switch key {
+		case "summary":
+			if _, hasDescription := info["description"]; !hasDescription {
+				result["description"] = value
+			}
+		case "license":
+			license := maps.Clone(asObject(value))
+			delete(license, "identifier")
+			result[key] = license
+		default:
+			result[key] = value
+		}
+	}
+
+	return result
+}
+
+// servers derives host, basePath and schemes from the server URLs, using the default value of server variables.
+func (d *downgrader) servers(servers []any, out map[string]any) {
+	var schemes []string
+	for i, item := range servers {
+		server := asObject(item)
+		raw := asString(server["url"])
+		for name, variable := range asObject(server["variables"]) {
+			raw = strings.ReplaceAll(raw, "{"+name+"}", asString(asObject(variable)["default"]))
+		}
+		u, err := url.Parse(raw)
+		if err != nil {
+			d.warnf("dropped invalid server url %q: %v", raw, err)
+			continue
+		}
+		if i == 0 {
+			if u.Host != "" {
+				out["host"] = u.Host
+			}
+			if u.Path != "" {
+				out["basePath"] = u.Path
+			}
+		} else if host, _ := out["host"].(string); u.Host != host || (u.Path != "" && u.Path != out["basePath"]) {
+			d.warnf("dropped server %q: swagger 2.0 supports a single host and base path", raw)
+			continue
+		}
+		if u.Scheme != "" && !slices.Contains(schemes, u.Scheme) {
+			schemes = append(schemes, u.Scheme)
+		}
+	}
+	if len(schemes) > 0 {
+		out["schemes"] = schemes
+	}
+}
+
+func (d *downgrader) componentsTo(out map[string]any) {
+	for _, kind := range sortedKeys(d.components) {
+		section := asObject(d.components[kind])
+		switch kind {
+		case "schemas":
+			definitions := make(map[string]any, len(section))
+			for _, name := range sortedKeys(section) {
+				definitions[name] = d.schema(section[name])
+			}
+			out["definitions"] = definitions
+		case "parameters":
+			parameters := make(map[string]any, len(section))
+			for _, name := range sortedKeys(section) {
+				if converted := d.parameter(asObject(section[name])); converted != nil {
+					parameters[name] = converted
+				}
+			}
+			out["parameters"] = parameters
+		case "responses":
+			responses := make(map[string]any, len(section))
+			for _, name := range sortedKeys(section) {
+				responses[name] = d.response(asObject(section[name]), nil)
+			}
+			out["responses"] = responses
+		case "securitySchemes":
+			definitions := make(map[string]any, len(section))
+			for _, name := range sortedKeys(section) {
+				if converted := d.securityScheme(name, asObject(section[name])); converted != nil {
+					definitions[name] = converted
+				}
+			}
+			out["securityDefinitions"] = definitions
+		case "requestBodies", "headers", "examples":
+			// resolved where they are used
+		default:
+			d.warnf("dropped unsupported components section %q", kind)
+		}
+	}
+}
+
+func (d *downgrader) securityScheme(name string, scheme map[string]any) map[string]any {
+	result := make(map[string]any)
+	copyFields(result, scheme, "description")
+	copyExtensions(result, scheme)
+
+	switch asString(scheme["type"]) {
+	case "apiKey":
+		if asString(scheme["in"]) == "cookie" {
+			d.warnf("dropped security scheme %q: cookie api keys are not supported", name)
+			return nil
+		}
+		result["type"] = "apiKey"
+		copyFields(result, scheme, "name", "in")
+	case "http":
+		switch strings.ToLower(asString(scheme["scheme"])) {
+		case "basic":
+			result["type"] = "basic"
+		case "bearer":
+			d.warnf("security scheme %q: bearer authentication converted to an Authorization header api key", name)
+			result["type"] = "apiKey"
+			result["name"] = "Authorization"
+			result["in"] = "header"
+		default:
+			d.warnf("dropped security scheme %q: unsupported http scheme %q", name, scheme["scheme"])
+			return nil
+		}
+	case "oauth2":
+		flows := asObject(scheme["flows"])
+		for _, flow := range []struct{ from, to string }{
+			{"authorizationCode", "accessCode"},
+			{"implicit", "implicit"},
+			{"password", "password"},
+			{"clientCredentials", "application"},
+		} {
+			settings, ok := flows[flow.from]
+			if !ok {
+				continue
+			}
+			if len(flows) > 1 {
+				d.warnf("security scheme %q: only the %s flow is retained", name, flow.from)
+			}
+			result["type"] = "oauth2"
+			result["flow"] = flow.to
+			copyFields(result, asObject(settings), "authorizationUrl", "tokenUrl", "scopes")
+			return result
+		}
+		d.warnf("dropped security scheme %q: no supported oauth2 flow", name)
+		return nil
+	default:
+		d.warnf("dropped security scheme %q: unsupported type %q", name, scheme["type"])
+		return nil
+	}
+
+	return result
+}
+
+func (d *downgrader) paths(paths map[string]any) map[string]any {
+	result := make(map[string]any, len(paths))
+	for _, pth := range sortedKeys(paths) {
+		item := asObject(paths[pth])
+		converted := make(map[string]any, len(item))
+		for _, key := range sortedKeys(item) {
+			field := item[key]
+			switch {
+			case slices.Contains([]string{"get", "put", "post", "delete", "options", "head", "patch"}, key):
+				converted[key] = d.operation(pth+" "+key, asObject(field))
+			case key == "parameters":
+				converted[key] = d.parameters(asList(field))
+			case key == "$ref" || rxAllowedExtensions.MatchString(key):
+				converted[key] = field
+			case key == "summary" || key == "description":
+				converted["x-"+key] = field
+			default:
+				d.warnf("path %s: dropped unsupported field %q", pth, key)
+			}
+		}
+		result[pth] = converted
+	}
+
+	return result
+}
+
+func (d *downgrader) operation(name string, op map[string]any) map[string]any {
+	result := make(map[string]any, len(op))
+	var produces []string
+
+	// NOTE: sorted keys process parameters before requestBody
+	for _, key := range sortedKeys(op) {
+		value := op[key]
+		switch key {
+		case "tags", "summary", "description", "externalDocs", "operationId", "deprecated", "security":
+			result[key] = value
+		case "parameters":
+			result[key] = append(asList(result[key]), d.parameters(asList(value))...)
+		case "requestBody":
+			params, consumes := d.requestBody(name, d.resolve(asObject(value)))
+			result["parameters"] = append(asList(result["parameters"]), params...)
+			if len(consumes) > 0 {
+				result["consumes"] = consumes
+			}
+		case "responses":
+			responses := make(map[string]any)
+			byCode := asObject(value)
+			for _, code := range sortedKeys(byCode) {
+				responses[code] = d.response(asObject(byCode[code]), &produces)
+			}
+			result[key] = responses
+		default:
+			if rxAllowedExtensions.MatchString(key) {
+				result[key] = value
+				continue
+			}
+			d.warnf("operation %s: dropped unsupported field %q", name, key)
+		}
+	}
+	if len(produces) > 0 {
+		result["produces"] = produces
+	}
+
+	return result
+}
+
+func (d *downgrader) parameters(params []any) []any {
+	result := make([]any, 0, len(params))
+	for _, param := range params {
+		if converted := d.parameter(asObject(param)); converted != nil {
+			result = append(result, converted)
+		}
+	}
+
+	return result
+}
+
+func (d *downgrader) parameter(param map[string]any) map[string]any {
+	if ref, ok := param["$ref"]; ok {
+		return map[string]any{"$ref": ref}
+	}
+
+	name := asString(param["name"])
+	in := asString(param["in"])
+	if in == "cookie" {
+		d.warnf("dropped cookie parameter %q", name)
+		return nil
+	}
+
+	schema, hasSchema := param["schema"]
+	if !hasSchema {
+		d.warnf("dropped parameter %q: only parameters described by a schema are supported", name)
+		return nil
+	}
+
+	result := d.simpleSchema(asObject(d.schema(schema)))
+	copyFields(result, param, "name", "in", "description", "required", "allowEmptyValue")
+	copyExtensions(result, param)
+	if example, ok := param["example"]; ok {
+		result["x-example"] = example
+	}
+	if deprecated, _ := param["deprecated"].(bool); deprecated {
+		result[deprecatedExtension] = true
+	}
+
+	if asString(result["type"]) == "array" {
+		explode := in == "query" || in == "cookie"
+		if value, ok := param["explode"].(bool); ok {
+			explode = value
+		}
+		switch asString(param["style"]) {
+		case "spaceDelimited":
+			result["collectionFormat"] = "ssv"
+		case "pipeDelimited":
+			result["collectionFormat"] = "pipes"
+		default:
+			if explode {
+				result["collectionFormat"] = "multi"
+			} else {
+				result["collectionFormat"] = "csv"
+			}
+		}
+	}
+
+	return result
+}
+
+// simpleSchema restricts a schema to the subset allowed for non-body parameters, items and headers.
+func (d *downgrader) simpleSchema(schema map[string]any) map[string]any {
+	result := make(map[string]any, len(schema))
+	for key, value := range schema {
+		switch key {
+		case "type", "format", "default", "maximum", "exclusiveMaximum", "minimum", "exclusiveMinimum",
+			"maxLength", "minLength", "pattern", "maxItems", "minItems", "uniqueItems", "enum", "multipleOf":
+			result[key] = value
+		case "items":
+			result[key] = d.simpleSchema(asObject(value))
+		default:
+			if rxAllowedExtensions.MatchString(key) {
+				result[key] = value
+			}
+		}
+	}
+	if _, isRef := schema["$ref"]; isRef {
+		d.warnf("simple parameter or header schema %v can't be a $ref: assuming a string", schema["$ref"])
+		result["type"] = "string"
+	}
+
+	return result
+}
+
+func (d *downgrader) requestBody(name string, body map[string]any) ([]any, []string) {
+	content := asObject(body["content"])
+	consumes := sortedKeys(content)
+	if len(consumes) == 0 {
+		return nil, nil
+	}
+
+	for _, mediaType := range consumes {
+		if mediaType != "multipart/form-data" && mediaType != "application/x-www-form-urlencoded" {
+			continue
+		}
+		schema := asObject(d.schema(d.resolve(asObject(asObject(content[mediaType])["schema"]))))
+		required := asList(schema["required"])
+		properties := asObject(schema["properties"])
+		params := make([]any, 0, len(properties))
+		for _, prop := range sortedKeys(properties) {
+			param := d.simpleSchema(asObject(properties[prop]))
+			if asString(param["format"]) == "binary" {
+				param = map[string]any{"type": "file"}
+			}
+			param["name"] = prop
+			param["in"] = "formData"
+			copyFields(param, asObject(properties[prop]), "description")
+			if slices.Contains(required, any(prop)) {
+				param["required"] = true
+			}
+			params = append(params, param)
+		}
+
+		return params, consumes
+	}
+
+	mediaType := preferredMediaType(consumes)
+	if len(consumes) > 1 {
+		d.warnf("operation %s: only the %s request body schema is retained", name, mediaType)
+	}
+	param := map[string]any{"name": "body", "in": "body"}
+	copyFields(param, body, "description", "required")
+	copyExtensions(param, body)
+	if schema, ok := asObject(content[mediaType])["schema"]; ok {
+		param["schema"] = d.schema(schema)
+	} else {
+		param["schema"] = map[string]any{}
+	}
+	if name, ok := body["x-codegen-request-body-name"]; ok {
+		param["name"] = name
+	}
+	if examples := d.namedExamples("operation "+name, asObject(content[mediaType])); len(examples) > 0 {
+		param[namedExamplesExtension] = examples
+	}
+
+	return []any{param}, consumes
+}
+
+func (d *downgrader) response(response map[string]any, produces *[]string) map[string]any {
+	if ref, ok := response["$ref"]; ok {
+		return map[string]any{"$ref": ref}
+	}
+
+	result := make(map[string]any, len(response))
+	result["description"] = asString(response["description"])
+	copyExtensions(result, response)
+
+	content := asObject(response["content"])
+	mediaTypes := sortedKeys(content)
+	if produces != nil {
+		for _, mediaType := range mediaTypes {
+			if !slices.Contains(*produces, mediaType) {
+				*produces = append(*produces, mediaType)
+			}
+		}
+	}
+	if len(mediaTypes) > 0 {
+		preferred := preferredMediaType(mediaTypes)
+		media := asObject(content[preferred])
+		if schema, ok := media["schema"]; ok {
+			result["schema"] = d.schema(schema)
+		}
+		if example, ok := media["example"]; ok {
+			result["examples"] = map[string]any{preferred: example}
+		}
+		if examples := d.namedExamples("response", media); len(examples) > 0 {
+			result[namedExamplesExtension] = examples
+		}
+		// the schemas of the other media types, when they differ
+		schemas := make(map[string]any)
+		for _, mediaType := range mediaTypes {
+			schema, ok := asObject(content[mediaType])["schema"]
+			if ok && mediaType != preferred && !reflect.DeepEqual(schema, media["schema"]) {
+				schemas[mediaType] = d.schema(schema)
+			}
+		}
+		if len(schemas) > 0 {
+			result[xContentSchemas] = schemas
+		}
+	}
+
+	if headers := asObject(response["headers"]); len(headers) > 0 {
+		converted := make(map[string]any, len(headers))
+		for _, name := range sortedKeys(headers) {
+			header := d.resolve(asObject(headers[name]))
+			h := d.simpleSchema(asObject(d.schema(header["schema"])))
+			copyFields(h, header, "description")
+			converted[name] = h
+		}
+		result["headers"] = converted
+	}
+
+	return result
+}
+
+func (d *downgrader) schema(value any) any {
+	schema := asObject(value)
+	if schema == nil {
+		return value
+	}
+
+	result := make(map[string]any, len(schema))
+	for _, key := range sortedKeys(schema) {
+		field := schema[key]
+		switch key {
+		case "properties", "patternProperties":
+			properties := make(map[string]any)
+			byName := asObject(field)
+			for _, name := range sortedKeys(byName) {
+				properties[name] = d.schema(byName[name])
+			}
+			result[key] = properties
+		case "items", "additionalProperties", "not":
+			result[key] = d.schema(field)
+		case "allOf":
+			members := make([]any, 0)
+			for _, member := range asList(field) {
+				members = append(members, d.schema(member))
+			}
+			result[key] = members
+		case "oneOf", "anyOf":
+			members := make([]any, 0)
+			nullable := false
+			for _, member := range asList(field) {
+				if asString(asObject(member)["type"]) == "null" {
+					nullable = true
+					continue
+				}
+				members = append(members, d.schema(member))
+			}
+			if nullable {
+				result["x-nullable"] = true
+			}
+			if len(members) == 1 {
+				for k, v := range asObject(members[0]) {
+					result[k] = v
+				}
+				continue
+			}
+			d.warnf("%s is not supported by swagger 2.0: preserved as x-%s", key, key)
+			result["x-"+key] = members
+		case "nullable":
+			if nullable, _ := field.(bool); nullable {
+				result["x-nullable"] = true
+			}
+		case "type":
+			types, isList := field.([]any)
+			if !isList {
+				result[key] = field
+				continue
+			}
+			var kept []any
+			for _, t := range types {
+				if asString(t) == "null" {
+					result["x-nullable"] = true
+					continue
+				}
+				kept = append(kept, t)
+			}
+			if len(kept) == 1 {
+				result[key] = kept[0]
+			} else if len(kept) > 1 {
+				d.warnf("multiple types %v are not supported by swagger 2.0: preserved as x-types", kept)
+				result["x-types"] = kept
+			}
+		case "const":
+			result["enum"] = []any{field}
+		case "examples":
+			if examples := asList(field); len(examples) > 0 {
+				result["example"] = examples[0]
+			}
+		case "exclusiveMinimum", "exclusiveMaximum":
+			if _, isBool := field.(bool); isBool {
+				result[key] = field
+				continue
+			}
+			// JSON schema 2020-12 form (OpenAPI 3.1): the bound is the value
+			result[key] = true
+			result[strings.Replace(key, "exclusiveM", "m", 1)] = field
+		case "deprecated":
+			if deprecated, _ := field.(bool); deprecated {
+				result[deprecatedExtension] = true
+			}
+		case "writeOnly", "contentMediaType", "contentEncoding", "$schema", "$id":
+			d.warnf("dropped unsupported schema keyword %q", key)
+		case "discriminator":
+			result[key] = asString(asObject(field)["propertyName"])
+		default:
+			result[key] = field
+		}
+	}
+
+	return result
+}
+
+// namedExamples converts the examples of a media type to the named examples of x-examples, resolving their
+// references to the components. The examples without a value, e.g. with an externalValue, are dropped.
+func (d *downgrader) namedExamples(where string, media map[string]any) map[string]any {
+	examples := asObject(media["examples"])
+	named := make(map[string]any, len(examples))
+	for _, name := range sortedKeys(examples) {
+		example := d.resolve(asObject(examples[name]))
+		value, ok := example["value"]
+		if !ok {
+			d.warnf("%s: dropped the example %s, which has no value", where, name)
+			continue
+		}
+		converted := map[string]any{"value": value}
+		copyFields(converted, example, "summary", "description")
+		named[name] = converted
+	}
+	return named
+}
+
+// resolve follows a local $ref to the components section if any.
+func (d *downgrader) resolve(value map[string]any) map[string]any {
+	for range 32 {
+		ref, ok := value["$ref"].(string)
+		if !ok {
+			return value
+		}
+		parts := strings.Split(strings.TrimPrefix(ref, "#/components/"), "/")
+		if !strings.HasPrefix(ref, "#/components/") || len(parts) != 2 {
+			return value
+		}
+		target, found := asObject(d.components[parts[0]])[unescapePointer(parts[1])]
+		if !found {
+			return value
+		}
+		value = asObject(target)
+	}
+
+	return value
+}
+
+// rewriteComponentRefs rewrites $refs to components as references to their Swagger 2.0 counterparts.
+func rewriteComponentRefs(value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if ref, isRef := field.(string); isRef && key == "$ref" {
+				v[key] = strings.NewReplacer(
+					"#/components/schemas/", definitionsPrefix,
+					"#/components/parameters/", "#/parameters/",
+					"#/components/responses/", "#/responses/",
+				).Replace(ref)
+				continue
+			}
+			rewriteComponentRefs(field)
+		}
+	case []any:
+		for _, item := range v {
+			rewriteComponentRefs(item)
+		}
+	}
+}
+
+func preferredMediaType(mediaTypes []string) string {
+	for _, mediaType := range mediaTypes {
+		if mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") {
+			return mediaType
+		}
+	}
+
+	return mediaTypes[0]
+}
+
+// pointerUnescaper unescapes the tokens of the JSON pointers, see unescapePointer.
+var pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
+func unescapePointer(token string) string {
+	return pointerUnescaper.Replace(token)
+}
+
+func copyFields(dst, src map[string]any, keys ...string) {
+	for _, key := range keys {
+		if value, ok := src[key]; ok {
+			dst[key] = value
+		}
+	}
+}
+
+func copyExtensions(dst, src map[string]any) {
+	for key, value := range src {
+		if rxAllowedExtensions.MatchString(key) {
+			dst[key] = value
+		}
+	}
+}
+
+func asObject(value any) map[string]any {
+	m, _ := value.(map[string]any)
+	return m
+}
+
+func asList(value any) []any {
+	l, _ := value.([]any)
+	return l
+}
+
+func asString(value any) string {
+	s, _ := value.(string)
+	return s
+}
@@ -0,0 +1,289 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-openapi/spec"
+)
+
+const openAPI3Fixture = `openapi: 3.0.3
+info:
+  title: Petstore
+  version: 1.0.0
+servers:
+  - url: https://{region}.example.com/v1
+    variables:
+      region:
+        default: eu
+  - url: http://eu.example.com/v1
+tags:
+  - name: pets
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      tags: [pets]
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            format: int32
+            maximum: 100
+        - name: kind
+          in: query
+          style: form
+          explode: false
+          schema:
+            type: array
+            items:
+              type: string
+        - name: session
+          in: cookie
+          schema:
+            type: string
+      responses:
+        "200":
+          description: the pets
+          headers:
+            X-Rate-Limit:
+              schema:
+                type: integer
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Pet'
+            text/csv:
+              schema:
+                type: string
+    post:
+      operationId: createPet
+      requestBody:
+        $ref: '#/components/requestBodies/NewPet'
+      responses:
+        default:
+          $ref: '#/components/responses/Error'
+  /pets/{id}/photo:
+    put:
+      operationId: uploadPhoto
+      parameters:
+        - $ref: '#/components/parameters/PetID'
+      requestBody:
+        content:
+          multipart/form-data:
+            schema:
+              type: object
+              required: [file]
+              properties:
+                file:
+                  type: string
+                  format: binary
+                caption:
+                  type: string
+      responses:
+        "204":
+          description: uploaded
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+        tag:
+          type: string
+          nullable: true
+        owner:
+          oneOf:
+            - $ref: '#/components/schemas/Owner'
+            - type: "null"
+    Owner:
+      type: object
+      properties:
+        name:
+          type: string
+  parameters:
+    PetID:
+      name: id
+      in: path
+      required: true
+      schema:
+        type: string
+  requestBodies:
+    NewPet:
+      required: true
+      content:
+        application/json:
+          schema:
+            $ref: '#/components/schemas/Pet'
+  responses:
+    Error:
+      description: unexpected error
+      content:
+        application/json:
+          schema:
+            type: object
+  securitySchemes:
+    token:
+      type: http
+      scheme: bearer
+    basic:
+      type: http
+      scheme: basic
+`
+
+func TestParseInputSpec(t *testing.T) {
+	t.Run("should refuse OpenAPI 3 YAML input", func(t *testing.T) {
+		_, err := ParseInputSpec([]byte(openAPI3Fixture), false)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrOpenAPI3Input))
+		assert.Contains(t, err.Error(), "openapi: 3.0.3")
+		assert.Contains(t, err.Error(), "codescan convert")
+	})
+
+	t.Run("should refuse OpenAPI 3 JSON input", func(t *testing.T) {
+		_, err := ParseInputSpec([]byte(`{"openapi": "3.1.0", "info": {"title": "x", "version": "1"}, "paths": {}}`), false)
+		require.ErrorIs(t, err, ErrOpenAPI3Input)
+	})
+
+	t.Run("should refuse other swagger versions", func(t *testing.T) {
+		_, err := ParseInputSpec([]byte(`swagger: "1.2"`), false)
+		require.Error(t, err)
+	})
+
+	t.Run("should parse swagger 2.0 input", func(t *testing.T) {
+		swspec, err := ParseInputSpec([]byte(`{"swagger": "2.0", "info": {"title": "x", "version": "1"}, "host": "example.com"}`), false)
+		require.NoError(t, err)
+		assert.Equal(t, "example.com", swspec.Host)
+	})
+
+	t.Run("should downgrade OpenAPI 3 input on demand", func(t *testing.T) {
+		swspec, err := ParseInputSpec([]byte(openAPI3Fixture), true)
+		require.NoError(t, err)
+		assert.Equal(t, "2.0", swspec.Swagger)
+		assert.Equal(t, "Petstore", swspec.Info.Title)
+	})
+}
+
+func TestDowngradeOpenAPI3(t *testing.T) {
+	swspec, err := DowngradeOpenAPI3([]byte(openAPI3Fixture))
+	require.NoError(t, err)
+
+	assert.Equal(t, "eu.example.com", swspec.Host)
+	assert.Equal(t, "/v1", swspec.BasePath)
+	assert.Equal(t, []string{"https", "http"}, swspec.Schemes)
+
+	t.Run("definitions", func(t *testing.T) {
+		require.Contains(t, swspec.Definitions, "Pet")
+		pet := swspec.Definitions["Pet"]
+		assert.Equal(t, []string{"name"}, pet.Required)
+		assert.Equal(t, true, pet.Properties["tag"].Extensions["x-nullable"])
+		owner := pet.Properties["owner"]
+		assert.Equal(t, "#/definitions/Owner", owner.Ref.String())
+		assert.Equal(t, true, owner.Extensions["x-nullable"])
+	})
+
+	t.Run("parameters", func(t *testing.T) {
+		list := swspec.Paths.Paths["/pets"].Get
+		require.NotNil(t, list)
+		require.Len(t, list.Parameters, 2)
+		assert.Equal(t, "limit", list.Parameters[0].Name)
+		assert.Equal(t, "integer", list.Parameters[0].Type)
+		assert.Equal(t, "int32", list.Parameters[0].Format)
+		require.NotNil(t, list.Parameters[0].Maximum)
+		assert.InDelta(t, 100, *list.Parameters[0].Maximum, 1e-9)
+		assert.Equal(t, "csv", list.Parameters[1].CollectionFormat)
+		assert.Equal(t, []string{"application/json", "text/csv"}, list.Produces)
+
+		upload := swspec.Paths.Paths["/pets/{id}/photo"].Put
+		require.NotNil(t, upload)
+		require.Len(t, upload.Parameters, 3)
+		assert.Equal(t, "#/parameters/PetID", upload.Parameters[0].Ref.String())
+		assert.Equal(t, "caption", upload.Parameters[1].Name)
+		assert.Equal(t, "formData", upload.Parameters[1].In)
+		assert.Equal(t, "file", upload.Parameters[2].Type)
+		assert.True(t, upload.Parameters[2].Required)
+		assert.Equal(t, []string{"multipart/form-data"}, upload.Consumes)
+	})
+
+	t.Run("request bodies", func(t *testing.T) {
+		create := swspec.Paths.Paths["/pets"].Post
+		require.NotNil(t, create)
+		require.Len(t, create.Parameters, 1)
+		body := create.Parameters[0]
+		assert.Equal(t, "body", body.In)
+		assert.True(t, body.Required)
+		require.NotNil(t, body.Schema)
+		assert.Equal(t, "#/definitions/Pet", body.Schema.Ref.String())
+		assert.Equal(t, "#/responses/Error", create.Responses.Default.Ref.String())
+	})
+
+	t.Run("responses", func(t *testing.T) {
+		ok := swspec.Paths.Paths["/pets"].Get.Responses.StatusCodeResponses[200]
+		assert.Equal(t, "the pets", ok.Description)
+		require.NotNil(t, ok.Schema)
+		assert.Equal(t, "#/definitions/Pet", ok.Schema.Items.Schema.Ref.String())
+		assert.Equal(t, "integer", ok.Headers["X-Rate-Limit"].Type)
+	})
+
+	t.Run("security definitions", func(t *testing.T) {
+		require.Len(t, swspec.SecurityDefinitions, 2)
+		assert.Equal(t, "basic", swspec.SecurityDefinitions["basic"].Type)
+		assert.Equal(t, "apiKey", swspec.SecurityDefinitions["token"].Type)
+		assert.Equal(t, "Authorization", swspec.SecurityDefinitions["token"].Name)
+	})
+
+	t.Run("should report the constructs dropped with diagnostics, in the order of the keys", func(t *testing.T) {
+		var logged, diagnostics []Diagnostic
+		opts := &Options{
+			Logger:      func(diagnostic Diagnostic) { logged = append(logged, diagnostic) },
+			Diagnostics: &diagnostics,
+		}
+		_, err := DowngradeOpenAPI3For([]byte(openAPI3Fixture), opts)
+		require.NoError(t, err)
+
+		require.Len(t, diagnostics, 2)
+		assert.Equal(t, logged, diagnostics)
+		for _, diagnostic := range diagnostics {
+			assert.Equal(t, DiagnosticUnsupportedSwagger2, diagnostic.Code)
+			assert.Equal(t, SeverityWarning, diagnostic.Severity)
+		}
+		assert.Contains(t, diagnostics[0].Message, `dropped cookie parameter "session"`)
+		assert.Contains(t, diagnostics[1].Message, `security scheme "token": bearer authentication`)
+
+		for range 10 {
+			var again []Diagnostic
+			_, err := DowngradeOpenAPI3For([]byte(openAPI3Fixture), &Options{Logger: func(Diagnostic) {}, Diagnostics: &again})
+			require.NoError(t, err)
+			require.Equal(t, diagnostics, again)
+		}
+
+		opts = &Options{Logger: func(Diagnostic) {}, FailOnWarning: true}
+		_, err = DowngradeOpenAPI3For([]byte(openAPI3Fixture), opts)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "2 warnings, see FailOnWarning")
+
+		opts = &Options{Rules: []Rule{{Name: DiagnosticUnsupportedSwagger2, Severity: SeverityOff}}, FailOnWarning: true}
+		_, err = DowngradeOpenAPI3For([]byte(openAPI3Fixture), opts)
+		require.NoError(t, err, "a rule disables the diagnostics")
+	})
+
+	t.Run("should produce a valid JSON document", func(t *testing.T) {
+		jazon, err := json.Marshal(swspec)
+		require.NoError(t, err)
+		var roundTrip spec.Swagger
+		require.NoError(t, json.Unmarshal(jazon, &roundTrip))
+		assert.Equal(t, swspec.Definitions, roundTrip.Definitions)
+	})
+}
@@ -0,0 +1,127 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// structuralExtensions are the extensions which do not take part in the shape of a schema.
+var structuralExtensions = []string{"x-go-name", "x-go-package", "x-order", sourcePositionExtension}
+
+// IdenticalDefinitions is a group of definitions sharing the same structure.
+type IdenticalDefinitions struct {
+	Hash      string   // structural hash shared by the definitions
+	Canonical string   // definition kept when merging: the most referenced one, then the first by name
+	Names     []string // all the definitions of the group, sorted by name
+}
+
+// IdenticalDefinitionGroups lists the groups of structurally identical definitions of a spec.
+//
+// The structure of a definition ignores its descriptions, titles, examples and Go-specific extensions,
+// as well as the order of its properties and required fields. Definitions without properties
+// (e.g. named strings) are not compared. Groups are sorted by canonical name.
+func IdenticalDefinitionGroups(doc *spec.Swagger) []IdenticalDefinitions {
+	if doc == nil {
+		return nil
+	}
+
+	byHash := make(map[string][]string)
+	for _, name := range sortedKeys(doc.Definitions) {
+		definition := doc.Definitions[name]
+		if len(definition.Properties) == 0 {
+			continue
+		}
+		hash, err := structuralHash(definition)
+		if err != nil {
+			debugLogf("could not hash definition %s: %v", name, err)
+			continue
+		}
+		byHash[hash] = append(byHash[hash], name)
+	}
+
+	referrers := definitionReferrers(doc)
+	var groups []IdenticalDefinitions
+	for hash, names := range byHash {
+		if len(names) < 2 {
+			continue
+		}
+		canonical := slices.MaxFunc(names, func(a, b string) int {
+			return cmp.Or(cmp.Compare(len(referrers[a]), len(referrers[b])), strings.Compare(b, a))
+		})
+		groups = append(groups, IdenticalDefinitions{Hash: hash, Canonical: canonical, Names: names})
+	}
+	slices.SortFunc(groups, func(a, b IdenticalDefinitions) int { return strings.Compare(a.Canonical, b.Canonical) })
+
+	return groups
+}
+
+// structuralHash returns a stable hash of the shape of a schema.
+func structuralHash(definition spec.Schema) (string, error) {
+	shape, err := cloneSchema(definition)
+	if err != nil {
+		return "", err
+	}
+
+	walkSchema(&shape, "", func(sch *spec.Schema, _ string) {
+		sch.Description = ""
+		sch.Title = ""
+		sch.Example = nil
+		for _, ext := range structuralExtensions {
+			delete(sch.Extensions, ext)
+		}
+		if len(sch.Extensions) == 0 {
+			sch.Extensions = nil
+		}
+		slices.Sort(sch.Required)
+	})
+
+	// map keys, hence properties, are marshaled in sorted order
+	jazon, err := json.Marshal(shape)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(jazon)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// mergeIdenticalDefinitions merges each group of structurally identical definitions into its canonical
+// definition, rewriting the $refs to the other ones, until no more definitions are identical.
+func mergeIdenticalDefinitions(doc *spec.Swagger) {
+	for {
+		groups := IdenticalDefinitionGroups(doc)
+		if len(groups) == 0 {
+			return
+		}
+
+		canonicals := make(map[string]string)
+		for _, group := range groups {
+			for _, name := range group.Names {
+				if name == group.Canonical {
+					continue
+				}
+				canonicals[name] = group.Canonical
+				delete(doc.Definitions, name)
+			}
+		}
+
+		walkSpecSchemas(doc, func(sch *spec.Schema, _ string) {
+			name, ok := definitionName(sch.Ref)
+			if !ok {
+				return
+			}
+			if canonical, merged := canonicals[name]; merged {
+				sch.Ref = spec.MustCreateRef(definitionsPrefix + escapePointer(canonical))
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"go/token"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStructuralHash(t *testing.T) {
+	base := spec.Schema{}
+	base.Typed("object", "")
+	base.Required = []string{"a", "b"}
+	base.Properties = map[string]spec.Schema{
+		"a": *spec.StringProperty(),
+		"b": *spec.Int64Property(),
+	}
+
+	other := base
+	other.Description = "Another description."
+	other.Required = []string{"b", "a"}
+	other.Properties = map[string]spec.Schema{
+		"b": *spec.Int64Property().WithDescription("The b property."),
+		"a": *spec.StringProperty(),
+	}
+	other.AddExtension("x-go-name", "Other")
+
+	hash, err := structuralHash(base)
+	require.NoError(t, err)
+	otherHash, err := structuralHash(other)
+	require.NoError(t, err)
+	assert.Equal(t, hash, otherHash)
+	assert.Equal(t, "The b property.", other.Properties["b"].Description, "the hashed schema is left untouched")
+
+	other.Properties["c"] = *spec.BoolProperty()
+	otherHash, err = structuralHash(other)
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, otherHash)
+}
+
+func TestIdenticalDefinitions(t *testing.T) {
+	const pkg = "github.com/3idey/codescan/fixtures/goparsing/dedupe"
+
+	t.Run("should report identical definitions", func(t *testing.T) {
+		positions := make(map[string]token.Position)
+		doc, err := Run(&Options{Packages: []string{pkg}, ScanModels: true, DefinitionPositions: positions})
+		require.NoError(t, err)
+
+		groups := IdenticalDefinitionGroups(doc)
+		require.Len(t, groups, 1)
+		assert.Equal(t, "UserSummary", groups[0].Canonical)
+		assert.Equal(t, []string{"UserBrief", "UserSummary"}, groups[0].Names)
+		assert.Len(t, groups[0].Hash, 64)
+
+		assert.Equal(t, IdenticalDefinitionGroups(doc), groups, "groups are stable")
+
+		require.Contains(t, positions, "UserBrief")
+		assert.Equal(t, "users.go", filepath.Base(positions["UserBrief"].Filename))
+		assert.Equal(t, 16, positions["UserBrief"].Line)
+	})
+
+	t.Run("should merge identical definitions", func(t *testing.T) {
+		doc, err := Run(&Options{Packages: []string{pkg}, ScanModels: true, MergeIdentical: true})
+		require.NoError(t, err)
+
+		assert.ElementsMatch(t, []string{"Team", "UserLite", "UserSummary"}, sortedKeys(doc.Definitions))
+		lead := doc.Definitions["Team"].Properties["lead"]
+		assert.Equal(t, "#/definitions/UserSummary", lead.Ref.String())
+		assert.Empty(t, IdenticalDefinitionGroups(doc))
+	})
+}
@@ -0,0 +1,112 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"cmp"
+	"fmt"
+	"go/ast"
+	"net/http"
+	"regexp"
+
+	"github.com/go-openapi/spec"
+)
+
+// injectedDefaultExtension marks the responses injected into the operations, see Options.DefaultResponse.
+const injectedDefaultExtension = "x-injected-default"
+
+// rxNoDefaultResponse matches the directive of the operations without the response of Options.DefaultResponse.
+var rxNoDefaultResponse = regexp.MustCompile(`^[\p{Zs}\t/\*-]*[Nn]o\p{Zs}*-?[Dd]efault\p{Zs}*-?[Rr]esponse\p{Zs}*:\p{Zs}*(true|false)\p{Zs}*$`)
+
+// DefaultResponse is a response every operation has, though none documents it, e.g. the error written by a
+// recovery middleware, see Options.DefaultResponse:
+//
+//	default_response:
+//	  status: 500
+//	  model: github.com/acme/httpkit.PanicError
+//	  description: internal error
+type DefaultResponse struct {
+	// Status is the status code of the response. When 0, it is the default response of the operations.
+	Status int
+	// Model is the body of the response, like ErrorHelper.Model: the Go type qualified by its import path, or
+	// the name of a definition. The response has no schema without one.
+	Model string
+	// Description is the description of the response, by default the text of the status, e.g. Internal
+	// Server Error.
+	Description string
+}
+
+func (d DefaultResponse) enabled() bool {
+	return d != DefaultResponse{}
+}
+
+func checkDefaultResponse(response DefaultResponse) error {
+	if response.Status != 0 && (response.Status < 100 || response.Status > 599) {
+		return fmt.Errorf("invalid status %d", response.Status)
+	}
+	return nil
+}
+
+// noDefaultResponse tells if the comment of an operation has the "No Default Response: true" directive,
+// before the YAML of a swagger:operation.
+func noDefaultResponse(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+	excluded := false
+	for _, cmt := range doc.List {
+		for line := range commentLines(cmt.Text) {
+			if rxBeginYAMLSpec.MatchString(line) {
+				return excluded
+			}
+			if matches := rxNoDefaultResponse.FindStringSubmatch(line); matches != nil {
+				excluded = matches[1] == "true"
+			}
+		}
+	}
+	return excluded
+}
+
+// injectDefaultResponse adds the response of Options.DefaultResponse to an operation, unless it documents
+// its status or a default response, or opts out with the No Default Response directive. It returns the
+// declaration of the model of the response, to build with the definitions.
+func (s *scanCtx) injectDefaultResponse(pp parsedPathContent, op *spec.Operation) ([]*entityDecl, error) {
+	injected := s.opts.DefaultResponse
+	if !injected.enabled() || noDefaultResponse(pp.Remaining) {
+		return nil, nil
+	}
+	if op.Responses != nil {
+		if _, documented := op.Responses.StatusCodeResponses[injected.Status]; op.Responses.Default != nil || documented {
+			return nil, nil
+		}
+	}
+
+	response := spec.NewResponse().WithDescription(cmp.Or(injected.Description, http.StatusText(injected.Status), "unexpected error"))
+	var decls []*entityDecl
+	if injected.Model != "" {
+		ref, decl, err := s.errorModelRef(injected.Model)
+		if err != nil {
+			return nil, fmt.Errorf("the default response of operation %s: %w", op.ID, err)
+		}
+		response.WithSchema(&spec.Schema{SchemaProps: spec.SchemaProps{Ref: ref}})
+		if decl != nil {
+			decls = append(decls, decl)
+		}
+	}
+	response.AddExtension(injectedDefaultExtension, true)
+	s.app.recordPosition(&response.VendorExtensible, pp.Pos)
+
+	if op.Responses == nil {
+		op.Responses = new(spec.Responses)
+	}
+	if injected.Status == 0 {
+		op.Responses.Default = response
+		return decls, nil
+	}
+	if op.Responses.StatusCodeResponses == nil {
+		op.Responses.StatusCodeResponses = make(map[int]spec.Response)
+	}
+	op.Responses.StatusCodeResponses[injected.Status] = *response
+	return decls, nil
+}
@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultResponse(t *testing.T) {
+	const (
+		pkg        = "github.com/3idey/codescan/fixtures/goparsing/defaultresponse"
+		panicError = pkg + "/httpkit.PanicError"
+	)
+	doc, err := Run(&Options{
+		Packages:        []string{pkg},
+		DefaultResponse: DefaultResponse{Status: 500, Model: panicError, Description: "internal error"},
+	})
+	require.NoError(t, err)
+
+	t.Run("should inject the response into the operations without it", func(t *testing.T) {
+		listPets := doc.Paths.Paths["/pets"].Get
+		injected, found := listPets.Responses.StatusCodeResponses[500]
+		require.True(t, found)
+		assert.Equal(t, "internal error", injected.Description)
+		require.NotNil(t, injected.Schema)
+		assert.Equal(t, "#/definitions/PanicError", injected.Schema.Ref.String())
+		assert.Equal(t, true, injected.Extensions[injectedDefaultExtension])
+		assert.Contains(t, listPets.Responses.StatusCodeResponses, 200)
+		assert.Contains(t, doc.Definitions, "PanicError", "the model is built")
+
+		getPet := doc.Paths.Paths["/pets/{id}"].Get
+		assert.Contains(t, getPet.Responses.StatusCodeResponses, 500, "the swagger:operation gets it too")
+	})
+
+	t.Run("should keep the documented 500 and default responses", func(t *testing.T) {
+		createPet := doc.Paths.Paths["/pets"].Post.Responses.StatusCodeResponses[500]
+		assert.Equal(t, " the pet can't be stored", createPet.Description)
+		assert.NotContains(t, createPet.Extensions, injectedDefaultExtension)
+
+		deletePet := doc.Paths.Paths["/pets/{id}"].Delete.Responses
+		assert.NotContains(t, deletePet.StatusCodeResponses, 500)
+		require.NotNil(t, deletePet.Default)
+		assert.NotContains(t, deletePet.Default.Extensions, injectedDefaultExtension)
+	})
+
+	t.Run("should skip the operations with No Default Response", func(t *testing.T) {
+		health := doc.Paths.Paths["/health"].Get
+		assert.Equal(t, []int{200}, sortedKeys(health.Responses.StatusCodeResponses))
+		assert.Equal(t, "Tells whether the API is up, outside of the recovery middleware.", health.Summary)
+		assert.Empty(t, health.Description, "the directive isn't part of the description")
+
+		metrics := doc.Paths.Paths["/metrics"].Get
+		assert.Equal(t, []int{200}, sortedKeys(metrics.Responses.StatusCodeResponses))
+		assert.Equal(t, "Serves the metrics.", metrics.Summary)
+		assert.Empty(t, metrics.Description, "the directive isn't part of the description")
+	})
+
+	t.Run("should inject a default response", func(t *testing.T) {
+		doc, err := Run(&Options{Packages: []string{pkg}, DefaultResponse: DefaultResponse{Model: "Error"}})
+		require.NoError(t, err)
+		injected := doc.Paths.Paths["/pets"].Post.Responses.Default
+		require.NotNil(t, injected, "a documented 500 doesn't cover the default response")
+		assert.Equal(t, "unexpected error", injected.Description)
+		assert.Equal(t, "#/definitions/Error", injected.Schema.Ref.String(), "the model is a definition name")
+		assert.Equal(t, true, injected.Extensions[injectedDefaultExtension])
+		assert.NotContains(t, doc.Definitions, "PanicError")
+
+		assert.NotContains(t, doc.Paths.Paths["/pets/{id}"].Delete.Responses.Default.Extensions, injectedDefaultExtension)
+		assert.Nil(t, doc.Paths.Paths["/health"].Get.Responses.Default)
+	})
+
+	t.Run("should inject nothing by default", func(t *testing.T) {
+		doc, err := Run(&Options{Packages: []string{pkg}})
+		require.NoError(t, err)
+		assert.NotContains(t, doc.Paths.Paths["/pets"].Get.Responses.StatusCodeResponses, 500)
+		assert.NotContains(t, doc.Definitions, "PanicError")
+	})
+
+	t.Run("should fail on an unknown model", func(t *testing.T) {
+		_, err := Run(&Options{Packages: []string{pkg}, DefaultResponse: DefaultResponse{Status: 500, Model: pkg + "/httpkit.Missing"}})
+		require.ErrorContains(t, err, "the default response of operation")
+		require.ErrorContains(t, err, "unknown model "+pkg+"/httpkit.Missing")
+	})
+
+	t.Run("should read the setting of the config file", func(t *testing.T) {
+		path := writeOptionsFile(t, map[string]string{".codescan.yaml": `
+default_response:
+  status: 500
+  model: github.com/acme/httpkit.PanicError
+  description: internal error
+`})
+		opts, err := LoadOptionsFromFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, DefaultResponse{Status: 500, Model: "github.com/acme/httpkit.PanicError", Description: "internal error"}, opts.DefaultResponse)
+
+		path = writeOptionsFile(t, map[string]string{".codescan.yaml": "default_response: {status: 500, modle: Error}\n"})
+		_, err = LoadOptionsFromFile(path)
+		require.ErrorIs(t, err, ErrUnknownOption)
+		assert.Contains(t, err.Error(), `unknown option "default_response.modle", did you mean model?`)
+	})
+
+	t.Run("should reject an invalid status", func(t *testing.T) {
+		_, err := Run(&Options{Packages: []string{pkg}, DefaultResponse: DefaultResponse{Status: 50}})
+		require.EqualError(t, err, "invalid default response: invalid status 50")
+		require.ErrorContains(t, (&Options{DefaultResponse: DefaultResponse{Status: 600}}).Validate(), "invalid status 600")
+	})
+}
@@ -0,0 +1,196 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-openapi/spec"
+)
+
+// setDefaultTag sets the default of a property from the Options.DefaultTag struct tag of its field, e.g.
+// `default:"25"`, parsed as a value of the Go type of the field, see parseDefault. The Default: line of the
+// documentation of the field wins over the tag, and the tag is left out next to a $ref, unless
+// Options.DescWithRef, where it is still checked. A value which isn't one of the type fails the scan.
+func (s *schemaBuilder) setDefaultTag(fld *ast.Field, tpe types.Type, ps *spec.Schema, name string) error {
+	if ps.Default != nil {
+		return nil
+	}
+	value, found, err := s.ctx.defaultTag(fld, tpe)
+	if err != nil {
+		return fmt.Errorf("%v: invalid default of property %s of %s: %w",
+			s.decl.Pkg.Fset.Position(fld.Tag.Pos()), name, s.decl.Obj().Name(), err)
+	}
+	if !found || ps.Ref.String() != "" && !s.ctx.opts.DescWithRef {
+		return nil
+	}
+	ps.Default = value
+	return nil
+}
+
+// setDefaultTag sets the default of a simple parameter from the Options.DefaultTag struct tag of its field,
+// like the properties do. The default of a map encoded as a JSON string is the JSON object of the tag, as a
+// string, and the parameters of the keys of a map have none.
+func (p *parameterBuilder) setDefaultTag(decl *entityDecl, afld *ast.Field, fld *types.Var, ps *spec.Parameter, exploded bool) error {
+	if ps.Default != nil || exploded {
+		return nil
+	}
+	value, found, err := p.ctx.defaultTag(afld, fld.Type())
+	if err != nil {
+		return fmt.Errorf("%v: invalid default of parameter %s of %s: %w",
+			decl.Pkg.Fset.Position(afld.Tag.Pos()), fld.Name(), decl.Obj().Name(), err)
+	}
+	if !found {
+		return nil
+	}
+	if serialization, _ := ps.Extensions.GetString(serializationExtension); serialization == "json" {
+		value, _ = defaultTagValue(afld, p.ctx.opts.DefaultTag)
+	}
+	ps.Default = value
+	return nil
+}
+
+// defaultTag parses the Options.DefaultTag struct tag of a field, if any, as a value of its type.
+func (s *scanCtx) defaultTag(fld *ast.Field, tpe types.Type) (any, bool, error) {
+	value, found := defaultTagValue(fld, s.opts.DefaultTag)
+	if !found {
+		return nil, false, nil
+	}
+	parsed, err := s.parseDefault(value, tpe)
+	return parsed, true, err
+}
+
+// defaultTagValue returns the value of the struct tag of a field with a key, when the key isn't empty.
+func defaultTagValue(fld *ast.Field, key string) (string, bool) {
+	if key == "" || fld.Tag == nil {
+		return "", false
+	}
+	tag, err := strconv.Unquote(fld.Tag.Value)
+	if err != nil {
+		return "", false
+	}
+	return reflect.StructTag(tag).Lookup(key)
+}
+
+// parseDefault parses the default of a struct tag as a value of a Go type, as encoding/json marshals it: a
+// boolean, an integer in the range of its type, a number, or a string; a duration, e.g. 5s, as its
+// nanoseconds; a value of a type mapping by the type of its schema; the comma separated values of a slice, an
+// array or a set, or their JSON array; and the JSON object of a struct or a map. The values of the text
+// marshalers and of the types annotated swagger:strfmt are strings, and those of the other marshalers JSON,
+// or else strings.
+func (s *scanCtx) parseDefault(value string, tpe types.Type) (any, error) {
+	tpe = types.Unalias(tpe)
+	if ptr, isPointer := tpe.(*types.Pointer); isPointer {
+		return s.parseDefault(value, ptr.Elem())
+	}
+	if named, isNamed := tpe.(*types.Named); isNamed && namedTypeKey(named) == "time.Duration" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return int64(duration), nil
+		}
+	}
+	if mapping, isMapped := s.app.typeMapping(tpe); isMapped {
+		return parseExample(value, &mapping)
+	}
+	if elem, isSet := s.app.setElement(tpe); isSet {
+		return s.parseDefaultItems(value, elem)
+	}
+	if isTextMarshaler(tpe) || isTextMarshaler(types.NewPointer(tpe)) {
+		return value, nil
+	}
+
+	switch dtpe := tpe.(type) {
+	case *types.Named:
+		if decl, found := s.DeclForType(dtpe.Obj().Type()); found {
+			if _, isStrfmt := strfmtName(decl.Comments); isStrfmt {
+				return value, nil
+			}
+			if name, isTyped := typeName(decl.Comments); isTyped {
+				var schema spec.Schema
+				if err := swaggerSchemaForType(name, schemaTypable{&schema, 0}); err != nil {
+					return nil, err
+				}
+				return parseExample(value, &schema)
+			}
+		}
+		if hasMarshaler(dtpe) {
+			return parseExample(value, new(spec.Schema))
+		}
+		return s.parseDefault(value, dtpe.Underlying())
+	case *types.Basic:
+		return parseBasicDefault(value, dtpe)
+	case *types.Slice:
+		if isByte(dtpe.Elem()) {
+			return value, nil // base64
+		}
+		return s.parseDefaultItems(value, dtpe.Elem())
+	case *types.Array:
+		return s.parseDefaultItems(value, dtpe.Elem())
+	case *types.Map, *types.Struct:
+		var object map[string]any
+		return object, decodeExample([]byte(value), &object)
+	default:
+		return parseExample(value, new(spec.Schema))
+	}
+}
+
+// parseDefaultItems parses the default of a slice: the comma separated values of its elements, or a JSON
+// array, e.g. for the elements with commas.
+func (s *scanCtx) parseDefaultItems(value string, elem types.Type) (any, error) {
+	if strings.HasPrefix(strings.TrimSpace(value), "[") {
+		var array []any
+		return array, decodeExample([]byte(value), &array)
+	}
+	items := []any{}
+	if value == "" {
+		return items, nil
+	}
+	for item := range strings.SplitSeq(value, ",") {
+		parsed, err := s.parseDefault(strings.TrimSpace(item), elem)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, parsed)
+	}
+	return items, nil
+}
+
+// parseBasicDefault parses the default of a basic type, checking the range of its integers.
+func parseBasicDefault(value string, basic *types.Basic) (any, error) {
+	info := basic.Info()
+	switch {
+	case info&types.IsBoolean != 0:
+		return strconv.ParseBool(value)
+	case info&types.IsUnsigned != 0:
+		return strconv.ParseUint(value, 10, basicBits(basic))
+	case info&types.IsInteger != 0:
+		return strconv.ParseInt(value, 10, basicBits(basic))
+	case info&types.IsFloat != 0:
+		if _, err := strconv.ParseFloat(value, basicBits(basic)); err != nil {
+			return nil, err
+		}
+		return strconv.ParseFloat(value, 64) // float32 values are written as they are, not rounded
+	default:
+		return value, nil
+	}
+}
+
+// basicBits is the size of a basic numeric type, in bits.
+func basicBits(basic *types.Basic) int {
+	switch basic.Kind() {
+	case types.Int8, types.Uint8:
+		return 8
+	case types.Int16, types.Uint16:
+		return 16
+	case types.Int32, types.Uint32, types.Float32:
+		return 32
+	default:
+		return 64
+	}
+}
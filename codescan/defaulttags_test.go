@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultTags(t *testing.T) {
+	const pkg = "github.com/3idey/codescan/fixtures/goparsing/defaulttags"
+	doc, err := Run(&Options{Packages: []string{pkg}, DefaultTag: "default"})
+	require.NoError(t, err)
+	props := doc.Definitions["Config"].Properties
+
+	t.Run("should parse the defaults as values of the types of the fields", func(t *testing.T) {
+		for name, expected := range map[string]any{
+			"port":    int64(8080),
+			"workers": uint64(4),
+			"ratio":   0.1,
+			"debug":   true,
+			"name":    "server",
+			"hosts":   []any{"a.example.com", "b.example.com"},
+			"started": "2024-01-01T00:00:00Z",
+			"timeout": int64(5000000000),
+		} {
+			assert.Equal(t, expected, props[name].Default, name)
+		}
+	})
+
+	t.Run("should parse the JSON arrays and objects", func(t *testing.T) {
+		for name, expected := range map[string]string{
+			"ports":  `[80,443]`,
+			"labels": `{"team":"core"}`,
+			"retry":  `{"count":3}`,
+		} {
+			jazon, err := json.Marshal(props[name].Default)
+			require.NoError(t, err)
+			assert.JSONEq(t, expected, string(jazon), name)
+		}
+	})
+
+	t.Run("should let the Default annotation win over the tag", func(t *testing.T) {
+		assert.Equal(t, 30, props["idle"].Default)
+	})
+
+	t.Run("should leave out the defaults next to a $ref, unless DescWithRef", func(t *testing.T) {
+		limits := props["limits"]
+		assert.Equal(t, "#/definitions/Limits", limits.Ref.String())
+		assert.Nil(t, limits.Default)
+
+		withRef, err := Run(&Options{Packages: []string{pkg}, DefaultTag: "default", DescWithRef: true})
+		require.NoError(t, err)
+		assert.Equal(t, "info", withRef.Definitions["Config"].Properties["level"].Default)
+	})
+
+	t.Run("should set the defaults of the parameters", func(t *testing.T) {
+		params := doc.Paths.Paths["/config"].Get.Parameters
+		require.Len(t, params, 2)
+		assert.Equal(t, int64(1), params[0].Default)
+		assert.Equal(t, `{"team": "core"}`, params[1].Default, "a map encoded as JSON has the JSON of the tag")
+	})
+
+	t.Run("should ignore the tags without DefaultTag", func(t *testing.T) {
+		doc, err := Run(&Options{Packages: []string{pkg}})
+		require.NoError(t, err)
+		assert.Nil(t, doc.Definitions["Config"].Properties["port"].Default)
+		assert.Equal(t, 30, doc.Definitions["Config"].Properties["idle"].Default)
+		assert.Nil(t, doc.Paths.Paths["/config"].Get.Parameters[0].Default)
+	})
+
+	t.Run("should fail on the defaults which aren't values of the type", func(t *testing.T) {
+		_, err := Run(&Options{Packages: []string{pkg + "/bad"}, ScanModels: true, DefaultTag: "default"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "bad/api.go:8:16: invalid default of property workers of Config: strconv.ParseUint: parsing \"300\": value out of range")
+
+		_, err = Run(&Options{Packages: []string{pkg + "/badref"}, ScanModels: true, DefaultTag: "default"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "badref/api.go:13:16: invalid default of property limits of Config", "the tag is checked next to a $ref")
+	})
+}
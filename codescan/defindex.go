@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"errors"
+	"fmt"
+	"go/types"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// DefinitionIndex maps Go types to the definitions of a published spec, so that other specs
+// refer to these definitions instead of emitting their own. See Options.DefinitionIndex.
+type DefinitionIndex struct {
+	// Location is the URL of the published spec, e.g. "https://specs.acme.com/common.json".
+	Location string `json:"location"`
+	// Definitions maps Go types, e.g. "github.com/acme/common/money.Money", to definition names.
+	Definitions map[string]string `json:"definitions"`
+}
+
+// goTypeKey identifies the Go type of a declaration in a definition index.
+func goTypeKey(decl *entityDecl) string {
+	if decl.instanceName != "" {
+		return types.TypeString(decl.Type, nil)
+	}
+	return decl.Obj().Pkg().Path() + "." + decl.Obj().Name()
+}
+
+func checkDefinitionIndex(index *DefinitionIndex) error {
+	if index == nil {
+		return nil
+	}
+	if index.Location == "" {
+		return errors.New("the definition index has no location")
+	}
+	if strings.Contains(index.Location, "#") {
+		return fmt.Errorf("invalid definition index location %q: it contains a fragment", index.Location)
+	}
+	return nil
+}
+
+// indexedRef returns the external $ref of a model found in the definition index, if any.
+//
+// Models of packages known to the index but missing from it are emitted locally, with a diagnostic.
+func (a *typeIndex) indexedRef(decl *entityDecl) (spec.Ref, bool) {
+	if a == nil || a.definitionIndex == nil {
+		return spec.Ref{}, false
+	}
+
+	key := goTypeKey(decl)
+	name, indexed := a.definitionIndex.Definitions[key]
+	if !indexed {
+		if a.indexedPackages[decl.Obj().Pkg().Path()] {
+			a.diagnose(Diagnostic{
+				Pos:     decl.Pkg.Fset.Position(decl.Ident.Pos()),
+				Code:    DiagnosticUnindexedDefinition,
+				Message: fmt.Sprintf("%s is missing from the definition index of %s: emitting a local definition", key, a.definitionIndex.Location),
+			})
+		}
+		return spec.Ref{}, false
+	}
+
+	ref, err := spec.NewRef(a.definitionIndex.Location + definitionsPrefix + escapePointer(name))
+	if err != nil {
+		return spec.Ref{}, false
+	}
+	return ref, true
+}
+
+// fillDefinitionIndex records the Go type of the definitions of a spec.
+func (a *typeIndex) fillDefinitionIndex(index *DefinitionIndex, doc *spec.Swagger) {
+	if index.Definitions == nil {
+		index.Definitions = make(map[string]string)
+	}
+	for name, key := range a.definitionTypes {
+		if _, exists := doc.Definitions[name]; exists {
+			index.Definitions[key] = name
+		}
+	}
+}
@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefinitionIndex(t *testing.T) {
+	const (
+		commonPath  = "github.com/3idey/codescan/fixtures/goparsing/defindex/common"
+		servicePath = "github.com/3idey/codescan/fixtures/goparsing/defindex/service"
+		location    = "https://specs.acme.com/common.json"
+	)
+
+	index := &DefinitionIndex{Location: location}
+	_, err := Run(&Options{Packages: []string{commonPath}, ScanModels: true, DefinitionIndex: index})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		commonPath + ".Money": "Money",
+		commonPath + ".Rate":  "Rate",
+	}, index.Definitions)
+
+	t.Run("indexed types become external refs", func(t *testing.T) {
+		doc, err := Run(&Options{Packages: []string{servicePath}, ScanModels: true, UseDefinitionIndex: index})
+		require.NoError(t, err)
+
+		assert.NotContains(t, doc.Definitions, "Money")
+		assert.NotContains(t, doc.Definitions, "Rate")
+		invoice := doc.Definitions["Invoice"]
+		total := invoice.Properties["total"]
+		assert.Equal(t, location+"#/definitions/Money", total.Ref.String())
+	})
+
+	t.Run("missing entries fall back to local definitions", func(t *testing.T) {
+		partial := &DefinitionIndex{Location: location, Definitions: map[string]string{commonPath + ".Money": "Money"}}
+		sctx, err := newScanCtx(&Options{Packages: []string{servicePath}, ScanModels: true, UseDefinitionIndex: partial})
+		require.NoError(t, err)
+		doc, err := newSpecBuilder(nil, sctx, true).Build()
+		require.NoError(t, err)
+
+		assert.NotContains(t, doc.Definitions, "Money")
+		require.Contains(t, doc.Definitions, "Rate")
+		invoice := doc.Definitions["Invoice"]
+		rate := invoice.Properties["rate"]
+		assert.Equal(t, "#/definitions/Rate", rate.Ref.String())
+
+		unindexed := sctx.app.diagnosticsWithCode(DiagnosticUnindexedDefinition)
+		require.Len(t, unindexed, 1)
+		assert.Contains(t, unindexed[0].Message, commonPath+".Rate is missing from the definition index")
+	})
+
+	t.Run("the index must have a location", func(t *testing.T) {
+		_, err := Run(&Options{Packages: []string{servicePath}, UseDefinitionIndex: &DefinitionIndex{}})
+		require.Error(t, err)
+	})
+}
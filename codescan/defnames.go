@@ -0,0 +1,206 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefinitionNameData is the data of Options.DefinitionNameTemplate, e.g. {{.PackageAlias}}.{{.TypeName}}.
+type DefinitionNameData struct {
+	TypeName     string // name of the Go type, e.g. User
+	PackageName  string // name of its package, e.g. v1
+	PackagePath  string // import path of its package, e.g. github.com/acme/api/users/v1
+	PackageAlias string // alias of the package in Options.PackageAliases, its name by default
+}
+
+// Definition naming strategies, see Options.DefinitionNaming.
+const (
+	// NamingShort names the definitions after their type, e.g. User.
+	NamingShort = "short"
+	// NamingFull names the definitions after their package path and type, e.g. github.com.acme.api.v1.User.
+	NamingFull = "full"
+	// NamingCamelPkg prefixes the type with its package alias, camel cased, e.g. V1User, or UsersV1User for
+	// the alias users.v1 of Options.PackageAliases.
+	NamingCamelPkg = "camel-pkg"
+)
+
+func checkDefinitionNaming(naming string) error {
+	switch naming {
+	case "", NamingShort, NamingFull, NamingCamelPkg:
+		return nil
+	default:
+		return fmt.Errorf("unknown definition naming %q, expected %s, %s or %s", naming, NamingShort, NamingFull, NamingCamelPkg)
+	}
+}
+
+// definitionNamer names the definitions of the types without a name in their swagger:model annotation.
+type definitionNamer struct {
+	option  string // the option naming the definitions, for the errors
+	execute func(DefinitionNameData) (string, error)
+	aliases map[string]string
+}
+
+// newDefinitionNamer returns the namer of the definitions of the options: DefinitionNamer, then
+// DefinitionNaming, then DefinitionNameTemplate, which is checked against a sample type. It returns nil
+// without any, the definitions being named after their type.
+func newDefinitionNamer(opts *Options) (*definitionNamer, error) {
+	if err := checkDefinitionNaming(opts.DefinitionNaming); err != nil {
+		return nil, err
+	}
+	namer := &definitionNamer{aliases: opts.PackageAliases}
+	switch {
+	case opts.DefinitionNamer != nil:
+		namer.option = "DefinitionNamer"
+		var mu sync.Mutex // the schemas are built concurrently, see Options.Concurrency
+		namer.execute = func(data DefinitionNameData) (string, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if name := opts.DefinitionNamer(data.PackagePath, data.TypeName); name != "" {
+				return name, nil
+			}
+			return data.TypeName, nil
+		}
+		return namer, nil
+	case opts.DefinitionNaming == NamingShort:
+		namer.option = "DefinitionNaming"
+		namer.execute = func(data DefinitionNameData) (string, error) { return data.TypeName, nil }
+		return namer, nil
+	case opts.DefinitionNaming == NamingFull:
+		namer.option = "DefinitionNaming"
+		namer.execute = func(data DefinitionNameData) (string, error) {
+			return strings.ReplaceAll(data.PackagePath, "/", ".") + "." + data.TypeName, nil
+		}
+		return namer, nil
+	case opts.DefinitionNaming == NamingCamelPkg:
+		namer.option = "DefinitionNaming"
+		namer.execute = func(data DefinitionNameData) (string, error) {
+			return camelCase(data.PackageAlias) + data.TypeName, nil
+		}
+		return namer, nil
+	case opts.DefinitionNameTemplate == "":
+		return nil, nil
+	}
+
+	tmpl, err := template.New("definition name").Parse(opts.DefinitionNameTemplate)
+	if err != nil {
+		return nil, err
+	}
+	namer.option = "DefinitionNameTemplate"
+	namer.execute = func(data DefinitionNameData) (string, error) {
+		var name strings.Builder
+		if err := tmpl.Execute(&name, data); err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(name.String()), nil
+	}
+
+	sample := DefinitionNameData{TypeName: "User", PackageName: "v1", PackagePath: "example.com/users/v1", PackageAlias: "users.v1"}
+	name, err := namer.execute(sample)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkDefinitionName(name); err != nil {
+		return nil, fmt.Errorf("the template names the sample type %s.%s %q: %w", sample.PackagePath, sample.TypeName, name, err)
+	}
+	if !strings.Contains(name, sample.TypeName) {
+		return nil, fmt.Errorf("the template names the sample type %s.%s %q, without its type name", sample.PackagePath, sample.TypeName, name)
+	}
+	return namer, nil
+}
+
+// camelCase joins the words of a package alias, capitalized, e.g. UsersV1 for users.v1.
+func camelCase(alias string) string {
+	var camel strings.Builder
+	for word := range strings.FieldsFuncSeq(alias, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		first, size := utf8.DecodeRuneInString(word)
+		camel.WriteRune(unicode.ToUpper(first))
+		camel.WriteString(word[size:])
+	}
+	return camel.String()
+}
+
+// name returns the definition name of a declaration, falling back to its type name when the namer fails,
+// which checkNamedDefinition reports.
+func (n *definitionNamer) name(decl *entityDecl) string {
+	name, err := n.execute(n.data(decl))
+	if err != nil {
+		return decl.Ident.Name
+	}
+	return name
+}
+
+func (n *definitionNamer) data(decl *entityDecl) DefinitionNameData {
+	pkg := decl.Obj().Pkg()
+	alias, ok := n.aliases[pkg.Path()]
+	if !ok {
+		alias = pkg.Name()
+	}
+	return DefinitionNameData{
+		TypeName:     decl.Ident.Name,
+		PackageName:  pkg.Name(),
+		PackagePath:  pkg.Path(),
+		PackageAlias: alias,
+	}
+}
+
+// checkNamedDefinition fails when the definition of a declaration has the name of another Go type, giving
+// the positions of both, rather than overriding its definition. Without a naming strategy, see Options.
+// DefinitionNaming, the definition is overridden like it always was, with a definition-name-collision
+// diagnostic. The names of the strategy are also checked, being invalid or failing.
+func (a *typeIndex) checkNamedDefinition(decl *entityDecl, name string) error {
+	if a == nil {
+		return nil
+	}
+	pos := decl.Pkg.Fset.Position(decl.Ident.Pos())
+	namer := a.definitionNamer
+	named := namer != nil && !decl.namedByAnnotation() && decl.instanceName == ""
+	if named {
+		if _, err := namer.execute(namer.data(decl)); err != nil {
+			return fmt.Errorf("%v: %s fails for type %s: %w", pos, namer.option, goTypeKey(decl), err)
+		}
+		if err := checkDefinitionName(name); err != nil {
+			return fmt.Errorf("%v: %s names type %s %q: %w", pos, namer.option, goTypeKey(decl), name, err)
+		}
+	}
+
+	other, known := a.definitionTypes[name]
+	if !known || sameDefinitionType(other, goTypeKey(decl)) {
+		return nil
+	}
+	if named {
+		return fmt.Errorf("%v: %s names types %s, declared at %v, and %s %q: set Options.PackageAliases or a name in swagger:model",
+			pos, namer.option, other, a.definitionPositions[name], goTypeKey(decl), name)
+	}
+	collision := fmt.Sprintf("types %s, declared at %v, and %s are both named %q: name one with swagger:model, or see DefinitionNaming",
+		other, a.definitionPositions[name], goTypeKey(decl), name)
+	if namer == nil {
+		a.diagnose(Diagnostic{Pos: pos, Code: DiagnosticDefinitionNameCollision, Message: collision})
+		return nil
+	}
+	return fmt.Errorf("%v: %s", pos, collision)
+}
+
+// sameDefinitionType tells if two Go types share a definition, e.g. the instantiations Page[Order] and
+// Page[*Order], whose type arguments marshal the same.
+func sameDefinitionType(a, b string) bool {
+	return strings.ReplaceAll(a, "*", "") == strings.ReplaceAll(b, "*", "")
+}
+
+func checkDefinitionName(name string) error {
+	switch {
+	case name == "":
+		return errors.New("the name is empty")
+	case strings.ContainsAny(name, "/#~ \t\n"):
+		return errors.New("the name must not contain slashes, #, ~ or spaces")
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefinitionNameTemplate(t *testing.T) {
+	const (
+		v1 = "github.com/3idey/codescan/fixtures/goparsing/defnames/users/v1"
+		v2 = "github.com/3idey/codescan/fixtures/goparsing/defnames/users/v2"
+	)
+
+	t.Run("should name the definitions with the template", func(t *testing.T) {
+		doc, err := Run(&Options{
+			Packages:               []string{v1, v2},
+			ScanModels:             true,
+			DefinitionNameTemplate: "{{.PackageAlias}}.{{.TypeName}}",
+			PackageAliases:         map[string]string{v1: "users.v1"},
+		})
+		require.NoError(t, err)
+
+		assert.ElementsMatch(t, []string{"users.v1.User", "users.v1.Address", "v2.User", "LegacyAccount"}, sortedKeys(doc.Definitions))
+		address := doc.Definitions["users.v1.User"].Properties["address"]
+		assert.Equal(t, "#/definitions/users.v1.Address", address.Ref.String())
+	})
+
+	t.Run("should fail when the template gives types the same name", func(t *testing.T) {
+		_, err := Run(&Options{
+			Packages:               []string{v1, v2},
+			ScanModels:             true,
+			DefinitionNameTemplate: "{{.TypeName}}",
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `DefinitionNameTemplate names types`)
+		assert.Contains(t, err.Error(), `"User"`)
+	})
+
+	t.Run("should reject invalid templates", func(t *testing.T) {
+		for _, tc := range []struct {
+			template string
+			err      string
+		}{
+			{"{{.PackageAlias}.{{.TypeName}}", "bad character"},
+			{"{{.Package}}.{{.TypeName}}", "can't evaluate field Package"},
+			{"{{.PackagePath}}.{{.TypeName}}", "must not contain slashes"},
+			{"{{.PackageAlias}}", "without its type name"},
+		} {
+			err := (&Options{DefinitionNameTemplate: tc.template}).Validate()
+			require.Error(t, err, tc.template)
+			assert.Contains(t, err.Error(), tc.err, tc.template)
+		}
+
+		var conflict *OptionsConflictError
+		require.ErrorAs(t, (&Options{PackageAliases: map[string]string{v1: "users"}}).Validate(), &conflict)
+		assert.Equal(t, []string{"PackageAliases", "DefinitionNameTemplate"}, conflict.Options)
+	})
+}
+
+func TestDefinitionNaming(t *testing.T) {
+	const (
+		v1 = "github.com/3idey/codescan/fixtures/goparsing/defnames/users/v1"
+		v2 = "github.com/3idey/codescan/fixtures/goparsing/defnames/users/v2"
+	)
+
+	t.Run("should name the definitions with the strategies", func(t *testing.T) {
+		for naming, expected := range map[string][]string{
+			NamingFull: {
+				"github.com.3idey.codescan.fixtures.goparsing.defnames.users.v1.User",
+				"github.com.3idey.codescan.fixtures.goparsing.defnames.users.v1.Address",
+				"github.com.3idey.codescan.fixtures.goparsing.defnames.users.v2.User",
+				"LegacyAccount",
+			},
+			NamingCamelPkg: {"UsersV1User", "UsersV1Address", "V2User", "LegacyAccount"},
+		} {
+			doc, err := Run(&Options{
+				Packages:         []string{v1, v2},
+				ScanModels:       true,
+				DefinitionNaming: naming,
+				PackageAliases:   map[string]string{v1: "users.v1"},
+			})
+			require.NoError(t, err, naming)
+			assert.ElementsMatch(t, expected, sortedKeys(doc.Definitions), naming)
+		}
+	})
+
+	t.Run("should name the definitions with the namer", func(t *testing.T) {
+		doc, err := Run(&Options{
+			Packages:   []string{v1, v2},
+			ScanModels: true,
+			DefinitionNamer: func(pkgPath, typeName string) string {
+				if pkgPath == v2 {
+					return "Next" + typeName
+				}
+				return ""
+			},
+		})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"User", "Address", "NextUser", "LegacyAccount"}, sortedKeys(doc.Definitions))
+
+		user := doc.Definitions["NextUser"]
+		assert.Equal(t, "User", user.Extensions["x-go-name"], "the Go name is kept for the generators")
+		assert.Equal(t, v2, user.Extensions["x-go-package"])
+	})
+
+	t.Run("should report the types with the same name", func(t *testing.T) {
+		var diagnostics []Diagnostic
+		doc, err := Run(&Options{Packages: []string{v1, v2}, ScanModels: true, Diagnostics: &diagnostics})
+		require.NoError(t, err, "the definition is overridden without a strategy")
+		assert.Contains(t, doc.Definitions, "User")
+		require.Len(t, diagnostics, 1)
+		assert.Equal(t, DiagnosticDefinitionNameCollision, diagnostics[0].Code)
+		assert.Contains(t, diagnostics[0].Message, "v1/models.go:7:6, and ")
+		assert.Contains(t, diagnostics[0].Message, `are both named "User"`)
+
+		_, err = Run(&Options{Packages: []string{v1, v2}, ScanModels: true, DefinitionNaming: NamingShort})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "v2/models.go:7:6: DefinitionNaming names types ")
+		assert.Contains(t, err.Error(), "v1/models.go:7:6, and ")
+	})
+
+	t.Run("should name the definitions with a single strategy", func(t *testing.T) {
+		var conflict *OptionsConflictError
+		require.ErrorAs(t, (&Options{DefinitionNaming: NamingFull, DefinitionNameTemplate: "{{.TypeName}}"}).Validate(), &conflict)
+		assert.Equal(t, []string{"DefinitionNameTemplate", "DefinitionNaming"}, conflict.Options)
+
+		var invalidErr *InvalidOptionError
+		require.ErrorAs(t, (&Options{DefinitionNaming: "long"}).Validate(), &invalidErr)
+		assert.Equal(t, "DefinitionNaming", invalidErr.Option)
+		require.NoError(t, (&Options{DefinitionNaming: NamingCamelPkg, PackageAliases: map[string]string{v1: "users"}}).Validate())
+	})
+}
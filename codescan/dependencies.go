@@ -0,0 +1,99 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"fmt"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// localModule tells if a package is part of the project rather than of a dependency, and explains why: the
+// packages of the main modules, i.e. the module of go.mod or those of go.work, and those of the modules
+// replaced by a local directory, e.g. nested modules, are local.
+func localModule(pkg *packages.Package) (string, bool) {
+	module := pkg.Module
+	switch {
+	case module == nil:
+		return "outside of the modules, e.g. in the standard library", false
+	case module.Main:
+		return "in the main module " + module.Path, true
+	case module.Replace != nil && module.Replace.Version == "":
+		return fmt.Sprintf("in the module %s, replaced by the local directory %s", module.Path, module.Replace.Path), true
+	case module.Replace != nil:
+		return fmt.Sprintf("in the dependency %s, replaced by %s@%s", module.Path, module.Replace.Path, module.Replace.Version), false
+	default:
+		return fmt.Sprintf("in the dependency %s@%s", module.Path, module.Version), false
+	}
+}
+
+// followsImport tells if the scan walks into an imported package: always, but with ExcludeDeps, which only
+// walks into the local packages, see localModule.
+func followsImport(excludeDeps bool, imp *packages.Package) bool {
+	if !excludeDeps {
+		return true
+	}
+	_, local := localModule(imp)
+	return local
+}
+
+// packageRule tells if the package rules classify a package, and explains why, like acceptsPackage.
+func (a *typeIndex) packageRule(pkg *packages.Package) (bool, string) {
+	if forced := a.forceIncludeDirFor(pkg); forced != nil {
+		return true, "force included from " + forced.dir
+	}
+	if root, skipped := a.skippedRoot(pkg); skipped {
+		return false, fmt.Sprintf("in %s/, skipped by default, see AlsoScan", root)
+	}
+	return explainPkgRules(pkg.PkgPath, a.includePkgs, a.excludePkgs)
+}
+
+// explainPackages records the decisions of the scan on the packages of Options.ExplainPackages in the stats.
+func (a *typeIndex) explainPackages(pkgs []*packages.Package) {
+	if len(a.explainPkgs) == 0 {
+		return
+	}
+	roots := make(map[string]bool, len(pkgs))
+	for _, pkg := range pkgs {
+		roots[pkg.PkgPath] = true
+	}
+	loaded := make(map[string]*packages.Package)
+	packages.Visit(pkgs, func(pkg *packages.Package) bool {
+		loaded[pkg.PkgPath] = pkg
+		return true
+	}, nil)
+
+	for _, path := range a.explainPkgs {
+		decision := PackageDecision{Package: path}
+		pkg, isLoaded := loaded[path]
+		_, walked := a.AllPackages[path]
+		module, local := "", false
+		if isLoaded {
+			module, local = localModule(pkg)
+		}
+		switch {
+		case !isLoaded:
+			decision.Reason = "neither matched by the patterns nor imported by the matched packages"
+		case !walked && !local && a.excludeDeps:
+			decision.Loaded = true
+			decision.Reason = fmt.Sprintf("imported, %s: excluded by ExcludeDeps", module)
+		case !walked && a.excludeDeps:
+			decision.Loaded = true
+			decision.Reason = fmt.Sprintf("imported, %s, but only by dependencies: excluded by ExcludeDeps", module)
+		case !walked:
+			decision.Loaded = true
+			decision.Reason = "loaded, but not walked, e.g. as the package of a test variant"
+		default:
+			decision.Loaded = true
+			origin := "matched by the patterns"
+			if !roots[path] {
+				origin = "imported, " + module
+			}
+			var rule string
+			decision.Classified, rule = a.packageRule(pkg)
+			decision.Reason = origin + "; " + rule
+		}
+		a.stats.PackageDecisions = append(a.stats.PackageDecisions, decision)
+	}
+}
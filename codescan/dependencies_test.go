@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExcludeDeps(t *testing.T) {
+	t.Run("should scan the packages of the local modules", func(t *testing.T) {
+		t.Setenv("GOFLAGS", "") // the workspace of the fixture refuses -mod=mod
+		var stats Stats
+		doc, err := Run(&Options{
+			WorkDir:         "../fixtures/goparsing/localmodules/app",
+			Packages:        []string{"./..."},
+			ExcludeDeps:     true,
+			ExplainPackages: []string{"example.com/app", "example.com/shared", "example.com/nested", "example.com/missing"},
+			Stats:           &stats,
+		})
+		require.NoError(t, err)
+
+		assert.Contains(t, doc.Definitions, "Pet", "a module of go.work")
+		assert.Contains(t, doc.Definitions, "Owner", "a module replaced by a local directory")
+		assert.Equal(t, 3, stats.Packages)
+		assert.Equal(t, []PackageDecision{
+			{Package: "example.com/app", Loaded: true, Classified: true, Reason: "matched by the patterns; no include or exclude rule"},
+			{Package: "example.com/shared", Loaded: true, Classified: true, Reason: "imported, in the main module example.com/shared; no include or exclude rule"},
+			{Package: "example.com/nested", Loaded: true, Classified: true, Reason: "imported, in the module example.com/nested, replaced by the local directory ./nested; no include or exclude rule"},
+			{Package: "example.com/missing", Reason: "neither matched by the patterns nor imported by the matched packages"},
+		}, stats.PackageDecisions)
+	})
+
+	t.Run("should explain the exclusion of a dependency", func(t *testing.T) {
+		const makeplans = "github.com/go-swagger/scan-repo-boundary/makeplans"
+		var stats Stats
+		doc, err := Run(&Options{
+			Packages:        []string{"github.com/3idey/codescan/fixtures/goparsing/bookings"},
+			ExcludeDeps:     true,
+			ExplainPackages: []string{makeplans},
+			Stats:           &stats,
+		})
+		require.NoError(t, err)
+
+		assert.NotContains(t, doc.Definitions, "Booking")
+		require.Len(t, stats.PackageDecisions, 1)
+		decision := stats.PackageDecisions[0]
+		assert.True(t, decision.Loaded)
+		assert.False(t, decision.Classified)
+		assert.Contains(t, decision.Reason, "imported, in the dependency github.com/go-swagger/scan-repo-boundary@")
+		assert.Contains(t, decision.Reason, ": excluded by ExcludeDeps")
+	})
+
+	t.Run("should explain the package rules", func(t *testing.T) {
+		const pkg = "github.com/3idey/codescan/fixtures/goparsing/bookings"
+		var stats Stats
+		_, err := Run(&Options{
+			Packages:        []string{pkg},
+			Exclude:         []string{"scan-repo-boundary"},
+			ExplainPackages: []string{pkg, "github.com/go-swagger/scan-repo-boundary/makeplans"},
+			Stats:           &stats,
+		})
+		require.NoError(t, err)
+
+		require.Len(t, stats.PackageDecisions, 2)
+		assert.True(t, stats.PackageDecisions[0].Classified)
+		assert.Equal(t, "matched by the patterns; no exclude rule matches", stats.PackageDecisions[0].Reason)
+		assert.False(t, stats.PackageDecisions[1].Classified)
+		assert.Contains(t, stats.PackageDecisions[1].Reason, "; excluded by scan-repo-boundary")
+	})
+}
+
+func TestExplainPkgRules(t *testing.T) {
+	for _, tc := range []struct {
+		path             string
+		include, exclude []string
+		accepted         bool
+		reason           string
+	}{
+		{path: "example.com/api", accepted: true, reason: "no include or exclude rule"},
+		{path: "example.com/api", include: []string{"api"}, exclude: []string{"example"}, accepted: true, reason: "included by api"},
+		{path: "example.com/api", exclude: []string{"api"}, reason: "excluded by api"},
+		{path: "example.com/api", exclude: []string{"models"}, accepted: true, reason: "no exclude rule matches"},
+		{path: "example.com/api", include: []string{"models"}, reason: "no include rule matches"},
+	} {
+		accepted, reason := explainPkgRules(tc.path, tc.include, tc.exclude)
+		assert.Equal(t, tc.accepted, accepted, tc.reason)
+		assert.Equal(t, tc.reason, reason)
+	}
+}
@@ -0,0 +1,140 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"go/ast"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// deprecatedExtension marks a property, a parameter or a definition as deprecated, which swagger 2.0 only
+// tells of the operations. The OpenAPI 3.0 output turns it into deprecated.
+const deprecatedExtension = "x-deprecated"
+
+// setDeprecated records the Deprecated: true|false line of the comment of a field or a type, see deprecated.
+type setDeprecated struct {
+	explicit   bool
+	deprecated bool
+}
+
+func (sd *setDeprecated) Matches(line string) bool {
+	return rxDeprecated.MatchString(line)
+}
+
+func (sd *setDeprecated) Parse(lines []string) error {
+	if len(lines) == 0 || (len(lines) == 1 && len(lines[0]) == 0) {
+		return nil
+	}
+	matches := rxDeprecated.FindStringSubmatch(lines[0])
+	if len(matches) > 1 && len(matches[1]) > 0 {
+		deprecated, err := strconv.ParseBool(matches[1])
+		if err != nil {
+			return err
+		}
+		sd.explicit, sd.deprecated = true, deprecated
+	}
+	return nil
+}
+
+// deprecated tells if the comment of a field or a type deprecates it: its Deprecated: line, else a
+// swagger:deprecated annotation, else, with Options.AutoDeprecate, a paragraph starting with Deprecated:,
+// the convention of the Go doc comments.
+func (s *scanCtx) deprecated(marker *setDeprecated, doc *ast.CommentGroup) bool {
+	switch {
+	case marker != nil && marker.explicit:
+		return marker.deprecated
+	case deprecatedDirective(doc):
+		return true
+	default:
+		return s.opts.AutoDeprecate && goDeprecated(doc)
+	}
+}
+
+// deprecate marks an element with the x-deprecated extension, prefixing its description, when not nil, with
+// Options.DeprecationNotice.
+func (s *scanCtx) deprecate(ext *spec.VendorExtensible, description *string) {
+	addExtension(ext, deprecatedExtension, true)
+	notice := s.opts.DeprecationNotice
+	if notice == "" || description == nil || strings.HasPrefix(*description, notice) {
+		return
+	}
+	if *description == "" {
+		*description = notice
+		return
+	}
+	*description = notice + " " + *description
+}
+
+// parseProperty parses the comment of the field or the method of a property, see createParser, and
+// deprecates the property, see deprecated. The notice is left out next to a $ref, like the description.
+func (s *schemaBuilder) parseProperty(name string, schema, ps *spec.Schema, afld *ast.Field) error {
+	sp := s.createParser(name, schema, ps, afld)
+	deprecation := new(setDeprecated)
+	sp.taggers = append(sp.taggers, newSingleLineTagParser("Deprecated", deprecation))
+	if err := sp.Parse(afld.Doc); err != nil {
+		return err
+	}
+	if !s.ctx.deprecated(deprecation, afld.Doc) {
+		return nil
+	}
+	var description *string
+	if ps.Ref.String() == "" || s.ctx.opts.DescWithRef {
+		description = &ps.Description
+	}
+	s.ctx.deprecate(&ps.VendorExtensible, description)
+	return nil
+}
+
+// deprecateOperation deprecates an operation whose handler follows the convention of the Go doc comments,
+// with Options.AutoDeprecate.
+func (s *scanCtx) deprecateOperation(path parsedPathContent, op *spec.Operation) {
+	if s.opts.AutoDeprecate && path.handler != nil && goDeprecated(path.handler.Doc) {
+		op.Deprecated = true
+	}
+}
+
+// deprecateYAMLOperation sets the deprecated flag of a swagger:operation from a Deprecated: true|false line
+// of its comment before its YAML, which may set it too.
+func deprecateYAMLOperation(doc *ast.CommentGroup, op *spec.Operation) {
+	if doc == nil {
+		return
+	}
+	for _, cmt := range doc.List {
+		for line := range commentLines(cmt.Text) {
+			if rxBeginYAMLSpec.MatchString(line) {
+				return
+			}
+			if matches := rxDeprecated.FindStringSubmatch(line); matches != nil {
+				op.Deprecated = matches[1] == "true"
+			}
+		}
+	}
+}
+
+func deprecatedDirective(comments *ast.CommentGroup) bool {
+	return commentMatcher(rxSwaggerDeprecated)(comments)
+}
+
+// goDeprecated tells if a doc comment has a paragraph starting with Deprecated:, e.g. "Deprecated: use Pets
+// instead.", the convention of the Go doc comments.
+func goDeprecated(comments *ast.CommentGroup) bool {
+	if comments == nil {
+		return false
+	}
+	for paragraph := range strings.SplitSeq(comments.Text(), "\n\n") {
+		if strings.HasPrefix(paragraph, "Deprecated: ") {
+			return true
+		}
+	}
+	return false
+}
+
+// isDeprecated tells if the extensions of an element mark it as deprecated.
+func isDeprecated(extensions spec.Extensions) bool {
+	deprecated, _ := extensions.GetBool(deprecatedExtension)
+	return deprecated
+}
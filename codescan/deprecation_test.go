@@ -0,0 +1,107 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeprecation(t *testing.T) {
+	const pkg = "github.com/3idey/codescan/fixtures/goparsing/deprecation"
+	scan := func(t *testing.T, opts Options) *spec.Swagger {
+		t.Helper()
+		opts.Packages = []string{pkg}
+		doc, err := Run(&opts)
+		require.NoError(t, err)
+		return doc
+	}
+	doc := scan(t, Options{})
+	pet := doc.Definitions["Pet"].Properties
+
+	t.Run("should deprecate the routes and the operations", func(t *testing.T) {
+		assert.True(t, doc.Paths.Paths["/pets"].Get.Deprecated)
+		getPet := doc.Paths.Paths["/pets/{id}"].Get
+		assert.True(t, getPet.Deprecated, "the Deprecated: line of a swagger:operation")
+		assert.Equal(t, "Gets a pet.", getPet.Summary)
+		assert.Empty(t, getPet.Description)
+		assert.False(t, doc.Paths.Paths["/owners"].Get.Deprecated, "the Go convention needs AutoDeprecate")
+	})
+
+	t.Run("should deprecate a query parameter", func(t *testing.T) {
+		params := doc.Paths.Paths["/pets"].Get.Parameters
+		require.Len(t, params, 2)
+		assert.Equal(t, "page_size", params[0].Name)
+		assert.True(t, isDeprecated(params[0].Extensions))
+		assert.Equal(t, "The page size, see limit.", params[0].Description)
+		assert.False(t, isDeprecated(params[1].Extensions))
+	})
+
+	t.Run("should deprecate the fields of the models", func(t *testing.T) {
+		assert.True(t, isDeprecated(pet["tag"].Extensions))
+		assert.Equal(t, "The legacy tag, see name.", pet["tag"].Description)
+		owner := pet["owner"]
+		assert.True(t, isDeprecated(owner.Extensions), "swagger:deprecated, next to the $ref")
+		assert.Equal(t, "#/definitions/Owner", owner.Ref.String())
+		assert.False(t, isDeprecated(pet["name"].Extensions))
+		assert.False(t, isDeprecated(pet["nickname"].Extensions))
+		assert.False(t, isDeprecated(doc.Definitions["Owner"].Extensions))
+	})
+
+	t.Run("should deprecate a field of the body of a response", func(t *testing.T) {
+		assert.Equal(t, "#/definitions/Page", doc.Responses["petsResponse"].Schema.Ref.String())
+		assert.True(t, isDeprecated(doc.Definitions["Page"].Properties["total"].Extensions))
+		assert.False(t, isDeprecated(doc.Definitions["Page"].Properties["pets"].Extensions))
+	})
+
+	t.Run("should follow the convention of Go with AutoDeprecate", func(t *testing.T) {
+		auto := scan(t, Options{AutoDeprecate: true})
+		assert.True(t, auto.Paths.Paths["/owners"].Get.Deprecated, "the doc comment of the handler")
+		assert.False(t, auto.Paths.Paths["/adoptions"].Get.Deprecated)
+		assert.True(t, isDeprecated(auto.Definitions["Owner"].Extensions))
+		assert.False(t, isDeprecated(auto.Definitions["Pet"].Extensions))
+		props := auto.Definitions["Pet"].Properties
+		assert.True(t, isDeprecated(props["nickname"].Extensions))
+		assert.False(t, isDeprecated(props["breed"].Extensions), "Deprecated: false opts out")
+		assert.False(t, isDeprecated(props["name"].Extensions))
+	})
+
+	t.Run("should prefix the descriptions with the notice", func(t *testing.T) {
+		noticed := scan(t, Options{AutoDeprecate: true, DeprecationNotice: "DEPRECATED:"})
+		props := noticed.Definitions["Pet"].Properties
+		assert.Equal(t, "DEPRECATED: The legacy tag, see name.", props["tag"].Description)
+		assert.Empty(t, props["owner"].Description, "left out next to a $ref")
+		assert.Equal(t, "The name.", props["name"].Description)
+		assert.Equal(t, "DEPRECATED: The page size, see limit.", noticed.Paths.Paths["/pets"].Get.Parameters[0].Description)
+		assert.Equal(t, "DEPRECATED: Owner is the owner of a pet.", noticed.Definitions["Owner"].Title, "the title without a description")
+	})
+
+	t.Run("should warn of the uses of the deprecated definitions", func(t *testing.T) {
+		var warnings []Diagnostic
+		for _, problem := range ValidateSpec(scan(t, Options{AutoDeprecate: true}), nil) {
+			if problem.Code == DiagnosticDeprecatedDefinition {
+				warnings = append(warnings, problem)
+			}
+		}
+		require.Len(t, warnings, 1, "but the deprecated operation and property")
+		assert.Equal(t, SeverityWarning, warnings[0].Severity)
+		assert.Equal(t, "#/definitions/Adoption/properties/owner: uses the deprecated definition Owner", warnings[0].Message)
+	})
+
+	t.Run("should write deprecated in OpenAPI 3.0", func(t *testing.T) {
+		oas3, err := UpgradeSwagger(scan(t, Options{}))
+		require.NoError(t, err)
+		components := oas3["components"].(map[string]any)
+		tag := components["schemas"].(map[string]any)["Pet"].(map[string]any)["properties"].(map[string]any)["tag"].(map[string]any)
+		assert.Equal(t, true, tag["deprecated"])
+		assert.NotContains(t, tag, deprecatedExtension)
+		pets := oas3["paths"].(map[string]any)["/pets"].(map[string]any)["get"].(map[string]any)
+		pageSize := pets["parameters"].([]any)[0].(map[string]any)
+		assert.Equal(t, true, pageSize["deprecated"])
+		assert.NotContains(t, pageSize, deprecatedExtension)
+	})
+}
@@ -0,0 +1,156 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// modelDerivation describes a model derived from another one, e.g. "swagger:model updateUser deriveFrom:User allOptional".
+type modelDerivation struct {
+	from        string   // name of the definition the model derives from
+	allOptional bool     // no property is required
+	optional    []string // properties which are not required, "*" for all of them
+	omit        []string // properties which are removed
+}
+
+// Derivation returns the derivation of the model declaration, or nil if the model is not derived.
+func (d *entityDecl) Derivation() (*modelDerivation, error) {
+	if d.Comments == nil {
+		return nil, nil
+	}
+
+	for _, cmt := range d.Comments.List {
+		for ln := range commentLines(cmt.Text) {
+			matches := rxModelDerivation.FindStringSubmatch(ln)
+			if len(matches) < 2 {
+				continue
+			}
+
+			derivation := new(modelDerivation)
+			for modifier := range strings.FieldsSeq(matches[1]) {
+				switch key, value, _ := strings.Cut(modifier, ":"); key {
+				case "deriveFrom":
+					derivation.from = value
+				case "allOptional":
+					derivation.allOptional = true
+				case "optional":
+					derivation.optional = append(derivation.optional, strings.Split(value, ",")...)
+				case "omit":
+					derivation.omit = append(derivation.omit, strings.Split(value, ",")...)
+				}
+			}
+			if derivation.from == "" {
+				return nil, fmt.Errorf(
+					"%v: model %s uses derivation modifiers without deriveFrom",
+					d.Pkg.Fset.Position(d.Ident.Pos()), d.Ident.Name,
+				)
+			}
+
+			return derivation, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// derive replaces the schema built for a derived model by its source definition, with the derivation applied.
+//
+// The title and description of the derived model are kept when set, and its Go traceability extensions apply.
+// The source definition is recorded in the x-derived-from extension.
+func (s *schemaBuilder) derive(definitions map[string]spec.Schema, derivation *modelDerivation, schema *spec.Schema) error {
+	position := s.decl.Pkg.Fset.Position(s.decl.Ident.Pos())
+	if derivation.from == s.Name {
+		return fmt.Errorf("%v: model %s can't derive from itself", position, s.Name)
+	}
+
+	source, ok := definitions[derivation.from]
+	if !ok {
+		decl, found := s.ctx.FindModelByName(derivation.from)
+		if !found {
+			return fmt.Errorf("%v: model %s derives from unknown model %q", position, s.Name, derivation.from)
+		}
+		sb := &schemaBuilder{ctx: s.ctx, decl: decl}
+		if err := sb.Build(definitions); err != nil {
+			return err
+		}
+		s.postDecls = append(s.postDecls, sb.postDecls...)
+		source = definitions[derivation.from]
+	}
+
+	derived, err := cloneSchema(source)
+	if err != nil {
+		return err
+	}
+	if schema.Title != "" || schema.Description != "" {
+		derived.Title = schema.Title
+		derived.Description = schema.Description
+	}
+	delete(derived.Extensions, "x-go-name")
+	if s.Name != s.GoName {
+		derived.AddExtension("x-go-name", s.GoName)
+	}
+	derived.AddExtension("x-go-package", s.decl.Obj().Pkg().Path())
+	derived.AddExtension("x-derived-from", derivation.from)
+
+	for _, name := range derivation.omit {
+		property, found := derivedProperty(derived, name)
+		if !found {
+			return fmt.Errorf("%v: model %s omits unknown property %q of %s", position, s.Name, name, derivation.from)
+		}
+		delete(derived.Properties, property)
+		derived.Required = slices.DeleteFunc(derived.Required, func(required string) bool { return required == property })
+	}
+
+	if derivation.allOptional || slices.Contains(derivation.optional, "*") {
+		derived.Required = nil
+	}
+	for _, name := range derivation.optional {
+		if name == "*" {
+			continue
+		}
+		property, found := derivedProperty(derived, name)
+		if !found {
+			return fmt.Errorf("%v: model %s makes unknown property %q of %s optional", position, s.Name, name, derivation.from)
+		}
+		derived.Required = slices.DeleteFunc(derived.Required, func(required string) bool { return required == property })
+	}
+	if len(derived.Required) == 0 {
+		derived.Required = nil
+	}
+
+	*schema = derived
+	return nil
+}
+
+// derivedProperty finds a property by its JSON name or by the name of its Go field.
+func derivedProperty(schema spec.Schema, name string) (string, bool) {
+	if _, ok := schema.Properties[name]; ok {
+		return name, true
+	}
+	for _, property := range sortedKeys(schema.Properties) {
+		if goName, ok := schema.Properties[property].Extensions.GetString("x-go-name"); ok && goName == name {
+			return property, true
+		}
+	}
+	return "", false
+}
+
+// cloneSchema returns a deep copy of a schema.
+func cloneSchema(schema spec.Schema) (spec.Schema, error) {
+	jazon, err := json.Marshal(schema)
+	if err != nil {
+		return spec.Schema{}, err
+	}
+	var clone spec.Schema
+	if err := json.Unmarshal(jazon, &clone); err != nil {
+		return spec.Schema{}, err
+	}
+	return clone, nil
+}
@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"regexp"
+	"strings"
+)
+
+// CleanDescription returns the lines of a doc comment as the scan documents them, in titles and
+// descriptions:
+//
+//   - the comment markers and the blank lines around the text are removed
+//   - the trailing block of directives is dropped: the last lines matching "^\s*[A-Za-z][A-Za-z .-]*:\s", e.g.
+//     "Maximum: 100" or "in: query", with the blank lines between them. A directive followed by text is text.
+//   - an escaped directive, e.g. "\Required: when shipping", is neither parsed nor dropped, and is
+//     documented without its backslash
+//
+// The lines are those of the comment before its first swagger: annotation, e.g. from ast.Comment.Text.
+func CleanDescription(lines []string) []string {
+	return cleanDescription(lines, rxUncommentHeaders)
+}
+
+// uncommentDescription returns what is removed from the start of the lines of a title or a description:
+// with Options.PreserveCommentFormat, only the comment markers and the space after them, so that the
+// markdown of the comments keeps its lists and code blocks, and otherwise all the indentation, with the
+// dashes and the stars.
+func uncommentDescription(preserveFormat bool) *regexp.Regexp {
+	if preserveFormat {
+		return rxUncommentMarkers
+	}
+	return rxUncommentHeaders
+}
+
+func cleanDescription(lines []string, uncomment *regexp.Regexp) []string {
+	lines = cleanupScannerLines(lines, uncomment)
+
+	end := len(lines)
+	for i := len(lines) - 1; i >= 0; i-- {
+		if strings.TrimSpace(lines[i]) == "" {
+			continue
+		}
+		if !rxDirectiveLine.MatchString(strings.TrimLeft(lines[i], "\t")) {
+			break
+		}
+		end = i
+	}
+	for end > 0 && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+	if end == 0 {
+		return nil
+	}
+
+	cleaned := make([]string, end)
+	for i, line := range lines[:end] {
+		cleaned[i] = rxEscapedLine.ReplaceAllString(line, "$1$2")
+	}
+	return cleaned
+}
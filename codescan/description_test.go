@@ -0,0 +1,187 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestCleanDescription(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		lines []string
+		want  []string
+	}{
+		{
+			name:  "plain text",
+			lines: []string{"// The name of the user."},
+			want:  []string{"The name of the user."},
+		},
+		{
+			name:  "comment markers and blank lines around the text",
+			lines: []string{"//", "//   The name", "// of the user.", "//", ""},
+			want:  []string{"The name", "of the user."},
+		},
+		{
+			name:  "uncommented lines",
+			lines: []string{"The name of the user.", "Maximum: 100"},
+			want:  []string{"The name of the user."},
+		},
+		{
+			name:  "trailing directive",
+			lines: []string{"// The limit.", "// Maximum: 100"},
+			want:  []string{"The limit."},
+		},
+		{
+			name:  "trailing block of directives with blank lines",
+			lines: []string{"// The limit.", "//", "// Maximum: 100", "//", "// in: query", "// Collection format: csv"},
+			want:  []string{"The limit."},
+		},
+		{
+			name:  "lowercase directive",
+			lines: []string{"// The offset.", "// in: query"},
+			want:  []string{"The offset."},
+		},
+		{
+			name:  "dotted and dashed directives",
+			lines: []string{"// The tags.", "// items.maxLength: 10", "// x-nullable: true"},
+			want:  []string{"The tags."},
+		},
+		{
+			name:  "directive without value",
+			lines: []string{"// The media types.", "// Consumes:"},
+			want:  []string{"The media types."},
+		},
+		{
+			name:  "directive followed by text",
+			lines: []string{"// The carrier.", "// Note: the carrier may change", "// until the parcel is shipped."},
+			want:  []string{"The carrier.", "Note: the carrier may change", "until the parcel is shipped."},
+		},
+		{
+			name:  "directive in the middle of the text",
+			lines: []string{"// Required: when shipping abroad", "//", "// The customs form is filled in by the sender."},
+			want:  []string{"Required: when shipping abroad", "", "The customs form is filled in by the sender."},
+		},
+		{
+			name:  "escaped directive",
+			lines: []string{"// The address.", "//", `// \Required: when the parcel is not picked up.`},
+			want:  []string{"The address.", "", "Required: when the parcel is not picked up."},
+		},
+		{
+			name:  "escaped directive before directives",
+			lines: []string{`// \Unit: grams`, "// minimum: 1"},
+			want:  []string{"Unit: grams"},
+		},
+		{
+			name:  "escaped text is kept as is",
+			lines: []string{`// The path, e.g. C:\Users.`, `// \not a directive`},
+			want:  []string{`The path, e.g. C:\Users.`, `\not a directive`},
+		},
+		{
+			name:  "sentence ending with a colon",
+			lines: []string{"// The allowed values are:", "// red, green and blue"},
+			want:  []string{"The allowed values are:", "red, green and blue"},
+		},
+		{
+			name:  "colon without space",
+			lines: []string{"// The endpoint.", "// see:https://example.com"},
+			want:  []string{"The endpoint.", "see:https://example.com"},
+		},
+		{
+			name:  "URL",
+			lines: []string{"// The documentation.", "// https://example.com/docs"},
+			want:  []string{"The documentation.", "https://example.com/docs"},
+		},
+		{
+			name:  "only directives",
+			lines: []string{"// required: true", "// minimum: 1"},
+			want:  nil,
+		},
+		{
+			name:  "no lines",
+			lines: nil,
+			want:  nil,
+		},
+		{
+			name:  "blank lines",
+			lines: []string{"//", "  "},
+			want:  nil,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, CleanDescription(tc.lines))
+		})
+	}
+}
+
+func TestDescriptionDirectives(t *testing.T) {
+	doc, err := Run(&Options{Packages: []string{"github.com/3idey/codescan/fixtures/goparsing/descriptions"}, ScanModels: true})
+	require.NoError(t, err)
+
+	shipment := doc.Definitions["Shipment"]
+	weight := shipment.Properties["weight"]
+	assert.Equal(t, "The weight of the parcel.", weight.Description)
+	require.NotNil(t, weight.Minimum)
+	assert.Contains(t, shipment.Required, "weight")
+
+	address := shipment.Properties["address"]
+	assert.Equal(t, "The address of the recipient.\n\nRequired: when the parcel is not picked up.\nExample: 221B Baker Street", address.Description)
+	assert.Nil(t, address.Example)
+	assert.NotContains(t, shipment.Required, "address")
+
+	carrier := shipment.Properties["carrier"]
+	assert.Equal(t, "The carrier of the parcel.\nNote: the carrier may change\nuntil the parcel is shipped.", carrier.Description)
+}
+
+func TestPreserveCommentFormat(t *testing.T) {
+	const pkg = "github.com/3idey/codescan/fixtures/goparsing/markdown"
+	golden := func(t *testing.T, name string, output []byte) {
+		t.Helper()
+		file := filepath.Join("..", "fixtures", "goparsing", "markdown", name)
+		if updateGolden {
+			require.NoError(t, os.WriteFile(file, output, 0o600))
+		}
+		expected, err := os.ReadFile(file)
+		require.NoError(t, err)
+		assert.Equal(t, string(expected), string(output), "%s is out of date, run the tests with -update-golden", name)
+	}
+
+	doc, err := Run(&Options{Packages: []string{pkg}, PreserveCommentFormat: true})
+	require.NoError(t, err)
+	jazon, err := MarshalJSON(doc, false)
+	require.NoError(t, err)
+	golden(t, "swagger.json", append(jazon, '\n'))
+	yml, err := MarshalYAML(doc)
+	require.NoError(t, err)
+	golden(t, "swagger.yaml", yml)
+
+	t.Run("should keep the lists and the code blocks", func(t *testing.T) {
+		assert.Equal(t, "It has:\n  - an ID\n  - lines, with a **quantity**", doc.Definitions["Order"].Description)
+		quantity := doc.Definitions["Line"].Properties["quantity"]
+		assert.Equal(t, "\tquantity := 2 // a code block first\n\t indented by one more space\n\n- the quantity, *at least* one", quantity.Description)
+		assert.Contains(t, doc.Info.Description, "\tcurl -X POST https://api.acme.com/orders \\\n\t  -d '{\"id\": 1}'")
+		assert.NotContains(t, doc.Info.Description, "Version:", "the directives are still stripped")
+	})
+
+	t.Run("should read the same descriptions from the YAML", func(t *testing.T) {
+		var fromYAML any
+		require.NoError(t, yaml.Unmarshal(yml, &fromYAML))
+		back, err := json.Marshal(fromYAML)
+		require.NoError(t, err)
+		assert.JSONEq(t, string(jazon), string(back))
+	})
+
+	t.Run("should strip the indentation by default", func(t *testing.T) {
+		doc, err := Run(&Options{Packages: []string{pkg}})
+		require.NoError(t, err)
+		assert.Equal(t, "It has:\nan ID\nlines, with a **quantity**", doc.Definitions["Order"].Description)
+	})
+}
@@ -0,0 +1,274 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"go/types"
+	"log"
+
+	"github.com/go-openapi/spec"
+)
+
+// Codes of diagnostics.
+const (
+	// DiagnosticEmptySchema reports a property documented with an empty schema.
+	DiagnosticEmptySchema = "empty-schema"
+	// DiagnosticSkippedField reports a struct field left out of the properties, e.g. a channel.
+	DiagnosticSkippedField = "skipped-field"
+	// DiagnosticUnindexedDefinition reports a model of a package known to Options.UseDefinitionIndex, but missing from it.
+	DiagnosticUnindexedDefinition = "unindexed-definition"
+	// DiagnosticMissingIdempotencyKey reports a POST operation declared idempotent without an Idempotency-Key header.
+	DiagnosticMissingIdempotencyKey = "missing-idempotency-key"
+	// DiagnosticJSONNameConflict reports a struct field dropped by encoding/json, because another field has its json name.
+	DiagnosticJSONNameConflict = "json-name-conflict"
+	// DiagnosticInvalidStrfmt reports a type annotated with swagger:strfmt which doesn't marshal as a string.
+	DiagnosticInvalidStrfmt = "invalid-strfmt"
+	// DiagnosticUnknownFormat reports a swagger:strfmt format missing from the strfmt registry and Options.CustomFormats.
+	DiagnosticUnknownFormat = "unknown-format"
+	// DiagnosticUndocumentedStatus reports a status code written by the handler of an operation, but missing from its responses.
+	DiagnosticUndocumentedStatus = "undocumented-status"
+	// DiagnosticUnwrittenStatus reports the responses of an operation with status codes its handler never writes.
+	DiagnosticUnwrittenStatus = "unwritten-status"
+	// DiagnosticMalformedAnnotation reports an annotation rejected or ignored by the scan, see Precheck.
+	DiagnosticMalformedAnnotation = "malformed-annotation"
+	// DiagnosticDuplicateParameter reports a parameter declared by several structs embedded in a swagger:parameters struct.
+	DiagnosticDuplicateParameter = "duplicate-parameter"
+	// DiagnosticBuilderPanic reports a declaration whose building panicked, see Options.KeepGoing.
+	DiagnosticBuilderPanic = "builder-panic"
+	// DiagnosticSecret reports an example, default or description matching a SecretPattern, see Options.CheckSecrets.
+	DiagnosticSecret = "secret"
+	// DiagnosticUnregisteredSet reports a map type with a MarshalJSON method, likely a set, missing from Options.SetTypes.
+	DiagnosticUnregisteredSet = "unregistered-set"
+	// DiagnosticReservedName reports a definition name breaking the generators, e.g. Error, see Options.RenameCollisions.
+	DiagnosticReservedName = "reserved-name"
+	// DiagnosticInvalidSuppression reports a codescan:ignore comment without a rule or a reason, or with an invalid expiry.
+	DiagnosticInvalidSuppression = "invalid-suppression"
+	// DiagnosticDynamicMediaType reports a media type passed to a ContentNegotiator which isn't a constant string.
+	DiagnosticDynamicMediaType = "dynamic-media-type"
+	// DiagnosticHeaderConflict reports a header of a response of swagger:route replacing one of its swagger:response.
+	DiagnosticHeaderConflict = "header-conflict"
+	// DiagnosticUnsupportedSchemaKeyword reports the keywords of a Schema File dropped, since swagger 2.0 can't express them.
+	DiagnosticUnsupportedSchemaKeyword = "unsupported-schema-keyword"
+	// DiagnosticUnresolvedHandler reports a route registered with a router whose handler can't be resolved, see Options.RouterDiscovery.
+	DiagnosticUnresolvedHandler = "unresolved-handler"
+	// DiagnosticUndocumentedOperation reports a route of Options.RouterDiscovery whose handler has no doc comment, see Options.IncludeUndocumented.
+	DiagnosticUndocumentedOperation = "undocumented-operation"
+	// DiagnosticOverriddenProperty reports a property of a subtype overriding that of its discriminated base with another type.
+	DiagnosticOverriddenProperty = "overridden-property"
+	// DiagnosticUnsupportedType reports a type left out of a schema, e.g. a channel or a type parameter.
+	DiagnosticUnsupportedType = "unsupported-type"
+	// DiagnosticDuplicateOperationID reports a route or an operation whose operationId another one uses already.
+	DiagnosticDuplicateOperationID = "duplicate-operation-id"
+	// DiagnosticUnresolvedRef reports a $ref of an operation or a definition to a definition, parameter or response of no spec.
+	DiagnosticUnresolvedRef = "unresolved-ref"
+	// DiagnosticUnsecuredOperation reports an operation without effective security requirements, see Options.RequireSecurity.
+	DiagnosticUnsecuredOperation = "unsecured-operation"
+	// DiagnosticDefinitionNameCollision reports a type whose definition overrides that of another type with its name, see Options.DefinitionNaming.
+	DiagnosticDefinitionNameCollision = "definition-name-collision"
+	// DiagnosticCompositeParameter reports a query, header, path or form parameter of a composite type without an encoding, e.g. a struct.
+	DiagnosticCompositeParameter = "composite-parameter"
+	// DiagnosticOpaqueMarshaler reports a struct marshaled by its MarshalJSON method, documented as an object for lack of an override.
+	DiagnosticOpaqueMarshaler = "opaque-marshaler"
+	// DiagnosticLegacyAnnotation reports an annotation spelled the legacy way, e.g. swagger:params, recognized with Options.Compat.
+	DiagnosticLegacyAnnotation = "legacy-annotation"
+	// DiagnosticPathConstraint reports a path parameter whose declared pattern differs from the regular expression of its route template.
+	DiagnosticPathConstraint = "path-constraint"
+	// DiagnosticInvalidExample reports a named example of a body parameter or a response whose value doesn't match its schema, see Options.ValidateExamples.
+	DiagnosticInvalidExample = "invalid-example"
+	// DiagnosticLargeEnum reports a type whose enum has more values than Options.MaxEnumValues, omitted from the schemas.
+	DiagnosticLargeEnum = "large-enum"
+	// DiagnosticFormConsumes reports the media types an operation with formData parameters consumes which can't carry its form, dropped from it.
+	DiagnosticFormConsumes = "form-consumes"
+	// DiagnosticUnsupportedOpenAPI3 reports a construct of the spec dropped from its OpenAPI 3.0 upgrade, see UpgradeSwaggerFor.
+	DiagnosticUnsupportedOpenAPI3 = "unsupported-openapi3"
+	// DiagnosticUnsupportedSwagger2 reports a construct of an OpenAPI 3.x document approximated or dropped by its downgrade, see DowngradeOpenAPI3For.
+	DiagnosticUnsupportedSwagger2 = "unsupported-swagger2"
+)
+
+// Diagnostic is a problem found in the Go sources while building the spec.
+type Diagnostic struct {
+	Pos      token.Position
+	Code     string // kind of problem, e.g. DiagnosticEmptySchema, or the name of a Rule
+	Message  string
+	Severity string // SeverityError or SeverityWarning, see Rule
+}
+
+func (d Diagnostic) Error() string {
+	return fmt.Sprintf("%v: %s", d.Pos, d.Message)
+}
+
+// diagnose records a diagnostic, once per position and kind, and logs it as a warning. The diagnostics
+// disabled by a Rule are still recorded for the checks of the scan, but neither logged nor reported.
+func (a *typeIndex) diagnose(diagnostic Diagnostic) {
+	if a.forked {
+		a.journal = append(a.journal, journaledDiagnostic{Diagnostic: diagnostic})
+		return
+	}
+	for _, known := range a.diagnostics {
+		if known.Pos == diagnostic.Pos && known.Code == diagnostic.Code {
+			return
+		}
+	}
+	a.record(&diagnostic)
+}
+
+// diagnoseMessage records a diagnostic like diagnose, but once per position, kind and message, for the kinds
+// of diagnostics reported several times at a position, e.g. at that of the declaration of a model.
+func (a *typeIndex) diagnoseMessage(diagnostic Diagnostic) {
+	if a.forked {
+		a.journal = append(a.journal, journaledDiagnostic{Diagnostic: diagnostic, byMessage: true})
+		return
+	}
+	for _, known := range a.diagnostics {
+		if known.Pos == diagnostic.Pos && known.Code == diagnostic.Code && known.Message == diagnostic.Message {
+			return
+		}
+	}
+	a.record(&diagnostic)
+}
+
+// record records a diagnostic with the severity of its code, and logs it unless disabled or suppressed.
+func (a *typeIndex) record(diagnostic *Diagnostic) {
+	if a.suppress(diagnostic) {
+		return
+	}
+	diagnostic.Severity = a.severities[diagnostic.Code]
+	switch {
+	case diagnostic.Severity != "":
+	case diagnostic.Code == DiagnosticLegacyAnnotation:
+		// a deprecation: the annotation is recognized
+		diagnostic.Severity = SeverityWarning
+	case diagnostic.Code == DiagnosticUndocumentedOperation:
+		// a list to burn down, rather than a mistake
+		diagnostic.Severity = SeverityWarning
+	case diagnostic.Code == DiagnosticUnsupportedOpenAPI3, diagnostic.Code == DiagnosticUnsupportedSwagger2:
+		// a limit of the output version, rather than of the sources
+		diagnostic.Severity = SeverityWarning
+	default:
+		diagnostic.Severity = SeverityError
+	}
+	a.diagnostics = append(a.diagnostics, *diagnostic)
+	if diagnostic.Severity != SeverityOff {
+		a.warn(*diagnostic)
+	}
+}
+
+// warn sends a diagnostic to Options.Logger, or logs it as a warning without one.
+func (a *typeIndex) warn(diagnostic Diagnostic) {
+	if a.logger != nil {
+		a.logger(diagnostic)
+		return
+	}
+	logDiagnostic(diagnostic)
+}
+
+func logDiagnostic(diagnostic Diagnostic) {
+	log.Printf("WARNING: %v", diagnostic)
+}
+
+// unsupported reports a type left out of the schema of the declaration being built, once per message.
+func (s *schemaBuilder) unsupported(format string, args ...any) {
+	diagnostic := Diagnostic{Code: DiagnosticUnsupportedType, Message: fmt.Sprintf(format, args...)}
+	if s.decl != nil && s.decl.Pkg != nil {
+		diagnostic.Pos = s.decl.Pkg.Fset.Position(s.decl.Ident.Pos())
+	}
+	s.ctx.app.diagnoseMessage(diagnostic)
+}
+
+// reportedDiagnostics returns the diagnostics which are not disabled.
+func (a *typeIndex) reportedDiagnostics() []Diagnostic {
+	var result []Diagnostic
+	for _, diagnostic := range a.diagnostics {
+		if diagnostic.Severity != SeverityOff {
+			result = append(result, diagnostic)
+		}
+	}
+	return result
+}
+
+func (a *typeIndex) diagnosticsWithCode(code string) []Diagnostic {
+	var result []Diagnostic
+	for _, diagnostic := range a.diagnostics {
+		if diagnostic.Code == code {
+			result = append(result, diagnostic)
+		}
+	}
+	return result
+}
+
+// checkEmptySchemas summarizes the empty schemas emitted, and fails with ForbidEmptySchemas.
+func (a *typeIndex) checkEmptySchemas(forbidden bool) error {
+	empty := a.diagnosticsWithCode(DiagnosticEmptySchema)
+	a.stats.EmptySchemas = len(empty)
+	a.stats.SkippedFields = len(a.diagnosticsWithCode(DiagnosticSkippedField))
+	if len(empty) == 0 {
+		return nil
+	}
+
+	summary := fmt.Sprintf("%d properties have empty schemas", len(empty))
+	if len(empty) == 1 {
+		summary = "1 property has an empty schema"
+	}
+	if !forbidden {
+		log.Printf("WARNING: %s", summary)
+		return nil
+	}
+
+	errs := []error{errors.New(summary)}
+	for _, diagnostic := range empty {
+		errs = append(errs, diagnostic)
+	}
+	return errors.Join(errs...)
+}
+
+// unsupportedFieldKind tells if a field type can't be documented at all, like channels and functions.
+func unsupportedFieldKind(tpe types.Type) string {
+	if ptr, isPointer := tpe.(*types.Pointer); isPointer {
+		tpe = ptr.Elem()
+	}
+	switch tpe.Underlying().(type) {
+	case *types.Chan:
+		return "channels"
+	case *types.Signature:
+		return "functions"
+	default:
+		return ""
+	}
+}
+
+// emptySchemaReason explains why the schema of a field type is empty.
+func emptySchemaReason(tpe types.Type) string {
+	if ptr, isPointer := tpe.(*types.Pointer); isPointer {
+		tpe = ptr.Elem()
+	}
+	switch underlying := tpe.Underlying().(type) {
+	case *types.Interface:
+		if underlying.Empty() {
+			return "empty interface"
+		}
+		return "interface without schema rules"
+	case *types.TypeParam:
+		return "type parameter"
+	default:
+		return fmt.Sprintf("unsupported type %s", tpe)
+	}
+}
+
+// isEmptySchema tells if a schema puts no constraint at all on values, disregarding its annotations.
+func isEmptySchema(schema spec.Schema) bool {
+	return schema.Ref.String() == "" &&
+		len(schema.Type) == 0 &&
+		schema.Format == "" &&
+		schema.Items == nil &&
+		len(schema.Properties) == 0 &&
+		schema.AdditionalProperties == nil &&
+		len(schema.AllOf) == 0 &&
+		len(schema.AnyOf) == 0 &&
+		len(schema.OneOf) == 0 &&
+		schema.Not == nil &&
+		len(schema.Enum) == 0
+}
@@ -0,0 +1,51 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmptySchemaDiagnostics(t *testing.T) {
+	const pkg = "github.com/3idey/codescan/fixtures/goparsing/emptyschema"
+
+	t.Run("should warn about empty schemas and skip channels and functions", func(t *testing.T) {
+		var stats Stats
+		doc, err := Run(&Options{Packages: []string{pkg}, ScanModels: true, Stats: &stats})
+		require.NoError(t, err)
+
+		event := doc.Definitions["Event"]
+		assert.ElementsMatch(t, []string{"name", "payload", "amplitude", "source"}, sortedKeys(event.Properties))
+		assert.Equal(t, 2, stats.EmptySchemas)
+		assert.Equal(t, 3, stats.SkippedFields)
+	})
+
+	t.Run("should fail with ForbidEmptySchemas", func(t *testing.T) {
+		_, err := Run(&Options{Packages: []string{pkg}, ScanModels: true, ForbidEmptySchemas: true})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "2 properties have empty schemas")
+
+		var diagnostic Diagnostic
+		require.True(t, errors.As(err, &diagnostic))
+		assert.Equal(t, DiagnosticEmptySchema, diagnostic.Code)
+		assert.Equal(t, "models.go", filepath.Base(diagnostic.Pos.Filename))
+		assert.Equal(t, 13, diagnostic.Pos.Line)
+		assert.Contains(t, err.Error(), `property "payload" has an empty schema: empty interface`)
+		assert.Contains(t, err.Error(), `property "amplitude" has an empty schema: unsupported type complex128`)
+	})
+}
+
+func TestIsEmptySchema(t *testing.T) {
+	assert.True(t, isEmptySchema(spec.Schema{}))
+	assert.True(t, isEmptySchema(*new(spec.Schema).WithDescription("documented, yet empty")))
+	assert.False(t, isEmptySchema(*spec.StringProperty()))
+	assert.False(t, isEmptySchema(*spec.RefSchema("#/definitions/Foo")))
+	assert.False(t, isEmptySchema(*spec.ArrayProperty(nil)))
+}
@@ -0,0 +1,234 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Directive documents an annotation of the comments, e.g. swagger:route, or a key of their sections, e.g.
+// Maximum, as shown by the editors, see the lsp command.
+type Directive struct {
+	Name       string   // e.g. swagger:route or Maximum
+	Syntax     string   // form of the directive, e.g. "Maximum: [<|<=] number"
+	Doc        string   // what the directive documents, and where it applies
+	Annotation bool     // the directive is a swagger: annotation, rather than a key
+	Aliases    []string // other spellings of a key, e.g. Max for Maximum
+}
+
+// rxCommentKey matches the key starting a line of a comment, e.g. "Max length" in "//	Max length: 10", after
+// its items prefix, e.g. "Items.".
+var rxCommentKey = regexp.MustCompile(`^[\p{Zs}\t]*(?://|/\*|\*)[\p{Zs}\t/\*-]*((?:[Ii]tems[\.\p{Zs}]*)*)([A-Za-z][A-Za-z .-]*?)\p{Zs}*:`)
+
+// directives are the annotations and the keys of the comments, in the order of the README.
+var directives = []Directive{
+	{Name: "swagger:meta", Syntax: "swagger:meta", Annotation: true,
+		Doc: "Documents the API in the package comment: the title and description, then the keys Version, Host, BasePath, Schemes, Consumes, Produces, Security, SecurityDefinitions, License, Contact, ExternalDocs and Extensions."},
+	{Name: "swagger:route", Syntax: pathAnnotationSyntax("route"), Annotation: true,
+		Doc: "Declares an operation, documented by the rest of the comment: the summary, the description and the keys Consumes, Produces, Schemes, Security, Parameters, Responses, Deprecated, ExternalDocs and Extensions."},
+	{Name: "swagger:operation", Syntax: pathAnnotationSyntax("operation"), Annotation: true,
+		Doc: "Declares an operation documented by the YAML of the operation object which follows a --- line of the comment."},
+	{Name: "swagger:path", Syntax: pathAnnotationSyntax("path"), Annotation: true,
+		Doc: "Documents a path: the parameters, summary and description shared by its operations."},
+	{Name: "swagger:tag", Syntax: pathAnnotationSyntax("tag"), Annotation: true,
+		Doc: "Documents a tag of the operations: its description, and its external docs."},
+	{Name: "swagger:parameters", Syntax: "swagger:parameters operationID [operationID...] [scope:name]", Annotation: true,
+		Doc: "Declares a struct whose fields are the parameters of the operations, with the keys In, Required and the validations of the fields."},
+	{Name: "swagger:response", Syntax: "swagger:response [name] [scope:name]", Annotation: true,
+		Doc: "Declares a struct as a response shared by the operations, with the headers of its fields and the schema of its Body field."},
+	{Name: "swagger:model", Syntax: "swagger:model [name] [deriveFrom:Type] [allOptional] [optional:fields] [omit:fields] [scope:name]", Annotation: true,
+		Doc: "Declares a type as a definition of the spec, by its name or the type name, even when no operation uses it."},
+	{Name: "swagger:strfmt", Syntax: "swagger:strfmt name", Annotation: true,
+		Doc: "Declares a type as a string with a format, e.g. swagger:strfmt uuid."},
+	{Name: "swagger:name", Syntax: "swagger:name name", Annotation: true,
+		Doc: "Names a field in the spec, or a method of an interface model, instead of its JSON name."},
+	{Name: "swagger:discriminated", Syntax: "swagger:discriminated", Annotation: true,
+		Doc: "Is accepted for the compatibility with go-swagger, and documents nothing: see swagger:discriminator."},
+	{Name: "swagger:discriminator", Syntax: "swagger:discriminator [field]", Annotation: true,
+		Doc: "Makes a string field the required discriminator of its struct, or sets the value of the discriminator of a subtype, its x-class."},
+	{Name: "swagger:file", Syntax: "swagger:file", Annotation: true,
+		Doc: "Declares a type or a parameter as a file upload, of type file."},
+	{Name: "swagger:enum", Syntax: "swagger:enum Type", Annotation: true,
+		Doc: "Declares a type whose constants are the values of its enum; swagger:enum:ignore opts out of the discovered enums."},
+	{Name: "swagger:default", Syntax: "swagger:default Type", Annotation: true,
+		Doc: "Leaves the schema of the fields of a named type empty, e.g. for a type with a default marshaling."},
+	{Name: "swagger:alias", Syntax: "swagger:alias", Annotation: true,
+		Doc: "Documents the fields of a named type by the schema of its underlying type, instead of a $ref to its definition."},
+	{Name: "swagger:type", Syntax: "swagger:type type", Annotation: true,
+		Doc: "Documents a type or a field by a swagger type, e.g. swagger:type string for a type with a custom marshaler."},
+	{Name: "swagger:example", Syntax: "swagger:example [file:path]", Annotation: true,
+		Doc: "Embeds a JSON or YAML file, relative to the Go file, as the example of a model or of a response."},
+	{Name: "swagger:allOf", Syntax: "swagger:allOf [name]", Annotation: true,
+		Doc: "Composes an embedded struct into its model with allOf, naming the subtype of a discriminated model."},
+	{Name: "swagger:allOfRef", Syntax: "swagger:allOfRef Type [class:name]", Annotation: true,
+		Doc: "Composes a model with allOf from the definition of a type, with its discriminator value as class."},
+	{Name: "swagger:ignore", Syntax: "swagger:ignore [name]", Annotation: true,
+		Doc: "Excludes a type or a field from the spec."},
+	{Name: "swagger:deprecated", Syntax: "swagger:deprecated", Annotation: true,
+		Doc: "Marks a field, a parameter or a model as deprecated, as an x-deprecated extension."},
+
+	{Name: "Maximum", Syntax: "Maximum: [<|<=] number", Aliases: []string{"Max"},
+		Doc: "Bounds a number from above, exclusively with <."},
+	{Name: "Minimum", Syntax: "Minimum: [>|>=] number", Aliases: []string{"Min"},
+		Doc: "Bounds a number from below, exclusively with >."},
+	{Name: "Multiple of", Syntax: "Multiple of: number",
+		Doc: "Requires a number to be a multiple of the value."},
+	{Name: "Max length", Syntax: "Max length: count", Aliases: []string{"Maximum length", "MaxLen"},
+		Doc: "Bounds the length of a string from above."},
+	{Name: "Min length", Syntax: "Min length: count", Aliases: []string{"Minimum length", "MinLen"},
+		Doc: "Bounds the length of a string from below."},
+	{Name: "Pattern", Syntax: "Pattern: regexp",
+		Doc: "Requires a string to match an ECMA 262 regular expression."},
+	{Name: "Max items", Syntax: "Max items: count|{Constant}", Aliases: []string{"Maximum items"},
+		Doc: "Bounds the number of items of an array from above, with a count or a constant of the package, e.g. {limits.MaxBatch}."},
+	{Name: "Min items", Syntax: "Min items: count|{Constant}", Aliases: []string{"Minimum items"},
+		Doc: "Bounds the number of items of an array from below, with a count or a constant of the package."},
+	{Name: "Unique", Syntax: "Unique: true|false", Aliases: []string{"Unique items"},
+		Doc: "Requires the items of an array to be distinct."},
+	{Name: "Collection format", Syntax: "Collection format: csv|ssv|tsv|pipes|multi",
+		Doc: "Sets how the items of an array parameter or header are joined."},
+	{Name: "Enum", Syntax: "Enum: value[,value...]",
+		Doc: "Restricts a value to a list, comma separated or as a JSON array. On an enum type, Enum: full keeps its values whatever their number, and Enum: omit leaves them out."},
+	{Name: "Default", Syntax: "Default: value",
+		Doc: "Sets the default of a field or a parameter."},
+	{Name: "Example", Syntax: "Example: value",
+		Doc: "Sets the example of a field or a parameter."},
+	{Name: "Examples", Syntax: "Examples:",
+		Doc: "Starts a YAML block of the named examples of a body parameter or a response, each with a summary and a value or a file, as x-examples."},
+	{Name: "Items", Syntax: "Items.Maximum: number",
+		Doc: "Prefixes a validation of the items of an array, once per level of nesting, e.g. Items.Items.Max length: 10."},
+	{Name: "In", Syntax: "In: query|path|header|body|formData",
+		Doc: "Sets where a parameter of a swagger:parameters struct is sent."},
+	{Name: "Keys", Syntax: "Keys: key[, key...]",
+		Doc: "Documents a map parameter of a query or a form by a parameter per key, named name[key], instead of a JSON string."},
+	{Name: "Required", Syntax: "Required: true|false",
+		Doc: "Requires a field or a parameter."},
+	{Name: "Read only", Syntax: "Read only: true|false", Aliases: []string{"ReadOnly"},
+		Doc: "Marks a field as set by the server, only in the responses."},
+	{Name: "Discriminator", Syntax: "Discriminator: true|false",
+		Doc: "Marks a field as the discriminator of the subtypes of its model."},
+	{Name: "Types", Syntax: "Types: Type[, Type...]",
+		Doc: "Narrows a field, typically of type any, to a union of swagger types or Go types, as an x-one-of-types extension."},
+	{Name: "Schema file", Syntax: "Schema file: path",
+		Doc: "Reads the schema of a field or a model from a JSON or YAML file relative to the Go file."},
+	{Name: "Consumes", Syntax: "Consumes:\n- media/type",
+		Doc: "Lists the media types an operation reads, on the line or below it, with the shorthands json, yaml, xml, form and multipart."},
+	{Name: "Produces", Syntax: "Produces:\n- media/type",
+		Doc: "Lists the media types an operation writes, on the line or below it, with the shorthands json, yaml, xml, form and multipart."},
+	{Name: "Schemes", Syntax: "Schemes: http, https, ws, wss",
+		Doc: "Lists the transfer protocols of the API or of an operation."},
+	{Name: "Security", Syntax: "Security:\n  name: scope, scope\nor Security: none",
+		Doc: "Lists the security requirements of the API or of an operation, one per line; none declares a public operation."},
+	{Name: "SecurityDefinitions", Syntax: "SecurityDefinitions:\n  name:\n    type: apiKey|basic|oauth2",
+		Doc: "Declares the security schemes of the API as YAML, in swagger:meta; the scopes, an object or a list, keep their order."},
+	{Name: "Parameters", Syntax: "Parameters:\n  + name: id\n    in: path",
+		Doc: "Declares the parameters of a route, each starting with +, with their keys."},
+	{Name: "Responses", Syntax: "Responses:\n  200: responseName\n  default: body:ErrorModel",
+		Doc: "Maps the status codes of a route to the swagger:response names, or to body:Model schemas, with an optional description."},
+	{Name: "Deprecated", Syntax: "Deprecated: true|false",
+		Doc: "Marks an operation as deprecated, or a field, a parameter or a model, as an x-deprecated extension."},
+	{Name: "Idempotent", Syntax: "Idempotent: true|false",
+		Doc: "Documents whether an operation is safe to retry, as an x-idempotent extension."},
+	{Name: "IdempotencyKey", Syntax: "IdempotencyKey: required|optional", Aliases: []string{"Idempotency key"},
+		Doc: "Adds an Idempotency-Key header parameter to an operation."},
+	{Name: "No Default Response", Syntax: "No Default Response: true|false",
+		Doc: "Keeps the response of the default_response setting out of an operation."},
+	{Name: "Unwrap", Syntax: "Unwrap: property",
+		Doc: "Documents the body of a response by the schema of a property of its type, without its envelope."},
+	{Name: "Audience", Syntax: "Audience: name[, name...]", Aliases: []string{"Audiences"},
+		Doc: "Declares the audiences of an operation, as an x-audience extension, which --audience filters the spec by."},
+	{Name: "Custom tag", Syntax: "Custom tag: name",
+		Doc: "Passes a struct tag of a field verbatim as the x-go-custom-tag extension of its property."},
+	{Name: "Group", Syntax: "Group: name=Prefix*[, name=Prefix*]", Aliases: []string{"Groups"},
+		Doc: "Nests the properties of the fields of a model named with a prefix under an object property."},
+	{Name: "Alias Of", Syntax: "Alias Of: [package.]Type",
+		Doc: "Documents a swagger:model alias re-exporting a model as a definition that is a $ref to the model."},
+	{Name: "Version", Syntax: "Version: 1.0.0",
+		Doc: "Sets the version of the API, in swagger:meta."},
+	{Name: "Host", Syntax: "Host: api.example.com",
+		Doc: "Sets the host of the API, in swagger:meta."},
+	{Name: "BasePath", Syntax: "BasePath: /v1", Aliases: []string{"Base path"},
+		Doc: "Sets the path prefixing those of the operations, in swagger:meta."},
+	{Name: "License", Syntax: "License: name url",
+		Doc: "Sets the license of the API, in swagger:meta."},
+	{Name: "Contact", Syntax: "Contact: name <email> url",
+		Doc: "Sets the contact of the API, in swagger:meta."},
+	{Name: "Terms of service", Syntax: "Terms Of Service:\n  text",
+		Doc: "Sets the terms of service of the API, on the next lines, in swagger:meta."},
+	{Name: "ExternalDocs", Syntax: "ExternalDocs: https://url [description]", Aliases: []string{"External docs"},
+		Doc: "Sets the externalDocs of the API, in swagger:meta, of a route, of a model or of a swagger:tag."},
+	{Name: "Extensions", Syntax: "Extensions:\n  x-name: value",
+		Doc: "Sets the vendor extensions of the API, of an operation or of a field, as YAML."},
+	{Name: "InfoExtensions", Syntax: "InfoExtensions:\n  x-name: value",
+		Doc: "Sets the vendor extensions of the info object, in swagger:meta."},
+}
+
+// pathAnnotationSyntax is the form Run expects of a path annotation, as Precheck reports it.
+func pathAnnotationSyntax(name string) string {
+	for _, annotation := range pathAnnotations {
+		if annotation.name == name {
+			return annotation.expected
+		}
+	}
+	return ""
+}
+
+// Directives returns the annotations and the keys of the comments, with their documentation.
+func Directives() []Directive {
+	return slices.Clone(directives)
+}
+
+// LookupDirective finds the documentation of an annotation, e.g. swagger:route, or of a key, e.g. Maximum.
+// The keys are found by any of their spellings, ignoring the case, the spaces, the dashes and the
+// underscores, e.g. max-length or MaxLength for Max length.
+func LookupDirective(name string) (Directive, bool) {
+	key := directiveKey(name)
+	for _, directive := range directives {
+		if directiveKey(directive.Name) == key {
+			return directive, true
+		}
+		for _, alias := range directive.Aliases {
+			if directiveKey(alias) == key {
+				return directive, true
+			}
+		}
+	}
+	return Directive{}, false
+}
+
+// DirectiveAt finds the directive at an offset of a line of Go source, in bytes: the swagger: annotation, or
+// the key starting a line of a comment, which the offset is on, e.g. for the hover of an editor.
+func DirectiveAt(line string, offset int) (Directive, bool) {
+	for _, match := range rxSwaggerAnnotation.FindAllStringIndex(line, -1) {
+		if offset >= match[0] && offset <= match[1] {
+			return LookupDirective(line[match[0]:match[1]])
+		}
+	}
+
+	match := rxCommentKey.FindStringSubmatchIndex(line)
+	switch {
+	case match == nil:
+		return Directive{}, false
+	case offset >= match[2] && offset < match[3]:
+		return LookupDirective("Items")
+	case offset >= match[4] && offset <= match[5]:
+		return LookupDirective(line[match[4]:match[5]])
+	}
+	return Directive{}, false
+}
+
+func directiveKey(name string) string {
+	if annotation, isAnnotation := strings.CutPrefix(name, "swagger:"); isAnnotation {
+		return "swagger:" + annotation
+	}
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || r == '-' || r == '_' {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, name)
+}
@@ -0,0 +1,53 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirectives(t *testing.T) {
+	t.Run("should document every annotation", func(t *testing.T) {
+		for _, verb := range annotationVerbs {
+			directive, found := LookupDirective("swagger:" + verb)
+			require.True(t, found, verb)
+			assert.True(t, directive.Annotation)
+			assert.NotEmpty(t, directive.Syntax, verb)
+			assert.NotEmpty(t, directive.Doc, verb)
+		}
+		route, _ := LookupDirective("swagger:route")
+		assert.Equal(t, "swagger:route METHOD /path [tags...] operationID", route.Syntax, "the form Precheck expects")
+	})
+
+	t.Run("should find the keys by any spelling", func(t *testing.T) {
+		for _, name := range []string{"Max length", "max-length", "MaxLength", "maximum_length", "maxLen"} {
+			directive, found := LookupDirective(name)
+			require.True(t, found, name)
+			assert.Equal(t, "Max length", directive.Name)
+		}
+		_, found := LookupDirective("swagger:Route")
+		assert.False(t, found, "the annotations are case sensitive")
+	})
+
+	t.Run("should find the directive at an offset of a line", func(t *testing.T) {
+		line := "\t// Items.Max length: 10"
+		directive, found := DirectiveAt(line, 5)
+		require.True(t, found)
+		assert.Equal(t, "Items", directive.Name)
+		directive, found = DirectiveAt(line, 12)
+		require.True(t, found)
+		assert.Equal(t, "Max length", directive.Name)
+		_, found = DirectiveAt(line, 22)
+		assert.False(t, found, "on the value")
+
+		directive, found = DirectiveAt("type Pet struct{} // swagger:model pet", 24)
+		require.True(t, found)
+		assert.Equal(t, "swagger:model", directive.Name)
+		_, found = DirectiveAt("Maximum: 10", 2)
+		assert.False(t, found, "outside of a comment")
+	})
+}
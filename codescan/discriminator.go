@@ -0,0 +1,208 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// subtypeRef is a struct type embedding a discriminated base, see collectSubtypes.
+type subtypeRef struct {
+	pkgPath, name string
+}
+
+// discriminatedBase tells whether a type, or the type it points to, is a struct with a field annotated
+// swagger:discriminator, e.g. the Type of an Event embedded by UserCreated and OrderPlaced.
+func (a *typeIndex) discriminatedBase(tpe types.Type) (*types.Named, bool) {
+	if ptr, isPtr := types.Unalias(tpe).(*types.Pointer); isPtr {
+		tpe = ptr.Elem()
+	}
+	named, isNamed := types.Unalias(tpe).(*types.Named)
+	if !isNamed || named.Obj().Pkg() == nil {
+		return nil, false
+	}
+	key := namedTypeKey(named)
+	unlock := a.lock()
+	discriminated, known := a.discriminatedBases[key]
+	unlock()
+	if known {
+		return named, discriminated
+	}
+
+	if decl, found := findDeclInPackage(a.AllPackages[named.Obj().Pkg().Path()], named.Obj().Name()); found {
+		if st, isStruct := decl.Spec.Type.(*ast.StructType); isStruct {
+			discriminated = slices.ContainsFunc(st.Fields.List, func(field *ast.Field) bool {
+				return discriminatorDirective(field.Doc)
+			})
+		}
+	}
+	unlock = a.lock()
+	a.discriminatedBases[key] = discriminated
+	unlock()
+	return named, discriminated
+}
+
+// collectSubtypes indexes the struct types of the scanned packages embedding a discriminated base, by base,
+// so that the subtypes of a base are built with it, whether they are referenced or not.
+func (a *typeIndex) collectSubtypes() {
+	for _, path := range sortedKeys(a.AllPackages) {
+		pkg := a.AllPackages[path]
+		if _, accepted := a.acceptsPackage(pkg); !accepted {
+			continue
+		}
+		for _, file := range pkg.Syntax {
+			if isTestFile(pkg, file) {
+				continue
+			}
+			for _, decl := range file.Decls {
+				gd, isGen := decl.(*ast.GenDecl)
+				if !isGen {
+					continue
+				}
+				for _, sp := range gd.Specs {
+					ts, isType := sp.(*ast.TypeSpec)
+					if !isType {
+						continue
+					}
+					st, isStruct := ts.Type.(*ast.StructType)
+					if !isStruct || ts.TypeParams != nil {
+						continue
+					}
+					for _, field := range st.Fields.List {
+						if len(field.Names) > 0 || ignored(field.Doc) {
+							continue
+						}
+						if base, ok := a.discriminatedBase(pkg.TypesInfo.TypeOf(field.Type)); ok {
+							key := namedTypeKey(base)
+							a.subtypes[key] = append(a.subtypes[key], subtypeRef{pkgPath: pkg.PkgPath, name: ts.Name.Name})
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// discoverSubtypes queues the subtypes of a discriminated base for building, once the base is.
+func (s *specBuilder) discoverSubtypes(decl *entityDecl) {
+	if decl.Type == nil {
+		return
+	}
+	if _, discriminated := s.ctx.app.discriminatedBase(decl.Type); !discriminated {
+		return
+	}
+	for _, subtype := range s.ctx.app.subtypes[namedTypeKey(decl.Type)] {
+		if sub, found := s.ctx.FindDecl(subtype.pkgPath, subtype.name); found {
+			s.discovered = append(s.discovered, sub)
+		}
+	}
+}
+
+// polymorphicBase finds the discriminated base of the subtypes implementing an interface, when they all embed
+// the same one, e.g. the Event of UserCreated and OrderPlaced implementing Notification: a value of the
+// interface is one of the subtypes, which the polymorphic base documents.
+func (a *typeIndex) polymorphicBase(iface *types.Named) (subtypeRef, bool) {
+	methods, isInterface := iface.Underlying().(*types.Interface)
+	if !isInterface || methods.NumMethods() == 0 {
+		return subtypeRef{}, false
+	}
+	if a.forked {
+		a.crossPackage = true
+	}
+	var bases []string
+	for _, key := range sortedKeys(a.subtypes) {
+		implemented := slices.ContainsFunc(a.subtypes[key], func(subtype subtypeRef) bool {
+			pkg := a.AllPackages[subtype.pkgPath]
+			if pkg == nil || pkg.Types == nil {
+				return false
+			}
+			obj := pkg.Types.Scope().Lookup(subtype.name)
+			if obj == nil {
+				return false
+			}
+			return types.Implements(obj.Type(), methods) || types.Implements(types.NewPointer(obj.Type()), methods)
+		})
+		if implemented {
+			bases = append(bases, key)
+		}
+	}
+	if len(bases) != 1 {
+		return subtypeRef{}, false
+	}
+	dot := strings.LastIndex(bases[0], ".")
+	return subtypeRef{pkgPath: bases[0][:dot], name: bases[0][dot+1:]}, true
+}
+
+// setDiscriminatorField makes the property of a field annotated swagger:discriminator the required
+// discriminator of its schema. The discriminator must be a string.
+func (s *schemaBuilder) setDiscriminatorField(decl *entityDecl, fld *types.Var, schema *spec.Schema, name string) error {
+	if basic, isBasic := fld.Type().Underlying().(*types.Basic); !isBasic || basic.Info()&types.IsString == 0 {
+		return fmt.Errorf("%v: the discriminator %s of %s must be a string, not %s",
+			decl.Pkg.Fset.Position(fld.Pos()), fld.Name(), decl.Ident.Name, fld.Type())
+	}
+	schema.Discriminator = name
+	if !slices.Contains(schema.Required, name) {
+		schema.Required = append(schema.Required, name)
+	}
+	return nil
+}
+
+// checkOverrides reports the properties of a subtype overriding those of its discriminated bases with another
+// type: encoding/json marshals the field of the subtype, but no value satisfies both members of the allOf.
+// An override of the same type, e.g. narrowing the enum of the discriminator, refines the base.
+func (s *schemaBuilder) checkOverrides(decl *entityDecl, st *types.Struct, bases []*types.Named) {
+	for _, base := range bases {
+		baseStruct, isStruct := base.Underlying().(*types.Struct)
+		if !isStruct {
+			continue
+		}
+		baseFields := make(map[string]*types.Var, baseStruct.NumFields())
+		for i := range baseStruct.NumFields() {
+			if name, ok := jsonFieldName(baseStruct, i); ok {
+				baseFields[name] = baseStruct.Field(i)
+			}
+		}
+		for i := range st.NumFields() {
+			name, ok := jsonFieldName(st, i)
+			if !ok {
+				continue
+			}
+			fld := st.Field(i)
+			overridden, found := baseFields[name]
+			if !found || types.Identical(overridden.Type(), fld.Type()) {
+				continue
+			}
+			s.ctx.app.diagnose(Diagnostic{
+				Pos:  decl.Pkg.Fset.Position(fld.Pos()),
+				Code: DiagnosticOverriddenProperty,
+				Message: fmt.Sprintf("the property %s of %s overrides that of its discriminated base %s with another type, %s rather than %s, which no value of their allOf satisfies",
+					name, decl.Ident.Name, base.Obj().Name(), fld.Type(), overridden.Type()),
+			})
+		}
+	}
+}
+
+// jsonFieldName is the JSON name of a non-embedded exported field of a struct, as encoding/json marshals it.
+func jsonFieldName(st *types.Struct, i int) (string, bool) {
+	fld := st.Field(i)
+	if fld.Embedded() || !fld.Exported() {
+		return "", false
+	}
+	name, _, _ := strings.Cut(reflect.StructTag(st.Tag(i)).Get("json"), ",")
+	switch name {
+	case "-":
+		return "", false
+	case "":
+		return fld.Name(), true
+	default:
+		return name, true
+	}
+}
@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscriminator(t *testing.T) {
+	const fixtures = "github.com/3idey/codescan/fixtures/goparsing/discriminator/"
+	var diags []Diagnostic
+	doc, err := Run(&Options{
+		Packages:    []string{fixtures + "events", fixtures + "orders"},
+		ScanModels:  true,
+		Diagnostics: &diags,
+	})
+	require.NoError(t, err)
+
+	t.Run("should make the discriminator of the base required", func(t *testing.T) {
+		event := doc.Definitions["Event"]
+		assert.Equal(t, "type", event.Discriminator)
+		assert.Equal(t, []string{"type"}, event.Required)
+		assert.Empty(t, event.AllOf)
+	})
+
+	t.Run("should compose the subtypes with their base", func(t *testing.T) {
+		for name, class := range map[string]string{
+			"UserCreated": "user.created",
+			"UserDeleted": "",
+			"OrderPlaced": "order.placed",
+		} {
+			subtype, found := doc.Definitions[name]
+			require.True(t, found, name)
+			require.Len(t, subtype.AllOf, 2, name)
+			base := subtype.AllOf[0].Ref
+			assert.Equal(t, "#/definitions/Event", base.String(), name)
+			assert.NotContains(t, subtype.AllOf[1].Properties, "occurredAt", name)
+			value, ok := subtype.Extensions.GetString("x-class")
+			assert.Equal(t, class, value, name)
+			assert.Equal(t, class != "", ok, name)
+		}
+		assert.Contains(t, doc.Definitions["OrderPlaced"].AllOf[1].Properties, "total",
+			"the subtypes of another package are discovered")
+	})
+
+	t.Run("should keep the overrides refining the base", func(t *testing.T) {
+		typ := doc.Definitions["UserDeleted"].AllOf[1].Properties["type"]
+		assert.Equal(t, []any{"UserDeleted"}, typ.Enum)
+	})
+
+	t.Run("should report the overrides of another type", func(t *testing.T) {
+		var overridden []Diagnostic
+		for _, diag := range diags {
+			if diag.Code == DiagnosticOverriddenProperty {
+				overridden = append(overridden, diag)
+			}
+		}
+		require.Len(t, overridden, 1)
+		assert.Contains(t, overridden[0].Message, "the property version of OrderPlaced")
+		assert.Contains(t, doc.Definitions["OrderPlaced"].AllOf[1].Properties, "version")
+	})
+
+	t.Run("should discover the subtypes of the scanned packages only", func(t *testing.T) {
+		doc, err := Run(&Options{Packages: []string{fixtures + "events"}, ScanModels: true})
+		require.NoError(t, err)
+		assert.Contains(t, doc.Definitions, "UserCreated")
+		assert.NotContains(t, doc.Definitions, "OrderPlaced", "the package isn't scanned")
+	})
+
+	t.Run("should reject a discriminator which isn't a string", func(t *testing.T) {
+		_, err := Run(&Options{Packages: []string{fixtures + "invalid"}, ScanModels: true})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "the discriminator Kind of Shape must be a string, not int")
+	})
+
+	t.Run("should document the properties of the discriminator", func(t *testing.T) {
+		typ := doc.Definitions["Event"].Properties["type"]
+		assert.Equal(t, spec.StringOrArray{"string"}, typ.Type)
+		assert.Equal(t, "the kind of event", typ.Description)
+	})
+}
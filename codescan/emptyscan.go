@@ -0,0 +1,123 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// ErrEmptyScan is returned when the scan finds no operations and no models, unless Options.AllowEmpty is set.
+var ErrEmptyScan = errors.New("empty scan: no operations and no models found")
+
+// EmptyScanError is the ErrEmptyScan of a scan, with what was scanned and the likely causes.
+type EmptyScanError struct {
+	Packages int      // packages scanned, see Stats.Packages
+	Files    int      // files scanned, see Stats.Files
+	Causes   []string // likely causes, e.g. "the patterns ./pkg/... match no package"
+}
+
+func (e *EmptyScanError) Error() string {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "%v, scanned packages: %d, files: %d\nlikely causes:", ErrEmptyScan, e.Packages, e.Files)
+	for _, cause := range e.Causes {
+		msg.WriteString("\n  - " + cause)
+	}
+	return msg.String()
+}
+
+func (e *EmptyScanError) Unwrap() error {
+	return ErrEmptyScan
+}
+
+// countOperations returns the number of operations of a spec.
+func countOperations(doc *spec.Swagger) int {
+	if doc.Paths == nil {
+		return 0
+	}
+	count := 0
+	for _, pth := range sortedKeys(doc.Paths.Paths) {
+		pathItem := doc.Paths.Paths[pth]
+		for range pathItemOperations(&pathItem) {
+			count++
+		}
+	}
+	return count
+}
+
+// countUndocumented returns the number of operations of a spec marked x-undocumented.
+func countUndocumented(doc *spec.Swagger) int {
+	if doc.Paths == nil {
+		return 0
+	}
+	count := 0
+	for _, pth := range sortedKeys(doc.Paths.Paths) {
+		pathItem := doc.Paths.Paths[pth]
+		for _, op := range pathItemOperations(&pathItem) {
+			if isUndocumented(op) {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// checkEmptyScan fails the scan of a spec without operations and definitions, telling what was scanned and
+// the likely causes, which are recorded in the stats either way.
+func (a *typeIndex) checkEmptyScan(doc *spec.Swagger, opts *Options) error {
+	a.stats.Operations = countOperations(doc)
+	a.stats.UndocumentedOperations = countUndocumented(doc)
+	a.stats.Definitions = len(doc.Definitions)
+	if a.stats.Operations > 0 || a.stats.Definitions > 0 {
+		return nil
+	}
+	a.stats.EmptyScanCauses = a.emptyScanCauses(opts)
+	if opts.AllowEmpty {
+		return nil
+	}
+
+	return &EmptyScanError{Packages: a.stats.Packages, Files: a.stats.Files, Causes: a.stats.EmptyScanCauses}
+}
+
+func (a *typeIndex) emptyScanCauses(opts *Options) []string {
+	var causes []string
+	switch {
+	case a.packagesTotal == 0:
+		causes = append(causes, fmt.Sprintf("the patterns %s match no package", strings.Join(opts.Packages, ", ")))
+	case a.stats.Packages == 0:
+		causes = append(causes, fmt.Sprintf("the package filters (Include %v, Exclude %v) leave out the %d packages of the patterns",
+			opts.Include, opts.Exclude, a.packagesTotal))
+	case a.stats.Files == 0:
+		causes = append(causes, fmt.Sprintf("the packages have no Go files with the build tags %q", opts.BuildTags))
+	}
+	if opts.BuildTags != "" && a.stats.Files > 0 {
+		causes = append(causes, fmt.Sprintf("the build tags %q leave out the annotated files", opts.BuildTags))
+	}
+	if excluded := a.excludedByTags(); excluded > 0 {
+		causes = append(causes, fmt.Sprintf("the tag rules exclude the %d routes and operations found", excluded))
+	}
+	if len(a.stats.SkippedDirs) > 0 {
+		causes = append(causes, fmt.Sprintf("the default skips leave out %s, see AlsoScan", strings.Join(a.stats.SkippedDirs, ", ")))
+	}
+	if a.stats.Files > 0 && a.stats.Annotations == 0 {
+		causes = append(causes, "the scanned files have no swagger:route, swagger:operation or swagger:model annotation")
+	}
+	if len(causes) == 0 {
+		causes = append(causes, "the patterns name the wrong packages")
+	}
+	return causes
+}
+
+func (a *typeIndex) excludedByTags() int {
+	excluded := 0
+	for _, decision := range a.stats.TagDecisions {
+		if !decision.Kept {
+			excluded++
+		}
+	}
+	return excluded
+}
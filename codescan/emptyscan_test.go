@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmptyScan(t *testing.T) {
+	const pkg = "github.com/3idey/codescan/fixtures/goparsing/emptyscan"
+
+	t.Run("should fail on a scan without annotations", func(t *testing.T) {
+		var stats Stats
+		_, err := Run(&Options{Packages: []string{pkg}, Stats: &stats})
+		require.ErrorIs(t, err, ErrEmptyScan)
+		assert.Contains(t, err.Error(), "scanned packages: 1, files: 1")
+		var empty *EmptyScanError
+		require.ErrorAs(t, err, &empty)
+		assert.Equal(t, 1, empty.Files)
+		assert.Contains(t, err.Error(), "the scanned files have no swagger:route, swagger:operation or swagger:model annotation")
+
+		assert.Zero(t, stats.Operations)
+		assert.Zero(t, stats.Definitions)
+		assert.Equal(t, []string{"the scanned files have no swagger:route, swagger:operation or swagger:model annotation"}, stats.EmptyScanCauses)
+	})
+
+	t.Run("should tell the filters which leave out everything", func(t *testing.T) {
+		_, err := Run(&Options{Packages: []string{pkg + "/..."}, Exclude: []string{"goparsing/emptyscan"}})
+		require.ErrorIs(t, err, ErrEmptyScan)
+		assert.Contains(t, err.Error(), "the package filters (Include [], Exclude [goparsing/emptyscan]) leave out the 2 packages of the patterns")
+
+		_, err = Run(&Options{Packages: []string{pkg + "/tagged"}, ExcludeTags: []string{"internal"}})
+		require.ErrorIs(t, err, ErrEmptyScan)
+		assert.Contains(t, err.Error(), "the tag rules exclude the 1 routes and operations found")
+	})
+
+	t.Run("should accept an empty scan with AllowEmpty", func(t *testing.T) {
+		var stats Stats
+		doc, err := Run(&Options{Packages: []string{pkg}, AllowEmpty: true, Stats: &stats})
+		require.NoError(t, err)
+		assert.Empty(t, doc.Paths.Paths)
+		assert.NotEmpty(t, stats.EmptyScanCauses)
+	})
+
+	t.Run("should count the operations and definitions of the spec", func(t *testing.T) {
+		var stats Stats
+		_, err := Run(&Options{Packages: []string{pkg + "/tagged"}, Stats: &stats})
+		require.NoError(t, err)
+		assert.Equal(t, 1, stats.Operations)
+		assert.Empty(t, stats.EmptyScanCauses)
+	})
+}
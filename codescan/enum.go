@@ -4,11 +4,18 @@
 package codescan
 
 import (
+	"fmt"
 	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/go-openapi/spec"
+	"golang.org/x/tools/go/packages"
 )
 
 func getEnumBasicLitValue(basicLit *ast.BasicLit) any {
@@ -27,9 +34,319 @@ func getEnumBasicLitValue(basicLit *ast.BasicLit) any {
 	return nil
 }
 
+// discoverEnum documents the exported constants of a named basic type as its enum, with Options.DiscoverEnums.
+// A type annotated with swagger:enum, swagger:enum:ignore or an enum: directive is left alone.
+func (s *schemaBuilder) discoverEnum(named *types.Named, cmt *ast.CommentGroup, tgt swaggerTypable) bool {
+	if !s.ctx.opts.DiscoverEnums || enumIgnored(cmt) || hasEnumDirective(cmt) {
+		return false
+	}
+	if _, explicit := enumName(cmt); explicit {
+		return false
+	}
+	pkg, found := s.ctx.PkgForType(named)
+	if !found {
+		return false
+	}
+
+	values, descs, names := discoverEnumValues(pkg, named)
+	if len(values) == 0 {
+		return false
+	}
+	if s.omitsEnum(named, cmt, len(values), tgt) {
+		return true
+	}
+	tgt.WithEnum(values...)
+	if s.ctx.opts.EnumExtensionStyle != "" {
+		tgt.WithEnumNames(names)
+	}
+	tgt.WithEnumDescription(strings.Join(descs, "\n"))
+	return true
+}
+
+// DefaultMaxEnumValues is the number of values above which the codescan command omits the enum of a type,
+// see Options.MaxEnumValues.
+const DefaultMaxEnumValues = 1000
+
+// enumReferenceExtension names the Go type of an enum omitted from a schema, see omitsEnum.
+const enumReferenceExtension = "x-enum-reference"
+
+// rxEnumPolicy matches the directive of a type keeping its enum whatever its size, or omitting it, see omitsEnum.
+// It isn't an enum: directive of the values full or omit.
+var rxEnumPolicy = regexp.MustCompile(`^[\p{Zs}\t/\*-]*[Ee]num\p{Zs}*:\p{Zs}*(full|omit)\p{Zs}*$`)
+
+// Enum policies of the directive of a type, see rxEnumPolicy.
+const (
+	enumPolicyFull = "full"
+	enumPolicyOmit = "omit"
+)
+
+// omitsEnum tells if the enum of a named type is left out of a schema: with the Enum: omit directive of the
+// type, or with more values than Options.MaxEnumValues, unless Enum: full. The schema names the Go type with
+// x-enum-reference instead, and a large-enum diagnostic reports the enums omitted for their size.
+func (s *schemaBuilder) omitsEnum(named *types.Named, cmt *ast.CommentGroup, count int, tgt swaggerTypable) bool {
+	policy, _ := commentSubMatcher(rxEnumPolicy)(cmt)
+	limit := s.ctx.opts.MaxEnumValues
+	switch {
+	case policy == enumPolicyFull:
+		return false
+	case policy == enumPolicyOmit:
+	case limit <= 0 || count <= limit:
+		return false
+	default:
+		s.ctx.app.diagnose(Diagnostic{
+			Pos:  s.decl.Pkg.Fset.Position(named.Origin().Obj().Pos()),
+			Code: DiagnosticLargeEnum,
+			Message: fmt.Sprintf("the enum of %s has %d values, more than %d: it is omitted, and x-enum-reference names the type; the Enum: full directive of the type keeps it",
+				namedTypeKey(named), count, limit),
+		})
+	}
+	tgt.AddExtension(enumReferenceExtension, namedTypeKey(named))
+	return true
+}
+
+// discoverDeclEnum discovers the enum of a declared named basic type, or of an alias expanded to one, before
+// its doc comment is parsed, so that the description of the model lists the values.
+func (s *schemaBuilder) discoverDeclEnum(schema *spec.Schema) {
+	var named *types.Named
+	switch tpe := s.decl.ObjType().(type) {
+	case *types.Named:
+		named = tpe
+	case *types.Alias:
+		if s.ctx.app.refAliases {
+			return
+		}
+		named, _ = types.Unalias(tpe).(*types.Named)
+	}
+	if named != nil {
+		s.discoverEnum(named, s.decl.Comments, schemaTypable{schema, 0})
+	}
+}
+
+// discoverEnumValues returns the values of the exported constants of a named type declared in its package,
+// in declaration order, across the const blocks and whether they are typed with the type or one of its aliases.
+func discoverEnumValues(pkg *packages.Package, named *types.Named) (values []any, descs, names []string) {
+	basic, ok := named.Underlying().(*types.Basic)
+	if !ok {
+		return nil, nil, nil
+	}
+
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				vs, ok := spec.(*ast.ValueSpec)
+				if !ok {
+					continue
+				}
+				doc := vs.Doc
+				if doc == nil && !gd.Lparen.IsValid() {
+					doc = gd.Doc
+				}
+				for _, ident := range vs.Names {
+					cnst, ok := pkg.TypesInfo.Defs[ident].(*types.Const)
+					if !ok || !cnst.Exported() || !types.Identical(types.Unalias(cnst.Type()), named) {
+						continue
+					}
+					value := constantValue(cnst.Val(), basic)
+					values = append(values, value)
+					names = append(names, ident.Name)
+					descs = append(descs, enumValueDescription(value, ident.Name, doc))
+				}
+			}
+		}
+	}
+
+	return values, descs, names
+}
+
+// constantValue returns the Go value of a constant of a basic type, like getEnumBasicLitValue.
+func constantValue(val constant.Value, basic *types.Basic) any {
+	switch {
+	case basic.Info()&types.IsString != 0:
+		return constant.StringVal(val)
+	case basic.Info()&types.IsBoolean != 0:
+		return constant.BoolVal(val)
+	case basic.Info()&types.IsFloat != 0:
+		f, _ := constant.Float64Val(constant.ToFloat(val))
+		return f
+	default:
+		i, _ := constant.Int64Val(constant.ToInt(val))
+		return i
+	}
+}
+
+// enumValueDescription describes an enum value like the constants of a swagger:enum: its value, its name and its doc.
+func enumValueDescription(value any, name string, doc *ast.CommentGroup) string {
+	desc := fmt.Sprintf("%v %s", value, name)
+	if text := strings.Join(strings.Fields(doc.Text()), " "); text != "" {
+		desc += " " + text
+	}
+	return desc
+}
+
 const extEnumDesc = "x-go-enum-desc"
 
 func getEnumDesc(extensions spec.Extensions) (desc string) {
 	desc, _ = extensions.GetString(extEnumDesc)
 	return desc
 }
+
+// Enum extension styles, see Options.EnumExtensionStyle.
+const (
+	EnumStyleGoSwagger = "go-swagger"
+	EnumStyleNSwag     = "nswag"
+	EnumStyleBoth      = "both"
+)
+
+const (
+	extEnumVarNames         = "x-enum-varnames"
+	extEnumNames            = "x-enumNames" // camel case: set without AddExtension, which lower cases keys
+	extDiscriminatorMapping = "x-discriminator-mapping"
+)
+
+func checkEnumExtensionStyle(style string) error {
+	switch style {
+	case "", EnumStyleGoSwagger, EnumStyleNSwag, EnumStyleBoth:
+		return nil
+	default:
+		return fmt.Errorf("unknown enum extension style %q, expected %s, %s or %s", style, EnumStyleGoSwagger, EnumStyleNSwag, EnumStyleBoth)
+	}
+}
+
+// applyEnumExtensions emits the names of the enum values and the discriminator mappings with the extensions
+// of a style. The names of the constants of a swagger:enum are recorded as x-enum-varnames while building,
+// the values of the other enums are their own names, so that all the enums are documented the same way.
+func applyEnumExtensions(doc *spec.Swagger, style string) {
+	if style == "" {
+		return
+	}
+
+	walkSpecEnums(doc, func(enum []any, holder *spec.VendorExtensible) {
+		names, ok := holder.Extensions[extEnumVarNames].([]string)
+		if !ok || len(names) != len(enum) {
+			names = make([]string, 0, len(enum))
+			for _, value := range enum {
+				names = append(names, fmt.Sprint(value))
+			}
+		}
+
+		if style == EnumStyleNSwag {
+			delete(holder.Extensions, extEnumVarNames)
+		} else {
+			holder.AddExtension(extEnumVarNames, names)
+		}
+		if style != EnumStyleGoSwagger {
+			if holder.Extensions == nil {
+				holder.Extensions = make(spec.Extensions)
+			}
+			holder.Extensions[extEnumNames] = names
+		}
+	})
+
+	if style != EnumStyleGoSwagger {
+		addDiscriminatorMappings(doc)
+	}
+}
+
+// addDiscriminatorMappings spells out the subtypes of the discriminated definitions, which swagger 2.0 leaves
+// implicit: the discriminator value of a subtype is its x-class, or its name.
+func addDiscriminatorMappings(doc *spec.Swagger) {
+	for _, base := range sortedKeys(doc.Definitions) {
+		definition := doc.Definitions[base]
+		if definition.Discriminator == "" {
+			continue
+		}
+
+		mapping := make(map[string]string)
+		baseRef := definitionsPrefix + escapePointer(base)
+		for _, name := range sortedKeys(doc.Definitions) {
+			subtype := doc.Definitions[name]
+			if !slices.ContainsFunc(subtype.AllOf, func(member spec.Schema) bool { return member.Ref.String() == baseRef }) {
+				continue
+			}
+			value, ok := subtype.Extensions.GetString("x-class")
+			if !ok {
+				value = name
+			}
+			mapping[value] = definitionsPrefix + escapePointer(name)
+		}
+		if len(mapping) == 0 {
+			continue
+		}
+
+		definition.AddExtension(extDiscriminatorMapping, mapping)
+		doc.Definitions[base] = definition
+	}
+}
+
+// walkSpecEnums visits the elements of a spec holding an enum: schemas, simple parameters, headers and their items.
+func walkSpecEnums(doc *spec.Swagger, visit func(enum []any, holder *spec.VendorExtensible)) {
+	walkSpecSchemas(doc, func(schema *spec.Schema, _ string) {
+		if len(schema.Enum) > 0 {
+			visit(schema.Enum, &schema.VendorExtensible)
+		}
+	})
+
+	items := func(it *spec.Items) {
+		for ; it != nil; it = it.Items {
+			if len(it.Enum) > 0 {
+				visit(it.Enum, &it.VendorExtensible)
+			}
+		}
+	}
+	params := func(params []spec.Parameter) {
+		for i := range params {
+			if len(params[i].Enum) > 0 {
+				visit(params[i].Enum, &params[i].VendorExtensible)
+			}
+			items(params[i].Items)
+		}
+	}
+	headers := func(resp *spec.Response) {
+		for _, name := range sortedKeys(resp.Headers) {
+			header := resp.Headers[name]
+			if len(header.Enum) > 0 {
+				visit(header.Enum, &header.VendorExtensible)
+			}
+			items(header.Items)
+			resp.Headers[name] = header
+		}
+	}
+
+	for _, name := range sortedKeys(doc.Parameters) {
+		param := []spec.Parameter{doc.Parameters[name]}
+		params(param)
+		doc.Parameters[name] = param[0]
+	}
+	for _, name := range sortedKeys(doc.Responses) {
+		resp := doc.Responses[name]
+		headers(&resp)
+		doc.Responses[name] = resp
+	}
+
+	if doc.Paths == nil {
+		return
+	}
+	for _, pth := range sortedKeys(doc.Paths.Paths) {
+		pathItem := doc.Paths.Paths[pth]
+		params(pathItem.Parameters)
+		for _, op := range pathItemOperations(&pathItem) {
+			params(op.Parameters)
+			if op.Responses == nil {
+				continue
+			}
+			if op.Responses.Default != nil {
+				headers(op.Responses.Default)
+			}
+			for _, code := range sortedKeys(op.Responses.StatusCodeResponses) {
+				resp := op.Responses.StatusCodeResponses[code]
+				headers(&resp)
+				op.Responses.StatusCodeResponses[code] = resp
+			}
+		}
+		doc.Paths.Paths[pth] = pathItem
+	}
+}
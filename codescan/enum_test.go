@@ -8,7 +8,10 @@ import (
 	"go/token"
 	"testing"
 
+	"github.com/go-openapi/spec"
+
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_getEnumBasicLitValue(t *testing.T) {
@@ -37,3 +40,169 @@ func verifyGetEnumBasicLitValue(t *testing.T, basicLit ast.BasicLit, expected an
 
 	assert.Equal(t, expected, actual)
 }
+
+func TestEnumExtensionStyle(t *testing.T) {
+	const packages = "github.com/3idey/codescan/fixtures/goparsing/enumstyle"
+
+	for _, tc := range []struct {
+		style              string
+		varNames, nswagged bool
+	}{
+		{"", false, false},
+		{EnumStyleGoSwagger, true, false},
+		{EnumStyleNSwag, false, true},
+		{EnumStyleBoth, true, true},
+	} {
+		t.Run("style "+tc.style, func(t *testing.T) {
+			doc, err := Run(&Options{Packages: []string{packages}, ScanModels: true, EnumExtensionStyle: tc.style})
+			require.NoError(t, err)
+
+			paint := doc.Definitions["Paint"]
+			color, finish := paint.Properties["color"], paint.Properties["finish"]
+			pet := doc.Definitions["Pet"]
+			if tc.varNames {
+				assert.Equal(t, []string{"ColorRed", "ColorBlue"}, color.Extensions[extEnumVarNames])
+				assert.Equal(t, []string{"matte", "gloss"}, finish.Extensions[extEnumVarNames], "the values name themselves")
+			} else {
+				assert.NotContains(t, color.Extensions, extEnumVarNames)
+				assert.NotContains(t, finish.Extensions, extEnumVarNames)
+			}
+			if tc.nswagged {
+				assert.Equal(t, []string{"ColorRed", "ColorBlue"}, color.Extensions[extEnumNames])
+				assert.Equal(t, []string{"matte", "gloss"}, finish.Extensions[extEnumNames])
+				assert.Equal(t, map[string]string{
+					"dog": "#/definitions/Dog",
+					"Cat": "#/definitions/Cat",
+				}, pet.Extensions[extDiscriminatorMapping])
+			} else {
+				assert.NotContains(t, color.Extensions, extEnumNames)
+				assert.NotContains(t, pet.Extensions, extDiscriminatorMapping)
+			}
+			assert.Equal(t, "red ColorRed\nblue ColorBlue", color.Extensions[extEnumDesc])
+		})
+	}
+}
+
+func TestDiscoverEnums(t *testing.T) {
+	const pkg = "github.com/3idey/codescan/fixtures/goparsing/autoenums"
+
+	t.Run("should document the constants of the named types as their enums", func(t *testing.T) {
+		doc, err := Run(&Options{Packages: []string{pkg}, DiscoverEnums: true, EnumExtensionStyle: EnumStyleGoSwagger})
+		require.NoError(t, err)
+
+		priority := doc.Definitions["Priority"]
+		assert.Equal(t, []any{int64(1), int64(2), int64(3)}, priority.Enum, "the values of iota")
+		assert.Equal(t, []string{"Low", "Medium", "High"}, priority.Extensions[extEnumVarNames])
+		assert.Equal(t, "1 Low\n2 Medium\n3 High", priority.Description)
+
+		level := doc.Definitions["Level"]
+		assert.Equal(t, []any{"beginner", "expert"}, level.Enum, "the untyped constants of the block are left out")
+		assert.Equal(t, "Level has a constant which isn't a Level.", level.Title)
+
+		color := doc.Definitions["Color"]
+		assert.Equal(t, []any{"red", "blue", "green", "teal"}, color.Enum,
+			"the constants of the imported package, across the blocks and with the alias, without the unexported ones")
+		assert.Contains(t, getEnumDesc(color.Extensions), "red Red Red is the default color.")
+		assert.Equal(t, color.Enum, doc.Definitions["Shade"].Enum, "the alias has the enum of its type")
+
+		assert.Empty(t, doc.Definitions["Mode"].Enum, "swagger:enum:ignore opts out")
+
+		params := doc.Paths.Paths["/tasks"].Get.Parameters
+		require.Len(t, params, 3)
+		assert.Equal(t, []any{int64(1), int64(2), int64(3)}, params[0].Enum, "the simple parameters have the enum inline")
+		assert.Equal(t, []any{"red", "blue", "green", "teal"}, params[1].Enum)
+		assert.Equal(t, []any{"expert"}, params[2].Enum, "an enum directive overrides the discovery")
+	})
+
+	t.Run("should leave the enums out by default", func(t *testing.T) {
+		doc, err := Run(&Options{Packages: []string{pkg}})
+		require.NoError(t, err)
+
+		assert.Empty(t, doc.Definitions["Priority"].Enum)
+		assert.Empty(t, doc.Paths.Paths["/tasks"].Get.Parameters[0].Enum)
+	})
+
+	t.Run("should only discover the constants of the scanned packages with ExcludeDeps", func(t *testing.T) {
+		doc, err := Run(&Options{Packages: []string{pkg, pkg + "/palette"}, DiscoverEnums: true, ExcludeDeps: true})
+		require.NoError(t, err)
+
+		assert.NotEmpty(t, doc.Definitions["Priority"].Enum)
+		assert.Equal(t, []any{"red", "blue", "green", "teal"}, doc.Paths.Paths["/tasks"].Get.Parameters[1].Enum)
+	})
+}
+
+func TestMaxEnumValues(t *testing.T) {
+	const (
+		pkg     = "github.com/3idey/codescan/fixtures/goparsing/largeenums"
+		locale  = pkg + ".Locale"
+		country = pkg + ".Country"
+	)
+	run := func(t *testing.T, opts Options) (*spec.Swagger, []Diagnostic) {
+		var diagnostics []Diagnostic
+		opts.Packages = []string{pkg}
+		opts.DiscoverEnums = true
+		opts.Logger = func(diagnostic Diagnostic) { diagnostics = append(diagnostics, diagnostic) }
+		doc, err := Run(&opts)
+		require.NoError(t, err)
+		return doc, diagnostics
+	}
+
+	t.Run("should keep a large enum whole with the zero value of the options", func(t *testing.T) {
+		doc, diagnostics := run(t, Options{})
+		locale := doc.Definitions["Account"].Properties["locale"]
+		assert.Len(t, locale.Enum, 1001)
+		assert.NotContains(t, locale.Extensions, enumReferenceExtension)
+		assert.Len(t, doc.Paths.Paths["/accounts"].Get.Parameters[1].Enum, 1001)
+		assert.Empty(t, diagnostics)
+	})
+
+	t.Run("should omit the enums above the default limit of the command", func(t *testing.T) {
+		doc, diagnostics := run(t, Options{MaxEnumValues: DefaultMaxEnumValues})
+		props := doc.Definitions["Account"].Properties
+		assert.Empty(t, props["locale"].Enum)
+		assert.Equal(t, spec.StringOrArray{"string"}, props["locale"].Type, "the type of the values is kept")
+		assert.Equal(t, locale, props["locale"].Extensions[enumReferenceExtension])
+		assert.Empty(t, getEnumDesc(props["locale"].Extensions))
+		assert.Len(t, doc.Definitions["Country"].Enum, 4)
+
+		params := doc.Paths.Paths["/accounts"].Get.Parameters
+		require.Len(t, params, 2)
+		assert.Empty(t, params[1].Enum)
+		assert.Equal(t, locale, params[1].Extensions[enumReferenceExtension])
+
+		require.Len(t, diagnostics, 1, "once per type")
+		assert.Equal(t, DiagnosticLargeEnum, diagnostics[0].Code)
+		assert.Equal(t, 7, diagnostics[0].Pos.Line)
+		assert.Equal(t, "the enum of "+locale+" has 1001 values, more than 1000: it is omitted, and x-enum-reference names the type; "+
+			"the Enum: full directive of the type keeps it", diagnostics[0].Message)
+	})
+
+	t.Run("should omit the enums above the limit", func(t *testing.T) {
+		doc, diagnostics := run(t, Options{MaxEnumValues: 3})
+		assert.Empty(t, doc.Definitions["Country"].Enum)
+		assert.Equal(t, country, doc.Definitions["Country"].Extensions[enumReferenceExtension])
+		assert.Equal(t, country, doc.Paths.Paths["/accounts"].Get.Parameters[0].Extensions[enumReferenceExtension])
+		require.Len(t, diagnostics, 2)
+	})
+
+	t.Run("should follow the directives of the types", func(t *testing.T) {
+		doc, diagnostics := run(t, Options{MaxEnumValues: 3})
+		currency := doc.Definitions["Account"].Properties["currency"]
+		assert.Equal(t, []any{"EUR", "USD", "GBP", "JPY"}, currency.Enum, "Enum: full keeps the enum")
+		assert.NotContains(t, currency.Extensions, enumReferenceExtension)
+
+		status := doc.Definitions["Status"]
+		assert.Empty(t, status.Enum, "Enum: omit omits the enum, rather than setting the value omit")
+		assert.Equal(t, pkg+".Status", status.Extensions[enumReferenceExtension])
+		assert.Equal(t, "Status omits its enum whatever its size.", status.Title)
+		for _, diagnostic := range diagnostics {
+			assert.NotContains(t, diagnostic.Message, "Status", "an omission asked for isn't reported")
+		}
+	})
+
+	t.Run("should keep the enums without a limit", func(t *testing.T) {
+		doc, diagnostics := run(t, Options{MaxEnumValues: -1})
+		assert.Len(t, doc.Definitions["Account"].Properties["locale"].Enum, 1001)
+		assert.Empty(t, diagnostics)
+	})
+}
@@ -0,0 +1,157 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// inferredExtension marks the responses inferred from the calls of the error helpers, see Options.ErrorHelpers.
+const inferredExtension = "x-inferred"
+
+// ErrorHelper is a function writing an error response, called by the handlers, see Options.ErrorHelpers:
+//
+//	httperr.NotFound(w, "user not found")
+type ErrorHelper struct {
+	// Func is the qualified name of the function, like ContentNegotiator.Func, or a pattern of names where *
+	// matches any part of a name, e.g. httperr.*.
+	Func string
+	// Status is the status code of the response written by the function. When 0, it is its first argument
+	// which is a constant status code, e.g. of http.Error(w, msg, http.StatusBadRequest).
+	Status int
+	// Model is the body of the response: the Go type qualified by its import path, e.g.
+	// github.com/acme/httperr.Error, or the name of a definition. The response has no schema without one.
+	Model string
+}
+
+func checkErrorHelpers(helpers []ErrorHelper) error {
+	var errs []error
+	for _, helper := range helpers {
+		if _, err := path.Match(helper.Func, ""); err != nil || helper.Func == "" {
+			errs = append(errs, fmt.Errorf("invalid function %q", helper.Func))
+		}
+		if helper.Status != 0 && (helper.Status < 100 || helper.Status > 599) {
+			errs = append(errs, fmt.Errorf("invalid status %d of %s", helper.Status, helper.Func))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// helperFor returns the error helper called, if any.
+func helperFor(helpers []ErrorHelper, names []string) (ErrorHelper, bool) {
+	for _, helper := range helpers {
+		for _, name := range names {
+			if matched, _ := path.Match(helper.Func, name); matched {
+				return helper, true
+			}
+		}
+	}
+	return ErrorHelper{}, false
+}
+
+// inferredError is a response written by the call of an error helper.
+type inferredError struct {
+	helper  ErrorHelper
+	status  int
+	message string
+	call    *ast.CallExpr
+}
+
+// inferErrorResponses adds to an operation the responses written by the error helpers its handler calls,
+// unless it documents their status. The description of a response is the first constant string passed to
+// the first call writing its status, e.g. the message of http.Error, or the text of the status. It returns
+// the declarations of the models of the responses, to build with the definitions.
+func (s *scanCtx) inferErrorResponses(pp parsedPathContent, op *spec.Operation) ([]*entityDecl, error) {
+	if len(s.opts.ErrorHelpers) == 0 || pp.handler == nil {
+		return nil, nil
+	}
+
+	var inferred []inferredError
+	ast.Inspect(pp.handler.Body, func(node ast.Node) bool {
+		call, isCall := node.(*ast.CallExpr)
+		if !isCall {
+			return true
+		}
+		helper, ok := helperFor(s.opts.ErrorHelpers, calleeNames(pp.pkg, call))
+		if !ok {
+			return true
+		}
+		found := inferredError{helper: helper, status: helper.Status, call: call}
+		for _, arg := range call.Args {
+			value := pp.pkg.TypesInfo.Types[arg].Value
+			switch {
+			case value == nil:
+			case value.Kind() == constant.String && found.message == "":
+				found.message = constant.StringVal(value)
+			case found.status == 0:
+				found.status, _ = constantStatus(pp.pkg, arg)
+			}
+		}
+		if found.status != 0 {
+			inferred = append(inferred, found)
+		}
+		return true
+	})
+
+	var decls []*entityDecl
+	for _, found := range inferred {
+		if op.Responses == nil {
+			op.Responses = new(spec.Responses)
+		}
+		if _, documented := op.Responses.StatusCodeResponses[found.status]; documented {
+			continue
+		}
+
+		response := spec.NewResponse().WithDescription(cmp.Or(found.message, http.StatusText(found.status)))
+		if found.helper.Model != "" {
+			ref, decl, err := s.errorModelRef(found.helper.Model)
+			if err != nil {
+				return nil, fmt.Errorf("%v: %s: %w", pp.pkg.Fset.Position(found.call.Pos()), found.helper.Func, err)
+			}
+			response.WithSchema(&spec.Schema{SchemaProps: spec.SchemaProps{Ref: ref}})
+			if decl != nil {
+				decls = append(decls, decl)
+			}
+		}
+		response.AddExtension(inferredExtension, true)
+		s.app.recordPosition(&response.VendorExtensible, pp.pkg.Fset.Position(found.call.Pos()))
+		if op.Responses.StatusCodeResponses == nil {
+			op.Responses.StatusCodeResponses = make(map[int]spec.Response)
+		}
+		op.Responses.StatusCodeResponses[found.status] = *response
+	}
+	return decls, nil
+}
+
+// errorModelRef returns the ref to the model of an error helper, with the declaration of the model to build,
+// if it is a Go type.
+func (s *scanCtx) errorModelRef(model string) (spec.Ref, *entityDecl, error) {
+	var decl *entityDecl
+	if dot := strings.LastIndex(model, "."); dot > 0 && strings.Contains(model[:dot], "/") {
+		var found bool
+		if decl, found = s.FindModel(model[:dot], model[dot+1:]); !found {
+			return spec.Ref{}, nil, fmt.Errorf("unknown model %s", model)
+		}
+	} else if decl, _ = s.FindModelByName(model); decl == nil {
+		// a definition of the input spec
+		ref, err := spec.NewRef(definitionsPrefix + model)
+		return ref, nil, err
+	}
+
+	if ref, indexed := s.app.indexedRef(decl); indexed {
+		return ref, nil, nil
+	}
+	name, _ := decl.Names()
+	ref, err := spec.NewRef(definitionsPrefix + name)
+	return ref, decl, err
+}
@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorHelpers(t *testing.T) {
+	const (
+		pkg     = "github.com/3idey/codescan/fixtures/goparsing/errorhelpers"
+		problem = pkg + "/httperr.Problem"
+	)
+	helpers := []ErrorHelper{
+		{Func: "httperr.NotFound", Status: 404, Model: problem},
+		{Func: pkg + "/httperr.Bad*", Status: 400, Model: problem},
+		{Func: "net/http.Error"},
+	}
+	var diagnostics []Diagnostic
+	doc, err := Run(&Options{
+		Packages:         []string{pkg},
+		ErrorHelpers:     helpers,
+		CheckStatusCodes: true,
+		Diagnostics:      &diagnostics,
+	})
+	require.NoError(t, err)
+
+	t.Run("should add the responses written by the helpers", func(t *testing.T) {
+		responses := doc.Paths.Paths["/users/{id}"].Get.Responses.StatusCodeResponses
+		require.Len(t, responses, 4)
+
+		notFound := responses[404]
+		assert.Equal(t, "user not found", notFound.Description, "the description is the message")
+		require.NotNil(t, notFound.Schema)
+		assert.Equal(t, "#/definitions/Problem", notFound.Schema.Ref.String())
+		assert.Equal(t, true, notFound.Extensions[inferredExtension])
+		assert.Contains(t, doc.Definitions, "Problem", "the model is built")
+
+		assert.Equal(t, "Bad Request", responses[400].Description, "a dynamic message is the text of the status")
+		assert.Equal(t, "forbidden user", responses[403].Description, "the status of http.Error is its argument")
+		assert.Nil(t, responses[403].Schema)
+		assert.NotContains(t, responses[200].Extensions, inferredExtension)
+	})
+
+	t.Run("should keep the documented responses", func(t *testing.T) {
+		notFound := doc.Paths.Paths["/users/{id}"].Delete.Responses.StatusCodeResponses[404]
+		assert.Equal(t, " no such user", notFound.Description)
+		assert.NotContains(t, notFound.Extensions, inferredExtension)
+	})
+
+	t.Run("should infer the responses of the swagger:operation handlers", func(t *testing.T) {
+		badRequest := doc.Paths.Paths["/users"].Get.Responses.StatusCodeResponses[400]
+		assert.Equal(t, "invalid query", badRequest.Description)
+		assert.Equal(t, true, badRequest.Extensions[inferredExtension])
+	})
+
+	t.Run("should check the status codes with the inferred responses", func(t *testing.T) {
+		for _, diagnostic := range diagnostics {
+			assert.NotEqual(t, DiagnosticUndocumentedStatus, diagnostic.Code, diagnostic.Message)
+		}
+	})
+
+	t.Run("should infer nothing without helpers", func(t *testing.T) {
+		doc, err := Run(&Options{Packages: []string{pkg}})
+		require.NoError(t, err)
+		assert.Len(t, doc.Paths.Paths["/users/{id}"].Get.Responses.StatusCodeResponses, 1)
+		assert.NotContains(t, doc.Definitions, "Problem")
+	})
+
+	t.Run("should fail on an unknown model", func(t *testing.T) {
+		_, err := Run(&Options{
+			Packages:     []string{pkg},
+			ErrorHelpers: []ErrorHelper{{Func: "httperr.NotFound", Status: 404, Model: pkg + "/httperr.Missing"}},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "api.go:")
+		assert.Contains(t, err.Error(), "httperr.NotFound: unknown model "+pkg+"/httperr.Missing")
+	})
+
+	t.Run("should reject the invalid helpers", func(t *testing.T) {
+		err := (&Options{ErrorHelpers: []ErrorHelper{{Func: "httperr.[", Status: 404}, {Func: "httperr.Gone", Status: 4100}}}).Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "ErrorHelpers")
+		assert.Contains(t, err.Error(), `invalid function "httperr.["`)
+		assert.Contains(t, err.Error(), "invalid status 4100 of httperr.Gone")
+	})
+}
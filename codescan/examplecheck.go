@@ -0,0 +1,385 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-openapi/spec"
+)
+
+// checkNamedExamples reports the named examples of the body parameters and the responses of a spec, see
+// namedExamplesExtension, whose values don't match their schemas, with DiagnosticInvalidExample diagnostics
+// giving the name of the example, positioned at the Go declaration of the parameter or the response. It
+// fails with the examples reported as errors, see Options.ValidateExamples.
+func (a *typeIndex) checkNamedExamples(doc *spec.Swagger) error {
+	checker := &exampleChecker{definitions: doc.Definitions}
+	var found []Diagnostic
+	check := func(ext spec.VendorExtensible, schema *spec.Schema, location string) {
+		examples := namedExamples(ext.Extensions)
+		if len(examples) == 0 || schema == nil {
+			return
+		}
+		pos, _ := positionValue(ext.Extensions[sourcePositionExtension])
+		for _, name := range sortedKeys(examples) {
+			example, _ := examples[name].(map[string]any)
+			value, hasValue := example["value"]
+			if !hasValue {
+				continue
+			}
+			problems := checker.check(schema, value)
+			if len(problems) == 0 {
+				continue
+			}
+			diagnostic := Diagnostic{
+				Pos:     pos,
+				Code:    DiagnosticInvalidExample,
+				Message: fmt.Sprintf("the example %q of %s doesn't match its schema: %s", name, location, strings.Join(problems, "; ")),
+			}
+			a.record(&diagnostic)
+			if diagnostic.Severity == SeverityError {
+				found = append(found, diagnostic)
+			}
+		}
+	}
+	params := func(params []spec.Parameter, location string) {
+		for i, param := range params {
+			if param.In == "body" {
+				check(param.VendorExtensible, param.Schema, location+"/parameters/"+strconv.Itoa(i))
+			}
+		}
+	}
+	responses := func(responses *spec.Responses, location string) {
+		if responses == nil {
+			return
+		}
+		if responses.Default != nil {
+			check(responses.Default.VendorExtensible, responses.Default.Schema, location+"/responses/default")
+		}
+		for _, code := range sortedKeys(responses.StatusCodeResponses) {
+			resp := responses.StatusCodeResponses[code]
+			check(resp.VendorExtensible, resp.Schema, location+"/responses/"+strconv.Itoa(code))
+		}
+	}
+
+	for _, name := range sortedKeys(doc.Parameters) {
+		param := doc.Parameters[name]
+		if param.In == "body" {
+			check(param.VendorExtensible, param.Schema, "/parameters/"+escapePointer(name))
+		}
+	}
+	for _, name := range sortedKeys(doc.Responses) {
+		resp := doc.Responses[name]
+		check(resp.VendorExtensible, resp.Schema, "/responses/"+escapePointer(name))
+	}
+	if doc.Paths != nil {
+		for _, pth := range sortedKeys(doc.Paths.Paths) {
+			item := doc.Paths.Paths[pth]
+			location := "/paths/" + escapePointer(pth)
+			params(item.Parameters, location)
+			for method, op := range pathItemOperations(&item) {
+				params(op.Parameters, location+"/"+method)
+				responses(op.Responses, location+"/"+method)
+			}
+		}
+	}
+	if len(found) == 0 {
+		return nil
+	}
+
+	summary := fmt.Sprintf("%d examples don't match their schemas", len(found))
+	if len(found) == 1 {
+		summary = "1 example doesn't match its schema"
+	}
+	errs := []error{errors.New(summary)}
+	for _, diagnostic := range found {
+		errs = append(errs, diagnostic)
+	}
+	return errors.Join(errs...)
+}
+
+// maxExampleRefs bounds the $refs followed for a value, e.g. of a definition composing itself by allOf.
+const maxExampleRefs = 32
+
+// exampleChecker checks values against the schemas of a spec, resolving the $refs to its definitions: the
+// types, with x-nullable, the enums, the validations, the required properties and those which
+// additionalProperties forbids. The formats aren't checked, nor are the $refs to other documents.
+type exampleChecker struct {
+	definitions spec.Definitions
+}
+
+// check returns the problems of a value, each prefixed with the JSON pointer of the value at fault.
+func (c *exampleChecker) check(schema *spec.Schema, value any) []string {
+	normalized, err := normalizeExampleValue(value)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	var problems []string
+	c.checkValue(schema, normalized, "", 0, &problems)
+	return problems
+}
+
+// normalizeExampleValue returns a value as decoded from JSON, with its numbers as json.Number.
+func normalizeExampleValue(value any) (any, error) {
+	jazon, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(jazon))
+	dec.UseNumber()
+	var normalized any
+	return normalized, dec.Decode(&normalized)
+}
+
+func (c *exampleChecker) checkValue(schema *spec.Schema, value any, pointer string, refs int, problems *[]string) {
+	if schema == nil {
+		return
+	}
+	report := func(format string, args ...any) {
+		*problems = append(*problems, describePointer(pointer)+" "+fmt.Sprintf(format, args...))
+	}
+
+	if name, isDefinition := definitionName(schema.Ref); isDefinition {
+		target, defined := c.definitions[name]
+		if !defined || refs >= maxExampleRefs {
+			return
+		}
+		c.checkValue(&target, value, pointer, refs+1, problems)
+		return
+	}
+	for i := range schema.AllOf {
+		c.checkValue(&schema.AllOf[i], value, pointer, refs, problems)
+	}
+
+	if value == nil {
+		if isNullable(schema) || len(schema.Type) == 0 {
+			return
+		}
+		report("is null, expected %s", expectedTypes(schema.Type))
+		return
+	}
+	if len(schema.Type) > 0 && !schema.Type.Contains("file") && !matchesType(schema.Type, value) {
+		report("is %s, expected %s", describeType(value), expectedTypes(schema.Type))
+		return
+	}
+	if len(schema.Enum) > 0 && !inEnum(schema.Enum, value) {
+		report("is %s, expected one of %s", exampleJSON(value), strings.Join(exampleJSONs(schema.Enum), ", "))
+	}
+
+	switch v := value.(type) {
+	case string:
+		c.checkString(schema, v, report)
+	case json.Number:
+		c.checkNumber(schema, v, report)
+	case []any:
+		c.checkArray(schema, v, pointer, refs, problems, report)
+	case map[string]any:
+		c.checkObject(schema, v, pointer, refs, problems, report)
+	}
+}
+
+func (c *exampleChecker) checkString(schema *spec.Schema, value string, report func(string, ...any)) {
+	length := int64(utf8.RuneCountInString(value))
+	if schema.MinLength != nil && length < *schema.MinLength {
+		report("is shorter than %d characters", *schema.MinLength)
+	}
+	if schema.MaxLength != nil && length > *schema.MaxLength {
+		report("is longer than %d characters", *schema.MaxLength)
+	}
+	if schema.Pattern != "" {
+		if rx, err := regexp.Compile(schema.Pattern); err == nil && !rx.MatchString(value) {
+			report("doesn't match the pattern %s", schema.Pattern)
+		}
+	}
+}
+
+func (c *exampleChecker) checkNumber(schema *spec.Schema, value json.Number, report func(string, ...any)) {
+	number, err := value.Float64()
+	if err != nil {
+		return
+	}
+	switch {
+	case schema.Minimum == nil:
+	case schema.ExclusiveMinimum && number <= *schema.Minimum:
+		report("isn't greater than the exclusive minimum %v", *schema.Minimum)
+	case number < *schema.Minimum:
+		report("is less than the minimum %v", *schema.Minimum)
+	}
+	switch {
+	case schema.Maximum == nil:
+	case schema.ExclusiveMaximum && number >= *schema.Maximum:
+		report("isn't less than the exclusive maximum %v", *schema.Maximum)
+	case number > *schema.Maximum:
+		report("is greater than the maximum %v", *schema.Maximum)
+	}
+	if schema.MultipleOf != nil && *schema.MultipleOf > 0 {
+		if quotient := number / *schema.MultipleOf; math.Abs(quotient-math.Round(quotient)) > 1e-9 {
+			report("isn't a multiple of %v", *schema.MultipleOf)
+		}
+	}
+}
+
+func (c *exampleChecker) checkArray(schema *spec.Schema, value []any, pointer string, refs int, problems *[]string, report func(string, ...any)) {
+	if schema.MinItems != nil && int64(len(value)) < *schema.MinItems {
+		report("has fewer than %d items", *schema.MinItems)
+	}
+	if schema.MaxItems != nil && int64(len(value)) > *schema.MaxItems {
+		report("has more than %d items", *schema.MaxItems)
+	}
+	if schema.UniqueItems {
+		seen := make(map[string]bool, len(value))
+		for _, item := range value {
+			if key := exampleJSON(item); seen[key] {
+				report("has the item %s twice", key)
+				break
+			} else {
+				seen[key] = true
+			}
+		}
+	}
+	if schema.Items == nil {
+		return
+	}
+	for i, item := range value {
+		itemSchema := schema.Items.Schema
+		if len(schema.Items.Schemas) > 0 {
+			if i >= len(schema.Items.Schemas) {
+				break
+			}
+			itemSchema = &schema.Items.Schemas[i]
+		}
+		c.checkValue(itemSchema, item, pointer+"/"+strconv.Itoa(i), refs, problems)
+	}
+}
+
+func (c *exampleChecker) checkObject(schema *spec.Schema, value map[string]any, pointer string, refs int, problems *[]string, report func(string, ...any)) {
+	for _, name := range schema.Required {
+		if _, found := value[name]; !found {
+			report("misses the required property %s", name)
+		}
+	}
+	if schema.MinProperties != nil && int64(len(value)) < *schema.MinProperties {
+		report("has fewer than %d properties", *schema.MinProperties)
+	}
+	if schema.MaxProperties != nil && int64(len(value)) > *schema.MaxProperties {
+		report("has more than %d properties", *schema.MaxProperties)
+	}
+	for _, name := range sortedKeys(value) {
+		property := pointer + "/" + escapePointer(name)
+		if propSchema, defined := schema.Properties[name]; defined {
+			c.checkValue(&propSchema, value[name], property, refs, problems)
+			continue
+		}
+		switch additional := schema.AdditionalProperties; {
+		case additional == nil:
+		case additional.Schema != nil:
+			c.checkValue(additional.Schema, value[name], property, refs, problems)
+		case !additional.Allows:
+			report("has the property %s, which the schema doesn't define", name)
+		}
+	}
+}
+
+// describePointer names the value at a JSON pointer of an example in the problems.
+func describePointer(pointer string) string {
+	if pointer == "" {
+		return "the value"
+	}
+	return pointer
+}
+
+// jsonType is the JSON type of a value normalized by normalizeExampleValue: integer for the numbers without
+// a fraction.
+func jsonType(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if number, err := v.Float64(); err == nil && number == math.Trunc(number) {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func matchesType(types spec.StringOrArray, value any) bool {
+	actual := jsonType(value)
+	return types.Contains(actual) || actual == "integer" && types.Contains("number")
+}
+
+func describeType(value any) string {
+	return withArticle(jsonType(value))
+}
+
+func expectedTypes(types spec.StringOrArray) string {
+	described := make([]string, 0, len(types))
+	for _, name := range types {
+		described = append(described, withArticle(name))
+	}
+	return strings.Join(described, " or ")
+}
+
+func withArticle(typeName string) string {
+	switch typeName {
+	case "null":
+		return typeName
+	case "integer", "object", "array":
+		return "an " + typeName
+	default:
+		return "a " + typeName
+	}
+}
+
+func inEnum(enum []any, value any) bool {
+	key := exampleJSON(value)
+	for _, allowed := range enum {
+		normalized, err := normalizeExampleValue(allowed)
+		if err == nil && exampleJSON(normalized) == key {
+			return true
+		}
+	}
+	return false
+}
+
+func isNullable(schema *spec.Schema) bool {
+	for _, key := range []string{"x-nullable", "x-isnullable"} {
+		if nullable, _ := schema.Extensions[key].(bool); nullable {
+			return true
+		}
+	}
+	return false
+}
+
+// exampleJSON returns the JSON of a value, to report it and to compare it, the keys of its objects sorted.
+func exampleJSON(value any) string {
+	jazon, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(jazon)
+}
+
+func exampleJSONs(values []any) []string {
+	encoded := make([]string, 0, len(values))
+	for _, value := range values {
+		encoded = append(encoded, exampleJSON(value))
+	}
+	return encoded
+}
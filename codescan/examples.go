@@ -0,0 +1,244 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"gopkg.in/yaml.v3"
+)
+
+// exampleExtensions are the extensions holding examples, which StripExamples removes.
+var exampleExtensions = []string{"x-example", "x-examples"}
+
+// StripExamples removes the examples of a spec at every level: the example of the schemas, parameters,
+// items and headers, the examples of the responses, and the x-example and x-examples extensions. Defaults
+// and enums are kept. Stripping a spec again changes nothing.
+func StripExamples(doc *spec.Swagger) {
+	if doc == nil {
+		return
+	}
+	stripExampleExtensions(&doc.VendorExtensible)
+	if doc.Info != nil {
+		stripExampleExtensions(&doc.Info.VendorExtensible)
+	}
+
+	walkSpecSchemas(doc, func(schema *spec.Schema, _ string) {
+		schema.Example = nil
+		stripExampleExtensions(&schema.VendorExtensible)
+	})
+
+	params := func(params []spec.Parameter) {
+		for i := range params {
+			stripParameterExamples(&params[i])
+		}
+	}
+	for name, param := range doc.Parameters {
+		stripParameterExamples(&param)
+		doc.Parameters[name] = param
+	}
+	for name, resp := range doc.Responses {
+		stripResponseExamples(&resp)
+		doc.Responses[name] = resp
+	}
+
+	if doc.Paths == nil {
+		return
+	}
+	stripExampleExtensions(&doc.Paths.VendorExtensible)
+	for pth, pathItem := range doc.Paths.Paths {
+		stripExampleExtensions(&pathItem.VendorExtensible)
+		params(pathItem.Parameters)
+		for _, op := range pathItemOperations(&pathItem) {
+			stripExampleExtensions(&op.VendorExtensible)
+			params(op.Parameters)
+			if op.Responses == nil {
+				continue
+			}
+			if op.Responses.Default != nil {
+				stripResponseExamples(op.Responses.Default)
+			}
+			for code, resp := range op.Responses.StatusCodeResponses {
+				stripResponseExamples(&resp)
+				op.Responses.StatusCodeResponses[code] = resp
+			}
+		}
+		doc.Paths.Paths[pth] = pathItem
+	}
+}
+
+func stripParameterExamples(param *spec.Parameter) {
+	param.Example = nil
+	stripExampleExtensions(&param.VendorExtensible)
+	stripItemsExamples(param.Items)
+}
+
+func stripResponseExamples(resp *spec.Response) {
+	resp.Examples = nil
+	stripExampleExtensions(&resp.VendorExtensible)
+	for name, header := range resp.Headers {
+		header.Example = nil
+		stripExampleExtensions(&header.VendorExtensible)
+		stripItemsExamples(header.Items)
+		resp.Headers[name] = header
+	}
+}
+
+func stripItemsExamples(items *spec.Items) {
+	for ; items != nil; items = items.Items {
+		items.Example = nil
+		stripExampleExtensions(&items.VendorExtensible)
+	}
+}
+
+func stripExampleExtensions(ext *spec.VendorExtensible) {
+	for _, name := range exampleExtensions {
+		delete(ext.Extensions, name)
+	}
+}
+
+// setExampleTag sets the example of a property from the example struct tag of its field, e.g.
+// `example:"42"`, parsed as a value of the type of the property: a number for the integers and numbers, a
+// boolean, or a JSON object or array. The Example: line of the documentation of the field wins over the tag,
+// and, like it, the tag is left out next to a $ref, unless Options.DescWithRef.
+func (s *schemaBuilder) setExampleTag(fld *ast.Field, ps *spec.Schema, name string) error {
+	if ps.Example != nil || fld.Tag == nil {
+		return nil
+	}
+	if ps.Ref.String() != "" && !s.ctx.opts.DescWithRef {
+		return nil
+	}
+	tag, err := strconv.Unquote(fld.Tag.Value)
+	if err != nil {
+		return nil
+	}
+	value, ok := reflect.StructTag(tag).Lookup("example")
+	if !ok {
+		return nil
+	}
+	example, err := parseExample(value, ps)
+	if err != nil {
+		return fmt.Errorf("%v: invalid example of property %s of %s: %w",
+			s.decl.Pkg.Fset.Position(fld.Tag.Pos()), name, s.decl.Obj().Name(), err)
+	}
+	ps.Example = example
+	return nil
+}
+
+// parseExample parses an example as a value of the type of a schema. The value of an untyped schema is
+// JSON, or else a string.
+func parseExample(value string, schema *spec.Schema) (any, error) {
+	var typeName string
+	if len(schema.Type) > 0 {
+		typeName = schema.Type[0]
+	}
+	switch typeName {
+	case "integer":
+		return strconv.ParseInt(value, 10, 64)
+	case "number":
+		return strconv.ParseFloat(value, 64)
+	case "boolean":
+		return strconv.ParseBool(value)
+	case "string", "file":
+		return value, nil
+	case "object":
+		var object map[string]any
+		return object, decodeExample([]byte(value), &object)
+	case "array":
+		var array []any
+		return array, decodeExample([]byte(value), &array)
+	default:
+		var untyped any
+		if err := decodeExample([]byte(value), &untyped); err != nil {
+			if trimmed := strings.TrimSpace(value); strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+				return nil, err
+			}
+			return value, nil
+		}
+		return untyped, nil
+	}
+}
+
+// decodeExample decodes a JSON example, keeping its numbers as they are written.
+func decodeExample(data []byte, value any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(value); err != nil {
+		return err
+	}
+	if dec.More() {
+		return errors.New("invalid JSON: unexpected data after the value")
+	}
+	return nil
+}
+
+// exampleFile reads the file of the swagger:example file: annotation of a model or response, if any, as the
+// example of its whole schema. The path is relative to the directory of the annotated file, and the file is
+// JSON, or YAML for the .yaml and .yml files. The files read are recorded for Stats.ExampleFiles.
+func (a *typeIndex) exampleFile(decl *entityDecl) (any, bool, error) {
+	if decl.Comments == nil {
+		return nil, false, nil
+	}
+	for _, cmt := range decl.Comments.List {
+		i := -1
+		for line := range commentLines(cmt.Text) {
+			i++
+			matches := rxExampleFile.FindStringSubmatch(line)
+			if matches == nil {
+				continue
+			}
+			pos := decl.Pkg.Fset.Position(cmt.Slash)
+			pos.Line += i
+			if matches[1] == "" {
+				return nil, false, fmt.Errorf("%v: swagger:example of %s expects the file of the example, e.g. swagger:example file:testdata/user.json",
+					pos, decl.Obj().Name())
+			}
+			file := matches[1]
+			if !filepath.IsAbs(file) {
+				file = filepath.Join(filepath.Dir(pos.Filename), file)
+			}
+			example, err := readExampleFile(file)
+			if err != nil {
+				return nil, false, fmt.Errorf("%v: invalid example file %s of %s: %w", pos, matches[1], decl.Obj().Name(), err)
+			}
+			a.recordExampleFile(file)
+			return example, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// recordExampleFile records a file read for an example, see Stats.ExampleFiles.
+func (a *typeIndex) recordExampleFile(file string) {
+	if !slices.Contains(a.stats.ExampleFiles, file) {
+		a.stats.ExampleFiles = append(a.stats.ExampleFiles, file)
+		slices.Sort(a.stats.ExampleFiles)
+	}
+}
+
+func readExampleFile(file string) (any, error) {
+	src, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var example any
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(src, &example)
+	default:
+		err = decodeExample(src, &example)
+	}
+	return example, err
+}
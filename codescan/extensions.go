@@ -0,0 +1,205 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/go-openapi/loads/fmts"
+	"github.com/go-openapi/spec"
+)
+
+// blockParser is a multi-line value parser of an indented block, e.g. the YAML of an Extensions: block: the
+// lines indented deeper than the line of its tag belong to the block, whatever tags they hold, and its errors
+// of a line, see blockLineError, are reported at the position of the line.
+type blockParser interface {
+	valueParser
+	startBlock(tagLine string)
+}
+
+// blockLineError is an error of a line of a block, see blockParser: its index in the lines of the block, and
+// its position in the source, when the sectioned parser knows it.
+type blockLineError struct {
+	line int
+	pos  token.Position
+	err  error
+}
+
+func (e *blockLineError) Error() string {
+	if e.pos.IsValid() {
+		return fmt.Sprintf("%v: %v", e.pos, e.err)
+	}
+	return fmt.Sprintf("line %d of the block: %v", e.line+1, e.err)
+}
+
+func (e *blockLineError) Unwrap() error {
+	return e.err
+}
+
+// rxYAMLLine finds the line of an error of the YAML parser, e.g. "yaml: line 3: did not find expected key".
+var rxYAMLLine = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+// yamlLineError returns the error of the YAML parser with the line of the block it reports, if any.
+func yamlLineError(err error) error {
+	message := err.Error()
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) && len(typeErr.Errors) > 0 {
+		message = typeErr.Errors[0]
+	}
+	matches := rxYAMLLine.FindStringSubmatch(message)
+	if matches == nil {
+		if text, found := strings.CutPrefix(message, "yaml: "); found {
+			return &blockLineError{err: errors.New(text)} // the parser leaves out the first line
+		}
+		return err
+	}
+	line, _ := strconv.Atoi(matches[1])
+	return &blockLineError{line: line - 1, err: errors.New(matches[2])}
+}
+
+// rxCommentMarker finds the comment markers of a line: the // of a line comment, or the /* and */ of a
+// general comment, whose lines keep their indentation.
+var rxCommentMarker = regexp.MustCompile(`^(?://|/\*)|\*/$`)
+
+// commentIndent is the width of the indentation of a comment line after its comment marker, the tabs
+// advancing to the next multiple of 8 columns, like gofmt.
+func commentIndent(line string) int {
+	width := 0
+	for _, r := range rxCommentMarker.ReplaceAllString(line, "") {
+		switch r {
+		case ' ':
+			width++
+		case '\t':
+			width += 8 - width%8
+		default:
+			return width
+		}
+	}
+	return width
+}
+
+// blockIndented tells if the first line of a block with content is indented deeper than the line of its tag.
+func blockIndented(tagIndent int, lines []string) bool {
+	for _, line := range lines {
+		if strings.TrimSpace(rxCommentMarker.ReplaceAllString(line, "")) != "" {
+			return commentIndent(line) > tagIndent
+		}
+	}
+	return false
+}
+
+// uncommentBlock removes the comment markers of the lines of a block, and the indentation of its first line
+// with content, which all the lines with content share.
+func uncommentBlock(lines []string) ([]string, error) {
+	uncommented := make([]string, len(lines))
+	indent, indented := "", false
+	for i, line := range lines {
+		line = rxCommentMarker.ReplaceAllString(line, "")
+		content := strings.TrimLeft(line, " \t")
+		if content == "" {
+			continue
+		}
+		if !indented {
+			indent, indented = line[:len(line)-len(content)], true
+		}
+		if !strings.HasPrefix(line, indent) {
+			return nil, &blockLineError{line: i, err: errors.New("indented less than the first line of the block, or with other spaces")}
+		}
+		uncommented[i] = line[len(indent):]
+	}
+	return uncommented, nil
+}
+
+// parseExtensionsBlock parses the lines of an Extensions: block as a YAML mapping of x- extensions, keeping
+// the nested mappings and lists, and the types of the values. A line which isn't YAML, a key which isn't an
+// extension, and an extension defined twice fail, see blockLineError.
+func parseExtensionsBlock(lines []string) (spec.Extensions, error) {
+	uncommented, err := uncommentBlock(lines)
+	if err != nil {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(strings.Join(uncommented, "\n")), &doc); err != nil {
+		return nil, yamlLineError(err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, &blockLineError{line: root.Line - 1, err: errors.New("expected a mapping of x- extensions")}
+	}
+
+	extensions := make(spec.Extensions, len(root.Content)/2) // the keys as written, like encoding/json
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		if !rxAllowedExtensions.MatchString(key.Value) {
+			return nil, &blockLineError{line: key.Line - 1, err: fmt.Errorf("invalid extension name %s, should start with x-", key.Value)}
+		}
+		if _, defined := extensions[key.Value]; defined {
+			return nil, &blockLineError{line: key.Line - 1, err: fmt.Errorf("extension %s is defined twice", key.Value)}
+		}
+		typed, err := decodeYAMLValue(value, json.Unmarshal)
+		if err != nil {
+			return nil, err
+		}
+		extensions[key.Value] = typed
+	}
+	return extensions, nil
+}
+
+// decodeYAMLValue decodes the value of a YAML node of a block as JSON would, e.g. with the keys of its
+// mappings as strings, with a JSON decoder, e.g. decodeExample keeping the numbers as they are written. The
+// errors are a blockLineError.
+func decodeYAMLValue(value *yaml.Node, decode func([]byte, any) error) (any, error) {
+	var decoded any
+	if err := value.Decode(&decoded); err != nil {
+		return nil, yamlLineError(err)
+	}
+	jazon, err := fmts.YAMLToJSON(decoded)
+	if err != nil {
+		return nil, &blockLineError{line: value.Line - 1, err: err}
+	}
+	var typed any
+	if err := decode(jazon, &typed); err != nil {
+		return nil, &blockLineError{line: value.Line - 1, err: err}
+	}
+	return typed, nil
+}
+
+// setExtensionsBlock parses an Extensions: block of swagger:meta, see parseExtensionsBlock, whether indented
+// or not.
+type setExtensionsBlock struct {
+	rx  *regexp.Regexp
+	set func(spec.Extensions)
+}
+
+func newSetExtensionsBlock(rx *regexp.Regexp, setter func(spec.Extensions)) *setExtensionsBlock {
+	return &setExtensionsBlock{rx: rx, set: setter}
+}
+
+func (sb *setExtensionsBlock) Matches(line string) bool {
+	return sb.rx.MatchString(line)
+}
+
+func (sb *setExtensionsBlock) startBlock(string) {}
+
+func (sb *setExtensionsBlock) Parse(lines []string) error {
+	extensions, err := parseExtensionsBlock(lines)
+	if err != nil {
+		return err
+	}
+	if len(extensions) > 0 {
+		sb.set(extensions)
+	}
+	return nil
+}
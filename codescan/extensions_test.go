@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/loads/fmts"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtensionsBlock(t *testing.T) {
+	const pkg = "github.com/3idey/codescan/fixtures/goparsing/extensions"
+	doc, err := Run(&Options{Packages: []string{pkg}})
+	require.NoError(t, err)
+
+	wantRoute := spec.Extensions{
+		"x-rate-limit": map[string]any{"requests": float64(100), "window": 1.5, "burst": true},
+		"x-owners":     []any{map[string]any{"name": "pets", "oncall": false}, "ops"},
+		"x-sunset":     "2027-01-01",
+	}
+	wantMeta := spec.Extensions{
+		"x-api-team":  map[string]any{"name": "pets", "channels": []any{"slack", "email"}},
+		"x-api-level": float64(3),
+	}
+
+	t.Run("should parse the YAML of an indented block", func(t *testing.T) {
+		listPets := doc.Paths.Paths["/pets"].Get
+		assert.Equal(t, wantRoute, listPets.Extensions)
+		assert.Equal(t, "Lists the pets.", listPets.Summary)
+		assert.Contains(t, listPets.Responses.StatusCodeResponses, 200, "the tags after the block")
+		assert.Equal(t, wantMeta, doc.Extensions)
+	})
+
+	t.Run("should keep the key: value lines of a block which isn't indented", func(t *testing.T) {
+		assert.Equal(t, spec.Extensions{"x-internal": "true", "x-level": "2"}, doc.Paths.Paths["/owners"].Get.Extensions)
+	})
+
+	t.Run("should round-trip through JSON", func(t *testing.T) {
+		jazon, err := MarshalJSON(doc, false)
+		require.NoError(t, err)
+		var decoded spec.Swagger
+		require.NoError(t, json.Unmarshal(jazon, &decoded))
+		assert.Equal(t, wantRoute, decoded.Paths.Paths["/pets"].Get.Extensions)
+		assert.Equal(t, wantMeta, decoded.Extensions)
+	})
+
+	t.Run("should round-trip through YAML", func(t *testing.T) {
+		yml, err := MarshalYAML(doc)
+		require.NoError(t, err)
+		yamlDoc, err := fmts.BytesToYAMLDoc(yml)
+		require.NoError(t, err)
+		jazon, err := fmts.YAMLToJSON(yamlDoc)
+		require.NoError(t, err)
+		var decoded spec.Swagger
+		require.NoError(t, json.Unmarshal(jazon, &decoded))
+		assert.Equal(t, wantRoute, decoded.Paths.Paths["/pets"].Get.Extensions)
+		assert.Equal(t, wantMeta, decoded.Extensions)
+	})
+
+	t.Run("should report the errors at their lines", func(t *testing.T) {
+		diagnostics, err := Precheck(&Options{Packages: []string{pkg + "/invalid"}})
+		require.NoError(t, err)
+
+		var problems []string
+		for _, diagnostic := range diagnostics {
+			problems = append(problems, fmt.Sprintf("%s:%d: %s", filepath.Base(diagnostic.Pos.Filename), diagnostic.Pos.Line, diagnostic.Message))
+		}
+		assert.Equal(t, []string{
+			"api.go:7: swagger:meta: invalid extension name api-team, should start with x-",
+			"api.go:17: extension x-rate-limit is defined twice",
+			"api.go:22: mapping values are not allowed in this context",
+			"api.go:28: indented less than the first line of the block, or with other spaces",
+		}, problems)
+	})
+
+	t.Run("should fail the scan at the line of the error", func(t *testing.T) {
+		_, err := Run(&Options{Packages: []string{pkg + "/invalid"}})
+		require.Error(t, err)
+		assert.Regexp(t, `operation \(listPets\): \S*invalid[/\\]api\.go:17:1: extension x-rate-limit is defined twice`, err.Error())
+	})
+}
+
+func TestParseExtensionsBlock(t *testing.T) {
+	for _, tc := range []struct {
+		lines []string
+		want  spec.Extensions
+		err   string
+	}{
+		{lines: []string{"\tx-int: 1", "\tx-list: [a, 2]"}, want: spec.Extensions{"x-int": float64(1), "x-list": []any{"a", float64(2)}}},
+		{lines: []string{"  x-text: |", "    two", "    lines"}, want: spec.Extensions{"x-text": "two\nlines"}},
+		{lines: []string{"", "  x-quoted: 'true'", ""}, want: spec.Extensions{"x-quoted": "true"}},
+		{lines: []string{"  - x-list"}, err: "line 1 of the block: expected a mapping of x- extensions"},
+		{lines: []string{"  x-a: 1", "  b: 2"}, err: "line 2 of the block: invalid extension name b, should start with x-"},
+		{lines: []string{"  x-a: 1", "  x-b: c: d"}, err: "line 2 of the block: mapping values are not allowed in this context"},
+	} {
+		extensions, err := parseExtensionsBlock(tc.lines)
+		if tc.err != "" {
+			require.EqualError(t, err, tc.err)
+			continue
+		}
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, extensions)
+	}
+}
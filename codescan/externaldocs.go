@@ -0,0 +1,37 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// setExternalDocs parses the "ExternalDocs: URL [description]" line of swagger:meta, of a route, of a model
+// or of a swagger:tag, which documents its externalDocs.
+type setExternalDocs struct {
+	set func(*spec.ExternalDocumentation)
+}
+
+func (se *setExternalDocs) Matches(line string) bool {
+	return rxExternalDocs.MatchString(line)
+}
+
+func (se *setExternalDocs) Parse(lines []string) error {
+	if len(lines) == 0 || (len(lines) == 1 && len(lines[0]) == 0) {
+		return nil
+	}
+	matches := rxExternalDocs.FindStringSubmatch(lines[0])
+	if len(matches) < 3 {
+		return nil
+	}
+	if u, err := url.Parse(matches[1]); err != nil || !u.IsAbs() {
+		return fmt.Errorf("ExternalDocs: %q is not an absolute URL", matches[1])
+	}
+	se.set(&spec.ExternalDocumentation{URL: matches[1], Description: strings.TrimSpace(matches[2])})
+	return nil
+}
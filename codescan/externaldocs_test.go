@@ -0,0 +1,34 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExternalDocs(t *testing.T) {
+	doc, err := Run(&Options{Packages: []string{"github.com/3idey/codescan/fixtures/goparsing/markdown"}})
+	require.NoError(t, err)
+
+	assert.Equal(t, &spec.ExternalDocumentation{URL: "https://docs.acme.com", Description: "The guide of the API"}, doc.ExternalDocs)
+	assert.Equal(t, &spec.ExternalDocumentation{URL: "https://docs.acme.com/orders#create"}, doc.Paths.Paths["/orders"].Post.ExternalDocs)
+	assert.Equal(t, &spec.ExternalDocumentation{URL: "https://docs.acme.com/orders#model"}, doc.Definitions["Order"].ExternalDocs)
+	require.Len(t, doc.Tags, 1)
+	assert.Equal(t, &spec.ExternalDocumentation{URL: "https://docs.acme.com/orders", Description: "The orders"}, doc.Tags[0].ExternalDocs)
+	assert.NotContains(t, doc.Paths.Paths["/orders"].Post.Description, "ExternalDocs")
+
+	t.Run("should reject a relative URL", func(t *testing.T) {
+		var docs *spec.ExternalDocumentation
+		parser := &setExternalDocs{func(d *spec.ExternalDocumentation) { docs = d }}
+		require.True(t, parser.Matches("External Docs: see the guide"))
+		err := parser.Parse([]string{"External Docs: see the guide"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `"see" is not an absolute URL`)
+		assert.Nil(t, docs)
+	})
+}
@@ -0,0 +1,140 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"fmt"
+	"go/types"
+	"slices"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// multipleFilesExtension marks the file parameters of a slice of files: swagger 2.0 has no arrays of files,
+// and the OpenAPI 3.0 upgrade makes them arrays of binary strings.
+const multipleFilesExtension = "x-multiple-files"
+
+// fileHeaderType tells if a type is a multipart.FileHeader, or a pointer to one, uploaded as a file parameter,
+// and if it is a slice of them.
+func fileHeaderType(tpe types.Type) (isFile, multiple bool) {
+	tpe = types.Unalias(tpe)
+	if slice, isSlice := tpe.Underlying().(*types.Slice); isSlice {
+		tpe, multiple = types.Unalias(slice.Elem()), true
+	}
+	if ptr, isPtr := tpe.(*types.Pointer); isPtr {
+		tpe = types.Unalias(ptr.Elem())
+	}
+	named, isNamed := tpe.(*types.Named)
+	if !isNamed || named.Obj().Pkg() == nil {
+		return false, false
+	}
+	if named.Obj().Pkg().Path() != "mime/multipart" || named.Obj().Name() != "FileHeader" {
+		return false, false
+	}
+	return true, multiple
+}
+
+// Media types of the forms, which the operations with formData parameters consume.
+const (
+	urlencodedMediaType = "application/x-www-form-urlencoded"
+	multipartMediaType  = "multipart/form-data"
+)
+
+// applyFormConsumes checks the final form parameters of the operations, once merged with the input spec, which
+// can't be mixed with a body parameter, and makes the operations with form parameters consume form media types.
+// The form parameters take precedence over the consumes an operation inherits from the spec: these are narrowed
+// to their form media types, multipart/form-data or application/x-www-form-urlencoded when there is none, and
+// the media types an operation declares which can't carry its form are dropped, with a DiagnosticFormConsumes
+// diagnostic. The files are only uploaded by the multipart media types.
+func (s *specBuilder) applyFormConsumes() error {
+	if s.input.Paths == nil {
+		return nil
+	}
+	for _, pp := range slices.Concat(s.ctx.app.Routes, s.ctx.app.Operations) {
+		op := s.operationAt(pp.Path, pp.Method)
+		if op == nil {
+			continue
+		}
+
+		var bodies, forms []string
+		hasFile := false
+		pathItem := s.input.Paths.Paths[pp.Path]
+		for _, param := range slices.Concat(pathItem.Parameters, op.Parameters) {
+			if name, isRef := strings.CutPrefix(param.Ref.String(), parametersPrefix); isRef {
+				param = s.input.Parameters[name]
+			}
+			switch param.In {
+			case "body":
+				bodies = append(bodies, param.Name)
+			case "formData":
+				forms = append(forms, param.Name)
+				hasFile = hasFile || param.Type == "file"
+			}
+		}
+		if len(bodies) > 0 && len(forms) > 0 {
+			return fmt.Errorf("%v: the operation %s of %s %s has both the body parameter %s and the formData parameters %s, which swagger 2.0 forbids",
+				pp.Pos, op.ID, pp.Method, pp.Path, strings.Join(bodies, ", "), strings.Join(forms, ", "))
+		}
+		if len(forms) > 0 {
+			s.applyOperationFormConsumes(pp, op, hasFile)
+		}
+	}
+	return nil
+}
+
+// applyOperationFormConsumes makes an operation with form parameters consume form media types, see
+// applyFormConsumes.
+func (s *specBuilder) applyOperationFormConsumes(pp parsedPathContent, op *spec.Operation, hasFile bool) {
+	carriesForm, fallback := isFormMediaType, urlencodedMediaType
+	if hasFile {
+		carriesForm, fallback = isMultipartMediaType, multipartMediaType
+	}
+	declared := len(op.Consumes) > 0
+	consumes := op.Consumes
+	if !declared {
+		consumes = s.input.Consumes
+	}
+
+	var kept, dropped []string
+	for _, mediaType := range consumes {
+		if carriesForm(mediaType) {
+			kept = append(kept, mediaType)
+		} else {
+			dropped = append(dropped, mediaType)
+		}
+	}
+	if len(dropped) == 0 && len(kept) > 0 {
+		return
+	}
+	if len(kept) == 0 {
+		kept = []string{fallback}
+	}
+	op.Consumes = kept
+	if declared {
+		s.ctx.app.diagnose(Diagnostic{
+			Pos:  pp.Pos,
+			Code: DiagnosticFormConsumes,
+			Message: fmt.Sprintf("the operation %s of %s %s has formData parameters, which %s can't carry: it consumes %s",
+				op.ID, pp.Method, pp.Path, strings.Join(dropped, ", "), strings.Join(kept, ", ")),
+		})
+	}
+}
+
+// isFormMediaType tells if a media type carries the formData parameters, e.g. multipart/form-data.
+func isFormMediaType(mediaType string) bool {
+	return strings.EqualFold(baseMediaType(mediaType), urlencodedMediaType) || isMultipartMediaType(mediaType)
+}
+
+// isMultipartMediaType tells if a media type is a multipart one, the only ones carrying the files.
+func isMultipartMediaType(mediaType string) bool {
+	return strings.HasPrefix(strings.ToLower(baseMediaType(mediaType)), "multipart/")
+}
+
+// baseMediaType returns a media type without its parameters, e.g. multipart/form-data of
+// "multipart/form-data; boundary=x".
+func baseMediaType(mediaType string) string {
+	base, _, _ := strings.Cut(mediaType, ";")
+	return strings.TrimSpace(base)
+}
@@ -0,0 +1,147 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileUploads(t *testing.T) {
+	const pkg = "github.com/3idey/codescan/fixtures/goparsing/uploads"
+	doc, err := Run(&Options{Packages: []string{pkg}})
+	require.NoError(t, err)
+
+	t.Run("should upload the multipart.FileHeader fields as files", func(t *testing.T) {
+		op := doc.Paths.Paths["/avatars"].Post
+		require.NotNil(t, op)
+		assert.Equal(t, []string{"multipart/form-data"}, op.Consumes)
+		require.Len(t, op.Parameters, 3)
+
+		file := op.Parameters[0]
+		assert.Equal(t, "file", file.Name)
+		assert.Equal(t, "formData", file.In, "the location of a file defaults to the form data")
+		assert.Equal(t, "file", file.Type)
+		assert.True(t, file.Required)
+		assert.Equal(t, "the avatar", file.Description)
+		assert.NotContains(t, file.Extensions, multipleFilesExtension)
+	})
+
+	t.Run("should mix the form fields with the files", func(t *testing.T) {
+		params := doc.Paths.Paths["/avatars"].Post.Parameters
+		assert.Equal(t, "formData", params[1].In)
+		assert.Equal(t, "string", params[1].Type)
+		assert.Equal(t, int64(140), *params[1].MaxLength)
+		assert.Equal(t, "integer", params[2].Type)
+		assert.InDelta(t, 16, *params[2].Minimum, 0)
+	})
+
+	t.Run("should mark the slices of files, and keep the annotated consumes", func(t *testing.T) {
+		op := doc.Paths.Paths["/documents"].Post
+		require.NotNil(t, op)
+		assert.Equal(t, []string{"multipart/mixed"}, op.Consumes)
+		require.Len(t, op.Parameters, 1)
+		assert.Equal(t, "file", op.Parameters[0].Type)
+		assert.Nil(t, op.Parameters[0].Items)
+		assert.Equal(t, true, op.Parameters[0].Extensions[multipleFilesExtension])
+	})
+
+	t.Run("should make the forms without files consume application/x-www-form-urlencoded", func(t *testing.T) {
+		assert.Equal(t, []string{"application/x-www-form-urlencoded"}, doc.Paths.Paths["/profiles"].Post.Consumes)
+	})
+
+	t.Run("should upgrade the slices of files to arrays of binary strings", func(t *testing.T) {
+		upgraded, err := UpgradeSwagger(doc)
+		require.NoError(t, err)
+		op := asObject(asObject(asObject(upgraded["paths"])["/documents"])["post"])
+		content := asObject(asObject(op["requestBody"])["content"])
+		schema := asObject(asObject(content["multipart/form-data"])["schema"])
+		assert.Equal(t, map[string]any{
+			"type":        "array",
+			"items":       map[string]any{"type": "string", "format": "binary"},
+			"description": "the documents",
+			"x-go-name":   "Documents",
+		}, asObject(schema["properties"])["documents"])
+	})
+
+	t.Run("should fail on a body mixed with form data", func(t *testing.T) {
+		_, err := Run(&Options{Packages: []string{pkg + "/bodyform"}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "the operation uploadAvatar of POST /avatars has both the body parameter metadata and the formData parameters file")
+	})
+
+	t.Run("should fail on a file out of the form data", func(t *testing.T) {
+		_, err := Run(&Options{Packages: []string{pkg + "/badin"}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "the file parameter File of uploadAvatarParams is in query, while files are uploaded as formData")
+	})
+
+	t.Run("should keep the consumes of a spec consuming multipart/form-data", func(t *testing.T) {
+		input := &spec.Swagger{SwaggerProps: spec.SwaggerProps{Consumes: []string{"multipart/form-data"}}}
+		doc, err := Run(&Options{Packages: []string{pkg}, InputSpec: input})
+		require.NoError(t, err)
+		assert.Empty(t, doc.Paths.Paths["/avatars"].Post.Consumes)
+		assert.Empty(t, doc.Paths.Paths["/profiles"].Post.Consumes, "multipart/form-data carries the forms without files too")
+	})
+}
+
+func TestFormConsumes(t *testing.T) {
+	const pkg = "github.com/3idey/codescan/fixtures/goparsing/uploads/login"
+	var diagnostics []Diagnostic
+	doc, err := Run(&Options{Packages: []string{pkg}, Logger: func(diagnostic Diagnostic) { diagnostics = append(diagnostics, diagnostic) }})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"application/json"}, doc.Consumes)
+
+	t.Run("should drop the inherited JSON of the forms", func(t *testing.T) {
+		assert.Equal(t, []string{"application/x-www-form-urlencoded"}, doc.Paths.Paths["/oauth/token"].Post.Consumes)
+		assert.Equal(t, []string{"multipart/form-data"}, doc.Paths.Paths["/avatars"].Post.Consumes, "the files are uploaded as multipart")
+		assert.Empty(t, doc.Paths.Paths["/users"].Post.Consumes, "the body parameters inherit the consumes")
+	})
+
+	t.Run("should drop the declared media types which can't carry the form", func(t *testing.T) {
+		assert.Equal(t, []string{"application/x-www-form-urlencoded"}, doc.Paths.Paths["/sessions"].Post.Consumes)
+		var messages []string
+		for _, diagnostic := range diagnostics {
+			if diagnostic.Code == DiagnosticFormConsumes {
+				messages = append(messages, diagnostic.Message)
+			}
+		}
+		assert.ElementsMatch(t, []string{
+			"the operation createSession of POST /sessions has formData parameters, which application/json can't carry: it consumes application/x-www-form-urlencoded",
+			"the operation uploadAvatar of POST /avatars has formData parameters, which application/x-www-form-urlencoded can't carry: it consumes multipart/form-data",
+		}, messages)
+	})
+
+	t.Run("should narrow the consumes joined with the input spec", func(t *testing.T) {
+		input := &spec.Swagger{SwaggerProps: spec.SwaggerProps{Consumes: []string{"application/json", "multipart/form-data"}}}
+		doc, err := Run(&Options{Packages: []string{pkg}, InputSpec: input, MergeStrategy: MergeScanWins})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"application/json", "multipart/form-data"}, doc.Consumes)
+		assert.Equal(t, []string{"multipart/form-data"}, doc.Paths.Paths["/oauth/token"].Post.Consumes)
+		assert.Empty(t, doc.Paths.Paths["/users"].Post.Consumes)
+	})
+
+	t.Run("should check the operations kept from the input spec", func(t *testing.T) {
+		input := &spec.Swagger{SwaggerProps: spec.SwaggerProps{Paths: &spec.Paths{Paths: map[string]spec.PathItem{
+			"/oauth/token": {PathItemProps: spec.PathItemProps{Post: &spec.Operation{OperationProps: spec.OperationProps{
+				ID:       "issueToken",
+				Consumes: []string{"application/json"},
+				Parameters: []spec.Parameter{
+					*spec.FormDataParam("grant_type").Typed("string", ""),
+					*spec.BodyParam("token", spec.StringProperty()),
+				},
+			}}}},
+		}}}}
+		_, err := Run(&Options{Packages: []string{pkg}, InputSpec: input, MergeStrategy: MergeInputWins})
+		require.ErrorContains(t, err, "the operation issueToken of POST /oauth/token has both the body parameter token and the formData parameters grant_type")
+
+		input.Paths.Paths["/oauth/token"].Post.Parameters = input.Paths.Paths["/oauth/token"].Post.Parameters[:1]
+		doc, err := Run(&Options{Packages: []string{pkg}, InputSpec: input, MergeStrategy: MergeInputWins})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"application/x-www-form-urlencoded"}, doc.Paths.Paths["/oauth/token"].Post.Consumes)
+	})
+}
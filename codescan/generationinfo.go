@@ -0,0 +1,351 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"gopkg.in/yaml.v3"
+)
+
+// GenerationInfoExtension is the extension of the root of a spec recording the options generating it, see
+// Options.EmitGenerationInfo and GenerationInfo.
+const GenerationInfoExtension = "x-codescan-options"
+
+// redactedValue replaces the secrets of the options recorded by a GenerationInfo.
+const redactedValue = "<redacted>"
+
+// fileOptions are the options read from files, which a GenerationInfo doesn't record.
+var fileOptions = []string{"InputSpec", "Meta", "UseDefinitionIndex", "DescriptionCatalog", "CodeSampleTemplates"}
+
+// shapingOptions are the options an options file can't set which shape the spec, omitted when set.
+var shapingOptions = []string{"DefinitionNamer", "Loader", "Overlay"}
+
+// pathOptions are the options naming a path of the machine of the scan, which a GenerationInfo only records
+// when relative.
+var pathOptions = []string{"WorkDir", "CacheDir", "PackagesDriver"}
+
+// secretEnvNames are the parts of the names of the environment variables whose values a GenerationInfo
+// redacts, e.g. GITHUB_TOKEN.
+var secretEnvNames = []string{"TOKEN", "SECRET", "PASSWORD", "PASSWD", "CREDENTIAL", "KEY", "AUTH"}
+
+// GenerationInfo is the x-codescan-options extension of the specs generated with Options.EmitGenerationInfo,
+// telling how to generate them again, e.g. with the replay command:
+//
+//	x-codescan-options:
+//	  codescan: v1.4.2
+//	  go: go1.25.1
+//	  options:
+//	    packages: [./...]
+//	    scan_models: true
+//	  omitted: [input_spec]
+//
+// The options are those set, with the keys of an options file (see LoadOptionsFromFile), so that two scans
+// with the same options record the same ones. The options read from files, e.g. input_spec, and the absolute
+// paths, e.g. work_dir, are omitted, and the secrets redacted: the values of the environment variables named
+// like secrets, e.g. GITHUB_TOKEN, and the values matching the secret patterns, see Options.SecretPatterns.
+type GenerationInfo struct {
+	Codescan string         `json:"codescan"`          // the version of the module of codescan, (devel) for a development build
+	Go       string         `json:"go"`                // the version of Go building codescan
+	Options  map[string]any `json:"options"`           // the options set, by key of an options file
+	Omitted  []string       `json:"omitted,omitempty"` // the keys of the options set but not recorded, sorted
+}
+
+// NewGenerationInfo records the options of a scan.
+func NewGenerationInfo(opts *Options) (*GenerationInfo, error) {
+	patterns, err := compileSecretPatterns(opts.SecretPatterns)
+	if err != nil {
+		return nil, err
+	}
+	info := &GenerationInfo{
+		Codescan: cmp.Or(moduleVersion(), "(devel)"),
+		Go:       runtime.Version(),
+		Options:  make(map[string]any),
+	}
+
+	value := reflect.ValueOf(opts).Elem()
+	for i := range value.NumField() {
+		name := value.Type().Field(i).Name
+		field := value.Field(i)
+		if field.IsZero() {
+			continue
+		}
+		key, settable := OptionsFileKey(name)
+		if !settable {
+			if slices.Contains(shapingOptions, name) {
+				info.Omitted = append(info.Omitted, optionKey(name))
+			}
+			continue
+		}
+
+		var (
+			recorded   any
+			recordable = true
+		)
+		switch {
+		case slices.Contains(fileOptions, name), slices.Contains(pathOptions, name) && filepath.IsAbs(field.String()):
+			recordable = false
+		case name == "TypeMappings":
+			recorded, recordable = typeMappingTexts(opts.TypeMappings)
+		case name == "SecurityDefaults":
+			recorded, recordable = securityRequirementTexts(opts.SecurityDefaults)
+		case name == "Env":
+			recorded = redactedEnv(opts.Env)
+		default:
+			recorded = optionValue(field)
+		}
+		if !recordable {
+			info.Omitted = append(info.Omitted, key)
+			continue
+		}
+		info.Options[key] = redactSecrets(recorded, patterns)
+	}
+	slices.Sort(info.Omitted)
+
+	return info, nil
+}
+
+// ReadGenerationInfo reads the GenerationInfo of a spec, in JSON or YAML, swagger 2.0 or OpenAPI 3.0. It
+// returns nil without an x-codescan-options extension.
+func ReadGenerationInfo(data []byte) (*GenerationInfo, error) {
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	extension, found := doc[GenerationInfoExtension]
+	if !found {
+		return nil, nil
+	}
+	jazon, err := json.Marshal(extension)
+	if err != nil {
+		return nil, err
+	}
+	info := new(GenerationInfo)
+	if err := decodeExample(jazon, info); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", GenerationInfoExtension, err)
+	}
+	return info, nil
+}
+
+// ToOptions returns the options recorded, read like an options file. The environment variables whose values
+// are redacted take their values from the environment, while the other redacted options fail.
+func (g *GenerationInfo) ToOptions() (*Options, error) {
+	recorded := make(map[string]any, len(g.Options))
+	var redacted []string
+	for _, key := range sortedKeys(g.Options) {
+		value := plainOptionValue(g.Options[key])
+		if key == optionKey("Env") {
+			value = unredactedEnv(value, os.Getenv)
+		}
+		walkStrings(value, func(text string) {
+			if strings.Contains(text, redactedValue) && !slices.Contains(redacted, key) {
+				redacted = append(redacted, key)
+			}
+		})
+		recorded[key] = value
+	}
+	if len(redacted) > 0 {
+		return nil, fmt.Errorf("the options %s are redacted, and can't be replayed", strings.Join(redacted, ", "))
+	}
+
+	data, err := yaml.Marshal(recorded)
+	if err != nil {
+		return nil, err
+	}
+	return parseOptions(data, GenerationInfoExtension, ".")
+}
+
+// extension returns the generation info as the generic JSON value of the extension of a spec.
+func (g *GenerationInfo) extension() (any, error) {
+	jazon, err := json.Marshal(g)
+	if err != nil {
+		return nil, err
+	}
+	var value map[string]any
+	if err := decodeExample(jazon, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// applyGenerationInfo records the options in the spec with Options.EmitGenerationInfo, unless
+// Options.Reproducible, which drops the generation info of the input spec too.
+func applyGenerationInfo(doc *spec.Swagger, opts *Options) error {
+	if opts.Reproducible || opts.EmitGenerationInfo {
+		StripGenerationInfo(doc)
+	}
+	if opts.Reproducible || !opts.EmitGenerationInfo {
+		return nil
+	}
+	info, err := NewGenerationInfo(opts)
+	if err != nil {
+		return fmt.Errorf("can't record the options: %w", err)
+	}
+	extension, err := info.extension()
+	if err != nil {
+		return fmt.Errorf("can't record the options: %w", err)
+	}
+	doc.AddExtension(GenerationInfoExtension, extension)
+	return nil
+}
+
+// StripGenerationInfo removes the x-codescan-options extension of a spec, see Options.Reproducible.
+func StripGenerationInfo(doc *spec.Swagger) {
+	delete(doc.Extensions, GenerationInfoExtension)
+	if len(doc.Extensions) == 0 {
+		doc.Extensions = nil
+	}
+}
+
+// optionValue returns the generic value of an option, like that of an options file: the structs are mappings
+// of the keys of their fields set.
+func optionValue(value reflect.Value) any {
+	switch value.Kind() {
+	case reflect.Struct:
+		fields := make(map[string]any, value.NumField())
+		for i := range value.NumField() {
+			if field := value.Type().Field(i); field.IsExported() && !value.Field(i).IsZero() {
+				fields[optionKey(field.Name)] = optionValue(value.Field(i))
+			}
+		}
+		return fields
+	case reflect.Slice:
+		items := make([]any, value.Len())
+		for i := range value.Len() {
+			items[i] = optionValue(value.Index(i))
+		}
+		return items
+	case reflect.Map:
+		entries := make(map[string]any, value.Len())
+		for iter := value.MapRange(); iter.Next(); {
+			entries[fmt.Sprint(iter.Key().Interface())] = optionValue(iter.Value())
+		}
+		return entries
+	default:
+		return value.Interface()
+	}
+}
+
+// plainOptionValue returns a value read from JSON with the numbers of YAML, for parseOptions.
+func plainOptionValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		plain := make(map[string]any, len(v))
+		for key, item := range v {
+			plain[key] = plainOptionValue(item)
+		}
+		return plain
+	case []any:
+		plain := make([]any, len(v))
+		for i, item := range v {
+			plain[i] = plainOptionValue(item)
+		}
+		return plain
+	case json.Number:
+		if integer, err := v.Int64(); err == nil {
+			return integer
+		}
+		float, _ := v.Float64()
+		return float
+	default:
+		return value
+	}
+}
+
+// typeMappingTexts writes the type mappings like ParseTypeMapping reads them, false when a schema is more
+// than a type with a format.
+func typeMappingTexts(mappings map[string]spec.Schema) (map[string]any, bool) {
+	texts := make(map[string]any, len(mappings))
+	for goType, schema := range mappings {
+		text := strings.Join(schema.Type, "")
+		if schema.Format != "" {
+			text += ":" + schema.Format
+		}
+		if mapping, err := ParseTypeMapping(text); err != nil || !reflect.DeepEqual(mapping, schema) {
+			return nil, false
+		}
+		texts[goType] = text
+	}
+	return texts, true
+}
+
+// securityRequirementTexts writes the security requirements like ParseSecurityRequirement reads them, false
+// when a requirement has several schemes.
+func securityRequirementTexts(requirements []map[string][]string) ([]any, bool) {
+	texts := make([]any, 0, len(requirements))
+	for _, requirement := range requirements {
+		if len(requirement) != 1 {
+			return nil, false
+		}
+		for name, scopes := range requirement {
+			text := name
+			if len(scopes) > 0 {
+				text += ": " + strings.Join(scopes, ", ")
+			}
+			texts = append(texts, text)
+		}
+	}
+	return texts, true
+}
+
+// redactedEnv returns the environment variables of Options.Env, with the values of those named like secrets
+// redacted.
+func redactedEnv(env []string) []any {
+	recorded := make([]any, len(env))
+	for i, entry := range env {
+		name, _, _ := strings.Cut(entry, "=")
+		if slices.ContainsFunc(secretEnvNames, func(part string) bool { return strings.Contains(strings.ToUpper(name), part) }) {
+			entry = name + "=" + redactedValue
+		}
+		recorded[i] = entry
+	}
+	return recorded
+}
+
+// unredactedEnv sets the values of the redacted environment variables of the value of Options.Env with
+// getenv.
+func unredactedEnv(value any, getenv func(string) string) any {
+	entries, _ := value.([]any)
+	unredacted := make([]any, len(entries))
+	for i, entry := range entries {
+		text, _ := entry.(string)
+		if name, found := strings.CutSuffix(text, "="+redactedValue); found {
+			entry = name + "=" + getenv(name)
+		}
+		unredacted[i] = entry
+	}
+	return unredacted
+}
+
+// redactSecrets replaces the parts of the strings of a value matching the secret patterns.
+func redactSecrets(value any, patterns []secretPattern) any {
+	switch v := value.(type) {
+	case string:
+		for _, pattern := range patterns {
+			v = pattern.rx.ReplaceAllLiteralString(v, redactedValue)
+		}
+		return v
+	case map[string]any:
+		for key, item := range v {
+			v[key] = redactSecrets(item, patterns)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = redactSecrets(item, patterns)
+		}
+		return v
+	default:
+		return value
+	}
+}
@@ -0,0 +1,177 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerationInfo(t *testing.T) {
+	const pkg = "github.com/3idey/codescan/fixtures/goparsing/hoisting"
+
+	// recordOptions records the options in a spec, and reads them back from its JSON.
+	recordOptions := func(t *testing.T, opts *Options) *GenerationInfo {
+		t.Helper()
+		opts.EmitGenerationInfo = true
+		doc := new(spec.Swagger)
+		require.NoError(t, applyGenerationInfo(doc, opts))
+		data, err := json.Marshal(doc)
+		require.NoError(t, err)
+		info, err := ReadGenerationInfo(data)
+		require.NoError(t, err)
+		require.NotNil(t, info)
+		return info
+	}
+
+	t.Run("should record the options set, by key", func(t *testing.T) {
+		amount, err := ParseTypeMapping("string:decimal")
+		require.NoError(t, err)
+		requirement, err := ParseSecurityRequirement("oauth2: read:users, write:users")
+		require.NoError(t, err)
+		opts := &Options{
+			Packages:         []string{"./api/..."},
+			ScanModels:       true,
+			MaxSchemaDepth:   10,
+			ForceIncludeDirs: []ForceIncludeDir{{Dir: "gen/http", ExcludeTags: []string{"internal"}}},
+			Rules:            []Rule{{Match: RuleMatchOperation, Require: "responses.204 exists", Severity: SeverityWarning}},
+			TypeMappings:     map[string]spec.Schema{"github.com/acme/money.Amount": amount},
+			SecurityDefaults: []map[string][]string{requirement},
+			WorkDir:          "services/api",
+		}
+		info := recordOptions(t, opts)
+		assert.Equal(t, []string{"emit_generation_info", "force_include_dirs", "max_schema_depth", "packages", "rules",
+			"scan_models", "security_defaults", "type_mappings", "work_dir"}, sortedKeys(info.Options))
+		assert.Equal(t, json.Number("10"), info.Options["max_schema_depth"])
+		assert.Equal(t, map[string]any{"github.com/acme/money.Amount": "string:decimal"}, info.Options["type_mappings"])
+		assert.Equal(t, []any{"oauth2: read:users, write:users"}, info.Options["security_defaults"])
+		assert.Empty(t, info.Omitted)
+		assert.NotEmpty(t, info.Codescan)
+		assert.NotEmpty(t, info.Go)
+
+		replayed, err := info.ToOptions()
+		require.NoError(t, err)
+		assert.Equal(t, opts, replayed)
+	})
+
+	t.Run("should omit the options read from files and the absolute paths", func(t *testing.T) {
+		info := recordOptions(t, &Options{
+			Packages:    []string{"./..."},
+			InputSpec:   new(spec.Swagger),
+			Meta:        new(spec.Swagger),
+			WorkDir:     "/home/ada/api",
+			CacheDir:    ".cache/codescan",
+			Overlay:     map[string][]byte{"/home/ada/api/gen.go": []byte("package api")},
+			Concurrency: 1,
+		})
+		assert.Equal(t, []string{"input_spec", "meta", "overlay", "work_dir"}, info.Omitted)
+		assert.Equal(t, []string{"cache_dir", "concurrency", "emit_generation_info", "packages"}, sortedKeys(info.Options))
+	})
+
+	t.Run("should redact the secrets", func(t *testing.T) {
+		info := recordOptions(t, &Options{
+			Packages:          []string{"./..."},
+			Env:               []string{"GOFLAGS=-mod=vendor", "GITHUB_TOKEN=ghp_0123", "NPM_AUTH=xyz"},
+			DeprecationNotice: "Deprecated: see password=hunter22",
+		})
+		assert.Equal(t, []any{"GOFLAGS=-mod=vendor", "GITHUB_TOKEN=<redacted>", "NPM_AUTH=<redacted>"}, info.Options["env"])
+		assert.Equal(t, "Deprecated: see <redacted>", info.Options["deprecation_notice"])
+
+		_, err := info.ToOptions()
+		require.EqualError(t, err, "the options deprecation_notice are redacted, and can't be replayed")
+
+		delete(info.Options, "deprecation_notice")
+		t.Setenv("GITHUB_TOKEN", "ghp_4567")
+		t.Setenv("NPM_AUTH", "")
+		replayed, err := info.ToOptions()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"GOFLAGS=-mod=vendor", "GITHUB_TOKEN=ghp_4567", "NPM_AUTH="}, replayed.Env)
+	})
+
+	t.Run("should read the generation info of a YAML spec", func(t *testing.T) {
+		info, err := ReadGenerationInfo([]byte(`
+openapi: 3.0.3
+x-codescan-options:
+  codescan: v1.4.2
+  go: go1.25.1
+  options:
+    packages: [./...]
+    max_enum_values: 20
+  omitted: [meta]
+`))
+		require.NoError(t, err)
+		assert.Equal(t, "v1.4.2", info.Codescan)
+		assert.Equal(t, []string{"meta"}, info.Omitted)
+		opts, err := info.ToOptions()
+		require.NoError(t, err)
+		assert.Equal(t, &Options{Packages: []string{"./..."}, MaxEnumValues: 20}, opts)
+
+		info, err = ReadGenerationInfo([]byte(`{"swagger": "2.0"}`))
+		require.NoError(t, err)
+		assert.Nil(t, info)
+	})
+
+	t.Run("should fail on the unknown options", func(t *testing.T) {
+		info := &GenerationInfo{Options: map[string]any{"scan_model": true}}
+		_, err := info.ToOptions()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid x-codescan-options")
+	})
+
+	t.Run("should emit the generation info with the spec", func(t *testing.T) {
+		doc, err := Run(&Options{Packages: []string{pkg}, EmitGenerationInfo: true})
+		require.NoError(t, err)
+		extension := asObject(doc.Extensions[GenerationInfoExtension])
+		assert.Equal(t, map[string]any{"emit_generation_info": true, "packages": []any{pkg}}, extension["options"])
+
+		doc3, err := Run3(&Options{Packages: []string{pkg}, EmitGenerationInfo: true})
+		require.NoError(t, err)
+		assert.Contains(t, doc3, GenerationInfoExtension)
+
+		plain, err := Run(&Options{Packages: []string{pkg}})
+		require.NoError(t, err)
+		assert.NotContains(t, plain.Extensions, GenerationInfoExtension)
+	})
+
+	t.Run("should leave the generation info out of a reproducible spec", func(t *testing.T) {
+		input := new(spec.Swagger)
+		input.AddExtension(GenerationInfoExtension, map[string]any{"codescan": "v1.0.0"})
+
+		doc, err := Run(&Options{Packages: []string{pkg}, InputSpec: input, EmitGenerationInfo: true, Reproducible: true})
+		require.NoError(t, err)
+		assert.NotContains(t, doc.Extensions, GenerationInfoExtension)
+
+		input = new(spec.Swagger)
+		input.AddExtension(GenerationInfoExtension, map[string]any{"codescan": "v1.0.0"})
+		doc, err = Run(&Options{Packages: []string{pkg}, InputSpec: input, EmitGenerationInfo: true})
+		require.NoError(t, err)
+		extension := asObject(doc.Extensions[GenerationInfoExtension])
+		assert.NotEqual(t, "v1.0.0", extension["codescan"], "the generation info of the scan replaces that of the input spec")
+		assert.Equal(t, []any{"input_spec"}, extension["omitted"])
+	})
+
+	t.Run("should reproduce the spec from its generation info", func(t *testing.T) {
+		doc, err := Run(&Options{Packages: []string{pkg}, EmitGenerationInfo: true, HoistParameters: 2, SortParameters: true})
+		require.NoError(t, err)
+		data, err := MarshalYAML(doc)
+		require.NoError(t, err)
+
+		info, err := ReadGenerationInfo(data)
+		require.NoError(t, err)
+		opts, err := info.ToOptions()
+		require.NoError(t, err)
+		replayed, err := Run(opts)
+		require.NoError(t, err)
+
+		want, err := json.Marshal(doc)
+		require.NoError(t, err)
+		got, err := json.Marshal(replayed)
+		require.NoError(t, err)
+		assert.JSONEq(t, string(want), string(got))
+	})
+}
@@ -0,0 +1,198 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"errors"
+	"fmt"
+	"go/types"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultGenericNameTemplate names the definitions of the instantiations of the generic struct types, e.g.
+// PageOfUser for Page[User] and MapOfStringAndUser for Map[string, User], see Options.GenericNameTemplate.
+const DefaultGenericNameTemplate = `{{ .TypeName }}Of{{ join .TypeArgs "And" }}`
+
+// GenericNameData is the data of Options.GenericNameTemplate, e.g. {{.TypeName}}Of{{join .TypeArgs "And"}},
+// where join is strings.Join.
+type GenericNameData struct {
+	TypeName string   // definition name of the generic type, e.g. Page
+	TypeArgs []string // names of the type arguments, e.g. User for Page[*User], PageOfUser for APIResponse[Page[User]]
+}
+
+// errOpenTypeArg tells an instantiation by a type parameter, e.g. Page[T] in a generic type, which is expanded.
+var errOpenTypeArg = errors.New("the type argument is a type parameter")
+
+// genericNamer names the definitions of the instantiations of the generic struct types.
+type genericNamer struct {
+	tmpl *template.Template
+}
+
+// newGenericNamer parses a generic name template, DefaultGenericNameTemplate without template, checking it
+// against a sample instantiation.
+func newGenericNamer(source string) (*genericNamer, error) {
+	if source == "" {
+		source = DefaultGenericNameTemplate
+	}
+	tmpl, err := template.New("generic name").Funcs(template.FuncMap{"join": strings.Join}).Parse(source)
+	if err != nil {
+		return nil, err
+	}
+	namer := &genericNamer{tmpl: tmpl}
+
+	sample := GenericNameData{TypeName: "Page", TypeArgs: []string{"User"}}
+	name, err := namer.execute(sample)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkDefinitionName(name); err != nil {
+		return nil, fmt.Errorf("the template names the sample type Page[User] %q: %w", name, err)
+	}
+	if !strings.Contains(name, sample.TypeName) || !strings.Contains(name, sample.TypeArgs[0]) {
+		return nil, fmt.Errorf("the template names the sample type Page[User] %q, without its type name and type arguments", name)
+	}
+	return namer, nil
+}
+
+func (n *genericNamer) execute(data GenericNameData) (string, error) {
+	var name strings.Builder
+	if err := n.tmpl.Execute(&name, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(name.String()), nil
+}
+
+func withGenericNamer(namer *genericNamer) typeIndexOption {
+	return func(a *typeIndex) {
+		a.genericNamer = namer
+		a.instances = make(map[string]*entityDecl)
+	}
+}
+
+// instanceDecl returns the declaration of an instantiation of a generic struct type, e.g. Page[User], which
+// is documented as a definition of its own, with the type arguments substituted. The instantiations of the
+// other generic types are expanded.
+func (s *scanCtx) instanceDecl(named *types.Named) (*entityDecl, error) {
+	if named.TypeArgs().Len() == 0 {
+		return nil, nil
+	}
+	if _, isStruct := named.Underlying().(*types.Struct); !isStruct {
+		return nil, nil
+	}
+	key := types.TypeString(named, nil)
+	unlock := s.app.lock()
+	instance, known := s.app.instances[key]
+	unlock()
+	if known {
+		return instance, nil
+	}
+
+	origin := named.Origin().Obj()
+	if origin.Pkg() == nil {
+		return nil, nil
+	}
+	generic, found := s.FindDecl(origin.Pkg().Path(), origin.Name())
+	if !found {
+		return nil, nil
+	}
+
+	typeName, _ := generic.Names()
+	data := GenericNameData{TypeName: typeName}
+	for arg := range named.TypeArgs().Types() {
+		argName, err := s.typeArgName(arg)
+		if errors.Is(err, errOpenTypeArg) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		data.TypeArgs = append(data.TypeArgs, argName)
+	}
+	name, err := s.app.genericNamer.execute(data)
+	if err != nil {
+		return nil, fmt.Errorf("GenericNameTemplate fails for type %s: %w", key, err)
+	}
+	if err := checkDefinitionName(name); err != nil {
+		return nil, fmt.Errorf("GenericNameTemplate names type %s %q: %w", key, name, err)
+	}
+
+	decl := *generic
+	decl.Type = named
+	decl.Alias = nil
+	decl.instanceName = name
+	decl.cacheAnnotations()
+	unlock = s.app.lock()
+	s.app.instances[key] = &decl
+	unlock()
+	return &decl, nil
+}
+
+// typeArgName names a type argument in the name of an instantiation: the definition name of a type, the
+// name of its instantiation, or a name spelling out an anonymous type, e.g. UserList for []User.
+func (s *scanCtx) typeArgName(tpe types.Type) (string, error) {
+	switch arg := types.Unalias(tpe).(type) {
+	case *types.Pointer:
+		return s.typeArgName(arg.Elem())
+	case *types.Named:
+		decl, err := s.instanceDecl(arg)
+		if err != nil {
+			return "", err
+		}
+		if decl != nil {
+			name, _ := decl.Names()
+			return name, nil
+		}
+		obj := arg.Obj()
+		name := obj.Name()
+		if obj.Pkg() != nil {
+			if decl, found := s.FindDecl(obj.Pkg().Path(), obj.Name()); found {
+				name, _ = decl.Names()
+			}
+		}
+		if arg.TypeArgs().Len() == 0 {
+			return upperFirst(name), nil
+		}
+		data := GenericNameData{TypeName: upperFirst(name)}
+		for elem := range arg.TypeArgs().Types() {
+			elemName, err := s.typeArgName(elem)
+			if err != nil {
+				return "", err
+			}
+			data.TypeArgs = append(data.TypeArgs, elemName)
+		}
+		return s.app.genericNamer.execute(data)
+	case *types.Basic:
+		return upperFirst(arg.Name()), nil
+	case *types.Slice:
+		elem, err := s.typeArgName(arg.Elem())
+		return elem + "List", err
+	case *types.Array:
+		elem, err := s.typeArgName(arg.Elem())
+		return elem + "List", err
+	case *types.Map:
+		key, err := s.typeArgName(arg.Key())
+		if err != nil {
+			return "", err
+		}
+		elem, err := s.typeArgName(arg.Elem())
+		return "MapOf" + key + "To" + elem, err
+	case *types.Interface:
+		if arg.Empty() {
+			return "Any", nil
+		}
+		return "Object", nil
+	case *types.TypeParam:
+		return "", errOpenTypeArg
+	default:
+		return "Object", nil
+	}
+}
+
+func upperFirst(name string) string {
+	first, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToUpper(first)) + name[size:]
+}
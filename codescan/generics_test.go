@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenericInstantiations(t *testing.T) {
+	const pkg = "github.com/3idey/codescan/fixtures/goparsing/generics"
+
+	t.Run("should document the instantiations as definitions of their own", func(t *testing.T) {
+		doc, err := Run(&Options{Packages: []string{pkg}, ScanModels: true})
+		require.NoError(t, err)
+
+		catalog := doc.Definitions["Catalog"]
+		assert.Equal(t, "#/definitions/PageOfUser", schemaRef(catalog.Properties["users"]))
+		assert.Equal(t, "#/definitions/PageOfUser", schemaRef(catalog.Properties["others"]), "the same instantiation has one definition")
+		assert.Equal(t, "#/definitions/PageOfOrder", schemaRef(catalog.Properties["orders"]), "the pointers are dereferenced")
+		assert.Equal(t, "#/definitions/PairOfStringAndUser", schemaRef(catalog.Properties["owner"]))
+
+		users := doc.Definitions["PageOfUser"]
+		assert.Equal(t, "#/definitions/User", users.Properties["items"].Items.Schema.Ref.String(), "the type argument is substituted")
+		assert.Equal(t, "The items of the page.", users.Properties["items"].Description)
+		assert.True(t, users.Properties["total"].Type.Contains("integer"))
+		assert.NotContains(t, users.Extensions, "x-go-name")
+		assert.Equal(t, "#/definitions/Order", doc.Definitions["PageOfOrder"].Properties["items"].Items.Schema.Ref.String())
+
+		owner := doc.Definitions["PairOfStringAndUser"]
+		assert.True(t, owner.Properties["key"].Type.Contains("string"))
+		assert.Equal(t, "#/definitions/User", schemaRef(owner.Properties["value"]))
+
+		assert.NotContains(t, doc.Definitions, "Page", "the generic type itself isn't documented")
+	})
+
+	t.Run("should document the nested instantiations of the responses and parameters", func(t *testing.T) {
+		doc, err := Run(&Options{Packages: []string{pkg}})
+		require.NoError(t, err)
+
+		assert.Equal(t, "#/definitions/APIResponseOfPageOfUser", doc.Responses["usersResponse"].Schema.Ref.String())
+		assert.Equal(t, "#/definitions/PageOfUser", schemaRef(doc.Definitions["APIResponseOfPageOfUser"].Properties["data"]))
+
+		params := doc.Paths.Paths["/orders"].Post.Parameters
+		require.Len(t, params, 1)
+		assert.Equal(t, "#/definitions/PageOfOrder", params[0].Schema.Ref.String())
+		assert.Contains(t, doc.Definitions, "PageOfOrder")
+	})
+
+	t.Run("should name the instantiations with GenericNameTemplate", func(t *testing.T) {
+		doc, err := Run(&Options{Packages: []string{pkg}, GenericNameTemplate: `{{.TypeName}}_{{join .TypeArgs "_"}}`})
+		require.NoError(t, err)
+
+		assert.Contains(t, doc.Definitions, "APIResponse_Page_User")
+		assert.Contains(t, doc.Definitions, "Page_User")
+	})
+
+	t.Run("should fail on invalid templates", func(t *testing.T) {
+		_, err := Run(&Options{Packages: []string{pkg}, GenericNameTemplate: "{{.TypeName}}"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid generic name template")
+		assert.Contains(t, err.Error(), "without its type name and type arguments")
+
+		_, err = Run(&Options{Packages: []string{pkg}, GenericNameTemplate: "{{.TypeName}} of {{index .TypeArgs 0}}"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "must not contain slashes, #, ~ or spaces")
+	})
+}
+
+func schemaRef(schema spec.Schema) string {
+	return schema.Ref.String()
+}
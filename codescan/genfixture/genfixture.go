@@ -0,0 +1,297 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package genfixture synthesizes a Go module of annotated API code, to benchmark and fuzz the scans on
+realistic shapes rather than on small hand-written fixtures.
+
+The module has a swagger:meta package, packages of models and packages of handlers. Each model has
+validated fields, a field of the enum of its package, anonymous structs nested to the depth of the options,
+a ref to the next model and, past the first package of models, a list of a model of the previous one. Each
+operation is a swagger:route handler with its swagger:parameters and swagger:response structs, whose bodies
+are models. The module only imports the standard library, and is the same for the same options:
+
+	err := genfixture.Write("bench/fixture", genfixture.Options{Operations: 500, Models: 2000, Depth: 2, Enums: 5})
+*/
+package genfixture
+
+import (
+	"errors"
+	"fmt"
+	"go/format"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const (
+	// DefaultModule is the module path of a fixture without Options.Module.
+	DefaultModule = "example.com/fixture"
+
+	modelsPerPackage     = 100
+	operationsPerPackage = 50
+)
+
+// Options are the size and the shapes of a fixture.
+type Options struct {
+	// Module is the module path of the fixture, DefaultModule when empty.
+	Module string
+	// Operations is the number of swagger:route handlers, which need at least a model for their bodies.
+	Operations int
+	// Models is the number of swagger:model structs.
+	Models int
+	// Depth is the number of levels of anonymous structs nested in each model.
+	Depth int
+	// Enums is the number of values of the swagger:enum of each package of models, which has none when zero.
+	Enums int
+}
+
+func (o Options) check() error {
+	switch {
+	case o.Operations < 0 || o.Models < 0 || o.Depth < 0 || o.Enums < 0:
+		return errors.New("the operations, models, depth and enums of a fixture can't be negative")
+	case o.Operations > 0 && o.Models == 0:
+		return errors.New("the operations of a fixture need at least a model")
+	default:
+		return nil
+	}
+}
+
+func (o Options) module() string {
+	if o.Module == "" {
+		return DefaultModule
+	}
+	return o.Module
+}
+
+// Generate returns the files of the fixture of the options, by slash-separated path in the module.
+func Generate(opts Options) (map[string][]byte, error) {
+	if err := opts.check(); err != nil {
+		return nil, err
+	}
+	g := &generator{opts: opts, module: opts.module(), files: make(map[string][]byte)}
+	g.files["go.mod"] = fmt.Appendf(nil, "module %s\n\ngo 1.22\n", g.module)
+	if err := g.writeMeta(); err != nil {
+		return nil, err
+	}
+	for pkg := range packageCount(opts.Models, modelsPerPackage) {
+		if err := g.writeModels(pkg); err != nil {
+			return nil, err
+		}
+	}
+	for pkg := range packageCount(opts.Operations, operationsPerPackage) {
+		if err := g.writeHandlers(pkg); err != nil {
+			return nil, err
+		}
+	}
+	return g.files, nil
+}
+
+// Write writes the fixture of the options to a directory, created if needed. The other files of the
+// directory are left as they are.
+func Write(dir string, opts Options) error {
+	files, err := Generate(opts)
+	if err != nil {
+		return err
+	}
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, files[name], 0o600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func packageCount(items, perPackage int) int {
+	return (items + perPackage - 1) / perPackage
+}
+
+type generator struct {
+	opts   Options
+	module string
+	files  map[string][]byte
+}
+
+// addFile formats the source of a file, which fails on a generated syntax error.
+func (g *generator) addFile(name string, src *strings.Builder) error {
+	formatted, err := format.Source([]byte(src.String()))
+	if err != nil {
+		return fmt.Errorf("generated %s: %w", name, err)
+	}
+	g.files[name] = formatted
+	return nil
+}
+
+func (g *generator) writeMeta() error {
+	name := path.Base(g.module)
+	var src strings.Builder
+	fmt.Fprintf(&src, "// Package %s is a generated API.\n", packageName(name))
+	src.WriteString("//\n// The API of a synthesized module, with its models and handlers.\n//\n")
+	src.WriteString("//\tVersion: 1.0.0\n//\tSchemes: https\n//\tConsumes:\n//\t- application/json\n//\tProduces:\n//\t- application/json\n//\n")
+	fmt.Fprintf(&src, "// swagger:meta\npackage %s\n", packageName(name))
+	return g.addFile("doc.go", &src)
+}
+
+func modelsPackage(pkg int) string {
+	return fmt.Sprintf("m%03d", pkg)
+}
+
+func handlersPackage(pkg int) string {
+	return fmt.Sprintf("h%03d", pkg)
+}
+
+// modelRef is the Go type of a model in a package of handlers or of models.
+func modelRef(model int) string {
+	return fmt.Sprintf("%s.Model%d", modelsPackage(model/modelsPerPackage), model)
+}
+
+func (g *generator) writeModels(pkg int) error {
+	name := modelsPackage(pkg)
+	first, last := pkg*modelsPerPackage, min((pkg+1)*modelsPerPackage, g.opts.Models)
+
+	var src strings.Builder
+	fmt.Fprintf(&src, "// Package %s holds generated models.\npackage %s\n\n", name, name)
+	if pkg > 0 {
+		fmt.Fprintf(&src, "import %q\n\n", g.module+"/models/"+modelsPackage(pkg-1))
+	}
+	status := fmt.Sprintf("Status%d", pkg)
+	if g.opts.Enums > 0 {
+		fmt.Fprintf(&src, "// %s is the status of the models of %s.\n//\n// swagger:enum %s\ntype %s string\n\nconst (\n", status, name, status, status)
+		for value := range g.opts.Enums {
+			fmt.Fprintf(&src, "\t%sValue%d %s = \"value%d\"\n", status, value, status, value)
+		}
+		src.WriteString(")\n\n")
+	}
+
+	for model := first; model < last; model++ {
+		fmt.Fprintf(&src, "// Model%d is a generated model.\n//\n// It spans several lines,\n// like the descriptions of the models.\n//\n// swagger:model\n", model)
+		fmt.Fprintf(&src, "type Model%d struct {\n", model)
+		src.WriteString("\t// The identifier.\n\t//\n\t// required: true\n\t// minimum: 1\n\tID int64 `json:\"id\"`\n")
+		src.WriteString("\t// The name.\n\t//\n\t// max length: 64\n\t// pattern: ^[a-z]+$\n\tName string `json:\"name,omitempty\"`\n")
+		if g.opts.Enums > 0 {
+			fmt.Fprintf(&src, "\t// The status.\n\tStatus %s `json:\"status\"`\n", status)
+		}
+		if g.opts.Depth > 0 {
+			src.WriteString("\t// The nested details.\n\tDetails ")
+			writeNested(&src, g.opts.Depth, 1)
+			src.WriteString(" `json:\"details\"`\n")
+		}
+		next := first + (model-first+1)%(last-first)
+		fmt.Fprintf(&src, "\t// The next model, closing a cycle.\n\tNext *Model%d `json:\"next,omitempty\"`\n", next)
+		if pkg > 0 {
+			fmt.Fprintf(&src, "\t// The models of the previous package.\n\tPrevious []%s `json:\"previous\"`\n", modelRef(model-modelsPerPackage))
+		}
+		src.WriteString("\t// The labels.\n\tLabels map[string]string `json:\"labels,omitempty\"`\n}\n\n")
+	}
+	return g.addFile("models/"+name+"/models.go", &src)
+}
+
+// writeNested writes an anonymous struct nesting the levels of the depth below it.
+func writeNested(src *strings.Builder, depth, level int) {
+	indent := strings.Repeat("\t", level)
+	fmt.Fprintf(src, "struct {\n%s\t// The count of level %d.\n%s\t//\n%s\t// maximum: 1000\n%s\tCount int `json:\"count\"`\n", indent, level, indent, indent, indent)
+	if level < depth {
+		fmt.Fprintf(src, "%s\tLevel%d ", indent, level+1)
+		writeNested(src, depth, level+1)
+		fmt.Fprintf(src, " `json:\"level%d\"`\n", level+1)
+	}
+	fmt.Fprintf(src, "%s}", indent)
+}
+
+// operation is the route of a generated operation: each resource has a GET, POST, PUT and DELETE.
+type operation struct {
+	method, path, id, handler, verb string
+	model                           int
+}
+
+func (g *generator) operation(index int) operation {
+	resource := index / 4
+	item := fmt.Sprintf("/resources%d/{id}", resource)
+	op := operation{model: index % g.opts.Models}
+	switch index % 4 {
+	case 0:
+		op.method, op.path, op.verb = "GET", item, "Gets"
+	case 1:
+		op.method, op.path, op.verb = "POST", fmt.Sprintf("/resources%d", resource), "Creates"
+	case 2:
+		op.method, op.path, op.verb = "PUT", item, "Updates"
+	default:
+		op.method, op.path, op.verb = "DELETE", item, "Deletes"
+	}
+	op.handler = fmt.Sprintf("%sResource%d", strings.TrimSuffix(op.verb, "s"), resource)
+	op.id = strings.ToLower(op.handler[:1]) + op.handler[1:]
+	return op
+}
+
+func (g *generator) writeHandlers(pkg int) error {
+	name := handlersPackage(pkg)
+	first, last := pkg*operationsPerPackage, min((pkg+1)*operationsPerPackage, g.opts.Operations)
+	ops := make([]operation, 0, last-first)
+	imports := make(map[int]bool)
+	for index := first; index < last; index++ {
+		op := g.operation(index)
+		ops = append(ops, op)
+		imports[op.model/modelsPerPackage] = true
+	}
+
+	var src strings.Builder
+	fmt.Fprintf(&src, "// Package %s holds generated handlers.\npackage %s\n\nimport (\n\t\"net/http\"\n\n", name, name)
+	for _, models := range slices.Sorted(maps.Keys(imports)) {
+		fmt.Fprintf(&src, "\t%q\n", g.module+"/models/"+modelsPackage(models))
+	}
+	src.WriteString(")\n\n")
+
+	for _, op := range ops {
+		fmt.Fprintf(&src, "// swagger:route %s %s %s %s\n//\n// %s a resource.\n//\n// Responses:\n", op.method, op.path, name, op.id, op.verb)
+		if op.method == "DELETE" {
+			src.WriteString("//   204: description: the resource was deleted\n")
+		} else {
+			fmt.Fprintf(&src, "//   200: %sResponse\n", op.id)
+		}
+		fmt.Fprintf(&src, "func %s(w http.ResponseWriter, r *http.Request) {}\n\n", op.handler)
+
+		fmt.Fprintf(&src, "// %sParams are the parameters of %s.\n//\n// swagger:parameters %s\ntype %sParams struct {\n", op.handler, op.id, op.id, op.handler)
+		if op.method != "POST" {
+			src.WriteString("\t// The identifier of the resource.\n\t//\n\t// in: path\n\t// required: true\n\tID int64 `json:\"id\"`\n")
+		}
+		switch op.method {
+		case "GET":
+			src.WriteString("\t// The number of items.\n\t//\n\t// in: query\n\t// maximum: 100\n\tLimit int `json:\"limit\"`\n")
+		case "POST", "PUT":
+			fmt.Fprintf(&src, "\t// The resource.\n\t//\n\t// in: body\n\t// required: true\n\tBody %s\n", modelRef(op.model))
+		}
+		src.WriteString("}\n\n")
+
+		if op.method != "DELETE" {
+			fmt.Fprintf(&src, "// %sResponse is the response of %s.\n//\n// swagger:response %sResponse\ntype %sResponse struct {\n", op.handler, op.id, op.id, op.handler)
+			fmt.Fprintf(&src, "\t// in: body\n\tBody %s\n}\n\n", modelRef(op.model))
+		}
+	}
+	return g.addFile("handlers/"+name+"/handlers.go", &src)
+}
+
+// packageName returns a Go package name of the last element of a module path, e.g. fixture of
+// example.com/fixture or v2 of example.com/api/v2.
+func packageName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r - 'A' + 'a'
+		default:
+			return -1
+		}
+	}, name)
+	if name == "" || name[0] >= '0' && name[0] <= '9' {
+		return "api" + name
+	}
+	return name
+}
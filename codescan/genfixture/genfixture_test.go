@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package genfixture
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	opts := Options{Module: "example.com/bench/v2", Operations: 130, Models: 210, Depth: 2, Enums: 4}
+	files, err := Generate(opts)
+	require.NoError(t, err)
+
+	t.Run("should split the models and the handlers in packages", func(t *testing.T) {
+		assert.Equal(t, []string{
+			"doc.go",
+			"go.mod",
+			"handlers/h000/handlers.go",
+			"handlers/h001/handlers.go",
+			"handlers/h002/handlers.go",
+			"models/m000/models.go",
+			"models/m001/models.go",
+			"models/m002/models.go",
+		}, slices.Sorted(maps.Keys(files)))
+		assert.Equal(t, "module example.com/bench/v2\n\ngo 1.22\n", string(files["go.mod"]))
+		assert.Contains(t, string(files["doc.go"]), "// swagger:meta\npackage v2\n")
+	})
+
+	t.Run("should generate the requested counts", func(t *testing.T) {
+		var models, routes, enums int
+		for name, content := range files {
+			models += strings.Count(string(content), "// swagger:model\n")
+			routes += strings.Count(string(content), "// swagger:route ")
+			if strings.HasPrefix(name, "models/") {
+				enums += strings.Count(string(content), "Value")
+			}
+		}
+		assert.Equal(t, 210, models)
+		assert.Equal(t, 130, routes)
+		assert.Equal(t, 3*4, enums, "the values of the enum of each package")
+	})
+
+	t.Run("should refer to the models of the other packages", func(t *testing.T) {
+		models := string(files["models/m002/models.go"])
+		assert.Contains(t, models, `import "example.com/bench/v2/models/m001"`)
+		assert.Contains(t, models, "Previous []m001.Model109")
+		assert.Contains(t, models, "Next *Model200", "the last model of a package closes the cycle")
+		handlers := string(files["handlers/h002/handlers.go"])
+		assert.Contains(t, handlers, "// swagger:route DELETE /resources31/{id} h002 deleteResource31")
+		assert.Contains(t, handlers, "// swagger:route POST /resources32 h002 createResource32")
+		assert.Contains(t, handlers, "Body m001.Model129")
+	})
+
+	t.Run("should generate the same files for the same options", func(t *testing.T) {
+		again, err := Generate(opts)
+		require.NoError(t, err)
+		assert.Equal(t, files, again)
+	})
+
+	t.Run("should write the files", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, Write(dir, opts))
+		content, err := os.ReadFile(filepath.Join(dir, "models", "m001", "models.go"))
+		require.NoError(t, err)
+		assert.Equal(t, files["models/m001/models.go"], content)
+	})
+
+	t.Run("should reject the invalid options", func(t *testing.T) {
+		_, err := Generate(Options{Operations: 1})
+		require.EqualError(t, err, "the operations of a fixture need at least a model")
+		_, err = Generate(Options{Models: 1, Depth: -1})
+		require.EqualError(t, err, "the operations, models, depth and enums of a fixture can't be negative")
+	})
+}
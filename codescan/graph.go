@@ -0,0 +1,302 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"fmt"
+	"go/token"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// Kinds of the nodes of a Graph.
+const (
+	GraphOperation  = "operation"
+	GraphParameter  = "parameter"
+	GraphResponse   = "response"
+	GraphDefinition = "definition"
+)
+
+// GraphOptions selects the nodes of the graph built by BuildGraph.
+type GraphOptions struct {
+	// Roots restricts the graph to the operations of these paths, with what they reach, e.g. "/v1/users" for
+	// all its operations, or "POST /v1/users" for one of them.
+	Roots []string
+	// Around restricts the graph to the nodes at most Depth edges away from this definition, in either
+	// direction, e.g. the operations using it and the definitions it refers to.
+	Around string
+	// Depth is the distance to Around of the nodes kept, 0 for any distance.
+	Depth int
+	// Positions are the Go positions of the elements of the spec by JSON pointer, e.g. the Options.SourceMap of
+	// its scan, set on the nodes.
+	Positions map[string]token.Position
+}
+
+// Graph is the dependency graph of a spec: its operations, then the parameters, responses and definitions
+// they refer to, and those refer to in turn.
+type Graph struct {
+	Nodes []GraphNode
+	Edges []GraphEdge
+}
+
+// GraphNode is an element of the spec, identified by its JSON pointer, e.g. /paths/~1users/get or
+// /definitions/User.
+type GraphNode struct {
+	ID    string
+	Kind  string // GraphOperation, GraphParameter, GraphResponse or GraphDefinition
+	Label string // e.g. "GET /users" or "User"
+	Pos   token.Position
+}
+
+// GraphEdge is a ref of an element to another one, or a use of a parameter, response or definition by an
+// operation, by their IDs.
+type GraphEdge struct {
+	From, To string
+}
+
+// graphRoot is the path, with an optional method, of the operations of a GraphOptions.Roots.
+type graphRoot struct {
+	path, method string
+}
+
+func parseGraphRoots(roots []string) ([]graphRoot, error) {
+	parsed := make([]graphRoot, 0, len(roots))
+	for _, root := range roots {
+		var r graphRoot
+		fields := strings.Fields(root)
+		if len(fields) == 2 && strings.HasPrefix(fields[0], "/") {
+			fields[0], fields[1] = fields[1], fields[0]
+		}
+		switch {
+		case len(fields) == 1:
+			r.path = fields[0]
+		case len(fields) == 2:
+			r.method, r.path = strings.ToLower(fields[0]), fields[1]
+		}
+		if !strings.HasPrefix(r.path, "/") || strings.HasPrefix(r.method, "/") {
+			return nil, fmt.Errorf("invalid root %q, expected a path with an optional method, e.g. POST /v1/users", root)
+		}
+		parsed = append(parsed, r)
+	}
+	return parsed, nil
+}
+
+// BuildGraph builds the dependency graph of a spec: each operation refers to the parameters, responses and
+// definitions of its parameters and responses, and these to the definitions they refer to. The subtypes of
+// a polymorphic definition refer to it with their allOf. Without Roots, the graph has every operation,
+// parameter, response and definition of the spec, used or not.
+func BuildGraph(doc *spec.Swagger, opts GraphOptions) (*Graph, error) {
+	roots, err := parseGraphRoots(opts.Roots)
+	if err != nil {
+		return nil, err
+	}
+	g := new(Graph)
+	if doc == nil {
+		return g, nil
+	}
+
+	var used []string
+	matched := make([]bool, len(roots))
+	var edges []GraphEdge
+	if doc.Paths != nil {
+		for _, pth := range sortedKeys(doc.Paths.Paths) {
+			pathItem := doc.Paths.Paths[pth]
+			for method, op := range pathItemOperations(&pathItem) {
+				selected := len(roots) == 0
+				for i, root := range roots {
+					if root.path == pth && (root.method == "" || root.method == method) {
+						matched[i], selected = true, true
+					}
+				}
+				if !selected {
+					continue
+				}
+				id := "/paths/" + escapePointer(pth) + "/" + method
+				g.Nodes = append(g.Nodes, GraphNode{ID: id, Kind: GraphOperation, Label: strings.ToUpper(method) + " " + pth})
+				uses := operationUses{refs: paramsRefs(pathItem.Parameters)}
+				uses.add(op)
+				for _, ref := range uses.refs {
+					edges = append(edges, GraphEdge{From: id, To: strings.TrimPrefix(ref, "#")})
+				}
+				used = append(used, uses.refs...)
+			}
+		}
+	}
+	for i := range roots {
+		if !matched[i] {
+			return nil, fmt.Errorf("no operation matches the root %q", opts.Roots[i])
+		}
+	}
+
+	var reached map[string]bool
+	if len(roots) > 0 {
+		reached = reachableRefs(doc, used)
+	} else {
+		reached = make(map[string]bool)
+		for name := range doc.Definitions {
+			reached[definitionsPrefix+escapePointer(name)] = true
+		}
+		for name := range doc.Parameters {
+			reached[parametersPrefix+escapePointer(name)] = true
+		}
+		for name := range doc.Responses {
+			reached[responsesPrefix+escapePointer(name)] = true
+		}
+	}
+	refs := newRefGraph(doc)
+	for _, ref := range sortedKeys(reached) {
+		node, known := graphElement(doc, ref)
+		if !known {
+			continue
+		}
+		g.Nodes = append(g.Nodes, node)
+		for _, target := range refs.targetRefs(ref) {
+			edges = append(edges, GraphEdge{From: node.ID, To: strings.TrimPrefix(target, "#")})
+		}
+	}
+	g.Edges = g.knownEdges(edges)
+
+	if opts.Around != "" {
+		if err := g.keepAround("/definitions/"+escapePointer(opts.Around), opts.Depth); err != nil {
+			return nil, err
+		}
+	}
+	for i := range g.Nodes {
+		g.Nodes[i].Pos = opts.Positions[g.Nodes[i].ID]
+	}
+	return g, nil
+}
+
+// graphElement returns the node of the definition, parameter or response a local ref points to.
+func graphElement(doc *spec.Swagger, ref string) (GraphNode, bool) {
+	node := GraphNode{ID: strings.TrimPrefix(ref, "#")}
+	var known bool
+	if name, ok := strings.CutPrefix(ref, definitionsPrefix); ok {
+		name = unescapePointer(name)
+		_, known = doc.Definitions[name]
+		node.Kind, node.Label = GraphDefinition, name
+	} else if name, ok := strings.CutPrefix(ref, parametersPrefix); ok {
+		name = unescapePointer(name)
+		_, known = doc.Parameters[name]
+		node.Kind, node.Label = GraphParameter, name
+	} else if name, ok := strings.CutPrefix(ref, responsesPrefix); ok {
+		name = unescapePointer(name)
+		_, known = doc.Responses[name]
+		node.Kind, node.Label = GraphResponse, name
+	}
+	return node, known
+}
+
+// knownEdges returns the edges between the nodes of the graph, once each, in their order: the refs to external
+// documents or to missing elements have no node.
+func (g *Graph) knownEdges(edges []GraphEdge) []GraphEdge {
+	nodes := make(map[string]bool, len(g.Nodes))
+	for _, node := range g.Nodes {
+		nodes[node.ID] = true
+	}
+	seen := make(map[GraphEdge]bool, len(edges))
+	known := make([]GraphEdge, 0, len(edges))
+	for _, edge := range edges {
+		if nodes[edge.From] && nodes[edge.To] && !seen[edge] {
+			seen[edge] = true
+			known = append(known, edge)
+		}
+	}
+	return known
+}
+
+// keepAround removes the nodes more than depth edges away from a node, in either direction, any distance
+// with a depth of 0.
+func (g *Graph) keepAround(center string, depth int) error {
+	if !slices.ContainsFunc(g.Nodes, func(node GraphNode) bool { return node.ID == center }) {
+		return fmt.Errorf("unknown definition %q in the graph", strings.TrimPrefix(center, "/definitions/"))
+	}
+	neighbours := make(map[string][]string)
+	for _, edge := range g.Edges {
+		neighbours[edge.From] = append(neighbours[edge.From], edge.To)
+		neighbours[edge.To] = append(neighbours[edge.To], edge.From)
+	}
+	kept := map[string]bool{center: true}
+	frontier := []string{center}
+	for distance := 0; len(frontier) > 0 && (depth <= 0 || distance < depth); distance++ {
+		var next []string
+		for _, id := range frontier {
+			for _, neighbour := range neighbours[id] {
+				if !kept[neighbour] {
+					kept[neighbour] = true
+					next = append(next, neighbour)
+				}
+			}
+		}
+		frontier = next
+	}
+	g.Nodes = slices.DeleteFunc(g.Nodes, func(node GraphNode) bool { return !kept[node.ID] })
+	g.Edges = g.knownEdges(g.Edges)
+	return nil
+}
+
+// label returns the label of a node, with its position on a second line when it is known.
+func (node GraphNode) label(newline string) string {
+	if !node.Pos.IsValid() {
+		return node.Label
+	}
+	return node.Label + newline + node.Pos.Filename + ":" + strconv.Itoa(node.Pos.Line)
+}
+
+// dotShapes are the DOT shapes of the kinds of nodes.
+var dotShapes = map[string]string{
+	GraphOperation:  "box",
+	GraphParameter:  "parallelogram",
+	GraphResponse:   "note",
+	GraphDefinition: "ellipse",
+}
+
+// WriteDOT writes the graph in the DOT language of Graphviz, e.g. for dot -Tsvg.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("digraph spec {\n  rankdir=LR;\n")
+	for _, node := range g.Nodes {
+		fmt.Fprintf(&b, "  %s [label=%s, shape=%s];\n", dotQuote(node.ID), dotQuote(node.label("\n")), dotShapes[node.Kind])
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&b, "  %s -> %s;\n", dotQuote(edge.From), dotQuote(edge.To))
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+// mermaidShapes are the opening and closing brackets of the Mermaid shapes of the kinds of nodes.
+var mermaidShapes = map[string][2]string{
+	GraphOperation:  {`["`, `"]`},
+	GraphParameter:  {`[/"`, `"/]`},
+	GraphResponse:   {`{{"`, `"}}`},
+	GraphDefinition: {`("`, `")`},
+}
+
+// WriteMermaid writes the graph as a Mermaid flowchart, e.g. for a markdown document.
+func (g *Graph) WriteMermaid(w io.Writer) error {
+	ids := make(map[string]string, len(g.Nodes))
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for i, node := range g.Nodes {
+		ids[node.ID] = "n" + strconv.Itoa(i)
+		shape := mermaidShapes[node.Kind]
+		label := strings.ReplaceAll(node.label("<br/>"), `"`, "#quot;")
+		fmt.Fprintf(&b, "  %s%s%s%s\n", ids[node.ID], shape[0], label, shape[1])
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&b, "  %s --> %s\n", ids[edge.From], ids[edge.To])
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
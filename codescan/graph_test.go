@@ -0,0 +1,129 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"go/token"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func nodeIDs(g *Graph) []string {
+	ids := make([]string, 0, len(g.Nodes))
+	for _, node := range g.Nodes {
+		ids = append(ids, node.ID)
+	}
+	return ids
+}
+
+func TestBuildGraph(t *testing.T) {
+	positions := make(map[string]token.Position)
+	doc, err := Run(&Options{Packages: []string{"github.com/3idey/codescan/fixtures/goparsing/stableorder/accounts"}, SourceMap: positions})
+	require.NoError(t, err)
+
+	t.Run("should link the operations to what they use", func(t *testing.T) {
+		g, err := BuildGraph(doc, GraphOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"/paths/~1accounts/get",
+			"/paths/~1accounts~1{id}/get",
+			"/definitions/Account",
+			"/definitions/Owner",
+			"/definitions/Settings",
+			"/responses/accounts",
+		}, nodeIDs(g))
+		assert.Equal(t, []GraphEdge{
+			{"/paths/~1accounts/get", "/responses/accounts"},
+			{"/paths/~1accounts~1{id}/get", "/definitions/Account"},
+			{"/definitions/Account", "/definitions/Owner"},
+			{"/definitions/Account", "/definitions/Settings"},
+			{"/responses/accounts", "/definitions/Account"},
+		}, g.Edges)
+		assert.Equal(t, "GET /accounts/{id}", g.Nodes[1].Label)
+		assert.Equal(t, GraphResponse, g.Nodes[5].Kind)
+		assert.False(t, g.Nodes[0].Pos.IsValid())
+	})
+
+	t.Run("should keep what the roots reach", func(t *testing.T) {
+		g, err := BuildGraph(doc, GraphOptions{Roots: []string{"/accounts/{id} GET"}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"/paths/~1accounts~1{id}/get", "/definitions/Account", "/definitions/Owner", "/definitions/Settings"}, nodeIDs(g))
+
+		_, err = BuildGraph(doc, GraphOptions{Roots: []string{"POST /accounts"}})
+		require.EqualError(t, err, `no operation matches the root "POST /accounts"`)
+		_, err = BuildGraph(doc, GraphOptions{Roots: []string{"GET POST"}})
+		require.EqualError(t, err, `invalid root "GET POST", expected a path with an optional method, e.g. POST /v1/users`)
+	})
+
+	t.Run("should keep the nodes around a definition", func(t *testing.T) {
+		g, err := BuildGraph(doc, GraphOptions{Around: "Owner", Depth: 1})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"/definitions/Account", "/definitions/Owner"}, nodeIDs(g))
+
+		g, err = BuildGraph(doc, GraphOptions{Around: "Owner", Depth: 2})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"/paths/~1accounts~1{id}/get", "/definitions/Account", "/definitions/Owner", "/definitions/Settings", "/responses/accounts"}, nodeIDs(g))
+
+		_, err = BuildGraph(doc, GraphOptions{Around: "Pet"})
+		require.EqualError(t, err, `unknown definition "Pet" in the graph`)
+	})
+
+	t.Run("should label the nodes with their positions", func(t *testing.T) {
+		g, err := BuildGraph(doc, GraphOptions{Around: "Owner", Depth: 1, Positions: positions})
+		require.NoError(t, err)
+		require.Len(t, g.Nodes, 2)
+		assert.Equal(t, "api.go", filepath.Base(g.Nodes[1].Pos.Filename))
+		assert.Equal(t, 68, g.Nodes[1].Pos.Line)
+	})
+
+	t.Run("should link the subtypes to their base", func(t *testing.T) {
+		polymorphic := &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+			Paths: &spec.Paths{Paths: map[string]spec.PathItem{"/pets": {PathItemProps: spec.PathItemProps{
+				Get: spec.NewOperation("listPets").RespondsWith(200, spec.NewResponse().WithSchema(spec.RefSchema("#/definitions/Pet"))),
+			}}}},
+			Definitions: spec.Definitions{
+				"Pet": *spec.StringProperty().WithDiscriminator("kind"),
+				"Cat": *new(spec.Schema).WithAllOf(*spec.RefSchema("#/definitions/Pet")),
+				"Toy": *spec.StringProperty(),
+			},
+		}}
+		g, err := BuildGraph(polymorphic, GraphOptions{Roots: []string{"/pets"}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"/paths/~1pets/get", "/definitions/Cat", "/definitions/Pet"}, nodeIDs(g))
+		assert.Contains(t, g.Edges, GraphEdge{"/definitions/Cat", "/definitions/Pet"})
+	})
+}
+
+func TestWriteGraph(t *testing.T) {
+	g := &Graph{
+		Nodes: []GraphNode{
+			{ID: "/paths/~1pets/get", Kind: GraphOperation, Label: "GET /pets", Pos: token.Position{Filename: "api.go", Line: 12, Column: 1}},
+			{ID: "/definitions/Pet", Kind: GraphDefinition, Label: `Pet "v2"`},
+		},
+		Edges: []GraphEdge{{"/paths/~1pets/get", "/definitions/Pet"}},
+	}
+
+	var dot strings.Builder
+	require.NoError(t, g.WriteDOT(&dot))
+	assert.Equal(t, `digraph spec {
+  rankdir=LR;
+  "/paths/~1pets/get" [label="GET /pets\napi.go:12", shape=box];
+  "/definitions/Pet" [label="Pet \"v2\"", shape=ellipse];
+  "/paths/~1pets/get" -> "/definitions/Pet";
+}
+`, dot.String())
+
+	var mermaid strings.Builder
+	require.NoError(t, g.WriteMermaid(&mermaid))
+	assert.Equal(t, `flowchart LR
+  n0["GET /pets<br/>api.go:12"]
+  n1("Pet #quot;v2#quot;")
+  n0 --> n1
+`, mermaid.String())
+}
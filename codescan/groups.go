@@ -0,0 +1,190 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-openapi/spec"
+)
+
+// propertyGroup nests the properties of the fields named with a prefix under an object property.
+type propertyGroup struct {
+	name   string // property of the group, e.g. billing
+	prefix string // prefix of the Go names of the fields of the group, e.g. Billing
+}
+
+// setPropertyGroups restructures a flat model into nested objects, documenting the wire format of a type
+// whose custom marshaler nests its fields:
+//
+//	// Group: billing=Billing*, shipping=Shipping*
+//
+// The properties of the fields named with a prefix move to the object property of their group, without
+// the prefix, e.g. BillingStreet to billing.street. The other properties stay at the top level.
+type setPropertyGroups struct {
+	builder *schemaBuilder
+}
+
+func (sg *setPropertyGroups) Matches(line string) bool {
+	return rxGroup.MatchString(line)
+}
+
+func (sg *setPropertyGroups) Parse(lines []string) error {
+	if len(lines) == 0 || (len(lines) == 1 && len(lines[0]) == 0) {
+		return nil
+	}
+	matches := rxGroup.FindStringSubmatch(lines[0])
+	if len(matches) < 2 {
+		return nil
+	}
+
+	decl := sg.builder.decl
+	for member := range strings.SplitSeq(matches[1], ",") {
+		name, pattern, _ := strings.Cut(member, "=")
+		name, pattern = strings.TrimSpace(name), strings.TrimSpace(pattern)
+		prefix, isPrefix := strings.CutSuffix(pattern, "*")
+		if !isPrefix || prefix == "" || strings.Contains(prefix, "*") {
+			return fmt.Errorf("%v: invalid group %q in the Group of %s, expected name=Prefix*",
+				decl.Pkg.Fset.Position(decl.Ident.Pos()), strings.TrimSpace(member), decl.Ident.Name)
+		}
+		if slices.ContainsFunc(sg.builder.groups, func(group propertyGroup) bool { return group.name == name }) {
+			return fmt.Errorf("%v: the Group of %s declares group %s twice",
+				decl.Pkg.Fset.Position(decl.Ident.Pos()), decl.Ident.Name, name)
+		}
+		sg.builder.groups = append(sg.builder.groups, propertyGroup{name: name, prefix: prefix})
+	}
+	return nil
+}
+
+// applyPropertyGroups moves the properties of a model to the object properties of its groups. A group is
+// required when one of its members is, and takes the x-order of its first member.
+func (s *schemaBuilder) applyPropertyGroups(schema *spec.Schema) error {
+	if len(s.groups) == 0 {
+		return nil
+	}
+	position := s.decl.Pkg.Fset.Position(s.decl.Ident.Pos())
+
+	nested := make(map[string]*spec.Schema, len(s.groups))
+	for _, group := range s.groups {
+		if _, exists := schema.Properties[group.name]; exists {
+			return fmt.Errorf("%v: group %s of %s has the name of a property", position, group.name, s.decl.Ident.Name)
+		}
+		nested[group.name] = new(spec.Schema).Typed("object", "")
+	}
+
+	for _, name := range sortedKeys(schema.Properties) {
+		property := schema.Properties[name]
+		goName, hasGoName := property.Extensions.GetString("x-go-name")
+		if !hasGoName {
+			goName = name
+		}
+		group, found := matchPropertyGroup(s.groups, goName)
+		if !found {
+			continue
+		}
+
+		member := groupMemberName(name, group.prefix)
+		target := nested[group.name]
+		if _, exists := target.Properties[member]; exists {
+			return fmt.Errorf("%v: several properties of %s are named %s in group %s", position, s.decl.Ident.Name, member, group.name)
+		}
+		if !hasGoName {
+			property.AddExtension("x-go-name", goName)
+		}
+		if order, ordered := propertyOrder(property); ordered {
+			if groupOrder, hasOrder := propertyOrder(*target); !hasOrder || order < groupOrder {
+				target.AddExtension("x-order", order)
+			}
+		}
+		target.SetProperty(member, property)
+		delete(schema.Properties, name)
+		if i := slices.Index(schema.Required, name); i >= 0 {
+			schema.Required = slices.Delete(schema.Required, i, i+1)
+			target.Required = append(target.Required, member)
+		}
+	}
+
+	for _, group := range s.groups {
+		target := nested[group.name]
+		if len(target.Properties) == 0 {
+			return fmt.Errorf("%v: group %s of %s matches no property with %s*", position, group.name, s.decl.Ident.Name, group.prefix)
+		}
+		renumberOrder(target.Properties)
+		slices.Sort(target.Required)
+		if len(target.Required) > 0 {
+			schema.Required = append(schema.Required, group.name)
+		}
+		schema.SetProperty(group.name, *target)
+	}
+	renumberOrder(schema.Properties)
+	return nil
+}
+
+// matchPropertyGroup returns the group of a field by its Go name, the one with the longest prefix when
+// several match.
+func matchPropertyGroup(groups []propertyGroup, goName string) (propertyGroup, bool) {
+	var match propertyGroup
+	found := false
+	for _, group := range groups {
+		if len(goName) > len(group.prefix) && strings.HasPrefix(goName, group.prefix) && len(group.prefix) > len(match.prefix) {
+			match, found = group, true
+		}
+	}
+	return match, found
+}
+
+// groupMemberName strips the prefix of a group from a property, e.g. billingStreet or billing_street to
+// street. A property named otherwise than its field keeps its name.
+func groupMemberName(name, prefix string) string {
+	if len(name) <= len(prefix) || !strings.EqualFold(name[:len(prefix)], prefix) {
+		return name
+	}
+	member := strings.TrimLeft(name[len(prefix):], "_-.")
+	if member == "" {
+		return name
+	}
+	if first, _ := utf8.DecodeRuneInString(name); unicode.IsLower(first) {
+		head, size := utf8.DecodeRuneInString(member)
+		member = string(unicode.ToLower(head)) + member[size:]
+	}
+	return member
+}
+
+// renumberOrder numbers the x-order of properties from 0 again, once properties moved to groups.
+func renumberOrder(properties spec.SchemaProperties) {
+	var names []string
+	for name, property := range properties {
+		if _, ordered := propertyOrder(property); ordered {
+			names = append(names, name)
+		}
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		orderA, _ := propertyOrder(properties[a])
+		orderB, _ := propertyOrder(properties[b])
+		// a group has the order of its first member: the names break the tie, rather than the map
+		return cmp.Or(cmp.Compare(orderA, orderB), strings.Compare(a, b))
+	})
+	for order, name := range names {
+		property := properties[name]
+		property.AddExtension("x-order", order)
+		properties[name] = property
+	}
+}
+
+// propertyOrder returns the x-order of a property, set by applyFieldOrder, or decoded from an input spec.
+func propertyOrder(property spec.Schema) (int, bool) {
+	switch order := property.Extensions["x-order"].(type) {
+	case int:
+		return order, true
+	case float64:
+		return int(order), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPropertyGroups(t *testing.T) {
+	const pkg = "github.com/3idey/codescan/fixtures/goparsing/groups"
+
+	build := func(t *testing.T, name string, opts *Options) (spec.Schema, error) {
+		t.Helper()
+		opts.Packages = []string{pkg}
+		sctx, err := newScanCtx(opts)
+		require.NoError(t, err)
+		decl, found := sctx.FindDecl(pkg, name)
+		require.True(t, found)
+
+		definitions := make(map[string]spec.Schema)
+		err = (&schemaBuilder{ctx: sctx, decl: decl}).Build(definitions)
+		return definitions[name], err
+	}
+
+	t.Run("should nest the fields of the groups", func(t *testing.T) {
+		schema, err := build(t, "Customer", &Options{})
+		require.NoError(t, err)
+
+		assert.ElementsMatch(t, []string{"name", "billing", "shipping"}, sortedKeys(schema.Properties))
+		assert.Equal(t, []string{"name", "billing"}, schema.Required)
+		assert.NotContains(t, schema.Description, "Group:")
+
+		billing := schema.Properties["billing"]
+		assert.True(t, billing.Type.Contains("object"))
+		assert.ElementsMatch(t, []string{"street", "city"}, sortedKeys(billing.Properties))
+		assert.Equal(t, []string{"street"}, billing.Required)
+		goName, _ := billing.Properties["street"].Extensions.GetString("x-go-name")
+		assert.Equal(t, "BillingStreet", goName)
+
+		shipping := schema.Properties["shipping"]
+		assert.ElementsMatch(t, []string{"street", "city", "Notes"}, sortedKeys(shipping.Properties))
+		assert.Empty(t, shipping.Required)
+		goName, _ = shipping.Properties["Notes"].Extensions.GetString("x-go-name")
+		assert.Equal(t, "ShippingNotes", goName)
+	})
+
+	t.Run("should order the groups by their first member", func(t *testing.T) {
+		schema, err := build(t, "Customer", &Options{DeclarationOrder: true})
+		require.NoError(t, err)
+
+		order := func(schema spec.Schema) any { return schema.Extensions["x-order"] }
+		assert.Equal(t, 0, order(schema.Properties["name"]))
+		assert.Equal(t, 1, order(schema.Properties["billing"]))
+		assert.Equal(t, 2, order(schema.Properties["shipping"]))
+		shipping := schema.Properties["shipping"]
+		assert.Equal(t, 0, order(shipping.Properties["street"]))
+		assert.Equal(t, 2, order(shipping.Properties["Notes"]))
+	})
+
+	t.Run("should fail when a group has the name of a property", func(t *testing.T) {
+		_, err := build(t, "Conflicting", &Options{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "group billing of Conflicting has the name of a property")
+	})
+
+	t.Run("should fail when a group matches no property", func(t *testing.T) {
+		_, err := build(t, "Unmatched", &Options{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "group billing of Unmatched matches no property with Invoice*")
+	})
+}
+
+func TestGroupMemberName(t *testing.T) {
+	assert.Equal(t, "street", groupMemberName("billingStreet", "Billing"))
+	assert.Equal(t, "street", groupMemberName("billing_street", "Billing"))
+	assert.Equal(t, "Street", groupMemberName("BillingStreet", "Billing"))
+	assert.Equal(t, "addr1", groupMemberName("addr1", "Billing"))
+	assert.Equal(t, "billing_", groupMemberName("billing_", "Billing"))
+}
@@ -0,0 +1,154 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-openapi/spec"
+)
+
+// HoistedParameter is a shared parameter of the spec replacing identical inline parameters of the operations,
+// see Options.HoistParameters.
+type HoistedParameter struct {
+	Name       string   `json:"name"`       // the name of the shared parameter, e.g. limitQuery
+	Operations []string `json:"operations"` // the operations referring to it, e.g. "GET /pets", sorted
+}
+
+// checkHoistParameters checks the number of operations of Options.HoistParameters.
+func checkHoistParameters(operations int) error {
+	if operations < 0 || operations == 1 {
+		return fmt.Errorf("the parameters are hoisted from 2 operations or more, or none with 0, got %d", operations)
+	}
+	return nil
+}
+
+// parameterOccurrence is an inline parameter of an operation.
+type parameterOccurrence struct {
+	op    *spec.Operation
+	index int
+	key   string // e.g. "GET /pets"
+}
+
+// hoistParameters replaces the inline parameters identical in at least minOperations operations with $refs
+// to shared parameters, and returns them, sorted by name. The parameters are identical when they are equal
+// but for the position of their source, which the shared parameter takes from the first operation, by path
+// and method. A shared parameter of the spec identical to them is used, the others being named after their
+// name and location, e.g. limitQuery, followed by a number when the name is taken. The body parameters stay
+// inline, their request bodies of OpenAPI 3.0 depending on the consumes of their operations.
+//
+// The resolved spec is unchanged: the parameters of the operations are those they were, in the same order.
+func hoistParameters(doc *spec.Swagger, minOperations int) ([]HoistedParameter, error) {
+	if doc.Paths == nil || minOperations < 2 {
+		return nil, nil
+	}
+
+	var hashes []string // in the order of the first occurrences
+	occurrences := make(map[string][]parameterOccurrence)
+	for _, pth := range sortedKeys(doc.Paths.Paths) {
+		pathItem := doc.Paths.Paths[pth]
+		for method, op := range pathItemOperations(&pathItem) {
+			for i, param := range op.Parameters {
+				if param.Ref.String() != "" || param.In == "body" {
+					continue
+				}
+				hash, err := parameterHash(param)
+				if err != nil {
+					return nil, fmt.Errorf("the parameter %s of %s %s: %w", param.Name, strings.ToUpper(method), pth, err)
+				}
+				if _, seen := occurrences[hash]; !seen {
+					hashes = append(hashes, hash)
+				}
+				occurrences[hash] = append(occurrences[hash], parameterOccurrence{op: op, index: i, key: strings.ToUpper(method) + " " + pth})
+			}
+		}
+	}
+
+	shared := make(map[string]string, len(doc.Parameters))
+	for _, name := range sortedKeys(doc.Parameters) {
+		hash, err := parameterHash(doc.Parameters[name])
+		if err != nil {
+			return nil, fmt.Errorf("the shared parameter %s: %w", name, err)
+		}
+		if _, found := shared[hash]; !found {
+			shared[hash] = name
+		}
+	}
+
+	var hoisted []HoistedParameter
+	for _, hash := range hashes {
+		found := occurrences[hash]
+		var operations []string
+		for _, occurrence := range found {
+			if !slices.Contains(operations, occurrence.key) {
+				operations = append(operations, occurrence.key)
+			}
+		}
+		if len(operations) < minOperations {
+			continue
+		}
+
+		name, exists := shared[hash]
+		if !exists {
+			first := found[0]
+			param := first.op.Parameters[first.index]
+			name = sharedParameterName(doc, param)
+			if doc.Parameters == nil {
+				doc.Parameters = make(map[string]spec.Parameter)
+			}
+			doc.Parameters[name] = param
+		}
+		ref := spec.Parameter{Refable: spec.Refable{Ref: spec.MustCreateRef(parametersPrefix + escapePointer(name))}}
+		for _, occurrence := range found {
+			occurrence.op.Parameters[occurrence.index] = ref
+		}
+		slices.Sort(operations)
+		hoisted = append(hoisted, HoistedParameter{Name: name, Operations: operations})
+	}
+	slices.SortFunc(hoisted, func(a, b HoistedParameter) int { return strings.Compare(a.Name, b.Name) })
+
+	return hoisted, nil
+}
+
+// parameterHash returns a hash of a parameter without the position of its source, shared by the identical
+// parameters.
+func parameterHash(param spec.Parameter) (string, error) {
+	if _, found := param.Extensions[sourcePositionExtension]; found {
+		param.Extensions = maps.Clone(param.Extensions)
+		delete(param.Extensions, sourcePositionExtension)
+	}
+	// map keys, hence extensions, are marshaled in sorted order
+	jazon, err := json.Marshal(param)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(jazon)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// sharedParameterName names a hoisted parameter after its name and location, e.g. limitQuery for the limit
+// query parameter, followed by a number when a parameter of the spec has the name.
+func sharedParameterName(doc *spec.Swagger, param spec.Parameter) string {
+	base := camelCase(param.Name + " " + param.In)
+	if first, size := utf8.DecodeRuneInString(base); size > 0 {
+		base = string(unicode.ToLower(first)) + base[size:]
+	}
+	name := base
+	for i := 2; ; i++ {
+		if _, taken := doc.Parameters[name]; !taken {
+			return name
+		}
+		name = base + strconv.Itoa(i)
+	}
+}
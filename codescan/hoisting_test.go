@@ -0,0 +1,104 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/3idey/codescan/codescan/specdiff"
+)
+
+func TestHoistParameters(t *testing.T) {
+	const pkg = "github.com/3idey/codescan/fixtures/goparsing/hoisting"
+	before, err := Run(&Options{Packages: []string{pkg}})
+	require.NoError(t, err)
+	var stats Stats
+	after, err := Run(&Options{Packages: []string{pkg}, HoistParameters: 3, Stats: &stats})
+	require.NoError(t, err)
+
+	t.Run("should hoist the parameters identical in enough operations", func(t *testing.T) {
+		assert.Equal(t, []string{"limitQuery", "offsetQuery", "xRequestIdHeader"}, sortedKeys(after.Parameters))
+		limit := after.Parameters["limitQuery"]
+		assert.Equal(t, "limit", limit.Name)
+		assert.Equal(t, "query", limit.In)
+		assert.Equal(t, "the number of items", limit.Description)
+
+		listPets := after.Paths.Paths["/pets"].Get.Parameters
+		require.Len(t, listPets, 3)
+		for i, name := range []string{"limitQuery", "offsetQuery", "xRequestIdHeader"} {
+			assert.Equal(t, "#/parameters/"+name, listPets[i].Ref.String(), "the refs keep the order of the parameters")
+		}
+
+		assert.Equal(t, []HoistedParameter{
+			{Name: "limitQuery", Operations: []string{"GET /owners", "GET /pets", "GET /toys"}},
+			{Name: "offsetQuery", Operations: []string{"GET /owners", "GET /pets", "GET /toys", "GET /vets"}},
+			{Name: "xRequestIdHeader", Operations: []string{"GET /owners", "GET /pets", "POST /pets"}},
+		}, stats.HoistedParameters)
+	})
+
+	t.Run("should leave the other parameters inline", func(t *testing.T) {
+		listVets := after.Paths.Paths["/vets"].Get.Parameters
+		require.Len(t, listVets, 2)
+		assert.Equal(t, "the number of vets", listVets[0].Description, "the limit differs")
+		assert.Equal(t, "#/parameters/offsetQuery", listVets[1].Ref.String())
+
+		createPet := after.Paths.Paths["/pets"].Post.Parameters
+		require.Len(t, createPet, 2)
+		assert.Equal(t, "#/parameters/xRequestIdHeader", createPet[0].Ref.String())
+		assert.Equal(t, "body", createPet[1].In, "the body parameters stay inline")
+
+		assert.Empty(t, before.Parameters, "the parameters are hoisted on demand")
+	})
+
+	t.Run("should preserve the resolved spec", func(t *testing.T) {
+		assert.Empty(t, specdiff.Diff(before, after, specdiff.DefaultPolicy()))
+
+		expanded := func(doc *spec.Swagger) string {
+			data, err := json.Marshal(doc)
+			require.NoError(t, err)
+			var clone spec.Swagger
+			require.NoError(t, json.Unmarshal(data, &clone))
+			require.NoError(t, spec.ExpandSpec(&clone, nil))
+			paths, err := json.Marshal(clone.Paths)
+			require.NoError(t, err)
+			return string(paths)
+		}
+		assert.JSONEq(t, expanded(before), expanded(after))
+	})
+
+	t.Run("should use an identical shared parameter, and number the names taken", func(t *testing.T) {
+		input := &spec.Swagger{SwaggerProps: spec.SwaggerProps{Parameters: map[string]spec.Parameter{
+			"pageSize":    before.Paths.Paths["/pets"].Get.Parameters[0],
+			"offsetQuery": *spec.QueryParam("offset").Typed("string", ""),
+		}}}
+		var stats Stats
+		doc, err := Run(&Options{Packages: []string{pkg}, InputSpec: input, HoistParameters: 3, Stats: &stats})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"offsetQuery", "offsetQuery2", "pageSize", "xRequestIdHeader"}, sortedKeys(doc.Parameters))
+		assert.Equal(t, "#/parameters/pageSize", doc.Paths.Paths["/pets"].Get.Parameters[0].Ref.String())
+		assert.Equal(t, "#/parameters/offsetQuery2", doc.Paths.Paths["/pets"].Get.Parameters[1].Ref.String())
+		assert.Equal(t, "string", doc.Parameters["offsetQuery"].Type)
+		assert.Equal(t, "pageSize", stats.HoistedParameters[1].Name, "the hoisted parameters sort by name")
+	})
+
+	t.Run("should upgrade the shared parameters to OpenAPI 3", func(t *testing.T) {
+		doc3, err := Run3(&Options{Packages: []string{pkg}, HoistParameters: 3})
+		require.NoError(t, err)
+		parameters := asObject(asObject(doc3["components"])["parameters"])
+		assert.Equal(t, []string{"limitQuery", "offsetQuery", "xRequestIdHeader"}, sortedKeys(parameters))
+		op := asObject(asObject(asObject(doc3["paths"])["/pets"])["get"])
+		assert.Equal(t, map[string]any{"$ref": "#/components/parameters/limitQuery"}, asList(op["parameters"])[0])
+	})
+
+	t.Run("should reject hoisting from a single operation", func(t *testing.T) {
+		_, err := Run(&Options{Packages: []string{pkg}, HoistParameters: 1})
+		require.EqualError(t, err, "the parameters are hoisted from 2 operations or more, or none with 0, got 1")
+		require.ErrorContains(t, (&Options{HoistParameters: -1}).Validate(), "got -1")
+	})
+}
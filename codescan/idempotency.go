@@ -0,0 +1,139 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"fmt"
+	"go/token"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+const (
+	xIdempotent = "x-idempotent"
+
+	idempotencyKeyHeader      = "Idempotency-Key"
+	idempotencyKeyDescription = "Unique key of the request, chosen by the client: the requests retried with the same key are processed at most once."
+)
+
+// idempotentMethods are the methods idempotent by definition (RFC 9110), see Options.DefaultIdempotency.
+var idempotentMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodOptions: true,
+	http.MethodPut:     true,
+	http.MethodDelete:  true,
+}
+
+// setIdempotentOp parses the "Idempotent: true|false" line of a route.
+type setIdempotentOp struct {
+	tgt *spec.Operation
+}
+
+func (su *setIdempotentOp) Matches(line string) bool {
+	return rxIdempotent.MatchString(line)
+}
+
+func (su *setIdempotentOp) Parse(lines []string) error {
+	if len(lines) == 0 || (len(lines) == 1 && len(lines[0]) == 0) {
+		return nil
+	}
+	matches := rxIdempotent.FindStringSubmatch(lines[0])
+	if len(matches) > 1 && len(matches[1]) > 0 {
+		idempotent, err := strconv.ParseBool(matches[1])
+		if err != nil {
+			return err
+		}
+		su.tgt.AddExtension(xIdempotent, idempotent)
+	}
+	return nil
+}
+
+// setIdempotencyKeyOp parses the "IdempotencyKey: required|optional" line of a route,
+// which documents the Idempotency-Key header of the operation.
+type setIdempotencyKeyOp struct {
+	tgt *spec.Operation
+}
+
+func (su *setIdempotencyKeyOp) Matches(line string) bool {
+	return rxIdempotencyKey.MatchString(line)
+}
+
+func (su *setIdempotencyKeyOp) Parse(lines []string) error {
+	if len(lines) == 0 || (len(lines) == 1 && len(lines[0]) == 0) {
+		return nil
+	}
+	matches := rxIdempotencyKey.FindStringSubmatch(lines[0])
+	if len(matches) < 2 || hasIdempotencyKey(su.tgt) {
+		return nil
+	}
+
+	param := spec.HeaderParam(idempotencyKeyHeader).Typed("string", "")
+	param.Description = idempotencyKeyDescription
+	param.Required = matches[1] == "required"
+	su.tgt.AddParam(param)
+	return nil
+}
+
+func hasIdempotencyKey(op *spec.Operation) bool {
+	for _, param := range op.Parameters {
+		if param.In == "header" && strings.EqualFold(param.Name, idempotencyKeyHeader) {
+			return true
+		}
+	}
+	return false
+}
+
+// isIdempotent tells if an operation is declared idempotent, by an annotation or an x-idempotent extension.
+func isIdempotent(op *spec.Operation) (idempotent, declared bool) {
+	value, ok := op.Extensions[xIdempotent]
+	if !ok {
+		return false, false
+	}
+	switch v := value.(type) {
+	case bool:
+		return v, true
+	case string:
+		idempotent, err := strconv.ParseBool(v)
+		return idempotent, err == nil
+	}
+	return false, false
+}
+
+// checkIdempotencyKey reports the POST operations declared idempotent without an Idempotency-Key header:
+// the server has no way to recognize a retried request then.
+func (a *typeIndex) checkIdempotencyKey(method string, op *spec.Operation, pos token.Position) {
+	if !strings.EqualFold(method, http.MethodPost) {
+		return
+	}
+	if idempotent, _ := isIdempotent(op); !idempotent || hasIdempotencyKey(op) {
+		return
+	}
+	a.diagnose(Diagnostic{
+		Pos:     pos,
+		Code:    DiagnosticMissingIdempotencyKey,
+		Message: fmt.Sprintf("POST operation %s is declared idempotent without an %s header, see IdempotencyKey", op.ID, idempotencyKeyHeader),
+	})
+}
+
+// applyDefaultIdempotency documents the idempotency of the operations which don't declare it,
+// from their method, see Options.DefaultIdempotency.
+func (s *specBuilder) applyDefaultIdempotency() {
+	if !s.ctx.opts.DefaultIdempotency || s.input.Paths == nil {
+		return
+	}
+
+	for pth, pathItem := range s.input.Paths.Paths {
+		for method, op := range pathItemOperations(&pathItem) {
+			if _, declared := isIdempotent(op); declared {
+				continue
+			}
+			op.AddExtension(xIdempotent, idempotentMethods[strings.ToUpper(method)])
+		}
+		s.input.Paths.Paths[pth] = pathItem
+	}
+}
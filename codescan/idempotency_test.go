@@ -0,0 +1,70 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const idempotencyFixture = "github.com/3idey/codescan/fixtures/goparsing/idempotency"
+
+func TestIdempotency(t *testing.T) {
+	var diagnostics []Diagnostic
+	doc, err := Run(&Options{Packages: []string{idempotencyFixture}, Diagnostics: &diagnostics})
+	require.NoError(t, err)
+
+	payments := doc.Paths.Paths["/payments"]
+	payment := doc.Paths.Paths["/payments/{id}"]
+	refunds := doc.Paths.Paths["/refunds"]
+
+	idempotent, declared := isIdempotent(payments.Post)
+	assert.True(t, declared)
+	assert.True(t, idempotent)
+	require.Len(t, payments.Post.Parameters, 1)
+	key := payments.Post.Parameters[0]
+	assert.Equal(t, "Idempotency-Key", key.Name)
+	assert.Equal(t, "header", key.In)
+	assert.True(t, key.Required)
+	assert.Equal(t, idempotencyKeyDescription, key.Description)
+
+	idempotent, declared = isIdempotent(payment.Put)
+	assert.True(t, declared)
+	assert.False(t, idempotent)
+
+	require.Len(t, payment.Patch.Parameters, 1)
+	assert.False(t, payment.Patch.Parameters[0].Required)
+
+	_, declared = isIdempotent(payments.Get)
+	assert.False(t, declared, "idempotency is only documented by default with DefaultIdempotency")
+
+	require.Len(t, diagnostics, 1)
+	assert.Equal(t, DiagnosticMissingIdempotencyKey, diagnostics[0].Code)
+	assert.Contains(t, diagnostics[0].Message, "createRefund")
+	assert.Equal(t, "api.go", filepath.Base(diagnostics[0].Pos.Filename))
+	assert.Empty(t, refunds.Post.Parameters)
+}
+
+func TestDefaultIdempotency(t *testing.T) {
+	doc, err := Run(&Options{Packages: []string{idempotencyFixture}, DefaultIdempotency: true})
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		op         *spec.Operation
+		idempotent bool
+	}{
+		{doc.Paths.Paths["/payments"].Get, true},
+		{doc.Paths.Paths["/payments"].Post, true},        // declared
+		{doc.Paths.Paths["/payments/{id}"].Put, false},   // declared
+		{doc.Paths.Paths["/payments/{id}"].Patch, false}, // from the method
+	} {
+		idempotent, declared := isIdempotent(tc.op)
+		assert.True(t, declared, tc.op.ID)
+		assert.Equal(t, tc.idempotent, idempotent, tc.op.ID)
+	}
+}
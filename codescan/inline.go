@@ -0,0 +1,176 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/go-openapi/spec"
+)
+
+// DefinitionUse describes a definition which is referenced from exactly one place in a spec.
+type DefinitionUse struct {
+	Name      string // name of the definition
+	Referrer  string // JSON pointer to the schema holding the only $ref to the definition
+	Inlinable bool   // false when the definition is part of a cycle or of a discriminated hierarchy
+	Reason    string // explains why the definition is not inlinable
+}
+
+// SingleUseDefinitions lists the definitions of a spec which are referenced exactly once, sorted by name.
+func SingleUseDefinitions(doc *spec.Swagger) []DefinitionUse {
+	if doc == nil {
+		return nil
+	}
+
+	referrers := definitionReferrers(doc)
+	graph := definitionGraph(doc)
+
+	var uses []DefinitionUse
+	for _, name := range sortedKeys(doc.Definitions) {
+		if len(referrers[name]) != 1 {
+			continue
+		}
+		use := DefinitionUse{
+			Name:      name,
+			Referrer:  referrers[name][0],
+			Inlinable: true,
+		}
+		switch {
+		case isDiscriminated(doc, name):
+			use.Inlinable = false
+			use.Reason = "part of a discriminated hierarchy"
+		case reachable(graph, name, name):
+			use.Inlinable = false
+			use.Reason = "part of a reference cycle"
+		}
+		uses = append(uses, use)
+	}
+
+	return uses
+}
+
+// inlineSingleUse replaces the only $ref to a single-use definition by the definition itself,
+// then removes the definition, until no more definition can be inlined.
+//
+// With descWithRef, annotations found at the ref site (e.g. a description overloaded on a property)
+// take precedence over the ones of the definition. Otherwise, the definition wins and
+// the ref site annotations are only used when the definition leaves them empty.
+func inlineSingleUse(doc *spec.Swagger, descWithRef bool) {
+	for {
+		uses := SingleUseDefinitions(doc)
+		idx := slices.IndexFunc(uses, func(use DefinitionUse) bool { return use.Inlinable })
+		if idx < 0 {
+			return
+		}
+		name := uses[idx].Name
+		definition := doc.Definitions[name]
+		delete(doc.Definitions, name)
+
+		walkSpecSchemas(doc, func(sch *spec.Schema, _ string) {
+			if target, ok := definitionName(sch.Ref); ok && target == name {
+				*sch = mergeRefSite(*sch, definition, descWithRef)
+			}
+		})
+	}
+}
+
+func mergeRefSite(site, definition spec.Schema, descWithRef bool) spec.Schema {
+	inlined := definition
+	inlined.Extensions = maps.Clone(definition.Extensions)
+
+	overrides := func(siteValue, definitionValue string) string {
+		if siteValue != "" && (descWithRef || definitionValue == "") {
+			return siteValue
+		}
+		return definitionValue
+	}
+	inlined.Description = overrides(site.Description, definition.Description)
+	inlined.Title = overrides(site.Title, definition.Title)
+	if site.Example != nil && (descWithRef || definition.Example == nil) {
+		inlined.Example = site.Example
+	}
+	inlined.ReadOnly = inlined.ReadOnly || site.ReadOnly
+
+	for k, v := range site.Extensions {
+		if _, exists := inlined.Extensions[k]; exists && !descWithRef {
+			continue
+		}
+		inlined.AddExtension(k, v)
+	}
+
+	return inlined
+}
+
+// definitionReferrers maps the definitions of a spec to the locations of the $refs pointing to them.
+func definitionReferrers(doc *spec.Swagger) map[string][]string {
+	referrers := make(map[string][]string)
+	walkSpecSchemas(doc, func(sch *spec.Schema, location string) {
+		if name, ok := definitionName(sch.Ref); ok {
+			referrers[name] = append(referrers[name], location)
+		}
+	})
+
+	return referrers
+}
+
+// definitionGraph maps the definitions of a spec to the definitions they refer to.
+func definitionGraph(doc *spec.Swagger) map[string]map[string]struct{} {
+	graph := make(map[string]map[string]struct{}, len(doc.Definitions))
+	for name, definition := range doc.Definitions {
+		edges := make(map[string]struct{})
+		walkSchema(&definition, definitionsPrefix+escapePointer(name), func(sch *spec.Schema, _ string) {
+			if target, ok := definitionName(sch.Ref); ok {
+				edges[target] = struct{}{}
+			}
+		})
+		graph[name] = edges
+	}
+
+	return graph
+}
+
+// reachable tells if the definition to may be reached by following the $refs from the definition from.
+func reachable(graph map[string]map[string]struct{}, from, to string) bool {
+	visited := make(map[string]bool)
+	stack := slices.Collect(maps.Keys(graph[from]))
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if current == to {
+			return true
+		}
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+		for next := range graph[current] {
+			stack = append(stack, next)
+		}
+	}
+
+	return false
+}
+
+// isDiscriminated tells if a definition is a discriminated base type, or a subtype of one.
+func isDiscriminated(doc *spec.Swagger, name string) bool {
+	definition := doc.Definitions[name]
+	if definition.Discriminator != "" {
+		return true
+	}
+	if _, isSubtype := definition.Extensions.GetString("x-class"); isSubtype {
+		return true
+	}
+	for _, member := range definition.AllOf {
+		base, ok := definitionName(member.Ref)
+		if !ok {
+			continue
+		}
+		if parent, exists := doc.Definitions[base]; exists && parent.Discriminator != "" {
+			return true
+		}
+	}
+
+	return false
+}
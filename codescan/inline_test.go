@@ -0,0 +1,126 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const singleUseFixture = `{
+  "swagger": "2.0",
+  "paths": {
+    "/pets": {
+      "get": {
+        "responses": {
+          "200": {
+            "description": "pets",
+            "schema": {"type": "array", "items": {"$ref": "#/definitions/Pet"}}
+          }
+        }
+      }
+    }
+  },
+  "definitions": {
+    "Pet": {
+      "type": "object",
+      "properties": {
+        "owner": {"$ref": "#/definitions/Owner", "description": "The owner of the pet."},
+        "tag": {"$ref": "#/definitions/Tag"},
+        "tags": {"type": "array", "items": {"$ref": "#/definitions/Tag"}},
+        "node": {"$ref": "#/definitions/Node"},
+        "shape": {"$ref": "#/definitions/Shape"}
+      }
+    },
+    "Owner": {
+      "type": "object",
+      "description": "An owner.",
+      "properties": {"name": {"type": "string"}, "address": {"$ref": "#/definitions/Address"}}
+    },
+    "Address": {"type": "object", "properties": {"street": {"type": "string"}}},
+    "Tag": {"type": "string"},
+    "Node": {"type": "object", "properties": {"next": {"$ref": "#/definitions/Next"}}},
+    "Next": {"type": "object", "properties": {"node": {"$ref": "#/definitions/Node"}}},
+    "Shape": {"type": "object", "discriminator": "kind", "properties": {"kind": {"type": "string"}}}
+  }
+}`
+
+func loadSingleUseFixture(t *testing.T) *spec.Swagger {
+	t.Helper()
+	var doc spec.Swagger
+	require.NoError(t, json.Unmarshal([]byte(singleUseFixture), &doc))
+
+	return &doc
+}
+
+func TestSingleUseDefinitions(t *testing.T) {
+	uses := SingleUseDefinitions(loadSingleUseFixture(t))
+
+	assert.Equal(t, []DefinitionUse{
+		{Name: "Address", Referrer: "#/definitions/Owner/properties/address", Inlinable: true},
+		{Name: "Next", Referrer: "#/definitions/Node/properties/next", Reason: "part of a reference cycle"},
+		{Name: "Owner", Referrer: "#/definitions/Pet/properties/owner", Inlinable: true},
+		{Name: "Pet", Referrer: "#/paths/~1pets/get/responses/200/schema/items", Inlinable: true},
+		{Name: "Shape", Referrer: "#/definitions/Pet/properties/shape", Reason: "part of a discriminated hierarchy"},
+	}, uses)
+}
+
+func TestInlineSingleUse(t *testing.T) {
+	t.Run("should inline single use definitions", func(t *testing.T) {
+		doc := loadSingleUseFixture(t)
+		inlineSingleUse(doc, false)
+
+		assert.ElementsMatch(t, []string{"Tag", "Node", "Next", "Shape"}, sortedKeys(doc.Definitions))
+
+		items := doc.Paths.Paths["/pets"].Get.Responses.StatusCodeResponses[200].Schema.Items.Schema
+		require.NotNil(t, items)
+		assert.Empty(t, items.Ref.String())
+
+		owner := items.Properties["owner"]
+		assert.Empty(t, owner.Ref.String())
+		assert.Equal(t, "An owner.", owner.Description)
+		assert.Contains(t, owner.Properties["address"].Properties, "street")
+
+		for prop, ref := range map[string]string{
+			"tag":   "#/definitions/Tag",
+			"node":  "#/definitions/Node",
+			"shape": "#/definitions/Shape",
+		} {
+			sch := items.Properties[prop]
+			assert.Equal(t, ref, sch.Ref.String())
+		}
+	})
+
+	t.Run("should keep the ref site description with DescWithRef", func(t *testing.T) {
+		doc := loadSingleUseFixture(t)
+		inlineSingleUse(doc, true)
+
+		items := doc.Paths.Paths["/pets"].Get.Responses.StatusCodeResponses[200].Schema.Items.Schema
+		require.NotNil(t, items)
+		assert.Equal(t, "The owner of the pet.", items.Properties["owner"].Description)
+	})
+
+	t.Run("should follow the escaped refs to the names with a / or a ~", func(t *testing.T) {
+		var doc spec.Swagger
+		require.NoError(t, json.Unmarshal([]byte(`{
+  "swagger": "2.0",
+  "definitions": {
+    "v1/Pet": {"type": "object", "properties": {"owner": {"$ref": "#/definitions/v1~1Owner~0draft"}}},
+    "v1/Owner~draft": {"type": "object", "properties": {"name": {"type": "string"}}}
+  }
+}`), &doc))
+
+		assert.Equal(t, []DefinitionUse{
+			{Name: "v1/Owner~draft", Referrer: "#/definitions/v1~1Pet/properties/owner", Inlinable: true},
+		}, SingleUseDefinitions(&doc))
+
+		inlineSingleUse(&doc, false)
+		assert.Equal(t, []string{"v1/Pet"}, sortedKeys(doc.Definitions))
+		assert.Contains(t, doc.Definitions["v1/Pet"].Properties["owner"].Properties, "name")
+	})
+}
@@ -0,0 +1,337 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// Merge strategies of the scan with the input spec, see Options.MergeStrategy.
+const (
+	// MergeScanWins keeps the scanned side of a conflict.
+	MergeScanWins = "scan-wins"
+	// MergeInputWins keeps the side of the input spec of a conflict.
+	MergeInputWins = "input-wins"
+	// MergeError fails the scan with the conflicts, see ErrMergeConflict.
+	MergeError = "error"
+)
+
+// Origins of the entries of a spec merged with the input spec, see MergeEntry.
+const (
+	MergeOriginInput = "input"
+	MergeOriginScan  = "scan"
+	MergeOriginBoth  = "both" // a conflict, resolved by Options.MergeStrategy
+)
+
+// ErrMergeConflict is returned with the MergeError strategy when the scan defines keys of the input spec.
+var ErrMergeConflict = errors.New("the scan conflicts with the input spec")
+
+func checkMergeStrategy(strategy string) error {
+	switch strategy {
+	case "", MergeScanWins, MergeInputWins, MergeError:
+		return nil
+	default:
+		return fmt.Errorf("unknown merge strategy %q, expected %s, %s or %s", strategy, MergeScanWins, MergeInputWins, MergeError)
+	}
+}
+
+// inputMerge merges the scan with the input spec, see Options.MergeStrategy: the scan builds its paths apart
+// from those of the input spec, and its definitions from scratch, the conflicts being resolved when both are
+// built, see mergeInput.
+type inputMerge struct {
+	strategy  string
+	input     *spec.Swagger             // a copy of the input spec, before the scan
+	paths     *spec.Paths               // the paths of the input spec, into which the scanned paths merge
+	scanned   map[string]token.Position // the definitions built by the scan
+	entries   []MergeEntry
+	conflicts []error
+}
+
+// startMerge sets aside the paths of the input spec, and the operations collected from them, for a merge
+// strategy.
+func (s *specBuilder) startMerge() error {
+	strategy := s.ctx.opts.MergeStrategy
+	if strategy == "" {
+		return nil
+	}
+	input, err := cloneSpec(s.input)
+	if err != nil {
+		return fmt.Errorf("copy of the input spec: %w", err)
+	}
+	s.merge = &inputMerge{
+		strategy: strategy,
+		input:    input,
+		paths:    s.input.Paths,
+		scanned:  make(map[string]token.Position),
+	}
+	s.input.Paths = &spec.Paths{Paths: make(map[string]spec.PathItem)}
+	s.operations = make(map[string]*spec.Operation)
+	return nil
+}
+
+// scannedDefinition records a definition built by the scan, for the merge with the input spec.
+func (s *specBuilder) scannedDefinition(name string, pos token.Position) {
+	if s.merge != nil {
+		s.merge.scanned[name] = pos
+	}
+}
+
+// rebuildsInput tells if a discovered type named after a definition of the input spec is built anyway, to
+// merge it: except with MergeInputWins, which would keep the definition of the input spec.
+func (s *specBuilder) rebuildsInput(name string) bool {
+	if s.merge == nil || s.merge.strategy == MergeInputWins {
+		return false
+	}
+	_, inInput := s.merge.input.Definitions[name]
+	_, scanned := s.merge.scanned[name]
+	return inInput && !scanned
+}
+
+// resolve records an entry of the merge, and tells if the scanned side wins a conflict.
+func (m *inputMerge) resolve(section, key, subject string, inInput, scanned bool, pos token.Position) bool {
+	entry := MergeEntry{Section: section, Key: key, Origin: MergeOriginInput}
+	if pos.IsValid() {
+		entry.Source = pos.String()
+	}
+	switch {
+	case !scanned:
+	case !inInput:
+		entry.Origin = MergeOriginScan
+	default:
+		entry.Origin = MergeOriginBoth
+		switch m.strategy {
+		case MergeScanWins:
+			entry.Kept = MergeOriginScan
+		case MergeInputWins:
+			entry.Kept = MergeOriginInput
+		default:
+			m.conflicts = append(m.conflicts, fmt.Errorf("%v: %s is also in the input spec", pos, subject))
+		}
+	}
+	m.entries = append(m.entries, entry)
+	return scanned && entry.Kept != MergeOriginInput
+}
+
+// mergeInput merges the scanned paths and definitions with those of the input spec.
+func (s *specBuilder) mergeInput() {
+	if s.merge == nil {
+		return
+	}
+	s.mergePaths()
+	s.mergeDefinitions()
+
+	// the scan defines no shared parameters, which all come from the input spec
+	for _, name := range sortedKeys(s.merge.input.Parameters) {
+		s.merge.resolve("parameters", name, "", true, false, token.Position{})
+	}
+}
+
+func (s *specBuilder) mergePaths() {
+	positions := make(map[string]token.Position, len(s.ctx.app.Routes)+len(s.ctx.app.Operations))
+	for _, pp := range append(slices.Clone(s.ctx.app.Routes), s.ctx.app.Operations...) {
+		positions[strings.ToLower(pp.Method)+" "+pp.Path] = pp.Pos
+	}
+
+	scanned, merged := s.input.Paths, s.merge.paths
+	if merged.Paths == nil {
+		merged.Paths = make(map[string]spec.PathItem, len(scanned.Paths))
+	}
+	for _, pth := range sortedKeys(mergedPathKeys(merged, scanned)) {
+		base, inInput := merged.Paths[pth]
+		item, isScanned := scanned.Paths[pth]
+		if !isScanned {
+			for method := range pathItemOperations(&base) {
+				s.merge.resolve("paths", strings.ToUpper(method)+" "+pth, "", true, false, token.Position{})
+			}
+			continue
+		}
+		if !inInput {
+			merged.Paths[pth] = item
+			for method := range pathItemOperations(&item) {
+				s.merge.resolve("paths", strings.ToUpper(method)+" "+pth, "", false, true, positions[method+" "+pth])
+			}
+			continue
+		}
+
+		scannedMethods := make(map[string]bool)
+		for method, op := range pathItemOperations(&item) {
+			scannedMethods[method] = true
+			key := strings.ToUpper(method) + " " + pth
+			subject := fmt.Sprintf("operation %s (%s)", key, op.ID)
+			_, conflicts := methodOperation(&base, method)
+			if s.merge.resolve("paths", key, subject, conflicts, true, positions[method+" "+pth]) {
+				setMethodOperation(&base, method, op)
+			}
+		}
+		for method := range pathItemOperations(&base) {
+			if !scannedMethods[method] {
+				s.merge.resolve("paths", strings.ToUpper(method)+" "+pth, "", true, false, token.Position{})
+			}
+		}
+		if len(base.Parameters) == 0 {
+			base.Parameters = item.Parameters
+		}
+		for k, v := range item.Extensions {
+			if _, found := base.Extensions[k]; !found {
+				base.AddExtension(k, v)
+			}
+		}
+		merged.Paths[pth] = base
+	}
+	s.input.Paths = merged
+}
+
+func mergedPathKeys(merged, scanned *spec.Paths) map[string]bool {
+	keys := make(map[string]bool, len(merged.Paths)+len(scanned.Paths))
+	for pth := range merged.Paths {
+		keys[pth] = true
+	}
+	for pth := range scanned.Paths {
+		keys[pth] = true
+	}
+	return keys
+}
+
+func (s *specBuilder) mergeDefinitions() {
+	for _, name := range sortedKeys(s.definitions) {
+		original, inInput := s.merge.input.Definitions[name]
+		pos, scanned := s.merge.scanned[name]
+		if !scanned && !inInput {
+			pos, scanned = s.ctx.app.definitionPositions[name], true // e.g. a definition of a derived model
+		}
+		if !s.merge.resolve("definitions", name, "definition "+name, inInput, scanned, pos) {
+			s.definitions[name] = original
+		}
+	}
+}
+
+// mergeMeta merges the swagger:meta of the scan with the input spec, which is the base the scan fills: each
+// field of the info, the host and the base path are kept when set, the schemes, consumes and produces are
+// joined, and the conflicting security definitions are resolved like the definitions.
+func (m *inputMerge) mergeMeta(doc, scanned *spec.Swagger, positions map[string]token.Position) {
+	if scanned.Info != nil {
+		info := safeInfo(doc)
+		fillString(&info.Title, scanned.Info.Title)
+		fillString(&info.Description, scanned.Info.Description)
+		fillString(&info.TermsOfService, scanned.Info.TermsOfService)
+		fillString(&info.Version, scanned.Info.Version)
+		if info.Contact == nil {
+			info.Contact = scanned.Info.Contact
+		}
+		if info.License == nil {
+			info.License = scanned.Info.License
+		}
+		for k, v := range scanned.Info.Extensions {
+			if _, found := info.Extensions[k]; !found {
+				info.AddExtension(k, v)
+			}
+		}
+	}
+	fillString(&doc.Host, scanned.Host)
+	fillString(&doc.BasePath, scanned.BasePath)
+	doc.Schemes = joinValues(doc.Schemes, scanned.Schemes)
+	doc.Consumes = joinValues(doc.Consumes, scanned.Consumes)
+	doc.Produces = joinValues(doc.Produces, scanned.Produces)
+	if len(doc.Security) == 0 {
+		doc.Security = scanned.Security
+	}
+	if doc.ExternalDocs == nil {
+		doc.ExternalDocs = scanned.ExternalDocs
+	}
+	for k, v := range scanned.Extensions {
+		if _, found := doc.Extensions[k]; !found {
+			doc.AddExtension(k, v)
+		}
+	}
+
+	if len(scanned.SecurityDefinitions) > 0 && doc.SecurityDefinitions == nil {
+		doc.SecurityDefinitions = make(spec.SecurityDefinitions, len(scanned.SecurityDefinitions))
+	}
+	names := maps.Clone(doc.SecurityDefinitions)
+	maps.Copy(names, scanned.SecurityDefinitions)
+	for _, name := range sortedKeys(names) {
+		_, inInput := doc.SecurityDefinitions[name]
+		scheme, isScanned := scanned.SecurityDefinitions[name]
+		if m.resolve("securityDefinitions", name, "security definition "+name, inInput, isScanned, positions[name]) {
+			mergeScopes(scheme, doc.SecurityDefinitions[name])
+			doc.SecurityDefinitions[name] = scheme
+		} else if isScanned {
+			mergeScopes(doc.SecurityDefinitions[name], scheme)
+		}
+	}
+}
+
+// checkMergeConflicts fails with the conflicts of the MergeError strategy, ErrMergeConflict listing each
+// conflicting key at the Go source of its scanned side.
+func (s *specBuilder) checkMergeConflicts() error {
+	if s.merge == nil {
+		return nil
+	}
+	s.ctx.app.stats.MergeEntries = s.merge.entries
+	if len(s.merge.conflicts) == 0 {
+		return nil
+	}
+	return errors.Join(append([]error{fmt.Errorf("%w, with %d conflicting keys, see MergeStrategy", ErrMergeConflict, len(s.merge.conflicts))}, s.merge.conflicts...)...)
+}
+
+// mergeTag merges the documentation of a swagger:tag with a tag of the input spec, filling its gaps.
+func mergeTag(tag *spec.Tag, scanned spec.Tag) {
+	fillString(&tag.Description, scanned.Description)
+	if tag.ExternalDocs == nil {
+		tag.ExternalDocs = scanned.ExternalDocs
+	}
+}
+
+func fillString(dst *string, value string) {
+	if *dst == "" {
+		*dst = value
+	}
+}
+
+// joinValues appends the values of scanned which aren't in values.
+func joinValues(values, scanned []string) []string {
+	for _, value := range scanned {
+		if !slices.Contains(values, value) {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// methodOperation returns the operation of a method, in lower case, of a path item.
+func methodOperation(pathItem *spec.PathItem, method string) (*spec.Operation, bool) {
+	for m, op := range pathItemOperations(pathItem) {
+		if m == method {
+			return op, true
+		}
+	}
+	return nil, false
+}
+
+// setMethodOperation sets the operation of a method, in lower case, of a path item, see removeOperation.
+func setMethodOperation(pathItem *spec.PathItem, method string, op *spec.Operation) {
+	switch method {
+	case "get":
+		pathItem.Get = op
+	case "put":
+		pathItem.Put = op
+	case "post":
+		pathItem.Post = op
+	case "delete":
+		pathItem.Delete = op
+	case "options":
+		pathItem.Options = op
+	case "head":
+		pathItem.Head = op
+	case "patch":
+		pathItem.Patch = op
+	}
+}
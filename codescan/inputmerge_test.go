@@ -0,0 +1,146 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mergeInputFixture = `{
+  "swagger": "2.0",
+  "info": {"title": "Pet store", "version": "1.0.0"},
+  "schemes": ["https"],
+  "consumes": ["application/json"],
+  "tags": [{"name": "pets", "externalDocs": {"url": "https://example.com/pets"}}],
+  "paths": {
+    "/pets": {
+      "get": {"operationId": "listAllPets", "summary": "Lists the pets of the input.", "responses": {"200": {"description": "the pets"}}},
+      "delete": {"operationId": "deletePets", "responses": {"204": {"description": "the pets were deleted"}}}
+    },
+    "/stores": {
+      "get": {"operationId": "listStores", "responses": {"200": {"description": "the stores"}}}
+    }
+  },
+  "definitions": {
+    "Pet": {"type": "object", "description": "the pet of the input", "properties": {"id": {"type": "integer"}}},
+    "Store": {"type": "object"}
+  },
+  "parameters": {
+    "limit": {"name": "limit", "in": "query", "type": "integer"}
+  },
+  "securityDefinitions": {
+    "api_key": {"type": "apiKey", "name": "X-Input-Key", "in": "header"}
+  }
+}`
+
+func TestMergeStrategy(t *testing.T) {
+	const pkg = "github.com/3idey/codescan/fixtures/goparsing/merge"
+	run := func(t *testing.T, strategy string) (*spec.Swagger, Stats, error) {
+		t.Helper()
+		input, err := ParseInputSpec([]byte(mergeInputFixture), false)
+		require.NoError(t, err)
+		var stats Stats
+		doc, err := Run(&Options{Packages: []string{pkg}, InputSpec: input, MergeStrategy: strategy, Stats: &stats})
+		return doc, stats, err
+	}
+
+	t.Run("should keep the scanned side of the conflicts with scan-wins", func(t *testing.T) {
+		doc, _, err := run(t, MergeScanWins)
+		require.NoError(t, err)
+		pets := doc.Paths.Paths["/pets"]
+		assert.Equal(t, "listPets", pets.Get.ID)
+		assert.Equal(t, "createPet", pets.Post.ID)
+		assert.Equal(t, "deletePets", pets.Delete.ID, "the methods of the input without conflict")
+		assert.Contains(t, doc.Paths.Paths, "/stores")
+		assert.Contains(t, doc.Definitions["Pet"].Properties, "name")
+		assert.NotContains(t, doc.Definitions["Pet"].Properties, "id", "the scanned definition isn't built over the input one")
+		assert.Contains(t, doc.Definitions, "Store")
+		assert.Equal(t, "X-Scanned-Key", doc.SecurityDefinitions["api_key"].Name)
+		assert.Contains(t, doc.SecurityDefinitions, "basic")
+	})
+
+	t.Run("should keep the input side of the conflicts with input-wins", func(t *testing.T) {
+		doc, _, err := run(t, MergeInputWins)
+		require.NoError(t, err)
+		pets := doc.Paths.Paths["/pets"]
+		assert.Equal(t, "listAllPets", pets.Get.ID)
+		assert.Equal(t, "createPet", pets.Post.ID)
+		assert.Contains(t, doc.Definitions["Pet"].Properties, "id")
+		assert.NotContains(t, doc.Definitions["Pet"].Properties, "name")
+		assert.Equal(t, "X-Input-Key", doc.SecurityDefinitions["api_key"].Name)
+		assert.Contains(t, doc.SecurityDefinitions, "basic")
+	})
+
+	t.Run("should fill the gaps of the input spec", func(t *testing.T) {
+		doc, _, err := run(t, MergeInputWins)
+		require.NoError(t, err)
+		assert.Equal(t, "Pet store", doc.Info.Title)
+		assert.Equal(t, "1.0.0", doc.Info.Version)
+		assert.Equal(t, "The pets of the store, scanned from the Go code.", doc.Info.Description)
+		assert.Equal(t, "scanned.example.com", doc.Host)
+		assert.Equal(t, []string{"https", "http"}, doc.Schemes)
+		assert.Equal(t, []string{"application/json", "application/xml"}, doc.Consumes)
+		require.Len(t, doc.Tags, 1)
+		assert.Equal(t, "The pets of the store.", doc.Tags[0].Description)
+		assert.Equal(t, "https://example.com/pets", doc.Tags[0].ExternalDocs.URL)
+	})
+
+	t.Run("should report every conflict at its Go source with error", func(t *testing.T) {
+		_, stats, err := run(t, MergeError)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrMergeConflict))
+
+		var conflicts []string
+		for _, match := range regexp.MustCompile(`(?m)^\S*[/\\](api\.go:\d+:\d+: .*)$`).FindAllStringSubmatch(err.Error(), -1) {
+			conflicts = append(conflicts, match[1])
+		}
+		assert.Equal(t, []string{
+			"api.go:27:1: operation GET /pets (listPets) is also in the input spec",
+			"api.go:52:6: definition Pet is also in the input spec",
+			"api.go:1:1: security definition api_key is also in the input spec",
+		}, conflicts)
+		assert.NotEmpty(t, stats.MergeEntries, "the entries of the merge")
+	})
+
+	t.Run("should tell where the entries come from", func(t *testing.T) {
+		_, stats, err := run(t, MergeScanWins)
+		require.NoError(t, err)
+		var entries []string
+		for _, entry := range stats.MergeEntries {
+			entries = append(entries, fmt.Sprintf("%s %s: %s %s", entry.Section, entry.Key, entry.Origin, entry.Kept))
+		}
+		assert.Equal(t, []string{
+			"paths GET /pets: both scan",
+			"paths POST /pets: scan ",
+			"paths DELETE /pets: input ",
+			"paths GET /stores: input ",
+			"definitions Pet: both scan",
+			"definitions Store: input ",
+			"parameters limit: input ",
+			"securityDefinitions api_key: both scan",
+			"securityDefinitions basic: scan ",
+		}, entries)
+	})
+
+	t.Run("should build over the input spec without a strategy", func(t *testing.T) {
+		doc, stats, err := run(t, "")
+		require.NoError(t, err)
+		assert.Equal(t, "listPets", doc.Paths.Paths["/pets"].Get.ID)
+		assert.Equal(t, "the pet of the input", doc.Definitions["Pet"].Description, "a discovered type of a definition of the input isn't built")
+		assert.Equal(t, "X-Scanned-Key", doc.SecurityDefinitions["api_key"].Name)
+		assert.Empty(t, stats.MergeEntries)
+	})
+
+	t.Run("should reject an unknown strategy", func(t *testing.T) {
+		_, _, err := run(t, "theirs")
+		require.EqualError(t, err, `unknown merge strategy "theirs", expected scan-wins, input-wins or error`)
+	})
+}
@@ -0,0 +1,35 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"fmt"
+	"go/types"
+)
+
+// callsMarshalJSON tells if encoding/json marshals the values of a named type with its MarshalJSON method at
+// the value being built: always with a value receiver, and with a pointer receiver unless it skips the
+// marshaler, see skipsMarshaler.
+func (s *schemaBuilder) callsMarshalJSON(tpe *types.Named, tgt swaggerTypable) bool {
+	if types.NewMethodSet(tpe).Lookup(nil, "MarshalJSON") != nil {
+		return true
+	}
+	return types.NewMethodSet(types.NewPointer(tpe)).Lookup(nil, "MarshalJSON") != nil && !s.skipsMarshaler(tpe, tgt)
+}
+
+// buildFromJSONMarshal builds a struct marshaled by its MarshalJSON method, whose fields don't tell what it
+// writes, e.g. a Money writing "12.34 USD": as an object of its Go type, with an opaque-marshaler diagnostic
+// suggesting the overrides, i.e. Options.TypeMappings, swagger:strfmt and swagger:type.
+func (s *schemaBuilder) buildFromJSONMarshal(tpe *types.Named, tgt swaggerTypable) error {
+	tgt.Typed("object", "")
+	tgt.AddExtension("x-go-type", namedTypeKey(tpe))
+
+	s.ctx.app.diagnose(Diagnostic{
+		Pos:  s.decl.Pkg.Fset.Position(tpe.Origin().Obj().Pos()),
+		Code: DiagnosticOpaqueMarshaler,
+		Message: fmt.Sprintf("%s marshals with MarshalJSON, documented as an object rather than its fields: map it with TypeMappings, or annotate it swagger:strfmt or swagger:type",
+			namedTypeKey(tpe)),
+	})
+	return nil
+}
@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONMarshalers(t *testing.T) {
+	const pkg = "github.com/3idey/codescan/fixtures/goparsing/jsonmarshalers"
+	var diagnostics []Diagnostic
+	doc, err := Run(&Options{
+		Packages:   []string{pkg},
+		ScanModels: true,
+		Logger:     func(diagnostic Diagnostic) { diagnostics = append(diagnostics, diagnostic) },
+	})
+	require.NoError(t, err)
+	invoice := doc.Definitions["Invoice"].Properties
+	assertOpaque := func(t *testing.T, schema spec.Schema, goType, name string) {
+		t.Helper()
+		assert.Empty(t, schema.Ref.String(), name)
+		assert.Empty(t, schema.Properties, name)
+		assert.Equal(t, spec.StringOrArray{"object"}, schema.Type, name)
+		assert.Equal(t, pkg+"."+goType, schema.Extensions["x-go-type"], name)
+	}
+
+	t.Run("should document the structs marshaled by MarshalJSON as objects", func(t *testing.T) {
+		assertOpaque(t, invoice["total"], "Money", "total")
+		assertOpaque(t, invoice["discount"], "Money", "discount")
+		assertOpaque(t, *invoice["taxes"].AdditionalProperties.Schema, "Money", "taxes")
+		assertOpaque(t, invoice["rate"], "Rate", "rate")
+
+		var opaque []string
+		for _, diagnostic := range diagnostics {
+			if diagnostic.Code == DiagnosticOpaqueMarshaler {
+				opaque = append(opaque, diagnostic.Message)
+			}
+		}
+		assert.Equal(t, []string{
+			pkg + ".Money marshals with MarshalJSON, documented as an object rather than its fields: map it with TypeMappings, or annotate it swagger:strfmt or swagger:type",
+			pkg + ".Rate marshals with MarshalJSON, documented as an object rather than its fields: map it with TypeMappings, or annotate it swagger:strfmt or swagger:type",
+		}, opaque)
+	})
+
+	t.Run("should refer to the fields where the marshaler isn't called", func(t *testing.T) {
+		rates := invoice["rates"].AdditionalProperties.Schema
+		assert.Equal(t, "#/definitions/Rate", rates.Ref.String(), "the values of a map aren't addressable")
+		assert.Contains(t, doc.Definitions["Rate"].Properties, "basis")
+	})
+
+	t.Run("should honor the overrides", func(t *testing.T) {
+		assert.Equal(t, spec.StringOrArray{"string"}, invoice["price"].Type, "swagger:type")
+
+		mapped, err := Run(&Options{
+			Packages:     []string{pkg},
+			ScanModels:   true,
+			TypeMappings: map[string]spec.Schema{pkg + ".Money": *spec.StringProperty().WithPattern(`^\d+\.\d+ [A-Z]{3}$`)},
+		})
+		require.NoError(t, err)
+		props := mapped.Definitions["Invoice"].Properties
+		for _, money := range []spec.Schema{props["total"], props["discount"], *props["taxes"].AdditionalProperties.Schema} {
+			assert.Equal(t, spec.StringOrArray{"string"}, money.Type)
+			assert.Equal(t, `^\d+\.\d+ [A-Z]{3}$`, money.Pattern)
+		}
+	})
+
+	t.Run("should document the fields of interface types", func(t *testing.T) {
+		payload, found := invoice["payload"]
+		require.True(t, found)
+		assert.Empty(t, payload.Type, "an empty schema")
+		shape, notification := invoice["shape"], invoice["notification"]
+		assert.Equal(t, "#/definitions/Shape", shape.Ref.String())
+		assert.Equal(t, "#/definitions/Event", notification.Ref.String(), "the polymorphic base of its implementations")
+	})
+}
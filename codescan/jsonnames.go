@@ -0,0 +1,193 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"fmt"
+	"go/types"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// jsonField is a field of a struct, or promoted from its embedded structs, as seen by encoding/json.
+type jsonField struct {
+	v      *types.Var
+	name   string // JSON name
+	path   string // Go selector from the struct, e.g. "Profile.Contact.Email"
+	depth  int    // embedding depth, 0 for the fields declared by the struct itself
+	tagged bool   // the JSON name comes from the tag
+}
+
+// jsonNameConflict is a JSON name claimed by several fields: encoding/json keeps the dominant field only.
+type jsonNameConflict struct {
+	name    string
+	winner  *jsonField // nil when ambiguous: encoding/json drops all the fields then
+	dropped []jsonField
+}
+
+// ambiguousTags tells if the conflict is between several tagged fields at the same depth, which is
+// more likely a mistake than a deliberate override.
+func (c jsonNameConflict) ambiguousTags() bool {
+	return c.winner == nil && c.dropped[0].tagged
+}
+
+// jsonFields lists the fields of a struct with their JSON name, following encoding/json:
+// exported fields, and the fields of untagged embedded structs, breadth first.
+func jsonFields(st *types.Struct) []jsonField {
+	type level struct {
+		st    *types.Struct
+		path  string
+		depth int
+	}
+
+	var fields []jsonField
+	visited := make(map[*types.Struct]bool)
+	current := []level{{st: st}}
+	for len(current) > 0 {
+		var next []level
+		// a struct embedded several times at the same depth claims its names several times,
+		// so that encoding/json drops them as ambiguous
+		instances := make(map[*types.Struct][]string)
+		for _, lvl := range current {
+			instances[lvl.st] = append(instances[lvl.st], lvl.path)
+		}
+
+		for _, lvl := range current {
+			if visited[lvl.st] {
+				continue
+			}
+			visited[lvl.st] = true
+
+			for i := range lvl.st.NumFields() {
+				fld := lvl.st.Field(i)
+				tag := reflect.StructTag(lvl.st.Tag(i)).Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, _, _ := strings.Cut(tag, ",")
+
+				ftpe := fld.Type()
+				if ptr, isPointer := ftpe.(*types.Pointer); isPointer && fld.Anonymous() {
+					ftpe = ptr.Elem()
+				}
+				embedded, isStruct := ftpe.Underlying().(*types.Struct)
+				if fld.Anonymous() {
+					if !fld.Exported() && !isStruct {
+						continue
+					}
+				} else if !fld.Exported() {
+					continue
+				}
+
+				if name != "" || !fld.Anonymous() || !isStruct {
+					for _, prefix := range instances[lvl.st] {
+						field := jsonField{v: fld, name: name, path: joinFieldPath(prefix, fld.Name()), depth: lvl.depth, tagged: name != ""}
+						if field.name == "" {
+							field.name = fld.Name()
+						}
+						fields = append(fields, field)
+					}
+					continue
+				}
+				next = append(next, level{st: embedded, path: joinFieldPath(lvl.path, fld.Name()), depth: lvl.depth + 1})
+			}
+		}
+		current = next
+	}
+
+	return fields
+}
+
+// jsonNameConflicts resolves the fields of a struct claiming the same JSON name, with the rules of
+// encoding/json: the shallowest field wins, then the tagged one. Several candidates left are ambiguous.
+func jsonNameConflicts(st *types.Struct) []jsonNameConflict {
+	byName := make(map[string][]jsonField)
+	for _, field := range jsonFields(st) {
+		byName[field.name] = append(byName[field.name], field)
+	}
+
+	var conflicts []jsonNameConflict
+	for _, name := range sortedKeys(byName) {
+		fields := byName[name]
+		if len(fields) < 2 {
+			continue
+		}
+		slices.SortStableFunc(fields, func(a, b jsonField) int {
+			if a.depth != b.depth {
+				return a.depth - b.depth
+			}
+			switch {
+			case a.tagged == b.tagged:
+				return 0
+			case a.tagged:
+				return -1
+			default:
+				return 1
+			}
+		})
+
+		conflict := jsonNameConflict{name: name}
+		first, second := fields[0], fields[1]
+		if first.depth == second.depth && first.tagged == second.tagged {
+			conflict.dropped = fields
+		} else {
+			conflict.winner = &fields[0]
+			conflict.dropped = fields[1:]
+		}
+		conflicts = append(conflicts, conflict)
+	}
+
+	return conflicts
+}
+
+// resolveJSONNames finds the fields of a struct model which encoding/json drops because of a conflict of
+// JSON names, and reports them. Ambiguous tags are an error with Options.StrictJSONNames.
+func (s *schemaBuilder) resolveJSONNames(decl *entityDecl, st *types.Struct) (map[*types.Var]bool, error) {
+	conflicts := jsonNameConflicts(st)
+	if len(conflicts) == 0 {
+		return nil, nil
+	}
+
+	typeName := decl.Obj().Name()
+	dropped := make(map[*types.Var]bool)
+	for _, conflict := range conflicts {
+		if conflict.ambiguousTags() && s.ctx.app.strictJSONNames {
+			return nil, fmt.Errorf("%v: fields %s of %s have the same json name %q at the same depth",
+				decl.Pkg.Fset.Position(decl.Obj().Pos()), fieldPaths(conflict.dropped), typeName, conflict.name)
+		}
+
+		for _, field := range conflict.dropped {
+			dropped[field.v] = true
+			message := fmt.Sprintf("field %s.%s is dropped: json name %q is ambiguous between %s, at the same depth",
+				typeName, field.path, conflict.name, fieldPaths(conflict.dropped))
+			if conflict.winner != nil {
+				message = fmt.Sprintf("field %s.%s is dropped: json name %q is taken by %s.%s",
+					typeName, field.path, conflict.name, typeName, conflict.winner.path)
+			}
+			s.ctx.app.diagnose(Diagnostic{
+				Pos:     decl.Pkg.Fset.Position(field.v.Pos()),
+				Code:    DiagnosticJSONNameConflict,
+				Message: message,
+			})
+		}
+	}
+
+	return dropped, nil
+}
+
+func joinFieldPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+func fieldPaths(fields []jsonField) string {
+	paths := make([]string, 0, len(fields))
+	for _, field := range fields {
+		paths = append(paths, field.path)
+	}
+	return strings.Join(paths, " and ")
+}
@@ -0,0 +1,53 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const jsonNamesFixture = "github.com/3idey/codescan/fixtures/goparsing/jsonnames"
+
+func TestJSONNameConflicts(t *testing.T) {
+	var diagnostics []Diagnostic
+	doc, err := Run(&Options{Packages: []string{jsonNamesFixture}, ScanModels: true, Diagnostics: &diagnostics})
+	require.NoError(t, err)
+
+	account := doc.Definitions["Account"]
+	assert.ElementsMatch(t, []string{"id", "name", "Label", "createdBy", "phone"}, sortedKeys(account.Properties))
+
+	id := account.Properties["id"]
+	assert.Equal(t, "string", id.Type[0], "Account.ID wins over Profile.ID")
+	name := account.Properties["name"]
+	assert.Equal(t, "depth 1: wins over Contact.Name", name.Description)
+	label := account.Properties["Label"]
+	assert.Equal(t, "depth 1, tagged: wins over Audit.Label", label.Description)
+	createdBy := account.Properties["createdBy"]
+	assert.Equal(t, "depth 1: wins over Contact.CreatedBy", createdBy.Description)
+
+	messages := make(map[string]bool)
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Code == DiagnosticJSONNameConflict {
+			messages[diagnostic.Message] = true
+		}
+	}
+	assert.Equal(t, map[string]bool{
+		`field Account.Profile.ID is dropped: json name "id" is taken by Account.ID`:                                                                           true,
+		`field Account.Audit.Label is dropped: json name "Label" is taken by Account.Profile.Title`:                                                            true,
+		`field Account.Profile.Contact.Name is dropped: json name "name" is taken by Account.Profile.Name`:                                                     true,
+		`field Account.Profile.Contact.CreatedBy is dropped: json name "createdBy" is taken by Account.Audit.CreatedBy`:                                        true,
+		`field Account.Profile.Contact.Email is dropped: json name "email" is ambiguous between Profile.Contact.Email and Audit.Meta.Email, at the same depth`: true,
+		`field Account.Audit.Meta.Email is dropped: json name "email" is ambiguous between Profile.Contact.Email and Audit.Meta.Email, at the same depth`:      true,
+	}, messages)
+
+	t.Run("ambiguous tags are an error in strict mode", func(t *testing.T) {
+		_, err := Run(&Options{Packages: []string{jsonNamesFixture}, ScanModels: true, StrictJSONNames: true})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `fields Profile.Contact.Email and Audit.Meta.Email of Account have the same json name "email" at the same depth`)
+		assert.Contains(t, err.Error(), "models.go:7:6")
+	})
+}
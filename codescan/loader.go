@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Loader loads the packages of a scan, see Options.Loader. The configuration holds the working directory,
+// the build flags, the overlay and the environment of the options, and the load mode, which the packages
+// must be loaded with: at least PackagesLoadMode for the scan, less for the fingerprints of CacheDir.
+type Loader interface {
+	Load(cfg *packages.Config, patterns ...string) ([]*packages.Package, error)
+}
+
+// LoaderFunc is a function loading packages, e.g. packages.Load, as a Loader.
+type LoaderFunc func(cfg *packages.Config, patterns ...string) ([]*packages.Package, error)
+
+// Load calls the function.
+func (f LoaderFunc) Load(cfg *packages.Config, patterns ...string) ([]*packages.Package, error) {
+	return f(cfg, patterns...)
+}
+
+// DefaultLoader loads the packages with packages.Load, with the go command, or with the driver of the
+// GOPACKAGESDRIVER environment variable, or of Options.PackagesDriver.
+var DefaultLoader Loader = LoaderFunc(packages.Load)
+
+// loader returns the loader of the options.
+func (o *Options) loader() Loader {
+	if o.Loader == nil {
+		return DefaultLoader
+	}
+	return o.Loader
+}
+
+// loadEnv returns the environment of the go command, or of the driver, loading the packages of the options:
+// that of the process with Env and PackagesDriver, nil for that of the process.
+func loadEnv(opts *Options) []string {
+	if len(opts.Env) == 0 && opts.PackagesDriver == "" {
+		return nil
+	}
+	env := append(os.Environ(), opts.Env...)
+	if opts.PackagesDriver != "" {
+		env = append(env, "GOPACKAGESDRIVER="+opts.PackagesDriver)
+	}
+	return env
+}
+
+// checkEnv fails on the variables of Options.Env which aren't KEY=value pairs.
+func checkEnv(env []string) error {
+	for _, kv := range env {
+		if key, _, found := strings.Cut(kv, "="); !found || key == "" {
+			return fmt.Errorf("invalid environment variable %q: expected KEY=value", kv)
+		}
+	}
+	return nil
+}
+
+// loadWith loads packages with the loader of the options, checking that a custom loader loaded them with
+// the mode of the configuration.
+func loadWith(opts *Options, cfg *packages.Config, patterns ...string) ([]*packages.Package, error) {
+	mode := cfg.Mode
+	pkgs, err := opts.loader().Load(cfg, patterns...)
+	if err != nil || opts.Loader == nil || mode != pkgLoadMode {
+		return pkgs, err
+	}
+	if err := checkLoadMode(pkgs); err != nil {
+		return nil, fmt.Errorf("the Loader: %w", err)
+	}
+	return pkgs, nil
+}
@@ -0,0 +1,101 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/packages"
+)
+
+const driverFixture = "github.com/3idey/codescan/fixtures/goparsing/packagesdriver"
+
+// buildFakeDriver builds the fake GOPACKAGESDRIVER of the fixtures.
+func buildFakeDriver(t *testing.T) string {
+	t.Helper()
+	driver := filepath.Join(t.TempDir(), "driver")
+	out, err := exec.Command("go", "build", "-o", driver, driverFixture+"/driver").CombinedOutput()
+	require.NoError(t, err, "can't build the fake driver: %s", out)
+	return driver
+}
+
+func TestPackagesDriver(t *testing.T) {
+	driver := buildFakeDriver(t)
+	driverLog := filepath.Join(t.TempDir(), "driver.log")
+
+	t.Run("should load the packages with the driver and its environment", func(t *testing.T) {
+		doc, err := Run(&Options{
+			Packages:       []string{driverFixture + "/api"},
+			ScanModels:     true,
+			PackagesDriver: driver,
+			Env:            []string{"FAKE_DRIVER_LOG=" + driverLog},
+		})
+		require.NoError(t, err)
+		require.Contains(t, doc.Definitions, "Pet")
+		assert.Contains(t, doc.Definitions["Pet"].Properties, "name")
+
+		logged, err := os.ReadFile(driverLog)
+		require.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(string(logged)), "\n")
+		require.Len(t, lines, 1)
+		assert.Equal(t, driverFixture+"/api "+PackagesLoadMode.String(), lines[0])
+	})
+
+	t.Run("should load the packages with the go command with GOPACKAGESDRIVER=off", func(t *testing.T) {
+		require.NoError(t, os.Remove(driverLog))
+		_, err := Run(&Options{
+			Packages:       []string{driverFixture + "/api"},
+			ScanModels:     true,
+			PackagesDriver: "off",
+			Env:            []string{"FAKE_DRIVER_LOG=" + driverLog},
+		})
+		require.NoError(t, err)
+		assert.NoFileExists(t, driverLog)
+	})
+}
+
+func TestLoader(t *testing.T) {
+	var loaded []string
+	var env []string
+	loader := LoaderFunc(func(cfg *packages.Config, patterns ...string) ([]*packages.Package, error) {
+		loaded = append(loaded, patterns...)
+		env = cfg.Env
+		assert.Equal(t, PackagesLoadMode, cfg.Mode)
+		return packages.Load(cfg, patterns...)
+	})
+
+	doc, err := Run(&Options{
+		Packages:   []string{driverFixture + "/api"},
+		ScanModels: true,
+		Loader:     loader,
+		Env:        []string{"CODESCAN_LOADER=test"},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, doc.Definitions, "Pet")
+	assert.Equal(t, []string{driverFixture + "/api"}, loaded)
+	assert.Contains(t, env, "CODESCAN_LOADER=test")
+
+	t.Run("should fail on the packages loaded without the mode of the scan", func(t *testing.T) {
+		_, err := Run(&Options{
+			Packages: []string{driverFixture + "/api"},
+			Loader: LoaderFunc(func(cfg *packages.Config, patterns ...string) ([]*packages.Package, error) {
+				cfg.Mode = packages.NeedName | packages.NeedFiles
+				return packages.Load(cfg, patterns...)
+			}),
+		})
+		require.ErrorContains(t, err, "the Loader: the packages are loaded without the mode bits")
+	})
+
+	t.Run("should reject the variables which aren't pairs", func(t *testing.T) {
+		_, err := Run(&Options{Packages: []string{driverFixture + "/api"}, Env: []string{"GOFLAGS"}})
+		require.EqualError(t, err, `invalid environment variable "GOFLAGS": expected KEY=value`)
+		require.ErrorContains(t, (&Options{Env: []string{"=off"}}).Validate(), `invalid environment variable "=off"`)
+	})
+}
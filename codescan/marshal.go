@@ -0,0 +1,391 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Encoder writes a spec (or any part of it) to a stream as it is encoded, rather than into a byte slice,
+// with the key order of MarshalJSON. See NewJSONEncoder and NewYAMLEncoder.
+type Encoder interface {
+	Encode(doc any) error
+}
+
+// NewJSONEncoder returns an Encoder writing JSON to w, indented unless compact. The output is the one of
+// MarshalJSON, without a trailing newline.
+func NewJSONEncoder(w io.Writer, compact bool) Encoder {
+	return &jsonEncoder{w: bufio.NewWriter(w), compact: compact}
+}
+
+// NewYAMLEncoder returns an Encoder writing YAML to w, one document per call to Encode. The output is the
+// one of MarshalYAML.
+func NewYAMLEncoder(w io.Writer) Encoder {
+	return yamlEncoder{w: w}
+}
+
+// MarshalJSON marshals a spec (or any part of it) to JSON.
+//
+// Keys are emitted in the order of the standard JSON marshaling of the spec, except for
+// the members of "properties" objects carrying an x-order extension (see Options.DeclarationOrder):
+// these are emitted in increasing x-order.
+func MarshalJSON(doc any, compact bool) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := NewJSONEncoder(&buf, compact).Encode(doc); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// MarshalYAML marshals a spec (or any part of it) to YAML, with the same key order as MarshalJSON.
+func MarshalYAML(doc any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := NewYAMLEncoder(&buf).Encode(doc); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+type jsonEncoder struct {
+	w       *bufio.Writer
+	compact bool
+}
+
+func (e *jsonEncoder) Encode(doc any) error {
+	tree, err := orderedTree(doc)
+	if err != nil {
+		return err
+	}
+	if err := e.encode(tree, 0); err != nil {
+		return err
+	}
+
+	return e.w.Flush()
+}
+
+// encode writes a value of the ordered tree, formatted like json.MarshalIndent with a two-space indent.
+func (e *jsonEncoder) encode(value any, depth int) error {
+	switch v := value.(type) {
+	case orderedObject:
+		if len(v) == 0 {
+			_, err := e.w.WriteString("{}")
+			return err
+		}
+		e.w.WriteByte('{')
+		for i, member := range v {
+			if i > 0 {
+				e.w.WriteByte(',')
+			}
+			e.newline(depth + 1)
+			if err := e.encode(member.Key, depth+1); err != nil {
+				return err
+			}
+			e.w.WriteByte(':')
+			if !e.compact {
+				e.w.WriteByte(' ')
+			}
+			if err := e.encode(member.Value, depth+1); err != nil {
+				return err
+			}
+		}
+		e.newline(depth)
+		return e.w.WriteByte('}')
+	case []any:
+		if len(v) == 0 {
+			_, err := e.w.WriteString("[]")
+			return err
+		}
+		e.w.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				e.w.WriteByte(',')
+			}
+			e.newline(depth + 1)
+			if err := e.encode(item, depth+1); err != nil {
+				return err
+			}
+		}
+		e.newline(depth)
+		return e.w.WriteByte(']')
+	case json.Number:
+		_, err := e.w.WriteString(plainNumber(v))
+		return err
+	default:
+		scalar, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = e.w.Write(scalar)
+		return err
+	}
+}
+
+func (e *jsonEncoder) newline(depth int) {
+	if e.compact {
+		return
+	}
+	e.w.WriteByte('\n')
+	for range depth {
+		e.w.WriteString("  ")
+	}
+}
+
+type yamlEncoder struct {
+	w io.Writer
+}
+
+func (e yamlEncoder) Encode(doc any) error {
+	tree, err := orderedTree(doc)
+	if err != nil {
+		return err
+	}
+
+	enc := yaml.NewEncoder(e.w)
+	enc.SetIndent(2)
+	if err := enc.Encode(yamlNode(tree)); err != nil {
+		return err
+	}
+
+	return enc.Close()
+}
+
+// orderedObject is a JSON object which retains the order of its members.
+type orderedObject []orderedMember
+
+type orderedMember struct {
+	Key   string
+	Value any
+}
+
+func (o orderedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, member := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(member.Key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		value, err := json.Marshal(member.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+func orderedTree(doc any) (any, error) {
+	jazon, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(jazon))
+	dec.UseNumber()
+	tree, err := decodeOrdered(dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, errors.New("unexpected trailing data after JSON document")
+	}
+
+	return orderByExtension(tree, ""), nil
+}
+
+func decodeOrdered(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch delim := tok.(type) {
+	case json.Delim:
+		switch delim {
+		case '{':
+			obj := orderedObject{}
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				key, ok := keyTok.(string)
+				if !ok {
+					return nil, fmt.Errorf("unexpected object key %v", keyTok)
+				}
+				value, err := decodeOrdered(dec)
+				if err != nil {
+					return nil, err
+				}
+				obj = append(obj, orderedMember{Key: key, Value: value})
+			}
+			_, err := dec.Token() // closing brace
+
+			return obj, err
+		case '[':
+			arr := []any{}
+			for dec.More() {
+				value, err := decodeOrdered(dec)
+				if err != nil {
+					return nil, err
+				}
+				arr = append(arr, value)
+			}
+			_, err := dec.Token() // closing bracket
+
+			return arr, err
+		default:
+			return nil, fmt.Errorf("unexpected delimiter %v", delim)
+		}
+	default:
+		return tok, nil
+	}
+}
+
+// orderByExtension sorts the members of "properties" objects by their x-order extension, and the scopes of
+// the security definitions by their x-scope-order, see sortScopes.
+// Members without x-order come last, in their original order.
+func orderByExtension(value any, key string) any {
+	switch v := value.(type) {
+	case orderedObject:
+		for i := range v {
+			v[i].Value = orderByExtension(v[i].Value, v[i].Key)
+		}
+		if key == "properties" {
+			slices.SortStableFunc(v, func(a, b orderedMember) int {
+				ao, aok := xOrder(a.Value)
+				bo, bok := xOrder(b.Value)
+				switch {
+				case aok && bok:
+					return cmp.Compare(ao, bo)
+				case aok:
+					return -1
+				case bok:
+					return 1
+				default:
+					return 0
+				}
+			})
+		} else {
+			v = sortScopes(v)
+		}
+		return v
+	case []any:
+		for i := range v {
+			v[i] = orderByExtension(v[i], "")
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+func xOrder(value any) (float64, bool) {
+	obj, ok := value.(orderedObject)
+	if !ok {
+		return 0, false
+	}
+	for _, member := range obj {
+		if member.Key != "x-order" {
+			continue
+		}
+		num, isNumber := member.Value.(json.Number)
+		if !isNumber {
+			return 0, false
+		}
+		f, err := num.Float64()
+		return f, err == nil
+	}
+
+	return 0, false
+}
+
+func yamlNode(value any) *yaml.Node {
+	switch v := value.(type) {
+	case orderedObject:
+		node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for _, member := range v {
+			node.Content = append(node.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: member.Key},
+				yamlNode(member.Value),
+			)
+		}
+		return node
+	case []any:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, item := range v {
+			node.Content = append(node.Content, yamlNode(item))
+		}
+		return node
+	case string:
+		node := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v}
+		if strings.HasPrefix(v, "\t") || strings.HasPrefix(v, "\n") {
+			// yaml.v3 writes these as literal blocks which don't read back, e.g. a description starting
+			// with a code block indented by a tab
+			node.Style = yaml.DoubleQuotedStyle
+		}
+		return node
+	case json.Number:
+		text := plainNumber(v)
+		tag := "!!float"
+		if _, err := strconv.ParseInt(text, 10, 64); err == nil {
+			tag = "!!int"
+		} else if _, err := strconv.ParseUint(text, 10, 64); err == nil {
+			tag = "!!int"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: text}
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: fmt.Sprint(v)}
+	default:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+	}
+}
+
+// maxPlainExponent bounds the exponents written out by plainNumber, beyond those of the float64 numbers.
+const maxPlainExponent = 400
+
+// plainNumber returns the text of a number without exponent, e.g. 1000000000000000000000 for 1e+21 and
+// 0.0000001 for 1e-07, which some consumers reject, so that the JSON and YAML outputs write the same digits.
+// The number is converted exactly, never through a float64.
+func plainNumber(number json.Number) string {
+	text := number.String()
+	mantissa, exponent, scientific := strings.Cut(strings.ToLower(text), "e")
+	if !scientific {
+		return text
+	}
+	exp, err := strconv.Atoi(exponent)
+	if err != nil || exp > maxPlainExponent || exp < -maxPlainExponent {
+		return text
+	}
+	value, ok := new(big.Rat).SetString(text)
+	if !ok {
+		return text
+	}
+	if value.IsInt() {
+		return value.Num().String()
+	}
+	_, fraction, _ := strings.Cut(mantissa, ".")
+	return value.FloatString(max(len(fraction)-exp, 0))
+}
@@ -0,0 +1,236 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-openapi/spec"
+	"gopkg.in/yaml.v3"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	doc := &spec.Swagger{
+		SwaggerProps: spec.SwaggerProps{
+			Swagger: "2.0",
+			Definitions: spec.Definitions{
+				"Pet": {
+					SchemaProps: spec.SchemaProps{
+						Properties: spec.SchemaProperties{
+							"name":  {VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{"x-order": 1}}},
+							"id":    {VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{"x-order": 0}}},
+							"extra": {},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	compact, err := MarshalJSON(doc, true)
+	require.NoError(t, err)
+	assert.Contains(t, string(compact), `"properties":{"id":{"x-order":0},"name":{"x-order":1},"extra":{}}`)
+
+	indented, err := MarshalJSON(doc, false)
+	require.NoError(t, err)
+	reference, err := json.Marshal(doc)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(reference), string(indented))
+}
+
+func TestMarshalYAML(t *testing.T) {
+	doc := &spec.Swagger{
+		SwaggerProps: spec.SwaggerProps{
+			Swagger: "2.0",
+			Info: &spec.Info{
+				InfoProps: spec.InfoProps{Title: "Pets", Version: "1.0"},
+			},
+			Paths: &spec.Paths{},
+		},
+	}
+
+	yml, err := MarshalYAML(doc)
+	require.NoError(t, err)
+	assert.Equal(t, `swagger: "2.0"
+info:
+  title: Pets
+  version: "1.0"
+paths: {}
+`, string(yml))
+
+	t.Run("should read back the descriptions indented by tabs", func(t *testing.T) {
+		for _, description := range []string{"\tcode\nafter", "\n\tcode", "text\n\n\tcode\n", "\tcode"} {
+			yml, err := MarshalYAML(map[string]string{"description": description})
+			require.NoError(t, err)
+			var back map[string]string
+			require.NoError(t, yaml.Unmarshal(yml, &back), description)
+			assert.Equal(t, description, back["description"])
+		}
+	})
+}
+
+func TestJSONEncoder(t *testing.T) {
+	doc, err := Run(&Options{Packages: []string{"github.com/3idey/codescan/fixtures/goparsing/petstore/..."}, ScanModels: true})
+	require.NoError(t, err)
+	tree, err := orderedTree(doc)
+	require.NoError(t, err)
+
+	t.Run("should stream the output of json.MarshalIndent", func(t *testing.T) {
+		reference, err := json.MarshalIndent(tree, "", "  ")
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, NewJSONEncoder(&buf, false).Encode(doc))
+		assert.Equal(t, string(reference), buf.String())
+	})
+
+	t.Run("should stream the output of json.Marshal", func(t *testing.T) {
+		reference, err := json.Marshal(tree)
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, NewJSONEncoder(&buf, true).Encode(doc))
+		assert.Equal(t, string(reference), buf.String())
+	})
+
+	t.Run("should report write errors", func(t *testing.T) {
+		require.ErrorIs(t, NewJSONEncoder(failingWriter{}, false).Encode(doc), errWrite)
+		require.ErrorContains(t, NewYAMLEncoder(failingWriter{}).Encode(doc), errWrite.Error())
+	})
+}
+
+func TestMarshalNumbers(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{name: "MaxInt64", value: int64(math.MaxInt64), want: "9223372036854775807"},
+		{name: "MinInt64", value: int64(math.MinInt64), want: "-9223372036854775808"},
+		{name: "MaxUint64", value: uint64(math.MaxUint64), want: "18446744073709551615"},
+		{name: "2^53-1", value: int64(1<<53 - 1), want: "9007199254740991"},
+		{name: "2^53+1", value: int64(1<<53 + 1), want: "9007199254740993"},
+		{name: "large float", value: 1e21, want: "1000000000000000000000"},
+		{name: "small float", value: 1e-7, want: "0.0000001"},
+		{name: "negative small float", value: -2.5e-10, want: "-0.00000000025"},
+		{name: "smallest float", value: math.SmallestNonzeroFloat64, want: "0." + strings.Repeat("0", 323) + "5"},
+		{name: "fraction", value: 0.1, want: "0.1"},
+		{name: "number", value: json.Number("1.5E+3"), want: "1500"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := map[string]any{"example": tc.value}
+			for _, compact := range []bool{false, true} {
+				jazon, err := MarshalJSON(doc, compact)
+				require.NoError(t, err)
+				var back map[string]json.Number
+				require.NoError(t, decodeExample(jazon, &back))
+				assert.Equal(t, tc.want, back["example"].String(), "compact: %v", compact)
+			}
+
+			yml, err := MarshalYAML(doc)
+			require.NoError(t, err)
+			assert.Equal(t, "example: "+tc.want+"\n", string(yml), "the YAML writes the digits of the JSON, without a tag")
+		})
+	}
+
+	t.Run("should read back the integers exactly", func(t *testing.T) {
+		doc := map[string]any{"max": int64(math.MaxInt64), "min": int64(math.MinInt64), "unsigned": uint64(math.MaxUint64)}
+		yml, err := MarshalYAML(doc)
+		require.NoError(t, err)
+		var back struct {
+			Max      int64  `yaml:"max"`
+			Min      int64  `yaml:"min"`
+			Unsigned uint64 `yaml:"unsigned"`
+		}
+		require.NoError(t, yaml.Unmarshal(yml, &back))
+		assert.Equal(t, int64(math.MaxInt64), back.Max)
+		assert.Equal(t, int64(math.MinInt64), back.Min)
+		assert.Equal(t, uint64(math.MaxUint64), back.Unsigned)
+	})
+
+	t.Run("should keep the numbers of the annotations exactly", func(t *testing.T) {
+		const pkg = "github.com/3idey/codescan/fixtures/goparsing/numbers"
+		doc, err := Run(&Options{Packages: []string{pkg}, ScanModels: true})
+		require.NoError(t, err)
+		doc3, err := Run3(&Options{Packages: []string{pkg}, ScanModels: true})
+		require.NoError(t, err)
+		record := doc.Definitions["Record"]
+		assert.Equal(t, 9007199254740993, record.Properties["id"].Default)
+		assert.Equal(t, uint64(math.MaxUint64), record.Properties["checksum"].Default)
+		assert.Equal(t, []any{int64(9007199254740993), int64(math.MaxInt64)}, record.Properties["sequence"].Enum)
+
+		for _, output := range []any{doc, doc3} {
+			for _, compact := range []bool{false, true} {
+				jazon, err := MarshalJSON(output, compact)
+				require.NoError(t, err)
+				yml, err := MarshalYAML(output)
+				require.NoError(t, err)
+				for _, number := range []string{"9007199254740993", "-9007199254740993", "9223372036854775807", "18446744073709551615", "1000000000000000000000", "0.0000001"} {
+					assert.Contains(t, string(jazon), number)
+					assert.Contains(t, string(yml), " "+number+"\n")
+				}
+				assert.NotContains(t, string(jazon), "e+")
+				assert.NotContains(t, string(jazon), "e-")
+			}
+		}
+	})
+}
+
+var errWrite = errors.New("disk full")
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errWrite
+}
+
+// largeSpec builds a spec with many definitions, standing for the expanded specs of large APIs.
+func largeSpec(definitions int) *spec.Swagger {
+	doc := &spec.Swagger{SwaggerProps: spec.SwaggerProps{Swagger: "2.0", Definitions: spec.Definitions{}}}
+	for i := range definitions {
+		schema := spec.Schema{SchemaProps: spec.SchemaProps{Properties: spec.SchemaProperties{}}}
+		for j := range 20 {
+			property := *spec.StringProperty()
+			property.Description = fmt.Sprintf("the property %d of the model %d, with a long enough description", j, i)
+			schema.Properties[fmt.Sprintf("property%d", j)] = property
+		}
+		doc.Definitions[fmt.Sprintf("Model%d", i)] = schema
+	}
+	return doc
+}
+
+// The encoders allocate less than MarshalJSON, and hold less at once: the output is never held in memory.
+func BenchmarkMarshalJSON(b *testing.B) {
+	doc := largeSpec(2000)
+	b.ReportAllocs()
+	for b.Loop() {
+		output, err := MarshalJSON(doc, false)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := io.Discard.Write(output); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkJSONEncoder(b *testing.B) {
+	doc := largeSpec(2000)
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := NewJSONEncoder(io.Discard, false).Encode(doc); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
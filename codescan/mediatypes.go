@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"log"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// mediaTypeShorthands are the shorthands accepted by Consumes and Produces, e.g. "Consumes: json".
+var mediaTypeShorthands = map[string]string{
+	"json":      "application/json",
+	"yaml":      "application/yaml",
+	"xml":       "application/xml",
+	"form":      "application/x-www-form-urlencoded",
+	"multipart": "multipart/form-data",
+}
+
+// knownSchemes are the transfer protocols of swagger 2.0.
+var knownSchemes = []string{"http", "https", "ws", "wss"}
+
+// rxMediaType matches a media type with the syntax of RFC 6838, e.g. "application/vnd.api+json; charset=utf-8".
+var rxMediaType = regexp.MustCompile(`^` + rxRestrictedName + `/` + rxRestrictedName + `(?:\p{Zs}*;\p{Zs}*[^;=\p{Zs}]+=[^;]+)*$`)
+
+const rxRestrictedName = `[A-Za-z0-9][A-Za-z0-9!#$&^_.+-]{0,126}`
+
+// normalizeMediaTypes normalizes the media types of Consumes or Produces, at the meta, route or operation
+// level: values may be given on several lines or separated by commas, shorthands are expanded, and duplicates
+// are dropped, disregarding the case. Unknown shorthands and invalid media types are kept, with a warning.
+func normalizeMediaTypes(values []string, subject string) []string {
+	var normalized []string
+	for _, value := range splitListValues(values) {
+		if mediaType, ok := mediaTypeShorthands[strings.ToLower(value)]; ok {
+			value = mediaType
+		} else if !strings.Contains(value, "/") {
+			log.Printf("WARNING: unknown media type shorthand %q in %s, expected one of %s or a media type",
+				value, subject, strings.Join(sortedKeys(mediaTypeShorthands), ", "))
+		} else if !rxMediaType.MatchString(value) {
+			log.Printf("WARNING: invalid media type %q in %s", value, subject)
+		}
+		normalized = appendFold(normalized, value)
+	}
+	return normalized
+}
+
+// normalizeSchemes normalizes the schemes of the meta, route or operation level to lower case, dropping the
+// duplicates. Unknown schemes are kept, with a warning.
+func normalizeSchemes(values []string, subject string) []string {
+	var normalized []string
+	for _, value := range splitListValues(values) {
+		value = strings.ToLower(value)
+		if !slices.Contains(knownSchemes, value) {
+			log.Printf("WARNING: unknown scheme %q in %s, expected one of %s", value, subject, strings.Join(knownSchemes, ", "))
+		}
+		normalized = appendFold(normalized, value)
+	}
+	return normalized
+}
+
+// splitListValues splits the values of a list given on several lines, as YAML items, or separated by commas.
+func splitListValues(values []string) []string {
+	var result []string
+	for _, value := range values {
+		for item := range strings.SplitSeq(value, ",") {
+			item = strings.TrimSpace(item)
+			item = strings.TrimSpace(strings.TrimPrefix(item, "- "))
+			if item != "" && item != "-" {
+				result = append(result, item)
+			}
+		}
+	}
+	return result
+}
+
+func appendFold(values []string, value string) []string {
+	if slices.ContainsFunc(values, func(known string) bool { return strings.EqualFold(known, value) }) {
+		return values
+	}
+	return append(values, value)
+}
+
+// setMediaTypes parses the Consumes or Produces section of a meta or route annotation.
+type setMediaTypes struct {
+	set     func([]string)
+	rx      *regexp.Regexp
+	subject string
+}
+
+func newSetMediaTypes(rx *regexp.Regexp, subject string, set func([]string)) *setMediaTypes {
+	return &setMediaTypes{set: set, rx: rx, subject: subject}
+}
+
+func (sm *setMediaTypes) Matches(line string) bool {
+	return sm.rx.MatchString(line)
+}
+
+func (sm *setMediaTypes) inlineValue(line string) string {
+	if loc := sm.rx.FindStringIndex(line); loc != nil {
+		return line[loc[1]:]
+	}
+	return ""
+}
+
+func (sm *setMediaTypes) Parse(lines []string) error {
+	sm.set(normalizeMediaTypes(lines, sm.subject))
+	return nil
+}
@@ -0,0 +1,51 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMediaTypes(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	doc, err := Run(&Options{Packages: []string{"github.com/3idey/codescan/fixtures/goparsing/mediatypes"}})
+	require.NoError(t, err)
+
+	t.Run("should normalize the meta", func(t *testing.T) {
+		assert.Equal(t, []string{"https", "http"}, doc.Schemes)
+		assert.Equal(t, []string{"application/json", "application/yaml"}, doc.Consumes)
+		assert.Equal(t, []string{"application/xml", "application/x-www-form-urlencoded", "multipart/form-data"}, doc.Produces)
+	})
+
+	t.Run("should normalize the routes", func(t *testing.T) {
+		op := doc.Paths.Paths["/uploads"].Post
+		assert.Equal(t, []string{"multipart/form-data", "application/x-www-form-urlencoded"}, op.Consumes)
+		assert.Equal(t, []string{"application/json", "application/yaml", "application/xml"}, op.Produces)
+		assert.Equal(t, []string{"wss", "ws"}, op.Schemes)
+	})
+
+	t.Run("should normalize the operations", func(t *testing.T) {
+		op := doc.Paths.Paths["/uploads/{id}"].Put
+		assert.Equal(t, []string{"application/x-www-form-urlencoded", "multipart/form-data", "application/json"}, op.Consumes)
+		assert.Equal(t, []string{"application/yaml", "application/xml"}, op.Produces)
+		assert.Equal(t, []string{"http", "https"}, op.Schemes)
+	})
+
+	t.Run("should keep the unknown media types with a warning", func(t *testing.T) {
+		op := doc.Paths.Paths["/uploads"].Get
+		assert.Equal(t, []string{"jsn", "application/vnd.api+json; charset=utf-8", "application/"}, op.Produces)
+		assert.Contains(t, logs.String(), `unknown media type shorthand "jsn" in the Produces of route listUploads`)
+		assert.Contains(t, logs.String(), `invalid media type "application/" in the Produces of route listUploads`)
+		assert.NotContains(t, logs.String(), "charset")
+	})
+}
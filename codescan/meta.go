@@ -5,8 +5,8 @@ package codescan
 
 import (
 	"encoding/json"
-	"fmt"
 	"go/ast"
+	"go/token"
 	"net/mail"
 	"regexp"
 	"strings"
@@ -16,6 +16,7 @@ import (
 
 type metaSection struct {
 	Comments *ast.CommentGroup
+	fset     *token.FileSet
 }
 
 func metaTOSSetter(meta *spec.Info) func([]string) {
@@ -47,43 +48,20 @@ func metaSecurityDefinitionsSetter(meta *spec.Swagger) func(json.RawMessage) err
 		if err != nil {
 			return err
 		}
+		for name, scheme := range jsonData {
+			mergeScopes(scheme, meta.SecurityDefinitions[name])
+		}
 		meta.SecurityDefinitions = jsonData
 		return nil
 	}
 }
 
-func metaVendorExtensibleSetter(meta *spec.Swagger) func(json.RawMessage) error {
-	return func(jsonValue json.RawMessage) error {
-		var jsonData spec.Extensions
-		err := json.Unmarshal(jsonValue, &jsonData)
-		if err != nil {
-			return err
-		}
-		for k := range jsonData {
-			if !rxAllowedExtensions.MatchString(k) {
-				return fmt.Errorf("invalid schema extension name, should start from `x-`: %s", k)
-			}
-		}
-		meta.Extensions = jsonData
-		return nil
-	}
+func metaVendorExtensibleSetter(meta *spec.Swagger) func(spec.Extensions) {
+	return func(extensions spec.Extensions) { meta.Extensions = extensions }
 }
 
-func infoVendorExtensibleSetter(meta *spec.Swagger) func(json.RawMessage) error {
-	return func(jsonValue json.RawMessage) error {
-		var jsonData spec.Extensions
-		err := json.Unmarshal(jsonValue, &jsonData)
-		if err != nil {
-			return err
-		}
-		for k := range jsonData {
-			if !rxAllowedExtensions.MatchString(k) {
-				return fmt.Errorf("invalid schema extension name, should start from `x-`: %s", k)
-			}
-		}
-		meta.Info.Extensions = jsonData
-		return nil
-	}
+func infoVendorExtensibleSetter(meta *spec.Swagger) func(spec.Extensions) {
+	return func(extensions spec.Extensions) { meta.Info.Extensions = extensions }
 }
 
 func newMetaParser(swspec *spec.Swagger) *sectionedParser {
@@ -102,18 +80,19 @@ func newMetaParser(swspec *spec.Swagger) *sectionedParser {
 	sp.setDescription = func(lines []string) { info.Description = joinDropLast(lines) }
 	sp.taggers = []tagParser{
 		newMultiLineTagParser("TOS", newMultilineDropEmptyParser(rxTOS, metaTOSSetter(info)), false),
-		newMultiLineTagParser("Consumes", newMultilineDropEmptyParser(rxConsumes, metaConsumesSetter(swspec)), false),
-		newMultiLineTagParser("Produces", newMultilineDropEmptyParser(rxProduces, metaProducesSetter(swspec)), false),
-		newSingleLineTagParser("Schemes", newSetSchemes(metaSchemeSetter(swspec))),
+		newMultiLineTagParser("Consumes", newSetMediaTypes(rxConsumes, "the Consumes of swagger:meta", metaConsumesSetter(swspec)), false),
+		newMultiLineTagParser("Produces", newSetMediaTypes(rxProduces, "the Produces of swagger:meta", metaProducesSetter(swspec)), false),
+		newSingleLineTagParser("Schemes", newSetSchemes("the Schemes of swagger:meta", metaSchemeSetter(swspec))),
 		newMultiLineTagParser("Security", newSetSecurity(rxSecuritySchemes, metaSecuritySetter(swspec)), false),
-		newMultiLineTagParser("SecurityDefinitions", newYamlParser(rxSecurity, metaSecurityDefinitionsSetter(swspec)), true),
+		newMultiLineTagParser("SecurityDefinitions", &yamlParser{set: metaSecurityDefinitionsSetter(swspec), rx: rxSecurity, prepare: orderScopes}, true),
 		newSingleLineTagParser("Version", &setMetaSingle{swspec, rxVersion, setInfoVersion}),
 		newSingleLineTagParser("Host", &setMetaSingle{swspec, rxHost, setSwaggerHost}),
 		newSingleLineTagParser("BasePath", &setMetaSingle{swspec, rxBasePath, setSwaggerBasePath}),
 		newSingleLineTagParser("Contact", &setMetaSingle{swspec, rxContact, setInfoContact}),
 		newSingleLineTagParser("License", &setMetaSingle{swspec, rxLicense, setInfoLicense}),
-		newMultiLineTagParser("YAMLInfoExtensionsBlock", newYamlParser(rxInfoExtensions, infoVendorExtensibleSetter(swspec)), true),
-		newMultiLineTagParser("YAMLExtensionsBlock", newYamlParser(rxExtensions, metaVendorExtensibleSetter(swspec)), true),
+		newSingleLineTagParser("ExternalDocs", &setExternalDocs{func(docs *spec.ExternalDocumentation) { swspec.ExternalDocs = docs }}),
+		newMultiLineTagParser("YAMLInfoExtensionsBlock", newSetExtensionsBlock(rxInfoExtensions, infoVendorExtensibleSetter(swspec)), true),
+		newMultiLineTagParser("YAMLExtensionsBlock", newSetExtensionsBlock(rxExtensions, metaVendorExtensibleSetter(swspec)), true),
 	}
 	return sp
 }
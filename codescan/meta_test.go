@@ -96,6 +96,36 @@ func verifyMeta(t *testing.T, doc *spec.Swagger) {
 				"bla2": "foo2",
 			},
 		},
+		VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{scopeOrderExtension: []any{"bla1", "bla2"}}},
+	}
+	expectedSecuritySchemaOrders := spec.SecurityScheme{
+		SecuritySchemeProps: spec.SecuritySchemeProps{
+			Type:             "oauth2",
+			AuthorizationURL: "/oauth/auth",
+			TokenURL:         "/oauth/token",
+			Flow:             "accessCode",
+			Scopes: map[string]string{
+				"read":        "read the orders",
+				"write":       "write the orders",
+				"orders:read": "read the orders",
+				"https://www.googleapis.com/auth/userinfo.email": "see the email address of the user",
+			},
+		},
+		VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{scopeOrderExtension: []any{
+			"read", "write", "orders:read", "https://www.googleapis.com/auth/userinfo.email",
+		}}},
+	}
+	expectedSecuritySchemaPetstore := spec.SecurityScheme{
+		SecuritySchemeProps: spec.SecuritySchemeProps{
+			Type:             "oauth2",
+			AuthorizationURL: "/petstore/auth",
+			Flow:             "implicit",
+			Scopes: map[string]string{
+				"read:pets":  "read the pets",
+				"write:pets": "modify the pets",
+			},
+		},
+		VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{scopeOrderExtension: []any{"read:pets", "write:pets"}}},
 	}
 	expectedExtensions := spec.Extensions{
 		"x-meta-array": []any{
@@ -125,7 +155,12 @@ func verifyMeta(t *testing.T, doc *spec.Swagger) {
 	}
 	assert.NotNil(t, doc.SecurityDefinitions["api_key"])
 	assert.NotNil(t, doc.SecurityDefinitions["oauth2"])
-	assert.Equal(t, spec.SecurityDefinitions{"api_key": &expectedSecuritySchemaKey, "oauth2": &expectedSecuritySchemaOAuth}, doc.SecurityDefinitions)
+	assert.Equal(t, spec.SecurityDefinitions{
+		"api_key":       &expectedSecuritySchemaKey,
+		"oauth2":        &expectedSecuritySchemaOAuth,
+		"oauth":         &expectedSecuritySchemaOrders,
+		"petstore_auth": &expectedSecuritySchemaPetstore,
+	}, doc.SecurityDefinitions)
 	assert.Equal(t, expectedExtensions, doc.Extensions)
 	assert.Equal(t, expectedInfoExtensions, doc.Info.Extensions)
 	assert.Equal(t, "localhost", doc.Host)
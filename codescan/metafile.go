@@ -0,0 +1,425 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/go-openapi/spec"
+)
+
+// MetaFileError reports a problem found at some position of a meta file.
+type MetaFileError struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e *MetaFileError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Message)
+}
+
+// ParseMeta parses a YAML (or JSON) document holding the same meta information as a swagger:meta block.
+//
+// Supported keys are info, host, basePath, schemes, consumes, produces, security, securityDefinitions,
+// tags, externalDocs and vendor extensions. The document is validated before being converted:
+// the returned error joins one *MetaFileError per problem found.
+func ParseMeta(src []byte) (*spec.Swagger, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(src, &doc); err != nil {
+		return nil, fmt.Errorf("invalid meta document: %w", err)
+	}
+
+	swspec := new(spec.Swagger)
+	if doc.Kind == 0 || len(doc.Content) == 0 {
+		return swspec, nil
+	}
+	root := doc.Content[0]
+
+	v := new(metaValidator)
+	v.validateRoot(root)
+	if len(v.errs) > 0 {
+		return nil, errors.Join(v.errs...)
+	}
+
+	if root.Kind == yaml.MappingNode {
+		orderScopes(metaLookup(root, "securityDefinitions"))
+	}
+	jazon, err := json.Marshal(metaNodeValue(root, false))
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(jazon, swspec); err != nil {
+		return nil, fmt.Errorf("invalid meta document: %w", err)
+	}
+
+	return swspec, nil
+}
+
+// metaNodeValue converts a validated node to a value suitable for JSON marshaling.
+//
+// All the meta fields are strings: scalars are kept verbatim (e.g. a version "1.0" remains a string),
+// except in vendor extensions, which retain their YAML types.
+func metaNodeValue(node *yaml.Node, typed bool) any {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		return metaNodeValue(node.Content[0], typed)
+	case yaml.AliasNode:
+		return metaNodeValue(node.Alias, typed)
+	case yaml.MappingNode:
+		result := make(map[string]any, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			result[key] = metaNodeValue(node.Content[i+1], typed || rxAllowedExtensions.MatchString(key))
+		}
+		return result
+	case yaml.SequenceNode:
+		result := make([]any, 0, len(node.Content))
+		for _, item := range node.Content {
+			result = append(result, metaNodeValue(item, typed))
+		}
+		return result
+	default:
+		if !typed {
+			return node.Value
+		}
+		var value any
+		if err := node.Decode(&value); err != nil {
+			return node.Value
+		}
+		return value
+	}
+}
+
+type metaValidator struct {
+	errs []error
+}
+
+func (v *metaValidator) errorf(node *yaml.Node, format string, args ...any) {
+	v.errs = append(v.errs, &MetaFileError{
+		Line:    node.Line,
+		Column:  node.Column,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// fields iterates over the keys of a mapping node, reporting the keys which are not allowed.
+func (v *metaValidator) fields(node *yaml.Node, context string, allowed []string, check func(key string, value *yaml.Node)) {
+	if !v.isKind(node, yaml.MappingNode, context, "an object") {
+		return
+	}
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		switch {
+		case rxAllowedExtensions.MatchString(key.Value):
+		case slices.Contains(allowed, key.Value):
+			check(key.Value, value)
+		default:
+			v.errorf(key, "unknown field %q in %s", key.Value, context)
+		}
+	}
+}
+
+func (v *metaValidator) isKind(node *yaml.Node, kind yaml.Kind, context, expected string) bool {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if node.Kind != kind {
+		v.errorf(node, "%s must be %s", context, expected)
+		return false
+	}
+	return true
+}
+
+func (v *metaValidator) scalar(node *yaml.Node, context string) bool {
+	return v.isKind(node, yaml.ScalarNode, context, "a string")
+}
+
+func (v *metaValidator) stringList(node *yaml.Node, context string) {
+	if !v.isKind(node, yaml.SequenceNode, context, "a list") {
+		return
+	}
+	for _, item := range node.Content {
+		v.scalar(item, context+" items")
+	}
+}
+
+func (v *metaValidator) required(node *yaml.Node, context string, keys ...string) {
+	for _, key := range keys {
+		if metaLookup(node, key) == nil {
+			v.errorf(node, "missing required field %q in %s", key, context)
+		}
+	}
+}
+
+func (v *metaValidator) oneOf(node *yaml.Node, context string, values ...string) {
+	if v.scalar(node, context) && !slices.Contains(values, node.Value) {
+		v.errorf(node, "invalid %s %q, expected one of: %s", context, node.Value, strings.Join(values, ", "))
+	}
+}
+
+func (v *metaValidator) validateRoot(root *yaml.Node) {
+	v.fields(root, "meta", []string{
+		"info", "host", "basePath", "schemes", "consumes", "produces",
+		"security", "securityDefinitions", "tags", "externalDocs",
+	}, func(key string, value *yaml.Node) {
+		switch key {
+		case "info":
+			v.validateInfo(value)
+		case "host":
+			if v.scalar(value, key) && (strings.Contains(value.Value, "://") || strings.Contains(value.Value, "/")) {
+				v.errorf(value, "host %q must not include a scheme or a path", value.Value)
+			}
+		case "basePath":
+			if v.scalar(value, key) && !strings.HasPrefix(value.Value, "/") {
+				v.errorf(value, "basePath %q must start with a slash", value.Value)
+			}
+		case "schemes":
+			if v.isKind(value, yaml.SequenceNode, key, "a list") {
+				for _, scheme := range value.Content {
+					v.oneOf(scheme, "scheme", "http", "https", "ws", "wss")
+				}
+			}
+		case "consumes", "produces":
+			v.stringList(value, key)
+		case "security":
+			v.validateSecurity(value)
+		case "securityDefinitions":
+			v.validateSecurityDefinitions(value)
+		case "tags":
+			v.validateTags(value)
+		case "externalDocs":
+			v.validateExternalDocs(value, key)
+		}
+	})
+}
+
+func (v *metaValidator) validateInfo(info *yaml.Node) {
+	v.fields(info, "info", []string{"title", "description", "termsOfService", "version", "contact", "license"},
+		func(key string, value *yaml.Node) {
+			switch key {
+			case "contact":
+				v.fields(value, "contact", []string{"name", "url", "email"}, func(key string, value *yaml.Node) {
+					v.scalar(value, "contact "+key)
+				})
+			case "license":
+				v.fields(value, "license", []string{"name", "url"}, func(key string, value *yaml.Node) {
+					v.scalar(value, "license "+key)
+				})
+				if value.Kind == yaml.MappingNode {
+					v.required(value, "license", "name")
+				}
+			default:
+				v.scalar(value, "info "+key)
+			}
+		})
+}
+
+func (v *metaValidator) validateSecurity(security *yaml.Node) {
+	if !v.isKind(security, yaml.SequenceNode, "security", "a list") {
+		return
+	}
+	for _, requirement := range security.Content {
+		if !v.isKind(requirement, yaml.MappingNode, "security requirement", "an object") {
+			continue
+		}
+		for i := 1; i < len(requirement.Content); i += 2 {
+			v.stringList(requirement.Content[i], "security scopes")
+		}
+	}
+}
+
+// validateScopes accepts the scopes as an object of descriptions, or as a list of scopes, with or without a
+// description, see orderScopes.
+func (v *metaValidator) validateScopes(scopes *yaml.Node) {
+	if scopes.Kind == yaml.SequenceNode {
+		for _, item := range scopes.Content {
+			if item.Kind == yaml.ScalarNode {
+				continue
+			}
+			if v.isKind(item, yaml.MappingNode, "scopes items", "a scope or an object") {
+				for j := 1; j < len(item.Content); j += 2 {
+					v.scalar(item.Content[j], "scope description")
+				}
+			}
+		}
+		return
+	}
+	if v.isKind(scopes, yaml.MappingNode, "scopes", "an object or a list") {
+		for j := 1; j < len(scopes.Content); j += 2 {
+			v.scalar(scopes.Content[j], "scope description")
+		}
+	}
+}
+
+func (v *metaValidator) validateSecurityDefinitions(definitions *yaml.Node) {
+	if !v.isKind(definitions, yaml.MappingNode, "securityDefinitions", "an object") {
+		return
+	}
+	allowed := []string{"type", "description", "name", "in", "flow", "authorizationUrl", "tokenUrl", "scopes"}
+	for i := 0; i+1 < len(definitions.Content); i += 2 {
+		name, scheme := definitions.Content[i].Value, definitions.Content[i+1]
+		context := fmt.Sprintf("security definition %q", name)
+		v.fields(scheme, context, allowed, func(key string, value *yaml.Node) {
+			switch key {
+			case "type":
+				v.oneOf(value, "security type", "basic", "apiKey", "oauth2")
+			case "in":
+				v.oneOf(value, "apiKey location", "query", "header")
+			case "flow":
+				v.oneOf(value, "oauth2 flow", "implicit", "password", "application", "accessCode")
+			case "scopes":
+				v.validateScopes(value)
+			default:
+				v.scalar(value, key)
+			}
+		})
+		if scheme.Kind != yaml.MappingNode {
+			continue
+		}
+		v.required(scheme, context, "type")
+		switch metaValue(scheme, "type") {
+		case "apiKey":
+			v.required(scheme, context, "name", "in")
+		case "oauth2":
+			v.required(scheme, context, "flow")
+			switch metaValue(scheme, "flow") {
+			case "implicit":
+				v.required(scheme, context, "authorizationUrl")
+			case "password", "application":
+				v.required(scheme, context, "tokenUrl")
+			case "accessCode":
+				v.required(scheme, context, "authorizationUrl", "tokenUrl")
+			}
+		}
+	}
+}
+
+func (v *metaValidator) validateTags(tags *yaml.Node) {
+	if !v.isKind(tags, yaml.SequenceNode, "tags", "a list") {
+		return
+	}
+	seen := make(map[string]bool)
+	for _, tag := range tags.Content {
+		v.fields(tag, "tag", []string{"name", "description", "externalDocs"}, func(key string, value *yaml.Node) {
+			if key == "externalDocs" {
+				v.validateExternalDocs(value, "tag externalDocs")
+				return
+			}
+			v.scalar(value, "tag "+key)
+		})
+		if tag.Kind != yaml.MappingNode {
+			continue
+		}
+		v.required(tag, "tag", "name")
+		name := metaValue(tag, "name")
+		if name != "" && seen[name] {
+			v.errorf(tag, "duplicate tag %q", name)
+		}
+		seen[name] = true
+	}
+}
+
+func (v *metaValidator) validateExternalDocs(docs *yaml.Node, context string) {
+	v.fields(docs, context, []string{"description", "url"}, func(key string, value *yaml.Node) {
+		v.scalar(value, context+" "+key)
+	})
+	if docs.Kind == yaml.MappingNode {
+		v.required(docs, context, "url")
+	}
+}
+
+func metaLookup(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func metaValue(node *yaml.Node, key string) string {
+	if value := metaLookup(node, key); value != nil {
+		return value.Value
+	}
+	return ""
+}
+
+// mergeMeta merges meta information over a spec: the values set in meta take precedence.
+func mergeMeta(swspec, meta *spec.Swagger) {
+	if meta == nil {
+		return
+	}
+
+	if meta.Info != nil {
+		info := safeInfo(swspec)
+		overrideString(&info.Title, meta.Info.Title)
+		overrideString(&info.Description, meta.Info.Description)
+		overrideString(&info.TermsOfService, meta.Info.TermsOfService)
+		overrideString(&info.Version, meta.Info.Version)
+		if meta.Info.Contact != nil {
+			info.Contact = meta.Info.Contact
+		}
+		if meta.Info.License != nil {
+			info.License = meta.Info.License
+		}
+		for k, v := range meta.Info.Extensions {
+			info.AddExtension(k, v)
+		}
+	}
+
+	overrideString(&swspec.Host, meta.Host)
+	overrideString(&swspec.BasePath, meta.BasePath)
+	if len(meta.Schemes) > 0 {
+		swspec.Schemes = meta.Schemes
+	}
+	if len(meta.Consumes) > 0 {
+		swspec.Consumes = meta.Consumes
+	}
+	if len(meta.Produces) > 0 {
+		swspec.Produces = meta.Produces
+	}
+	if len(meta.Security) > 0 {
+		swspec.Security = meta.Security
+	}
+	if meta.ExternalDocs != nil {
+		swspec.ExternalDocs = meta.ExternalDocs
+	}
+
+	if len(meta.SecurityDefinitions) > 0 && swspec.SecurityDefinitions == nil {
+		swspec.SecurityDefinitions = make(spec.SecurityDefinitions, len(meta.SecurityDefinitions))
+	}
+	for name, scheme := range meta.SecurityDefinitions {
+		mergeScopes(scheme, swspec.SecurityDefinitions[name])
+		swspec.SecurityDefinitions[name] = scheme
+	}
+
+	for _, tag := range meta.Tags {
+		idx := slices.IndexFunc(swspec.Tags, func(existing spec.Tag) bool { return existing.Name == tag.Name })
+		if idx < 0 {
+			swspec.Tags = append(swspec.Tags, tag)
+			continue
+		}
+		swspec.Tags[idx] = tag
+	}
+
+	for k, v := range meta.Extensions {
+		swspec.AddExtension(k, v)
+	}
+}
+
+func overrideString(target *string, value string) {
+	if value != "" {
+		*target = value
+	}
+}
@@ -0,0 +1,135 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-openapi/spec"
+)
+
+func TestParseMeta(t *testing.T) {
+	t.Run("should parse a meta file", func(t *testing.T) {
+		swspec, err := ParseMeta([]byte(`info:
+  title: Petstore API
+  version: 1.0
+  contact:
+    name: API Support
+    email: support@example.com
+  license:
+    name: MIT
+  x-audience: public
+host: api.example.com
+basePath: /v1
+schemes: [https]
+securityDefinitions:
+  api_key:
+    type: apiKey
+    name: X-API-Key
+    in: header
+  oauth:
+    type: oauth2
+    flow: accessCode
+    authorizationUrl: https://example.com/authorize
+    tokenUrl: https://example.com/token
+    scopes:
+      read:pets: read your pets
+tags:
+  - name: pets
+    description: Everything about pets
+x-release: 42
+`))
+		require.NoError(t, err)
+
+		require.NotNil(t, swspec.Info)
+		assert.Equal(t, "Petstore API", swspec.Info.Title)
+		assert.Equal(t, "1.0", swspec.Info.Version)
+		assert.Equal(t, "support@example.com", swspec.Info.Contact.Email)
+		assert.Equal(t, "MIT", swspec.Info.License.Name)
+		assert.Equal(t, "public", swspec.Info.Extensions["x-audience"])
+		assert.Equal(t, "api.example.com", swspec.Host)
+		assert.Equal(t, "/v1", swspec.BasePath)
+		assert.Equal(t, []string{"https"}, swspec.Schemes)
+		require.Len(t, swspec.SecurityDefinitions, 2)
+		assert.Equal(t, "header", swspec.SecurityDefinitions["api_key"].In)
+		assert.Equal(t, "read your pets", swspec.SecurityDefinitions["oauth"].Scopes["read:pets"])
+		require.Len(t, swspec.Tags, 1)
+		assert.Equal(t, "pets", swspec.Tags[0].Name)
+		assert.EqualValues(t, 42, swspec.Extensions["x-release"])
+	})
+
+	t.Run("should report positioned errors", func(t *testing.T) {
+		_, err := ParseMeta([]byte(`info:
+  title: Petstore API
+  versions: 1.0
+host: https://api.example.com
+schemes: [https, gopher]
+securityDefinitions:
+  api_key:
+    type: apiKey
+tags:
+  - description: no name
+`))
+		require.Error(t, err)
+
+		var positions [][2]int
+		for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+			var metaErr *MetaFileError
+			require.True(t, errors.As(e, &metaErr))
+			positions = append(positions, [2]int{metaErr.Line, metaErr.Column})
+		}
+		assert.Equal(t, [][2]int{{3, 3}, {4, 7}, {5, 18}, {8, 5}, {8, 5}, {10, 5}}, positions)
+		assert.Contains(t, err.Error(), `line 3, column 3: unknown field "versions" in info`)
+		assert.Contains(t, err.Error(), `line 5, column 18: invalid scheme "gopher"`)
+		assert.Contains(t, err.Error(), `missing required field "in" in security definition "api_key"`)
+	})
+
+	t.Run("should report syntax errors", func(t *testing.T) {
+		_, err := ParseMeta([]byte("info: [title"))
+		require.Error(t, err)
+	})
+
+	t.Run("should accept an empty document", func(t *testing.T) {
+		swspec, err := ParseMeta(nil)
+		require.NoError(t, err)
+		assert.Nil(t, swspec.Info)
+	})
+}
+
+func TestMergeMeta(t *testing.T) {
+	swspec := &spec.Swagger{
+		SwaggerProps: spec.SwaggerProps{
+			Info: &spec.Info{InfoProps: spec.InfoProps{Title: "Scanned", Description: "From the doc comment", Version: "0.0.1"}},
+			Host: "localhost",
+			Tags: []spec.Tag{
+				{TagProps: spec.TagProps{Name: "pets", Description: "scanned"}},
+				{TagProps: spec.TagProps{Name: "stores"}},
+			},
+		},
+	}
+	meta := &spec.Swagger{
+		SwaggerProps: spec.SwaggerProps{
+			Info: &spec.Info{InfoProps: spec.InfoProps{Version: "2.3.4"}},
+			Host: "api.example.com",
+			Tags: []spec.Tag{
+				{TagProps: spec.TagProps{Name: "pets", Description: "from meta"}},
+				{TagProps: spec.TagProps{Name: "users"}},
+			},
+		},
+	}
+
+	mergeMeta(swspec, meta)
+
+	assert.Equal(t, "Scanned", swspec.Info.Title)
+	assert.Equal(t, "From the doc comment", swspec.Info.Description)
+	assert.Equal(t, "2.3.4", swspec.Info.Version)
+	assert.Equal(t, "api.example.com", swspec.Host)
+	require.Len(t, swspec.Tags, 3)
+	assert.Equal(t, "from meta", swspec.Tags[0].Description)
+	assert.Equal(t, "users", swspec.Tags[2].Name)
+}
@@ -0,0 +1,154 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"regexp"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+var (
+	// rxModelAnnotation matches the swagger:model annotations starting a line, unlike rxModelOverride, which
+	// also matches their mentions.
+	rxModelAnnotation = regexp.MustCompile(`^[\p{Zs}\t/\*-]*swagger:model\b`)
+	// rxAliasOf matches the Alias Of directive of an alias re-exporting a model, e.g. "Alias Of: models.User".
+	rxAliasOf = regexp.MustCompile(`^[\p{Zs}\t/\*]*[Aa]lias\p{Zs}*-?[Oo]f\p{Zs}*:\p{Zs}*([\p{L}_][\p{L}\p{N}_]*(?:\.[\p{L}_][\p{L}\p{N}_]*)?)\p{Zs}*$`)
+)
+
+// modelAnnotations returns the positions of the swagger:model annotations of the doc comment of a declaration.
+func (d *entityDecl) modelAnnotations() []token.Position {
+	if d.Comments == nil {
+		return nil
+	}
+	var positions []token.Position
+	for _, cmt := range d.Comments.List {
+		for pos, line := range commentLinesAt(cmt) {
+			if rxModelAnnotation.MatchString(line) {
+				positions = append(positions, d.Pkg.Fset.Position(pos))
+			}
+		}
+	}
+	return positions
+}
+
+// AliasOf returns the type named by the Alias Of directive of the declaration, e.g. models.User, if any.
+func (d *entityDecl) AliasOf() string {
+	if d.Comments == nil {
+		return ""
+	}
+	for _, cmt := range d.Comments.List {
+		for line := range commentLines(cmt.Text) {
+			if matches := rxAliasOf.FindStringSubmatch(line); matches != nil {
+				return matches[1]
+			}
+		}
+	}
+	return ""
+}
+
+// aliasTarget returns the named type an Alias Of declaration re-exports, checking that it is the one of the
+// directive.
+func (d *entityDecl) aliasTarget() (*types.Named, error) {
+	aliasOf := d.AliasOf()
+	pos := d.Pkg.Fset.Position(d.Ident.Pos())
+	if d.Alias == nil {
+		return nil, fmt.Errorf("%v: Alias Of only applies to the aliases, e.g. type %s = %s, and %s isn't one", pos, d.Ident.Name, aliasOf, d.Ident.Name)
+	}
+	target, isNamed := types.Unalias(d.Alias).(*types.Named)
+	if !isNamed || target.Obj().Pkg() == nil {
+		return nil, fmt.Errorf("%v: Alias Of %s of %s, which aliases %s rather than a named type", pos, aliasOf, d.Ident.Name, d.Alias.Rhs())
+	}
+	obj := target.Obj()
+	pkgName, name, qualified := strings.Cut(aliasOf, ".")
+	if !qualified {
+		pkgName, name = d.Obj().Pkg().Name(), aliasOf
+	}
+	if name != obj.Name() || pkgName != obj.Pkg().Name() {
+		return nil, fmt.Errorf("%v: Alias Of %s of %s, which aliases %s.%s", pos, aliasOf, d.Ident.Name, obj.Pkg().Name(), obj.Name())
+	}
+	return target, nil
+}
+
+// checkModelAnnotations fails with the Go types documented by several swagger:model annotations, giving the
+// positions of both, rather than depending on the order of the scan: a type annotated twice, e.g. after a
+// bad merge, and a type annotated with an alias of it, unless the alias re-exports it with Alias Of or
+// RefAliases.
+func (a *typeIndex) checkModelAnnotations() error {
+	documented := make(map[string]*entityDecl)
+	var errs []error
+	for _, decl := range sortedDecls(a.Models) {
+		annotations := decl.modelAnnotations()
+		if len(annotations) > 1 {
+			errs = append(errs, fmt.Errorf("%v: type %s has another swagger:model annotation at %v: keep one of them",
+				annotations[1], decl.Ident.Name, annotations[0]))
+		}
+
+		if decl.AliasOf() != "" {
+			if _, err := decl.aliasTarget(); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		if decl.Alias != nil && a.refAliases {
+			// already a $ref to the definition of its type
+			continue
+		}
+		target, isNamed := types.Unalias(decl.ObjType()).(*types.Named)
+		if !isNamed {
+			continue
+		}
+		// the instantiations of a generic type are distinct types
+		key := types.TypeString(target, nil)
+		other, found := documented[key]
+		if !found {
+			documented[key] = decl
+			continue
+		}
+		name, _ := decl.Names()
+		otherName, _ := other.Names()
+		errs = append(errs, fmt.Errorf("%v: swagger:model %s and swagger:model %s, declared at %v, both document type %s: keep one, or add \"Alias Of: %s\" to the alias to document it as a $ref",
+			decl.Pkg.Fset.Position(decl.Ident.Pos()), name, otherName, other.Pkg.Fset.Position(other.Ident.Pos()), key, aliasExpression(decl, other, target.Obj())))
+	}
+
+	if len(errs) > 0 && a.failFast {
+		return errs[0]
+	}
+	a.annotationErrors = append(a.annotationErrors, errs...)
+	return nil
+}
+
+// aliasExpression returns the type of the Alias Of directive of the alias among two declarations of a
+// type, as written in the package of the alias.
+func aliasExpression(decl, other *entityDecl, target types.Object) string {
+	alias := decl
+	if decl.Alias == nil {
+		alias = other
+	}
+	if alias.Obj().Pkg() == target.Pkg() {
+		return target.Name()
+	}
+	return target.Pkg().Name() + "." + target.Name()
+}
+
+// buildAliasOf builds the definition of an alias re-exporting a model with Alias Of: a $ref to the definition
+// of its type, without the documentation of the alias, which a $ref has no room for.
+func (s *schemaBuilder) buildAliasOf(schema *spec.Schema) error {
+	target, err := s.decl.aliasTarget()
+	if err != nil {
+		// reported by checkModelAnnotations
+		return nil
+	}
+	obj := target.Obj()
+	decl, found := s.ctx.FindModel(obj.Pkg().Path(), obj.Name())
+	if !found {
+		return fmt.Errorf("can't find source file for the type %s.%s of Alias Of", obj.Pkg().Path(), obj.Name())
+	}
+	*schema = spec.Schema{}
+	return s.makeRef(decl, schemaTypable{schema, 0})
+}
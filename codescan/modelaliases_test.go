@@ -0,0 +1,58 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModelAnnotationsOfOneType(t *testing.T) {
+	const pkg = "github.com/3idey/codescan/fixtures/goparsing/duplicatemodels/conflict"
+	dir, err := filepath.Abs("../fixtures/goparsing/duplicatemodels/conflict")
+	require.NoError(t, err)
+
+	_, err = Run(&Options{Packages: []string{pkg}, ScanModels: true})
+	require.Error(t, err)
+	msg := err.Error()
+	assert.Contains(t, msg, filepath.Join(dir, "user.go")+":10:6: swagger:model user and swagger:model Person, declared at "+
+		filepath.Join(dir, "person.go")+":9:6, both document type "+pkg+`.User: keep one, or add "Alias Of: User" to the alias to document it as a $ref`)
+	assert.Contains(t, msg, filepath.Join(dir, "account.go")+":10:1: type Account has another swagger:model annotation at "+
+		filepath.Join(dir, "account.go")+":8:1: keep one of them")
+	assert.Contains(t, msg, filepath.Join(dir, "member.go")+":10:6: Alias Of Account of Member, which aliases conflict.User")
+
+	t.Run("should accept the aliases with RefAliases", func(t *testing.T) {
+		_, err := Run(&Options{Packages: []string{pkg}, ScanModels: true, RefAliases: true})
+		require.Error(t, err)
+		assert.NotContains(t, err.Error(), "swagger:model Person")
+		assert.Contains(t, err.Error(), "type Account has another swagger:model annotation")
+	})
+
+	t.Run("should stop at the first error with FailFast", func(t *testing.T) {
+		_, err := Run(&Options{Packages: []string{pkg}, ScanModels: true, FailFast: true})
+		require.EqualError(t, err, filepath.Join(dir, "account.go")+":10:1: type Account has another swagger:model annotation at "+
+			filepath.Join(dir, "account.go")+":8:1: keep one of them")
+	})
+}
+
+func TestAliasOf(t *testing.T) {
+	doc, err := Run(&Options{Packages: []string{"github.com/3idey/codescan/fixtures/goparsing/duplicatemodels/reexport/..."}, ScanModels: true})
+	require.NoError(t, err)
+
+	require.Len(t, doc.Definitions, 3)
+	assert.Equal(t, *spec.RefSchema("#/definitions/user"), doc.Definitions["Person"])
+	assert.Equal(t, *spec.RefSchema("#/definitions/user"), doc.Definitions["Owner"])
+	user := doc.Definitions["user"]
+	assert.Equal(t, "User is a user of the service.", user.Title)
+	assert.Contains(t, user.Properties, "name")
+
+	for _, line := range []string{"Alias Of: models.User", "// alias-of: User", " * AliasOf:User "} {
+		assert.True(t, rxAliasOf.MatchString(line), line)
+	}
+	assert.False(t, rxAliasOf.MatchString("Alias Of: models.User and more"))
+}
@@ -0,0 +1,359 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/token"
+	"log"
+	"maps"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+
+	"github.com/go-openapi/spec"
+	"golang.org/x/tools/go/packages"
+)
+
+// modelCache holds the schemas of the models built by the scans missing the cache of Options.CacheDir, by
+// package: the next scans read the models of the packages which, with their dependencies, are unchanged, and
+// build the others. The schemas are built by forks of the scan, and read back with what joining the forks
+// records, see prebuildSchemas, so that the spec is the one built without the cache.
+type modelCache struct {
+	dir          string // the directory of the models in Options.CacheDir
+	key          string // the key of the options, see cacheKey
+	packages     map[*packages.Package]*cachedPackage
+	fingerprints map[*packages.Package]string // see fingerprintPackage
+}
+
+// cachedPackage are the models of a package in a modelCache. Each package has a file, keyed by the options
+// and the package, holding the models of the last fingerprint of the package and of its dependencies.
+type cachedPackage struct {
+	file    string // empty when the package can't be fingerprinted
+	changed bool   // the scan built models of the package, which save writes
+
+	Fingerprint string                  `json:"fingerprint"`
+	Models      map[string]*cachedModel `json:"models"` // by name of the declaration
+}
+
+// cachedModel is the schema of a model, with what joining the fork building it records, see join.
+type cachedModel struct {
+	Schema       json.RawMessage    `json:"schema"`
+	Journal      []cachedDiagnostic `json:"journal,omitempty"`
+	Discovered   []declRef          `json:"discovered,omitempty"`  // the declarations the schema refers to
+	ExtraModels  []declRef          `json:"extraModels,omitempty"` // see typeIndex.ExtraModels
+	ExternalRefs []cachedAllOfRef   `json:"externalRefs,omitempty"`
+	SchemaFiles  map[string]string  `json:"schemaFiles,omitempty"`  // hash by path
+	ExampleFiles map[string]string  `json:"exampleFiles,omitempty"` // hash by path
+}
+
+type cachedDiagnostic struct {
+	Diagnostic
+
+	ByMessage bool `json:"byMessage,omitempty"`
+}
+
+type cachedAllOfRef struct {
+	Name  string         `json:"name"`
+	Class string         `json:"class,omitempty"`
+	Pos   token.Position `json:"pos"`
+}
+
+// declRef refers to a declaration by package and name, see resolveDecl.
+type declRef struct {
+	Pkg  string `json:"pkg"`
+	Name string `json:"name"`
+}
+
+func newModelCache(dir, key string) *modelCache {
+	return &modelCache{
+		dir:          dir,
+		key:          key,
+		packages:     make(map[*packages.Package]*cachedPackage),
+		fingerprints: make(map[*packages.Package]string),
+	}
+}
+
+// reuse reads the schema of the declaration of a builder from the cache, as prebuilt, when the cache holds it.
+// The schemas prebuildSchemas doesn't build, e.g. those of the derived models, are never cached.
+func (c *modelCache) reuse(ctx *scanCtx, sb *schemaBuilder, prebuilt *prebuiltSchema) {
+	if c == nil || sb.decl.instanceName != "" {
+		return
+	}
+	if derivation, err := sb.decl.Derivation(); err != nil || derivation != nil {
+		return
+	}
+	if _, indexed := ctx.app.indexedRef(sb.decl); indexed {
+		return
+	}
+	model, found := c.pkg(sb.decl.Pkg).Models[sb.decl.Ident.Name]
+	if !found || !unchangedFiles(model.SchemaFiles) || !unchangedFiles(model.ExampleFiles) {
+		return
+	}
+	schema, err := decodeSchema(model.Schema)
+	if err != nil {
+		return
+	}
+	discovered, resolved := ctx.resolveDecls(model.Discovered)
+	if !resolved {
+		return
+	}
+	extraModels, resolved := ctx.resolveDecls(model.ExtraModels)
+	if !resolved {
+		return
+	}
+
+	fork := ctx.app.fork()
+	for _, entry := range model.Journal {
+		fork.journal = append(fork.journal, journaledDiagnostic{Diagnostic: entry.Diagnostic, byMessage: entry.ByMessage})
+	}
+	for _, decl := range extraModels {
+		fork.ExtraModels[decl.Ident] = decl
+	}
+	fork.stats.SchemaFiles = sortedKeys(model.SchemaFiles)
+	fork.stats.ExampleFiles = sortedKeys(model.ExampleFiles)
+	sb.postDecls = discovered
+	for _, ref := range model.ExternalRefs {
+		sb.externalRefs = append(sb.externalRefs, allOfRef{name: ref.Name, class: ref.Class, pos: ref.Pos})
+	}
+	prebuilt.builder, prebuilt.schema, prebuilt.fork, prebuilt.reused = sb, schema, fork, true
+}
+
+// capture returns the schema a fork built for the declaration of a builder, to cache once recorded, or nil when
+// the cache can't hold it: the schemas of the instances of generic types, those reading packages besides the
+// dependencies of their declaration, and those which wouldn't read back the same, see cachedSchema.
+func (c *modelCache) capture(ctx *scanCtx, sb *schemaBuilder, schema spec.Schema) *cachedModel {
+	if c == nil || ctx.app.crossPackage {
+		return nil
+	}
+	if _, refers := ctx.declRefs([]*entityDecl{sb.decl}); !refers {
+		return nil
+	}
+	data, cacheable := cachedSchema(schema)
+	if !cacheable {
+		return nil
+	}
+	discovered, refers := ctx.declRefs(sb.postDecls)
+	if !refers {
+		return nil
+	}
+	extraModels, refers := ctx.declRefs(sortedDecls(ctx.app.ExtraModels))
+	if !refers {
+		return nil
+	}
+	schemaFiles, err := hashFiles(ctx.app.stats.SchemaFiles)
+	if err != nil {
+		return nil
+	}
+	exampleFiles, err := hashFiles(ctx.app.stats.ExampleFiles)
+	if err != nil {
+		return nil
+	}
+
+	model := &cachedModel{
+		Schema:       data,
+		Discovered:   discovered,
+		ExtraModels:  extraModels,
+		SchemaFiles:  schemaFiles,
+		ExampleFiles: exampleFiles,
+	}
+	for _, entry := range ctx.app.journal {
+		model.Journal = append(model.Journal, cachedDiagnostic{Diagnostic: entry.Diagnostic, ByMessage: entry.byMessage})
+	}
+	for _, ref := range sb.externalRefs {
+		model.ExternalRefs = append(model.ExternalRefs, cachedAllOfRef{Name: ref.name, Class: ref.class, Pos: ref.pos})
+	}
+	return model
+}
+
+// recordModel records a schema prebuilt with the models cached, once the scan recorded it: in the stats when it
+// is read from the cache, in the cache otherwise.
+func (a *typeIndex) recordModel(decl *entityDecl, prebuilt *prebuiltSchema) {
+	switch {
+	case prebuilt.reused:
+		if i, found := slices.BinarySearch(a.stats.ReusedModels, prebuilt.name); !found {
+			a.stats.ReusedModels = slices.Insert(a.stats.ReusedModels, i, prebuilt.name)
+		}
+	case prebuilt.model != nil && a.models != nil:
+		cached := a.models.pkg(decl.Pkg)
+		cached.Models[decl.Ident.Name] = prebuilt.model
+		cached.changed = true
+	}
+}
+
+// save writes the models of the packages the scan built models of.
+func (c *modelCache) save() error {
+	var errs []error
+	for _, cached := range c.packages {
+		if !cached.changed || cached.file == "" {
+			continue
+		}
+		if err := writeCacheFile(cached.file, cached); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// pkg returns the models cached of a package, those of another fingerprint being dropped.
+func (c *modelCache) pkg(pkg *packages.Package) *cachedPackage {
+	if cached, loaded := c.packages[pkg]; loaded {
+		return cached
+	}
+	cached := &cachedPackage{Models: make(map[string]*cachedModel)}
+	c.packages[pkg] = cached
+	fingerprint, err := c.fingerprint(pkg)
+	if err != nil {
+		return cached
+	}
+	sum := sha256.Sum256([]byte(c.key + "\n" + pkg.ID))
+	cached.file = filepath.Join(c.dir, hex.EncodeToString(sum[:16])+".json")
+	cached.Fingerprint = fingerprint
+
+	data, err := os.ReadFile(cached.file)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Printf("WARNING: ignoring the models of the scan cache %s: %v", cached.file, err)
+		}
+		return cached
+	}
+	var read cachedPackage
+	if err := json.Unmarshal(data, &read); err != nil {
+		log.Printf("WARNING: ignoring the models of the scan cache %s: %v", cached.file, err)
+		return cached
+	}
+	if read.Fingerprint == fingerprint && read.Models != nil {
+		cached.Models = read.Models
+	}
+	return cached
+}
+
+// fingerprint returns the fingerprint of a package with its dependencies, see fingerprintPackage.
+func (c *modelCache) fingerprint(pkg *packages.Package) (string, error) {
+	var lines []string
+	var errs []error
+	packages.Visit([]*packages.Package{pkg}, nil, func(dep *packages.Package) {
+		fingerprint, done := c.fingerprints[dep]
+		if !done {
+			var err error
+			if fingerprint, err = fingerprintPackage(dep); err != nil {
+				errs = append(errs, err)
+				return
+			}
+			c.fingerprints[dep] = fingerprint
+		}
+		lines = append(lines, dep.ID+" "+fingerprint)
+	})
+	if len(errs) > 0 {
+		return "", errors.Join(errs...)
+	}
+	slices.Sort(lines)
+	h := sha256.New()
+	for _, line := range lines {
+		fmt.Fprintln(h, line)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// declRefs refers to declarations by package and name, when resolveDecl finds them again.
+func (s *scanCtx) declRefs(decls []*entityDecl) ([]declRef, bool) {
+	refs := make([]declRef, 0, len(decls))
+	for _, decl := range decls {
+		if decl.instanceName != "" || decl.Pkg == nil {
+			return nil, false
+		}
+		ref := declRef{Pkg: decl.Pkg.PkgPath, Name: decl.Ident.Name}
+		if resolved, found := s.resolveDecl(ref); !found || resolved.Ident != decl.Ident {
+			return nil, false
+		}
+		refs = append(refs, ref)
+	}
+	return refs, true
+}
+
+func (s *scanCtx) resolveDecls(refs []declRef) ([]*entityDecl, bool) {
+	decls := make([]*entityDecl, 0, len(refs))
+	for _, ref := range refs {
+		decl, found := s.resolveDecl(ref)
+		if !found {
+			return nil, false
+		}
+		decls = append(decls, decl)
+	}
+	return decls, true
+}
+
+// resolveDecl returns the declaration a declRef refers to: the model, or the type declared, like FindModel.
+func (s *scanCtx) resolveDecl(ref declRef) (*entityDecl, bool) {
+	for _, model := range s.app.Models {
+		if obj := model.Obj(); obj.Name() == ref.Name && obj.Pkg().Path() == ref.Pkg {
+			return model, true
+		}
+	}
+	return s.FindDecl(ref.Pkg, ref.Name)
+}
+
+func unchangedFiles(hashes map[string]string) bool {
+	current, err := hashFiles(slices.Collect(maps.Keys(hashes)))
+	return err == nil && maps.Equal(current, hashes)
+}
+
+// cachedSchema encodes a schema for the cache, unless decodeSchema wouldn't give it back as is: e.g. the
+// values of an extension the scan doesn't type again would come back as generic JSON values.
+func cachedSchema(schema spec.Schema) (json.RawMessage, bool) {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, false
+	}
+	decoded, err := decodeSchema(data)
+	if err != nil || !reflect.DeepEqual(decoded, schema) {
+		return nil, false
+	}
+	return data, true
+}
+
+// decodeSchema decodes a cached schema, with the values typed as the scan builds them: the extensions of the
+// source positions, of the orders, of the enum names and of the unions of types, and the integers.
+func decodeSchema(data []byte) (spec.Schema, error) {
+	var schema spec.Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return schema, err
+	}
+	walkSchema(&schema, "", func(sch *spec.Schema, _ string) {
+		retypeExtension[token.Position](sch.Extensions, sourcePositionExtension)
+		retypeExtension[int](sch.Extensions, "x-order")
+		retypeExtension[[]string](sch.Extensions, extEnumVarNames)
+		retypeExtension[[]spec.Schema](sch.Extensions, xOneOfTypes)
+		if !sch.Type.Contains("integer") {
+			return
+		}
+		for i, value := range sch.Enum {
+			sch.Enum[i] = integerValue(value)
+		}
+		sch.Default = integerValue(sch.Default)
+		sch.Example = integerValue(sch.Example)
+	})
+	return schema, nil
+}
+
+func retypeExtension[T any](extensions spec.Extensions, key string) {
+	if typed, ok := decodeExtension[T](extensions, key); ok {
+		extensions[key] = typed
+	}
+}
+
+// integerValue returns the int of a JSON number which is one, as parseValueFromSchema parses them.
+func integerValue(value any) any {
+	number, isNumber := value.(float64)
+	if !isNumber || number != math.Trunc(number) || math.Abs(number) > 1<<53 {
+		return value
+	}
+	return int(number)
+}
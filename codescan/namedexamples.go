@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"gopkg.in/yaml.v3"
+)
+
+// namedExamplesExtension holds the named examples of a body parameter or a response, which the OpenAPI 3.0
+// output turns into the examples of their media types.
+const namedExamplesExtension = "x-examples"
+
+// rxNamedExamples matches the Examples: line starting the block of the named examples of a body parameter
+// or a response, unlike rxExampleFmt, the Example: of a single value.
+var rxNamedExamples = regexp.MustCompile(`^[\p{Zs}\t/\*-]*[Ee]xamples\p{Zs}*:\p{Zs}*$`)
+
+// namedExampleKeys are the keys of a named example of an Examples: block.
+var namedExampleKeys = []string{"summary", "description", "value", "file"}
+
+// setNamedExamples parses the Examples: block of a body parameter or a response: a YAML mapping of the
+// names of the examples to their summary, description, and value, or file holding the value. The files are
+// relative to the directory of the Go file of the comment, and read like those of swagger:example.
+type setNamedExamples struct {
+	app *typeIndex
+	dir string
+	set func(examples map[string]any)
+}
+
+func newSetNamedExamples(app *typeIndex, goFile string, setter func(map[string]any)) *setNamedExamples {
+	return &setNamedExamples{app: app, dir: filepath.Dir(goFile), set: setter}
+}
+
+func (se *setNamedExamples) Matches(line string) bool {
+	return rxNamedExamples.MatchString(line)
+}
+
+func (se *setNamedExamples) startBlock(string) {}
+
+func (se *setNamedExamples) Parse(lines []string) error {
+	uncommented, err := uncommentBlock(lines)
+	if err != nil {
+		return err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(strings.Join(uncommented, "\n")), &doc); err != nil {
+		return yamlLineError(err)
+	}
+	if len(doc.Content) == 0 {
+		return nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return &blockLineError{line: root.Line - 1, err: errors.New("expected a mapping of the names of the examples, e.g. minimal:")}
+	}
+
+	examples := make(map[string]any, len(root.Content)/2)
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		name, value := root.Content[i], root.Content[i+1]
+		if _, defined := examples[name.Value]; defined {
+			return &blockLineError{line: name.Line - 1, err: fmt.Errorf("example %s is defined twice", name.Value)}
+		}
+		example, err := se.parseExample(name, value)
+		if err != nil {
+			return err
+		}
+		examples[name.Value] = example
+	}
+	if len(examples) > 0 {
+		se.set(examples)
+	}
+	return nil
+}
+
+// parseExample parses a named example of an Examples: block, as an example object of OpenAPI 3.0.
+func (se *setNamedExamples) parseExample(nameKey, node *yaml.Node) (map[string]any, error) {
+	name := nameKey.Value
+	if node.Kind != yaml.MappingNode {
+		return nil, &blockLineError{line: node.Line - 1, err: fmt.Errorf("expected the summary and the value of example %s", name)}
+	}
+	example := make(map[string]any, 2)
+	var file *yaml.Node
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		switch key.Value {
+		case "summary", "description":
+			if value.Kind != yaml.ScalarNode {
+				return nil, &blockLineError{line: value.Line - 1, err: fmt.Errorf("the %s of example %s must be a string", key.Value, name)}
+			}
+			example[key.Value] = value.Value
+		case "value":
+			decoded, err := decodeYAMLValue(value, decodeExample)
+			if err != nil {
+				return nil, err
+			}
+			example["value"] = decoded
+		case "file":
+			file = value
+		default:
+			return nil, &blockLineError{line: key.Line - 1, err: fmt.Errorf("unknown key %s of example %s, expected %s",
+				key.Value, name, strings.Join(namedExampleKeys, ", "))}
+		}
+	}
+
+	_, hasValue := example["value"]
+	switch {
+	case file != nil && hasValue:
+		return nil, &blockLineError{line: file.Line - 1, err: fmt.Errorf("example %s has both a value and a file", name)}
+	case file != nil:
+		path := file.Value
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(se.dir, path)
+		}
+		value, err := readExampleFile(path)
+		if err != nil {
+			return nil, &blockLineError{line: file.Line - 1, err: fmt.Errorf("invalid example file %s of example %s: %w", file.Value, name, err)}
+		}
+		se.app.recordExampleFile(path)
+		example["value"] = value
+	case !hasValue:
+		return nil, &blockLineError{line: nameKey.Line - 1, err: fmt.Errorf("example %s has neither a value nor a file", name)}
+	}
+	return example, nil
+}
+
+// namedExamples returns the named examples of an element of the spec, by name.
+func namedExamples(ext spec.Extensions) map[string]any {
+	examples, _ := ext[namedExamplesExtension].(map[string]any)
+	return examples
+}
@@ -0,0 +1,173 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamedExamples(t *testing.T) {
+	const pkg = "github.com/3idey/codescan/fixtures/goparsing/namedexamples"
+	var stats Stats
+	doc, err := Run(&Options{Packages: []string{pkg}, Stats: &stats})
+	require.NoError(t, err)
+	asJSON := func(value any) string {
+		data, err := json.Marshal(value)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	t.Run("should set the examples of a body parameter", func(t *testing.T) {
+		createUser := doc.Paths.Paths["/users"].Post
+		require.Len(t, createUser.Parameters, 1)
+		body := createUser.Parameters[0]
+		assert.Equal(t, "The user to create.", body.Description)
+		assert.JSONEq(t, `{
+			"minimal": {"summary": "only the required fields", "value": {"name": "ada", "email": "ada@example.com"}},
+			"complete": {"summary": "every field", "value": {"name": "ada", "email": "ada@example.com", "age": 36, "role": "admin"}}
+		}`, asJSON(body.Extensions[namedExamplesExtension]))
+		assert.Nil(t, body.Schema.Example)
+
+		require.Len(t, stats.ExampleFiles, 1)
+		assert.True(t, strings.HasSuffix(stats.ExampleFiles[0], filepath.Join("testdata", "complete_user.json")))
+	})
+
+	t.Run("should set the examples of a response", func(t *testing.T) {
+		resp := doc.Responses["userResponse"]
+		assert.Equal(t, "The created user.", resp.Description)
+		assert.JSONEq(t, `{
+			"created": {"summary": "a member", "description": "the role is member by default", "value": {"name": "ada", "email": "ada@example.com", "role": "member"}}
+		}`, asJSON(resp.Extensions[namedExamplesExtension]))
+	})
+
+	t.Run("should set the examples of every media type in OpenAPI 3", func(t *testing.T) {
+		doc3, err := Run3(&Options{Packages: []string{pkg}})
+		require.NoError(t, err)
+		op := asObject(asObject(asObject(doc3["paths"])["/users"])["post"])
+		body := asObject(op["requestBody"])
+		assert.NotContains(t, body, namedExamplesExtension)
+		content := asObject(body["content"])
+		require.Equal(t, []string{"application/json", "application/xml"}, sortedKeys(content))
+		for _, mediaType := range sortedKeys(content) {
+			assert.Equal(t, []string{"complete", "minimal"}, sortedKeys(asObject(asObject(content[mediaType])["examples"])), mediaType)
+		}
+
+		resp := asObject(asObject(asObject(doc3["components"])["responses"])["userResponse"])
+		assert.NotContains(t, resp, namedExamplesExtension)
+		media := asObject(asObject(resp["content"])["application/json"])
+		assert.JSONEq(t, `{
+			"created": {"summary": "a member", "description": "the role is member by default", "value": {"name": "ada", "email": "ada@example.com", "role": "member"}}
+		}`, asJSON(media["examples"]))
+
+		data, err := json.Marshal(doc3)
+		require.NoError(t, err)
+		downgraded, err := DowngradeOpenAPI3(data)
+		require.NoError(t, err)
+		param := downgraded.Paths.Paths["/users"].Post.Parameters[0]
+		assert.JSONEq(t, asJSON(doc.Paths.Paths["/users"].Post.Parameters[0].Extensions[namedExamplesExtension]), asJSON(param.Extensions[namedExamplesExtension]))
+		assert.JSONEq(t, asJSON(doc.Responses["userResponse"].Extensions[namedExamplesExtension]), asJSON(downgraded.Responses["userResponse"].Extensions[namedExamplesExtension]))
+	})
+
+	t.Run("should validate the examples against their schemas", func(t *testing.T) {
+		_, err := Run(&Options{Packages: []string{pkg}, ValidateExamples: true})
+		require.NoError(t, err)
+
+		file, err := filepath.Abs("../fixtures/goparsing/namedexamples/invalid/api.go")
+		require.NoError(t, err)
+		_, err = Run(&Options{Packages: []string{pkg + "/invalid"}, ValidateExamples: true})
+		require.EqualError(t, err, "2 examples don't match their schemas\n"+
+			file+`:37:6: the example "nameless" of /responses/petResponse doesn't match its schema: the value misses the required property name`+"\n"+
+			file+`:27:2: the example "wrong" of /paths/~1pets/post/parameters/0 doesn't match its schema: `+
+			`/kind is "bird", expected one of "cat", "dog"; /name is an integer, expected a string; /tags has more than 2 items`)
+
+		_, err = Run(&Options{Packages: []string{pkg + "/invalid"}})
+		require.NoError(t, err, "the examples aren't validated by default")
+	})
+
+	t.Run("should lower the severity of the invalid examples with a rule", func(t *testing.T) {
+		var diagnostics []Diagnostic
+		_, err := Run(&Options{
+			Packages:         []string{pkg + "/invalid"},
+			ValidateExamples: true,
+			Rules:            []Rule{{Name: DiagnosticInvalidExample, Severity: SeverityWarning}},
+			Logger:           func(diagnostic Diagnostic) { diagnostics = append(diagnostics, diagnostic) },
+		})
+		require.NoError(t, err)
+		require.Len(t, diagnostics, 2)
+		assert.Equal(t, SeverityWarning, diagnostics[0].Severity)
+		assert.Equal(t, DiagnosticInvalidExample, diagnostics[0].Code)
+	})
+
+	t.Run("should report the malformed blocks at their lines", func(t *testing.T) {
+		file, err := filepath.Abs("../fixtures/goparsing/namedexamples/malformed/api.go")
+		require.NoError(t, err)
+		_, err = Run(&Options{Packages: []string{pkg + "/malformed"}})
+		require.Error(t, err)
+		for _, msg := range []string{
+			file + ":10:2: example minimal has both a value and a file",
+			file + ":19:2: unknown key sumary of example minimal, expected summary, description, value, file",
+			file + ":26:1: invalid example file testdata/missing.json of example missing",
+			file + ":35:1: example empty has neither a value nor a file",
+		} {
+			assert.Contains(t, err.Error(), msg)
+		}
+	})
+}
+
+func TestExampleChecker(t *testing.T) {
+	definitions := spec.Definitions{
+		"Node": {SchemaProps: spec.SchemaProps{
+			Type:       spec.StringOrArray{"object"},
+			Properties: spec.SchemaProperties{"next": *spec.RefSchema("#/definitions/Node")},
+		}},
+	}
+	checker := &exampleChecker{definitions: definitions}
+	for _, tc := range []struct {
+		name   string
+		schema *spec.Schema
+		value  any
+		want   []string
+	}{
+		{name: "integer as number", schema: spec.Float64Property(), value: 3},
+		{name: "number as integer", schema: spec.Int64Property(), value: 1.5, want: []string{"the value is a number, expected an integer"}},
+		{name: "null", schema: spec.StringProperty(), value: nil, want: []string{"the value is null, expected a string"}},
+		{name: "x-nullable", schema: &spec.Schema{SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string"}}, VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{"x-nullable": true}}}, value: nil},
+		{name: "length", schema: spec.StringProperty().WithMinLength(3), value: "ab", want: []string{"the value is shorter than 3 characters"}},
+		{name: "pattern", schema: spec.StringProperty().WithPattern("^[a-z]+$"), value: "AB", want: []string{"the value doesn't match the pattern ^[a-z]+$"}},
+		{name: "exclusive minimum", schema: spec.Int64Property().WithMinimum(1, true), value: 1, want: []string{"the value isn't greater than the exclusive minimum 1"}},
+		{name: "multiple", schema: spec.Float64Property().WithMultipleOf(0.5), value: 1.25, want: []string{"the value isn't a multiple of 0.5"}},
+		{name: "unique items", schema: spec.ArrayProperty(spec.StringProperty()).UniqueValues(), value: []any{"a", "a"}, want: []string{`the value has the item "a" twice`}},
+		{name: "items", schema: spec.ArrayProperty(spec.StringProperty()), value: []any{"a", 2}, want: []string{"/1 is an integer, expected a string"}},
+		{
+			name:   "additional properties",
+			schema: &spec.Schema{SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"object"}, AdditionalProperties: &spec.SchemaOrBool{Allows: false}}},
+			value:  map[string]any{"a/b": 1},
+			want:   []string{"the value has the property a/b, which the schema doesn't define"},
+		},
+		{
+			name:   "additional properties schema",
+			schema: spec.MapProperty(spec.BooleanProperty()),
+			value:  map[string]any{"a/b": 1},
+			want:   []string{"/a~1b is an integer, expected a boolean"},
+		},
+		{
+			name:   "allOf",
+			schema: &spec.Schema{SchemaProps: spec.SchemaProps{AllOf: []spec.Schema{*spec.RefSchema("#/definitions/Node"), {SchemaProps: spec.SchemaProps{Required: []string{"id"}}}}}},
+			value:  map[string]any{"next": map[string]any{"next": "last"}},
+			want:   []string{"/next/next is a string, expected an object", "the value misses the required property id"},
+		},
+		{name: "enum of numbers", schema: spec.Int64Property().WithEnum(1, 2), value: 2.0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, checker.check(tc.schema, tc.value))
+		})
+	}
+}
@@ -0,0 +1,246 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"slices"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"golang.org/x/tools/go/packages"
+)
+
+// ContentNegotiator is a function, e.g. the constructor of a content-negotiation middleware, whose string
+// arguments are the media types an API supports, see Options.ContentNegotiators:
+//
+//	negotiate.New("application/json", "application/msgpack")
+type ContentNegotiator struct {
+	// Func is the qualified name of the function, with the import path or the name of its package, e.g.
+	// github.com/acme/negotiate.New or negotiate.New, and the type of a method, e.g. negotiate.Router.Offer.
+	Func string
+	// Consumes tells that the media types are those of the request bodies, rather than of the responses.
+	Consumes bool
+}
+
+// negotiatedMediaTypes are the media types passed to the content negotiators called by a package.
+type negotiatedMediaTypes struct {
+	consumes, produces []string
+}
+
+func withContentNegotiators(negotiators []ContentNegotiator) typeIndexOption {
+	return func(a *typeIndex) {
+		a.contentNegotiators = negotiators
+	}
+}
+
+// collectNegotiatedMediaTypes records the media types passed to the content negotiators called in a file:
+// the constant strings, and the elements of the slices of constant strings. The other string arguments are
+// reported with DiagnosticDynamicMediaType, and skipped.
+func (a *typeIndex) collectNegotiatedMediaTypes(pkg *packages.Package, file *ast.File) {
+	if len(a.contentNegotiators) == 0 {
+		return
+	}
+	ast.Inspect(file, func(node ast.Node) bool {
+		call, isCall := node.(*ast.CallExpr)
+		if !isCall {
+			return true
+		}
+		negotiator, ok := a.negotiatorFor(pkg, call)
+		if !ok {
+			return true
+		}
+
+		var mediaTypes []string
+		for _, arg := range call.Args {
+			values, resolved := constantStrings(pkg, arg)
+			if !resolved {
+				a.diagnose(Diagnostic{
+					Pos:  pkg.Fset.Position(arg.Pos()),
+					Code: DiagnosticDynamicMediaType,
+					Message: fmt.Sprintf("the media types passed to %s are not constant strings, and are skipped: annotate Produces or Consumes instead",
+						negotiator.Func),
+				})
+				continue
+			}
+			mediaTypes = append(mediaTypes, values...)
+		}
+		if len(mediaTypes) == 0 {
+			return true
+		}
+
+		if a.negotiated == nil {
+			a.negotiated = make(map[string]*negotiatedMediaTypes)
+		}
+		negotiated := a.negotiated[pkg.PkgPath]
+		if negotiated == nil {
+			negotiated = new(negotiatedMediaTypes)
+			a.negotiated[pkg.PkgPath] = negotiated
+		}
+		subject := "the media types passed to " + negotiator.Func
+		for _, mediaType := range normalizeMediaTypes(mediaTypes, subject) {
+			if negotiator.Consumes {
+				negotiated.consumes = appendFold(negotiated.consumes, mediaType)
+			} else {
+				negotiated.produces = appendFold(negotiated.produces, mediaType)
+			}
+		}
+		return true
+	})
+}
+
+// negotiatorFor returns the content negotiator called, if any.
+func (a *typeIndex) negotiatorFor(pkg *packages.Package, call *ast.CallExpr) (ContentNegotiator, bool) {
+	qualified := calleeNames(pkg, call)
+	for _, negotiator := range a.contentNegotiators {
+		if slices.Contains(qualified, negotiator.Func) {
+			return negotiator, true
+		}
+	}
+	return ContentNegotiator{}, false
+}
+
+// calleeNames returns the qualified names of the function called, with the import path and with the name of
+// its package, e.g. github.com/acme/negotiate.New and negotiate.New, and the type of a method, e.g.
+// negotiate.Router.Offer. The calls of function values have no names.
+func calleeNames(pkg *packages.Package, call *ast.CallExpr) []string {
+	var ident *ast.Ident
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		ident = fun
+	case *ast.SelectorExpr:
+		ident = fun.Sel
+	default:
+		return nil
+	}
+	fn, isFunc := pkg.TypesInfo.Uses[ident].(*types.Func)
+	if !isFunc || fn.Pkg() == nil {
+		return nil
+	}
+
+	name := fn.Name()
+	if recv := fn.Type().(*types.Signature).Recv(); recv != nil {
+		recvType := recv.Type()
+		if ptr, isPointer := recvType.(*types.Pointer); isPointer {
+			recvType = ptr.Elem()
+		}
+		named, isNamed := recvType.(*types.Named)
+		if !isNamed {
+			return nil
+		}
+		name = named.Obj().Name() + "." + name
+	}
+	return []string{fn.Pkg().Path() + "." + name, fn.Pkg().Name() + "." + name}
+}
+
+// constantStrings returns the values of a string argument: a constant, or a slice of constants spread over
+// the variadic parameter. Arguments of other types resolve to no value.
+func constantStrings(pkg *packages.Package, arg ast.Expr) ([]string, bool) {
+	tv := pkg.TypesInfo.Types[arg]
+	if tv.Value != nil {
+		if tv.Value.Kind() != constant.String {
+			return nil, true
+		}
+		return []string{constant.StringVal(tv.Value)}, true
+	}
+	if isStringType(tv.Type) {
+		return nil, false
+	}
+
+	slice, isSlice := types.Unalias(tv.Type).(*types.Slice)
+	if !isSlice || !isStringType(slice.Elem()) {
+		return nil, true
+	}
+	lit, isLit := ast.Unparen(arg).(*ast.CompositeLit)
+	if !isLit {
+		return nil, false
+	}
+	var values []string
+	for _, elt := range lit.Elts {
+		elem := pkg.TypesInfo.Types[elt]
+		if elem.Value == nil || elem.Value.Kind() != constant.String {
+			return nil, false
+		}
+		values = append(values, constant.StringVal(elem.Value))
+	}
+	return values, true
+}
+
+func isStringType(tpe types.Type) bool {
+	if tpe == nil {
+		return false
+	}
+	basic, isBasic := tpe.Underlying().(*types.Basic)
+	return isBasic && basic.Info()&types.IsString != 0
+}
+
+// applyNegotiatedMediaTypes sets the media types of the content negotiators, see Options.ContentNegotiators.
+// Those of the packages without operations apply to the spec, and those of the other packages to their
+// operations. The media types annotated, at either level, are kept.
+func (s *specBuilder) applyNegotiatedMediaTypes() {
+	negotiated := s.ctx.app.negotiated
+	if len(negotiated) == 0 {
+		return
+	}
+
+	withOperations := make(map[string][]parsedPathContent)
+	for _, pp := range slices.Concat(s.ctx.app.Routes, s.ctx.app.Operations) {
+		withOperations[pp.pkg.PkgPath] = append(withOperations[pp.pkg.PkgPath], pp)
+	}
+
+	var global negotiatedMediaTypes
+	for _, pkgPath := range sortedKeys(negotiated) {
+		if _, found := withOperations[pkgPath]; found {
+			continue
+		}
+		for _, mediaType := range negotiated[pkgPath].consumes {
+			global.consumes = appendFold(global.consumes, mediaType)
+		}
+		for _, mediaType := range negotiated[pkgPath].produces {
+			global.produces = appendFold(global.produces, mediaType)
+		}
+	}
+	if len(s.input.Consumes) == 0 {
+		s.input.Consumes = global.consumes
+	}
+	if len(s.input.Produces) == 0 {
+		s.input.Produces = global.produces
+	}
+
+	for _, pkgPath := range sortedKeys(withOperations) {
+		media, found := negotiated[pkgPath]
+		if !found {
+			continue
+		}
+		for _, pp := range withOperations[pkgPath] {
+			op := s.operationAt(pp.Path, pp.Method)
+			if op == nil {
+				continue
+			}
+			if len(op.Consumes) == 0 && !slices.Equal(media.consumes, s.input.Consumes) {
+				op.Consumes = slices.Clone(media.consumes)
+			}
+			if len(op.Produces) == 0 && !slices.Equal(media.produces, s.input.Produces) {
+				op.Produces = slices.Clone(media.produces)
+			}
+		}
+	}
+}
+
+// operationAt returns the operation of a path and a method, nil if it isn't in the spec, e.g. filtered out.
+func (s *specBuilder) operationAt(path, method string) *spec.Operation {
+	pathItem, found := s.input.Paths.Paths[path]
+	if !found {
+		return nil
+	}
+	for opMethod, op := range pathItemOperations(&pathItem) {
+		if strings.EqualFold(opMethod, method) {
+			return op
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentNegotiators(t *testing.T) {
+	const pkg = "github.com/3idey/codescan/fixtures/goparsing/negotiation"
+
+	negotiators := []ContentNegotiator{
+		{Func: "negotiate.New"},
+		{Func: pkg + "/negotiate.Router.Accept", Consumes: true},
+	}
+	scan := func(t *testing.T, opts *Options) *spec.Swagger {
+		t.Helper()
+		opts.Packages = []string{pkg + "/..."}
+		doc, err := Run(opts)
+		require.NoError(t, err)
+		return doc
+	}
+
+	t.Run("should leave the media types alone without negotiators", func(t *testing.T) {
+		doc := scan(t, &Options{})
+
+		assert.Empty(t, doc.Produces)
+		assert.Empty(t, doc.Paths.Paths["/orders"].Post.Consumes)
+	})
+
+	t.Run("should set the media types of the spec from the packages without operations", func(t *testing.T) {
+		var diagnostics []Diagnostic
+		doc := scan(t, &Options{ContentNegotiators: negotiators, Diagnostics: &diagnostics})
+
+		assert.Equal(t, []string{"application/json", "application/msgpack", "text/csv"}, doc.Produces,
+			"constants and slices of constants, with the shorthands expanded")
+		assert.Empty(t, doc.Consumes)
+
+		require.Len(t, diagnostics, 1)
+		assert.Equal(t, DiagnosticDynamicMediaType, diagnostics[0].Code)
+		assert.Equal(t, "server.go", filepath.Base(diagnostics[0].Pos.Filename))
+		assert.Equal(t, 19, diagnostics[0].Pos.Line)
+		assert.Contains(t, diagnostics[0].Message, "negotiate.New")
+	})
+
+	t.Run("should set the media types of the operations of the packages calling them", func(t *testing.T) {
+		doc := scan(t, &Options{ContentNegotiators: negotiators})
+
+		orders := doc.Paths.Paths["/orders"].Post
+		require.NotNil(t, orders)
+		assert.Equal(t, []string{"application/xml", "application/json"}, orders.Consumes)
+		assert.Empty(t, orders.Produces, "the produces of the spec apply")
+
+		uploads := doc.Paths.Paths["/uploads"].Post
+		require.NotNil(t, uploads)
+		assert.Equal(t, []string{"multipart/form-data"}, uploads.Consumes, "the annotation wins")
+	})
+
+	t.Run("should keep the media types of the meta", func(t *testing.T) {
+		doc := scan(t, &Options{
+			ContentNegotiators: negotiators,
+			Meta:               &spec.Swagger{SwaggerProps: spec.SwaggerProps{Produces: []string{"application/yaml"}}},
+		})
+
+		assert.Equal(t, []string{"application/yaml"}, doc.Produces)
+	})
+}
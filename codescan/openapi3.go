@@ -0,0 +1,1005 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// OpenAPI3Version is the version of the OpenAPI documents produced by Run3.
+const OpenAPI3Version = "3.0.3"
+
+// OpenAPI3 is an OpenAPI 3.0 document, represented as generic JSON values.
+//
+// It marshals its top-level fields in the order of the specification, and is encoded by MarshalJSON,
+// MarshalYAML and the encoders like a Swagger 2.0 spec.
+type OpenAPI3 map[string]any
+
+var openAPI3Fields = []string{"openapi", "info", "servers", "tags", "paths", "components", "security", "externalDocs"}
+
+// MarshalJSON marshals the fields of the specification first, then the vendor extensions in increasing order.
+func (doc OpenAPI3) MarshalJSON() ([]byte, error) {
+	keys := make([]string, 0, len(doc))
+	for _, key := range openAPI3Fields {
+		if _, ok := doc[key]; ok {
+			keys = append(keys, key)
+		}
+	}
+	for _, key := range sortedKeys(doc) {
+		if !slices.Contains(openAPI3Fields, key) {
+			keys = append(keys, key)
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(doc[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// Run3 scans the packages like Run, and produces an OpenAPI 3.0 document instead of a Swagger 2.0 one.
+//
+// The annotations are the same: the spec built by the scan is converted, see UpgradeSwaggerFor, and the
+// constructs it drops are reported at their position in the Go sources.
+func Run3(opts *Options) (OpenAPI3, error) {
+	upgrading := *opts
+	if upgrading.SourceMap == nil {
+		upgrading.SourceMap = make(map[string]token.Position)
+	}
+	swspec, err := Run(&upgrading)
+	if err != nil {
+		return nil, err
+	}
+
+	return UpgradeSwaggerFor(swspec, &upgrading)
+}
+
+// UpgradeSwagger converts a Swagger 2.0 spec to OpenAPI 3.0 like UpgradeSwaggerFor, logging the constructs
+// dropped as warnings.
+func UpgradeSwagger(doc *spec.Swagger) (OpenAPI3, error) {
+	return UpgradeSwaggerFor(doc, &Options{})
+}
+
+// UpgradeSwaggerFor converts a Swagger 2.0 spec to OpenAPI 3.0, keeping its vendor extensions.
+//
+// The definitions, parameters, responses and security definitions become components, the body and
+// formData parameters become request bodies, with a content per media type of consumes, and the
+// schemas of the responses get a content per media type of produces, or the schema of their media type
+// in x-content-schemas. x-nullable becomes nullable, x-deprecated deprecated, x-one-of-types oneOf, the
+// x-summary and x-description of the path items their summary and description, and the named examples of
+// x-examples the examples of the media types of the request bodies and the responses.
+// The null of a type array becomes nullable, and its other types the members of a oneOf.
+//
+// Constructs without an OpenAPI 3.0 equivalent are dropped, each reported by a DiagnosticUnsupportedOpenAPI3
+// sent to Options.Logger, or logged as a warning without one, and added to Options.Diagnostics. Its position
+// is that of the element dropped in Options.SourceMap, if any. The Rules set its severity, and FailOnWarning
+// fails the conversion.
+func UpgradeSwaggerFor(doc *spec.Swagger, opts *Options) (OpenAPI3, error) {
+	_, configured, err := compileRules(opts.Rules)
+	if err != nil {
+		return nil, err
+	}
+	reporter := &typeIndex{severities: configured, logger: opts.Logger}
+	u := &upgrader{doc: doc, positions: opts.SourceMap, report: reporter.diagnoseMessage}
+	out := u.document()
+
+	if opts.Diagnostics != nil {
+		*opts.Diagnostics = append(*opts.Diagnostics, reporter.reportedDiagnostics()...)
+	}
+	if err := reporter.checkWarnings(opts.FailOnWarning); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// upgrader converts a Swagger 2.0 spec to OpenAPI 3.0, represented as generic JSON values.
+type upgrader struct {
+	doc       *spec.Swagger
+	positions map[string]token.Position // by JSON pointer, see Options.SourceMap
+	report    func(Diagnostic)
+}
+
+// warnf reports a construct dropped, at the position of the closest element of the spec with one.
+func (u *upgrader) warnf(location, format string, args ...any) {
+	diagnostic := Diagnostic{Code: DiagnosticUnsupportedOpenAPI3, Message: "openapi upgrade: " + fmt.Sprintf(format, args...)}
+	for {
+		if pos, found := u.positions[location]; found {
+			diagnostic.Pos = pos
+			break
+		}
+		parent := strings.LastIndexByte(location, '/')
+		if parent < 0 {
+			break
+		}
+		location = location[:parent]
+	}
+	u.report(diagnostic)
+}
+
+func (u *upgrader) document() OpenAPI3 {
+	doc := u.doc
+	out := OpenAPI3{"openapi": OpenAPI3Version}
+	copyVendorExtensions(out, doc.Extensions)
+	if doc.ID != "" {
+		u.warnf("", "dropped unsupported top-level field %q", "id")
+	}
+	if doc.Info != nil {
+		out["info"] = upgradeInfo(doc.Info)
+	}
+	if len(doc.Tags) > 0 {
+		tags := make([]any, 0, len(doc.Tags))
+		for _, tag := range doc.Tags {
+			converted := make(map[string]any)
+			setIf(converted, "name", tag.Name)
+			setIf(converted, "description", tag.Description)
+			if tag.ExternalDocs != nil {
+				converted["externalDocs"] = upgradeExternalDocs(tag.ExternalDocs)
+			}
+			copyVendorExtensions(converted, tag.Extensions)
+			tags = append(tags, converted)
+		}
+		out["tags"] = tags
+	}
+	if doc.ExternalDocs != nil {
+		out["externalDocs"] = upgradeExternalDocs(doc.ExternalDocs)
+	}
+	if len(doc.Security) > 0 {
+		out["security"] = upgradeSecurity(doc.Security)
+	}
+	out["paths"] = u.paths(doc.Paths)
+	if servers := u.servers(doc.Schemes); len(servers) > 0 {
+		out["servers"] = servers
+	}
+	if components := u.components(); len(components) > 0 {
+		out["components"] = components
+	}
+
+	return out
+}
+
+// servers derives the server URLs from the host, base path and schemes. Without a host, the URL is
+// relative to the location of the document.
+func (u *upgrader) servers(schemes []string) []any {
+	url := u.doc.BasePath
+	if u.doc.Host != "" {
+		url = "//" + u.doc.Host + u.doc.BasePath
+	}
+	if url == "" {
+		return nil
+	}
+	if u.doc.Host == "" || len(schemes) == 0 {
+		return []any{map[string]any{"url": url}}
+	}
+
+	servers := make([]any, 0, len(schemes))
+	for _, scheme := range schemes {
+		servers = append(servers, map[string]any{"url": scheme + ":" + url})
+	}
+
+	return servers
+}
+
+func (u *upgrader) components() map[string]any {
+	doc := u.doc
+	components := make(map[string]any)
+
+	if len(doc.Definitions) > 0 {
+		schemas := make(map[string]any, len(doc.Definitions))
+		for _, name := range sortedKeys(doc.Definitions) {
+			schema := doc.Definitions[name]
+			schemas[name] = u.schema(&schema, definitionsPrefix[1:]+escapePointer(name))
+		}
+		components["schemas"] = schemas
+	}
+
+	parameters := make(map[string]any)
+	requestBodies := make(map[string]any)
+	for _, name := range sortedKeys(doc.Parameters) {
+		param := doc.Parameters[name]
+		location := parametersPrefix[1:] + escapePointer(name)
+		switch param.In {
+		case "body":
+			requestBodies[name] = u.bodyRequest(bodyParameter{&param, location}, doc.Consumes)
+		case "formData":
+			// inlined in the request bodies of the operations
+		default:
+			parameters[name] = u.parameter(&param, location)
+		}
+	}
+	if len(parameters) > 0 {
+		components["parameters"] = parameters
+	}
+	if len(requestBodies) > 0 {
+		components["requestBodies"] = requestBodies
+	}
+
+	if len(doc.Responses) > 0 {
+		responses := make(map[string]any, len(doc.Responses))
+		for _, name := range sortedKeys(doc.Responses) {
+			response := doc.Responses[name]
+			responses[name] = u.response(name, &response, doc.Produces, responsesPrefix[1:]+escapePointer(name))
+		}
+		components["responses"] = responses
+	}
+
+	if len(doc.SecurityDefinitions) > 0 {
+		schemes := make(map[string]any, len(doc.SecurityDefinitions))
+		for _, name := range sortedKeys(doc.SecurityDefinitions) {
+			if converted := u.securityScheme(name, doc.SecurityDefinitions[name]); converted != nil {
+				schemes[name] = converted
+			}
+		}
+		components["securitySchemes"] = schemes
+	}
+
+	return components
+}
+
+func (u *upgrader) securityScheme(name string, scheme *spec.SecurityScheme) map[string]any {
+	location := "/securityDefinitions/" + escapePointer(name)
+	result := make(map[string]any)
+	setIf(result, "description", scheme.Description)
+	copyVendorExtensions(result, scheme.Extensions)
+
+	switch scheme.Type {
+	case "basic":
+		result["type"] = "http"
+		result["scheme"] = "basic"
+	case "apiKey":
+		result["type"] = "apiKey"
+		setIf(result, "name", scheme.Name)
+		setIf(result, "in", scheme.In)
+	case "oauth2":
+		flow, ok := map[string]string{
+			"accessCode":  "authorizationCode",
+			"implicit":    "implicit",
+			"password":    "password",
+			"application": "clientCredentials",
+		}[scheme.Flow]
+		if !ok {
+			u.warnf(location, "dropped security scheme %q: unsupported oauth2 flow %q", name, scheme.Flow)
+			return nil
+		}
+		scopes := make(map[string]any, len(scheme.Scopes))
+		for scope, description := range scheme.Scopes {
+			scopes[scope] = description
+		}
+		settings := map[string]any{"scopes": scopes}
+		setIf(settings, "authorizationUrl", scheme.AuthorizationURL)
+		setIf(settings, "tokenUrl", scheme.TokenURL)
+		result["type"] = "oauth2"
+		result["flows"] = map[string]any{flow: settings}
+	default:
+		u.warnf(location, "dropped security scheme %q: unsupported type %q", name, scheme.Type)
+		return nil
+	}
+
+	return result
+}
+
+func (u *upgrader) paths(paths *spec.Paths) map[string]any {
+	result := make(map[string]any)
+	if paths == nil {
+		return result
+	}
+	copyVendorExtensions(result, paths.Extensions)
+
+	for _, pth := range sortedKeys(paths.Paths) {
+		if !strings.HasPrefix(pth, "/") {
+			continue
+		}
+		item := paths.Paths[pth]
+		location := "/paths/" + escapePointer(pth)
+		shared, bodyParams := u.splitParameters(item.Parameters, location)
+		converted := make(map[string]any)
+		if ref := item.Ref.String(); ref != "" {
+			converted["$ref"] = ref
+		}
+		// the documentation of swagger:path is native to the path items of OpenAPI 3.0
+		copyVendorExtensions(converted, item.Extensions, xSummary, xDescription)
+		if summary, ok := item.Extensions.GetString(xSummary); ok {
+			converted["summary"] = summary
+		}
+		if description, ok := item.Extensions.GetString(xDescription); ok {
+			converted["description"] = description
+		}
+		if len(shared) > 0 {
+			converted["parameters"] = shared
+		}
+		for method, op := range pathItemOperations(&item) {
+			converted[method] = u.operation(strings.ToUpper(method)+" "+pth, op, bodyParams, location+"/"+method)
+		}
+		result[pth] = converted
+	}
+
+	return result
+}
+
+// bodyParameter is a body or formData parameter, with its location in the spec.
+type bodyParameter struct {
+	*spec.Parameter
+	location string
+}
+
+// splitParameters separates the body and formData parameters, which make the request body, from the others.
+func (u *upgrader) splitParameters(params []spec.Parameter, location string) ([]any, []bodyParameter) {
+	var parameters []any
+	var bodyParams []bodyParameter
+	for i := range params {
+		param := &params[i]
+		paramLocation := location + "/parameters/" + strconv.Itoa(i)
+		switch u.resolveParameter(param).In {
+		case "body", "formData":
+			bodyParams = append(bodyParams, bodyParameter{param, paramLocation})
+		default:
+			parameters = append(parameters, u.parameter(param, paramLocation))
+		}
+	}
+
+	return parameters, bodyParams
+}
+
+func (u *upgrader) operation(name string, op *spec.Operation, shared []bodyParameter, location string) map[string]any {
+	result := make(map[string]any)
+	parameters, bodyParams := u.splitParameters(op.Parameters, location)
+	bodyParams = append(slices.Clone(shared), bodyParams...)
+	consumes, produces := u.doc.Consumes, u.doc.Produces
+	if len(op.Consumes) > 0 {
+		consumes = op.Consumes
+	}
+	if len(op.Produces) > 0 {
+		produces = op.Produces
+	}
+
+	copyVendorExtensions(result, op.Extensions)
+	if len(op.Tags) > 0 {
+		result["tags"] = anyStrings(op.Tags)
+	}
+	setIf(result, "summary", op.Summary)
+	setIf(result, "description", op.Description)
+	setIf(result, "operationId", op.ID)
+	setIf(result, "deprecated", op.Deprecated)
+	if op.ExternalDocs != nil {
+		result["externalDocs"] = upgradeExternalDocs(op.ExternalDocs)
+	}
+	if op.Security != nil {
+		// an empty list of requirements removes the top-level ones
+		result["security"] = upgradeSecurity(op.Security)
+	}
+	if len(op.Schemes) > 0 {
+		if servers := u.servers(op.Schemes); len(servers) > 0 {
+			result["servers"] = servers
+		}
+	}
+	if len(parameters) > 0 {
+		result["parameters"] = parameters
+	}
+	if body := u.requestBody(bodyParams, consumes); body != nil {
+		result["requestBody"] = body
+	}
+	if op.Responses != nil {
+		result["responses"] = u.responses(name, op.Responses, produces, location+"/responses")
+	}
+
+	return result
+}
+
+func (u *upgrader) responses(name string, responses *spec.Responses, produces []string, location string) map[string]any {
+	result := make(map[string]any)
+	copyVendorExtensions(result, responses.Extensions)
+	if responses.Default != nil {
+		result["default"] = u.response(name+" default", responses.Default, produces, location+"/default")
+	}
+	for _, code := range sortedKeys(responses.StatusCodeResponses) {
+		response := responses.StatusCodeResponses[code]
+		status := strconv.Itoa(code)
+		result[status] = u.response(name+" "+status, &response, produces, location+"/"+status)
+	}
+
+	return result
+}
+
+// resolveParameter follows a $ref to the parameters of the spec if any.
+func (u *upgrader) resolveParameter(param *spec.Parameter) *spec.Parameter {
+	name, isRef := strings.CutPrefix(param.Ref.String(), parametersPrefix)
+	if !isRef {
+		return param
+	}
+	if target, found := u.doc.Parameters[unescapePointer(name)]; found {
+		return &target
+	}
+
+	return param
+}
+
+func (u *upgrader) parameter(param *spec.Parameter, location string) map[string]any {
+	if ref := param.Ref.String(); ref != "" {
+		return map[string]any{"$ref": upgradeRef(ref)}
+	}
+
+	result := make(map[string]any)
+	setIf(result, "name", param.Name)
+	setIf(result, "in", param.In)
+	setIf(result, "description", param.Description)
+	setIf(result, "required", param.Required)
+	setIf(result, "allowEmptyValue", param.AllowEmptyValue)
+	copyVendorExtensions(result, param.Extensions, "x-example", "x-nullable", deprecatedExtension)
+
+	schema := simpleSchema(&param.SimpleSchema, &param.CommonValidations)
+	if nullable, _ := param.Extensions["x-nullable"].(bool); nullable {
+		schema["nullable"] = true
+	}
+	if deprecated, _ := param.Extensions[deprecatedExtension].(bool); deprecated {
+		result["deprecated"] = true
+	}
+	result["schema"] = schema
+	if example, ok := param.Extensions["x-example"]; ok {
+		result["example"] = genericJSON(example)
+	}
+
+	if param.Type == "array" {
+		switch param.CollectionFormat {
+		case "multi":
+			result["style"] = "form"
+			result["explode"] = true
+		case "ssv":
+			result["style"] = "spaceDelimited"
+			result["explode"] = false
+		case "pipes":
+			result["style"] = "pipeDelimited"
+			result["explode"] = false
+		case "", "csv":
+			// the default style of the path and header parameters is simple, and the one of the query
+			// parameters is exploded
+			if param.In == "query" {
+				result["style"] = "form"
+				result["explode"] = false
+			}
+		default:
+			u.warnf(location, "parameter %q: dropped unsupported collection format %q", param.Name, param.CollectionFormat)
+		}
+	}
+
+	return result
+}
+
+// simpleSchema converts the type of a non-body parameter, items or header to a schema.
+func simpleSchema(simple *spec.SimpleSchema, validations *spec.CommonValidations) map[string]any {
+	result := make(map[string]any)
+	setIf(result, "type", simple.Type)
+	setIf(result, "format", simple.Format)
+	if simple.Default != nil {
+		result["default"] = genericJSON(simple.Default)
+	}
+	setPointer(result, "maximum", validations.Maximum)
+	setIf(result, "exclusiveMaximum", validations.ExclusiveMaximum)
+	setPointer(result, "minimum", validations.Minimum)
+	setIf(result, "exclusiveMinimum", validations.ExclusiveMinimum)
+	setPointer(result, "maxLength", validations.MaxLength)
+	setPointer(result, "minLength", validations.MinLength)
+	setIf(result, "pattern", validations.Pattern)
+	setPointer(result, "maxItems", validations.MaxItems)
+	setPointer(result, "minItems", validations.MinItems)
+	setIf(result, "uniqueItems", validations.UniqueItems)
+	setPointer(result, "multipleOf", validations.MultipleOf)
+	if len(validations.Enum) > 0 {
+		result["enum"] = genericJSON(validations.Enum)
+	}
+	if items := simple.Items; items != nil {
+		converted := simpleSchema(&items.SimpleSchema, &items.CommonValidations)
+		copyVendorExtensions(converted, items.Extensions)
+		result["items"] = converted
+	}
+	if simple.Type == "file" {
+		result["type"] = "string"
+		result["format"] = "binary"
+	}
+
+	return result
+}
+
+func (u *upgrader) requestBody(params []bodyParameter, consumes []string) map[string]any {
+	if len(params) == 0 {
+		return nil
+	}
+	for _, param := range params {
+		if u.resolveParameter(param.Parameter).In != "body" {
+			continue
+		}
+		if ref, isRef := strings.CutPrefix(param.Ref.String(), parametersPrefix); isRef {
+			return map[string]any{"$ref": "#/components/requestBodies/" + ref}
+		}
+		return u.bodyRequest(param, consumes)
+	}
+
+	return u.formRequest(params, consumes)
+}
+
+// bodyRequest converts a body parameter to a request body, with the same schema for every media type.
+func (u *upgrader) bodyRequest(param bodyParameter, consumes []string) map[string]any {
+	body := make(map[string]any)
+	setIf(body, "description", param.Description)
+	setIf(body, "required", param.Required)
+	copyVendorExtensions(body, param.Extensions, namedExamplesExtension)
+	if param.Name != "" && param.Name != "body" {
+		body["x-codegen-request-body-name"] = param.Name
+	}
+
+	schema := map[string]any{}
+	if param.Schema != nil {
+		schema = u.schema(param.Schema, param.location+"/schema")
+	}
+	examples := asObject(genericJSON(param.Extensions[namedExamplesExtension]))
+	content := make(map[string]any)
+	for _, mediaType := range mediaTypesOr(consumes, "application/json") {
+		media := map[string]any{"schema": schema}
+		if len(examples) > 0 {
+			media["examples"] = examples
+		}
+		content[mediaType] = media
+	}
+	body["content"] = content
+
+	return body
+}
+
+// formRequest converts the formData parameters to a request body with an object schema, for the form
+// media types of consumes.
+func (u *upgrader) formRequest(params []bodyParameter, consumes []string) map[string]any {
+	properties := make(map[string]any, len(params))
+	var required []any
+	hasFile := false
+	for _, value := range params {
+		param := u.resolveParameter(value.Parameter)
+		prop := simpleSchema(&param.SimpleSchema, &param.CommonValidations)
+		hasFile = hasFile || param.Type == "file"
+		if multiple, _ := param.Extensions[multipleFilesExtension].(bool); multiple {
+			prop = map[string]any{"type": "array", "items": prop}
+		}
+		setIf(prop, "description", param.Description)
+		copyVendorExtensions(prop, param.Extensions, multipleFilesExtension)
+		properties[param.Name] = prop
+		if param.Required {
+			required = append(required, param.Name)
+		}
+	}
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	var mediaTypes []string
+	for _, mediaType := range consumes {
+		if mediaType == "multipart/form-data" || mediaType == "application/x-www-form-urlencoded" {
+			mediaTypes = append(mediaTypes, mediaType)
+		}
+	}
+	switch {
+	case len(mediaTypes) > 0:
+	case hasFile:
+		mediaTypes = []string{"multipart/form-data"}
+	default:
+		mediaTypes = []string{"application/x-www-form-urlencoded"}
+	}
+	content := make(map[string]any, len(mediaTypes))
+	for _, mediaType := range mediaTypes {
+		content[mediaType] = map[string]any{"schema": schema}
+	}
+
+	return map[string]any{"content": content}
+}
+
+func (u *upgrader) response(name string, response *spec.Response, produces []string, location string) map[string]any {
+	if ref := response.Ref.String(); ref != "" {
+		return map[string]any{"$ref": upgradeRef(ref)}
+	}
+
+	result := map[string]any{"description": response.Description}
+	copyVendorExtensions(result, response.Extensions, xContentSchemas, namedExamplesExtension)
+
+	schemas, _ := decodeExtension[map[string]*spec.Schema](response.Extensions, xContentSchemas)
+	named := asObject(genericJSON(response.Extensions[namedExamplesExtension]))
+	if response.Schema != nil || len(schemas) > 0 || len(named) > 0 {
+		var converted map[string]any
+		if response.Schema != nil {
+			converted = u.schema(response.Schema, location+"/schema")
+		}
+		mediaTypes := mediaTypesOr(produces, "application/json")
+		for _, mediaType := range sortedKeys(schemas) {
+			if !slices.Contains(mediaTypes, mediaType) {
+				mediaTypes = append(slices.Clip(mediaTypes), mediaType)
+			}
+		}
+		content := make(map[string]any, len(mediaTypes))
+		for _, mediaType := range mediaTypes {
+			media := map[string]any{}
+			if schema, specific := schemas[mediaType]; specific {
+				media["schema"] = u.schema(schema, location+"/"+xContentSchemas+"/"+escapePointer(mediaType))
+			} else if response.Schema != nil {
+				media["schema"] = converted
+			}
+			if example, ok := response.Examples[mediaType]; ok && len(named) > 0 {
+				u.warnf(location, "response %s: dropped the example of %s for its named examples", name, mediaType)
+			} else if ok {
+				media["example"] = genericJSON(example)
+			}
+			if len(named) > 0 {
+				media["examples"] = named
+			}
+			content[mediaType] = media
+		}
+		for _, mediaType := range sortedKeys(response.Examples) {
+			if !slices.Contains(mediaTypes, mediaType) {
+				u.warnf(location, "response %s: dropped the example of %s, which is not produced", name, mediaType)
+			}
+		}
+		result["content"] = content
+	}
+
+	if len(response.Headers) > 0 {
+		headers := make(map[string]any, len(response.Headers))
+		for _, name := range sortedKeys(response.Headers) {
+			header := response.Headers[name]
+			converted := map[string]any{"schema": simpleSchema(&header.SimpleSchema, &header.CommonValidations)}
+			setIf(converted, "description", header.Description)
+			copyVendorExtensions(converted, header.Extensions)
+			headers[name] = converted
+		}
+		result["headers"] = headers
+	}
+
+	return result
+}
+
+// unsupportedSchemaKeywords are the keywords of the swagger 2.0 schemas, e.g. of an input spec, which the
+// OpenAPI 3.0 schemas don't have.
+var unsupportedSchemaKeywords = []string{"id", "$schema", "patternProperties", "dependencies", "additionalItems", "definitions"}
+
+func (u *upgrader) schema(schema *spec.Schema, location string) map[string]any {
+	if schema == nil {
+		return nil
+	}
+
+	result := make(map[string]any)
+	if ref := schema.Ref.String(); ref != "" {
+		result["$ref"] = upgradeRef(ref)
+	}
+	// the union types of x-one-of-types are the oneOf of OpenAPI 3.0
+	union, _ := decodeExtension[[]spec.Schema](schema.Extensions, xOneOfTypes)
+	// OpenAPI 3.0 has no type arrays: null becomes nullable, and the other types the members of a oneOf
+	var types []string
+	for _, typ := range schema.Type {
+		if typ == "null" {
+			result["nullable"] = true
+			continue
+		}
+		types = append(types, typ)
+	}
+	var typeMembers []spec.Schema
+	switch {
+	case len(types) == 1:
+		result["type"] = types[0]
+	case len(types) > 1 && len(schema.OneOf)+len(union) > 0:
+		u.warnf(location+"/type", "the types %v of a schema with a oneOf are not supported by openapi 3.0: dropped", types)
+	case len(types) > 1:
+		for _, typ := range types {
+			typeMembers = append(typeMembers, *new(spec.Schema).Typed(typ, ""))
+		}
+	}
+	setIf(result, "title", schema.Title)
+	setIf(result, "description", schema.Description)
+	setIf(result, "format", schema.Format)
+	if schema.Default != nil {
+		result["default"] = genericJSON(schema.Default)
+	}
+	setPointer(result, "maximum", schema.Maximum)
+	setIf(result, "exclusiveMaximum", schema.ExclusiveMaximum)
+	setPointer(result, "minimum", schema.Minimum)
+	setIf(result, "exclusiveMinimum", schema.ExclusiveMinimum)
+	setPointer(result, "maxLength", schema.MaxLength)
+	setPointer(result, "minLength", schema.MinLength)
+	setIf(result, "pattern", schema.Pattern)
+	setPointer(result, "maxItems", schema.MaxItems)
+	setPointer(result, "minItems", schema.MinItems)
+	setIf(result, "uniqueItems", schema.UniqueItems)
+	setPointer(result, "multipleOf", schema.MultipleOf)
+	if len(schema.Enum) > 0 {
+		result["enum"] = genericJSON(schema.Enum)
+	}
+	setPointer(result, "maxProperties", schema.MaxProperties)
+	setPointer(result, "minProperties", schema.MinProperties)
+	if len(schema.Required) > 0 {
+		result["required"] = anyStrings(schema.Required)
+	}
+
+	if items := schema.Items; items != nil {
+		switch {
+		case len(items.Schemas) > 0:
+			u.warnf(location+"/items", "tuple items are not supported by openapi 3.0: only the first item is retained")
+			result["items"] = u.schema(&items.Schemas[0], location+"/items/0")
+		case items.Schema != nil:
+			result["items"] = u.schema(items.Schema, location+"/items")
+		}
+	}
+	for _, composition := range []struct {
+		keyword string
+		members []spec.Schema
+	}{
+		{"allOf", schema.AllOf},
+		{"oneOf", slices.Concat(schema.OneOf, union, typeMembers)},
+		{"anyOf", schema.AnyOf},
+	} {
+		if len(composition.members) == 0 {
+			continue
+		}
+		members := make([]any, 0, len(composition.members))
+		for i := range composition.members {
+			members = append(members, u.schema(&composition.members[i], location+"/"+composition.keyword+"/"+strconv.Itoa(i)))
+		}
+		result[composition.keyword] = members
+	}
+	if schema.Not != nil {
+		result["not"] = u.schema(schema.Not, location+"/not")
+	}
+	if len(schema.Properties) > 0 {
+		properties := make(map[string]any, len(schema.Properties))
+		for _, name := range sortedKeys(schema.Properties) {
+			prop := schema.Properties[name]
+			properties[name] = u.schema(&prop, location+"/properties/"+escapePointer(name))
+		}
+		result["properties"] = properties
+	}
+	if additional := schema.AdditionalProperties; additional != nil {
+		if additional.Schema != nil {
+			result["additionalProperties"] = u.schema(additional.Schema, location+"/additionalProperties")
+		} else {
+			result["additionalProperties"] = additional.Allows
+		}
+	}
+
+	if schema.Discriminator != "" {
+		result["discriminator"] = map[string]any{"propertyName": schema.Discriminator}
+	}
+	setIf(result, "readOnly", schema.ReadOnly)
+	if xml := schema.XML; xml != nil {
+		converted := make(map[string]any)
+		setIf(converted, "name", xml.Name)
+		setIf(converted, "namespace", xml.Namespace)
+		setIf(converted, "prefix", xml.Prefix)
+		setIf(converted, "attribute", xml.Attribute)
+		setIf(converted, "wrapped", xml.Wrapped)
+		result["xml"] = converted
+	}
+	if schema.ExternalDocs != nil {
+		result["externalDocs"] = upgradeExternalDocs(schema.ExternalDocs)
+	}
+	if schema.Example != nil {
+		result["example"] = genericJSON(schema.Example)
+	}
+
+	copyVendorExtensions(result, schema.Extensions, "x-nullable", "x-isnullable", deprecatedExtension, xOneOfTypes)
+	for key, value := range schema.ExtraProps {
+		result[key] = genericJSON(value)
+	}
+	nullable, _ := schema.Extensions["x-nullable"].(bool)
+	isNullable, _ := schema.Extensions["x-isnullable"].(bool)
+	if schema.Nullable || nullable || isNullable {
+		result["nullable"] = true
+	}
+	if deprecated, _ := schema.Extensions[deprecatedExtension].(bool); deprecated {
+		result["deprecated"] = true
+	}
+	if schema.Type.Contains("file") && len(schema.Type) == 1 {
+		result["type"] = "string"
+		result["format"] = "binary"
+	}
+	if ref, isRef := result["$ref"]; isRef && result["nullable"] == true {
+		// the siblings of a $ref are ignored
+		delete(result, "$ref")
+		result["allOf"] = []any{map[string]any{"$ref": ref}}
+	}
+
+	for _, keyword := range unsupportedSchemaKeywords {
+		if schemaHasKeyword(schema, keyword) {
+			u.warnf(location, "schema: dropped unsupported keyword %q", keyword)
+		}
+	}
+
+	return result
+}
+
+// schemaHasKeyword tells if a schema has one of the unsupportedSchemaKeywords.
+func schemaHasKeyword(schema *spec.Schema, keyword string) bool {
+	switch keyword {
+	case "id":
+		return schema.ID != ""
+	case "$schema":
+		return schema.Schema != ""
+	case "patternProperties":
+		return len(schema.PatternProperties) > 0
+	case "dependencies":
+		return len(schema.Dependencies) > 0
+	case "additionalItems":
+		return schema.AdditionalItems != nil
+	case "definitions":
+		return len(schema.Definitions) > 0
+	}
+
+	return false
+}
+
+func upgradeInfo(info *spec.Info) map[string]any {
+	result := make(map[string]any)
+	setIf(result, "title", info.Title)
+	setIf(result, "description", info.Description)
+	setIf(result, "termsOfService", info.TermsOfService)
+	setIf(result, "version", info.Version)
+	if contact := info.Contact; contact != nil {
+		converted := make(map[string]any)
+		setIf(converted, "name", contact.Name)
+		setIf(converted, "url", contact.URL)
+		setIf(converted, "email", contact.Email)
+		copyVendorExtensions(converted, contact.Extensions)
+		result["contact"] = converted
+	}
+	if license := info.License; license != nil {
+		converted := make(map[string]any)
+		setIf(converted, "name", license.Name)
+		setIf(converted, "url", license.URL)
+		copyVendorExtensions(converted, license.Extensions)
+		result["license"] = converted
+	}
+	copyVendorExtensions(result, info.Extensions)
+
+	return result
+}
+
+func upgradeExternalDocs(docs *spec.ExternalDocumentation) map[string]any {
+	result := make(map[string]any)
+	setIf(result, "description", docs.Description)
+	setIf(result, "url", docs.URL)
+
+	return result
+}
+
+func upgradeSecurity(requirements []map[string][]string) []any {
+	result := make([]any, 0, len(requirements))
+	for _, requirement := range requirements {
+		converted := make(map[string]any, len(requirement))
+		for name, scopes := range requirement {
+			converted[name] = anyStrings(scopes)
+		}
+		result = append(result, converted)
+	}
+
+	return result
+}
+
+func upgradeRef(ref string) string {
+	for _, prefix := range []struct{ from, to string }{
+		{definitionsPrefix, "#/components/schemas/"},
+		{parametersPrefix, "#/components/parameters/"},
+		{responsesPrefix, "#/components/responses/"},
+	} {
+		if name, ok := strings.CutPrefix(ref, prefix.from); ok {
+			return prefix.to + name
+		}
+	}
+
+	return ref
+}
+
+// copyVendorExtensions copies the vendor extensions of an element of the spec as generic JSON values, but
+// those skipped, converted by the caller.
+func copyVendorExtensions(dst map[string]any, extensions spec.Extensions, skipped ...string) {
+	for key, value := range extensions {
+		if rxAllowedExtensions.MatchString(key) && !slices.Contains(skipped, key) {
+			dst[key] = genericJSON(value)
+		}
+	}
+}
+
+// decodeExtension returns the value of an extension as a T: the value set by the scan, or its generic JSON
+// value, e.g. of an input spec, decoded.
+func decodeExtension[T any](extensions spec.Extensions, key string) (T, bool) {
+	var result T
+	value, ok := extensions[key]
+	if !ok {
+		return result, false
+	}
+	if typed, isTyped := value.(T); isTyped {
+		return typed, true
+	}
+
+	jazon, err := json.Marshal(value)
+	if err != nil {
+		return result, false
+	}
+	if err := json.Unmarshal(jazon, &result); err != nil {
+		return result, false
+	}
+
+	return result, true
+}
+
+// genericJSON returns a value held by the spec, e.g. an example or an extension set by the scan, as generic
+// JSON values. A value which doesn't marshal is kept, and fails the encoding of the document.
+func genericJSON(value any) any {
+	switch value.(type) {
+	case nil, bool, string, float64, json.Number:
+		return value
+	}
+
+	jazon, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var generic any
+	if err := decodeExample(jazon, &generic); err != nil {
+		return value
+	}
+
+	return generic
+}
+
+// setIf sets a field of a converted element, unless its value is the zero value, like the omitempty fields of
+// the spec.
+func setIf[T comparable](dst map[string]any, key string, value T) {
+	var zero T
+	if value != zero {
+		dst[key] = value
+	}
+}
+
+func setPointer[T any](dst map[string]any, key string, value *T) {
+	if value != nil {
+		dst[key] = *value
+	}
+}
+
+func anyStrings(values []string) []any {
+	if values == nil {
+		return nil
+	}
+	result := make([]any, 0, len(values))
+	for _, value := range values {
+		result = append(result, value)
+	}
+
+	return result
+}
+
+func mediaTypesOr(mediaTypes []string, fallback string) []string {
+	if len(mediaTypes) == 0 {
+		return []string{fallback}
+	}
+
+	return mediaTypes
+}
@@ -0,0 +1,174 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"encoding/json"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAPI3(t *testing.T) {
+	const pkg = "github.com/3idey/codescan/fixtures/goparsing/openapi3"
+	dir := filepath.Join("..", "fixtures", "goparsing", "openapi3")
+	opts := func() *Options {
+		return &Options{Packages: []string{pkg}, SetXNullableForPointers: true}
+	}
+	golden := func(t *testing.T, name string, doc any) {
+		t.Helper()
+		output, err := MarshalJSON(doc, false)
+		require.NoError(t, err)
+		output = append(output, '\n')
+		file := filepath.Join(dir, name)
+		if updateGolden {
+			require.NoError(t, os.WriteFile(file, output, 0o600))
+		}
+		expected, err := os.ReadFile(file)
+		require.NoError(t, err)
+		assert.Equal(t, string(expected), string(output), "%s is out of date, run the tests with -update-golden", name)
+	}
+
+	t.Run("should produce the swagger 2.0 document", func(t *testing.T) {
+		doc, err := Run(opts())
+		require.NoError(t, err)
+		golden(t, "swagger.json", doc)
+	})
+
+	t.Run("should produce the openapi 3.0 document from the same annotations", func(t *testing.T) {
+		doc, err := Run3(opts())
+		require.NoError(t, err)
+		golden(t, "openapi.json", doc)
+
+		assert.Equal(t, OpenAPI3Version, doc["openapi"])
+		assert.Equal(t, "public", doc["x-audience"], "the vendor extensions are kept")
+		createPet := asObject(asObject(asObject(doc["paths"])["/pets"])["post"])
+		assert.NotContains(t, createPet, "parameters", "the body parameter is the request body")
+		assert.Contains(t, asObject(asObject(createPet["requestBody"])["content"]), "application/xml")
+	})
+
+	t.Run("should round trip with the downgrade", func(t *testing.T) {
+		doc, err := Run(opts())
+		require.NoError(t, err)
+		upgraded, err := UpgradeSwagger(doc)
+		require.NoError(t, err)
+		data, err := json.Marshal(upgraded)
+		require.NoError(t, err)
+		downgraded, err := DowngradeOpenAPI3(data)
+		require.NoError(t, err)
+
+		assert.Equal(t, doc.Host, downgraded.Host)
+		assert.Equal(t, doc.BasePath, downgraded.BasePath)
+		assert.ElementsMatch(t, doc.Schemes, downgraded.Schemes)
+		assert.Equal(t, sortedKeys(doc.Definitions), sortedKeys(downgraded.Definitions))
+		createPet := downgraded.Paths.Paths["/pets"].Post
+		require.Len(t, createPet.Parameters, 1)
+		assert.Equal(t, "pet", createPet.Parameters[0].Name)
+		assert.Equal(t, "body", createPet.Parameters[0].In)
+		assert.Equal(t, "#/definitions/Pet", createPet.Parameters[0].Schema.Ref.String())
+	})
+
+	t.Run("should convert the shared body parameters to request bodies", func(t *testing.T) {
+		doc := &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+			Swagger: "2.0",
+			Parameters: map[string]spec.Parameter{
+				"pet": *spec.BodyParam("pet", spec.RefSchema("#/definitions/Pet")),
+				"id":  *spec.PathParam("id").Typed("string", ""),
+			},
+			Paths: &spec.Paths{Paths: map[string]spec.PathItem{
+				"/pets/{id}": {PathItemProps: spec.PathItemProps{
+					Parameters: []spec.Parameter{*spec.ParamRef("#/parameters/id")},
+					Put: &spec.Operation{OperationProps: spec.OperationProps{
+						Parameters: []spec.Parameter{*spec.ParamRef("#/parameters/pet")},
+					}},
+				}},
+			}},
+		}}
+		upgraded, err := UpgradeSwagger(doc)
+		require.NoError(t, err)
+
+		components := asObject(upgraded["components"])
+		assert.Contains(t, asObject(components["requestBodies"]), "pet")
+		assert.Contains(t, asObject(components["parameters"]), "id")
+		item := asObject(asObject(upgraded["paths"])["/pets/{id}"])
+		assert.Equal(t, []any{map[string]any{"$ref": "#/components/parameters/id"}}, item["parameters"])
+		assert.Equal(t, map[string]any{"$ref": "#/components/requestBodies/pet"}, asObject(item["put"])["requestBody"])
+	})
+	t.Run("should report the constructs dropped with diagnostics", func(t *testing.T) {
+		doc := &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+			Swagger: "2.0",
+			SecurityDefinitions: spec.SecurityDefinitions{
+				"legacy": &spec.SecurityScheme{SecuritySchemeProps: spec.SecuritySchemeProps{Type: "oauth2", Flow: "device"}},
+			},
+			Definitions: spec.Definitions{
+				"Pair": {SchemaProps: spec.SchemaProps{
+					Type:  spec.StringOrArray{"array"},
+					Items: &spec.SchemaOrArray{Schemas: []spec.Schema{*spec.StringProperty(), *spec.Int64Property()}},
+				}},
+			},
+		}}
+		pos := token.Position{Filename: "api/doc.go", Line: 12, Column: 1}
+		var logged, diagnostics []Diagnostic
+		opts := &Options{
+			Logger:      func(diagnostic Diagnostic) { logged = append(logged, diagnostic) },
+			Diagnostics: &diagnostics,
+			SourceMap:   map[string]token.Position{"/securityDefinitions/legacy": pos},
+		}
+		upgraded, err := UpgradeSwaggerFor(doc, opts)
+		require.NoError(t, err)
+
+		assert.NotContains(t, asObject(asObject(upgraded["components"])["securitySchemes"]), "legacy")
+		assert.Equal(t, map[string]any{"type": "string"}, asObject(asObject(asObject(upgraded["components"])["schemas"])["Pair"])["items"])
+		require.Len(t, diagnostics, 2)
+		assert.Equal(t, logged, diagnostics)
+		for _, diagnostic := range diagnostics {
+			assert.Equal(t, DiagnosticUnsupportedOpenAPI3, diagnostic.Code)
+			assert.Equal(t, SeverityWarning, diagnostic.Severity)
+		}
+		assert.Contains(t, diagnostics[0].Message, "tuple items")
+		assert.Equal(t, pos, diagnostics[1].Pos, "the position is that of the security scheme")
+
+		opts = &Options{Logger: func(Diagnostic) {}, FailOnWarning: true}
+		_, err = UpgradeSwaggerFor(doc, opts)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "2 warnings, see FailOnWarning")
+
+		opts = &Options{Rules: []Rule{{Name: DiagnosticUnsupportedOpenAPI3, Severity: SeverityOff}}, FailOnWarning: true}
+		_, err = UpgradeSwaggerFor(doc, opts)
+		require.NoError(t, err, "a rule disables the diagnostics")
+	})
+	t.Run("should convert the type arrays, which openapi 3.0 doesn't have", func(t *testing.T) {
+		doc := &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+			Swagger: "2.0",
+			Definitions: spec.Definitions{
+				"Name":   {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string", "null"}}},
+				"Amount": {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string", "number", "null"}}},
+				"Choice": {SchemaProps: spec.SchemaProps{
+					Type:  spec.StringOrArray{"string", "integer"},
+					OneOf: []spec.Schema{*spec.StringProperty(), *spec.Int64Property()},
+				}},
+			},
+		}}
+		var diagnostics []Diagnostic
+		upgraded, err := UpgradeSwaggerFor(doc, &Options{Logger: func(Diagnostic) {}, Diagnostics: &diagnostics})
+		require.NoError(t, err)
+
+		schemas := asObject(asObject(upgraded["components"])["schemas"])
+		assert.Equal(t, map[string]any{"type": "string", "nullable": true}, schemas["Name"])
+		assert.Equal(t, map[string]any{
+			"nullable": true,
+			"oneOf":    []any{map[string]any{"type": "string"}, map[string]any{"type": "number"}},
+		}, schemas["Amount"])
+		assert.NotContains(t, asObject(schemas["Choice"]), "type")
+		assert.Len(t, asObject(schemas["Choice"])["oneOf"], 2)
+		require.Len(t, diagnostics, 1)
+		assert.Equal(t, DiagnosticUnsupportedOpenAPI3, diagnostics[0].Code)
+		assert.Contains(t, diagnostics[0].Message, "the types [string integer] of a schema with a oneOf")
+	})
+}
@@ -6,16 +6,19 @@ package codescan
 import (
 	"fmt"
 	"go/ast"
+	"go/token"
 	"regexp"
 	"strings"
 
 	"github.com/go-openapi/spec"
+	"golang.org/x/tools/go/packages"
 )
 
 type operationsBuilder struct {
 	ctx        *scanCtx
 	path       parsedPathContent
 	operations map[string]*spec.Operation
+	postDecls  []*entityDecl
 }
 
 func (o *operationsBuilder) Build(tgt *spec.Paths) error {
@@ -27,16 +30,40 @@ func (o *operationsBuilder) Build(tgt *spec.Paths) error {
 
 	op.Tags = o.path.Tags
 
-	sp := new(yamlSpecScanner)
+	sp := &yamlSpecScanner{preserveFormat: o.ctx.opts.PreserveCommentFormat}
 	sp.setTitle = func(lines []string) { op.Summary = joinDropLast(lines) }
 	sp.setDescription = func(lines []string) { op.Description = joinDropLast(lines) }
 
 	if err := sp.Parse(o.path.Remaining); err != nil {
 		return fmt.Errorf("operation (%s): %w", op.ID, err)
 	}
+	deprecateYAMLOperation(o.path.Remaining, op)
 	if err := sp.UnmarshalSpec(op.UnmarshalJSON); err != nil {
 		return fmt.Errorf("operation (%s): %w", op.ID, err)
 	}
+	o.ctx.deprecateOperation(o.path, op)
+	o.ctx.applyPathConstraints(o.path, op)
+	if len(op.Consumes) > 0 {
+		op.Consumes = normalizeMediaTypes(op.Consumes, "the consumes of operation "+op.ID)
+	}
+	if len(op.Produces) > 0 {
+		op.Produces = normalizeMediaTypes(op.Produces, "the produces of operation "+op.ID)
+	}
+	if len(op.Schemes) > 0 {
+		op.Schemes = normalizeSchemes(op.Schemes, "the schemes of operation "+op.ID)
+	}
+	o.ctx.app.recordPosition(&op.VendorExtensible, o.path.Pos)
+	o.ctx.app.checkIdempotencyKey(o.path.Method, op, o.path.Pos)
+	decls, err := o.ctx.inferErrorResponses(o.path, op)
+	if err != nil {
+		return err
+	}
+	o.postDecls = append(o.postDecls, decls...)
+	if decls, err = o.ctx.injectDefaultResponse(o.path, op); err != nil {
+		return err
+	}
+	o.postDecls = append(o.postDecls, decls...)
+	o.ctx.app.checkStatusCodes(o.path, op)
 
 	if tgt.Paths == nil {
 		tgt.Paths = make(map[string]spec.PathItem)
@@ -50,17 +77,31 @@ type parsedPathContent struct {
 	Method, Path, ID string
 	Tags             []string
 	Remaining        *ast.CommentGroup
+	Pos              token.Position // position of the annotation
+
+	annotation token.Pos
+	handler    *ast.FuncDecl // function documented by the annotation, if any
+	inferred   bool          // the route is registered with a router, see Options.RouterDiscovery
+	// undocumented tells that the handler of an inferred route has no doc comment, see Options.IncludeUndocumented
+	undocumented bool
+	file         *ast.File // file of the annotation, whose imports resolve the types it references
+	pkg          *packages.Package
+
+	// constraints are the regular expressions of the path parameters of the template, by name, e.g. [0-9]+
+	// of /users/{id:[0-9]+}, see applyPathConstraints
+	constraints map[string]string
 }
 
 func parsePathAnnotation(annotation *regexp.Regexp, lines []*ast.Comment) (cnt parsedPathContent) {
 	var justMatched bool
 
 	for _, cmt := range lines {
-		txt := cmt.Text
-		for line := range strings.SplitSeq(txt, "\n") {
+		for line := range commentLines(cmt.Text) {
 			matches := annotation.FindStringSubmatch(line)
 			if len(matches) > 3 {
-				cnt.Method, cnt.Path, cnt.ID = matches[1], matches[2], matches[len(matches)-1]
+				cnt.Method, cnt.ID = matches[1], matches[len(matches)-1]
+				cnt.Path, cnt.constraints = splitPathConstraints(matches[2])
+				cnt.annotation = cmt.Slash
 				cnt.Tags = rxSpace.Split(matches[3], -1)
 				if len(matches[3]) == 0 {
 					cnt.Tags = nil
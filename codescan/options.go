@@ -0,0 +1,220 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrUnknownOption is returned when settings refer to an option this version does not know,
+// e.g. a config file written for a newer version.
+var ErrUnknownOption = errors.New("unknown option")
+
+// OptionsConflictError reports options which can't be used together.
+type OptionsConflictError struct {
+	Options []string // names of the fields of Options in conflict
+	Reason  string
+}
+
+func (e *OptionsConflictError) Error() string {
+	return fmt.Sprintf("options %s can't be used together: %s", strings.Join(e.Options, " and "), e.Reason)
+}
+
+// InvalidOptionError reports an option with an invalid value.
+type InvalidOptionError struct {
+	Option string // name of the field of Options
+	Err    error
+}
+
+func (e *InvalidOptionError) Error() string {
+	return fmt.Sprintf("invalid option %s: %v", e.Option, e.Err)
+}
+
+func (e *InvalidOptionError) Unwrap() error {
+	return e.Err
+}
+
+// NewOptions returns the default options, which are the zero value of Options.
+func NewOptions() *Options {
+	return &Options{}
+}
+
+// Validate rejects incoherent combinations of options, and invalid values.
+//
+// Every problem is reported, joined in the returned error, as an *OptionsConflictError or an *InvalidOptionError.
+func (o *Options) Validate() error {
+	var errs []error
+	conflict := func(reason string, options ...string) {
+		errs = append(errs, &OptionsConflictError{Options: options, Reason: reason})
+	}
+	invalid := func(option string, err error) {
+		errs = append(errs, &InvalidOptionError{Option: option, Err: err})
+	}
+
+	if o.TransparentAliases && o.RefAliases {
+		conflict("transparent aliases never produce a $ref", "TransparentAliases", "RefAliases")
+	}
+	if o.DescWithRef && o.WrapRefsForDescription {
+		conflict("the descriptions are next to the $refs with DescWithRef", "DescWithRef", "WrapRefsForDescription")
+	}
+	if len(o.RequiredFromPointersPackages) > 0 && !o.RequiredFromPointers {
+		conflict("the packages are ignored unless RequiredFromPointers is set", "RequiredFromPointersPackages", "RequiredFromPointers")
+	}
+	if common := intersection(o.IncludeTags, o.ExcludeTags); len(common) > 0 {
+		conflict(fmt.Sprintf("tags %s are both included and excluded", strings.Join(common, ", ")), "IncludeTags", "ExcludeTags")
+	}
+	if len(o.PackageAliases) > 0 && o.DefinitionNameTemplate == "" && o.DefinitionNaming != NamingCamelPkg {
+		conflict("the aliases are only used by DefinitionNameTemplate and the camel-pkg DefinitionNaming", "PackageAliases", "DefinitionNameTemplate")
+	}
+	var namers []string
+	for name, set := range map[string]bool{
+		"DefinitionNamer":        o.DefinitionNamer != nil,
+		"DefinitionNaming":       o.DefinitionNaming != "",
+		"DefinitionNameTemplate": o.DefinitionNameTemplate != "",
+	} {
+		if set {
+			namers = append(namers, name)
+		}
+	}
+	if len(namers) > 1 {
+		slices.Sort(namers)
+		conflict("the definitions are named by a single strategy", namers...)
+	}
+	if o.RequireAllIncludeTags && len(o.IncludeTags) == 0 && !slices.ContainsFunc(o.ForceIncludeDirs, func(dir ForceIncludeDir) bool {
+		return len(dir.IncludeTags) > 0
+	}) {
+		conflict("all the included tags are only required with IncludeTags", "RequireAllIncludeTags", "IncludeTags")
+	}
+	if len(o.AlsoScan) > 0 && !o.DefaultSkips {
+		conflict("all the directories are scanned unless DefaultSkips is set", "AlsoScan", "DefaultSkips")
+	}
+	if len(o.CodeSampleTemplates) > 0 && !o.CodeSamples {
+		conflict("the templates are ignored unless CodeSamples is set", "CodeSampleTemplates", "CodeSamples")
+	}
+	if o.MarkUntranslated && o.DescriptionCatalog == nil {
+		conflict("there is nothing to translate without DescriptionCatalog", "MarkUntranslated", "DescriptionCatalog")
+	}
+	if o.PruneInput && !o.PruneUnused && o.UnusedDefinitions == nil {
+		conflict("the input spec is only pruned with PruneUnused", "PruneInput", "PruneUnused")
+	}
+	if o.ScanModels && o.PruneUnused {
+		conflict("the models no operation uses, which ScanModels adds, are removed by PruneUnused", "ScanModels", "PruneUnused")
+	}
+	if o.IncludeUndocumented && o.RouterDiscovery == "" {
+		conflict("only the routes of RouterDiscovery can be undocumented", "IncludeUndocumented", "RouterDiscovery")
+	}
+	if o.RequireAudience && len(o.Audience) == 0 {
+		conflict("operations are only filtered by audience with Audience", "RequireAudience", "Audience")
+	}
+	if common := intersection(o.Include, o.Exclude); len(common) > 0 {
+		conflict(fmt.Sprintf("packages %s are both included and excluded", strings.Join(common, ", ")), "Include", "Exclude")
+	}
+
+	for _, dir := range o.ForceIncludeDirs {
+		if dir.Dir == "" {
+			invalid("ForceIncludeDirs", errors.New("force include directory must not be empty"))
+		}
+	}
+	for _, pattern := range sortedKeys(o.RateLimits) {
+		var err error
+		if pattern == defaultRateLimit {
+			_, err = parseRateLimit(o.RateLimits[pattern])
+		} else {
+			_, err = parseRateLimitRule(pattern, o.RateLimits[pattern])
+		}
+		if err != nil {
+			invalid("RateLimits", err)
+		}
+	}
+	if o.RelativeRefs != "" {
+		if err := checkRefDocument(o.RelativeRefs); err != nil {
+			invalid("RelativeRefs", err)
+		}
+	}
+	if err := checkDefinitionIndex(o.UseDefinitionIndex); err != nil {
+		invalid("UseDefinitionIndex", err)
+	}
+	if _, err := codeSampleLanguages(o.CodeSampleTemplates); err != nil {
+		invalid("CodeSampleTemplates", err)
+	}
+	if err := checkEnumExtensionStyle(o.EnumExtensionStyle); err != nil {
+		invalid("EnumExtensionStyle", err)
+	}
+	if err := checkDefinitionNaming(o.DefinitionNaming); err != nil {
+		invalid("DefinitionNaming", err)
+	}
+	if err := checkMergeStrategy(o.MergeStrategy); err != nil {
+		invalid("MergeStrategy", err)
+	}
+	if err := checkCompat(o.Compat); err != nil {
+		invalid("Compat", err)
+	}
+	if err := checkEnv(o.Env); err != nil {
+		invalid("Env", err)
+	}
+	if err := checkTrailingSlash(o.TrailingSlash); err != nil {
+		invalid("TrailingSlash", err)
+	}
+	if _, err := newDefinitionNamer(&Options{DefinitionNameTemplate: o.DefinitionNameTemplate, PackageAliases: o.PackageAliases}); err != nil {
+		invalid("DefinitionNameTemplate", err)
+	}
+	if _, _, err := compileRules(o.Rules); err != nil {
+		invalid("Rules", err)
+	}
+	if _, err := compileSecretPatterns(o.SecretPatterns); err != nil {
+		invalid("SecretPatterns", err)
+	}
+	if err := checkSetTypes(o.SetTypes); err != nil {
+		invalid("SetTypes", err)
+	}
+	if err := checkTypeMappings(o.TypeMappings); err != nil {
+		invalid("TypeMappings", err)
+	}
+	if err := checkRouterDiscovery(o.RouterDiscovery); err != nil {
+		invalid("RouterDiscovery", err)
+	}
+	if err := checkSecurityDefaults(o.SecurityDefaults); err != nil {
+		invalid("SecurityDefaults", err)
+	}
+	if err := checkOutputSets(o.OutputSets); err != nil {
+		invalid("OutputSets", err)
+	}
+	if err := checkErrorHelpers(o.ErrorHelpers); err != nil {
+		invalid("ErrorHelpers", err)
+	}
+	if err := checkDefaultResponse(o.DefaultResponse); err != nil {
+		invalid("DefaultResponse", err)
+	}
+	if err := checkHoistParameters(o.HoistParameters); err != nil {
+		invalid("HoistParameters", err)
+	}
+	for _, name := range sortedKeys(o.DefinitionRenames) {
+		if err := checkDefinitionName(o.DefinitionRenames[name]); err != nil {
+			invalid("DefinitionRenames", fmt.Errorf("invalid name %q for definition %s: %w", o.DefinitionRenames[name], name, err))
+		}
+	}
+	if o.MaxSchemaDepth < 0 {
+		invalid("MaxSchemaDepth", fmt.Errorf("maximum schema depth must not be negative, got %d", o.MaxSchemaDepth))
+	}
+	if o.Concurrency < 0 {
+		invalid("Concurrency", fmt.Errorf("concurrency must not be negative, got %d", o.Concurrency))
+	}
+
+	return errors.Join(errs...)
+}
+
+// intersection returns the values found in both lists, sorted.
+func intersection(a, b []string) []string {
+	var common []string
+	for _, value := range a {
+		if slices.Contains(b, value) && !slices.Contains(common, value) {
+			common = append(common, value)
+		}
+	}
+	slices.Sort(common)
+	return common
+}
@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewOptions(t *testing.T) {
+	opts := NewOptions()
+	assert.Equal(t, &Options{}, opts, "the defaults are the zero value")
+	require.NoError(t, opts.Validate())
+}
+
+func TestOptionsValidate(t *testing.T) {
+	opts := &Options{
+		RefAliases:                   true,
+		TransparentAliases:           true,
+		RequiredFromPointersPackages: []string{"github.com/acme/api/..."},
+		IncludeTags:                  []string{"users", "admin"},
+		ExcludeTags:                  []string{"admin"},
+		ForceIncludeDirs:             []ForceIncludeDir{{}},
+		RateLimits:                   map[string]string{"default": "100/min", "GET /users": "lots"},
+		RelativeRefs:                 "swagger.json#",
+		UseDefinitionIndex:           &DefinitionIndex{},
+		AlsoScan:                     []string{"vendor/github.com/acme/..."},
+		MaxSchemaDepth:               -1,
+		RequireAudience:              true,
+		ScanModels:                   true,
+		PruneUnused:                  true,
+	}
+
+	err := opts.Validate()
+	require.Error(t, err)
+	errs := err.(interface{ Unwrap() []error }).Unwrap()
+
+	var conflicts [][]string
+	var invalid []string
+	for _, err := range errs {
+		var conflictErr *OptionsConflictError
+		var invalidErr *InvalidOptionError
+		switch {
+		case errors.As(err, &conflictErr):
+			conflicts = append(conflicts, conflictErr.Options)
+		case errors.As(err, &invalidErr):
+			invalid = append(invalid, invalidErr.Option)
+		default:
+			t.Errorf("unexpected error type %T", err)
+		}
+	}
+
+	assert.Equal(t, [][]string{
+		{"TransparentAliases", "RefAliases"},
+		{"RequiredFromPointersPackages", "RequiredFromPointers"},
+		{"IncludeTags", "ExcludeTags"},
+		{"AlsoScan", "DefaultSkips"},
+		{"ScanModels", "PruneUnused"},
+		{"RequireAudience", "Audience"},
+	}, conflicts)
+	assert.Equal(t, []string{"ForceIncludeDirs", "RateLimits", "RelativeRefs", "UseDefinitionIndex", "MaxSchemaDepth"}, invalid)
+	assert.Contains(t, err.Error(), "tags admin are both included and excluded")
+}
+
+func TestOptionsValidateRequireAllIncludeTags(t *testing.T) {
+	var conflict *OptionsConflictError
+	err := (&Options{RequireAllIncludeTags: true, ExcludeTags: []string{"internal"}}).Validate()
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, []string{"RequireAllIncludeTags", "IncludeTags"}, conflict.Options)
+
+	require.NoError(t, (&Options{RequireAllIncludeTags: true, IncludeTags: []string{"users"}}).Validate())
+	require.NoError(t, (&Options{
+		RequireAllIncludeTags: true,
+		ForceIncludeDirs:      []ForceIncludeDir{{Dir: "gen", IncludeTags: []string{"users"}}},
+	}).Validate())
+}
@@ -0,0 +1,335 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/go-openapi/spec"
+	"gopkg.in/yaml.v3"
+)
+
+// unsettableOptions are the fields of Options an options file can't set: the results of the scan, the
+// callbacks and the loader, the overlay of the editors, and the output sets, whose specs are written by the
+// caller.
+var unsettableOptions = []string{
+	"DefinitionPositions", "OnProgress", "Stats", "DefinitionIndex", "SourceMap", "Diagnostics", "Suppressions",
+	"Logger", "OutputSets", "OutputSetSpecs", "UnusedDefinitions", "DefinitionNamer", "Overlay", "Loader",
+}
+
+// commandKeys are the top-level keys of the settings of the codescan command, which share its config file
+// with the options and are left to it.
+var commandKeys = []string{"generate", "services"}
+
+// OptionsFileKey returns the key setting a field of Options in an options file, e.g. build_tags for
+// BuildTags, and false for the fields an options file can't set, e.g. Logger.
+func OptionsFileKey(field string) (string, bool) {
+	if _, exists := reflect.TypeFor[Options]().FieldByName(field); !exists || slices.Contains(unsettableOptions, field) {
+		return "", false
+	}
+	return optionKey(field), true
+}
+
+// optionKey is the snake case of the name of a field, e.g. set_x_nullable_for_pointers or strict_json_names.
+func optionKey(field string) string {
+	runes := []rune(field)
+	var key strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) && (!unicode.IsUpper(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			key.WriteByte('_')
+		}
+		key.WriteRune(unicode.ToLower(r))
+	}
+	return key.String()
+}
+
+// LoadOptionsFromFile reads the options of a YAML file, e.g. a .codescan.yaml shared with the codescan
+// command. The keys are those of OptionsFileKey, the keys of the structs are their fields in snake case
+// too, and the options which aren't set keep their zero value:
+//
+//	packages: [./...]
+//	build_tags: integration
+//	scan_models: true
+//	force_include_dirs:
+//	  - dir: gen/http
+//	    exclude_tags: [internal]
+//
+// A few options are given as text and parsed:
+//   - input_spec, meta, use_definition_index and description_catalog are the paths of their files, and
+//     code_sample_templates a directory of <language>.tmpl files, relative to the options file;
+//   - type_mappings are written like ParseTypeMapping, e.g. github.com/acme/money.Amount: string:decimal;
+//   - security_defaults are written like ParseSecurityRequirement, e.g. "oauth2: read:users".
+//
+// The keys generate and services, settings of the codescan command, are ignored. Every other unknown key is an
+// error wrapping ErrUnknownOption, with its line and the closest key. The options aren't validated, see
+// Options.Validate.
+func LoadOptionsFromFile(path string) (*Options, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseOptions(data, "options file "+path, filepath.Dir(path))
+}
+
+// parseOptions reads the options of the YAML of an options file, described by source in the errors, with the
+// paths relative to dir.
+func parseOptions(data []byte, source, dir string) (*Options, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", source, err)
+	}
+	opts := NewOptions()
+	if len(root.Content) == 0 {
+		return opts, nil
+	}
+	mapping := root.Content[0]
+	if mapping.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("invalid %s: line %d: expected a mapping of options", source, mapping.Line)
+	}
+
+	keys := optionsFileKeys()
+	value := reflect.ValueOf(opts).Elem()
+	var errs []error
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key, node := mapping.Content[i], mapping.Content[i+1]
+		if slices.Contains(commandKeys, key.Value) {
+			continue
+		}
+		field, known := keys[key.Value]
+		if !known {
+			errs = append(errs, unknownOption(key, "", slices.Collect(maps.Keys(keys))))
+			continue
+		}
+		if err := decodeOptionsField(node, value.FieldByName(field), key.Value, field, dir); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid %s: %w", source, errors.Join(errs...))
+	}
+
+	return opts, nil
+}
+
+// optionsFileKeys are the fields of Options set by the keys of an options file, by key.
+func optionsFileKeys() map[string]string {
+	optionsType := reflect.TypeFor[Options]()
+	keys := make(map[string]string, optionsType.NumField())
+	for i := range optionsType.NumField() {
+		if name := optionsType.Field(i).Name; optionsType.Field(i).IsExported() {
+			if key, settable := OptionsFileKey(name); settable {
+				keys[key] = name
+			}
+		}
+	}
+	return keys
+}
+
+// unknownOption reports an unknown key of an options file, of the struct at path unless on the top level.
+func unknownOption(key *yaml.Node, path string, known []string) error {
+	name := key.Value
+	if path != "" {
+		name = path + "." + key.Value
+	}
+	slices.Sort(known)
+	if closest := closestName(key.Value, known); closest != "" {
+		return fmt.Errorf("line %d: %w %q, did you mean %s?", key.Line, ErrUnknownOption, name, closest)
+	}
+	return fmt.Errorf("line %d: %w %q", key.Line, ErrUnknownOption, name)
+}
+
+// decodeOptionsField sets a field of Options from the node of its key.
+func decodeOptionsField(node *yaml.Node, field reflect.Value, key, name, dir string) error {
+	value, parsed, err := parseOptionsField(node, name, dir)
+	if !parsed {
+		return decodeOptionValue(node, field, key)
+	}
+	if err != nil {
+		return fmt.Errorf("line %d: %s: %w", node.Line, key, err)
+	}
+	field.Set(reflect.ValueOf(value))
+	return nil
+}
+
+// parseOptionsField parses the value of the options given as text, reading the files of those given by their
+// path, relative to dir. It returns false for the other options.
+func parseOptionsField(node *yaml.Node, name, dir string) (any, bool, error) {
+	resolve := func() (string, error) {
+		var path string
+		if err := node.Decode(&path); err != nil {
+			return "", err
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		return path, nil
+	}
+
+	var value any
+	switch name {
+	case "InputSpec", "Meta", "UseDefinitionIndex", "DescriptionCatalog":
+		path, err := resolve()
+		if err != nil {
+			return nil, true, err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, true, err
+		}
+		switch name {
+		case "InputSpec":
+			value, err = ParseInputSpec(data, false)
+		case "Meta":
+			value, err = ParseMeta(data)
+		case "UseDefinitionIndex":
+			index := new(DefinitionIndex)
+			value, err = index, json.Unmarshal(data, index)
+		default:
+			catalog := make(map[string]string)
+			value, err = catalog, yaml.Unmarshal(data, &catalog)
+		}
+		if err != nil {
+			return nil, true, fmt.Errorf("invalid file %s: %w", path, err)
+		}
+	case "CodeSampleTemplates":
+		path, err := resolve()
+		if err != nil {
+			return nil, true, err
+		}
+		if value, err = loadCodeSampleTemplates(path); err != nil {
+			return nil, true, err
+		}
+	case "TypeMappings":
+		var texts map[string]string
+		if err := node.Decode(&texts); err != nil {
+			return nil, true, err
+		}
+		mappings := make(map[string]spec.Schema, len(texts))
+		for _, goType := range slices.Sorted(maps.Keys(texts)) {
+			schema, err := ParseTypeMapping(texts[goType])
+			if err != nil {
+				return nil, true, fmt.Errorf("type mapping of %s: %w", goType, err)
+			}
+			mappings[goType] = schema
+		}
+		value = mappings
+	case "SecurityDefaults":
+		var texts []string
+		if err := node.Decode(&texts); err != nil {
+			return nil, true, err
+		}
+		requirements := make([]map[string][]string, 0, len(texts))
+		for _, text := range texts {
+			requirement, err := ParseSecurityRequirement(text)
+			if err != nil {
+				return nil, true, err
+			}
+			requirements = append(requirements, requirement)
+		}
+		value = requirements
+	default:
+		return nil, false, nil
+	}
+	return value, true, nil
+}
+
+// decodeOptionValue sets the value of the key at path from its node, e.g. rules[0].match: the structs are
+// mappings with the keys of their fields in snake case.
+func decodeOptionValue(node *yaml.Node, value reflect.Value, path string) error {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			return fmt.Errorf("line %d: %s: expected a mapping", node.Line, path)
+		}
+		fields := make(map[string]string, value.NumField())
+		for i := range value.NumField() {
+			if field := value.Type().Field(i); field.IsExported() {
+				fields[optionKey(field.Name)] = field.Name
+			}
+		}
+		var errs []error
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			field, known := fields[key.Value]
+			if !known {
+				errs = append(errs, unknownOption(key, path, slices.Collect(maps.Keys(fields))))
+				continue
+			}
+			if err := decodeOptionValue(node.Content[i+1], value.FieldByName(field), path+"."+key.Value); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	case reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			return fmt.Errorf("line %d: %s: expected a list", node.Line, path)
+		}
+		slice := reflect.MakeSlice(value.Type(), len(node.Content), len(node.Content))
+		var errs []error
+		for i, item := range node.Content {
+			errs = append(errs, decodeOptionValue(item, slice.Index(i), fmt.Sprintf("%s[%d]", path, i)))
+		}
+		value.Set(slice)
+		return errors.Join(errs...)
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			return fmt.Errorf("line %d: %s: expected a mapping", node.Line, path)
+		}
+		mapping := reflect.MakeMapWithSize(value.Type(), len(node.Content)/2)
+		var errs []error
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			item := reflect.New(value.Type().Elem()).Elem()
+			if err := decodeOptionValue(node.Content[i+1], item, path+"."+key); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			mapping.SetMapIndex(reflect.ValueOf(key).Convert(value.Type().Key()), item)
+		}
+		value.Set(mapping)
+		return errors.Join(errs...)
+	default:
+		if err := node.Decode(value.Addr().Interface()); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		return nil
+	}
+}
+
+// loadCodeSampleTemplates reads the <language>.tmpl files of a directory, by language.
+func loadCodeSampleTemplates(dir string) (map[string]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+	if err != nil {
+		return nil, err
+	}
+	if files == nil {
+		if _, err := os.Stat(dir); err != nil {
+			return nil, err
+		}
+	}
+
+	templates := make(map[string]string, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		templates[strings.TrimSuffix(filepath.Base(file), ".tmpl")] = string(data)
+	}
+
+	return templates, nil
+}
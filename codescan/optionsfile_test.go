@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeOptionsFile(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+	return filepath.Join(dir, ".codescan.yaml")
+}
+
+func TestLoadOptionsFromFile(t *testing.T) {
+	t.Run("should set the options of the keys", func(t *testing.T) {
+		path := writeOptionsFile(t, map[string]string{
+			".codescan.yaml": `
+packages: [./api/...]
+build_tags: integration
+scan_models: true
+max_schema_depth: 10
+set_x_nullable_for_pointers: true
+strict_json_names: true
+include_tags: [pets]
+force_include_dirs:
+  - dir: gen/http
+    exclude_tags: [internal]
+rules:
+  - match: operation
+    require: responses.204 exists
+rate_limits:
+  default: 100/min
+type_mappings:
+  github.com/acme/money.Amount: string:decimal
+security_defaults: ["oauth2: read:users"]
+input_spec: docs/input.json
+code_sample_templates: samples
+generate:
+  output: swagger.json
+services:
+  - name: payments
+`,
+			"docs/input.json":     `{"swagger": "2.0", "info": {"title": "Pets", "version": "1.0"}}`,
+			"samples/python.tmpl": `requests.get({{ printf "%q" .URL }})`,
+		})
+
+		opts, err := LoadOptionsFromFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"./api/..."}, opts.Packages)
+		assert.Equal(t, "integration", opts.BuildTags)
+		assert.True(t, opts.ScanModels)
+		assert.Equal(t, 10, opts.MaxSchemaDepth)
+		assert.True(t, opts.SetXNullableForPointers)
+		assert.True(t, opts.StrictJSONNames)
+		assert.Equal(t, []string{"pets"}, opts.IncludeTags)
+		assert.Equal(t, []ForceIncludeDir{{Dir: "gen/http", ExcludeTags: []string{"internal"}}}, opts.ForceIncludeDirs)
+		assert.Equal(t, []Rule{{Match: "operation", Require: "responses.204 exists"}}, opts.Rules)
+		assert.Equal(t, map[string]string{"default": "100/min"}, opts.RateLimits)
+		require.Contains(t, opts.TypeMappings, "github.com/acme/money.Amount")
+		assert.Equal(t, "decimal", opts.TypeMappings["github.com/acme/money.Amount"].Format)
+		assert.Equal(t, []map[string][]string{{"oauth2": {"read:users"}}}, opts.SecurityDefaults)
+		require.NotNil(t, opts.InputSpec, "relative to the options file")
+		assert.Equal(t, "Pets", opts.InputSpec.Info.Title)
+		assert.Contains(t, opts.CodeSampleTemplates, "python")
+		assert.False(t, opts.DefaultSkips, "the options which aren't set keep their zero value")
+	})
+
+	t.Run("should report the unknown keys with their line and the closest key", func(t *testing.T) {
+		path := writeOptionsFile(t, map[string]string{".codescan.yaml": `
+scan_model: true
+rules:
+  - requir: responses.204 exists
+logger: stderr
+`})
+		_, err := LoadOptionsFromFile(path)
+		require.ErrorIs(t, err, ErrUnknownOption)
+		assert.Contains(t, err.Error(), `line 2: unknown option "scan_model", did you mean scan_models?`)
+		assert.Contains(t, err.Error(), `line 4: unknown option "rules[0].requir", did you mean require?`)
+		assert.Contains(t, err.Error(), `line 5: unknown option "logger"`, "the callbacks can't be set")
+	})
+
+	t.Run("should report the invalid values", func(t *testing.T) {
+		path := writeOptionsFile(t, map[string]string{".codescan.yaml": `
+max_schema_depth: deep
+include_tags: pets
+type_mappings:
+  github.com/acme/money.Amount: money
+meta: missing.yaml
+`})
+		_, err := LoadOptionsFromFile(path)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "max_schema_depth")
+		assert.Contains(t, err.Error(), "line 3: include_tags: expected a list")
+		assert.Contains(t, err.Error(), "line 5: type_mappings: type mapping of github.com/acme/money.Amount")
+		assert.Contains(t, err.Error(), "line 6: meta: ")
+	})
+
+	t.Run("should accept an empty file", func(t *testing.T) {
+		opts, err := LoadOptionsFromFile(writeOptionsFile(t, map[string]string{".codescan.yaml": ""}))
+		require.NoError(t, err)
+		assert.Equal(t, NewOptions(), opts)
+	})
+}
+
+func TestOptionsFileKey(t *testing.T) {
+	for field, expected := range map[string]string{
+		"BuildTags":               "build_tags",
+		"SetXNullableForPointers": "set_x_nullable_for_pointers",
+		"StrictJSONNames":         "strict_json_names",
+		"DescWithRef":             "desc_with_ref",
+		"InputSpec":               "input_spec",
+	} {
+		key, ok := OptionsFileKey(field)
+		assert.True(t, ok, field)
+		assert.Equal(t, expected, key, field)
+	}
+
+	for _, field := range []string{"Logger", "Stats", "OutputSets", "Unknown"} {
+		_, ok := OptionsFileKey(field)
+		assert.False(t, ok, field)
+	}
+}
@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+
+	"github.com/go-openapi/spec"
+)
+
+// OutputSet is a spec derived from the scan with the operations of some tags, see Options.OutputSets. The
+// spec of a set is built from the declarations of the scan, merged with its input spec, then the operations
+// out of its tags are removed, with what only they use: the definitions, parameters, responses, security
+// definitions and tags, like Prune does.
+type OutputSet struct {
+	// Name identifies the spec of the set in Options.OutputSetSpecs and Result.OutputSets.
+	Name string
+	// IncludeTags keeps the operations with one of these tags, among those the tags of the options keep.
+	IncludeTags []string
+	// ExcludeTags drops the operations with one of these tags, whatever their other tags.
+	ExcludeTags []string
+	// InputSpec is merged with the spec of the set, instead of Options.InputSpec.
+	InputSpec *spec.Swagger
+	// ScanModels keeps all the models of the scan in the spec of the set, like Options.ScanModels, rather than
+	// the definitions its operations reach.
+	ScanModels bool
+}
+
+func checkOutputSets(sets []OutputSet) error {
+	var errs []error
+	seen := make(map[string]bool, len(sets))
+	for i, set := range sets {
+		switch {
+		case set.Name == "":
+			errs = append(errs, fmt.Errorf("output set %d has no name", i))
+		case seen[set.Name]:
+			errs = append(errs, fmt.Errorf("output set %s is declared twice", set.Name))
+		}
+		seen[set.Name] = true
+		if common := intersection(set.IncludeTags, set.ExcludeTags); len(common) > 0 {
+			errs = append(errs, fmt.Errorf("output set %s both includes and excludes the tags %v", set.Name, common))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// outputSetInputs copies the input specs of the output sets, before the spec of the scan is built on
+// Options.InputSpec.
+func outputSetInputs(opts *Options) ([]*spec.Swagger, error) {
+	if len(opts.OutputSets) == 0 {
+		return nil, nil
+	}
+	if err := checkOutputSets(opts.OutputSets); err != nil {
+		return nil, err
+	}
+	inputs := make([]*spec.Swagger, len(opts.OutputSets))
+	for i, set := range opts.OutputSets {
+		input := set.InputSpec
+		if input == nil {
+			input = opts.InputSpec
+		}
+		if input == nil {
+			continue
+		}
+		clone, err := cloneSpec(input)
+		if err != nil {
+			return nil, fmt.Errorf("output set %s: can't copy the input spec: %w", set.Name, err)
+		}
+		inputs[i] = clone
+	}
+	return inputs, nil
+}
+
+// buildOutputSets builds the specs of the output sets from the declarations indexed by the scan, without
+// loading the packages again.
+func buildOutputSets(sc *scanCtx, sets []OutputSet, inputs []*spec.Swagger) (map[string]*spec.Swagger, error) {
+	if len(sets) == 0 {
+		return nil, nil
+	}
+	// the progress and the statistics of the scan are those of its own spec
+	setCtx := *sc
+	setCtx.progress = nil
+	stats := sc.app.stats
+	defer func() { sc.app.stats = stats }()
+
+	specs := make(map[string]*spec.Swagger, len(sets))
+	for i, set := range sets {
+		doc, err := newSpecBuilder(inputs[i], &setCtx, set.ScanModels).Build()
+		if err != nil {
+			return nil, fmt.Errorf("output set %s: %w", set.Name, err)
+		}
+		if sc.app.sourceMap {
+			extractSourceMap(doc)
+		}
+
+		includeTags, excludeTags := sliceToSet(set.IncludeTags), sliceToSet(set.ExcludeTags)
+		definitions := maps.Clone(doc.Definitions)
+		if _, err := pruneOperations(doc, func(_, _ string, op *spec.Operation) (bool, error) {
+			accepted, _ := shouldAcceptTag(op.Tags, includeTags, excludeTags, false)
+			return accepted, nil
+		}, !set.ScanModels); err != nil {
+			return nil, err
+		}
+		if set.ScanModels {
+			doc.Definitions = definitions
+		}
+		specs[set.Name] = doc
+	}
+	return specs, nil
+}
@@ -0,0 +1,112 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputSets(t *testing.T) {
+	t.Run("should derive the specs of the sets from one scan", func(t *testing.T) {
+		sets := make(map[string]*spec.Swagger)
+		var stats, alone Stats
+		doc := audienceScan(t, &Options{
+			Stats: &stats,
+			OutputSets: []OutputSet{
+				{Name: "public", IncludeTags: []string{"pets"}},
+				{Name: "admin", IncludeTags: []string{"admin"}},
+			},
+			OutputSetSpecs: sets,
+		})
+		assert.Len(t, doc.Paths.Paths, 4, "the spec of the scan is unfiltered")
+		audienceScan(t, &Options{Stats: &alone})
+		assert.Equal(t, alone.Operations, stats.Operations, "the output sets don't count in the statistics")
+		assert.Contains(t, doc.Definitions, "Unused")
+		require.Len(t, sets, 2)
+
+		public := sets["public"]
+		assert.Equal(t, []string{"/pets", "/pets/{id}", "/pets/{id}/shares"}, sortedKeys(public.Paths.Paths))
+		assert.Equal(t, []string{"Owner", "Pet"}, sortedKeys(public.Definitions))
+		assert.Equal(t, []string{"petsResponse"}, sortedKeys(public.Responses))
+		assert.Equal(t, []string{"api_key"}, sortedKeys(public.SecurityDefinitions))
+
+		admin := sets["admin"]
+		assert.Equal(t, []string{"/audit"}, sortedKeys(admin.Paths.Paths))
+		assert.Equal(t, []string{"AuditEntry", "Owner", "Pet"}, sortedKeys(admin.Definitions))
+		assert.Equal(t, []string{"admin_key"}, sortedKeys(admin.SecurityDefinitions))
+	})
+
+	t.Run("should exclude tags and keep the models of a set", func(t *testing.T) {
+		result, err := RunWithContext(t.Context(), &Options{
+			Packages: []string{audienceFixture},
+			OutputSets: []OutputSet{
+				{Name: "no-admin", ExcludeTags: []string{"admin"}, ScanModels: true},
+			},
+		})
+		require.NoError(t, err)
+		set := result.OutputSets["no-admin"]
+		require.NotNil(t, set)
+		assert.NotContains(t, set.Paths.Paths, "/audit")
+		assert.Contains(t, set.Definitions, "Unused", "the set scans the models")
+		assert.NotContains(t, result.Spec.Definitions, "Unused")
+	})
+
+	t.Run("should merge the input spec of a set", func(t *testing.T) {
+		input := &spec.Swagger{SwaggerProps: spec.SwaggerProps{Host: "admin.example.com"}}
+		sets := make(map[string]*spec.Swagger)
+		doc, err := Run(&Options{
+			Packages:  []string{audienceFixture},
+			InputSpec: &spec.Swagger{SwaggerProps: spec.SwaggerProps{Host: "api.example.com"}},
+			OutputSets: []OutputSet{
+				{Name: "admin", IncludeTags: []string{"admin"}, InputSpec: input},
+				{Name: "public", IncludeTags: []string{"pets"}},
+			},
+			OutputSetSpecs: sets,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "api.example.com", doc.Host)
+		assert.Equal(t, "admin.example.com", sets["admin"].Host)
+		assert.Equal(t, "api.example.com", sets["public"].Host)
+		assert.Nil(t, input.Paths, "the input spec is copied")
+	})
+
+	t.Run("should cache the specs of the sets", func(t *testing.T) {
+		cacheDir := t.TempDir()
+		opts := func(sets map[string]*spec.Swagger) *Options {
+			return &Options{
+				Packages:       []string{audienceFixture},
+				CacheDir:       cacheDir,
+				OutputSets:     []OutputSet{{Name: "admin", IncludeTags: []string{"admin"}}},
+				OutputSetSpecs: sets,
+			}
+		}
+		scanned, cached := make(map[string]*spec.Swagger), make(map[string]*spec.Swagger)
+		_, err := Run(opts(scanned))
+		require.NoError(t, err)
+		second := opts(cached)
+		var stats Stats
+		second.Stats = &stats
+		_, err = Run(second)
+		require.NoError(t, err)
+		require.True(t, stats.Cached)
+		assert.Equal(t, sortedKeys(scanned["admin"].Paths.Paths), sortedKeys(cached["admin"].Paths.Paths))
+	})
+
+	t.Run("should reject the invalid sets", func(t *testing.T) {
+		err := (&Options{OutputSets: []OutputSet{
+			{Name: "public", IncludeTags: []string{"pets", "admin"}, ExcludeTags: []string{"admin"}},
+			{Name: "public"},
+			{},
+		}}).Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "OutputSets")
+		assert.Contains(t, err.Error(), "output set public both includes and excludes the tags [admin]")
+		assert.Contains(t, err.Error(), "output set public is declared twice")
+		assert.Contains(t, err.Error(), "output set 2 has no name")
+	})
+}
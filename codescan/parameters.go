@@ -73,6 +73,10 @@ func (pt paramTypable) WithEnumDescription(desc string) {
 	pt.param.AddExtension(extEnumDesc, desc)
 }
 
+func (pt paramTypable) WithEnumNames(names []string) {
+	pt.param.AddExtension(extEnumVarNames, names)
+}
+
 type itemsTypable struct {
 	items *spec.Items
 	level int
@@ -115,6 +119,10 @@ func (pt itemsTypable) WithEnumDescription(_ string) {
 	// no
 }
 
+func (pt itemsTypable) WithEnumNames(names []string) {
+	pt.items.AddExtension(extEnumVarNames, names)
+}
+
 type paramValidations struct {
 	current *spec.Parameter
 }
@@ -299,7 +307,11 @@ func (p *parameterBuilder) buildFromField(fld *types.Var, tpe types.Type, typabl
 	case *types.Interface:
 		return p.buildFromFieldInterface(ftpe, typable)
 	case *types.Array:
-		return p.buildFromField(fld, ftpe.Elem(), typable.Items(), seen)
+		if err := p.buildFromField(fld, ftpe.Elem(), typable.Items(), seen); err != nil {
+			return err
+		}
+		setArrayLength(typable, ftpe.Len())
+		return nil
 	case *types.Slice:
 		return p.buildFromField(fld, ftpe.Elem(), typable.Items(), seen)
 	case *types.Map:
@@ -372,6 +384,18 @@ func (p *parameterBuilder) buildNamedField(ftpe *types.Named, typable swaggerTyp
 	}
 	mustNotBeABuiltinType(o)
 
+	if mapping, isMapped := p.ctx.app.typeMapping(ftpe); isMapped {
+		return buildTypeMapping(mapping, typable)
+	}
+	if elem, isSet := p.ctx.app.setElement(ftpe); isSet {
+		sb := &schemaBuilder{ctx: p.ctx, decl: p.decl}
+		if err := sb.buildSet(elem, typable); err != nil {
+			return err
+		}
+		p.postDecls = append(p.postDecls, sb.postDecls...)
+		return nil
+	}
+
 	decl, found := p.ctx.DeclForType(o.Type())
 	if !found {
 		return fmt.Errorf("unable to find package and source file for: %s", ftpe.String())
@@ -387,9 +411,13 @@ func (p *parameterBuilder) buildNamedField(ftpe *types.Named, typable swaggerTyp
 		return nil
 	}
 
+	tpe := decl.ObjType()
+	if ftpe.TypeArgs().Len() > 0 {
+		tpe = ftpe // an instantiation of the generic type of the declaration
+	}
 	sb := &schemaBuilder{ctx: p.ctx, decl: decl}
 	sb.inferNames()
-	if err := sb.buildFromType(decl.ObjType(), typable); err != nil {
+	if err := sb.buildFromType(tpe, typable); err != nil {
 		return err
 	}
 
@@ -488,13 +516,36 @@ func (p *parameterBuilder) buildFromStruct(decl *entityDecl, tpe *types.Struct,
 	}
 
 	sequence := make([]string, 0, numFields)
+	embeddedBy := make(map[paramKey]string) // the embedded field declaring each parameter of the embedded structs
 	for i := range numFields {
 		fld := tpe.Field(i)
 
+		if fld.Embedded() && jsonIgnored(tpe.Tag(i)) {
+			continue
+		}
 		if fld.Embedded() {
-			if err := p.buildFromType(fld.Type(), op, seen); err != nil {
+			// the parameters of the embedded structs come first, in declaration order: own fields are added last
+			embedded := new(spec.Operation)
+			if err := p.buildFromType(fld.Type(), embedded, seen); err != nil {
 				return err
 			}
+			for _, param := range embedded.Parameters {
+				key := paramKey{in: param.In, name: param.Name}
+				if other, duplicate := embeddedBy[key]; duplicate {
+					problem := Diagnostic{
+						Pos:  decl.Pkg.Fset.Position(fld.Pos()),
+						Code: DiagnosticDuplicateParameter,
+						Message: fmt.Sprintf("%s parameter %q of %s is declared by both embedded %s and %s",
+							param.In, param.Name, decl.Obj().Name(), other, fld.Name()),
+					}
+					if p.ctx.app.strictParameters {
+						return problem
+					}
+					p.ctx.app.diagnose(problem)
+				}
+				embeddedBy[key] = fld.Name()
+				op.Parameters = replaceParam(op.Parameters, param)
+			}
 			continue
 		}
 
@@ -536,18 +587,33 @@ func (p *parameterBuilder) buildFromStruct(decl *entityDecl, tpe *types.Struct,
 			continue
 		}
 
-		in := "query"
+		in := ""
+		var keys []string
 		// scan for param location first, this changes some behavior down the line
 		if afld.Doc != nil {
 			for _, cmt := range afld.Doc.List {
-				for line := range strings.SplitSeq(cmt.Text, "\n") {
+				for line := range commentLines(cmt.Text) {
 					matches := rxIn.FindStringSubmatch(line)
 					if len(matches) > 0 && len(strings.TrimSpace(matches[1])) > 0 {
 						in = strings.TrimSpace(matches[1])
 					}
+					if matches := rxKeys.FindStringSubmatch(line); len(matches) > 0 {
+						keys = splitKeys(matches[1])
+					}
 				}
 			}
 		}
+		// the uploaded files are form data, the other parameters default to the query
+		fileHeader, multipleFiles := fileHeaderType(fld.Type())
+		switch {
+		case fileHeader && in == "":
+			in = "formData"
+		case fileHeader && in != "formData":
+			return fmt.Errorf("%v: the file parameter %s of %s is in %s, while files are uploaded as formData",
+				decl.Pkg.Fset.Position(fld.Pos()), fld.Name(), decl.Ident.Name, in)
+		case in == "":
+			in = "query"
+		}
 
 		ps := seen[name]
 		ps.In = in
@@ -555,10 +621,23 @@ func (p *parameterBuilder) buildFromStruct(decl *entityDecl, tpe *types.Struct,
 		if in == "body" {
 			pty = schemaTypable{pty.Schema(), 0}
 		}
-		if in == "formData" && afld.Doc != nil && fileParam(afld.Doc) {
+		_, isStrfmt := strfmtName(afld.Doc)
+		mapType, composite := p.compositeType(fld.Type())
+		var exploded bool
+		switch {
+		case in == "formData" && (fileHeader || afld.Doc != nil && fileParam(afld.Doc)):
 			pty.Typed("file", "")
-		} else if err := p.buildFromField(fld, fld.Type(), pty, seen); err != nil {
-			return err
+			if multipleFiles {
+				addExtension(&ps.VendorExtensible, multipleFilesExtension, true)
+			}
+		case composite && in != "body" && !isStrfmt:
+			if exploded, err = p.buildComposite(decl, fld, mapType, keys, &ps, seen); err != nil {
+				return err
+			}
+		default:
+			if err := p.buildFromField(fld, fld.Type(), pty, seen); err != nil {
+				return err
+			}
 		}
 
 		if strfmtName, ok := strfmtName(afld.Doc); ok {
@@ -566,8 +645,16 @@ func (p *parameterBuilder) buildFromStruct(decl *entityDecl, tpe *types.Struct,
 			ps.Ref = spec.Ref{}
 			ps.Items = nil
 		}
+		for _, rules := range validatorRules(afld, p.ctx.opts.ValidatorTags) {
+			target := paramValidatorTarget(&ps)
+			if in == "body" {
+				target = schemaValidatorTarget(ps.Schema, nil, "")
+				target.setRequired = func() { ps.Required = true }
+			}
+			applyValidatorRules(target, rules)
+		}
 
-		sp := new(sectionedParser)
+		sp := &sectionedParser{preserveFormat: p.ctx.opts.PreserveCommentFormat, fset: decl.Pkg.Fset, ignore: p.ctx.app.ignoreItemsValidations(decl.Pkg.Fset, afld)}
 		sp.setDescription = func(lines []string) {
 			ps.Description = joinDropLast(lines)
 			enumDesc := getEnumDesc(ps.Extensions)
@@ -585,13 +672,14 @@ func (p *parameterBuilder) buildFromStruct(decl *entityDecl, tpe *types.Struct,
 				newSingleLineTagParser("maxLength", &setMaxLength{paramValidations{&ps}, rxf(rxMaxLengthFmt, "")}),
 				newSingleLineTagParser("pattern", &setPattern{paramValidations{&ps}, rxf(rxPatternFmt, "")}),
 				newSingleLineTagParser("collectionFormat", &setCollectionFormat{paramValidations{&ps}, rxf(rxCollectionFormatFmt, "")}),
-				newSingleLineTagParser("minItems", &setMinItems{paramValidations{&ps}, rxf(rxMinItemsFmt, "")}),
-				newSingleLineTagParser("maxItems", &setMaxItems{paramValidations{&ps}, rxf(rxMaxItemsFmt, "")}),
+				newSingleLineTagParser("minItems", &setMinItems{paramValidations{&ps}, rxf(rxMinItemsFmt, ""), decl.Pkg.Types}),
+				newSingleLineTagParser("maxItems", &setMaxItems{paramValidations{&ps}, rxf(rxMaxItemsFmt, ""), decl.Pkg.Types}),
 				newSingleLineTagParser("unique", &setUnique{paramValidations{&ps}, rxf(rxUniqueFmt, "")}),
 				newSingleLineTagParser("enum", &setEnum{paramValidations{&ps}, rxf(rxEnumFmt, "")}),
 				newSingleLineTagParser("default", &setDefault{&ps.SimpleSchema, paramValidations{&ps}, rxf(rxDefaultFmt, "")}),
 				newSingleLineTagParser("example", &setExample{&ps.SimpleSchema, paramValidations{&ps}, rxf(rxExampleFmt, "")}),
 				newSingleLineTagParser("required", &setRequiredParam{&ps}),
+				newSingleLineTagParser("keys", &matchOnlyParam{&ps, rxKeys}),
 				newMultiLineTagParser("Extensions", newSetExtensions(spExtensionsSetter(&ps)), true),
 			}
 
@@ -607,8 +695,8 @@ func (p *parameterBuilder) buildFromStruct(decl *entityDecl, tpe *types.Struct,
 					newSingleLineTagParser(fmt.Sprintf("items%dMaxLength", level), &setMaxLength{itemsValidations{items}, rxf(rxMaxLengthFmt, itemsPrefix)}),
 					newSingleLineTagParser(fmt.Sprintf("items%dPattern", level), &setPattern{itemsValidations{items}, rxf(rxPatternFmt, itemsPrefix)}),
 					newSingleLineTagParser(fmt.Sprintf("items%dCollectionFormat", level), &setCollectionFormat{itemsValidations{items}, rxf(rxCollectionFormatFmt, itemsPrefix)}),
-					newSingleLineTagParser(fmt.Sprintf("items%dMinItems", level), &setMinItems{itemsValidations{items}, rxf(rxMinItemsFmt, itemsPrefix)}),
-					newSingleLineTagParser(fmt.Sprintf("items%dMaxItems", level), &setMaxItems{itemsValidations{items}, rxf(rxMaxItemsFmt, itemsPrefix)}),
+					newSingleLineTagParser(fmt.Sprintf("items%dMinItems", level), &setMinItems{itemsValidations{items}, rxf(rxMinItemsFmt, itemsPrefix), decl.Pkg.Types}),
+					newSingleLineTagParser(fmt.Sprintf("items%dMaxItems", level), &setMaxItems{itemsValidations{items}, rxf(rxMaxItemsFmt, itemsPrefix), decl.Pkg.Types}),
 					newSingleLineTagParser(fmt.Sprintf("items%dUnique", level), &setUnique{itemsValidations{items}, rxf(rxUniqueFmt, itemsPrefix)}),
 					newSingleLineTagParser(fmt.Sprintf("items%dEnum", level), &setEnum{itemsValidations{items}, rxf(rxEnumFmt, itemsPrefix)}),
 					newSingleLineTagParser(fmt.Sprintf("items%dDefault", level), &setDefault{&items.SimpleSchema, itemsValidations{items}, rxf(rxDefaultFmt, itemsPrefix)}),
@@ -673,9 +761,29 @@ func (p *parameterBuilder) buildFromStruct(decl *entityDecl, tpe *types.Struct,
 				newMultiLineTagParser("Extensions", newSetExtensions(spExtensionsSetter(&ps)), true),
 			}
 		}
+		deprecation := new(setDeprecated)
+		sp.taggers = append(sp.taggers, newSingleLineTagParser("Deprecated", deprecation))
+		if in == "body" {
+			sp.taggers = append(sp.taggers, newMultiLineTagParser("Examples", newSetNamedExamples(p.ctx.app, decl.Pkg.Fset.Position(fld.Pos()).Filename,
+				func(examples map[string]any) { ps.AddExtension(namedExamplesExtension, examples) }), true))
+		}
 		if err := sp.Parse(afld.Doc); err != nil {
 			return err
 		}
+		if in != "body" {
+			if err := p.setDefaultTag(decl, afld, fld, &ps, exploded); err != nil {
+				return err
+			}
+		}
+		if serialization, _ := ps.Extensions.GetString(serializationExtension); serialization == "json" {
+			if ps.Description != "" {
+				ps.Description += "\n\n"
+			}
+			ps.Description += encodingDescription(mapType)
+		}
+		if p.ctx.deprecated(deprecation, afld.Doc) {
+			p.ctx.deprecate(&ps.VendorExtensible, &ps.Description)
+		}
 		if ps.In == "path" {
 			ps.Required = true
 		}
@@ -687,24 +795,75 @@ func (p *parameterBuilder) buildFromStruct(decl *entityDecl, tpe *types.Struct,
 		if name != fld.Name() {
 			addExtension(&ps.VendorExtensible, "x-go-name", fld.Name())
 		}
-		seen[name] = ps
-		sequence = append(sequence, name)
+		if p.ctx.opts.BindingExtensions {
+			addBindingExtensions(&ps, fld)
+		}
+		p.ctx.app.recordPosition(&ps.VendorExtensible, decl.Pkg.Fset.Position(fld.Pos()))
+		if !exploded {
+			seen[name] = ps
+			sequence = append(sequence, name)
+			continue
+		}
+		for _, key := range keys {
+			keyed, err := cloneParameter(ps)
+			if err != nil {
+				return err
+			}
+			keyed.Name = ps.Name + "[" + key + "]"
+			seen[keyed.Name] = keyed
+			sequence = append(sequence, keyed.Name)
+		}
 	}
 
 	for _, k := range sequence {
-		p := seen[k]
-		for i, v := range op.Parameters {
-			if v.Name == k {
-				op.Parameters = append(op.Parameters[:i], op.Parameters[i+1:]...)
-				break
-			}
-		}
-		op.Parameters = append(op.Parameters, p)
+		op.Parameters = replaceParam(op.Parameters, seen[k])
 	}
 	return nil
 }
 
+// addBindingExtensions records how a parameter binds to the field of its swagger:parameters struct, for
+// the generators of server code: the Go field, its type and the decoder of the location of the parameter.
+func addBindingExtensions(ps *spec.Parameter, fld *types.Var) {
+	addExtension(&ps.VendorExtensible, "x-go-field", fld.Name())
+	addExtension(&ps.VendorExtensible, "x-go-type", types.TypeString(fld.Type(), nil))
+	addExtension(&ps.VendorExtensible, "x-go-decoder", paramDecoder(ps.In))
+}
+
+// paramDecoder returns the decoder of a parameter location: json for a body, form for form data, else the
+// location itself, i.e. path, query or header.
+func paramDecoder(in string) string {
+	switch in {
+	case "body":
+		return "json"
+	case "formData":
+		return "form"
+	default:
+		return in
+	}
+}
+
+// paramKey identifies a parameter of an operation.
+type paramKey struct {
+	in, name string
+}
+
+// replaceParam appends a parameter to the parameters of an operation, removing the parameter of the same
+// name, e.g. an embedded one overridden by an own field.
+func replaceParam(params []spec.Parameter, param spec.Parameter) []spec.Parameter {
+	for i, v := range params {
+		if v.Name == param.Name {
+			params = append(params[:i], params[i+1:]...)
+			break
+		}
+	}
+	return append(params, param)
+}
+
 func (p *parameterBuilder) makeRef(decl *entityDecl, prop swaggerTypable) error {
+	if ref, indexed := p.ctx.app.indexedRef(decl); indexed {
+		prop.SetRef(ref)
+		return nil
+	}
 	nm, _ := decl.Names()
 	ref, err := spec.NewRef("#/definitions/" + nm)
 	if err != nil {
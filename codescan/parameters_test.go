@@ -493,3 +493,80 @@ func TestParameterParser_Issue2011(t *testing.T) {
 	sch := op.Parameters[0].Schema
 	require.NotNil(t, sch)
 }
+
+func TestEmbeddedParameters(t *testing.T) {
+	const fixture = "github.com/3idey/codescan/fixtures/goparsing/paramembed"
+	var diagnostics []Diagnostic
+	doc, err := Run(&Options{Packages: []string{fixture}, Diagnostics: &diagnostics})
+	require.NoError(t, err)
+
+	names := func(params []spec.Parameter) []string {
+		var result []string
+		for _, param := range params {
+			result = append(result, param.In+" "+param.Name)
+		}
+		return result
+	}
+
+	t.Run("should compose the parameters of the embedded structs first", func(t *testing.T) {
+		op := doc.Paths.Paths["/users"].Get
+		assert.Equal(t, []string{"query limit", "query offset", "query sort", "header X-Trace-Id", "query name"}, names(op.Parameters))
+		assert.Equal(t, "The maximum number of items.", op.Parameters[0].Description)
+	})
+
+	t.Run("should report the parameters declared by several embedded structs", func(t *testing.T) {
+		op := doc.Paths.Paths["/groups"].Get
+		assert.Equal(t, []string{"query offset", "query cursor", "query limit"}, names(op.Parameters))
+		require.Len(t, diagnostics, 1)
+		assert.Equal(t, DiagnosticDuplicateParameter, diagnostics[0].Code)
+		assert.Equal(t, `query parameter "limit" of ListGroupsParams is declared by both embedded PaginationParams and CursorParams`, diagnostics[0].Message)
+	})
+
+	t.Run("should fail on duplicate parameters in strict mode", func(t *testing.T) {
+		_, err := Run(&Options{Packages: []string{fixture}, StrictParameters: true})
+		require.Error(t, err)
+		assert.Regexp(t, `params\.go:57:2: query parameter "limit" of ListGroupsParams is declared by both`, err.Error())
+	})
+}
+
+func TestBindingExtensions(t *testing.T) {
+	const pkg = "github.com/3idey/codescan/fixtures/goparsing/binding"
+
+	t.Run("should record the Go field, type and decoder of the parameters", func(t *testing.T) {
+		doc, err := Run(&Options{Packages: []string{pkg}, BindingExtensions: true})
+		require.NoError(t, err)
+
+		params := make(map[string]spec.Parameter)
+		for _, param := range doc.Paths.Paths["/orders/{id}"].Put.Parameters {
+			params[param.Name] = param
+		}
+		require.Len(t, params, 5)
+
+		for name, want := range map[string][3]string{
+			"X-Trace-Id": {"TraceID", "string", "header"},
+			"id":         {"ID", "int64", "path"},
+			"since":      {"Since", "*time.Time", "query"},
+			"tags":       {"Tags", "[]string", "query"},
+			"order":      {"Order", "*" + pkg + ".Order", "json"},
+		} {
+			param := params[name]
+			assert.Equal(t, want[0], param.Extensions["x-go-field"], name)
+			assert.Equal(t, want[1], param.Extensions["x-go-type"], name)
+			assert.Equal(t, want[2], param.Extensions["x-go-decoder"], name)
+		}
+
+		form := doc.Paths.Paths["/receipts"].Post.Parameters
+		require.Len(t, form, 1)
+		assert.Equal(t, "form", form[0].Extensions["x-go-decoder"])
+	})
+
+	t.Run("should leave the binding extensions out by default", func(t *testing.T) {
+		doc, err := Run(&Options{Packages: []string{pkg}})
+		require.NoError(t, err)
+
+		for _, param := range doc.Paths.Paths["/orders/{id}"].Put.Parameters {
+			assert.NotContains(t, param.Extensions, "x-go-field")
+			assert.NotContains(t, param.Extensions, "x-go-decoder")
+		}
+	})
+}
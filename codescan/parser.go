@@ -8,13 +8,18 @@ import (
 	"errors"
 	"fmt"
 	"go/ast"
+	"go/token"
 	"go/types"
+	"iter"
 	"log"
+	"math"
+	"path"
 	"reflect"
 	"regexp"
 	"slices"
 	"strconv"
 	"strings"
+	"sync"
 
 	"gopkg.in/yaml.v3"
 
@@ -22,42 +27,87 @@ import (
 	"github.com/go-openapi/spec"
 )
 
-func shouldAcceptTag(tags []string, includeTags map[string]bool, excludeTags map[string]bool) bool {
+// shouldAcceptTag decides if the tag rules keep a route or an operation with these tags, and explains why.
+// An excluded tag always drops it. Otherwise, it needs one of the included tags, or all of them with
+// requireAll, and is kept without included tags.
+func shouldAcceptTag(tags []string, includeTags map[string]bool, excludeTags map[string]bool, requireAll bool) (bool, string) {
 	for _, tag := range tags {
-		if len(includeTags) > 0 {
-			if includeTags[tag] {
-				return true
-			}
-		} else if len(excludeTags) > 0 {
-			if excludeTags[tag] {
-				return false
-			}
+		if excludeTags[tag] {
+			return false, fmt.Sprintf("tag %s is excluded", tag)
 		}
 	}
+	if len(includeTags) == 0 {
+		return true, "no tag is excluded"
+	}
 
-	return len(includeTags) == 0
+	if requireAll {
+		for _, tag := range sortedKeys(includeTags) {
+			if !slices.Contains(tags, tag) {
+				return false, fmt.Sprintf("included tag %s is missing", tag)
+			}
+		}
+		return true, "all the included tags are present"
+	}
+	for _, tag := range tags {
+		if includeTags[tag] {
+			return true, fmt.Sprintf("tag %s is included", tag)
+		}
+	}
+	return false, "no tag is included"
 }
 
 func shouldAcceptPkg(path string, includePkgs, excludePkgs []string) bool {
+	accepted, _ := explainPkgRules(path, includePkgs, excludePkgs)
+	return accepted
+}
+
+// explainPkgRules decides if the include and exclude rules keep a package, and explains why. An included
+// package is kept, even when excluded. Otherwise, an excluded package is dropped, and the others are kept
+// without include rules.
+func explainPkgRules(path string, includePkgs, excludePkgs []string) (bool, string) {
 	if len(includePkgs) == 0 && len(excludePkgs) == 0 {
-		return true
+		return true, "no include or exclude rule"
 	}
 
 	for _, pkgName := range includePkgs {
 		matched, _ := regexp.MatchString(pkgName, path)
 		if matched {
-			return true
+			return true, "included by " + pkgName
 		}
 	}
 
 	for _, pkgName := range excludePkgs {
 		matched, _ := regexp.MatchString(pkgName, path)
 		if matched {
-			return false
+			return false, "excluded by " + pkgName
 		}
 	}
 
-	return len(includePkgs) == 0
+	if len(includePkgs) == 0 {
+		return true, "no exclude rule matches"
+	}
+	return false, "no include rule matches"
+}
+
+// matchPackageGlob matches a package path against a glob pattern.
+//
+// Patterns follow path.Match, with the go tool convention that a trailing "/..."
+// matches the package itself and all the packages below it.
+func matchPackageGlob(pattern, pkgPath string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "/..."); ok {
+		if matched, _ := path.Match(prefix, pkgPath); matched {
+			return true
+		}
+		parts := strings.Count(prefix, "/") + 1
+		segments := strings.SplitN(pkgPath, "/", parts+1)
+		if len(segments) <= parts {
+			return false
+		}
+		matched, _ := path.Match(prefix, strings.Join(segments[:parts], "/"))
+		return matched
+	}
+	matched, _ := path.Match(pattern, pkgPath)
+	return matched
 }
 
 // Many thanks go to https://github.com/yvasiyarov/swagger
@@ -84,14 +134,40 @@ func removeEmptyLines(lines []string) []string {
 	return notEmpty
 }
 
+// rxfKey identifies a regexp compiled by rxf.
+type rxfKey struct {
+	rxp, ar string
+}
+
+// rxfCache holds the regexps compiled by rxf, which the parsers of the fields and of the parameters ask for
+// again and again.
+var rxfCache sync.Map // of rxfKey to *regexp.Regexp
+
 func rxf(rxp, ar string) *regexp.Regexp {
-	return regexp.MustCompile(fmt.Sprintf(rxp, ar))
+	key := rxfKey{rxp: rxp, ar: ar}
+	if rx, cached := rxfCache.Load(key); cached {
+		return rx.(*regexp.Regexp)
+	}
+	rx, _ := rxfCache.LoadOrStore(key, regexp.MustCompile(fmt.Sprintf(rxp, ar)))
+	return rx.(*regexp.Regexp)
 }
 
 func allOfMember(comments *ast.CommentGroup) bool {
 	return commentMatcher(rxAllOf)(comments)
 }
 
+func discriminatorDirective(comments *ast.CommentGroup) bool {
+	return commentMatcher(rxDiscriminatorField)(comments)
+}
+
+func discriminatorValue(comments *ast.CommentGroup) (string, bool) {
+	return commentSubMatcher(rxDiscriminatorField)(comments)
+}
+
+func requiredDirective(comments *ast.CommentGroup) bool {
+	return commentMatcher(rxRequired)(comments)
+}
+
 func fileParam(comments *ast.CommentGroup) bool {
 	return commentMatcher(rxFileUpload)(comments)
 }
@@ -108,6 +184,27 @@ func enumName(comments *ast.CommentGroup) (string, bool) {
 	return commentSubMatcher(rxEnum)(comments)
 }
 
+func enumIgnored(comments *ast.CommentGroup) bool {
+	return commentMatcher(rxEnumIgnore)(comments)
+}
+
+// hasEnumDirective tells if a comment has an enum: directive setting the values, rather than the policy of
+// rxEnumPolicy.
+func hasEnumDirective(comments *ast.CommentGroup) bool {
+	if comments == nil {
+		return false
+	}
+	rx := rxf(rxEnumFmt, "")
+	return slices.ContainsFunc(comments.List, func(cmt *ast.Comment) bool {
+		for ln := range commentLines(cmt.Text) {
+			if rx.MatchString(ln) && !rxEnumPolicy.MatchString(ln) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
 func aliasParam(comments *ast.CommentGroup) bool {
 	return commentMatcher(rxAlias)(comments)
 }
@@ -119,7 +216,7 @@ func commentMatcher(rx *regexp.Regexp) func(*ast.CommentGroup) bool {
 		}
 
 		return slices.ContainsFunc(comments.List, func(cmt *ast.Comment) bool {
-			for ln := range strings.SplitSeq(cmt.Text, "\n") {
+			for ln := range commentLines(cmt.Text) {
 				if rx.MatchString(ln) {
 					return true
 				}
@@ -137,7 +234,7 @@ func commentSubMatcher(rx *regexp.Regexp) func(*ast.CommentGroup) (string, bool)
 		}
 
 		for _, cmt := range comments.List {
-			for ln := range strings.SplitSeq(cmt.Text, "\n") {
+			for ln := range commentLines(cmt.Text) {
 				matches := rx.FindStringSubmatch(ln)
 				if len(matches) > 1 && len(strings.TrimSpace(matches[1])) > 0 {
 					return strings.TrimSpace(matches[1]), true
@@ -177,6 +274,7 @@ type swaggerTypable interface {
 	AddExtension(key string, value any)
 	WithEnum(values ...any)
 	WithEnumDescription(desc string)
+	WithEnumNames(names []string)
 	In() string
 }
 
@@ -268,8 +366,9 @@ func (st *tagParser) Parse(lines []string) error {
 }
 
 type yamlParser struct {
-	set func(json.RawMessage) error
-	rx  *regexp.Regexp
+	set     func(json.RawMessage) error
+	rx      *regexp.Regexp
+	prepare func(*yaml.Node) // edits the parsed YAML while its mappings retain their order, when set
 }
 
 func newYamlParser(rx *regexp.Regexp, setter func(json.RawMessage) error) *yamlParser {
@@ -293,6 +392,17 @@ func (y *yamlParser) Parse(lines []string) error {
 	if err != nil {
 		return err
 	}
+	if y.prepare != nil && yamlValue != nil {
+		var doc yaml.Node
+		if err := yaml.Unmarshal([]byte(yamlContent), &doc); err != nil {
+			return err
+		}
+		y.prepare(doc.Content[0])
+		yamlValue = nil
+		if err := doc.Decode(&yamlValue); err != nil {
+			return err
+		}
+	}
 
 	var jsonValue json.RawMessage
 	jsonValue, err = fmts.YAMLToJSON(yamlValue)
@@ -317,6 +427,7 @@ type yamlSpecScanner struct {
 	workedOutTitle bool
 	title          []string
 	skipHeader     bool
+	preserveFormat bool // keeps the indentation of the title and the description, see Options.PreserveCommentFormat
 }
 
 func (sp *yamlSpecScanner) Title() []string {
@@ -336,7 +447,10 @@ func (sp *yamlSpecScanner) Parse(doc *ast.CommentGroup) error {
 	var startedYAMLSpec bool
 COMMENTS:
 	for _, c := range doc.List {
-		for line := range strings.SplitSeq(c.Text, "\n") {
+		for line := range commentLines(c.Text) {
+			if rxSuppression.MatchString(line) {
+				continue // suppressions of diagnostics are not documentation
+			}
 			if rxSwaggerAnnotation.MatchString(line) {
 				break COMMENTS // a new swagger: annotation terminates this parser
 			}
@@ -418,13 +532,14 @@ func (sp *yamlSpecScanner) collectTitleDescription() {
 	if sp.workedOutTitle {
 		return
 	}
+	uncomment := uncommentDescription(sp.preserveFormat)
 	if sp.setTitle == nil {
-		sp.header = cleanupScannerLines(sp.header, rxUncommentHeaders)
+		sp.header = cleanDescription(sp.header, uncomment)
 		return
 	}
 
 	sp.workedOutTitle = true
-	sp.title, sp.header = collectScannerTitleDescription(sp.header)
+	sp.title, sp.header = collectScannerTitleDescription(sp.header, uncomment)
 }
 
 // removes indent based on the first line.
@@ -496,6 +611,13 @@ type sectionedParser struct {
 	currentTagger  *tagParser
 	title          []string
 	ignored        bool
+	preserveFormat bool // keeps the indentation of the title and the description, see Options.PreserveCommentFormat
+
+	fset        *token.FileSet              // positions the errors of the lines of the blocks, see blockParser, when not nil
+	blockIndent int                         // the indentation of the tag of the current block, see blockParser
+	positions   map[string][]token.Position // the positions of the lines of the blocks, by tagger
+
+	ignore func(error) // reports the validations ignored rather than failing the parse, see notAnArrayError
 }
 
 func (st *sectionedParser) Title() []string {
@@ -514,7 +636,10 @@ func (st *sectionedParser) Parse(doc *ast.CommentGroup) error {
 	}
 COMMENTS:
 	for _, c := range doc.List {
-		for line := range strings.SplitSeq(c.Text, "\n") {
+		for pos, line := range commentLinesAt(c) {
+			if rxSuppression.MatchString(line) {
+				continue // suppressions of diagnostics are not documentation
+			}
 			if rxSwaggerAnnotation.MatchString(line) {
 				if rxIgnoreOverride.MatchString(line) {
 					st.ignored = true
@@ -532,12 +657,17 @@ COMMENTS:
 			}
 
 			var matched bool
+			inBlock := st.inBlock(line) // the lines of a block hold no tags
 			for _, tg := range st.taggers {
 				tagger := tg
-				if tagger.Matches(line) {
+				if !inBlock && !rxEscapedComment.MatchString(line) && tagger.Matches(line) {
 					st.seenTag = true
 					st.currentTagger = &tagger
 					matched = true
+					if block, isBlock := tagger.Parser.(blockParser); isBlock {
+						block.startBlock(line)
+						st.blockIndent = commentIndent(line)
+					}
 					break
 				}
 			}
@@ -551,8 +681,14 @@ COMMENTS:
 			}
 
 			if st.currentTagger.MultiLine && matched {
-				// the first line of a multiline tagger doesn't count
-				continue
+				// the first line of a multiline tagger doesn't count, unless it holds a value
+				inline, ok := st.currentTagger.Parser.(inlineValueParser)
+				if !ok {
+					continue
+				}
+				if line = inline.inlineValue(line); strings.TrimSpace(line) == "" {
+					continue
+				}
 			}
 
 			ts, ok := st.matched[st.currentTagger.Name]
@@ -560,6 +696,12 @@ COMMENTS:
 				ts = *st.currentTagger
 			}
 			ts.Lines = append(ts.Lines, line)
+			if _, isBlock := ts.Parser.(blockParser); isBlock && st.fset != nil {
+				if st.positions == nil {
+					st.positions = make(map[string][]token.Position)
+				}
+				st.positions[ts.Name] = append(st.positions[ts.Name], st.fset.Position(pos))
+			}
 			if st.matched == nil {
 				st.matched = make(map[string]tagParser)
 			}
@@ -576,28 +718,63 @@ COMMENTS:
 	if st.setDescription != nil {
 		st.setDescription(st.Description())
 	}
-	for _, mt := range st.matched {
+	// in the order of the taggers, since some replace what others refine
+	parsed := make(map[string]bool, len(st.matched))
+	for _, tg := range st.taggers {
+		mt, ok := st.matched[tg.Name]
+		if !ok || parsed[tg.Name] {
+			continue
+		}
+		parsed[tg.Name] = true
 		if !mt.SkipCleanUp {
 			mt.Lines = cleanupScannerLines(mt.Lines, rxUncommentHeaders)
 		}
 		if err := mt.Parse(mt.Lines); err != nil {
-			return err
+			var notArray *notAnArrayError
+			if st.ignore != nil && errors.As(err, &notArray) {
+				st.ignore(err)
+				continue
+			}
+			return st.locate(mt, err)
 		}
 	}
 	return nil
 }
 
+// locate positions the error of a line of a block, see blockLineError, when the positions of its lines are
+// known, i.e. those of the raw lines of a sectioned parser with a file set.
+func (st *sectionedParser) locate(tagger tagParser, err error) error {
+	var lineErr *blockLineError
+	positions := st.positions[tagger.Name]
+	if tagger.SkipCleanUp && errors.As(err, &lineErr) && lineErr.line >= 0 && lineErr.line < len(positions) {
+		lineErr.pos = positions[lineErr.line]
+	}
+	return err
+}
+
+// inBlock tells if a line belongs to the current block, see blockParser, being indented deeper than its tag.
+func (st *sectionedParser) inBlock(line string) bool {
+	if st.currentTagger == nil {
+		return false
+	}
+	if _, isBlock := st.currentTagger.Parser.(blockParser); !isBlock {
+		return false
+	}
+	return commentIndent(line) > st.blockIndent
+}
+
 func (st *sectionedParser) collectTitleDescription() {
 	if st.workedOutTitle {
 		return
 	}
+	uncomment := uncommentDescription(st.preserveFormat)
 	if st.setTitle == nil {
-		st.header = cleanupScannerLines(st.header, rxUncommentHeaders)
+		st.header = cleanDescription(st.header, uncomment)
 		return
 	}
 
 	st.workedOutTitle = true
-	st.title, st.header = collectScannerTitleDescription(st.header)
+	st.title, st.header = collectScannerTitleDescription(st.header, uncomment)
 }
 
 type validationBuilder interface {
@@ -623,6 +800,11 @@ type valueParser interface {
 	Matches(commentLine string) bool
 }
 
+// inlineValueParser is a multi-line value parser accepting a value on the line of its tag, e.g. "Produces: json, xml".
+type inlineValueParser interface {
+	inlineValue(commentLine string) string
+}
+
 type operationValidationBuilder interface {
 	validationBuilder
 	SetCollectionFormat(collectionFormat string)
@@ -703,6 +885,7 @@ func (sm *setMultipleOf) Parse(lines []string) error {
 type setMaxItems struct {
 	builder validationBuilder
 	rx      *regexp.Regexp
+	pkg     *types.Package // resolves the constants, e.g. Max Items: {MaxBatchSize}
 }
 
 func (sm *setMaxItems) Matches(line string) bool {
@@ -715,7 +898,10 @@ func (sm *setMaxItems) Parse(lines []string) error {
 	}
 	matches := sm.rx.FindStringSubmatch(lines[0])
 	if len(matches) > 1 && len(matches[1]) > 0 {
-		maxItems, err := strconv.ParseInt(matches[1], 10, 64)
+		if tpe, invalid := notAnArray(sm.builder); invalid {
+			return &notAnArrayError{keyword: "maxItems", tpe: tpe}
+		}
+		maxItems, err := itemsCount(matches[1], sm.pkg)
 		if err != nil {
 			return err
 		}
@@ -727,6 +913,7 @@ func (sm *setMaxItems) Parse(lines []string) error {
 type setMinItems struct {
 	builder validationBuilder
 	rx      *regexp.Regexp
+	pkg     *types.Package // resolves the constants, e.g. Min Items: {MaxBatchSize}
 }
 
 func (sm *setMinItems) Matches(line string) bool {
@@ -739,7 +926,10 @@ func (sm *setMinItems) Parse(lines []string) error {
 	}
 	matches := sm.rx.FindStringSubmatch(lines[0])
 	if len(matches) > 1 && len(matches[1]) > 0 {
-		minItems, err := strconv.ParseInt(matches[1], 10, 64)
+		if tpe, invalid := notAnArray(sm.builder); invalid {
+			return &notAnArrayError{keyword: "minItems", tpe: tpe}
+		}
+		minItems, err := itemsCount(matches[1], sm.pkg)
 		if err != nil {
 			return err
 		}
@@ -851,6 +1041,9 @@ func (su *setUnique) Parse(lines []string) error {
 	}
 	matches := su.rx.FindStringSubmatch(lines[0])
 	if len(matches) > 1 && len(matches[1]) > 0 {
+		if tpe, invalid := notAnArray(su.builder); invalid {
+			return &notAnArrayError{keyword: "unique", tpe: tpe}
+		}
 		req, err := strconv.ParseBool(matches[1])
 		if err != nil {
 			return err
@@ -866,7 +1059,7 @@ type setEnum struct {
 }
 
 func (se *setEnum) Matches(line string) bool {
-	return se.rx.MatchString(line)
+	return se.rx.MatchString(line) && !rxEnumPolicy.MatchString(line)
 }
 
 func (se *setEnum) Parse(lines []string) error {
@@ -884,6 +1077,10 @@ func parseValueFromSchema(s string, schema *spec.SimpleSchema) (any, error) {
 	if schema != nil {
 		switch strings.Trim(schema.TypeName(), "\"") {
 		case "integer", "int", "int64", "int32", "int16":
+			if value, err := strconv.ParseUint(s, 10, 64); err == nil && value > math.MaxInt64 {
+				// the uint64 beyond int64 are kept exactly, rather than as a float64.
+				return value, nil
+			}
 			return strconv.Atoi(s)
 		case "bool", "boolean":
 			return strconv.ParseBool(s)
@@ -891,14 +1088,14 @@ func parseValueFromSchema(s string, schema *spec.SimpleSchema) (any, error) {
 			return strconv.ParseFloat(s, 64)
 		case "object":
 			var obj map[string]any
-			if err := json.Unmarshal([]byte(s), &obj); err != nil {
+			if err := decodeExample([]byte(s), &obj); err != nil {
 				// If we can't parse it, just return the string.
 				return s, nil
 			}
 			return obj, nil
 		case "array":
 			var slice []any
-			if err := json.Unmarshal([]byte(s), &slice); err != nil {
+			if err := decodeExample([]byte(s), &slice); err != nil {
 				// If we can't parse it, just return the string.
 				return s, nil
 			}
@@ -1020,6 +1217,26 @@ func (su *setReadOnlySchema) Parse(lines []string) error {
 	return nil
 }
 
+type setCustomTagSchema struct {
+	tgt *spec.Schema
+}
+
+func (su *setCustomTagSchema) Matches(line string) bool {
+	return rxCustomTag.MatchString(line)
+}
+
+// Parse sets the x-go-custom-tag extension verbatim, e.g. for "Custom Tag: gorm:"column:user_id"".
+func (su *setCustomTagSchema) Parse(lines []string) error {
+	if len(lines) == 0 || (len(lines) == 1 && len(lines[0]) == 0) {
+		return nil
+	}
+	matches := rxCustomTag.FindStringSubmatch(lines[0])
+	if len(matches) > 1 && len(matches[1]) > 0 {
+		su.tgt.AddExtension("x-go-custom-tag", matches[1])
+	}
+	return nil
+}
+
 type setDeprecatedOp struct {
 	tgt *spec.Operation
 }
@@ -1129,16 +1346,18 @@ func (m *multiLineDropEmptyParser) Parse(lines []string) error {
 	return nil
 }
 
-func newSetSchemes(set func([]string)) *setSchemes {
+func newSetSchemes(subject string, set func([]string)) *setSchemes {
 	return &setSchemes{
-		set: set,
-		rx:  rxSchemes,
+		set:     set,
+		rx:      rxSchemes,
+		subject: subject,
 	}
 }
 
 type setSchemes struct {
-	set func([]string)
-	rx  *regexp.Regexp
+	set     func([]string)
+	rx      *regexp.Regexp
+	subject string
 }
 
 func (ss *setSchemes) Matches(line string) bool {
@@ -1151,16 +1370,7 @@ func (ss *setSchemes) Parse(lines []string) error {
 	}
 	matches := ss.rx.FindStringSubmatch(lines[0])
 	if len(matches) > 1 && len(matches[1]) > 0 {
-		sch := strings.Split(matches[1], ", ")
-
-		schemes := []string{}
-		for _, s := range sch {
-			ts := strings.TrimSpace(s)
-			if ts != "" {
-				schemes = append(schemes, ts)
-			}
-		}
-		ss.set(schemes)
+		ss.set(normalizeSchemes([]string{matches[1]}, ss.subject))
 	}
 	return nil
 }
@@ -1181,10 +1391,22 @@ func (ss *setSecurity) Matches(line string) bool {
 	return ss.rx.MatchString(line)
 }
 
+func (ss *setSecurity) inlineValue(line string) string {
+	if loc := ss.rx.FindStringIndex(line); loc != nil {
+		return line[loc[1]:]
+	}
+	return ""
+}
+
 func (ss *setSecurity) Parse(lines []string) error {
 	if len(lines) == 0 || (len(lines) == 1 && len(lines[0]) == 0) {
 		return nil
 	}
+	if len(lines) == 1 && strings.TrimSpace(lines[0]) == securityNone {
+		// an explicit empty list of requirements, opting out of the default ones
+		ss.set([]map[string][]string{})
+		return nil
+	}
 
 	var result []map[string][]string
 	for _, line := range lines {
@@ -1225,6 +1447,13 @@ type setOpResponses struct {
 	rx          *regexp.Regexp
 	definitions map[string]spec.Schema
 	responses   map[string]spec.Response
+
+	// resolve returns the $ref of the Go type of a body tag which is not a known definition, e.g.
+	// models2.User, false when it names no Go type.
+	resolve func(target string) (spec.Ref, bool, error)
+	// headerConflicts reports the headers of the swagger:responses replaced by those of the route, as
+	// "status header" pairs, e.g. "200 X-Request-Id".
+	headerConflicts func(conflicts []string)
 }
 
 func (ss *setOpResponses) Matches(line string) bool {
@@ -1240,14 +1469,28 @@ const BodyTag = "body"
 // DescriptionTag used when specifying a response that gives a description of the response.
 const DescriptionTag = "description"
 
-func parseTags(line string) (modelOrResponse string, arrays int, isDefinitionRef bool, description string, err error) {
+// splitArrays returns the element of a possibly nested array type, e.g. Pet for [][]Pet, with its nesting.
+func splitArrays(value string) (string, int) {
+	arrays := 0
+	for strings.HasPrefix(value, "[]") {
+		arrays++
+		value = value[2:]
+	}
+	return value, arrays
+}
+
+func parseTags(line string) (modelOrResponse string, arrays int, isDefinitionRef bool, description string, contents []mediaTypeBody, err error) {
 	tags := strings.Split(line, " ")
 	parsedModelOrResponse := false
 
 	for i, tagAndValue := range tags {
 		tagValList := strings.SplitN(tagAndValue, ":", 2)
 		var tag, value string
-		if len(tagValList) > 1 {
+		if i > 0 && strings.HasPrefix(tagAndValue, `"`) {
+			// A quoted description may hold colons
+			tag = DescriptionTag
+			value = tagAndValue
+		} else if len(tagValList) > 1 {
 			tag = tagValList[0]
 			value = tagValList[1]
 		} else {
@@ -1261,6 +1504,12 @@ func parseTags(line string) (modelOrResponse string, arrays int, isDefinitionRef
 			value = tagValList[0]
 		}
 
+		if mediaType, ok := mediaTypeBodyTag(tag); ok {
+			target, nested := splitArrays(value)
+			contents = append(contents, mediaTypeBody{mediaType: mediaType, target: target, arrays: nested})
+			continue
+		}
+
 		foundModelOrResponse := false
 		if !parsedModelOrResponse {
 			if tag == BodyTag {
@@ -1275,14 +1524,8 @@ func parseTags(line string) (modelOrResponse string, arrays int, isDefinitionRef
 		if foundModelOrResponse {
 			// Read the model or response tag
 			parsedModelOrResponse = true
-			// Check for nested arrays
-			arrays = 0
-			for strings.HasPrefix(value, "[]") {
-				arrays++
-				value = value[2:]
-			}
-			// What's left over is the model name
-			modelOrResponse = value
+			// What's left over the nested arrays is the model name
+			modelOrResponse, arrays = splitArrays(value)
 		} else {
 			foundDescription := false
 			if tag == DescriptionTag {
@@ -1303,12 +1546,12 @@ func parseTags(line string) (modelOrResponse string, arrays int, isDefinitionRef
 				err = fmt.Errorf("invalid tag: %s", tag)
 			}
 			// return error
-			return modelOrResponse, arrays, isDefinitionRef, description, err
+			return modelOrResponse, arrays, isDefinitionRef, description, contents, err
 		}
 	}
 
 	// TODO: Maybe do, if !parsedModelOrResponse {return some error}
-	return modelOrResponse, arrays, isDefinitionRef, description, err
+	return modelOrResponse, arrays, isDefinitionRef, description, contents, err
 }
 
 func (ss *setOpResponses) Parse(lines []string) error {
@@ -1318,8 +1561,22 @@ func (ss *setOpResponses) Parse(lines []string) error {
 
 	var def *spec.Response
 	var scr map[int]spec.Response
+	var lastResponse string
+	var blocks []*headersBlock
+	var block *headersBlock // the Headers being parsed, if any
 
 	for _, line := range lines {
+		if block != nil {
+			inBlock, err := block.parse(line)
+			if err != nil {
+				return err
+			}
+			if inBlock {
+				continue
+			}
+			block = nil
+		}
+
 		kv := strings.SplitN(line, ":", 2)
 		var key, value string
 
@@ -1330,6 +1587,17 @@ func (ss *setOpResponses) Parse(lines []string) error {
 				continue
 			}
 			value = strings.TrimSpace(kv[1])
+			if strings.EqualFold(key, HeadersTag) && value == "" {
+				if lastResponse == "" {
+					return errors.New("the headers of a response follow its status code")
+				}
+				block = &headersBlock{response: lastResponse}
+				blocks = append(blocks, block)
+				continue
+			}
+			if isResponseKey(key) {
+				lastResponse = key
+			}
 			if value == "" {
 				var resp spec.Response
 				if strings.EqualFold("default", key) {
@@ -1346,24 +1614,40 @@ func (ss *setOpResponses) Parse(lines []string) error {
 				}
 				continue
 			}
-			refTarget, arrays, isDefinitionRef, description, err := parseTags(value)
+			refTarget, arrays, isDefinitionRef, description, contents, err := parseTags(value)
 			if err != nil {
 				return err
 			}
+			quoted := strings.HasPrefix(description, `"`)
+			if quoted {
+				if description, err = strconv.Unquote(description); err != nil {
+					return fmt.Errorf("invalid quoted description of response %s: %w", key, err)
+				}
+			}
 			// A possible exception for having a definition
+			var ref spec.Ref
+			resolved := false
 			if _, ok := ss.responses[refTarget]; !ok {
 				if _, ok := ss.definitions[refTarget]; ok {
 					isDefinitionRef = true
+				} else if isDefinitionRef && ss.resolve != nil {
+					if ref, resolved, err = ss.resolve(refTarget); err != nil {
+						return fmt.Errorf("response %s: %w", key, err)
+					}
 				}
 			}
 
-			var ref spec.Ref
-			if isDefinitionRef {
+			switch {
+			case resolved:
+				if description == "" {
+					description = refTarget
+				}
+			case isDefinitionRef:
 				if description == "" {
 					description = refTarget
 				}
 				ref, err = spec.NewRef("#/definitions/" + refTarget)
-			} else {
+			default:
 				ref, err = spec.NewRef("#/responses/" + refTarget)
 			}
 			if err != nil {
@@ -1389,9 +1673,21 @@ func (ss *setOpResponses) Parse(lines []string) error {
 					cs.Ref = ref
 				}
 				// ref. could be empty while use description tag
+			} else if shared, known := ss.responses[refTarget]; known && quoted {
+				// swagger 2.0 ignores the siblings of a $ref: a response reused with its own description
+				// is inlined, the doc comment of the struct remaining the description of the others
+				if resp, err = cloneResponse(shared); err != nil {
+					return err
+				}
+				resp.Description = description
 			} else if len(refTarget) > 0 {
 				resp.Ref = ref
 			}
+			if len(contents) > 0 {
+				if err := ss.setContents(&resp, contents); err != nil {
+					return fmt.Errorf("response %s: %w", key, err)
+				}
+			}
 
 			if strings.EqualFold("default", key) {
 				if def == nil {
@@ -1407,10 +1703,63 @@ func (ss *setOpResponses) Parse(lines []string) error {
 			}
 		}
 	}
+	if err := ss.setHeaders(blocks, def, scr); err != nil {
+		return err
+	}
 	ss.set(def, scr)
 	return nil
 }
 
+// setHeaders adds the Headers of the responses of a route to them, reporting those replacing the headers of a
+// swagger:response.
+func (ss *setOpResponses) setHeaders(blocks []*headersBlock, def *spec.Response, scr map[int]spec.Response) error {
+	var conflicts []string
+	for _, block := range blocks {
+		if err := block.check(); err != nil {
+			return err
+		}
+		var replaced []string
+		var err error
+		if strings.EqualFold(block.response, "default") {
+			if def == nil {
+				continue
+			}
+			*def, replaced, err = ss.withHeaders(*def, block)
+		} else {
+			code, _ := strconv.Atoi(block.response)
+			resp, found := scr[code]
+			if !found {
+				continue
+			}
+			resp, replaced, err = ss.withHeaders(resp, block)
+			scr[code] = resp
+		}
+		if err != nil {
+			return err
+		}
+		for _, name := range replaced {
+			conflicts = append(conflicts, block.response+" "+name)
+		}
+	}
+	if len(conflicts) > 0 && ss.headerConflicts != nil {
+		ss.headerConflicts(conflicts)
+	}
+	return nil
+}
+
+// cloneResponse returns a deep copy of a response.
+func cloneResponse(response spec.Response) (spec.Response, error) {
+	jazon, err := json.Marshal(response)
+	if err != nil {
+		return spec.Response{}, err
+	}
+	var clone spec.Response
+	if err := json.Unmarshal(jazon, &clone); err != nil {
+		return spec.Response{}, err
+	}
+	return clone, nil
+}
+
 func parseEnumOld(val string, s *spec.SimpleSchema) []any {
 	list := strings.Split(val, ",")
 	interfaceSlice := make([]any, len(list))
@@ -1465,8 +1814,9 @@ func newSetExtensions(setter func(*spec.Extensions)) *setOpExtensions {
 }
 
 type setOpExtensions struct {
-	set func(*spec.Extensions)
-	rx  *regexp.Regexp
+	set       func(*spec.Extensions)
+	rx        *regexp.Regexp
+	tagIndent int // the indentation of the Extensions: line, see startBlock
 }
 
 type extensionObject struct {
@@ -1623,11 +1973,31 @@ func (ss *setOpExtensions) Matches(line string) bool {
 	return ss.rx.MatchString(line)
 }
 
+func (ss *setOpExtensions) startBlock(tagLine string) {
+	ss.tagIndent = commentIndent(tagLine)
+}
+
+// Parse parses the extensions of the lines following an Extensions: line: as YAML, see parseExtensionsBlock,
+// when they are indented deeper than it, or else as the key: value lines of the extensions, whose values
+// are strings.
 func (ss *setOpExtensions) Parse(lines []string) error {
 	if len(lines) == 0 || (len(lines) == 1 && len(lines[0]) == 0) {
 		return nil
 	}
+	if blockIndented(ss.tagIndent, lines) {
+		exts, err := parseExtensionsBlock(lines)
+		if err != nil {
+			return err
+		}
+		if len(exts) > 0 {
+			ss.set(&exts)
+		}
+		return nil
+	}
 
+	for len(lines) > 0 && strings.TrimSpace(rxUncommentHeaders.ReplaceAllString(lines[len(lines)-1], "")) == "" {
+		lines = lines[:len(lines)-1] // the blank lines before the next tag, which the clean lines drop
+	}
 	cleanLines := cleanupScannerLines(lines, rxUncommentHeaders)
 
 	exts := new(spec.VendorExtensible)
@@ -1711,6 +2081,34 @@ func unsupportedBasic(tpe *types.Basic) bool {
 	return found
 }
 
+// commentLines splits the text of a comment in lines, without the carriage returns of the files with CRLF
+// line endings. The Go scanner drops them from the comments of the files it parses, but the ASTs built
+// otherwise may keep them, e.g. those of the packages given to RunOnPackages.
+func commentLines(text string) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for line := range strings.SplitSeq(text, "\n") {
+			if !yield(strings.TrimSuffix(line, "\r")) {
+				return
+			}
+		}
+	}
+}
+
+// commentLinesAt iterates over the lines of a comment, like commentLines, with their positions.
+func commentLinesAt(c *ast.Comment) iter.Seq2[token.Pos, string] {
+	return func(yield func(token.Pos, string) bool) {
+		pos := c.Slash
+		for line := range strings.SplitSeq(c.Text, "\n") {
+			if !yield(pos, strings.TrimSuffix(line, "\r")) {
+				return
+			}
+			if pos.IsValid() {
+				pos += token.Pos(len(line) + 1)
+			}
+		}
+	}
+}
+
 func cleanupScannerLines(lines []string, ur *regexp.Regexp) []string {
 	// bail early when there is nothing to parse
 	if len(lines) == 0 {
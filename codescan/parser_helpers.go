@@ -4,13 +4,14 @@
 package codescan
 
 import (
+	"regexp"
 	"strings"
 )
 
 // a shared function that can be used to split given headers
 // into a title and description.
-func collectScannerTitleDescription(headers []string) (title, desc []string) {
-	hdrs := cleanupScannerLines(headers, rxUncommentHeaders)
+func collectScannerTitleDescription(headers []string, uncomment *regexp.Regexp) (title, desc []string) {
+	hdrs := cleanDescription(headers, uncomment)
 
 	idx := -1
 	for i, line := range hdrs {
@@ -272,19 +272,51 @@ func ascg(txt string) *ast.CommentGroup {
 }
 
 func TestShouldAcceptTag(t *testing.T) {
+	users := map[string]bool{"users": true}
+	usersAndAdmin := map[string]bool{"users": true, "admin": true}
+	internal := map[string]bool{"internal": true}
+
 	tagTests := []struct {
 		tags        []string
 		includeTags map[string]bool
 		excludeTags map[string]bool
+		requireAll  bool
 		expected    bool
+		reason      string
 	}{
-		{nil, nil, nil, true},
-		{[]string{"app"}, map[string]bool{"app": true}, nil, true},
-		{[]string{"app"}, nil, map[string]bool{"app": true}, false},
+		{nil, nil, nil, false, true, "no tag is excluded"},
+		{[]string{"app"}, map[string]bool{"app": true}, nil, false, true, "tag app is included"},
+		{[]string{"app"}, nil, map[string]bool{"app": true}, false, false, "tag app is excluded"},
+
+		// truth table of the tags users and internal, with --include-tags users --exclude-tags internal
+		{nil, users, internal, false, false, "no tag is included"},
+		{[]string{"users"}, users, internal, false, true, "tag users is included"},
+		{[]string{"internal"}, users, internal, false, false, "tag internal is excluded"},
+		{[]string{"users", "internal"}, users, internal, false, false, "tag internal is excluded"},
+		{[]string{"internal", "users"}, users, internal, false, false, "tag internal is excluded"},
+		{[]string{"other"}, users, internal, false, false, "no tag is included"},
+
+		// only excluded tags
+		{nil, nil, internal, false, true, "no tag is excluded"},
+		{[]string{"users"}, nil, internal, false, true, "no tag is excluded"},
+		{[]string{"users", "internal"}, nil, internal, false, false, "tag internal is excluded"},
+
+		// one of the included tags, or all of them with requireAll
+		{[]string{"users"}, usersAndAdmin, nil, false, true, "tag users is included"},
+		{[]string{"admin", "other"}, usersAndAdmin, nil, false, true, "tag admin is included"},
+		{[]string{"users"}, usersAndAdmin, nil, true, false, "included tag admin is missing"},
+		{[]string{"admin"}, usersAndAdmin, nil, true, false, "included tag users is missing"},
+		{[]string{"users", "admin"}, usersAndAdmin, nil, true, true, "all the included tags are present"},
+		{[]string{"admin", "other", "users"}, usersAndAdmin, nil, true, true, "all the included tags are present"},
+		{[]string{"users", "admin", "internal"}, usersAndAdmin, internal, true, false, "tag internal is excluded"},
+		{nil, usersAndAdmin, nil, true, false, "included tag admin is missing"},
+		{[]string{"users"}, users, internal, true, true, "all the included tags are present"},
+		{[]string{"users", "internal"}, users, internal, true, false, "tag internal is excluded"},
 	}
 	for _, tt := range tagTests {
-		actual := shouldAcceptTag(tt.tags, tt.includeTags, tt.excludeTags)
-		assert.Equal(t, tt.expected, actual)
+		actual, reason := shouldAcceptTag(tt.tags, tt.includeTags, tt.excludeTags, tt.requireAll)
+		assert.Equal(t, tt.expected, actual, "%v", tt)
+		assert.Equal(t, tt.reason, reason, "%v", tt)
 	}
 }
 
@@ -308,3 +340,69 @@ func TestShouldAcceptPkg(t *testing.T) {
 		assert.Equal(t, tt.expected, actual)
 	}
 }
+
+func TestMatchPackageGlob(t *testing.T) {
+	globTests := []struct {
+		pattern  string
+		path     string
+		expected bool
+	}{
+		{"github.com/acme/api/models", "github.com/acme/api/models", true},
+		{"github.com/acme/api/models", "github.com/acme/api/models/v2", false},
+		{"github.com/acme/api/models/...", "github.com/acme/api/models", true},
+		{"github.com/acme/api/models/...", "github.com/acme/api/models/v2", true},
+		{"github.com/acme/api/models/...", "github.com/acme/api/modelsx", false},
+		{"github.com/acme/*/models/...", "github.com/acme/billing/models/v1", true},
+		{"github.com/acme/*/models", "github.com/acme/billing/responses", false},
+	}
+	for _, tt := range globTests {
+		actual := matchPackageGlob(tt.pattern, tt.path)
+		assert.Equal(t, tt.expected, actual, "%s => %s", tt.pattern, tt.path)
+	}
+}
+
+func TestSectionedParser_CRLF(t *testing.T) {
+	block := "This has a title.\r\n\r\nAnd a description.\r\nminimum: 10\r\nmaximum: 20\r"
+
+	schema := new(spec.Schema)
+	st := &sectionedParser{}
+	st.setTitle = func(_ []string) {}
+	st.taggers = []tagParser{
+		{"Maximum", false, false, nil, &setMaximum{schemaValidations{schema}, regexp.MustCompile(fmt.Sprintf(rxMaximumFmt, ""))}},
+		{"Minimum", false, false, nil, &setMinimum{schemaValidations{schema}, regexp.MustCompile(fmt.Sprintf(rxMinimumFmt, ""))}},
+	}
+
+	require.NoError(t, st.Parse(ascg(block)))
+	assert.Equal(t, []string{"This has a title."}, st.Title())
+	assert.Equal(t, []string{"And a description."}, st.Description())
+	require.NotNil(t, schema.Minimum)
+	require.NotNil(t, schema.Maximum)
+	assert.InDelta(t, 10, *schema.Minimum, 0)
+	assert.InDelta(t, 20, *schema.Maximum, 0)
+}
+
+func TestCRLFSources(t *testing.T) {
+	doc, err := Run(&Options{Packages: []string{"github.com/3idey/codescan/fixtures/goparsing/crlf"}})
+	require.NoError(t, err)
+
+	pet := doc.Definitions["Pet"]
+	assert.Equal(t, "A Pet is an animal of the store.", pet.Title)
+	assert.Equal(t, "It has a name and an age.", pet.Description)
+
+	name := pet.Properties["name"]
+	assert.Equal(t, "The name of the pet.", name.Description)
+	require.NotNil(t, name.MaxLength)
+	assert.Equal(t, int64(64), *name.MaxLength)
+	assert.Equal(t, "Rex", name.Example)
+
+	age := pet.Properties["age"]
+	require.NotNil(t, age.Maximum)
+	assert.InDelta(t, 40, *age.Maximum, 0)
+
+	require.NotNil(t, doc.Paths)
+	op := doc.Paths.Paths["/pets"].Get
+	require.NotNil(t, op)
+	assert.Equal(t, "Lists the pets.", op.Summary)
+	assert.Contains(t, op.Responses.StatusCodeResponses, 200)
+	assert.Equal(t, "The pets of the store.", doc.Responses["petsResponse"].Description)
+}
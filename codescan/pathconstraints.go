@@ -0,0 +1,157 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// rxNumericConstraint matches the regular expressions of path parameters which only match integers, e.g.
+// [0-9]+, \d{4} or [1-9][0-9]*.
+var rxNumericConstraint = regexp.MustCompile(`^\^?(?:(?:\[[0-9]-[0-9]\]|\\d|[0-9])(?:[+*?]|\{[0-9]+(?:,[0-9]*)?\})?)+\$?$`)
+
+// splitPathConstraints strips the regular expressions of the path parameters of a route template, e.g.
+// /users/{id:[0-9]+} of chi and gorilla/mux, returning the swagger path, /users/{id}, and the regular
+// expressions by parameter name. The braces of a regular expression, e.g. [0-9]{4}, are kept with it.
+func splitPathConstraints(path string) (string, map[string]string) {
+	if !strings.Contains(path, ":") {
+		return path, nil
+	}
+	var stripped strings.Builder
+	var constraints map[string]string
+	for len(path) > 0 {
+		open := strings.IndexByte(path, '{')
+		if open < 0 {
+			stripped.WriteString(path)
+			break
+		}
+		stripped.WriteString(path[:open+1])
+		path = path[open+1:]
+
+		end, depth := -1, 1
+		for i := 0; i < len(path) && end < 0; i++ {
+			switch path[i] {
+			case '\\':
+				i++
+			case '{':
+				depth++
+			case '}':
+				if depth--; depth == 0 {
+					end = i
+				}
+			}
+		}
+		if end < 0 {
+			stripped.WriteString(path)
+			break
+		}
+		name, constraint, constrained := strings.Cut(path[:end], ":")
+		stripped.WriteString(name + "}")
+		if constrained && constraint != "" {
+			if constraints == nil {
+				constraints = make(map[string]string)
+			}
+			constraints[name] = constraint
+		}
+		path = path[end+1:]
+	}
+	return stripped.String(), constraints
+}
+
+// anchoredPattern is the pattern of the regular expression of a path parameter, which the routers match against
+// the whole segment, while a pattern of JSON Schema matches anywhere in the value: [0-9]+ is ^[0-9]+$, and
+// a|b ^(?:a|b)$. The anchors written in the expression are kept, not doubled.
+func anchoredPattern(constraint string) string {
+	inner := strings.TrimPrefix(constraint, "^")
+	if strings.HasSuffix(inner, "$") && !escapedAt(inner, len(inner)-1) {
+		inner = inner[:len(inner)-1]
+	}
+	if hasTopLevelAlternation(inner) {
+		inner = "(?:" + inner + ")"
+	}
+	return "^" + inner + "$"
+}
+
+// escapedAt tells whether the character at i of a regular expression is escaped by an odd number of
+// backslashes.
+func escapedAt(expr string, i int) bool {
+	backslashes := 0
+	for j := i - 1; j >= 0 && expr[j] == '\\'; j-- {
+		backslashes++
+	}
+	return backslashes%2 == 1
+}
+
+// hasTopLevelAlternation tells whether a regular expression has a | outside of its groups and classes, which
+// the anchors would otherwise bind to the first and last alternatives only.
+func hasTopLevelAlternation(expr string) bool {
+	depth, inClass := 0, false
+	for i := 0; i < len(expr); i++ {
+		switch c := expr[i]; {
+		case c == '\\':
+			i++
+		case inClass:
+			inClass = c != ']'
+		case c == '[':
+			inClass = true
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == '|' && depth == 0:
+			return true
+		}
+	}
+	return false
+}
+
+// applyPathConstraints sets the regular expressions of the template of a route as the patterns of its path
+// parameters, anchored like the routers match them, see anchoredPattern. A pattern declared on the parameter wins, with a path-constraint diagnostic when it differs.
+// The constrained parameters the operation doesn't declare are added as required strings, or integers for
+// the numeric expressions with Options.InferParamTypes.
+func (c *scanCtx) applyPathConstraints(route parsedPathContent, op *spec.Operation) {
+	if len(route.constraints) == 0 {
+		return
+	}
+	declared := make(map[string]bool)
+	for i := range op.Parameters {
+		param := &op.Parameters[i]
+		constraint, found := route.constraints[param.Name]
+		if param.In != "path" || !found {
+			continue
+		}
+		declared[param.Name] = true
+		switch {
+		case param.Type != "" && param.Type != TypeString:
+			// the patterns only apply to strings
+		case param.Pattern == "":
+			param.Pattern = anchoredPattern(constraint)
+		case param.Pattern != constraint && param.Pattern != anchoredPattern(constraint):
+			c.app.diagnose(Diagnostic{
+				Pos:  route.Pos,
+				Code: DiagnosticPathConstraint,
+				Message: fmt.Sprintf("the pattern %s of the path parameter %s of operation %s wins over the constraint %s of its route",
+					param.Pattern, param.Name, op.ID, constraint),
+			})
+		}
+	}
+
+	for _, name := range sortedKeys(route.constraints) {
+		if declared[name] {
+			continue
+		}
+		constraint := route.constraints[name]
+		param := spec.PathParam(name).Typed(TypeString, "")
+		if c.opts.InferParamTypes && rxNumericConstraint.MatchString(constraint) {
+			param.Typed(TypeInteger, "")
+		} else {
+			param.WithPattern(anchoredPattern(constraint))
+		}
+		op.AddParam(param)
+	}
+}
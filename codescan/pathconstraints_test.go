@@ -0,0 +1,130 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPathConstraints(t *testing.T) {
+	const pkg = "github.com/3idey/codescan/fixtures/goparsing/pathconstraints"
+	run := func(t *testing.T, infer bool) (*spec.Swagger, []Diagnostic) {
+		t.Helper()
+		var diagnostics []Diagnostic
+		doc, err := Run(&Options{Packages: []string{pkg}, InferParamTypes: infer, Diagnostics: &diagnostics})
+		require.NoError(t, err)
+		return doc, diagnostics
+	}
+
+	t.Run("should strip the regular expressions from the paths", func(t *testing.T) {
+		doc, _ := run(t, false)
+		assert.Contains(t, doc.Paths.Paths, "/users/{id}")
+		assert.Contains(t, doc.Paths.Paths, "/orders/{year}/{slug}")
+		assert.Len(t, doc.Paths.Paths, 2)
+	})
+
+	t.Run("should set the regular expressions as the patterns of the declared parameters", func(t *testing.T) {
+		doc, _ := run(t, false)
+		get := doc.Paths.Paths["/users/{id}"].Get
+		require.Len(t, get.Parameters, 1)
+		assert.Equal(t, "^[0-9]+$", get.Parameters[0].Pattern)
+		assert.Equal(t, "string", get.Parameters[0].Type)
+	})
+
+	t.Run("should prefer the declared pattern, with a warning", func(t *testing.T) {
+		doc, diagnostics := run(t, false)
+		put := doc.Paths.Paths["/users/{id}"].Put
+		require.Len(t, put.Parameters, 1)
+		assert.Equal(t, "^[1-9][0-9]*$", put.Parameters[0].Pattern)
+
+		var conflicts []Diagnostic
+		for _, diagnostic := range diagnostics {
+			if diagnostic.Code == DiagnosticPathConstraint {
+				conflicts = append(conflicts, diagnostic)
+			}
+		}
+		require.Len(t, conflicts, 1)
+		assert.Equal(t, 17, conflicts[0].Pos.Line)
+		assert.Equal(t, "the pattern ^[1-9][0-9]*$ of the path parameter id of operation updateUser wins over the constraint [0-9]+ of its route", conflicts[0].Message)
+	})
+
+	t.Run("should add the undeclared parameters", func(t *testing.T) {
+		doc, _ := run(t, false)
+		list := doc.Paths.Paths["/orders/{year}/{slug}"].Get
+		require.Len(t, list.Parameters, 2)
+		slug, year := list.Parameters[0], list.Parameters[1]
+		assert.Equal(t, "slug", slug.Name)
+		assert.Equal(t, "path", slug.In)
+		assert.True(t, slug.Required)
+		assert.Equal(t, "^[a-z-]+$", slug.Pattern)
+		assert.Equal(t, "string", year.Type)
+		assert.Equal(t, "^[0-9]{4}$", year.Pattern)
+	})
+
+	t.Run("should type the numeric parameters with InferParamTypes", func(t *testing.T) {
+		doc, _ := run(t, true)
+		list := doc.Paths.Paths["/orders/{year}/{slug}"].Get
+		require.Len(t, list.Parameters, 2)
+		assert.Equal(t, "string", list.Parameters[0].Type)
+		year := list.Parameters[1]
+		assert.Equal(t, "integer", year.Type)
+		assert.Empty(t, year.Pattern, "the patterns only apply to strings")
+		assert.Equal(t, "string", doc.Paths.Paths["/users/{id}"].Get.Parameters[0].Type, "the declared types are kept")
+	})
+}
+
+func TestSplitPathConstraints(t *testing.T) {
+	for _, tc := range []struct {
+		path, expected string
+		constraints    map[string]string
+	}{
+		{"/users/{id}", "/users/{id}", nil},
+		{"/users/{id:[0-9]+}", "/users/{id}", map[string]string{"id": "[0-9]+"}},
+		{"/{year:[0-9]{4}}/{month:\\d{1,2}}", "/{year}/{month}", map[string]string{"year": "[0-9]{4}", "month": "\\d{1,2}"}},
+		{"/files/{path:.+/.+}", "/files/{path}", map[string]string{"path": ".+/.+"}},
+		{"/v1/:id", "/v1/:id", nil},
+		{"/broken/{id:[0-9]+", "/broken/{id:[0-9]+", nil},
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			path, constraints := splitPathConstraints(tc.path)
+			assert.Equal(t, tc.expected, path)
+			assert.Equal(t, tc.constraints, constraints)
+		})
+	}
+
+	for _, numeric := range []string{"[0-9]+", "\\d{4}", "^[1-9][0-9]*$", "[0-9]{1,5}"} {
+		assert.True(t, rxNumericConstraint.MatchString(numeric), numeric)
+	}
+	for _, other := range []string{"[a-z]+", "[0-9a-f]+", "\\d+\\.\\d+", ".+"} {
+		assert.False(t, rxNumericConstraint.MatchString(other), other)
+	}
+}
+
+func TestAnchoredPattern(t *testing.T) {
+	for constraint, want := range map[string]string{
+		`[0-9]+`:        `^[0-9]+$`,
+		`[a-z]{2,3}`:    `^[a-z]{2,3}$`,
+		`^[0-9]+$`:      `^[0-9]+$`,
+		`^v[0-9]+`:      `^v[0-9]+$`,
+		`[a-z]+$`:       `^[a-z]+$`,
+		`cat|dog`:       `^(?:cat|dog)$`,
+		`^cat|dog$`:     `^(?:cat|dog)$`,
+		`(cat|dog)s?`:   `^(cat|dog)s?$`,
+		`[|a]+`:         `^[|a]+$`,
+		`\|[a-z]+`:      `^\|[a-z]+$`,
+		`[0-9]+\$`:      `^[0-9]+\$$`,
+		`[0-9]+\\$`:     `^[0-9]+\\$`,
+		`[^/]+`:         `^[^/]+$`,
+		`[a\]|]+`:       `^[a\]|]+$`,
+		`\d{4}-\d{2}`:   `^\d{4}-\d{2}$`,
+		`(?i)[a-z]+`:    `^(?i)[a-z]+$`,
+		`[0-9]+|latest`: `^(?:[0-9]+|latest)$`,
+	} {
+		assert.Equal(t, want, anchoredPattern(constraint), constraint)
+	}
+}
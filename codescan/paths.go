@@ -0,0 +1,100 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"log"
+
+	"golang.org/x/tools/go/packages"
+)
+
+const (
+	xSummary     = "x-summary"
+	xDescription = "x-description"
+)
+
+// parsedPathDoc is the documentation of a path, shared by all its operations:
+//
+//	// swagger:path /users/{id}
+//	//
+//	// A user of the API.
+//	//
+//	// Users are created by signing up, and are never deleted.
+type parsedPathDoc struct {
+	Path      string
+	Remaining *ast.CommentGroup
+	Pos       token.Position // position of the annotation
+}
+
+func parsePathDoc(lines []*ast.Comment) (doc parsedPathDoc, annotation token.Pos) {
+	for _, cmt := range lines {
+		for line := range commentLines(cmt.Text) {
+			if matches := rxPathDoc.FindStringSubmatch(line); len(matches) > 1 {
+				doc.Path = matches[1]
+				annotation = cmt.Slash
+				continue
+			}
+			if doc.Path == "" {
+				continue
+			}
+			if doc.Remaining == nil {
+				doc.Remaining = new(ast.CommentGroup)
+			}
+			doc.Remaining.List = append(doc.Remaining.List, &ast.Comment{Slash: cmt.Slash, Text: line})
+		}
+	}
+	return doc, annotation
+}
+
+// collectPathDocs collects the swagger:path annotations of a file. A path is documented once.
+func (a *typeIndex) collectPathDocs(pkg *packages.Package, file *ast.File) error {
+	for _, cmts := range file.Comments {
+		doc, annotation := parsePathDoc(cmts.List)
+		if doc.Path == "" {
+			continue
+		}
+		doc.Path = a.normalizePath(doc.Path)
+		doc.Pos = pkg.Fset.Position(annotation)
+		for _, other := range a.Paths {
+			if other.Path == doc.Path {
+				return fmt.Errorf("%v: path %s is already documented at %v", doc.Pos, doc.Path, other.Pos)
+			}
+		}
+		a.Paths = append(a.Paths, doc)
+		a.countAnnotation(pkg)
+	}
+	return nil
+}
+
+// buildPathDocs sets the summary and description of the documented paths, as the x-summary and
+// x-description extensions of their path item: swagger 2.0 has no such fields.
+func (s *specBuilder) buildPathDocs() error {
+	for _, doc := range s.ctx.app.Paths {
+		pathItem, ok := s.input.Paths.Paths[doc.Path]
+		if !ok {
+			log.Printf("WARNING: %v: swagger:path %s documents a path without operations", doc.Pos, doc.Path)
+			continue
+		}
+
+		sp := &sectionedParser{preserveFormat: s.ctx.opts.PreserveCommentFormat}
+		sp.setTitle = func(lines []string) {
+			if summary := joinDropLast(lines); summary != "" {
+				pathItem.AddExtension(xSummary, summary)
+			}
+		}
+		sp.setDescription = func(lines []string) {
+			if description := joinDropLast(lines); description != "" {
+				pathItem.AddExtension(xDescription, description)
+			}
+		}
+		if err := sp.Parse(doc.Remaining); err != nil {
+			return fmt.Errorf("path (%s): %w", doc.Path, err)
+		}
+		s.input.Paths.Paths[doc.Path] = pathItem
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPathDocs(t *testing.T) {
+	doc, err := Run(&Options{Packages: []string{"github.com/3idey/codescan/fixtures/goparsing/paths"}})
+	require.NoError(t, err)
+
+	user := doc.Paths.Paths["/users/{id}"]
+	assert.Equal(t, "A user of the API.", user.Extensions[xSummary])
+	assert.Equal(t, "Users are created by signing up,\nand are deleted with their account.", user.Extensions[xDescription])
+	assert.NotNil(t, user.Get)
+	assert.NotNil(t, user.Delete)
+
+	users := doc.Paths.Paths["/users"]
+	assert.Equal(t, "The users of the API.", users.Extensions[xSummary])
+	assert.NotContains(t, users.Extensions, xDescription)
+
+	assert.NotContains(t, doc.Paths.Paths, "/groups", "a path without operations is not created")
+}
+
+func TestPathDocsDuplicate(t *testing.T) {
+	_, err := Run(&Options{Packages: []string{"github.com/3idey/codescan/fixtures/goparsing/paths/duplicate"}})
+	require.Error(t, err)
+	assert.Regexp(t, `api\.go:16:1: path /users is already documented at .*api\.go:11:1`, err.Error())
+}
+
+func TestPathDocsOpenAPI3(t *testing.T) {
+	doc, err := Run3(&Options{Packages: []string{"github.com/3idey/codescan/fixtures/goparsing/paths"}})
+	require.NoError(t, err)
+
+	user := asObject(asObject(doc["paths"])["/users/{id}"])
+	assert.Equal(t, "A user of the API.", user["summary"])
+	assert.Equal(t, "Users are created by signing up,\nand are deleted with their account.", user["description"])
+	assert.NotContains(t, user, xSummary)
+	assert.NotContains(t, user, xDescription)
+	assert.Contains(t, user, "get")
+
+	users := asObject(asObject(doc["paths"])["/users"])
+	assert.Equal(t, "The users of the API.", users["summary"])
+	assert.NotContains(t, users, "description")
+}
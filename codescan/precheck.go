@@ -0,0 +1,186 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"errors"
+	"go/ast"
+	"go/token"
+	"regexp"
+	"slices"
+
+	"github.com/go-openapi/spec"
+	"golang.org/x/tools/go/packages"
+)
+
+// precheckLoadMode parses the files of the packages, without loading their dependencies nor type checking them.
+const precheckLoadMode = packages.NeedName | packages.NeedFiles | packages.NeedSyntax
+
+// pathAnnotations are the annotations of paths, and of their tags, with the form Run expects.
+var pathAnnotations = []struct {
+	name     string
+	rx       *regexp.Regexp
+	expected string
+}{
+	{"route", rxRoute, "swagger:route METHOD /path [tags...] operationID"},
+	{"operation", rxOperation, "swagger:operation METHOD /path [tags...] operationID"},
+	{"path", rxPathDoc, "swagger:path /path"},
+	{"tag", rxTagDoc, "swagger:tag name"},
+}
+
+// Precheck checks the grammar of the annotations of the packages, parsing their files without type checking
+// them, which takes a fraction of the time of Run. It reports, as diagnostics with the code
+// DiagnosticMalformedAnnotation, the annotations Run would reject or ignore: unknown annotations, paths
+// without method or operation ID, misspelled swagger: prefixes, malformed validations of fields, e.g.
+// "Maximum: abc", and invalid route, operation and meta sections. Annotations which need the types, e.g.
+// the properties of models, are only checked by Run. With Compat, the legacy annotations are reported as
+// DiagnosticLegacyAnnotation.
+//
+// Precheck uses Packages, WorkDir, BuildTags, ExtraBuildTags, IncludeTestScope, Include, Exclude, Overlay,
+// Env, PackagesDriver, Loader, Compat, and the severity Rules give to DiagnosticMalformedAnnotation and
+// DiagnosticLegacyAnnotation.
+func Precheck(opts *Options) ([]Diagnostic, error) {
+	_, ruleSeverities, err := compileRules(opts.Rules)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCompat(opts.Compat); err != nil {
+		return nil, err
+	}
+	if err := checkEnv(opts.Env); err != nil {
+		return nil, err
+	}
+	cfg := &packages.Config{
+		Dir:     opts.WorkDir,
+		Mode:    precheckLoadMode,
+		Tests:   opts.IncludeTestScope,
+		Overlay: opts.Overlay,
+		Env:     loadEnv(opts),
+	}
+	tags := []string{opts.BuildTags}
+	if opts.ExtraBuildTags != "" {
+		tags = append(tags, joinBuildTags(opts.BuildTags, opts.ExtraBuildTags))
+	}
+
+	app := &typeIndex{severities: ruleSeverities, legacyAnnotations: slices.Contains(opts.Compat, CompatLegacyGoSwagger)}
+	ctx := &scanCtx{app: app, opts: &Options{}}
+	checked := make(map[string]bool)
+	for _, tag := range tags {
+		cfg.BuildFlags = nil
+		if tag != "" {
+			cfg.BuildFlags = []string{"-tags", tag}
+		}
+		pkgs, err := loadWith(opts, cfg, opts.Packages...)
+		if err != nil {
+			return nil, err
+		}
+		for _, pkg := range pkgs {
+			if !shouldAcceptPkg(pkg.PkgPath, opts.Include, opts.Exclude) {
+				continue
+			}
+			for _, file := range pkg.Syntax {
+				filename := pkg.Fset.Position(file.Pos()).Filename
+				if checked[filename] {
+					continue
+				}
+				checked[filename] = true
+				ctx.app.precheckFile(ctx, pkg.Fset, file)
+			}
+		}
+	}
+
+	diagnostics := slices.Concat(ctx.app.diagnosticsWithCode(DiagnosticMalformedAnnotation), ctx.app.diagnosticsWithCode(DiagnosticLegacyAnnotation))
+	diagnostics = slices.DeleteFunc(diagnostics, func(diagnostic Diagnostic) bool {
+		return diagnostic.Severity == SeverityOff
+	})
+	slices.SortStableFunc(diagnostics, func(a, b Diagnostic) int {
+		if a.Pos.Filename != b.Pos.Filename {
+			if a.Pos.Filename < b.Pos.Filename {
+				return -1
+			}
+			return 1
+		}
+		return a.Pos.Line - b.Pos.Line
+	})
+	return diagnostics, nil
+}
+
+// precheckFile checks the annotations of a file with the parsers of Run.
+func (a *typeIndex) precheckFile(ctx *scanCtx, fset *token.FileSet, file *ast.File) {
+	if _, err := a.detectNodes(fset, file); err != nil {
+		for _, err := range joinedErrors(err) {
+			var diagnostic Diagnostic
+			if !errors.As(err, &diagnostic) {
+				diagnostic = malformedAnnotation(fset.Position(file.Pos()), err.Error())
+			}
+			a.diagnose(diagnostic)
+		}
+	}
+
+	for _, cmts := range file.Comments {
+		paths := &spec.Paths{Paths: make(map[string]spec.PathItem)}
+		if pp := parsePathAnnotation(rxRoute, cmts.List); pp.Method != "" {
+			pp.Pos = fset.Position(pp.annotation)
+			rb := &routesBuilder{ctx: ctx, route: pp, operations: make(map[string]*spec.Operation), fset: fset}
+			if err := rb.Build(paths); err != nil {
+				a.diagnose(blockAnnotationError(pp.Pos, "", err))
+			}
+		}
+		if pp := parsePathAnnotation(rxOperation, cmts.List); pp.Method != "" {
+			pp.Pos = fset.Position(pp.annotation)
+			ob := &operationsBuilder{ctx: ctx, path: pp, operations: make(map[string]*spec.Operation)}
+			if err := ob.Build(paths); err != nil {
+				a.diagnose(malformedAnnotation(pp.Pos, err.Error()))
+			}
+		}
+	}
+
+	if pos, found := annotationPos(file.Doc, "meta"); found {
+		parser := newMetaParser(new(spec.Swagger))
+		parser.fset = fset
+		if err := parser.Parse(file.Doc); err != nil {
+			a.diagnose(blockAnnotationError(fset.Position(pos), "swagger:meta: ", err))
+		}
+	}
+}
+
+func malformedAnnotation(pos token.Position, message string) Diagnostic {
+	return Diagnostic{Pos: pos, Code: DiagnosticMalformedAnnotation, Message: message}
+}
+
+// blockAnnotationError reports the error of an annotation at the line of the block it fails at, if any, see
+// blockLineError, and at the annotation otherwise.
+func blockAnnotationError(pos token.Position, prefix string, err error) Diagnostic {
+	var lineErr *blockLineError
+	if errors.As(err, &lineErr) && lineErr.pos.IsValid() {
+		return malformedAnnotation(lineErr.pos, prefix+lineErr.err.Error())
+	}
+	return malformedAnnotation(pos, prefix+err.Error())
+}
+
+// annotationPos finds a swagger annotation in a comment group.
+func annotationPos(cmts *ast.CommentGroup, name string) (token.Pos, bool) {
+	if cmts == nil {
+		return token.NoPos, false
+	}
+	for _, cmt := range cmts.List {
+		for _, matches := range rxSwaggerAnnotation.FindAllStringSubmatch(cmt.Text, -1) {
+			if matches[1] == name {
+				return cmt.Slash, true
+			}
+		}
+	}
+	return token.NoPos, false
+}
+
+func hasMatch(cmts *ast.CommentGroup, rx *regexp.Regexp) bool {
+	for _, cmt := range cmts.List {
+		for line := range commentLines(cmt.Text) {
+			if rx.MatchString(line) {
+				return true
+			}
+		}
+	}
+	return false
+}
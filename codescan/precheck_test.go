@@ -0,0 +1,60 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const precheckFixture = "github.com/3idey/codescan/fixtures/goparsing/precheck"
+
+func TestPrecheck(t *testing.T) {
+	diagnostics, err := Precheck(&Options{Packages: []string{precheckFixture + "/..."}})
+	require.NoError(t, err)
+
+	var problems []string
+	for _, diagnostic := range diagnostics {
+		problems = append(problems, fmt.Sprintf("%s:%d: %s: %s", filepath.Base(diagnostic.Pos.Filename), diagnostic.Pos.Line, diagnostic.Code, diagnostic.Message))
+	}
+	assert.Equal(t, []string{
+		"api.go:8: malformed-annotation: swagger:meta: json: cannot unmarshal array into Go value of type spec.SecuritySchemeProps",
+		"api.go:25: malformed-annotation: malformed swagger:route annotation, expected swagger:route METHOD /path [tags...] operationID",
+		"api.go:29: malformed-annotation: operation (createPet): json: cannot unmarshal array into Go value of type spec.ResponseProps",
+		`models.go:6: malformed-annotation: classifier: unknown swagger annotation "modle", did you mean swagger:model?`,
+	}, problems)
+
+	t.Run("should reject what Run rejects", func(t *testing.T) {
+		_, err := Run(&Options{Packages: []string{precheckFixture + "/unknown"}})
+		require.Error(t, err)
+		assert.Regexp(t, `models\.go:6:1: classifier: unknown swagger annotation "modle", did you mean swagger:model\?$`, err.Error())
+	})
+
+	t.Run("should read the overlay instead of the files", func(t *testing.T) {
+		file, err := filepath.Abs("../fixtures/goparsing/paths/api.go")
+		require.NoError(t, err)
+		content, err := os.ReadFile(file)
+		require.NoError(t, err)
+
+		diagnostics, err := Precheck(&Options{
+			Packages: []string{"github.com/3idey/codescan/fixtures/goparsing/paths"},
+			Overlay:  map[string][]byte{file: append(content, "\n// swagger:modle\ntype Unsaved struct{}\n"...)},
+		})
+		require.NoError(t, err)
+		require.Len(t, diagnostics, 1)
+		assert.Equal(t, file, diagnostics[0].Pos.Filename)
+		assert.Contains(t, diagnostics[0].Message, `unknown swagger annotation "modle"`)
+	})
+
+	t.Run("should accept valid annotations", func(t *testing.T) {
+		diagnostics, err := Precheck(&Options{Packages: []string{"github.com/3idey/codescan/fixtures/goparsing/paths"}})
+		require.NoError(t, err)
+		assert.Empty(t, diagnostics)
+	})
+}
@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-openapi/spec"
+	"golang.org/x/tools/go/packages"
+)
+
+// PackagesLoadMode is the minimum load mode of the packages scanned by RunOnPackages: the names, files,
+// syntax, types and type information of the packages and of their dependencies, and their modules, which
+// Options.ExcludeDeps needs to walk into the packages of the local modules.
+const PackagesLoadMode = pkgLoadMode
+
+// RunOnPackages scans packages loaded already, e.g. by a pipeline loading them for other analyzers, instead
+// of loading Options.Packages, which is ignored like Options.WorkDir, Options.BuildTags and Options.Loader. The
+// packages must be loaded with at least PackagesLoadMode, and with Tests for Options.IncludeTestScope.
+//
+// Options.ForceIncludeDirs and Options.ExtraBuildTags, which load more packages, are not supported.
+func RunOnPackages(pkgs []*packages.Package, opts *Options) (*spec.Swagger, error) {
+	if len(pkgs) == 0 {
+		return nil, errors.New("no packages to scan")
+	}
+	if len(opts.ForceIncludeDirs) > 0 || opts.ExtraBuildTags != "" {
+		return nil, errors.New("ForceIncludeDirs and ExtraBuildTags load packages, which RunOnPackages doesn't")
+	}
+	if err := checkLoadMode(pkgs); err != nil {
+		return nil, err
+	}
+	return run(context.Background(), opts, pkgs, nil)
+}
+
+// checkLoadMode fails when the packages, or their dependencies, lack some of the information loaded with
+// PackagesLoadMode, naming the load mode bits missing.
+func checkLoadMode(pkgs []*packages.Package) error {
+	var missing packages.LoadMode
+	var example string
+	check := func(pkg *packages.Package, bit packages.LoadMode, lacks bool) {
+		if !lacks || missing&bit != 0 {
+			return
+		}
+		missing |= bit
+		if example == "" {
+			example = pkg.ID
+		}
+	}
+
+	for _, pkg := range pkgs {
+		check(pkg, packages.NeedName, pkg.PkgPath == "")
+		check(pkg, packages.NeedFiles, len(pkg.GoFiles) == 0 && len(pkg.Syntax) > 0)
+		check(pkg, packages.NeedSyntax, len(pkg.Syntax) == 0 && len(pkg.GoFiles) > 0)
+		check(pkg, packages.NeedTypes, pkg.Types == nil)
+		check(pkg, packages.NeedTypesInfo, pkg.TypesInfo == nil)
+		check(pkg, packages.NeedImports, pkg.Imports == nil && pkg.Types != nil && len(pkg.Types.Imports()) > 0)
+	}
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		for _, imported := range pkg.Imports {
+			check(imported, packages.NeedDeps, imported.Types == nil || imported.TypesInfo == nil)
+		}
+	})
+
+	if missing == 0 {
+		return nil
+	}
+	return fmt.Errorf("the packages are loaded without the mode bits %v, e.g. %s: load them with at least codescan.PackagesLoadMode",
+		missing, example)
+}
@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/packages"
+)
+
+func TestRunOnPackages(t *testing.T) {
+	const pkg = "github.com/3idey/codescan/fixtures/goparsing/petstore/..."
+
+	load := func(t *testing.T, mode packages.LoadMode, tests bool) []*packages.Package {
+		t.Helper()
+		pkgs, err := packages.Load(&packages.Config{Mode: mode, Tests: tests}, pkg)
+		require.NoError(t, err)
+		require.NotEmpty(t, pkgs)
+		return pkgs
+	}
+
+	t.Run("should build the spec of the loaded packages", func(t *testing.T) {
+		expected, err := Run(&Options{Packages: []string{pkg}, ScanModels: true})
+		require.NoError(t, err)
+
+		var stats Stats
+		doc, err := RunOnPackages(load(t, PackagesLoadMode|packages.NeedModule, false), &Options{ScanModels: true, Stats: &stats})
+		require.NoError(t, err)
+
+		expectedJSON, err := MarshalJSON(expected, false)
+		require.NoError(t, err)
+		actualJSON, err := MarshalJSON(doc, false)
+		require.NoError(t, err)
+		assert.JSONEq(t, string(expectedJSON), string(actualJSON))
+		assert.Positive(t, stats.Packages)
+	})
+
+	t.Run("should build the spec of the test variants with the test scope", func(t *testing.T) {
+		doc, err := RunOnPackages(load(t, PackagesLoadMode, true), &Options{IncludeTestScope: true})
+		require.NoError(t, err)
+		assert.NotEmpty(t, doc.Paths.Paths)
+	})
+
+	t.Run("should fail on packages loaded without the required mode bits", func(t *testing.T) {
+		_, err := RunOnPackages(load(t, packages.NeedName|packages.NeedFiles|packages.NeedImports, false), NewOptions())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "NeedTypes")
+		assert.Contains(t, err.Error(), "NeedSyntax")
+		assert.Contains(t, err.Error(), "NeedTypesInfo")
+		assert.Contains(t, err.Error(), "NeedDeps")
+		assert.Contains(t, err.Error(), "load them with at least codescan.PackagesLoadMode")
+		assert.NotContains(t, err.Error(), "NeedName")
+	})
+
+	t.Run("should fail on the dependencies loaded without types", func(t *testing.T) {
+		pkgs := load(t, PackagesLoadMode, false)
+		var strfmt *packages.Package
+		for _, pkg := range pkgs {
+			if imported, found := pkg.Imports["github.com/go-openapi/strfmt"]; found {
+				strfmt = imported
+			}
+		}
+		require.NotNil(t, strfmt)
+		strfmt.Types, strfmt.TypesInfo = nil, nil
+		_, err := RunOnPackages(pkgs, NewOptions())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "without the mode bits NeedDeps, e.g. github.com/go-openapi/strfmt")
+	})
+
+	t.Run("should fail without packages", func(t *testing.T) {
+		_, err := RunOnPackages(nil, NewOptions())
+		require.EqualError(t, err, "no packages to scan")
+	})
+
+	t.Run("should fail with the options loading packages", func(t *testing.T) {
+		_, err := RunOnPackages(load(t, PackagesLoadMode, false), &Options{ExtraBuildTags: "docs"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "RunOnPackages")
+	})
+}
@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+// Phase is a step of a scan, reported to Options.OnProgress.
+type Phase string
+
+const (
+	// PhasePackages reports the classification of the loaded packages, one at a time.
+	PhasePackages Phase = "packages"
+	// PhaseDefinitions reports the definitions built. The total is unknown, since dependencies
+	// are discovered while building.
+	PhaseDefinitions Phase = "definitions"
+	// PhaseOperations reports the routes and operations assembled into paths.
+	PhaseOperations Phase = "operations"
+)
+
+type progressUpdate struct {
+	phase       Phase
+	done, total int
+}
+
+// progressReporter calls a progress callback from its own goroutine, so that a slow callback never blocks the scan.
+// Updates which are not consumed yet are coalesced: the callback only sees the latest one.
+//
+// A nil reporter ignores updates.
+type progressReporter struct {
+	updates chan progressUpdate
+	stopped chan struct{}
+}
+
+func newProgressReporter(notify func(phase Phase, done, total int)) *progressReporter {
+	if notify == nil {
+		return nil
+	}
+
+	p := &progressReporter{
+		updates: make(chan progressUpdate, 1),
+		stopped: make(chan struct{}),
+	}
+	go func() {
+		defer close(p.stopped)
+		for update := range p.updates {
+			notify(update.phase, update.done, update.total)
+		}
+	}()
+
+	return p
+}
+
+// report sends an update without blocking, replacing the pending update if any.
+func (p *progressReporter) report(phase Phase, done, total int) {
+	if p == nil {
+		return
+	}
+
+	update := progressUpdate{phase: phase, done: done, total: total}
+	for {
+		select {
+		case p.updates <- update:
+			return
+		default:
+		}
+		select {
+		case <-p.updates: // drop the stale update
+		default:
+		}
+	}
+}
+
+// close waits until the last update has been delivered, blocking for the call in progress and the pending
+// update, if any: the callback is never called once the scan returns.
+func (p *progressReporter) close() {
+	if p == nil {
+		return
+	}
+	close(p.updates)
+	<-p.stopped
+}
@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type progressRecorder struct {
+	mu      sync.Mutex
+	updates []progressUpdate
+}
+
+func (r *progressRecorder) notify(phase Phase, done, total int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.updates = append(r.updates, progressUpdate{phase: phase, done: done, total: total})
+}
+
+func TestProgressReporter(t *testing.T) {
+	t.Run("should ignore updates when nil", func(t *testing.T) {
+		var p *progressReporter
+		p.report(PhasePackages, 1, 1)
+		p.close()
+		assert.Nil(t, newProgressReporter(nil))
+	})
+
+	t.Run("should coalesce updates for a slow consumer", func(t *testing.T) {
+		release := make(chan struct{})
+		var rec progressRecorder
+		p := newProgressReporter(func(phase Phase, done, total int) {
+			<-release
+			rec.notify(phase, done, total)
+		})
+
+		reported := make(chan struct{})
+		go func() {
+			defer close(reported)
+			for i := 1; i <= 1000; i++ {
+				p.report(PhaseDefinitions, i, 0)
+			}
+		}()
+		select {
+		case <-reported:
+		case <-time.After(10 * time.Second):
+			t.Fatal("reporting progress should not block")
+		}
+
+		close(release)
+		p.close()
+		require.NotEmpty(t, rec.updates)
+		assert.Less(t, len(rec.updates), 1000)
+		assert.Equal(t, progressUpdate{phase: PhaseDefinitions, done: 1000}, rec.updates[len(rec.updates)-1])
+	})
+
+	t.Run("should deliver the last update of a slow consumer before closing", func(t *testing.T) {
+		var rec progressRecorder
+		var closed atomic.Bool
+		p := newProgressReporter(func(phase Phase, done, total int) {
+			time.Sleep(20 * time.Millisecond)
+			assert.False(t, closed.Load(), "the callback should not be called once closed")
+			rec.notify(phase, done, total)
+		})
+
+		p.report(PhaseOperations, 1, 2)
+		p.report(PhaseOperations, 2, 2)
+		p.close()
+		closed.Store(true)
+
+		require.NotEmpty(t, rec.updates)
+		assert.Equal(t, progressUpdate{phase: PhaseOperations, done: 2, total: 2}, rec.updates[len(rec.updates)-1])
+	})
+}
+
+func TestRun_OnProgress(t *testing.T) {
+	var rec progressRecorder
+	_, err := Run(&Options{
+		Packages:   []string{"github.com/3idey/codescan/fixtures/goparsing/testscope"},
+		ScanModels: true,
+		OnProgress: rec.notify,
+	})
+	require.NoError(t, err)
+
+	require.NotEmpty(t, rec.updates)
+	assert.Equal(t, progressUpdate{phase: PhaseOperations, done: 1, total: 1}, rec.updates[len(rec.updates)-1])
+	for _, update := range rec.updates {
+		if update.phase == PhasePackages {
+			assert.LessOrEqual(t, update.done, update.total)
+		}
+	}
+}
+
+func TestRun_SlowOnProgress(t *testing.T) {
+	var rec progressRecorder
+	var returned atomic.Bool
+	_, err := Run(&Options{
+		Packages:   []string{"github.com/3idey/codescan/fixtures/goparsing/testscope"},
+		ScanModels: true,
+		OnProgress: func(phase Phase, done, total int) {
+			time.Sleep(50 * time.Millisecond)
+			assert.False(t, returned.Load(), "OnProgress should not be called once Run returns")
+			rec.notify(phase, done, total)
+		},
+	})
+	returned.Store(true)
+	require.NoError(t, err)
+
+	require.NotEmpty(t, rec.updates)
+	assert.Equal(t, progressUpdate{phase: PhaseOperations, done: 1, total: 1}, rec.updates[len(rec.updates)-1])
+}
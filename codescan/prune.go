@@ -0,0 +1,348 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// PruneOptions selects the operations Prune keeps. An operation is dropped by any of the exclusions, and
+// otherwise needs to match each kind of inclusion given: one of the IncludeTags, or all of them with
+// RequireAllIncludeTags, one of the IncludePaths and one of the IncludeExtensions.
+type PruneOptions struct {
+	// IncludeTags keeps the operations with one of these tags, like Options.IncludeTags.
+	IncludeTags []string
+	// ExcludeTags drops the operations with one of these tags, whatever their other tags.
+	ExcludeTags []string
+	// RequireAllIncludeTags keeps the operations with all the IncludeTags, rather than one of them.
+	RequireAllIncludeTags bool
+	// IncludePaths keeps the operations of the paths matching one of these patterns, where * matches a
+	// segment of the path and ** any number of them, e.g. /v1/** or /users/*/orders.
+	IncludePaths []string
+	// ExcludePaths drops the operations of the paths matching one of these patterns.
+	ExcludePaths []string
+	// IncludeExtensions keeps the operations with one of these extensions, written x-name to require it, or
+	// x-name=value to require its value, or one of its values for a list, e.g. x-audience=public.
+	IncludeExtensions []string
+	// ExcludeExtensions drops the operations with one of these extensions.
+	ExcludeExtensions []string
+	// KeepUnreferenced keeps the definitions, parameters, responses, security definitions and tags which no
+	// operation uses, e.g. the models of Options.ScanModels, only removing what the dropped operations used.
+	KeepUnreferenced bool
+}
+
+// PruneReport lists what Prune removed from a spec, e.g. "GET /v1/pets" for an operation.
+type PruneReport struct {
+	Operations          []string `json:"operations,omitempty"`
+	Definitions         []string `json:"definitions,omitempty"`
+	Parameters          []string `json:"parameters,omitempty"`
+	Responses           []string `json:"responses,omitempty"`
+	SecurityDefinitions []string `json:"securityDefinitions,omitempty"`
+	Tags                []string `json:"tags,omitempty"`
+}
+
+// Prune removes from a spec the operations the options don't keep, then what the kept operations don't
+// reach through their refs, security requirements and tags: the definitions, parameters, responses, security
+// definitions and tags. The subtypes of a polymorphic definition which is kept are kept with it. The
+// operations are selected like those of the scan, with the tag rules of Options.IncludeTags, and pruned by
+// the same code as Options.Audience.
+func Prune(doc *spec.Swagger, opts PruneOptions) (*PruneReport, error) {
+	keep, err := opts.operationFilter()
+	if err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		return new(PruneReport), nil
+	}
+	return pruneOperations(doc, keep, !opts.KeepUnreferenced)
+}
+
+// operationFilter compiles the options to the function selecting the operations.
+func (o PruneOptions) operationFilter() (func(pth, method string, op *spec.Operation) (bool, error), error) {
+	var errs []error
+	for _, pattern := range slices.Concat(o.IncludePaths, o.ExcludePaths) {
+		if err := checkPathPattern(pattern); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	includeExtensions, err := parseExtensionPredicates(o.IncludeExtensions)
+	errs = append(errs, err)
+	excludeExtensions, err := parseExtensionPredicates(o.ExcludeExtensions)
+	errs = append(errs, err)
+	if o.RequireAllIncludeTags && len(o.IncludeTags) == 0 {
+		errs = append(errs, errors.New("all the included tags are only required with IncludeTags"))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	includeTags, excludeTags := make(map[string]bool), make(map[string]bool)
+	for _, tag := range o.IncludeTags {
+		includeTags[tag] = true
+	}
+	for _, tag := range o.ExcludeTags {
+		excludeTags[tag] = true
+	}
+	matchesPath := func(patterns []string, pth string) bool {
+		return slices.ContainsFunc(patterns, func(pattern string) bool { return matchPathPattern(pattern, pth) })
+	}
+
+	return func(pth, _ string, op *spec.Operation) (bool, error) {
+		if accepted, _ := shouldAcceptTag(op.Tags, includeTags, excludeTags, o.RequireAllIncludeTags); !accepted {
+			return false, nil
+		}
+		if matchesPath(o.ExcludePaths, pth) || (len(o.IncludePaths) > 0 && !matchesPath(o.IncludePaths, pth)) {
+			return false, nil
+		}
+		if excludeExtensions.match(op) || (len(includeExtensions) > 0 && !includeExtensions.match(op)) {
+			return false, nil
+		}
+		return true, nil
+	}, nil
+}
+
+// pruneOperations removes the operations keep rejects, then what only they used, unless the kept ones use it
+// too: definitions, parameters and responses, security definitions and tags. With unreferenced, what no kept
+// operation uses is removed as well, e.g. the models of Options.ScanModels.
+func pruneOperations(doc *spec.Swagger, keep func(pth, method string, op *spec.Operation) (bool, error), unreferenced bool) (*PruneReport, error) {
+	report := new(PruneReport)
+	var kept, dropped operationUses
+	if doc.Paths != nil {
+		for _, pth := range sortedKeys(doc.Paths.Paths) {
+			pathItem := doc.Paths.Paths[pth]
+			hasOperations := false
+			for method, op := range pathItemOperations(&pathItem) {
+				accepted, err := keep(pth, method, op)
+				if err != nil {
+					return nil, err
+				}
+				if accepted {
+					kept.add(op)
+					hasOperations = true
+					continue
+				}
+				dropped.add(op)
+				report.Operations = append(report.Operations, strings.ToUpper(method)+" "+pth)
+				removeOperation(&pathItem, method)
+			}
+
+			if !hasOperations {
+				dropped.refs = append(dropped.refs, paramsRefs(pathItem.Parameters)...)
+				delete(doc.Paths.Paths, pth)
+				continue
+			}
+			kept.refs = append(kept.refs, paramsRefs(pathItem.Parameters)...)
+			doc.Paths.Paths[pth] = pathItem
+		}
+	}
+
+	for _, requirement := range doc.Security {
+		for name := range requirement {
+			kept.security = append(kept.security, name)
+		}
+	}
+	pruneDropped(doc, &kept, &dropped, unreferenced, report)
+	return report, nil
+}
+
+// pruneDropped removes what the dropped operations used, unless the kept ones use it too: definitions, parameters
+// and responses, security definitions and tags. What no operation uses is removed with unreferenced, and kept
+// otherwise.
+func pruneDropped(doc *spec.Swagger, kept, dropped *operationUses, unreferenced bool, report *PruneReport) {
+	used := reachableRefs(doc, kept.refs)
+	candidates := refsClosure(doc, dropped.refs)
+	droppedSecurity, droppedTags := dropped.security, dropped.tags
+	if unreferenced {
+		for name := range doc.Definitions {
+			candidates[definitionsPrefix+escapePointer(name)] = true
+		}
+		for name := range doc.Parameters {
+			candidates[parametersPrefix+escapePointer(name)] = true
+		}
+		for name := range doc.Responses {
+			candidates[responsesPrefix+escapePointer(name)] = true
+		}
+		droppedSecurity = slices.Collect(maps.Keys(doc.SecurityDefinitions))
+		droppedTags = nil
+		for _, tag := range doc.Tags {
+			droppedTags = append(droppedTags, tag.Name)
+		}
+	}
+
+	for _, ref := range sortedKeys(candidates) {
+		if used[ref] {
+			continue
+		}
+		if name, ok := strings.CutPrefix(ref, definitionsPrefix); ok {
+			name = unescapePointer(name)
+			if _, known := doc.Definitions[name]; known {
+				delete(doc.Definitions, name)
+				report.Definitions = append(report.Definitions, name)
+			}
+		} else if name, ok := strings.CutPrefix(ref, parametersPrefix); ok {
+			name = unescapePointer(name)
+			if _, known := doc.Parameters[name]; known {
+				delete(doc.Parameters, name)
+				report.Parameters = append(report.Parameters, name)
+			}
+		} else if name, ok := strings.CutPrefix(ref, responsesPrefix); ok {
+			name = unescapePointer(name)
+			if _, known := doc.Responses[name]; known {
+				delete(doc.Responses, name)
+				report.Responses = append(report.Responses, name)
+			}
+		}
+	}
+
+	for _, name := range slices.Sorted(slices.Values(droppedSecurity)) {
+		if _, known := doc.SecurityDefinitions[name]; known && !slices.Contains(kept.security, name) {
+			delete(doc.SecurityDefinitions, name)
+			report.SecurityDefinitions = append(report.SecurityDefinitions, name)
+		}
+	}
+
+	doc.Tags = slices.DeleteFunc(doc.Tags, func(tag spec.Tag) bool {
+		if slices.Contains(droppedTags, tag.Name) && !slices.Contains(kept.tags, tag.Name) {
+			report.Tags = append(report.Tags, tag.Name)
+			return true
+		}
+		return false
+	})
+	slices.Sort(report.Tags)
+}
+
+// reachableRefs follows the local refs of a spec from the given ones, like refsClosure, with the subtypes of
+// the polymorphic definitions reached, which refer to their base in their allOf rather than the other way
+// around.
+func reachableRefs(doc *spec.Swagger, refs []string) map[string]bool {
+	reached := refsClosure(doc, refs)
+	for {
+		var subtypes []string
+		for _, name := range sortedKeys(doc.Definitions) {
+			if reached[definitionsPrefix+escapePointer(name)] {
+				continue
+			}
+			for _, member := range doc.Definitions[name].AllOf {
+				base, ok := definitionName(member.Ref)
+				if ok && reached[definitionsPrefix+escapePointer(base)] && doc.Definitions[base].Discriminator != "" {
+					subtypes = append(subtypes, definitionsPrefix+escapePointer(name))
+					break
+				}
+			}
+		}
+		if len(subtypes) == 0 {
+			return reached
+		}
+		maps.Copy(reached, refsClosure(doc, subtypes))
+	}
+}
+
+func removeOperation(pathItem *spec.PathItem, method string) {
+	switch method {
+	case "get":
+		pathItem.Get = nil
+	case "put":
+		pathItem.Put = nil
+	case "post":
+		pathItem.Post = nil
+	case "delete":
+		pathItem.Delete = nil
+	case "options":
+		pathItem.Options = nil
+	case "head":
+		pathItem.Head = nil
+	case "patch":
+		pathItem.Patch = nil
+	}
+}
+
+// matchPathPattern matches a path of a spec against a pattern, whose segments match those of the path like
+// path.Match, except ** which matches any number of them: /v1/** matches /v1 and the paths below it.
+func matchPathPattern(pattern, pth string) bool {
+	return matchPathSegments(strings.Split(strings.Trim(pattern, "/"), "/"), strings.Split(strings.Trim(pth, "/"), "/"))
+}
+
+func matchPathSegments(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(segments); i++ {
+				if matchPathSegments(pattern[1:], segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segments) == 0 {
+			return false
+		}
+		if matched, _ := path.Match(pattern[0], segments[0]); !matched {
+			return false
+		}
+		pattern, segments = pattern[1:], segments[1:]
+	}
+	return len(segments) == 0
+}
+
+func checkPathPattern(pattern string) error {
+	if !strings.HasPrefix(pattern, "/") {
+		return fmt.Errorf("invalid path pattern %q: the patterns of the paths start with /", pattern)
+	}
+	for segment := range strings.SplitSeq(strings.Trim(pattern, "/"), "/") {
+		if _, err := path.Match(segment, ""); err != nil {
+			return fmt.Errorf("invalid path pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// extensionPredicate matches the operations with an extension, or with a value of it.
+type extensionPredicate struct {
+	name     string
+	value    string
+	hasValue bool
+}
+
+type extensionPredicates []extensionPredicate
+
+func parseExtensionPredicates(values []string) (extensionPredicates, error) {
+	predicates := make(extensionPredicates, 0, len(values))
+	var errs []error
+	for _, value := range values {
+		name, expected, hasValue := strings.Cut(value, "=")
+		name = strings.TrimSpace(name)
+		if !rxAllowedExtensions.MatchString(name) {
+			errs = append(errs, fmt.Errorf("invalid extension %q, expected x-name or x-name=value", value))
+			continue
+		}
+		predicates = append(predicates, extensionPredicate{name: strings.ToLower(name), value: expected, hasValue: hasValue})
+	}
+	return predicates, errors.Join(errs...)
+}
+
+func (p extensionPredicates) match(op *spec.Operation) bool {
+	return slices.ContainsFunc(p, func(predicate extensionPredicate) bool {
+		value, found := op.Extensions[predicate.name]
+		if !found {
+			return false
+		}
+		if !predicate.hasValue {
+			return value != false
+		}
+		switch values := value.(type) {
+		case []any:
+			return slices.ContainsFunc(values, func(item any) bool { return fmt.Sprint(item) == predicate.value })
+		case []string:
+			return slices.Contains(values, predicate.value)
+		default:
+			return fmt.Sprint(value) == predicate.value
+		}
+	})
+}
@@ -0,0 +1,152 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func pruneFixture() *spec.Swagger {
+	operation := func(id string, tags []string, extensions map[string]any, ref string) *spec.Operation {
+		op := spec.NewOperation(id)
+		op.Tags = tags
+		for name, value := range extensions {
+			op.AddExtension(name, value)
+		}
+		op.SecuredWith("api_key")
+		return op.RespondsWith(200, spec.NewResponse().WithSchema(spec.RefSchema(ref)))
+	}
+
+	dog := spec.Schema{}
+	dog.AllOf = []spec.Schema{*spec.RefSchema("#/definitions/Pet")}
+	pet := *new(spec.Schema).SetProperty("owner", *spec.RefSchema("#/definitions/Owner"))
+	pet.Discriminator = "kind"
+
+	return &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+		Definitions: spec.Definitions{
+			"Pet":    pet,
+			"Dog":    dog,
+			"Owner":  *spec.StringProperty(),
+			"Order":  *spec.StringProperty(),
+			"Unused": *spec.StringProperty(),
+		},
+		Parameters: map[string]spec.Parameter{"limit": *spec.QueryParam("limit")},
+		Responses:  map[string]spec.Response{"error": *spec.NewResponse().WithDescription("an error")},
+		SecurityDefinitions: spec.SecurityDefinitions{
+			"api_key": spec.APIKeyAuth("X-API-Key", "header"),
+			"oauth2":  spec.OAuth2Implicit("https://example.com/authorize"),
+		},
+		Tags: []spec.Tag{spec.NewTag("pets", "", nil), spec.NewTag("orders", "", nil), spec.NewTag("admin", "", nil)},
+		Paths: &spec.Paths{Paths: map[string]spec.PathItem{
+			"/v1/pets": {PathItemProps: spec.PathItemProps{
+				Get: operation("listPets", []string{"pets"}, map[string]any{"x-audience": []any{"public", "partner"}}, "#/definitions/Pet"),
+			}},
+			"/v1/orders/{id}": {PathItemProps: spec.PathItemProps{
+				Get: operation("getOrder", []string{"orders"}, map[string]any{"x-internal": true}, "#/definitions/Order"),
+			}},
+			"/v2/pets": {PathItemProps: spec.PathItemProps{
+				Get: operation("listPetsV2", []string{"pets", "admin"}, nil, "#/definitions/Pet"),
+			}},
+		}},
+	}}
+}
+
+func TestPrune(t *testing.T) {
+	t.Run("should keep the operations by tag and path, with what they reach", func(t *testing.T) {
+		doc := pruneFixture()
+		report, err := Prune(doc, PruneOptions{IncludeTags: []string{"pets"}, IncludePaths: []string{"/v1/**"}})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"/v1/pets"}, sortedKeys(doc.Paths.Paths))
+		assert.Equal(t, []string{"Dog", "Owner", "Pet"}, sortedKeys(doc.Definitions), "Dog is a subtype of Pet")
+		assert.Empty(t, doc.Parameters)
+		assert.Empty(t, doc.Responses)
+		assert.Equal(t, []string{"api_key"}, sortedKeys(doc.SecurityDefinitions))
+		require.Len(t, doc.Tags, 1)
+		assert.Equal(t, "pets", doc.Tags[0].Name)
+
+		assert.Equal(t, &PruneReport{
+			Operations:          []string{"GET /v1/orders/{id}", "GET /v2/pets"},
+			Definitions:         []string{"Order", "Unused"},
+			Parameters:          []string{"limit"},
+			Responses:           []string{"error"},
+			SecurityDefinitions: []string{"oauth2"},
+			Tags:                []string{"admin", "orders"},
+		}, report)
+	})
+
+	t.Run("should drop the excluded operations, whatever the inclusions", func(t *testing.T) {
+		doc := pruneFixture()
+		report, err := Prune(doc, PruneOptions{ExcludeTags: []string{"admin"}, ExcludeExtensions: []string{"x-internal"}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"/v1/pets"}, sortedKeys(doc.Paths.Paths))
+		assert.Equal(t, []string{"GET /v1/orders/{id}", "GET /v2/pets"}, report.Operations)
+	})
+
+	t.Run("should match the values of the extensions", func(t *testing.T) {
+		doc := pruneFixture()
+		_, err := Prune(doc, PruneOptions{IncludeExtensions: []string{"x-audience=partner"}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"/v1/pets"}, sortedKeys(doc.Paths.Paths))
+
+		doc = pruneFixture()
+		_, err = Prune(doc, PruneOptions{IncludeExtensions: []string{"x-internal=true"}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"/v1/orders/{id}"}, sortedKeys(doc.Paths.Paths))
+	})
+
+	t.Run("should keep what no operation uses with KeepUnreferenced", func(t *testing.T) {
+		doc := pruneFixture()
+		report, err := Prune(doc, PruneOptions{ExcludePaths: []string{"/v1/orders/*"}, KeepUnreferenced: true})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Dog", "Owner", "Pet", "Unused"}, sortedKeys(doc.Definitions))
+		assert.Equal(t, []string{"Order"}, report.Definitions)
+		assert.Contains(t, doc.Parameters, "limit")
+		assert.Contains(t, doc.SecurityDefinitions, "oauth2")
+	})
+
+	t.Run("should keep everything reachable without filters", func(t *testing.T) {
+		doc := pruneFixture()
+		report, err := Prune(doc, PruneOptions{})
+		require.NoError(t, err)
+		assert.Len(t, doc.Paths.Paths, 3)
+		assert.Empty(t, report.Operations)
+		assert.Equal(t, []string{"Unused"}, report.Definitions)
+	})
+
+	t.Run("should reject the invalid filters", func(t *testing.T) {
+		_, err := Prune(pruneFixture(), PruneOptions{
+			IncludePaths:          []string{"v1/**", "/v1/[a"},
+			ExcludeExtensions:     []string{"internal"},
+			RequireAllIncludeTags: true,
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `invalid path pattern "v1/**"`)
+		assert.Contains(t, err.Error(), `invalid path pattern "/v1/[a"`)
+		assert.Contains(t, err.Error(), `invalid extension "internal"`)
+		assert.Contains(t, err.Error(), "IncludeTags")
+	})
+}
+
+func TestMatchPathPattern(t *testing.T) {
+	for _, tc := range []struct {
+		pattern, path string
+		matches       bool
+	}{
+		{"/v1/**", "/v1", true},
+		{"/v1/**", "/v1/pets/{id}", true},
+		{"/v1/**", "/v2/pets", false},
+		{"/users/*/orders", "/users/{id}/orders", true},
+		{"/users/*/orders", "/users/{id}/orders/{order}", false},
+		{"/**/orders", "/users/{id}/orders", true},
+		{"/pets", "/pets/", true},
+		{"/**", "/", true},
+	} {
+		assert.Equal(t, tc.matches, matchPathPattern(tc.pattern, tc.path), "%s against %s", tc.pattern, tc.path)
+	}
+}
@@ -0,0 +1,185 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"fmt"
+	"log"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	xRateLimit       = "x-rate-limit"
+	defaultRateLimit = "default"
+)
+
+var rateLimitPeriods = map[string]string{
+	"s":      "second",
+	"sec":    "second",
+	"second": "second",
+	"m":      "minute",
+	"min":    "minute",
+	"minute": "minute",
+	"h":      "hour",
+	"hour":   "hour",
+	"d":      "day",
+	"day":    "day",
+}
+
+// rateLimit is a number of requests allowed per period, e.g. "100/min".
+type rateLimit struct {
+	limit  int
+	period string
+}
+
+func parseRateLimit(value string) (rateLimit, error) {
+	limit, period, ok := strings.Cut(strings.TrimSpace(value), "/")
+	if !ok {
+		return rateLimit{}, fmt.Errorf("invalid rate limit %q, expected <limit>/<period>", value)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(limit))
+	if err != nil || n <= 0 {
+		return rateLimit{}, fmt.Errorf("invalid rate limit %q, the limit must be a positive integer", value)
+	}
+	normalized, known := rateLimitPeriods[strings.ToLower(strings.TrimSpace(period))]
+	if !known {
+		return rateLimit{}, fmt.Errorf("invalid rate limit %q, unknown period %q", value, period)
+	}
+
+	return rateLimit{limit: n, period: normalized}, nil
+}
+
+func (r rateLimit) extension() map[string]any {
+	return map[string]any{
+		"limit":  r.limit,
+		"period": r.period,
+	}
+}
+
+func (r rateLimit) sentence() string {
+	requests := "requests"
+	if r.limit == 1 {
+		requests = "request"
+	}
+	return fmt.Sprintf("Rate limit: %d %s per %s.", r.limit, requests, r.period)
+}
+
+// rateLimitRule is an entry of the rate limit table, other than the default one.
+type rateLimitRule struct {
+	pattern string
+	method  string
+	path    string
+	exact   bool
+	limit   rateLimit
+}
+
+func parseRateLimitRule(pattern, value string) (rateLimitRule, error) {
+	method, pth, ok := strings.Cut(strings.TrimSpace(pattern), " ")
+	pth = strings.TrimSpace(pth)
+	if !ok || method == "" || !strings.HasPrefix(pth, "/") {
+		return rateLimitRule{}, fmt.Errorf("invalid rate limit pattern %q, expected \"METHOD /path\"", pattern)
+	}
+	method = strings.ToUpper(method)
+	for _, glob := range []string{method, strings.TrimSuffix(pth, "/...")} {
+		if _, err := path.Match(glob, ""); err != nil {
+			return rateLimitRule{}, fmt.Errorf("invalid rate limit pattern %q: %w", pattern, err)
+		}
+	}
+	limit, err := parseRateLimit(value)
+	if err != nil {
+		return rateLimitRule{}, fmt.Errorf("rate limit for %q: %w", pattern, err)
+	}
+
+	return rateLimitRule{
+		pattern: pattern,
+		method:  method,
+		path:    pth,
+		exact:   !strings.ContainsAny(method+pth, "*?[\\") && !strings.HasSuffix(pth, "/..."),
+		limit:   limit,
+	}, nil
+}
+
+func (r rateLimitRule) matches(method, pth string) bool {
+	if matched, _ := path.Match(r.method, strings.ToUpper(method)); !matched {
+		return false
+	}
+	return matchPackageGlob(r.path, pth)
+}
+
+// bestRateLimitRule returns the rule matching an operation: exact patterns win over globs,
+// then the longest glob wins.
+func bestRateLimitRule(rules []rateLimitRule, method, pth string) (rateLimitRule, bool) {
+	var (
+		best  rateLimitRule
+		found bool
+	)
+	for _, rule := range rules {
+		if !rule.matches(method, pth) {
+			continue
+		}
+		if !found || (rule.exact && !best.exact) || (rule.exact == best.exact && len(rule.pattern) > len(best.pattern)) {
+			best = rule
+			found = true
+		}
+	}
+	return best, found
+}
+
+// applyRateLimits documents the rate limits of Options.RateLimits with x-rate-limit extensions
+// on the spec root and on the matching operations. Explicit x-rate-limit extensions are kept.
+func (s *specBuilder) applyRateLimits() error {
+	table := s.ctx.opts.RateLimits
+	if len(table) == 0 {
+		return nil
+	}
+
+	var rules []rateLimitRule
+	for _, pattern := range sortedKeys(table) {
+		if pattern == defaultRateLimit {
+			limit, err := parseRateLimit(table[pattern])
+			if err != nil {
+				return fmt.Errorf("default rate limit: %w", err)
+			}
+			if _, explicit := s.input.Extensions[xRateLimit]; !explicit {
+				s.input.AddExtension(xRateLimit, limit.extension())
+			}
+			continue
+		}
+		rule, err := parseRateLimitRule(pattern, table[pattern])
+		if err != nil {
+			return err
+		}
+		rules = append(rules, rule)
+	}
+
+	matched := make(map[string]bool, len(rules))
+	for _, pth := range sortedKeys(s.input.Paths.Paths) {
+		pathItem := s.input.Paths.Paths[pth]
+		for method, op := range pathItemOperations(&pathItem) {
+			rule, ok := bestRateLimitRule(rules, method, pth)
+			if !ok {
+				continue
+			}
+			matched[rule.pattern] = true
+			if _, explicit := op.Extensions[xRateLimit]; explicit {
+				continue
+			}
+			op.AddExtension(xRateLimit, rule.limit.extension())
+			if op.Description != "" {
+				op.Description += "\n\n"
+			}
+			op.Description += rule.limit.sentence()
+		}
+	}
+
+	for _, rule := range rules {
+		if !matched[rule.pattern] {
+			log.Printf("WARNING: rate limit pattern %q does not match any operation", rule.pattern)
+		}
+	}
+
+	return nil
+}
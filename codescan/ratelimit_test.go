@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	limit, err := parseRateLimit("100/min")
+	require.NoError(t, err)
+	assert.Equal(t, rateLimit{limit: 100, period: "minute"}, limit)
+	assert.Equal(t, "Rate limit: 100 requests per minute.", limit.sentence())
+
+	limit, err = parseRateLimit(" 1 / Hour ")
+	require.NoError(t, err)
+	assert.Equal(t, "Rate limit: 1 request per hour.", limit.sentence())
+
+	for _, invalid := range []string{"100", "0/min", "ten/min", "10/fortnight"} {
+		_, err := parseRateLimit(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func rateLimitedSpec() *spec.Swagger {
+	op := func(description string) *spec.Operation {
+		o := new(spec.Operation)
+		o.Description = description
+		return o
+	}
+
+	explicit := op("")
+	explicit.AddExtension(xRateLimit, map[string]any{"limit": 1, "period": "second"})
+
+	doc := new(spec.Swagger)
+	doc.Paths = &spec.Paths{Paths: map[string]spec.PathItem{
+		"/orders": {PathItemProps: spec.PathItemProps{
+			Get:  op("Lists orders."),
+			Post: op(""),
+		}},
+		"/orders/{id}": {PathItemProps: spec.PathItemProps{
+			Get:    op(""),
+			Delete: explicit,
+		}},
+		"/users": {PathItemProps: spec.PathItemProps{
+			Get: op(""),
+		}},
+	}}
+
+	return doc
+}
+
+func TestApplyRateLimits(t *testing.T) {
+	t.Run("should document rate limits from the table", func(t *testing.T) {
+		doc := rateLimitedSpec()
+		sb := &specBuilder{input: doc, ctx: &scanCtx{opts: &Options{RateLimits: map[string]string{
+			"default":          "100/min",
+			"POST /orders":     "10/min",
+			"* /orders/...":    "50/min",
+			"GET /orders/{id}": "20/s",
+			"PUT /carts":       "5/min",
+		}}}}
+		require.NoError(t, sb.applyRateLimits())
+
+		assert.Equal(t, map[string]any{"limit": 100, "period": "minute"}, doc.Extensions[xRateLimit])
+
+		orders := doc.Paths.Paths["/orders"]
+		assert.Equal(t, map[string]any{"limit": 10, "period": "minute"}, orders.Post.Extensions[xRateLimit])
+		assert.Equal(t, "Rate limit: 10 requests per minute.", orders.Post.Description)
+		assert.Equal(t, map[string]any{"limit": 50, "period": "minute"}, orders.Get.Extensions[xRateLimit])
+		assert.Equal(t, "Lists orders.\n\nRate limit: 50 requests per minute.", orders.Get.Description)
+
+		order := doc.Paths.Paths["/orders/{id}"]
+		assert.Equal(t, map[string]any{"limit": 20, "period": "second"}, order.Get.Extensions[xRateLimit])
+		assert.Equal(t, map[string]any{"limit": 1, "period": "second"}, order.Delete.Extensions[xRateLimit], "explicit annotations win")
+		assert.Empty(t, order.Delete.Description)
+
+		assert.NotContains(t, doc.Paths.Paths["/users"].Get.Extensions, xRateLimit)
+	})
+
+	t.Run("should keep an explicit root rate limit", func(t *testing.T) {
+		doc := rateLimitedSpec()
+		doc.AddExtension(xRateLimit, "custom")
+		sb := &specBuilder{input: doc, ctx: &scanCtx{opts: &Options{RateLimits: map[string]string{"default": "100/min"}}}}
+		require.NoError(t, sb.applyRateLimits())
+		assert.Equal(t, "custom", doc.Extensions[xRateLimit])
+	})
+
+	t.Run("should refuse invalid entries", func(t *testing.T) {
+		for _, table := range []map[string]string{
+			{"default": "fast"},
+			{"/orders": "10/min"},
+			{"GET orders": "10/min"},
+			{"GET /orders/[": "10/min"},
+			{"GET /orders": "10"},
+		} {
+			sb := &specBuilder{input: rateLimitedSpec(), ctx: &scanCtx{opts: &Options{RateLimits: table}}}
+			assert.Error(t, sb.applyRateLimits(), table)
+		}
+	})
+}
@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"log"
+	"runtime/debug"
+)
+
+// recoverBuild builds a declaration, turning a panic of the builders into a DiagnosticBuilderPanic positioned
+// at the declaration, so that the scan goes on with the other declarations. checkPanics fails the scan once
+// the spec is built. With Options.NoRecover, the panic is left to crash the process, for debugging. Once the
+// scan is canceled, the declarations are no longer built.
+func (s *specBuilder) recoverBuild(pos token.Position, subject string, build func() error) (err error) {
+	if err := s.ctx.canceled(); err != nil {
+		return err
+	}
+	if s.ctx.opts != nil && s.ctx.opts.NoRecover {
+		return build()
+	}
+	defer func() {
+		recovered := recover()
+		if recovered == nil {
+			return
+		}
+		debugLogf("panic while building %s: %v\n%s", subject, recovered, debug.Stack())
+		s.ctx.app.diagnose(Diagnostic{
+			Pos:     pos,
+			Code:    DiagnosticBuilderPanic,
+			Message: fmt.Sprintf("building %s panicked: %v", subject, recovered),
+		})
+		err = nil
+	}()
+	return build()
+}
+
+// checkPanics summarizes the declarations whose building panicked, and fails unless keepGoing, which keeps
+// the spec built without them.
+func (a *typeIndex) checkPanics(keepGoing bool) error {
+	panics := a.diagnosticsWithCode(DiagnosticBuilderPanic)
+	if len(panics) == 0 {
+		return nil
+	}
+
+	summary := fmt.Sprintf("%d declarations failed to build", len(panics))
+	if len(panics) == 1 {
+		summary = "1 declaration failed to build"
+	}
+	if keepGoing {
+		log.Printf("WARNING: %s, the spec leaves them out", summary)
+		return nil
+	}
+
+	errs := []error{errors.New(summary)}
+	for _, diagnostic := range panics {
+		errs = append(errs, diagnostic)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"errors"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecoverBuild(t *testing.T) {
+	pos := token.Position{Filename: "models.go", Line: 12, Column: 6}
+	outOfRange := func() error {
+		var fields []string
+		_ = fields[len(fields)]
+		return nil
+	}
+
+	newBuilder := func(opts *Options) *specBuilder {
+		return &specBuilder{ctx: &scanCtx{app: &typeIndex{}, opts: opts}}
+	}
+
+	t.Run("should turn a panic into a positioned diagnostic", func(t *testing.T) {
+		sb := newBuilder(&Options{})
+		require.NoError(t, sb.recoverBuild(pos, "model example.com/api.Holder", outOfRange))
+
+		require.Len(t, sb.ctx.app.diagnostics, 1)
+		diagnostic := sb.ctx.app.diagnostics[0]
+		assert.Equal(t, DiagnosticBuilderPanic, diagnostic.Code)
+		assert.Equal(t, pos, diagnostic.Pos)
+		assert.Contains(t, diagnostic.Message, "building model example.com/api.Holder panicked: runtime error: index out of range")
+	})
+
+	t.Run("should return the errors of the build", func(t *testing.T) {
+		sb := newBuilder(&Options{})
+		failure := errors.New("invalid annotation")
+		err := sb.recoverBuild(pos, "model example.com/api.Holder", func() error { return failure })
+		require.ErrorIs(t, err, failure)
+		assert.Empty(t, sb.ctx.app.diagnostics)
+	})
+
+	t.Run("should panic with NoRecover", func(t *testing.T) {
+		sb := newBuilder(&Options{NoRecover: true})
+		assert.Panics(t, func() { _ = sb.recoverBuild(pos, "model example.com/api.Holder", outOfRange) })
+	})
+
+	t.Run("should fail with a summary, unless keep going", func(t *testing.T) {
+		sb := newBuilder(&Options{})
+		require.NoError(t, sb.recoverBuild(pos, "model example.com/api.Holder", outOfRange))
+		other := token.Position{Filename: "routes.go", Line: 30, Column: 1}
+		require.NoError(t, sb.recoverBuild(other, "route GET /holders", func() error { panic("unsupported type") }))
+
+		err := sb.ctx.app.checkPanics(false)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "2 declarations failed to build")
+		assert.Contains(t, err.Error(), "routes.go:30:1: building route GET /holders panicked: unsupported type")
+		var diagnostic Diagnostic
+		require.True(t, errors.As(err, &diagnostic))
+		assert.Equal(t, pos, diagnostic.Pos)
+
+		require.NoError(t, sb.ctx.app.checkPanics(true))
+	})
+
+	t.Run("should pass without panics", func(t *testing.T) {
+		require.NoError(t, newBuilder(&Options{}).ctx.app.checkPanics(false))
+	})
+}
@@ -0,0 +1,195 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// QualifyRefs rewrites the local $refs of a spec, e.g. "#/definitions/Pet", into refs qualified by the
+// name of the document, e.g. "swagger.json#/definitions/Pet".
+//
+// Such refs still resolve when the spec is served under a rewritten base path. NormalizeRefs reverses it.
+func QualifyRefs(doc *spec.Swagger, document string) error {
+	if err := checkRefDocument(document); err != nil {
+		return err
+	}
+
+	return rewriteRefs(doc, func(ref string) string {
+		if strings.HasPrefix(ref, "#/") {
+			return document + ref
+		}
+		return ref
+	})
+}
+
+// NormalizeRefs rewrites the $refs qualified by the name of the document, e.g. "swagger.json#/definitions/Pet",
+// into local refs, e.g. "#/definitions/Pet". Refs to other documents are left untouched.
+func NormalizeRefs(doc *spec.Swagger, document string) error {
+	if err := checkRefDocument(document); err != nil {
+		return err
+	}
+
+	return rewriteRefs(doc, func(ref string) string {
+		if local, ok := strings.CutPrefix(ref, document); ok && strings.HasPrefix(local, "#/") {
+			return local
+		}
+		return ref
+	})
+}
+
+func checkRefDocument(document string) error {
+	if document == "" {
+		return errors.New("the document name qualifying refs is empty")
+	}
+	if strings.Contains(document, "#") {
+		return fmt.Errorf("invalid document name qualifying refs %q: it contains a fragment", document)
+	}
+	return nil
+}
+
+// rewriteRefs applies a rewrite to all the $refs of a spec: schemas, parameters and responses.
+func rewriteRefs(doc *spec.Swagger, rewrite func(ref string) string) error {
+	if doc == nil {
+		return nil
+	}
+
+	var err error
+	update := func(ref *spec.Ref) {
+		current := ref.String()
+		if current == "" || err != nil {
+			return
+		}
+		if rewritten := rewrite(current); rewritten != current {
+			var newRef spec.Ref
+			if newRef, err = spec.NewRef(rewritten); err == nil {
+				*ref = newRef
+			}
+		}
+	}
+
+	walkSpecSchemas(doc, func(schema *spec.Schema, _ string) {
+		update(&schema.Ref)
+	})
+
+	updateParams := func(params []spec.Parameter) {
+		for i := range params {
+			update(&params[i].Ref)
+		}
+	}
+	updateResponses := func(responses *spec.Responses) {
+		if responses == nil {
+			return
+		}
+		if responses.Default != nil {
+			update(&responses.Default.Ref)
+		}
+		for code, resp := range responses.StatusCodeResponses {
+			update(&resp.Ref)
+			responses.StatusCodeResponses[code] = resp
+		}
+	}
+
+	if doc.Paths != nil {
+		for pth, pathItem := range doc.Paths.Paths {
+			update(&pathItem.Ref)
+			updateParams(pathItem.Parameters)
+			for _, op := range pathItemOperations(&pathItem) {
+				updateParams(op.Parameters)
+				updateResponses(op.Responses)
+			}
+			doc.Paths.Paths[pth] = pathItem
+		}
+	}
+
+	return err
+}
+
+// reportUnresolvedRefs reports the local $refs of the scanned operations and definitions to a definition, a
+// parameter or a response missing from the spec, at the position of their route or declaration.
+func (s *specBuilder) reportUnresolvedRefs() {
+	doc := s.input
+	report := func(pos token.Position, subject, ref string) {
+		if resolvesLocally(doc, ref) {
+			return
+		}
+		s.ctx.app.diagnoseMessage(Diagnostic{
+			Pos:     pos,
+			Code:    DiagnosticUnresolvedRef,
+			Message: fmt.Sprintf("%s refers to %s, which the spec doesn't define", subject, ref),
+		})
+	}
+
+	for _, name := range sortedKeys(doc.Definitions) {
+		pos, scanned := s.ctx.app.definitionPositions[name]
+		if !scanned {
+			continue // a definition of the input spec
+		}
+		schema := doc.Definitions[name]
+		walkSchema(&schema, definitionsPrefix+escapePointer(name), func(schema *spec.Schema, _ string) {
+			report(pos, "definition "+name, schema.Ref.String())
+		})
+	}
+
+	if doc.Paths == nil {
+		return
+	}
+	// by method and path, rather than by ID, which a route may share with another one
+	scanned := make(map[string]token.Position, len(s.ctx.app.Routes)+len(s.ctx.app.Operations))
+	for _, pp := range slices.Concat(s.ctx.app.Routes, s.ctx.app.Operations) {
+		scanned[strings.ToLower(pp.Method)+" "+pp.Path] = pp.Pos
+	}
+	for _, pth := range sortedKeys(doc.Paths.Paths) {
+		pathItem := doc.Paths.Paths[pth]
+		for method, op := range pathItemOperations(&pathItem) {
+			pos, ok := scanned[method+" "+pth]
+			if !ok {
+				continue
+			}
+			subject := "operation " + op.ID
+			visit := func(schema *spec.Schema, _ string) {
+				report(pos, subject, schema.Ref.String())
+			}
+			for _, param := range op.Parameters {
+				report(pos, subject, param.Ref.String())
+			}
+			walkParams(op.Parameters, "", visit)
+			if op.Responses == nil {
+				continue
+			}
+			responses := make(map[int]spec.Response, len(op.Responses.StatusCodeResponses)+1)
+			maps.Copy(responses, op.Responses.StatusCodeResponses)
+			if op.Responses.Default != nil {
+				responses[0] = *op.Responses.Default
+			}
+			for _, code := range sortedKeys(responses) {
+				resp := responses[code]
+				report(pos, subject, resp.Ref.String())
+				walkResponse(&resp, "", visit)
+			}
+		}
+	}
+}
+
+// resolvesLocally tells whether a $ref resolves in the spec. The $refs to other documents are not checked.
+func resolvesLocally(doc *spec.Swagger, ref string) bool {
+	var found bool
+	if name, ok := strings.CutPrefix(ref, definitionsPrefix); ok {
+		_, found = doc.Definitions[unescapePointer(name)]
+	} else if name, ok := strings.CutPrefix(ref, parametersPrefix); ok {
+		_, found = doc.Parameters[unescapePointer(name)]
+	} else if name, ok := strings.CutPrefix(ref, responsesPrefix); ok {
+		_, found = doc.Responses[unescapePointer(name)]
+	} else {
+		return true
+	}
+	return found
+}
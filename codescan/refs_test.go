@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func collectRefs(t *testing.T, doc *spec.Swagger) []string {
+	t.Helper()
+	var refs []string
+	require.NoError(t, rewriteRefs(doc, func(ref string) string {
+		refs = append(refs, ref)
+		return ref
+	}))
+	return refs
+}
+
+func TestRelativeRefs(t *testing.T) {
+	packages := []string{"github.com/3idey/codescan/fixtures/goparsing/petstore/..."}
+
+	local, err := Run(&Options{Packages: packages})
+	require.NoError(t, err)
+	qualified, err := Run(&Options{Packages: packages, RelativeRefs: "swagger.json"})
+	require.NoError(t, err)
+
+	refs := collectRefs(t, qualified)
+	require.NotEmpty(t, refs)
+	for _, ref := range refs {
+		assert.True(t, strings.HasPrefix(ref, "swagger.json#/"), ref)
+	}
+
+	// both forms resolve
+	dir := t.TempDir()
+	for name, doc := range map[string]*spec.Swagger{"swagger.json": qualified, "local.json": local} {
+		jazon, err := json.Marshal(doc)
+		require.NoError(t, err)
+		pth := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(pth, jazon, 0o600))
+		var loaded spec.Swagger
+		require.NoError(t, json.Unmarshal(jazon, &loaded))
+		require.NoError(t, spec.ExpandSpec(&loaded, &spec.ExpandOptions{RelativeBase: pth}), name)
+	}
+
+	// the round trip is lossless
+	require.NoError(t, NormalizeRefs(qualified, "swagger.json"))
+	expected, err := json.Marshal(local)
+	require.NoError(t, err)
+	actual, err := json.Marshal(qualified)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(expected), string(actual))
+}
+
+func TestNormalizeRefs(t *testing.T) {
+	doc := &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+		Definitions: spec.Definitions{
+			"Pet":   *spec.RefSchema("swagger.json#/definitions/Animal"),
+			"Money": *spec.RefSchema("common.json#/definitions/Money"),
+			"Tag":   *spec.RefSchema("#/definitions/Label"),
+		},
+	}}
+
+	require.NoError(t, NormalizeRefs(doc, "swagger.json"))
+	assert.Equal(t, []string{
+		"common.json#/definitions/Money", // refs to other documents are kept
+		"#/definitions/Animal",
+		"#/definitions/Label",
+	}, collectRefs(t, doc))
+
+	require.Error(t, NormalizeRefs(doc, ""))
+	require.Error(t, QualifyRefs(doc, "swagger.json#/definitions"))
+}
@@ -7,10 +7,13 @@ import "regexp"
 
 const (
 	rxMethod = "(\\p{L}+)"
-	rxPath   = "((?:/[\\p{L}\\p{N}\\p{Pd}\\p{Pc}{}\\-\\.\\?_~%!$&'()*+,;=:@/]*)+/?)"
+	rxPath   = "((?:/(?:" + rxPathConstraint + "|[\\p{L}\\p{N}\\p{Pd}\\p{Pc}{}\\-\\.\\?_~%!$&'()*+,;=:@/])*)+/?)"
 	rxOpTags = "(\\p{L}[\\p{L}\\p{N}\\p{Pd}\\.\\p{Pc}\\p{Zs}]+)"
 	rxOpID   = "((?:\\p{L}[\\p{L}\\p{N}\\p{Pd}\\p{Pc}]+)+)"
 
+	// rxPathConstraint matches a path parameter with a regular expression, e.g. {id:[0-9]+} or {year:[0-9]{4}}
+	rxPathConstraint = "\\{[\\p{L}\\p{N}\\p{Pd}\\p{Pc}]+:[^\\p{Zs}{}]*(?:\\{[^\\p{Zs}{}]*\\}[^\\p{Zs}{}]*)*\\}"
+
 	rxMaximumFmt    = "%s[Mm]ax(?:imum)?\\p{Zs}*:\\p{Zs}*([\\<=])?\\p{Zs}*([\\+-]?(?:\\p{N}+\\.)?\\p{N}+)$"
 	rxMinimumFmt    = "%s[Mm]in(?:imum)?\\p{Zs}*:\\p{Zs}*([\\>=])?\\p{Zs}*([\\+-]?(?:\\p{N}+\\.)?\\p{N}+)$"
 	rxMultipleOfFmt = "%s[Mm]ultiple\\p{Zs}*[Oo]f\\p{Zs}*:\\p{Zs}*([\\+-]?(?:\\p{N}+\\.)?\\p{N}+)$"
@@ -23,11 +26,18 @@ const (
 	rxDefaultFmt          = "%s[Dd]efault\\p{Zs}*:\\p{Zs}*(.*)$"
 	rxExampleFmt          = "%s[Ee]xample\\p{Zs}*:\\p{Zs}*(.*)$"
 
-	rxMaxItemsFmt = "%s[Mm]ax(?:imum)?(?:\\p{Zs}*|[\\p{Pd}\\p{Pc}]|\\.)?[Ii]tems\\p{Zs}*:\\p{Zs}*(\\p{N}+)$"
-	rxMinItemsFmt = "%s[Mm]in(?:imum)?(?:\\p{Zs}*|[\\p{Pd}\\p{Pc}]|\\.)?[Ii]tems\\p{Zs}*:\\p{Zs}*(\\p{N}+)$"
-	rxUniqueFmt   = "%s[Uu]nique\\p{Zs}*:\\p{Zs}*(true|false)$"
+	// the count of items is a number or a constant between braces, e.g. {MaxBatchSize} or {limits.MaxBatchSize}
+	rxMaxItemsFmt = "%s[Mm]ax(?:imum)?(?:\\p{Zs}*|[\\p{Pd}\\p{Pc}]|\\.)?[Ii]tems\\p{Zs}*:\\p{Zs}*(\\p{N}+|\\{[\\p{L}_][\\p{L}\\p{N}_]*(?:\\.[\\p{L}_][\\p{L}\\p{N}_]*)?\\})$"
+	rxMinItemsFmt = "%s[Mm]in(?:imum)?(?:\\p{Zs}*|[\\p{Pd}\\p{Pc}]|\\.)?[Ii]tems\\p{Zs}*:\\p{Zs}*(\\p{N}+|\\{[\\p{L}_][\\p{L}\\p{N}_]*(?:\\.[\\p{L}_][\\p{L}\\p{N}_]*)?\\})$"
+	rxUniqueFmt   = "%s[Uu]nique(?:\\p{Zs}*[\\p{Pd}\\p{Pc}]?[Ii]tems)?\\p{Zs}*:\\p{Zs}*(true|false)$"
 
 	rxItemsPrefixFmt = "(?:[Ii]tems[\\.\\p{Zs}]*){%d}"
+
+	// rxScopeModifier matches an optional "scope:xxx" modifier at the end of a declaration annotation
+	rxScopeModifier = `(?:\p{Zs}+scope:\p{L}+)?\p{Zs}*$`
+
+	// rxDeriveModifiers matches the modifiers deriving a model from another one, e.g. "deriveFrom:User allOptional"
+	rxDeriveModifiers = `(?:\p{Zs}+(?:deriveFrom:[\p{L}\p{N}\p{Pd}\p{Pc}]+|allOptional|optional:[^\p{Zs}]+|omit:[^\p{Zs}]+))*`
 )
 
 var (
@@ -37,13 +47,21 @@ var (
 	rxAlias              = regexp.MustCompile(`swagger:alias`)
 	rxName               = regexp.MustCompile(`swagger:name\p{Zs}*(\p{L}[\p{L}\p{N}\p{Pd}\p{Pc}\.]+)$`)
 	rxAllOf              = regexp.MustCompile(`swagger:allOf\p{Zs}*(\p{L}[\p{L}\p{N}\p{Pd}\p{Pc}\.]+)?$`)
-	rxModelOverride      = regexp.MustCompile(`swagger:model\p{Zs}*(\p{L}[\p{L}\p{N}\p{Pd}\p{Pc}]+)?$`)
-	rxResponseOverride   = regexp.MustCompile(`swagger:response\p{Zs}*(\p{L}[\p{L}\p{N}\p{Pd}\p{Pc}]+)?$`)
-	rxParametersOverride = regexp.MustCompile(`swagger:parameters\p{Zs}*(\p{L}[\p{L}\p{N}\p{Pd}\p{Pc}\p{Zs}]+)$`)
+	rxDiscriminatorField = regexp.MustCompile(`swagger:discriminator(?:\p{Zs}+(\S+))?\p{Zs}*$`)
+	rxAllOfRef           = regexp.MustCompile(`swagger:allOfRef\p{Zs}+(\p{L}[\p{L}\p{N}\p{Pd}\p{Pc}\.]+)(?:\p{Zs}+class:(\p{L}[\p{L}\p{N}\p{Pd}\p{Pc}\.]+))?\p{Zs}*$`)
+	rxModelOverride      = regexp.MustCompile(`swagger:model\p{Zs}*(\p{L}[\p{L}\p{N}\p{Pd}\p{Pc}]+)?` + rxDeriveModifiers + rxScopeModifier)
+	rxModelDerivation    = regexp.MustCompile(`swagger:model\b(.*\p{Zs}(?:deriveFrom:|allOptional|optional:|omit:).*)$`)
+	rxResponseOverride   = regexp.MustCompile(`swagger:response\p{Zs}*(\p{L}[\p{L}\p{N}\p{Pd}\p{Pc}]+)?` + rxScopeModifier)
+	rxParametersOverride = regexp.MustCompile(`swagger:parameters\p{Zs}*(\p{L}[\p{L}\p{N}\p{Pd}\p{Pc}\p{Zs}]+)` + rxScopeModifier)
+	rxDeclScope          = regexp.MustCompile(`swagger:(?:model|response|parameters)\b.*\p{Zs}scope:(\p{L}+)\p{Zs}*$`)
 	rxEnum               = regexp.MustCompile(`swagger:enum\p{Zs}*(\p{L}[\p{L}\p{N}\p{Pd}\p{Pc}]+)$`)
+	rxEnumIgnore         = regexp.MustCompile(`swagger:enum:ignore\p{Zs}*$`)
+	rxSuppression        = regexp.MustCompile(`^[\p{Zs}\t/\*-]*codescan:ignore(?:\p{Zs}|$)`)
 	rxIgnoreOverride     = regexp.MustCompile(`swagger:ignore\p{Zs}*(\p{L}[\p{L}\p{N}\p{Pd}\p{Pc}]+)?$`)
+	rxSwaggerDeprecated  = regexp.MustCompile(`swagger:deprecated\p{Zs}*$`)
 	rxDefault            = regexp.MustCompile(`swagger:default\p{Zs}*(\p{L}[\p{L}\p{N}\p{Pd}\p{Pc}]+)$`)
 	rxType               = regexp.MustCompile(`swagger:type\p{Zs}*(\p{L}[\p{L}\p{N}\p{Pd}\p{Pc}]+)$`)
+	rxExampleFile        = regexp.MustCompile(`swagger:example\b(?:\p{Zs}+file:(\S+))?\p{Zs}*$`)
 	rxRoute              = regexp.MustCompile(
 		"swagger:route\\p{Zs}*" +
 			rxMethod +
@@ -53,9 +71,12 @@ var (
 			rxOpTags +
 			")?\\p{Zs}+" +
 			rxOpID + "\\p{Zs}*$")
+	rxPathDoc          = regexp.MustCompile("swagger:path\\p{Zs}+" + rxPath + "\\p{Zs}*$")
+	rxTagDoc           = regexp.MustCompile(`swagger:tag\p{Zs}+(\S+)\p{Zs}*$`)
 	rxBeginYAMLSpec    = regexp.MustCompile(`---\p{Zs}*$`)
 	rxUncommentHeaders = regexp.MustCompile(`^[\p{Zs}\t/\*-]*\|?`)
 	rxUncommentYAML    = regexp.MustCompile(`^[\p{Zs}\t]*/*`)
+	rxUncommentMarkers = regexp.MustCompile(`^[\p{Zs}\t]*(?://|/\*+|\*+(?:/|\p{Zs}|$))?\p{Zs}?`)
 	rxOperation        = regexp.MustCompile(
 		"swagger:operation\\p{Zs}*" +
 			rxMethod +
@@ -78,6 +99,8 @@ var (
 	rxIn              = regexp.MustCompile(`[Ii]n\p{Zs}*:\p{Zs}*(query|path|header|body|formData)$`)
 	rxRequired        = regexp.MustCompile(`[Rr]equired\p{Zs}*:\p{Zs}*(true|false)$`)
 	rxDiscriminator   = regexp.MustCompile(`[Dd]iscriminator\p{Zs}*:\p{Zs}*(true|false)$`)
+	rxUnionTypes      = regexp.MustCompile(`(?:^|[^\p{L}\p{N}])[Tt]ypes\p{Zs}*:\p{Zs}*(\p{L}[\p{L}\p{N}\p{Pc}\.]*(?:\p{Zs}*,\p{Zs}*\p{L}[\p{L}\p{N}\p{Pc}\.]*)*)\p{Zs}*$`)
+	rxSchemaFile      = regexp.MustCompile(`(?:^|[^\p{L}\p{N}])[Ss]chema\p{Zs}*[Ff]ile\p{Zs}*:\p{Zs}*(\S+)\p{Zs}*$`)
 	rxReadOnly        = regexp.MustCompile(`[Rr]ead(?:\p{Zs}*|[\p{Pd}\p{Pc}])?[Oo]nly\p{Zs}*:\p{Zs}*(true|false)$`)
 	rxConsumes        = regexp.MustCompile(`[Cc]onsumes\p{Zs}*:`)
 	rxProduces        = regexp.MustCompile(`[Pp]roduces\p{Zs}*:`)
@@ -95,5 +118,16 @@ var (
 	rxExtensions      = regexp.MustCompile(`[Ee]xtensions\p{Zs}*:`)
 	rxInfoExtensions  = regexp.MustCompile(`[In]nfo\p{Zs}*[Ee]xtensions:`)
 	rxDeprecated      = regexp.MustCompile(`[Dd]eprecated\p{Zs}*:\p{Zs}*(true|false)$`)
+	rxIdempotent      = regexp.MustCompile(`[Ii]dempotent\p{Zs}*:\p{Zs}*(true|false)$`)
+	rxUnwrap          = regexp.MustCompile(`[Uu]nwrap\p{Zs}*:\p{Zs}*(\p{L}[\p{L}\p{N}\p{Pd}\p{Pc}]*)$`)
+	rxAudience        = regexp.MustCompile(`[Aa]udiences?\p{Zs}*:\p{Zs}*(\w[\w\p{Zs},-]*)$`)
+	rxIdempotencyKey  = regexp.MustCompile(`[Ii]dempotency\p{Zs}*-?[Kk]ey\p{Zs}*:\p{Zs}*(required|optional)$`)
+	rxExternalDocs    = regexp.MustCompile(`[Ee]xternal\p{Zs}*-?[Dd]ocs\p{Zs}*:\p{Zs}*(\S+)(?:\p{Zs}+(.*?))?\p{Zs}*$`)
+	rxCustomTag       = regexp.MustCompile(`[Cc]ustom\p{Zs}*-?[Tt]ag\p{Zs}*:\p{Zs}*(.+?)\p{Zs}*$`)
+	rxDirectiveLine   = regexp.MustCompile(`^\p{Zs}*[A-Za-z][A-Za-z .-]*:(?:\p{Zs}|$)`)
+	rxEscapedLine     = regexp.MustCompile(`^(\p{Zs}*)\\([A-Za-z][A-Za-z .-]*:(?:\p{Zs}|$))`)
+	rxEscapedComment  = regexp.MustCompile(`^[\p{Zs}\t/\*]*\\[A-Za-z][A-Za-z .-]*:(?:\p{Zs}|$)`)
+	rxKeys            = regexp.MustCompile(`^[\p{Zs}\t/\*]*[Kk]eys\p{Zs}*:\p{Zs}*([^\p{Zs},]+(?:\p{Zs}*,\p{Zs}*[^\p{Zs},]+)*)\p{Zs}*$`)
+	rxGroup           = regexp.MustCompile(`^[\p{Zs}\t/\*]*[Gg]roups?\p{Zs}*:\p{Zs}*([^\p{Zs}=,]+\p{Zs}*=[^,]+(?:,\p{Zs}*[^\p{Zs}=,]+\p{Zs}*=[^,]+)*?)\p{Zs}*$`)
 	// currently unused: rxExample         = regexp.MustCompile(`[Ex]ample\p{Zs}*:\p{Zs}*(.*)$`).
 )
@@ -118,6 +118,7 @@ func TestSchemaValueExtractors(t *testing.T) {
 
 	verifyIntegerMinMaxManyWords(t, rxf(rxMinItemsFmt, ""), "min", []string{"items"})
 	verifyBoolean(t, rxf(rxUniqueFmt, ""), []string{"unique"}, nil)
+	verifyBoolean(t, rxf(rxUniqueFmt, ""), []string{"unique"}, []string{"items"})
 
 	verifyBoolean(t, rxReadOnly, []string{"read"}, []string{"only"})
 	verifyBoolean(t, rxRequired, []string{"required"}, nil)
@@ -0,0 +1,536 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go/token"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-openapi/spec"
+)
+
+// Codes of the problems reported by ResolveRemoteRefs.
+const (
+	// DiagnosticChangedRemoteRef reports a remote document whose content changed since its hash was recorded.
+	DiagnosticChangedRemoteRef = "changed-remote-ref"
+)
+
+// ErrUnresolvedRefs is returned when external refs can't be resolved, e.g. offline.
+var ErrUnresolvedRefs = errors.New("unresolved external refs")
+
+// maxRemoteDocument is the size over which a remote document is rejected.
+const maxRemoteDocument = 32 << 20
+
+// RemoteRefOptions configure the resolution of the external refs of a spec, see ResolveRemoteRefs.
+type RemoteRefOptions struct {
+	// Base is the location of the spec, a file or a URL, its relative refs resolve against, e.g. the
+	// swagger.json of a split spec. They resolve against the current directory when empty.
+	Base string
+	// CacheDir keeps the remote documents by the hash of their content, so that the documents of Lock are
+	// read from it offline, or when the remote ones changed.
+	CacheDir string
+	// AllowedHosts are the hosts the remote documents are fetched from, e.g. specs.acme.com or *.acme.com.
+	// Every host is allowed when empty.
+	AllowedHosts []string
+	// Offline fails with the remote refs whose documents aren't in the CacheDir, rather than fetching them.
+	Offline bool
+	// Retries is the number of retries of a fetch failing with a network error, 429 Too Many Requests or a 5xx status.
+	Retries int
+	// Backoff is the delay before the first retry, doubled after each one, or the Retry-After of the response
+	// when longer.
+	Backoff time.Duration
+	// Interval is the minimum delay between two requests to a host.
+	Interval time.Duration
+	// Lock are the hashes of the remote documents recorded by a previous resolution, by URL, see ResolvedRefs.Lock.
+	Lock map[string]string
+	// Client fetches the remote documents, http.DefaultClient when nil.
+	Client *http.Client
+	// Logger receives the warnings, which are logged without it.
+	Logger func(Diagnostic)
+}
+
+// ResolvedRefs are the documents the external refs of a spec point to, see ValidateExternalRefs and BundleSpec.
+type ResolvedRefs struct {
+	// Base is the location of the spec, see RemoteRefOptions.Base.
+	Base string
+	// Documents are the documents, by location: a URL, or an absolute file path.
+	Documents map[string]map[string]any
+	// Lock are the hashes of the remote documents, e.g. sha256:<hex>, by URL, which the next resolutions
+	// compare with for reproducible results.
+	Lock map[string]string
+}
+
+// ResolveRemoteRefs reads the documents the external refs of a spec point to, and those their own refs point
+// to: the files, relative to the document referring to them, and the remote documents of http and https URLs.
+//
+// A remote document whose hash is in RemoteRefOptions.Lock is read from the CacheDir when the remote one
+// changed, with a DiagnosticChangedRemoteRef warning, so that the results stay the same until its hash is
+// removed from the lock. The documents which can't be resolved are reported together, offline with
+// ErrUnresolvedRefs.
+func ResolveRemoteRefs(ctx context.Context, doc *spec.Swagger, opts RemoteRefOptions) (*ResolvedRefs, error) {
+	r := &refResolver{
+		opts:     opts,
+		resolved: &ResolvedRefs{Base: opts.Base, Documents: make(map[string]map[string]any), Lock: maps.Clone(opts.Lock)},
+		requests: make(map[string]time.Time),
+	}
+	if r.resolved.Lock == nil {
+		r.resolved.Lock = make(map[string]string)
+	}
+	if r.opts.Client == nil {
+		r.opts.Client = http.DefaultClient
+	}
+
+	root := rootLocation(opts.Base)
+	var pending []externalRef
+	for _, ref := range specRefs(doc) {
+		if location := refLocation(root, ref); location != "" && location != root {
+			pending = append(pending, externalRef{ref: ref, document: location})
+		}
+	}
+
+	var errs []error
+	var unresolved []string
+	for len(pending) > 0 {
+		next := pending[0]
+		pending = pending[1:]
+		if _, loaded := r.resolved.Documents[next.document]; loaded || next.document == root {
+			continue
+		}
+
+		data, err := r.read(ctx, next.document)
+		switch {
+		case errors.Is(err, errOffline):
+			unresolved = append(unresolved, next.document)
+			r.resolved.Documents[next.document] = nil
+			continue
+		case err != nil:
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+			errs = append(errs, fmt.Errorf("%s, referred to by %s: %w", next.document, next.ref, err))
+			r.resolved.Documents[next.document] = nil
+			continue
+		}
+		document, err := parseSpecDocument(data)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", next.document, err))
+			r.resolved.Documents[next.document] = nil
+			continue
+		}
+		r.resolved.Documents[next.document] = document
+
+		for _, ref := range documentRefs(document) {
+			if location := refLocation(next.document, ref); location != "" {
+				pending = append(pending, externalRef{ref: ref, document: location})
+			}
+		}
+	}
+
+	if len(unresolved) > 0 {
+		slices.Sort(unresolved)
+		errs = append(errs, fmt.Errorf("%w, offline and missing from the cache: %s", ErrUnresolvedRefs, strings.Join(slices.Compact(unresolved), ", ")))
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	maps.DeleteFunc(r.resolved.Documents, func(_ string, document map[string]any) bool {
+		return document == nil
+	})
+	return r.resolved, nil
+}
+
+// errOffline tells a remote document which is neither fetched, nor cached.
+var errOffline = errors.New("offline")
+
+type externalRef struct {
+	ref      string // as written
+	document string // location of its document
+}
+
+type refResolver struct {
+	opts     RemoteRefOptions
+	resolved *ResolvedRefs
+	requests map[string]time.Time // last request by host, see RemoteRefOptions.Interval
+}
+
+// read returns the content of a document: a file, or a remote document.
+func (r *refResolver) read(ctx context.Context, location string) ([]byte, error) {
+	if !isRemote(location) {
+		return os.ReadFile(location)
+	}
+
+	locked := r.opts.Lock[location]
+	cached, cacheErr := r.cached(locked)
+	if r.opts.Offline {
+		if cacheErr != nil {
+			return nil, errOffline
+		}
+		return cached, nil
+	}
+
+	data, err := r.fetch(ctx, location)
+	switch {
+	case err != nil && cacheErr == nil && ctx.Err() == nil:
+		r.warn(location, "the document can't be fetched, using the content of its hash %s: %v", locked, err)
+		return cached, nil
+	case err != nil:
+		return nil, err
+	}
+
+	hash := contentHash(data)
+	switch {
+	case locked == "" || locked == hash:
+		r.resolved.Lock[location] = hash
+	case cacheErr == nil:
+		r.warn(location, "the document changed since its hash %s was recorded, using the recorded content; remove it from the lock to use the new one", locked)
+		return cached, nil
+	default:
+		r.warn(location, "the document changed since its hash %s was recorded, and the recorded content isn't cached: using the new one, %s", locked, hash)
+		r.resolved.Lock[location] = hash
+	}
+	if err := r.cache(hash, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// cached returns the content of a hash in the cache directory.
+func (r *refResolver) cached(hash string) ([]byte, error) {
+	if hash == "" || r.opts.CacheDir == "" {
+		return nil, os.ErrNotExist
+	}
+	data, err := os.ReadFile(filepath.Join(r.opts.CacheDir, cacheFileName(hash)))
+	if err != nil {
+		return nil, err
+	}
+	if contentHash(data) != hash {
+		return nil, fmt.Errorf("the cached content of %s is corrupted", hash)
+	}
+	return data, nil
+}
+
+func (r *refResolver) cache(hash string, data []byte) error {
+	if r.opts.CacheDir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(r.opts.CacheDir, 0o755); err != nil {
+		return fmt.Errorf("failed to cache the remote documents: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(r.opts.CacheDir, cacheFileName(hash)), data, 0o644); err != nil {
+		return fmt.Errorf("failed to cache the remote documents: %w", err)
+	}
+	return nil
+}
+
+func (r *refResolver) warn(location, format string, args ...any) {
+	diagnostic := Diagnostic{
+		Pos:      token.Position{Filename: location},
+		Code:     DiagnosticChangedRemoteRef,
+		Message:  fmt.Sprintf(format, args...),
+		Severity: SeverityWarning,
+	}
+	if r.opts.Logger != nil {
+		r.opts.Logger(diagnostic)
+		return
+	}
+	logDiagnostic(diagnostic)
+}
+
+// fetch gets a remote document from an allowed host, retrying the transient failures.
+func (r *refResolver) fetch(ctx context.Context, location string) ([]byte, error) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, err
+	}
+	if !hostAllowed(u, r.opts.AllowedHosts) {
+		return nil, fmt.Errorf("the host %s isn't allowed, see AllowedHosts", u.Host)
+	}
+
+	delay := r.opts.Backoff
+	for attempt := 0; ; attempt++ {
+		if err := r.throttle(ctx, u.Host); err != nil {
+			return nil, err
+		}
+		data, retryAfter, err := r.get(ctx, location)
+		var transient *transientError
+		if err == nil || !errors.As(err, &transient) || attempt >= r.opts.Retries || ctx.Err() != nil {
+			return data, err
+		}
+		if err := sleep(ctx, max(delay, retryAfter)); err != nil {
+			return nil, err
+		}
+		delay *= 2
+	}
+}
+
+// transientError is a failure of a fetch worth retrying.
+type transientError struct {
+	err error
+}
+
+func (e *transientError) Error() string { return e.err.Error() }
+
+func (e *transientError) Unwrap() error { return e.err }
+
+// get requests a remote document, returning the Retry-After of the failed responses.
+func (r *refResolver) get(ctx context.Context, location string) ([]byte, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Accept", "application/json, application/yaml;q=0.9, */*;q=0.1")
+	resp, err := r.opts.Client.Do(req)
+	if err != nil {
+		return nil, 0, &transientError{err: err}
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError:
+		return nil, retryAfter(resp.Header.Get("Retry-After")), &transientError{err: errors.New(resp.Status)}
+	case resp.StatusCode != http.StatusOK:
+		return nil, 0, errors.New(resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteDocument+1))
+	if err != nil {
+		return nil, 0, &transientError{err: err}
+	}
+	if len(data) > maxRemoteDocument {
+		return nil, 0, fmt.Errorf("the document is larger than %d bytes", maxRemoteDocument)
+	}
+	return data, 0, nil
+}
+
+// throttle waits for RemoteRefOptions.Interval since the last request to a host.
+func (r *refResolver) throttle(ctx context.Context, host string) error {
+	if last, requested := r.requests[host]; requested {
+		if err := sleep(ctx, time.Until(last.Add(r.opts.Interval))); err != nil {
+			return err
+		}
+	}
+	r.requests[host] = time.Now()
+	return nil
+}
+
+func sleep(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// retryAfter parses a Retry-After header: seconds, or a date.
+func retryAfter(value string) time.Duration {
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date)
+	}
+	return 0
+}
+
+// hostAllowed tells whether a URL is on one of the hosts, with or without port, matched as path.Match patterns.
+func hostAllowed(u *url.URL, hosts []string) bool {
+	if len(hosts) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(hosts, func(host string) bool {
+		matchesHost, _ := path.Match(host, u.Hostname())
+		matchesHostPort, _ := path.Match(host, u.Host)
+		return matchesHost || matchesHostPort
+	})
+}
+
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func cacheFileName(hash string) string {
+	return strings.ReplaceAll(hash, ":", "-")
+}
+
+func isRemote(location string) bool {
+	return strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://")
+}
+
+// rootLocation is the location of the spec, the absolute path of a file.
+func rootLocation(base string) string {
+	if base == "" || isRemote(base) {
+		return base
+	}
+	if abs, err := filepath.Abs(base); err == nil {
+		return abs
+	}
+	return base
+}
+
+// refLocation returns the location of the document of a ref found in the document at base, empty for the
+// local refs of the spec.
+func refLocation(base, ref string) string {
+	location, _, _ := strings.Cut(ref, "#")
+	switch {
+	case location == "":
+		return base
+	case isRemote(location) || filepath.IsAbs(location):
+		return location
+	case isRemote(base):
+		u, err := url.Parse(base)
+		if err != nil {
+			return location
+		}
+		relative, err := url.Parse(location)
+		if err != nil {
+			return location
+		}
+		return u.ResolveReference(relative).String()
+	case base == "":
+		return rootLocation(location)
+	default:
+		return filepath.Join(filepath.Dir(base), filepath.FromSlash(location))
+	}
+}
+
+// specRefs are the $refs of a spec.
+func specRefs(doc *spec.Swagger) []string {
+	var refs []string
+	_ = rewriteRefs(doc, func(ref string) string {
+		refs = append(refs, ref)
+		return ref
+	})
+	return refs
+}
+
+// documentRefs are the $refs of a document, in a stable order.
+func documentRefs(value any) []string {
+	var refs []string
+	switch value := value.(type) {
+	case map[string]any:
+		for _, key := range sortedKeys(value) {
+			if ref, isRef := value[key].(string); isRef && key == "$ref" {
+				refs = append(refs, ref)
+				continue
+			}
+			refs = append(refs, documentRefs(value[key])...)
+		}
+	case []any:
+		for _, item := range value {
+			refs = append(refs, documentRefs(item)...)
+		}
+	}
+	return refs
+}
+
+// documentElement returns the element of a document a JSON pointer points to.
+func documentElement(document any, pointer string) (any, bool) {
+	if pointer == "" || pointer == "/" {
+		return document, true
+	}
+	current := document
+	for _, part := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		part = unescapePointer(part)
+		switch value := current.(type) {
+		case map[string]any:
+			item, found := value[part]
+			if !found {
+				return nil, false
+			}
+			current = item
+		case []any:
+			index, err := strconv.Atoi(part)
+			if err != nil || index < 0 || index >= len(value) {
+				return nil, false
+			}
+			current = value[index]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// ValidateExternalRefs reports the external refs of a spec, and of the documents they point to, which point
+// to nothing, as DiagnosticInvalidSpec errors like ValidateSpec.
+func ValidateExternalRefs(doc *spec.Swagger, resolved *ResolvedRefs) []Diagnostic {
+	v := &specValidator{doc: doc, located: make(map[string][]string)}
+	root := rootLocation(resolved.Base)
+	check := func(location, base, ref string) {
+		document := refLocation(base, ref)
+		if ref == "" || document == "" || document == root {
+			return
+		}
+		_, pointer, _ := strings.Cut(ref, "#")
+		content, resolvedDocument := resolved.Documents[document]
+		switch {
+		case !resolvedDocument:
+			v.invalid(location, "the document of $ref %s isn't resolved", ref)
+		case !pointsToSomething(content, pointer):
+			v.invalid(location, "$ref %s points to nothing in %s", ref, document)
+		}
+	}
+
+	walkSpecSchemas(doc, func(sch *spec.Schema, location string) {
+		check(location, root, sch.Ref.String())
+	})
+	checkParams := func(params []spec.Parameter, location string) {
+		for i := range params {
+			check(location+"/"+strconv.Itoa(i), root, params[i].Ref.String())
+		}
+	}
+	if doc.Paths != nil {
+		for _, pth := range sortedKeys(doc.Paths.Paths) {
+			pathItem := doc.Paths.Paths[pth]
+			location := "#/paths/" + escapePointer(pth)
+			check(location, root, pathItem.Ref.String())
+			checkParams(pathItem.Parameters, location+"/parameters")
+			for method, op := range pathItemOperations(&pathItem) {
+				checkParams(op.Parameters, location+"/"+method+"/parameters")
+				if op.Responses == nil {
+					continue
+				}
+				if op.Responses.Default != nil {
+					check(location+"/"+method+"/responses/default", root, op.Responses.Default.Ref.String())
+				}
+				for _, code := range sortedKeys(op.Responses.StatusCodeResponses) {
+					resp := op.Responses.StatusCodeResponses[code]
+					check(location+"/"+method+"/responses/"+strconv.Itoa(code), root, resp.Ref.String())
+				}
+			}
+		}
+	}
+	for _, document := range sortedKeys(resolved.Documents) {
+		for _, ref := range documentRefs(resolved.Documents[document]) {
+			check(document, document, ref)
+		}
+	}
+	return v.problems
+}
+
+func pointsToSomething(document map[string]any, pointer string) bool {
+	_, found := documentElement(document, pointer)
+	return found
+}
@@ -0,0 +1,194 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func remoteRefSpec(t *testing.T, ref string) *spec.Swagger {
+	t.Helper()
+	doc, err := ParseInputSpec([]byte(`{
+  "swagger": "2.0",
+  "info": {"title": "Orders", "version": "1.0"},
+  "paths": {},
+  "definitions": {
+    "Order": {"type": "object", "properties": {"total": {"$ref": "`+ref+`"}}}
+  }
+}`), false)
+	require.NoError(t, err)
+	return doc
+}
+
+const moneyDocument = `{"definitions": {"Money": {"type": "object", "properties": {"currency": {"$ref": "#/definitions/Currency"}}}, "Currency": {"type": "string"}}}`
+
+func TestResolveRemoteRefs(t *testing.T) {
+	t.Run("should fetch the remote documents and those they refer to", func(t *testing.T) {
+		server := httptest.NewServer(http.FileServer(http.Dir(writeRemoteDocuments(t, map[string]string{
+			"common.json": `{"definitions": {"Money": {"$ref": "money.json#/definitions/Money"}}}`,
+			"money.json":  moneyDocument,
+		}))))
+		defer server.Close()
+
+		resolved, err := ResolveRemoteRefs(context.Background(), remoteRefSpec(t, server.URL+"/common.json#/definitions/Money"), RemoteRefOptions{})
+		require.NoError(t, err)
+		assert.Contains(t, resolved.Documents, server.URL+"/common.json")
+		assert.Contains(t, resolved.Documents, server.URL+"/money.json", "relative to the document referring to it")
+		assert.Regexp(t, `^sha256:[0-9a-f]{64}$`, resolved.Lock[server.URL+"/money.json"])
+	})
+
+	t.Run("should retry the transient failures", func(t *testing.T) {
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			switch requests.Add(1) {
+			case 1:
+				w.WriteHeader(http.StatusServiceUnavailable)
+			case 2:
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+			default:
+				_, _ = w.Write([]byte(moneyDocument))
+			}
+		}))
+		defer server.Close()
+
+		_, err := ResolveRemoteRefs(context.Background(), remoteRefSpec(t, server.URL+"/money.json#/definitions/Money"),
+			RemoteRefOptions{Retries: 2, Backoff: time.Millisecond})
+		require.NoError(t, err)
+		assert.Equal(t, int32(3), requests.Load())
+
+		requests.Store(0)
+		_, err = ResolveRemoteRefs(context.Background(), remoteRefSpec(t, server.URL+"/money.json#/definitions/Money"),
+			RemoteRefOptions{Retries: 1, Backoff: time.Millisecond})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "429")
+	})
+
+	t.Run("should not retry the other failures", func(t *testing.T) {
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			requests.Add(1)
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		_, err := ResolveRemoteRefs(context.Background(), remoteRefSpec(t, server.URL+"/money.json#/definitions/Money"),
+			RemoteRefOptions{Retries: 3, Backoff: time.Millisecond})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "404")
+		assert.Equal(t, int32(1), requests.Load())
+	})
+
+	t.Run("should only fetch from the allowed hosts", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(moneyDocument))
+		}))
+		defer server.Close()
+		u, err := url.Parse(server.URL)
+		require.NoError(t, err)
+
+		doc := remoteRefSpec(t, server.URL+"/money.json#/definitions/Money")
+		_, err = ResolveRemoteRefs(context.Background(), doc, RemoteRefOptions{AllowedHosts: []string{"specs.acme.com"}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "the host "+u.Host+" isn't allowed")
+
+		_, err = ResolveRemoteRefs(context.Background(), doc, RemoteRefOptions{AllowedHosts: []string{"specs.acme.com", u.Hostname()}})
+		assert.NoError(t, err)
+	})
+
+	t.Run("should list the refs missing from the cache offline", func(t *testing.T) {
+		doc := remoteRefSpec(t, "https://specs.acme.com/money.json#/definitions/Money")
+		doc.Definitions["Invoice"] = spec.Schema{SchemaProps: spec.SchemaProps{Ref: spec.MustCreateRef("https://specs.acme.com/common.json#/definitions/Invoice")}}
+
+		_, err := ResolveRemoteRefs(context.Background(), doc, RemoteRefOptions{Offline: true, CacheDir: t.TempDir()})
+		require.ErrorIs(t, err, ErrUnresolvedRefs)
+		assert.Contains(t, err.Error(), "https://specs.acme.com/common.json, https://specs.acme.com/money.json")
+	})
+
+	t.Run("should read the locked documents from the cache", func(t *testing.T) {
+		content := moneyDocument
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(content))
+		}))
+		defer server.Close()
+		location := server.URL + "/money.json"
+		doc := remoteRefSpec(t, location+"#/definitions/Money")
+		cacheDir := t.TempDir()
+
+		first, err := ResolveRemoteRefs(context.Background(), doc, RemoteRefOptions{CacheDir: cacheDir})
+		require.NoError(t, err)
+		lock := first.Lock
+
+		offline, err := ResolveRemoteRefs(context.Background(), doc, RemoteRefOptions{CacheDir: cacheDir, Lock: lock, Offline: true})
+		require.NoError(t, err)
+		assert.Equal(t, first.Documents, offline.Documents)
+
+		content = `{"definitions": {"Money": {"type": "number"}}}`
+		var warnings []Diagnostic
+		changed, err := ResolveRemoteRefs(context.Background(), doc, RemoteRefOptions{
+			CacheDir: cacheDir, Lock: lock,
+			Logger: func(d Diagnostic) { warnings = append(warnings, d) },
+		})
+		require.NoError(t, err)
+		assert.Equal(t, first.Documents, changed.Documents, "the recorded content is used")
+		assert.Equal(t, lock, changed.Lock)
+		require.Len(t, warnings, 1)
+		assert.Equal(t, DiagnosticChangedRemoteRef, warnings[0].Code)
+		assert.Equal(t, location, warnings[0].Pos.Filename)
+		assert.Contains(t, warnings[0].Message, "the document changed since its hash")
+
+		unlocked, err := ResolveRemoteRefs(context.Background(), doc, RemoteRefOptions{CacheDir: cacheDir})
+		require.NoError(t, err)
+		assert.NotEqual(t, lock[location], unlocked.Lock[location])
+		assert.Equal(t, "number", unlocked.Documents[location]["definitions"].(map[string]any)["Money"].(map[string]any)["type"])
+	})
+
+	t.Run("should read the files relative to the base", func(t *testing.T) {
+		dir := writeRemoteDocuments(t, map[string]string{"common/money.yaml": "definitions:\n  Money:\n    type: string\n"})
+		resolved, err := ResolveRemoteRefs(context.Background(), remoteRefSpec(t, "common/money.yaml#/definitions/Money"),
+			RemoteRefOptions{Base: filepath.Join(dir, "swagger.json")})
+		require.NoError(t, err)
+		assert.Contains(t, resolved.Documents, filepath.Join(dir, "common", "money.yaml"))
+		assert.Empty(t, resolved.Lock, "only the remote documents are locked")
+	})
+}
+
+func TestValidateExternalRefs(t *testing.T) {
+	dir := writeRemoteDocuments(t, map[string]string{"money.json": moneyDocument})
+	doc := remoteRefSpec(t, "money.json#/definitions/Money")
+	doc.Definitions["Invoice"] = spec.Schema{SchemaProps: spec.SchemaProps{Ref: spec.MustCreateRef("money.json#/definitions/Amount")}}
+	resolved, err := ResolveRemoteRefs(context.Background(), doc, RemoteRefOptions{Base: filepath.Join(dir, "swagger.json")})
+	require.NoError(t, err)
+
+	problems := ValidateExternalRefs(doc, resolved)
+	require.Len(t, problems, 1)
+	assert.Equal(t, DiagnosticInvalidSpec, problems[0].Code)
+	assert.Contains(t, problems[0].Message, "$ref money.json#/definitions/Amount points to nothing in "+filepath.Join(dir, "money.json"))
+
+	resolved.Documents = nil
+	assert.Len(t, ValidateExternalRefs(doc, resolved), 2)
+}
+
+func writeRemoteDocuments(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+	return dir
+}
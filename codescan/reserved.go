@@ -0,0 +1,166 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/go-openapi/spec"
+)
+
+// xGoOriginalName records the name of a renamed definition, see Options.DefinitionRenames.
+const xGoOriginalName = "x-go-original-name"
+
+// collisionSuffix is appended to the colliding definition names by Options.RenameCollisions, e.g. ErrorModel.
+const collisionSuffix = "Model"
+
+// reservedDefinitionNames collide with the identifiers generated by go-swagger and oapi-codegen next to the
+// models, e.g. the Error interface of the go-swagger responses or the Client of oapi-codegen.
+var reservedDefinitionNames = []string{
+	"Client", "ClientInterface", "ClientOption", "ClientWithResponses", "Error", "HttpRequestDoer", "Response",
+	"Runtime", "ServerInterface", "ServerInterfaceWrapper", "StrictServerInterface",
+}
+
+// rxPortableName matches the definition names which the generators turn into identifiers as is.
+var rxPortableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// definitionCollision tells why a definition name breaks the generators, if it does.
+func definitionCollision(name string) string {
+	switch {
+	case slices.Contains(reservedDefinitionNames, name):
+		return "collides with an identifier generated by go-swagger or oapi-codegen"
+	case !rxPortableName.MatchString(name):
+		return "holds characters which are not valid in identifiers"
+	default:
+		return ""
+	}
+}
+
+// collisionFreeName returns the name Options.RenameCollisions gives to a colliding definition: the name
+// without its invalid characters, e.g. PageUser for Page/User, suffixed with Model when it is reserved,
+// and numbered when it is already taken.
+func collisionFreeName(name string, taken func(string) bool) string {
+	var clean strings.Builder
+	upper := false
+	for _, r := range name {
+		switch {
+		case r == '_' || r == '.' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9':
+			if upper {
+				r = unicode.ToUpper(r)
+			}
+			clean.WriteRune(r)
+			upper = false
+		default:
+			upper = clean.Len() > 0
+		}
+	}
+	renamed := clean.String()
+	if renamed == "" || !rxPortableName.MatchString(renamed) {
+		renamed = "Definition" + renamed
+	}
+	if slices.Contains(reservedDefinitionNames, renamed) {
+		renamed += collisionSuffix
+	}
+
+	candidate := renamed
+	for i := 2; taken(candidate); i++ {
+		candidate = renamed + strconv.Itoa(i)
+	}
+	return candidate
+}
+
+// renameDefinitions renames the definitions of a spec, by their current name, rewriting the $refs to them,
+// and records the original names in x-go-original-name. The renamed definitions must be free.
+func renameDefinitions(doc *spec.Swagger, renames map[string]string) error {
+	for _, name := range sortedKeys(renames) {
+		target := renames[name]
+		if err := checkDefinitionName(target); err != nil {
+			return fmt.Errorf("can't rename definition %s to %q: %w", name, target, err)
+		}
+		if _, exists := doc.Definitions[target]; exists {
+			return fmt.Errorf("can't rename definition %s to %s: %s is already defined", name, target, target)
+		}
+		definition := doc.Definitions[name]
+		if _, renamed := definition.Extensions.GetString(xGoOriginalName); !renamed {
+			definition.AddExtension(xGoOriginalName, name)
+		}
+		delete(doc.Definitions, name)
+		doc.Definitions[target] = definition
+	}
+
+	walkSpecSchemas(doc, func(sch *spec.Schema, _ string) {
+		name, ok := definitionName(sch.Ref)
+		if !ok {
+			return
+		}
+		if target, renamed := renames[name]; renamed {
+			sch.Ref = spec.MustCreateRef(definitionsPrefix + escapePointer(target))
+		}
+	})
+	return nil
+}
+
+// applyDefinitionRenames renames the definitions of Options.DefinitionRenames and, with Options.RenameCollisions,
+// those whose names break the generators. The others are reported by DiagnosticReservedName diagnostics.
+func (s *specBuilder) applyDefinitionRenames() error {
+	renames := make(map[string]string)
+	for _, name := range sortedKeys(s.ctx.opts.DefinitionRenames) {
+		if _, exists := s.input.Definitions[name]; exists {
+			renames[name] = s.ctx.opts.DefinitionRenames[name]
+		}
+	}
+
+	taken := func(candidate string) bool {
+		if _, exists := s.input.Definitions[candidate]; exists {
+			return true
+		}
+		return slices.Contains(slices.Collect(maps.Values(renames)), candidate)
+	}
+	for _, name := range sortedKeys(s.input.Definitions) {
+		if _, renamed := renames[name]; renamed {
+			continue
+		}
+		reason := definitionCollision(name)
+		if reason == "" {
+			continue
+		}
+		if s.ctx.opts.RenameCollisions {
+			renames[name] = collisionFreeName(name, taken)
+			continue
+		}
+		s.ctx.app.diagnose(Diagnostic{
+			Pos:     s.ctx.app.definitionPositions[name],
+			Code:    DiagnosticReservedName,
+			Message: fmt.Sprintf("the definition name %s %s, rename it or set RenameCollisions", name, reason),
+		})
+	}
+	if len(renames) == 0 {
+		return nil
+	}
+
+	if err := renameDefinitions(s.input, renames); err != nil {
+		return err
+	}
+	for name, target := range renames {
+		if pos, known := s.ctx.app.definitionPositions[name]; known {
+			delete(s.ctx.app.definitionPositions, name)
+			s.ctx.app.definitionPositions[target] = pos
+		}
+		if key, known := s.ctx.app.definitionTypes[name]; known {
+			delete(s.ctx.app.definitionTypes, name)
+			s.ctx.app.definitionTypes[target] = key
+		}
+		if s.inputDefinitions[name] {
+			delete(s.inputDefinitions, name)
+			s.inputDefinitions[target] = true
+		}
+	}
+	return nil
+}
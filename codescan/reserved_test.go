@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReservedNames(t *testing.T) {
+	const pkg = "github.com/3idey/codescan/fixtures/goparsing/reserved"
+
+	t.Run("should report the definition names breaking the generators", func(t *testing.T) {
+		var diagnostics []Diagnostic
+		doc, err := Run(&Options{Packages: []string{pkg}, ScanModels: true, Diagnostics: &diagnostics})
+		require.NoError(t, err)
+
+		assert.Contains(t, doc.Definitions, "Error")
+		require.Len(t, diagnostics, 3)
+		for _, diagnostic := range diagnostics {
+			assert.Equal(t, DiagnosticReservedName, diagnostic.Code)
+		}
+		assert.Equal(t, 33, diagnostics[0].Pos.Line)
+		assert.Contains(t, diagnostics[0].Message, "the definition name Client collides with an identifier generated")
+		assert.Equal(t, 10, diagnostics[1].Pos.Line)
+		assert.Contains(t, diagnostics[2].Message, "the definition name user-profile holds characters which are not valid in identifiers")
+	})
+
+	t.Run("should rename the definitions and their refs", func(t *testing.T) {
+		var diagnostics []Diagnostic
+		positions := make(map[string]token.Position)
+		doc, err := Run(&Options{
+			Packages:            []string{pkg},
+			ScanModels:          true,
+			RenameCollisions:    true,
+			DefinitionRenames:   map[string]string{"Client": "APIClient", "Unknown": "Ignored"},
+			Diagnostics:         &diagnostics,
+			DefinitionPositions: positions,
+		})
+		require.NoError(t, err)
+		assert.Empty(t, diagnostics)
+
+		assert.ElementsMatch(t, []string{"APIClient", "ErrorModel", "ErrorModel2", "userProfile"}, sortedKeys(doc.Definitions))
+		renamed := doc.Definitions["ErrorModel2"]
+		assert.Equal(t, "Error", renamed.Extensions["x-go-original-name"], "ErrorModel is taken")
+		assert.Equal(t, "#/definitions/ErrorModel2", schemaRef(renamed.Properties["cause"]))
+		assert.Equal(t, "#/definitions/ErrorModel2", doc.Responses["errorResponse"].Schema.Ref.String())
+		assert.Equal(t, "user-profile", doc.Definitions["userProfile"].Extensions["x-go-original-name"])
+		assert.Equal(t, "Client", doc.Definitions["APIClient"].Extensions["x-go-original-name"])
+		assert.NotContains(t, doc.Definitions["ErrorModel"].Extensions, "x-go-original-name")
+		assert.Equal(t, 10, positions["ErrorModel2"].Line)
+	})
+
+	t.Run("should fail on renames to existing definitions", func(t *testing.T) {
+		_, err := Run(&Options{Packages: []string{pkg}, ScanModels: true, DefinitionRenames: map[string]string{"Error": "ErrorModel"}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "can't rename definition Error to ErrorModel: ErrorModel is already defined")
+	})
+
+	t.Run("should fail on invalid renames", func(t *testing.T) {
+		err := (&Options{DefinitionRenames: map[string]string{"Error": "api/Error"}}).Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `invalid name "api/Error" for definition Error`)
+	})
+}
+
+func TestCollisionFreeName(t *testing.T) {
+	taken := func(name string) bool { return name == "PageUser" }
+	assert.Equal(t, "ResponseModel", collisionFreeName("Response", taken))
+	assert.Equal(t, "PageUser2", collisionFreeName("Page/User", taken))
+	assert.Equal(t, "Definition2xx", collisionFreeName("2xx", taken))
+	assert.Equal(t, "usersV1.User", collisionFreeName("users v1.User", taken))
+}
@@ -93,6 +93,10 @@ func (ht responseTypable) WithEnumDescription(_ string) {
 	// no
 }
 
+func (ht responseTypable) WithEnumNames(_ []string) {
+	// no
+}
+
 type headerValidations struct {
 	current *spec.Header
 }
@@ -151,6 +155,7 @@ type responseBuilder struct {
 	ctx       *scanCtx
 	decl      *entityDecl
 	postDecls []*entityDecl
+	unwrap    string // the property the body is unwrapped to, see responseUnwrap
 }
 
 func (r *responseBuilder) Build(responses map[string]spec.Response) error {
@@ -164,8 +169,14 @@ func (r *responseBuilder) Build(responses map[string]spec.Response) error {
 	debugLogf("building response: %s", name)
 
 	// analyze doc comment for the model
-	sp := new(sectionedParser)
+	sp := &sectionedParser{preserveFormat: r.ctx.opts.PreserveCommentFormat, fset: r.decl.Pkg.Fset}
 	sp.setDescription = func(lines []string) { response.Description = joinDropLast(lines) }
+	sp.taggers = []tagParser{
+		newSingleLineTagParser("unwrap", &setUnwrapOp{&r.unwrap}),
+		newMultiLineTagParser("Examples", newSetNamedExamples(r.ctx.app, r.decl.Pkg.Fset.Position(r.decl.Ident.Pos()).Filename, func(examples map[string]any) {
+			response.AddExtension(namedExamplesExtension, examples)
+		}), true),
+	}
 	if err := sp.Parse(r.decl.Comments); err != nil {
 		return err
 	}
@@ -180,6 +191,15 @@ func (r *responseBuilder) Build(responses map[string]spec.Response) error {
 	if err := r.buildFromType(r.decl.ObjType(), &response, make(map[string]bool)); err != nil {
 		return err
 	}
+	if example, found, err := r.ctx.app.exampleFile(r.decl); err != nil {
+		return err
+	} else if found {
+		if response.Examples == nil {
+			response.Examples = make(map[string]any)
+		}
+		response.Examples["application/json"] = example
+	}
+	r.ctx.app.recordPosition(&response.VendorExtensible, r.decl.Pkg.Fset.Position(r.decl.Ident.Pos()))
 	responses[name] = response
 	return nil
 }
@@ -197,7 +217,11 @@ func (r *responseBuilder) buildFromField(fld *types.Var, tpe types.Type, typable
 	case *types.Interface:
 		return r.buildFromFieldInterface(ftpe, typable)
 	case *types.Array:
-		return r.buildFromField(fld, ftpe.Elem(), typable.Items(), seen)
+		if err := r.buildFromField(fld, ftpe.Elem(), typable.Items(), seen); err != nil {
+			return err
+		}
+		setArrayLength(typable, ftpe.Len())
+		return nil
 	case *types.Slice:
 		return r.buildFromField(fld, ftpe.Elem(), typable.Items(), seen)
 	case *types.Map:
@@ -371,6 +395,18 @@ func (r *responseBuilder) buildAlias(tpe *types.Alias, resp *spec.Response, seen
 }
 
 func (r *responseBuilder) buildNamedField(ftpe *types.Named, typable swaggerTypable) error {
+	if mapping, isMapped := r.ctx.app.typeMapping(ftpe); isMapped {
+		return buildTypeMapping(mapping, typable)
+	}
+	if elem, isSet := r.ctx.app.setElement(ftpe); isSet {
+		sb := &schemaBuilder{ctx: r.ctx, decl: r.decl}
+		if err := sb.buildSet(elem, typable); err != nil {
+			return err
+		}
+		r.postDecls = append(r.postDecls, sb.postDecls...)
+		return nil
+	}
+
 	decl, found := r.ctx.DeclForType(ftpe.Obj().Type())
 	if !found {
 		return fmt.Errorf("unable to find package and source file for: %s", ftpe.String())
@@ -387,9 +423,13 @@ func (r *responseBuilder) buildNamedField(ftpe *types.Named, typable swaggerTypa
 		return nil
 	}
 
+	tpe := decl.ObjType()
+	if ftpe.TypeArgs().Len() > 0 {
+		tpe = ftpe // an instantiation of the generic type of the declaration
+	}
 	sb := &schemaBuilder{ctx: r.ctx, decl: decl}
 	sb.inferNames()
-	if err := sb.buildFromType(decl.ObjType(), typable); err != nil {
+	if err := sb.buildFromType(tpe, typable); err != nil {
 		return err
 	}
 
@@ -438,6 +478,9 @@ func (r *responseBuilder) buildFromStruct(decl *entityDecl, tpe *types.Struct, r
 
 	for i := range tpe.NumFields() {
 		fld := tpe.Field(i)
+		if fld.Embedded() && jsonIgnored(tpe.Tag(i)) {
+			continue
+		}
 		if fld.Embedded() {
 			if err := r.buildFromType(fld.Type(), resp, seen); err != nil {
 				return err
@@ -487,7 +530,7 @@ func (r *responseBuilder) buildFromStruct(decl *entityDecl, tpe *types.Struct, r
 		// scan for param location first, this changes some behavior down the line
 		if afld.Doc != nil {
 			for _, cmt := range afld.Doc.List {
-				for line := range strings.SplitSeq(cmt.Text, "\n") {
+				for line := range commentLines(cmt.Text) {
 					matches := rxIn.FindStringSubmatch(line)
 					if len(matches) > 0 && len(strings.TrimSpace(matches[1])) > 0 {
 						in = strings.TrimSpace(matches[1])
@@ -515,7 +558,7 @@ func (r *responseBuilder) buildFromStruct(decl *entityDecl, tpe *types.Struct, r
 			ps.Typed("string", strfmtName)
 		}
 
-		sp := new(sectionedParser)
+		sp := &sectionedParser{preserveFormat: r.ctx.opts.PreserveCommentFormat, ignore: r.ctx.app.ignoreItemsValidations(r.decl.Pkg.Fset, afld)}
 		sp.setDescription = func(lines []string) { ps.Description = joinDropLast(lines) }
 		sp.taggers = []tagParser{
 			newSingleLineTagParser("maximum", &setMaximum{headerValidations{&ps}, rxf(rxMaximumFmt, "")}),
@@ -525,8 +568,8 @@ func (r *responseBuilder) buildFromStruct(decl *entityDecl, tpe *types.Struct, r
 			newSingleLineTagParser("maxLength", &setMaxLength{headerValidations{&ps}, rxf(rxMaxLengthFmt, "")}),
 			newSingleLineTagParser("pattern", &setPattern{headerValidations{&ps}, rxf(rxPatternFmt, "")}),
 			newSingleLineTagParser("collectionFormat", &setCollectionFormat{headerValidations{&ps}, rxf(rxCollectionFormatFmt, "")}),
-			newSingleLineTagParser("minItems", &setMinItems{headerValidations{&ps}, rxf(rxMinItemsFmt, "")}),
-			newSingleLineTagParser("maxItems", &setMaxItems{headerValidations{&ps}, rxf(rxMaxItemsFmt, "")}),
+			newSingleLineTagParser("minItems", &setMinItems{headerValidations{&ps}, rxf(rxMinItemsFmt, ""), decl.Pkg.Types}),
+			newSingleLineTagParser("maxItems", &setMaxItems{headerValidations{&ps}, rxf(rxMaxItemsFmt, ""), decl.Pkg.Types}),
 			newSingleLineTagParser("unique", &setUnique{headerValidations{&ps}, rxf(rxUniqueFmt, "")}),
 			newSingleLineTagParser("enum", &setEnum{headerValidations{&ps}, rxf(rxEnumFmt, "")}),
 			newSingleLineTagParser("default", &setDefault{&ps.SimpleSchema, headerValidations{&ps}, rxf(rxDefaultFmt, "")}),
@@ -544,8 +587,8 @@ func (r *responseBuilder) buildFromStruct(decl *entityDecl, tpe *types.Struct, r
 				newSingleLineTagParser(fmt.Sprintf("items%dMaxLength", level), &setMaxLength{itemsValidations{items}, rxf(rxMaxLengthFmt, itemsPrefix)}),
 				newSingleLineTagParser(fmt.Sprintf("items%dPattern", level), &setPattern{itemsValidations{items}, rxf(rxPatternFmt, itemsPrefix)}),
 				newSingleLineTagParser(fmt.Sprintf("items%dCollectionFormat", level), &setCollectionFormat{itemsValidations{items}, rxf(rxCollectionFormatFmt, itemsPrefix)}),
-				newSingleLineTagParser(fmt.Sprintf("items%dMinItems", level), &setMinItems{itemsValidations{items}, rxf(rxMinItemsFmt, itemsPrefix)}),
-				newSingleLineTagParser(fmt.Sprintf("items%dMaxItems", level), &setMaxItems{itemsValidations{items}, rxf(rxMaxItemsFmt, itemsPrefix)}),
+				newSingleLineTagParser(fmt.Sprintf("items%dMinItems", level), &setMinItems{itemsValidations{items}, rxf(rxMinItemsFmt, itemsPrefix), decl.Pkg.Types}),
+				newSingleLineTagParser(fmt.Sprintf("items%dMaxItems", level), &setMaxItems{itemsValidations{items}, rxf(rxMaxItemsFmt, itemsPrefix), decl.Pkg.Types}),
 				newSingleLineTagParser(fmt.Sprintf("items%dUnique", level), &setUnique{itemsValidations{items}, rxf(rxUniqueFmt, itemsPrefix)}),
 				newSingleLineTagParser(fmt.Sprintf("items%dEnum", level), &setEnum{itemsValidations{items}, rxf(rxEnumFmt, itemsPrefix)}),
 				newSingleLineTagParser(fmt.Sprintf("items%dDefault", level), &setDefault{&items.SimpleSchema, itemsValidations{items}, rxf(rxDefaultFmt, itemsPrefix)}),
@@ -612,6 +655,7 @@ func (r *responseBuilder) buildFromStruct(decl *entityDecl, tpe *types.Struct, r
 			if resp.Headers == nil {
 				resp.Headers = make(map[string]spec.Header)
 			}
+			r.ctx.app.recordPosition(&ps.VendorExtensible, decl.Pkg.Fset.Position(fld.Pos()))
 			resp.Headers[name] = ps
 		}
 	}
@@ -625,6 +669,10 @@ func (r *responseBuilder) buildFromStruct(decl *entityDecl, tpe *types.Struct, r
 }
 
 func (r *responseBuilder) makeRef(decl *entityDecl, prop swaggerTypable) error {
+	if ref, indexed := r.ctx.app.indexedRef(decl); indexed {
+		prop.SetRef(ref)
+		return nil
+	}
 	nm, _ := decl.Names()
 	ref, err := spec.NewRef("#/definitions/" + nm)
 	if err != nil {
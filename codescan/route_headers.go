@@ -0,0 +1,179 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// HeadersTag opens the headers of a response in the Responses of swagger:route:
+//
+//	Responses:
+//	  200: orderResponse
+//	    Headers:
+//	      + name: X-Request-Id
+//	        type: string
+//	        description: the id of the request
+const HeadersTag = "headers"
+
+const (
+	// HeaderNameKey indicates the tag used to define a header name in the Responses of swagger:route.
+	HeaderNameKey = "name"
+	// HeaderTypeKey indicates the tag used to define a header type, a swagger or a Go basic type, in the Responses of swagger:route.
+	HeaderTypeKey = "type"
+	// HeaderFormatKey indicates the tag used to define a header format in the Responses of swagger:route.
+	HeaderFormatKey = "format"
+	// HeaderItemsKey indicates the tag used to define the type of the items of an array header in the Responses of swagger:route.
+	HeaderItemsKey = "items"
+	// HeaderCollectionFormatKey indicates the tag used to define the collection format of an array header in the Responses of swagger:route.
+	HeaderCollectionFormatKey = "collectionformat"
+	// HeaderDescriptionKey indicates the tag used to define a header description in the Responses of swagger:route.
+	HeaderDescriptionKey = "description"
+)
+
+// namedHeader is a header declared in the Responses of swagger:route.
+type namedHeader struct {
+	name   string
+	header spec.Header
+}
+
+// headersBlock parses the Headers of a response of swagger:route, each header starting with + name:.
+type headersBlock struct {
+	response string // status code, or default
+	headers  []namedHeader
+}
+
+// isResponseKey tells if the key of a line of Responses is a status code or default, which starts a response.
+func isResponseKey(key string) bool {
+	if strings.EqualFold(key, "default") {
+		return true
+	}
+	_, err := strconv.Atoi(key)
+	return err == nil
+}
+
+// parse parses a line of the block. It returns false when the line isn't part of the block, e.g. because it
+// starts the next response.
+func (b *headersBlock) parse(line string) (bool, error) {
+	l := strings.TrimSpace(line)
+	l, starts := strings.CutPrefix(l, "+")
+	kv := strings.SplitN(l, ":", 2)
+	if len(kv) <= 1 {
+		return true, nil
+	}
+	key := strings.ToLower(strings.TrimSpace(kv[0]))
+	value := strings.TrimSpace(kv[1])
+	if !starts && isResponseKey(key) {
+		return false, nil
+	}
+
+	if starts {
+		b.headers = append(b.headers, namedHeader{})
+	}
+	if len(b.headers) == 0 {
+		return true, fmt.Errorf("the headers of response %s start with + %s:", b.response, HeaderNameKey)
+	}
+	current := &b.headers[len(b.headers)-1]
+
+	switch key {
+	case HeaderNameKey:
+		current.name = value
+	case HeaderTypeKey:
+		tpe, format, err := headerType(value)
+		if err != nil {
+			return true, fmt.Errorf("header %s of response %s: %w", current.name, b.response, err)
+		}
+		current.header.Typed(tpe, format)
+	case HeaderFormatKey:
+		current.header.Format = value
+	case HeaderItemsKey:
+		tpe, format, err := headerType(value)
+		if err != nil || tpe == TypeArray {
+			return true, fmt.Errorf("header %s of response %s: invalid items %q", current.name, b.response, value)
+		}
+		current.header.Items = new(spec.Items).Typed(tpe, format)
+	case HeaderCollectionFormatKey:
+		current.header.CollectionFormat = value
+	case HeaderDescriptionKey:
+		current.header.Description = value
+	default:
+		return true, fmt.Errorf("header %s of response %s: unknown key %q", current.name, b.response, key)
+	}
+	return true, nil
+}
+
+// check checks the headers of the block are complete: named and typed, with the items of the arrays.
+func (b *headersBlock) check() error {
+	seen := make(map[string]bool, len(b.headers))
+	for _, h := range b.headers {
+		switch {
+		case h.name == "":
+			return fmt.Errorf("a header of response %s has no %s", b.response, HeaderNameKey)
+		case seen[strings.ToLower(h.name)]:
+			return fmt.Errorf("header %s of response %s is declared twice", h.name, b.response)
+		case h.header.Type == "":
+			return fmt.Errorf("header %s of response %s has no %s", h.name, b.response, HeaderTypeKey)
+		case h.header.Type == TypeArray && h.header.Items == nil:
+			return fmt.Errorf("array header %s of response %s has no %s", h.name, b.response, HeaderItemsKey)
+		}
+		seen[strings.ToLower(h.name)] = true
+	}
+	return nil
+}
+
+// headerType resolves the type of a header: a swagger type, or a Go basic type with its format, e.g. int32.
+func headerType(value string) (string, string, error) {
+	switch strings.ToLower(value) {
+	case TypeInteger, TypeNumber, TypeString, TypeBoolean, TypeArray:
+		return strings.ToLower(value), "", nil
+	case TypeBool:
+		return TypeBoolean, "", nil
+	}
+
+	var items spec.Items
+	if err := swaggerSchemaForType(value, itemsTypable{&items, 1, "header"}); err != nil || items.Type == TypeObject {
+		return "", "", fmt.Errorf("unsupported type %q", value)
+	}
+	return items.Type, items.Format, nil
+}
+
+// withHeaders returns a response with the headers of a block. A response referring to a swagger:response is
+// inlined, since swagger 2.0 ignores the siblings of a $ref. The headers of the route replace those of the
+// swagger:response with the same name, which are returned.
+func (ss *setOpResponses) withHeaders(resp spec.Response, block *headersBlock) (spec.Response, []string, error) {
+	if ref := resp.Ref.String(); ref != "" {
+		name := strings.TrimPrefix(ref, "#/responses/")
+		shared, known := ss.responses[name]
+		if !known {
+			return resp, nil, fmt.Errorf("response %s: the headers can't be added to %s, which isn't a swagger:response", block.response, ref)
+		}
+		description := resp.Description
+		var err error
+		if resp, err = cloneResponse(shared); err != nil {
+			return resp, nil, err
+		}
+		if description != "" {
+			resp.Description = description
+		}
+	}
+
+	var replaced []string
+	if resp.Headers == nil {
+		resp.Headers = make(map[string]spec.Header, len(block.headers))
+	}
+	for _, h := range block.headers {
+		for existing := range resp.Headers {
+			if strings.EqualFold(existing, h.name) {
+				delete(resp.Headers, existing)
+				replaced = append(replaced, h.name)
+			}
+		}
+		resp.Headers[h.name] = h.header
+	}
+	return resp, replaced, nil
+}
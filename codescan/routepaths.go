@@ -0,0 +1,129 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"regexp"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// Policies of Options.TrailingSlash.
+const (
+	// TrailingSlashKeep keeps the paths as they are written, e.g. /users/ and /users are two paths.
+	TrailingSlashKeep = "keep"
+	// TrailingSlashStrip removes the slash ending the paths, but the root path, e.g. /users/ is /users.
+	TrailingSlashStrip = "strip"
+)
+
+// rxAnnotatedPath matches the path of a swagger:route or swagger:operation annotation, whatever its form, for
+// checkAnnotatedPaths to tell what is wrong with it.
+var rxAnnotatedPath = regexp.MustCompile(`swagger:(route|operation)\p{Zs}+` + rxMethod + `\p{Zs}+(\S+)`)
+
+func checkTrailingSlash(policy string) error {
+	switch policy {
+	case "", TrailingSlashKeep, TrailingSlashStrip:
+		return nil
+	default:
+		return fmt.Errorf("unsupported trailing slash policy %q, expected %s or %s", policy, TrailingSlashKeep, TrailingSlashStrip)
+	}
+}
+
+// checkAnnotatedPaths rejects the paths of the swagger:route and swagger:operation annotations of a comment
+// group which would make an invalid or surprising spec: a path without a leading slash, which the annotation
+// would otherwise be ignored for, a path with a query string, e.g. /users?active=true, and a path declaring a
+// parameter twice, e.g. /users/{id}/pets/{id}, whatever the case of their names.
+func checkAnnotatedPaths(fset *token.FileSet, cmts *ast.CommentGroup) []error {
+	var errs []error
+	for _, cmt := range cmts.List {
+		for pos, line := range commentLinesAt(cmt) {
+			text := strings.TrimSpace(rxUncommentHeaders.ReplaceAllString(line, ""))
+			matches := rxAnnotatedPath.FindStringSubmatch(text)
+			if matches == nil || !strings.HasPrefix(text, matches[0]) {
+				continue
+			}
+			if err := checkAnnotatedPath(matches[3]); err != nil {
+				errs = append(errs, malformedAnnotation(fset.Position(pos), fmt.Sprintf("the path %q of swagger:%s %v", matches[3], matches[1], err)))
+			}
+		}
+	}
+	return errs
+}
+
+func checkAnnotatedPath(path string) error {
+	if !strings.HasPrefix(path, "/") {
+		return fmt.Errorf("must start with a slash, e.g. /%s", path)
+	}
+	stripped, _ := splitPathConstraints(path)
+	if _, query, found := strings.Cut(stripped, "?"); found {
+		return fmt.Errorf("has the query string ?%s: document the query parameters with swagger:parameters, in: query", query)
+	}
+	seen := make(map[string]string)
+	for _, param := range rxPathParam.FindAllStringSubmatch(stripped, -1) {
+		name := param[1]
+		other, found := seen[strings.ToLower(name)]
+		switch {
+		case !found:
+			seen[strings.ToLower(name)] = name
+		case other == name:
+			return fmt.Errorf("declares the path parameter {%s} twice", name)
+		default:
+			return fmt.Errorf("declares the path parameters {%s} and {%s}, which only differ by case", other, name)
+		}
+	}
+	return nil
+}
+
+// normalizePath applies the trailing slash policy of the scan to a path.
+func (a *typeIndex) normalizePath(path string) string {
+	if a == nil || a.trailingSlash != TrailingSlashStrip || path == "/" {
+		return path
+	}
+	if trimmed := strings.TrimRight(path, "/"); trimmed != "" {
+		return trimmed
+	}
+	return "/"
+}
+
+// normalizePaths applies the trailing slash policy of the scan to the paths of a spec, e.g. of the input spec
+// the scanned paths merge into, so that they compare like the scanned ones. The operations of the paths made
+// the same are merged, those of the path already normalized winning.
+func (a *typeIndex) normalizePaths(paths *spec.Paths) {
+	if a == nil || a.trailingSlash != TrailingSlashStrip || paths == nil {
+		return
+	}
+	for _, pth := range sortedKeys(paths.Paths) {
+		normalized := a.normalizePath(pth)
+		if normalized == pth {
+			continue
+		}
+		item := paths.Paths[pth]
+		delete(paths.Paths, pth)
+		if existing, found := paths.Paths[normalized]; found {
+			item = mergePathItems(existing, item)
+		}
+		paths.Paths[normalized] = item
+	}
+}
+
+// mergePathItems fills the operations and the parameters base lacks from other.
+func mergePathItems(base, other spec.PathItem) spec.PathItem {
+	ops := []struct{ base, other **spec.Operation }{
+		{&base.Get, &other.Get}, {&base.Put, &other.Put}, {&base.Post, &other.Post}, {&base.Delete, &other.Delete},
+		{&base.Options, &other.Options}, {&base.Head, &other.Head}, {&base.Patch, &other.Patch},
+	}
+	for _, op := range ops {
+		if *op.base == nil {
+			*op.base = *op.other
+		}
+	}
+	if len(base.Parameters) == 0 {
+		base.Parameters = other.Parameters
+	}
+	return base
+}
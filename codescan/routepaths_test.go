@@ -0,0 +1,128 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckAnnotatedPath(t *testing.T) {
+	for _, tc := range []struct {
+		path string
+		err  string
+	}{
+		{path: "/"},
+		{path: "/users/"},
+		{path: "/users/{ID}"},
+		{path: "/users/{id}/pets/{petID}"},
+		{path: "/users/{id:[0-9]?}"},
+		{path: "/reports/{year:[0-9]{4}}/{month}"},
+		{path: "users", err: "must start with a slash, e.g. /users"},
+		{path: "{id}", err: "must start with a slash, e.g. /{id}"},
+		{path: "/users?active=true", err: "has the query string ?active=true: document the query parameters with swagger:parameters, in: query"},
+		{path: "/users/?", err: "has the query string ?: document the query parameters with swagger:parameters, in: query"},
+		{path: "/users/{id}/pets/{id}", err: "declares the path parameter {id} twice"},
+		{path: "/users/{id:[0-9]+}/pets/{id}", err: "declares the path parameter {id} twice"},
+		{path: "/owners/{id}/pets/{ID}", err: "declares the path parameters {id} and {ID}, which only differ by case"},
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			err := checkAnnotatedPath(tc.path)
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestRoutePaths(t *testing.T) {
+	const pkg = "github.com/3idey/codescan/fixtures/goparsing/routepaths"
+
+	t.Run("should reject the paths with their positions", func(t *testing.T) {
+		file, err := filepath.Abs("../fixtures/goparsing/routepaths/invalid/api.go")
+		require.NoError(t, err)
+		_, err = Run(&Options{Packages: []string{pkg + "/invalid"}})
+		require.Error(t, err)
+		for _, msg := range []string{
+			file + `:7:1: the path "users" of swagger:route must start with a slash, e.g. /users`,
+			file + `:12:1: the path "/users?active=true" of swagger:route has the query string ?active=true`,
+			file + `:17:1: the path "/users/{id}/pets/{id}" of swagger:route declares the path parameter {id} twice`,
+			file + `:22:1: the path "/owners/{id}/pets/{ID}" of swagger:operation declares the path parameters {id} and {ID}`,
+		} {
+			assert.Contains(t, err.Error(), msg)
+		}
+
+		diagnostics, err := Precheck(&Options{Packages: []string{pkg + "/invalid"}})
+		require.NoError(t, err)
+		require.Len(t, diagnostics, 4)
+		assert.Equal(t, 7, diagnostics[0].Pos.Line)
+	})
+
+	t.Run("should keep the trailing slashes by default", func(t *testing.T) {
+		for _, policy := range []string{"", TrailingSlashKeep} {
+			doc, err := Run(&Options{Packages: []string{pkg + "/valid"}, TrailingSlash: policy})
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []string{"/", "/users/", "/users", "/users/{ID}"}, sortedKeys(doc.Paths.Paths))
+		}
+	})
+
+	t.Run("should strip the trailing slashes", func(t *testing.T) {
+		doc, err := Run(&Options{Packages: []string{pkg + "/valid"}, TrailingSlash: TrailingSlashStrip})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"/", "/users", "/users/{ID}"}, sortedKeys(doc.Paths.Paths))
+		users := doc.Paths.Paths["/users"]
+		require.NotNil(t, users.Get)
+		require.NotNil(t, users.Post)
+		assert.Equal(t, "listUsers", users.Get.ID)
+		assert.Equal(t, "createUser", users.Post.ID)
+	})
+
+	t.Run("should strip the trailing slashes of the input spec", func(t *testing.T) {
+		for _, strategy := range []string{"", MergeScanWins} {
+			input := &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+				Swagger: "2.0",
+				Paths: &spec.Paths{Paths: map[string]spec.PathItem{
+					"/users/":      {PathItemProps: spec.PathItemProps{Delete: spec.NewOperation("deleteUsers")}},
+					"/users/{ID}/": {PathItemProps: spec.PathItemProps{Put: spec.NewOperation("updateUser")}},
+				}},
+			}}
+			doc, err := Run(&Options{Packages: []string{pkg + "/valid"}, TrailingSlash: TrailingSlashStrip, InputSpec: input, MergeStrategy: strategy})
+			require.NoError(t, err)
+			assert.Equal(t, []string{"/", "/users", "/users/{ID}"}, sortedKeys(doc.Paths.Paths))
+			users := doc.Paths.Paths["/users"]
+			require.NotNil(t, users.Delete)
+			require.NotNil(t, users.Get)
+			user := doc.Paths.Paths["/users/{ID}"]
+			require.NotNil(t, user.Put)
+			require.NotNil(t, user.Get)
+		}
+	})
+
+	t.Run("should merge the operations of the paths made the same", func(t *testing.T) {
+		a := &typeIndex{trailingSlash: TrailingSlashStrip}
+		paths := &spec.Paths{Paths: map[string]spec.PathItem{
+			"/pets":   {PathItemProps: spec.PathItemProps{Get: spec.NewOperation("listPets")}},
+			"/pets/":  {PathItemProps: spec.PathItemProps{Get: spec.NewOperation("listPetsAgain"), Post: spec.NewOperation("createPet")}},
+			"/pets//": {PathItemProps: spec.PathItemProps{Head: spec.NewOperation("headPets")}},
+		}}
+		a.normalizePaths(paths)
+		require.Equal(t, []string{"/pets"}, sortedKeys(paths.Paths))
+		pets := paths.Paths["/pets"]
+		assert.Equal(t, "listPets", pets.Get.ID)
+		assert.Equal(t, "createPet", pets.Post.ID)
+		assert.Equal(t, "headPets", pets.Head.ID)
+	})
+
+	t.Run("should reject an unknown policy", func(t *testing.T) {
+		_, err := Run(&Options{Packages: []string{pkg + "/valid"}, TrailingSlash: "drop"})
+		require.EqualError(t, err, `unsupported trailing slash policy "drop", expected keep or strip`)
+		require.ErrorContains(t, (&Options{TrailingSlash: "drop"}).Validate(), "unsupported trailing slash policy")
+	})
+}
@@ -0,0 +1,814 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/go-openapi/spec"
+	"golang.org/x/tools/go/packages"
+)
+
+// Routers of Options.RouterDiscovery.
+const (
+	RouterChi  = "chi"
+	RouterGin  = "gin"
+	RouterEcho = "echo"
+)
+
+// routerFlavor describes the API of a router package, which is recognized by its name and the names of its
+// types, e.g. chi.Router, rather than by its import path, so that its major versions and forks work alike.
+type routerFlavor struct {
+	pkg   string
+	types []string
+	// methods register a route of an HTTP method, with its path and its handler, e.g. Get
+	methods map[string]string
+	// anyMethod register a route of the HTTP method of their first argument, e.g. chi's Method
+	anyMethod []string
+	// handlerLast tells that the handler is the last argument, after the middlewares, rather than the one
+	// after the path
+	handlerLast bool
+	// subrouters return a group of routes, e.g. gin's Group, with the prefix of their first argument when true
+	subrouters map[string]bool
+	// callbacks call a function with a group of routes, e.g. chi's Route, with the prefix of their first
+	// argument when true
+	callbacks map[string]bool
+	// mounts mount a router on the prefix of their first argument, e.g. chi's Mount
+	mounts []string
+}
+
+var routerFlavors = map[string]*routerFlavor{
+	RouterChi: {
+		pkg:   "chi",
+		types: []string{"Router", "Mux"},
+		methods: map[string]string{
+			"Get": "get", "Post": "post", "Put": "put", "Patch": "patch",
+			"Delete": "delete", "Head": "head", "Options": "options",
+		},
+		anyMethod:  []string{"Method", "MethodFunc"},
+		subrouters: map[string]bool{"Route": true, "Group": false, "With": false},
+		callbacks:  map[string]bool{"Route": true, "Group": false},
+		mounts:     []string{"Mount"},
+	},
+	RouterGin: {
+		pkg:   "gin",
+		types: []string{"Engine", "RouterGroup", "IRouter", "IRoutes"},
+		methods: map[string]string{
+			"GET": "get", "POST": "post", "PUT": "put", "PATCH": "patch",
+			"DELETE": "delete", "HEAD": "head", "OPTIONS": "options",
+		},
+		anyMethod:   []string{"Handle"},
+		handlerLast: true,
+		subrouters:  map[string]bool{"Group": true},
+	},
+	RouterEcho: {
+		pkg:   "echo",
+		types: []string{"Echo", "Group"},
+		methods: map[string]string{
+			"GET": "get", "POST": "post", "PUT": "put", "PATCH": "patch",
+			"DELETE": "delete", "HEAD": "head", "OPTIONS": "options",
+		},
+		anyMethod:  []string{"Add"},
+		subrouters: map[string]bool{"Group": true},
+	},
+}
+
+var routeMethods = []string{"get", "put", "post", "delete", "options", "head", "patch"}
+
+// undocumentedExtension marks the minimal operations of the routes whose handler has no doc comment, see
+// Options.IncludeUndocumented.
+const undocumentedExtension = "x-undocumented"
+
+func withRouterDiscovery(router string, includeUndocumented bool) typeIndexOption {
+	return func(a *typeIndex) {
+		a.routerFlavor = routerFlavors[router]
+		a.includeUndocumented = includeUndocumented
+	}
+}
+
+func checkRouterDiscovery(router string) error {
+	if _, known := routerFlavors[router]; router != "" && !known {
+		return fmt.Errorf("unknown router %q, expected %s, %s or %s", router, RouterChi, RouterGin, RouterEcho)
+	}
+	return nil
+}
+
+// routerRoot is a router created by the code. The paths of its routes are relative to its prefix, which is
+// the path it's mounted on, if any.
+type routerRoot struct {
+	parent *routerRoot
+	prefix string
+}
+
+// fullPrefix is the prefix of a router, with the prefixes of the routers it's mounted on.
+func (r *routerRoot) fullPrefix() string {
+	var prefix string
+	visited := make(map[*routerRoot]bool)
+	for root := r; root != nil && !visited[root]; root = root.parent {
+		visited[root] = true
+		prefix = root.prefix + prefix
+	}
+	return prefix
+}
+
+// routerRef is a router, or a group of routes of a router, with the prefix of the group.
+type routerRef struct {
+	root   *routerRoot
+	prefix string
+}
+
+// registration is a route registered with a router, whose path is relative to the root of the router.
+type registration struct {
+	method  string
+	path    string
+	group   string // the prefix of the group of the route, relative to the root of the router
+	root    *routerRoot
+	handler ast.Expr
+	call    *ast.CallExpr
+	pkg     *packages.Package
+	file    *ast.File
+}
+
+// funcSite is the declaration of a function, with its file and package.
+type funcSite struct {
+	decl *ast.FuncDecl
+	file *ast.File
+	pkg  *packages.Package
+}
+
+// routerDiscovery is the state of the discovery of the routes registered by the scanned packages.
+type routerDiscovery struct {
+	a             *typeIndex
+	flavor        *routerFlavor
+	funcs         map[*types.Func]funcSite
+	active        map[*ast.FuncDecl]bool // the functions being analyzed, which recursive calls skip
+	called        map[*ast.FuncDecl]bool // the functions analyzed for a call, rather than on their own
+	registrations []registration
+}
+
+// discoverRoutes infers the routes registered with the router of Options.RouterDiscovery by the functions
+// of the scanned packages, see inferRoutes. A function passed a router, or returning one, is analyzed for
+// each call, with the prefix of the router it's passed, or mounted on, rather than on its own.
+func (a *typeIndex) discoverRoutes() {
+	if a.routerFlavor == nil {
+		return
+	}
+	d := &routerDiscovery{
+		a:      a,
+		flavor: a.routerFlavor,
+		funcs:  make(map[*types.Func]funcSite),
+		active: make(map[*ast.FuncDecl]bool),
+		called: make(map[*ast.FuncDecl]bool),
+	}
+	for _, path := range sortedKeys(a.AllPackages) {
+		pkg := a.AllPackages[path]
+		for _, file := range pkg.Syntax {
+			for _, decl := range file.Decls {
+				if fd, isFunc := decl.(*ast.FuncDecl); isFunc && fd.Body != nil {
+					if fn, ok := pkg.TypesInfo.Defs[fd.Name].(*types.Func); ok {
+						d.funcs[fn] = funcSite{decl: fd, file: file, pkg: pkg}
+					}
+				}
+			}
+		}
+	}
+
+	type analysis struct {
+		decl          *ast.FuncDecl
+		registrations []registration
+	}
+	var analyses []analysis
+	for _, path := range sortedKeys(a.AllPackages) {
+		pkg := a.AllPackages[path]
+		if pkg.Name == d.flavor.pkg || !d.importsRouter(pkg) {
+			continue
+		}
+		if _, accepted := a.acceptsPackage(pkg); !accepted {
+			continue
+		}
+		for _, file := range pkg.Syntax {
+			if isTestFile(pkg, file) {
+				continue
+			}
+			for _, decl := range file.Decls {
+				fd, isFunc := decl.(*ast.FuncDecl)
+				if !isFunc || fd.Body == nil {
+					continue
+				}
+				d.registrations = nil
+				d.active[fd] = true
+				d.walker(pkg, file).walk(fd.Body)
+				delete(d.active, fd)
+				analyses = append(analyses, analysis{decl: fd, registrations: d.registrations})
+			}
+		}
+	}
+
+	var registrations []registration
+	for _, analyzed := range analyses {
+		if !d.called[analyzed.decl] {
+			registrations = append(registrations, analyzed.registrations...)
+		}
+	}
+	d.inferRoutes(registrations)
+}
+
+func (d *routerDiscovery) importsRouter(pkg *packages.Package) bool {
+	for _, imp := range pkg.Imports {
+		if imp.Name == d.flavor.pkg {
+			return true
+		}
+	}
+	return false
+}
+
+// isRouter tells whether a type is a router, or a pointer to one.
+func (d *routerDiscovery) isRouter(tpe types.Type) bool {
+	if tpe == nil {
+		return false
+	}
+	if ptr, isPtr := types.Unalias(tpe).(*types.Pointer); isPtr {
+		tpe = ptr.Elem()
+	}
+	named, isNamed := types.Unalias(tpe).(*types.Named)
+	if !isNamed || named.Obj().Pkg() == nil {
+		return false
+	}
+	return named.Obj().Pkg().Name() == d.flavor.pkg && slices.Contains(d.flavor.types, named.Obj().Name())
+}
+
+// routerWalker follows the routers of a function, from their creation, or from the parameters they're passed
+// in, through the variables and fields they're assigned to.
+type routerWalker struct {
+	d        *routerDiscovery
+	pkg      *packages.Package
+	file     *ast.File
+	env      map[types.Object]routerRef
+	values   map[ast.Expr]routerRef
+	done     map[*ast.CallExpr]bool
+	walked   map[*ast.FuncLit]bool
+	returned *routerRef
+}
+
+func (d *routerDiscovery) walker(pkg *packages.Package, file *ast.File) *routerWalker {
+	return &routerWalker{
+		d:      d,
+		pkg:    pkg,
+		file:   file,
+		env:    make(map[types.Object]routerRef),
+		values: make(map[ast.Expr]routerRef),
+		done:   make(map[*ast.CallExpr]bool),
+		walked: make(map[*ast.FuncLit]bool),
+	}
+}
+
+func (w *routerWalker) walk(body ast.Node) {
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.FuncLit:
+			// the callbacks of the routers are walked with the group they're passed
+			return !w.walked[node]
+		case *ast.AssignStmt:
+			if len(node.Lhs) == len(node.Rhs) {
+				for i, rhs := range node.Rhs {
+					if ref, ok := w.ref(rhs); ok {
+						w.bind(node.Lhs[i], ref)
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			if len(node.Names) == len(node.Values) {
+				for i, value := range node.Values {
+					if ref, ok := w.ref(value); ok {
+						w.bind(node.Names[i], ref)
+					}
+				}
+			}
+		case *ast.ReturnStmt:
+			for _, result := range node.Results {
+				if ref, ok := w.ref(result); ok && w.returned == nil {
+					w.returned = &ref
+				}
+			}
+		case *ast.CallExpr:
+			w.call(node)
+		}
+		return true
+	})
+}
+
+func (w *routerWalker) bind(expr ast.Expr, ref routerRef) {
+	if obj := w.object(expr); obj != nil {
+		w.env[obj] = ref
+	}
+}
+
+// object is the variable or field of an expression, if any.
+func (w *routerWalker) object(expr ast.Expr) types.Object {
+	switch expr := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		if obj := w.pkg.TypesInfo.Defs[expr]; obj != nil {
+			return obj
+		}
+		return w.pkg.TypesInfo.Uses[expr]
+	case *ast.SelectorExpr:
+		return w.pkg.TypesInfo.Uses[expr.Sel]
+	default:
+		return nil
+	}
+}
+
+// ref is the router of an expression, if it's one. A router whose origin is unknown, e.g. a parameter of a
+// function analyzed on its own, is a root.
+func (w *routerWalker) ref(expr ast.Expr) (routerRef, bool) {
+	expr = ast.Unparen(expr)
+	if ref, known := w.values[expr]; known {
+		return ref, true
+	}
+	switch e := expr.(type) {
+	case *ast.Ident, *ast.SelectorExpr:
+		if obj := w.object(e); obj != nil {
+			if ref, known := w.env[obj]; known {
+				return ref, true
+			}
+		}
+	case *ast.StarExpr:
+		return w.ref(e.X)
+	case *ast.UnaryExpr:
+		if e.Op == token.AND {
+			return w.ref(e.X)
+		}
+	case *ast.CallExpr:
+		if ref, ok := w.call(e); ok {
+			return ref, true
+		}
+	}
+	if !w.d.isRouter(w.pkg.TypesInfo.TypeOf(expr)) {
+		return routerRef{}, false
+	}
+	ref := routerRef{root: new(routerRoot)}
+	w.values[expr] = ref
+	if obj := w.object(expr); obj != nil {
+		w.env[obj] = ref
+	}
+	return ref, true
+}
+
+// call analyzes a call once, and returns the router it returns, if any.
+func (w *routerWalker) call(call *ast.CallExpr) (routerRef, bool) {
+	if w.done[call] {
+		ref, known := w.values[call]
+		return ref, known
+	}
+	w.done[call] = true
+
+	var (
+		ref routerRef
+		ok  bool
+	)
+	if name, recv, isMethod := w.routerMethod(call); isMethod {
+		ref, ok = w.routerCall(call, name, recv)
+	} else if site, isFunc := w.callee(call); isFunc {
+		ref, ok = w.analyze(site, call.Args)
+	}
+	if ok {
+		w.values[call] = ref
+	}
+	return ref, ok
+}
+
+// routerMethod tells whether a call is a call of a method of a router, with the name of the method and the
+// expression of the router.
+func (w *routerWalker) routerMethod(call *ast.CallExpr) (string, ast.Expr, bool) {
+	sel, isSel := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !isSel {
+		return "", nil, false
+	}
+	fn, isFunc := w.pkg.TypesInfo.Uses[sel.Sel].(*types.Func)
+	if !isFunc || fn.Pkg() == nil || fn.Pkg().Name() != w.d.flavor.pkg {
+		return "", nil, false
+	}
+	if sig, isSig := fn.Type().(*types.Signature); !isSig || sig.Recv() == nil {
+		return "", nil, false
+	}
+	if !w.d.isRouter(w.pkg.TypesInfo.TypeOf(sel.X)) {
+		return "", nil, false
+	}
+	return sel.Sel.Name, sel.X, true
+}
+
+func (w *routerWalker) routerCall(call *ast.CallExpr, name string, recvExpr ast.Expr) (routerRef, bool) {
+	recv, ok := w.ref(recvExpr)
+	if !ok {
+		return routerRef{}, false
+	}
+	flavor := w.d.flavor
+	args := call.Args
+	switch {
+	case flavor.methods[name] != "" || slices.Contains(flavor.anyMethod, name):
+		w.register(call, name, recv)
+		return routerRef{}, false
+	case slices.Contains(flavor.mounts, name) && len(args) == 2:
+		prefix, isConst := w.constantString(args[0])
+		mounted, isRouter := w.ref(args[1])
+		if isConst && isRouter && mounted.root != recv.root && mounted.root.parent == nil {
+			mounted.root.parent, mounted.root.prefix = recv.root, recv.prefix+prefix
+		}
+		return routerRef{}, false
+	}
+
+	group := recv
+	if prefixed := flavor.subrouters[name]; prefixed && len(args) > 0 {
+		prefix, isConst := w.constantString(args[0])
+		if !isConst {
+			debugLogf("the routes of the group at %v are skipped, since its prefix isn't a constant", w.pkg.Fset.Position(call.Pos()))
+			return routerRef{}, false
+		}
+		group.prefix += prefix
+	}
+	if prefixed, isCallback := flavor.callbacks[name]; isCallback {
+		i := 0
+		if prefixed {
+			i = 1
+		}
+		if len(args) > i {
+			w.callback(args[i], group)
+		}
+	}
+	if !w.d.isRouter(w.pkg.TypesInfo.TypeOf(call)) {
+		return routerRef{}, false
+	}
+	return group, true
+}
+
+// callback walks the function a group of routes is passed to: a function literal, or a declared function.
+func (w *routerWalker) callback(arg ast.Expr, group routerRef) {
+	switch fn := ast.Unparen(arg).(type) {
+	case *ast.FuncLit:
+		if params := fn.Type.Params.List; len(params) > 0 && len(params[0].Names) > 0 {
+			w.bind(params[0].Names[0], group)
+		}
+		w.walked[fn] = true
+		w.walk(fn.Body)
+	default:
+		if site, ok := w.funcSite(arg); ok {
+			w.values[arg] = group
+			w.analyze(site, []ast.Expr{arg})
+		}
+	}
+}
+
+// register records the route registered by a call of a method of a router, when its method and its path are
+// constants.
+func (w *routerWalker) register(call *ast.CallExpr, name string, recv routerRef) {
+	flavor := w.d.flavor
+	args := call.Args
+	method, pathArg := flavor.methods[name], 0
+	if method == "" {
+		if len(args) == 0 {
+			return
+		}
+		value, isConst := w.constantString(args[0])
+		if !isConst {
+			debugLogf("the route at %v is skipped, since its method isn't a constant", w.pkg.Fset.Position(call.Pos()))
+			return
+		}
+		method, pathArg = strings.ToLower(value), 1
+	}
+	if !slices.Contains(routeMethods, method) || len(args) < pathArg+2 {
+		return
+	}
+	path, isConst := w.constantString(args[pathArg])
+	if !isConst {
+		debugLogf("the route at %v is skipped, since its path isn't a constant", w.pkg.Fset.Position(call.Pos()))
+		return
+	}
+	handler := args[pathArg+1]
+	if flavor.handlerLast {
+		handler = args[len(args)-1]
+	}
+	w.d.registrations = append(w.d.registrations, registration{
+		method:  method,
+		path:    recv.prefix + path,
+		group:   recv.prefix,
+		root:    recv.root,
+		handler: handler,
+		call:    call,
+		pkg:     w.pkg,
+		file:    w.file,
+	})
+}
+
+func (w *routerWalker) constantString(expr ast.Expr) (string, bool) {
+	tv := w.pkg.TypesInfo.Types[expr]
+	if tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
+
+// funcSite is the declaration of the function of an expression, e.g. a function or a method value.
+func (w *routerWalker) funcSite(expr ast.Expr) (funcSite, bool) {
+	var ident *ast.Ident
+	switch expr := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		ident = expr
+	case *ast.SelectorExpr:
+		ident = expr.Sel
+	case *ast.IndexExpr:
+		return w.funcSite(expr.X)
+	default:
+		return funcSite{}, false
+	}
+	fn, isFunc := w.pkg.TypesInfo.Uses[ident].(*types.Func)
+	if !isFunc {
+		return funcSite{}, false
+	}
+	site, found := w.d.funcs[fn.Origin()]
+	return site, found
+}
+
+// callee is the function of a call passing, or returning, a router.
+func (w *routerWalker) callee(call *ast.CallExpr) (funcSite, bool) {
+	site, found := w.funcSite(call.Fun)
+	if !found {
+		return funcSite{}, false
+	}
+	if w.d.isRouter(w.pkg.TypesInfo.TypeOf(call)) {
+		return site, true
+	}
+	for _, arg := range call.Args {
+		if _, ok := w.ref(arg); ok {
+			return site, true
+		}
+	}
+	return funcSite{}, false
+}
+
+// analyze walks a function called with some arguments, with the routers they are bound to its parameters,
+// and returns the router it returns, if any.
+func (w *routerWalker) analyze(site funcSite, args []ast.Expr) (routerRef, bool) {
+	d := w.d
+	if d.active[site.decl] {
+		return routerRef{}, false
+	}
+	d.active[site.decl] = true
+	defer delete(d.active, site.decl)
+	d.called[site.decl] = true
+
+	callee := d.walker(site.pkg, site.file)
+	i := 0
+	for _, field := range site.decl.Type.Params.List {
+		for _, name := range field.Names {
+			if i < len(args) {
+				if ref, ok := w.ref(args[i]); ok {
+					callee.bind(name, ref)
+				}
+			}
+			i++
+		}
+	}
+	callee.walk(site.decl.Body)
+	if callee.returned == nil {
+		return routerRef{}, false
+	}
+	return *callee.returned, true
+}
+
+// inferRoutes adds the routes of the registrations to the routes of the annotations, unless their handler,
+// or their method and path, are documented by a swagger:route or a swagger:operation. The doc comment of the
+// handler is the body of the route, as that of a swagger:route, and its name is the ID of the operation,
+// which the swagger:parameters and swagger:response declarations are linked with. A handler which is neither
+// a declared function nor a function literal is reported with DiagnosticUnresolvedHandler, and one without a
+// doc comment with DiagnosticUndocumentedOperation.
+func (d *routerDiscovery) inferRoutes(registrations []registration) {
+	a := d.a
+	declared := make(map[string]bool)
+	documented := make(map[*ast.FuncDecl]bool)
+	ids := make(map[string]bool)
+	for _, pp := range slices.Concat(a.Routes, a.Operations) {
+		declared[strings.ToLower(pp.Method)+" "+pp.Path] = true
+		if pp.handler != nil {
+			documented[pp.handler] = true
+		}
+		ids[pp.ID] = true
+	}
+
+	for _, reg := range registrations {
+		path, constraints, ok := routePath(reg.root.fullPrefix() + reg.path)
+		pos := reg.pkg.Fset.Position(reg.call.Pos())
+		if !ok {
+			debugLogf("the route at %v is skipped, since its path %s has a wildcard", pos, reg.path)
+			continue
+		}
+		path = a.normalizePath(path)
+		key := reg.method + " " + path
+		if declared[key] {
+			continue
+		}
+		declared[key] = true
+
+		pp := parsedPathContent{
+			Method:   strings.ToUpper(reg.method),
+			Path:     path,
+			Pos:      pos,
+			inferred: true,
+
+			constraints: constraints,
+		}
+		handler := d.handler(reg)
+		switch {
+		case handler.decl != nil:
+			if documented[handler.decl] {
+				continue
+			}
+			pp.ID = handlerID(ids, handler.funcSite)
+			pp.Remaining = handler.decl.Doc
+			pp.handler, pp.file, pp.pkg = handler.decl, handler.file, handler.pkg
+		case handler.lit:
+			pp.ID = uniqueOperationID(ids, operationIDFor(reg.method, path))
+			pp.Remaining = commentBefore(reg.pkg.Fset, reg.file, reg.call.Pos())
+			pp.file, pp.pkg = reg.file, reg.pkg
+		default:
+			a.diagnose(Diagnostic{
+				Pos:  pos,
+				Code: DiagnosticUnresolvedHandler,
+				Message: fmt.Sprintf("could not resolve the handler of %s %s, which should be a function, a method value or a function literal, or be documented with swagger:route",
+					pp.Method, path),
+			})
+			continue
+		}
+		if pp.Remaining == nil && !d.undocumented(&pp, reg) {
+			continue
+		}
+
+		includeTags, excludeTags := a.includeTags, a.excludeTags
+		if forced := a.forcedPkgs[pp.pkg.PkgPath]; forced != nil {
+			includeTags, excludeTags = forced.includeTags, forced.excludeTags
+		}
+		if !a.filterTags(pp, includeTags, excludeTags) {
+			continue
+		}
+		ids[pp.ID] = true
+		a.Routes = append(a.Routes, pp)
+	}
+}
+
+// undocumented reports a route whose handler has no doc comment, and tells whether it's kept with
+// Options.IncludeUndocumented, as a minimal operation tagged after its router group, or its package.
+func (d *routerDiscovery) undocumented(pp *parsedPathContent, reg registration) bool {
+	a := d.a
+	if !a.includeUndocumented {
+		a.diagnose(Diagnostic{
+			Pos:     pp.Pos,
+			Code:    DiagnosticUndocumentedOperation,
+			Message: fmt.Sprintf("the handler of %s %s has no doc comment, and the route is left out of the spec", pp.Method, pp.Path),
+		})
+		return false
+	}
+	a.diagnose(Diagnostic{
+		Pos:  pp.Pos,
+		Code: DiagnosticUndocumentedOperation,
+		Message: fmt.Sprintf("the handler of %s %s has no doc comment, and the route is the minimal operation %s, marked %s",
+			pp.Method, pp.Path, pp.ID, undocumentedExtension),
+	})
+	pp.undocumented = true
+	pp.Tags = []string{groupTag(reg.root.fullPrefix()+reg.group, pp.pkg.Name)}
+	return true
+}
+
+// groupTag is the tag of an undocumented route: the last segment of the prefix of its router group which
+// isn't a parameter, e.g. owners for /api/owners, or the name of the package of its handler.
+func groupTag(prefix, pkgName string) string {
+	raw, _ := splitPathConstraints(prefix)
+	segments := strings.Split(raw, "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		switch segment := segments[i]; {
+		case segment == "", strings.HasPrefix(segment, "{"), strings.HasPrefix(segment, ":"), strings.HasPrefix(segment, "*"):
+			continue
+		default:
+			return segment
+		}
+	}
+	return pkgName
+}
+
+// isUndocumented tells whether an operation is the minimal operation of an undocumented route.
+func isUndocumented(op *spec.Operation) bool {
+	undocumented, _ := op.Extensions.GetBool(undocumentedExtension)
+	return undocumented
+}
+
+// resolvedHandler is the handler of a registration: a declared function, or a function literal.
+type resolvedHandler struct {
+	funcSite
+	lit bool
+}
+
+func (d *routerDiscovery) handler(reg registration) resolvedHandler {
+	w := d.walker(reg.pkg, reg.file)
+	expr := ast.Unparen(reg.handler)
+	// conversions, e.g. http.HandlerFunc(listPets)
+	for {
+		call, isCall := expr.(*ast.CallExpr)
+		if !isCall || len(call.Args) != 1 || !reg.pkg.TypesInfo.Types[call.Fun].IsType() {
+			break
+		}
+		expr = ast.Unparen(call.Args[0])
+	}
+	if _, isLit := expr.(*ast.FuncLit); isLit {
+		return resolvedHandler{lit: true}
+	}
+	site, _ := w.funcSite(expr)
+	return resolvedHandler{funcSite: site}
+}
+
+// routePath is the swagger path of the path of a route, with the path parameters of the routers, e.g.
+// :id, *path or {id:[0-9]+}, as {id}, and the regular expressions of the parameters, see
+// splitPathConstraints. The paths with a wildcard, e.g. /static/*, have no swagger path.
+func routePath(raw string) (string, map[string]string, bool) {
+	raw, constraints := splitPathConstraints(raw)
+	var segments []string
+	for segment := range strings.SplitSeq(raw, "/") {
+		switch {
+		case segment == "":
+			continue
+		case segment == "*":
+			return "", nil, false
+		case strings.HasPrefix(segment, ":"), strings.HasPrefix(segment, "*"):
+			segment = "{" + segment[1:] + "}"
+		}
+		segments = append(segments, segment)
+	}
+	return "/" + strings.Join(segments, "/"), constraints, true
+}
+
+// handlerID is the operation ID of a handler: its name, e.g. ListPets, or the name of its receiver type and
+// its own, e.g. PetsList, when its name is taken.
+func handlerID(ids map[string]bool, site funcSite) string {
+	id := site.decl.Name.Name
+	if fn, isFunc := site.pkg.TypesInfo.Defs[site.decl.Name].(*types.Func); isFunc && ids[id] {
+		if recv := fn.Type().(*types.Signature).Recv(); recv != nil {
+			tpe := recv.Type()
+			if ptr, isPtr := tpe.(*types.Pointer); isPtr {
+				tpe = ptr.Elem()
+			}
+			if named, isNamed := types.Unalias(tpe).(*types.Named); isNamed {
+				id = named.Obj().Name() + strings.ToUpper(id[:1]) + id[1:]
+			}
+		}
+	}
+	return uniqueOperationID(ids, id)
+}
+
+// uniqueOperationID is an operation ID which isn't taken yet, e.g. ListPets2 when ListPets is.
+func uniqueOperationID(ids map[string]bool, id string) string {
+	if !ids[id] {
+		return id
+	}
+	for i := 2; ; i++ {
+		if candidate := id + strconv.Itoa(i); !ids[candidate] {
+			return candidate
+		}
+	}
+}
+
+// operationIDFor is the operation ID of a route whose handler has no name, e.g. getPetsId for GET /pets/{id}.
+func operationIDFor(method, path string) string {
+	var id strings.Builder
+	id.WriteString(method)
+	upper := true
+	for _, r := range path {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		id.WriteRune(r)
+	}
+	return id.String()
+}
+
+// commentBefore is the comment group ending on the line before a position, e.g. the comment of the
+// registration of a route.
+func commentBefore(fset *token.FileSet, file *ast.File, pos token.Pos) *ast.CommentGroup {
+	line := fset.Position(pos).Line
+	for _, cg := range file.Comments {
+		if fset.Position(cg.End()).Line == line-1 {
+			return cg
+		}
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouterDiscovery(t *testing.T) {
+	const fixtures = "github.com/3idey/codescan/fixtures/goparsing/routers/"
+	scanWith := func(t *testing.T, opts *Options) *spec.Swagger {
+		t.Helper()
+		doc, err := Run(opts)
+		require.NoError(t, err)
+		return doc
+	}
+	scan := func(t *testing.T, router, app string, diagnostics *[]Diagnostic) *spec.Swagger {
+		t.Helper()
+		return scanWith(t, &Options{
+			Packages:        []string{fixtures + app},
+			RouterDiscovery: router,
+			Diagnostics:     diagnostics,
+		})
+	}
+	operationIDs := func(doc *spec.Swagger) map[string]string {
+		ids := make(map[string]string)
+		for path, item := range doc.Paths.Paths {
+			for method, op := range map[string]*spec.Operation{
+				"GET": item.Get, "POST": item.Post, "PUT": item.Put, "DELETE": item.Delete,
+			} {
+				if op != nil {
+					ids[method+" "+path] = op.ID
+				}
+			}
+		}
+		return ids
+	}
+
+	t.Run("should infer the routes of chi, through the groups and the mounts", func(t *testing.T) {
+		var diagnostics []Diagnostic
+		doc := scan(t, RouterChi, "chiapp", &diagnostics)
+
+		assert.Equal(t, map[string]string{
+			"GET /health":                  "getHealth",
+			"GET /api/v1/pets":             "ListPets",
+			"GET /api/v1/pets/{id}":        "GetPet",
+			"POST /api/v1/owners":          "CreateOwner",
+			"GET /api/v1/stores/{storeID}": "GetStore",
+			"DELETE /admin/pets/{id}":      "DeletePet",
+			"GET /explicit/{id}":           "getExplicit",
+		}, operationIDs(doc))
+
+		require.Len(t, diagnostics, 3)
+		assert.Equal(t, DiagnosticUnresolvedHandler, diagnostics[0].Code)
+		assert.Equal(t, "routes.go", filepath.Base(diagnostics[0].Pos.Filename))
+		assert.Equal(t, 29, diagnostics[0].Pos.Line)
+		assert.Contains(t, diagnostics[0].Message, "GET /api/v1/dynamic")
+		for _, diagnostic := range diagnostics[1:] {
+			assert.Equal(t, DiagnosticUndocumentedOperation, diagnostic.Code)
+			assert.Equal(t, SeverityWarning, diagnostic.Severity)
+			assert.Contains(t, diagnostic.Message, "has no doc comment, and the route is left out of the spec")
+		}
+	})
+
+	t.Run("should include the undocumented routes as minimal operations", func(t *testing.T) {
+		var (
+			diagnostics []Diagnostic
+			stats       Stats
+		)
+		doc := scanWith(t, &Options{
+			Packages:            []string{fixtures + "chiapp"},
+			RouterDiscovery:     RouterChi,
+			IncludeUndocumented: true,
+			Diagnostics:         &diagnostics,
+			Stats:               &stats,
+		})
+
+		for path, want := range map[string]struct {
+			op  *spec.Operation
+			id  string
+			tag string
+		}{
+			"/admin/stats": {doc.Paths.Paths["/admin/stats"].Get, "Stats", "admin"},
+			"/cache":       {doc.Paths.Paths["/cache"].Delete, "deleteCache", "chiapp"},
+		} {
+			require.NotNil(t, want.op, path)
+			assert.Equal(t, want.id, want.op.ID, path)
+			assert.Equal(t, []string{want.tag}, want.op.Tags, "the tag of the router group, or of the package of %s", path)
+			assert.Equal(t, true, want.op.Extensions[undocumentedExtension], path)
+			assert.Empty(t, want.op.Summary, path)
+			require.NotNil(t, want.op.Responses, path)
+			require.NotNil(t, want.op.Responses.Default, path)
+			assert.Empty(t, want.op.Responses.StatusCodeResponses, path)
+		}
+		assert.NotContains(t, doc.Paths.Paths["/api/v1/pets"].Get.Extensions, undocumentedExtension)
+
+		assert.Equal(t, 2, stats.UndocumentedOperations)
+		assert.Equal(t, 9, stats.Operations)
+		for _, tag := range stats.Tags {
+			switch tag.Tag {
+			case "admin", "chiapp":
+				assert.Equal(t, 1, tag.Undocumented, tag.Tag)
+			default:
+				assert.Zero(t, tag.Undocumented, tag.Tag)
+			}
+		}
+
+		var undocumented []string
+		for _, diagnostic := range diagnostics {
+			if diagnostic.Code == DiagnosticUndocumentedOperation {
+				undocumented = append(undocumented, diagnostic.Message)
+			}
+		}
+		assert.Equal(t, []string{
+			"the handler of GET /admin/stats has no doc comment, and the route is the minimal operation Stats, marked x-undocumented",
+			"the handler of DELETE /cache has no doc comment, and the route is the minimal operation deleteCache, marked x-undocumented",
+		}, undocumented)
+	})
+
+	t.Run("should only include the undocumented routes of the router discovery", func(t *testing.T) {
+		err := (&Options{IncludeUndocumented: true}).Validate()
+		var conflictErr *OptionsConflictError
+		require.ErrorAs(t, err, &conflictErr)
+		assert.Equal(t, []string{"IncludeUndocumented", "RouterDiscovery"}, conflictErr.Options)
+	})
+
+	t.Run("should merge the annotations of the handlers", func(t *testing.T) {
+		doc := scan(t, RouterChi, "chiapp", nil)
+
+		list := doc.Paths.Paths["/api/v1/pets"].Get
+		assert.Equal(t, "ListPets lists the pets of the store.", list.Summary)
+		require.NotNil(t, list.Responses)
+		ok := list.Responses.StatusCodeResponses[200]
+		assert.Equal(t, "#/responses/ListPets", ok.Ref.String(),
+			"the swagger:response named after the handler")
+
+		get := doc.Paths.Paths["/api/v1/pets/{id}"].Get
+		require.Len(t, get.Parameters, 1, "the swagger:parameters of the operation ID")
+		assert.Equal(t, "id", get.Parameters[0].Name)
+		assert.Equal(t, "path", get.Parameters[0].In)
+		assert.Empty(t, get.Parameters[0].Pattern, "the constraint of an integer, to which the patterns don't apply")
+		require.NotNil(t, get.Responses)
+		assert.Contains(t, get.Responses.StatusCodeResponses, 404, "the responses of the doc comment")
+
+		health := doc.Paths.Paths["/health"].Get
+		assert.Equal(t, "Health reports the health of the server.", health.Summary,
+			"a function literal is documented by the comment of its registration")
+	})
+
+	t.Run("should infer the routes of gin", func(t *testing.T) {
+		doc := scan(t, RouterGin, "ginapp", nil)
+
+		assert.Equal(t, map[string]string{
+			"GET /v1/pets/{id}": "getPet",
+			"POST /v1/pets":     "createPet",
+			"PUT /files/{path}": "putFile",
+		}, operationIDs(doc), "the routes of a function are those of its calls, and the wildcards are skipped")
+	})
+
+	t.Run("should infer the routes of echo, with the method values", func(t *testing.T) {
+		doc := scan(t, RouterEcho, "echoapp", nil)
+
+		ids := operationIDs(doc)
+		assert.Equal(t, map[string]string{
+			"GET /api/pets/{id}":  "get",
+			"POST /api/pets":      "create",
+			"GET /api/users/{id}": "usersGet",
+		}, ids)
+		assert.Equal(t, "get returns a user.", doc.Paths.Paths["/api/users/{id}"].Get.Summary)
+	})
+
+	t.Run("should leave the routers alone by default", func(t *testing.T) {
+		doc := scan(t, "", "chiapp", nil)
+
+		assert.Equal(t, map[string]string{"GET /explicit/{id}": "getExplicit"}, operationIDs(doc))
+	})
+
+	t.Run("should reject an unknown router", func(t *testing.T) {
+		err := (&Options{RouterDiscovery: "mux"}).Validate()
+		var invalidErr *InvalidOptionError
+		require.ErrorAs(t, err, &invalidErr)
+		assert.Equal(t, "RouterDiscovery", invalidErr.Option)
+	})
+}
@@ -5,6 +5,9 @@ package codescan
 
 import (
 	"fmt"
+	"go/token"
+	"net/http"
+	"strings"
 
 	"github.com/go-openapi/spec"
 )
@@ -58,6 +61,8 @@ type routesBuilder struct {
 	operations  map[string]*spec.Operation
 	responses   map[string]spec.Response
 	parameters  []*spec.Parameter
+	postDecls   []*entityDecl
+	fset        *token.FileSet // positions the errors of the blocks of a route without a package, e.g. in Precheck
 }
 
 func (r *routesBuilder) Build(tgt *spec.Paths) error {
@@ -68,24 +73,70 @@ func (r *routesBuilder) Build(tgt *spec.Paths) error {
 
 	op.Tags = r.route.Tags
 
-	sp := new(sectionedParser)
+	sp := &sectionedParser{preserveFormat: r.ctx.opts.PreserveCommentFormat, fset: r.fset}
+	if r.route.pkg != nil {
+		sp.fset = r.route.pkg.Fset
+	}
 	sp.setTitle = func(lines []string) { op.Summary = joinDropLast(lines) }
 	sp.setDescription = func(lines []string) { op.Description = joinDropLast(lines) }
 	sr := newSetResponses(r.definitions, r.responses, opResponsesSetter(op))
+	sr.resolve = r.resolveBody
+	sr.headerConflicts = func(conflicts []string) {
+		r.ctx.app.diagnose(Diagnostic{
+			Pos:  r.route.Pos,
+			Code: DiagnosticHeaderConflict,
+			Message: fmt.Sprintf("the headers of route %s replace those of their swagger:response with the same name: %s",
+				op.ID, strings.Join(conflicts, ", ")),
+		})
+	}
 	spa := newSetParams(r.parameters, opParamSetter(op))
 	sp.taggers = []tagParser{
-		newMultiLineTagParser("Consumes", newMultilineDropEmptyParser(rxConsumes, opConsumesSetter(op)), false),
-		newMultiLineTagParser("Produces", newMultilineDropEmptyParser(rxProduces, opProducesSetter(op)), false),
-		newSingleLineTagParser("Schemes", newSetSchemes(opSchemeSetter(op))),
+		newMultiLineTagParser("Consumes", newSetMediaTypes(rxConsumes, "the Consumes of route "+op.ID, opConsumesSetter(op)), false),
+		newMultiLineTagParser("Produces", newSetMediaTypes(rxProduces, "the Produces of route "+op.ID, opProducesSetter(op)), false),
+		newSingleLineTagParser("Schemes", newSetSchemes("the Schemes of route "+op.ID, opSchemeSetter(op))),
 		newMultiLineTagParser("Security", newSetSecurity(rxSecuritySchemes, opSecurityDefsSetter(op)), false),
 		newMultiLineTagParser("Parameters", spa, false),
 		newMultiLineTagParser("Responses", sr, false),
 		newSingleLineTagParser("Deprecated", &setDeprecatedOp{op}),
+		newSingleLineTagParser("Idempotent", &setIdempotentOp{op}),
+		newSingleLineTagParser("IdempotencyKey", &setIdempotencyKeyOp{op}),
+		newSingleLineTagParser("Audience", &setAudienceOp{op}),
+		newSingleLineTagParser("NoDefaultResponse", &matchOnlyParam{rx: rxNoDefaultResponse}),
+		newSingleLineTagParser("ExternalDocs", &setExternalDocs{func(docs *spec.ExternalDocumentation) { op.ExternalDocs = docs }}),
 		newMultiLineTagParser("Extensions", newSetExtensions(opExtensionsSetter(op)), true),
 	}
 	if err := sp.Parse(r.route.Remaining); err != nil {
 		return fmt.Errorf("operation (%s): %w", op.ID, err)
 	}
+	r.ctx.deprecateOperation(r.route, op)
+	r.ctx.applyPathConstraints(r.route, op)
+	if _, found := r.responses[op.ID]; found && r.route.inferred && op.Responses == nil {
+		// the swagger:response named after the handler of an inferred route is its response
+		op.Responses = &spec.Responses{ResponsesProps: spec.ResponsesProps{
+			StatusCodeResponses: map[int]spec.Response{http.StatusOK: *spec.ResponseRef("#/responses/" + op.ID)},
+		}}
+	}
+	r.ctx.app.recordPosition(&op.VendorExtensible, r.route.Pos)
+	r.ctx.app.checkIdempotencyKey(r.route.Method, op, r.route.Pos)
+	decls, err := r.ctx.inferErrorResponses(r.route, op)
+	if err != nil {
+		return err
+	}
+	r.postDecls = append(r.postDecls, decls...)
+	if decls, err = r.ctx.injectDefaultResponse(r.route, op); err != nil {
+		return err
+	}
+	r.postDecls = append(r.postDecls, decls...)
+	if r.route.undocumented {
+		// the minimal operation of a route whose handler has no doc comment, see Options.IncludeUndocumented
+		if op.Responses == nil {
+			op.Responses = &spec.Responses{ResponsesProps: spec.ResponsesProps{
+				Default: spec.NewResponse().WithDescription("undocumented response"),
+			}}
+		}
+		op.AddExtension(undocumentedExtension, true)
+	}
+	r.ctx.app.checkStatusCodes(r.route, op)
 
 	if tgt.Paths == nil {
 		tgt.Paths = make(map[string]spec.PathItem)
@@ -93,3 +144,33 @@ func (r *routesBuilder) Build(tgt *spec.Paths) error {
 	tgt.Paths[r.route.Path] = pthObj
 	return nil
 }
+
+// resolveBody resolves the Go type of the body of a response which is not a known definition, with the
+// imports of the file of the route, e.g. body:models2.User. The type is built once the routes are. An
+// unqualified name naming no Go type remains a definition name, e.g. of the input spec.
+func (r *routesBuilder) resolveBody(target string) (spec.Ref, bool, error) {
+	if r.route.pkg == nil {
+		return spec.Ref{}, false, nil
+	}
+	obj, err := r.ctx.resolveTypeRef(r.route.pkg, r.route.file, target)
+	if err != nil {
+		if !strings.Contains(target, ".") {
+			return spec.Ref{}, false, nil
+		}
+		return spec.Ref{}, false, err
+	}
+	decl, found := r.ctx.FindDecl(obj.Pkg().Path(), obj.Name())
+	if !found {
+		return spec.Ref{}, false, fmt.Errorf("type %s.%s is not a named type declaration", obj.Pkg().Path(), obj.Name())
+	}
+	if ref, indexed := r.ctx.app.indexedRef(decl); indexed {
+		return ref, true, nil
+	}
+	name, _ := decl.Names()
+	ref, err := spec.NewRef("#/definitions/" + name)
+	if err != nil {
+		return spec.Ref{}, false, err
+	}
+	r.postDecls = append(r.postDecls, decl)
+	return ref, true, nil
+}
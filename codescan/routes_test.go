@@ -406,3 +406,176 @@ func assertOperationBody(t *testing.T, op *spec.Operation, id, summary, descript
 	assert.Empty(t, rsp.Ref.String())
 	assert.Equal(t, "#/definitions/validationError", rsp.Schema.Ref.String())
 }
+
+func TestReusedResponses(t *testing.T) {
+	doc, err := Run(&Options{Packages: []string{"github.com/3idey/codescan/fixtures/goparsing/responsereuse"}})
+	require.NoError(t, err)
+
+	responses := doc.Paths.Paths["/users/{id}"].Put.Responses
+
+	t.Run("should reference the response without a suffix", func(t *testing.T) {
+		ok := responses.StatusCodeResponses[200]
+		assert.Equal(t, "#/responses/userResponse", ok.Ref.String())
+		assert.Equal(t, "The user.", doc.Responses["userResponse"].Description)
+	})
+
+	t.Run("should inline the response with a quoted description", func(t *testing.T) {
+		created := responses.StatusCodeResponses[201]
+		assert.Empty(t, created.Ref.String())
+		assert.Equal(t, "The user was created: it is found at its Location.", created.Description)
+		require.NotNil(t, created.Schema)
+		assert.Equal(t, "#/definitions/User", created.Schema.Ref.String())
+		assert.Contains(t, created.Headers, "Location")
+
+		assert.Equal(t, `The "pending" user.`, responses.StatusCodeResponses[202].Description)
+		assert.Equal(t, "The user.", doc.Responses["userResponse"].Description)
+	})
+
+	t.Run("should keep the unquoted descriptions next to the reference", func(t *testing.T) {
+		require.NotNil(t, responses.Default)
+		assert.Equal(t, "#/responses/userResponse", responses.Default.Ref.String())
+		assert.Equal(t, "legacy description", responses.Default.Description)
+	})
+}
+
+func TestResponseHeaders(t *testing.T) {
+	var diagnostics []Diagnostic
+	doc, err := Run(&Options{
+		Packages:    []string{"github.com/3idey/codescan/fixtures/goparsing/respheaders"},
+		Diagnostics: &diagnostics,
+	})
+	require.NoError(t, err)
+
+	t.Run("should document the header fields of the swagger:response", func(t *testing.T) {
+		headers := doc.Responses["ordersResponse"].Headers
+		require.Len(t, headers, 3)
+		assert.Equal(t, "The id of the request.", headers["X-Request-Id"].Description)
+		assert.Equal(t, "integer", headers["X-Total-Count"].Type)
+		assert.Equal(t, "int32", headers["X-Total-Count"].Format)
+
+		links := headers["Link"]
+		assert.Equal(t, "array", links.Type)
+		require.NotNil(t, links.Items)
+		assert.Equal(t, "string", links.Items.Type)
+		assert.Equal(t, "pipes", links.CollectionFormat)
+	})
+
+	responses := doc.Paths.Paths["/orders"].Get.Responses
+
+	t.Run("should inline the swagger:response with the headers of the route", func(t *testing.T) {
+		ok := responses.StatusCodeResponses[200]
+		assert.Empty(t, ok.Ref.String())
+		assert.Equal(t, "The orders.", ok.Description)
+		require.NotNil(t, ok.Schema)
+		assert.Equal(t, "#/definitions/Order", ok.Schema.Items.Schema.Ref.String())
+		require.Len(t, ok.Headers, 4)
+		assert.Contains(t, ok.Headers, "Link")
+
+		remaining := ok.Headers["X-Rate-Limit-Remaining"]
+		assert.Equal(t, "integer", remaining.Type)
+		assert.Equal(t, "int32", remaining.Format, "the format of the Go type")
+		assert.Equal(t, "The requests left.", remaining.Description)
+	})
+
+	t.Run("should prefer the headers of the route, with a warning", func(t *testing.T) {
+		requestID := responses.StatusCodeResponses[200].Headers["X-Request-Id"]
+		assert.Equal(t, "uuid", requestID.Format)
+		assert.Equal(t, "The id of the request, a UUID.", requestID.Description)
+		assert.Equal(t, "The id of the request.", doc.Responses["ordersResponse"].Headers["X-Request-Id"].Description,
+			"the swagger:response is left alone")
+
+		require.Len(t, diagnostics, 1)
+		assert.Equal(t, DiagnosticHeaderConflict, diagnostics[0].Code)
+		assert.Contains(t, diagnostics[0].Message, "200 X-Request-Id")
+	})
+
+	t.Run("should add the headers to the other responses", func(t *testing.T) {
+		tooMany := responses.StatusCodeResponses[429]
+		assert.Equal(t, "Too many requests.", tooMany.Description)
+		require.Len(t, tooMany.Headers, 2)
+		assert.Equal(t, "integer", tooMany.Headers["Retry-After"].Type)
+
+		hosts := tooMany.Headers["X-Retry-Hosts"]
+		assert.Equal(t, "array", hosts.Type)
+		require.NotNil(t, hosts.Items)
+		assert.Equal(t, "string", hosts.Items.Type)
+		assert.Equal(t, "csv", hosts.CollectionFormat)
+	})
+
+	t.Run("should keep referring to the swagger:response without headers", func(t *testing.T) {
+		require.NotNil(t, responses.Default)
+		assert.Equal(t, "#/responses/ordersResponse", responses.Default.Ref.String())
+		recent := doc.Paths.Paths["/orders/recent"].Get.Responses.StatusCodeResponses[200]
+		assert.Equal(t, "#/responses/ordersResponse", recent.Ref.String())
+	})
+}
+
+func TestResponseHeadersErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		lines []string
+		err   string
+	}{
+		{"headers without a response", []string{"Headers:", "+ name: X-Id", "type: string"}, "follow its status code"},
+		{"header without a name", []string{"200: description:ok", "Headers:", "type: string"}, "start with + name:"},
+		{"header without a type", []string{"200: description:ok", "Headers:", "+ name: X-Id"}, "has no type"},
+		{"array header without items", []string{"200: description:ok", "Headers:", "+ name: X-Ids", "type: array"}, "has no items"},
+		{"header of an object type", []string{"200: description:ok", "Headers:", "+ name: X-Id", "type: object"}, `unsupported type "object"`},
+		{"unknown key", []string{"200: description:ok", "Headers:", "+ name: X-Id", "required: true"}, `unknown key "required"`},
+		{"header declared twice", []string{"200: description:ok", "Headers:", "+ name: X-Id", "type: string", "+ name: x-id", "type: string"}, "declared twice"},
+		{"headers of an unknown response", []string{"200: unknownResponse", "Headers:", "+ name: X-Id", "type: string"}, "isn't a swagger:response"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sr := newSetResponses(nil, nil, func(*spec.Response, map[int]spec.Response) {})
+			err := sr.Parse(tc.lines)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
+
+func TestUndocumentedRoutes(t *testing.T) {
+	sctx := loadClassificationPkgsCtx(t)
+	require.NotEmpty(t, sctx.app.Routes)
+	route := sctx.app.Routes[0]
+	route.Remaining = nil
+	route.undocumented = true
+
+	var ops spec.Paths
+	prs := &routesBuilder{
+		ctx:        sctx,
+		route:      route,
+		operations: make(map[string]*spec.Operation),
+	}
+	require.NoError(t, prs.Build(&ops))
+
+	pathItem := ops.Paths[route.Path]
+	var built []*spec.Operation
+	for _, op := range pathItemOperations(&pathItem) {
+		built = append(built, op)
+	}
+	require.Len(t, built, 1)
+	op := built[0]
+
+	t.Run("should build a minimal operation marked x-undocumented", func(t *testing.T) {
+		assert.Equal(t, route.ID, op.ID)
+		assert.Empty(t, op.Summary)
+		assert.Equal(t, true, op.Extensions[undocumentedExtension])
+		require.NotNil(t, op.Responses)
+		require.NotNil(t, op.Responses.Default)
+		assert.Equal(t, "undocumented response", op.Responses.Default.Description)
+		assert.Empty(t, op.Responses.StatusCodeResponses)
+	})
+
+	t.Run("should count the undocumented operations", func(t *testing.T) {
+		assert.Equal(t, 1, countUndocumented(&spec.Swagger{SwaggerProps: spec.SwaggerProps{Paths: &ops}}))
+		assert.Zero(t, countUndocumented(&spec.Swagger{}))
+	})
+
+	t.Run("should warn about the undocumented operations by default", func(t *testing.T) {
+		a := &typeIndex{}
+		a.record(&Diagnostic{Code: DiagnosticUndocumentedOperation})
+		require.Len(t, a.diagnostics, 1)
+		assert.Equal(t, SeverityWarning, a.diagnostics[0].Severity)
+	})
+}
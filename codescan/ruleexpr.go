@@ -0,0 +1,484 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ruleExpr is an expression of the language of the lint rules, evaluated against an element of the spec
+// decoded as JSON, e.g.
+//
+//	method == 'DELETE' && !(responses.204 exists)
+//
+// Paths like responses.204 or parameters.0.name select the fields of the element by their JSON names, an
+// index selecting an item of an array and a name selecting the field of all the items (e.g. parameters.in).
+// The operators are ==, !=, <, <=, >, >=, contains (a substring, an item or a key), in, matches (a regular
+// expression), exists, !, && and ||, along with parentheses, quoted strings, numbers, true, false and null.
+type ruleExpr interface {
+	eval(env map[string]any) any
+}
+
+// undefined is the value of a path selecting no field.
+type undefined struct{}
+
+type literalExpr struct {
+	value any
+}
+
+func (e literalExpr) eval(map[string]any) any { return e.value }
+
+type pathExpr struct {
+	segments []string
+}
+
+func (e pathExpr) eval(env map[string]any) any {
+	var current any = env
+	for _, segment := range e.segments {
+		current = selectField(current, segment)
+	}
+	return current
+}
+
+func selectField(value any, segment string) any {
+	switch container := value.(type) {
+	case map[string]any:
+		field, ok := container[segment]
+		if !ok {
+			return undefined{}
+		}
+		return field
+	case []any:
+		if index, err := strconv.Atoi(segment); err == nil {
+			if index < 0 || index >= len(container) {
+				return undefined{}
+			}
+			return container[index]
+		}
+		var fields []any
+		for _, item := range container {
+			if field := selectField(item, segment); isDefined(field) {
+				fields = append(fields, field)
+			}
+		}
+		return fields
+	default:
+		return undefined{}
+	}
+}
+
+type existsExpr struct {
+	operand ruleExpr
+}
+
+func (e existsExpr) eval(env map[string]any) any {
+	return isDefined(e.operand.eval(env))
+}
+
+type notExpr struct {
+	operand ruleExpr
+}
+
+func (e notExpr) eval(env map[string]any) any {
+	return !truthy(e.operand.eval(env))
+}
+
+type logicalExpr struct {
+	and         bool
+	left, right ruleExpr
+}
+
+func (e logicalExpr) eval(env map[string]any) any {
+	if truthy(e.left.eval(env)) != e.and {
+		return !e.and
+	}
+	return truthy(e.right.eval(env))
+}
+
+type compareExpr struct {
+	operator    string
+	left, right ruleExpr
+	pattern     *regexp.Regexp // of matches with a literal pattern
+}
+
+func (e compareExpr) eval(env map[string]any) any {
+	left, right := e.left.eval(env), e.right.eval(env)
+	switch e.operator {
+	case "==":
+		return equalValues(left, right)
+	case "!=":
+		return !equalValues(left, right)
+	case "contains":
+		return containsValue(left, right)
+	case "in":
+		return containsValue(right, left)
+	case "matches":
+		text, isString := left.(string)
+		if !isString {
+			return false
+		}
+		pattern := e.pattern
+		if pattern == nil {
+			source, isSource := right.(string)
+			if !isSource {
+				return false
+			}
+			var err error
+			if pattern, err = regexp.Compile(source); err != nil {
+				return false
+			}
+		}
+		return pattern.MatchString(text)
+	default:
+		order, comparable := compareValues(left, right)
+		if !comparable {
+			return false
+		}
+		switch e.operator {
+		case "<":
+			return order < 0
+		case "<=":
+			return order <= 0
+		case ">":
+			return order > 0
+		default:
+			return order >= 0
+		}
+	}
+}
+
+func isDefined(value any) bool {
+	_, isUndefined := value.(undefined)
+	return !isUndefined && value != nil
+}
+
+// truthy tells if a value holds: a defined value other than false, zero, or an empty string, array or object.
+func truthy(value any) bool {
+	switch v := value.(type) {
+	case nil, undefined:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	case []any:
+		return len(v) > 0
+	case map[string]any:
+		return len(v) > 0
+	default:
+		return true
+	}
+}
+
+func equalValues(left, right any) bool {
+	if !isDefined(left) || !isDefined(right) {
+		return !isDefined(left) && !isDefined(right)
+	}
+	return reflect.DeepEqual(left, right)
+}
+
+func containsValue(container, value any) bool {
+	switch c := container.(type) {
+	case string:
+		text, isString := value.(string)
+		return isString && strings.Contains(c, text)
+	case []any:
+		for _, item := range c {
+			if equalValues(item, value) {
+				return true
+			}
+		}
+		return false
+	case map[string]any:
+		key, isString := value.(string)
+		if !isString {
+			return false
+		}
+		_, ok := c[key]
+		return ok
+	default:
+		return false
+	}
+}
+
+func compareValues(left, right any) (int, bool) {
+	switch l := left.(type) {
+	case float64:
+		r, isNumber := right.(float64)
+		if !isNumber {
+			return 0, false
+		}
+		switch {
+		case l < r:
+			return -1, true
+		case l > r:
+			return 1, true
+		default:
+			return 0, true
+		}
+	case string:
+		r, isString := right.(string)
+		if !isString {
+			return 0, false
+		}
+		return strings.Compare(l, r), true
+	default:
+		return 0, false
+	}
+}
+
+// parseRuleExpr parses an expression of a lint rule.
+func parseRuleExpr(source string) (ruleExpr, error) {
+	tokens, err := tokenizeRuleExpr(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &ruleExprParser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEnd {
+		return nil, fmt.Errorf("unexpected %s at offset %d", tok, tok.offset)
+	}
+	return expr, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenString
+	tokenNumber
+	tokenPath
+	tokenOperator
+)
+
+type ruleToken struct {
+	kind   tokenKind
+	text   string
+	value  any
+	offset int
+}
+
+func (t ruleToken) String() string {
+	if t.kind == tokenEnd {
+		return "end of expression"
+	}
+	return strconv.Quote(t.text)
+}
+
+var ruleKeywords = []string{"exists", "contains", "in", "matches", "true", "false", "null"}
+
+var ruleOperators = []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!", "(", ")"}
+
+func tokenizeRuleExpr(source string) ([]ruleToken, error) {
+	var tokens []ruleToken
+	for i := 0; i < len(source); {
+		c := rune(source[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '\'' || c == '"':
+			end := strings.IndexByte(source[i+1:], source[i])
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string at offset %d", i)
+			}
+			text := source[i : i+end+2]
+			tokens = append(tokens, ruleToken{kind: tokenString, text: text, value: text[1 : len(text)-1], offset: i})
+			i += end + 2
+		case c == '-' || unicode.IsDigit(c):
+			end := i + 1
+			for end < len(source) && (unicode.IsDigit(rune(source[end])) || source[end] == '.') {
+				end++
+			}
+			number, err := strconv.ParseFloat(source[i:end], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q at offset %d", source[i:end], i)
+			}
+			tokens = append(tokens, ruleToken{kind: tokenNumber, text: source[i:end], value: number, offset: i})
+			i = end
+		case isPathStart(c):
+			end := i + 1
+			for end < len(source) && isPathChar(rune(source[end])) {
+				end++
+			}
+			tokens = append(tokens, ruleToken{kind: tokenPath, text: source[i:end], offset: i})
+			i = end
+		default:
+			operator := ""
+			for _, candidate := range ruleOperators {
+				if strings.HasPrefix(source[i:], candidate) {
+					operator = candidate
+					break
+				}
+			}
+			if operator == "" {
+				return nil, fmt.Errorf("unexpected %q at offset %d", c, i)
+			}
+			tokens = append(tokens, ruleToken{kind: tokenOperator, text: operator, offset: i})
+			i += len(operator)
+		}
+	}
+	return append(tokens, ruleToken{kind: tokenEnd, offset: len(source)}), nil
+}
+
+func isPathStart(c rune) bool {
+	return unicode.IsLetter(c) || c == '_' || c == '$'
+}
+
+func isPathChar(c rune) bool {
+	return isPathStart(c) || unicode.IsDigit(c) || strings.ContainsRune(".-/{}~", c)
+}
+
+type ruleExprParser struct {
+	tokens []ruleToken
+	pos    int
+}
+
+func (p *ruleExprParser) peek() ruleToken {
+	return p.tokens[p.pos]
+}
+
+func (p *ruleExprParser) next() ruleToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEnd {
+		p.pos++
+	}
+	return tok
+}
+
+// accept consumes the next token when it is one of the operators or keywords.
+func (p *ruleExprParser) accept(texts ...string) (string, bool) {
+	tok := p.peek()
+	if tok.kind != tokenOperator && tok.kind != tokenPath {
+		return "", false
+	}
+	for _, text := range texts {
+		if tok.text == text {
+			p.pos++
+			return text, true
+		}
+	}
+	return "", false
+}
+
+func (p *ruleExprParser) parseOr() (ruleExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("||"); !ok {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalExpr{left: left, right: right}
+	}
+}
+
+func (p *ruleExprParser) parseAnd() (ruleExpr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("&&"); !ok {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalExpr{and: true, left: left, right: right}
+	}
+}
+
+func (p *ruleExprParser) parseUnary() (ruleExpr, error) {
+	if _, ok := p.accept("!"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *ruleExprParser) parseComparison() (ruleExpr, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := p.accept("exists"); ok {
+		return existsExpr{operand: left}, nil
+	}
+	operator, ok := p.accept("==", "!=", "<=", ">=", "<", ">", "contains", "in", "matches")
+	if !ok {
+		return left, nil
+	}
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	expr := compareExpr{operator: operator, left: left, right: right}
+	if literal, isLiteral := right.(literalExpr); isLiteral && operator == "matches" {
+		source, isString := literal.value.(string)
+		if !isString {
+			return nil, errors.New("matches takes a regular expression")
+		}
+		if expr.pattern, err = regexp.Compile(source); err != nil {
+			return nil, err
+		}
+	}
+	return expr, nil
+}
+
+func (p *ruleExprParser) parseOperand() (ruleExpr, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenString, tokenNumber:
+		return literalExpr{value: tok.value}, nil
+	case tokenPath:
+		switch tok.text {
+		case "true":
+			return literalExpr{value: true}, nil
+		case "false":
+			return literalExpr{value: false}, nil
+		case "null":
+			return literalExpr{value: nil}, nil
+		}
+		for _, keyword := range ruleKeywords {
+			if tok.text == keyword {
+				return nil, fmt.Errorf("unexpected %s at offset %d", tok, tok.offset)
+			}
+		}
+		return pathExpr{segments: strings.Split(tok.text, ".")}, nil
+	case tokenOperator:
+		if tok.text == "(" {
+			expr, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := p.accept(")"); !ok {
+				tok := p.peek()
+				return nil, fmt.Errorf("expected \")\" at offset %d, got %s", tok.offset, tok)
+			}
+			return expr, nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected %s at offset %d", tok, tok.offset)
+}
@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuleExpr(t *testing.T) {
+	env := map[string]any{
+		"method": "DELETE",
+		"tags":   []any{"pets", "admin"},
+		"responses": map[string]any{
+			"204": map[string]any{"description": "deleted"},
+		},
+		"parameters": []any{
+			map[string]any{"name": "id", "in": "path"},
+			map[string]any{"name": "X-Request-ID", "in": "header"},
+		},
+		"x-audience": "internal",
+		"deprecated": false,
+		"maxLength":  float64(10),
+	}
+
+	for _, tc := range []struct {
+		expr     string
+		expected bool
+	}{
+		{"method == 'DELETE'", true},
+		{`method != "DELETE"`, false},
+		{"responses.204 exists", true},
+		{"responses.404 exists", false},
+		{"!(responses.404 exists)", true},
+		{"responses.204.description == 'deleted'", true},
+		{"tags contains 'admin'", true},
+		{"'pets' in tags", true},
+		{"parameters.in contains 'header'", true},
+		{"parameters.1.name == 'X-Request-ID'", true},
+		{"parameters.5.name exists", false},
+		{"responses contains '204'", true},
+		{"method contains 'LET'", true},
+		{"method matches '^(PUT|DELETE)$'", true},
+		{"x-audience == 'internal' && !deprecated", true},
+		{"deprecated || maxLength >= 10", true},
+		{"maxLength < 10 || maxLength > 10", false},
+		{"maxLength <= -1", false},
+		{"summary == null", true},
+		{"summary", false},
+		{"method < 10", false},
+	} {
+		expr, err := parseRuleExpr(tc.expr)
+		require.NoError(t, err, tc.expr)
+		assert.Equal(t, tc.expected, truthy(expr.eval(env)), tc.expr)
+	}
+}
+
+func TestRuleExprErrors(t *testing.T) {
+	for _, tc := range []struct {
+		expr string
+		err  string
+	}{
+		{"method ==", "unexpected end of expression at offset 9"},
+		{"method == 'DELETE", "unterminated string at offset 10"},
+		{"(method == 'GET'", `expected ")" at offset 16`},
+		{"method == 'GET' responses", `unexpected "responses" at offset 16`},
+		{"method matches '('", "error parsing regexp"},
+		{"method matches 10", "matches takes a regular expression"},
+		{"method = 'GET'", `unexpected '=' at offset 7`},
+		{"exists", `unexpected "exists" at offset 0`},
+	} {
+		_, err := parseRuleExpr(tc.expr)
+		require.Error(t, err, tc.expr)
+		assert.Contains(t, err.Error(), tc.err, tc.expr)
+	}
+}
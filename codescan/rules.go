@@ -0,0 +1,267 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/token"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// Severities of the diagnostics, see Rule.
+const (
+	// SeverityError fails a lint report. It is the severity of the diagnostics by default.
+	SeverityError = "error"
+	// SeverityWarning is reported, without failing a lint report.
+	SeverityWarning = "warning"
+	// SeverityOff disables a rule.
+	SeverityOff = "off"
+)
+
+// Elements of the spec matched by the rules.
+const (
+	RuleMatchOperation  = "operation"
+	RuleMatchDefinition = "definition"
+	RuleMatchParameter  = "parameter"
+	RuleMatchResponse   = "response"
+)
+
+// Rule is a lint rule, reported with diagnostics.
+//
+// A rule with a Match checks the elements of the built spec: those selected by Where must satisfy Require,
+// both expressions evaluated against the element decoded as JSON (see the README for the language), e.g.
+//
+//	Rule{Match: "operation", Where: "method == 'DELETE'", Require: "responses.204 exists"}
+//
+// The operations also have a method and a path, the definitions and the responses a name, and the parameters
+// the method, path and operationId of their operation. A rule without a Match configures the built-in
+// diagnostics named by its Name, e.g. Rule{Name: DiagnosticSkippedField, Severity: SeverityOff}.
+type Rule struct {
+	Name     string // code of the diagnostics of the rule, "rule-<n>" by default for the n-th rule
+	Match    string // RuleMatchOperation, RuleMatchDefinition, RuleMatchParameter, RuleMatchResponse or empty
+	Where    string // expression selecting the elements checked, all of them when empty
+	Require  string // expression the elements checked must satisfy
+	Severity string // SeverityError (default), SeverityWarning or SeverityOff
+	Message  string // message of the diagnostics, explaining the rule by default
+}
+
+// diagnosticCodes are the codes of the built-in diagnostics, which rules without a Match configure.
+var diagnosticCodes = []string{
+	DiagnosticEmptySchema, DiagnosticSkippedField, DiagnosticUnindexedDefinition, DiagnosticMissingIdempotencyKey,
+	DiagnosticJSONNameConflict, DiagnosticInvalidStrfmt, DiagnosticUnknownFormat, DiagnosticUndocumentedStatus,
+	DiagnosticUnwrittenStatus, DiagnosticMalformedAnnotation, DiagnosticDuplicateParameter,
+	DiagnosticSecret, DiagnosticUnregisteredSet, DiagnosticReservedName, DiagnosticInvalidSuppression,
+	DiagnosticDynamicMediaType, DiagnosticHeaderConflict, DiagnosticUnsupportedSchemaKeyword,
+	DiagnosticUnresolvedHandler, DiagnosticUndocumentedOperation,
+	DiagnosticOverriddenProperty, DiagnosticUnsupportedType, DiagnosticDuplicateOperationID, DiagnosticUnresolvedRef,
+	DiagnosticUnsecuredOperation, DiagnosticDefinitionNameCollision, DiagnosticCompositeParameter,
+	DiagnosticOpaqueMarshaler, DiagnosticLegacyAnnotation, DiagnosticPathConstraint,
+	DiagnosticInvalidExample, DiagnosticLargeEnum, DiagnosticFormConsumes, DiagnosticUnsupportedOpenAPI3,
+	DiagnosticUnsupportedSwagger2,
+}
+
+var ruleMatches = []string{RuleMatchOperation, RuleMatchDefinition, RuleMatchParameter, RuleMatchResponse}
+
+var severities = []string{SeverityError, SeverityWarning, SeverityOff}
+
+type compiledRule struct {
+	Rule
+	where   ruleExpr
+	require ruleExpr
+}
+
+// compileRules checks the rules and parses their expressions. It returns the rules checking the spec, and
+// the severities of the built-in diagnostics.
+func compileRules(rules []Rule) ([]compiledRule, map[string]string, error) {
+	var compiled []compiledRule
+	configured := make(map[string]string)
+	var names []string
+	var errs []error
+	for i, rule := range rules {
+		if rule.Name == "" {
+			rule.Name = "rule-" + strconv.Itoa(i+1)
+		}
+		if rule.Severity == "" {
+			rule.Severity = SeverityError
+		}
+		fail := func(format string, args ...any) {
+			errs = append(errs, fmt.Errorf("rule %s: %s", rule.Name, fmt.Sprintf(format, args...)))
+		}
+
+		if slices.Contains(names, rule.Name) {
+			fail("the rule is already defined")
+			continue
+		}
+		names = append(names, rule.Name)
+		if !slices.Contains(severities, rule.Severity) {
+			fail("unknown severity %q, expected one of %s", rule.Severity, strings.Join(severities, ", "))
+			continue
+		}
+
+		if rule.Match == "" {
+			switch {
+			case !slices.Contains(diagnosticCodes, rule.Name):
+				fail("no built-in diagnostic has this code, and the rule matches no element")
+			case rule.Where != "" || rule.Require != "":
+				fail("a built-in diagnostic is only configured with a severity and a message")
+			default:
+				configured[rule.Name] = rule.Severity
+			}
+			continue
+		}
+		if !slices.Contains(ruleMatches, rule.Match) {
+			fail("unknown match %q, expected one of %s", rule.Match, strings.Join(ruleMatches, ", "))
+			continue
+		}
+		if slices.Contains(diagnosticCodes, rule.Name) {
+			fail("the name is the code of a built-in diagnostic")
+			continue
+		}
+		if rule.Require == "" {
+			fail("the rule requires nothing")
+			continue
+		}
+
+		compiledRule := compiledRule{Rule: rule}
+		var err error
+		if rule.Where != "" {
+			if compiledRule.where, err = parseRuleExpr(rule.Where); err != nil {
+				fail("invalid where: %v", err)
+				continue
+			}
+		}
+		if compiledRule.require, err = parseRuleExpr(rule.Require); err != nil {
+			fail("invalid require: %v", err)
+			continue
+		}
+		compiled = append(compiled, compiledRule)
+	}
+	return compiled, configured, errors.Join(errs...)
+}
+
+// ruleElement is an element of the spec checked by the rules.
+type ruleElement struct {
+	location string // JSON pointer, e.g. "/paths/~1pets/get"
+	subject  string // e.g. "operation listPets (GET /pets)"
+	env      map[string]any
+}
+
+// checkRules reports, with diagnostics, the elements of a spec which don't satisfy the rules. Positions are
+// found by JSON pointer of the elements, from the source map of the spec.
+func (a *typeIndex) checkRules(rules []compiledRule, doc *spec.Swagger, positions map[string]token.Position) error {
+	if len(rules) == 0 {
+		return nil
+	}
+	elements, err := ruleElements(doc)
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range rules {
+		if rule.Severity == SeverityOff {
+			continue
+		}
+		for _, element := range elements[rule.Match] {
+			if rule.where != nil && !truthy(rule.where.eval(element.env)) {
+				continue
+			}
+			if truthy(rule.require.eval(element.env)) {
+				continue
+			}
+			message := rule.Message
+			if message == "" {
+				message = fmt.Sprintf("requires %s", rule.Require)
+			}
+			diagnostic := Diagnostic{
+				Pos:      positions[element.location],
+				Code:     rule.Name,
+				Message:  element.subject + ": " + message,
+				Severity: rule.Severity,
+			}
+			if a.suppress(&diagnostic) {
+				continue
+			}
+			a.diagnostics = append(a.diagnostics, diagnostic)
+			a.warn(diagnostic)
+		}
+	}
+	return nil
+}
+
+// ruleElements returns the elements of a spec, by match.
+func ruleElements(doc *spec.Swagger) (map[string][]ruleElement, error) {
+	elements := make(map[string][]ruleElement)
+	add := func(match, location, subject string, value any, fields map[string]any) error {
+		env, err := ruleEnv(value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", subject, err)
+		}
+		for key, field := range fields {
+			env[key] = field
+		}
+		elements[match] = append(elements[match], ruleElement{location: location, subject: subject, env: env})
+		return nil
+	}
+
+	for _, name := range sortedKeys(doc.Definitions) {
+		fields := map[string]any{"name": name}
+		if err := add(RuleMatchDefinition, definitionsPrefix[1:]+escapePointer(name), "definition "+name, doc.Definitions[name], fields); err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range sortedKeys(doc.Parameters) {
+		fields := map[string]any{"name": name}
+		if err := add(RuleMatchParameter, parametersPrefix[1:]+escapePointer(name), "parameter "+name, doc.Parameters[name], fields); err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range sortedKeys(doc.Responses) {
+		fields := map[string]any{"name": name}
+		if err := add(RuleMatchResponse, responsesPrefix[1:]+escapePointer(name), "response "+name, doc.Responses[name], fields); err != nil {
+			return nil, err
+		}
+	}
+	if doc.Paths == nil {
+		return elements, nil
+	}
+
+	for _, pth := range sortedKeys(doc.Paths.Paths) {
+		pathItem := doc.Paths.Paths[pth]
+		for method, op := range pathItemOperations(&pathItem) {
+			location := "/paths/" + escapePointer(pth) + "/" + method
+			method = strings.ToUpper(method)
+			subject := fmt.Sprintf("operation %s (%s %s)", op.ID, method, pth)
+			if err := add(RuleMatchOperation, location, subject, op, map[string]any{"method": method, "path": pth}); err != nil {
+				return nil, err
+			}
+			for i, param := range op.Parameters {
+				fields := map[string]any{"method": method, "path": pth, "operationId": op.ID}
+				paramSubject := fmt.Sprintf("parameter %s of %s", param.Name, subject)
+				if err := add(RuleMatchParameter, location+"/parameters/"+strconv.Itoa(i), paramSubject, param, fields); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return elements, nil
+}
+
+// ruleEnv decodes an element of the spec as JSON, for the expressions of the rules.
+func ruleEnv(value any) (map[string]any, error) {
+	jazon, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	env := make(map[string]any)
+	if err := json.Unmarshal(jazon, &env); err != nil {
+		return nil, err
+	}
+	return env, nil
+}
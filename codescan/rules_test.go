@@ -0,0 +1,120 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"io"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRules(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	scan := func(t *testing.T, rules ...Rule) []Diagnostic {
+		t.Helper()
+		var diagnostics []Diagnostic
+		_, err := Run(&Options{
+			Packages:    []string{"github.com/3idey/codescan/fixtures/goparsing/rules"},
+			ScanModels:  true,
+			Diagnostics: &diagnostics,
+			Rules:       rules,
+		})
+		require.NoError(t, err)
+		return diagnostics
+	}
+
+	t.Run("should report the elements not satisfying a rule", func(t *testing.T) {
+		diagnostics := scan(t, Rule{
+			Name:     "delete-no-content",
+			Match:    RuleMatchOperation,
+			Where:    "method == 'DELETE'",
+			Require:  "responses.204 exists",
+			Severity: SeverityWarning,
+		})
+
+		reported := diagnosticsWithRuleCode(diagnostics, "delete-no-content")
+		require.Len(t, reported, 1)
+		assert.Equal(t, "operation deletePet (DELETE /pets/{id}): requires responses.204 exists", reported[0].Message)
+		assert.Equal(t, SeverityWarning, reported[0].Severity)
+		assert.Equal(t, 23, reported[0].Pos.Line)
+	})
+
+	t.Run("should report with the message and default name of a rule", func(t *testing.T) {
+		diagnostics := scan(t, Rule{
+			Match:   RuleMatchDefinition,
+			Require: "title exists",
+			Message: "models are documented",
+		}, Rule{
+			Match:   RuleMatchDefinition,
+			Where:   "name == 'Order'",
+			Require: "properties.quantity.description matches '^the '",
+		})
+
+		reported := diagnosticsWithRuleCode(diagnostics, "rule-1")
+		assert.Empty(t, reported)
+		assert.Empty(t, diagnosticsWithRuleCode(diagnostics, "rule-2"))
+
+		diagnostics = scan(t, Rule{
+			Match:   RuleMatchDefinition,
+			Require: "required contains 'name'",
+			Message: "models have a required name",
+		})
+		reported = diagnosticsWithRuleCode(diagnostics, "rule-1")
+		require.Len(t, reported, 2)
+		assert.Equal(t, "definition Order: models have a required name", reported[0].Message)
+		assert.Equal(t, "definition Pet: models have a required name", reported[1].Message)
+		assert.Equal(t, SeverityError, reported[0].Severity)
+	})
+
+	t.Run("should set the severity of the built-in diagnostics", func(t *testing.T) {
+		skipped := diagnosticsWithRuleCode(scan(t), DiagnosticSkippedField)
+		require.Len(t, skipped, 1)
+		assert.Equal(t, SeverityError, skipped[0].Severity)
+
+		skipped = diagnosticsWithRuleCode(scan(t, Rule{Name: DiagnosticSkippedField, Severity: SeverityWarning}), DiagnosticSkippedField)
+		require.Len(t, skipped, 1)
+		assert.Equal(t, SeverityWarning, skipped[0].Severity)
+
+		assert.Empty(t, diagnosticsWithRuleCode(scan(t, Rule{Name: DiagnosticSkippedField, Severity: SeverityOff}), DiagnosticSkippedField))
+	})
+
+	t.Run("should reject invalid rules", func(t *testing.T) {
+		for _, tc := range []struct {
+			rule Rule
+			err  string
+		}{
+			{Rule{Name: "unknown"}, "rule unknown: no built-in diagnostic has this code"},
+			{Rule{Name: DiagnosticSkippedField, Require: "name exists"}, "only configured with a severity"},
+			{Rule{Match: "path", Require: "name exists"}, `rule rule-1: unknown match "path"`},
+			{Rule{Match: RuleMatchOperation}, "rule rule-1: the rule requires nothing"},
+			{Rule{Match: RuleMatchOperation, Require: "name exists", Severity: "fatal"}, `unknown severity "fatal"`},
+			{Rule{Match: RuleMatchOperation, Where: "method ==", Require: "name exists"}, "invalid where: unexpected end of expression"},
+			{Rule{Name: DiagnosticEmptySchema, Match: RuleMatchOperation, Require: "name exists"}, "the code of a built-in diagnostic"},
+		} {
+			err := (&Options{Rules: []Rule{tc.rule}}).Validate()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.err)
+		}
+
+		err := (&Options{Rules: []Rule{{Name: "twice", Match: RuleMatchResponse, Require: "name exists"}, {Name: "twice", Match: RuleMatchResponse, Require: "name exists"}}}).Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "rule twice: the rule is already defined")
+	})
+}
+
+func diagnosticsWithRuleCode(diagnostics []Diagnostic, code string) []Diagnostic {
+	var result []Diagnostic
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Code == code {
+			result = append(result, diagnostic)
+		}
+	}
+	return result
+}
@@ -0,0 +1,204 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"regexp"
+	"strings"
+)
+
+// annotationVerbs are the annotations classified by detectNodes, which the unknown ones are compared with.
+var annotationVerbs = []string{
+	"route", "operation", "path", "tag", "model", "meta", "parameters", "response",
+	"strfmt", "name", "discriminated", "discriminator", "file", "enum", "default", "alias", "type", "example",
+	"allOf", "allOfRef", "ignore", "deprecated",
+}
+
+var (
+	// rxMisspelledPrefix matches the annotations starting a line whose swagger: prefix may be misspelled, e.g. swager:route.
+	rxMisspelledPrefix = regexp.MustCompile(`^[\p{Zs}\t/\*-]*(\p{L}+):(route|operation|parameters|response|model|meta|path|tag)\b`)
+	// rxNumericValidation matches the validations of fields with a numeric value, e.g. "Maximum: abc".
+	rxNumericValidation = regexp.MustCompile(`^[\p{Zs}\t/\*-]*(?:[Ii]tems[\.\p{Zs}]*)*((?:[Mm]ax|[Mm]in)(?:imum)?(?:\p{Zs}*[\p{Pd}\p{Pc}]?[Ll]en(?:gth)?|(?:\p{Zs}*|[\p{Pd}\p{Pc}]|\.)?[Ii]tems)?|[Mm]ultiple\p{Zs}*[Oo]f)\p{Zs}*:\p{Zs}*([^\p{Zs}]+)\p{Zs}*$`)
+	rxNumber            = regexp.MustCompile(`^(?:[\<\>]?=?)[\+-]?(?:\p{N}+\.)?\p{N}+$`)
+	rxInteger           = regexp.MustCompile(`^\p{N}+$`)
+	rxItemsCount        = regexp.MustCompile(`^(?:\p{N}+|\{[\p{L}_][\p{L}\p{N}_]*(?:\.[\p{L}_][\p{L}\p{N}_]*)?\})$`)
+)
+
+// closestAnnotation returns the annotation an unknown one is at most 2 edits away from, e.g. route for rotue.
+func closestAnnotation(name string) string {
+	best, bestDistance := "", 3
+	for _, verb := range annotationVerbs {
+		if distance := editDistance(name, verb); distance < bestDistance {
+			best, bestDistance = verb, distance
+		}
+	}
+	return best
+}
+
+// diagnoseMisspelledPrefixes reports the annotations of a comment whose prefix is at most 2 edits away from
+// swagger:, e.g. swager:route or Swagger:model, which the scan ignores.
+func (a *typeIndex) diagnoseMisspelledPrefixes(fset *token.FileSet, cmt *ast.Comment) {
+	for line := range commentLines(cmt.Text) {
+		matches := rxMisspelledPrefix.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		prefix := matches[1]
+		if prefix == "swagger" || editDistance(strings.ToLower(prefix), "swagger") > 2 {
+			continue
+		}
+		a.diagnose(malformedAnnotation(fset.Position(cmt.Slash),
+			fmt.Sprintf("%s:%s is ignored, did you mean swagger:%s? - %s", prefix, matches[2], matches[2], strings.TrimSpace(line))))
+	}
+}
+
+// diagnosePathAnnotations reports the path annotations starting a line of a comment without the form Run
+// expects, which the scan ignores, e.g. a swagger:route without operation ID. The annotations mentioned in
+// the prose of the comments are left alone.
+func (a *typeIndex) diagnosePathAnnotations(fset *token.FileSet, cmts *ast.CommentGroup) {
+	for _, annotation := range pathAnnotations {
+		if pos, found := leadingAnnotationPos(cmts, annotation.name); found && !hasMatch(cmts, annotation.rx) {
+			a.diagnose(malformedAnnotation(fset.Position(pos),
+				fmt.Sprintf("malformed swagger:%s annotation, expected %s", annotation.name, annotation.expected)))
+		}
+	}
+}
+
+// leadingAnnotationPos finds a swagger annotation starting a line of a comment group.
+func leadingAnnotationPos(cmts *ast.CommentGroup, name string) (token.Pos, bool) {
+	for _, cmt := range cmts.List {
+		for line := range commentLines(cmt.Text) {
+			text := strings.TrimSpace(rxUncommentHeaders.ReplaceAllString(line, ""))
+			if matches := rxSwaggerAnnotation.FindStringSubmatch(text); matches != nil && matches[1] == name && strings.HasPrefix(text, matches[0]) {
+				return cmt.Slash, true
+			}
+		}
+	}
+	return token.NoPos, false
+}
+
+// malformedValidations returns the validations of the fields of a file with a value the parsers don't match,
+// e.g. "Maximum: abc", which would otherwise be left out of the schema.
+func malformedValidations(fset *token.FileSet, file *ast.File) []error {
+	var errs []error
+	ast.Inspect(file, func(node ast.Node) bool {
+		field, isField := node.(*ast.Field)
+		if !isField || field.Doc == nil {
+			return true
+		}
+		for _, cmt := range field.Doc.List {
+			for line := range commentLines(cmt.Text) {
+				matches := rxNumericValidation.FindStringSubmatch(line)
+				if matches == nil {
+					continue
+				}
+				keyword, value := matches[1], matches[2]
+				expected, rx := "a number", rxNumber
+				switch lower := strings.ToLower(keyword); {
+				case strings.Contains(lower, "len"):
+					expected, rx = "a positive integer", rxInteger
+				case strings.Contains(lower, "items"):
+					expected, rx = "a positive integer or a {Constant}", rxItemsCount
+				}
+				if rx.MatchString(value) {
+					continue
+				}
+				errs = append(errs, malformedAnnotation(fset.Position(cmt.Slash),
+					fmt.Sprintf("malformed %s of the field %s, expected %s - %s", keyword, fieldName(field), expected, strings.TrimSpace(rxUncommentHeaders.ReplaceAllString(line, "")))))
+			}
+		}
+		return true
+	})
+	return errs
+}
+
+// fieldName is the name of a field, or of the type of an embedded field.
+func fieldName(field *ast.Field) string {
+	if len(field.Names) > 0 {
+		return field.Names[0].Name
+	}
+	expr := field.Type
+	if star, isPointer := expr.(*ast.StarExpr); isPointer {
+		expr = star.X
+	}
+	if selector, isSelector := expr.(*ast.SelectorExpr); isSelector {
+		return selector.Sel.Name
+	}
+	if ident, isIdent := expr.(*ast.Ident); isIdent {
+		return ident.Name
+	}
+	return "embedded"
+}
+
+// joinedErrors returns the errors joined by errors.Join, or the error alone.
+func joinedErrors(err error) []error {
+	if joined, isJoined := err.(interface{ Unwrap() []error }); isJoined {
+		return joined.Unwrap()
+	}
+	return []error{err}
+}
+
+// rxPositioned matches the errors starting with their position, e.g. "models.go:12:6: ...".
+var rxPositioned = regexp.MustCompile(`^(?:\p{L}:)?[^:\n]+:\d+(?::\d+)?: `)
+
+// collect records the error of the build of a declaration, prefixed with its position unless positioned
+// already, to report it with the others once the declarations are built. It returns the error with
+// Options.FailFast, or once the scan is canceled.
+func (s *specBuilder) collect(pos token.Position, subject string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || s.ctx.canceled() != nil {
+		return err
+	}
+	var diagnostic Diagnostic
+	if !errors.As(err, &diagnostic) && !rxPositioned.MatchString(err.Error()) {
+		err = fmt.Errorf("%v: %s: %w", pos, subject, err)
+	}
+	if s.ctx.opts != nil && s.ctx.opts.FailFast {
+		return err
+	}
+	s.errs = append(s.errs, err)
+	return nil
+}
+
+// checkErrors fails with the mistakes of the annotations of the files and the errors of the declarations,
+// after a summary when there are several of them.
+func (s *specBuilder) checkErrors() error {
+	errs := append(append([]error(nil), s.ctx.app.annotationErrors...), s.errs...)
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	default:
+		return errors.Join(append([]error{fmt.Errorf("%d errors in the annotations", len(errs))}, errs...)...)
+	}
+}
+
+// checkWarnings fails with the diagnostics reported by the scan with Options.FailOnWarning.
+func (a *typeIndex) checkWarnings(failOnWarning bool) error {
+	if !failOnWarning {
+		return nil
+	}
+	reported := a.reportedDiagnostics()
+	if len(reported) == 0 {
+		return nil
+	}
+
+	summary := fmt.Sprintf("%d warnings, see FailOnWarning", len(reported))
+	if len(reported) == 1 {
+		summary = "1 warning, see FailOnWarning"
+	}
+	errs := []error{errors.New(summary)}
+	for _, diagnostic := range reported {
+		errs = append(errs, diagnostic)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mistakesFixture = "github.com/3idey/codescan/fixtures/goparsing/mistakes"
+
+func TestAnnotationMistakes(t *testing.T) {
+	t.Run("should report all the mistakes, each at its position", func(t *testing.T) {
+		dir, err := filepath.Abs(filepath.Join("..", "fixtures", "goparsing", "mistakes"))
+		require.NoError(t, err)
+		_, err = Run(&Options{Packages: []string{mistakesFixture}, ScanModels: true})
+		require.Error(t, err)
+
+		lines := strings.Split(err.Error(), "\n")
+		assert.Equal(t, "5 errors in the annotations", lines[0])
+		var mistakes []string
+		for _, line := range lines[1:] {
+			mistakes = append(mistakes, strings.TrimPrefix(line, dir+string(filepath.Separator)))
+		}
+		assert.ElementsMatch(t, []string{
+			`api.go:16:1: classifier: unknown swagger annotation "rotue", did you mean swagger:route?`,
+			`api.go:31:1: operation POST /pets: operation (createPet): json: cannot unmarshal array into Go value of type spec.ResponseProps`,
+			`models.go:9:2: malformed Maximum of the field Age, expected a number - Maximum: abc`,
+			`models.go:14:2: malformed Min Length of the field Name, expected a positive integer - Min Length: -1`,
+			`models.go:21:1: swagger:example of Owner expects the file of the example, e.g. swagger:example file:testdata/user.json`,
+		}, mistakes)
+	})
+
+	t.Run("should stop at the first mistake with FailFast", func(t *testing.T) {
+		_, err := Run(&Options{Packages: []string{mistakesFixture}, ScanModels: true, FailFast: true})
+		require.Error(t, err)
+		assert.NotContains(t, err.Error(), "\n")
+		assert.Contains(t, err.Error(), `unknown swagger annotation "rotue"`)
+	})
+
+	t.Run("should warn about the annotations it ignores", func(t *testing.T) {
+		var diagnostics []Diagnostic
+		_, err := Run(&Options{Packages: []string{mistakesFixture + "/warnings"}, Diagnostics: &diagnostics})
+		require.NoError(t, err)
+
+		var warnings []string
+		for _, diagnostic := range diagnostics {
+			warnings = append(warnings, fmt.Sprintf("%s:%d: %s: %s", filepath.Base(diagnostic.Pos.Filename), diagnostic.Pos.Line, diagnostic.Code, diagnostic.Message))
+		}
+		assert.ElementsMatch(t, []string{
+			"api.go:15: malformed-annotation: Swagger:route is ignored, did you mean swagger:route? - // Swagger:route DELETE /pets/{id} pets deletePet",
+			"api.go:6: unresolved-ref: operation listPets refers to #/responses/missingResponse, which the spec doesn't define",
+		}, warnings)
+	})
+
+	t.Run("should fail on the warnings with FailOnWarning", func(t *testing.T) {
+		_, err := Run(&Options{Packages: []string{mistakesFixture + "/warnings"}, FailOnWarning: true})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "2 warnings, see FailOnWarning")
+		assert.Contains(t, err.Error(), "did you mean swagger:route?")
+		assert.Contains(t, err.Error(), "#/responses/missingResponse")
+
+		_, err = Run(&Options{Packages: []string{"github.com/3idey/codescan/fixtures/goparsing/paths"}, FailOnWarning: true})
+		require.NoError(t, err)
+	})
+}
+
+func TestClosestAnnotation(t *testing.T) {
+	for name, expected := range map[string]string{
+		"rotue":      "route",
+		"parameter":  "parameters",
+		"respones":   "response",
+		"modle":      "model",
+		"allof":      "allOf",
+		"unofficial": "",
+	} {
+		assert.Equal(t, expected, closestAnnotation(name), name)
+	}
+}
@@ -8,13 +8,14 @@ import (
 	"errors"
 	"fmt"
 	"go/ast"
-	"go/importer"
+	"go/token"
 	"go/types"
-	"log"
 	"os"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 
 	"golang.org/x/tools/go/ast/astutil"
 
@@ -89,6 +90,10 @@ func (st schemaTypable) WithEnumDescription(desc string) {
 	st.AddExtension(extEnumDesc, desc)
 }
 
+func (st schemaTypable) WithEnumNames(names []string) {
+	st.AddExtension(extEnumVarNames, names)
+}
+
 type schemaValidations struct {
 	current *spec.Schema
 }
@@ -123,17 +128,90 @@ type schemaBuilder struct {
 	annotated  bool
 	discovered []*entityDecl
 	postDecls  []*entityDecl
+
+	// externalRefs are the composition members expected from the input spec
+	externalRefs []allOfRef
+
+	// pointerEmbeds counts the embedded pointers traversed to reach the struct being built
+	pointerEmbeds int
+
+	// addressable tells if encoding/json reaches the value being built through a pointer or a slice, so
+	// that it calls the marshalers of the pointer receivers, see skipsMarshaler
+	addressable bool
+
+	// structDepth, fieldPath and fieldOrder track the declaration order of struct fields, for x-order
+	structDepth int
+	fieldPath   []int
+	fieldOrder  map[*spec.Schema]map[string][]int
+
+	// embeds counts the embedded structs traversed to reach the struct being built, and droppedFields
+	// are the fields of the outermost struct which lose a conflict of json names
+	embeds        int
+	droppedFields map[*types.Var]bool
+
+	// schemaPath holds the nesting levels of the schema being built, for Options.MaxSchemaDepth
+	schemaPath []schemaStep
+
+	// groups nest the properties of the model, from its Group directive
+	groups []propertyGroup
 }
 
 func (s *schemaBuilder) Build(definitions map[string]spec.Schema) error {
+	schema, err := s.buildSchema(definitions)
+	if err != nil {
+		return err
+	}
+	return s.commit(definitions, schema)
+}
+
+// buildSchema builds the schema of the declaration over its definition in the input spec, if any, unless a
+// MergeStrategy merges them afterwards, without recording it. Only a derived model writes to the definitions, building its source, see derive.
+func (s *schemaBuilder) buildSchema(definitions map[string]spec.Schema) (spec.Schema, error) {
 	s.inferNames()
 
-	schema := definitions[s.Name]
+	var schema spec.Schema
+	if s.ctx.opts.MergeStrategy == "" {
+		schema = definitions[s.Name]
+	}
 	err := s.buildFromDecl(s.decl, &schema)
 	if err != nil {
+		return schema, err
+	}
+	if err := s.applyPropertyGroups(&schema); err != nil {
+		return schema, err
+	}
+	if example, found, err := s.ctx.app.exampleFile(s.decl); err != nil {
+		return schema, err
+	} else if found {
+		schema.Example = example
+	}
+	derivation, err := s.decl.Derivation()
+	if err != nil {
+		return schema, err
+	}
+	if derivation != nil {
+		if err := s.derive(definitions, derivation, &schema); err != nil {
+			return schema, err
+		}
+	}
+	return schema, nil
+}
+
+// commit records the schema of the declaration in the definitions, once its composition members and its
+// name are checked.
+func (s *schemaBuilder) commit(definitions map[string]spec.Schema, schema spec.Schema) error {
+	if err := s.checkExternalRefs(definitions); err != nil {
 		return err
 	}
+	if err := s.ctx.app.checkNamedDefinition(s.decl, s.Name); err != nil {
+		return err
+	}
+	s.ctx.app.recordPosition(&schema.VendorExtensible, s.decl.Pkg.Fset.Position(s.decl.Ident.Pos()))
 	definitions[s.Name] = schema
+	if s.ctx.app != nil {
+		s.ctx.app.definitionPositions[s.Name] = s.decl.Pkg.Fset.Position(s.decl.Ident.Pos())
+		s.ctx.app.definitionTypes[s.Name] = goTypeKey(s.decl)
+	}
 	return nil
 }
 
@@ -141,13 +219,19 @@ func (s *schemaBuilder) inferNames() {
 	if s.GoName != "" {
 		return
 	}
+	if s.decl.instanceName != "" {
+		s.Name, s.GoName = s.decl.Names()
+		return
+	}
 
-	goName := s.decl.Ident.Name
-	name := goName
+	goName, name := s.decl.Ident.Name, ""
 
 	defer func() {
 		s.GoName = goName
 		s.Name = name
+		if s.Name == "" {
+			s.Name, _ = s.decl.Names()
+		}
 	}()
 
 	if s.decl.Comments == nil {
@@ -156,7 +240,7 @@ func (s *schemaBuilder) inferNames() {
 
 DECLS:
 	for _, cmt := range s.decl.Comments.List {
-		for ln := range strings.SplitSeq(cmt.Text, "\n") {
+		for ln := range commentLines(cmt.Text) {
 			matches := rxModelOverride.FindStringSubmatch(ln)
 			if len(matches) > 0 {
 				s.annotated = true
@@ -173,14 +257,23 @@ func (s *schemaBuilder) buildFromDecl(_ *entityDecl, schema *spec.Schema) error
 	// analyze doc comment for the model
 	// This includes parsing "example", "default" and other validation at the top-level declaration.
 	sp := s.createParser("", schema, schema, nil)
+	deprecation := new(setDeprecated)
+	sp.taggers = append(sp.taggers, newSingleLineTagParser("Group", &setPropertyGroups{builder: s}),
+		newSingleLineTagParser("ExternalDocs", &setExternalDocs{func(docs *spec.ExternalDocumentation) { schema.ExternalDocs = docs }}),
+		newSingleLineTagParser("Deprecated", deprecation),
+		newSingleLineTagParser("AliasOf", &matchOnlyParam{rx: rxAliasOf}))
 	sp.setTitle = func(lines []string) { schema.Title = joinDropLast(lines) }
 	sp.setDescription = func(lines []string) {
 		schema.Description = joinDropLast(lines)
-		enumDesc := getEnumDesc(schema.Extensions)
-		if enumDesc != "" {
+		switch enumDesc := getEnumDesc(schema.Extensions); {
+		case enumDesc == "":
+		case schema.Description == "":
+			schema.Description = enumDesc
+		default:
 			schema.Description += "\n" + enumDesc
 		}
 	}
+	s.discoverDeclEnum(schema)
 	if err := sp.Parse(s.decl.Comments); err != nil {
 		return err
 	}
@@ -189,6 +282,9 @@ func (s *schemaBuilder) buildFromDecl(_ *entityDecl, schema *spec.Schema) error
 	if sp.ignored {
 		return nil
 	}
+	if s.decl.AliasOf() != "" {
+		return s.buildAliasOf(schema)
+	}
 
 	defer func() {
 		if schema.Ref.String() == "" {
@@ -197,6 +293,13 @@ func (s *schemaBuilder) buildFromDecl(_ *entityDecl, schema *spec.Schema) error
 				addExtension(&schema.VendorExtensible, "x-go-name", s.GoName)
 			}
 			addExtension(&schema.VendorExtensible, "x-go-package", s.decl.Obj().Pkg().Path())
+			if s.ctx.deprecated(deprecation, s.decl.Comments) {
+				description := &schema.Description
+				if *description == "" && schema.Title != "" {
+					description = &schema.Title
+				}
+				s.ctx.deprecate(&schema.VendorExtensible, description)
+			}
 		}
 	}()
 
@@ -227,23 +330,23 @@ func (s *schemaBuilder) buildFromDecl(_ *entityDecl, schema *spec.Schema) error
 
 		return s.buildDeclAlias(tpe, tgt)
 	case *types.TypeParam:
-		log.Printf("WARNING: generic type parameters are not supported yet %[1]v (%[1]T). Skipped", tpe)
+		s.unsupported("generic type parameters are not supported yet %[1]v (%[1]T). Skipped", tpe)
 		return nil
 	case *types.Chan:
-		log.Printf("WARNING: channels are not supported %[1]v (%[1]T). Skipped", tpe)
+		s.unsupported("channels are not supported %[1]v (%[1]T). Skipped", tpe)
 		return nil
 	case *types.Signature:
-		log.Printf("WARNING: functions are not supported %[1]v (%[1]T). Skipped", tpe)
+		s.unsupported("functions are not supported %[1]v (%[1]T). Skipped", tpe)
 		return nil
 	default:
-		log.Printf("WARNING: missing parser for type %T, skipping model: %s\n", tpe, s.Name)
+		s.unsupported("missing parser for type %T, skipping model: %s", tpe, s.Name)
 		return nil
 	}
 }
 
 func (s *schemaBuilder) buildDeclNamed(tpe *types.Named, schema *spec.Schema) error {
 	if unsupportedBuiltin(tpe) {
-		log.Printf("WARNING: skipped unsupported builtin type: %v", tpe)
+		s.unsupported("skipped unsupported builtin type: %v", tpe)
 
 		return nil
 	}
@@ -258,10 +361,24 @@ func (s *schemaBuilder) buildDeclNamed(tpe *types.Named, schema *spec.Schema) er
 	}
 
 	ps := schemaTypable{schema, 0}
+	if elem, isSet := s.ctx.app.setElement(tpe); isSet {
+		return s.buildSet(elem, ps)
+	}
+	s.checkUnregisteredSet(tpe)
+
+	if s.decl.instanceName != "" {
+		// the type arguments are substituted in the underlying type of the instantiation
+		return s.buildFromType(tpe.Underlying(), ps)
+	}
+
 	ti := s.decl.Pkg.TypesInfo.Types[s.decl.Spec.Type]
 	if !ti.IsType() {
 		return fmt.Errorf("declaration is not a type: %v", o)
 	}
+	if named, isNamed := ti.Type.(*types.Named); isNamed && named.TypeArgs().Len() > 0 {
+		// a type defined by an instantiation, e.g. type UserPage Page[User], is a definition of its own
+		return s.buildFromType(named.Underlying(), ps)
+	}
 
 	return s.buildFromType(ti.Type, ps)
 }
@@ -303,11 +420,6 @@ func (s *schemaBuilder) buildFromTextMarshal(tpe types.Type, tgt swaggerTypable)
 		return nil
 	}
 
-	if isStdJSONRawMessage(tio) {
-		tgt.Typed("object", "") // TODO: this should actually be any type
-		return nil
-	}
-
 	cmt, hasComments := s.ctx.FindComments(pkg, tio.Name())
 	if !hasComments {
 		cmt = new(ast.CommentGroup)
@@ -329,56 +441,100 @@ func (s *schemaBuilder) buildFromType(tpe types.Type, tgt swaggerTypable) error
 	// if so, the type is rendered as a string.
 	debugLogf("schema buildFromType %v (%T)", tpe, tpe)
 
-	if isTextMarshaler(tpe) {
+	if mapping, isMapped := s.ctx.app.typeMapping(tpe); isMapped {
+		return buildTypeMapping(mapping, tgt)
+	}
+	if elem, isSet := s.ctx.app.setElement(tpe); isSet {
+		return s.buildSet(elem, tgt)
+	}
+
+	if isTextMarshaler(tpe) || (s.addressable && isTextMarshaler(types.NewPointer(tpe))) {
 		return s.buildFromTextMarshal(tpe, tgt)
 	}
 
 	switch titpe := tpe.(type) {
 	case *types.Basic:
 		if unsupportedBuiltinType(titpe) {
-			log.Printf("WARNING: skipped unsupported builtin type: %v", tpe)
+			s.unsupported("skipped unsupported builtin type: %v", tpe)
 			return nil
 		}
 		return swaggerSchemaForType(titpe.String(), tgt)
 	case *types.Pointer:
-		return s.buildFromType(titpe.Elem(), tgt)
+		return s.buildAddressable(true, titpe.Elem(), tgt)
 	case *types.Struct:
 		return s.buildFromStruct(s.decl, titpe, tgt.Schema(), make(map[string]string))
 	case *types.Interface:
 		return s.buildFromInterface(s.decl, titpe, tgt.Schema(), make(map[string]string))
 	case *types.Slice:
-		// anonymous slice
-		return s.buildFromType(titpe.Elem(), tgt.Items())
+		// anonymous slice, whose elements are addressable
+		return s.buildItems(titpe.Elem(), tgt, true)
 	case *types.Array:
-		// anonymous array
-		return s.buildFromType(titpe.Elem(), tgt.Items())
+		// anonymous array, of a fixed length
+		if err := s.buildItems(titpe.Elem(), tgt, s.addressable); err != nil {
+			return err
+		}
+		setArrayLength(tgt, titpe.Len())
+		return nil
 	case *types.Map:
 		return s.buildFromMap(titpe, tgt)
 	case *types.Named:
 		// a named type, e.g. type X struct {}
+		s.checkUnregisteredSet(titpe)
 		return s.buildNamedType(titpe, tgt)
 	case *types.Alias:
 		// a named alias, e.g. type X = {RHS type}.
 		debugLogf("alias(schema.buildFromType): got alias %v to %v", titpe, titpe.Rhs())
 		return s.buildAlias(titpe, tgt)
 	case *types.TypeParam:
-		log.Printf("WARNING: generic type parameters are not supported yet %[1]v (%[1]T). Skipped", titpe)
+		s.unsupported("generic type parameters are not supported yet %[1]v (%[1]T). Skipped", titpe)
 		return nil
 	case *types.Chan:
-		log.Printf("WARNING: channels are not supported %[1]v (%[1]T). Skipped", tpe)
+		s.unsupported("channels are not supported %[1]v (%[1]T). Skipped", tpe)
 		return nil
 	case *types.Signature:
-		log.Printf("WARNING: functions are not supported %[1]v (%[1]T). Skipped", tpe)
+		s.unsupported("functions are not supported %[1]v (%[1]T). Skipped", tpe)
 		return nil
 	default:
 		panic(fmt.Errorf("ERROR: can't determine refined type %[1]v (%[1]T): %w", titpe, ErrInternal))
 	}
 }
 
+func (s *schemaBuilder) buildItems(elem types.Type, tgt swaggerTypable, addressable bool) error {
+	if err := s.enterSchema(itemsSelector, token.NoPos); err != nil {
+		return err
+	}
+	defer s.leaveSchema()
+
+	return s.buildAddressable(addressable, elem, tgt.Items())
+}
+
+// buildAddressable builds a type reached by encoding/json as an addressable value, or not, e.g. the element
+// of a pointer or of a slice, or the value of a map.
+func (s *schemaBuilder) buildAddressable(addressable bool, tpe types.Type, tgt swaggerTypable) error {
+	previous := s.addressable
+	s.addressable = addressable
+	defer func() { s.addressable = previous }()
+
+	return s.buildFromType(tpe, tgt)
+}
+
+// skipsMarshaler tells if encoding/json skips the marshaler of a named type at the value being built: when
+// MarshalJSON or MarshalText is declared on the pointer receiver, and the value isn't addressable, e.g. a
+// value field of a struct, or the value of a map. The value is then marshaled as its underlying type, and the
+// swagger:strfmt and swagger:type overrides of the type, which document the marshaler, don't apply. The
+// simple parameters and headers, which are not encoded as JSON, keep them.
+func (s *schemaBuilder) skipsMarshaler(tpe *types.Named, tgt swaggerTypable) bool {
+	if tgt.In() != "body" {
+		return false
+	}
+	value, pointer := marshalerReceivers(tpe)
+	return pointer && !value && !s.addressable
+}
+
 func (s *schemaBuilder) buildNamedType(titpe *types.Named, tgt swaggerTypable) error {
 	tio := titpe.Obj()
 	if unsupportedBuiltin(titpe) {
-		log.Printf("WARNING: skipped unsupported builtin type: %v", titpe)
+		s.unsupported("skipped unsupported builtin type: %v", titpe)
 		return nil
 	}
 	if isAny(tio) {
@@ -400,12 +556,6 @@ func (s *schemaBuilder) buildNamedType(titpe *types.Named, tgt swaggerTypable) e
 		return nil
 	}
 
-	// special case of the "json.RawMessage" type
-	if isStdJSONRawMessage(tio) {
-		tgt.Typed("object", "") // TODO: this should actually be any type
-		return nil
-	}
-
 	pkg, found := s.ctx.PkgForType(titpe)
 	debugLogf("named refined type %s.%s", pkg, tio.Name())
 	if !found {
@@ -423,7 +573,7 @@ func (s *schemaBuilder) buildNamedType(titpe *types.Named, tgt swaggerTypable) e
 		cmt = new(ast.CommentGroup)
 	}
 
-	if typeName, ok := typeName(cmt); ok {
+	if typeName, ok := typeName(cmt); ok && !s.skipsMarshaler(titpe, tgt) {
 		_ = swaggerSchemaForType(typeName, tgt)
 
 		return nil
@@ -435,6 +585,13 @@ func (s *schemaBuilder) buildNamedType(titpe *types.Named, tgt swaggerTypable) e
 	}
 
 	if titpe.TypeArgs() != nil && titpe.TypeArgs().Len() > 0 {
+		decl, err := s.ctx.instanceDecl(titpe)
+		if err != nil {
+			return err
+		}
+		if decl != nil {
+			return s.makeRef(decl, tgt)
+		}
 		return s.buildFromType(titpe.Underlying(), tgt)
 	}
 
@@ -454,6 +611,10 @@ func (s *schemaBuilder) buildNamedType(titpe *types.Named, tgt swaggerTypable) e
 			tgt.Typed("string", "date-time")
 			return nil
 		}
+		if s.skipsMarshaler(titpe, tgt) {
+			// the marshaler isn't called, the value is the object of the definition
+			return s.makeRef(decl, tgt)
+		}
 
 		if sfnm, isf := strfmtName(cmt); isf {
 			tgt.Typed("string", sfnm)
@@ -464,11 +625,19 @@ func (s *schemaBuilder) buildNamedType(titpe *types.Named, tgt swaggerTypable) e
 			_ = swaggerSchemaForType(typeName, tgt)
 			return nil
 		}
+		if s.callsMarshalJSON(titpe, tgt) {
+			return s.buildFromJSONMarshal(titpe, tgt)
+		}
 
 		return s.makeRef(decl, tgt)
 	case *types.Interface:
 		debugLogf("found interface: %s.%s", tio.Pkg().Path(), tio.Name())
 
+		if base, polymorphic := s.ctx.app.polymorphicBase(titpe); polymorphic {
+			if decl, found := s.ctx.FindModel(base.pkgPath, base.name); found {
+				return s.makeRef(decl, tgt)
+			}
+		}
 		decl, found := s.ctx.FindModel(tio.Pkg().Path(), tio.Name())
 		if !found {
 			return fmt.Errorf("can't find source file for type: %v", utitpe)
@@ -477,23 +646,29 @@ func (s *schemaBuilder) buildNamedType(titpe *types.Named, tgt swaggerTypable) e
 		return s.makeRef(decl, tgt)
 	case *types.Basic:
 		if unsupportedBuiltinType(utitpe) {
-			log.Printf("WARNING: skipped unsupported builtin type: %v", utitpe)
+			s.unsupported("skipped unsupported builtin type: %v", utitpe)
 			return nil
 		}
 
 		debugLogf("found primitive type: %s.%s", tio.Pkg().Path(), tio.Name())
 
-		if sfnm, isf := strfmtName(cmt); isf {
+		if sfnm, isf := strfmtName(cmt); isf && !s.skipsMarshaler(titpe, tgt) {
 			tgt.Typed("string", sfnm)
 			return nil
 		}
 
 		if enumName, ok := enumName(cmt); ok {
-			enumValues, enumDesces, _ := s.ctx.FindEnumValues(pkg, enumName)
+			enumValues, enumDesces, enumNames, _ := s.ctx.FindEnumValues(pkg, enumName)
 			if len(enumValues) > 0 {
-				tgt.WithEnum(enumValues...)
 				enumTypeName := reflect.TypeOf(enumValues[0]).String()
 				_ = swaggerSchemaForType(enumTypeName, tgt)
+				if s.omitsEnum(titpe, cmt, len(enumValues), tgt) {
+					return nil
+				}
+				tgt.WithEnum(enumValues...)
+				if s.ctx.opts.EnumExtensionStyle != "" {
+					tgt.WithEnumNames(enumNames)
+				}
 			}
 			if len(enumDesces) > 0 {
 				tgt.WithEnumDescription(strings.Join(enumDesces, "\n"))
@@ -506,11 +681,16 @@ func (s *schemaBuilder) buildNamedType(titpe *types.Named, tgt swaggerTypable) e
 			return nil
 		}
 
-		if typeName, ok := typeName(cmt); ok {
+		if typeName, ok := typeName(cmt); ok && !s.skipsMarshaler(titpe, tgt) {
 			_ = swaggerSchemaForType(typeName, tgt)
 			return nil
 		}
 
+		if tgt.In() != "body" && s.discoverEnum(titpe, cmt, tgt) {
+			// simple parameters and headers don't refer to the definition holding the enum
+			return swaggerSchemaForType(utitpe.String(), tgt)
+		}
+
 		if isAliasParam(tgt) || aliasParam(cmt) {
 			err := swaggerSchemaForType(utitpe.Name(), tgt)
 			if err == nil {
@@ -522,6 +702,7 @@ func (s *schemaBuilder) buildNamedType(titpe *types.Named, tgt swaggerTypable) e
 			return s.makeRef(decl, tgt)
 		}
 
+		s.discoverEnum(titpe, cmt, tgt)
 		return swaggerSchemaForType(utitpe.String(), tgt)
 	case *types.Array:
 		debugLogf("found array type: %s.%s", tio.Pkg().Path(), tio.Name())
@@ -542,7 +723,11 @@ func (s *schemaBuilder) buildNamedType(titpe *types.Named, tgt swaggerTypable) e
 		if decl, ok := s.ctx.FindModel(tio.Pkg().Path(), tio.Name()); ok {
 			return s.makeRef(decl, tgt)
 		}
-		return s.buildFromType(utitpe.Elem(), tgt.Items())
+		if err := s.buildFromType(utitpe.Elem(), tgt.Items()); err != nil {
+			return err
+		}
+		setArrayLength(tgt, utitpe.Len())
+		return nil
 	case *types.Slice:
 		debugLogf("found slice type: %s.%s", tio.Pkg().Path(), tio.Name())
 
@@ -557,7 +742,7 @@ func (s *schemaBuilder) buildNamedType(titpe *types.Named, tgt swaggerTypable) e
 		if decl, ok := s.ctx.FindModel(tio.Pkg().Path(), tio.Name()); ok {
 			return s.makeRef(decl, tgt)
 		}
-		return s.buildFromType(utitpe.Elem(), tgt.Items())
+		return s.buildAddressable(true, utitpe.Elem(), tgt.Items())
 	case *types.Map:
 		debugLogf("found map type: %s.%s", tio.Pkg().Path(), tio.Name())
 
@@ -566,17 +751,17 @@ func (s *schemaBuilder) buildNamedType(titpe *types.Named, tgt swaggerTypable) e
 		}
 		return nil
 	case *types.TypeParam:
-		log.Printf("WARNING: generic type parameters are not supported yet %[1]v (%[1]T). Skipped", utitpe)
+		s.unsupported("generic type parameters are not supported yet %[1]v (%[1]T). Skipped", utitpe)
 		return nil
 	case *types.Chan:
-		log.Printf("WARNING: channels are not supported %[1]v (%[1]T). Skipped", utitpe)
+		s.unsupported("channels are not supported %[1]v (%[1]T). Skipped", utitpe)
 		return nil
 	case *types.Signature:
-		log.Printf("WARNING: functions are not supported %[1]v (%[1]T). Skipped", utitpe)
+		s.unsupported("functions are not supported %[1]v (%[1]T). Skipped", utitpe)
 		return nil
 	default:
-		log.Printf(
-			"WARNING: can't figure out object type for named type (%T): %v [alias: %t]",
+		s.unsupported(
+			"can't figure out object type for named type (%T): %v [alias: %t]",
 			titpe.Underlying(), titpe.Underlying(), titpe.Obj().IsAlias(),
 		)
 
@@ -587,7 +772,7 @@ func (s *schemaBuilder) buildNamedType(titpe *types.Named, tgt swaggerTypable) e
 // buildDeclAlias builds a top-level alias declaration.
 func (s *schemaBuilder) buildDeclAlias(tpe *types.Alias, tgt swaggerTypable) error {
 	if unsupportedBuiltinType(tpe) {
-		log.Printf("WARNING: skipped unsupported builtin type: %v", tpe)
+		s.unsupported("skipped unsupported builtin type: %v", tpe)
 		return nil
 	}
 
@@ -641,7 +826,7 @@ func (s *schemaBuilder) buildDeclAlias(tpe *types.Alias, tgt swaggerTypable) err
 	case *types.Alias:
 		ro := rtpe.Obj()
 		if unsupportedBuiltin(rtpe) {
-			log.Printf("WARNING: skipped unsupported builtin type: %v", rtpe)
+			s.unsupported("skipped unsupported builtin type: %v", rtpe)
 			return nil
 		}
 		if isAny(ro) {
@@ -713,7 +898,7 @@ func (s *schemaBuilder) buildAnonymousInterface(it *types.Interface, tgt swagger
 		name := fld.Name()
 		if afld.Doc != nil {
 			for _, cmt := range afld.Doc.List {
-				for ln := range strings.SplitSeq(cmt.Text, "\n") {
+				for ln := range commentLines(cmt.Text) {
 					matches := rxName.FindStringSubmatch(ln)
 					ml := len(matches)
 					if ml > 1 {
@@ -736,13 +921,14 @@ func (s *schemaBuilder) buildAnonymousInterface(it *types.Interface, tgt swagger
 			ps.Items = nil
 		}
 
-		if err := s.createParser(name, tgt.Schema(), &ps, afld).Parse(afld.Doc); err != nil {
+		if err := s.parseProperty(name, tgt.Schema(), &ps, afld); err != nil {
 			return err
 		}
 
 		if ps.Ref.String() == "" && name != fld.Name() {
 			ps.AddExtension("x-go-name", fld.Name())
 		}
+		s.ctx.app.recordPosition(&ps.VendorExtensible, decl.Pkg.Fset.Position(fld.Pos()))
 
 		if s.ctx.app.setXNullableForPointers {
 			if _, isPointer := fld.Type().(*types.Signature).Results().At(0).Type().(*types.Pointer); isPointer && (ps.Extensions == nil || (ps.Extensions["x-nullable"] == nil && ps.Extensions["x-isnullable"] == nil)) {
@@ -760,7 +946,7 @@ func (s *schemaBuilder) buildAnonymousInterface(it *types.Interface, tgt swagger
 // buildAlias builds a reference to an alias from another type.
 func (s *schemaBuilder) buildAlias(tpe *types.Alias, tgt swaggerTypable) error {
 	if unsupportedBuiltinType(tpe) {
-		log.Printf("WARNING: skipped unsupported builtin type: %v", tpe)
+		s.unsupported("skipped unsupported builtin type: %v", tpe)
 
 		return nil
 	}
@@ -799,7 +985,13 @@ func (s *schemaBuilder) buildFromMap(titpe *types.Map, tgt swaggerTypable) error
 	eleProp := schemaTypable{sch, tgt.Level()}
 	key := titpe.Key()
 	if key.Underlying().String() == "string" || isTextMarshaler(key) {
-		return s.buildFromType(titpe.Elem(), eleProp.AdditionalProperties())
+		if err := s.enterSchema(valuesSelector, token.NoPos); err != nil {
+			return err
+		}
+		defer s.leaveSchema()
+
+		// the values of a map are not addressable
+		return s.buildAddressable(false, titpe.Elem(), eleProp.AdditionalProperties())
 	}
 
 	return nil
@@ -875,16 +1067,16 @@ func (s *schemaBuilder) buildFromInterface(decl *entityDecl, it *types.Interface
 				fieldHasAllOf = true
 			}
 		case *types.Union: // e.g. type X interface{ ~uint16 | ~float32 }
-			log.Printf("WARNING: union type constraints are not supported yet %[1]v (%[1]T). Skipped", ftpe)
+			s.unsupported("union type constraints are not supported yet %[1]v (%[1]T). Skipped", ftpe)
 		case *types.TypeParam:
-			log.Printf("WARNING: generic type parameters are not supported yet %[1]v (%[1]T). Skipped", ftpe)
+			s.unsupported("generic type parameters are not supported yet %[1]v (%[1]T). Skipped", ftpe)
 		case *types.Chan:
-			log.Printf("WARNING: channels are not supported %[1]v (%[1]T). Skipped", ftpe)
+			s.unsupported("channels are not supported %[1]v (%[1]T). Skipped", ftpe)
 		case *types.Signature:
-			log.Printf("WARNING: functions are not supported %[1]v (%[1]T). Skipped", ftpe)
+			s.unsupported("functions are not supported %[1]v (%[1]T). Skipped", ftpe)
 		default:
-			log.Printf(
-				"WARNING: can't figure out object type for allOf named type (%T): %v",
+			s.unsupported(
+				"can't figure out object type for allOf named type (%T): %v",
 				ftpe, ftpe.Underlying(),
 			)
 		}
@@ -945,7 +1137,7 @@ func (s *schemaBuilder) buildFromInterface(decl *entityDecl, it *types.Interface
 		name := fld.Name()
 		if afld.Doc != nil {
 			for _, cmt := range afld.Doc.List {
-				for ln := range strings.SplitSeq(cmt.Text, "\n") {
+				for ln := range commentLines(cmt.Text) {
 					matches := rxName.FindStringSubmatch(ln)
 					ml := len(matches)
 					if ml > 1 {
@@ -964,13 +1156,14 @@ func (s *schemaBuilder) buildFromInterface(decl *entityDecl, it *types.Interface
 			ps.Items = nil
 		}
 
-		if err := s.createParser(name, tgt, &ps, afld).Parse(afld.Doc); err != nil {
+		if err := s.parseProperty(name, tgt, &ps, afld); err != nil {
 			return err
 		}
 
 		if ps.Ref.String() == "" && name != fld.Name() {
 			ps.AddExtension("x-go-name", fld.Name())
 		}
+		s.ctx.app.recordPosition(&ps.VendorExtensible, decl.Pkg.Fset.Position(fld.Pos()))
 
 		if s.ctx.app.setXNullableForPointers {
 			if _, isPointer := fld.Type().(*types.Signature).Results().At(0).Type().(*types.Pointer); isPointer && (ps.Extensions == nil || (ps.Extensions["x-nullable"] == nil && ps.Extensions["x-isnullable"] == nil)) {
@@ -1048,9 +1241,10 @@ func (s *schemaBuilder) buildNamedInterface(ftpe *types.Named, flist []*ast.Fiel
 		return hasAllOf, err
 	}
 
+	s.describeRef(&newSch, fieldDescription(afld.Doc))
 	if afld.Doc != nil {
 		for _, cmt := range afld.Doc.List {
-			for ln := range strings.SplitSeq(cmt.Text, "\n") {
+			for ln := range commentLines(cmt.Text) {
 				matches := rxAllOf.FindStringSubmatch(ln)
 				ml := len(matches)
 				if ml <= 1 {
@@ -1081,8 +1275,32 @@ func (s *schemaBuilder) buildFromStruct(decl *entityDecl, st *types.Struct, sche
 		_ = swaggerSchemaForType(name, schemaTypable{schema: schema})
 		return nil
 	}
+
+	if s.embeds == 0 {
+		dropped, err := s.resolveJSONNames(decl, st)
+		if err != nil {
+			return err
+		}
+		outer := s.droppedFields
+		s.droppedFields = dropped
+		defer func() { s.droppedFields = outer }()
+	}
+
+	if s.ctx.app.declarationOrder {
+		s.structDepth++
+		defer func() {
+			s.structDepth--
+			if s.structDepth == 0 {
+				s.applyFieldOrder()
+			}
+		}()
+	}
+
 	// First check for all of schemas
-	var tgt *spec.Schema
+	var (
+		tgt   *spec.Schema
+		bases []*types.Named // the discriminated bases, which are always allOf members
+	)
 	hasAllOf := false
 
 	for i := range st.NumFields() {
@@ -1131,12 +1349,20 @@ func (s *schemaBuilder) buildFromStruct(decl *entityDecl, st *types.Struct, sche
 		}
 
 		_, isAliased := fld.Type().(*types.Alias)
+		base, discriminated := s.ctx.app.discriminatedBase(fld.Type())
+		if discriminated {
+			bases = append(bases, base)
+		}
 
-		if !allOfMember(afld.Doc) && !isAliased {
+		if !allOfMember(afld.Doc) && !discriminated && !isAliased {
 			if tgt == nil {
 				tgt = schema
 			}
-			if err := s.buildEmbedded(fld.Type(), tgt, seen); err != nil {
+			// promoted fields slot in at the position of the embedded field
+			s.fieldPath = append(s.fieldPath, i)
+			err := s.buildEmbedded(fld.Type(), tgt, seen)
+			s.fieldPath = s.fieldPath[:len(s.fieldPath)-1]
+			if err != nil {
 				return err
 			}
 			continue
@@ -1161,9 +1387,10 @@ func (s *schemaBuilder) buildFromStruct(decl *entityDecl, st *types.Struct, sche
 			return err
 		}
 
+		s.describeRef(&newSch, fieldDescription(afld.Doc))
 		if afld.Doc != nil {
 			for _, cmt := range afld.Doc.List {
-				for ln := range strings.SplitSeq(cmt.Text, "\n") {
+				for ln := range commentLines(cmt.Text) {
 					matches := rxAllOf.FindStringSubmatch(ln)
 					ml := len(matches)
 					if ml > 1 {
@@ -1179,6 +1406,28 @@ func (s *schemaBuilder) buildFromStruct(decl *entityDecl, st *types.Struct, sche
 		schema.AllOf = append(schema.AllOf, newSch)
 	}
 
+	// then the members declared by name, which come after the embedded ones and before the properties
+	refs, err := s.buildAllOfRefs(decl, schema)
+	if err != nil {
+		return err
+	}
+	if len(refs) > 0 {
+		hasAllOf = true
+		if tgt == schema {
+			// promoted fields were collected in the schema itself: move them to their own member
+			tgt = &spec.Schema{SchemaProps: spec.SchemaProps{Properties: schema.Properties, Required: schema.Required}}
+			schema.Properties, schema.Required, schema.Type = nil, nil, nil
+			if order, ok := s.fieldOrder[schema]; ok {
+				s.fieldOrder[tgt] = order
+				delete(s.fieldOrder, schema)
+			}
+		}
+		if tgt == nil {
+			tgt = &spec.Schema{}
+		}
+		schema.AllOf = append(schema.AllOf, refs...)
+	}
+
 	if tgt == nil {
 		if schema != nil {
 			tgt = schema
@@ -1205,6 +1454,11 @@ func (s *schemaBuilder) buildFromStruct(decl *entityDecl, st *types.Struct, sche
 			continue
 		}
 
+		if s.droppedFields[fld] {
+			debugLogf("skipping field %s because encoding/json drops it", fld.Name())
+			continue
+		}
+
 		var afld *ast.Field
 		ans, _ := astutil.PathEnclosingInterval(decl.File, fld.Pos(), fld.Pos())
 		for _, an := range ans {
@@ -1242,12 +1496,35 @@ func (s *schemaBuilder) buildFromStruct(decl *entityDecl, st *types.Struct, sche
 			continue
 		}
 
+		if kind := unsupportedFieldKind(fld.Type()); kind != "" {
+			s.ctx.app.diagnose(Diagnostic{
+				Pos:     decl.Pkg.Fset.Position(fld.Pos()),
+				Code:    DiagnosticSkippedField,
+				Message: fmt.Sprintf("field %s is skipped: %s are not supported", fld.Name(), kind),
+			})
+			continue
+		}
+
 		ps := tgt.Properties[name]
-		if err = s.buildFromType(fld.Type(), schemaTypable{&ps, 0}); err != nil {
+		// the type of the field is a struct of its own
+		if err := s.enterSchema("."+fld.Name(), fld.Pos()); err != nil {
+			return err
+		}
+		embeds := s.embeds
+		s.embeds = 0
+		err = s.buildFromType(fld.Type(), schemaTypable{&ps, 0})
+		s.embeds = embeds
+		s.leaveSchema()
+		if err != nil {
 			return err
 		}
-		if isString {
-			ps.Typed("string", ps.Format)
+		if basic, quoted := quotedBasic(fld.Type()); isString && quoted {
+			// the value is a JSON string, with the format of the Go type, e.g. int64, as a hint of its content
+			var scalar spec.Schema
+			if err := swaggerSchemaForType(basic.Name(), schemaTypable{&scalar, 0}); err != nil {
+				return err
+			}
+			ps.Typed("string", scalar.Format)
 			ps.Ref = spec.Ref{}
 			ps.Items = nil
 		}
@@ -1256,10 +1533,46 @@ func (s *schemaBuilder) buildFromStruct(decl *entityDecl, st *types.Struct, sche
 			ps.Ref = spec.Ref{}
 			ps.Items = nil
 		}
+		for _, rules := range validatorRules(afld, s.ctx.opts.ValidatorTags) {
+			applyValidatorRules(schemaValidatorTarget(&ps, tgt, name), rules)
+		}
 
-		if err = s.createParser(name, tgt, &ps, afld).Parse(afld.Doc); err != nil {
+		inherited := ps.Example // from the input spec, unless the field documents an example
+		ps.Example = nil
+		if err = s.parseProperty(name, tgt, &ps, afld); err != nil {
+			return err
+		}
+		if err := s.setExampleTag(afld, &ps, name); err != nil {
 			return err
 		}
+		if err := s.setDefaultTag(afld, fld.Type(), &ps, name); err != nil {
+			return err
+		}
+		if ps.Example == nil {
+			ps.Example = inherited
+		}
+		if discriminatorDirective(afld.Doc) {
+			if err := s.setDiscriminatorField(decl, fld, tgt, name); err != nil {
+				return err
+			}
+		}
+
+		if isEmptySchema(ps) && !s.ctx.app.isMappedType(fld.Type()) {
+			s.ctx.app.diagnose(Diagnostic{
+				Pos:     decl.Pkg.Fset.Position(fld.Pos()),
+				Code:    DiagnosticEmptySchema,
+				Message: fmt.Sprintf("property %q has an empty schema: %s", name, emptySchemaReason(fld.Type())),
+			})
+		}
+
+		s.ctx.app.recordPosition(&ps.VendorExtensible, decl.Pkg.Fset.Position(fld.Pos()))
+
+		if s.ctx.app.requiredFromPointersFor(decl.Pkg.PkgPath) && !requiredDirective(afld.Doc) {
+			s.requireFromPointer(tgt, name, fld.Type(), omitEmpty, ps.ReadOnly)
+		}
+		if omitEmpty && s.ctx.opts.OmitEmptyAsOptional {
+			tgt.Required = slices.DeleteFunc(tgt.Required, func(required string) bool { return required == name })
+		}
 
 		if ps.Ref.String() == "" && name != fld.Name() {
 			addExtension(&ps.VendorExtensible, "x-go-name", fld.Name())
@@ -1278,11 +1591,24 @@ func (s *schemaBuilder) buildFromStruct(decl *entityDecl, st *types.Struct, sche
 		// so we need to save both tag&name
 		seen[name] = fld.Name()
 		tgt.Properties[name] = ps
+		s.recordFieldOrder(tgt, name, i)
+	}
+
+	if len(bases) > 0 {
+		s.checkOverrides(decl, st, bases)
+		if value, ok := discriminatorValue(decl.Comments); ok {
+			schema.AddExtension("x-class", value)
+		}
 	}
 
 	if tgt == nil {
 		return nil
 	}
+	if hasAllOf && tgt.Discriminator != "" {
+		// the discriminator belongs to the composed type, not to its inline member
+		schema.Discriminator = tgt.Discriminator
+		tgt.Discriminator = ""
+	}
 	if hasAllOf && len(tgt.Properties) > 0 {
 		schema.AllOf = append(schema.AllOf, *tgt)
 	}
@@ -1294,6 +1620,59 @@ func (s *schemaBuilder) buildFromStruct(decl *entityDecl, st *types.Struct, sche
 	return nil
 }
 
+func (s *schemaBuilder) recordFieldOrder(schema *spec.Schema, name string, index int) {
+	if s.structDepth == 0 {
+		return
+	}
+	if s.fieldOrder == nil {
+		s.fieldOrder = make(map[*spec.Schema]map[string][]int)
+	}
+	if s.fieldOrder[schema] == nil {
+		s.fieldOrder[schema] = make(map[string][]int)
+	}
+	s.fieldOrder[schema][name] = append(slices.Clone(s.fieldPath), index)
+}
+
+// applyFieldOrder sets the x-order extension of the properties collected while building structs,
+// following the declaration order of their fields.
+func (s *schemaBuilder) applyFieldOrder() {
+	for schema, positions := range s.fieldOrder {
+		names := make([]string, 0, len(positions))
+		for name := range positions {
+			if _, exists := schema.Properties[name]; exists {
+				names = append(names, name)
+			}
+		}
+		slices.SortFunc(names, func(a, b string) int {
+			return slices.Compare(positions[a], positions[b])
+		})
+		for order, name := range names {
+			ps := schema.Properties[name]
+			ps.AddExtension("x-order", order)
+			schema.Properties[name] = ps
+		}
+	}
+	s.fieldOrder = nil
+}
+
+// requireFromPointer applies the RequiredFromPointers convention to a struct field
+// which carries no explicit "required:" directive.
+//
+// Non-pointer fields without omitempty are required. Pointer fields, readOnly fields and
+// fields promoted through an embedded pointer are left optional.
+func (s *schemaBuilder) requireFromPointer(schema *spec.Schema, name string, tpe types.Type, omitEmpty, readOnly bool) {
+	if omitEmpty || readOnly || s.pointerEmbeds > 0 {
+		return
+	}
+	if _, isPointer := types.Unalias(tpe).(*types.Pointer); isPointer {
+		return
+	}
+	if slices.Contains(schema.Required, name) {
+		return
+	}
+	schema.Required = append(schema.Required, name)
+}
+
 func (s *schemaBuilder) buildAllOf(tpe types.Type, schema *spec.Schema) error {
 	debugLogf("allOf %s", tpe.Underlying())
 
@@ -1307,16 +1686,16 @@ func (s *schemaBuilder) buildAllOf(tpe types.Type, schema *spec.Schema) error {
 		tgt := schemaTypable{schema: schema}
 		return s.buildAlias(ftpe, tgt)
 	case *types.TypeParam:
-		log.Printf("WARNING: generic type parameters are not supported yet %[1]v (%[1]T). Skipped", ftpe)
+		s.unsupported("generic type parameters are not supported yet %[1]v (%[1]T). Skipped", ftpe)
 		return nil
 	case *types.Chan:
-		log.Printf("WARNING: channels are not supported %[1]v (%[1]T). Skipped", ftpe)
+		s.unsupported("channels are not supported %[1]v (%[1]T). Skipped", ftpe)
 		return nil
 	case *types.Signature:
-		log.Printf("WARNING: functions are not supported %[1]v (%[1]T). Skipped", ftpe)
+		s.unsupported("functions are not supported %[1]v (%[1]T). Skipped", ftpe)
 		return nil
 	default:
-		log.Printf("WARNING: missing allOf parser for a %T, skipping field", ftpe)
+		s.unsupported("missing allOf parser for a %T, skipping field", ftpe)
 		return fmt.Errorf("unable to resolve allOf member for: %v", ftpe)
 	}
 }
@@ -1339,7 +1718,7 @@ func (s *schemaBuilder) buildNamedAllOf(ftpe *types.Named, schema *spec.Schema)
 			return nil
 		}
 
-		if decl.HasModelAnnotation() {
+		if _, discriminated := s.ctx.app.discriminatedBase(ftpe); discriminated || decl.HasModelAnnotation() {
 			return s.makeRef(decl, schemaTypable{schema, 0})
 		}
 
@@ -1361,17 +1740,17 @@ func (s *schemaBuilder) buildNamedAllOf(ftpe *types.Named, schema *spec.Schema)
 
 		return s.buildFromInterface(decl, utpe, schema, make(map[string]string))
 	case *types.TypeParam:
-		log.Printf("WARNING: generic type parameters are not supported yet %[1]v (%[1]T). Skipped", ftpe)
+		s.unsupported("generic type parameters are not supported yet %[1]v (%[1]T). Skipped", ftpe)
 		return nil
 	case *types.Chan:
-		log.Printf("WARNING: channels are not supported %[1]v (%[1]T). Skipped", ftpe)
+		s.unsupported("channels are not supported %[1]v (%[1]T). Skipped", ftpe)
 		return nil
 	case *types.Signature:
-		log.Printf("WARNING: functions are not supported %[1]v (%[1]T). Skipped", ftpe)
+		s.unsupported("functions are not supported %[1]v (%[1]T). Skipped", ftpe)
 		return nil
 	default:
-		log.Printf(
-			"WARNING: can't figure out object type for allOf named type (%T): %v",
+		s.unsupported(
+			"can't figure out object type for allOf named type (%T): %v",
 			ftpe, utpe,
 		)
 		return fmt.Errorf("unable to locate source file for allOf (%T): %v",
@@ -1385,6 +1764,8 @@ func (s *schemaBuilder) buildEmbedded(tpe types.Type, schema *spec.Schema, seen
 
 	switch ftpe := tpe.(type) {
 	case *types.Pointer:
+		s.pointerEmbeds++
+		defer func() { s.pointerEmbeds-- }()
 		return s.buildEmbedded(ftpe.Elem(), schema, seen)
 	case *types.Named:
 		return s.buildNamedEmbedded(ftpe, schema, seen)
@@ -1393,19 +1774,19 @@ func (s *schemaBuilder) buildEmbedded(tpe types.Type, schema *spec.Schema, seen
 		tgt := schemaTypable{schema, 0}
 		return s.buildAlias(ftpe, tgt)
 	case *types.Union: // e.g. type X interface{ ~uint16 | ~float32 }
-		log.Printf("WARNING: union type constraints are not supported yet %[1]v (%[1]T). Skipped", ftpe)
+		s.unsupported("union type constraints are not supported yet %[1]v (%[1]T). Skipped", ftpe)
 		return nil
 	case *types.TypeParam:
-		log.Printf("WARNING: generic type parameters are not supported yet %[1]v (%[1]T). Skipped", ftpe)
+		s.unsupported("generic type parameters are not supported yet %[1]v (%[1]T). Skipped", ftpe)
 		return nil
 	case *types.Chan:
-		log.Printf("WARNING: channels are not supported %[1]v (%[1]T). Skipped", ftpe)
+		s.unsupported("channels are not supported %[1]v (%[1]T). Skipped", ftpe)
 		return nil
 	case *types.Signature:
-		log.Printf("WARNING: functions are not supported %[1]v (%[1]T). Skipped", ftpe)
+		s.unsupported("functions are not supported %[1]v (%[1]T). Skipped", ftpe)
 		return nil
 	default:
-		log.Printf("WARNING: Missing embedded parser for a %T, skipping model\n", ftpe)
+		s.unsupported("missing embedded parser for a %T, skipping model", ftpe)
 		return nil
 	}
 }
@@ -1413,7 +1794,7 @@ func (s *schemaBuilder) buildEmbedded(tpe types.Type, schema *spec.Schema, seen
 func (s *schemaBuilder) buildNamedEmbedded(ftpe *types.Named, schema *spec.Schema, seen map[string]string) error {
 	debugLogf("embedded named type: %T", ftpe.Underlying())
 	if unsupportedBuiltin(ftpe) {
-		log.Printf("WARNING: skipped unsupported builtin type: %v", ftpe)
+		s.unsupported("skipped unsupported builtin type: %v", ftpe)
 
 		return nil
 	}
@@ -1425,6 +1806,8 @@ func (s *schemaBuilder) buildNamedEmbedded(ftpe *types.Named, schema *spec.Schem
 			return fmt.Errorf("can't find source file for struct: %s", ftpe.String())
 		}
 
+		s.embeds++
+		defer func() { s.embeds-- }()
 		return s.buildFromStruct(decl, utpe, schema, seen)
 	case *types.Interface:
 		if utpe.Empty() {
@@ -1447,19 +1830,19 @@ func (s *schemaBuilder) buildNamedEmbedded(ftpe *types.Named, schema *spec.Schem
 		}
 		return s.buildFromInterface(decl, utpe, schema, seen)
 	case *types.Union: // e.g. type X interface{ ~uint16 | ~float32 }
-		log.Printf("WARNING: union type constraints are not supported yet %[1]v (%[1]T). Skipped", utpe)
+		s.unsupported("union type constraints are not supported yet %[1]v (%[1]T). Skipped", utpe)
 		return nil
 	case *types.TypeParam:
-		log.Printf("WARNING: generic type parameters are not supported yet %[1]v (%[1]T). Skipped", utpe)
+		s.unsupported("generic type parameters are not supported yet %[1]v (%[1]T). Skipped", utpe)
 		return nil
 	case *types.Chan:
-		log.Printf("WARNING: channels are not supported %[1]v (%[1]T). Skipped", utpe)
+		s.unsupported("channels are not supported %[1]v (%[1]T). Skipped", utpe)
 		return nil
 	case *types.Signature:
-		log.Printf("WARNING: functions are not supported %[1]v (%[1]T). Skipped", utpe)
+		s.unsupported("functions are not supported %[1]v (%[1]T). Skipped", utpe)
 		return nil
 	default:
-		log.Printf("WARNING: can't figure out object type for embedded named type (%T): %v",
+		s.unsupported("can't figure out object type for embedded named type (%T): %v",
 			ftpe, utpe,
 		)
 		return nil
@@ -1467,6 +1850,10 @@ func (s *schemaBuilder) buildNamedEmbedded(ftpe *types.Named, schema *spec.Schem
 }
 
 func (s *schemaBuilder) makeRef(decl *entityDecl, prop swaggerTypable) error {
+	if ref, indexed := s.ctx.app.indexedRef(decl); indexed {
+		prop.SetRef(ref)
+		return nil
+	}
 	nm, _ := decl.Names()
 	ref, err := spec.NewRef("#/definitions/" + nm)
 	if err != nil {
@@ -1478,19 +1865,25 @@ func (s *schemaBuilder) makeRef(decl *entityDecl, prop swaggerTypable) error {
 }
 
 func (s *schemaBuilder) createParser(nm string, schema, ps *spec.Schema, fld *ast.Field) *sectionedParser {
-	sp := new(sectionedParser)
+	sp := &sectionedParser{preserveFormat: s.ctx.opts.PreserveCommentFormat}
+	if fld != nil {
+		sp.ignore = s.ctx.app.ignoreItemsValidations(s.decl.Pkg.Fset, fld)
+	}
 
 	schemeType, err := ps.Type.MarshalJSON()
 	if err != nil {
 		return nil
 	}
 
-	if ps.Ref.String() != "" && !s.ctx.opts.DescWithRef {
+	if ps.Ref.String() != "" && !s.ctx.opts.DescWithRef && !hasSchemaFile(fld) {
 		// if DescWithRef option is enabled, allow the tagged documentation to flow alongside the $ref
 		// otherwise behave as expected by jsonschema draft4: $ref predates all sibling keys.
 		sp.taggers = []tagParser{
 			newSingleLineTagParser("required", &setRequiredSchema{schema, nm}),
 		}
+		if s.ctx.opts.WrapRefsForDescription {
+			sp.setDescription = func(lines []string) { s.describeRef(ps, joinDropLast(lines)) }
+		}
 
 		return sp
 	}
@@ -1503,14 +1896,16 @@ func (s *schemaBuilder) createParser(nm string, schema, ps *spec.Schema, fld *as
 		}
 	}
 	sp.taggers = []tagParser{
+		// first, since it replaces the schema the other tags refine
+		newSingleLineTagParser("SchemaFile", &setSchemaFile{builder: s, schema: ps, field: fld, property: nm}),
 		newSingleLineTagParser("maximum", &setMaximum{schemaValidations{ps}, rxf(rxMaximumFmt, "")}),
 		newSingleLineTagParser("minimum", &setMinimum{schemaValidations{ps}, rxf(rxMinimumFmt, "")}),
 		newSingleLineTagParser("multipleOf", &setMultipleOf{schemaValidations{ps}, rxf(rxMultipleOfFmt, "")}),
 		newSingleLineTagParser("minLength", &setMinLength{schemaValidations{ps}, rxf(rxMinLengthFmt, "")}),
 		newSingleLineTagParser("maxLength", &setMaxLength{schemaValidations{ps}, rxf(rxMaxLengthFmt, "")}),
 		newSingleLineTagParser("pattern", &setPattern{schemaValidations{ps}, rxf(rxPatternFmt, "")}),
-		newSingleLineTagParser("minItems", &setMinItems{schemaValidations{ps}, rxf(rxMinItemsFmt, "")}),
-		newSingleLineTagParser("maxItems", &setMaxItems{schemaValidations{ps}, rxf(rxMaxItemsFmt, "")}),
+		newSingleLineTagParser("minItems", &setMinItems{schemaValidations{ps}, rxf(rxMinItemsFmt, ""), s.decl.Pkg.Types}),
+		newSingleLineTagParser("maxItems", &setMaxItems{schemaValidations{ps}, rxf(rxMaxItemsFmt, ""), s.decl.Pkg.Types}),
 		newSingleLineTagParser("unique", &setUnique{schemaValidations{ps}, rxf(rxUniqueFmt, "")}),
 		newSingleLineTagParser("enum", &setEnum{schemaValidations{ps}, rxf(rxEnumFmt, "")}),
 		newSingleLineTagParser("default", &setDefault{&spec.SimpleSchema{Type: string(schemeType)}, schemaValidations{ps}, rxf(rxDefaultFmt, "")}),
@@ -1518,7 +1913,9 @@ func (s *schemaBuilder) createParser(nm string, schema, ps *spec.Schema, fld *as
 		newSingleLineTagParser("example", &setExample{&spec.SimpleSchema{Type: string(schemeType)}, schemaValidations{ps}, rxf(rxExampleFmt, "")}),
 		newSingleLineTagParser("required", &setRequiredSchema{schema, nm}),
 		newSingleLineTagParser("readOnly", &setReadOnlySchema{ps}),
+		newSingleLineTagParser("customTag", &setCustomTagSchema{ps}),
 		newSingleLineTagParser("discriminator", &setDiscriminator{schema, nm}),
+		newSingleLineTagParser("Types", &setUnionTypes{builder: s, schema: ps, field: fld, property: nm}),
 		newMultiLineTagParser("YAMLExtensionsBlock", newYamlParser(rxExtensions, schemaVendorExtensibleSetter(ps)), true),
 	}
 
@@ -1536,8 +1933,8 @@ func (s *schemaBuilder) createParser(nm string, schema, ps *spec.Schema, fld *as
 			newSingleLineTagParser(fmt.Sprintf("items%dMinLength", level), &setMinLength{schemaValidations{items}, rxf(rxMinLengthFmt, itemsPrefix)}),
 			newSingleLineTagParser(fmt.Sprintf("items%dMaxLength", level), &setMaxLength{schemaValidations{items}, rxf(rxMaxLengthFmt, itemsPrefix)}),
 			newSingleLineTagParser(fmt.Sprintf("items%dPattern", level), &setPattern{schemaValidations{items}, rxf(rxPatternFmt, itemsPrefix)}),
-			newSingleLineTagParser(fmt.Sprintf("items%dMinItems", level), &setMinItems{schemaValidations{items}, rxf(rxMinItemsFmt, itemsPrefix)}),
-			newSingleLineTagParser(fmt.Sprintf("items%dMaxItems", level), &setMaxItems{schemaValidations{items}, rxf(rxMaxItemsFmt, itemsPrefix)}),
+			newSingleLineTagParser(fmt.Sprintf("items%dMinItems", level), &setMinItems{schemaValidations{items}, rxf(rxMinItemsFmt, itemsPrefix), s.decl.Pkg.Types}),
+			newSingleLineTagParser(fmt.Sprintf("items%dMaxItems", level), &setMaxItems{schemaValidations{items}, rxf(rxMaxItemsFmt, itemsPrefix), s.decl.Pkg.Types}),
 			newSingleLineTagParser(fmt.Sprintf("items%dUnique", level), &setUnique{schemaValidations{items}, rxf(rxUniqueFmt, itemsPrefix)}),
 			newSingleLineTagParser(fmt.Sprintf("items%dEnum", level), &setEnum{schemaValidations{items}, rxf(rxEnumFmt, itemsPrefix)}),
 			newSingleLineTagParser(fmt.Sprintf("items%dDefault", level), &setDefault{&spec.SimpleSchema{Type: string(schemeType)}, schemaValidations{items}, rxf(rxDefaultFmt, itemsPrefix)}),
@@ -1599,6 +1996,29 @@ func (s *schemaBuilder) createParser(nm string, schema, ps *spec.Schema, fld *as
 	return sp
 }
 
+// describeRef keeps the description of a $ref, depending on the options: next to the $ref with DescWithRef,
+// in an allOf wrapping it with WrapRefsForDescription, else it is left out, as JSON schema draft 4 ignores the
+// siblings of $ref.
+func (s *schemaBuilder) describeRef(ps *spec.Schema, description string) {
+	if description == "" || ps.Ref.String() == "" {
+		return
+	}
+	switch {
+	case s.ctx.opts.DescWithRef:
+		ps.Description = description
+	case s.ctx.opts.WrapRefsForDescription:
+		*ps = spec.Schema{SchemaProps: spec.SchemaProps{Description: description, AllOf: []spec.Schema{*ps}}}
+	}
+}
+
+// fieldDescription returns the description of a field, before its annotations and tags.
+func fieldDescription(doc *ast.CommentGroup) string {
+	var description string
+	sp := &sectionedParser{setDescription: func(lines []string) { description = joinDropLast(lines) }}
+	_ = sp.Parse(doc)
+	return description
+}
+
 func schemaVendorExtensibleSetter(meta *spec.Schema) func(json.RawMessage) error {
 	return func(jsonValue json.RawMessage) error {
 		var jsonData spec.Extensions
@@ -1606,7 +2026,7 @@ func schemaVendorExtensibleSetter(meta *spec.Schema) func(json.RawMessage) error
 		if err != nil {
 			return err
 		}
-		for k := range jsonData {
+		for _, k := range sortedKeys(jsonData) {
 			if !rxAllowedExtensions.MatchString(k) {
 				return fmt.Errorf("invalid schema extension name, should start from `x-`: %s", k)
 			}
@@ -1631,6 +2051,11 @@ func (t tagOptions) Name() string {
 	return t[0]
 }
 
+// jsonIgnored tells if a struct tag drops its field from the JSON, with json:"-", e.g. to skip an embedded struct.
+func jsonIgnored(tag string) bool {
+	return reflect.StructTag(tag).Get("json") == "-"
+}
+
 func parseJSONTag(field *ast.Field) (name string, ignore, isString, omitEmpty bool, err error) {
 	if len(field.Names) > 0 {
 		name = field.Names[0].Name
@@ -1648,12 +2073,8 @@ func parseJSONTag(field *ast.Field) (name string, ignore, isString, omitEmpty bo
 		st := reflect.StructTag(tv)
 		jsonParts := tagOptions(strings.Split(st.Get("json"), ","))
 
-		if jsonParts.Contain("string") {
-			// Need to check if the field type is a scalar. Otherwise, the
-			// ",string" directive doesn't apply.
-			isString = isFieldStringable(field.Type)
-		}
-
+		// the ",string" directive applies to scalars only, see quotedBasic
+		isString = jsonParts.Contain("string")
 		omitEmpty = jsonParts.Contain("omitempty")
 
 		switch jsonParts.Name() {
@@ -1668,33 +2089,37 @@ func parseJSONTag(field *ast.Field) (name string, ignore, isString, omitEmpty bo
 	return name, false, false, false, nil
 }
 
-// isFieldStringable check if the field type is a scalar. If the field type is
-// *ast.StarExpr and is pointer type, check if it refers to a scalar.
-// Otherwise, the ",string" directive doesn't apply.
-func isFieldStringable(tpe ast.Expr) bool {
-	if ident, ok := tpe.(*ast.Ident); ok {
-		switch ident.Name {
-		case "int", "int8", "int16", "int32", "int64",
-			"uint", "uint8", "uint16", "uint32", "uint64",
-			"float64", "string", "bool":
-			return true
-		}
-	} else if starExpr, ok := tpe.(*ast.StarExpr); ok {
-		return isFieldStringable(starExpr.X)
-	} else {
-		return false
+// quotedBasic returns the basic type of a field which the ",string" directive of encoding/json encodes as a
+// JSON string: a boolean, a number or a string, named or not, or a pointer to one. The directive doesn't apply
+// to the other types, nor to the types with a marshaler of their own.
+func quotedBasic(tpe types.Type) (*types.Basic, bool) {
+	if ptr, isPointer := types.Unalias(tpe).(*types.Pointer); isPointer {
+		tpe = ptr.Elem()
 	}
-	return false
+	if hasMarshaler(tpe) {
+		return nil, false
+	}
+	basic, isBasic := tpe.Underlying().(*types.Basic)
+	if !isBasic || basic.Info()&(types.IsBoolean|types.IsNumeric|types.IsString) == 0 || basic.Info()&types.IsComplex != 0 {
+		return nil, false
+	}
+	return basic, true
 }
 
-func isTextMarshaler(tpe types.Type) bool {
-	encodingPkg, err := importer.Default().Import("encoding")
-	if err != nil {
-		return false
-	}
-	ifc := encodingPkg.Scope().Lookup("TextMarshaler").Type().Underlying().(*types.Interface) // TODO: there is a better way to check this
+// textMarshaler is the interface of encoding.TextMarshaler, made once rather than imported by each check.
+var textMarshaler = sync.OnceValue(func() *types.Interface {
+	bytes := types.NewSlice(types.Typ[types.Byte])
+	results := types.NewTuple(
+		types.NewVar(token.NoPos, nil, "", bytes),
+		types.NewVar(token.NoPos, nil, "", types.Universe.Lookup("error").Type()),
+	)
+	signature := types.NewSignatureType(nil, nil, nil, nil, results, false)
+	ifc := types.NewInterfaceType([]*types.Func{types.NewFunc(token.NoPos, nil, "MarshalText", signature)}, nil)
+	return ifc.Complete()
+})
 
-	return types.Implements(tpe, ifc)
+func isTextMarshaler(tpe types.Type) bool {
+	return types.Implements(tpe, textMarshaler())
 }
 
 func isStdTime(o *types.TypeName) bool {
@@ -1705,10 +2130,6 @@ func isStdError(o *types.TypeName) bool {
 	return o.Pkg() == nil && o.Name() == "error"
 }
 
-func isStdJSONRawMessage(o *types.TypeName) bool {
-	return o.Pkg() != nil && o.Pkg().Path() == "encoding/json" && o.Name() == "RawMessage"
-}
-
 func isAny(o *types.TypeName) bool {
 	return o.Pkg() == nil && o.Name() == "any"
 }
@@ -7,6 +7,8 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -347,11 +349,20 @@ func TestEmbeddedTypes(t *testing.T) {
 	require.NoError(t, prs.Build(models))
 	schema := models["ComplexerOne"]
 	assertProperty(t, &schema, "integer", "age", "int32", "Age")
-	assertProperty(t, &schema, "integer", "id", "int64", "ID")
 	assertProperty(t, &schema, "string", "createdAt", "date-time", "CreatedAt")
 	assertProperty(t, &schema, "string", "extra", "", "Extra")
-	assertProperty(t, &schema, "string", "name", "", "Name")
 	assertProperty(t, &schema, "string", "notes", "", "Notes")
+
+	// SimpleOne and NotSelected both have id and name fields, which encoding/json drops as ambiguous
+	assert.NotContains(t, schema.Properties, "id")
+	assert.NotContains(t, schema.Properties, "name")
+	var dropped int
+	for _, diagnostic := range sctx.app.diagnosticsWithCode(DiagnosticJSONNameConflict) {
+		if strings.HasPrefix(diagnostic.Message, "field ComplexerOne.") {
+			dropped++
+		}
+	}
+	assert.Equal(t, 4, dropped)
 }
 
 func TestParsePrimitiveSchemaProperty(t *testing.T) {
@@ -1489,7 +1500,10 @@ func TestSpecialSchemas(t *testing.T) {
 	t.Setenv("SWAGGER_GENERATE_EXTENSION", "true")
 
 	fixturesPath := filepath.Join("..", "fixtures", "goparsing", "go123", "special")
-	var sp *spec.Swagger
+	var (
+		sp          *spec.Swagger
+		diagnostics []Diagnostic
+	)
 
 	t.Run("end-to-end source scan should succeed", func(t *testing.T) {
 		var err error
@@ -1498,6 +1512,7 @@ func TestSpecialSchemas(t *testing.T) {
 			BuildTags:  "testscanner", // fixture code is excluded from normal build
 			ScanModels: true,
 			RefAliases: true,
+			Logger:     func(diagnostic Diagnostic) { diagnostics = append(diagnostics, diagnostic) },
 		})
 		require.NoError(t, err)
 	})
@@ -1631,28 +1646,30 @@ func TestSpecialSchemas(t *testing.T) {
 				})
 			})
 
-			t.Run("a json.RawMessage should be recognized and render as an object (yes this is wrong)", func(t *testing.T) {
+			t.Run("a json.RawMessage should be recognized and render as any value", func(t *testing.T) {
 				m, ok := props["Message"]
 				require.True(t, ok)
-				require.True(t, m.Type.Contains("object"))
+				assert.Empty(t, m.Type)
+				assert.Empty(t, m.Ref.String())
+				assert.Nil(t, m.Items)
+
+				t.Run("the mapped type should not be discovered as a definition", func(t *testing.T) {
+					assert.NotContains(t, sp.Definitions, "RawMessage")
+					// with GOEXPERIMENT=jsonv2, json.RawMessage is an alias of jsontext.Value, and the Value
+					// definition is that of reflect.Value
+					assert.Equal(t, "reflect", sp.Definitions["Value"].Extensions["x-go-package"])
+				})
 			})
 
-			t.Run("type time.Duration is not recognized as a special type and should just render as a ref", func(t *testing.T) {
+			t.Run("type time.Duration should render with its built-in mapping, as int64 nanoseconds", func(t *testing.T) {
 				d, ok := props["Duration"]
 				require.True(t, ok)
-				assertIsRef(t, &d, "#/definitions/Duration")
-
-				t.Run("discovered definition should be an integer", func(t *testing.T) {
-					duration, ok := sp.Definitions["Duration"]
-					require.True(t, ok)
-					require.True(t, duration.Type.Contains("integer"))
-					require.Equal(t, "int64", duration.Format)
+				assert.Empty(t, d.Ref.String())
+				require.True(t, d.Type.Contains("integer"))
+				require.Equal(t, "int64", d.Format)
 
-					t.Run("time.Duration schema should be decorated with a x-go-package: time", func(t *testing.T) {
-						val, hasExt := duration.Extensions.GetString("x-go-package")
-						assert.True(t, hasExt)
-						assert.Equal(t, "time", val)
-					})
+				t.Run("the mapped type should not be discovered as a definition", func(t *testing.T) {
+					assert.NotContains(t, sp.Definitions, "Duration")
 				})
 			})
 
@@ -1705,12 +1722,26 @@ func TestSpecialSchemas(t *testing.T) {
 
 					markedProps := make([]string, 0)
 
+					for _, skippedProp := range []string{
+						"B", // chan int
+						"C", // func()
+						"D", // func() string
+					} {
+						t.Run("with property "+skippedProp, func(t *testing.T) {
+							_, ok := wn.Properties[skippedProp]
+							require.False(t, ok, "channels and functions are skipped")
+
+							t.Run("the skipped field should be reported", func(t *testing.T) {
+								assert.True(t, slices.ContainsFunc(diagnostics, func(diagnostic Diagnostic) bool {
+									return diagnostic.Code == DiagnosticSkippedField && strings.HasPrefix(diagnostic.Message, "field "+skippedProp+" is skipped")
+								}))
+							})
+						})
+					}
+
 					for _, unsupportedProp := range []string{
 						"AA", // complex128
 						"A",  // complex64
-						"B",  // chan int
-						"C",  // func()
-						"D",  // func() string
 						"E",  // unsafe.Pointer
 					} {
 						t.Run("with property "+unsupportedProp, func(t *testing.T) {
@@ -2507,3 +2538,426 @@ func testIssue2540(descWithRef bool, expectedJSON string) func(*testing.T) {
 		assert.JSONEq(t, expectedJSON, string(b))
 	}
 }
+
+func TestRequiredFromPointers(t *testing.T) {
+	const (
+		rootPath   = "github.com/3idey/codescan/fixtures/goparsing/requiredptr"
+		modelsPath = rootPath + "/models"
+	)
+
+	buildModel := func(t *testing.T, opts *Options, packagePath, modelName string) spec.Schema {
+		t.Helper()
+		opts.Packages = []string{rootPath + "/..."}
+		sctx, err := newScanCtx(opts)
+		require.NoError(t, err)
+
+		decl, _ := sctx.FindDecl(packagePath, modelName)
+		require.NotNil(t, decl)
+		prs := &schemaBuilder{
+			ctx:  sctx,
+			decl: decl,
+		}
+		models := make(map[string]spec.Schema)
+		require.NoError(t, prs.Build(models))
+
+		return models[modelName]
+	}
+
+	t.Run("should infer required fields from pointers", func(t *testing.T) {
+		schema := buildModel(t, &Options{RequiredFromPointers: true}, modelsPath, "Widget")
+		assert.ElementsMatch(t, []string{"id", "name", "owner"}, schema.Required)
+
+		envelope := buildModel(t, &Options{RequiredFromPointers: true}, rootPath, "Envelope")
+		assert.ElementsMatch(t, []string{"code", "widget"}, envelope.Required)
+	})
+
+	t.Run("should restrict the convention to package globs", func(t *testing.T) {
+		opts := func() *Options {
+			return &Options{
+				RequiredFromPointers:         true,
+				RequiredFromPointersPackages: []string{"*/*/*/fixtures/goparsing/requiredptr/models/..."},
+			}
+		}
+		schema := buildModel(t, opts(), modelsPath, "Widget")
+		assert.ElementsMatch(t, []string{"id", "name", "owner"}, schema.Required)
+
+		envelope := buildModel(t, opts(), rootPath, "Envelope")
+		assert.Empty(t, envelope.Required)
+	})
+
+	t.Run("should leave required fields alone when disabled", func(t *testing.T) {
+		schema := buildModel(t, &Options{}, modelsPath, "Widget")
+		assert.Equal(t, []string{"owner"}, schema.Required)
+	})
+}
+
+func TestJSONTagOptions(t *testing.T) {
+	const packagePath = "github.com/3idey/codescan/fixtures/goparsing/jsontags"
+
+	scan := func(t *testing.T, opts *Options) *spec.Swagger {
+		t.Helper()
+		opts.Packages = []string{packagePath}
+		opts.ScanModels = true
+		doc, err := Run(opts)
+		require.NoError(t, err)
+		return doc
+	}
+
+	t.Run("should drop the fields tagged - through embedding", func(t *testing.T) {
+		doc := scan(t, &Options{})
+
+		account := doc.Definitions["Account"]
+		assert.NotContains(t, account.Properties, "Revision", "a field tagged - in an embedded struct")
+		assert.NotContains(t, account.Properties, "Secret", "a field tagged - in a struct embedded through a pointer")
+		assert.NotContains(t, account.Properties, "X-Leak", "the fields of an embedded struct tagged -")
+		assert.Contains(t, account.Properties, "enabled")
+
+		op := doc.Paths.Paths["/accounts"].Get
+		require.NotNil(t, op)
+		require.Len(t, op.Parameters, 1)
+		assert.Equal(t, "name", op.Parameters[0].Name)
+
+		response := doc.Responses["accountsResponse"]
+		assert.Contains(t, response.Headers, "X-Total")
+		assert.NotContains(t, response.Headers, "X-Leak")
+	})
+
+	t.Run("should make strings of the scalars tagged string", func(t *testing.T) {
+		doc := scan(t, &Options{})
+
+		account := doc.Definitions["Account"]
+		assertProperty(t, &account, "string", "balance", "int64", "Balance")
+		assertProperty(t, &account, "string", "limit", "int64", "Limit")
+		assertProperty(t, &account, "string", "ratio", "float", "Ratio")
+		assertProperty(t, &account, "string", "owner", "int64", "Owner")
+		assertProperty(t, &account, "string", "active", "", "Active")
+		assertProperty(t, &account, "string", "name", "", "Name")
+		owner := account.Properties["owner"]
+		assert.Empty(t, owner.Ref.String(), "a named scalar is inlined as a string")
+		assert.True(t, account.Properties["tags"].Type.Contains("array"), "the directive doesn't apply to slices")
+
+		event := doc.Definitions["Event"]
+		assertProperty(t, &event, "string", "owner", "int64", "Owner")
+		assert.Empty(t, event.Properties["at"].Format, "a marshaler keeps its schema")
+		assert.Equal(t, "github.com/3idey/codescan/fixtures/goparsing/jsontags.Stamp", event.Properties["at"].Extensions["x-go-type"])
+		assert.True(t, event.Properties["ratios"].Type.Contains("array"), "the directive doesn't apply to arrays")
+	})
+
+	t.Run("should keep the required omitempty fields by default", func(t *testing.T) {
+		doc := scan(t, &Options{})
+
+		account := doc.Definitions["Account"]
+		assert.ElementsMatch(t, []string{"createdBy", "balance", "name"}, account.Required)
+	})
+
+	t.Run("should make the omitempty fields optional with OmitEmptyAsOptional", func(t *testing.T) {
+		doc := scan(t, &Options{OmitEmptyAsOptional: true})
+
+		account := doc.Definitions["Account"]
+		assert.ElementsMatch(t, []string{"createdBy", "balance"}, account.Required)
+
+		doc = scan(t, &Options{OmitEmptyAsOptional: true, RequiredFromPointers: true})
+		account = doc.Definitions["Account"]
+		assert.ElementsMatch(t, []string{"createdBy", "balance", "ratio", "owner", "active", "tags"}, account.Required)
+		assert.NotContains(t, account.Required, "enabled", "promoted through an embedded pointer")
+	})
+}
+
+func TestDeclarationOrder(t *testing.T) {
+	const packagePath = "github.com/3idey/codescan/fixtures/goparsing/ordering"
+
+	buildItem := func(t *testing.T, declarationOrder bool) spec.Schema {
+		t.Helper()
+		sctx, err := newScanCtx(&Options{Packages: []string{packagePath}, DeclarationOrder: declarationOrder})
+		require.NoError(t, err)
+		decl, _ := sctx.FindDecl(packagePath, "Item")
+		require.NotNil(t, decl)
+		prs := &schemaBuilder{
+			ctx:  sctx,
+			decl: decl,
+		}
+		models := make(map[string]spec.Schema)
+		require.NoError(t, prs.Build(models))
+
+		return models["Item"]
+	}
+
+	t.Run("should emit x-order following declaration order", func(t *testing.T) {
+		schema := buildItem(t, true)
+		for name, order := range map[string]int{
+			"zeta": 0, "id": 1, "created": 2, "alpha": 3, "note": 4, "mid": 5, "nested": 6,
+		} {
+			require.Contains(t, schema.Properties, name)
+			assert.Equal(t, order, schema.Properties[name].Extensions["x-order"], name)
+		}
+
+		nested := schema.Properties["nested"]
+		assert.Equal(t, 0, nested.Properties["second"].Extensions["x-order"])
+		assert.Equal(t, 1, nested.Properties["first"].Extensions["x-order"])
+
+		jazon, err := MarshalJSON(schema, true)
+		require.NoError(t, err)
+		assert.Regexp(t, `"properties":\{"zeta":.*"id":.*"created":.*"alpha":.*"note":.*"mid":.*"nested":\{.*"properties":\{"second":.*"first":`, string(jazon))
+	})
+
+	t.Run("should not emit x-order by default", func(t *testing.T) {
+		schema := buildItem(t, false)
+		for name, prop := range schema.Properties {
+			assert.NotContains(t, prop.Extensions, "x-order", name)
+		}
+	})
+}
+
+func TestCustomTag(t *testing.T) {
+	const packagePath = "github.com/3idey/codescan/fixtures/goparsing/customtag"
+
+	sctx, err := newScanCtx(&Options{Packages: []string{packagePath}})
+	require.NoError(t, err)
+	decl, _ := sctx.FindDecl(packagePath, "Account")
+	require.NotNil(t, decl)
+	prs := &schemaBuilder{
+		ctx:  sctx,
+		decl: decl,
+	}
+	models := make(map[string]spec.Schema)
+	require.NoError(t, prs.Build(models))
+
+	schema := models["Account"]
+	const userTag = `gorm:"column:user_id;index" validate:"required"`
+	userID := schema.Properties["userId"]
+	assert.Equal(t, userTag, userID.Extensions["x-go-custom-tag"])
+	assert.Equal(t, "The owner of the account.", userID.Description)
+	assert.Equal(t, `db:"display_name"`, schema.Properties["name"].Extensions["x-go-custom-tag"])
+	assert.NotContains(t, schema.Properties["balance"].Extensions, "x-go-custom-tag")
+
+	t.Run("should survive JSON and YAML round-trips", func(t *testing.T) {
+		jazon, err := MarshalJSON(schema, true)
+		require.NoError(t, err)
+		var fromJSON spec.Schema
+		require.NoError(t, json.Unmarshal(jazon, &fromJSON))
+		assert.Equal(t, userTag, fromJSON.Properties["userId"].Extensions["x-go-custom-tag"])
+
+		yml, err := MarshalYAML(schema)
+		require.NoError(t, err)
+		var fromYAML struct {
+			Properties map[string]map[string]any `yaml:"properties"`
+		}
+		require.NoError(t, yaml.Unmarshal(yml, &fromYAML))
+		assert.Equal(t, userTag, fromYAML.Properties["userId"]["x-go-custom-tag"])
+	})
+}
+
+func TestDerivedModels(t *testing.T) {
+	const packagePath = "github.com/3idey/codescan/fixtures/goparsing/derive"
+
+	sctx, err := newScanCtx(&Options{Packages: []string{packagePath}})
+	require.NoError(t, err)
+	build := func(t *testing.T, name string, models map[string]spec.Schema) error {
+		t.Helper()
+		decl, _ := sctx.FindDecl(packagePath, name)
+		require.NotNil(t, decl)
+		prs := &schemaBuilder{
+			ctx:  sctx,
+			decl: decl,
+		}
+		return prs.Build(models)
+	}
+
+	models := make(map[string]spec.Schema)
+	require.NoError(t, build(t, "UpdateUserRequest", models))
+	require.NoError(t, build(t, "createUser", models))
+
+	require.Contains(t, models, "User", "the source of a derived model is built too")
+	user := models["User"]
+	assert.ElementsMatch(t, []string{"id", "email", "name", "createdAt"}, user.Required)
+
+	update := models["updateUser"]
+	assert.ElementsMatch(t, []string{"email", "name"}, sortedKeys(update.Properties))
+	assert.Empty(t, update.Required)
+	assert.Equal(t, "UpdateUserRequest updates some fields of a user.", update.Title)
+	assert.Equal(t, "User", update.Extensions["x-derived-from"])
+	assert.Equal(t, "UpdateUserRequest", update.Extensions["x-go-name"])
+
+	create := models["createUser"]
+	assert.ElementsMatch(t, []string{"email", "name"}, sortedKeys(create.Properties))
+	assert.Equal(t, []string{"email"}, create.Required)
+	assert.Equal(t, user.Title, create.Title)
+	assert.NotContains(t, create.Extensions, "x-go-name")
+
+	err = build(t, "broken", models)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `derives from unknown model "Missing"`)
+	assert.Contains(t, err.Error(), "models.go:")
+}
+
+func TestAllOfRefs(t *testing.T) {
+	const packagePath = "github.com/3idey/codescan/fixtures/goparsing/compose"
+
+	sctx, err := newScanCtx(&Options{Packages: []string{packagePath}})
+	require.NoError(t, err)
+	build := func(t *testing.T, name string, models map[string]spec.Schema) error {
+		t.Helper()
+		decl, _ := sctx.FindDecl(packagePath, name)
+		require.NotNil(t, decl)
+		prs := &schemaBuilder{
+			ctx:  sctx,
+			decl: decl,
+		}
+		return prs.Build(models)
+	}
+
+	models := map[string]spec.Schema{
+		"Audit": *spec.StringProperty(), // supplied by the input spec
+	}
+	for _, name := range []string{"Dog", "Show", "Owner"} {
+		require.NoError(t, build(t, name, models))
+	}
+
+	refs := func(schema spec.Schema) []string {
+		var result []string
+		for _, member := range schema.AllOf {
+			result = append(result, member.Ref.String())
+		}
+		return result
+	}
+
+	dog := models["Dog"]
+	assert.Equal(t, []string{"#/definitions/Pet", "#/definitions/Audit", ""}, refs(dog),
+		"embedded members come first, then the members declared by name, then the properties")
+	assert.Equal(t, "dog", dog.Extensions["x-class"])
+	assert.Equal(t, "kind", dog.Discriminator, "the discriminator is set on the composed type")
+	assert.Empty(t, dog.AllOf[2].Discriminator)
+	assert.ElementsMatch(t, []string{"kind", "barks"}, sortedKeys(dog.AllOf[2].Properties))
+
+	show := models["Show"]
+	require.Len(t, show.AllOf, 3)
+	assert.Equal(t, []string{"#/definitions/Audit", ""}, refs(show.AllOf[0]), "a member may itself be composed")
+	assert.Equal(t, "#/definitions/Dog", show.AllOf[1].Ref.String())
+	assert.ElementsMatch(t, []string{"venue"}, sortedKeys(show.AllOf[2].Properties))
+
+	owner := models["Owner"]
+	assert.Empty(t, owner.Type)
+	assert.Empty(t, owner.Properties)
+	assert.Equal(t, []string{"#/definitions/Pet", ""}, refs(owner))
+	assert.ElementsMatch(t, []string{"street", "phone"}, sortedKeys(owner.AllOf[1].Properties))
+
+	err = build(t, "Broken", models)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `composed with unknown definition "Missing"`)
+	assert.Contains(t, err.Error(), "models.go:")
+}
+
+func TestDescriptionsOfRefs(t *testing.T) {
+	scan := func(t *testing.T, opts Options) spec.Definitions {
+		t.Helper()
+		opts.Packages = []string{"github.com/3idey/codescan/fixtures/goparsing/descref"}
+		opts.ScanModels = true
+		doc, err := Run(&opts)
+		require.NoError(t, err)
+		return doc.Definitions
+	}
+	refs := func(definitions spec.Definitions) map[string]spec.Schema {
+		owner, kennel := definitions["Owner"], definitions["Kennel"]
+		require.Len(t, owner.AllOf, 2)
+		return map[string]spec.Schema{
+			"the base of the owner": owner.AllOf[0],
+			"the favorite pet":      owner.AllOf[1].Properties["favorite"],
+			"the pet of the box":    kennel.Properties["boxes"].Items.Schema.Properties["pet"],
+			"the pet of the room":   kennel.Properties["rooms"].AdditionalProperties.Schema.Properties["pet"],
+		}
+	}
+
+	t.Run("should leave out the descriptions next to the refs by default", func(t *testing.T) {
+		for description, schema := range refs(scan(t, Options{})) {
+			assert.NotEmpty(t, schema.Ref.String(), description)
+			assert.Empty(t, schema.Description, description)
+		}
+	})
+
+	t.Run("should write the descriptions next to the refs at every level with DescWithRef", func(t *testing.T) {
+		for description, schema := range refs(scan(t, Options{DescWithRef: true})) {
+			assert.NotEmpty(t, schema.Ref.String(), description)
+			assert.Equal(t, description, schema.Description)
+		}
+	})
+
+	t.Run("should wrap the refs in an allOf with WrapRefsForDescription", func(t *testing.T) {
+		definitions := scan(t, Options{WrapRefsForDescription: true})
+		for description, schema := range refs(definitions) {
+			assert.Empty(t, schema.Ref.String(), description)
+			assert.Equal(t, description, schema.Description)
+			require.Len(t, schema.AllOf, 1, description)
+			assert.NotEmpty(t, schema.AllOf[0].Ref.String(), description)
+		}
+		pets := definitions["Owner"].AllOf[1].Properties["pets"]
+		assert.Equal(t, "#/definitions/Pet", pets.Items.Schema.Ref.String(), "the refs without description are left as they are")
+	})
+
+	t.Run("should reject DescWithRef with WrapRefsForDescription", func(t *testing.T) {
+		err := (&Options{Packages: []string{"./..."}, DescWithRef: true, WrapRefsForDescription: true}).Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "WrapRefsForDescription")
+	})
+}
+
+func TestMarshalerReceivers(t *testing.T) {
+	doc, err := Run(&Options{
+		Packages:   []string{"github.com/3idey/codescan/fixtures/goparsing/marshalers"},
+		ScanModels: true,
+	})
+	require.NoError(t, err)
+	matrix := doc.Definitions["Matrix"]
+	property := func(t *testing.T, schema spec.Schema, name string) spec.Schema {
+		t.Helper()
+		prop, found := schema.Properties[name]
+		require.True(t, found, name)
+		return prop
+	}
+	assertString := func(t *testing.T, schema spec.Schema, format, name string) {
+		t.Helper()
+		assert.Empty(t, schema.Ref.String(), name)
+		assert.Equal(t, spec.StringOrArray{"string"}, schema.Type, name)
+		assert.Equal(t, format, schema.Format, name)
+	}
+	assertRef := func(t *testing.T, schema spec.Schema, definition, name string) {
+		t.Helper()
+		ref := schema.Ref
+		assert.Equal(t, "#/definitions/"+definition, ref.String(), name)
+	}
+
+	t.Run("should call the marshalers of the value receivers", func(t *testing.T) {
+		for _, name := range []string{"valueText", "valueTextPtr"} {
+			assertString(t, property(t, matrix, name), "", name)
+		}
+		for _, name := range []string{"valueRange", "valueRangePtr"} {
+			assertString(t, property(t, matrix, name), "duration", name)
+		}
+	})
+
+	t.Run("should call the marshalers of the pointer receivers on the pointers only", func(t *testing.T) {
+		assertString(t, property(t, matrix, "pointerTextPtr"), "", "pointerTextPtr")
+		assertString(t, property(t, matrix, "pointerRangePtr"), "duration", "pointerRangePtr")
+		assertString(t, property(t, matrix, "pointerCodePtr"), "hostname", "pointerCodePtr")
+
+		assertRef(t, property(t, matrix, "pointerText"), "PointerText", "pointerText")
+		assertRef(t, property(t, matrix, "pointerRange"), "PointerRange", "pointerRange")
+		assertRef(t, property(t, matrix, "pointerCode"), "PointerCode", "pointerCode")
+
+		assert.ElementsMatch(t, []string{"from", "to"}, sortedKeys(doc.Definitions["PointerRange"].Properties))
+		assert.ElementsMatch(t, []string{"text"}, sortedKeys(doc.Definitions["PointerText"].Properties))
+		code := doc.Definitions["PointerCode"]
+		assert.Equal(t, spec.StringOrArray{"integer"}, code.Type)
+		assert.Equal(t, "int64", code.Format)
+	})
+
+	t.Run("should call the marshalers of the pointer receivers on the addressable elements", func(t *testing.T) {
+		assertString(t, *property(t, matrix, "pointerTexts").Items.Schema, "", "pointerTexts")
+		assertString(t, *property(t, matrix, "pointerRanges").Items.Schema, "duration", "pointerRanges")
+		assertString(t, *property(t, matrix, "pointerTextArrayPtr").Items.Schema, "", "pointerTextArrayPtr")
+		assertString(t, property(t, property(t, matrix, "nested"), "pointerRange"), "duration", "nested")
+
+		assertRef(t, *property(t, matrix, "pointerTextMap").AdditionalProperties.Schema, "PointerText", "pointerTextMap")
+		assertRef(t, *property(t, matrix, "pointerRangeMap").AdditionalProperties.Schema, "PointerRange", "pointerRangeMap")
+		assertRef(t, *property(t, matrix, "pointerTextArray").Items.Schema, "PointerText", "pointerTextArray")
+	})
+}
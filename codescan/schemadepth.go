@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"fmt"
+	"go/token"
+	"strings"
+)
+
+// schemaStep is a nesting level of the schema being built: a struct field, the items of a slice or an array,
+// or the values of a map.
+type schemaStep struct {
+	selector string // Go selector of the level, e.g. ".Servers", "[i]" or "[key]"
+	pos      token.Pos
+}
+
+const (
+	itemsSelector  = "[i]"
+	valuesSelector = "[key]"
+)
+
+// enterSchema nests the schema being built one level deeper, checking Options.MaxSchemaDepth.
+// The level is left with leaveSchema, unless an error is returned.
+func (s *schemaBuilder) enterSchema(selector string, pos token.Pos) error {
+	s.schemaPath = append(s.schemaPath, schemaStep{selector: selector, pos: pos})
+	maxDepth := s.ctx.app.maxSchemaDepth
+	if maxDepth <= 0 || len(s.schemaPath) <= maxDepth {
+		return nil
+	}
+
+	err := fmt.Errorf(
+		"%v: the schema of %s is nested deeper than %d levels at %s: promote a nested anonymous struct to a named type, which is built as a definition of its own",
+		s.schemaPosition(), s.decl.Obj().Name(), maxDepth, s.schemaSelector(),
+	)
+	s.leaveSchema()
+	return err
+}
+
+func (s *schemaBuilder) leaveSchema() {
+	s.schemaPath = s.schemaPath[:len(s.schemaPath)-1]
+}
+
+// schemaSelector renders the Go selector of the schema being built, e.g. "Config.Servers[i].TLS".
+func (s *schemaBuilder) schemaSelector() string {
+	var b strings.Builder
+	b.WriteString(s.decl.Obj().Name())
+	for _, step := range s.schemaPath {
+		b.WriteString(step.selector)
+	}
+	return b.String()
+}
+
+// schemaPosition is the position of the innermost field of the schema being built, or of its declaration.
+func (s *schemaBuilder) schemaPosition() token.Position {
+	for i := len(s.schemaPath) - 1; i >= 0; i-- {
+		if pos := s.schemaPath[i].pos; pos.IsValid() {
+			return s.decl.Pkg.Fset.Position(pos)
+		}
+	}
+	return s.decl.Pkg.Fset.Position(s.decl.Obj().Pos())
+}
@@ -0,0 +1,29 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const schemaDepthFixture = "github.com/3idey/codescan/fixtures/goparsing/schemadepth"
+
+func TestMaxSchemaDepth(t *testing.T) {
+	for _, depth := range []int{0, 6} {
+		doc, err := Run(&Options{Packages: []string{schemaDepthFixture}, ScanModels: true, MaxSchemaDepth: depth})
+		require.NoError(t, err)
+		servers := doc.Definitions["Config"].Properties["servers"]
+		tls := servers.Items.Schema.Properties["tls"]
+		options := tls.Properties["options"]
+		assert.Contains(t, options.AdditionalProperties.Schema.Properties, "value")
+	}
+
+	_, err := Run(&Options{Packages: []string{schemaDepthFixture}, ScanModels: true, MaxSchemaDepth: 5})
+	require.Error(t, err)
+	assert.Regexp(t, `models\.go:11:5: the schema of Config is nested deeper than 5 levels at Config\.Servers\[i\]\.TLS\.Options\[key\]\.Value: `, err.Error())
+	assert.Contains(t, err.Error(), "promote a nested anonymous struct to a named type")
+}
@@ -0,0 +1,424 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"gopkg.in/yaml.v3"
+)
+
+// setSchemaFile replaces the schema of a property, typically a JSON column, by the one of a JSON Schema file:
+//
+//	// Schema File: schemas/preferences.schema.json
+//	Preferences json.RawMessage `json:"preferences"`
+//
+// The path is relative to the directory of the Go file. The keywords swagger 2.0 can't express are dropped,
+// which is an unsupported-schema-keyword diagnostic.
+type setSchemaFile struct {
+	builder  *schemaBuilder
+	schema   *spec.Schema
+	field    *ast.Field
+	property string
+}
+
+func (sf *setSchemaFile) Matches(line string) bool {
+	return rxSchemaFile.MatchString(line)
+}
+
+func (sf *setSchemaFile) Parse(lines []string) error {
+	if len(lines) == 0 || (len(lines) == 1 && len(lines[0]) == 0) {
+		return nil
+	}
+	matches := rxSchemaFile.FindStringSubmatch(lines[0])
+	if len(matches) < 2 {
+		return nil
+	}
+
+	pos := sf.position()
+	file := matches[1]
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(filepath.Dir(pos.Filename), file)
+	}
+	conv := &schemaFileConverter{dir: filepath.Dir(pos.Filename), documents: make(map[string]any)}
+	converted, err := conv.convertFile(file)
+	if err != nil {
+		return fmt.Errorf("%v: %w in the Schema File of %s", pos, err, sf.subject())
+	}
+
+	app := sf.builder.ctx.app
+	for _, read := range conv.files {
+		if !slices.Contains(app.stats.SchemaFiles, read) {
+			app.stats.SchemaFiles = append(app.stats.SchemaFiles, read)
+		}
+	}
+	slices.Sort(app.stats.SchemaFiles)
+	if len(conv.dropped) > 0 {
+		app.diagnose(Diagnostic{
+			Pos:  pos,
+			Code: DiagnosticUnsupportedSchemaKeyword,
+			Message: fmt.Sprintf("the schema of %s drops what swagger 2.0 can't express of %s: %s",
+				sf.subject(), matches[1], strings.Join(conv.dropped, ", ")),
+		})
+	}
+
+	// the documentation of the field wins over the file, e.g. its description
+	description, extensions := sf.schema.Description, sf.schema.Extensions
+	*sf.schema = converted
+	if description != "" {
+		sf.schema.Description = description
+	}
+	for name, value := range extensions {
+		if _, set := sf.schema.Extensions[name]; !set {
+			sf.schema.AddExtension(name, value)
+		}
+	}
+	return nil
+}
+
+func (sf *setSchemaFile) subject() string {
+	typeName := sf.builder.decl.Obj().Name()
+	if sf.property == "" {
+		return typeName
+	}
+	return typeName + "." + sf.property
+}
+
+func (sf *setSchemaFile) position() token.Position {
+	decl := sf.builder.decl
+	if sf.field == nil {
+		return decl.Pkg.Fset.Position(decl.Obj().Pos())
+	}
+	return decl.Pkg.Fset.Position(sf.field.Pos())
+}
+
+// hasSchemaFile tells if the documentation of a field has a Schema File directive, which replaces its $ref.
+func hasSchemaFile(fld *ast.Field) bool {
+	if fld == nil || fld.Doc == nil {
+		return false
+	}
+	for _, c := range fld.Doc.List {
+		for line := range commentLines(c.Text) {
+			if rxSchemaFile.MatchString(line) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// schemaKeywords are the keywords of JSON Schema kept as they are in swagger 2.0.
+var schemaKeywords = []string{
+	"title", "description", "format", "default", "multipleOf", "maximum", "minimum", "maxLength", "minLength",
+	"pattern", "maxItems", "minItems", "uniqueItems", "maxProperties", "minProperties", "enum", "readOnly",
+	"example", "discriminator",
+}
+
+// schemaMetaKeywords are the keywords of JSON Schema identifying or holding schemas, rather than describing
+// values, which are left out silently.
+var schemaMetaKeywords = []string{"$schema", "$id", "id", "$comment", "definitions", "$defs"}
+
+// schemaFileConverter converts the subset of JSON Schema that swagger 2.0 supports. The $refs are inlined,
+// those to the files next to the schema included, and the recursive ones cut with an empty schema.
+type schemaFileConverter struct {
+	dir       string         // directory of the Go file, which the messages are relative to
+	documents map[string]any // the documents loaded, by path
+	files     []string       // the paths of the documents, in their order
+	expanding []string       // the $refs being inlined, as path#pointer
+	dropped   []string       // what is dropped, e.g. anyOf at preferences.schema.json#/properties/theme
+}
+
+func (c *schemaFileConverter) convertFile(file string) (spec.Schema, error) {
+	var converted spec.Schema
+	doc, err := c.load(file)
+	if err != nil {
+		return converted, err
+	}
+	c.expanding = []string{file + "#"}
+	node, err := c.convert(file, "", doc)
+	if err != nil {
+		return converted, err
+	}
+
+	// the converted schema is only made of JSON values, which the swagger schema reads back
+	data, err := json.Marshal(node)
+	if err != nil {
+		return converted, err
+	}
+	if err := json.Unmarshal(data, &converted); err != nil {
+		return converted, fmt.Errorf("invalid schema %s: %w", c.display(file), err)
+	}
+	return converted, nil
+}
+
+// load reads a JSON, or YAML, document once.
+func (c *schemaFileConverter) load(file string) (any, error) {
+	if doc, loaded := c.documents[file]; loaded {
+		return doc, nil
+	}
+	src, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc any
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(src, &doc)
+	default:
+		dec := json.NewDecoder(bytes.NewReader(src))
+		dec.UseNumber()
+		err = dec.Decode(&doc)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema %s: %w", c.display(file), err)
+	}
+	c.documents[file] = doc
+	c.files = append(c.files, file)
+	return doc, nil
+}
+
+// convert converts the schema at a JSON pointer of a document.
+func (c *schemaFileConverter) convert(file, pointer string, node any) (map[string]any, error) {
+	out := make(map[string]any)
+	switch schema := node.(type) {
+	case bool:
+		if !schema {
+			c.drop("false schema", file, pointer)
+		}
+		return out, nil
+	case map[string]any:
+		if ref, isRef := schema["$ref"].(string); isRef {
+			return c.convertRef(file, pointer, ref, schema)
+		}
+		return out, c.convertKeywords(file, pointer, schema, out)
+	default:
+		return nil, fmt.Errorf("invalid schema at %s: %v", c.location(file, pointer), node)
+	}
+}
+
+// convertRef inlines the target of a $ref, with its siblings, which apply since draft 2019-09.
+func (c *schemaFileConverter) convertRef(file, pointer, ref string, schema map[string]any) (map[string]any, error) {
+	target, fragment, _ := strings.Cut(ref, "#")
+	fragment, err := url.PathUnescape(fragment)
+	if err != nil {
+		return nil, fmt.Errorf("invalid $ref %q at %s: %w", ref, c.location(file, pointer), err)
+	}
+	switch {
+	case strings.Contains(target, ":"):
+		c.drop("remote $ref "+ref, file, pointer)
+		return make(map[string]any), nil
+	case fragment != "" && !strings.HasPrefix(fragment, "/"):
+		c.drop("$ref to anchor "+ref, file, pointer)
+		return make(map[string]any), nil
+	}
+
+	targetFile := file
+	if target != "" {
+		targetFile = filepath.Join(filepath.Dir(file), filepath.FromSlash(target))
+	}
+	key := targetFile + "#" + fragment
+	if slices.Contains(c.expanding, key) {
+		c.drop("recursive $ref "+ref, file, pointer)
+		return make(map[string]any), nil
+	}
+
+	doc, err := c.load(targetFile)
+	if err != nil {
+		return nil, err
+	}
+	resolved, err := resolveSchemaPointer(doc, fragment)
+	if err != nil {
+		return nil, fmt.Errorf("unresolved $ref %q at %s: %w", ref, c.location(file, pointer), err)
+	}
+	c.expanding = append(c.expanding, key)
+	out, err := c.convert(targetFile, fragment, resolved)
+	c.expanding = c.expanding[:len(c.expanding)-1]
+	if err != nil {
+		return nil, err
+	}
+
+	siblings := make(map[string]any, len(schema))
+	for key, value := range schema {
+		if key != "$ref" {
+			siblings[key] = value
+		}
+	}
+	return out, c.convertKeywords(file, pointer, siblings, out)
+}
+
+func (c *schemaFileConverter) convertKeywords(file, pointer string, schema, out map[string]any) error {
+	for _, key := range sortedKeys(schema) {
+		value := schema[key]
+		at := pointer + "/" + escapePointer(key)
+		switch {
+		case slices.Contains(schemaKeywords, key), strings.HasPrefix(key, "x-"):
+			out[key] = value
+			continue
+		case slices.Contains(schemaMetaKeywords, key):
+			continue
+		}
+
+		switch key {
+		case "type":
+			c.convertType(file, at, value, out)
+		case "required":
+			if _, isList := value.([]any); !isList {
+				c.drop("boolean required", file, at) // draft 3
+				continue
+			}
+			out[key] = value
+		case "exclusiveMaximum", "exclusiveMinimum":
+			if _, isBool := value.(bool); isBool {
+				out[key] = value
+				continue
+			}
+			// since draft 6, the exclusive bound is the value
+			bound := map[string]string{"exclusiveMaximum": "maximum", "exclusiveMinimum": "minimum"}[key]
+			out[bound] = value
+			out[key] = true
+		case "const":
+			out["enum"] = []any{value}
+		case "examples":
+			if examples, isList := value.([]any); isList && len(examples) > 0 && schema["example"] == nil {
+				out["example"] = examples[0]
+			}
+		case "nullable": // OpenAPI 3.0
+			if nullable, _ := value.(bool); nullable {
+				out["x-nullable"] = true
+			}
+		case "items":
+			if _, isTuple := value.([]any); isTuple {
+				c.drop("tuple items", file, at)
+				continue
+			}
+			items, err := c.convert(file, at, value)
+			if err != nil {
+				return err
+			}
+			out[key] = items
+		case "additionalProperties":
+			if allowed, isBool := value.(bool); isBool {
+				out[key] = allowed
+				continue
+			}
+			additional, err := c.convert(file, at, value)
+			if err != nil {
+				return err
+			}
+			out[key] = additional
+		case "properties":
+			properties, isMap := value.(map[string]any)
+			if !isMap {
+				return fmt.Errorf("invalid properties at %s", c.location(file, at))
+			}
+			converted := make(map[string]any, len(properties))
+			for name, property := range properties {
+				schema, err := c.convert(file, at+"/"+escapePointer(name), property)
+				if err != nil {
+					return err
+				}
+				converted[name] = schema
+			}
+			out[key] = converted
+		case "allOf":
+			members, isList := value.([]any)
+			if !isList {
+				return fmt.Errorf("invalid allOf at %s", c.location(file, at))
+			}
+			converted := make([]any, 0, len(members))
+			for i, member := range members {
+				schema, err := c.convert(file, at+"/"+strconv.Itoa(i), member)
+				if err != nil {
+					return err
+				}
+				converted = append(converted, schema)
+			}
+			out[key] = converted
+		default:
+			c.drop(key, file, at)
+		}
+	}
+	return nil
+}
+
+// convertType converts a type, nullable with a list holding null since draft 4: swagger 2.0 only has x-nullable.
+func (c *schemaFileConverter) convertType(file, pointer string, value any, out map[string]any) {
+	types, isList := value.([]any)
+	if !isList {
+		types = []any{value}
+	}
+	var kept []any
+	for _, tpe := range types {
+		if tpe == "null" {
+			out["x-nullable"] = true
+			continue
+		}
+		kept = append(kept, tpe)
+	}
+	switch len(kept) {
+	case 0:
+		c.drop("type null", file, pointer)
+	case 1:
+		out["type"] = kept[0]
+	default:
+		c.drop(fmt.Sprintf("type %v", kept), file, pointer)
+	}
+}
+
+func (c *schemaFileConverter) drop(what, file, pointer string) {
+	c.dropped = append(c.dropped, what+" at "+c.location(file, pointer))
+}
+
+// location is a JSON pointer in a document, relative to the directory of the Go file.
+func (c *schemaFileConverter) location(file, pointer string) string {
+	return c.display(file) + "#" + pointer
+}
+
+func (c *schemaFileConverter) display(file string) string {
+	if rel, err := filepath.Rel(c.dir, file); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return file
+}
+
+// resolveSchemaPointer resolves a JSON pointer in a document, e.g. /$defs/address.
+func resolveSchemaPointer(doc any, pointer string) (any, error) {
+	if pointer == "" {
+		return doc, nil
+	}
+	node := doc
+	for token := range strings.SplitSeq(strings.TrimPrefix(pointer, "/"), "/") {
+		token = unescapePointer(token)
+		switch parent := node.(type) {
+		case map[string]any:
+			child, found := parent[token]
+			if !found {
+				return nil, fmt.Errorf("no %s in %s", token, pointer)
+			}
+			node = child
+		case []any:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(parent) {
+				return nil, fmt.Errorf("no %s in %s", token, pointer)
+			}
+			node = parent[i]
+		default:
+			return nil, errors.New("no " + pointer)
+		}
+	}
+	return node, nil
+}
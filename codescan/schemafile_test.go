@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaFile(t *testing.T) {
+	var diagnostics []Diagnostic
+	var stats Stats
+	doc, err := Run(&Options{
+		Packages:    []string{"github.com/3idey/codescan/fixtures/goparsing/schemafile"},
+		ScanModels:  true,
+		Diagnostics: &diagnostics,
+		Stats:       &stats,
+	})
+	require.NoError(t, err)
+
+	settings := doc.Definitions["Settings"]
+	preferences := settings.Properties["preferences"]
+	assert.True(t, preferences.Type.Contains("object"))
+	assert.Equal(t, "Preferences", preferences.Title)
+	assert.Equal(t, "The preferences of the user.", preferences.Description, "the documentation of the field wins")
+	assert.Equal(t, []string{"theme"}, preferences.Required)
+	require.NotNil(t, preferences.AdditionalProperties)
+	assert.False(t, preferences.AdditionalProperties.Allows)
+	assert.Equal(t, "Preferences", preferences.Extensions["x-go-name"])
+
+	props := preferences.Properties
+	assert.Equal(t, []any{"light", "dark"}, props["theme"].Enum)
+	assert.Len(t, props["version"].Enum, 1, "const is a single enum value")
+
+	fontSize := props["fontSize"]
+	require.NotNil(t, fontSize.Minimum)
+	assert.InDelta(t, 0, *fontSize.Minimum, 0)
+	assert.True(t, fontSize.ExclusiveMinimum, "the numeric exclusiveMinimum of draft 6")
+	assert.NotNil(t, fontSize.Example, "the first of the examples")
+
+	nickname := props["nickname"]
+	assert.True(t, nickname.Type.Contains("string"))
+	assert.Len(t, nickname.Type, 1)
+	assert.Equal(t, true, nickname.Extensions["x-nullable"])
+
+	assert.Empty(t, props["contact"].Type, "oneOf is dropped")
+
+	notifications := props["notifications"]
+	assert.Empty(t, notifications.Ref.String(), "the $refs are inlined")
+	assert.Equal(t, "When to notify the user.", notifications.Description, "with their siblings")
+	assert.Contains(t, notifications.Properties, "digest")
+
+	shortcut := props["shortcuts"].Items.Schema
+	assert.Contains(t, shortcut.Properties, "key")
+	assert.Empty(t, shortcut.Properties["then"].Properties, "the recursive $ref is cut")
+
+	address := props["address"]
+	assert.Equal(t, []string{"city"}, address.Required, "from the $ref to another file")
+
+	layout := settings.Properties["layout"]
+	assert.Empty(t, layout.Ref.String(), "the $ref of the named type is replaced")
+	assert.Equal(t, "The widgets of the dashboard, by slot.", layout.Description)
+	assert.True(t, layout.ReadOnly, "the tags of the field refine the schema of the file")
+	require.NotNil(t, layout.AdditionalProperties)
+	assert.Contains(t, layout.AdditionalProperties.Schema.Properties, "widget")
+
+	raw := settings.Properties["raw"]
+	assert.Empty(t, raw.Ref.String(), "without a schema file, a json.RawMessage is any value")
+	assert.Empty(t, raw.Type)
+
+	require.Len(t, diagnostics, 1)
+	assert.Equal(t, DiagnosticUnsupportedSchemaKeyword, diagnostics[0].Code)
+	assert.Contains(t, diagnostics[0].Message, "oneOf at schemas/preferences.schema.json#/properties/contact/oneOf")
+	assert.Contains(t, diagnostics[0].Message, "recursive $ref #/$defs/shortcut at schemas/preferences.schema.json#/$defs/shortcut/properties/then")
+
+	names := make([]string, 0, len(stats.SchemaFiles))
+	for _, file := range stats.SchemaFiles {
+		names = append(names, filepath.Base(file))
+	}
+	assert.Equal(t, []string{"common.schema.json", "layout.yaml", "preferences.schema.json"}, names)
+}
+
+func TestSchemaFileUnresolvedRef(t *testing.T) {
+	_, err := Run(&Options{Packages: []string{"github.com/3idey/codescan/fixtures/goparsing/schemafile/broken"}, ScanModels: true})
+	require.Error(t, err)
+	assert.Regexp(t, `models\.go:9:2: unresolved \$ref "#/\$defs/theme" at broken\.schema\.json#/properties/theme: .* in the Schema File of Settings\.preferences`, err.Error())
+}
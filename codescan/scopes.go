@@ -0,0 +1,158 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"slices"
+
+	"github.com/go-openapi/spec"
+	"gopkg.in/yaml.v3"
+)
+
+// scopeOrderExtension lists the scopes of a security definition in their source order, which the map of
+// spec.SecurityScheme loses. The encoders emit the scopes in that order, and drop the extension.
+const scopeOrderExtension = "x-scope-order"
+
+// orderScopes records the order of the scopes of the security definitions of a YAML node as their
+// x-scope-order extension. It rewrites the list form of the scopes of the swagger:meta grammar, whose items
+// are either a scope with its description, e.g. `- pets:read: read the pets`, or a bare scope, e.g.
+// `- pets:write`, to the object of swagger 2.0.
+func orderScopes(definitions *yaml.Node) {
+	if definitions == nil || definitions.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 1; i < len(definitions.Content); i += 2 {
+		scheme := definitions.Content[i]
+		if scheme.Kind != yaml.MappingNode || metaLookup(scheme, scopeOrderExtension) != nil {
+			continue
+		}
+		scopes := metaLookup(scheme, "scopes")
+		if scopes == nil {
+			continue
+		}
+		if scopes.Kind == yaml.SequenceNode {
+			*scopes = *scopesObject(scopes)
+		}
+		if scopes.Kind != yaml.MappingNode || len(scopes.Content) < 4 {
+			continue
+		}
+		order := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for j := 0; j < len(scopes.Content); j += 2 {
+			order.Content = append(order.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: scopes.Content[j].Value})
+		}
+		scheme.Content = append(scheme.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: scopeOrderExtension}, order)
+	}
+}
+
+// scopesObject converts the list form of scopes to an object, the bare scopes having an empty description.
+func scopesObject(list *yaml.Node) *yaml.Node {
+	scopes := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Line: list.Line, Column: list.Column}
+	for _, item := range list.Content {
+		switch item.Kind {
+		case yaml.ScalarNode:
+			scopes.Content = append(scopes.Content, item, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str"})
+		case yaml.MappingNode:
+			scopes.Content = append(scopes.Content, item.Content...)
+		}
+	}
+	return scopes
+}
+
+// mergeScopes fills the empty descriptions of the scopes of a security definition with those of the
+// definition it merges with, and takes the order of its scopes when it has none.
+func mergeScopes(scheme, other *spec.SecurityScheme) {
+	if scheme == nil || other == nil || scheme == other {
+		return
+	}
+	for name, description := range scheme.Scopes {
+		if description == "" && other.Scopes[name] != "" {
+			scheme.Scopes[name] = other.Scopes[name]
+		}
+	}
+	if _, ordered := scheme.Extensions[scopeOrderExtension]; ordered {
+		return
+	}
+	var order []any
+	for _, name := range scopeOrder(other) {
+		if _, found := scheme.Scopes[name]; found {
+			order = append(order, name)
+		}
+	}
+	if len(order) > 1 {
+		scheme.AddExtension(scopeOrderExtension, order)
+	}
+}
+
+// scopeOrder returns the x-scope-order of a security definition.
+func scopeOrder(scheme *spec.SecurityScheme) []string {
+	values, _ := scheme.Extensions[scopeOrderExtension].([]any)
+	order := make([]string, 0, len(values))
+	for _, value := range values {
+		if name, ok := value.(string); ok {
+			order = append(order, name)
+		}
+	}
+	return order
+}
+
+// sortScopes sorts the scopes of an encoded security definition, and those of its OpenAPI 3 flows, by its
+// x-scope-order extension, which it drops. The scopes it doesn't list come last, in their original order.
+func sortScopes(scheme orderedObject) orderedObject {
+	at := slices.IndexFunc(scheme, func(member orderedMember) bool { return member.Key == scopeOrderExtension })
+	if at < 0 {
+		return scheme
+	}
+	values, isList := scheme[at].Value.([]any)
+	if !isList {
+		return scheme
+	}
+	rank := make(map[string]int, len(values))
+	for i, value := range values {
+		if name, ok := value.(string); ok {
+			rank[name] = i
+		}
+	}
+	sortObject := func(value any) {
+		scopes, _ := value.(orderedObject)
+		slices.SortStableFunc(scopes, func(a, b orderedMember) int {
+			ar, aok := rank[a.Key]
+			br, bok := rank[b.Key]
+			switch {
+			case aok && bok:
+				return ar - br
+			case aok:
+				return -1
+			case bok:
+				return 1
+			default:
+				return 0
+			}
+		})
+	}
+
+	var sorted bool
+	for _, member := range scheme {
+		switch member.Key {
+		case "scopes":
+			sortObject(member.Value)
+			sorted = true
+		case "flows":
+			flows, _ := member.Value.(orderedObject)
+			for _, flow := range flows {
+				if settings, ok := flow.Value.(orderedObject); ok {
+					for _, setting := range settings {
+						if setting.Key == "scopes" {
+							sortObject(setting.Value)
+						}
+					}
+				}
+			}
+			sorted = true
+		}
+	}
+	if !sorted {
+		return scheme
+	}
+	return slices.Delete(scheme, at, at+1)
+}
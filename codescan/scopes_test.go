@@ -0,0 +1,175 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var fixtureScopes = []string{
+	"pets:write", "pets:read", "owners:write", "owners:read", "stores:write", "stores:read", "orders:write",
+	"orders:read", "users:write", "users:read", "billing:write", "billing:read", "reports:read", "audit:read",
+	"webhooks:write", "tokens:write", "admin", "support", "metrics:read", "zones:read",
+}
+
+// emittedScopes returns the scopes of the oauth security definition of an encoded fixture, in their order: the
+// first scopes object, from its start to its end.
+func emittedScopes(t *testing.T, encoded []byte, start, end string, rx *regexp.Regexp) []string {
+	t.Helper()
+	block, found := bytes.CutPrefix(encoded[max(bytes.Index(encoded, []byte(start)), 0):], []byte(start))
+	require.True(t, found, "no scopes in %s", encoded)
+	block, _, _ = bytes.Cut(block, []byte(end))
+	var scopes []string
+	for _, match := range rx.FindAllSubmatch(block, -1) {
+		scopes = append(scopes, string(match[1]))
+	}
+	return scopes
+}
+
+func TestScopeOrder(t *testing.T) {
+	const pkg = "github.com/3idey/codescan/fixtures/goparsing/scopes"
+	rxJSON := regexp.MustCompile(`"([a-z:]+)": "[^"]*"`)
+	rxYAML := regexp.MustCompile(`(?m)^ {6}([a-z:]+):`)
+	jsonScopes := func(t *testing.T, encoded []byte) []string {
+		t.Helper()
+		return emittedScopes(t, encoded, `"scopes": {`, "}", rxJSON)
+	}
+
+	doc, err := Run(&Options{Packages: []string{pkg}})
+	require.NoError(t, err)
+	oauth := doc.SecurityDefinitions["oauth"]
+	require.NotNil(t, oauth)
+	require.Len(t, oauth.Scopes, 20)
+
+	t.Run("should emit the scopes in their source order in JSON", func(t *testing.T) {
+		encoded, err := MarshalJSON(doc, false)
+		require.NoError(t, err)
+		assert.NotContains(t, string(encoded), scopeOrderExtension)
+		assert.Equal(t, fixtureScopes, jsonScopes(t, encoded))
+
+		again, err := MarshalJSON(doc, false)
+		require.NoError(t, err)
+		assert.Equal(t, encoded, again)
+	})
+
+	t.Run("should emit the scopes in their source order in YAML", func(t *testing.T) {
+		encoded, err := MarshalYAML(doc)
+		require.NoError(t, err)
+		assert.NotContains(t, string(encoded), scopeOrderExtension)
+		assert.Equal(t, fixtureScopes, emittedScopes(t, encoded, "    scopes:\n", "\n  partner:", rxYAML))
+	})
+
+	t.Run("should accept the list form of the scopes", func(t *testing.T) {
+		partner := doc.SecurityDefinitions["partner"]
+		require.NotNil(t, partner)
+		assert.Equal(t, map[string]string{
+			"partners:sync": "synchronizes the catalogs",
+			"partners:read": "",
+			"catalog:write": "writes the catalogs",
+		}, partner.Scopes)
+		assert.Equal(t, []string{"partners:sync", "partners:read", "catalog:write"}, scopeOrder(partner))
+	})
+
+	t.Run("should keep the scopes ordered in OpenAPI 3", func(t *testing.T) {
+		doc3, err := Run3(&Options{Packages: []string{pkg}})
+		require.NoError(t, err)
+		encoded, err := MarshalJSON(doc3, false)
+		require.NoError(t, err)
+		assert.NotContains(t, string(encoded), scopeOrderExtension)
+		assert.Equal(t, fixtureScopes, jsonScopes(t, encoded))
+	})
+
+	input := []byte(`swagger: "2.0"
+info: {title: pets, version: "1.0"}
+paths: {}
+securityDefinitions:
+  oauth:
+    type: oauth2
+    flow: accessCode
+    authorizationUrl: https://example.com/authorize
+    tokenUrl: https://example.com/token
+    scopes:
+      admin: administers the service
+      pets:read: reads the pets
+  sso:
+    type: oauth2
+    flow: implicit
+    authorizationUrl: https://example.com/authorize
+    scopes:
+      zeta: the last letter
+      alpha: the first letter
+      mu: a letter in between
+`)
+
+	t.Run("should keep the order of the scopes of the input spec", func(t *testing.T) {
+		inputSpec, err := ParseInputSpec(input, false)
+		require.NoError(t, err)
+		encoded, err := MarshalYAML(inputSpec)
+		require.NoError(t, err)
+		assert.Contains(t, string(encoded), "      zeta: the last letter\n      alpha: the first letter\n      mu: a letter in between\n")
+	})
+
+	for _, strategy := range []string{"", MergeScanWins} {
+		t.Run("should keep the descriptions of the input spec through the merge "+strategy, func(t *testing.T) {
+			inputSpec, err := ParseInputSpec(input, false)
+			require.NoError(t, err)
+			merged, err := Run(&Options{Packages: []string{pkg}, InputSpec: inputSpec, MergeStrategy: strategy})
+			require.NoError(t, err)
+			oauth := merged.SecurityDefinitions["oauth"]
+			require.NotNil(t, oauth)
+			assert.Equal(t, "administers the service", oauth.Scopes["admin"])
+			assert.Equal(t, "reads the pets", oauth.Scopes["pets:read"])
+			assert.Equal(t, fixtureScopes, scopeOrder(oauth))
+		})
+	}
+
+	t.Run("should keep the descriptions of the scanned side when the input wins", func(t *testing.T) {
+		inputSpec := &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+			Swagger:             "2.0",
+			Paths:               &spec.Paths{},
+			SecurityDefinitions: spec.SecurityDefinitions{"partner": spec.OAuth2Application("https://example.com/token")},
+		}}
+		inputSpec.SecurityDefinitions["partner"].AddScope("partners:read", "")
+		inputSpec.SecurityDefinitions["partner"].AddScope("partners:sync", "")
+		merged, err := Run(&Options{Packages: []string{pkg}, InputSpec: inputSpec, MergeStrategy: MergeInputWins})
+		require.NoError(t, err)
+		partner := merged.SecurityDefinitions["partner"]
+		assert.Len(t, partner.Scopes, 2)
+		assert.Equal(t, "synchronizes the catalogs", partner.Scopes["partners:sync"])
+		assert.Equal(t, []string{"partners:sync", "partners:read"}, scopeOrder(partner))
+	})
+}
+
+func TestParseMetaScopes(t *testing.T) {
+	meta, err := ParseMeta([]byte(`securityDefinitions:
+  oauth:
+    type: oauth2
+    flow: implicit
+    authorizationUrl: https://example.com/authorize
+    scopes:
+    - write: writes
+    - read
+`))
+	require.NoError(t, err)
+	oauth := meta.SecurityDefinitions["oauth"]
+	require.NotNil(t, oauth)
+	assert.Equal(t, map[string]string{"write": "writes", "read": ""}, oauth.Scopes)
+	assert.Equal(t, []string{"write", "read"}, scopeOrder(oauth))
+
+	_, err = ParseMeta([]byte(`securityDefinitions:
+  oauth:
+    type: oauth2
+    flow: implicit
+    authorizationUrl: https://example.com/authorize
+    scopes:
+    - [write]
+`))
+	require.ErrorContains(t, err, "scopes items must be a scope or an object")
+}
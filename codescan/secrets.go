@@ -0,0 +1,187 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"log"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// SecretPattern is a regular expression of the secrets which must not leak into the examples, defaults and
+// descriptions of the spec, see Options.CheckSecrets.
+type SecretPattern struct {
+	Name    string // reported with the findings of the pattern, e.g. aws-access-key
+	Pattern string // regular expression, empty to disable the default pattern of the same name
+}
+
+// DefaultSecretPatterns returns the patterns of Options.CheckSecrets, which Options.SecretPatterns extend.
+func DefaultSecretPatterns() []SecretPattern {
+	return []SecretPattern{
+		{Name: "aws-access-key", Pattern: `\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`},
+		{Name: "jwt", Pattern: `\beyJ[A-Za-z0-9_-]{8,}\.eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}`},
+		{Name: "password-pair", Pattern: `(?i)\b(?:password|passwd|pwd|secret|api_?key|access_?token)=[^\s&,;'"]{4,}`},
+		{Name: "private-key", Pattern: `-----BEGIN (?:[A-Z]+ )?PRIVATE KEY-----`},
+		{Name: "github-token", Pattern: `\bgh[pousr]_[A-Za-z0-9]{36}\b`},
+	}
+}
+
+type secretPattern struct {
+	name string
+	rx   *regexp.Regexp
+}
+
+// compileSecretPatterns compiles the default patterns, replaced or disabled by name by the extra ones.
+func compileSecretPatterns(extra []SecretPattern) ([]secretPattern, error) {
+	patterns := DefaultSecretPatterns()
+	var errs []error
+	for _, pattern := range extra {
+		if pattern.Name == "" {
+			errs = append(errs, fmt.Errorf("secret pattern %q has no name", pattern.Pattern))
+			continue
+		}
+		i := slices.IndexFunc(patterns, func(known SecretPattern) bool { return known.Name == pattern.Name })
+		if i >= 0 {
+			patterns[i] = pattern
+		} else {
+			patterns = append(patterns, pattern)
+		}
+	}
+
+	compiled := make([]secretPattern, 0, len(patterns))
+	for _, pattern := range patterns {
+		if pattern.Pattern == "" {
+			continue
+		}
+		rx, err := regexp.Compile(pattern.Pattern)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("secret pattern %s: %w", pattern.Name, err))
+			continue
+		}
+		compiled = append(compiled, secretPattern{name: pattern.Name, rx: rx})
+	}
+	return compiled, errors.Join(errs...)
+}
+
+// secretKeywords are the members of the spec checked for secrets.
+var secretKeywords = []string{"example", "examples", "x-example", "x-examples", "default", "description"}
+
+// namedMembers are the members of the spec keyed by names, which are never keywords.
+var namedMembers = []string{"definitions", "parameters", "responses", "paths", "properties", "patternProperties", "headers", "securityDefinitions"}
+
+// checkSecrets reports the examples, defaults and descriptions of a spec matching the secret patterns, with
+// DiagnosticSecret diagnostics positioned at the closest element found in the source map. The secrets
+// themselves are never reported. It fails with failOnSecrets.
+func (a *typeIndex) checkSecrets(patterns []secretPattern, doc *spec.Swagger, positions map[string]token.Position, failOnSecrets bool) error {
+	if len(patterns) == 0 {
+		return nil
+	}
+	env, err := ruleEnv(doc)
+	if err != nil {
+		return err
+	}
+
+	var found []Diagnostic
+	walkSecretValues(env, "", false, func(location, keyword, text string) {
+		for _, pattern := range patterns {
+			if !pattern.rx.MatchString(text) {
+				continue
+			}
+			diagnostic := Diagnostic{
+				Pos:     closestPosition(positions, location),
+				Code:    DiagnosticSecret,
+				Message: fmt.Sprintf("the %s of %s matches the secret pattern %s", keyword, location, pattern.name),
+			}
+			a.record(&diagnostic)
+			found = append(found, diagnostic)
+		}
+	})
+	if len(found) == 0 {
+		return nil
+	}
+
+	summary := fmt.Sprintf("%d secrets found in the examples, defaults and descriptions", len(found))
+	if len(found) == 1 {
+		summary = "1 secret found in the examples, defaults and descriptions"
+	}
+	if !failOnSecrets {
+		log.Printf("WARNING: %s", summary)
+		return nil
+	}
+
+	errs := []error{errors.New(summary)}
+	for _, diagnostic := range found {
+		errs = append(errs, diagnostic)
+	}
+	return errors.Join(errs...)
+}
+
+// walkSecretValues calls check with the strings of the secret keywords of a spec decoded as JSON, and the
+// JSON pointer of their element.
+func walkSecretValues(value any, location string, named bool, check func(location, keyword, text string)) {
+	switch v := value.(type) {
+	case map[string]any:
+		for _, key := range sortedKeys(v) {
+			if !named && slices.Contains(secretKeywords, key) {
+				if key != "description" || isString(v[key]) {
+					walkStrings(v[key], func(text string) { check(orRoot(location), key, text) })
+				}
+				continue
+			}
+			walkSecretValues(v[key], location+"/"+escapePointer(key), !named && slices.Contains(namedMembers, key), check)
+		}
+	case []any:
+		for i, item := range v {
+			walkSecretValues(item, fmt.Sprintf("%s/%d", location, i), false, check)
+		}
+	}
+}
+
+func walkStrings(value any, check func(text string)) {
+	switch v := value.(type) {
+	case string:
+		check(v)
+	case map[string]any:
+		for _, key := range sortedKeys(v) {
+			walkStrings(v[key], check)
+		}
+	case []any:
+		for _, item := range v {
+			walkStrings(item, check)
+		}
+	}
+}
+
+func isString(value any) bool {
+	_, ok := value.(string)
+	return ok
+}
+
+func orRoot(location string) string {
+	if location == "" {
+		return "/"
+	}
+	return location
+}
+
+// closestPosition returns the position of an element of the spec, or of its closest parent with one.
+func closestPosition(positions map[string]token.Position, location string) token.Position {
+	for location != "" {
+		if pos, ok := positions[location]; ok {
+			return pos
+		}
+		i := strings.LastIndexByte(location, '/')
+		if i < 0 {
+			break
+		}
+		location = location[:i]
+	}
+	return token.Position{}
+}
@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckSecrets(t *testing.T) {
+	const pkg = "github.com/3idey/codescan/fixtures/goparsing/secrets"
+
+	t.Run("should report the secrets with the position of their field", func(t *testing.T) {
+		var diagnostics []Diagnostic
+		_, err := Run(&Options{Packages: []string{pkg}, ScanModels: true, CheckSecrets: true, Diagnostics: &diagnostics})
+		require.NoError(t, err)
+
+		secrets := make(map[string]int)
+		for _, diagnostic := range diagnostics {
+			require.Equal(t, DiagnosticSecret, diagnostic.Code)
+			assert.Equal(t, SeverityError, diagnostic.Severity)
+			assert.Equal(t, "models.go", filepath.Base(diagnostic.Pos.Filename))
+			assert.NotContains(t, diagnostic.Message, "AKIA")
+			secrets[diagnostic.Message] = diagnostic.Pos.Line
+		}
+		assert.Equal(t, map[string]int{
+			"the example of /definitions/Credentials/properties/accessKey matches the secret pattern aws-access-key": 13,
+			"the default of /definitions/Credentials/properties/dsn matches the secret pattern password-pair":        18,
+			"the description of /definitions/Credentials/properties/token matches the secret pattern jwt":            21,
+		}, secrets)
+	})
+
+	t.Run("should fail with FailOnSecrets", func(t *testing.T) {
+		_, err := Run(&Options{Packages: []string{pkg}, ScanModels: true, FailOnSecrets: true})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "3 secrets found in the examples, defaults and descriptions")
+		var diagnostic Diagnostic
+		require.True(t, errors.As(err, &diagnostic))
+		assert.Equal(t, DiagnosticSecret, diagnostic.Code)
+	})
+
+	t.Run("should extend and disable the default patterns", func(t *testing.T) {
+		var diagnostics []Diagnostic
+		_, err := Run(&Options{
+			Packages:     []string{pkg},
+			ScanModels:   true,
+			CheckSecrets: true,
+			Diagnostics:  &diagnostics,
+			SecretPatterns: []SecretPattern{
+				{Name: "jwt"},
+				{Name: "password-pair"},
+				{Name: "aws-access-key"},
+				{Name: "region", Pattern: `\beu-west-\d\b`},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, diagnostics, 1)
+		assert.Contains(t, diagnostics[0].Message, "the example of /definitions/Credentials/properties/region matches the secret pattern region")
+	})
+
+	t.Run("should not check without CheckSecrets", func(t *testing.T) {
+		var diagnostics []Diagnostic
+		_, err := Run(&Options{Packages: []string{pkg}, ScanModels: true, Diagnostics: &diagnostics})
+		require.NoError(t, err)
+		assert.Empty(t, diagnostics)
+	})
+}
+
+func TestCompileSecretPatterns(t *testing.T) {
+	patterns, err := compileSecretPatterns(nil)
+	require.NoError(t, err)
+	assert.Len(t, patterns, len(DefaultSecretPatterns()))
+
+	_, err = compileSecretPatterns([]SecretPattern{{Name: "broken", Pattern: "("}, {Pattern: "x"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "secret pattern broken: error parsing regexp")
+	assert.Contains(t, err.Error(), `secret pattern "x" has no name`)
+}
+
+func TestWalkSecretValues(t *testing.T) {
+	doc := map[string]any{
+		"definitions": map[string]any{
+			"description": map[string]any{
+				"description": "a model named description",
+				"properties": map[string]any{
+					"example": map[string]any{"type": "string", "example": []any{"one", "two"}},
+				},
+			},
+		},
+	}
+	var found []string
+	walkSecretValues(doc, "", false, func(location, keyword, text string) {
+		found = append(found, keyword+" "+location+" "+text)
+	})
+	assert.Equal(t, []string{
+		"description /definitions/description a model named description",
+		"example /definitions/description/properties/example one",
+		"example /definitions/description/properties/example two",
+	}, found)
+}
@@ -0,0 +1,200 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// securityNone is the value of the "Security: none" line of a route, opting it out of any security.
+const securityNone = "none"
+
+// ParseSecurityRequirement parses a security requirement written like a line of the Security: section of a
+// route, a scheme with its optional scopes, e.g. "oauth2: read:users, write:users" or "api_key", for
+// Options.SecurityDefaults.
+func ParseSecurityRequirement(value string) (map[string][]string, error) {
+	name, scopes, _ := strings.Cut(value, ":")
+	if name = strings.TrimSpace(name); name == "" {
+		return nil, fmt.Errorf("invalid security requirement %q, expected a scheme with its optional scopes, e.g. oauth2: read:users", value)
+	}
+	requirement := map[string][]string{name: {}}
+	for scope := range strings.SplitSeq(scopes, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			requirement[name] = append(requirement[name], scope)
+		}
+	}
+	return requirement, nil
+}
+
+func checkSecurityDefaults(requirements []map[string][]string) error {
+	var errs []error
+	for i, requirement := range requirements {
+		if len(requirement) == 0 {
+			errs = append(errs, fmt.Errorf("security requirement %d has no scheme", i))
+		}
+		for _, name := range sortedKeys(requirement) {
+			if strings.TrimSpace(name) == "" || name == securityNone {
+				errs = append(errs, fmt.Errorf("invalid security scheme %q in security requirement %d", name, i))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// applySecurityDefaults sets Options.SecurityDefaults on the scanned operations without security
+// requirements. The operations declaring "Security: none" have an empty list of requirements, and keep it.
+func (s *specBuilder) applySecurityDefaults() {
+	if len(s.ctx.opts.SecurityDefaults) == 0 || s.input.Paths == nil {
+		return
+	}
+	scanned := s.scannedOperations()
+	for _, pth := range sortedKeys(s.input.Paths.Paths) {
+		pathItem := s.input.Paths.Paths[pth]
+		for _, op := range pathItemOperations(&pathItem) {
+			if _, ok := scanned[op.ID]; !ok || op.Security != nil {
+				continue
+			}
+			op.Security = make([]map[string][]string, 0, len(s.ctx.opts.SecurityDefaults))
+			for _, requirement := range s.ctx.opts.SecurityDefaults {
+				op.Security = append(op.Security, maps.Clone(requirement))
+			}
+		}
+	}
+}
+
+// checkSecurity fails on the security requirements of the scanned operations naming a scheme which
+// securityDefinitions, from swagger:meta or the input spec, doesn't define, at the position of the route
+// or operation. The scopes of an oauth2 scheme must be among its scopes, and the other schemes have none.
+// The top-level requirements are checked the same way, unless they are those of the input spec.
+func (s *specBuilder) checkSecurity() error {
+	defined := slices.Sorted(maps.Keys(s.input.SecurityDefinitions))
+
+	var errs []error
+	if !sameRequirements(s.input.Security, s.inputSecurity) {
+		for _, requirement := range s.input.Security {
+			for _, name := range sortedKeys(requirement) {
+				if err := s.checkRequirement(name, requirement[name], defined); err != nil {
+					errs = append(errs, fmt.Errorf("the top-level security of swagger:meta %w", err))
+				}
+			}
+		}
+	}
+	if s.input.Paths == nil {
+		return errors.Join(errs...)
+	}
+	scanned := s.scannedOperations()
+	for _, pth := range sortedKeys(s.input.Paths.Paths) {
+		pathItem := s.input.Paths.Paths[pth]
+		for _, op := range pathItemOperations(&pathItem) {
+			pos, ok := scanned[op.ID]
+			if !ok {
+				continue // an operation of the input spec
+			}
+			for _, requirement := range op.Security {
+				for _, name := range sortedKeys(requirement) {
+					if err := s.checkRequirement(name, requirement[name], defined); err != nil {
+						errs = append(errs, fmt.Errorf("%v: operation %s %w", pos, op.ID, err))
+					}
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// sameRequirements tells if two lists of security requirements are the same, e.g. the top-level ones of the
+// input spec, which swagger:meta didn't replace.
+func sameRequirements(a, b []map[string][]string) bool {
+	return slices.EqualFunc(a, b, func(x, y map[string][]string) bool {
+		return maps.EqualFunc(x, y, slices.Equal[[]string])
+	})
+}
+
+func (s *specBuilder) checkRequirement(name string, scopes, defined []string) error {
+	scheme := s.input.SecurityDefinitions[name]
+	if scheme == nil {
+		message := fmt.Sprintf("requires the undefined security scheme %q, see securityDefinitions", name)
+		if suggestion := closestName(name, defined); suggestion != "" {
+			message += fmt.Sprintf(": did you mean %q?", suggestion)
+		}
+		return errors.New(message)
+	}
+	if scheme.Type != "oauth2" {
+		if len(scopes) > 0 {
+			return fmt.Errorf("requires scopes of the %s security scheme %q, which only oauth2 schemes have", scheme.Type, name)
+		}
+		return nil
+	}
+	declared := slices.Sorted(maps.Keys(scheme.Scopes))
+	for _, scope := range scopes {
+		if _, found := scheme.Scopes[scope]; found {
+			continue
+		}
+		message := fmt.Sprintf("requires the scope %q, which the security scheme %q doesn't declare", scope, name)
+		if suggestion := closestName(scope, declared); suggestion != "" {
+			message += fmt.Sprintf(": did you mean %q?", suggestion)
+		}
+		return errors.New(message)
+	}
+	return nil
+}
+
+// checkRequiredSecurity fails on the operations without effective security requirements with
+// Options.RequireSecurity, reporting each with an unsecured-operation diagnostic: the requirements of an
+// operation win, then those of Options.SecurityDefaults on the scanned ones, then the top-level ones of
+// swagger:meta or the input spec. The operations declaring "Security: none" have an empty list, and pass.
+// The diagnostics which a Rule makes warnings are only reported.
+func (s *specBuilder) checkRequiredSecurity() error {
+	if !s.ctx.opts.RequireSecurity || len(s.input.Security) > 0 || s.input.Paths == nil {
+		return nil
+	}
+	scanned := s.scannedOperations()
+
+	var unsecured []error
+	for _, pth := range sortedKeys(s.input.Paths.Paths) {
+		pathItem := s.input.Paths.Paths[pth]
+		for method, op := range pathItemOperations(&pathItem) {
+			if op.Security != nil {
+				continue
+			}
+			pos, ok := scanned[op.ID]
+			subject := fmt.Sprintf("operation %s %s %s", op.ID, strings.ToUpper(method), pth)
+			if !ok {
+				subject += " of the input spec"
+			}
+			diagnostic := Diagnostic{
+				Pos:     pos,
+				Code:    DiagnosticUnsecuredOperation,
+				Message: subject + ` has no security requirements, nor has the spec: declare Security:, or "Security: none" for a public operation`,
+			}
+			s.ctx.app.record(&diagnostic)
+			if diagnostic.Severity == SeverityError {
+				unsecured = append(unsecured, diagnostic)
+			}
+		}
+	}
+	if len(unsecured) == 0 {
+		return nil
+	}
+
+	summary := fmt.Sprintf("%d operations without security requirements, see RequireSecurity", len(unsecured))
+	if len(unsecured) == 1 {
+		summary = "1 operation without security requirements, see RequireSecurity"
+	}
+	return errors.Join(append([]error{errors.New(summary)}, unsecured...)...)
+}
+
+// scannedOperations are the positions of the routes and operations of the scan, by operation ID.
+func (s *specBuilder) scannedOperations() map[string]token.Position {
+	positions := make(map[string]token.Position, len(s.ctx.app.Routes)+len(s.ctx.app.Operations))
+	for _, pp := range slices.Concat(s.ctx.app.Routes, s.ctx.app.Operations) {
+		positions[pp.ID] = pp.Pos
+	}
+	return positions
+}
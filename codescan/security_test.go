@@ -0,0 +1,158 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecurityRequirements(t *testing.T) {
+	const fixture = "github.com/3idey/codescan/fixtures/goparsing/security"
+	doc, err := Run(&Options{Packages: []string{fixture}})
+	require.NoError(t, err)
+
+	t.Run("should set the security requirements of the routes", func(t *testing.T) {
+		createUser := doc.Paths.Paths["/users"].Post
+		require.NotNil(t, createUser)
+		assert.Equal(t, []map[string][]string{
+			{"oauth2": {"read:users", "write:users"}},
+			{"api_key": {}},
+		}, createUser.Security)
+
+		deleteUser := doc.Paths.Paths["/users/{id}"].Delete
+		require.NotNil(t, deleteUser)
+		assert.Equal(t, []map[string][]string{{"oauth2": {"write:users"}}}, deleteUser.Security)
+		assert.Nil(t, doc.Paths.Paths["/users"].Get.Security)
+	})
+
+	t.Run("should emit an empty list for Security: none", func(t *testing.T) {
+		getHealth := doc.Paths.Paths["/health"].Get
+		require.NotNil(t, getHealth)
+		assert.NotNil(t, getHealth.Security)
+		assert.Empty(t, getHealth.Security)
+		jazon, err := json.Marshal(getHealth)
+		require.NoError(t, err)
+		assert.Contains(t, string(jazon), `"security":[]`)
+	})
+
+	t.Run("should apply the default requirements", func(t *testing.T) {
+		requirement, err := ParseSecurityRequirement("oauth2: read:users")
+		require.NoError(t, err)
+		doc, err := Run(&Options{Packages: []string{fixture}, SecurityDefaults: []map[string][]string{requirement}})
+		require.NoError(t, err)
+		assert.Equal(t, []map[string][]string{{"oauth2": {"read:users"}}}, doc.Paths.Paths["/users"].Get.Security)
+		assert.Empty(t, doc.Paths.Paths["/health"].Get.Security, "Security: none opts out of the defaults")
+		assert.Len(t, doc.Paths.Paths["/users"].Post.Security, 2)
+
+		_, err = Run(&Options{Packages: []string{fixture}, SecurityDefaults: []map[string][]string{{"basic": {}}}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `operation listUsers requires the undefined security scheme "basic"`)
+	})
+
+	t.Run("should fail on the undefined schemes", func(t *testing.T) {
+		_, err := Run(&Options{Packages: []string{fixture + "/invalid"}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `api.go:12:1: operation listUsers requires the undefined security scheme "apikey", see securityDefinitions: did you mean "api_key"?`)
+	})
+
+	t.Run("should fail on the undeclared scopes", func(t *testing.T) {
+		_, err := Run(&Options{Packages: []string{fixture + "/scopes"}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `operation listUsers requires the scope "read:user", which the security scheme "oauth2" doesn't declare: did you mean "read:users"?`)
+	})
+
+	t.Run("should parse and check the default requirements", func(t *testing.T) {
+		requirement, err := ParseSecurityRequirement("api_key")
+		require.NoError(t, err)
+		assert.Equal(t, map[string][]string{"api_key": {}}, requirement)
+
+		_, err = ParseSecurityRequirement(": read")
+		require.Error(t, err)
+
+		err = (&Options{SecurityDefaults: []map[string][]string{{}}}).Validate()
+		var invalidErr *InvalidOptionError
+		require.ErrorAs(t, err, &invalidErr)
+		assert.Equal(t, "SecurityDefaults", invalidErr.Option)
+	})
+}
+
+func TestTopLevelSecurity(t *testing.T) {
+	const (
+		fixture = "github.com/3idey/codescan/fixtures/goparsing/security"
+		global  = fixture + "/global"
+	)
+
+	t.Run("should apply the requirements of swagger:meta to all the operations", func(t *testing.T) {
+		doc, err := Run(&Options{Packages: []string{global}, RequireSecurity: true})
+		require.NoError(t, err)
+		assert.Equal(t, []map[string][]string{{"api_key": {}}}, doc.Security)
+		assert.Nil(t, doc.Paths.Paths["/orders"].Get.Security, "the top-level ones apply")
+		assert.Equal(t, []map[string][]string{{"basic": {}}}, doc.Paths.Paths["/orders/{id}"].Get.Security)
+		assert.NotNil(t, doc.Paths.Paths["/status"].Get.Security)
+		assert.Empty(t, doc.Paths.Paths["/status"].Get.Security)
+	})
+
+	t.Run("should fail on the operations without security requirements", func(t *testing.T) {
+		var diagnostics []Diagnostic
+		_, err := Run(&Options{Packages: []string{fixture}, RequireSecurity: true, Logger: func(d Diagnostic) { diagnostics = append(diagnostics, d) }})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "1 operation without security requirements, see RequireSecurity")
+		assert.Contains(t, err.Error(), "operation listUsers GET /users has no security requirements")
+		require.Len(t, diagnostics, 1)
+		assert.Equal(t, DiagnosticUnsecuredOperation, diagnostics[0].Code)
+		assert.Equal(t, 40, diagnostics[0].Pos.Line)
+
+		_, err = Run(&Options{
+			Packages: []string{fixture}, RequireSecurity: true,
+			Rules: []Rule{{Name: DiagnosticUnsecuredOperation, Severity: SeverityWarning}},
+		})
+		require.NoError(t, err, "only reported as a warning")
+	})
+
+	t.Run("should give precedence to swagger:meta over the input spec", func(t *testing.T) {
+		input := func(t *testing.T, security string) *spec.Swagger {
+			t.Helper()
+			doc, err := ParseInputSpec([]byte(`{
+  "swagger": "2.0",
+  "info": {"title": "Orders", "version": "1.0"},
+  "paths": {"/invoices": {"get": {"operationId": "listInvoices", "responses": {"200": {"description": "the invoices"}}}}},
+  "security": `+security+`,
+  "securityDefinitions": {"oauth2": {"type": "oauth2", "flow": "application", "tokenUrl": "https://example.com/token", "scopes": {"read": "reads"}}}
+}`), false)
+			require.NoError(t, err)
+			return doc
+		}
+
+		doc, err := Run(&Options{Packages: []string{global}, InputSpec: input(t, `[{"oauth2": ["read"]}]`), RequireSecurity: true})
+		require.NoError(t, err)
+		assert.Equal(t, []map[string][]string{{"api_key": {}}}, doc.Security, "swagger:meta wins")
+
+		doc, err = Run(&Options{Packages: []string{fixture}, InputSpec: input(t, `[{"oauth2": ["read"]}]`), RequireSecurity: true})
+		require.NoError(t, err, "the top-level requirements of the input spec apply")
+		assert.Equal(t, []map[string][]string{{"oauth2": {"read"}}}, doc.Security)
+
+		_, err = Run(&Options{Packages: []string{fixture}, InputSpec: input(t, `[]`), RequireSecurity: true})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "operation listInvoices GET /invoices of the input spec has no security requirements")
+	})
+
+	t.Run("should fail on the undefined schemes of swagger:meta", func(t *testing.T) {
+		doc := new(spec.Swagger)
+		doc.Security = []map[string][]string{{"basic": {}}}
+		b := newSpecBuilder(doc, &scanCtx{opts: &Options{}, app: &typeIndex{}}, false)
+		doc.Security = []map[string][]string{{"apikey": {}}}
+		doc.SecurityDefinitions = spec.SecurityDefinitions{"api_key": spec.APIKeyAuth("X-API-Key", "header")}
+		err := b.checkSecurity()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `the top-level security of swagger:meta requires the undefined security scheme "apikey", see securityDefinitions: did you mean "api_key"?`)
+
+		doc.Security = b.inputSecurity
+		assert.NoError(t, b.checkSecurity(), "the requirements of the input spec aren't checked")
+	})
+}
@@ -0,0 +1,147 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"errors"
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// defaultSetTypes are the set types of the common libraries, which marshal as JSON arrays of unique
+// elements. Options.SetTypes extends them.
+var defaultSetTypes = []string{
+	"github.com/deckarep/golang-set.Set",
+	"github.com/deckarep/golang-set/v2.Set",
+	"github.com/hashicorp/go-set.Set",
+	"github.com/hashicorp/go-set.HashSet",
+	"github.com/hashicorp/go-set.TreeSet",
+	"github.com/hashicorp/go-set/v2.Set",
+	"github.com/hashicorp/go-set/v2.HashSet",
+	"github.com/hashicorp/go-set/v2.TreeSet",
+	"github.com/hashicorp/go-set/v3.Set",
+	"github.com/hashicorp/go-set/v3.HashSet",
+	"github.com/hashicorp/go-set/v3.TreeSet",
+}
+
+func withSetTypes(extra []string) typeIndexOption {
+	return func(a *typeIndex) {
+		a.setTypes = make(map[string]bool, len(defaultSetTypes)+len(extra))
+		for _, name := range defaultSetTypes {
+			a.setTypes[name] = true
+		}
+		for _, name := range extra {
+			a.setTypes[name] = true
+		}
+	}
+}
+
+func checkSetTypes(names []string) error {
+	var errs []error
+	for _, name := range names {
+		i := strings.LastIndexByte(name, '.')
+		if i <= 0 || i == len(name)-1 || strings.Contains(name[i:], "/") {
+			errs = append(errs, fmt.Errorf("invalid set type %q, expected a qualified name like github.com/acme/sets.StringSet", name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// setElement returns the type of the elements of a set type, registered by Options.SetTypes: the first
+// type argument of a generic type, else the key of a map, or the element of a slice or an array. The
+// elements of the other sets, e.g. the interface of github.com/deckarep/golang-set, are of any type.
+func (a *typeIndex) setElement(tpe types.Type) (types.Type, bool) {
+	named, ok := types.Unalias(tpe).(*types.Named)
+	if !ok || !a.setTypes[namedTypeKey(named)] {
+		return nil, false
+	}
+	if args := named.TypeArgs(); args.Len() > 0 {
+		return args.At(0), true
+	}
+	switch underlying := named.Underlying().(type) {
+	case *types.Map:
+		return underlying.Key(), true
+	case *types.Slice:
+		return underlying.Elem(), true
+	case *types.Array:
+		return underlying.Elem(), true
+	default:
+		return types.Universe.Lookup("any").Type(), true
+	}
+}
+
+// namedTypeKey identifies a named type, generic or not, e.g. github.com/deckarep/golang-set/v2.Set.
+func namedTypeKey(named *types.Named) string {
+	obj := named.Origin().Obj()
+	if obj.Pkg() == nil {
+		return obj.Name()
+	}
+	return obj.Pkg().Path() + "." + obj.Name()
+}
+
+// buildSet builds a set as an array of unique items.
+func (s *schemaBuilder) buildSet(elem types.Type, tgt swaggerTypable) error {
+	// the elements of the sets are keys of maps, which are not addressable
+	if err := s.buildItems(elem, tgt, false); err != nil {
+		return err
+	}
+	setUniqueItems(tgt)
+	return nil
+}
+
+func setUniqueItems(tgt swaggerTypable) {
+	switch typable := tgt.(type) {
+	case paramTypable:
+		if typable.param.In == "body" {
+			typable.Schema().UniqueItems = true
+			return
+		}
+		typable.param.UniqueItems = true
+	case itemsTypable:
+		typable.items.UniqueItems = true
+	case responseTypable:
+		if typable.in == "body" {
+			typable.Schema().UniqueItems = true
+			return
+		}
+		typable.header.UniqueItems = true
+	default:
+		if schema := tgt.Schema(); schema != nil {
+			schema.UniqueItems = true
+		}
+	}
+}
+
+// checkUnregisteredSet reports the types which look like sets marshaled as JSON arrays, i.e. maps of
+// empty structs or booleans with a MarshalJSON method, but are missing from Options.SetTypes: they are
+// documented as objects.
+func (s *schemaBuilder) checkUnregisteredSet(named *types.Named) {
+	mapType, isMap := named.Underlying().(*types.Map)
+	if !isMap || !isSetValue(mapType.Elem()) {
+		return
+	}
+	obj := named.Origin().Obj()
+	marshaler, _, _ := types.LookupFieldOrMethod(types.NewPointer(named), true, obj.Pkg(), "MarshalJSON")
+	if _, isMethod := marshaler.(*types.Func); !isMethod {
+		return
+	}
+	s.ctx.app.diagnose(Diagnostic{
+		Pos:  s.decl.Pkg.Fset.Position(obj.Pos()),
+		Code: DiagnosticUnregisteredSet,
+		Message: fmt.Sprintf("%s marshals with MarshalJSON but is documented as an object: register it with SetTypes if it marshals as an array",
+			namedTypeKey(named)),
+	})
+}
+
+func isSetValue(tpe types.Type) bool {
+	switch value := tpe.Underlying().(type) {
+	case *types.Struct:
+		return value.NumFields() == 0
+	case *types.Basic:
+		return value.Kind() == types.Bool
+	default:
+		return false
+	}
+}
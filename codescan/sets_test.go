@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetTypes(t *testing.T) {
+	const pkg = "github.com/3idey/codescan/fixtures/goparsing/sets"
+
+	t.Run("should document the set types as arrays of unique items", func(t *testing.T) {
+		var diagnostics []Diagnostic
+		doc, err := Run(&Options{
+			Packages:    []string{pkg},
+			ScanModels:  true,
+			SetTypes:    []string{pkg + ".StringSet", pkg + ".Set"},
+			Diagnostics: &diagnostics,
+		})
+		require.NoError(t, err)
+
+		product := doc.Definitions["Product"]
+		labels := product.Properties["labels"]
+		assert.True(t, labels.Type.Contains("array"))
+		assert.True(t, labels.UniqueItems)
+		assert.True(t, labels.Items.Schema.Type.Contains("string"), "the elements are the keys of the map")
+		assert.Equal(t, "the labels of the product", labels.Description)
+
+		colors := product.Properties["colors"]
+		assert.True(t, colors.UniqueItems)
+		assert.Equal(t, "#/definitions/Color", colors.Items.Schema.Ref.String(), "the elements are the type argument")
+		assert.Contains(t, doc.Definitions, "Color")
+
+		sizes := product.Properties["sizes"]
+		assert.True(t, sizes.UniqueItems)
+		assert.Equal(t, "int32", sizes.Items.Schema.Format)
+
+		assert.Empty(t, diagnostics)
+	})
+
+	t.Run("should report the sets which aren't registered", func(t *testing.T) {
+		var diagnostics []Diagnostic
+		doc, err := Run(&Options{Packages: []string{pkg}, ScanModels: true, SetTypes: []string{pkg + ".Set"}, Diagnostics: &diagnostics})
+		require.NoError(t, err)
+
+		assert.False(t, doc.Definitions["Product"].Properties["labels"].UniqueItems)
+		require.Len(t, diagnostics, 1)
+		assert.Equal(t, DiagnosticUnregisteredSet, diagnostics[0].Code)
+		assert.Equal(t, 12, diagnostics[0].Pos.Line)
+		assert.Contains(t, diagnostics[0].Message, pkg+".StringSet marshals with MarshalJSON but is documented as an object")
+	})
+
+	t.Run("should document the set parameters with unique items", func(t *testing.T) {
+		doc, err := Run(&Options{Packages: []string{pkg}, SetTypes: []string{pkg + ".StringSet"}})
+		require.NoError(t, err)
+
+		params := doc.Paths.Paths["/products"].Get.Parameters
+		require.Len(t, params, 1)
+		assert.Equal(t, "array", params[0].Type)
+		assert.True(t, params[0].UniqueItems)
+		assert.Equal(t, "string", params[0].Items.Type)
+	})
+
+	t.Run("should fail on invalid set types", func(t *testing.T) {
+		err := (&Options{SetTypes: []string{"StringSet", "github.com/acme/sets."}}).Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `invalid set type "StringSet"`)
+		assert.Contains(t, err.Error(), `invalid set type "github.com/acme/sets."`)
+	})
+}
+
+func TestSetElement(t *testing.T) {
+	index := &typeIndex{}
+	withSetTypes(nil)(index)
+
+	mapset := types.NewPackage("github.com/deckarep/golang-set/v2", "mapset")
+	param := types.NewTypeParam(types.NewTypeName(token.NoPos, mapset, "T", nil), types.Universe.Lookup("comparable").Type())
+	set := types.NewNamed(types.NewTypeName(token.NoPos, mapset, "Set", nil), nil, nil)
+	set.SetTypeParams([]*types.TypeParam{param})
+	set.SetUnderlying(types.NewInterfaceType(nil, nil))
+	strings, err := types.Instantiate(nil, set, []types.Type{types.Typ[types.String]}, true)
+	require.NoError(t, err)
+
+	elem, isSet := index.setElement(strings)
+	require.True(t, isSet, "the sets of golang-set are built in")
+	assert.Equal(t, types.Typ[types.String], elem)
+
+	_, isSet = index.setElement(types.NewNamed(types.NewTypeName(token.NoPos, mapset, "Thread", nil), types.Typ[types.Int], nil))
+	assert.False(t, isSet)
+}
@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// defaultSkippedDirs are the directories skipped with Options.DefaultSkips.
+var defaultSkippedDirs = []string{"vendor", "third_party", "testdata"}
+
+// packageDir returns the directory of the files of a package.
+func packageDir(pkg *packages.Package) (string, bool) {
+	files := pkg.GoFiles
+	if len(files) == 0 {
+		files = pkg.CompiledGoFiles
+	}
+	if len(files) == 0 {
+		return "", false
+	}
+	return filepath.Dir(files[0]), true
+}
+
+// skippedRoot returns the default skipped directory holding a package, relative to the working directory,
+// e.g. "vendor" or "internal/third_party". Packages matching Options.AlsoScan are never skipped.
+func (a *typeIndex) skippedRoot(pkg *packages.Package) (string, bool) {
+	if !a.defaultSkips {
+		return "", false
+	}
+	dir, ok := packageDir(pkg)
+	if !ok {
+		return "", false
+	}
+	rel, err := filepath.Rel(a.workDir, dir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	rel = filepath.ToSlash(rel)
+	for _, glob := range a.alsoScan {
+		if matchPackageGlob(strings.TrimPrefix(glob, "./"), rel) {
+			return "", false
+		}
+	}
+
+	segments := strings.Split(rel, "/")
+	for i, segment := range segments {
+		if slices.Contains(defaultSkippedDirs, segment) {
+			return strings.Join(segments[:i+1], "/"), true
+		}
+	}
+	return "", false
+}
+
+// recordSkippedRoot lists a skipped directory in the stats, once.
+func (a *typeIndex) recordSkippedRoot(root string) {
+	if slices.Contains(a.stats.SkippedDirs, root) {
+		return
+	}
+	a.stats.SkippedDirs = append(a.stats.SkippedDirs, root)
+	slices.Sort(a.stats.SkippedDirs)
+}
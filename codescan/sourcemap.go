@@ -0,0 +1,148 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"encoding/json"
+	"go/token"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// sourcePositionExtension carries the Go position of an element while the spec is built,
+// when a source map is requested. It is removed from the spec by extractSourceMap.
+const sourcePositionExtension = "x-go-source-position"
+
+// recordPosition marks an element of the spec with the Go position it is built from, see Options.SourceMap.
+//
+// The position travels with the element through the transforms of the spec, so that the source map
+// points into the final document.
+func (a *typeIndex) recordPosition(ext *spec.VendorExtensible, pos token.Position) {
+	if a == nil || !a.sourceMap || !pos.IsValid() {
+		return
+	}
+	ext.AddExtension(sourcePositionExtension, pos)
+}
+
+// extractSourceMap removes the positions recorded in a spec, and returns them by JSON pointer,
+// e.g. "/paths/~1users/post/parameters/0".
+func extractSourceMap(doc *spec.Swagger) map[string]token.Position {
+	positions := make(map[string]token.Position)
+	collect := func(ext *spec.VendorExtensible, location string) {
+		if pos, ok := popPosition(ext); ok {
+			positions[strings.TrimPrefix(location, "#")] = pos
+		}
+	}
+
+	walkSpecSchemas(doc, func(schema *spec.Schema, location string) {
+		collect(&schema.VendorExtensible, location)
+	})
+
+	collectHeaders := func(resp *spec.Response, location string) {
+		for _, name := range sortedKeys(resp.Headers) {
+			header := resp.Headers[name]
+			collect(&header.VendorExtensible, location+"/headers/"+escapePointer(name))
+			resp.Headers[name] = header
+		}
+	}
+	collectParams := func(params []spec.Parameter, location string) {
+		for i := range params {
+			collect(&params[i].VendorExtensible, location+"/"+strconv.Itoa(i))
+		}
+	}
+
+	for _, name := range sortedKeys(doc.Parameters) {
+		param := doc.Parameters[name]
+		collect(&param.VendorExtensible, "#/parameters/"+escapePointer(name))
+		doc.Parameters[name] = param
+	}
+	for _, name := range sortedKeys(doc.Responses) {
+		resp := doc.Responses[name]
+		location := "#/responses/" + escapePointer(name)
+		collect(&resp.VendorExtensible, location)
+		collectHeaders(&resp, location)
+		doc.Responses[name] = resp
+	}
+
+	if doc.Paths == nil {
+		return positions
+	}
+	for _, pth := range sortedKeys(doc.Paths.Paths) {
+		pathItem := doc.Paths.Paths[pth]
+		location := "#/paths/" + escapePointer(pth)
+		collectParams(pathItem.Parameters, location+"/parameters")
+		for method, op := range pathItemOperations(&pathItem) {
+			opLocation := location + "/" + method
+			collect(&op.VendorExtensible, opLocation)
+			collectParams(op.Parameters, opLocation+"/parameters")
+			if op.Responses == nil {
+				continue
+			}
+			if op.Responses.Default != nil {
+				collectHeaders(op.Responses.Default, opLocation+"/responses/default")
+			}
+			for _, code := range sortedKeys(op.Responses.StatusCodeResponses) {
+				resp := op.Responses.StatusCodeResponses[code]
+				collectHeaders(&resp, opLocation+"/responses/"+strconv.Itoa(code))
+				op.Responses.StatusCodeResponses[code] = resp
+			}
+		}
+		doc.Paths.Paths[pth] = pathItem
+	}
+
+	return positions
+}
+
+// popPosition removes the recorded position of an element.
+func popPosition(ext *spec.VendorExtensible) (token.Position, bool) {
+	value, ok := ext.Extensions[sourcePositionExtension]
+	if !ok {
+		return token.Position{}, false
+	}
+	delete(ext.Extensions, sourcePositionExtension)
+	if len(ext.Extensions) == 0 {
+		ext.Extensions = nil
+	}
+	return positionValue(value)
+}
+
+// positionValue returns the position held by a sourcePositionExtension.
+func positionValue(value any) (token.Position, bool) {
+	if pos, isPosition := value.(token.Position); isPosition {
+		return pos, true
+	}
+	// the schema went through a JSON copy, e.g. for a derived model
+	var pos token.Position
+	jazon, err := json.Marshal(value)
+	if err != nil || json.Unmarshal(jazon, &pos) != nil {
+		return token.Position{}, false
+	}
+	return pos, true
+}
+
+// RelativePath returns the path of a file relative to a directory, e.g. the working directory of the source
+// map, when the file is below it, and else the file. Both have forward slashes: the backslashes of Windows
+// paths are separators whatever the system, so that the paths written are the same on every system.
+func RelativePath(dir, file string) string {
+	dir, file = slashPath(dir), slashPath(file)
+	if file == dir {
+		return "."
+	}
+	if rel, below := strings.CutPrefix(file, strings.TrimSuffix(dir, "/")+"/"); below {
+		return rel
+	}
+	return file
+}
+
+// slashPath cleans a path with forward slashes, and the upper case drive letter of Windows.
+func slashPath(name string) string {
+	name = path.Clean(strings.ReplaceAll(name, `\`, "/"))
+	if len(name) >= 2 && name[1] == ':' {
+		name = strings.ToUpper(name[:1]) + name[1:]
+	}
+	return name
+}
@@ -0,0 +1,100 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"encoding/json"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// resolvePointer tells if a JSON pointer designates a value of a JSON document.
+func resolvePointer(document any, pointer string) bool {
+	current := document
+	for _, reference := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		reference = strings.NewReplacer("~1", "/", "~0", "~").Replace(reference)
+		switch value := current.(type) {
+		case map[string]any:
+			next, ok := value[reference]
+			if !ok {
+				return false
+			}
+			current = next
+		case []any:
+			index, err := strconv.Atoi(reference)
+			if err != nil || index < 0 || index >= len(value) {
+				return false
+			}
+			current = value[index]
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func TestSourceMap(t *testing.T) {
+	for _, inline := range []bool{false, true} {
+		t.Run("inline single use "+strconv.FormatBool(inline), func(t *testing.T) {
+			sourceMap := make(map[string]token.Position)
+			doc, err := Run(&Options{
+				Packages:        []string{"github.com/3idey/codescan/fixtures/goparsing/petstore/..."},
+				InlineSingleUse: inline,
+				SourceMap:       sourceMap,
+			})
+			require.NoError(t, err)
+
+			jazon, err := MarshalJSON(doc, true)
+			require.NoError(t, err)
+			assert.NotContains(t, string(jazon), sourcePositionExtension)
+
+			var document any
+			require.NoError(t, json.Unmarshal(jazon, &document))
+			require.NotEmpty(t, sourceMap)
+			for pointer, pos := range sourceMap {
+				assert.True(t, resolvePointer(document, pointer), "pointer %s is not in the spec", pointer)
+				assert.True(t, pos.IsValid(), pointer)
+			}
+
+			for pointer, file := range map[string]string{
+				"/paths/~1pets/get":                              "pets.go",
+				"/paths/~1pets/get/parameters/0":                 "pets.go",
+				"/paths/~1orders/post":                           "orders.go",
+				"/responses/genericError":                        "pets.go",
+				"/responses/genericError/schema/properties/code": "pets.go",
+			} {
+				require.Contains(t, sourceMap, pointer)
+				assert.Equal(t, file, filepath.Base(sourceMap[pointer].Filename), pointer)
+			}
+
+			if !inline {
+				require.Contains(t, sourceMap, "/definitions/pet/properties/name")
+				assert.Equal(t, "pet.go", filepath.Base(sourceMap["/definitions/pet/properties/name"].Filename))
+			}
+		})
+	}
+}
+
+func TestRelativePath(t *testing.T) {
+	for _, tc := range []struct {
+		dir, file, expected string
+	}{
+		{"/home/dev/api", "/home/dev/api/models/user.go", "models/user.go"},
+		{"/home/dev/api/", "/home/dev/api/user.go", "user.go"},
+		{"/home/dev/api", "/home/dev/apis/user.go", "/home/dev/apis/user.go"},
+		{"/home/dev/api", "/home/dev/api", "."},
+		{`C:\Users\dev\api`, `C:\Users\dev\api\models\user.go`, "models/user.go"},
+		{`c:\Users\dev\api\`, `C:\Users\dev\api\user.go`, "user.go"},
+		{`C:\Users\dev\api`, `D:\go\pkg\mod\user.go`, "D:/go/pkg/mod/user.go"},
+		{`C:\Users\dev\api`, `C:/Users/dev/api/user.go`, "user.go"},
+	} {
+		assert.Equal(t, tc.expected, RelativePath(tc.dir, tc.file), "%s in %s", tc.file, tc.dir)
+	}
+}
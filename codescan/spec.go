@@ -4,7 +4,10 @@
 package codescan
 
 import (
-	"go/ast"
+	"fmt"
+	"go/token"
+	"maps"
+	"slices"
 
 	"github.com/go-openapi/spec"
 )
@@ -18,6 +21,9 @@ func newSpecBuilder(input *spec.Swagger, sc *scanCtx, scanModels bool) *specBuil
 	if input.Paths == nil {
 		input.Paths = new(spec.Paths)
 	}
+	if sc != nil {
+		sc.app.normalizePaths(input.Paths)
+	}
 	if input.Definitions == nil {
 		input.Definitions = make(map[string]spec.Schema)
 	}
@@ -35,6 +41,9 @@ func newSpecBuilder(input *spec.Swagger, sc *scanCtx, scanModels bool) *specBuil
 		operations:  collectOperationsFromInput(input),
 		definitions: input.Definitions,
 		responses:   input.Responses,
+
+		inputDefinitions: sliceToSet(slices.Collect(maps.Keys(input.Definitions))),
+		inputSecurity:    input.Security,
 	}
 }
 
@@ -46,9 +55,23 @@ type specBuilder struct {
 	definitions map[string]spec.Schema
 	responses   map[string]spec.Response
 	operations  map[string]*spec.Operation
+	unwraps     map[string]responseUnwrap // by response name
+
+	inputDefinitions map[string]bool       // names of the definitions of the input spec, see pruneUnused
+	inputSecurity    []map[string][]string // top-level security requirements of the input spec, see checkSecurity
+
+	definitionsBuilt int
+	pathsBuilt       int
+	errs             []error // the errors of the declarations built, see collect
+
+	merge *inputMerge // the merge with the input spec, see Options.MergeStrategy
 }
 
 func (s *specBuilder) Build() (*spec.Swagger, error) {
+	if err := s.startMerge(); err != nil {
+		return nil, err
+	}
+
 	// this initial scan step is skipped if !scanModels.
 	// Discovered dependencies should however be resolved.
 	if err := s.buildModels(); err != nil {
@@ -68,6 +91,7 @@ func (s *specBuilder) Build() (*spec.Swagger, error) {
 		return nil, err
 	}
 
+	s.reportDuplicateOperationIDs()
 	if err := s.buildRoutes(); err != nil {
 		return nil, err
 	}
@@ -76,17 +100,145 @@ func (s *specBuilder) Build() (*spec.Swagger, error) {
 		return nil, err
 	}
 
+	// types resolved from the responses of the routes
+	if err := s.buildDiscovered(); err != nil {
+		return nil, err
+	}
+	if err := s.checkErrors(); err != nil {
+		return nil, err
+	}
+
+	if err := s.unwrapResponses(); err != nil {
+		return nil, err
+	}
+	s.mergeInput()
+
+	if err := s.buildPathDocs(); err != nil {
+		return nil, err
+	}
+
+	if err := s.buildTagDocs(); err != nil {
+		return nil, err
+	}
+
 	if err := s.buildMeta(); err != nil {
 		return nil, err
 	}
+	if err := s.checkMergeConflicts(); err != nil {
+		return nil, err
+	}
+	if err := s.checkStrictTags(); err != nil {
+		return nil, err
+	}
+	s.applySecurityDefaults()
+	if err := s.checkSecurity(); err != nil {
+		return nil, err
+	}
+	if err := s.checkRequiredSecurity(); err != nil {
+		return nil, err
+	}
+	s.applyNegotiatedMediaTypes()
+	if err := s.applyFormConsumes(); err != nil {
+		return nil, err
+	}
+
+	s.dropOutOfScopeResponses()
+
+	if err := s.applyRateLimits(); err != nil {
+		return nil, err
+	}
+	s.applyDefaultIdempotency()
+
+	if err := FilterAudience(s.input, s.ctx.opts.Audience, s.ctx.opts.RequireAudience); err != nil {
+		return nil, err
+	}
 
 	if s.input.Swagger == "" {
 		s.input.Swagger = "2.0"
 	}
 
+	if s.ctx.opts.MergeIdentical {
+		mergeIdenticalDefinitions(s.input)
+	}
+
+	if s.ctx.opts.InlineSingleUse {
+		inlineSingleUse(s.input, s.ctx.opts.DescWithRef)
+	}
+
+	if err := s.applyDefinitionRenames(); err != nil {
+		return nil, err
+	}
+	s.pruneUnused()
+
+	applyEnumExtensions(s.input, s.ctx.opts.EnumExtensionStyle)
+	if s.ctx.opts.SortParameters {
+		sortParameters(s.input)
+	}
+	hoisted, err := hoistParameters(s.input, s.ctx.opts.HoistParameters)
+	if err != nil {
+		return nil, err
+	}
+	s.ctx.app.stats.HoistedParameters = hoisted
+
+	s.reportUnresolvedRefs()
+	if s.ctx.opts.RelativeRefs != "" {
+		if err := QualifyRefs(s.input, s.ctx.opts.RelativeRefs); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.applyCodeSamples(); err != nil {
+		return nil, err
+	}
+
+	if s.ctx.opts.DescriptionCatalog != nil {
+		translateStrings(s.input, s.ctx.opts.DescriptionCatalog, s.ctx.opts.MarkUntranslated)
+	}
+
+	if s.ctx.opts.ValidateExamples {
+		if err := s.ctx.app.checkNamedExamples(s.input); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.ctx.opts.NoExamples {
+		StripExamples(s.input)
+	}
+
+	if err := applyGenerationInfo(s.input, s.ctx.opts); err != nil {
+		return nil, err
+	}
+
 	return s.input, nil
 }
 
+// dropOutOfScopeResponses removes from operations the references to the responses excluded
+// from the scan because of their scope, which would otherwise dangle.
+func (s *specBuilder) dropOutOfScopeResponses() {
+	if len(s.ctx.app.outOfScopeResponses) == 0 || s.input.Paths == nil {
+		return
+	}
+
+	for _, pathItem := range s.input.Paths.Paths {
+		for _, op := range pathItemOperations(&pathItem) {
+			if op.Responses == nil {
+				continue
+			}
+			for _, name := range s.ctx.app.outOfScopeResponses {
+				ref := "#/responses/" + name
+				if op.Responses.Default != nil && op.Responses.Default.Ref.String() == ref {
+					op.Responses.Default = nil
+				}
+				for code, resp := range op.Responses.StatusCodeResponses {
+					if resp.Ref.String() == ref {
+						delete(op.Responses.StatusCodeResponses, code)
+					}
+				}
+			}
+		}
+	}
+}
+
 func (s *specBuilder) buildDiscovered() error {
 	// loop over discovered until all the items are in definitions
 	keepGoing := len(s.discovered) > 0
@@ -94,15 +246,13 @@ func (s *specBuilder) buildDiscovered() error {
 		var queue []*entityDecl
 		for _, d := range s.discovered {
 			nm, _ := d.Names()
-			if _, ok := s.definitions[nm]; !ok {
+			if _, ok := s.definitions[nm]; !ok || s.rebuildsInput(nm) {
 				queue = append(queue, d)
 			}
 		}
 		s.discovered = nil
-		for _, sd := range queue {
-			if err := s.buildDiscoveredSchema(sd); err != nil {
-				return err
-			}
+		if err := s.buildSchemas(queue); err != nil {
+			return err
 		}
 		keepGoing = len(s.discovered) > 0
 	}
@@ -110,26 +260,81 @@ func (s *specBuilder) buildDiscovered() error {
 	return nil
 }
 
-func (s *specBuilder) buildDiscoveredSchema(decl *entityDecl) error {
+// buildDiscoveredSchema builds the schema of a declaration, or records the schema prebuilt concurrently, if
+// any, see buildSchemas.
+func (s *specBuilder) buildDiscoveredSchema(decl *entityDecl, prebuilt *prebuiltSchema) error {
+	if _, indexed := s.ctx.app.indexedRef(decl); indexed {
+		// published by another spec
+		return nil
+	}
 	sb := &schemaBuilder{
 		ctx:        s.ctx,
 		decl:       decl,
 		discovered: s.discovered,
 	}
-	if err := sb.Build(s.definitions); err != nil {
-		return err
+	build := func() error {
+		return sb.Build(s.definitions)
+	}
+	if prebuilt != nil {
+		sb = prebuilt.builder
+		sb.ctx = s.ctx
+		build = func() error {
+			s.ctx.app.join(prebuilt.fork)
+			return sb.commit(s.definitions, prebuilt.schema)
+		}
+	}
+	pos, subject := decl.Pkg.Fset.Position(decl.Ident.Pos()), "model "+goTypeKey(decl)
+	err := s.recoverBuild(pos, subject, build)
+	if err != nil {
+		return s.collect(pos, subject, err)
 	}
+	if prebuilt != nil {
+		s.ctx.app.recordModel(decl, prebuilt)
+	}
+	s.scannedDefinition(sb.Name, pos)
 	s.discovered = append(s.discovered, sb.postDecls...)
+	s.discoverSubtypes(decl)
+	s.definitionsBuilt++
+	s.ctx.progress.report(PhaseDefinitions, s.definitionsBuilt, 0)
 	return nil
 }
 
 func (s *specBuilder) buildMeta() error {
+	if s.merge != nil {
+		return s.buildMergedMeta()
+	}
 	// build swagger object
 	for _, decl := range s.ctx.app.Meta {
-		if err := newMetaParser(s.input).Parse(decl.Comments); err != nil {
+		parser := newMetaParser(s.input)
+		parser.preserveFormat = s.ctx.opts.PreserveCommentFormat
+		parser.fset = decl.fset
+		if err := parser.Parse(decl.Comments); err != nil {
 			return err
 		}
 	}
+	mergeMeta(s.input, s.ctx.opts.Meta)
+	return nil
+}
+
+// buildMergedMeta builds the swagger:meta apart from the input spec, merging them, see inputMerge.mergeMeta.
+func (s *specBuilder) buildMergedMeta() error {
+	scanned := new(spec.Swagger)
+	positions := make(map[string]token.Position) // of the security definitions
+	for _, decl := range s.ctx.app.Meta {
+		parser := newMetaParser(scanned)
+		parser.preserveFormat = s.ctx.opts.PreserveCommentFormat
+		parser.fset = decl.fset
+		if err := parser.Parse(decl.Comments); err != nil {
+			return err
+		}
+		for name := range scanned.SecurityDefinitions {
+			if _, found := positions[name]; !found {
+				positions[name] = decl.fset.Position(decl.Comments.Pos())
+			}
+		}
+	}
+	s.merge.mergeMeta(s.input, scanned, positions)
+	mergeMeta(s.input, s.ctx.opts.Meta)
 	return nil
 }
 
@@ -140,9 +345,15 @@ func (s *specBuilder) buildOperations() error {
 			ctx:        s.ctx,
 			path:       pp,
 		}
-		if err := ob.Build(s.input.Paths); err != nil {
+		subject := fmt.Sprintf("operation %s %s", pp.Method, pp.Path)
+		err := s.recoverBuild(pp.Pos, subject, func() error {
+			return ob.Build(s.input.Paths)
+		})
+		if err := s.collect(pp.Pos, subject, err); err != nil {
 			return err
 		}
+		s.discovered = append(s.discovered, ob.postDecls...)
+		s.reportPath()
 	}
 	return nil
 }
@@ -157,14 +368,44 @@ func (s *specBuilder) buildRoutes() error {
 			operations:  s.operations,
 			definitions: s.definitions,
 		}
-		if err := rb.Build(s.input.Paths); err != nil {
+		subject := fmt.Sprintf("route %s %s", pp.Method, pp.Path)
+		err := s.recoverBuild(pp.Pos, subject, func() error {
+			return rb.Build(s.input.Paths)
+		})
+		if err := s.collect(pp.Pos, subject, err); err != nil {
 			return err
 		}
+		s.discovered = append(s.discovered, rb.postDecls...)
+		s.reportPath()
 	}
 
 	return nil
 }
 
+// reportDuplicateOperationIDs reports the routes and operations with the operationId of an earlier one, at
+// their position: the spec documents both with the same operationId, which the generators reject.
+func (s *specBuilder) reportDuplicateOperationIDs() {
+	seen := make(map[string]parsedPathContent)
+	for _, pp := range slices.Concat(s.ctx.app.Routes, s.ctx.app.Operations) {
+		previous, duplicate := seen[pp.ID]
+		if !duplicate {
+			seen[pp.ID] = pp
+			continue
+		}
+		s.ctx.app.diagnose(Diagnostic{
+			Pos:  pp.Pos,
+			Code: DiagnosticDuplicateOperationID,
+			Message: fmt.Sprintf("the operationId %s of %s %s is already used by %s %s, at %v",
+				pp.ID, pp.Method, pp.Path, previous.Method, previous.Path, previous.Pos),
+		})
+	}
+}
+
+func (s *specBuilder) reportPath() {
+	s.pathsBuilt++
+	s.ctx.progress.report(PhaseOperations, s.pathsBuilt, len(s.ctx.app.Routes)+len(s.ctx.app.Operations))
+}
+
 func (s *specBuilder) buildResponses() error {
 	// build responses dictionary
 	for _, decl := range s.ctx.app.Responses {
@@ -172,10 +413,24 @@ func (s *specBuilder) buildResponses() error {
 			ctx:  s.ctx,
 			decl: decl,
 		}
-		if err := rb.Build(s.responses); err != nil {
-			return err
+		pos, subject := decl.Pkg.Fset.Position(decl.Ident.Pos()), "response "+goTypeKey(decl)
+		err := s.recoverBuild(pos, subject, func() error {
+			return rb.Build(s.responses)
+		})
+		if err != nil {
+			if err := s.collect(pos, subject, err); err != nil {
+				return err
+			}
+			continue
 		}
 		s.discovered = append(s.discovered, rb.postDecls...)
+		if rb.unwrap != "" {
+			name, _ := decl.ResponseNames()
+			if s.unwraps == nil {
+				s.unwraps = make(map[string]responseUnwrap)
+			}
+			s.unwraps[name] = responseUnwrap{property: rb.unwrap, pos: decl.Pkg.Fset.Position(decl.Ident.Pos())}
+		}
 	}
 	return nil
 }
@@ -187,7 +442,11 @@ func (s *specBuilder) buildParameters() error {
 			ctx:  s.ctx,
 			decl: decl,
 		}
-		if err := pb.Build(s.operations); err != nil {
+		pos, subject := decl.Pkg.Fset.Position(decl.Ident.Pos()), "parameters "+goTypeKey(decl)
+		err := s.recoverBuild(pos, subject, func() error {
+			return pb.Build(s.operations)
+		})
+		if err := s.collect(pos, subject, err); err != nil {
 			return err
 		}
 		s.discovered = append(s.discovered, pb.postDecls...)
@@ -201,28 +460,23 @@ func (s *specBuilder) buildModels() error {
 		return nil
 	}
 
-	for _, decl := range s.ctx.app.Models {
-		if err := s.buildDiscoveredSchema(decl); err != nil {
-			return err
-		}
+	if err := s.buildSchemas(sortedDecls(s.ctx.app.Models)); err != nil {
+		return err
 	}
 
 	return s.joinExtraModels()
 }
 
 func (s *specBuilder) joinExtraModels() error {
-	tmp := make(map[*ast.Ident]*entityDecl, len(s.ctx.app.ExtraModels))
-	for k, v := range s.ctx.app.ExtraModels {
-		tmp[k] = v
-		s.ctx.app.Models[k] = v
-		delete(s.ctx.app.ExtraModels, k)
+	tmp := sortedDecls(s.ctx.app.ExtraModels)
+	for _, decl := range tmp {
+		s.ctx.app.Models[decl.Ident] = decl
+		delete(s.ctx.app.ExtraModels, decl.Ident)
 	}
 
 	// process extra models and see if there is any reference to a new extra one
-	for _, decl := range tmp {
-		if err := s.buildDiscoveredSchema(decl); err != nil {
-			return err
-		}
+	if err := s.buildSchemas(tmp); err != nil {
+		return err
 	}
 
 	if len(s.ctx.app.ExtraModels) > 0 {
@@ -235,7 +489,8 @@ func (s *specBuilder) joinExtraModels() error {
 func collectOperationsFromInput(input *spec.Swagger) map[string]*spec.Operation {
 	operations := make(map[string]*spec.Operation)
 	if input != nil && input.Paths != nil {
-		for _, pth := range input.Paths.Paths {
+		for _, key := range sortedKeys(input.Paths.Paths) {
+			pth := input.Paths.Paths[key]
 			if pth.Get != nil {
 				operations[pth.Get.ID] = pth.Get
 			}
@@ -0,0 +1,779 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package specdiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+const (
+	definitionsPrefix = "#/definitions/"
+	parametersPrefix  = "#/parameters/"
+	responsesPrefix   = "#/responses/"
+
+	// injectedDefaultExtension marks the responses injected by Options.DefaultResponse of codescan.
+	injectedDefaultExtension = "x-injected-default"
+)
+
+// methods are the methods of the operations of a path item, in the order of the changes.
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch"}
+
+// Diff compares the surface of two specs, and classifies the changes with a policy, or the DefaultPolicy when
+// nil. The changes are ordered by path and method, then by definition name.
+//
+// The operations are matched by path and method, their parameters by location and name, with those of their
+// path and those they refer to in #/parameters, and their responses by status code, with those they refer to
+// in #/responses. The schemas are compared with their allOf members, which are not refs, flattened, and the
+// definitions are compared once, by name: the schemas which refer to them only report a changed $ref.
+func Diff(before, after *spec.Swagger, policy Policy) []Change {
+	if policy == nil {
+		policy = DefaultPolicy()
+	}
+	d := &differ{before: before, after: after, directions: definitionDirections(before)}
+	for name, direction := range definitionDirections(after) {
+		d.directions[name] |= direction
+	}
+	d.diffSpec()
+	return policy.Classify(d.changes)
+}
+
+type differ struct {
+	before, after *spec.Swagger
+	// directions of the definitions, by name, in both specs
+	directions map[string]Direction
+	changes    []Change
+}
+
+func (d *differ) report(kind Kind, path JSONPointer, direction Direction, before, after any, format string, args ...any) {
+	d.changes = append(d.changes, Change{
+		Kind:        kind,
+		Path:        path,
+		Direction:   direction,
+		Before:      before,
+		After:       after,
+		Description: fmt.Sprintf(format, args...),
+	})
+}
+
+func (d *differ) diffSpec() {
+	if d.before.BasePath != d.after.BasePath {
+		d.report(BasePathChanged, Pointer("basePath"), Both, d.before.BasePath, d.after.BasePath,
+			"the base path is %s instead of %s", orNone(d.after.BasePath), orNone(d.before.BasePath))
+	}
+
+	beforePaths, afterPaths := paths(d.before), paths(d.after)
+	for _, pth := range sortedUnion(beforePaths, afterPaths) {
+		ptr := Pointer("paths", pth)
+		previous, existed := beforePaths[pth]
+		current, exists := afterPaths[pth]
+		switch {
+		case !exists:
+			d.report(PathRemoved, ptr, 0, previous, nil, "the path %s was removed", pth)
+		case !existed:
+			d.report(PathAdded, ptr, 0, nil, current, "the path %s was added", pth)
+		default:
+			d.diffPathItem(pth, &previous, &current)
+		}
+	}
+
+	for _, name := range sortedUnion(d.before.Definitions, d.after.Definitions) {
+		ptr := Pointer("definitions", name)
+		direction := d.directions[name]
+		previous, existed := d.before.Definitions[name]
+		current, exists := d.after.Definitions[name]
+		switch {
+		case !exists:
+			d.report(DefinitionRemoved, ptr, direction, previous, nil, "the definition %s was removed", name)
+		case !existed:
+			d.report(DefinitionAdded, ptr, direction, nil, current, "the definition %s was added", name)
+		default:
+			d.diffSchema(ptr, direction, &previous, &current)
+		}
+	}
+}
+
+func (d *differ) diffPathItem(pth string, previousItem, currentItem *spec.PathItem) {
+	for _, method := range methods {
+		previous, current := operation(previousItem, method), operation(currentItem, method)
+		ptr := Pointer("paths", pth, method)
+		name := strings.ToUpper(method) + " " + pth
+		switch {
+		case previous == nil && current == nil:
+		case current == nil:
+			d.report(OperationRemoved, ptr, 0, previous, nil, "the operation %s was removed", name)
+		case previous == nil:
+			d.report(OperationAdded, ptr, 0, nil, current, "the operation %s was added", name)
+		default:
+			d.diffOperation(ptr, name, operationParameters(d.before, pth, method, previousItem, previous),
+				operationParameters(d.after, pth, method, currentItem, current), previous, current)
+		}
+	}
+}
+
+func (d *differ) diffOperation(ptr JSONPointer, name string, previousParams, currentParams map[string]located[spec.Parameter], previous, current *spec.Operation) {
+	if previous.ID != current.ID {
+		d.report(OperationIDChanged, ptr.Append("operationId"), 0, previous.ID, current.ID,
+			"the operation ID of %s is %s instead of %s", name, orNone(current.ID), orNone(previous.ID))
+	}
+	if previous.Deprecated != current.Deprecated {
+		format := "the operation %s is deprecated"
+		if !current.Deprecated {
+			format = "the operation %s isn't deprecated anymore"
+		}
+		d.report(OperationDeprecated, ptr.Append("deprecated"), 0, previous.Deprecated, current.Deprecated, format, name)
+	}
+	d.diffDescription(ptr.Append("summary"), 0, "summary", previous.Summary, current.Summary)
+	d.diffDescription(ptr.Append("description"), 0, "description", previous.Description, current.Description)
+
+	d.diffMediaTypes(ptr.Append("consumes"), Request, ConsumesAdded, ConsumesRemoved, "consume",
+		orDefault(previous.Consumes, d.before.Consumes), orDefault(current.Consumes, d.after.Consumes))
+	d.diffMediaTypes(ptr.Append("produces"), Response, ProducesAdded, ProducesRemoved, "produce",
+		orDefault(previous.Produces, d.before.Produces), orDefault(current.Produces, d.after.Produces))
+	d.diffSecurity(ptr.Append("security"), name, security(d.before, previous), security(d.after, current))
+
+	for _, key := range sortedUnion(previousParams, currentParams) {
+		old, existed := previousParams[key]
+		param, exists := currentParams[key]
+		switch {
+		case !exists:
+			d.report(ParameterRemoved, old.ptr, Request, old.value, nil, "the parameter %s was removed", key)
+		case !existed && param.value.Required:
+			d.report(RequiredParameterAdded, param.ptr, Request, nil, param.value, "the new parameter %s is required", key)
+		case !existed:
+			d.report(ParameterAdded, param.ptr, Request, nil, param.value, "the optional parameter %s was added", key)
+		default:
+			d.diffParameter(param.ptr, key, &old.value, &param.value)
+		}
+	}
+
+	previousResponses, currentResponses := operationResponses(d.before, ptr, previous), operationResponses(d.after, ptr, current)
+	for _, code := range sortedUnion(previousResponses, currentResponses) {
+		old, existed := previousResponses[code]
+		resp, exists := currentResponses[code]
+		switch {
+		case (!existed || injected(old.value)) && (!exists || injected(resp.value)):
+			d.diffInjectedResponse(code, old, resp, existed, exists)
+		case !exists:
+			d.report(ResponseRemoved, old.ptr, Response, old.value, nil, "the response %s was removed", code)
+		case !existed:
+			d.report(ResponseAdded, resp.ptr, Response, nil, resp.value, "the response %s was added", code)
+		default:
+			d.diffResponse(resp.ptr, &old.value, &resp.value)
+		}
+	}
+}
+
+// diffInjectedResponse reports an injected response which was added or removed, or changed, as a single
+// change without the details of the response: the changes of its model are those of its definition.
+func (d *differ) diffInjectedResponse(code string, old, resp located[spec.Response], existed, exists bool) {
+	switch {
+	case !exists:
+		d.report(InjectedDefaultChanged, old.ptr, Response, old.value, nil, "the injected response %s was removed", code)
+	case !existed:
+		d.report(InjectedDefaultChanged, resp.ptr, Response, nil, resp.value, "the injected response %s was added", code)
+	case jsonKey(old.value) != jsonKey(resp.value):
+		d.report(InjectedDefaultChanged, resp.ptr, Response, old.value, resp.value, "the injected response %s changed", code)
+	}
+}
+
+func (d *differ) diffDescription(ptr JSONPointer, direction Direction, what, previous, current string) {
+	if previous != current {
+		d.report(DescriptionChanged, ptr, direction, previous, current, "the %s changed", what)
+	}
+}
+
+func (d *differ) diffMediaTypes(ptr JSONPointer, direction Direction, added, removed Kind, verb string, previous, current []string) {
+	for _, mediaType := range previous {
+		if !slices.Contains(current, mediaType) {
+			d.report(removed, ptr, direction, mediaType, nil, "the operation doesn't %s %s anymore", verb, mediaType)
+		}
+	}
+	for _, mediaType := range current {
+		if !slices.Contains(previous, mediaType) {
+			d.report(added, ptr, direction, nil, mediaType, "the operation %ss %s", verb, mediaType)
+		}
+	}
+}
+
+// diffSecurity compares the alternative security requirements of an operation, i.e. those of the operation,
+// or of the spec.
+func (d *differ) diffSecurity(ptr JSONPointer, name string, previous, current []map[string][]string) {
+	switch {
+	case len(previous) == 0 && len(current) == 0:
+		return
+	case len(previous) == 0:
+		d.report(SecurityAdded, ptr, Request, nil, current, "the operation %s requires %s", name, securityKeys(current))
+		return
+	case len(current) == 0:
+		d.report(SecurityRemoved, ptr, Request, previous, nil, "the operation %s doesn't require security anymore", name)
+		return
+	}
+	previousKeys, currentKeys := securityKeys(previous), securityKeys(current)
+	for i, key := range previousKeys {
+		if !slices.Contains(currentKeys, key) {
+			d.report(SecurityRequirementRemoved, ptr, Request, previous[i], nil, "the operation %s doesn't accept %s anymore", name, key)
+		}
+	}
+	for i, key := range currentKeys {
+		if !slices.Contains(previousKeys, key) {
+			d.report(SecurityRequirementAdded, ptr, Request, nil, current[i], "the operation %s accepts %s", name, key)
+		}
+	}
+}
+
+func (d *differ) diffParameter(ptr JSONPointer, key string, previous, current *spec.Parameter) {
+	if previous.Required != current.Required {
+		if current.Required {
+			d.report(ParameterRequired, ptr.Append("required"), Request, false, true, "the parameter %s is required", key)
+		} else {
+			d.report(ParameterOptional, ptr.Append("required"), Request, true, false, "the parameter %s isn't required anymore", key)
+		}
+	}
+	d.diffDescription(ptr.Append("description"), Request, "description", previous.Description, current.Description)
+	if previous.In == "body" {
+		d.diffDeprecated(ptr, Request, previous.Extensions, current.Extensions)
+		d.diffSchemaAt(ptr.Append("schema"), Request, previous.Schema, current.Schema)
+		return
+	}
+	if previousFormat, currentFormat := collectionFormat(previous.CollectionFormat), collectionFormat(current.CollectionFormat); previousFormat != currentFormat {
+		d.report(CollectionFormatChanged, ptr.Append("collectionFormat"), Request, previousFormat, currentFormat,
+			"the collection format of %s is %s instead of %s", key, currentFormat, previousFormat)
+	}
+	d.diffSchema(ptr, Request,
+		simpleSchema(previous.SimpleSchema, previous.CommonValidations, previous.Extensions),
+		simpleSchema(current.SimpleSchema, current.CommonValidations, current.Extensions))
+}
+
+func (d *differ) diffResponse(ptr JSONPointer, previous, current *spec.Response) {
+	d.diffDescription(ptr.Append("description"), Response, "description", previous.Description, current.Description)
+	d.diffSchemaAt(ptr.Append("schema"), Response, previous.Schema, current.Schema)
+	for _, name := range sortedUnion(previous.Headers, current.Headers) {
+		headerPtr := ptr.Append("headers", name)
+		old, existed := previous.Headers[name]
+		header, exists := current.Headers[name]
+		switch {
+		case !exists:
+			d.report(ResponseHeaderRemoved, headerPtr, Response, old, nil, "the header %s was removed", name)
+		case !existed:
+			d.report(ResponseHeaderAdded, headerPtr, Response, nil, header, "the header %s was added", name)
+		default:
+			d.diffSchema(headerPtr, Response,
+				simpleSchema(old.SimpleSchema, old.CommonValidations, old.Extensions),
+				simpleSchema(header.SimpleSchema, header.CommonValidations, header.Extensions))
+		}
+	}
+}
+
+// diffSchemaAt compares the optional schemas of an element, e.g. of a response.
+func (d *differ) diffSchemaAt(ptr JSONPointer, direction Direction, previous, current *spec.Schema) {
+	switch {
+	case previous == nil && current == nil:
+	case current == nil:
+		d.report(SchemaRemoved, ptr, direction, previous, nil, "the schema was removed")
+	case previous == nil:
+		d.report(SchemaAdded, ptr, direction, nil, current, "the schema was added")
+	default:
+		d.diffSchema(ptr, direction, previous, current)
+	}
+}
+
+func (d *differ) diffSchema(ptr JSONPointer, direction Direction, previous, current *spec.Schema) {
+	d.diffDeprecated(ptr, direction, previous.Extensions, current.Extensions)
+	if previousRef, currentRef := previous.Ref.String(), current.Ref.String(); previousRef != "" || currentRef != "" {
+		if previousRef != currentRef {
+			d.report(RefChanged, ptr.Append("$ref"), direction, previousRef, currentRef,
+				"the schema refers to %s instead of %s", orNone(currentRef), orNone(previousRef))
+		}
+		return
+	}
+
+	previousType, currentType := strings.Join(previous.Type, ","), strings.Join(current.Type, ",")
+	switch {
+	case previousType != currentType:
+		d.report(TypeChanged, ptr.Append("type"), direction, previousType, currentType,
+			"the type is %s instead of %s", orNone(currentType), orNone(previousType))
+	case previous.Format != current.Format:
+		d.report(FormatChanged, ptr.Append("format"), direction, previous.Format, current.Format,
+			"the format is %s instead of %s", orNone(current.Format), orNone(previous.Format))
+	}
+	d.diffDescription(ptr.Append("description"), direction, "description", previous.Description, current.Description)
+	if previousDefault, currentDefault := jsonKey(previous.Default), jsonKey(current.Default); previousDefault != currentDefault {
+		d.report(DefaultChanged, ptr.Append("default"), direction, previous.Default, current.Default,
+			"the default is %s instead of %s", currentDefault, previousDefault)
+	}
+	if previousNullable, currentNullable := nullable(previous), nullable(current); previousNullable != currentNullable {
+		if currentNullable {
+			d.report(NullableAdded, ptr.Append("x-nullable"), direction, false, true, "the value may be null")
+		} else {
+			d.report(NullableRemoved, ptr.Append("x-nullable"), direction, true, false, "the value may not be null anymore")
+		}
+	}
+	d.diffEnum(ptr.Append("enum"), direction, previous.Enum, current.Enum)
+	d.diffConstraints(ptr, direction, previous, current)
+
+	previousFlat, currentFlat := flatten(ptr, previous), flatten(ptr, current)
+	for _, ref := range previousFlat.refs {
+		if !slices.Contains(currentFlat.refs, ref) {
+			d.report(AllOfRemoved, ptr.Append("allOf"), direction, ref, nil, "the schema doesn't compose %s anymore", ref)
+		}
+	}
+	for _, ref := range currentFlat.refs {
+		if !slices.Contains(previousFlat.refs, ref) {
+			d.report(AllOfAdded, ptr.Append("allOf"), direction, nil, ref, "the schema composes %s", ref)
+		}
+	}
+	for _, name := range sortedUnion(previousFlat.properties, currentFlat.properties) {
+		old, existed := previousFlat.properties[name]
+		property, exists := currentFlat.properties[name]
+		required := slices.Contains(currentFlat.required, name)
+		switch {
+		case !exists:
+			d.report(PropertyRemoved, old.ptr, direction, old.value, nil, "the property %s was removed", name)
+		case !existed && required:
+			d.report(RequiredPropertyAdded, property.ptr, direction, nil, property.value, "the new property %s is required", name)
+		case !existed:
+			d.report(PropertyAdded, property.ptr, direction, nil, property.value, "the optional property %s was added", name)
+		default:
+			switch wasRequired := slices.Contains(previousFlat.required, name); {
+			case required && !wasRequired:
+				d.report(PropertyRequired, property.ptr, direction, false, true, "the property %s is required", name)
+			case !required && wasRequired:
+				d.report(PropertyOptional, property.ptr, direction, true, false, "the property %s isn't required anymore", name)
+			}
+			d.diffSchema(property.ptr, direction, &old.value, &property.value)
+		}
+	}
+
+	d.diffSchemaAt(ptr.Append("items"), direction, items(previous), items(current))
+	d.diffSchemaAt(ptr.Append("additionalProperties"), direction, additionalProperties(previous), additionalProperties(current))
+}
+
+// diffDeprecated compares the x-deprecated extensions of an element.
+func (d *differ) diffDeprecated(ptr JSONPointer, direction Direction, previous, current spec.Extensions) {
+	previousDeprecated, _ := previous.GetBool("x-deprecated")
+	currentDeprecated, _ := current.GetBool("x-deprecated")
+	switch {
+	case currentDeprecated && !previousDeprecated:
+		d.report(SchemaDeprecated, ptr.Append("x-deprecated"), direction, false, true, "the value is deprecated")
+	case previousDeprecated && !currentDeprecated:
+		d.report(SchemaDeprecated, ptr.Append("x-deprecated"), direction, true, false, "the value isn't deprecated anymore")
+	}
+}
+
+func (d *differ) diffEnum(ptr JSONPointer, direction Direction, previous, current []any) {
+	switch {
+	case len(previous) == 0 && len(current) == 0:
+		return
+	case len(previous) == 0:
+		d.report(EnumAdded, ptr, direction, nil, current, "the values are restricted to an enum")
+		return
+	case len(current) == 0:
+		d.report(EnumRemoved, ptr, direction, previous, nil, "the values aren't restricted to an enum anymore")
+		return
+	}
+	previousKeys, currentKeys := jsonKeys(previous), jsonKeys(current)
+	for i, key := range previousKeys {
+		if !slices.Contains(currentKeys, key) {
+			d.report(EnumValueRemoved, ptr, direction, previous[i], nil, "the enum value %s was removed", key)
+		}
+	}
+	for i, key := range currentKeys {
+		if !slices.Contains(previousKeys, key) {
+			d.report(EnumValueAdded, ptr, direction, nil, current[i], "the enum value %s was added", key)
+		}
+	}
+}
+
+// diffConstraints compares the validations of two schemas, reporting those which accept fewer values, or
+// more. A pattern or a multipleOf which changed is considered to accept fewer values.
+func (d *differ) diffConstraints(ptr JSONPointer, direction Direction, previous, current *spec.Schema) {
+	constraint := func(keyword string, comparison int, before, after any) {
+		switch {
+		case comparison < 0:
+			d.report(ConstraintTightened, ptr.Append(keyword), direction, before, after,
+				"the %s is %s instead of %s", keyword, valueOrNone(after), valueOrNone(before))
+		case comparison > 0:
+			d.report(ConstraintLoosened, ptr.Append(keyword), direction, before, after,
+				"the %s is %s instead of %s", keyword, valueOrNone(after), valueOrNone(before))
+		}
+	}
+
+	constraint("maximum", compareBound(previous.Maximum, current.Maximum, false), deref(previous.Maximum), deref(current.Maximum))
+	if compareBound(previous.Maximum, current.Maximum, false) == 0 && current.Maximum != nil {
+		constraint("exclusiveMaximum", compareFlag(previous.ExclusiveMaximum, current.ExclusiveMaximum), previous.ExclusiveMaximum, current.ExclusiveMaximum)
+	}
+	constraint("minimum", compareBound(previous.Minimum, current.Minimum, true), deref(previous.Minimum), deref(current.Minimum))
+	if compareBound(previous.Minimum, current.Minimum, true) == 0 && current.Minimum != nil {
+		constraint("exclusiveMinimum", compareFlag(previous.ExclusiveMinimum, current.ExclusiveMinimum), previous.ExclusiveMinimum, current.ExclusiveMinimum)
+	}
+	constraint("maxLength", compareBound(previous.MaxLength, current.MaxLength, false), deref(previous.MaxLength), deref(current.MaxLength))
+	constraint("minLength", compareBound(previous.MinLength, current.MinLength, true), deref(previous.MinLength), deref(current.MinLength))
+	constraint("maxItems", compareBound(previous.MaxItems, current.MaxItems, false), deref(previous.MaxItems), deref(current.MaxItems))
+	constraint("minItems", compareBound(previous.MinItems, current.MinItems, true), deref(previous.MinItems), deref(current.MinItems))
+	constraint("maxProperties", compareBound(previous.MaxProperties, current.MaxProperties, false), deref(previous.MaxProperties), deref(current.MaxProperties))
+	constraint("minProperties", compareBound(previous.MinProperties, current.MinProperties, true), deref(previous.MinProperties), deref(current.MinProperties))
+	constraint("uniqueItems", compareFlag(previous.UniqueItems, current.UniqueItems), previous.UniqueItems, current.UniqueItems)
+
+	switch {
+	case previous.Pattern == current.Pattern:
+	case current.Pattern == "":
+		constraint("pattern", 1, previous.Pattern, nil)
+	case previous.Pattern == "":
+		constraint("pattern", -1, nil, current.Pattern)
+	default:
+		constraint("pattern", -1, previous.Pattern, current.Pattern)
+	}
+	switch {
+	case compareBound(previous.MultipleOf, current.MultipleOf, false) == 0:
+	case current.MultipleOf == nil:
+		constraint("multipleOf", 1, deref(previous.MultipleOf), nil)
+	default:
+		constraint("multipleOf", -1, deref(previous.MultipleOf), deref(current.MultipleOf))
+	}
+}
+
+// compareBound compares two bounds of the values, nil when there is none: it is negative when the current
+// bound accepts fewer values, and positive when it accepts more.
+func compareBound[T int64 | float64](previous, current *T, lower bool) int {
+	switch {
+	case previous == nil && current == nil:
+		return 0
+	case previous == nil:
+		return -1
+	case current == nil:
+		return 1
+	case *previous == *current:
+		return 0
+	case (*current < *previous) != lower:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// compareFlag compares two restrictions, e.g. uniqueItems, like compareBound.
+func compareFlag(previous, current bool) int {
+	switch {
+	case previous == current:
+		return 0
+	case current:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// located is an element of a spec, with its pointer.
+type located[T any] struct {
+	value T
+	ptr   JSONPointer
+}
+
+// flatSchema is a schema with the properties of its allOf members which aren't refs.
+type flatSchema struct {
+	refs       []string
+	properties map[string]located[spec.Schema]
+	required   []string
+}
+
+func flatten(ptr JSONPointer, schema *spec.Schema) flatSchema {
+	flat := flatSchema{properties: make(map[string]located[spec.Schema], len(schema.Properties))}
+	for name, property := range schema.Properties {
+		flat.properties[name] = located[spec.Schema]{value: property, ptr: ptr.Append("properties", name)}
+	}
+	flat.required = append(flat.required, schema.Required...)
+	for i, member := range schema.AllOf {
+		if ref := member.Ref.String(); ref != "" {
+			flat.refs = append(flat.refs, ref)
+			continue
+		}
+		inner := flatten(ptr.Append("allOf", strconv.Itoa(i)), &member)
+		flat.refs = append(flat.refs, inner.refs...)
+		maps.Copy(flat.properties, inner.properties)
+		flat.required = append(flat.required, inner.required...)
+	}
+	return flat
+}
+
+// simpleSchema is the schema of a parameter, a header or items which are not in a body.
+func simpleSchema(simple spec.SimpleSchema, validations spec.CommonValidations, extensions spec.Extensions) *spec.Schema {
+	schema := &spec.Schema{
+		VendorExtensible: spec.VendorExtensible{Extensions: extensions},
+		SchemaProps: spec.SchemaProps{
+			Format:           simple.Format,
+			Default:          simple.Default,
+			Maximum:          validations.Maximum,
+			ExclusiveMaximum: validations.ExclusiveMaximum,
+			Minimum:          validations.Minimum,
+			ExclusiveMinimum: validations.ExclusiveMinimum,
+			MaxLength:        validations.MaxLength,
+			MinLength:        validations.MinLength,
+			Pattern:          validations.Pattern,
+			MaxItems:         validations.MaxItems,
+			MinItems:         validations.MinItems,
+			UniqueItems:      validations.UniqueItems,
+			MultipleOf:       validations.MultipleOf,
+			Enum:             validations.Enum,
+		},
+	}
+	if simple.Type != "" {
+		schema.Type = spec.StringOrArray{simple.Type}
+	}
+	if simple.Items != nil {
+		schema.Items = &spec.SchemaOrArray{Schema: simpleSchema(simple.Items.SimpleSchema, simple.Items.CommonValidations, simple.Items.Extensions)}
+	}
+	return schema
+}
+
+func items(schema *spec.Schema) *spec.Schema {
+	if schema.Items == nil {
+		return nil
+	}
+	return schema.Items.Schema
+}
+
+func additionalProperties(schema *spec.Schema) *spec.Schema {
+	if schema.AdditionalProperties == nil {
+		return nil
+	}
+	return schema.AdditionalProperties.Schema
+}
+
+func nullable(schema *spec.Schema) bool {
+	value, _ := schema.Extensions.GetBool("x-nullable")
+	return value
+}
+
+func collectionFormat(format string) string {
+	if format == "" {
+		return "csv"
+	}
+	return format
+}
+
+func paths(doc *spec.Swagger) map[string]spec.PathItem {
+	if doc.Paths == nil {
+		return nil
+	}
+	return doc.Paths.Paths
+}
+
+func operation(item *spec.PathItem, method string) *spec.Operation {
+	switch method {
+	case "get":
+		return item.Get
+	case "put":
+		return item.Put
+	case "post":
+		return item.Post
+	case "delete":
+		return item.Delete
+	case "options":
+		return item.Options
+	case "head":
+		return item.Head
+	default:
+		return item.Patch
+	}
+}
+
+// operationParameters are the parameters of an operation by location and name, e.g. query.limit, with those
+// of its path which it doesn't override. The parameters referring to #/parameters are resolved.
+func operationParameters(doc *spec.Swagger, pth, method string, item *spec.PathItem, op *spec.Operation) map[string]located[spec.Parameter] {
+	params := make(map[string]located[spec.Parameter], len(item.Parameters)+len(op.Parameters))
+	for _, declared := range []struct {
+		params []spec.Parameter
+		ptr    JSONPointer
+	}{
+		{item.Parameters, Pointer("paths", pth, "parameters")},
+		{op.Parameters, Pointer("paths", pth, method, "parameters")},
+	} {
+		for i, param := range declared.params {
+			if name, ok := strings.CutPrefix(param.Ref.String(), parametersPrefix); ok {
+				if shared, found := doc.Parameters[unescapeToken(name)]; found {
+					param = shared
+				}
+			}
+			params[param.In+"."+param.Name] = located[spec.Parameter]{value: param, ptr: declared.ptr.Append(strconv.Itoa(i))}
+		}
+	}
+	return params
+}
+
+// injected tells if a response is injected into the operations by the scan, e.g. the default_response of
+// codescan, rather than documented by them.
+func injected(resp spec.Response) bool {
+	value, _ := resp.Extensions.GetBool(injectedDefaultExtension)
+	return value
+}
+
+// operationResponses are the responses of an operation by status code, or "default". The responses referring
+// to #/responses are resolved.
+func operationResponses(doc *spec.Swagger, ptr JSONPointer, op *spec.Operation) map[string]located[spec.Response] {
+	if op.Responses == nil {
+		return nil
+	}
+	responses := make(map[string]located[spec.Response], len(op.Responses.StatusCodeResponses)+1)
+	add := func(code string, resp spec.Response) {
+		if name, ok := strings.CutPrefix(resp.Ref.String(), responsesPrefix); ok {
+			if shared, found := doc.Responses[unescapeToken(name)]; found {
+				resp = shared
+			}
+		}
+		responses[code] = located[spec.Response]{value: resp, ptr: ptr.Append("responses", code)}
+	}
+	if op.Responses.Default != nil {
+		add("default", *op.Responses.Default)
+	}
+	for code, resp := range op.Responses.StatusCodeResponses {
+		add(strconv.Itoa(code), resp)
+	}
+	return responses
+}
+
+// security are the security requirements of an operation, or of the spec when the operation declares none.
+func security(doc *spec.Swagger, op *spec.Operation) []map[string][]string {
+	if op.Security != nil {
+		return op.Security
+	}
+	return doc.Security
+}
+
+// securityKeys identify security requirements, e.g. "api_key & oauth2:read,write".
+func securityKeys(requirements []map[string][]string) []string {
+	keys := make([]string, 0, len(requirements))
+	for _, requirement := range requirements {
+		schemes := make([]string, 0, len(requirement))
+		for _, name := range slices.Sorted(maps.Keys(requirement)) {
+			if scopes := requirement[name]; len(scopes) > 0 {
+				name += ":" + strings.Join(slices.Sorted(slices.Values(scopes)), ",")
+			}
+			schemes = append(schemes, name)
+		}
+		keys = append(keys, strings.Join(schemes, " & "))
+	}
+	return keys
+}
+
+// definitionDirections tells whether the definitions are used by the requests of the operations of a spec,
+// through their body parameters, or by their responses, directly or through other definitions.
+func definitionDirections(doc *spec.Swagger) map[string]Direction {
+	directions := make(map[string]Direction, len(doc.Definitions))
+	var visit func(schema *spec.Schema, direction Direction)
+	visit = func(schema *spec.Schema, direction Direction) {
+		if schema == nil {
+			return
+		}
+		if name, ok := strings.CutPrefix(schema.Ref.String(), definitionsPrefix); ok {
+			name = unescapeToken(name)
+			if directions[name]&direction != 0 {
+				return
+			}
+			directions[name] |= direction
+			if definition, found := doc.Definitions[name]; found {
+				visit(&definition, direction)
+			}
+			return
+		}
+		for _, property := range schema.Properties {
+			visit(&property, direction)
+		}
+		for _, members := range [][]spec.Schema{schema.AllOf, schema.AnyOf, schema.OneOf} {
+			for _, member := range members {
+				visit(&member, direction)
+			}
+		}
+		if schema.Items != nil {
+			visit(schema.Items.Schema, direction)
+			for _, item := range schema.Items.Schemas {
+				visit(&item, direction)
+			}
+		}
+		if schema.AdditionalProperties != nil {
+			visit(schema.AdditionalProperties.Schema, direction)
+		}
+	}
+
+	for pth, item := range paths(doc) {
+		for _, method := range methods {
+			op := operation(&item, method)
+			if op == nil {
+				continue
+			}
+			for _, param := range operationParameters(doc, pth, method, &item, op) {
+				visit(param.value.Schema, Request)
+			}
+			for _, resp := range operationResponses(doc, "", op) {
+				visit(resp.value.Schema, Response)
+			}
+		}
+	}
+	return directions
+}
+
+func sortedUnion[V any](previous, current map[string]V) []string {
+	keys := slices.Collect(maps.Keys(previous))
+	keys = slices.AppendSeq(keys, maps.Keys(current))
+	slices.Sort(keys)
+	return slices.Compact(keys)
+}
+
+func orDefault(values, defaults []string) []string {
+	if len(values) > 0 {
+		return values
+	}
+	return defaults
+}
+
+// jsonKey compares the values of a spec with those decoded from another one.
+func jsonKey(value any) string {
+	if value == nil {
+		return "none"
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+func jsonKeys(values []any) []string {
+	keys := make([]string, 0, len(values))
+	for _, value := range values {
+		keys = append(keys, jsonKey(value))
+	}
+	return keys
+}
+
+func deref[T any](value *T) any {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
+
+func valueOrNone(value any) string {
+	if value == nil {
+		return "none"
+	}
+	return fmt.Sprint(value)
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "none"
+	}
+	return value
+}
+
+func unescapeToken(token string) string {
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+}
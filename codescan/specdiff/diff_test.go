@@ -0,0 +1,333 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package specdiff
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const petstore = `{
+  "swagger": "2.0",
+  "basePath": "/v1",
+  "consumes": ["application/json"],
+  "produces": ["application/json"],
+  "securityDefinitions": {
+    "api_key": {"type": "apiKey", "in": "header", "name": "X-API-Key"},
+    "oauth2": {"type": "oauth2", "flow": "implicit", "authorizationUrl": "https://example.com/auth", "scopes": {"read": "", "write": ""}}
+  },
+  "paths": {
+    "/pets": {
+      "get": {
+        "operationId": "listPets",
+        "summary": "Lists the pets.",
+        "parameters": [
+          {"name": "limit", "in": "query", "type": "integer", "format": "int32", "maximum": 100},
+          {"name": "status", "in": "query", "type": "array", "items": {"type": "string", "enum": ["available", "sold"]}},
+          {"$ref": "#/parameters/page"}
+        ],
+        "responses": {
+          "200": {
+            "description": "the pets",
+            "headers": {"X-Total": {"type": "integer"}},
+            "schema": {"type": "array", "items": {"$ref": "#/definitions/Pet"}}
+          },
+          "default": {"$ref": "#/responses/error"}
+        }
+      },
+      "post": {
+        "operationId": "addPet",
+        "security": [{"api_key": []}],
+        "parameters": [{"name": "pet", "in": "body", "required": true, "schema": {"$ref": "#/definitions/NewPet"}}],
+        "responses": {
+          "201": {"description": "the pet", "schema": {"$ref": "#/definitions/Pet"}}
+        }
+      }
+    },
+    "/pets/{id}": {
+      "parameters": [{"name": "id", "in": "path", "type": "integer", "format": "int64", "required": true}],
+      "delete": {
+        "operationId": "deletePet",
+        "responses": {"204": {"description": "deleted"}}
+      }
+    }
+  },
+  "parameters": {
+    "page": {"name": "page", "in": "query", "type": "integer"}
+  },
+  "responses": {
+    "error": {"description": "an error", "schema": {"$ref": "#/definitions/Error"}}
+  },
+  "definitions": {
+    "NewPet": {
+      "type": "object",
+      "required": ["name"],
+      "properties": {
+        "name": {"type": "string", "maxLength": 50},
+        "tag": {"type": "string", "pattern": "^[a-z]+$", "x-nullable": true},
+        "kind": {"type": "string", "enum": ["cat", "dog"]}
+      }
+    },
+    "Pet": {
+      "allOf": [
+        {"$ref": "#/definitions/NewPet"},
+        {"type": "object", "required": ["id"], "properties": {"id": {"type": "integer", "format": "int64"}}}
+      ]
+    },
+    "Error": {
+      "type": "object",
+      "required": ["code"],
+      "properties": {
+        "code": {"type": "integer", "minimum": 400},
+        "message": {"type": "string"}
+      }
+    }
+  }
+}`
+
+func loadPetstore(t *testing.T, edit func(doc *spec.Swagger)) *spec.Swagger {
+	t.Helper()
+	doc := new(spec.Swagger)
+	require.NoError(t, json.Unmarshal([]byte(petstore), doc))
+	if edit != nil {
+		edit(doc)
+	}
+	return doc
+}
+
+func editDefinition(doc *spec.Swagger, name string, edit func(schema *spec.Schema)) {
+	schema := doc.Definitions[name]
+	edit(&schema)
+	doc.Definitions[name] = schema
+}
+
+func editProperty(doc *spec.Swagger, definition, name string, edit func(schema *spec.Schema)) {
+	editDefinition(doc, definition, func(schema *spec.Schema) {
+		property := schema.Properties[name]
+		edit(&property)
+		schema.Properties[name] = property
+	})
+}
+
+func listPets(doc *spec.Swagger) *spec.Operation {
+	return doc.Paths.Paths["/pets"].Get
+}
+
+func pets200(doc *spec.Swagger, edit func(resp *spec.Response)) {
+	resp := listPets(doc).Responses.StatusCodeResponses[200]
+	edit(&resp)
+	listPets(doc).Responses.StatusCodeResponses[200] = resp
+}
+
+func injectedResponse(description string) spec.Response {
+	resp := spec.NewResponse().WithDescription(description).WithSchema(spec.RefSchema("#/definitions/Error"))
+	resp.AddExtension(injectedDefaultExtension, true)
+	return *resp
+}
+
+func TestDiff(t *testing.T) {
+	t.Run("should report no change of a spec", func(t *testing.T) {
+		assert.Empty(t, Diff(loadPetstore(t, nil), loadPetstore(t, nil), nil))
+	})
+
+	for _, tc := range []struct {
+		kind     Kind
+		edit     func(doc *spec.Swagger)
+		path     JSONPointer // of the only change of the kind
+		breaking bool
+	}{
+		{BasePathChanged, func(doc *spec.Swagger) { doc.BasePath = "/v2" }, "/basePath", true},
+		{PathAdded, func(doc *spec.Swagger) { doc.Paths.Paths["/owners"] = spec.PathItem{} }, "/paths/~1owners", false},
+		{PathRemoved, func(doc *spec.Swagger) { delete(doc.Paths.Paths, "/pets/{id}") }, "/paths/~1pets~1{id}", true},
+		{OperationAdded, func(doc *spec.Swagger) {
+			item := doc.Paths.Paths["/pets/{id}"]
+			item.Get = spec.NewOperation("getPet")
+			doc.Paths.Paths["/pets/{id}"] = item
+		}, "/paths/~1pets~1{id}/get", false},
+		{OperationRemoved, func(doc *spec.Swagger) {
+			item := doc.Paths.Paths["/pets"]
+			item.Post = nil
+			doc.Paths.Paths["/pets"] = item
+		}, "/paths/~1pets/post", true},
+		{OperationIDChanged, func(doc *spec.Swagger) { listPets(doc).ID = "findPets" }, "/paths/~1pets/get/operationId", true},
+		{OperationDeprecated, func(doc *spec.Swagger) { listPets(doc).Deprecated = true }, "/paths/~1pets/get/deprecated", false},
+		{DescriptionChanged, func(doc *spec.Swagger) { listPets(doc).Summary = "Finds the pets." }, "/paths/~1pets/get/summary", false},
+		{ConsumesAdded, func(doc *spec.Swagger) {
+			doc.Paths.Paths["/pets"].Post.Consumes = []string{"application/json", "application/xml"}
+		}, "/paths/~1pets/post/consumes", false},
+		{ConsumesRemoved, func(doc *spec.Swagger) {
+			doc.Paths.Paths["/pets"].Post.Consumes = []string{"application/xml"}
+		}, "/paths/~1pets/post/consumes", true},
+		{ProducesAdded, func(doc *spec.Swagger) {
+			listPets(doc).Produces = []string{"application/json", "application/xml"}
+		}, "/paths/~1pets/get/produces", false},
+		{ProducesRemoved, func(doc *spec.Swagger) { listPets(doc).Produces = []string{"text/plain"} }, "/paths/~1pets/get/produces", true},
+		{SecurityAdded, func(doc *spec.Swagger) {
+			listPets(doc).Security = []map[string][]string{{"oauth2": {"read"}}}
+		}, "/paths/~1pets/get/security", true},
+		{SecurityRemoved, func(doc *spec.Swagger) {
+			doc.Paths.Paths["/pets"].Post.Security = []map[string][]string{}
+		}, "/paths/~1pets/post/security", false},
+		{SecurityRequirementAdded, func(doc *spec.Swagger) {
+			post := doc.Paths.Paths["/pets"].Post
+			post.Security = append(post.Security, map[string][]string{"oauth2": {"write"}})
+		}, "/paths/~1pets/post/security", false},
+		{SecurityRequirementRemoved, func(doc *spec.Swagger) {
+			doc.Paths.Paths["/pets"].Post.Security = []map[string][]string{{"oauth2": {"write"}}}
+		}, "/paths/~1pets/post/security", true},
+		{ParameterAdded, func(doc *spec.Swagger) {
+			listPets(doc).AddParam(spec.QueryParam("sort").Typed("string", ""))
+		}, "/paths/~1pets/get/parameters/3", false},
+		{RequiredParameterAdded, func(doc *spec.Swagger) {
+			listPets(doc).AddParam(spec.HeaderParam("X-Tenant").Typed("string", "").AsRequired())
+		}, "/paths/~1pets/get/parameters/3", true},
+		{ParameterRemoved, func(doc *spec.Swagger) {
+			listPets(doc).Parameters = listPets(doc).Parameters[1:]
+		}, "/paths/~1pets/get/parameters/0", true},
+		{ParameterRequired, func(doc *spec.Swagger) {
+			doc.Parameters["page"] = *spec.QueryParam("page").Typed("integer", "").AsRequired()
+		}, "/paths/~1pets/get/parameters/2/required", true},
+		{ParameterOptional, func(doc *spec.Swagger) {
+			doc.Paths.Paths["/pets"].Post.Parameters[0].Required = false
+		}, "/paths/~1pets/post/parameters/0/required", false},
+		{CollectionFormatChanged, func(doc *spec.Swagger) {
+			listPets(doc).Parameters[1].CollectionFormat = "multi"
+		}, "/paths/~1pets/get/parameters/1/collectionFormat", true},
+		{ResponseAdded, func(doc *spec.Swagger) {
+			listPets(doc).Responses.StatusCodeResponses[400] = *spec.NewResponse().WithDescription("bad request")
+		}, "/paths/~1pets/get/responses/400", false},
+		{ResponseRemoved, func(doc *spec.Swagger) { listPets(doc).Responses.Default = nil }, "/paths/~1pets/get/responses/default", true},
+		{ResponseHeaderAdded, func(doc *spec.Swagger) {
+			pets200(doc, func(resp *spec.Response) { resp.AddHeader("X-Next", spec.ResponseHeader().Typed("string", "")) })
+		}, "/paths/~1pets/get/responses/200/headers/X-Next", false},
+		{ResponseHeaderRemoved, func(doc *spec.Swagger) {
+			pets200(doc, func(resp *spec.Response) { resp.Headers = nil })
+		}, "/paths/~1pets/get/responses/200/headers/X-Total", true},
+		{InjectedDefaultChanged, func(doc *spec.Swagger) {
+			doc.Paths.Paths["/pets"].Post.Responses.StatusCodeResponses[500] = injectedResponse("internal error")
+		}, "/paths/~1pets/post/responses/500", false},
+		{DefinitionAdded, func(doc *spec.Swagger) {
+			doc.Definitions["Owner"] = *new(spec.Schema).Typed("object", "")
+		}, "/definitions/Owner", false},
+		{DefinitionRemoved, func(doc *spec.Swagger) {
+			delete(doc.Definitions, "Error")
+			doc.Responses["error"] = *spec.NewResponse().WithDescription("an error")
+		}, "/definitions/Error", true},
+		{SchemaAdded, func(doc *spec.Swagger) {
+			item := doc.Paths.Paths["/pets/{id}"]
+			item.Delete.Responses.StatusCodeResponses[204] = *spec.NewResponse().WithDescription("deleted").WithSchema(spec.StringProperty())
+			doc.Paths.Paths["/pets/{id}"] = item
+		}, "/paths/~1pets~1{id}/delete/responses/204/schema", false},
+		{SchemaRemoved, func(doc *spec.Swagger) {
+			pets200(doc, func(resp *spec.Response) { resp.Schema = nil })
+		}, "/paths/~1pets/get/responses/200/schema", true},
+		{RefChanged, func(doc *spec.Swagger) {
+			doc.Paths.Paths["/pets"].Post.Responses.StatusCodeResponses[201] = *spec.NewResponse().WithSchema(spec.RefSchema("#/definitions/NewPet"))
+		}, "/paths/~1pets/post/responses/201/schema/$ref", true},
+		{TypeChanged, func(doc *spec.Swagger) {
+			editProperty(doc, "Error", "code", func(schema *spec.Schema) { schema.Typed("string", "") })
+		}, "/definitions/Error/properties/code/type", true},
+		{FormatChanged, func(doc *spec.Swagger) { listPets(doc).Parameters[0].Format = "int64" }, "/paths/~1pets/get/parameters/0/format", true},
+		{EnumAdded, func(doc *spec.Swagger) {
+			editProperty(doc, "Error", "message", func(schema *spec.Schema) { schema.WithEnum("not found", "conflict") })
+		}, "/definitions/Error/properties/message/enum", false},
+		{EnumRemoved, func(doc *spec.Swagger) {
+			editProperty(doc, "NewPet", "kind", func(schema *spec.Schema) { schema.Enum = nil })
+		}, "/definitions/NewPet/properties/kind/enum", true},
+		{EnumValueAdded, func(doc *spec.Swagger) {
+			editProperty(doc, "NewPet", "kind", func(schema *spec.Schema) { schema.Enum = append(schema.Enum, "bird") })
+		}, "/definitions/NewPet/properties/kind/enum", false},
+		{EnumValueRemoved, func(doc *spec.Swagger) {
+			listPets(doc).Parameters[1].Items.Enum = []any{"available"}
+		}, "/paths/~1pets/get/parameters/1/items/enum", true},
+		{PropertyAdded, func(doc *spec.Swagger) {
+			editDefinition(doc, "Error", func(schema *spec.Schema) { schema.SetProperty("details", *spec.StringProperty()) })
+		}, "/definitions/Error/properties/details", false},
+		{RequiredPropertyAdded, func(doc *spec.Swagger) {
+			editDefinition(doc, "NewPet", func(schema *spec.Schema) {
+				schema.SetProperty("owner", *spec.StringProperty())
+				schema.AddRequired("owner")
+			})
+		}, "/definitions/NewPet/properties/owner", true},
+		{PropertyRemoved, func(doc *spec.Swagger) {
+			editDefinition(doc, "Error", func(schema *spec.Schema) { delete(schema.Properties, "message") })
+		}, "/definitions/Error/properties/message", true},
+		{PropertyRequired, func(doc *spec.Swagger) {
+			editDefinition(doc, "Error", func(schema *spec.Schema) { schema.AddRequired("message") })
+		}, "/definitions/Error/properties/message", false},
+		{PropertyOptional, func(doc *spec.Swagger) {
+			editDefinition(doc, "Pet", func(schema *spec.Schema) { schema.AllOf[1].Required = nil })
+		}, "/definitions/Pet/allOf/1/properties/id", true},
+		{AllOfAdded, func(doc *spec.Swagger) {
+			editDefinition(doc, "Error", func(schema *spec.Schema) { schema.AllOf = []spec.Schema{*spec.RefSchema("#/definitions/NewPet")} })
+		}, "/definitions/Error/allOf", false},
+		{AllOfRemoved, func(doc *spec.Swagger) {
+			editDefinition(doc, "Pet", func(schema *spec.Schema) { schema.AllOf = schema.AllOf[1:] })
+		}, "/definitions/Pet/allOf", true},
+		{NullableAdded, func(doc *spec.Swagger) {
+			editProperty(doc, "Error", "message", func(schema *spec.Schema) { schema.AddExtension("x-nullable", true) })
+		}, "/definitions/Error/properties/message/x-nullable", true},
+		{NullableRemoved, func(doc *spec.Swagger) {
+			editProperty(doc, "NewPet", "tag", func(schema *spec.Schema) { delete(schema.Extensions, "x-nullable") })
+		}, "/definitions/NewPet/properties/tag/x-nullable", true},
+		{SchemaDeprecated, func(doc *spec.Swagger) {
+			editProperty(doc, "Error", "message", func(schema *spec.Schema) { schema.AddExtension("x-deprecated", true) })
+		}, "/definitions/Error/properties/message/x-deprecated", false},
+		{ConstraintTightened, func(doc *spec.Swagger) {
+			editProperty(doc, "NewPet", "name", func(schema *spec.Schema) { schema.WithMaxLength(20) })
+		}, "/definitions/NewPet/properties/name/maxLength", true},
+		{ConstraintLoosened, func(doc *spec.Swagger) {
+			editProperty(doc, "Error", "code", func(schema *spec.Schema) { schema.Minimum = nil })
+		}, "/definitions/Error/properties/code/minimum", true},
+		{DefaultChanged, func(doc *spec.Swagger) { listPets(doc).Parameters[0].Default = 20 }, "/paths/~1pets/get/parameters/0/default", false},
+	} {
+		t.Run(string(tc.kind), func(t *testing.T) {
+			var found []Change
+			for _, change := range Diff(loadPetstore(t, nil), loadPetstore(t, tc.edit), nil) {
+				if change.Kind == tc.kind {
+					found = append(found, change)
+				}
+			}
+			require.Len(t, found, 1)
+			assert.Equal(t, tc.path, found[0].Path)
+			assert.Equal(t, tc.breaking, found[0].Breaking, found[0].Description)
+			assert.NotEmpty(t, found[0].Description)
+		})
+	}
+
+	t.Run("should report the injected responses as not breaking", func(t *testing.T) {
+		withInjected := func(description string) func(doc *spec.Swagger) {
+			return func(doc *spec.Swagger) {
+				doc.Paths.Paths["/pets"].Post.Responses.StatusCodeResponses[500] = injectedResponse(description)
+			}
+		}
+		removed := Diff(loadPetstore(t, withInjected("internal error")), loadPetstore(t, nil), nil)
+		require.Len(t, removed, 1)
+		assert.Equal(t, InjectedDefaultChanged, removed[0].Kind)
+		assert.False(t, removed[0].Breaking)
+		assert.Equal(t, "the injected response 500 was removed", removed[0].Description)
+
+		changed := Diff(loadPetstore(t, withInjected("internal error")), loadPetstore(t, withInjected("panic")), nil)
+		require.Len(t, changed, 1)
+		assert.Equal(t, InjectedDefaultChanged, changed[0].Kind)
+		assert.False(t, changed[0].Breaking)
+		assert.Empty(t, Diff(loadPetstore(t, withInjected("panic")), loadPetstore(t, withInjected("panic")), nil))
+	})
+
+	t.Run("should compare a documented response with the injected one", func(t *testing.T) {
+		before := loadPetstore(t, func(doc *spec.Swagger) {
+			doc.Paths.Paths["/pets"].Post.Responses.StatusCodeResponses[500] = injectedResponse("internal error")
+		})
+		after := loadPetstore(t, func(doc *spec.Swagger) {
+			doc.Paths.Paths["/pets"].Post.Responses.StatusCodeResponses[500] = *spec.NewResponse().WithDescription("internal error")
+		})
+		breaking := Breaking(Diff(before, after, nil))
+		require.Len(t, breaking, 1)
+		assert.Equal(t, SchemaRemoved, breaking[0].Kind)
+	})
+}
@@ -0,0 +1,318 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package specdiff compares two swagger 2.0 specs into typed changes, classified as breaking or not by a
+policy.
+
+Diff reports a Change per difference of the surface of the API, with its Kind, the JSON pointer of the
+element in the specs, its values before and after, and whether it is seen by the requests or the
+responses of the operations. A Policy classifies the changes by kind, so that the rules are easily
+replaced, e.g. to accept the removal of enum values of responses:
+
+	policy := specdiff.DefaultPolicy()
+	policy[specdiff.EnumValueRemoved] = specdiff.InRequests
+	changes := specdiff.Diff(before, after, policy)
+*/
+package specdiff
+
+import (
+	"encoding"
+	"fmt"
+	"strings"
+)
+
+// Kind is the kind of a change, e.g. "property-removed".
+type Kind string
+
+// The kinds of changes reported by Diff.
+const (
+	BasePathChanged Kind = "base-path-changed"
+
+	PathAdded          Kind = "path-added"
+	PathRemoved        Kind = "path-removed"
+	OperationAdded     Kind = "operation-added"
+	OperationRemoved   Kind = "operation-removed"
+	OperationIDChanged Kind = "operation-id-changed"
+	// OperationDeprecated is an operation which is deprecated, or not anymore.
+	OperationDeprecated Kind = "operation-deprecated"
+	// DescriptionChanged is a summary or a description which changed, of an operation or a schema.
+	DescriptionChanged Kind = "description-changed"
+	ConsumesAdded      Kind = "consumes-added"
+	ConsumesRemoved    Kind = "consumes-removed"
+	ProducesAdded      Kind = "produces-added"
+	ProducesRemoved    Kind = "produces-removed"
+	// SecurityAdded is an operation which requires a security scheme, and required none.
+	SecurityAdded Kind = "security-added"
+	// SecurityRemoved is an operation which requires no security scheme anymore.
+	SecurityRemoved Kind = "security-removed"
+	// SecurityRequirementAdded is a new alternative security requirement of an operation.
+	SecurityRequirementAdded   Kind = "security-requirement-added"
+	SecurityRequirementRemoved Kind = "security-requirement-removed"
+
+	// ParameterAdded is a new optional parameter.
+	ParameterAdded          Kind = "parameter-added"
+	RequiredParameterAdded  Kind = "required-parameter-added"
+	ParameterRemoved        Kind = "parameter-removed"
+	ParameterRequired       Kind = "parameter-required"
+	ParameterOptional       Kind = "parameter-optional"
+	CollectionFormatChanged Kind = "collection-format-changed"
+
+	ResponseAdded         Kind = "response-added"
+	ResponseRemoved       Kind = "response-removed"
+	ResponseHeaderAdded   Kind = "response-header-added"
+	ResponseHeaderRemoved Kind = "response-header-removed"
+	// InjectedDefaultChanged is a response injected into the operations by the scan, e.g. by the
+	// default_response of codescan, marked x-injected-default, which was added, removed or changed.
+	InjectedDefaultChanged Kind = "injected-default-changed"
+
+	DefinitionAdded   Kind = "definition-added"
+	DefinitionRemoved Kind = "definition-removed"
+
+	// SchemaAdded is a schema where there was none, e.g. of a response.
+	SchemaAdded   Kind = "schema-added"
+	SchemaRemoved Kind = "schema-removed"
+	RefChanged    Kind = "ref-changed"
+	TypeChanged   Kind = "type-changed"
+	FormatChanged Kind = "format-changed"
+	// EnumAdded is a value restricted to an enum, which was not.
+	EnumAdded Kind = "enum-added"
+	// EnumRemoved is a value which is not restricted to an enum anymore.
+	EnumRemoved      Kind = "enum-removed"
+	EnumValueAdded   Kind = "enum-value-added"
+	EnumValueRemoved Kind = "enum-value-removed"
+	// PropertyAdded is a new optional property.
+	PropertyAdded         Kind = "property-added"
+	RequiredPropertyAdded Kind = "required-property-added"
+	PropertyRemoved       Kind = "property-removed"
+	PropertyRequired      Kind = "property-required"
+	PropertyOptional      Kind = "property-optional"
+	// AllOfAdded is a schema which composes a new schema, by allOf.
+	AllOfAdded      Kind = "all-of-added"
+	AllOfRemoved    Kind = "all-of-removed"
+	NullableAdded   Kind = "nullable-added"
+	NullableRemoved Kind = "nullable-removed"
+	// SchemaDeprecated is a definition, a property or a parameter which is deprecated by an x-deprecated
+	// extension, or not anymore.
+	SchemaDeprecated Kind = "schema-deprecated"
+	// ConstraintTightened is a validation which accepts fewer values, e.g. a lower maxLength or a new pattern.
+	ConstraintTightened Kind = "constraint-tightened"
+	// ConstraintLoosened is a validation which accepts more values, e.g. a higher maximum.
+	ConstraintLoosened Kind = "constraint-loosened"
+	DefaultChanged     Kind = "default-changed"
+)
+
+// Kinds lists the kinds of changes reported by Diff, in the order of their declaration.
+func Kinds() []Kind {
+	return []Kind{
+		BasePathChanged,
+		PathAdded, PathRemoved, OperationAdded, OperationRemoved, OperationIDChanged, OperationDeprecated,
+		DescriptionChanged, ConsumesAdded, ConsumesRemoved, ProducesAdded, ProducesRemoved,
+		SecurityAdded, SecurityRemoved, SecurityRequirementAdded, SecurityRequirementRemoved,
+		ParameterAdded, RequiredParameterAdded, ParameterRemoved, ParameterRequired, ParameterOptional,
+		CollectionFormatChanged,
+		ResponseAdded, ResponseRemoved, ResponseHeaderAdded, ResponseHeaderRemoved, InjectedDefaultChanged,
+		DefinitionAdded, DefinitionRemoved,
+		SchemaAdded, SchemaRemoved, RefChanged, TypeChanged, FormatChanged,
+		EnumAdded, EnumRemoved, EnumValueAdded, EnumValueRemoved,
+		PropertyAdded, RequiredPropertyAdded, PropertyRemoved, PropertyRequired, PropertyOptional,
+		AllOfAdded, AllOfRemoved, NullableAdded, NullableRemoved, SchemaDeprecated,
+		ConstraintTightened, ConstraintLoosened, DefaultChanged,
+	}
+}
+
+// JSONPointer is the JSON pointer of an element of a spec, e.g. /paths/~1users~1{id}/get/parameters/0.
+type JSONPointer string
+
+// Pointer is the JSON pointer of reference tokens, escaped.
+func Pointer(tokens ...string) JSONPointer {
+	return JSONPointer("").Append(tokens...)
+}
+
+// Append is the pointer of the reference tokens below a pointer, escaped.
+func (p JSONPointer) Append(tokens ...string) JSONPointer {
+	var b strings.Builder
+	b.WriteString(string(p))
+	for _, token := range tokens {
+		b.WriteByte('/')
+		b.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(token))
+	}
+	return JSONPointer(b.String())
+}
+
+// Tokens are the reference tokens of a pointer, unescaped.
+func (p JSONPointer) Tokens() []string {
+	if p == "" {
+		return nil
+	}
+	tokens := strings.Split(strings.TrimPrefix(string(p), "/"), "/")
+	for i, token := range tokens {
+		tokens[i] = unescapeToken(token)
+	}
+	return tokens
+}
+
+// Direction tells whether a changed element is sent by the clients, in the requests, or by the API, in the
+// responses. A definition used by both has both directions. The zero Direction is an element used by no
+// operation, e.g. an unused definition, which the policies consider as used by both.
+type Direction uint8
+
+// Directions of the changes.
+const (
+	Request Direction = 1 << iota
+	Response
+
+	Both = Request | Response
+)
+
+var _ encoding.TextMarshaler = Direction(0)
+
+// String is "request", "response", "both" or "none".
+func (d Direction) String() string {
+	switch d {
+	case Request:
+		return "request"
+	case Response:
+		return "response"
+	case Both:
+		return "both"
+	default:
+		return "none"
+	}
+}
+
+// MarshalText encodes a direction as its String.
+func (d Direction) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText decodes a direction from its String.
+func (d *Direction) UnmarshalText(text []byte) error {
+	for _, direction := range []Direction{0, Request, Response, Both} {
+		if direction.String() == string(text) {
+			*d = direction
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid direction %q, expected request, response, both or none", text)
+}
+
+// Change is a difference between two specs.
+type Change struct {
+	Kind Kind `json:"kind"`
+	// Breaking is set by the policy classifying the change.
+	Breaking bool `json:"breaking"`
+	// Path is the pointer of the changed element, in the spec after the change, or before it when the element
+	// was removed.
+	Path      JSONPointer `json:"path,omitempty"`
+	Direction Direction   `json:"direction"`
+	// Before and After are the values of the element, or nil when it was added, or removed.
+	Before      any    `json:"before,omitempty"`
+	After       any    `json:"after,omitempty"`
+	Description string `json:"description"`
+}
+
+// InRequests tells whether the changed element may be sent by the clients.
+func (c Change) InRequests() bool {
+	return c.Direction == 0 || c.Direction&Request != 0
+}
+
+// InResponses tells whether the changed element may be received by the clients.
+func (c Change) InResponses() bool {
+	return c.Direction == 0 || c.Direction&Response != 0
+}
+
+func (c Change) String() string {
+	if c.Path == "" {
+		return c.Description
+	}
+	return fmt.Sprintf("%s: %s", c.Path, c.Description)
+}
+
+// Rule tells whether a change breaks the clients.
+type Rule func(Change) bool
+
+// Always is breaking.
+func Always(Change) bool { return true }
+
+// Never is not breaking.
+func Never(Change) bool { return false }
+
+// InRequests is breaking for the elements which may be sent by the clients.
+func InRequests(c Change) bool { return c.InRequests() }
+
+// InResponses is breaking for the elements which may be received by the clients.
+func InResponses(c Change) bool { return c.InResponses() }
+
+// Policy classifies the changes by kind. The changes of a kind without rule are not breaking.
+type Policy map[Kind]Rule
+
+// DefaultPolicy returns a new copy of the default policy, which callers may change.
+//
+// The removals are breaking: of paths, operations, parameters, responses, headers, definitions,
+// properties, media types, security requirements and enum values, as are the changes of the types, the
+// formats, the refs and the operation IDs. A new required parameter is breaking, and so is a property
+// required by the requests, or not anymore by the responses. The validations which accept fewer values
+// break the requests, and those which accept more values, or null, break the responses. Enums may grow:
+// a new enum value is not breaking, like the other additions, the descriptions, the defaults, the
+// deprecations and the injected responses.
+func DefaultPolicy() Policy {
+	return Policy{
+		BasePathChanged:            Always,
+		PathRemoved:                Always,
+		OperationRemoved:           Always,
+		OperationIDChanged:         Always,
+		ConsumesRemoved:            Always,
+		ProducesRemoved:            Always,
+		SecurityAdded:              Always,
+		SecurityRequirementRemoved: Always,
+		RequiredParameterAdded:     Always,
+		ParameterRemoved:           Always,
+		ParameterRequired:          Always,
+		CollectionFormatChanged:    Always,
+		ResponseRemoved:            Always,
+		ResponseHeaderRemoved:      Always,
+		DefinitionRemoved:          Always,
+		SchemaRemoved:              Always,
+		RefChanged:                 Always,
+		TypeChanged:                Always,
+		FormatChanged:              Always,
+		EnumAdded:                  InRequests,
+		EnumRemoved:                InResponses,
+		EnumValueRemoved:           Always,
+		RequiredPropertyAdded:      InRequests,
+		PropertyRemoved:            Always,
+		PropertyRequired:           InRequests,
+		PropertyOptional:           InResponses,
+		AllOfRemoved:               Always,
+		NullableAdded:              InResponses,
+		NullableRemoved:            InRequests,
+		ConstraintTightened:        InRequests,
+		ConstraintLoosened:         InResponses,
+	}
+}
+
+// Breaking tells whether a change breaks the clients, by the rule of its kind.
+func (p Policy) Breaking(c Change) bool {
+	rule, found := p[c.Kind]
+	return found && rule(c)
+}
+
+// Classify sets Breaking on the changes, by the rules of their kinds, and returns them.
+func (p Policy) Classify(changes []Change) []Change {
+	for i := range changes {
+		changes[i].Breaking = p.Breaking(changes[i])
+	}
+	return changes
+}
+
+// Breaking returns the breaking changes.
+func Breaking(changes []Change) []Change {
+	var breaking []Change
+	for _, change := range changes {
+		if change.Breaking {
+			breaking = append(breaking, change)
+		}
+	}
+	return breaking
+}
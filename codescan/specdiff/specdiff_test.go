@@ -0,0 +1,94 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package specdiff
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicy(t *testing.T) {
+	t.Run("should classify the kinds by the direction of the changes", func(t *testing.T) {
+		policy := DefaultPolicy()
+		for _, tc := range []struct {
+			kind                    Kind
+			request, response, both bool
+		}{
+			{OperationRemoved, true, true, true},
+			{EnumValueAdded, false, false, false},
+			{EnumAdded, true, false, true},
+			{EnumRemoved, false, true, true},
+			{PropertyRequired, true, false, true},
+			{PropertyOptional, false, true, true},
+			{RequiredPropertyAdded, true, false, true},
+			{NullableAdded, false, true, true},
+			{NullableRemoved, true, false, true},
+			{ConstraintTightened, true, false, true},
+			{ConstraintLoosened, false, true, true},
+			{"unknown-kind", false, false, false},
+		} {
+			assert.Equal(t, tc.request, policy.Breaking(Change{Kind: tc.kind, Direction: Request}), "%s of a request", tc.kind)
+			assert.Equal(t, tc.response, policy.Breaking(Change{Kind: tc.kind, Direction: Response}), "%s of a response", tc.kind)
+			assert.Equal(t, tc.both, policy.Breaking(Change{Kind: tc.kind, Direction: Both}), "%s of both", tc.kind)
+			assert.Equal(t, tc.both, policy.Breaking(Change{Kind: tc.kind}), "%s of an unused element", tc.kind)
+		}
+	})
+
+	t.Run("should classify with a custom policy", func(t *testing.T) {
+		before, after := loadPetstore(t, nil), loadPetstore(t, nil)
+		before.Definitions["Error"].Properties["code"] = *spec.Int64Property().WithEnum(400, 404, 409, 500)
+		after.Definitions["Error"].Properties["code"] = *spec.Int64Property().WithEnum(400, 404, 500)
+		after.Definitions["NewPet"].Properties["kind"] = *spec.StringProperty().WithEnum("cat")
+
+		assert.Len(t, Breaking(Diff(before, after, nil)), 2)
+
+		policy := DefaultPolicy()
+		policy[EnumValueRemoved] = InRequests
+		breaking := Breaking(Diff(before, after, policy))
+		require.Len(t, breaking, 1)
+		assert.Equal(t, JSONPointer("/definitions/NewPet/properties/kind/enum"), breaking[0].Path)
+		assert.Equal(t, "dog", breaking[0].Before)
+		assert.True(t, DefaultPolicy().Breaking(Change{Kind: EnumValueRemoved, Direction: Response}), "the default policy is a copy")
+	})
+
+	t.Run("should tell the directions of the definitions", func(t *testing.T) {
+		directions := definitionDirections(loadPetstore(t, nil))
+		assert.Equal(t, map[string]Direction{"NewPet": Both, "Pet": Response, "Error": Response}, directions)
+	})
+
+	t.Run("should cover the kinds", func(t *testing.T) {
+		assert.Len(t, Kinds(), 51)
+		policy := DefaultPolicy()
+		for kind := range policy {
+			assert.Contains(t, Kinds(), kind)
+		}
+	})
+}
+
+func TestChange(t *testing.T) {
+	t.Run("should escape the pointers", func(t *testing.T) {
+		ptr := Pointer("paths", "/users/{id}").Append("get", "a~b")
+		assert.Equal(t, JSONPointer("/paths/~1users~1{id}/get/a~0b"), ptr)
+		assert.Equal(t, []string{"paths", "/users/{id}", "get", "a~b"}, ptr.Tokens())
+		assert.Empty(t, JSONPointer("").Tokens())
+	})
+
+	t.Run("should encode the changes as JSON", func(t *testing.T) {
+		change := Change{Kind: PropertyRemoved, Breaking: true, Path: "/definitions/Pet/properties/tag", Direction: Both, Before: map[string]any{"type": "string"}, Description: "the property tag was removed"}
+		data, err := json.Marshal(change)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"kind": "property-removed", "breaking": true, "path": "/definitions/Pet/properties/tag", "direction": "both", "before": {"type": "string"}, "description": "the property tag was removed"}`, string(data))
+
+		var decoded Change
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, change, decoded)
+		assert.Equal(t, "/definitions/Pet/properties/tag: the property tag was removed", change.String())
+
+		require.Error(t, json.Unmarshal([]byte(`{"direction": "sideways"}`), &decoded))
+	})
+}
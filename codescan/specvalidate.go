@@ -0,0 +1,780 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/token"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	openapierrors "github.com/go-openapi/errors"
+	"github.com/go-openapi/loads"
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/validate"
+)
+
+// Codes of the problems reported by ValidateSpec.
+const (
+	// DiagnosticInvalidSpec reports an element of the spec breaking a rule of swagger 2.0, e.g. an unresolved $ref.
+	DiagnosticInvalidSpec = "invalid-spec"
+	// DiagnosticUnusedDefinition reports a definition which no $ref points to.
+	DiagnosticUnusedDefinition = "unused-definition"
+	// DiagnosticMissingOperationID reports an operation without an operationId.
+	DiagnosticMissingOperationID = "missing-operation-id"
+	// DiagnosticDeprecatedDefinition reports a $ref to a deprecated definition, outside of the deprecated
+	// operations and definitions.
+	DiagnosticDeprecatedDefinition = "deprecated-definition"
+)
+
+var rxPathParam = regexp.MustCompile(`{([^{}]+)}`)
+
+// ValidateSpec checks a spec against the rules of swagger 2.0, e.g. unresolved $refs, duplicate
+// operationIds, path parameters missing from the operations or body parameters mixed with form parameters,
+// and against those of go-openapi/validate: the JSON schema of swagger 2.0, e.g. an unknown parameter
+// location or type, and its semantic checks. The problems of go-openapi/validate which the checks of
+// ValidateSpec find too, at the same elements, are reported once, by the latter, whose locations are precise.
+// Unused definitions, operations without an operationId, uses of deprecated definitions and the warnings of
+// go-openapi/validate are reported as warnings.
+//
+// The problems are located at the Go position of the closest element of the spec found in the source map,
+// see Options.SourceMap, and their messages start with the JSON pointer of the element at fault, when it's
+// known.
+func ValidateSpec(doc *spec.Swagger, sourceMap map[string]token.Position) []Diagnostic {
+	v := &specValidator{doc: doc, sourceMap: sourceMap, located: make(map[string][]string)}
+	v.validateRefs()
+	v.validateSchemas()
+	v.validateSecurity(doc.Security, "#/security")
+	v.validatePaths()
+	v.validateUsage()
+	v.validateDeprecations()
+	v.validateStandard()
+	return v.problems
+}
+
+type specValidator struct {
+	doc       *spec.Swagger
+	sourceMap map[string]token.Position
+	problems  []Diagnostic
+	// located are the locations of the problems reported, by severity.
+	located map[string][]string
+}
+
+func (v *specValidator) report(location, code, severity, format string, args ...any) {
+	message := fmt.Sprintf(format, args...)
+	if location != "" {
+		message = location + ": " + message
+		v.located[severity] = append(v.located[severity], location)
+	}
+	v.problems = append(v.problems, Diagnostic{
+		Pos:      v.position(location),
+		Code:     code,
+		Message:  message,
+		Severity: severity,
+	})
+}
+
+func (v *specValidator) invalid(location, format string, args ...any) {
+	v.report(location, DiagnosticInvalidSpec, SeverityError, format, args...)
+}
+
+// position returns the position of the closest element of the source map containing a location.
+func (v *specValidator) position(location string) token.Position {
+	pointer := strings.TrimPrefix(location, "#")
+	for pointer != "" {
+		if pos, ok := v.sourceMap[pointer]; ok {
+			return pos
+		}
+		pointer = pointer[:strings.LastIndexByte(pointer, '/')]
+	}
+	return token.Position{}
+}
+
+// validateRefs reports the local $refs which point to nothing.
+func (v *specValidator) validateRefs() {
+	walkSpecSchemas(v.doc, func(sch *spec.Schema, location string) {
+		ref := sch.Ref.String()
+		if !strings.HasPrefix(ref, "#") {
+			return
+		}
+		if name, ok := definitionName(sch.Ref); !ok {
+			v.invalid(location, "$ref %s doesn't point to a definition", ref)
+		} else if _, exists := v.doc.Definitions[name]; !exists {
+			v.invalid(location, "$ref %s points to an undefined definition", ref)
+		}
+	})
+}
+
+// validateSchemas reports the schemas of unknown types, with required properties they don't define, and the
+// arrays without items.
+func (v *specValidator) validateSchemas() {
+	walkSpecSchemas(v.doc, func(sch *spec.Schema, location string) {
+		for _, typ := range sch.Type {
+			if !slices.Contains(schemaTypes, typ) {
+				v.invalid(location+"/type", "the schema is of type %q, which is none of %s", typ, strings.Join(schemaTypes, ", "))
+			}
+		}
+		if len(sch.Properties) > 0 {
+			for _, name := range sch.Required {
+				if _, defined := sch.Properties[name]; !defined {
+					v.invalid(location, "the required property %q is not defined", name)
+				}
+			}
+		}
+		if sch.Type.Contains("array") && sch.Items == nil {
+			v.invalid(location, "the array schema has no items")
+		}
+	})
+}
+
+func (v *specValidator) validateSecurity(requirements []map[string][]string, location string) {
+	for i, requirement := range requirements {
+		for _, name := range sortedKeys(requirement) {
+			if _, defined := v.doc.SecurityDefinitions[name]; !defined {
+				v.invalid(location+"/"+strconv.Itoa(i), "the security scheme %q is not defined", name)
+			}
+		}
+	}
+}
+
+func (v *specValidator) validatePaths() {
+	if v.doc.Paths == nil {
+		return
+	}
+	operationIDs := make(map[string]string)
+	for _, pth := range sortedKeys(v.doc.Paths.Paths) {
+		pathItem := v.doc.Paths.Paths[pth]
+		location := "#/paths/" + escapePointer(pth)
+		pathParams := v.validateParams(pathItem.Parameters, location+"/parameters")
+
+		for method, op := range pathItemOperations(&pathItem) {
+			opLocation := location + "/" + method
+			switch previous, duplicate := operationIDs[op.ID]; {
+			case op.ID == "":
+				v.report(opLocation, DiagnosticMissingOperationID, SeverityWarning, "the operation has no operationId")
+			case duplicate:
+				v.invalid(opLocation, "the operationId %q is already used by %s", op.ID, previous)
+			default:
+				operationIDs[op.ID] = opLocation
+			}
+
+			params := v.validateParams(op.Parameters, opLocation+"/parameters")
+			for key, param := range pathParams {
+				if _, overridden := params[key]; !overridden {
+					params[key] = param
+				}
+			}
+			v.validateOperationParams(pth, params, opLocation)
+
+			if op.Responses == nil || (op.Responses.Default == nil && len(op.Responses.StatusCodeResponses) == 0) {
+				v.invalid(opLocation, "the operation has no responses")
+			}
+			v.validateSecurity(op.Security, opLocation+"/security")
+		}
+	}
+}
+
+// validateParams reports the parameters declared twice, in no location of swagger 2.0, and the simple
+// parameters without type or of an unknown one, and returns the parameters by name and location, with their
+// $refs resolved, with the pointers locating them.
+func (v *specValidator) validateParams(params []spec.Parameter, location string) map[paramKey]locatedParam {
+	located := make(map[paramKey]locatedParam, len(params))
+	for i, param := range params {
+		paramLocation := location + "/" + strconv.Itoa(i)
+		if ref := param.Ref.String(); ref != "" {
+			name, isLocal := strings.CutPrefix(ref, parametersPrefix)
+			if !isLocal {
+				continue
+			}
+			shared, exists := v.doc.Parameters[name]
+			if !exists {
+				v.invalid(paramLocation, "$ref %s points to an undefined parameter", ref)
+				continue
+			}
+			param = shared
+		}
+
+		key := paramKey{name: param.Name, in: param.In}
+		if previous, duplicate := located[key]; duplicate {
+			v.invalid(paramLocation, "the %s parameter %q is already declared by %s", param.In, param.Name, previous.location)
+			continue
+		}
+		located[key] = locatedParam{param: param, location: paramLocation}
+
+		switch {
+		case !slices.Contains(paramLocations, param.In):
+			v.invalid(paramLocation+"/in", "the parameter %q is in %q, which is none of %s", param.Name, param.In, strings.Join(paramLocations, ", "))
+		case param.In == "body":
+			if param.Schema == nil {
+				v.invalid(paramLocation, "the body parameter %q has no schema", param.Name)
+			}
+		case param.Type == "":
+			v.invalid(paramLocation, "the %s parameter %q has no type", param.In, param.Name)
+		case !slices.Contains(paramTypes, param.Type):
+			v.invalid(paramLocation+"/type", "the %s parameter %q is of type %q, which is none of %s", param.In, param.Name, param.Type, strings.Join(paramTypes, ", "))
+		case param.Type == "array" && param.Items == nil:
+			v.invalid(paramLocation, "the array parameter %q has no items", param.Name)
+		case param.Type == "file" && param.In != "formData":
+			v.invalid(paramLocation, "the file parameter %q is not a formData parameter", param.Name)
+		}
+	}
+	return located
+}
+
+var (
+	// paramLocations are the values of the in of the parameters of swagger 2.0, and paramTypes the types of
+	// its simple parameters.
+	paramLocations = []string{"query", "header", "path", "formData", "body"}
+	paramTypes     = []string{"string", "number", "integer", "boolean", "array", "file"}
+	// schemaTypes are the types of the schemas: those of JSON schema, and file for the responses.
+	schemaTypes = []string{"string", "number", "integer", "boolean", "array", "object", "null", "file"}
+)
+
+type locatedParam struct {
+	param    spec.Parameter
+	location string
+}
+
+// validateOperationParams checks the parameters of an operation, including those of its path: a single body
+// parameter, not mixed with form parameters, and a required path parameter for each parameter of the path.
+func (v *specValidator) validateOperationParams(pth string, params map[paramKey]locatedParam, location string) {
+	var bodies, forms []string
+	declared := make(map[string]bool)
+	keys := slices.SortedFunc(maps.Keys(params), func(a, b paramKey) int {
+		return cmp.Or(cmp.Compare(a.in, b.in), cmp.Compare(a.name, b.name))
+	})
+	for _, key := range keys {
+		param := params[key]
+		switch key.in {
+		case "body":
+			bodies = append(bodies, key.name)
+		case "formData":
+			forms = append(forms, key.name)
+		case "path":
+			declared[key.name] = true
+			if !param.param.Required {
+				v.invalid(param.location, "the path parameter %q is not required", key.name)
+			}
+			if !strings.Contains(pth, "{"+key.name+"}") {
+				v.invalid(param.location, "the path parameter %q is not a parameter of the path %s", key.name, pth)
+			}
+		}
+	}
+
+	if len(bodies) > 1 {
+		v.invalid(location, "the operation has several body parameters: %s", strings.Join(bodies, ", "))
+	}
+	if len(bodies) > 0 && len(forms) > 0 {
+		v.invalid(location, "the operation has both a body parameter and formData parameters")
+	}
+	for _, match := range rxPathParam.FindAllStringSubmatch(pth, -1) {
+		if !declared[match[1]] {
+			v.invalid(location, "the path parameter {%s} is not declared by the operation", match[1])
+		}
+	}
+}
+
+// validateUsage reports the definitions which no $ref points to, but the subtypes of discriminated bases.
+func (v *specValidator) validateUsage() {
+	referrers := definitionReferrers(v.doc)
+	for _, name := range sortedKeys(v.doc.Definitions) {
+		location := definitionsPrefix + escapePointer(name)
+		used := slices.ContainsFunc(referrers[name], func(referrer string) bool {
+			return referrer != location && !strings.HasPrefix(referrer, location+"/")
+		})
+		if used || isDiscriminated(v.doc, name) {
+			continue
+		}
+		v.report(location, DiagnosticUnusedDefinition, SeverityWarning, "the definition %s is not used", name)
+	}
+}
+
+// validateDeprecations reports the $refs to the definitions marked x-deprecated, as warnings since the clients
+// may still use them, but those of the deprecated operations, definitions and properties.
+func (v *specValidator) validateDeprecations() {
+	var deprecated []string
+	for _, name := range sortedKeys(v.doc.Definitions) {
+		if isDeprecated(v.doc.Definitions[name].Extensions) {
+			deprecated = append(deprecated, definitionsPrefix+escapePointer(name))
+		}
+	}
+	if len(deprecated) == 0 {
+		return
+	}
+	if v.doc.Paths != nil {
+		for pth, pathItem := range v.doc.Paths.Paths {
+			for method, op := range pathItemOperations(&pathItem) {
+				if op.Deprecated {
+					deprecated = append(deprecated, "#/paths/"+escapePointer(pth)+"/"+method)
+				}
+			}
+		}
+	}
+
+	walkSpecSchemas(v.doc, func(sch *spec.Schema, location string) {
+		name, ok := definitionName(sch.Ref)
+		if !ok || !isDeprecated(v.doc.Definitions[name].Extensions) || isDeprecated(sch.Extensions) {
+			return
+		}
+		if slices.ContainsFunc(deprecated, func(prefix string) bool {
+			return location == prefix || strings.HasPrefix(location, prefix+"/")
+		}) {
+			return
+		}
+		v.report(location, DiagnosticDeprecatedDefinition, SeverityWarning, "uses the deprecated definition %s", name)
+	})
+}
+
+var (
+	// rxStandardQuoted is the dotted location a problem of go-openapi/validate starts with, e.g.
+	// "paths./pets.get.parameters" must validate one and only one schema, or that of an operation, e.g.
+	// "/pets.GET.parameters.limit"
+	rxStandardQuoted    = regexp.MustCompile(`^"([^"]+)"`)
+	rxStandardOperation = regexp.MustCompile(`^(/.*)\.(GET|PUT|POST|DELETE|OPTIONS|HEAD|PATCH)(\..*)?$`)
+	rxStandardName      = regexp.MustCompile(`"(?:[^"\\]|\\.)*"`)
+	rxStandardParamIn   = regexp.MustCompile(`\.parameters(\..+)?\.in$`)
+	rxStandardParam     = regexp.MustCompile(`^(?:default|example) value for (.+) in (query|header|path|formData) does not validate`)
+)
+
+// validateStandard reports the problems of go-openapi/validate, both of the JSON schema of swagger 2.0 and of
+// its semantic checks. A problem is identified by its severity and the elements of the spec it's about, see
+// standardLocations: it's left out when the checks of ValidateSpec reported a problem of the same severity at
+// one of these elements or within it, e.g. the duplicate operationId of an operation, or the unknown type of
+// a parameter failing the schema.
+func (v *specValidator) validateStandard() {
+	raw, err := json.Marshal(v.doc)
+	if err != nil {
+		v.invalid("", "the spec can't be encoded: %v", err)
+		return
+	}
+	var tree any
+	if err = json.Unmarshal(raw, &tree); err != nil {
+		v.invalid("", "the spec can't be decoded: %v", err)
+		return
+	}
+	stripped := stripRefs(tree, tree, "")
+	if raw, err = json.Marshal(tree); err != nil {
+		v.invalid("", "the spec can't be encoded: %v", err)
+		return
+	}
+	document, err := loads.Analyzed(raw, "")
+	if err != nil {
+		v.invalid("", "the spec can't be analyzed: %v", err)
+		return
+	}
+	validator := validate.NewSpecValidator(document.Schema(), strfmt.Default)
+	validator.SetContinueOnErrors(true)
+	errs, warnings := validator.Validate(document)
+
+	var problems []standardProblem
+	add := func(results []error, severity string) {
+		for _, result := range flattenErrors(results) {
+			message := result.Error()
+			problem := standardProblem{message: message, rule: message, severity: severity}
+			problem.locations = v.standardLocations(tree, result)
+			problem.location = innermostLocation(problem.locations)
+			var validation *openapierrors.Validation
+			if errors.As(result, &validation) {
+				if rxStandardParamIn.MatchString(validation.Name) {
+					// the in of the parameters is told by validateParams: this is the enum of the last of
+					// the kinds of parameters, e.g. [header], for a parameter failing another rule
+					continue
+				}
+				// the schema is checked for the spec, then for the parameters of each operation, naming the
+				// element each its way, e.g. paths./pets.get.parameters.type and /pets.GET.parameters.limit.type
+				problem.rule = strings.TrimPrefix(message, validation.Name)
+			}
+			problems = append(problems, problem)
+		}
+	}
+	// the warnings are also among the errors
+	add(slices.DeleteFunc(slices.Clone(errs.Errors), func(err error) bool { return slices.Contains(warnings.Errors, err) }), SeverityError)
+	add(warnings.Errors, SeverityWarning)
+
+	slices.SortStableFunc(problems, func(a, b standardProblem) int {
+		return cmp.Or(cmp.Compare(a.location, b.location), cmp.Compare(a.rule, b.rule), cmp.Compare(a.message, b.message))
+	})
+	problems = slices.CompactFunc(problems, func(a, b standardProblem) bool {
+		return a.location == b.location && a.rule == b.rule
+	})
+
+	// go-openapi/validate tells the problems of the last of the alternatives of a schema failing, e.g. the
+	// kinds of parameters, which are those of the element told by the checks of ValidateSpec, and those of the
+	// elements whose $refs it can't resolve
+	explained := stripped
+	var locations []string
+	for _, problem := range problems {
+		if !rxSchemaAlternatives.MatchString(problem.message) {
+			locations = append(locations, problem.location)
+		} else if problem.location != "" && containsLocation(v.located[problem.severity], problem.location) {
+			explained = append(explained, problem.location)
+		}
+	}
+	for _, problem := range problems {
+		reported := slices.ContainsFunc(problem.locations, func(location string) bool {
+			return location != "#" && containsLocation(v.located[problem.severity], location)
+		})
+		switch {
+		case reported, problem.location != "" && problem.location != "#" && withinLocations(explained, problem.location):
+			continue
+		case rxSchemaAlternatives.MatchString(problem.message) && problem.location != "" && containsLocation(locations, problem.location):
+			// the alternative failing is told by the problems of its elements
+			continue
+		}
+		v.report(problem.location, DiagnosticInvalidSpec, problem.severity, "%s", problem.message)
+	}
+}
+
+// standardProblem is a problem of go-openapi/validate, with the text of its rule, which is its message
+// without the name of the element for the JSON schema errors, the locations of the elements it's about, and
+// the one it's reported at.
+type standardProblem struct {
+	location, message, rule, severity string
+	locations                         []string
+}
+
+// rxSchemaAlternatives matches the JSON schema errors of the alternatives of a schema, e.g. those of the
+// kinds of parameters, which none of the values match.
+var rxSchemaAlternatives = regexp.MustCompile(`must validate (one and only one|at least one) schema`)
+
+// containsLocation tells whether one of the locations is location, or is within it.
+func containsLocation(locations []string, location string) bool {
+	return slices.ContainsFunc(locations, func(known string) bool {
+		return known == location || strings.HasPrefix(known, location+"/")
+	})
+}
+
+// withinLocations tells whether location is one of the locations, or is within one of them.
+func withinLocations(locations []string, location string) bool {
+	return slices.ContainsFunc(locations, func(known string) bool {
+		return known == location || strings.HasPrefix(location, known+"/")
+	})
+}
+
+// innermostLocation is the location within all the others, e.g. an operation rather than its path. It's
+// empty when there's none, e.g. for several operations.
+func innermostLocation(locations []string) string {
+	var innermost string
+	for _, location := range locations {
+		if len(location) > len(innermost) {
+			innermost = location
+		}
+	}
+	for _, location := range locations {
+		if !withinLocations([]string{location}, innermost) {
+			return ""
+		}
+	}
+	return innermost
+}
+
+// stripRefs removes the $refs of a tree of the spec which don't resolve in it: the local ones pointing to
+// nothing, which the checks of ValidateSpec report, and the external ones, which go-openapi/validate would
+// fetch relative to the working directory. It returns the JSON pointers of the elements left without $ref.
+func stripRefs(root, node any, location string) []string {
+	var stripped []string
+	switch node := node.(type) {
+	case map[string]any:
+		if ref, isRef := node["$ref"].(string); isRef && !resolvesInTree(root, ref) {
+			delete(node, "$ref")
+			stripped = append(stripped, "#"+location)
+		}
+		for _, key := range sortedKeys(node) {
+			stripped = append(stripped, stripRefs(root, node[key], location+"/"+escapePointer(key))...)
+		}
+	case []any:
+		for i, element := range node {
+			stripped = append(stripped, stripRefs(root, element, location+"/"+strconv.Itoa(i))...)
+		}
+	}
+	return stripped
+}
+
+// resolvesInTree tells whether a $ref points to an element of the tree of the spec.
+func resolvesInTree(root any, ref string) bool {
+	pointer, isLocal := strings.CutPrefix(ref, "#")
+	if !isLocal {
+		return false
+	}
+	_, found := pointedElement(root, pointer)
+	return found
+}
+
+// pointedElement returns the element of a tree at a JSON pointer.
+func pointedElement(root any, pointer string) (any, bool) {
+	node := root
+	for _, token := range strings.Split(pointer, "/")[1:] {
+		token = unescapePointer(token)
+		switch parent := node.(type) {
+		case map[string]any:
+			child, found := parent[token]
+			if !found {
+				return nil, false
+			}
+			node = child
+		case []any:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(parent) {
+				return nil, false
+			}
+			node = parent[i]
+		default:
+			return nil, false
+		}
+	}
+	return node, true
+}
+
+// flattenErrors lists the errors of the composite errors.
+func flattenErrors(errs []error) []error {
+	var flat []error
+	for _, err := range errs {
+		var composite *openapierrors.CompositeError
+		if errors.As(err, &composite) {
+			flat = append(flat, flattenErrors(composite.Errors)...)
+			continue
+		}
+		flat = append(flat, err)
+	}
+	return flat
+}
+
+// standardLocations are the JSON pointers of the elements a problem of go-openapi/validate is about: the
+// location of its JSON schema errors, e.g. paths./pets.get.parameters.type, or the elements its semantic ones
+// name, e.g. an operation by its operationId, a path, the paths of a path parameter or a definition. They're
+// empty when none is found.
+func (v *specValidator) standardLocations(tree any, problem error) []string {
+	located := func(location string) []string {
+		if location == "" {
+			return nil
+		}
+		return []string{location}
+	}
+	var validation *openapierrors.Validation
+	if errors.As(problem, &validation) {
+		// the properties of the spec are named .info, and so on
+		dotted := strings.TrimPrefix(validation.Name, ".")
+		if validation.Code() == openapierrors.RequiredFailCode {
+			// the property missing is located at the element lacking it, e.g. info.title is required, or
+			// items in definitions.Pet.properties.tags is required
+			if validation.In != "" && validation.In != "body" {
+				return located(v.dottedLocation(tree, validation.In, nil))
+			}
+			dot := strings.LastIndex(dotted, ".")
+			if dot < 0 {
+				return []string{"#"}
+			}
+			return located(lackingLocation(tree, v.dottedLocation(tree, dotted[:dot], nil), dotted[dot+1:]))
+		}
+		if slices.Contains(paramLocations, validation.In) && validation.In != "body" {
+			// the problems of the default values and examples of the parameters name them, e.g. limit in query
+			return located(v.paramLocation(validation.Name, validation.In))
+		}
+		return located(v.dottedLocation(tree, dotted, validation.Value))
+	}
+
+	message := problem.Error()
+	if match := rxStandardParam.FindStringSubmatch(message); match != nil {
+		return located(v.paramLocation(match[1], match[2]))
+	}
+	if message == validate.NoValidPathErrorOrWarning {
+		return []string{"#/paths"}
+	}
+	if match := rxStandardQuoted.FindStringSubmatch(message); match != nil && strings.Contains(match[1], ".") {
+		return located(v.dottedLocation(tree, match[1], nil))
+	}
+	return v.namedLocations(message)
+}
+
+// lackingLocation is the location of the element lacking a property, which is the single element of the
+// array at location lacking it, e.g. a parameter of an operation without name.
+func lackingLocation(tree any, location, property string) string {
+	if location == "" {
+		return location
+	}
+	element, _ := pointedElement(tree, strings.TrimPrefix(location, "#"))
+	array, isArray := element.([]any)
+	if !isArray {
+		return location
+	}
+	lacking := -1
+	for i, element := range array {
+		if object, isObject := element.(map[string]any); isObject {
+			if _, has := object[property]; !has {
+				if lacking >= 0 {
+					return location
+				}
+				lacking = i
+			}
+		}
+	}
+	if lacking < 0 {
+		return location
+	}
+	return location + "/" + strconv.Itoa(lacking)
+}
+
+// namedLocations are the JSON pointers of the elements named by the quoted names of a message of
+// go-openapi/validate: the definitions, the paths, the paths of the path parameters and the operations by
+// their operationIds.
+func (v *specValidator) namedLocations(message string) []string {
+	var locations []string
+	for _, quoted := range rxStandardName.FindAllString(message, -1) {
+		name, err := strconv.Unquote(quoted)
+		if err != nil {
+			continue
+		}
+		if _, defined := v.doc.Definitions[strings.TrimPrefix(name, definitionsPrefix)]; defined {
+			locations = append(locations, definitionsPrefix+escapePointer(strings.TrimPrefix(name, definitionsPrefix)))
+		}
+		if v.doc.Paths == nil {
+			continue
+		}
+		for _, pth := range sortedKeys(v.doc.Paths.Paths) {
+			pathItem := v.doc.Paths.Paths[pth]
+			location := "#/paths/" + escapePointer(pth)
+			// the path parameters are named with or without their braces
+			if pth == name || strings.Contains(pth, "{"+strings.Trim(name, "{}")+"}") {
+				locations = append(locations, location)
+			}
+			for method, op := range pathItemOperations(&pathItem) {
+				if op.ID == name {
+					locations = append(locations, location+"/"+method)
+				}
+			}
+		}
+	}
+	slices.Sort(locations)
+	return slices.Compact(locations)
+}
+
+// paramLocation is the JSON pointer of the parameter of an operation of a name and location, or of the spec,
+// when there's a single one.
+func (v *specValidator) paramLocation(name, in string) string {
+	var locations []string
+	for key, param := range v.doc.Parameters {
+		if param.Name == name && param.In == in {
+			locations = append(locations, "#/parameters/"+escapePointer(key))
+		}
+	}
+	if v.doc.Paths != nil {
+		for pth, pathItem := range v.doc.Paths.Paths {
+			base := "#/paths/" + escapePointer(pth)
+			for i, param := range pathItem.Parameters {
+				if param.Name == name && param.In == in {
+					locations = append(locations, base+"/parameters/"+strconv.Itoa(i))
+				}
+			}
+			for method, op := range pathItemOperations(&pathItem) {
+				for i, param := range op.Parameters {
+					if param.Name == name && param.In == in {
+						locations = append(locations, base+"/"+method+"/parameters/"+strconv.Itoa(i))
+					}
+				}
+			}
+		}
+	}
+	if len(locations) != 1 {
+		return ""
+	}
+	return locations[0]
+}
+
+// dottedLocation is the JSON pointer of a dotted location of go-openapi/validate in the tree of the spec, e.g.
+// #/paths/~1v1.0~1pets/get/parameters/1/type for paths./v1.0/pets.get.parameters.type. The keys may hold dots,
+// and the indexes of the arrays are left out: an array stands for its element whose value at the rest of the
+// location is the value of the problem, when there's a single one. The pointer stops at the last element
+// found otherwise. The location of a parameter of an operation names it instead, e.g.
+// /v1.0/pets.GET.parameters.limit.type.
+func (v *specValidator) dottedLocation(tree any, dotted string, value any) string {
+	if operation := rxStandardOperation.FindStringSubmatch(dotted); operation != nil {
+		location := "#/paths/" + escapePointer(operation[1]) + "/" + strings.ToLower(operation[2])
+		rest, isParam := strings.CutPrefix(operation[3], ".parameters.")
+		if !isParam || v.doc.Paths == nil {
+			return location
+		}
+		pathItem := v.doc.Paths.Paths[operation[1]]
+		for method, op := range pathItemOperations(&pathItem) {
+			if method != strings.ToLower(operation[2]) {
+				continue
+			}
+			// the longest name matching, as for the keys
+			index, name := -1, ""
+			for i, param := range op.Parameters {
+				if (rest == param.Name || strings.HasPrefix(rest, param.Name+".")) && len(param.Name) >= len(name) {
+					index, name = i, param.Name
+				}
+			}
+			if index >= 0 {
+				location += "/parameters/" + strconv.Itoa(index)
+				if rest = strings.TrimPrefix(strings.TrimPrefix(rest, name), "."); rest != "" {
+					location += "/" + strings.ReplaceAll(rest, ".", "/")
+				}
+			}
+		}
+		return location
+	}
+	pointer, _ := resolveDotted(tree, dotted, value)
+	if pointer == "" {
+		return ""
+	}
+	return "#" + pointer
+}
+
+// resolveDotted resolves a dotted location in a tree, and tells whether it was resolved to its end, to a
+// value equal to value unless nil.
+func resolveDotted(node any, dotted string, value any) (string, bool) {
+	if dotted == "" {
+		return "", value == nil || fmt.Sprint(node) == fmt.Sprint(value)
+	}
+	switch node := node.(type) {
+	case map[string]any:
+		var key string
+		found := false
+		for candidate := range node {
+			// the longest key matching, e.g. /v1.0/pets rather than /v1
+			if (dotted == candidate || strings.HasPrefix(dotted, candidate+".")) && (!found || len(candidate) > len(key)) {
+				key, found = candidate, true
+			}
+		}
+		if !found {
+			// the properties are left out of the locations of the default values, e.g. definitions.Pet.age.default
+			if properties, isSchema := node["properties"].(map[string]any); isSchema {
+				pointer, resolved := resolveDotted(properties, dotted, value)
+				if pointer == "" {
+					return "", false
+				}
+				return "/properties" + pointer, resolved
+			}
+			return "", false
+		}
+		rest := strings.TrimPrefix(strings.TrimPrefix(dotted, key), ".")
+		pointer, resolved := resolveDotted(node[key], rest, value)
+		return "/" + escapePointer(key) + pointer, resolved
+	case []any:
+		var (
+			match   string
+			matches int
+		)
+		for i, element := range node {
+			if pointer, resolved := resolveDotted(element, dotted, value); resolved {
+				match = "/" + strconv.Itoa(i) + pointer
+				matches++
+			}
+		}
+		if matches != 1 {
+			return "", false
+		}
+		return match, true
+	default:
+		return "", false
+	}
+}
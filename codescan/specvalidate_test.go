@@ -0,0 +1,158 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"go/token"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSpec(t *testing.T) {
+	t.Run("should report the problems at the annotations building them", func(t *testing.T) {
+		sourceMap := make(map[string]token.Position)
+		doc, err := Run(&Options{
+			Packages:   []string{"github.com/3idey/codescan/fixtures/goparsing/validate"},
+			ScanModels: true,
+			SourceMap:  sourceMap,
+		})
+		require.NoError(t, err)
+
+		problems := ValidateSpec(doc, sourceMap)
+		require.Len(t, problems, 3)
+		for _, problem := range problems {
+			assert.Equal(t, "api.go", filepath.Base(problem.Pos.Filename))
+		}
+
+		assert.Equal(t, DiagnosticInvalidSpec, problems[0].Code)
+		assert.Equal(t, "#/paths/~1users/post: the operation has several body parameters: profile, user", problems[0].Message)
+		assert.Equal(t, 49, problems[0].Pos.Line)
+		assert.Equal(t, "#/paths/~1users~1{id}/get: the path parameter {id} is not declared by the operation", problems[1].Message)
+		assert.Equal(t, 31, problems[1].Pos.Line)
+
+		assert.Equal(t, DiagnosticUnusedDefinition, problems[2].Code)
+		assert.Equal(t, SeverityWarning, problems[2].Severity)
+		assert.Equal(t, 21, problems[2].Pos.Line)
+	})
+
+	t.Run("should check the operations of a spec", func(t *testing.T) {
+		getUser := spec.NewOperation("getUser").RespondsWith(200, spec.NewResponse())
+		getUser.Parameters = []spec.Parameter{
+			*spec.PathParam("id").Typed("string", ""),
+			{ParamProps: spec.ParamProps{Name: "name", In: "path"}, SimpleSchema: spec.SimpleSchema{Type: "string"}},
+		}
+		putUser := spec.NewOperation("getUser")
+		putUser.Parameters = []spec.Parameter{
+			*spec.PathParam("id").Typed("string", ""),
+			*spec.BodyParam("user", spec.RefSchema("#/definitions/User")),
+			*spec.BodyParam("profile", spec.StringProperty()),
+		}
+		listUsers := spec.NewOperation("").RespondsWith(200, spec.NewResponse())
+		listUsers.Parameters = []spec.Parameter{
+			*spec.ParamRef("#/parameters/limit"),
+			*spec.ParamRef("#/parameters/offset"),
+			*spec.QueryParam("tags").Typed("array", ""),
+			*spec.QueryParam("tags").Typed("string", ""),
+		}
+		listUsers.Security = []map[string][]string{{"oauth2": {}}}
+		doc := &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+			Swagger:    "2.0",
+			Info:       &spec.Info{InfoProps: spec.InfoProps{Title: "users", Version: "1.0.0"}},
+			Parameters: map[string]spec.Parameter{"limit": *spec.QueryParam("limit").Typed("integer", "int32")},
+			Paths: &spec.Paths{Paths: map[string]spec.PathItem{
+				"/users/{id}": {PathItemProps: spec.PathItemProps{Get: getUser, Put: putUser}},
+				"/users":      {PathItemProps: spec.PathItemProps{Get: listUsers}},
+			}},
+		}}
+
+		var messages []string
+		for _, problem := range ValidateSpec(doc, nil) {
+			assert.False(t, problem.Pos.IsValid())
+			messages = append(messages, problem.Message)
+		}
+		assert.Equal(t, []string{
+			"#/paths/~1users/get: the operation has no operationId",
+			"#/paths/~1users/get/parameters/1: $ref #/parameters/offset points to an undefined parameter",
+			"#/paths/~1users/get/parameters/2: the array parameter \"tags\" has no items",
+			"#/paths/~1users/get/parameters/3: the query parameter \"tags\" is already declared by #/paths/~1users/get/parameters/2",
+			"#/paths/~1users/get/security/0: the security scheme \"oauth2\" is not defined",
+			"#/paths/~1users~1{id}/get/parameters/1: the path parameter \"name\" is not required",
+			"#/paths/~1users~1{id}/get/parameters/1: the path parameter \"name\" is not a parameter of the path /users/{id}",
+			"#/paths/~1users~1{id}/put: the operationId \"getUser\" is already used by #/paths/~1users~1{id}/get",
+			"#/paths/~1users~1{id}/put: the operation has several body parameters: profile, user",
+			"#/paths/~1users~1{id}/put: the operation has no responses",
+		}, messages[1:], "the $ref of the body parameter is reported first")
+		assert.Equal(t, "#/paths/~1users~1{id}/put/parameters/1/schema: $ref #/definitions/User points to an undefined definition", messages[0])
+	})
+
+	t.Run("should check the schemas of a spec", func(t *testing.T) {
+		user := spec.Schema{SchemaProps: spec.SchemaProps{
+			Type:       spec.StringOrArray{"object"},
+			Required:   []string{"name", "email"},
+			Properties: map[string]spec.Schema{"name": *spec.StringProperty(), "tags": {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"array"}}}},
+		}}
+		doc := &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+			Swagger:     "2.0",
+			Info:        &spec.Info{InfoProps: spec.InfoProps{Title: "users", Version: "1.0.0"}},
+			Paths:       &spec.Paths{Paths: map[string]spec.PathItem{"/health": {PathItemProps: spec.PathItemProps{Get: spec.NewOperation("health").RespondsWith(204, spec.NewResponse())}}}},
+			Definitions: spec.Definitions{"User": user, "Node": *spec.RefSchema("#/definitions/Node")},
+		}}
+
+		var messages []string
+		for _, problem := range ValidateSpec(doc, nil) {
+			messages = append(messages, problem.Message)
+		}
+		assert.Equal(t, []string{
+			"#/definitions/User: the required property \"email\" is not defined",
+			"#/definitions/User/properties/tags: the array schema has no items",
+			"#/definitions/Node: the definition Node is not used",
+			"#/definitions/User: the definition User is not used",
+		}, messages, "a definition referring to itself is not used")
+	})
+
+	t.Run("should report the problems of go-openapi/validate", func(t *testing.T) {
+		listUsers := spec.NewOperation("listUsers").RespondsWith(200, spec.NewResponse().WithDescription("the users"))
+		listUsers.Parameters = []spec.Parameter{
+			{ParamProps: spec.ParamProps{Name: "limit", In: "cookie"}, SimpleSchema: spec.SimpleSchema{Type: "integer"}},
+			*spec.QueryParam("offset").Typed("int", ""),
+		}
+		doc := &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+			Swagger: "2.0",
+			Paths:   &spec.Paths{Paths: map[string]spec.PathItem{"/users": {PathItemProps: spec.PathItemProps{Get: listUsers}}}},
+		}}
+
+		var messages []string
+		for _, problem := range ValidateSpec(doc, nil) {
+			assert.Equal(t, SeverityError, problem.Severity)
+			messages = append(messages, problem.Message)
+		}
+		assert.Equal(t, []string{
+			"#/paths/~1users/get/parameters/0/in: the parameter \"limit\" is in \"cookie\", which is none of query, header, path, formData, body",
+			"#/paths/~1users/get/parameters/1/type: the query parameter \"offset\" is of type \"int\", which is none of string, number, integer, boolean, array, file",
+			"#: .info in body is required",
+		}, messages, "the schema problems of the parameters are those of ValidateSpec")
+	})
+
+	t.Run("should leave the external $refs out of the problems of go-openapi/validate", func(t *testing.T) {
+		getPet := spec.NewOperation("getPet").RespondsWith(200, spec.NewResponse().WithDescription("the pet").WithSchema(spec.RefSchema("pets.json#/definitions/Pet")))
+		getPet.Parameters = []spec.Parameter{*spec.ParamRef("params.json#/parameters/id")}
+		doc := &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+			Swagger: "2.0",
+			Info:    &spec.Info{InfoProps: spec.InfoProps{Title: "pets", Version: "1.0.0"}},
+			Paths:   &spec.Paths{Paths: map[string]spec.PathItem{"/pets/{id}": {PathItemProps: spec.PathItemProps{Get: getPet}}}},
+		}}
+
+		var messages []string
+		for _, problem := range ValidateSpec(doc, nil) {
+			messages = append(messages, problem.Message)
+		}
+		assert.Equal(t, []string{
+			"#/paths/~1pets~1{id}/get: the path parameter {id} is not declared by the operation",
+		}, messages, "the problems of the operation are told once, and its external $refs are not fetched")
+	})
+}
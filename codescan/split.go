@@ -0,0 +1,162 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// Directories of the documents of a split spec, see SplitSpec.
+const (
+	SplitDefinitionsDir = "definitions"
+	SplitPathsDir       = "paths"
+)
+
+// SplitSpec splits a spec into a root document, named root (e.g. swagger.json), with a document per definition
+// under definitions/ and per path under paths/, joined by relative $refs, e.g. ../definitions/Pet.json. The
+// documents are keyed by their path relative to the root document, with the extension of root.
+//
+// The definitions of the root document refer to their documents, and so do its paths, so that the documents
+// resolve from the root one, e.g. with go-openapi/loads. The names of the documents are the names of the
+// definitions, and the paths without their slashes and braces, e.g. pets_id.json for /pets/{id}, made unique
+// regardless of the case. The spec is left untouched.
+func SplitSpec(doc *spec.Swagger, root string) (map[string]any, error) {
+	if err := checkRefDocument(root); err != nil {
+		return nil, err
+	}
+	if strings.ContainsRune(root, '/') {
+		return nil, fmt.Errorf("invalid root document %q: it is in a directory", root)
+	}
+	split, err := cloneSpec(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	ext := path.Ext(root)
+	definitionFiles := splitFileNames(sortedKeys(split.Definitions), SplitDefinitionsDir, ext, func(name string) string {
+		return name
+	})
+	var paths []string
+	if split.Paths != nil {
+		paths = sortedKeys(split.Paths.Paths)
+	}
+	pathFiles := splitFileNames(paths, SplitPathsDir, ext, func(pth string) string {
+		name := strings.ReplaceAll(strings.Trim(pth, "/"), "/", "_")
+		name = strings.NewReplacer("{", "", "}", "").Replace(name)
+		if name == "" {
+			return "root"
+		}
+		return name
+	})
+
+	// the $refs of a document are relative to its directory
+	relocate := func(dir string) func(string) string {
+		return func(ref string) string {
+			if !strings.HasPrefix(ref, "#/") {
+				return ref
+			}
+			if local, ok := strings.CutPrefix(ref, definitionsPrefix); ok {
+				name, pointer, _ := strings.Cut(local, "/")
+				if file, known := definitionFiles[unescapePointer(name)]; known {
+					if pointer == "" {
+						return relativeDocument(dir, file)
+					}
+					return relativeDocument(dir, file) + "#/" + pointer
+				}
+			}
+			if dir == "" {
+				return ref
+			}
+			return relativeDocument(dir, root) + ref
+		}
+	}
+	documents := make(map[string]any, 1+len(definitionFiles)+len(pathFiles))
+
+	for name, file := range definitionFiles {
+		part := &spec.Swagger{SwaggerProps: spec.SwaggerProps{Definitions: spec.Definitions{name: split.Definitions[name]}}}
+		if err := rewriteRefs(part, relocate(SplitDefinitionsDir)); err != nil {
+			return nil, err
+		}
+		documents[file] = part.Definitions[name]
+		split.Definitions[name] = *spec.RefSchema(file)
+	}
+	for pth, file := range pathFiles {
+		part := &spec.Swagger{SwaggerProps: spec.SwaggerProps{Paths: &spec.Paths{Paths: map[string]spec.PathItem{pth: split.Paths.Paths[pth]}}}}
+		if err := rewriteRefs(part, relocate(SplitPathsDir)); err != nil {
+			return nil, err
+		}
+		documents[file] = part.Paths.Paths[pth]
+
+		ref, err := spec.NewRef(file)
+		if err != nil {
+			return nil, err
+		}
+		split.Paths.Paths[pth] = spec.PathItem{Refable: spec.Refable{Ref: ref}}
+	}
+
+	if err := rewriteRefs(split, relocate("")); err != nil {
+		return nil, err
+	}
+	documents[root] = split
+	return documents, nil
+}
+
+// splitFileNames names the documents of the keys of a split spec, in a directory. The names are unique
+// regardless of the case, for the case-insensitive file systems, with a numbered suffix after the first.
+func splitFileNames(keys []string, dir, ext string, name func(string) string) map[string]string {
+	files := make(map[string]string, len(keys))
+	taken := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		base := sanitizeFileName(name(key))
+		file := base
+		for i := 2; taken[strings.ToLower(file)]; i++ {
+			file = base + "-" + strconv.Itoa(i)
+		}
+		taken[strings.ToLower(file)] = true
+		files[key] = path.Join(dir, file+ext)
+	}
+	return files
+}
+
+// sanitizeFileName replaces the characters of a name which aren't safe in file names and URIs with _.
+func sanitizeFileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
+
+// relativeDocument is the path of a document of a split spec, relative to the directory of another one.
+func relativeDocument(dir, file string) string {
+	if dir == "" {
+		return file
+	}
+	if path.Dir(file) == dir {
+		return path.Base(file)
+	}
+	return "../" + file
+}
+
+// cloneSpec deep copies a spec, through its JSON.
+func cloneSpec(doc *spec.Swagger) (*spec.Swagger, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	clone := new(spec.Swagger)
+	if err := json.Unmarshal(data, clone); err != nil {
+		return nil, err
+	}
+	return clone, nil
+}
@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/loads"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitSpec(t *testing.T) {
+	doc, err := Run(&Options{Packages: []string{"github.com/3idey/codescan/fixtures/goparsing/petstore/..."}})
+	require.NoError(t, err)
+	before, err := MarshalJSON(doc, false)
+	require.NoError(t, err)
+
+	documents, err := SplitSpec(doc, "swagger.json")
+	require.NoError(t, err)
+	after, err := MarshalJSON(doc, false)
+	require.NoError(t, err)
+	assert.Equal(t, string(before), string(after), "the spec is left untouched")
+
+	require.Contains(t, documents, "swagger.json")
+	assert.Len(t, documents, 1+len(doc.Definitions)+len(doc.Paths.Paths))
+	root, ok := documents["swagger.json"].(*spec.Swagger)
+	require.True(t, ok)
+	pet := root.Definitions["pet"]
+	assert.Equal(t, "definitions/pet.json", pet.Ref.String())
+	pets := root.Paths.Paths["/pets/{id}"]
+	assert.Equal(t, "paths/pets_id.json", pets.Ref.String())
+
+	t.Run("should relocate the refs of the documents", func(t *testing.T) {
+		for name, document := range documents {
+			jazon, err := MarshalJSON(document, false)
+			require.NoError(t, err)
+			if name != "swagger.json" {
+				assert.NotContains(t, string(jazon), `"$ref":"#/`, name)
+			}
+		}
+		path, ok := documents["paths/pets_id.json"].(spec.PathItem)
+		require.True(t, ok)
+		require.NotNil(t, path.Get)
+		assert.Equal(t, "../swagger.json#/responses/genericError", path.Get.Responses.Default.Ref.String())
+		resp := path.Get.Responses.StatusCodeResponses[200]
+		assert.Equal(t, "../definitions/pet.json", resp.Schema.Ref.String())
+	})
+
+	t.Run("should load with the refs resolved", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, SplitDefinitionsDir), 0o755))
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, SplitPathsDir), 0o755))
+		for name, document := range documents {
+			jazon, err := MarshalJSON(document, false)
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(filepath.Join(dir, filepath.FromSlash(name)), jazon, 0o600))
+		}
+
+		loaded, err := loads.Spec(filepath.Join(dir, "swagger.json"))
+		require.NoError(t, err)
+		expanded, err := loaded.Expanded()
+		require.NoError(t, err)
+		swspec := expanded.Spec()
+		require.Contains(t, swspec.Paths.Paths, "/pets/{id}")
+		get := swspec.Paths.Paths["/pets/{id}"].Get
+		require.NotNil(t, get)
+		assert.Equal(t, doc.Paths.Paths["/pets/{id}"].Get.ID, get.ID)
+		pet := swspec.Definitions["pet"]
+		assert.Empty(t, pet.Ref.String())
+		assert.Contains(t, pet.Properties, "name")
+	})
+}
+
+func TestSplitFileNames(t *testing.T) {
+	files := splitFileNames([]string{"Pet", "pet", "Page[Pet]", "a/b"}, SplitDefinitionsDir, ".yaml", func(name string) string {
+		return name
+	})
+	assert.Equal(t, map[string]string{
+		"Pet":       "definitions/Pet.yaml",
+		"pet":       "definitions/pet-2.yaml",
+		"Page[Pet]": "definitions/Page_Pet_.yaml",
+		"a/b":       "definitions/a_b.yaml",
+	}, files)
+
+	_, err := SplitSpec(new(spec.Swagger), "docs/swagger.json")
+	require.Error(t, err)
+}
@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"cmp"
+	"go/ast"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// sortedDecls returns declarations in the order of their sources, by file and position, rather than in
+// the order of a map, so that the first declaration building a definition is the same from a scan to the next.
+func sortedDecls(decls map[*ast.Ident]*entityDecl) []*entityDecl {
+	return slices.SortedFunc(maps.Values(decls), func(a, b *entityDecl) int {
+		pa, pb := a.Pkg.Fset.Position(a.Ident.Pos()), b.Pkg.Fset.Position(b.Ident.Pos())
+		return cmp.Or(
+			cmp.Compare(a.Pkg.PkgPath, b.Pkg.PkgPath),
+			cmp.Compare(pa.Filename, pb.Filename),
+			cmp.Compare(pa.Offset, pb.Offset),
+		)
+	})
+}
+
+// sortParameters orders the parameters of the paths and operations of a spec by location, then name, the
+// $refs to shared parameters by the location and name of the parameters they point to.
+func sortParameters(doc *spec.Swagger) {
+	if doc.Paths == nil {
+		return
+	}
+	key := func(param spec.Parameter) (string, string) {
+		ref := param.Ref.String()
+		if ref == "" {
+			return param.In, param.Name
+		}
+		if shared, exists := doc.Parameters[strings.TrimPrefix(ref, parametersPrefix)]; exists {
+			return shared.In, shared.Name
+		}
+		return "", ref
+	}
+	compare := func(a, b spec.Parameter) int {
+		inA, nameA := key(a)
+		inB, nameB := key(b)
+		return cmp.Or(cmp.Compare(inA, inB), cmp.Compare(nameA, nameB))
+	}
+
+	for _, pth := range sortedKeys(doc.Paths.Paths) {
+		pathItem := doc.Paths.Paths[pth]
+		slices.SortStableFunc(pathItem.Parameters, compare)
+		for _, op := range pathItemOperations(&pathItem) {
+			slices.SortStableFunc(op.Parameters, compare)
+		}
+		doc.Paths.Paths[pth] = pathItem
+	}
+}
@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStableOutput(t *testing.T) {
+	const pkg = "github.com/3idey/codescan/fixtures/goparsing/stableorder"
+
+	scan := func(t *testing.T, input []byte) ([]byte, []byte) {
+		t.Helper()
+		opts := &Options{Packages: []string{pkg}, ScanModels: true}
+		if input != nil {
+			inputSpec, err := ParseInputSpec(input, false)
+			require.NoError(t, err)
+			opts.InputSpec = inputSpec
+		}
+		doc, err := Run(opts)
+		require.NoError(t, err)
+		jazon, err := MarshalJSON(doc, false)
+		require.NoError(t, err)
+		yml, err := MarshalYAML(doc)
+		require.NoError(t, err)
+		return jazon, yml
+	}
+
+	t.Run("should produce the same spec from a scan to the next", func(t *testing.T) {
+		jazon, yml := scan(t, nil)
+		for range 9 {
+			nextJSON, nextYAML := scan(t, nil)
+			require.Equal(t, string(jazon), string(nextJSON))
+			require.Equal(t, string(yml), string(nextYAML))
+		}
+	})
+
+	t.Run("should produce the spec of the golden file", func(t *testing.T) {
+		// the golden file is the spec written by the scanner when it was checked in, with -update-golden: a
+		// change of the spec, e.g. of its order after a change of the iterations of the maps, fails the test
+		// until the file is updated on purpose
+		doc, err := Run(&Options{Packages: []string{pkg + "/accounts"}})
+		require.NoError(t, err)
+		jazon, err := MarshalJSON(doc, false)
+		require.NoError(t, err)
+		jazon = append(jazon, '\n')
+		file := filepath.Join("..", "fixtures", "goparsing", "stableorder", "accounts", "swagger.json")
+		if updateGolden {
+			require.NoError(t, os.WriteFile(file, jazon, 0o600))
+		}
+		expected, err := os.ReadFile(file)
+		require.NoError(t, err)
+		assert.Equal(t, string(expected), string(jazon), "swagger.json is out of date, run the tests with -update-golden")
+	})
+
+	t.Run("should produce the same spec when merging an input spec", func(t *testing.T) {
+		input, _ := scan(t, nil)
+		jazon, yml := scan(t, input)
+		for range 9 {
+			nextJSON, nextYAML := scan(t, input)
+			require.Equal(t, string(jazon), string(nextJSON))
+			require.Equal(t, string(yml), string(nextYAML))
+		}
+	})
+}
+
+func TestSortParameters(t *testing.T) {
+	t.Run("should order the parameters by location and name", func(t *testing.T) {
+		doc, err := Run(&Options{
+			Packages:       []string{"github.com/3idey/codescan/fixtures/goparsing/paramembed"},
+			SortParameters: true,
+		})
+		require.NoError(t, err)
+
+		var params []string
+		for _, param := range doc.Paths.Paths["/users"].Get.Parameters {
+			params = append(params, param.In+" "+param.Name)
+		}
+		assert.Equal(t, []string{"header X-Trace-Id", "query limit", "query name", "query offset", "query sort"}, params)
+	})
+
+	t.Run("should sort the refs like the parameters they point to", func(t *testing.T) {
+		op := spec.NewOperation("listItems")
+		op.Parameters = []spec.Parameter{
+			*spec.QueryParam("sort"),
+			*spec.ParamRef("#/parameters/traceID"),
+			*spec.QueryParam("limit"),
+			*spec.ParamRef("external.json#/parameters/page"),
+		}
+		doc := &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+			Parameters: map[string]spec.Parameter{"traceID": *spec.HeaderParam("X-Trace-Id")},
+			Paths:      &spec.Paths{Paths: map[string]spec.PathItem{"/items": {PathItemProps: spec.PathItemProps{Get: op}}}},
+		}}
+		sortParameters(doc)
+
+		var params []string
+		for _, param := range op.Parameters {
+			params = append(params, param.Ref.String()+param.Name)
+		}
+		assert.Equal(t, []string{"external.json#/parameters/page", "#/parameters/traceID", "limit", "sort"}, params,
+			"the unresolved refs come first")
+	})
+}
@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+// Stats collects figures about a scan, see Options.Stats.
+type Stats struct {
+	// Packages is the number of packages classified, i.e. not filtered out by the package rules.
+	Packages int `json:"packages"`
+	// Files is the number of source files classified.
+	Files int `json:"files"`
+	// Annotations counts the meta sections, routes, operations, models, parameters and responses found.
+	Annotations int `json:"annotations"`
+	// ForcedAnnotations is the part of Annotations found in Options.ForceIncludeDirs.
+	ForcedAnnotations int `json:"forcedAnnotations"`
+	// EmptySchemas is the number of properties documented with an empty schema.
+	EmptySchemas int `json:"emptySchemas"`
+	// SkippedFields is the number of struct fields left out of the properties, e.g. channels.
+	SkippedFields int `json:"skippedFields"`
+	// SkippedDirs are the directories skipped with Options.DefaultSkips, relative to the working directory.
+	SkippedDirs []string `json:"skippedDirs,omitempty"`
+	// TagDecisions are the decisions of the tag rules (IncludeTags, ExcludeTags and those of ForceIncludeDirs)
+	// on the routes and operations, when there are rules.
+	TagDecisions []TagDecision `json:"tagDecisions,omitempty"`
+	// PackageDecisions are the decisions of the scan on the packages of Options.ExplainPackages.
+	PackageDecisions []PackageDecision `json:"packageDecisions,omitempty"`
+	// MergeEntries tell where the entries of the spec merged with the input spec by Options.MergeStrategy come
+	// from.
+	MergeEntries []MergeEntry `json:"mergeEntries,omitempty"`
+	// Operations is the number of operations of the spec.
+	Operations int `json:"operations"`
+	// UndocumentedOperations is the part of Operations marked x-undocumented, see Options.IncludeUndocumented.
+	UndocumentedOperations int `json:"undocumentedOperations"`
+	// Definitions is the number of definitions of the spec.
+	Definitions int `json:"definitions"`
+	// EmptyScanCauses are the likely causes of a scan without operations and definitions, see ErrEmptyScan.
+	EmptyScanCauses []string `json:"emptyScanCauses,omitempty"`
+	// Cached tells the spec was read from Options.CacheDir, the other statistics being those of the cached scan.
+	Cached bool `json:"cached,omitempty"`
+	// ChangedPackages are the packages whose changes invalidated the scan cached in Options.CacheDir, if any.
+	ChangedPackages []string `json:"changedPackages,omitempty"`
+	// ReusedModels are the definitions whose schemas were read from Options.CacheDir, rather than built again,
+	// their packages and the dependencies of these being unchanged since they were cached.
+	ReusedModels []string `json:"reusedModels,omitempty"`
+	// SchemaFiles are the JSON Schema files read for the Schema File directives, with those of their $refs.
+	SchemaFiles []string `json:"schemaFiles,omitempty"`
+	// Tags measure the operations of the spec by tag, see StatsByTag.
+	Tags []TagStats `json:"tags,omitempty"`
+	// ExampleFiles are the files read for the swagger:example file: annotations of the models and responses.
+	ExampleFiles []string `json:"exampleFiles,omitempty"`
+	// HoistedParameters are the shared parameters replacing the identical inline parameters of the operations,
+	// see Options.HoistParameters.
+	HoistedParameters []HoistedParameter `json:"hoistedParameters,omitempty"`
+}
+
+// TagDecision tells if the tag rules keep a route or an operation, and why.
+type TagDecision struct {
+	Method string   `json:"method"`
+	Path   string   `json:"path"`
+	ID     string   `json:"id"`
+	Tags   []string `json:"tags"`
+	Kept   bool     `json:"kept"`
+	Reason string   `json:"reason"` // e.g. "tag internal is excluded"
+}
+
+// PackageDecision tells if a package of Options.ExplainPackages was loaded and classified, and why.
+type PackageDecision struct {
+	Package    string `json:"package"`
+	Loaded     bool   `json:"loaded"`     // matched by the patterns, or imported by the matched packages
+	Classified bool   `json:"classified"` // its annotations were classified
+	Reason     string `json:"reason"`     // e.g. "imported, in the main module example.com/api; no include or exclude rule"
+}
+
+// MergeEntry tells if an operation, a definition, a shared parameter or a security definition of the spec merged
+// with the input spec comes from the input spec, the scan or both, and which side of a conflict was kept.
+type MergeEntry struct {
+	Section string `json:"section"`          // paths, definitions, parameters or securityDefinitions
+	Key     string `json:"key"`              // e.g. "GET /pets", or the name of a definition
+	Origin  string `json:"origin"`           // MergeOriginInput, MergeOriginScan or MergeOriginBoth
+	Kept    string `json:"kept,omitempty"`   // the side of a conflict kept, none with MergeError
+	Source  string `json:"source,omitempty"` // the Go source of the scanned side
+}
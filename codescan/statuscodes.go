@@ -0,0 +1,168 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"golang.org/x/tools/go/packages"
+)
+
+// statusMethods are the methods of response writers and web framework contexts taking a status code as
+// their first argument, e.g. w.WriteHeader(http.StatusCreated) or c.JSON(404, body).
+var statusMethods = []string{
+	"WriteHeader", "JSON", "IndentedJSON", "XML", "YAML", "String", "HTML", "Blob", "Data",
+	"Status", "SendStatus", "NoContent", "Redirect", "AbortWithStatus", "AbortWithStatusJSON",
+}
+
+// statusFuncs are the functions of net/http taking a status code, by the index of that argument.
+var statusFuncs = map[string]int{
+	"Error":    2, // http.Error(w, msg, code)
+	"Redirect": 3, // http.Redirect(w, r, url, code)
+}
+
+// handlerFor finds the function documented by a route or operation annotation, or holding it in its body.
+func handlerFor(file *ast.File, annotation token.Pos) *ast.FuncDecl {
+	for _, decl := range file.Decls {
+		fn, isFunc := decl.(*ast.FuncDecl)
+		if !isFunc || fn.Body == nil {
+			continue
+		}
+		start := fn.Pos()
+		if fn.Doc != nil {
+			start = fn.Doc.Pos()
+		}
+		if start <= annotation && annotation < fn.End() {
+			return fn
+		}
+	}
+	return nil
+}
+
+// writtenStatus is a status code written by a handler.
+type writtenStatus struct {
+	code int
+	pos  token.Position
+}
+
+// writtenStatuses collects the constant status codes written by the body of a handler: literals, constants
+// and the status identifiers of net/http. Dynamic status codes are ignored, but reported as such.
+func writtenStatuses(pkg *packages.Package, fn *ast.FuncDecl) (statuses []writtenStatus, dynamic bool) {
+	ast.Inspect(fn.Body, func(node ast.Node) bool {
+		call, isCall := node.(*ast.CallExpr)
+		if !isCall {
+			return true
+		}
+		sel, isSelector := call.Fun.(*ast.SelectorExpr)
+		if !isSelector {
+			return true
+		}
+
+		arg := -1
+		if obj, isFunc := pkg.TypesInfo.Uses[sel.Sel].(*types.Func); isFunc && obj.Pkg() != nil && obj.Pkg().Path() == "net/http" &&
+			obj.Type().(*types.Signature).Recv() == nil {
+			if index, known := statusFuncs[sel.Sel.Name]; known {
+				arg = index
+			}
+		} else if slices.Contains(statusMethods, sel.Sel.Name) {
+			arg = 0
+		}
+		if arg < 0 || arg >= len(call.Args) {
+			return true
+		}
+
+		if code, ok := constantStatus(pkg, call.Args[arg]); ok {
+			statuses = append(statuses, writtenStatus{code: code, pos: pkg.Fset.Position(call.Args[arg].Pos())})
+		} else if !isIntegerConstant(pkg, call.Args[arg]) && isInteger(pkg.TypesInfo.TypeOf(call.Args[arg])) {
+			dynamic = true
+		}
+		return true
+	})
+	return statuses, dynamic
+}
+
+func isIntegerConstant(pkg *packages.Package, expr ast.Expr) bool {
+	return pkg.TypesInfo.Types[expr].Value != nil
+}
+
+func isInteger(tpe types.Type) bool {
+	if tpe == nil {
+		return false
+	}
+	basic, isBasic := tpe.Underlying().(*types.Basic)
+	return isBasic && basic.Info()&types.IsInteger != 0
+}
+
+func constantStatus(pkg *packages.Package, expr ast.Expr) (int, bool) {
+	value := pkg.TypesInfo.Types[expr].Value
+	if value == nil || value.Kind() != constant.Int {
+		return 0, false
+	}
+	code, exact := constant.Int64Val(value)
+	if !exact || code < 100 || code > 599 {
+		return 0, false
+	}
+	return int(code), true
+}
+
+// checkStatusCodes compares the status codes written by the handler of an operation with the responses it
+// documents, see Options.CheckStatusCodes. Written codes are documented by a default response. A documented
+// 200 is never reported, since it is written implicitly, nor are the documented codes of a handler writing
+// dynamic codes.
+func (a *typeIndex) checkStatusCodes(path parsedPathContent, op *spec.Operation) {
+	if !a.checkStatuses || path.handler == nil {
+		return
+	}
+	statuses, dynamic := writtenStatuses(path.pkg, path.handler)
+	if len(statuses) == 0 {
+		return
+	}
+
+	var documented map[int]spec.Response
+	hasDefault := false
+	if op.Responses != nil {
+		documented = op.Responses.StatusCodeResponses
+		hasDefault = op.Responses.Default != nil
+	}
+
+	var written []int
+	for _, status := range statuses {
+		written = append(written, status.code)
+		if _, ok := documented[status.code]; ok || hasDefault {
+			continue
+		}
+		a.diagnose(Diagnostic{
+			Pos:     status.pos,
+			Code:    DiagnosticUndocumentedStatus,
+			Message: fmt.Sprintf("operation %s writes status %d, which its responses don't document", op.ID, status.code),
+		})
+	}
+
+	if dynamic {
+		return
+	}
+	var unwritten []string
+	for _, code := range sortedKeys(documented) {
+		if code != http.StatusOK && !slices.Contains(written, code) {
+			unwritten = append(unwritten, strconv.Itoa(code))
+		}
+	}
+	if len(unwritten) > 0 {
+		a.diagnose(Diagnostic{
+			Pos:  path.Pos,
+			Code: DiagnosticUnwrittenStatus,
+			Message: fmt.Sprintf("operation %s documents status %s, which its handler %s never writes",
+				op.ID, strings.Join(unwritten, ", "), path.handler.Name.Name),
+		})
+	}
+}
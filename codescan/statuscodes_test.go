@@ -0,0 +1,43 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const statusCodesFixture = "github.com/3idey/codescan/fixtures/goparsing/statuscodes"
+
+func TestCheckStatusCodes(t *testing.T) {
+	var diagnostics []Diagnostic
+	_, err := Run(&Options{Packages: []string{statusCodesFixture}, Diagnostics: &diagnostics, CheckStatusCodes: true})
+	require.NoError(t, err)
+
+	var problems []string
+	for _, diagnostic := range diagnostics {
+		problems = append(problems, diagnostic.Code+": "+diagnostic.Message)
+	}
+	assert.ElementsMatch(t, []string{
+		"undocumented-status: operation replaceOrder writes status 409, which its responses don't document",
+		"unwritten-status: operation replaceOrder documents status 410, which its handler ReplaceOrder never writes",
+	}, problems)
+	for _, diagnostic := range diagnostics {
+		switch diagnostic.Code {
+		case DiagnosticUndocumentedStatus:
+			assert.Equal(t, 40, diagnostic.Pos.Line)
+		case DiagnosticUnwrittenStatus:
+			assert.Equal(t, 29, diagnostic.Pos.Line)
+		}
+	}
+
+	t.Run("should not check the status codes by default", func(t *testing.T) {
+		diagnostics = nil
+		_, err := Run(&Options{Packages: []string{statusCodesFixture}, Diagnostics: &diagnostics})
+		require.NoError(t, err)
+		assert.Empty(t, diagnostics)
+	})
+}
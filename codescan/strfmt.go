@@ -0,0 +1,154 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+
+	"github.com/go-openapi/strfmt"
+)
+
+// swaggerFormats are the formats defined by the swagger 2.0 specification, some of which the strfmt
+// registry doesn't hold.
+var swaggerFormats = []string{"int32", "int64", "float", "double", "byte", "binary", "date", "date-time", "password"}
+
+// formatSpellings are the misspelled formats which the strfmt registry accepts, since it ignores dashes,
+// but which other tools don't.
+var formatSpellings = map[string]string{
+	"datetime": "date-time",
+}
+
+// checkFormats checks the swagger:strfmt annotations of a declaration and of its fields: the annotated
+// types must marshal as strings, and the formats must be known. Problems are reported with diagnostics,
+// or fail the scan with Options.StrictFormats.
+func (a *typeIndex) checkFormats(decl *entityDecl) error {
+	pkg := decl.Pkg
+	if format, ok := strfmtName(decl.Comments); ok {
+		subject := "type " + decl.Ident.Name
+		if err := a.checkFormat(pkg.Fset.Position(decl.Ident.Pos()), subject, decl.ObjType(), format); err != nil {
+			return err
+		}
+	}
+
+	st, isStruct := decl.Spec.Type.(*ast.StructType)
+	if !isStruct {
+		return nil
+	}
+	for _, field := range st.Fields.List {
+		format, ok := strfmtName(field.Doc)
+		if !ok || len(field.Names) == 0 {
+			continue
+		}
+		subject := "field " + decl.Ident.Name + "." + field.Names[0].Name
+		if err := a.checkFormat(pkg.Fset.Position(field.Pos()), subject, pkg.TypesInfo.TypeOf(field.Type), format); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *typeIndex) checkFormat(pos token.Position, subject string, tpe types.Type, format string) error {
+	var problems []Diagnostic
+	if tpe != nil && !marshalsAsString(tpe) {
+		problems = append(problems, Diagnostic{
+			Pos:  pos,
+			Code: DiagnosticInvalidStrfmt,
+			Message: fmt.Sprintf("%s is annotated swagger:strfmt %s, but its values are neither strings, encoding.TextMarshalers nor json.Marshalers",
+				subject, format),
+		})
+	}
+	if reason := a.unknownFormat(format); reason != "" {
+		problems = append(problems, Diagnostic{
+			Pos:     pos,
+			Code:    DiagnosticUnknownFormat,
+			Message: fmt.Sprintf("%s is annotated swagger:strfmt %s: %s", subject, format, reason),
+		})
+	}
+
+	for _, problem := range problems {
+		if a.strictFormats {
+			return problem
+		}
+		a.diagnose(problem)
+	}
+	return nil
+}
+
+// unknownFormat explains why a format is unknown, or returns an empty string for the formats of the spec,
+// of the strfmt registry and of Options.CustomFormats.
+func (a *typeIndex) unknownFormat(format string) string {
+	if slices.Contains(a.customFormats, format) || slices.Contains(swaggerFormats, format) {
+		return ""
+	}
+	if spelling, misspelled := formatSpellings[format]; misspelled {
+		return fmt.Sprintf("the format is spelled %q", spelling)
+	}
+	if strfmt.Default.ContainsName(format) {
+		return ""
+	}
+	return "unknown format, register it with Options.CustomFormats"
+}
+
+// marshalsAsString tells if encoding/json may marshal the values of a type as strings: strings, byte slices,
+// and the types implementing encoding.TextMarshaler or json.Marshaler, with a value or a pointer receiver.
+// The format of a slice, an array or a map applies to its elements.
+func marshalsAsString(tpe types.Type) bool {
+	if ptr, isPointer := tpe.(*types.Pointer); isPointer {
+		tpe = ptr.Elem()
+	}
+
+	if hasMarshaler(tpe) {
+		return true
+	}
+
+	switch underlying := tpe.Underlying().(type) {
+	case *types.Basic:
+		return underlying.Info()&types.IsString != 0
+	case *types.Slice:
+		return isByte(underlying.Elem()) || marshalsAsString(underlying.Elem())
+	case *types.Array:
+		return isByte(underlying.Elem()) || marshalsAsString(underlying.Elem())
+	case *types.Map:
+		return marshalsAsString(underlying.Elem())
+	default:
+		return false
+	}
+}
+
+// hasMarshaler tells if a type implements encoding.TextMarshaler or json.Marshaler, with a value or a pointer receiver.
+func hasMarshaler(tpe types.Type) bool {
+	ptr := types.NewPointer(tpe)
+	if isTextMarshaler(tpe) || isTextMarshaler(ptr) {
+		return true
+	}
+	if method, _, _ := types.LookupFieldOrMethod(ptr, true, nil, "MarshalJSON"); method != nil {
+		if _, isFunc := method.(*types.Func); isFunc {
+			return true
+		}
+	}
+	return false
+}
+
+// marshalerReceivers tells if a named type declares MarshalJSON or MarshalText on the value receiver, which
+// encoding/json always calls, or only on the pointer receiver, which it calls on addressable values only.
+func marshalerReceivers(tpe *types.Named) (value, pointer bool) {
+	for _, name := range []string{"MarshalJSON", "MarshalText"} {
+		if types.NewMethodSet(tpe).Lookup(nil, name) != nil {
+			return true, false
+		}
+		if types.NewMethodSet(types.NewPointer(tpe)).Lookup(nil, name) != nil {
+			pointer = true
+		}
+	}
+	return false, pointer
+}
+
+func isByte(tpe types.Type) bool {
+	basic, isBasic := tpe.Underlying().(*types.Basic)
+	return isBasic && basic.Kind() == types.Byte
+}
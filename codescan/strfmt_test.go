@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const strfmtCheckFixture = "github.com/3idey/codescan/fixtures/goparsing/strfmtcheck"
+
+func TestStrfmtChecks(t *testing.T) {
+	var diagnostics []Diagnostic
+	_, err := Run(&Options{Packages: []string{strfmtCheckFixture}, ScanModels: true, Diagnostics: &diagnostics})
+	require.NoError(t, err)
+
+	var problems []string
+	for _, diagnostic := range diagnostics {
+		problems = append(problems, diagnostic.Code+": "+diagnostic.Message)
+	}
+	assert.Equal(t, []string{
+		"invalid-strfmt: type Revision is annotated swagger:strfmt revision, but its values are neither strings, encoding.TextMarshalers nor json.Marshalers",
+		"unknown-format: type Revision is annotated swagger:strfmt revision: unknown format, register it with Options.CustomFormats",
+		`unknown-format: field Event.At is annotated swagger:strfmt datetime: the format is spelled "date-time"`,
+		"invalid-strfmt: field Event.Sequence is annotated swagger:strfmt uuid, but its values are neither strings, encoding.TextMarshalers nor json.Marshalers",
+	}, problems)
+	assert.Equal(t, 40, diagnostics[2].Pos.Line)
+
+	t.Run("should know the custom formats", func(t *testing.T) {
+		diagnostics = nil
+		_, err := Run(&Options{Packages: []string{strfmtCheckFixture}, ScanModels: true, Diagnostics: &diagnostics, CustomFormats: []string{"revision"}})
+		require.NoError(t, err)
+		assert.Len(t, diagnostics, 3)
+	})
+
+	t.Run("should fail in strict mode", func(t *testing.T) {
+		_, err := Run(&Options{Packages: []string{strfmtCheckFixture}, ScanModels: true, StrictFormats: true})
+		require.Error(t, err)
+		assert.Regexp(t, `models\.go:26:6: type Revision is annotated swagger:strfmt revision, but`, err.Error())
+	})
+}
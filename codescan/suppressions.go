@@ -0,0 +1,144 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+	"time"
+)
+
+// suppressionPrefix starts the suppression comments, see Suppression.
+const suppressionPrefix = "codescan:ignore"
+
+// Suppression is a codescan:ignore comment, which suppresses the diagnostics of a rule within the declaration
+// or the field it documents, until the end of the day it expires:
+//
+//	// codescan:ignore skipped-field until=2026-01-01 the channel is set by the server
+//
+// The reason is required. The comment may also follow a field, on its line.
+type Suppression struct {
+	Pos        token.Position // position of the comment
+	Code       string         // code of the diagnostics suppressed, e.g. DiagnosticSkippedField or the name of a Rule
+	Reason     string
+	Until      string // expiry date, formatted as 2006-01-02, empty when the suppression never expires
+	Expired    bool
+	Suppressed int // number of diagnostics suppressed
+
+	first, last int             // lines of the declaration
+	seen        map[string]bool // positions of the diagnostics suppressed
+}
+
+func withSuppressions(disabled bool, now time.Time) typeIndexOption {
+	return func(a *typeIndex) {
+		a.suppressionsDisabled = disabled
+		a.today = now.Format(baselineDate)
+	}
+}
+
+// collectSuppressions records the codescan:ignore comments of a file, with the lines of the declarations,
+// specs or fields they document. The malformed ones are reported with DiagnosticInvalidSuppression.
+func (a *typeIndex) collectSuppressions(fset *token.FileSet, file *ast.File) {
+	if a.suppressionsDisabled {
+		return
+	}
+	documented := make(map[*ast.CommentGroup]ast.Node)
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.GenDecl:
+			documented[node.Doc] = node
+		case *ast.FuncDecl:
+			documented[node.Doc] = node
+		case *ast.TypeSpec:
+			documented[node.Doc], documented[node.Comment] = node, node
+		case *ast.ValueSpec:
+			documented[node.Doc], documented[node.Comment] = node, node
+		case *ast.Field:
+			documented[node.Doc], documented[node.Comment] = node, node
+		}
+		return true
+	})
+
+	for _, group := range file.Comments {
+		for _, cmt := range group.List {
+			loc := rxSuppression.FindStringIndex(cmt.Text)
+			if loc == nil {
+				continue
+			}
+			text := cmt.Text[loc[1]:]
+			pos := fset.Position(cmt.Pos())
+			suppression, err := parseSuppression(text, a.today)
+			if err != nil {
+				a.diagnose(Diagnostic{Pos: pos, Code: DiagnosticInvalidSuppression, Message: err.Error()})
+				continue
+			}
+			suppression.Pos = pos
+			suppression.first, suppression.last = fset.Position(group.Pos()).Line, fset.Position(group.End()).Line
+			if node, isDoc := documented[group]; isDoc {
+				suppression.first = min(suppression.first, fset.Position(node.Pos()).Line)
+				suppression.last = max(suppression.last, fset.Position(node.End()).Line)
+			}
+			a.suppressions = append(a.suppressions, suppression)
+		}
+	}
+}
+
+// parseSuppression parses the text of a suppression comment after codescan:ignore: a rule, an optional
+// expiry date and a reason.
+func parseSuppression(text, today string) (*Suppression, error) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%s requires a rule and a reason", suppressionPrefix)
+	}
+	suppression := &Suppression{Code: fields[0], seen: make(map[string]bool)}
+	fields = fields[1:]
+	if len(fields) > 0 {
+		if until, hasExpiry := strings.CutPrefix(fields[0], "until="); hasExpiry {
+			if _, err := time.Parse(baselineDate, until); err != nil {
+				return nil, fmt.Errorf("invalid expiry date %q of the suppression of %s, expected until=YYYY-MM-DD", until, suppression.Code)
+			}
+			suppression.Until = until
+			suppression.Expired = until < today
+			fields = fields[1:]
+		}
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("the suppression of %s requires a reason", suppression.Code)
+	}
+	suppression.Reason = strings.Join(fields, " ")
+	return suppression, nil
+}
+
+// suppress tells if a diagnostic is suppressed by an active codescan:ignore comment, which counts it.
+func (a *typeIndex) suppress(diagnostic *Diagnostic) bool {
+	if diagnostic.Code == DiagnosticInvalidSuppression {
+		return false
+	}
+	for _, suppression := range a.suppressions {
+		if suppression.Expired || suppression.Code != diagnostic.Code || suppression.Pos.Filename != diagnostic.Pos.Filename {
+			continue
+		}
+		if suppression.first <= diagnostic.Pos.Line && diagnostic.Pos.Line <= suppression.last {
+			if !suppression.seen[diagnostic.Pos.String()] {
+				suppression.seen[diagnostic.Pos.String()] = true
+				suppression.Suppressed++
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// reportedSuppressions returns the suppressions found, none when they are disabled.
+func (a *typeIndex) reportedSuppressions() []Suppression {
+	result := make([]Suppression, 0, len(a.suppressions))
+	for _, suppression := range a.suppressions {
+		copied := *suppression
+		copied.seen = nil
+		result = append(result, copied)
+	}
+	return result
+}
@@ -0,0 +1,87 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuppressions(t *testing.T) {
+	const pkg = "github.com/3idey/codescan/fixtures/goparsing/suppressions"
+
+	t.Run("should suppress the diagnostics of the declarations and the fields", func(t *testing.T) {
+		var (
+			diagnostics  []Diagnostic
+			suppressions []Suppression
+		)
+		doc, err := Run(&Options{Packages: []string{pkg}, ScanModels: true, Diagnostics: &diagnostics, Suppressions: &suppressions})
+		require.NoError(t, err)
+
+		var reported []string
+		for _, diagnostic := range diagnostics {
+			reported = append(reported, diagnostic.Code+" "+diagnostic.Message)
+		}
+		assert.Equal(t, []string{
+			"invalid-suppression the suppression of skipped-field requires a reason",
+			`invalid-suppression invalid expiry date "tomorrow" of the suppression of skipped-field, expected until=YYYY-MM-DD`,
+			"skipped-field field Results is skipped: channels are not supported",
+			"skipped-field field Logs is skipped: channels are not supported",
+			"skipped-field field Ticks is skipped: channels are not supported",
+		}, reported, "the expired and invalid suppressions don't suppress anything")
+
+		require.Len(t, suppressions, 3)
+		assert.Equal(t, 9, suppressions[0].Pos.Line)
+		assert.Equal(t, DiagnosticSkippedField, suppressions[0].Code)
+		assert.Equal(t, "the events are streamed by the websocket", suppressions[0].Reason)
+		assert.Equal(t, 2, suppressions[0].Suppressed, "a suppression covers the fields of the declaration")
+		assert.True(t, suppressions[1].Expired)
+		assert.Equal(t, "2020-01-01", suppressions[1].Until)
+		assert.Zero(t, suppressions[1].Suppressed)
+		assert.Equal(t, "set by the server", suppressions[2].Reason)
+		assert.Equal(t, 1, suppressions[2].Suppressed, "a suppression may follow a field")
+
+		stream := doc.Definitions["Stream"]
+		assert.Equal(t, "Stream is a stream of events.", stream.Title)
+		assert.Empty(t, stream.Description, "the suppressions are not documentation")
+	})
+
+	t.Run("should ignore the suppressions with NoSuppressions", func(t *testing.T) {
+		var (
+			diagnostics  []Diagnostic
+			suppressions []Suppression
+		)
+		_, err := Run(&Options{
+			Packages:       []string{pkg},
+			ScanModels:     true,
+			NoSuppressions: true,
+			Diagnostics:    &diagnostics,
+			Suppressions:   &suppressions,
+		})
+		require.NoError(t, err)
+
+		assert.Len(t, diagnostics, 6)
+		for _, diagnostic := range diagnostics {
+			assert.Equal(t, DiagnosticSkippedField, diagnostic.Code)
+		}
+		assert.Empty(t, suppressions)
+	})
+}
+
+func TestParseSuppression(t *testing.T) {
+	suppression, err := parseSuppression(" rule-1 until=2026-01-01 legacy clients", "2026-01-01")
+	require.NoError(t, err)
+	assert.Equal(t, "rule-1", suppression.Code)
+	assert.Equal(t, "legacy clients", suppression.Reason)
+	assert.False(t, suppression.Expired, "a suppression works until the end of the day it expires")
+
+	suppression, err = parseSuppression("rule-1 until=2026-01-01 legacy clients", "2026-01-02")
+	require.NoError(t, err)
+	assert.True(t, suppression.Expired)
+
+	_, err = parseSuppression("", "2026-01-01")
+	require.EqualError(t, err, "codescan:ignore requires a rule and a reason")
+}
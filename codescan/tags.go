@@ -0,0 +1,173 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"slices"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"golang.org/x/tools/go/packages"
+)
+
+// parsedTagDoc is the declaration of a tag of the operations, with its description:
+//
+//	// swagger:tag pets
+//	//
+//	// Everything about the pets of the store.
+type parsedTagDoc struct {
+	Name      string
+	Remaining *ast.CommentGroup
+	Pos       token.Position // position of the annotation
+}
+
+func parseTagDoc(lines []*ast.Comment) (doc parsedTagDoc, annotation token.Pos) {
+	for _, cmt := range lines {
+		for line := range commentLines(cmt.Text) {
+			if matches := rxTagDoc.FindStringSubmatch(line); len(matches) > 1 {
+				doc.Name = matches[1]
+				annotation = cmt.Slash
+				continue
+			}
+			if doc.Name == "" {
+				continue
+			}
+			if doc.Remaining == nil {
+				doc.Remaining = new(ast.CommentGroup)
+			}
+			doc.Remaining.List = append(doc.Remaining.List, &ast.Comment{Slash: cmt.Slash, Text: line})
+		}
+	}
+	return doc, annotation
+}
+
+// collectTagDocs collects the swagger:tag annotations of a file. A tag is declared once.
+func (a *typeIndex) collectTagDocs(pkg *packages.Package, file *ast.File) error {
+	for _, cmts := range file.Comments {
+		doc, annotation := parseTagDoc(cmts.List)
+		if doc.Name == "" {
+			continue
+		}
+		doc.Pos = pkg.Fset.Position(annotation)
+		for _, other := range a.Tags {
+			if other.Name == doc.Name {
+				return fmt.Errorf("%v: tag %s is already declared at %v", doc.Pos, doc.Name, other.Pos)
+			}
+		}
+		a.Tags = append(a.Tags, doc)
+		a.countAnnotation(pkg)
+	}
+	return nil
+}
+
+// buildTagDocs adds the declared tags to the spec, in their order, with their description. The description
+// replaces the one of a tag of the input spec, or only fills it with a MergeStrategy.
+func (s *specBuilder) buildTagDocs() error {
+	for _, doc := range s.ctx.app.Tags {
+		tag := spec.Tag{TagProps: spec.TagProps{Name: doc.Name}}
+		idx := slices.IndexFunc(s.input.Tags, func(existing spec.Tag) bool { return existing.Name == doc.Name })
+		if idx >= 0 {
+			tag = s.input.Tags[idx]
+		}
+
+		sp := &sectionedParser{preserveFormat: s.ctx.opts.PreserveCommentFormat}
+		sp.setDescription = func(lines []string) {
+			if description := joinDropLast(lines); description != "" {
+				tag.Description = description
+			}
+		}
+		sp.taggers = []tagParser{
+			newSingleLineTagParser("ExternalDocs", &setExternalDocs{func(docs *spec.ExternalDocumentation) { tag.ExternalDocs = docs }}),
+		}
+		if err := sp.Parse(doc.Remaining); err != nil {
+			return fmt.Errorf("tag (%s): %w", doc.Name, err)
+		}
+
+		if idx >= 0 {
+			if s.merge != nil {
+				// the input spec is the base, which the swagger:tag fills
+				merged := s.input.Tags[idx]
+				mergeTag(&merged, tag)
+				tag = merged
+			}
+			s.input.Tags[idx] = tag
+			continue
+		}
+		s.input.Tags = append(s.input.Tags, tag)
+	}
+	return nil
+}
+
+// checkStrictTags fails on the tags of the scanned operations which are declared by no swagger:tag, input
+// spec or meta file, see Options.StrictTags, suggesting the closest declared tag.
+func (s *specBuilder) checkStrictTags() error {
+	if !s.ctx.opts.StrictTags || s.input.Paths == nil {
+		return nil
+	}
+	declared := make([]string, 0, len(s.input.Tags))
+	for _, tag := range s.input.Tags {
+		declared = append(declared, tag.Name)
+	}
+	positions := s.scannedOperations()
+
+	var errs []error
+	for _, pth := range sortedKeys(s.input.Paths.Paths) {
+		pathItem := s.input.Paths.Paths[pth]
+		for _, op := range pathItemOperations(&pathItem) {
+			pos, scanned := positions[op.ID]
+			if !scanned {
+				continue // an operation of the input spec
+			}
+			for _, tag := range op.Tags {
+				if slices.Contains(declared, tag) {
+					continue
+				}
+				message := fmt.Sprintf("%v: operation %s uses the undeclared tag %q, see swagger:tag", pos, op.ID, tag)
+				if suggestion := closestName(tag, declared); suggestion != "" {
+					message += fmt.Sprintf(": did you mean %q?", suggestion)
+				}
+				errs = append(errs, errors.New(message))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// closestName returns the candidate closest to a name by edit distance, ignoring the case, when it is close
+// enough to be a typo of it: at most 2 edits, or a third of the name.
+func closestName(name string, candidates []string) string {
+	best, bestDistance := "", max(2, len(name)/3)+1
+	for _, candidate := range candidates {
+		if distance := editDistance(strings.ToLower(name), strings.ToLower(candidate)); distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance of two strings, in runes.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagDocs(t *testing.T) {
+	doc, err := Run(&Options{Packages: []string{"github.com/3idey/codescan/fixtures/goparsing/tags"}})
+	require.NoError(t, err)
+
+	require.Len(t, doc.Tags, 2)
+	assert.Equal(t, "users", doc.Tags[0].Name)
+	assert.Equal(t, "The users of the API,\ncreated by signing up.", doc.Tags[0].Description)
+	assert.Equal(t, "orders", doc.Tags[1].Name)
+	assert.Equal(t, "The orders of the users.", doc.Tags[1].Description)
+}
+
+func TestTagDocsDuplicate(t *testing.T) {
+	_, err := Run(&Options{Packages: []string{"github.com/3idey/codescan/fixtures/goparsing/tags/duplicate"}, AllowEmpty: true})
+	require.Error(t, err)
+	assert.Regexp(t, `api\.go:8:1: tag users is already declared at .*api\.go:4:1`, err.Error())
+}
+
+func TestStrictTags(t *testing.T) {
+	opts := func() *Options {
+		return &Options{Packages: []string{"github.com/3idey/codescan/fixtures/goparsing/tags"}, StrictTags: true}
+	}
+
+	_, err := Run(opts())
+	require.Error(t, err)
+	assert.Regexp(t, `api\.go:20:1: operation listUserOrders uses the undeclared tag "user", see swagger:tag: did you mean "users"\?`, err.Error())
+	assert.Regexp(t, `(?m)api\.go:27:1: operation getStatus uses the undeclared tag "health", see swagger:tag$`, err.Error())
+
+	t.Run("should accept the tags of the input spec and of the meta file", func(t *testing.T) {
+		withDeclarations := opts()
+		withDeclarations.InputSpec = &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+			Tags: []spec.Tag{{TagProps: spec.TagProps{Name: "user", Description: "Deprecated, see users."}}},
+		}}
+		withDeclarations.Meta = &spec.Swagger{SwaggerProps: spec.SwaggerProps{Tags: []spec.Tag{{TagProps: spec.TagProps{Name: "health"}}}}}
+		doc, err := Run(withDeclarations)
+		require.NoError(t, err)
+		assert.Len(t, doc.Tags, 4)
+	})
+
+	t.Run("should be off by default", func(t *testing.T) {
+		lenient := opts()
+		lenient.StrictTags = false
+		_, err := Run(lenient)
+		require.NoError(t, err)
+	})
+}
+
+func TestClosestName(t *testing.T) {
+	declared := []string{"users", "orders", "payments"}
+	assert.Equal(t, "users", closestName("user", declared))
+	assert.Equal(t, "orders", closestName("Orders", declared))
+	assert.Equal(t, "payments", closestName("paymnets", declared))
+	assert.Empty(t, closestName("health", declared))
+	assert.Equal(t, 3, editDistance("kitten", "sitting"))
+}
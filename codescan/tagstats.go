@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// UntaggedOperations is the tag of the TagStats of the operations without tags.
+const UntaggedOperations = "(none)"
+
+// TagStats measures the operations of a tag, see StatsByTag.
+type TagStats struct {
+	Tag        string `json:"tag"`
+	Operations int    `json:"operations"`
+	// Undocumented is the number of the operations marked x-undocumented, see Options.IncludeUndocumented.
+	Undocumented int `json:"undocumented"`
+	// AverageParameters is the average number of parameters of the operations, with those of their paths.
+	AverageParameters float64 `json:"averageParameters"`
+	// Definitions is the number of definitions the operations reach, through their parameters and responses.
+	Definitions int `json:"definitions"`
+	// DeprecatedPercent is the percentage of the operations which are deprecated.
+	DeprecatedPercent float64 `json:"deprecatedPercent"`
+}
+
+// StatsByTag measures the operations of a spec by tag, ordered by tag with the untagged operations last. An
+// operation with several tags counts for each. The definitions reached by the operations of a tag are found
+// like those FilterAudience keeps, following the refs through the definitions, parameters and responses.
+func StatsByTag(doc *spec.Swagger) []TagStats {
+	if doc == nil || doc.Paths == nil {
+		return nil
+	}
+
+	type tagUses struct {
+		stats      TagStats
+		parameters int
+		deprecated int
+		uses       operationUses
+	}
+	byTag := make(map[string]*tagUses)
+	for _, pth := range sortedKeys(doc.Paths.Paths) {
+		pathItem := doc.Paths.Paths[pth]
+		for _, op := range pathItemOperations(&pathItem) {
+			tags := op.Tags
+			if len(tags) == 0 {
+				tags = []string{UntaggedOperations}
+			}
+			for _, tag := range slices.Compact(slices.Sorted(slices.Values(tags))) {
+				tu := byTag[tag]
+				if tu == nil {
+					tu = &tagUses{stats: TagStats{Tag: tag}}
+					byTag[tag] = tu
+				}
+				tu.stats.Operations++
+				if isUndocumented(op) {
+					tu.stats.Undocumented++
+				}
+				tu.parameters += len(op.Parameters) + len(pathItem.Parameters)
+				if op.Deprecated {
+					tu.deprecated++
+				}
+				tu.uses.add(op)
+				tu.uses.refs = append(tu.uses.refs, paramsRefs(pathItem.Parameters)...)
+			}
+		}
+	}
+
+	result := make([]TagStats, 0, len(byTag))
+	graph := newRefGraph(doc)
+	for _, tu := range byTag {
+		for ref := range graph.closure(tu.uses.refs) {
+			if name, ok := strings.CutPrefix(ref, definitionsPrefix); ok {
+				if _, known := doc.Definitions[unescapePointer(name)]; known {
+					tu.stats.Definitions++
+				}
+			}
+		}
+		operations := float64(tu.stats.Operations)
+		tu.stats.AverageParameters = roundHundredths(float64(tu.parameters) / operations)
+		tu.stats.DeprecatedPercent = roundHundredths(100 * float64(tu.deprecated) / operations)
+		result = append(result, tu.stats)
+	}
+	slices.SortFunc(result, func(a, b TagStats) int {
+		switch {
+		case a.Tag == b.Tag:
+			return 0
+		case a.Tag == UntaggedOperations:
+			return 1
+		case b.Tag == UntaggedOperations:
+			return -1
+		default:
+			return strings.Compare(a.Tag, b.Tag)
+		}
+	})
+	return result
+}
+
+func roundHundredths(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,101 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsByTag(t *testing.T) {
+	operation := func(tags []string, deprecated bool, params ...spec.Parameter) *spec.Operation {
+		op := spec.NewOperation("")
+		op.Tags = tags
+		op.Deprecated = deprecated
+		op.Parameters = params
+		return op
+	}
+	withResponse := func(op *spec.Operation, ref string) *spec.Operation {
+		return op.RespondsWith(200, spec.ResponseRef(ref))
+	}
+	body := func(ref string) spec.Parameter {
+		return *spec.BodyParam("body", spec.RefSchema(ref))
+	}
+
+	doc := &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+		Definitions: spec.Definitions{
+			"Pet":    *spec.RefProperty("#/definitions/Owner"),
+			"Owner":  *spec.StringProperty(),
+			"Order":  *spec.StringProperty(),
+			"Unused": *spec.StringProperty(),
+		},
+		Responses: map[string]spec.Response{
+			"petsResponse": *spec.NewResponse().WithSchema(spec.ArrayProperty(spec.RefProperty("#/definitions/Pet"))),
+		},
+		Paths: &spec.Paths{Paths: map[string]spec.PathItem{
+			"/pets": {PathItemProps: spec.PathItemProps{
+				Get:  withResponse(operation([]string{"pets"}, false, *spec.QueryParam("limit")), "#/responses/petsResponse"),
+				Post: operation([]string{"pets", "pets"}, true, body("#/definitions/Pet")),
+			}},
+			"/pets/{id}/orders": {PathItemProps: spec.PathItemProps{
+				Parameters: []spec.Parameter{*spec.PathParam("id")},
+				Get:        operation([]string{"orders", "pets"}, false, *spec.QueryParam("status")),
+				Post:       operation([]string{"orders"}, false, body("#/definitions/Order")),
+			}},
+			"/health": {PathItemProps: spec.PathItemProps{
+				Get: operation(nil, false),
+			}},
+		}},
+	}}
+
+	stats := StatsByTag(doc)
+	require.Len(t, stats, 3)
+
+	t.Run("should order the tags with the untagged operations last", func(t *testing.T) {
+		assert.Equal(t, "orders", stats[0].Tag)
+		assert.Equal(t, "pets", stats[1].Tag)
+		assert.Equal(t, UntaggedOperations, stats[2].Tag)
+	})
+
+	t.Run("should count the operations of each of their tags", func(t *testing.T) {
+		assert.Equal(t, 2, stats[0].Operations)
+		assert.Equal(t, 3, stats[1].Operations, "a tag repeated by an operation counts once")
+		assert.Equal(t, 1, stats[2].Operations)
+	})
+
+	t.Run("should average the parameters, with those of the paths", func(t *testing.T) {
+		assert.InDelta(t, 2.0, stats[0].AverageParameters, 0.001)
+		assert.InDelta(t, 1.33, stats[1].AverageParameters, 0.001)
+		assert.Zero(t, stats[2].AverageParameters)
+	})
+
+	t.Run("should count the definitions reached through the refs", func(t *testing.T) {
+		assert.Equal(t, 1, stats[0].Definitions, "Order")
+		assert.Equal(t, 2, stats[1].Definitions, "Pet, through the response, and Owner, through Pet")
+		assert.Zero(t, stats[2].Definitions)
+	})
+
+	t.Run("should tell the percentage of deprecated operations", func(t *testing.T) {
+		assert.Zero(t, stats[0].DeprecatedPercent)
+		assert.InDelta(t, 33.33, stats[1].DeprecatedPercent, 0.001)
+	})
+
+	t.Run("should fill the stats of the scan", func(t *testing.T) {
+		var scanned Stats
+		_, err := Run(&Options{
+			Packages: []string{"github.com/3idey/codescan/fixtures/goparsing/petstore/..."},
+			Stats:    &scanned,
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, scanned.Tags)
+		total := 0
+		for _, tag := range scanned.Tags {
+			total += tag.Operations
+		}
+		assert.GreaterOrEqual(t, total, scanned.Operations)
+	})
+}
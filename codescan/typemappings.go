@@ -0,0 +1,128 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/types"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// defaultTypeMappings are the schemas of the common types of the standard library and of the usual
+// libraries, which would otherwise be expanded into their internal fields when the dependencies are scanned.
+// Options.TypeMappings extends and overrides them. time.Time is always a date-time.
+var defaultTypeMappings = map[string]spec.Schema{
+	// encoding/json marshals a duration as its int64 nanoseconds
+	"time.Duration": *spec.Int64Property(),
+	// a raw message holds any JSON value, and is an alias of jsontext.Value with GOEXPERIMENT=jsonv2
+	"encoding/json.RawMessage":                  {},
+	"encoding/json/jsontext.Value":              {},
+	"net.IP":                                    *spec.StringProperty(),
+	"net/netip.Addr":                            *spec.StringProperty(),
+	"net/netip.AddrPort":                        *spec.StringProperty(),
+	"net/netip.Prefix":                          *spec.StringProperty(),
+	"math/big.Int":                              *new(spec.Schema).Typed("integer", ""),
+	"github.com/google/uuid.UUID":               *spec.StrFmtProperty("uuid"),
+	"github.com/google/uuid.NullUUID":           *spec.StrFmtProperty("uuid"),
+	"github.com/gofrs/uuid.UUID":                *spec.StrFmtProperty("uuid"),
+	"github.com/gofrs/uuid/v5.UUID":             *spec.StrFmtProperty("uuid"),
+	"github.com/satori/go.uuid.UUID":            *spec.StrFmtProperty("uuid"),
+	"github.com/shopspring/decimal.Decimal":     *spec.StrFmtProperty("decimal"),
+	"github.com/shopspring/decimal.NullDecimal": *spec.StrFmtProperty("decimal"),
+}
+
+func withTypeMappings(extra map[string]spec.Schema) typeIndexOption {
+	return func(a *typeIndex) {
+		a.typeMappings = make(map[string]spec.Schema, len(defaultTypeMappings)+len(extra))
+		for name, schema := range defaultTypeMappings {
+			a.typeMappings[name] = schema
+		}
+		for name, schema := range extra {
+			a.typeMappings[name] = schema
+		}
+	}
+}
+
+func checkTypeMappings(mappings map[string]spec.Schema) error {
+	var errs []error
+	for _, name := range sortedKeys(mappings) {
+		i := strings.LastIndexByte(name, '.')
+		if i <= 0 || i == len(name)-1 || strings.Contains(name[i:], "/") {
+			errs = append(errs, fmt.Errorf("invalid mapped type %q, expected a qualified name like github.com/google/uuid.UUID", name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ParseTypeMapping parses the schema of a type mapping written as a type with an optional format, e.g.
+// string:uuid or integer, for Options.TypeMappings.
+func ParseTypeMapping(value string) (spec.Schema, error) {
+	tpe, format, _ := strings.Cut(value, ":")
+	switch tpe {
+	case "string", "integer", "number", "boolean", "object", "array":
+	default:
+		return spec.Schema{}, fmt.Errorf("invalid type mapping %q, expected a swagger type with an optional format, e.g. string:uuid", value)
+	}
+	var schema spec.Schema
+	schema.Typed(tpe, format)
+	return schema, nil
+}
+
+// typeMapping returns the schema a named type is mapped to, by Options.TypeMappings or by default.
+func (a *typeIndex) typeMapping(tpe types.Type) (spec.Schema, bool) {
+	named, ok := types.Unalias(tpe).(*types.Named)
+	if !ok {
+		return spec.Schema{}, false
+	}
+	schema, found := a.typeMappings[namedTypeKey(named)]
+	return schema, found
+}
+
+// isMappedType tells whether a type, or the element of a pointer type, has a type mapping, whose schema is
+// deliberate even when empty, e.g. the any schema of json.RawMessage.
+func (a *typeIndex) isMappedType(tpe types.Type) bool {
+	if ptr, isPointer := types.Unalias(tpe).(*types.Pointer); isPointer {
+		tpe = ptr.Elem()
+	}
+	_, found := a.typeMapping(tpe)
+	return found
+}
+
+// buildTypeMapping documents a value with the schema of its type mapping: a copy of the schema, or its type,
+// format and enum for the simple parameters and headers.
+func buildTypeMapping(mapping spec.Schema, tgt swaggerTypable) error {
+	if tgt.In() == "body" {
+		data, err := json.Marshal(mapping)
+		if err != nil {
+			return err
+		}
+		var mapped spec.Schema
+		if err := json.Unmarshal(data, &mapped); err != nil {
+			return err
+		}
+		// the schema keeps what is already documented, e.g. its description or x-nullable
+		schema := tgt.Schema()
+		if mapped.Description == "" {
+			mapped.Description = schema.Description
+		}
+		for name, value := range schema.Extensions {
+			if _, set := mapped.Extensions[name]; !set {
+				mapped.AddExtension(name, value)
+			}
+		}
+		*schema = mapped
+		return nil
+	}
+	if len(mapping.Type) > 0 {
+		tgt.Typed(mapping.Type[0], mapping.Format)
+	}
+	if len(mapping.Enum) > 0 {
+		tgt.WithEnum(mapping.Enum...)
+	}
+	return nil
+}
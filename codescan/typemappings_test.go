@@ -0,0 +1,138 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTypeMappings(t *testing.T) {
+	const amount = "github.com/3idey/codescan/fixtures/goparsing/typemappings/money.Amount"
+	scan := func(t *testing.T, mappings map[string]spec.Schema) *spec.Swagger {
+		t.Helper()
+		doc, err := Run(&Options{
+			Packages:     []string{"github.com/3idey/codescan/fixtures/goparsing/typemappings"},
+			TypeMappings: mappings,
+		})
+		require.NoError(t, err)
+		return doc
+	}
+	money := *spec.StrFmtProperty("money").WithPattern(`^\d+(\.\d+)? [A-Z]{3}$`)
+	doc := scan(t, map[string]spec.Schema{amount: money})
+	invoice := doc.Definitions["Invoice"]
+	assertMoney := func(t *testing.T, schema *spec.Schema, name string) {
+		t.Helper()
+		require.NotNil(t, schema, name)
+		assert.Empty(t, schema.Ref.String(), name)
+		assert.Equal(t, spec.StringOrArray{"string"}, schema.Type, name)
+		assert.Equal(t, "money", schema.Format, name)
+		assert.Equal(t, money.Pattern, schema.Pattern, name)
+	}
+
+	t.Run("should map the types directly, as pointers, as elements and as map values", func(t *testing.T) {
+		total := invoice.Properties["total"]
+		assertMoney(t, &total, "total")
+		assert.Equal(t, "the total of the lines", total.Description)
+		assert.Equal(t, "Total", total.Extensions["x-go-name"])
+
+		discount := invoice.Properties["discount"]
+		assertMoney(t, &discount, "discount")
+		assertMoney(t, invoice.Properties["lines"].Items.Schema, "lines")
+		assertMoney(t, invoice.Properties["taxes"].AdditionalProperties.Schema, "taxes")
+		assert.NotContains(t, doc.Definitions, "Amount", "a mapped type has no definition")
+	})
+
+	t.Run("should map the types of the simple parameters and headers", func(t *testing.T) {
+		operation := doc.Paths.Paths["/invoices"].Get
+		require.NotNil(t, operation)
+		require.Len(t, operation.Parameters, 2)
+		for _, param := range operation.Parameters {
+			switch param.Name {
+			case "minTotal":
+				assert.Equal(t, "string", param.Type)
+				assert.Equal(t, "money", param.Format)
+			case "totals":
+				assert.Equal(t, "array", param.Type)
+				require.NotNil(t, param.Items)
+				assert.Equal(t, "string", param.Items.Type)
+				assert.Equal(t, "money", param.Items.Format)
+			default:
+				t.Errorf("unexpected parameter %s", param.Name)
+			}
+		}
+
+		header := doc.Responses["invoices"].Headers["X-Balance"]
+		assert.Equal(t, "string", header.Type)
+		assert.Equal(t, "money", header.Format)
+	})
+
+	t.Run("should map the common types by default", func(t *testing.T) {
+		terms := invoice.Properties["terms"]
+		assert.Equal(t, spec.StringOrArray{"integer"}, terms.Type)
+		assert.Equal(t, "int64", terms.Format)
+		issued := invoice.Properties["issued"]
+		assert.Equal(t, "date-time", issued.Format)
+		client := invoice.Properties["client"]
+		assert.Equal(t, spec.StringOrArray{"string"}, client.Type)
+		server := invoice.Properties["server"]
+		assert.Equal(t, spec.StringOrArray{"string"}, server.Type)
+	})
+
+	t.Run("should map json.RawMessage to any value, on every path", func(t *testing.T) {
+		extra, previous := invoice.Properties["extra"], invoice.Properties["previous"]
+		assert.Equal(t, "the raw JSON of the issuer", extra.Description)
+		for name, schema := range map[string]*spec.Schema{
+			"extra":    &extra,
+			"previous": &previous,
+			"history":  invoice.Properties["history"].Items.Schema,
+			"metadata": invoice.Properties["metadata"].AdditionalProperties.Schema,
+		} {
+			require.NotNil(t, schema, name)
+			assert.Empty(t, schema.Ref.String(), name)
+			assert.Empty(t, schema.Type, name)
+			assert.Nil(t, schema.Items, name)
+		}
+		assert.NotContains(t, doc.Definitions, "RawMessage")
+		assert.NotContains(t, doc.Definitions, "Value")
+	})
+
+	t.Run("should not report the empty schema of a mapped type", func(t *testing.T) {
+		var stats Stats
+		_, err := Run(&Options{Packages: []string{"github.com/3idey/codescan/fixtures/goparsing/typemappings"}, Stats: &stats})
+		require.NoError(t, err)
+		assert.Zero(t, stats.EmptySchemas)
+	})
+
+	t.Run("should override the built-in mappings", func(t *testing.T) {
+		duration, err := ParseTypeMapping("string:duration")
+		require.NoError(t, err)
+		doc := scan(t, map[string]spec.Schema{"time.Duration": duration})
+		terms := doc.Definitions["Invoice"].Properties["terms"]
+		assert.Equal(t, spec.StringOrArray{"string"}, terms.Type)
+		assert.Equal(t, "duration", terms.Format)
+
+		total := doc.Definitions["Invoice"].Properties["total"]
+		ref := total.Ref
+		assert.Equal(t, "#/definitions/Amount", ref.String(), "an unmapped type refers to its definition")
+	})
+
+	t.Run("should parse and check the mappings", func(t *testing.T) {
+		schema, err := ParseTypeMapping("integer")
+		require.NoError(t, err)
+		assert.Equal(t, spec.StringOrArray{"integer"}, schema.Type)
+		assert.Empty(t, schema.Format)
+
+		_, err = ParseTypeMapping("uuid")
+		require.Error(t, err)
+
+		err = (&Options{TypeMappings: map[string]spec.Schema{"UUID": schema}}).Validate()
+		var invalidErr *InvalidOptionError
+		require.ErrorAs(t, err, &invalidErr)
+		assert.Equal(t, "TypeMappings", invalidErr.Option)
+	})
+}
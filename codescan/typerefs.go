@@ -0,0 +1,135 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"path"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// fileImport is a package imported by a file, with the name the file refers to it by.
+type fileImport struct {
+	name string // alias of the import, or name of the package, "." for a dot-import
+	pkg  *packages.Package
+}
+
+// fileImports returns the imports of a file of a package, or all the imports of the package by name when
+// the file is unknown.
+func fileImports(pkg *packages.Package, file *ast.File) []fileImport {
+	var imports []fileImport
+	if file == nil {
+		for _, importPath := range sortedKeys(pkg.Imports) {
+			imported := pkg.Imports[importPath]
+			imports = append(imports, fileImport{name: imported.Name, pkg: imported})
+		}
+		return imports
+	}
+
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		imported, ok := pkg.Imports[importPath]
+		if !ok {
+			continue
+		}
+		name := imported.Name
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		if name == "_" {
+			continue
+		}
+		imports = append(imports, fileImport{name: name, pkg: imported})
+	}
+	return imports
+}
+
+// resolveTypeRef resolves a Go type referenced by an annotation of a file, e.g. models2.User, with the
+// imports of the file: a qualified name is looked up in the package imported with this name, aliased or
+// not, an unqualified one in the package of the file, then in its dot-imports. A qualifier which the file
+// doesn't import is looked up in the packages of the scan with this name, failing when several of them
+// declare the type: the schema then depends on a package its declaration doesn't import, see crossPackage.
+func (s *scanCtx) resolveTypeRef(pkg *packages.Package, file *ast.File, ref string) (*types.TypeName, error) {
+	imports := fileImports(pkg, file)
+	qualifier, name, qualified := strings.Cut(ref, ".")
+	if !qualified {
+		considered := []*packages.Package{pkg}
+		for _, imported := range imports {
+			if imported.name == "." {
+				considered = append(considered, imported.pkg)
+			}
+		}
+		for _, candidate := range considered {
+			if obj := lookupTypeInPackage(candidate, ref); obj != nil {
+				return obj, nil
+			}
+		}
+		return nil, unknownTypeRef(ref, considered)
+	}
+
+	for _, imported := range imports {
+		if imported.name != qualifier {
+			continue
+		}
+		if obj := lookupTypeInPackage(imported.pkg, name); obj != nil {
+			return obj, nil
+		}
+		return nil, unknownTypeRef(ref, []*packages.Package{imported.pkg})
+	}
+
+	if s.app.forked {
+		s.app.crossPackage = true
+	}
+	var considered []*packages.Package
+	var matches []*types.TypeName
+	for _, pkgPath := range sortedKeys(s.app.AllPackages) {
+		candidate := s.app.AllPackages[pkgPath]
+		if candidate.Name != qualifier && path.Base(pkgPath) != qualifier {
+			continue
+		}
+		considered = append(considered, candidate)
+		if obj := lookupTypeInPackage(candidate, name); obj != nil {
+			matches = append(matches, obj)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		if len(considered) == 0 {
+			return nil, fmt.Errorf("unknown type %q (no package is imported or scanned as %s)", ref, qualifier)
+		}
+		return nil, unknownTypeRef(ref, considered)
+	case 1:
+		return matches[0], nil
+	default:
+		candidates := make([]string, 0, len(matches))
+		for _, match := range matches {
+			candidates = append(candidates, match.Pkg().Path()+"."+match.Name())
+		}
+		return nil, fmt.Errorf("ambiguous type %q (declared by %s), import the package of the type", ref, strings.Join(candidates, ", "))
+	}
+}
+
+func lookupTypeInPackage(pkg *packages.Package, name string) *types.TypeName {
+	if pkg == nil || pkg.Types == nil {
+		return nil
+	}
+	obj, _ := pkg.Types.Scope().Lookup(name).(*types.TypeName)
+	return obj
+}
+
+func unknownTypeRef(ref string, considered []*packages.Package) error {
+	paths := make([]string, 0, len(considered))
+	for _, pkg := range considered {
+		paths = append(paths, pkg.PkgPath)
+	}
+	return fmt.Errorf("unknown type %q (looked up in %s)", ref, strings.Join(paths, ", "))
+}
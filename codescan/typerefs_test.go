@@ -0,0 +1,52 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTypeRefs(t *testing.T) {
+	const fixtures = "github.com/3idey/codescan/fixtures/goparsing/typerefs/"
+
+	t.Run("should resolve the aliased and dot-imported types of the file", func(t *testing.T) {
+		doc, err := Run(&Options{Packages: []string{fixtures + "api"}})
+		require.NoError(t, err)
+
+		responses := doc.Paths.Paths["/users/{id}"].Get.Responses.StatusCodeResponses
+		assert.Equal(t, "#/definitions/userV2", responses[200].Schema.Ref.String())
+		assert.Equal(t, "models2.User", responses[200].Description)
+		assert.Equal(t, "#/definitions/Account", responses[404].Schema.Ref.String())
+
+		require.Contains(t, doc.Definitions, "userV2")
+		assert.Contains(t, doc.Definitions["userV2"].Properties, "email")
+		require.Contains(t, doc.Definitions, "Account")
+		assert.True(t, doc.Definitions["Account"].Properties["id"].Type.Contains("integer"), "the dot-imported package")
+	})
+
+	t.Run("should resolve a package the file doesn't import", func(t *testing.T) {
+		doc, err := Run(&Options{Packages: []string{fixtures + "api"}})
+		require.NoError(t, err)
+
+		schema := doc.Paths.Paths["/accounts"].Get.Responses.StatusCodeResponses[200].Schema
+		require.NotNil(t, schema.Items)
+		assert.Equal(t, "#/definitions/userV2", schema.Items.Schema.Ref.String())
+	})
+
+	t.Run("should fail with the candidates of an ambiguous type", func(t *testing.T) {
+		_, err := Run(&Options{Packages: []string{fixtures + "ambiguous"}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `ambiguous type "models.Account" (declared by `+
+			fixtures+`models.Account, `+fixtures+`models/v2.Account)`)
+	})
+
+	t.Run("should fail with the packages looked up", func(t *testing.T) {
+		_, err := Run(&Options{Packages: []string{fixtures + "unknown"}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `response 200: unknown type "models2.Profile" (looked up in `+fixtures+`models/v2)`)
+	})
+}
@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"fmt"
+	"go/ast"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// xOneOfTypes holds the schemas of a union of types, which swagger 2.0 can't express with oneOf.
+const xOneOfTypes = "x-one-of-types"
+
+// unionPrimitives are the swagger types a union may name.
+var unionPrimitives = []string{"string", "number", "integer", "boolean", "object"}
+
+// setUnionTypes narrows a property, typically declared any, to a union of types:
+//
+//	// Types: string, number
+//	Amount any `json:"amount"`
+//
+// The members are swagger types, or Go types of the package of the field, or of its imports (e.g. models.Dog).
+type setUnionTypes struct {
+	builder  *schemaBuilder
+	schema   *spec.Schema
+	field    *ast.Field
+	property string
+}
+
+func (su *setUnionTypes) Matches(line string) bool {
+	return rxUnionTypes.MatchString(line)
+}
+
+func (su *setUnionTypes) Parse(lines []string) error {
+	if len(lines) == 0 || (len(lines) == 1 && len(lines[0]) == 0) {
+		return nil
+	}
+	matches := rxUnionTypes.FindStringSubmatch(lines[0])
+	if len(matches) < 2 {
+		return nil
+	}
+
+	var members []spec.Schema
+	for name := range strings.SplitSeq(matches[1], ",") {
+		member, err := su.builder.unionMember(strings.TrimSpace(name))
+		if err != nil {
+			return fmt.Errorf("%v: %w in the Types of %s", su.position(), err, su.subject())
+		}
+		members = append(members, member)
+	}
+
+	su.schema.Type = nil
+	su.schema.Format = ""
+	su.schema.Items = nil
+	su.schema.AddExtension(xOneOfTypes, members)
+	return nil
+}
+
+func (su *setUnionTypes) subject() string {
+	typeName := su.builder.decl.Obj().Name()
+	if su.property == "" {
+		return typeName
+	}
+	return typeName + "." + su.property
+}
+
+func (su *setUnionTypes) position() fmt.Stringer {
+	decl := su.builder.decl
+	if su.field == nil {
+		return decl.Pkg.Fset.Position(decl.Obj().Pos())
+	}
+	return decl.Pkg.Fset.Position(su.field.Pos())
+}
+
+// unionMember builds the schema of a member of a union: a swagger type, or a Go type resolved with the
+// imports of the file of the declaration being built.
+func (s *schemaBuilder) unionMember(name string) (spec.Schema, error) {
+	var member spec.Schema
+	for _, primitive := range unionPrimitives {
+		if name == primitive {
+			return *member.Typed(name, ""), nil
+		}
+	}
+
+	obj, err := s.ctx.resolveTypeRef(s.decl.Pkg, s.decl.File, name)
+	if err != nil {
+		return member, err
+	}
+	if err := s.buildFromType(obj.Type(), schemaTypable{&member, 0}); err != nil {
+		return member, err
+	}
+	return member, nil
+}
@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnionTypes(t *testing.T) {
+	doc, err := Run(&Options{Packages: []string{"github.com/3idey/codescan/fixtures/goparsing/uniontypes"}, ScanModels: true})
+	require.NoError(t, err)
+
+	entry := doc.Definitions["Entry"]
+	amount := entry.Properties["amount"]
+	assert.Empty(t, amount.Type)
+	assert.Equal(t, "The amount, as a number or a formatted string.", amount.Description)
+	assert.Equal(t, []spec.Schema{
+		*new(spec.Schema).Typed("string", ""),
+		*new(spec.Schema).Typed("number", ""),
+	}, amount.Extensions[xOneOfTypes])
+
+	pet := entry.Properties["pet"]
+	members, ok := pet.Extensions[xOneOfTypes].([]spec.Schema)
+	require.True(t, ok)
+	require.Len(t, members, 2)
+	assert.Equal(t, "#/definitions/Dog", members[0].Ref.String())
+	assert.Equal(t, "#/definitions/Cat", members[1].Ref.String())
+	assert.Contains(t, doc.Definitions, "Cat", "the members are discovered")
+
+	assert.NotContains(t, entry.Properties["other"].Extensions, xOneOfTypes)
+}
+
+func TestUnionTypesUnknown(t *testing.T) {
+	_, err := Run(&Options{Packages: []string{"github.com/3idey/codescan/fixtures/goparsing/uniontypes/unknown"}, ScanModels: true})
+	require.Error(t, err)
+	assert.Regexp(t, `models\.go:9:2: unknown type "nubmer" \(looked up in github\.com/3idey/codescan/fixtures/goparsing/uniontypes/unknown\) in the Types of Entry\.amount`, err.Error())
+}
+
+func TestUnionTypesOpenAPI3(t *testing.T) {
+	t.Run("should turn the unions into oneOf", func(t *testing.T) {
+		doc, err := Run3(&Options{Packages: []string{"github.com/3idey/codescan/fixtures/goparsing/uniontypes"}, ScanModels: true})
+		require.NoError(t, err)
+
+		properties := asObject(asObject(asObject(asObject(doc["components"])["schemas"])["Entry"])["properties"])
+		amount := asObject(properties["amount"])
+		assert.Equal(t, []any{map[string]any{"type": "string"}, map[string]any{"type": "number"}}, amount["oneOf"])
+		assert.NotContains(t, amount, xOneOfTypes)
+		assert.Equal(t, []any{
+			map[string]any{"$ref": "#/components/schemas/Dog"},
+			map[string]any{"$ref": "#/components/schemas/Cat"},
+		}, asObject(properties["pet"])["oneOf"])
+		assert.NotContains(t, asObject(properties["other"]), "oneOf")
+	})
+
+	t.Run("should turn the unions of an input spec into oneOf", func(t *testing.T) {
+		pet := spec.Schema{VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{
+			xOneOfTypes: []any{map[string]any{"$ref": "#/definitions/Dog"}, map[string]any{"type": "string"}},
+		}}}
+		doc := &spec.Swagger{SwaggerProps: spec.SwaggerProps{Swagger: "2.0", Definitions: spec.Definitions{"Pet": pet}}}
+		upgraded, err := UpgradeSwagger(doc)
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]any{"oneOf": []any{
+			map[string]any{"$ref": "#/components/schemas/Dog"},
+			map[string]any{"type": "string"},
+		}}, asObject(asObject(upgraded["components"])["schemas"])["Pet"])
+	})
+}
@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"go/token"
+
+	"github.com/go-openapi/spec"
+)
+
+// UnusedDefinition is a definition of the spec which no operation reaches, as reported to Options.UnusedDefinitions.
+type UnusedDefinition struct {
+	Name   string
+	GoType string         // Go type of the definition, e.g. github.com/acme/pets.Pet, empty for those of the input spec
+	Pos    token.Position // position of the declaration of the Go type
+	Input  bool           // the definition comes from Options.InputSpec, see Options.PruneInput
+}
+
+// UnreachableDefinitions lists the definitions of a spec which the operations don't reach through their
+// $refs, sorted by name. The refs are followed through the definitions, including their allOf, items and
+// additionalProperties, and through the parameters and responses; the shared parameters and responses of
+// the spec are reached too, and so are the subtypes of a polymorphic definition which is reached.
+func UnreachableDefinitions(doc *spec.Swagger) []string {
+	var uses operationUses
+	if doc.Paths != nil {
+		for _, pth := range sortedKeys(doc.Paths.Paths) {
+			pathItem := doc.Paths.Paths[pth]
+			uses.refs = append(uses.refs, paramsRefs(pathItem.Parameters)...)
+			for _, op := range pathItemOperations(&pathItem) {
+				uses.add(op)
+			}
+		}
+	}
+	for _, name := range sortedKeys(doc.Parameters) {
+		uses.refs = append(uses.refs, parametersPrefix+escapePointer(name))
+	}
+	for _, name := range sortedKeys(doc.Responses) {
+		uses.refs = append(uses.refs, responsesPrefix+escapePointer(name))
+	}
+
+	reached := reachableRefs(doc, uses.refs)
+	var unreachable []string
+	for _, name := range sortedKeys(doc.Definitions) {
+		if !reached[definitionsPrefix+escapePointer(name)] {
+			unreachable = append(unreachable, name)
+		}
+	}
+	return unreachable
+}
+
+// pruneUnused removes the definitions no operation reaches with Options.PruneUnused, and reports them with
+// their Go type to Options.UnusedDefinitions. Unless Options.PruneInput is set, those of the input spec are
+// kept, with the definitions they refer to.
+func (s *specBuilder) pruneUnused() {
+	opts := s.ctx.opts
+	if opts == nil || !opts.PruneUnused && opts.UnusedDefinitions == nil {
+		return
+	}
+
+	unreachable := UnreachableDefinitions(s.input)
+	var kept []string
+	if !opts.PruneInput {
+		for _, name := range unreachable {
+			if s.inputDefinitions[name] {
+				kept = append(kept, definitionsPrefix+name)
+			}
+		}
+	}
+	reached := reachableRefs(s.input, kept)
+
+	var unused []UnusedDefinition
+	for _, name := range unreachable {
+		if reached[definitionsPrefix+name] {
+			continue
+		}
+		definition := UnusedDefinition{Name: name, Input: s.inputDefinitions[name]}
+		if !definition.Input {
+			definition.GoType = s.ctx.app.definitionTypes[name]
+			definition.Pos = s.ctx.app.definitionPositions[name]
+		}
+		unused = append(unused, definition)
+	}
+	if opts.PruneUnused {
+		for _, definition := range unused {
+			delete(s.input.Definitions, definition.Name)
+		}
+	}
+	if opts.UnusedDefinitions != nil {
+		*opts.UnusedDefinitions = unused
+	}
+}
@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnreachableDefinitions(t *testing.T) {
+	doc, err := ParseInputSpec([]byte(`{
+  "swagger": "2.0",
+  "info": {"title": "Shop", "version": "1.0"},
+  "paths": {"/orders/{id}": {
+    "parameters": [{"name": "filter", "in": "body", "schema": {"$ref": "#/definitions/Filter"}}],
+    "get": {"responses": {
+      "200": {"description": "the order", "schema": {"$ref": "#/definitions/Order"}},
+      "404": {"$ref": "#/responses/notFound"}
+    }}
+  }},
+  "responses": {"notFound": {"description": "not found", "schema": {"$ref": "#/definitions/Error"}}},
+  "definitions": {
+    "Filter": {"type": "object"},
+    "Order": {"allOf": [{"$ref": "#/definitions/Base"}, {"properties": {
+      "lines": {"type": "array", "items": {"$ref": "#/definitions/Line"}},
+      "labels": {"type": "object", "additionalProperties": {"$ref": "#/definitions/Label"}},
+      "payment": {"$ref": "#/definitions/Payment"}
+    }}]},
+    "Base": {"type": "object"},
+    "Line": {"type": "object"},
+    "Label": {"type": "string"},
+    "Payment": {"type": "object", "discriminator": "kind", "required": ["kind"], "properties": {"kind": {"type": "string"}}},
+    "Card": {"allOf": [{"$ref": "#/definitions/Payment"}]},
+    "Error": {"type": "object"},
+    "Legacy": {"type": "object", "properties": {"owner": {"$ref": "#/definitions/Owner"}}},
+    "Owner": {"type": "object"}
+  }
+}`), false)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Legacy", "Owner"}, UnreachableDefinitions(doc))
+
+	t.Run("should follow the escaped refs to the names with a / or a ~", func(t *testing.T) {
+		doc, err := ParseInputSpec([]byte(`{
+  "swagger": "2.0",
+  "info": {"title": "Shop", "version": "1.0"},
+  "paths": {"/orders": {"get": {"responses": {"200": {"$ref": "#/responses/v1~1orders"}}}}},
+  "responses": {"v1/orders": {"description": "the orders", "schema": {"$ref": "#/definitions/v1~1Order"}}},
+  "definitions": {
+    "v1/Order": {"type": "object", "properties": {"line": {"$ref": "#/definitions/v1~1Line~0draft"}}},
+    "v1/Line~draft": {"type": "object"},
+    "v1/Legacy": {"type": "object"}
+  }
+}`), false)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"v1/Legacy"}, UnreachableDefinitions(doc))
+	})
+}
+
+func TestPruneUnused(t *testing.T) {
+	const petstore = "github.com/3idey/codescan/fixtures/goparsing/petstore/..."
+	input := func(t *testing.T) *spec.Swagger {
+		t.Helper()
+		doc, err := ParseInputSpec([]byte(`{
+  "swagger": "2.0",
+  "info": {"title": "Petstore", "version": "1.0"},
+  "paths": {},
+  "definitions": {
+    "legacyUser": {"type": "object", "properties": {"address": {"$ref": "#/definitions/address"}}},
+    "address": {"type": "object"}
+  }
+}`), false)
+		require.NoError(t, err)
+		return doc
+	}
+
+	t.Run("should report the unreachable definitions with their Go type", func(t *testing.T) {
+		var unused []UnusedDefinition
+		doc, err := Run(&Options{Packages: []string{petstore}, ScanModels: true, UnusedDefinitions: &unused})
+		require.NoError(t, err)
+		require.Len(t, unused, 1)
+		assert.Equal(t, "user", unused[0].Name)
+		assert.Equal(t, "github.com/3idey/codescan/fixtures/goparsing/petstore/models.User", unused[0].GoType)
+		assert.Equal(t, "user.go", filepath.Base(unused[0].Pos.Filename))
+		assert.False(t, unused[0].Input)
+		assert.Contains(t, doc.Definitions, "user", "only reported without PruneUnused")
+	})
+
+	t.Run("should remove the unreachable definitions but those of the input spec", func(t *testing.T) {
+		var unused []UnusedDefinition
+		doc, err := Run(&Options{Packages: []string{petstore}, ScanModels: true, PruneUnused: true, InputSpec: input(t), UnusedDefinitions: &unused})
+		require.NoError(t, err)
+		assert.NotContains(t, doc.Definitions, "user")
+		assert.Contains(t, doc.Definitions, "pet")
+		assert.Contains(t, doc.Definitions, "legacyUser")
+		assert.Contains(t, doc.Definitions, "address", "referred to by a definition of the input spec")
+		require.Len(t, unused, 1)
+		assert.Equal(t, "user", unused[0].Name)
+	})
+
+	t.Run("should remove those of the input spec with PruneInput", func(t *testing.T) {
+		var unused []UnusedDefinition
+		doc, err := Run(&Options{Packages: []string{petstore}, PruneUnused: true, PruneInput: true, InputSpec: input(t), UnusedDefinitions: &unused})
+		require.NoError(t, err)
+		assert.NotContains(t, doc.Definitions, "legacyUser")
+		assert.NotContains(t, doc.Definitions, "address")
+		assert.Contains(t, doc.Definitions, "pet")
+		require.Len(t, unused, 2)
+		assert.Equal(t, UnusedDefinition{Name: "address", Input: true}, unused[0])
+		assert.Empty(t, unused[1].GoType, "the definitions of the input spec have no Go type")
+	})
+
+	t.Run("should only prune the input spec with PruneUnused", func(t *testing.T) {
+		err := (&Options{PruneInput: true}).Validate()
+		var conflict *OptionsConflictError
+		require.ErrorAs(t, err, &conflict)
+		assert.Equal(t, []string{"PruneInput", "PruneUnused"}, conflict.Options)
+	})
+}
@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"fmt"
+	"go/token"
+
+	"github.com/go-openapi/spec"
+)
+
+// responseUnwrap is the "Unwrap: data" line of a swagger:response, documenting the body as the schema
+// of a property of the declared type, without its envelope.
+type responseUnwrap struct {
+	property string
+	pos      token.Position
+}
+
+// setUnwrapOp parses the "Unwrap: data" line of a swagger:response.
+type setUnwrapOp struct {
+	tgt *string
+}
+
+func (su *setUnwrapOp) Matches(line string) bool {
+	return rxUnwrap.MatchString(line)
+}
+
+func (su *setUnwrapOp) Parse(lines []string) error {
+	if len(lines) == 0 || (len(lines) == 1 && len(lines[0]) == 0) {
+		return nil
+	}
+	matches := rxUnwrap.FindStringSubmatch(lines[0])
+	if len(matches) > 1 && len(matches[1]) > 0 {
+		*su.tgt = matches[1]
+	}
+	return nil
+}
+
+// unwrapResponses replaces the body of the responses declaring Unwrap by the schema of the property of their
+// envelope, once the definitions are built: the definitions it refers to are already discovered through the
+// envelope. The examples of the responses are re-rooted under the property as well.
+func (s *specBuilder) unwrapResponses() error {
+	for _, name := range sortedKeys(s.unwraps) {
+		unwrap := s.unwraps[name]
+		resp, found := s.responses[name]
+		if !found {
+			continue
+		}
+		if resp.Schema == nil {
+			return fmt.Errorf("%v: response %s has no body to unwrap", unwrap.pos, name)
+		}
+		envelope, err := s.resolveEnvelope(*resp.Schema)
+		if err != nil {
+			return fmt.Errorf("%v: response %s: %w", unwrap.pos, name, err)
+		}
+		property, found := envelopeProperty(envelope, unwrap.property, s.resolveEnvelope)
+		if !found {
+			return fmt.Errorf("%v: response %s can't be unwrapped: its body has no %s property", unwrap.pos, name, unwrap.property)
+		}
+
+		// the property is copied, since the definition of the envelope may still be used elsewhere
+		body, err := cloneSchema(property)
+		if err != nil {
+			return err
+		}
+		delete(body.Extensions, "x-go-name")
+		for mediaType, example := range resp.Examples {
+			if unwrapped, ok := exampleProperty(example, unwrap.property); ok {
+				resp.Examples[mediaType] = unwrapped
+			} else {
+				// an example without the property documents the envelope, not the body
+				delete(resp.Examples, mediaType)
+			}
+		}
+		if example, ok := exampleProperty(envelope.Example, unwrap.property); ok && body.Example == nil {
+			if body.Ref.String() == "" {
+				body.Example = example
+			} else if len(resp.Examples) == 0 {
+				// the siblings of a $ref are ignored: the example goes to the response
+				resp.Examples = map[string]any{"application/json": example}
+			}
+		}
+		resp.Schema = &body
+		s.responses[name] = resp
+	}
+	return nil
+}
+
+// resolveEnvelope follows the references of a body to the definitions of the spec.
+func (s *specBuilder) resolveEnvelope(schema spec.Schema) (spec.Schema, error) {
+	seen := make(map[string]bool)
+	for schema.Ref.String() != "" {
+		ref := schema.Ref.String()
+		name, ok := definitionName(schema.Ref)
+		if !ok || seen[ref] {
+			return spec.Schema{}, fmt.Errorf("can't unwrap the body referring to %s", ref)
+		}
+		seen[ref] = true
+		definition, found := s.definitions[name]
+		if !found {
+			return spec.Schema{}, fmt.Errorf("can't unwrap the body referring to the unknown definition %s", name)
+		}
+		schema = definition
+	}
+	return schema, nil
+}
+
+// envelopeProperty finds a property of an envelope, in its own properties or in those of its allOf members.
+func envelopeProperty(envelope spec.Schema, name string, resolve func(spec.Schema) (spec.Schema, error)) (spec.Schema, bool) {
+	if property, found := envelope.Properties[name]; found {
+		return property, true
+	}
+	for _, member := range envelope.AllOf {
+		resolved, err := resolve(member)
+		if err != nil {
+			continue
+		}
+		if property, found := envelopeProperty(resolved, name, resolve); found {
+			return property, true
+		}
+	}
+	return spec.Schema{}, false
+}
+
+// exampleProperty is the value of a property of the example of an envelope.
+func exampleProperty(example any, name string) (any, bool) {
+	object, isObject := example.(map[string]any)
+	if !isObject {
+		return nil, false
+	}
+	value, found := object[name]
+	return value, found
+}
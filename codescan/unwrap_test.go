@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnwrapResponses(t *testing.T) {
+	const fixture = "github.com/3idey/codescan/fixtures/goparsing/unwrap"
+	doc, err := Run(&Options{Packages: []string{fixture}})
+	require.NoError(t, err)
+
+	t.Run("should document the property of the envelope", func(t *testing.T) {
+		resp := doc.Responses["petResponse"]
+		require.NotNil(t, resp.Schema)
+		ref := resp.Schema.Ref
+		assert.Equal(t, "#/definitions/Pet", ref.String())
+		assert.Equal(t, "PetResponse is a pet, without its envelope.", resp.Description)
+		assert.Contains(t, doc.Definitions, "Owner", "the definitions of the property are pulled in")
+	})
+
+	t.Run("should keep the envelope of the other responses", func(t *testing.T) {
+		resp := doc.Responses["petEnvelope"]
+		require.NotNil(t, resp.Schema)
+		ref := resp.Schema.Ref
+		assert.Equal(t, "#/definitions/Envelope", ref.String())
+		assert.Contains(t, doc.Definitions["Envelope"].Properties, "data")
+	})
+
+	t.Run("should re-root the examples under the property", func(t *testing.T) {
+		resp := doc.Responses["petResponse"]
+		assert.Equal(t, map[string]any{
+			"application/json": map[string]any{"name": "Rex", "owner": map[string]any{"name": "Ana"}},
+		}, resp.Examples)
+		assert.Empty(t, doc.Responses["petEnvelope"].Examples)
+	})
+
+	t.Run("should unwrap the properties of embedded envelopes", func(t *testing.T) {
+		resp := doc.Responses["petsResponse"]
+		require.NotNil(t, resp.Schema)
+		assert.Equal(t, spec.StringOrArray{"array"}, resp.Schema.Type)
+		require.NotNil(t, resp.Schema.Items)
+		ref := resp.Schema.Items.Schema.Ref
+		assert.Equal(t, "#/definitions/Pet", ref.String())
+		assert.NotContains(t, resp.Schema.Extensions, "x-go-name")
+	})
+
+	t.Run("should emit the unwrapped responses of an audience only", func(t *testing.T) {
+		doc, err := Run(&Options{Packages: []string{fixture}, Audience: []string{"partner"}})
+		require.NoError(t, err)
+		assert.NotContains(t, doc.Responses, "petEnvelope")
+		assert.NotContains(t, doc.Definitions, "Envelope")
+		assert.NotContains(t, doc.Definitions, "Meta")
+		assert.Contains(t, doc.Definitions, "Pet")
+	})
+
+	t.Run("should fail on an unknown property", func(t *testing.T) {
+		_, err := Run(&Options{Packages: []string{fixture + "/invalid"}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "response nameResponse can't be unwrapped: its body has no payload property")
+	})
+}
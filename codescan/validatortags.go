@@ -0,0 +1,252 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"encoding/json"
+	"go/ast"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// validatorFormats are the formats of the string rules of go-playground/validator.
+var validatorFormats = map[string]string{
+	"email":    "email",
+	"uuid":     "uuid",
+	"uuid3":    "uuid3",
+	"uuid4":    "uuid4",
+	"uuid5":    "uuid5",
+	"uri":      "uri",
+	"url":      "uri",
+	"hostname": "hostname",
+	"ipv4":     "ipv4",
+	"ipv6":     "ipv6",
+	"mac":      "mac",
+	"isbn10":   "isbn10",
+	"isbn13":   "isbn13",
+}
+
+// validatorTarget is a value the rules of a validator tag apply to: a schema, a parameter or its items.
+type validatorTarget struct {
+	// typ is the swagger type of the value, e.g. string
+	typ         string
+	hasFormat   bool
+	hasEnum     bool
+	validations validationBuilder
+	setFormat   func(string)
+	// setRequired is nil for the items
+	setRequired func()
+	// elements are the target of the rules after dive: of the items, or of the values of a map, when any
+	elements func() *validatorTarget
+}
+
+// validatorRules are the rules of the validator tags of a field, by tag key, e.g. the rules of
+// validate:"required,max=64", in the order of the keys of Options.ValidatorTags.
+func validatorRules(field *ast.Field, keys []string) [][]string {
+	if len(keys) == 0 || field.Tag == nil {
+		return nil
+	}
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return nil
+	}
+	var rules [][]string
+	for _, key := range keys {
+		if value, ok := reflect.StructTag(tag).Lookup(key); ok && value != "" && value != "-" {
+			rules = append(rules, strings.Split(value, ","))
+		}
+	}
+	return rules
+}
+
+// applyValidatorRules sets the constraints of the rules of a validator tag on a target, before the comments
+// of the field are parsed, so that the annotations win. The rules which don't map to a constraint are
+// ignored, e.g. alphanum, as are the alternatives, e.g. email|url, and the rules of the keys of maps.
+func applyValidatorRules(target *validatorTarget, rules []string) {
+	for i := 0; i < len(rules); i++ {
+		rule, param, _ := strings.Cut(rules[i], "=")
+		if strings.Contains(rules[i], "|") {
+			continue
+		}
+		switch rule {
+		case "dive":
+			if target.elements == nil {
+				return
+			}
+			if elements := target.elements(); elements != nil {
+				applyValidatorRules(elements, rules[i+1:])
+			}
+			return
+		case "keys":
+			// the rules of the keys of a map, up to endkeys, have no schema
+			for i < len(rules) && rules[i] != "endkeys" {
+				i++
+			}
+		case "required":
+			if target.setRequired != nil {
+				target.setRequired()
+			}
+		case "min", "gte":
+			target.setBound(param, 0, false)
+		case "max", "lte":
+			target.setBound(param, 0, true)
+		case "gt":
+			target.setBound(param, 1, false)
+		case "lt":
+			target.setBound(param, 1, true)
+		case "len":
+			target.setBound(param, 0, false)
+			target.setBound(param, 0, true)
+		case "oneof":
+			target.setOneOf(param)
+		case "unique":
+			if target.typ == "array" {
+				target.validations.SetUnique(true)
+			}
+		default:
+			if format, known := validatorFormats[rule]; known && target.typ == "string" && !target.hasFormat {
+				target.setFormat(format)
+				target.hasFormat = true
+			}
+		}
+	}
+}
+
+// setBound sets a minimum, or a maximum, of a number, or of the length of a string or an array, depending on
+// the type. An exclusive bound, of gt or lt, is exclusive for numbers and off by one for the lengths.
+func (t *validatorTarget) setBound(param string, exclusive int64, upper bool) {
+	value, err := strconv.ParseFloat(param, 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return
+	}
+	length := int64(value)
+	if upper {
+		length -= exclusive
+	} else {
+		length += exclusive
+	}
+	switch t.typ {
+	case "integer", "number":
+		if upper {
+			t.validations.SetMaximum(value, exclusive != 0)
+		} else {
+			t.validations.SetMinimum(value, exclusive != 0)
+		}
+	case "string":
+		if upper {
+			t.validations.SetMaxLength(max(length, 0))
+		} else {
+			t.validations.SetMinLength(max(length, 0))
+		}
+	case "array":
+		if upper {
+			t.validations.SetMaxItems(max(length, 0))
+		} else {
+			t.validations.SetMinItems(max(length, 0))
+		}
+	}
+}
+
+// setOneOf sets the enum of a oneof rule, with values separated by spaces, or quoted, e.g. oneof='a b' c.
+func (t *validatorTarget) setOneOf(param string) {
+	if t.hasEnum || !slices.Contains([]string{"string", "integer", "number"}, t.typ) {
+		return
+	}
+	var values []string
+	for param = strings.TrimSpace(param); param != ""; param = strings.TrimSpace(param) {
+		if quoted, ok := strings.CutPrefix(param, "'"); ok {
+			if value, rest, closed := strings.Cut(quoted, "'"); closed {
+				values, param = append(values, value), rest
+				continue
+			}
+		}
+		value, rest, _ := strings.Cut(param, " ")
+		values, param = append(values, value), rest
+	}
+	if len(values) == 0 {
+		return
+	}
+	encoded, err := json.Marshal(values)
+	if err != nil {
+		return
+	}
+	t.validations.SetEnum(string(encoded))
+	t.hasEnum = true
+}
+
+// schemaValidatorTarget is the validatorTarget of the schema of a property, which the rules require in its
+// object. A schema with a $ref only takes the required rule.
+func schemaValidatorTarget(schema *spec.Schema, object *spec.Schema, name string) *validatorTarget {
+	target := validatorTarget{
+		hasFormat:   schema.Format != "",
+		hasEnum:     len(schema.Enum) > 0,
+		validations: schemaValidations{schema},
+		setFormat:   func(format string) { schema.Format = format },
+	}
+	if schema.Ref.String() == "" && len(schema.Type) > 0 {
+		target.typ = schema.Type[0]
+	}
+	if object != nil {
+		target.setRequired = func() {
+			if !slices.Contains(object.Required, name) {
+				object.Required = append(object.Required, name)
+			}
+		}
+	}
+	target.elements = func() *validatorTarget {
+		switch {
+		case schema.Items != nil && schema.Items.Schema != nil:
+			return schemaValidatorTarget(schema.Items.Schema, nil, "")
+		case schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil:
+			return schemaValidatorTarget(schema.AdditionalProperties.Schema, nil, "")
+		default:
+			return nil
+		}
+	}
+	return &target
+}
+
+// paramValidatorTarget is the validatorTarget of a parameter which isn't in the body.
+func paramValidatorTarget(param *spec.Parameter) *validatorTarget {
+	target := validatorTarget{
+		typ:         param.Type,
+		hasFormat:   param.Format != "",
+		hasEnum:     len(param.Enum) > 0,
+		validations: paramValidations{param},
+		setFormat:   func(format string) { param.Format = format },
+		setRequired: func() { param.Required = true },
+	}
+	if param.Ref.String() != "" {
+		target.typ = ""
+	}
+	target.elements = func() *validatorTarget {
+		if param.Items == nil {
+			return nil
+		}
+		return itemsValidatorTarget(param.Items)
+	}
+	return &target
+}
+
+func itemsValidatorTarget(items *spec.Items) *validatorTarget {
+	target := validatorTarget{
+		typ:         items.Type,
+		hasFormat:   items.Format != "",
+		hasEnum:     len(items.Enum) > 0,
+		validations: itemsValidations{items},
+		setFormat:   func(format string) { items.Format = format },
+	}
+	target.elements = func() *validatorTarget {
+		if items.Items == nil {
+			return nil
+		}
+		return itemsValidatorTarget(items.Items)
+	}
+	return &target
+}
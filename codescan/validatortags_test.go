@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatorTags(t *testing.T) {
+	scan := func(t *testing.T, tags ...string) *spec.Swagger {
+		t.Helper()
+		doc, err := Run(&Options{
+			Packages:      []string{"github.com/3idey/codescan/fixtures/goparsing/validatortags"},
+			ScanModels:    true,
+			ValidatorTags: tags,
+		})
+		require.NoError(t, err)
+		return doc
+	}
+	doc := scan(t, "validate", "binding")
+	user := doc.Definitions["User"]
+	property := func(name string) spec.Schema {
+		t.Helper()
+		schema, found := user.Properties[name]
+		require.True(t, found, name)
+		return schema
+	}
+	int64p := func(v int64) *int64 { return &v }
+	float64p := func(v float64) *float64 { return &v }
+
+	t.Run("should constrain the strings", func(t *testing.T) {
+		assert.Equal(t, int64p(1), property("name").MinLength)
+		assert.Equal(t, int64p(64), property("name").MaxLength)
+		assert.Equal(t, int64p(8), property("code").MinLength, "len")
+		assert.Equal(t, int64p(8), property("code").MaxLength, "len")
+		assert.Equal(t, []any{"admin", "power user", "guest"}, property("role").Enum)
+		assert.Equal(t, "email", property("email").Format)
+		assert.Equal(t, "uuid4", property("id").Format)
+		assert.Empty(t, property("homepage").Format, "the alternatives are ignored")
+	})
+
+	t.Run("should constrain the pointers as their values", func(t *testing.T) {
+		assert.Equal(t, int64p(3), property("nickname").MinLength, "gt is exclusive")
+		assert.Equal(t, int64p(19), property("nickname").MaxLength, "lt is exclusive")
+		score := property("score")
+		assert.Equal(t, float64p(0), score.Minimum)
+		assert.True(t, score.ExclusiveMinimum)
+		assert.Equal(t, float64p(1), score.Maximum)
+		assert.True(t, score.ExclusiveMaximum)
+	})
+
+	t.Run("should constrain the numbers", func(t *testing.T) {
+		age := property("age")
+		assert.Equal(t, float64p(18), age.Minimum)
+		assert.False(t, age.ExclusiveMinimum)
+		assert.Equal(t, float64p(130), age.Maximum)
+		assert.Equal(t, []any{1, 2, 3}, property("rating").Enum)
+	})
+
+	t.Run("should constrain the slices and their items", func(t *testing.T) {
+		tags := property("tags")
+		assert.Equal(t, int64p(1), tags.MinItems)
+		assert.Equal(t, int64p(10), tags.MaxItems)
+		assert.True(t, tags.UniqueItems)
+		require.NotNil(t, tags.Items)
+		assert.Equal(t, int64p(2), tags.Items.Schema.MinLength)
+		assert.Equal(t, int64p(32), tags.Items.Schema.MaxLength)
+
+		emails := property("emails")
+		assert.Nil(t, emails.MaxItems)
+		assert.Equal(t, int64p(2), emails.Items.Schema.MinItems)
+		assert.Equal(t, "email", emails.Items.Schema.Items.Schema.Format)
+
+		labels := property("labels")
+		assert.Equal(t, int64p(16), labels.AdditionalProperties.Schema.MaxLength, "the rules of the keys are skipped")
+	})
+
+	t.Run("should only require the refs", func(t *testing.T) {
+		assert.Contains(t, user.Required, "address")
+		address := property("address")
+		assert.Equal(t, "#/definitions/Address", address.Ref.String())
+		assert.Nil(t, address.MinLength)
+	})
+
+	t.Run("should prefer the annotations", func(t *testing.T) {
+		bio := property("bio")
+		assert.Equal(t, int64p(5), bio.MinLength)
+		assert.Equal(t, int64p(100), bio.MaxLength)
+		assert.NotContains(t, user.Required, "bio")
+		assert.Equal(t, []any{"low", "high"}, property("level").Enum, "the enum of the type is kept")
+	})
+
+	t.Run("should ignore the unknown rules", func(t *testing.T) {
+		unknown := property("unknown")
+		assert.Equal(t, spec.StringOrArray{"string"}, unknown.Type)
+		assert.Empty(t, unknown.Format)
+		assert.Empty(t, unknown.Pattern)
+		assert.Equal(t, []string{"name", "email", "tags", "address", "bound"}, user.Required)
+	})
+
+	t.Run("should constrain the parameters", func(t *testing.T) {
+		params := make(map[string]spec.Parameter)
+		for _, param := range doc.Paths.Paths["/users"].Get.Parameters {
+			params[param.Name] = param
+		}
+		limit := params["limit"]
+		assert.True(t, limit.Required)
+		assert.Equal(t, float64p(1), limit.Minimum)
+		assert.Equal(t, float64p(100), limit.Maximum)
+		assert.Equal(t, []any{"name", "age"}, params["sort"].Enum)
+		assert.Equal(t, int64p(5), params["ids"].MaxItems)
+		assert.Equal(t, "uuid", params["ids"].Items.Format)
+		assert.True(t, params["filter"].Required)
+	})
+
+	t.Run("should be off by default", func(t *testing.T) {
+		plain := scan(t).Definitions["User"]
+		assert.Empty(t, plain.Required)
+		assert.Nil(t, plain.Properties["name"].MaxLength)
+	})
+
+	t.Run("should read the keys of the options only", func(t *testing.T) {
+		bound := scan(t, "validate").Definitions["User"]
+		assert.NotContains(t, bound.Required, "bound")
+		assert.Nil(t, bound.Properties["bound"].MaxLength)
+	})
+}
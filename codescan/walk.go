@@ -0,0 +1,189 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package codescan
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+const (
+	definitionsPrefix = "#/definitions/"
+	parametersPrefix  = "#/parameters/"
+	responsesPrefix   = "#/responses/"
+)
+
+// schemaVisitor is called for every schema found in a spec, with the JSON pointer locating it.
+//
+// Visitors may modify the schema in place: changes are written back into the spec.
+type schemaVisitor func(schema *spec.Schema, location string)
+
+// walkSpecSchemas visits all the schemas of a spec, depth-first and in a deterministic order.
+func walkSpecSchemas(doc *spec.Swagger, visit schemaVisitor) {
+	if doc == nil {
+		return
+	}
+
+	for _, name := range sortedKeys(doc.Definitions) {
+		sch := doc.Definitions[name]
+		walkSchema(&sch, definitionsPrefix+escapePointer(name), visit)
+		doc.Definitions[name] = sch
+	}
+
+	for _, name := range sortedKeys(doc.Parameters) {
+		param := doc.Parameters[name]
+		if param.Schema != nil {
+			walkSchema(param.Schema, "#/parameters/"+escapePointer(name)+"/schema", visit)
+		}
+	}
+
+	for _, name := range sortedKeys(doc.Responses) {
+		resp := doc.Responses[name]
+		walkResponse(&resp, "#/responses/"+escapePointer(name), visit)
+	}
+
+	if doc.Paths == nil {
+		return
+	}
+
+	for _, pth := range sortedKeys(doc.Paths.Paths) {
+		pathItem := doc.Paths.Paths[pth]
+		location := "#/paths/" + escapePointer(pth)
+		walkParams(pathItem.Parameters, location+"/parameters", visit)
+
+		for method, op := range pathItemOperations(&pathItem) {
+			opLocation := location + "/" + method
+			walkParams(op.Parameters, opLocation+"/parameters", visit)
+			if op.Responses == nil {
+				continue
+			}
+			if op.Responses.Default != nil {
+				walkResponse(op.Responses.Default, opLocation+"/responses/default", visit)
+			}
+			for _, code := range sortedKeys(op.Responses.StatusCodeResponses) {
+				resp := op.Responses.StatusCodeResponses[code]
+				walkResponse(&resp, opLocation+"/responses/"+strconv.Itoa(code), visit)
+			}
+		}
+	}
+}
+
+func walkParams(params []spec.Parameter, location string, visit schemaVisitor) {
+	for i := range params {
+		if params[i].Schema != nil {
+			walkSchema(params[i].Schema, location+"/"+strconv.Itoa(i)+"/schema", visit)
+		}
+	}
+}
+
+// walkResponse visits the schema of a response, and the schemas of its media types, see xContentSchemas.
+func walkResponse(resp *spec.Response, location string, visit schemaVisitor) {
+	if resp.Schema != nil {
+		walkSchema(resp.Schema, location+"/schema", visit)
+	}
+	schemas := contentSchemas(resp)
+	for _, mediaType := range sortedKeys(schemas) {
+		walkSchema(schemas[mediaType], location+"/"+xContentSchemas+"/"+escapePointer(mediaType), visit)
+	}
+}
+
+func walkSchema(sch *spec.Schema, location string, visit schemaVisitor) {
+	visit(sch, location)
+
+	for _, name := range sortedKeys(sch.Properties) {
+		prop := sch.Properties[name]
+		walkSchema(&prop, location+"/properties/"+escapePointer(name), visit)
+		sch.Properties[name] = prop
+	}
+
+	for _, name := range sortedKeys(sch.PatternProperties) {
+		prop := sch.PatternProperties[name]
+		walkSchema(&prop, location+"/patternProperties/"+escapePointer(name), visit)
+		sch.PatternProperties[name] = prop
+	}
+
+	if sch.Items != nil {
+		if sch.Items.Schema != nil {
+			walkSchema(sch.Items.Schema, location+"/items", visit)
+		}
+		for i := range sch.Items.Schemas {
+			walkSchema(&sch.Items.Schemas[i], location+"/items/"+strconv.Itoa(i), visit)
+		}
+	}
+
+	if sch.AdditionalProperties != nil && sch.AdditionalProperties.Schema != nil {
+		walkSchema(sch.AdditionalProperties.Schema, location+"/additionalProperties", visit)
+	}
+
+	if sch.AdditionalItems != nil && sch.AdditionalItems.Schema != nil {
+		walkSchema(sch.AdditionalItems.Schema, location+"/additionalItems", visit)
+	}
+
+	for i := range sch.AllOf {
+		walkSchema(&sch.AllOf[i], location+"/allOf/"+strconv.Itoa(i), visit)
+	}
+
+	for i := range sch.AnyOf {
+		walkSchema(&sch.AnyOf[i], location+"/anyOf/"+strconv.Itoa(i), visit)
+	}
+
+	for i := range sch.OneOf {
+		walkSchema(&sch.OneOf[i], location+"/oneOf/"+strconv.Itoa(i), visit)
+	}
+
+	if sch.Not != nil {
+		walkSchema(sch.Not, location+"/not", visit)
+	}
+}
+
+// pathItemOperations yields the operations defined on a path item, in a stable order.
+func pathItemOperations(pathItem *spec.PathItem) func(yield func(string, *spec.Operation) bool) {
+	return func(yield func(string, *spec.Operation) bool) {
+		for _, op := range []struct {
+			method string
+			op     *spec.Operation
+		}{
+			{"get", pathItem.Get},
+			{"put", pathItem.Put},
+			{"post", pathItem.Post},
+			{"delete", pathItem.Delete},
+			{"options", pathItem.Options},
+			{"head", pathItem.Head},
+			{"patch", pathItem.Patch},
+		} {
+			if op.op == nil {
+				continue
+			}
+			if !yield(op.method, op.op) {
+				return
+			}
+		}
+	}
+}
+
+// definitionName returns the name of the local definition a $ref points to, unescaped, e.g. a/b for
+// #/definitions/a~1b.
+func definitionName(ref spec.Ref) (string, bool) {
+	name, ok := strings.CutPrefix(ref.String(), definitionsPrefix)
+	return unescapePointer(name), ok
+}
+
+// pointerEscaper escapes the tokens of the JSON pointers, see escapePointer.
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+func escapePointer(token string) string {
+	return pointerEscaper.Replace(token)
+}
+
+func sortedKeys[K string | int, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
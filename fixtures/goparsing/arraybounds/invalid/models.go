@@ -0,0 +1,17 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package invalid
+
+import "github.com/go-openapi/strfmt"
+
+// Label limits the items of a string.
+//
+// swagger:model
+type Label struct {
+	// Max Items: 3
+	Name string `json:"name"`
+
+	// unique: true
+	Email strfmt.Email `json:"email"`
+}
@@ -0,0 +1,8 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+// Package limits holds the limits the handlers enforce.
+package limits
+
+// MaxTags is the most tags of an order.
+const MaxTags = 8
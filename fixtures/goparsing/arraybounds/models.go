@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package arraybounds
+
+import "github.com/3idey/codescan/fixtures/goparsing/arraybounds/limits"
+
+// MaxBatchSize is the most orders of a batch the handler accepts.
+const MaxBatchSize = 100
+
+// Checksum is a SHA-1 sum.
+type Checksum [20]uint8
+
+// Batch of orders.
+//
+// swagger:model
+type Batch struct {
+	// the orders of the batch
+	//
+	// Min Items: 1
+	// Max Items: {MaxBatchSize}
+	// Unique Items: true
+	Orders []Order `json:"orders"`
+
+	// the tags of the batch
+	//
+	// Max Items: {limits.MaxTags}
+	// Items.Max Items: 2
+	Tags [][]string `json:"tags"`
+
+	// a point of the batch
+	Location [2]float64 `json:"location"`
+
+	Checksum Checksum `json:"checksum"`
+}
+
+// Order of a batch.
+//
+// swagger:model
+type Order struct {
+	ID int64 `json:"id"`
+}
+
+// CreateBatchParams creates a batch.
+//
+// swagger:parameters createBatch
+type CreateBatchParams struct {
+	// the ids of the orders
+	//
+	// in: query
+	// Max Items: {MaxBatchSize}
+	// Unique Items: true
+	IDs []int64 `json:"ids"`
+
+	// in: query
+	Window [2]int64 `json:"window"`
+}
+
+// CreateBatch creates a batch.
+//
+// swagger:route POST /batches batches createBatch
+//
+// Responses:
+//
+//	201: Batch
+func CreateBatch() {}
+
+// checkTags enforces the limit documented by the tags of a batch.
+func checkTags(batch Batch) bool {
+	return len(batch.Tags) <= limits.MaxTags
+}
@@ -0,0 +1,96 @@
+// Package audience is the fixture of the filtering of operations by audience.
+//
+//	SecurityDefinitions:
+//	api_key:
+//	  type: apiKey
+//	  name: X-API-Key
+//	  in: header
+//	admin_key:
+//	  type: apiKey
+//	  name: X-Admin-Key
+//	  in: header
+//
+// swagger:meta
+package audience
+
+// A Pet of the store.
+//
+// swagger:model
+type Pet struct {
+	Name  string `json:"name"`
+	Owner Owner  `json:"owner"`
+}
+
+// Owner of a pet.
+type Owner struct {
+	Name string `json:"name"`
+}
+
+// AuditEntry records a change of the store.
+type AuditEntry struct {
+	Pet    Pet    `json:"pet"`
+	Author string `json:"author"`
+}
+
+// Unused is a model no operation uses.
+//
+// swagger:model
+type Unused struct {
+	Value string `json:"value"`
+}
+
+// PetsResponse holds pets.
+//
+// swagger:response petsResponse
+type PetsResponse struct {
+	// in: body
+	Body []Pet
+}
+
+// AuditResponse holds the audit of the store.
+//
+// swagger:response auditResponse
+type AuditResponse struct {
+	// in: body
+	Body []AuditEntry
+}
+
+// swagger:route GET /pets pets listPets
+//
+// Lists the pets.
+//
+// Audience: public
+//
+// Security:
+//   api_key:
+//
+// Responses:
+//   200: petsResponse
+
+// swagger:route GET /pets/{id} pets getPet
+//
+// Gets a pet, without audience.
+//
+// Responses:
+//   200: description: the pet
+
+// swagger:route GET /audit admin getAudit
+//
+// Gets the audit of the store.
+//
+// Audience: internal
+//
+// Security:
+//   admin_key:
+//
+// Responses:
+//   200: auditResponse
+
+// swagger:route POST /pets/{id}/shares pets sharePet
+//
+// Shares a pet with a partner.
+//
+// Audience: internal, partner
+//
+// Responses:
+//   204: description: shared
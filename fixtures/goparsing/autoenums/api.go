@@ -0,0 +1,28 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package autoenums
+
+import "github.com/3idey/codescan/fixtures/goparsing/autoenums/palette"
+
+// ListTasks lists the tasks.
+//
+// swagger:route GET /tasks tasks listTasks
+//
+// Responses:
+//
+//	200: body:[]Task the tasks
+func ListTasks() {}
+
+// swagger:parameters listTasks
+type ListTasksParams struct {
+	// in: query
+	Priority Priority `json:"priority"`
+
+	// in: query
+	Color palette.Color `json:"color"`
+
+	// in: query
+	// enum: expert
+	Level Level `json:"level"`
+}
@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+// Package autoenums is the fixture of the enums discovered from the const blocks.
+package autoenums
+
+import "github.com/3idey/codescan/fixtures/goparsing/autoenums/palette"
+
+// Priority of a task.
+type Priority int
+
+const (
+	Low Priority = iota + 1
+	Medium
+	High
+)
+
+// Level has a constant which isn't a Level.
+//
+// swagger:model Level
+type Level string
+
+const (
+	Beginner Level = "beginner"
+	Expert   Level = "expert"
+
+	Unrelated = "unrelated"
+)
+
+// Mode opts out of the discovery.
+//
+// swagger:enum:ignore
+type Mode string
+
+const (
+	ModeFast Mode = "fast"
+	ModeSafe Mode = "safe"
+)
+
+// Task is a model with enums discovered from the const blocks.
+//
+// swagger:model Task
+type Task struct {
+	Priority Priority `json:"priority"`
+
+	Level Level `json:"level"`
+
+	Mode Mode `json:"mode"`
+
+	Color palette.Color `json:"color"`
+
+	Shade palette.Shade `json:"shade"`
+
+	// enum: red
+	Accent palette.Color `json:"accent"`
+}
@@ -0,0 +1,26 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+// Package palette declares an enum imported by the models.
+package palette
+
+// Color of a paint.
+type Color string
+
+const (
+	// Red is the default color.
+	Red Color = "red"
+	// Blue is the color of the sky.
+	Blue Color = "blue"
+)
+
+// Green is declared on its own.
+const Green Color = "green"
+
+// Shade is an alias of Color, its constants are Color constants.
+type Shade = Color
+
+// Teal is declared with the alias.
+const Teal Shade = "teal"
+
+const unexported Color = "secret"
@@ -0,0 +1,60 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+// Package binding is the fixture of the binding extensions of the parameters.
+package binding
+
+import "time"
+
+// Order is the body of an order.
+//
+// swagger:model Order
+type Order struct {
+	Quantity int `json:"quantity"`
+}
+
+// TraceParams carry the trace of a request.
+type TraceParams struct {
+	// in: header
+	TraceID string `json:"X-Trace-Id"`
+}
+
+// swagger:parameters updateOrder
+type UpdateOrderParams struct {
+	TraceParams
+
+	// in: path
+	ID int64 `json:"id"`
+
+	// in: query
+	Since *time.Time `json:"since"`
+
+	// in: query
+	Tags []string `json:"tags"`
+
+	// in: body
+	Order *Order `json:"order"`
+}
+
+// swagger:route PUT /orders/{id} orders updateOrder
+//
+// Updates an order.
+//
+// Responses:
+//   200: description: the order is updated
+
+// swagger:parameters attachReceipt
+type AttachReceiptParams struct {
+	// in: formData
+	Note string `json:"note"`
+}
+
+// swagger:route POST /receipts orders attachReceipt
+//
+// Attaches a receipt.
+//
+// Consumes:
+// - multipart/form-data
+//
+// Responses:
+//   201: description: the receipt is attached
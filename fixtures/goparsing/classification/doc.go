@@ -74,6 +74,23 @@
 //	      bla1: foo1
 //	      bla2: foo2
 //	    flow: accessCode
+//	oauth:
+//	    type: oauth2
+//	    authorizationUrl: /oauth/auth
+//	    tokenUrl: /oauth/token
+//	    scopes:
+//	      read: read the orders
+//	      write: write the orders
+//	      orders:read: read the orders
+//	      https://www.googleapis.com/auth/userinfo.email: see the email address of the user
+//	    flow: accessCode
+//	petstore_auth:
+//	    type: oauth2
+//	    authorizationUrl: /petstore/auth
+//	    scopes:
+//	      read:pets: read the pets
+//	      write:pets: modify the pets
+//	    flow: implicit
 //
 // swagger:meta
 package classification
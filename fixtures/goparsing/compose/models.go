@@ -0,0 +1,68 @@
+// Package compose provides fixtures for compositions mixing models, input spec definitions and properties.
+package compose
+
+// Pet is the base of the composed pets.
+//
+// swagger:model Pet
+type Pet struct {
+	// required: true
+	Name string `json:"name"`
+}
+
+// Tagged is a composition member which is not a model, composed with a definition of the input spec.
+//
+// swagger:allOfRef Audit
+type Tagged struct {
+	Tags []string `json:"tags"`
+}
+
+// Dog composes a model, a definition of the input spec and its own properties.
+//
+// swagger:allOfRef Audit class:dog
+// swagger:model Dog
+type Dog struct {
+	// swagger:allOf
+	Pet
+
+	// discriminator: true
+	// required: true
+	Kind string `json:"kind"`
+
+	Barks bool `json:"barks"`
+}
+
+// Show composes a member which is itself composed.
+//
+// swagger:model Show
+type Show struct {
+	// swagger:allOf
+	Tagged
+
+	// swagger:allOf
+	Dog
+
+	Venue string `json:"venue"`
+}
+
+// Owner has its promoted fields moved to their own member.
+//
+// swagger:allOfRef Pet
+// swagger:model Owner
+type Owner struct {
+	Address
+
+	Phone string `json:"phone"`
+}
+
+// Address is embedded without composition.
+type Address struct {
+	Street string `json:"street"`
+}
+
+// Broken is composed with a definition found nowhere.
+//
+// swagger:allOfRef Missing
+// swagger:model broken
+type Broken struct {
+	Name string `json:"name"`
+}
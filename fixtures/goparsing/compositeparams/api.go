@@ -0,0 +1,67 @@
+// Package compositeparams is the fixture of the simple parameters of composite types, e.g. maps and structs.
+package compositeparams
+
+import "time"
+
+// swagger:route GET /pets pets listPets
+//
+// Lists the pets.
+//
+// Responses:
+//   200: description: the pets
+
+// Filters is a filter DSL, e.g. {"color": ["black", "white"]}.
+type Filters map[string][]string
+
+// Range is a range of ages.
+type Range struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+}
+
+// Day is a day, marshaled as a string.
+//
+// swagger:strfmt date
+type Day struct {
+	Time time.Time
+}
+
+// MarshalJSON marshals a day as a string.
+func (d Day) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + d.Time.Format(time.DateOnly) + `"`), nil
+}
+
+// swagger:parameters listPets
+type listPetsParams struct {
+	// the filters
+	Filters Filters `json:"filters"`
+
+	// the labels of the pets
+	//
+	// Keys: team, env
+	// max length: 10
+	Labels map[string]string `json:"labels"`
+
+	// the scores of the pets
+	Scores *map[string]float64 `json:"scores"`
+
+	// the ages of the pets
+	Ages Range `json:"ages"`
+
+	// the ranges of the pets
+	Ranges []Range `json:"ranges"`
+
+	// the day of birth
+	Born Day `json:"born"`
+
+	// the flags of the request
+	//
+	// in: header
+	// keys: debug
+	Flags map[string]bool `json:"X-Flags"`
+
+	// the nested filters
+	//
+	// keys: owner
+	Nested map[string]map[string]int `json:"nested"`
+}
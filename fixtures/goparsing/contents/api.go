@@ -0,0 +1,35 @@
+// Package contents is the fixture of the bodies of the media types of the responses.
+package contents
+
+// A Report of the sales.
+type Report struct {
+	Total int64 `json:"total"`
+}
+
+// A ReportXML is the report, as marshaled to XML.
+type ReportXML struct {
+	Sales []int64 `json:"sales"`
+}
+
+// swagger:route GET /reports reports listReports
+//
+// Lists the reports.
+//
+// Produces:
+//   application/json
+//   text/csv
+//   application/xml
+//
+// Responses:
+//   200: body:Report body[text/csv]:string body[xml]:ReportXML the reports
+
+// swagger:route GET /reports/latest reports latestReport
+//
+// Returns the latest report.
+//
+// Produces:
+//   text/csv
+//   text/plain
+//
+// Responses:
+//   200: body[text/csv]:[]string body[text/plain]:string the latest report
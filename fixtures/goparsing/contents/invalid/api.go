@@ -0,0 +1,15 @@
+// Package invalid declares the bodies of media types with a swagger:response.
+package invalid
+
+// a report
+//
+// swagger:response reportResponse
+type reportResponse struct {
+	// in: body
+	Body string
+}
+
+// swagger:route GET /reports reports listReports
+//
+// Responses:
+//   200: reportResponse body[text/csv]:string
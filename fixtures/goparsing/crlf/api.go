@@ -0,0 +1,38 @@
+// Package crlf is the fixture of the Go files with CRLF line endings.
+package crlf
+
+// swagger:route GET /pets pets listPets
+//
+// Lists the pets.
+//
+// Responses:
+//   200: petsResponse
+
+/*
+A Pet is an animal of the store.
+
+It has a name and an age.
+
+swagger:model
+*/
+type Pet struct {
+	// The name of the pet.
+	//
+	// Max Length: 64
+	// Example: Rex
+	Name string `json:"name"`
+
+	// The age of the pet.
+	//
+	// Minimum: 0
+	// Maximum: 40
+	Age int `json:"age"`
+}
+
+// The pets of the store.
+//
+// swagger:response petsResponse
+type PetsResponse struct {
+	// in: body
+	Body []Pet
+}
@@ -0,0 +1,17 @@
+// Package customtag provides fixtures for x-go-custom-tag.
+package customtag
+
+// Account is persisted with gorm.
+//
+// swagger:model
+type Account struct {
+	// The owner of the account.
+	//
+	// Custom Tag: gorm:"column:user_id;index" validate:"required"
+	UserID int64 `json:"userId"`
+
+	// custom-tag: db:"display_name"
+	Name string `json:"name"`
+
+	Balance float64 `json:"balance"`
+}
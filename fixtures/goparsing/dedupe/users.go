@@ -0,0 +1,39 @@
+// Package dedupe provides fixtures for structurally identical models.
+package dedupe
+
+// UserSummary summarizes a user.
+//
+// swagger:model
+type UserSummary struct {
+	// required: true
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// UserBrief is the same as UserSummary, under another name.
+//
+// swagger:model
+type UserBrief struct {
+	Name string `json:"name"`
+	// The identifier of the user.
+	//
+	// required: true
+	ID int64 `json:"id"`
+}
+
+// UserLite has the same fields, but a different required list.
+//
+// swagger:model
+type UserLite struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// Team references users.
+//
+// swagger:model
+type Team struct {
+	Lead    UserBrief     `json:"lead"`
+	Members []UserSummary `json:"members"`
+	Owner   UserSummary   `json:"owner"`
+}
@@ -0,0 +1,75 @@
+// Package defaultresponse is the fixture of the response injected into every operation.
+package defaultresponse
+
+import (
+	"net/http"
+
+	"github.com/3idey/codescan/fixtures/goparsing/defaultresponse/httpkit"
+)
+
+// Handler serves the routes, recovering their panics.
+func Handler(mux *http.ServeMux) http.Handler {
+	return httpkit.Recover(mux)
+}
+
+// swagger:route GET /pets pets listPets
+//
+// Lists the pets.
+//
+// Responses:
+//   200: description: the pets
+func ListPets(http.ResponseWriter, *http.Request) {}
+
+// swagger:route POST /pets pets createPet
+//
+// Creates a pet.
+//
+// Responses:
+//   201: description: created
+//   500: description: the pet can't be stored
+func CreatePet(http.ResponseWriter, *http.Request) {}
+
+// swagger:route DELETE /pets/{id} pets deletePet
+//
+// Deletes a pet.
+//
+// Responses:
+//   204: description: deleted
+//   default: description: an error
+func DeletePet(http.ResponseWriter, *http.Request) {}
+
+// swagger:route GET /health health getHealth
+//
+// Tells whether the API is up, outside of the recovery middleware.
+//
+// No Default Response: true
+//
+// Responses:
+//   200: description: up
+func GetHealth(http.ResponseWriter, *http.Request) {}
+
+// GetPet gets a pet.
+func GetPet(http.ResponseWriter, *http.Request) {
+	// swagger:operation GET /pets/{id} pets getPet
+	//
+	// Gets a pet.
+	//
+	// ---
+	// responses:
+	//   "200":
+	//     description: the pet
+}
+
+// GetMetrics serves the metrics.
+func GetMetrics(http.ResponseWriter, *http.Request) {
+	// swagger:operation GET /metrics health getMetrics
+	//
+	// Serves the metrics.
+	//
+	// No Default Response: true
+	//
+	// ---
+	// responses:
+	//   "200":
+	//     description: the metrics
+}
@@ -0,0 +1,26 @@
+// Package httpkit recovers the panics of the handlers of the defaultresponse fixture.
+package httpkit
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// PanicError is the body of the responses of the recovered panics.
+type PanicError struct {
+	// the identifier of the request, to find it in the logs
+	RequestID string `json:"requestId"`
+}
+
+// Recover writes a 500 response when the next handler panics.
+func Recover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if recover() != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				_ = json.NewEncoder(w).Encode(PanicError{RequestID: r.Header.Get("X-Request-ID")})
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,54 @@
+// Package defaulttags is the fixture of the defaults of the struct tags.
+package defaulttags
+
+import "time"
+
+// swagger:route GET /config config getConfig
+//
+// Gets the config.
+//
+// Responses:
+//   200: body:Config
+
+// Level is a log level.
+type Level string
+
+// Limits are the limits of the server.
+type Limits struct {
+	RPS int `json:"rps"`
+}
+
+// Config is the config of the server.
+//
+// swagger:model
+type Config struct {
+	Port    int               `json:"port" default:"8080"`
+	Workers uint8             `json:"workers" default:"4"`
+	Ratio   float32           `json:"ratio" default:"0.1"`
+	Debug   bool              `json:"debug" default:"true"`
+	Name    string            `json:"name" default:"server"`
+	Hosts   []string          `json:"hosts" default:"a.example.com, b.example.com"`
+	Ports   []int             `json:"ports" default:"[80, 443]"`
+	Timeout time.Duration     `json:"timeout" default:"5s"`
+	Started time.Time         `json:"started" default:"2024-01-01T00:00:00Z"`
+	Level   Level             `json:"level" default:"info"`
+	Limits  Limits            `json:"limits" default:"{\"rps\": 10}"`
+	Labels  map[string]string `json:"labels" default:"{\"team\": \"core\"}"`
+	Retry   *struct {
+		Count int `json:"count"`
+	} `json:"retry" default:"{\"count\": 3}"`
+
+	// the idle timeout
+	//
+	// default: 30
+	Idle int `json:"idle" default:"10"`
+}
+
+// swagger:parameters getConfig
+type getConfigParams struct {
+	// the page
+	Page int `json:"page" default:"1"`
+
+	// the filters
+	Filters map[string]string `json:"filters" default:"{\"team\": \"core\"}"`
+}
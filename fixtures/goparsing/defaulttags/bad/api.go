@@ -0,0 +1,9 @@
+// Package bad is the fixture of a default tag which isn't a value of the type of its field.
+package bad
+
+// Config is the config of the server.
+//
+// swagger:model
+type Config struct {
+	Workers uint8 `json:"workers" default:"300"`
+}
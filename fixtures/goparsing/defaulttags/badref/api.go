@@ -0,0 +1,14 @@
+// Package badref is the fixture of an invalid default tag of a field referring to a definition.
+package badref
+
+// Limits are the limits of the server.
+type Limits struct {
+	RPS int `json:"rps"`
+}
+
+// Config is the config of the server.
+//
+// swagger:model
+type Config struct {
+	Limits Limits `json:"limits" default:"{rps: 10}"`
+}
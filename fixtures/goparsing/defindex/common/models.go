@@ -0,0 +1,20 @@
+// Package common provides the shared models of the definition index fixtures.
+package common
+
+// Money is an amount in a currency.
+//
+// swagger:model Money
+type Money struct {
+	// required: true
+	Amount int64 `json:"amount"`
+
+	// required: true
+	Currency string `json:"currency"`
+}
+
+// Rate is a conversion rate between currencies.
+//
+// swagger:model Rate
+type Rate struct {
+	Value float64 `json:"value"`
+}
@@ -0,0 +1,13 @@
+// Package service provides the models of the definition index fixtures which use the shared ones.
+package service
+
+import "github.com/3idey/codescan/fixtures/goparsing/defindex/common"
+
+// Invoice is billed to a customer.
+//
+// swagger:model Invoice
+type Invoice struct {
+	Total common.Money `json:"total"`
+
+	Rate *common.Rate `json:"rate,omitempty"`
+}
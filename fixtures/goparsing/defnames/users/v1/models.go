@@ -0,0 +1,15 @@
+// Package v1 is the first version of the users of the definition names fixture.
+package v1
+
+// User of the first version.
+//
+// swagger:model
+type User struct {
+	Name    string  `json:"name"`
+	Address Address `json:"address"`
+}
+
+// Address of a user.
+type Address struct {
+	City string `json:"city"`
+}
@@ -0,0 +1,16 @@
+// Package v2 is the second version of the users of the definition names fixture.
+package v2
+
+// User of the second version.
+//
+// swagger:model
+type User struct {
+	FullName string `json:"fullName"`
+}
+
+// Account keeps the name of the annotation.
+//
+// swagger:model LegacyAccount
+type Account struct {
+	ID string `json:"id"`
+}
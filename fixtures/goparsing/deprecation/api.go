@@ -0,0 +1,125 @@
+// Package deprecation is the fixture of the deprecations of the operations, parameters and fields.
+package deprecation
+
+// swagger:route GET /pets pets listPets
+//
+// Lists the pets.
+//
+// Deprecated: true
+//
+// Responses:
+//   200: petsResponse
+
+// swagger:operation GET /pets/{id} pets getPet
+//
+// Gets a pet.
+//
+// Deprecated: true
+//
+// ---
+// parameters:
+// - name: id
+//   in: path
+//   required: true
+//   type: string
+// responses:
+//   200:
+//     description: the pet
+//     schema:
+//       $ref: '#/definitions/Pet'
+
+// ListOwners lists the owners.
+//
+// Deprecated: the owners are the adoptions now.
+//
+// swagger:route GET /owners owners listOwners
+//
+// Responses:
+//   200: body:Owner
+func ListOwners() {}
+
+// swagger:route GET /adoptions adoptions listAdoptions
+//
+// Lists the adoptions.
+//
+// Responses:
+//   200: body:Adoption
+
+// Pet is a pet.
+//
+// swagger:model
+type Pet struct {
+	// The name.
+	Name string `json:"name"`
+
+	// The legacy tag, see name.
+	//
+	// Deprecated: true
+	Tag string `json:"tag,omitempty"`
+
+	// The owner of the pet.
+	//
+	// swagger:deprecated
+	Owner *Owner `json:"owner,omitempty"`
+
+	// The nickname.
+	//
+	// Deprecated: use name instead.
+	Nickname string `json:"nickname,omitempty"`
+
+	// The breed.
+	//
+	// Deprecated: false
+	Breed string `json:"breed,omitempty"`
+}
+
+// Owner is the owner of a pet.
+//
+// Deprecated: the pets have adoptions.
+//
+// swagger:model
+type Owner struct {
+	Name string `json:"name"`
+}
+
+// Adoption is the adoption of a pet.
+//
+// swagger:model
+type Adoption struct {
+	Pet   Pet   `json:"pet"`
+	Owner Owner `json:"owner"`
+}
+
+// ListPetsParams are the parameters of listPets.
+//
+// swagger:parameters listPets
+type ListPetsParams struct {
+	// The page size, see limit.
+	//
+	// in: query
+	// Deprecated: true
+	PageSize int `json:"page_size"`
+
+	// The number of pets.
+	//
+	// in: query
+	Limit int `json:"limit"`
+}
+
+// Page is a page of pets.
+type Page struct {
+	Pets []Pet `json:"pets"`
+
+	// The total of the pets, see the length of pets.
+	//
+	// Deprecated: true
+	Total int `json:"total"`
+}
+
+// PetsResponse is a page of pets.
+//
+// swagger:response petsResponse
+type PetsResponse struct {
+	// in: body
+	Body Page
+}
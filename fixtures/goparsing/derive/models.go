@@ -0,0 +1,32 @@
+// Package derive provides fixtures for derived models.
+package derive
+
+import "time"
+
+// User is a user of the API.
+//
+// swagger:model User
+type User struct {
+	// required: true
+	ID int64 `json:"id"`
+
+	// required: true
+	Email string `json:"email"`
+
+	// required: true
+	Name string `json:"name"`
+
+	// required: true
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// UpdateUserRequest updates some fields of a user.
+//
+// swagger:model updateUser deriveFrom:User allOptional omit:ID,CreatedAt
+type UpdateUserRequest User
+
+// swagger:model createUser deriveFrom:User omit:id,createdAt optional:name
+type createUser struct{}
+
+// swagger:model broken deriveFrom:Missing
+type broken struct{}
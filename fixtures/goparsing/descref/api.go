@@ -0,0 +1,61 @@
+// Package descref is the fixture of the descriptions next to $refs, see DescWithRef.
+package descref
+
+// Pet is a pet.
+//
+// swagger:model
+type Pet struct {
+	Name string `json:"name"`
+}
+
+// Base is the base of Owner.
+//
+// swagger:model
+type Base struct {
+	ID int64 `json:"id"`
+}
+
+// Owner owns pets.
+//
+// swagger:model
+type Owner struct {
+	// the base of the owner
+	//
+	// swagger:allOf
+	Base
+
+	// the favorite pet
+	Favorite Pet `json:"favorite"`
+
+	// the pets, by age
+	Pets []Pet `json:"pets"`
+
+	// the pets, by name
+	ByName map[string]Pet `json:"byName"`
+
+	// the pets, by shelter
+	Shelters [][]Pet `json:"shelters"`
+}
+
+// Kennel nests refs.
+//
+// swagger:model
+type Kennel struct {
+	// the boxes
+	Boxes []struct {
+		// the pet of the box
+		Pet Pet `json:"pet"`
+	} `json:"boxes"`
+
+	// the rooms, by name
+	Rooms map[string]struct {
+		// the pet of the room
+		Pet Pet `json:"pet"`
+	} `json:"rooms"`
+
+	// the yard
+	Yard struct {
+		// the pet of the yard
+		Pet Pet `json:"pet"`
+	} `json:"yard"`
+}
@@ -0,0 +1,27 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package descriptions
+
+// Shipment is a parcel on its way.
+//
+// swagger:model
+type Shipment struct {
+	// The weight of the parcel.
+	// Unit: grams
+	//
+	// required: true
+	// minimum: 1
+	Weight int `json:"weight"`
+
+	// The address of the recipient.
+	//
+	// \Required: when the parcel is not picked up.
+	// \Example: 221B Baker Street
+	Address string `json:"address"`
+
+	// The carrier of the parcel.
+	// Note: the carrier may change
+	// until the parcel is shipped.
+	Carrier string `json:"carrier"`
+}
@@ -0,0 +1,48 @@
+// Package events publishes the events of the shop.
+package events
+
+// Event is the envelope of the events.
+//
+// swagger:model
+type Event struct {
+	// the kind of event
+	//
+	// swagger:discriminator
+	Type string `json:"type"`
+
+	// when the event occurred
+	OccurredAt string `json:"occurredAt,omitempty"`
+
+	// the version of the payload
+	Version int `json:"version,omitempty"`
+}
+
+// UserCreated is published when a user signs up.
+//
+// swagger:discriminator user.created
+type UserCreated struct {
+	Event
+
+	// the name of the user
+	Name string `json:"name"`
+}
+
+// UserDeleted is published when a user is deleted.
+type UserDeleted struct {
+	*Event
+
+	// the schema refines that of the base
+	//
+	// enum: ["UserDeleted"]
+	Type string `json:"type"`
+
+	// the reason of the deletion
+	Reason string `json:"reason,omitempty"`
+}
+
+// EventLog is a log of events.
+//
+// swagger:model
+type EventLog struct {
+	Events []Event `json:"events"`
+}
@@ -0,0 +1,10 @@
+// Package invalid has a discriminator which isn't a string.
+package invalid
+
+// Shape is a shape.
+//
+// swagger:model
+type Shape struct {
+	// swagger:discriminator
+	Kind int `json:"kind"`
+}
@@ -0,0 +1,17 @@
+// Package orders declares the events of the orders.
+package orders
+
+import "github.com/3idey/codescan/fixtures/goparsing/discriminator/events"
+
+// OrderPlaced is published when an order is placed.
+//
+// swagger:discriminator order.placed
+type OrderPlaced struct {
+	events.Event
+
+	// the total of the order
+	Total float64 `json:"total"`
+
+	// the version of the order, rather than of the payload
+	Version string `json:"version,omitempty"`
+}
@@ -0,0 +1,24 @@
+//go:build codescan_docs
+
+package doconly
+
+// A user, with the documents it owns.
+//
+// swagger:response userResponse
+type userResponse struct {
+	// in: body
+	Body struct {
+		User
+		Documents []Document `json:"documents"`
+	}
+}
+
+// Document is only declared for the documentation.
+type Document struct {
+	Title string `json:"title"`
+}
+
+// The user was not found.
+//
+// swagger:response notFoundResponse
+type notFoundResponse struct{}
@@ -0,0 +1,18 @@
+// Package doconly provides fixtures for documentation-only files.
+package doconly
+
+// User is a user of the API.
+//
+// swagger:model User
+type User struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// swagger:route GET /users/{id} users getUser
+//
+// Gets a user.
+//
+// Responses:
+//   200: userResponse
+//   404: notFoundResponse
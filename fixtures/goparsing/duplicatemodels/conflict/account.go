@@ -0,0 +1,13 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package conflict
+
+// Account is annotated twice, after a bad merge.
+//
+// swagger:model account
+//
+// swagger:model Account
+type Account struct {
+	ID int64 `json:"id"`
+}
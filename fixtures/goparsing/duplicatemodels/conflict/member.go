@@ -0,0 +1,10 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package conflict
+
+// Member re-exports User, under a directive naming another type.
+//
+// swagger:model Member
+// Alias Of: Account
+type Member = User
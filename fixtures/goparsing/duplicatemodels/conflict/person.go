@@ -0,0 +1,9 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package conflict
+
+// Person is the former name of User, documented as another definition of the same type.
+//
+// swagger:model Person
+type Person = User
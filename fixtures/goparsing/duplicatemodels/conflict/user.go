@@ -0,0 +1,13 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+// Package conflict is the fixture of the Go types documented by several swagger:model annotations.
+package conflict
+
+// User is a user of the service.
+//
+// swagger:model user
+type User struct {
+	// the name of the user
+	Name string `json:"name"`
+}
@@ -0,0 +1,19 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+// Package reexport is the fixture of the models re-exported with Alias Of.
+package reexport
+
+import "github.com/3idey/codescan/fixtures/goparsing/duplicatemodels/reexport/models"
+
+// User re-exports the user of the models, under its former name.
+//
+// swagger:model Person
+// Alias Of: models.User
+type User = models.User
+
+// Owner re-exports User again, by its local name.
+//
+// swagger:model Owner
+// Alias Of: models.User
+type Owner = User
@@ -0,0 +1,13 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+// Package models declares the models re-exported by the package reexport.
+package models
+
+// User is a user of the service.
+//
+// swagger:model user
+type User struct {
+	// the name of the user
+	Name string `json:"name"`
+}
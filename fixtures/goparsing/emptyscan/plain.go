@@ -0,0 +1,7 @@
+// Package emptyscan has no annotations, for the detection of the empty scans.
+package emptyscan
+
+// Thing is not annotated.
+type Thing struct {
+	Name string `json:"name"`
+}
@@ -0,0 +1,9 @@
+// Package tagged has a single route, excluded by the tag rules of the test.
+package tagged
+
+// swagger:route GET /internal/health internal health
+//
+// Checks the health.
+//
+// Responses:
+//   200: description: healthy
@@ -0,0 +1,25 @@
+// Package emptyschema provides fixtures for properties without a schema.
+package emptyschema
+
+// Handler is a callback.
+type Handler func(string) error
+
+// Event is an event with documentation holes.
+//
+// swagger:model
+type Event struct {
+	Name string `json:"name"`
+
+	Payload interface{} `json:"payload"`
+
+	Amplitude complex128 `json:"amplitude"`
+
+	// swagger:strfmt uuid
+	Source interface{} `json:"source"`
+
+	Updates chan string `json:"updates"`
+
+	OnDone Handler `json:"onDone"`
+
+	Callback *func() `json:"callback"`
+}
@@ -0,0 +1,51 @@
+// Package enumstyle is the fixture of the enum extension styles.
+package enumstyle
+
+// Color of a paint.
+//
+// swagger:enum Color
+type Color string
+
+const (
+	ColorRed  Color = "red"
+	ColorBlue Color = "blue"
+)
+
+// Paint has enums from a swagger:enum and from a directive.
+//
+// swagger:model Paint
+type Paint struct {
+	Color Color `json:"color"`
+
+	// enum: matte,gloss
+	Finish string `json:"finish"`
+}
+
+// Pet is a discriminated base type.
+//
+// swagger:model Pet
+type Pet struct {
+	// discriminator: true
+	// required: true
+	Kind string `json:"kind"`
+}
+
+// Dog is a pet with a class.
+//
+// swagger:model Dog
+type Dog struct {
+	// swagger:allOf dog
+	Pet
+
+	Bark bool `json:"bark"`
+}
+
+// Cat is a pet named after its definition.
+//
+// swagger:model Cat
+type Cat struct {
+	// swagger:allOf
+	Pet
+
+	Lives int `json:"lives"`
+}
@@ -0,0 +1,56 @@
+// Package errorhelpers is the fixture of the error responses inferred from the calls of error helpers.
+package errorhelpers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/3idey/codescan/fixtures/goparsing/errorhelpers/httperr"
+)
+
+// swagger:route GET /users/{id} users getUser
+//
+// Gets a user.
+//
+// Responses:
+//   200: description: the user
+func GetUser(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		httperr.BadRequest(w, err.Error())
+		return
+	}
+	if id == 0 {
+		httperr.NotFound(w, "user not found")
+		return
+	}
+	if id < 0 {
+		http.Error(w, "forbidden user", http.StatusForbidden)
+	}
+}
+
+// swagger:route DELETE /users/{id} users deleteUser
+//
+// Deletes a user.
+//
+// Responses:
+//   204: description: deleted
+//   404: description: no such user
+func DeleteUser(w http.ResponseWriter, _ *http.Request) {
+	httperr.NotFound(w, "user not found")
+}
+
+// ListUsers lists the users.
+func ListUsers(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /users users listUsers
+	//
+	// Lists the users.
+	//
+	// ---
+	// responses:
+	//   "200":
+	//     description: the users
+	if r.URL.Query().Has("bad") {
+		httperr.BadRequest(w, "invalid query")
+	}
+}
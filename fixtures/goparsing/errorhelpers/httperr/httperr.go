@@ -0,0 +1,28 @@
+// Package httperr writes the error responses of the errorhelpers fixture.
+package httperr
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Problem is the body of the error responses.
+type Problem struct {
+	// the message of the error
+	Message string `json:"message"`
+}
+
+func write(w http.ResponseWriter, status int, message string) {
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(Problem{Message: message})
+}
+
+// NotFound writes a 404 response.
+func NotFound(w http.ResponseWriter, message string) {
+	write(w, http.StatusNotFound, message)
+}
+
+// BadRequest writes a 400 response.
+func BadRequest(w http.ResponseWriter, message string) {
+	write(w, http.StatusBadRequest, message)
+}
@@ -0,0 +1,46 @@
+// Package examples is the fixture of the examples of the struct tags and of the example files.
+package examples
+
+// A User of the store.
+//
+// swagger:model
+// swagger:example file:testdata/user.json
+type User struct {
+	// The ID of the user.
+	ID int64 `json:"id" example:"42"`
+
+	Name string `json:"name" example:"Jane Doe"`
+
+	Admin bool `json:"admin" example:"true"`
+
+	Score float64 `json:"score" example:"4.5"`
+
+	Settings map[string]any `json:"settings" example:"{\"theme\": \"dark\", \"size\": 12}"`
+
+	Roles []string `json:"roles" example:"[\"editor\", \"viewer\"]"`
+
+	// The nickname of the user.
+	//
+	// Example: Janie
+	Nickname string `json:"nickname" example:"JD"`
+
+	Address Address `json:"address" example:"{\"city\": \"Paris\"}"`
+
+	Notes string `json:"notes"`
+}
+
+// An Address of a user.
+//
+// swagger:model
+type Address struct {
+	City string `json:"city"`
+}
+
+// The users of the store.
+//
+// swagger:response usersResponse
+// swagger:example file:testdata/users.yaml
+type UsersResponse struct {
+	// in: body
+	Body []User
+}
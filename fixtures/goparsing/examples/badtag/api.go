@@ -0,0 +1,9 @@
+// Package badtag is the fixture of a malformed example tag.
+package badtag
+
+// A User of the store.
+//
+// swagger:model
+type User struct {
+	Settings map[string]string `json:"settings" example:"{theme: dark}"`
+}
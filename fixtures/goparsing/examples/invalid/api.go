@@ -0,0 +1,10 @@
+// Package invalid is the fixture of a malformed example file.
+package invalid
+
+// A User of the store.
+//
+// swagger:model
+// swagger:example file:testdata/user.json
+type User struct {
+	Name string `json:"name"`
+}
@@ -0,0 +1,49 @@
+// Package extensions is the fixture of the extensions blocks of the routes and of the meta.
+//
+//	Version: 1.0.0
+//
+//	Extensions:
+//	  x-api-team:
+//	    name: pets
+//	    channels: [slack, email]
+//	  x-api-level: 3
+//
+// swagger:meta
+package extensions
+
+// swagger:route GET /pets pets listPets
+//
+// Lists the pets.
+//
+//	Extensions:
+//	  x-rate-limit:
+//	    requests: 100
+//	    window: 1.5
+//	    burst: true
+//	  x-owners:
+//	    - name: pets
+//	      oncall: false
+//	    - ops
+//	  x-sunset: "2027-01-01"
+//
+//	Responses:
+//	  200: petsResponse
+
+// swagger:route GET /owners owners listOwners
+//
+// Lists the owners, with the key: value extensions of the older versions.
+//
+// Extensions:
+// x-internal: true
+// x-level: 2
+//
+// Responses:
+//   200: petsResponse
+
+// PetsResponse is a list of pets.
+//
+// swagger:response petsResponse
+type PetsResponse struct {
+	// in: body
+	Body []string
+}
@@ -0,0 +1,28 @@
+// Package invalid is the fixture of the errors of the extensions blocks.
+//
+//	Version: 1.0.0
+//
+//	Extensions:
+//	  x-api-level: 3
+//	  api-team: pets
+//
+// swagger:meta
+package invalid
+
+// swagger:route GET /pets pets listPets
+//
+//	Extensions:
+//	  x-rate-limit:
+//	    requests: 100
+//	  x-rate-limit: 200
+
+// swagger:route GET /owners owners listOwners
+//
+//	Extensions:
+//	  x-owners: pets: ops
+
+// swagger:route GET /adoptions adoptions listAdoptions
+//
+//	Extensions:
+//	    x-level: 2
+//	  x-team: pets
@@ -0,0 +1,16 @@
+// Package api is the hand-written part of the forceinclude fixture.
+package api
+
+// Status reports the health of the service.
+//
+// swagger:model Status
+type Status struct {
+	Healthy bool `json:"healthy"`
+}
+
+// swagger:route GET /status health getStatus
+//
+// Reports the health of the service.
+//
+// Responses:
+//   200: Status
@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+// Package generics is the fixture of the instantiations of generic types.
+package generics
+
+// User of the API.
+type User struct {
+	Name string `json:"name"`
+}
+
+// Order of a user.
+type Order struct {
+	Quantity int `json:"quantity"`
+}
+
+// Page is a page of items.
+type Page[T any] struct {
+	// The items of the page.
+	Items []T `json:"items"`
+	// The total number of items.
+	Total int `json:"total"`
+}
+
+// APIResponse wraps the data of a response.
+type APIResponse[T any] struct {
+	Data  T      `json:"data"`
+	Error string `json:"error,omitempty"`
+}
+
+// Pair has two type parameters.
+type Pair[K comparable, V any] struct {
+	Key   K `json:"key"`
+	Value V `json:"value"`
+}
+
+// Catalog references instantiations.
+//
+// swagger:model Catalog
+type Catalog struct {
+	Users  Page[User]         `json:"users"`
+	Orders *Page[*Order]      `json:"orders"`
+	Others Page[User]         `json:"others"`
+	Owner  Pair[string, User] `json:"owner"`
+}
+
+// swagger:response usersResponse
+type UsersResponse struct {
+	// in: body
+	Body APIResponse[Page[User]]
+}
+
+// swagger:parameters createOrders
+type CreateOrdersParams struct {
+	// in: body
+	Orders Page[Order] `json:"orders"`
+}
+
+// swagger:route GET /users users listUsers
+//
+// Lists the users.
+//
+// Responses:
+//   200: usersResponse
+
+// swagger:route POST /orders orders createOrders
+//
+// Creates orders.
+//
+// Responses:
+//   201: description: the orders are created
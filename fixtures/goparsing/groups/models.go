@@ -0,0 +1,41 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package groups
+
+// Customer is stored flat, but marshaled with nested billing and shipping addresses.
+//
+// Group: billing=Billing*, shipping=Shipping*
+//
+// swagger:model
+type Customer struct {
+	// required: true
+	Name string `json:"name"`
+
+	// required: true
+	BillingStreet string `json:"billingStreet"`
+	BillingCity   string `json:"billingCity"`
+
+	ShippingStreet string `json:"shipping_street"`
+	ShippingCity   string `json:"shipping_city,omitempty"`
+	ShippingNotes  string
+}
+
+// Conflicting has a property named after its group.
+//
+// Group: billing=Billing*
+//
+// swagger:model
+type Conflicting struct {
+	Billing       string `json:"billing"`
+	BillingStreet string `json:"billingStreet"`
+}
+
+// Unmatched has a group without members.
+//
+// Group: billing=Invoice*
+//
+// swagger:model
+type Unmatched struct {
+	BillingStreet string `json:"billingStreet"`
+}
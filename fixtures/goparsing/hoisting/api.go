@@ -0,0 +1,119 @@
+// Package hoisting is the fixture of the parameters identical in several operations, whose parameters structs
+// are copies.
+package hoisting
+
+// swagger:route GET /pets pets listPets
+//
+// Lists the pets.
+//
+// Responses:
+//   200: description: the pets
+
+// swagger:route GET /owners owners listOwners
+//
+// Lists the owners.
+//
+// Responses:
+//   200: description: the owners
+
+// swagger:route GET /toys toys listToys
+//
+// Lists the toys.
+//
+// Responses:
+//   200: description: the toys
+
+// swagger:route GET /vets vets listVets
+//
+// Lists the vets.
+//
+// Responses:
+//   200: description: the vets
+
+// swagger:route POST /pets pets createPet
+//
+// Creates a pet.
+//
+// Responses:
+//   201: description: created
+
+// swagger:parameters listPets
+type listPetsParams struct {
+	// the number of items
+	//
+	// in: query
+	// maximum: 100
+	Limit int `json:"limit"`
+
+	// the number of items skipped
+	//
+	// in: query
+	Offset int `json:"offset"`
+
+	// the identifier of the request
+	//
+	// in: header
+	RequestID string `json:"X-Request-Id"`
+}
+
+// swagger:parameters listOwners
+type listOwnersParams struct {
+	// the number of items
+	//
+	// in: query
+	// maximum: 100
+	Limit int `json:"limit"`
+
+	// the number of items skipped
+	//
+	// in: query
+	Offset int `json:"offset"`
+
+	// the identifier of the request
+	//
+	// in: header
+	RequestID string `json:"X-Request-Id"`
+}
+
+// swagger:parameters listToys
+type listToysParams struct {
+	// the number of items
+	//
+	// in: query
+	// maximum: 100
+	Limit int `json:"limit"`
+
+	// the number of items skipped
+	//
+	// in: query
+	Offset int `json:"offset"`
+}
+
+// swagger:parameters listVets
+type listVetsParams struct {
+	// the number of vets
+	//
+	// in: query
+	// maximum: 10
+	Limit int `json:"limit"`
+
+	// the number of items skipped
+	//
+	// in: query
+	Offset int `json:"offset"`
+}
+
+// swagger:parameters createPet
+type createPetParams struct {
+	// the identifier of the request
+	//
+	// in: header
+	RequestID string `json:"X-Request-Id"`
+
+	// the pet
+	//
+	// in: body
+	Pet struct {
+		Name string `json:"name"`
+	}
+}
@@ -0,0 +1,46 @@
+// Package idempotency is the fixture of the idempotency metadata of operations.
+package idempotency
+
+// swagger:route POST /payments payments createPayment
+//
+// Creates a payment.
+//
+// Idempotent: true
+// IdempotencyKey: required
+//
+// Responses:
+//   201: description: created
+
+// swagger:route POST /refunds payments createRefund
+//
+// Creates a refund.
+//
+// Idempotent: true
+//
+// Responses:
+//   201: description: created
+
+// swagger:route GET /payments payments listPayments
+//
+// Lists the payments.
+//
+// Responses:
+//   200: description: the payments
+
+// swagger:route PUT /payments/{id} payments replacePayment
+//
+// Replaces a payment, which triggers a new charge.
+//
+// Idempotent: false
+//
+// Responses:
+//   200: description: replaced
+
+// swagger:route PATCH /payments/{id} payments updatePayment
+//
+// Updates a payment.
+//
+// IdempotencyKey: optional
+//
+// Responses:
+//   200: description: updated
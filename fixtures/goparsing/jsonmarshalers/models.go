@@ -0,0 +1,85 @@
+// Package jsonmarshalers is the fixture of the structs marshaled by their MarshalJSON method, and of the fields
+// of interface types.
+package jsonmarshalers
+
+// Money marshals as a string, e.g. "12.34 USD".
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+	Scale    int    `json:"scale"`
+}
+
+func (Money) MarshalJSON() ([]byte, error) { return nil, nil }
+
+func (*Money) UnmarshalJSON([]byte) error { return nil }
+
+// Price marshals as a string, e.g. "12.34".
+//
+// swagger:type string
+type Price struct {
+	Units int64 `json:"units"`
+	Cents int64 `json:"cents"`
+}
+
+func (Price) MarshalJSON() ([]byte, error) { return nil, nil }
+
+// Rate marshals as a number, see Options.TypeMappings.
+type Rate struct {
+	Basis int64 `json:"basis"`
+}
+
+func (*Rate) MarshalJSON() ([]byte, error) { return nil, nil }
+
+// Shape is a shape.
+type Shape interface {
+	Area() float64
+}
+
+// Event is an event.
+//
+// swagger:model
+type Event struct {
+	// swagger:discriminator
+	Type string `json:"type"`
+}
+
+// Notification is implemented by the events.
+type Notification interface {
+	Notify()
+}
+
+// UserCreated is the event of a new user.
+//
+// swagger:discriminator user.created
+type UserCreated struct {
+	Event
+
+	Name string `json:"name"`
+}
+
+func (UserCreated) Notify() {}
+
+// OrderPlaced is the event of a new order.
+type OrderPlaced struct {
+	Event
+
+	Order string `json:"order"`
+}
+
+func (*OrderPlaced) Notify() {}
+
+// Invoice is an invoice.
+//
+// swagger:model
+type Invoice struct {
+	Total    Money            `json:"total"`
+	Discount *Money           `json:"discount"`
+	Taxes    map[string]Money `json:"taxes"`
+	Price    Price            `json:"price"`
+	Rate     *Rate            `json:"rate"`
+	Rates    map[string]Rate  `json:"rates"`
+
+	Payload      any          `json:"payload"`
+	Shape        Shape        `json:"shape"`
+	Notification Notification `json:"notification"`
+}
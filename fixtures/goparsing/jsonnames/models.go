@@ -0,0 +1,53 @@
+// Package jsonnames is the fixture of the conflicts of json names between struct fields.
+package jsonnames
+
+// Account embeds structs on three levels, with fields claiming the same json names.
+//
+// swagger:model Account
+type Account struct {
+	Profile
+	Audit
+
+	// depth 0: wins over Profile.ID
+	ID string `json:"id"`
+}
+
+// Profile is embedded in Account.
+type Profile struct {
+	Contact
+
+	// depth 1: loses to Account.ID
+	ID int64 `json:"id"`
+	// depth 1: wins over Contact.Name
+	Name string `json:"name"`
+	// depth 1, tagged: wins over Audit.Label
+	Title string `json:"Label"`
+}
+
+// Audit is embedded in Account.
+type Audit struct {
+	Meta
+
+	// depth 1, untagged: loses to Profile.Title
+	Label string
+	// depth 1: wins over Contact.CreatedBy
+	CreatedBy string `json:"createdBy"`
+}
+
+// Contact is embedded in Profile.
+type Contact struct {
+	// depth 2: ambiguous with Meta.Email
+	Email string `json:"email"`
+	// depth 2: loses to Profile.Name
+	Name string `json:"name"`
+	// depth 2: loses to Audit.CreatedBy
+	CreatedBy string `json:"createdBy"`
+	// depth 2: no conflict
+	Phone string `json:"phone"`
+}
+
+// Meta is embedded in Audit.
+type Meta struct {
+	// depth 2: ambiguous with Contact.Email
+	Email string `json:"email"`
+}
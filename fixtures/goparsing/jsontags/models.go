@@ -0,0 +1,112 @@
+// Package jsontags provides fixtures for the options of the json struct tags.
+package jsontags
+
+// ID is an identifier encoded as a JSON string.
+type ID int64
+
+// Audit is embedded by the models.
+type Audit struct {
+	// required: true
+	CreatedBy string `json:"createdBy"`
+
+	// the revision is internal
+	Revision int `json:"-"`
+
+	// required: true
+	Note string `json:"note"`
+}
+
+// Flags is embedded through a pointer.
+type Flags struct {
+	Enabled bool `json:"enabled"`
+
+	Secret string `json:"-"`
+}
+
+// Account is a model with the options of the json tags.
+//
+// swagger:model Account
+type Account struct {
+	Audit
+
+	*Flags
+
+	// Hidden from the wire
+	Hidden `json:"-"`
+
+	// required: true
+	Balance int64 `json:"balance,string"`
+
+	Limit *int64 `json:"limit,omitempty,string"`
+
+	Ratio float32 `json:"ratio,string"`
+
+	Owner ID `json:"owner,string"`
+
+	Active bool `json:"active,string"`
+
+	// required: true
+	Name string `json:"name,omitempty,string"`
+
+	Nickname string `json:"nickname,omitempty"`
+
+	// shadows the required note of Audit
+	Note string `json:"note,omitempty"`
+
+	Tags []int64 `json:"tags,string"`
+}
+
+// Hidden is embedded without being encoded.
+type Hidden struct {
+	// in: header
+	Leak string `json:"X-Leak"`
+}
+
+// Stamp marshals itself, which the ",string" directive doesn't change.
+type Stamp int64
+
+// MarshalText encodes the stamp.
+func (s Stamp) MarshalText() ([]byte, error) {
+	return []byte("stamp"), nil
+}
+
+// Event is a model with fields which the ",string" directive doesn't apply to.
+//
+// swagger:model Event
+type Event struct {
+	At Stamp `json:"at,string"`
+
+	Owner *ID `json:"owner,omitempty,string"`
+
+	Ratios [2]float64 `json:"ratios,string"`
+}
+
+// swagger:parameters listAccounts
+type ListAccountsParams struct {
+	// The accounts named like this.
+	Name string `json:"name"`
+
+	Hidden `json:"-"`
+}
+
+// AccountsResponse is the list of accounts.
+//
+// swagger:response accountsResponse
+type AccountsResponse struct {
+	// The total number of accounts.
+	//
+	// in: header
+	Total int64 `json:"X-Total"`
+
+	*Hidden `json:"-"`
+
+	// in: body
+	Body []Account
+}
+
+// swagger:route GET /accounts accounts listAccounts
+//
+// Lists the accounts.
+//
+// Responses:
+//   200: accountsResponse
@@ -0,0 +1,19 @@
+package largeenums
+
+// ListAccounts lists the accounts.
+//
+// swagger:route GET /accounts accounts listAccounts
+//
+// Responses:
+//
+//	200: body:[]Account the accounts
+func ListAccounts() {}
+
+// swagger:parameters listAccounts
+type ListAccountsParams struct {
+	// in: query
+	Country Country `json:"country"`
+
+	// in: query
+	Locale Locale `json:"locale"`
+}
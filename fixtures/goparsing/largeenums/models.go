@@ -0,0 +1,54 @@
+// Package largeenums is the fixture of the enums omitted for their size.
+package largeenums
+
+// Locale is a language-region code, with the constants of locales_gen.go.
+//
+// swagger:enum Locale
+type Locale string
+
+// Country is an ISO country code.
+type Country string
+
+const (
+	France  Country = "FR"
+	Germany Country = "DE"
+	Italy   Country = "IT"
+	Spain   Country = "ES"
+)
+
+// Currency keeps its enum whatever its size.
+//
+// Enum: full
+//
+// swagger:enum Currency
+type Currency string
+
+const (
+	Euro   Currency = "EUR"
+	Dollar Currency = "USD"
+	Pound  Currency = "GBP"
+	Yen    Currency = "JPY"
+)
+
+// Status omits its enum whatever its size.
+//
+// Enum: omit
+type Status string
+
+const (
+	Active  Status = "active"
+	Blocked Status = "blocked"
+)
+
+// Account is a model referring to the enum types.
+//
+// swagger:model Account
+type Account struct {
+	Locale Locale `json:"locale"`
+
+	Country Country `json:"country"`
+
+	Currency Currency `json:"currency"`
+
+	Status Status `json:"status"`
+}
@@ -0,0 +1,43 @@
+// Package legacy is annotated in the dialect of older go-swagger code.
+//
+// The pets of the store.
+//
+//	Version: 1.0.0
+//
+// swagger:meta
+package legacy
+
+// @swagger:route GET /pets pets listPets
+//
+// Lists the pets.
+//
+// Responses:
+//
+//	200: petsResponse
+func listPets() {}
+
+// ListPetsParams are the parameters of listPets.
+//
+// swagger:params listPets
+type ListPetsParams struct {
+	// The number of pets.
+	//
+	// in: query
+	Limit int `json:"limit"`
+}
+
+// PetsResponse is the response of listPets.
+//
+// swagger:response petsResponse
+type PetsResponse struct {
+	// in: body
+	Body []Pet
+}
+
+// Pet is a pet of the store.
+//
+// +swagger:model
+type Pet struct {
+	// The name of the pet.
+	Name string `json:"name"`
+}
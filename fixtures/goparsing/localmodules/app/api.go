@@ -0,0 +1,25 @@
+// Package api is the fixture of the packages of the local modules, scanned with ExcludeDeps.
+package api
+
+import (
+	"example.com/nested"
+	"example.com/shared"
+)
+
+// swagger:route GET /pets pets listPets
+//
+// Lists the pets.
+//
+// Responses:
+//   200: petsResponse
+
+// PetsResponse are the pets and their owners.
+//
+// swagger:response petsResponse
+type PetsResponse struct {
+	// in: body
+	Body struct {
+		Pets   []shared.Pet   `json:"pets"`
+		Owners []nested.Owner `json:"owners"`
+	}
+}
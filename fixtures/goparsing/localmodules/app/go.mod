@@ -0,0 +1,7 @@
+module example.com/app
+
+go 1.24
+
+require example.com/nested v0.0.0
+
+replace example.com/nested => ../nested
@@ -0,0 +1,7 @@
+// Package nested is a module replaced by a local directory in the go.mod of the fixture.
+package nested
+
+// Owner is the owner of a pet.
+type Owner struct {
+	Name string `json:"name"`
+}
@@ -0,0 +1,7 @@
+// Package shared is a module of the workspace of the fixture.
+package shared
+
+// Pet is a pet.
+type Pet struct {
+	Name string `json:"name"`
+}
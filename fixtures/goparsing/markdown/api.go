@@ -0,0 +1,45 @@
+// Package markdown is the API of the orders, documented in markdown.
+//
+// # Orders
+//
+// The orders API:
+//
+//   - lists the orders
+//   - creates *orders*, see [the guide](https://docs.acme.com/guide)
+//
+// Creating an order:
+//
+//	curl -X POST https://api.acme.com/orders \
+//	  -d '{"id": 1}'
+//
+//	Version: 1.0.0
+//	ExternalDocs: https://docs.acme.com The guide of the API
+//
+// swagger:meta
+package markdown
+
+// swagger:route POST /orders orders createOrder
+//
+// Creates an order.
+//
+// The order is:
+//
+//  1. validated
+//  2. stored, then
+//  3. shipped
+//
+// Example:
+//
+//	{"id": 1, "lines": []}
+//
+//	ExternalDocs: https://docs.acme.com/orders#create
+//
+//	Responses:
+//	  201: body:Order the order
+func createOrder() {}
+
+// swagger:tag orders
+//
+// The orders of the store.
+//
+//	ExternalDocs: https://docs.acme.com/orders The orders
@@ -0,0 +1,29 @@
+package markdown
+
+// Order is an order of the store.
+//
+// It has:
+//   - an ID
+//   - lines, with a **quantity**
+//
+//	ExternalDocs: https://docs.acme.com/orders#model
+//
+// swagger:model
+type Order struct {
+	// The ID of the order.
+	//
+	//	Minimum: 1
+	ID int64 `json:"id"`
+
+	// Lines of the order.
+	Lines []Line `json:"lines"`
+}
+
+// Line is a line of an order.
+type Line struct {
+	//	quantity := 2 // a code block first
+	//	 indented by one more space
+	//
+	// - the quantity, *at least* one
+	Quantity int64 `json:"quantity"`
+}
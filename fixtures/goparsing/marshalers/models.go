@@ -0,0 +1,75 @@
+// Package marshalers is the fixture of the marshalers declared on the value and on the pointer receivers.
+package marshalers
+
+// ValueText marshals as text, with a value receiver.
+type ValueText struct {
+	Text string `json:"text"`
+}
+
+func (ValueText) MarshalText() ([]byte, error) { return nil, nil }
+
+// PointerText marshals as text, with a pointer receiver.
+type PointerText struct {
+	Text string `json:"text"`
+}
+
+func (*PointerText) MarshalText() ([]byte, error) { return nil, nil }
+
+// ValueRange marshals as a JSON string, with a value receiver.
+//
+// swagger:strfmt duration
+type ValueRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+func (ValueRange) MarshalJSON() ([]byte, error) { return nil, nil }
+
+// PointerRange marshals as a JSON string, with a pointer receiver.
+//
+// swagger:strfmt duration
+type PointerRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+func (*PointerRange) MarshalJSON() ([]byte, error) { return nil, nil }
+
+// PointerCode marshals as a JSON string, with a pointer receiver.
+//
+// swagger:strfmt hostname
+type PointerCode int64
+
+func (*PointerCode) MarshalJSON() ([]byte, error) { return nil, nil }
+
+// Matrix holds the marshalers as values, pointers and elements.
+//
+// swagger:model
+type Matrix struct {
+	ValueText       ValueText     `json:"valueText"`
+	ValueTextPtr    *ValueText    `json:"valueTextPtr"`
+	PointerText     PointerText   `json:"pointerText"`
+	PointerTextPtr  *PointerText  `json:"pointerTextPtr"`
+	ValueRange      ValueRange    `json:"valueRange"`
+	ValueRangePtr   *ValueRange   `json:"valueRangePtr"`
+	PointerRange    PointerRange  `json:"pointerRange"`
+	PointerRangePtr *PointerRange `json:"pointerRangePtr"`
+	PointerCode     PointerCode   `json:"pointerCode"`
+	PointerCodePtr  *PointerCode  `json:"pointerCodePtr"`
+
+	// the elements of a slice are addressable
+	PointerTexts  []PointerText  `json:"pointerTexts"`
+	PointerRanges []PointerRange `json:"pointerRanges"`
+	// the values of a map are not
+	PointerTextMap  map[string]PointerText  `json:"pointerTextMap"`
+	PointerRangeMap map[string]PointerRange `json:"pointerRangeMap"`
+	// nor are the elements of the arrays of a value
+	PointerTextArray [2]PointerText `json:"pointerTextArray"`
+	// unlike the elements of the arrays behind a pointer
+	PointerTextArrayPtr *[2]PointerText `json:"pointerTextArrayPtr"`
+
+	// the fields of a struct behind a pointer are addressable
+	Nested *struct {
+		PointerRange PointerRange `json:"pointerRange"`
+	} `json:"nested"`
+}
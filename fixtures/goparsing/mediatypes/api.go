@@ -0,0 +1,54 @@
+// Package mediatypes is the fixture of the normalization of media types and schemes.
+//
+//	Schemes: HTTPS, http,
+//
+//	Consumes:
+//	- json
+//	- yaml
+//	- application/JSON
+//
+//	Produces: xml, form, multipart,
+//
+// swagger:meta
+package mediatypes
+
+// swagger:route POST /uploads uploads createUpload
+//
+// Uploads a file.
+//
+// Consumes:
+//   multipart
+//   form
+//   Multipart/Form-Data
+//
+// Produces:
+//   json, yaml, xml,
+//
+// Schemes: wss, ws, WS
+//
+// Responses:
+//   201: description: uploaded
+
+// swagger:route GET /uploads uploads listUploads
+//
+// Lists the uploads.
+//
+// Produces:
+//   jsn
+//   application/vnd.api+json; charset=utf-8
+//   application/
+//
+// Responses:
+//   200: description: the uploads
+
+// swagger:operation PUT /uploads/{id} uploads replaceUpload
+//
+// Replaces an upload.
+//
+// ---
+// consumes: [form, multipart, json]
+// produces: [yaml, xml, application/xml]
+// schemes: [HTTP, https]
+// responses:
+//   "204":
+//     description: replaced
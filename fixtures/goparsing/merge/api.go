@@ -0,0 +1,55 @@
+// Package merge is the fixture of the merge of the scan with an input spec.
+//
+// The pets of the store, scanned from the Go code.
+//
+//	Host: scanned.example.com
+//	Schemes: http, https
+//	Consumes:
+//	- application/json
+//	- application/xml
+//	Version: 2.0.0
+//
+//	SecurityDefinitions:
+//	api_key:
+//	  type: apiKey
+//	  name: X-Scanned-Key
+//	  in: header
+//	basic:
+//	  type: basic
+//
+// swagger:meta
+package merge
+
+// swagger:tag pets
+//
+// The pets of the store.
+
+// swagger:route GET /pets pets listPets
+//
+// Lists the pets.
+//
+// Responses:
+//   200: petsResponse
+
+// swagger:route POST /pets pets createPet
+//
+// Creates a pet.
+//
+// Responses:
+//   201: description: the pet was created
+
+// PetsResponse is a list of pets.
+//
+// swagger:response petsResponse
+type PetsResponse struct {
+	// in: body
+	Body []Pet
+}
+
+// Pet is a pet of the store.
+//
+// swagger:model
+type Pet struct {
+	// the name of the pet
+	Name string `json:"name"`
+}
@@ -0,0 +1,44 @@
+// Package mistakes is the fixture of the mistakes of the annotations, which the scan reports together.
+package mistakes
+
+// ListPets lists the pets, and documents a response no swagger:response declares.
+//
+// swagger:route GET /pets pets listPets
+//
+// Responses:
+//
+//	200: petsResponse
+//	404: missingResponse
+func ListPets() {}
+
+// GetPet misspells the route annotation.
+//
+// swagger:rotue GET /pets/{id} pets getPet
+func GetPet() {}
+
+// DeletePet misspells the prefix of the annotation, which the scan ignores.
+//
+// swager:route DELETE /pets/{id} pets deletePet
+func DeletePet() {}
+
+// UpdatePet has no operation ID, which the scan ignores.
+//
+// swagger:route PUT /pets/{id}
+func UpdatePet() {}
+
+// CreatePet has invalid responses.
+//
+// swagger:operation POST /pets pets createPet
+//
+// ---
+// responses:
+//   "201": [created]
+func CreatePet() {}
+
+// PetsResponse is the list of pets.
+//
+// swagger:response petsResponse
+type PetsResponse struct {
+	// in: body
+	Body []Pet
+}
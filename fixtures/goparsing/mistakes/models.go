@@ -0,0 +1,24 @@
+package mistakes
+
+// Pet is a pet.
+//
+// swagger:model
+type Pet struct {
+	// the age of the pet
+	//
+	// Maximum: abc
+	Age int `json:"age"`
+
+	// the name of the pet
+	//
+	// Min Length: -1
+	Name string `json:"name"`
+}
+
+// Owner has an example without its file.
+//
+// swagger:model
+// swagger:example
+type Owner struct {
+	Name string `json:"name"`
+}
@@ -0,0 +1,16 @@
+// Package warnings is the fixture of the mistakes of the annotations which the scan ignores, see FailOnWarning.
+package warnings
+
+// ListPets lists the pets, and documents a response no swagger:response declares.
+//
+// swagger:route GET /pets pets listPets
+//
+// Responses:
+//
+//	404: missingResponse
+func ListPets() {}
+
+// DeletePet misspells the prefix of the annotation.
+//
+// Swagger:route DELETE /pets/{id} pets deletePet
+func DeletePet() {}
@@ -0,0 +1,62 @@
+// Package namedexamples is the fixture of the named examples of the body parameters and the responses.
+package namedexamples
+
+import "net/http"
+
+// User of the store.
+//
+// swagger:model
+type User struct {
+	// required: true
+	Name string `json:"name"`
+	// required: true
+	Email string `json:"email"`
+	// minimum: 0
+	Age int `json:"age,omitempty"`
+	// enum: admin,member
+	Role string `json:"role,omitempty"`
+}
+
+// swagger:parameters createUser
+type CreateUserParams struct {
+	// The user to create.
+	//
+	// in: body
+	// Examples:
+	//   minimal:
+	//     summary: only the required fields
+	//     value: {name: ada, email: ada@example.com}
+	//   complete:
+	//     summary: every field
+	//     file: testdata/complete_user.json
+	Body User
+}
+
+// The created user.
+//
+// Examples:
+//   created:
+//     summary: a member
+//     description: the role is member by default
+//     value:
+//       name: ada
+//       email: ada@example.com
+//       role: member
+//
+// swagger:response userResponse
+type UserResponse struct {
+	// in: body
+	Body User
+}
+
+// swagger:route POST /users users createUser
+//
+// Creates a user.
+//
+// Consumes:
+//   - application/json
+//   - application/xml
+//
+// Responses:
+//   201: userResponse
+func CreateUser(http.ResponseWriter, *http.Request) {}
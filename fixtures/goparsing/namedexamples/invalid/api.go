@@ -0,0 +1,48 @@
+// Package invalid is the fixture of the named examples which don't match their schemas.
+package invalid
+
+import "net/http"
+
+// Pet of the store.
+//
+// swagger:model
+type Pet struct {
+	// required: true
+	Name string `json:"name"`
+	// enum: cat,dog
+	Kind string `json:"kind,omitempty"`
+	// max items: 2
+	Tags []string `json:"tags,omitempty"`
+}
+
+// swagger:parameters createPet
+type CreatePetParams struct {
+	// in: body
+	// Examples:
+	//   valid:
+	//     value: {name: rex, kind: dog}
+	//   wrong:
+	//     summary: a pet of an unknown kind
+	//     value: {name: 42, kind: bird, tags: [a, b, c]}
+	Body Pet
+}
+
+// The created pet.
+//
+// Examples:
+//   nameless:
+//     value: {kind: cat}
+//
+// swagger:response petResponse
+type PetResponse struct {
+	// in: body
+	Body Pet
+}
+
+// swagger:route POST /pets pets createPet
+//
+// Creates a pet.
+//
+// Responses:
+//   201: petResponse
+func CreatePet(http.ResponseWriter, *http.Request) {}
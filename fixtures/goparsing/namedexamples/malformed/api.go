@@ -0,0 +1,42 @@
+// Package malformed is the fixture of the Examples: blocks which don't parse.
+package malformed
+
+// swagger:parameters createUser
+type CreateUserParams struct {
+	// in: body
+	// Examples:
+	//   minimal:
+	//     value: {name: ada}
+	//     file: testdata/user.json
+	Body struct{}
+}
+
+// swagger:parameters updateUser
+type UpdateUserParams struct {
+	// in: body
+	// Examples:
+	//   minimal:
+	//     sumary: a typo
+	//     value: {name: ada}
+	Body struct{}
+}
+
+// Examples:
+//   missing:
+//     file: testdata/missing.json
+//
+// swagger:response userResponse
+type UserResponse struct {
+	// in: body
+	Body struct{}
+}
+
+// Examples:
+//   empty:
+//     summary: no value
+//
+// swagger:response usersResponse
+type UsersResponse struct {
+	// in: body
+	Body []struct{}
+}
@@ -0,0 +1,26 @@
+// Package api declares the routes of a group accepting XML bodies.
+package api
+
+import "github.com/3idey/codescan/fixtures/goparsing/negotiation/negotiate"
+
+// Group configures the router of the group.
+func Group(r *negotiate.Router) {
+	r.Accept("application/xml", "application/json")
+}
+
+// swagger:route POST /orders orders createOrder
+//
+// Creates an order.
+//
+// Responses:
+//   201: description: the order is created
+
+// swagger:route POST /uploads uploads createUpload
+//
+// Uploads a file.
+//
+// Consumes:
+//   - multipart/form-data
+//
+// Responses:
+//   201: description: the file is uploaded
@@ -0,0 +1,18 @@
+// Package negotiate is a content-negotiation middleware.
+package negotiate
+
+import "net/http"
+
+// Middleware negotiates the media types of the requests and responses.
+type Middleware func(http.Handler) http.Handler
+
+// New offers the media types of the responses.
+func New(mediaTypes ...string) Middleware {
+	return func(next http.Handler) http.Handler { return next }
+}
+
+// Router routes the requests of a group.
+type Router struct{}
+
+// Accept declares the media types of the request bodies.
+func (r *Router) Accept(mediaTypes ...string) {}
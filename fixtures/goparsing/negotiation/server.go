@@ -0,0 +1,21 @@
+// Package negotiation is the fixture of the media types detected from content-negotiation middlewares.
+//
+// swagger:meta
+package negotiation
+
+import (
+	"os"
+
+	"github.com/3idey/codescan/fixtures/goparsing/negotiation/negotiate"
+)
+
+const msgpack = "application/msgpack"
+
+// Setup installs the middlewares of the server.
+func Setup() []negotiate.Middleware {
+	return []negotiate.Middleware{
+		negotiate.New("application/json", msgpack),
+		negotiate.New([]string{"json", "text/csv"}...),
+		negotiate.New(os.Getenv("EXTRA_MEDIA_TYPE")),
+	}
+}
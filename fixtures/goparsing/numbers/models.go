@@ -0,0 +1,40 @@
+// Package numbers is the fixture of the numbers which don't fit a float64.
+package numbers
+
+// Sequence is an identifier near the bounds of int64.
+//
+// swagger:enum Sequence
+type Sequence int64
+
+const (
+	Next Sequence = 9007199254740993
+	Last Sequence = 9223372036854775807
+)
+
+// Record is a model with numbers beyond the integers a float64 holds exactly.
+//
+// swagger:model Record
+type Record struct {
+	// The identifier of the record.
+	//
+	// default: 9007199254740993
+	ID int64 `json:"id" example:"9223372036854775807"`
+
+	// The limits of the record.
+	//
+	// default: {"max": 9007199254740993, "min": -9007199254740993, "ratio": 1e21}
+	// example: {"max": 9223372036854775807, "tiny": 1e-7}
+	Limits map[string]any `json:"limits"`
+
+	// The shards of the record.
+	//
+	// example: [9007199254740993, 18446744073709551615]
+	Shards []uint64 `json:"shards"`
+
+	// The checksum of the record.
+	//
+	// default: 18446744073709551615
+	Checksum uint64 `json:"checksum"`
+
+	Sequence Sequence `json:"sequence"`
+}
@@ -0,0 +1,174 @@
+// Package openapi3 Pet Store API.
+//
+// The fixture of the OpenAPI 3.0 output, compared with the golden files of both versions.
+//
+//	Schemes: https, http
+//	Host: api.example.com
+//	BasePath: /v1
+//	Version: 1.0.0
+//
+//	Consumes:
+//	- application/json
+//	- application/xml
+//
+//	Produces:
+//	- application/json
+//	- application/xml
+//
+//	Extensions:
+//	x-audience: public
+//
+//	Security:
+//	- api_key:
+//
+//	SecurityDefinitions:
+//	api_key:
+//	  type: apiKey
+//	  name: X-API-Key
+//	  in: header
+//	oauth2:
+//	  type: oauth2
+//	  authorizationUrl: https://auth.example.com/authorize
+//	  tokenUrl: https://auth.example.com/token
+//	  flow: accessCode
+//	  scopes:
+//	    pets:read: read the pets
+//
+// swagger:meta
+package openapi3
+
+import "os"
+
+// A Pet of the store.
+//
+// swagger:model
+type Pet struct {
+	// the id of the pet
+	//
+	// required: true
+	ID int64 `json:"id"`
+
+	// the name of the pet
+	//
+	// required: true
+	// min length: 1
+	Name string `json:"name"`
+
+	// the owner of the pet, if any
+	Owner *Owner `json:"owner"`
+
+	// the nickname of the pet
+	//
+	// Extensions:
+	// x-nullable: true
+	Nickname *string `json:"nickname"`
+}
+
+// An Owner of pets.
+//
+// swagger:model
+type Owner struct {
+	Name string `json:"name"`
+}
+
+// swagger:route GET /pets pets listPets
+//
+// Lists the pets.
+//
+// Responses:
+//   200: petsResponse
+//   default: errorResponse
+
+// swagger:route POST /pets pets createPet
+//
+// Creates a pet.
+//
+// Responses:
+//   201: body:Pet the created pet
+
+// swagger:route GET /pets/export pets exportPets
+//
+// Exports the pets, as JSON or CSV.
+//
+// Produces:
+//   application/json
+//   text/csv
+//
+// Responses:
+//   200: body[application/json]:[]Pet body[text/csv]:string the exported pets
+
+// swagger:route POST /pets/{id}/photo pets uploadPhoto
+//
+// Uploads a photo of a pet.
+//
+// Consumes:
+//   multipart/form-data
+//
+// Responses:
+//   204: description: uploaded
+
+// swagger:parameters listPets
+type listPetsParams struct {
+	// the tags of the pets
+	//
+	// in: query
+	// collection format: multi
+	Tags []string `json:"tags"`
+
+	// the maximum number of pets
+	//
+	// in: query
+	// maximum: 100
+	Limit int32 `json:"limit"`
+}
+
+// swagger:parameters createPet
+type createPetParams struct {
+	// the pet to create
+	//
+	// in: body
+	// required: true
+	Pet Pet `json:"pet"`
+}
+
+// swagger:parameters uploadPhoto
+type uploadPhotoParams struct {
+	// the id of the pet
+	//
+	// in: path
+	// required: true
+	ID int64 `json:"id"`
+
+	// the photo
+	//
+	// in: formData
+	// required: true
+	// swagger:file
+	Photo *os.File `json:"photo"`
+
+	// a caption of the photo
+	//
+	// in: formData
+	Caption string `json:"caption"`
+}
+
+// the pets of the store
+//
+// swagger:response petsResponse
+type petsResponse struct {
+	// the total number of pets
+	Total int64 `json:"X-Total-Count"`
+
+	// in: body
+	Body []Pet
+}
+
+// a failure
+//
+// swagger:response errorResponse
+type errorResponse struct {
+	// in: body
+	Body struct {
+		Message string `json:"message"`
+	}
+}
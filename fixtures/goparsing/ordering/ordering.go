@@ -0,0 +1,33 @@
+// Package ordering provides fixtures to exercise declaration order of properties.
+package ordering
+
+// Base is embedded in Item.
+type Base struct {
+	ID      int64  `json:"id"`
+	Created string `json:"created"`
+}
+
+// Extra is embedded in Item by pointer.
+type Extra struct {
+	Note string `json:"note"`
+}
+
+// Item has its fields declared in no particular alphabetical order.
+//
+// swagger:model Item
+type Item struct {
+	Zeta string `json:"zeta"`
+	Base
+	Alpha string `json:"alpha"`
+	*Extra
+	Mid int32 `json:"mid"`
+
+	// Ignored is not a property.
+	Ignored string `json:"-"`
+
+	// Nested keeps its own order.
+	Nested struct {
+		Second string `json:"second"`
+		First  string `json:"first"`
+	} `json:"nested"`
+}
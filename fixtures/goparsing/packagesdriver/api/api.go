@@ -0,0 +1,13 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+// Package api is the fixture of the packages loaded by a GOPACKAGESDRIVER.
+package api
+
+// Pet is a pet of the store.
+//
+// swagger:model
+type Pet struct {
+	// the name of the pet
+	Name string `json:"name"`
+}
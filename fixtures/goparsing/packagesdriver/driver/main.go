@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+// Command driver is a fake GOPACKAGESDRIVER, answering the requests of go/packages with go list, as a build
+// system would with its own metadata. It appends the patterns and the mode of each request to the file of the
+// FAKE_DRIVER_LOG variable of the environment of the request.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	var req packages.DriverRequest
+	if err := json.NewDecoder(os.Stdin).Decode(&req); err != nil {
+		return err
+	}
+	patterns := os.Args[1:]
+
+	env := append(req.Env, "GOPACKAGESDRIVER=off")
+	for _, kv := range req.Env {
+		if file, ok := strings.CutPrefix(kv, "FAKE_DRIVER_LOG="); ok {
+			if err := appendLog(file, fmt.Sprintf("%s %v\n", strings.Join(patterns, " "), req.Mode)); err != nil {
+				return err
+			}
+		}
+	}
+
+	// the metadata only: go/packages parses and type checks the files
+	cfg := &packages.Config{
+		Mode:       packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles | packages.NeedImports | packages.NeedDeps | packages.NeedModule,
+		Env:        env,
+		BuildFlags: req.BuildFlags,
+		Tests:      req.Tests,
+		Overlay:    req.Overlay,
+	}
+	roots, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return err
+	}
+	resp := packages.DriverResponse{Compiler: "gc", Arch: runtime.GOARCH, GoVersion: goVersion()}
+	for _, root := range roots {
+		resp.Roots = append(resp.Roots, root.ID)
+	}
+	packages.Visit(roots, nil, func(pkg *packages.Package) {
+		resp.Packages = append(resp.Packages, pkg)
+	})
+	return json.NewEncoder(os.Stdout).Encode(&resp)
+}
+
+func appendLog(file, line string) error {
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(line); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// goVersion returns the minor version of the go toolchain, e.g. 25 for go1.25.1.
+func goVersion() int {
+	var minor int
+	_, _ = fmt.Sscanf(strings.TrimPrefix(runtime.Version(), "go1."), "%d", &minor)
+	return minor
+}
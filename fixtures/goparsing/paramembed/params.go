@@ -0,0 +1,65 @@
+// Package paramembed is the fixture of the composition of parameters by embedding.
+package paramembed
+
+// PaginationParams are the parameters of the paginated lists.
+type PaginationParams struct {
+	// The maximum number of items.
+	Limit int `json:"limit"`
+	// The number of items to skip.
+	Offset int `json:"offset"`
+}
+
+// SortParams are the parameters of the sorted lists.
+type SortParams struct {
+	// The field to sort on.
+	Sort string `json:"sort"`
+}
+
+// ListParams are the parameters of the lists.
+type ListParams struct {
+	PaginationParams
+	SortParams
+}
+
+// TraceParams carry the trace of a request.
+type TraceParams struct {
+	// in: header
+	TraceID string `json:"X-Trace-Id"`
+}
+
+// swagger:parameters listUsers
+type ListUsersParams struct {
+	// The users named like this.
+	Name string `json:"name"`
+
+	ListParams
+	*TraceParams
+}
+
+// swagger:route GET /users users listUsers
+//
+// Lists the users.
+//
+// Responses:
+//   200: description: the users
+
+// CursorParams paginate with a cursor.
+type CursorParams struct {
+	// The cursor of the page.
+	Cursor string `json:"cursor"`
+	// The maximum number of items.
+	Limit int `json:"limit"`
+}
+
+// swagger:parameters listGroups
+type ListGroupsParams struct {
+	PaginationParams
+	CursorParams
+}
+
+// swagger:route GET /groups groups listGroups
+//
+// Lists the groups.
+//
+// Responses:
+//   200: description: the groups
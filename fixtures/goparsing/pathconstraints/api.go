@@ -0,0 +1,56 @@
+// Package pathconstraints has routes whose templates constrain their path parameters.
+//
+//	Version: 1.0.0
+//
+// swagger:meta
+package pathconstraints
+
+// swagger:route GET /users/{id:[0-9]+} users getUser
+//
+// Gets a user.
+//
+// Responses:
+//
+//	200: description: the user
+func getUser() {}
+
+// swagger:route PUT /users/{id:[0-9]+} users updateUser
+//
+// Updates a user.
+//
+// Responses:
+//
+//	204: description: the user was updated
+func updateUser() {}
+
+// swagger:route GET /orders/{year:[0-9]{4}}/{slug:[a-z-]+} orders listOrders
+//
+// Lists the orders of a year.
+//
+// Responses:
+//
+//	200: description: the orders
+func listOrders() {}
+
+// GetUserParams are the parameters of getUser.
+//
+// swagger:parameters getUser
+type GetUserParams struct {
+	// The identifier of the user.
+	//
+	// in: path
+	// required: true
+	ID string `json:"id"`
+}
+
+// UpdateUserParams are the parameters of updateUser.
+//
+// swagger:parameters updateUser
+type UpdateUserParams struct {
+	// The identifier of the user.
+	//
+	// in: path
+	// required: true
+	// pattern: ^[1-9][0-9]*$
+	ID string `json:"id"`
+}
@@ -0,0 +1,42 @@
+// Package paths is the fixture of the documentation of paths.
+package paths
+
+// swagger:route GET /users users listUsers
+//
+// Lists the users.
+//
+// Responses:
+//   200: description: the users
+
+// swagger:route GET /users/{id} users getUser
+//
+// Gets a user.
+//
+// Responses:
+//   200: description: the user
+
+// swagger:route DELETE /users/{id} users deleteUser
+//
+// Deletes a user.
+//
+// Responses:
+//   204: description: deleted
+
+// UserHandler serves a user.
+//
+// swagger:path /users/{id}
+//
+// A user of the API.
+//
+// Users are created by signing up,
+// and are deleted with their account.
+type UserHandler struct{}
+
+// swagger:path /users
+//
+// The users of the API.
+var _ = UserHandler{}
+
+// swagger:path /groups
+//
+// The groups, which have no operation yet.
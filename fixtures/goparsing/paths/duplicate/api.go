@@ -0,0 +1,19 @@
+// Package duplicate documents a path twice.
+package duplicate
+
+// swagger:route GET /users users listUsers
+//
+// Lists the users.
+//
+// Responses:
+//   200: description: the users
+
+// swagger:path /users
+//
+// The users.
+type Users struct{}
+
+// swagger:path /users
+//
+// The users, again.
+type MoreUsers struct{}
@@ -0,0 +1,44 @@
+// Package precheck is the fixture of the syntax-only check of the annotations.
+//
+//	Version: 1.0.0
+//
+//	SecurityDefinitions:
+//	api_key: [not, a, map]
+//
+// swagger:meta
+package precheck
+
+// Pet is a pet.
+//
+// swagger:model
+type Pet struct {
+	Name string `json:"name"`
+}
+
+// swagger:route GET /pets pets listPets
+//
+// Lists the pets.
+//
+// Responses:
+//   200: description: the pets
+
+// swagger:route GET /pets/{id}
+//
+// Gets a pet, but has no operation ID.
+
+// swagger:operation POST /pets pets createPet
+//
+// Creates a pet.
+//
+// ---
+// responses:
+//   "201": [created]
+
+// swagger:route POST /pets/{id}/adoptions pets adoptPet
+//
+// Adopts a pet.
+//
+// Idempotent: true
+//
+// Responses:
+//   201: description: adopted
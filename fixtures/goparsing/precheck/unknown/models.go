@@ -0,0 +1,9 @@
+// Package unknown is the fixture of an unknown annotation.
+package unknown
+
+// Owner owns pets.
+//
+// swagger:modle
+type Owner struct {
+	Name string `json:"name"`
+}
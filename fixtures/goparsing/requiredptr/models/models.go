@@ -0,0 +1,44 @@
+// Package models provides fixtures to exercise the required-from-pointers convention.
+package models
+
+// Base is embedded by value, so its fields follow the convention.
+type Base struct {
+	ID int64 `json:"id"`
+}
+
+// Audit is embedded by pointer, so its fields are optional.
+type Audit struct {
+	CreatedBy string `json:"createdBy"`
+}
+
+// Widget is a model following the required-from-pointers convention.
+//
+// swagger:model Widget
+type Widget struct {
+	Base
+	*Audit
+
+	// Name is required because it is not a pointer.
+	Name string `json:"name"`
+
+	// Color is optional because it is a pointer.
+	Color *string `json:"color"`
+
+	// Tags is optional because of omitempty.
+	Tags []string `json:"tags,omitempty"`
+
+	// Notes is explicitly optional.
+	//
+	// required: false
+	Notes string `json:"notes"`
+
+	// Owner is explicitly required.
+	//
+	// required: true
+	Owner *string `json:"owner"`
+
+	// Revision is set by the server.
+	//
+	// read only: true
+	Revision int64 `json:"revision"`
+}
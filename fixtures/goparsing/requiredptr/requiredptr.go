@@ -0,0 +1,13 @@
+// Package requiredptr provides fixtures for structs outside of the required-from-pointers scope.
+package requiredptr
+
+import "github.com/3idey/codescan/fixtures/goparsing/requiredptr/models"
+
+// Envelope is a response model that does not follow the convention.
+//
+// swagger:model Envelope
+type Envelope struct {
+	Code int32 `json:"code"`
+
+	Widget models.Widget `json:"widget"`
+}
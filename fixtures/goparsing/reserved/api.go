@@ -0,0 +1,48 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+// Package reserved is the fixture of the definition names breaking the generators.
+package reserved
+
+// Error is the body of the failures, colliding with the Error of go-swagger.
+//
+// swagger:model Error
+type Error struct {
+	Message string `json:"message"`
+	// the failure behind this one
+	Cause *Error `json:"cause,omitempty"`
+}
+
+// ErrorModel takes the name RenameCollisions would give to Error.
+//
+// swagger:model ErrorModel
+type ErrorModel struct {
+	Code int `json:"code"`
+}
+
+// Profile has a name which is not an identifier.
+//
+// swagger:model user-profile
+type Profile struct {
+	Bio string `json:"bio"`
+}
+
+// Client is renamed by the options.
+//
+// swagger:model Client
+type Client struct {
+	ID string `json:"id"`
+}
+
+// swagger:response errorResponse
+type ErrorResponse struct {
+	// in: body
+	Body Error
+}
+
+// swagger:route GET /profile profiles getProfile
+//
+// Responses:
+//
+//	default: errorResponse
+func getProfile() {}
@@ -0,0 +1,63 @@
+// Package respheaders is the fixture of the headers of the responses, declared by swagger:response structs
+// and by the Responses of swagger:route.
+package respheaders
+
+// Order of the API.
+type Order struct {
+	ID string `json:"id"`
+}
+
+// The orders.
+//
+// swagger:response ordersResponse
+type OrdersResponse struct {
+	// The id of the request.
+	//
+	// in: header
+	RequestID string `json:"X-Request-Id"`
+
+	// The total number of orders.
+	//
+	// in: header
+	Total int32 `json:"X-Total-Count"`
+
+	// The links to the other pages.
+	//
+	// in: header
+	// collectionFormat: pipes
+	Links []string `json:"Link"`
+
+	// in: body
+	Body []Order
+}
+
+// swagger:route GET /orders orders listOrders
+//
+// Lists the orders.
+//
+// Responses:
+//   200: ordersResponse
+//     Headers:
+//       + name: X-Request-Id
+//         type: string
+//         format: uuid
+//         description: The id of the request, a UUID.
+//       + name: X-Rate-Limit-Remaining
+//         type: int32
+//         description: The requests left.
+//   429: description:"Too many requests."
+//     Headers:
+//       + name: Retry-After
+//         type: integer
+//       + name: X-Retry-Hosts
+//         type: array
+//         items: string
+//         collectionFormat: csv
+//   default: ordersResponse
+
+// swagger:route GET /orders/recent orders listRecentOrders
+//
+// Lists the recent orders.
+//
+// Responses:
+//   200: ordersResponse
@@ -0,0 +1,28 @@
+// Package responsereuse is the fixture of the responses reused with several status codes.
+package responsereuse
+
+// User of the API.
+type User struct {
+	Name string `json:"name"`
+}
+
+// The user.
+//
+// swagger:response userResponse
+type UserResponse struct {
+	// Location of the user.
+	Location string `json:"Location"`
+
+	// in: body
+	Body User
+}
+
+// swagger:route PUT /users/{id} users saveUser
+//
+// Creates or replaces a user.
+//
+// Responses:
+//   200: userResponse
+//   201: userResponse "The user was created: it is found at its Location."
+//   202: userResponse "The \"pending\" user."
+//   default: userResponse legacy description
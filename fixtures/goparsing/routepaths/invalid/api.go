@@ -0,0 +1,30 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+// Package invalid is the fixture of the paths of routes rejected by the scan.
+package invalid
+
+// swagger:route GET users users listUsers
+//
+// Lists the users, without a leading slash.
+func ListUsers() {}
+
+// swagger:route GET /users?active=true users listActiveUsers
+//
+// Lists the active users, with a query string.
+func ListActiveUsers() {}
+
+// swagger:route GET /users/{id}/pets/{id} pets getPet
+//
+// Gets a pet, of a parameter declared twice.
+func GetPet() {}
+
+// swagger:operation GET /owners/{id}/pets/{ID} pets getOwnerPet
+//
+// Gets a pet of an owner, of parameters differing by case.
+//
+// ---
+// responses:
+//   "200":
+//     description: the pet
+func GetOwnerPet() {}
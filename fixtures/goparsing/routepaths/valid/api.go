@@ -0,0 +1,41 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+// Package valid is the fixture of the trailing slashes and of the case of the paths of the routes.
+package valid
+
+// swagger:route GET /users/ users listUsers
+//
+// Lists the users.
+//
+// Responses:
+//
+//	200: description: the users
+func ListUsers() {}
+
+// swagger:route POST /users users createUser
+//
+// Creates a user.
+//
+// Responses:
+//
+//	201: description: the user is created
+func CreateUser() {}
+
+// swagger:route GET /users/{ID} users getUser
+//
+// Gets a user.
+//
+// Responses:
+//
+//	200: description: the user
+func GetUser() {}
+
+// swagger:route GET / root
+//
+// Describes the service.
+//
+// Responses:
+//
+//	200: description: the service
+func Root() {}
@@ -0,0 +1,41 @@
+// Package chi is a stub of the router of github.com/go-chi/chi.
+package chi
+
+import "net/http"
+
+// Router registers the routes.
+type Router interface {
+	http.Handler
+
+	Use(middlewares ...func(http.Handler) http.Handler)
+	With(middlewares ...func(http.Handler) http.Handler) Router
+	Group(fn func(r Router)) Router
+	Route(pattern string, fn func(r Router)) Router
+	Mount(pattern string, h http.Handler)
+
+	Method(method, pattern string, h http.Handler)
+	MethodFunc(method, pattern string, h http.HandlerFunc)
+	Get(pattern string, h http.HandlerFunc)
+	Post(pattern string, h http.HandlerFunc)
+	Put(pattern string, h http.HandlerFunc)
+	Delete(pattern string, h http.HandlerFunc)
+}
+
+// Mux is a Router.
+type Mux struct{}
+
+// NewRouter returns a new router.
+func NewRouter() *Mux { return &Mux{} }
+
+func (mx *Mux) ServeHTTP(http.ResponseWriter, *http.Request)   {}
+func (mx *Mux) Use(...func(http.Handler) http.Handler)         {}
+func (mx *Mux) With(...func(http.Handler) http.Handler) Router { return mx }
+func (mx *Mux) Group(fn func(r Router)) Router                 { fn(mx); return mx }
+func (mx *Mux) Route(_ string, fn func(r Router)) Router       { fn(mx); return mx }
+func (mx *Mux) Mount(string, http.Handler)                     {}
+func (mx *Mux) Method(_, _ string, _ http.Handler)             {}
+func (mx *Mux) MethodFunc(_, _ string, _ http.HandlerFunc)     {}
+func (mx *Mux) Get(string, http.HandlerFunc)                   {}
+func (mx *Mux) Post(string, http.HandlerFunc)                  {}
+func (mx *Mux) Put(string, http.HandlerFunc)                   {}
+func (mx *Mux) Delete(string, http.HandlerFunc)                {}
@@ -0,0 +1,58 @@
+package chiapp
+
+import "net/http"
+
+// Pet is a pet of the store.
+type Pet struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// The pets of the store.
+//
+// swagger:response ListPets
+type petsResponse struct {
+	// in: body
+	Body []Pet
+}
+
+// swagger:parameters GetPet
+type petParams struct {
+	// in: path
+	// required: true
+	ID int64 `json:"id"`
+}
+
+// ListPets lists the pets of the store.
+func ListPets(http.ResponseWriter, *http.Request) {}
+
+// GetPet returns a pet.
+//
+// Responses:
+//
+//	200: description: the pet
+//	404: description: the pet is not found
+func GetPet(http.ResponseWriter, *http.Request) {}
+
+// CreateOwner creates the owner of a pet.
+func CreateOwner(http.ResponseWriter, *http.Request) {}
+
+// GetStore returns a store.
+func GetStore(http.ResponseWriter, *http.Request) {}
+
+// swagger:route GET /explicit/{id} explicit getExplicit
+//
+// Explicit is documented by its annotation.
+//
+// Responses:
+//
+//	200: description: documented
+func Explicit(http.ResponseWriter, *http.Request) {}
+
+// Admin handles the administration of the store.
+type Admin struct{}
+
+// DeletePet deletes a pet.
+func (a *Admin) DeletePet(http.ResponseWriter, *http.Request) {}
+
+func (a *Admin) Stats(http.ResponseWriter, *http.Request) {}
@@ -0,0 +1,50 @@
+// Package chiapp registers its routes with chi.
+package chiapp
+
+import (
+	"net/http"
+
+	"github.com/3idey/codescan/fixtures/goparsing/routers/chi"
+)
+
+var handlers = map[string]http.HandlerFunc{}
+
+func auth(next http.Handler) http.Handler { return next }
+
+// NewRouter registers the routes of the API.
+func NewRouter() http.Handler {
+	r := chi.NewRouter()
+	// Health reports the health of the server.
+	r.Get("/health", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	r.Route("/api/v1", func(r chi.Router) {
+		r.Get("/pets", ListPets)
+		r.With(auth).Get("/pets/{id:[0-9]+}", GetPet)
+		r.Route("/owners", func(r chi.Router) {
+			r.Post("/", CreateOwner)
+		})
+		r.Mount("/stores", storesRouter())
+		r.Get("/explicit", Explicit)
+		r.Get("/dynamic", handlers["dynamic"])
+	})
+
+	admin := chi.NewRouter()
+	h := &Admin{}
+	admin.Method(http.MethodDelete, "/pets/{id}", http.HandlerFunc(h.DeletePet))
+	admin.Get("/stats", h.Stats)
+	r.Mount("/admin", admin)
+
+	r.Delete("/cache", func(http.ResponseWriter, *http.Request) {})
+	return r
+}
+
+func storesRouter() chi.Router {
+	r := chi.NewRouter()
+	r.Group(registerStores)
+	return r
+}
+
+func registerStores(r chi.Router) {
+	r.Get("/{storeID}", GetStore)
+}
@@ -0,0 +1,36 @@
+// Package echo is a stub of the router of github.com/labstack/echo.
+package echo
+
+// Context is the context of a request.
+type Context interface{}
+
+// HandlerFunc handles a request.
+type HandlerFunc func(Context) error
+
+// MiddlewareFunc wraps a handler.
+type MiddlewareFunc func(HandlerFunc) HandlerFunc
+
+// Route is a registered route.
+type Route struct{}
+
+// Echo is the router.
+type Echo struct{}
+
+// New returns a new router.
+func New() *Echo { return &Echo{} }
+
+// Group returns a group of routes.
+func (e *Echo) Group(string, ...MiddlewareFunc) *Group { return &Group{} }
+
+func (e *Echo) GET(string, HandlerFunc, ...MiddlewareFunc) *Route         { return nil }
+func (e *Echo) Add(string, string, HandlerFunc, ...MiddlewareFunc) *Route { return nil }
+
+// Group is a group of routes with a prefix.
+type Group struct{}
+
+// Group returns a nested group of routes.
+func (g *Group) Group(string, ...MiddlewareFunc) *Group { return g }
+
+func (g *Group) GET(string, HandlerFunc, ...MiddlewareFunc) *Route         { return nil }
+func (g *Group) POST(string, HandlerFunc, ...MiddlewareFunc) *Route        { return nil }
+func (g *Group) Add(string, string, HandlerFunc, ...MiddlewareFunc) *Route { return nil }
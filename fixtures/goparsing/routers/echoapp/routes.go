@@ -0,0 +1,33 @@
+// Package echoapp registers its routes with echo.
+package echoapp
+
+import (
+	"net/http"
+
+	"github.com/3idey/codescan/fixtures/goparsing/routers/echo"
+)
+
+type pets struct{}
+
+// get returns a pet.
+func (pets) get(echo.Context) error { return nil }
+
+// create creates a pet.
+func (pets) create(echo.Context) error { return nil }
+
+type users struct{}
+
+// get returns a user.
+func (*users) get(echo.Context) error { return nil }
+
+// New registers the routes of the API.
+func New() *echo.Echo {
+	e := echo.New()
+	api := e.Group("/api")
+	p := pets{}
+	api.GET("/pets/:id", p.get)
+	api.Add(http.MethodPost, "/pets", p.create)
+	u := &users{}
+	api.Group("/users").GET("/:id", u.get)
+	return e
+}
@@ -0,0 +1,33 @@
+// Package gin is a stub of the router of github.com/gin-gonic/gin.
+package gin
+
+// Context is the context of a request.
+type Context struct{}
+
+// HandlerFunc handles a request.
+type HandlerFunc func(*Context)
+
+// IRoutes registers the routes.
+type IRoutes interface {
+	GET(string, ...HandlerFunc) IRoutes
+	POST(string, ...HandlerFunc) IRoutes
+	Handle(string, string, ...HandlerFunc) IRoutes
+}
+
+// RouterGroup is a group of routes with a prefix.
+type RouterGroup struct{}
+
+// Group returns a group of routes.
+func (group *RouterGroup) Group(string, ...HandlerFunc) *RouterGroup { return group }
+
+func (group *RouterGroup) GET(string, ...HandlerFunc) IRoutes            { return group }
+func (group *RouterGroup) POST(string, ...HandlerFunc) IRoutes           { return group }
+func (group *RouterGroup) Handle(string, string, ...HandlerFunc) IRoutes { return group }
+
+// Engine is the router.
+type Engine struct {
+	RouterGroup
+}
+
+// Default returns a new router.
+func Default() *Engine { return &Engine{} }
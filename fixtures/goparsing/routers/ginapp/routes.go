@@ -0,0 +1,37 @@
+// Package ginapp registers its routes with gin.
+package ginapp
+
+import (
+	"net/http"
+
+	"github.com/3idey/codescan/fixtures/goparsing/routers/gin"
+)
+
+func auth(*gin.Context) {}
+
+// Run serves the API.
+func Run() {
+	e := gin.Default()
+	Register(e)
+}
+
+// Register registers the routes of the API.
+func Register(e *gin.Engine) {
+	v1 := e.Group("/v1")
+	{
+		v1.GET("/pets/:id", auth, getPet)
+		pets := v1.Group("/pets/")
+		pets.POST("", createPet)
+	}
+	e.Handle(http.MethodPut, "/files/*path", putFile)
+	e.GET("/assets/*", getPet)
+}
+
+// getPet returns a pet.
+func getPet(*gin.Context) {}
+
+// createPet creates a pet.
+func createPet(*gin.Context) {}
+
+// putFile uploads a file.
+func putFile(*gin.Context) {}
@@ -0,0 +1,42 @@
+// Package rules is the fixture of the lint rules.
+package rules
+
+// Pet of the store.
+//
+// swagger:model
+type Pet struct {
+	Name string `json:"name"`
+
+	Updates chan string `json:"updates"`
+}
+
+// Order of a pet.
+//
+// swagger:model
+type Order struct {
+	Pet Pet `json:"pet"`
+
+	// the number of pets
+	Quantity int `json:"quantity"`
+}
+
+// swagger:route DELETE /pets/{id} pets deletePet
+//
+// Deletes a pet.
+//
+// Responses:
+//   200: description: deleted
+
+// swagger:route DELETE /orders/{id} orders deleteOrder
+//
+// Deletes an order.
+//
+// Responses:
+//   204: description: deleted
+
+// swagger:route GET /pets pets listPets
+//
+// Lists the pets.
+//
+// Responses:
+//   200: description: the pets
@@ -0,0 +1,15 @@
+// Package schemadepth is the fixture of the maximum schema depth.
+package schemadepth
+
+// Config nests anonymous structs, like generated config types.
+//
+// swagger:model
+type Config struct {
+	Servers []struct {
+		TLS struct {
+			Options map[string]struct {
+				Value string `json:"value"`
+			} `json:"options"`
+		} `json:"tls"`
+	} `json:"servers"`
+}
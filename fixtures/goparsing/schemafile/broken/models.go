@@ -0,0 +1,10 @@
+// Package broken is the fixture of a Schema File directive with an unresolved $ref.
+package broken
+
+// Settings are the settings of a user.
+//
+// swagger:model Settings
+type Settings struct {
+	// Schema File: broken.schema.json
+	Preferences map[string]any `json:"preferences"`
+}
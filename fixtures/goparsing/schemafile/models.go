@@ -0,0 +1,23 @@
+// Package schemafile is the fixture of the properties whose schema is a JSON Schema file.
+package schemafile
+
+import "encoding/json"
+
+// Layout is the layout of the dashboard of a user, a JSON column.
+type Layout map[string]any
+
+// Settings are the settings of a user.
+//
+// swagger:model Settings
+type Settings struct {
+	// The preferences of the user.
+	//
+	// Schema File: schemas/preferences.schema.json
+	Preferences json.RawMessage `json:"preferences"`
+
+	// Schema File: schemas/layout.yaml
+	// Read Only: true
+	Layout Layout `json:"layout"`
+
+	Raw json.RawMessage `json:"raw"`
+}
@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+// Package scopes is the fixture of the order of the scopes of the security definitions.
+//
+//	SecurityDefinitions:
+//	oauth:
+//	  type: oauth2
+//	  flow: accessCode
+//	  authorizationUrl: https://example.com/authorize
+//	  tokenUrl: https://example.com/token
+//	  scopes:
+//	    pets:write: writes the pets
+//	    pets:read: reads the pets
+//	    owners:write: writes the owners
+//	    owners:read: reads the owners
+//	    stores:write: writes the stores
+//	    stores:read: reads the stores
+//	    orders:write: writes the orders
+//	    orders:read: reads the orders
+//	    users:write: writes the users
+//	    users:read: reads the users
+//	    billing:write: writes the invoices
+//	    billing:read: reads the invoices
+//	    reports:read: reads the reports
+//	    audit:read: reads the audit log
+//	    webhooks:write: manages the webhooks
+//	    tokens:write: issues the tokens
+//	    admin:
+//	    support: impersonates the users
+//	    metrics:read: reads the metrics
+//	    zones:read: reads the zones
+//	partner:
+//	  type: oauth2
+//	  flow: application
+//	  tokenUrl: https://example.com/token
+//	  scopes:
+//	  - partners:sync: synchronizes the catalogs
+//	  - partners:read
+//	  - catalog:write: writes the catalogs
+//
+// swagger:meta
+package scopes
+
+// swagger:route GET /pets pets listPets
+//
+// Lists the pets.
+//
+// Security:
+//
+//	oauth: pets:read
+//
+// Responses:
+//
+//	200: description: the pets
+func listPets() {}
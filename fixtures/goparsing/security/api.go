@@ -0,0 +1,56 @@
+// Package security is the fixture of the security requirements of the operations.
+//
+//	SecurityDefinitions:
+//	oauth2:
+//	  type: oauth2
+//	  flow: accessCode
+//	  authorizationUrl: https://example.com/authorize
+//	  tokenUrl: https://example.com/token
+//	  scopes:
+//	    read:users: reads the users
+//	    write:users: writes the users
+//	api_key:
+//	  type: apiKey
+//	  name: X-API-Key
+//	  in: header
+//
+// swagger:meta
+package security
+
+// swagger:route POST /users users createUser
+//
+// Creates a user.
+//
+// Security:
+//   oauth2: read:users, write:users
+//   api_key:
+//
+// Responses:
+//   201: description: the user was created
+
+// swagger:route GET /health health getHealth
+//
+// Checks the health of the API.
+//
+// Security: none
+//
+// Responses:
+//   200: description: the API is healthy
+
+// swagger:route GET /users users listUsers
+//
+// Lists the users.
+//
+// Responses:
+//   200: description: the users
+
+// swagger:operation DELETE /users/{id} users deleteUser
+//
+// Deletes a user.
+//
+// ---
+// security:
+//   - oauth2: [write:users]
+// responses:
+//   "204":
+//     description: the user was deleted
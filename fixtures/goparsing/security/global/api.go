@@ -0,0 +1,41 @@
+// Package global is the fixture of the top-level security requirements, applying to all the operations.
+//
+//	Security:
+//	  api_key:
+//
+//	SecurityDefinitions:
+//	api_key:
+//	  type: apiKey
+//	  name: X-API-Key
+//	  in: header
+//	basic:
+//	  type: basic
+//
+// swagger:meta
+package global
+
+// swagger:route GET /orders orders listOrders
+//
+// Lists the orders.
+//
+// Responses:
+//   200: description: the orders
+
+// swagger:route GET /orders/{id} orders getOrder
+//
+// Gets an order.
+//
+// Security:
+//   basic:
+//
+// Responses:
+//   200: description: the order
+
+// swagger:route GET /status status getStatus
+//
+// Checks the status of the API.
+//
+// Security: none
+//
+// Responses:
+//   200: description: the API is up
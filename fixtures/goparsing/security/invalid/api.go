@@ -0,0 +1,20 @@
+// Package invalid requires a security scheme which isn't defined.
+//
+//	SecurityDefinitions:
+//	api_key:
+//	  type: apiKey
+//	  name: X-API-Key
+//	  in: header
+//
+// swagger:meta
+package invalid
+
+// swagger:route GET /users users listUsers
+//
+// Lists the users.
+//
+// Security:
+//   apikey:
+//
+// Responses:
+//   200: description: the users
@@ -0,0 +1,22 @@
+// Package scopes requires a scope which its oauth2 scheme doesn't declare.
+//
+//	SecurityDefinitions:
+//	oauth2:
+//	  type: oauth2
+//	  flow: implicit
+//	  authorizationUrl: https://example.com/authorize
+//	  scopes:
+//	    read:users: reads the users
+//
+// swagger:meta
+package scopes
+
+// swagger:route GET /users users listUsers
+//
+// Lists the users.
+//
+// Security:
+//   oauth2: read:user
+//
+// Responses:
+//   200: description: the users
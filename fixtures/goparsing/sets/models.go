@@ -0,0 +1,70 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package sets
+
+import (
+	"encoding/json"
+	"slices"
+)
+
+// StringSet marshals as a JSON array of its members.
+type StringSet map[string]struct{}
+
+// MarshalJSON marshals the members of the set, sorted.
+func (s StringSet) MarshalJSON() ([]byte, error) {
+	members := make([]string, 0, len(s))
+	for member := range s {
+		members = append(members, member)
+	}
+	slices.Sort(members)
+	return json.Marshal(members)
+}
+
+// Set is a generic set, marshaled as a JSON array.
+type Set[T comparable] struct {
+	members map[T]struct{}
+}
+
+// MarshalJSON marshals the members of the set.
+func (s Set[T]) MarshalJSON() ([]byte, error) {
+	members := make([]T, 0, len(s.members))
+	for member := range s.members {
+		members = append(members, member)
+	}
+	return json.Marshal(members)
+}
+
+// Color of a product.
+type Color struct {
+	Name string `json:"name"`
+}
+
+// Product is sold in colors.
+//
+// swagger:model
+type Product struct {
+	// the labels of the product
+	Labels StringSet `json:"labels"`
+
+	Colors Set[Color] `json:"colors"`
+
+	Sizes *Set[int32] `json:"sizes,omitempty"`
+}
+
+// ListProductsParams filters the products.
+//
+// swagger:parameters listProducts
+type ListProductsParams struct {
+	// in: query
+	Labels StringSet `json:"labels"`
+}
+
+// ListProducts swagger:route GET /products products listProducts
+//
+// Lists the products.
+//
+// Responses:
+//
+//	200: body:[]Product
+func ListProducts() {}
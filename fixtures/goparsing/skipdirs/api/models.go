@@ -0,0 +1,9 @@
+// Package api provides the models of the default skips fixtures.
+package api
+
+// Account is the model of the scanned package.
+//
+// swagger:model Account
+type Account struct {
+	ID int64 `json:"id"`
+}
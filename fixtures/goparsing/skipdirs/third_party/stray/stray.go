@@ -0,0 +1,9 @@
+// Package stray provides checked-in third-party code with stray annotations.
+package stray
+
+// Stray is a model which is not part of the API.
+//
+// swagger:model Stray
+type Stray struct {
+	Name string `json:"name"`
+}
@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+// Package accounts is the fixture of the golden file of the stable output, without the types of the
+// standard library, whose documentation changes with the Go releases.
+//
+//	SecurityDefinitions:
+//	oauth:
+//	  type: oauth2
+//	  flow: accessCode
+//	  authorizationUrl: https://example.com/authorize
+//	  tokenUrl: https://example.com/token
+//	  scopes:
+//	    write: writes the accounts
+//	    read: reads the accounts
+//	key:
+//	  type: apiKey
+//	  name: X-API-Key
+//	  in: header
+//
+// swagger:meta
+package accounts
+
+// Audit holds the fields of the audited models.
+type Audit struct {
+	// required: true
+	CreatedBy string `json:"createdBy"`
+	// required: true
+	CreatedAt string `json:"createdAt"`
+	UpdatedBy string `json:"updatedBy,omitempty"`
+}
+
+// Account is an account of the service.
+//
+// swagger:model Account
+type Account struct {
+	Audit
+
+	// required: true
+	Zone string `json:"zone"`
+	// required: true
+	ID int64 `json:"id"`
+	// required: true
+	// enum: ["suspended", "active", "closed"]
+	Status string `json:"status"`
+	// required: true
+	Name string `json:"name"`
+
+	Owner    *Owner            `json:"owner,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Settings Settings          `json:"settings"`
+}
+
+// Settings are the settings of an account.
+type Settings struct {
+	// required: true
+	Locale string `json:"locale"`
+	// required: true
+	// minimum: 1
+	PageSize int `json:"pageSize"`
+	// enum: ["weekly", "daily"]
+	Digest []string `json:"digest"`
+}
+
+// Owner owns accounts.
+//
+// swagger:model Owner
+type Owner struct {
+	// required: true
+	Name string `json:"name"`
+	// required: true
+	Email string `json:"email"`
+}
+
+// swagger:parameters listAccounts getAccount
+type accountParams struct {
+	// in: header
+	// required: true
+	Trace string `json:"X-Trace"`
+	// in: query
+	Zone string `json:"zone"`
+	// in: query
+	Limit int `json:"limit"`
+}
+
+// The accounts.
+//
+// swagger:response accounts
+type accountsResponse struct {
+	// the next page
+	Next string `json:"X-Next"`
+	// the rate limit
+	Limit int `json:"X-Limit"`
+	// in: body
+	Body []Account
+}
+
+// swagger:route GET /accounts zones accounts listAccounts
+//
+// Lists the accounts.
+//
+// Produces:
+//   - application/xml
+//   - application/json
+//
+// Security:
+//   oauth: write, read
+//   key:
+//
+// Responses:
+//
+//	200: accounts
+//	404: description: not found
+//	400: description: bad request
+
+// swagger:route GET /accounts/{id} accounts zones getAccount
+//
+// Returns an account.
+//
+// Responses:
+//
+//	200: body:Account
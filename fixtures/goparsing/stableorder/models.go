@@ -0,0 +1,29 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+// Package stableorder is the fixture of the stable output of the scans.
+package stableorder
+
+import "net/url"
+
+// URL is a URL formatted as a string.
+//
+// swagger:strfmt url
+type URL url.URL
+
+// Link is a link to a site.
+//
+// swagger:model Link
+type Link struct {
+	// the parsed target, documented as the URL definition
+	Target url.URL `json:"target"`
+	Site   URL     `json:"site"`
+}
+
+// Bookmark is a saved link.
+//
+// swagger:model Bookmark
+type Bookmark struct {
+	Link
+	Tags []string `json:"tags"`
+}
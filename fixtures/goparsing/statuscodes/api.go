@@ -0,0 +1,64 @@
+// Package statuscodes is the fixture of the check of the status codes written by handlers.
+package statuscodes
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+const statusConflict = 409
+
+// CreateOrder is documented with all the statuses it writes.
+//
+// swagger:route POST /orders orders createOrder
+//
+// Creates an order.
+//
+// Responses:
+//
+//	201: description: created
+//	400: description: invalid order
+func CreateOrder(w http.ResponseWriter, r *http.Request) {
+	if r.Body == nil {
+		http.Error(w, "no order", http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+// swagger:route PUT /orders/{id} orders replaceOrder
+//
+// Replaces an order.
+//
+// Responses:
+//
+//	200: description: replaced
+//	404: description: unknown order
+//	410: description: deleted order
+func ReplaceOrder(w http.ResponseWriter, r *http.Request) {
+	if r.ContentLength == 0 {
+		w.WriteHeader(statusConflict)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(r.URL.Path)
+	w.WriteHeader(404)
+}
+
+// DeleteOrder writes a dynamic status code, which is ignored.
+//
+// swagger:route DELETE /orders/{id} orders deleteOrder
+//
+// Deletes an order.
+//
+// Responses:
+//
+//	204: description: deleted
+//	default: description: error
+func DeleteOrder(w http.ResponseWriter, r *http.Request) {
+	status := http.StatusNoContent
+	if r.URL.Query().Has("force") {
+		status = http.StatusAccepted
+	}
+	w.WriteHeader(status)
+	w.WriteHeader(http.StatusTeapot)
+}
@@ -0,0 +1,46 @@
+// Package strfmtcheck is the fixture of the checks of swagger:strfmt annotations.
+package strfmtcheck
+
+import "time"
+
+// ID is a string.
+//
+// swagger:strfmt uuid
+type ID string
+
+// Stamp marshals as a string.
+//
+// swagger:strfmt date-time
+type Stamp struct {
+	t time.Time
+}
+
+// MarshalJSON writes the stamp as a string.
+func (s *Stamp) MarshalJSON() ([]byte, error) {
+	return s.t.MarshalJSON()
+}
+
+// Revision doesn't marshal as a string.
+//
+// swagger:strfmt revision
+type Revision struct {
+	Major int `json:"major"`
+}
+
+// Stamps are stamps.
+//
+// swagger:strfmt date-time
+type Stamps []time.Time
+
+// Event is a model with formatted fields.
+//
+// swagger:model Event
+type Event struct {
+	// swagger:strfmt datetime
+	At time.Time `json:"at"`
+
+	// swagger:strfmt uuid
+	Sequence int64 `json:"sequence"`
+
+	ID ID `json:"id"`
+}
@@ -0,0 +1,30 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+// Package suppressions is the fixture of the codescan:ignore comments.
+package suppressions
+
+// Stream is a stream of events.
+//
+// codescan:ignore skipped-field the events are streamed by the websocket
+// swagger:model Stream
+type Stream struct {
+	Events chan string `json:"events"`
+	Done   chan bool   `json:"done"`
+}
+
+// Job is a background job.
+//
+// swagger:model Job
+type Job struct {
+	// codescan:ignore skipped-field until=2020-01-01 the results were dropped in v2
+	Results chan int `json:"results"`
+
+	Cancel chan bool `json:"cancel"` // codescan:ignore skipped-field until=2999-12-31 set by the server
+
+	// codescan:ignore skipped-field
+	Logs chan string `json:"logs"`
+
+	// codescan:ignore skipped-field until=tomorrow because
+	Ticks chan int `json:"ticks"`
+}
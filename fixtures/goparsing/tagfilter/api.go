@@ -0,0 +1,30 @@
+// Package tagfilter is the fixture of the tag rules, with routes of several tags.
+package tagfilter
+
+// swagger:route GET /users users listUsers
+//
+// Lists the users.
+//
+// Responses:
+//   200: description: the users
+
+// swagger:route POST /users/reindex users internal reindexUsers
+//
+// Reindexes the users.
+//
+// Responses:
+//   204: description: reindexed
+
+// swagger:route GET /users/admins users admin listAdmins
+//
+// Lists the administrators.
+//
+// Responses:
+//   200: description: the administrators
+
+// swagger:route GET /audit admin getAudit
+//
+// Reads the audit log.
+//
+// Responses:
+//   200: description: the audit log
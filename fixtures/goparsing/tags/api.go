@@ -0,0 +1,32 @@
+// Package tags is the fixture of the declarations of the tags of the operations.
+package tags
+
+// swagger:tag users
+//
+// The users of the API,
+// created by signing up.
+
+// swagger:tag orders
+//
+// The orders of the users.
+
+// swagger:route GET /users users listUsers
+//
+// Lists the users.
+//
+// Responses:
+//   200: description: the users
+
+// swagger:route GET /users/{id}/orders user orders listUserOrders
+//
+// Lists the orders of a user.
+//
+// Responses:
+//   200: description: the orders
+
+// swagger:route GET /status health getStatus
+//
+// Gets the status of the API.
+//
+// Responses:
+//   200: description: the status
@@ -0,0 +1,10 @@
+// Package duplicate declares a tag twice.
+package duplicate
+
+// swagger:tag users
+//
+// The users.
+
+// swagger:tag users
+//
+// The users, again.
@@ -0,0 +1,2 @@
+// Package testscope provides fixtures for test-scoped declarations.
+package testscope
@@ -0,0 +1,37 @@
+package testscope
+
+// User is a user of the API.
+//
+// swagger:model User
+type User struct {
+	// required: true
+	ID int64 `json:"id"`
+
+	Name string `json:"name"`
+}
+
+// swagger:route GET /users users listUsers
+//
+// Lists users.
+//
+// Responses:
+//   200: listUsersResponse
+//   default: errorResponse
+
+// A list of users.
+//
+// swagger:response listUsersResponse
+type ListUsersResponse struct {
+	// in: body
+	Body []User `json:"body"`
+}
+
+// ErrorResponse is only documented in the contract spec.
+//
+// swagger:response errorResponse scope:test
+type ErrorResponse struct {
+	// in: body
+	Body struct {
+		Message string `json:"message"`
+	} `json:"body"`
+}
@@ -0,0 +1,38 @@
+package testscope
+
+import "testing"
+
+// PagedUsersResponse is a richer response defined by contract tests.
+//
+// swagger:response pagedUsersResponse scope:test
+type PagedUsersResponse struct {
+	// in: body
+	Body struct {
+		Users []User `json:"users"`
+		Next  string `json:"next"`
+	} `json:"body"`
+}
+
+// ListUsersParams are the parameters of listUsers, only documented in the contract spec.
+//
+// swagger:parameters listUsers scope:test
+type ListUsersParams struct {
+	// in: query
+	Limit int `json:"limit"`
+}
+
+// NotScoped is ignored: declarations in test files must be test-scoped.
+//
+// swagger:response notScopedResponse
+type NotScoped struct {
+	// in: body
+	Body string `json:"body"`
+}
+
+// swagger:route GET /ignored users ignored
+//
+// Routes in test files are ignored.
+
+func TestUsers(t *testing.T) {
+	_ = PagedUsersResponse{}
+}
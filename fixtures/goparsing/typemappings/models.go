@@ -0,0 +1,54 @@
+// Package typemappings is the fixture of the schemas of the mapped types.
+package typemappings
+
+import (
+	"encoding/json"
+	"net"
+	"net/netip"
+	"time"
+
+	"github.com/3idey/codescan/fixtures/goparsing/typemappings/money"
+)
+
+// Invoice holds mapped types, directly, as pointers, as elements and as map values.
+//
+// swagger:model
+type Invoice struct {
+	// the total of the lines
+	Total    money.Amount            `json:"total"`
+	Discount *money.Amount           `json:"discount"`
+	Lines    []money.Amount          `json:"lines"`
+	Taxes    map[string]money.Amount `json:"taxes"`
+	Terms    time.Duration           `json:"terms"`
+	Issued   time.Time               `json:"issued"`
+	Client   net.IP                  `json:"client"`
+	Server   netip.Addr              `json:"server"`
+	// the raw JSON of the issuer
+	Extra    json.RawMessage            `json:"extra"`
+	Previous *json.RawMessage           `json:"previous"`
+	History  []json.RawMessage          `json:"history"`
+	Metadata map[string]json.RawMessage `json:"metadata"`
+}
+
+// swagger:parameters listInvoices
+type ListInvoicesParams struct {
+	// in: query
+	MinTotal money.Amount `json:"minTotal"`
+	// in: query
+	Totals []money.Amount `json:"totals"`
+}
+
+// swagger:response invoices
+type InvoicesResponse struct {
+	// in: header
+	Balance money.Amount `json:"X-Balance"`
+	// in: body
+	Body []Invoice
+}
+
+// swagger:route GET /invoices invoices listInvoices
+//
+// Lists the invoices.
+//
+// Responses:
+//   200: invoices
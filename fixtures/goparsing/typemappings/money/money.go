@@ -0,0 +1,9 @@
+// Package money is a library type mapped to a schema of its own.
+package money
+
+// Amount is marshaled by its library as a string, e.g. "12.50 EUR".
+type Amount struct {
+	units int64
+	nanos int32
+	code  string
+}
@@ -0,0 +1,16 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package ambiguous
+
+import (
+	_ "github.com/3idey/codescan/fixtures/goparsing/typerefs/models"
+	_ "github.com/3idey/codescan/fixtures/goparsing/typerefs/models/v2"
+)
+
+// GetAccount swagger:route GET /accounts/{id} accounts getAccount
+//
+// Responses:
+//
+//	200: body:models.Account
+func GetAccount() {}
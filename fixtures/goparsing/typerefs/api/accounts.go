@@ -0,0 +1,13 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+// ListAccounts swagger:route GET /accounts accounts listAccounts
+//
+// Lists the users of the accounts, with the second version of the API whose package the file doesn't import.
+//
+// Responses:
+//
+//	200: body:[]v2.User
+func ListAccounts() {}
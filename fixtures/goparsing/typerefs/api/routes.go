@@ -0,0 +1,22 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	models2 "github.com/3idey/codescan/fixtures/goparsing/typerefs/models/v2"
+
+	. "github.com/3idey/codescan/fixtures/goparsing/typerefs/models"
+)
+
+// GetUser swagger:route GET /users/{id} users getUser
+//
+// Gets a user.
+//
+// Responses:
+//
+//	200: body:models2.User
+//	404: body:Account
+func GetUser() (models2.User, Account) {
+	return models2.User{}, Account{}
+}
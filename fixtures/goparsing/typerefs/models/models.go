@@ -0,0 +1,14 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+// User of the first version of the API.
+type User struct {
+	Name string `json:"name"`
+}
+
+// Account of a user.
+type Account struct {
+	ID int64 `json:"id"`
+}
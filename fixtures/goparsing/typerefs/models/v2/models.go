@@ -0,0 +1,17 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+// User of the second version of the API.
+//
+// swagger:model userV2
+type User struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// Account of a user.
+type Account struct {
+	ID string `json:"id"`
+}
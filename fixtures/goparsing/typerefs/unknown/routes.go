@@ -0,0 +1,17 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package unknown
+
+import (
+	models2 "github.com/3idey/codescan/fixtures/goparsing/typerefs/models/v2"
+)
+
+var _ models2.User
+
+// GetProfile swagger:route GET /profile users getProfile
+//
+// Responses:
+//
+//	200: body:models2.Profile
+func GetProfile() {}
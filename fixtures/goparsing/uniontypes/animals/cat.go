@@ -0,0 +1,9 @@
+// Package animals is imported by the unions of types.
+package animals
+
+// Cat purrs.
+//
+// swagger:model Cat
+type Cat struct {
+	Purr bool `json:"purr"`
+}
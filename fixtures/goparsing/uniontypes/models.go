@@ -0,0 +1,29 @@
+// Package uniontypes is the fixture of the unions of types of any fields.
+package uniontypes
+
+import "github.com/3idey/codescan/fixtures/goparsing/uniontypes/animals"
+
+// Dog barks.
+//
+// swagger:model Dog
+type Dog struct {
+	Bark bool `json:"bark"`
+}
+
+// Entry has fields narrowed to unions of types.
+//
+// swagger:model Entry
+type Entry struct {
+	// The amount, as a number or a formatted string.
+	//
+	// Types: string, number
+	Amount any `json:"amount"`
+
+	// Types: Dog, animals.Cat
+	Pet any `json:"pet"`
+
+	Other any `json:"other"`
+}
+
+// the animals are only referenced by the union of Entry.Pet
+var _ animals.Cat
@@ -0,0 +1,10 @@
+// Package unknown has a union naming an unknown type.
+package unknown
+
+// Entry has a union with a typo.
+//
+// swagger:model Entry
+type Entry struct {
+	// Types: string, nubmer
+	Amount any `json:"amount"`
+}
@@ -0,0 +1,89 @@
+// Package unwrap is the fixture of the responses documented without their envelope.
+package unwrap
+
+// A Pet of the store.
+type Pet struct {
+	Name  string `json:"name"`
+	Owner Owner  `json:"owner"`
+}
+
+// Owner of a pet.
+type Owner struct {
+	Name string `json:"name"`
+}
+
+// Meta describes a page of results.
+type Meta struct {
+	Total int `json:"total"`
+}
+
+// Envelope wraps the payloads of the API.
+//
+// example: {"data": {"name": "Rex", "owner": {"name": "Ana"}}, "meta": {"total": 1}}
+type Envelope struct {
+	Data Pet  `json:"data"`
+	Meta Meta `json:"meta"`
+}
+
+// Page wraps a list of payloads.
+type Page[T any] struct {
+	Items []T `json:"items"`
+	Meta  Meta `json:"meta"`
+}
+
+// PetEnvelope is a pet in its envelope.
+//
+// swagger:response petEnvelope
+type PetEnvelope struct {
+	// in: body
+	Body Envelope
+}
+
+// PetResponse is a pet, without its envelope.
+//
+// Unwrap: data
+//
+// swagger:response petResponse
+type PetResponse struct {
+	// in: body
+	Body Envelope
+}
+
+// PetsResponse lists pets, without their envelope.
+//
+// Unwrap: items
+//
+// swagger:response petsResponse
+type PetsResponse struct {
+	// in: body
+	Body struct {
+		Page[Pet]
+	}
+}
+
+// swagger:route GET /internal/pets/{id} pets getInternalPet
+//
+// Gets a pet in its envelope.
+//
+// Audience: internal
+//
+// Responses:
+//   200: petEnvelope
+
+// swagger:route GET /pets/{id} pets getPet
+//
+// Gets a pet.
+//
+// Audience: partner
+//
+// Responses:
+//   200: petResponse
+
+// swagger:route GET /pets pets listPets
+//
+// Lists the pets.
+//
+// Audience: partner
+//
+// Responses:
+//   200: petsResponse
@@ -0,0 +1,17 @@
+// Package invalid unwraps a property the envelope doesn't have.
+package invalid
+
+// Envelope wraps the payloads of the API.
+type Envelope struct {
+	Data string `json:"data"`
+}
+
+// NameResponse is a name, without its envelope.
+//
+// Unwrap: payload
+//
+// swagger:response nameResponse
+type NameResponse struct {
+	// in: body
+	Body Envelope
+}
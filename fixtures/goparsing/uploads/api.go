@@ -0,0 +1,65 @@
+// Package uploads is the fixture of the file uploads, with multipart.FileHeader fields.
+package uploads
+
+import "mime/multipart"
+
+// swagger:route POST /avatars avatars uploadAvatar
+//
+// Uploads an avatar.
+//
+// Responses:
+//   204: description: uploaded
+
+// swagger:route POST /documents documents uploadDocuments
+//
+// Uploads documents.
+//
+// Consumes:
+//   multipart/mixed
+//
+// Responses:
+//   204: description: uploaded
+
+// swagger:route POST /profiles profiles updateProfile
+//
+// Updates a profile, without a file.
+//
+// Responses:
+//   204: description: updated
+
+// swagger:parameters uploadAvatar
+type uploadAvatarParams struct {
+	// the avatar
+	//
+	// required: true
+	File *multipart.FileHeader `json:"file"`
+
+	// the caption of the avatar
+	//
+	// in: formData
+	// maxLength: 140
+	Caption string `json:"caption"`
+
+	// the size of the avatar, in pixels
+	//
+	// in: formData
+	// minimum: 16
+	Size int `json:"size"`
+}
+
+// swagger:parameters uploadDocuments
+type uploadDocumentsParams struct {
+	// the documents
+	//
+	// in: formData
+	Documents []*multipart.FileHeader `json:"documents"`
+}
+
+// swagger:parameters updateProfile
+type updateProfileParams struct {
+	// the name of the profile
+	//
+	// in: formData
+	// required: true
+	Name string `json:"name"`
+}
@@ -0,0 +1,19 @@
+// Package badin is the fixture of a file parameter out of the form data.
+package badin
+
+import "mime/multipart"
+
+// swagger:route POST /avatars avatars uploadAvatar
+//
+// Uploads an avatar.
+//
+// Responses:
+//   204: description: uploaded
+
+// swagger:parameters uploadAvatar
+type uploadAvatarParams struct {
+	// the avatar
+	//
+	// in: query
+	File *multipart.FileHeader `json:"file"`
+}
@@ -0,0 +1,24 @@
+// Package bodyform is the fixture of an operation mixing a body and form data.
+package bodyform
+
+import "mime/multipart"
+
+// swagger:route POST /avatars avatars uploadAvatar
+//
+// Uploads an avatar.
+//
+// Responses:
+//   204: description: uploaded
+
+// swagger:parameters uploadAvatar
+type uploadAvatarParams struct {
+	// the avatar
+	File multipart.FileHeader `json:"file"`
+
+	// the metadata of the avatar
+	//
+	// in: body
+	Metadata struct {
+		Caption string `json:"caption"`
+	} `json:"metadata"`
+}
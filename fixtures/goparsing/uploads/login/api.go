@@ -0,0 +1,82 @@
+// Package login is the fixture of the form endpoints of a JSON API, e.g. an OAuth token endpoint.
+//
+//	Consumes:
+//	- application/json
+//
+// swagger:meta
+package login
+
+import "mime/multipart"
+
+// swagger:route POST /oauth/token oauth issueToken
+//
+// Issues an access token.
+//
+// Responses:
+//   200: description: issued
+
+// swagger:route POST /sessions sessions createSession
+//
+// Signs in.
+//
+// Consumes:
+//   application/json
+//   application/x-www-form-urlencoded
+//
+// Responses:
+//   201: description: signed in
+
+// swagger:route POST /avatars avatars uploadAvatar
+//
+// Uploads an avatar.
+//
+// Consumes:
+//   application/x-www-form-urlencoded
+//
+// Responses:
+//   204: description: uploaded
+
+// swagger:route POST /users users createUser
+//
+// Creates a user.
+//
+// Responses:
+//   201: description: created
+
+// swagger:parameters issueToken
+type issueTokenParams struct {
+	// the grant type
+	//
+	// in: formData
+	// required: true
+	GrantType string `json:"grant_type"`
+
+	// the name of the user
+	//
+	// in: formData
+	Username string `json:"username"`
+}
+
+// swagger:parameters createSession
+type createSessionParams struct {
+	// the password of the user
+	//
+	// in: formData
+	Password string `json:"password"`
+}
+
+// swagger:parameters uploadAvatar
+type uploadAvatarParams struct {
+	// the avatar
+	File *multipart.FileHeader `json:"file"`
+}
+
+// swagger:parameters createUser
+type createUserParams struct {
+	// the user
+	//
+	// in: body
+	User struct {
+		Name string `json:"name"`
+	}
+}
@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+// Package validate is the fixture of the validation of the spec.
+//
+//	Version: 1.0.0
+//
+// swagger:meta
+package validate
+
+// User is a user.
+//
+// swagger:model User
+type User struct {
+	Name string `json:"name"`
+}
+
+// Orphan is not used by the operations.
+//
+// swagger:model Orphan
+type Orphan struct {
+	Note string `json:"note"`
+}
+
+// swagger:response userResponse
+type UserResponse struct {
+	// in: body
+	Body User
+}
+
+// swagger:route GET /users/{id} users getUser
+//
+// Gets a user, without declaring the id.
+//
+// Responses:
+//
+//	200: userResponse
+func getUser() {}
+
+// swagger:parameters createUser
+type CreateUserParams struct {
+	// in: body
+	User User `json:"user"`
+
+	// in: body
+	Profile string `json:"profile"`
+}
+
+// swagger:route POST /users users createUser
+//
+// Creates a user, with two bodies.
+//
+// Responses:
+//
+//	200: userResponse
+func createUser() {}
@@ -0,0 +1,72 @@
+// Package validatortags is the fixture of the constraints of the validator struct tags.
+package validatortags
+
+// Level is an enum of its own.
+//
+// swagger:enum Level
+type Level string
+
+const (
+	LevelLow  Level = "low"
+	LevelHigh Level = "high"
+)
+
+// Address is referred to by the user.
+//
+// swagger:model
+type Address struct {
+	City string `json:"city"`
+}
+
+// User is documented by its validator tags.
+//
+// swagger:model
+type User struct {
+	// strings
+	Name     string  `json:"name" validate:"required,min=1,max=64"`
+	Code     string  `json:"code" validate:"len=8"`
+	Nickname *string `json:"nickname,omitempty" validate:"omitempty,gt=2,lt=20"`
+	Role     string  `json:"role" validate:"oneof=admin 'power user' guest"`
+	Email    string  `json:"email" validate:"required,email"`
+	ID       string  `json:"id" validate:"uuid4"`
+	Homepage string  `json:"homepage" validate:"url|uri"`
+
+	// numbers
+	Age    int      `json:"age" validate:"gte=18,lte=130"`
+	Score  *float64 `json:"score" validate:"gt=0,lt=1"`
+	Rating int      `json:"rating" validate:"oneof=1 2 3"`
+
+	// slices
+	Tags    []string          `json:"tags" validate:"required,min=1,max=10,unique,dive,min=2,max=32"`
+	Emails  [][]string        `json:"emails" validate:"dive,len=2,dive,email"`
+	Labels  map[string]string `json:"labels" validate:"dive,keys,max=8,endkeys,max=16"`
+	Address *Address          `json:"address" validate:"required,min=3"`
+
+	// Min Length: 5
+	// Max Length: 100
+	// Required: false
+	Bio string `json:"bio" validate:"required,min=1,max=2000"`
+
+	Level   Level  `json:"level" validate:"oneof=low"`
+	Unknown string `json:"unknown" validate:"alphanum,startswith=x,excludesall=0x2C"`
+	Bound   string `json:"bound" binding:"required,max=3"`
+}
+
+// swagger:parameters listUsers
+type ListUsersParams struct {
+	// in: query
+	Limit int `json:"limit" binding:"required,min=1,max=100"`
+	// in: query
+	Sort string `json:"sort" binding:"oneof=name age"`
+	// in: query
+	IDs []string `json:"ids" binding:"max=5,dive,uuid"`
+	// in: body
+	Filter *Address `json:"filter" binding:"required"`
+}
+
+// swagger:route GET /users users listUsers
+//
+// Lists the users.
+//
+// Responses:
+//   200: description: the users
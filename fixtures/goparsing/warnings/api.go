@@ -0,0 +1,38 @@
+// Package warnings is the fixture of the structured warnings of a scan.
+package warnings
+
+// swagger:route GET /pets pets listPets
+//
+// Lists the pets.
+//
+// Responses:
+//   200: petsResponse
+//   404: notFoundResponse
+
+// swagger:route GET /animals pets listPets
+//
+// Lists the animals, with the operationId of listPets.
+//
+// Responses:
+//   200: petsResponse
+
+// A Pet is an animal of the store.
+//
+// swagger:model
+type Pet struct {
+	Name string `json:"name"`
+
+	// the updates are pushed to a channel
+	Updates chan string `json:"updates"`
+}
+
+// Feed streams pets.
+//
+// swagger:model
+type Feed chan Pet
+
+// swagger:response petsResponse
+type petsResponse struct {
+	// in: body
+	Body []Pet
+}